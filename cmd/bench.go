@@ -0,0 +1,95 @@
+package gotenbergcmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/bench"
+)
+
+// runBench runs the "bench" command against a Gotenberg instance and returns
+// the exit code.
+func runBench(args []string) int {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	fs.String("url", "http://localhost:3000", "Set the base URL of the Gotenberg instance")
+	fs.StringSlice("workloads", []string{"html", "docx", "pptx"}, "Set the workloads to send - html, docx and pptx")
+	fs.Int("pages", 5, "Set the number of pages of each workload")
+	fs.IntSlice("concurrency", []int{1, 2, 4, 8}, "Set the number of concurrent requests of each step")
+	fs.Duration("duration", time.Duration(10)*time.Second, "Set the duration of each step")
+	fs.Duration("timeout", time.Duration(30)*time.Second, "Set the maximum duration of a request")
+	fs.StringSlice("header", make([]string, 0), "Set extra HTTP headers sent with each request - key:value")
+	fs.Bool("json", false, "Print the report as JSON")
+
+	err := fs.Parse(args)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	url, _ := fs.GetString("url")
+	names, _ := fs.GetStringSlice("workloads")
+	pages, _ := fs.GetInt("pages")
+	concurrency, _ := fs.GetIntSlice("concurrency")
+	duration, _ := fs.GetDuration("duration")
+	timeout, _ := fs.GetDuration("timeout")
+	rawHeaders, _ := fs.GetStringSlice("header")
+	asJson, _ := fs.GetBool("json")
+
+	workloads, err := bench.Workloads(names, pages)
+	if err != nil {
+		fmt.Printf("[FATAL] %s\n", err)
+		return 1
+	}
+
+	headers := make(map[string]string, len(rawHeaders))
+	for _, rawHeader := range rawHeaders {
+		key, value, ok := strings.Cut(rawHeader, ":")
+		if !ok {
+			fmt.Printf("[FATAL] invalid header '%s', expected key:value\n", rawHeader)
+			return 1
+		}
+
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := bench.Run(ctx, bench.Options{
+		URL:         url,
+		Workloads:   workloads,
+		Concurrency: concurrency,
+		Duration:    duration,
+		Timeout:     timeout,
+		Headers:     headers,
+		OnStep: func(step bench.Step) {
+			if !asJson {
+				fmt.Printf("[BENCH] %d concurrent requests: %d requests, %d failures\n", step.Concurrency, step.Requests, step.Failures)
+			}
+		},
+	})
+	if err != nil {
+		fmt.Printf("[FATAL] %s\n", err)
+		return 1
+	}
+
+	if asJson {
+		err = report.WriteJson(os.Stdout)
+	} else {
+		fmt.Println()
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		fmt.Printf("[FATAL] %s\n", err)
+		return 1
+	}
+
+	return 0
+}
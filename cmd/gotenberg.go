@@ -34,7 +34,20 @@ var Version = "snapshot"
 
 // Run starts the Gotenberg application. Call this in the main of your program.
 func Run() {
+	if len(os.Args) > 1 && os.Args[1] == "bench" {
+		os.Exit(runBench(os.Args[2:]))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:]))
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == gotenberg.SandboxExecCommand {
+		os.Exit(gotenberg.RunSandboxExec(os.Args[2:]))
+	}
+
 	fmt.Printf(banner, Version)
+	gotenberg.Version = Version
 
 	// Create the root FlagSet and adds the modules flags to it.
 	fs := flag.NewFlagSet("gotenberg", flag.ExitOnError)
@@ -0,0 +1,67 @@
+package gotenbergcmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/replay"
+)
+
+// runReplay runs the "replay" command, which sends a captured request to a
+// Gotenberg instance, and returns the exit code.
+func runReplay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	fs.String("url", "http://localhost:3000", "Set the base URL of the Gotenberg instance")
+	fs.String("output", "", "Set the path of the file in which to write the output file")
+	fs.Duration("timeout", time.Duration(30)*time.Second, "Set the maximum duration of the request")
+
+	err := fs.Parse(args)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	if fs.NArg() != 1 {
+		fmt.Println("[FATAL] expected the directory of a captured request")
+		return 1
+	}
+
+	url, _ := fs.GetString("url")
+	outputPath, _ := fs.GetString("output")
+	timeout, _ := fs.GetDuration("timeout")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	result, err := replay.Replay(ctx, replay.Options{
+		URL:        url,
+		Dir:        fs.Arg(0),
+		OutputPath: outputPath,
+		Timeout:    timeout,
+	})
+	if err != nil {
+		fmt.Printf("[FATAL] %s\n", err)
+		return 1
+	}
+
+	fmt.Printf("[REPLAY] %s %s (trace %s) originally failed with %d: %s\n", result.Capture.Method, result.Capture.Path, result.Capture.Trace, result.Capture.Status, result.Capture.Error)
+	fmt.Printf("[REPLAY] replayed with %d in %s\n", result.Status, result.Duration)
+
+	// Unless written to the output file.
+	if len(result.Body) > 0 {
+		fmt.Println(string(result.Body))
+	}
+
+	if result.Status != http.StatusOK {
+		return 1
+	}
+
+	return 0
+}
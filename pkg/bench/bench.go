@@ -0,0 +1,366 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// Options gathers the options of a benchmark.
+type Options struct {
+	// URL is the base URL of the Gotenberg instance, e.g.,
+	// http://localhost:3000.
+	URL string
+
+	// Workloads are the documents sent, in turn, to the Gotenberg instance.
+	Workloads []Workload
+
+	// Concurrency are the numbers of concurrent requests of each step.
+	Concurrency []int
+
+	// Duration is the duration of each step.
+	Duration time.Duration
+
+	// Timeout is the maximum duration of a request.
+	Timeout time.Duration
+
+	// Headers are extra HTTP headers sent with each request, e.g., for
+	// authentication.
+	Headers map[string]string
+
+	// OnStep is called after each step, e.g., for reporting the progress.
+	// Optional.
+	OnStep func(step Step)
+}
+
+// Validate validates the options.
+func (opts Options) Validate() error {
+	var err error
+
+	if opts.URL == "" {
+		err = multierr.Append(err, errors.New("URL must not be empty"))
+	}
+
+	if len(opts.Workloads) == 0 {
+		err = multierr.Append(err, errors.New("at least one workload is required"))
+	}
+
+	if len(opts.Concurrency) == 0 {
+		err = multierr.Append(err, errors.New("at least one concurrency step is required"))
+	}
+
+	for _, concurrency := range opts.Concurrency {
+		if concurrency < 1 {
+			err = multierr.Append(err, fmt.Errorf("concurrency must be at least 1, got %d", concurrency))
+		}
+	}
+
+	if opts.Duration <= 0 {
+		err = multierr.Append(err, errors.New("duration must be strictly positive"))
+	}
+
+	if opts.Timeout <= 0 {
+		err = multierr.Append(err, errors.New("timeout must be strictly positive"))
+	}
+
+	return err
+}
+
+// Step is the result of a benchmark step, i.e., a number of concurrent
+// requests sustained during a duration.
+type Step struct {
+	Concurrency int           `json:"concurrency"`
+	Requests    int           `json:"requests"`
+	Failures    int           `json:"failures"`
+	Throughput  float64       `json:"throughput"`
+	P50         time.Duration `json:"p50"`
+	P90         time.Duration `json:"p90"`
+	P99         time.Duration `json:"p99"`
+	Max         time.Duration `json:"max"`
+	FirstError  string        `json:"firstError,omitempty"`
+}
+
+// FailureRate returns the ratio of failed requests.
+func (step Step) FailureRate() float64 {
+	if step.Requests == 0 {
+		return 0
+	}
+
+	return float64(step.Failures) / float64(step.Requests)
+}
+
+// Report is the result of a benchmark.
+type Report struct {
+	Steps []Step `json:"steps"`
+
+	// Saturation is the number of concurrent requests beyond which the
+	// throughput stops increasing significantly, or requests start failing.
+	// Zero if the last step did not reach this point.
+	Saturation int `json:"saturation"`
+}
+
+const (
+	// saturationMinGain is the minimum throughput gain between two steps
+	// below which the instance is considered saturated.
+	saturationMinGain = 0.1
+
+	// saturationMaxFailureRate is the failure rate above which the instance
+	// is considered saturated.
+	saturationMaxFailureRate = 0.01
+)
+
+// request is a pre-built multipart request body.
+type request struct {
+	url         string
+	contentType string
+	body        []byte
+}
+
+// Run runs the benchmark steps, one after another.
+func Run(ctx context.Context, opts Options) (Report, error) {
+	err := opts.Validate()
+	if err != nil {
+		return Report{}, fmt.Errorf("validate options: %w", err)
+	}
+
+	client := &http.Client{Timeout: opts.Timeout}
+	baseUrl := strings.TrimSuffix(opts.URL, "/")
+
+	err = checkHealth(ctx, client, baseUrl, opts.Headers)
+	if err != nil {
+		return Report{}, err
+	}
+
+	requests := make([]request, len(opts.Workloads))
+	for i, workload := range opts.Workloads {
+		requests[i], err = newRequest(baseUrl, workload)
+		if err != nil {
+			return Report{}, fmt.Errorf("build request for workload '%s': %w", workload.Name, err)
+		}
+	}
+
+	var report Report
+	for _, concurrency := range opts.Concurrency {
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		step := runStep(ctx, client, requests, opts.Headers, concurrency, opts.Duration)
+		report.Steps = append(report.Steps, step)
+
+		if opts.OnStep != nil {
+			opts.OnStep(step)
+		}
+	}
+
+	report.Saturation = saturation(report.Steps)
+
+	return report, nil
+}
+
+// checkHealth makes sure the Gotenberg instance is up before sending it any
+// workload.
+func checkHealth(ctx context.Context, client *http.Client, baseUrl string, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseUrl+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("create health request: %w", err)
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("check health of '%s': %w", baseUrl, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("check health of '%s': got status '%s'", baseUrl, resp.Status)
+	}
+
+	return nil
+}
+
+func newRequest(baseUrl string, workload Workload) (request, error) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("files", workload.Filename)
+	if err != nil {
+		return request{}, fmt.Errorf("create form file: %w", err)
+	}
+
+	_, err = part.Write(workload.Content)
+	if err != nil {
+		return request{}, fmt.Errorf("write form file: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return request{}, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	return request{
+		url:         baseUrl + workload.Route,
+		contentType: writer.FormDataContentType(),
+		body:        body.Bytes(),
+	}, nil
+}
+
+// runStep sends requests with the given concurrency until the duration
+// elapses. The requests still running at that time complete, and count.
+func runStep(ctx context.Context, client *http.Client, requests []request, headers map[string]string, concurrency int, duration time.Duration) Step {
+	stepCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var (
+		mu         sync.Mutex
+		latencies  []time.Duration
+		failures   int
+		firstError string
+		wg         sync.WaitGroup
+	)
+
+	start := time.Now()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func(offset int) {
+			defer wg.Done()
+
+			for n := offset; stepCtx.Err() == nil; n++ {
+				// The parent context, so that the running request completes
+				// after the end of the step.
+				latency, err := send(ctx, client, requests[n%len(requests)], headers)
+
+				mu.Lock()
+				latencies = append(latencies, latency)
+				if err != nil {
+					failures++
+					if firstError == "" {
+						firstError = err.Error()
+					}
+				}
+				mu.Unlock()
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	step := Step{
+		Concurrency: concurrency,
+		Requests:    len(latencies),
+		Failures:    failures,
+		FirstError:  firstError,
+	}
+
+	if elapsed > 0 {
+		step.Throughput = float64(step.Requests-step.Failures) / elapsed.Seconds()
+	}
+
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+
+	step.P50 = percentile(latencies, 50)
+	step.P90 = percentile(latencies, 90)
+	step.P99 = percentile(latencies, 99)
+	step.Max = percentile(latencies, 100)
+
+	return step
+}
+
+// send sends a request and returns its latency.
+func send(ctx context.Context, client *http.Client, r request, headers map[string]string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(r.body))
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", r.contentType)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Since(start), fmt.Errorf("send request to '%s': %w", r.url, err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	// The latency includes the download of the output file.
+	_, err = io.Copy(io.Discard, resp.Body)
+	latency := time.Since(start)
+
+	if err != nil {
+		return latency, fmt.Errorf("read response from '%s': %w", r.url, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return latency, fmt.Errorf("send request to '%s': got status '%s'", r.url, resp.Status)
+	}
+
+	return latency, nil
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// saturation returns the concurrency of the last step before the throughput
+// stops increasing significantly, or requests start failing.
+func saturation(steps []Step) int {
+	for i, step := range steps {
+		if step.FailureRate() > saturationMaxFailureRate {
+			if i == 0 {
+				return step.Concurrency
+			}
+
+			return steps[i-1].Concurrency
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		previous := steps[i-1]
+		if step.Throughput < previous.Throughput*(1+saturationMinGain) {
+			return previous.Concurrency
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,264 @@
+package bench
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		opts        Options
+		expectError bool
+	}{
+		{
+			scenario:    "empty options",
+			opts:        Options{},
+			expectError: true,
+		},
+		{
+			scenario: "invalid concurrency",
+			opts: Options{
+				URL:         "http://localhost:3000",
+				Workloads:   []Workload{{Name: "html"}},
+				Concurrency: []int{1, 0},
+				Duration:    time.Duration(1) * time.Second,
+				Timeout:     time.Duration(1) * time.Second,
+			},
+			expectError: true,
+		},
+		{
+			scenario: "valid options",
+			opts: Options{
+				URL:         "http://localhost:3000",
+				Workloads:   []Workload{{Name: "html"}},
+				Concurrency: []int{1, 2},
+				Duration:    time.Duration(1) * time.Second,
+				Timeout:     time.Duration(1) * time.Second,
+			},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.opts.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		handler        func(calls int64) int
+		healthStatus   int
+		expectError    bool
+		expectFailures bool
+	}{
+		{
+			scenario:     "unhealthy instance",
+			healthStatus: http.StatusServiceUnavailable,
+			expectError:  true,
+		},
+		{
+			scenario:     "success",
+			healthStatus: http.StatusOK,
+			handler: func(calls int64) int {
+				return http.StatusOK
+			},
+			expectError: false,
+		},
+		{
+			scenario:     "failures",
+			healthStatus: http.StatusOK,
+			handler: func(calls int64) int {
+				return http.StatusServiceUnavailable
+			},
+			expectError:    false,
+			expectFailures: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var calls atomic.Int64
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/health" {
+					w.WriteHeader(tc.healthStatus)
+					return
+				}
+
+				err := r.ParseMultipartForm(32 << 20)
+				if err != nil || len(r.MultipartForm.File["files"]) != 1 {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+
+				w.WriteHeader(tc.handler(calls.Add(1)))
+			}))
+			defer srv.Close()
+
+			workloads, err := Workloads([]string{"html", "docx"}, 1)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var steps int
+			report, err := Run(context.Background(), Options{
+				URL:         srv.URL,
+				Workloads:   workloads,
+				Concurrency: []int{1, 2},
+				Duration:    time.Duration(50) * time.Millisecond,
+				Timeout:     time.Duration(1) * time.Second,
+				OnStep: func(step Step) {
+					steps++
+				},
+			})
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if steps != 2 || len(report.Steps) != 2 {
+				t.Fatalf("expected 2 steps but got %d (%d reported)", len(report.Steps), steps)
+			}
+
+			for _, step := range report.Steps {
+				if step.Requests == 0 {
+					t.Errorf("expected requests with %d concurrent requests", step.Concurrency)
+				}
+
+				if tc.expectFailures && step.Failures != step.Requests {
+					t.Errorf("expected %d failures but got %d", step.Requests, step.Failures)
+				}
+
+				if !tc.expectFailures && step.Failures != 0 {
+					t.Errorf("expected no failures but got %d: %s", step.Failures, step.FirstError)
+				}
+			}
+
+			if tc.expectFailures && report.Saturation != 1 {
+				t.Errorf("expected saturation at 1 but got %d", report.Saturation)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	for _, tc := range []struct {
+		scenario string
+		sorted   []time.Duration
+		p        int
+		expect   time.Duration
+	}{
+		{
+			scenario: "no latencies",
+			sorted:   nil,
+			p:        50,
+			expect:   0,
+		},
+		{
+			scenario: "p50",
+			sorted:   sorted,
+			p:        50,
+			expect:   time.Duration(50) * time.Millisecond,
+		},
+		{
+			scenario: "p99",
+			sorted:   sorted,
+			p:        99,
+			expect:   time.Duration(99) * time.Millisecond,
+		},
+		{
+			scenario: "max",
+			sorted:   sorted,
+			p:        100,
+			expect:   time.Duration(100) * time.Millisecond,
+		},
+		{
+			scenario: "single latency",
+			sorted:   []time.Duration{time.Second},
+			p:        50,
+			expect:   time.Second,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := percentile(tc.sorted, tc.p)
+
+			if actual != tc.expect {
+				t.Errorf("expected %s but got %s", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestSaturation(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		steps    []Step
+		expect   int
+	}{
+		{
+			scenario: "linear scaling",
+			steps: []Step{
+				{Concurrency: 1, Requests: 10, Throughput: 1},
+				{Concurrency: 2, Requests: 20, Throughput: 2},
+				{Concurrency: 4, Requests: 40, Throughput: 4},
+			},
+			expect: 0,
+		},
+		{
+			scenario: "throughput plateau",
+			steps: []Step{
+				{Concurrency: 1, Requests: 10, Throughput: 1},
+				{Concurrency: 2, Requests: 20, Throughput: 2},
+				{Concurrency: 4, Requests: 21, Throughput: 2.1},
+			},
+			expect: 2,
+		},
+		{
+			scenario: "failures",
+			steps: []Step{
+				{Concurrency: 1, Requests: 10, Throughput: 1},
+				{Concurrency: 2, Requests: 20, Failures: 2, Throughput: 1.8},
+			},
+			expect: 1,
+		},
+		{
+			scenario: "failures from the first step",
+			steps: []Step{
+				{Concurrency: 1, Requests: 10, Failures: 10},
+			},
+			expect: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := saturation(tc.steps)
+
+			if actual != tc.expect {
+				t.Errorf("expected %d but got %d", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package bench provides the logic of the "bench" command, which sends
+// representative workloads to a Gotenberg instance with an increasing number
+// of concurrent requests, and reports the latency percentiles and the
+// saturation point.
+package bench
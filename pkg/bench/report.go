@@ -0,0 +1,73 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// WriteText writes the report as a human-readable table.
+func (r Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+
+	_, err := fmt.Fprintln(tw, "concurrency\trequests\tfailures\treq/s\tp50\tp90\tp99\tmax\t")
+	if err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+
+	for _, step := range r.Steps {
+		_, err = fmt.Fprintf(tw, "%d\t%d\t%d\t%.2f\t%s\t%s\t%s\t%s\t\n",
+			step.Concurrency,
+			step.Requests,
+			step.Failures,
+			step.Throughput,
+			step.P50.Round(time.Millisecond),
+			step.P90.Round(time.Millisecond),
+			step.P99.Round(time.Millisecond),
+			step.Max.Round(time.Millisecond),
+		)
+		if err != nil {
+			return fmt.Errorf("write step: %w", err)
+		}
+	}
+
+	err = tw.Flush()
+	if err != nil {
+		return fmt.Errorf("flush table: %w", err)
+	}
+
+	for _, step := range r.Steps {
+		if step.FirstError != "" {
+			_, err = fmt.Fprintf(w, "\nfirst error with %d concurrent requests: %s\n", step.Concurrency, step.FirstError)
+			if err != nil {
+				return fmt.Errorf("write error: %w", err)
+			}
+		}
+	}
+
+	if r.Saturation == 0 {
+		_, err = fmt.Fprintln(w, "\nsaturation not reached, try more concurrent requests")
+	} else {
+		_, err = fmt.Fprintf(w, "\nsaturation reached beyond %d concurrent requests\n", r.Saturation)
+	}
+	if err != nil {
+		return fmt.Errorf("write saturation: %w", err)
+	}
+
+	return nil
+}
+
+// WriteJson writes the report as JSON.
+func (r Report) WriteJson(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	err := encoder.Encode(r)
+	if err != nil {
+		return fmt.Errorf("encode report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport_WriteText(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		report         Report
+		expectContains []string
+	}{
+		{
+			scenario: "saturation not reached",
+			report: Report{
+				Steps: []Step{
+					{Concurrency: 1, Requests: 10, Throughput: 1, P50: time.Duration(100) * time.Millisecond},
+				},
+			},
+			expectContains: []string{"concurrency", "100ms", "saturation not reached"},
+		},
+		{
+			scenario: "saturation reached with failures",
+			report: Report{
+				Steps: []Step{
+					{Concurrency: 1, Requests: 10, Throughput: 1},
+					{Concurrency: 2, Requests: 10, Failures: 5, Throughput: 0.5, FirstError: "foo"},
+				},
+				Saturation: 1,
+			},
+			expectContains: []string{"first error with 2 concurrent requests: foo", "saturation reached beyond 1 concurrent requests"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+
+			err := tc.report.WriteText(buf)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			for _, expect := range tc.expectContains {
+				if !strings.Contains(buf.String(), expect) {
+					t.Errorf("expected '%s' in:\n%s", expect, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestReport_WriteJson(t *testing.T) {
+	report := Report{
+		Steps:      []Step{{Concurrency: 4, Requests: 42}},
+		Saturation: 4,
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := report.WriteJson(buf)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var actual Report
+	err = json.Unmarshal(buf.Bytes(), &actual)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if actual.Saturation != 4 || len(actual.Steps) != 1 || actual.Steps[0].Requests != 42 {
+		t.Errorf("expected %+v but got %+v", report, actual)
+	}
+}
@@ -0,0 +1,217 @@
+package bench
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Workload is a document sent to a route of a Gotenberg instance.
+type Workload struct {
+	// Name is the name of the workload, e.g., "docx".
+	Name string
+
+	// Route is the path of the route handling the document.
+	Route string
+
+	// Filename is the name of the document in the multipart form.
+	Filename string
+
+	// Content is the content of the document.
+	Content []byte
+}
+
+// workloadGenerators gathers the available workloads.
+var workloadGenerators = map[string]func(pages int) (Workload, error){
+	"html": htmlWorkload,
+	"docx": docxWorkload,
+	"pptx": pptxWorkload,
+}
+
+// Workloads generates the given workloads, each document having the given
+// number of pages.
+func Workloads(names []string, pages int) ([]Workload, error) {
+	if pages < 1 {
+		return nil, fmt.Errorf("number of pages must be at least 1, got %d", pages)
+	}
+
+	workloads := make([]Workload, len(names))
+	for i, name := range names {
+		generate, ok := workloadGenerators[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown workload '%s'", name)
+		}
+
+		workload, err := generate(pages)
+		if err != nil {
+			return nil, fmt.Errorf("generate workload '%s': %w", name, err)
+		}
+
+		workloads[i] = workload
+	}
+
+	return workloads, nil
+}
+
+// loremIpsum is the text of the paragraphs in the generated documents.
+const loremIpsum = "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua. Ut enim ad minim veniam, quis nostrud exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat."
+
+// paragraphsPerPage is the number of paragraphs filling roughly a page.
+const paragraphsPerPage = 8
+
+func htmlWorkload(pages int) (Workload, error) {
+	var b strings.Builder
+	b.WriteString(`<!doctype html><html><head><meta charset="utf-8"><title>Gotenberg</title>`)
+	b.WriteString(`<style>body { font-family: sans-serif; } section { page-break-after: always; } table { border-collapse: collapse; } td { border: 1px solid #ccc; padding: 4px; }</style>`)
+	b.WriteString(`</head><body>`)
+
+	for page := 1; page <= pages; page++ {
+		fmt.Fprintf(&b, "<section><h1>Page %d</h1>", page)
+
+		for i := 0; i < paragraphsPerPage/2; i++ {
+			fmt.Fprintf(&b, "<p>%s</p>", html.EscapeString(loremIpsum))
+		}
+
+		b.WriteString("<table>")
+		for row := 1; row <= 5; row++ {
+			fmt.Fprintf(&b, "<tr><td>Row %d</td><td>%d</td><td>%s</td></tr>", row, row*page, html.EscapeString(loremIpsum[:40]))
+		}
+		b.WriteString("</table></section>")
+	}
+
+	b.WriteString(`</body></html>`)
+
+	return Workload{
+		Name:     "html",
+		Route:    "/forms/chromium/convert/html",
+		Filename: "index.html",
+		Content:  []byte(b.String()),
+	}, nil
+}
+
+func docxWorkload(pages int) (Workload, error) {
+	var body strings.Builder
+	for page := 1; page <= pages; page++ {
+		fmt.Fprintf(&body, `<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Page %d</w:t></w:r></w:p>`, page)
+
+		for i := 0; i < paragraphsPerPage; i++ {
+			fmt.Fprintf(&body, `<w:p><w:r><w:t>%s</w:t></w:r></w:p>`, html.EscapeString(loremIpsum))
+		}
+
+		if page < pages {
+			body.WriteString(`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+		}
+	}
+
+	content, err := zipFiles([][2]string{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/></Relationships>`},
+		{"word/document.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + body.String() + `</w:body></w:document>`},
+	})
+	if err != nil {
+		return Workload{}, err
+	}
+
+	return Workload{
+		Name:     "docx",
+		Route:    "/forms/libreoffice/convert",
+		Filename: "document.docx",
+		Content:  content,
+	}, nil
+}
+
+func pptxWorkload(pages int) (Workload, error) {
+	const (
+		nsA       = `xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"`
+		nsP       = `xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main"`
+		nsR       = `xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+		rel       = `http://schemas.openxmlformats.org/officeDocument/2006/relationships`
+		emptyTree = `<p:cSld><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/></p:spTree></p:cSld>`
+	)
+
+	var (
+		overrides strings.Builder
+		slideIds  strings.Builder
+		slideRels strings.Builder
+	)
+
+	files := [][2]string{
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="` + rel + `/officeDocument" Target="ppt/presentation.xml"/></Relationships>`},
+		{"ppt/slideMasters/slideMaster1.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sldMaster ` + nsA + ` ` + nsP + ` ` + nsR + `>` + emptyTree + `<p:clrMap bg1="lt1" tx1="dk1" bg2="lt2" tx2="dk2" accent1="accent1" accent2="accent2" accent3="accent3" accent4="accent4" accent5="accent5" accent6="accent6" hlink="hlink" folHlink="folHlink"/><p:sldLayoutIdLst><p:sldLayoutId id="2147483649" r:id="rId1"/></p:sldLayoutIdLst></p:sldMaster>`},
+		{"ppt/slideMasters/_rels/slideMaster1.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="` + rel + `/slideLayout" Target="../slideLayouts/slideLayout1.xml"/></Relationships>`},
+		{"ppt/slideLayouts/slideLayout1.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sldLayout ` + nsA + ` ` + nsP + ` ` + nsR + `>` + emptyTree + `</p:sldLayout>`},
+		{"ppt/slideLayouts/_rels/slideLayout1.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="` + rel + `/slideMaster" Target="../slideMasters/slideMaster1.xml"/></Relationships>`},
+	}
+
+	for page := 1; page <= pages; page++ {
+		fmt.Fprintf(&overrides, `<Override PartName="/ppt/slides/slide%d.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slide+xml"/>`, page)
+		fmt.Fprintf(&slideIds, `<p:sldId id="%d" r:id="rId%d"/>`, 255+page, page+1)
+		fmt.Fprintf(&slideRels, `<Relationship Id="rId%d" Type="%s/slide" Target="slides/slide%d.xml"/>`, page+1, rel, page)
+
+		files = append(files,
+			[2]string{fmt.Sprintf("ppt/slides/slide%d.xml", page), `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:sld ` + nsA + ` ` + nsP + ` ` + nsR + `><p:cSld><p:spTree><p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/>` +
+				fmt.Sprintf(`<p:sp><p:nvSpPr><p:cNvPr id="2" name="Title"/><p:cNvSpPr/><p:nvPr/></p:nvSpPr><p:spPr><a:xfrm><a:off x="457200" y="274638"/><a:ext cx="8229600" cy="1143000"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></p:spPr><p:txBody><a:bodyPr/><a:lstStyle/><a:p><a:r><a:rPr lang="en-US" sz="4000"/><a:t>Slide %d</a:t></a:r></a:p></p:txBody></p:sp>`, page) +
+				fmt.Sprintf(`<p:sp><p:nvSpPr><p:cNvPr id="3" name="Body"/><p:cNvSpPr/><p:nvPr/></p:nvSpPr><p:spPr><a:xfrm><a:off x="457200" y="1600200"/><a:ext cx="8229600" cy="4525963"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></p:spPr><p:txBody><a:bodyPr/><a:lstStyle/><a:p><a:r><a:rPr lang="en-US" sz="2000"/><a:t>%s</a:t></a:r></a:p></p:txBody></p:sp>`, html.EscapeString(loremIpsum)) +
+				`</p:spTree></p:cSld></p:sld>`},
+			[2]string{fmt.Sprintf("ppt/slides/_rels/slide%d.xml.rels", page), `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="` + rel + `/slideLayout" Target="../slideLayouts/slideLayout1.xml"/></Relationships>`},
+		)
+	}
+
+	files = append(files,
+		[2]string{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/ppt/presentation.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.presentation.main+xml"/><Override PartName="/ppt/slideMasters/slideMaster1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideMaster+xml"/><Override PartName="/ppt/slideLayouts/slideLayout1.xml" ContentType="application/vnd.openxmlformats-officedocument.presentationml.slideLayout+xml"/>` + overrides.String() + `</Types>`},
+		[2]string{"ppt/presentation.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<p:presentation ` + nsA + ` ` + nsP + ` ` + nsR + `><p:sldMasterIdLst><p:sldMasterId id="2147483648" r:id="rId1"/></p:sldMasterIdLst><p:sldIdLst>` + slideIds.String() + `</p:sldIdLst><p:sldSz cx="9144000" cy="6858000"/><p:notesSz cx="6858000" cy="9144000"/></p:presentation>`},
+		[2]string{"ppt/_rels/presentation.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="` + rel + `/slideMaster" Target="slideMasters/slideMaster1.xml"/>` + slideRels.String() + `</Relationships>`},
+	)
+
+	content, err := zipFiles(files)
+	if err != nil {
+		return Workload{}, err
+	}
+
+	return Workload{
+		Name:     "pptx",
+		Route:    "/forms/libreoffice/convert",
+		Filename: "presentation.pptx",
+		Content:  content,
+	}, nil
+}
+
+// zipFiles creates a ZIP archive from pairs of filename and content.
+func zipFiles(files [][2]string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+
+	for _, file := range files {
+		f, err := w.Create(file[0])
+		if err != nil {
+			return nil, fmt.Errorf("create '%s': %w", file[0], err)
+		}
+
+		_, err = f.Write([]byte(file[1]))
+		if err != nil {
+			return nil, fmt.Errorf("write '%s': %w", file[0], err)
+		}
+	}
+
+	err := w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,91 @@
+package bench
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWorkloads(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		names          []string
+		pages          int
+		expectError    bool
+		expectArchives []string
+	}{
+		{
+			scenario:    "invalid number of pages",
+			names:       []string{"html"},
+			pages:       0,
+			expectError: true,
+		},
+		{
+			scenario:    "unknown workload",
+			names:       []string{"foo"},
+			pages:       1,
+			expectError: true,
+		},
+		{
+			scenario:    "html workload",
+			names:       []string{"html"},
+			pages:       3,
+			expectError: false,
+		},
+		{
+			scenario:       "office workloads",
+			names:          []string{"docx", "pptx"},
+			pages:          3,
+			expectError:    false,
+			expectArchives: []string{"document.docx", "presentation.pptx"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			workloads, err := Workloads(tc.names, tc.pages)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if len(workloads) != len(tc.names) {
+				t.Fatalf("expected %d workloads but got %d", len(tc.names), len(workloads))
+			}
+
+			for i, workload := range workloads {
+				if workload.Name != tc.names[i] {
+					t.Errorf("expected workload '%s' but got '%s'", tc.names[i], workload.Name)
+				}
+
+				if !strings.HasPrefix(workload.Route, "/forms/") {
+					t.Errorf("expected a route starting with '/forms/' but got '%s'", workload.Route)
+				}
+
+				if len(workload.Content) == 0 {
+					t.Error("expected a non-empty content")
+				}
+			}
+
+			for _, filename := range tc.expectArchives {
+				for _, workload := range workloads {
+					if workload.Filename != filename {
+						continue
+					}
+
+					_, err = zip.NewReader(bytes.NewReader(workload.Content), int64(len(workload.Content)))
+					if err != nil {
+						t.Errorf("expected '%s' to be a valid archive but got: %v", filename, err)
+					}
+				}
+			}
+		})
+	}
+}
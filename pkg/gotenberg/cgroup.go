@@ -0,0 +1,193 @@
+package gotenberg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/multierr"
+)
+
+// cpuMaxPeriod is the period, in microseconds, of the "cpu.max" file.
+const cpuMaxPeriod = 100000
+
+// CgroupLimits are the resource limits of a [Cgroup].
+type CgroupLimits struct {
+	// ParentPath is the path of the cgroup (v2) in which the cgroups are
+	// created, e.g., "/sys/fs/cgroup/gotenberg". It must be delegated to the
+	// user running Gotenberg. If empty, no cgroup is created.
+	// Optional.
+	ParentPath string
+
+	// MemoryMax is the maximum memory usage, in bytes.
+	// Optional.
+	MemoryMax int64
+
+	// CpuMax is the maximum number of CPUs, e.g., 0.5 for half a CPU.
+	// Optional.
+	CpuMax float64
+
+	// PidsMax is the maximum number of processes.
+	// Optional.
+	PidsMax int64
+}
+
+// Enabled tells if cgroups have to be created.
+func (limits CgroupLimits) Enabled() bool {
+	return limits.ParentPath != ""
+}
+
+// Validate validates the limits.
+func (limits CgroupLimits) Validate() error {
+	if !limits.Enabled() {
+		return nil
+	}
+
+	var err error
+
+	stat, statErr := os.Stat(limits.ParentPath)
+	if statErr != nil {
+		err = multierr.Append(err, fmt.Errorf("parent cgroup: %w", statErr))
+	} else if !stat.IsDir() {
+		err = multierr.Append(err, fmt.Errorf("parent cgroup '%s' is not a directory", limits.ParentPath))
+	}
+
+	if limits.MemoryMax < 0 {
+		err = multierr.Append(err, errors.New("cgroup maximum memory must be at least 0"))
+	}
+
+	if limits.CpuMax < 0 {
+		err = multierr.Append(err, errors.New("cgroup maximum number of CPUs must be at least 0"))
+	}
+
+	if limits.PidsMax < 0 {
+		err = multierr.Append(err, errors.New("cgroup maximum number of processes must be at least 0"))
+	}
+
+	return err
+}
+
+// Cgroup is a cgroup (v2) with resource limits, so that a pathological unix
+// process cannot take down the other ones.
+type Cgroup struct {
+	path string
+}
+
+// NewCgroup creates a cgroup with a unique name inside the parent cgroup and
+// applies the limits. The limits equal to 0 are not applied.
+func NewCgroup(limits CgroupLimits) (*Cgroup, error) {
+	if !limits.Enabled() {
+		return nil, errors.New("no parent cgroup")
+	}
+
+	// The controllers have to be enabled in the parent cgroup, so that they
+	// are available in its children.
+	err := os.WriteFile(filepath.Join(limits.ParentPath, "cgroup.subtree_control"), []byte("+memory +cpu +pids"), 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("enable cgroup controllers in '%s': %w", limits.ParentPath, err)
+	}
+
+	cg := &Cgroup{
+		path: filepath.Join(limits.ParentPath, uuid.NewString()),
+	}
+
+	err = os.Mkdir(cg.path, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create cgroup '%s': %w", cg.path, err)
+	}
+
+	files := make(map[string]string)
+
+	if limits.MemoryMax > 0 {
+		files["memory.max"] = strconv.FormatInt(limits.MemoryMax, 10)
+		// No swap, otherwise the memory limit is a lie.
+		files["memory.swap.max"] = "0"
+	}
+
+	if limits.CpuMax > 0 {
+		files["cpu.max"] = fmt.Sprintf("%d %d", int64(limits.CpuMax*cpuMaxPeriod), cpuMaxPeriod)
+	}
+
+	if limits.PidsMax > 0 {
+		files["pids.max"] = strconv.FormatInt(limits.PidsMax, 10)
+	}
+
+	for name, value := range files {
+		err = os.WriteFile(filepath.Join(cg.path, name), []byte(value), 0o644)
+		if err != nil && name != "memory.swap.max" {
+			removeErr := cg.Remove()
+			if removeErr != nil {
+				err = multierr.Append(err, removeErr)
+			}
+
+			return nil, fmt.Errorf("write '%s' of cgroup '%s': %w", name, cg.path, err)
+		}
+	}
+
+	return cg, nil
+}
+
+// Path returns the path of the cgroup.
+func (cg *Cgroup) Path() string {
+	return cg.path
+}
+
+// MemoryUsage returns the current memory usage, in bytes, of all the unix
+// processes in the cgroup.
+func (cg *Cgroup) MemoryUsage() (int64, error) {
+	b, err := os.ReadFile(filepath.Join(cg.path, "memory.current"))
+	if err != nil {
+		return 0, fmt.Errorf("read cgroup memory usage: %w", err)
+	}
+
+	usage, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse cgroup memory usage: %w", err)
+	}
+
+	return usage, nil
+}
+
+// Apply configures the attributes of a unix process so that it starts
+// directly inside the cgroup, with all its children. The returned file must be
+// closed once the unix process has started.
+func (cg *Cgroup) Apply(attr *syscall.SysProcAttr) (*os.File, error) {
+	dir, err := os.Open(cg.path)
+	if err != nil {
+		return nil, fmt.Errorf("open cgroup '%s': %w", cg.path, err)
+	}
+
+	attr.UseCgroupFD = true
+	attr.CgroupFD = int(dir.Fd())
+
+	return dir, nil
+}
+
+// Remove kills the remaining unix processes of the cgroup, if any, and
+// removes it.
+func (cg *Cgroup) Remove() error {
+	// Not available before Linux 5.14, hence the error is irrelevant.
+	kill, err := os.OpenFile(filepath.Join(cg.path, "cgroup.kill"), os.O_WRONLY, 0)
+	if err == nil {
+		_, _ = kill.WriteString("1")
+		_ = kill.Close()
+	}
+
+	// The kernel may take some time to release the killed unix processes.
+	for i := 0; i < 10; i++ {
+		err = os.Remove(cg.path)
+		if err == nil || os.IsNotExist(err) {
+			return nil
+		}
+
+		time.Sleep(time.Duration(100) * time.Millisecond)
+	}
+
+	return fmt.Errorf("remove cgroup '%s': %w", cg.path, err)
+}
@@ -0,0 +1,186 @@
+package gotenberg
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestCgroupLimits_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		limits      CgroupLimits
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			limits:   CgroupLimits{MemoryMax: -1},
+		},
+		{
+			scenario:    "non-existing parent cgroup",
+			limits:      CgroupLimits{ParentPath: "/foo"},
+			expectError: true,
+		},
+		{
+			scenario: "negative limits",
+			limits: CgroupLimits{
+				ParentPath: os.TempDir(),
+				MemoryMax:  -1,
+				CpuMax:     -1,
+				PidsMax:    -1,
+			},
+			expectError: true,
+		},
+		{
+			scenario: "valid limits",
+			limits: CgroupLimits{
+				ParentPath: os.TempDir(),
+				MemoryMax:  1024,
+				CpuMax:     0.5,
+				PidsMax:    64,
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.limits.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestNewCgroup(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		_, err := NewCgroup(CgroupLimits{})
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("non-existing parent cgroup", func(t *testing.T) {
+		_, err := NewCgroup(CgroupLimits{ParentPath: "/foo"})
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("limits written", func(t *testing.T) {
+		// A regular directory mimics the cgroup file system.
+		parentPath := t.TempDir()
+
+		cg, err := NewCgroup(CgroupLimits{
+			ParentPath: parentPath,
+			MemoryMax:  1024,
+			CpuMax:     0.5,
+			PidsMax:    64,
+		})
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if filepath.Dir(cg.Path()) != parentPath {
+			t.Errorf("expected cgroup inside '%s' but got '%s'", parentPath, cg.Path())
+		}
+
+		for name, expect := range map[string]string{
+			"memory.max":      "1024",
+			"memory.swap.max": "0",
+			"cpu.max":         "50000 100000",
+			"pids.max":        "64",
+		} {
+			b, err := os.ReadFile(filepath.Join(cg.Path(), name))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(b) != expect {
+				t.Errorf("expected '%s' in '%s' but got '%s'", expect, name, string(b))
+			}
+		}
+	})
+}
+
+func TestCgroup_MemoryUsage(t *testing.T) {
+	cg := &Cgroup{path: t.TempDir()}
+
+	_, err := cg.MemoryUsage()
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	err = os.WriteFile(filepath.Join(cg.path, "memory.current"), []byte("foo\n"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = cg.MemoryUsage()
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	err = os.WriteFile(filepath.Join(cg.path, "memory.current"), []byte("1048576\n"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	usage, err := cg.MemoryUsage()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if usage != 1048576 {
+		t.Errorf("expected %d but got %d", 1048576, usage)
+	}
+}
+
+func TestCgroup_Apply(t *testing.T) {
+	cg := &Cgroup{path: t.TempDir()}
+	attr := new(syscall.SysProcAttr)
+
+	dir, err := cg.Apply(attr)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		err := dir.Close()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}()
+
+	if !attr.UseCgroupFD || attr.CgroupFD != int(dir.Fd()) {
+		t.Errorf("expected the cgroup file descriptor in the attributes but got %+v", attr)
+	}
+
+	_, err = (&Cgroup{path: "/foo"}).Apply(attr)
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
+
+func TestCgroup_Remove(t *testing.T) {
+	cg := &Cgroup{path: filepath.Join(t.TempDir(), "foo")}
+
+	err := os.Mkdir(cg.path, 0o755)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = cg.Remove()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(cg.path)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected cgroup '%s' to be removed", cg.path)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -15,9 +17,16 @@ import (
 
 // Cmd wraps an [exec.Cmd].
 type Cmd struct {
-	ctx     context.Context
-	logger  *zap.Logger
-	process *exec.Cmd
+	ctx       context.Context
+	logger    *zap.Logger
+	process   *exec.Cmd
+	cgroupDir *os.File
+	// tracker accounts the memory of the unix process, if the context
+	// holds a [ProcessTracker].
+	tracker *ProcessTracker
+	// redirectedStdout is true if the stdout of the unix process is
+	// redirected with [Cmd.SetStdout].
+	redirectedStdout bool
 }
 
 // Command creates a [Cmd] without a context. It configures the internal
@@ -56,8 +65,70 @@ func CommandContext(ctx context.Context, logger *zap.Logger, binPath string, arg
 	}, nil
 }
 
+// SetCgroup makes the unix process and all its children start inside the
+// given [Cgroup].
+func (cmd *Cmd) SetCgroup(cg *Cgroup) error {
+	dir, err := cg.Apply(cmd.process.SysProcAttr)
+	if err != nil {
+		return fmt.Errorf("apply cgroup: %w", err)
+	}
+
+	cmd.cgroupDir = dir
+
+	return nil
+}
+
+// SetEnv adds environment variables, in the form "key=value", to the ones
+// inherited by the unix process.
+func (cmd *Cmd) SetEnv(env ...string) {
+	if cmd.process.Env == nil {
+		cmd.process.Env = os.Environ()
+	}
+
+	cmd.process.Env = append(cmd.process.Env, env...)
+}
+
+// SetDir sets the working directory of the unix process.
+func (cmd *Cmd) SetDir(dir string) {
+	cmd.process.Dir = dir
+}
+
+// SetStdin sets the stdin of the unix process, e.g., for sending requests
+// to a long-running process.
+func (cmd *Cmd) SetStdin(r io.Reader) {
+	cmd.process.Stdin = r
+}
+
+// SetStdout redirects the stdout of the unix process to the given writer,
+// e.g., for reading the output of a CLI tool. Such output is not logged.
+func (cmd *Cmd) SetStdout(w io.Writer) {
+	cmd.process.Stdout = w
+	cmd.redirectedStdout = true
+}
+
+// MemoryUsage returns the resident set size, in bytes, of the started unix
+// process.
+func (cmd *Cmd) MemoryUsage() (int64, error) {
+	if cmd.process.Process == nil {
+		return 0, errors.New("unix process not started")
+	}
+
+	return processMemoryUsage(fmt.Sprintf("/proc/%d/status", cmd.process.Process.Pid))
+}
+
 // Start starts the command but does not wait for its completion.
 func (cmd *Cmd) Start() error {
+	if cmd.cgroupDir != nil {
+		defer func() {
+			err := cmd.cgroupDir.Close()
+			if err != nil {
+				cmd.logger.Error(fmt.Sprintf("close cgroup directory: %s", err))
+			}
+
+			cmd.cgroupDir = nil
+		}()
+	}
+
 	err := cmd.pipeOutput()
 	if err != nil {
 		return fmt.Errorf("pipe unix process output: %w", err)
@@ -70,6 +141,11 @@ func (cmd *Cmd) Start() error {
 		return fmt.Errorf("start unix process: %w", err)
 	}
 
+	cmd.tracker = processTrackerFromContext(cmd.ctx)
+	if cmd.tracker != nil {
+		cmd.tracker.add(cmd)
+	}
+
 	return nil
 }
 
@@ -77,6 +153,11 @@ func (cmd *Cmd) Start() error {
 // Start method, so that the command does not leak zombies.
 func (cmd *Cmd) Wait() error {
 	err := cmd.process.Wait()
+
+	if cmd.tracker != nil {
+		cmd.tracker.remove(cmd)
+	}
+
 	if err != nil {
 		return fmt.Errorf("wait for unix process: %w", err)
 	}
@@ -140,11 +221,6 @@ func (cmd *Cmd) pipeOutput() error {
 		return nil
 	}
 
-	stdout, err := cmd.process.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("pipe unix process stdout: %w", err)
-	}
-
 	stderr, err := cmd.process.StderrPipe()
 	if err != nil {
 		return fmt.Errorf("unix process sdterr: %w", err)
@@ -177,12 +253,47 @@ func (cmd *Cmd) pipeOutput() error {
 		}
 	}
 
-	go logCommandOutput(cmd.logger.Named("stdout"), stdout)
 	go logCommandOutput(cmd.logger.Named("stderr"), stderr)
 
+	if cmd.redirectedStdout {
+		return nil
+	}
+
+	stdout, err := cmd.process.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("pipe unix process stdout: %w", err)
+	}
+
+	go logCommandOutput(cmd.logger.Named("stdout"), stdout)
+
 	return nil
 }
 
+// processMemoryUsage reads the resident set size of a unix process from its
+// procfs status file.
+func processMemoryUsage(statusPath string) (int64, error) {
+	b, err := os.ReadFile(statusPath)
+	if err != nil {
+		return 0, fmt.Errorf("read '%s': %w", statusPath, err)
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "VmRSS:" {
+			continue
+		}
+
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse resident set size '%s': %w", fields[1], err)
+		}
+
+		return kb * 1024, nil
+	}
+
+	return 0, fmt.Errorf("no resident set size in '%s'", statusPath)
+}
+
 // Kill kills the unix process and all its children without creating orphans.
 //
 // See https://medium.com/@felixge/killing-a-child-process-and-all-of-its-children-in-go-54079af94773.
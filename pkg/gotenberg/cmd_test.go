@@ -1,7 +1,11 @@
 package gotenberg
 
 import (
+	"bytes"
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -52,6 +56,199 @@ func TestCommandContext(t *testing.T) {
 	}
 }
 
+func TestCmd_SetCgroup(t *testing.T) {
+	cmd := Command(zap.NewNop(), "foo")
+
+	err := cmd.SetCgroup(&Cgroup{path: "/foo"})
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	err = cmd.SetCgroup(&Cgroup{path: t.TempDir()})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if cmd.cgroupDir == nil || !cmd.process.SysProcAttr.UseCgroupFD {
+		t.Error("expected the unix process to start inside the cgroup")
+	}
+
+	err = cmd.cgroupDir.Close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestCmd_SetEnv(t *testing.T) {
+	cmd := Command(zap.NewNop(), "foo")
+	cmd.SetEnv("FOO=foo")
+	cmd.SetEnv("BAR=bar")
+
+	env := cmd.process.Env
+	if len(env) < 2 || env[len(env)-2] != "FOO=foo" || env[len(env)-1] != "BAR=bar" {
+		t.Errorf("expected FOO and BAR in the environment variables but got %v", env)
+	}
+}
+
+func TestCmd_SetDir(t *testing.T) {
+	dir := t.TempDir()
+
+	cmd, err := CommandContext(context.Background(), zap.NewNop(), "pwd")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	cmd.SetDir(dir)
+
+	buf := new(bytes.Buffer)
+	cmd.SetStdout(buf)
+
+	_, err = cmd.Exec()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if buf.String() != dir+"\n" {
+		t.Errorf("expected '%s' but got '%s'", dir, buf.String())
+	}
+}
+
+func TestCmd_SetStdout(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		logger   *zap.Logger
+	}{
+		{
+			scenario: "info level",
+			logger:   zap.NewNop(),
+		},
+		{
+			scenario: "debug level",
+			logger:   zap.NewExample(),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			cmd, err := CommandContext(context.Background(), tc.logger, "echo", "foo")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			buf := new(bytes.Buffer)
+			cmd.SetStdout(buf)
+
+			_, err = cmd.Exec()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if buf.String() != "foo\n" {
+				t.Errorf("expected 'foo' but got '%s'", buf.String())
+			}
+		})
+	}
+}
+
+func TestCmd_SetStdin(t *testing.T) {
+	cmd, err := CommandContext(context.Background(), zap.NewNop(), "cat")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	buf := new(bytes.Buffer)
+	cmd.SetStdin(strings.NewReader("foo"))
+	cmd.SetStdout(buf)
+
+	_, err = cmd.Exec()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if buf.String() != "foo" {
+		t.Errorf("expected 'foo' but got '%s'", buf.String())
+	}
+}
+
+func TestCmd_MemoryUsage(t *testing.T) {
+	cmd := Command(zap.NewNop(), "sleep", "10")
+
+	_, err := cmd.MemoryUsage()
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = cmd.Kill()
+		_ = cmd.Wait()
+	}()
+
+	usage, err := cmd.MemoryUsage()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if usage <= 0 {
+		t.Errorf("expected a positive memory usage but got %d", usage)
+	}
+}
+
+func TestProcessMemoryUsage(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		content     string
+		expectUsage int64
+		expectError bool
+	}{
+		{
+			scenario:    "no resident set size",
+			content:     "Name:\tsoffice.bin\n",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid resident set size",
+			content:     "VmRSS:\t   foo kB\n",
+			expectError: true,
+		},
+		{
+			scenario:    "valid resident set size",
+			content:     "Name:\tsoffice.bin\nVmRSS:\t  2048 kB\n",
+			expectUsage: 2097152,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "status")
+
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			usage, err := processMemoryUsage(path)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if usage != tc.expectUsage {
+				t.Errorf("expected %d but got %d", tc.expectUsage, usage)
+			}
+		})
+	}
+
+	_, err := processMemoryUsage("/foo")
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
+
 func TestCmd_Start(t *testing.T) {
 	tests := []struct {
 		scenario         string
@@ -232,6 +429,16 @@ func TestCmd_pipeOutput(t *testing.T) {
 			run:                   false,
 			expectPipeOutputError: true,
 		},
+		{
+			scenario: "stdout redirected",
+			cmd: func() *Cmd {
+				cmd := Command(zap.NewExample(), "echo", "Hello", "World")
+				cmd.SetStdout(new(bytes.Buffer))
+				return cmd
+			}(),
+			run:                   true,
+			expectPipeOutputError: false,
+		},
 		{
 			scenario: "stderr already piped",
 			cmd: func() *Cmd {
@@ -0,0 +1,38 @@
+package gotenberg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Version is the version of the Gotenberg application. The application sets
+// it at startup.
+var Version = "snapshot"
+
+// Debuggable is a module interface which provides diagnostics, e.g., the
+// version of its external binary, for the debug bundle.
+type Debuggable interface {
+	Debug(ctx context.Context) (map[string]any, error)
+}
+
+// BinaryVersion returns the output of the "--version" argument of a binary.
+func BinaryVersion(ctx context.Context, logger *zap.Logger, binPath string) (string, error) {
+	cmd, err := CommandContext(ctx, logger, binPath, "--version")
+	if err != nil {
+		return "", fmt.Errorf("create command: %w", err)
+	}
+
+	var output bytes.Buffer
+	cmd.SetStdout(&output)
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return "", fmt.Errorf("get version of '%s': %w", binPath, err)
+	}
+
+	return strings.TrimSpace(output.String()), nil
+}
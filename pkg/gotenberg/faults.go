@@ -0,0 +1,23 @@
+package gotenberg
+
+import "context"
+
+const (
+	// FaultPointConversion is the point of the faults which slow down or
+	// fail the conversions. The target is the path of the route.
+	FaultPointConversion = "conversion"
+
+	// FaultPointWebhook is the point of the faults which fail the calls to
+	// the webhooks. The target is the URL of the webhook.
+	FaultPointWebhook = "webhook"
+)
+
+// FaultInjector is a module interface which injects failures on purpose, so
+// that the platform teams test their retry and alerting logic against a
+// staging instance.
+type FaultInjector interface {
+	// InjectFault applies the faults of a point whose target matches, e.g.,
+	// it waits or returns an error. It returns nil if no fault fails the
+	// operation.
+	InjectFault(ctx context.Context, point, target string) error
+}
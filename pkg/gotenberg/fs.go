@@ -7,30 +7,60 @@ import (
 	"github.com/google/uuid"
 )
 
+// Storage is a backend for the temporary directories of the modules. As
+// external processes (e.g., LibreOffice) read and write the files, a storage
+// always exposes directories of a file system, whether the underlying medium
+// is a disk or the memory (i.e., tmpfs).
+type Storage interface {
+	// RootDir returns the directory in which the working directory lives.
+	RootDir() string
+
+	// WorkingDirPath returns the path of the unique working directory.
+	WorkingDirPath() string
+
+	// MkdirAll creates a new unique directory inside the working directory
+	// and returns its path.
+	MkdirAll() (string, error)
+}
+
 // FileSystem provides utilities for managing temporary directories. It creates
 // unique directory names based on UUIDs to ensure isolation of temporary files
 // for different modules.
 type FileSystem struct {
+	rootDir    string
 	workingDir string
 }
 
 // NewFileSystem initializes a new [FileSystem] instance with a unique working
-// directory.
+// directory inside the system's temporary directory.
 func NewFileSystem() *FileSystem {
+	return NewFileSystemAt(os.TempDir())
+}
+
+// NewFileSystemAt initializes a new [FileSystem] instance with a unique
+// working directory inside the given root directory, e.g., the mount point
+// of a tmpfs.
+func NewFileSystemAt(rootDir string) *FileSystem {
 	return &FileSystem{
+		rootDir:    rootDir,
 		workingDir: uuid.NewString(),
 	}
 }
 
+// RootDir returns the directory in which the working directory lives.
+func (fs *FileSystem) RootDir() string {
+	return fs.rootDir
+}
+
 // WorkingDir returns the unique name of the working directory.
 func (fs *FileSystem) WorkingDir() string {
 	return fs.workingDir
 }
 
 // WorkingDirPath constructs and returns the full path to the working directory
-// inside the system's temporary directory.
+// inside the root directory.
 func (fs *FileSystem) WorkingDirPath() string {
-	return fmt.Sprintf("%s/%s", os.TempDir(), fs.workingDir)
+	return fmt.Sprintf("%s/%s", fs.rootDir, fs.workingDir)
 }
 
 // NewDirPath generates a new unique path for a directory inside the working
@@ -51,3 +81,8 @@ func (fs *FileSystem) MkdirAll() (string, error) {
 
 	return path, nil
 }
+
+// Interface guards.
+var (
+	_ Storage = (*FileSystem)(nil)
+)
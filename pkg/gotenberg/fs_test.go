@@ -25,6 +25,20 @@ func TestFileSystem_WorkingDirPath(t *testing.T) {
 	}
 }
 
+func TestNewFileSystemAt(t *testing.T) {
+	fs := NewFileSystemAt("/foo")
+
+	if fs.RootDir() != "/foo" {
+		t.Errorf("expected root directory '/foo' but got '%s'", fs.RootDir())
+	}
+
+	expectedPath := fmt.Sprintf("/foo/%s", fs.WorkingDir())
+
+	if fs.WorkingDirPath() != expectedPath {
+		t.Errorf("expected path '%s' but got '%s'", expectedPath, fs.WorkingDirPath())
+	}
+}
+
 func TestFileSystem_NewDirPath(t *testing.T) {
 	fs := NewFileSystem()
 	newDir := fs.NewDirPath()
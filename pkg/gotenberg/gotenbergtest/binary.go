@@ -0,0 +1,26 @@
+// Package gotenbergtest provides utilities for the tests of the modules.
+package gotenbergtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// FakeBinary writes a shell script, which stands for the given binary, in a
+// temporary directory of the test and returns its path. The script must not
+// start with a shebang.
+//
+//	binPath := gotenbergtest.FakeBinary(t, "pdftoppm", "exit 1\n")
+func FakeBinary(t testing.TB, name, script string) string {
+	t.Helper()
+
+	binPath := filepath.Join(t.TempDir(), name)
+
+	err := os.WriteFile(binPath, []byte("#!/bin/sh\n"+script), 0o700)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	return binPath
+}
@@ -0,0 +1,24 @@
+package gotenbergtest
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestFakeBinary(t *testing.T) {
+	binPath := FakeBinary(t, "foo", "echo \"$@\"\n")
+
+	if !strings.HasSuffix(binPath, "/foo") {
+		t.Errorf("expected a path ending with '/foo' but got '%s'", binPath)
+	}
+
+	output, err := exec.Command(binPath, "bar").Output()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if string(output) != "bar\n" {
+		t.Errorf("expected 'bar' but got '%s'", output)
+	}
+}
@@ -0,0 +1,113 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// ErrImageFormatNotSupported is returned when an [ImageEngine] does not
+// support a requested image format.
+var ErrImageFormatNotSupported = errors.New("image format not supported")
+
+const (
+	// ImageFormatPng represents the PNG format.
+	ImageFormatPng string = "png"
+
+	// ImageFormatJpeg represents the JPEG format.
+	ImageFormatJpeg string = "jpeg"
+
+	// ImageFormatTiff represents the TIFF format.
+	ImageFormatTiff string = "tiff"
+
+	// ImageFormatWebp represents the WebP format.
+	ImageFormatWebp string = "webp"
+)
+
+const (
+	// TiffCompressionNone represents uncompressed TIFF images.
+	TiffCompressionNone string = "none"
+
+	// TiffCompressionLzw represents the LZW compression of TIFF images.
+	TiffCompressionLzw string = "lzw"
+
+	// TiffCompressionDeflate represents the Deflate compression of TIFF
+	// images.
+	TiffCompressionDeflate string = "deflate"
+
+	// TiffCompressionPackBits represents the PackBits compression of TIFF
+	// images.
+	TiffCompressionPackBits string = "packbits"
+
+	// TiffCompressionG4 represents the CCITT Group 4 compression of TIFF
+	// images, as fax machines expect. The images are black and white.
+	TiffCompressionG4 string = "g4"
+)
+
+// ImageOptions specifies how an [ImageEngine] renders the pages of a PDF.
+type ImageOptions struct {
+	// Format is the format of the images, e.g., [ImageFormatPng].
+	Format string
+
+	// Resolution is the resolution of the images, in DPI. The images carry
+	// it as metadata.
+	Resolution int
+
+	// Quality is the quality of the JPEG images, from 1 to 100.
+	Quality int
+
+	// FirstPage and LastPage restrict the rendering to a range of pages,
+	// starting at 1. Zero means the first or the last page of the PDF.
+	FirstPage int
+	LastPage  int
+
+	// Gray tells to render the pages in grayscale.
+	Gray bool
+
+	// Width and Height are the size of the images, in pixels, instead of the
+	// one the resolution gives. If one of them is zero, the other one alone
+	// defines the size, keeping the aspect ratio of the pages.
+	Width  int
+	Height int
+
+	// Compression is the compression of the TIFF images, e.g.,
+	// [TiffCompressionLzw]. If empty, the engine chooses.
+	Compression string
+
+	// MultiPage tells to render all the pages to a unique TIFF image.
+	MultiPage bool
+
+	// StripMetadata tells to remove the metadata of the images, e.g., EXIF,
+	// but their resolution.
+	StripMetadata bool
+
+	// Transparent tells to render the pages without a white backdrop. JPEG
+	// images have no alpha channel.
+	Transparent bool
+}
+
+// ImageEngine provides an interface for rendering the pages of PDFs to
+// images.
+type ImageEngine interface {
+	// Render renders each page of a PDF to an image within outputDirPath. It
+	// returns the paths of the images, in the order of the pages, or the
+	// path of the unique image if [ImageOptions.MultiPage] is set.
+	Render(ctx context.Context, logger *zap.Logger, options ImageOptions, inputPath, outputDirPath string) ([]string, error)
+}
+
+// ImageEngineProvider offers an interface to instantiate an [ImageEngine].
+// This is used to decouple the creation of an [ImageEngine] from its
+// consumers.
+//
+// Example:
+//
+//	func (m *YourModule) Provision(ctx *gotenberg.Context) error {
+//		provider, _ := ctx.Module(new(gotenberg.ImageEngineProvider))
+//		engine, _ := provider.(gotenberg.ImageEngineProvider).ImageEngine()
+//	}
+type ImageEngineProvider interface {
+	// ImageEngine returns an instance of the [ImageEngine] interface for
+	// rendering the pages of PDFs to images.
+	ImageEngine() (ImageEngine, error)
+}
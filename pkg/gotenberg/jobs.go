@@ -0,0 +1,108 @@
+package gotenberg
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrOutboxDisabled happens if an [Outbox] is not configured for persisting
+// the webhook deliveries.
+var ErrOutboxDisabled = errors.New("outbox disabled")
+
+// ReplicaHeader is the header of the asynchronous requests a replica pulls
+// from a shared queue and sends to itself. Such requests wait for the end of
+// the asynchronous process, so that a replica does not pull more requests
+// than it may convert.
+const ReplicaHeader = "Gotenberg-Replica"
+
+// The states of a [Job].
+const (
+	// JobStateQueued is the state of a job waiting for its conversion.
+	JobStateQueued = "queued"
+
+	// JobStateRunning is the state of a job whose conversion has started.
+	JobStateRunning = "running"
+
+	// JobStateDone is the state of a job whose conversion succeeded, even
+	// if the delivery of its output file failed.
+	JobStateDone = "done"
+
+	// JobStateFailed is the state of a job whose conversion failed.
+	JobStateFailed = "failed"
+)
+
+// JobTracker is a module interface which records the lifecycle of the
+// asynchronous requests, so that their clients may recover their results,
+// e.g., after a transient failure of their webhook.
+type JobTracker interface {
+	// TrackJob creates or updates a job.
+	TrackJob(job Job)
+}
+
+// Job is an asynchronous request, as recorded by a [JobTracker].
+type Job struct {
+	// ID identifies the job. It is the trace of the request, or a random ID
+	// if its client polls for the result.
+	ID string `json:"id"`
+
+	// TokenHash is the SHA-256 hash of the token of the job, which only its
+	// client gets, if any. The routes never return it.
+	TokenHash string `json:"tokenHash,omitempty"`
+
+	// Path is the path of the route, e.g., "/forms/chromium/convert/url".
+	Path string `json:"path"`
+
+	// State is either [JobStateQueued], [JobStateRunning], [JobStateDone]
+	// or [JobStateFailed].
+	State string `json:"state"`
+
+	// Delivered tells if the webhook received the output file.
+	Delivered bool `json:"delivered"`
+
+	// Error is the message of the error of a failed conversion or delivery.
+	Error string `json:"error,omitempty"`
+
+	// Result is the output file kept by the [ResultStore], if any.
+	Result *StoredResult `json:"result,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Outbox is a module interface which persists the deliveries of the output
+// files to the webhooks until the webhooks acknowledge them, so that a crash
+// or a restart does not lose them.
+type Outbox interface {
+	// PutDelivery persists a delivery with a copy of its payload. It returns
+	// [ErrOutboxDisabled] if the outbox is not configured.
+	PutDelivery(delivery Delivery, payloadPath string) error
+
+	// PendingDeliveries returns the deliveries not acknowledged yet. It
+	// returns [ErrOutboxDisabled] if the outbox is not configured.
+	PendingDeliveries() ([]Delivery, error)
+
+	// AckDelivery removes an acknowledged delivery and its payload.
+	AckDelivery(id string) error
+}
+
+// Delivery is the intent to send the output file of a job to its webhook,
+// as persisted by an [Outbox].
+type Delivery struct {
+	// ID identifies the delivery. The webhook receives it on every attempt,
+	// so that it may ignore the deliveries repeated after a restart.
+	ID string `json:"id"`
+
+	// Job is the job as of the delivery.
+	Job Job `json:"job"`
+
+	Url              string            `json:"url"`
+	Method           string            `json:"method"`
+	Headers          map[string]string `json:"headers"`
+	ExtraHttpHeaders map[string]string `json:"extraHttpHeaders,omitempty"`
+
+	// PayloadPath is the path of the persisted payload, as set by the
+	// [Outbox].
+	PayloadPath string `json:"-"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -11,8 +11,22 @@ type Metric struct {
 	Description string
 
 	// Read returns the current value.
-	// Required.
+	// Required if no label.
 	Read func() float64
+
+	// Label is the name of the label distinguishing the values of the metric
+	// (e.g., "key"). If set, ReadLabeled is used instead of Read.
+	// Optional.
+	Label string
+
+	// ReadLabeled returns the current values by label value.
+	// Required if label.
+	ReadLabeled func() map[string]float64
+
+	// Counter tells if the metric is a counter, i.e., a value which only
+	// increases. Otherwise, it is a gauge.
+	// Optional.
+	Counter bool
 }
 
 // MetricsProvider is a module interface which provides a list of [Metric].
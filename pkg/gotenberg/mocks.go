@@ -35,8 +35,9 @@ func (mod *ValidatorMock) Validate() error {
 
 // PdfEngineMock is a mock for the [PdfEngine] interface.
 type PdfEngineMock struct {
-	MergeMock   func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error
-	ConvertMock func(ctx context.Context, logger *zap.Logger, formats PdfFormats, inputPath, outputPath string) error
+	MergeMock     func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error
+	ConvertMock   func(ctx context.Context, logger *zap.Logger, formats PdfFormats, inputPath, outputPath string) error
+	PageCountMock func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error)
 }
 
 func (engine *PdfEngineMock) Merge(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
@@ -47,6 +48,37 @@ func (engine *PdfEngineMock) Convert(ctx context.Context, logger *zap.Logger, fo
 	return engine.ConvertMock(ctx, logger, formats, inputPath, outputPath)
 }
 
+func (engine *PdfEngineMock) PageCount(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+	return engine.PageCountMock(ctx, logger, inputPath)
+}
+
+// PdfAppenderMock is a mock for the [PdfAppender] interface.
+type PdfAppenderMock struct {
+	AppendMock func(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error
+}
+
+func (appender *PdfAppenderMock) Append(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+	return appender.AppendMock(ctx, logger, outputPath, inputPaths)
+}
+
+// ImageEngineMock is a mock for the [ImageEngine] interface.
+type ImageEngineMock struct {
+	RenderMock func(ctx context.Context, logger *zap.Logger, options ImageOptions, inputPath, outputDirPath string) ([]string, error)
+}
+
+func (engine *ImageEngineMock) Render(ctx context.Context, logger *zap.Logger, options ImageOptions, inputPath, outputDirPath string) ([]string, error) {
+	return engine.RenderMock(ctx, logger, options, inputPath, outputDirPath)
+}
+
+// ImageEngineProviderMock is a mock for the [ImageEngineProvider] interface.
+type ImageEngineProviderMock struct {
+	ImageEngineMock func() (ImageEngine, error)
+}
+
+func (provider *ImageEngineProviderMock) ImageEngine() (ImageEngine, error) {
+	return provider.ImageEngineMock()
+}
+
 // PdfEngineProviderMock is a mock for the [PdfEngineProvider] interface.
 type PdfEngineProviderMock struct {
 	PdfEngineMock func() (PdfEngine, error)
@@ -127,6 +159,66 @@ func (provider *MetricsProviderMock) Metrics() ([]Metric, error) {
 	return provider.MetricsMock()
 }
 
+// SecretResolverMock is a mock for the [SecretResolver] interface.
+type SecretResolverMock struct {
+	ResolveSecretMock func(ctx context.Context, value string) (string, error)
+}
+
+func (resolver *SecretResolverMock) ResolveSecret(ctx context.Context, value string) (string, error) {
+	return resolver.ResolveSecretMock(ctx, value)
+}
+
+// ResultStoreMock is a mock for the [ResultStore] interface.
+type ResultStoreMock struct {
+	StoreResultMock  func(outputPath, filename string) (StoredResult, error)
+	DeleteResultMock func(id string) error
+}
+
+func (store *ResultStoreMock) StoreResult(outputPath, filename string) (StoredResult, error) {
+	return store.StoreResultMock(outputPath, filename)
+}
+
+func (store *ResultStoreMock) DeleteResult(id string) error {
+	return store.DeleteResultMock(id)
+}
+
+// JobTrackerMock is a mock for the [JobTracker] interface.
+type JobTrackerMock struct {
+	TrackJobMock func(job Job)
+}
+
+func (tracker *JobTrackerMock) TrackJob(job Job) {
+	tracker.TrackJobMock(job)
+}
+
+// OutboxMock is a mock for the [Outbox] interface.
+type OutboxMock struct {
+	PutDeliveryMock       func(delivery Delivery, payloadPath string) error
+	PendingDeliveriesMock func() ([]Delivery, error)
+	AckDeliveryMock       func(id string) error
+}
+
+func (outbox *OutboxMock) PutDelivery(delivery Delivery, payloadPath string) error {
+	return outbox.PutDeliveryMock(delivery, payloadPath)
+}
+
+func (outbox *OutboxMock) PendingDeliveries() ([]Delivery, error) {
+	return outbox.PendingDeliveriesMock()
+}
+
+func (outbox *OutboxMock) AckDelivery(id string) error {
+	return outbox.AckDeliveryMock(id)
+}
+
+// FaultInjectorMock is a mock for the [FaultInjector] interface.
+type FaultInjectorMock struct {
+	InjectFaultMock func(ctx context.Context, point, target string) error
+}
+
+func (injector *FaultInjectorMock) InjectFault(ctx context.Context, point, target string) error {
+	return injector.InjectFaultMock(ctx, point, target)
+}
+
 // Interface guards.
 var (
 	_ Module            = (*ModuleMock)(nil)
@@ -137,4 +229,9 @@ var (
 	_ ProcessSupervisor = (*ProcessSupervisorMock)(nil)
 	_ LoggerProvider    = (*LoggerProviderMock)(nil)
 	_ MetricsProvider   = (*MetricsProviderMock)(nil)
+	_ SecretResolver    = (*SecretResolverMock)(nil)
+	_ ResultStore       = (*ResultStoreMock)(nil)
+	_ JobTracker        = (*JobTrackerMock)(nil)
+	_ Outbox            = (*OutboxMock)(nil)
+	_ FaultInjector     = (*FaultInjectorMock)(nil)
 )
@@ -55,6 +55,9 @@ func TestPDFEngineMock(t *testing.T) {
 		ConvertMock: func(ctx context.Context, logger *zap.Logger, formats PdfFormats, inputPath, outputPath string) error {
 			return nil
 		},
+		PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+			return 1, nil
+		},
 	}
 
 	err := mock.Merge(context.Background(), zap.NewNop(), nil, "")
@@ -66,6 +69,50 @@ func TestPDFEngineMock(t *testing.T) {
 	if err != nil {
 		t.Errorf("expected no error from PdfEngineMock.Convert, but got: %v", err)
 	}
+
+	_, err = mock.PageCount(context.Background(), zap.NewNop(), "")
+	if err != nil {
+		t.Errorf("expected no error from PdfEngineMock.PageCount, but got: %v", err)
+	}
+}
+
+func TestPdfAppenderMock(t *testing.T) {
+	mock := &PdfAppenderMock{
+		AppendMock: func(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+			return nil
+		},
+	}
+
+	err := mock.Append(context.Background(), zap.NewNop(), "", nil)
+	if err != nil {
+		t.Errorf("expected no error from PdfAppenderMock.Append, but got: %v", err)
+	}
+}
+
+func TestImageEngineMock(t *testing.T) {
+	mock := &ImageEngineMock{
+		RenderMock: func(ctx context.Context, logger *zap.Logger, options ImageOptions, inputPath, outputDirPath string) ([]string, error) {
+			return nil, nil
+		},
+	}
+
+	_, err := mock.Render(context.Background(), zap.NewNop(), ImageOptions{}, "", "")
+	if err != nil {
+		t.Errorf("expected no error from ImageEngineMock.Render, but got: %v", err)
+	}
+}
+
+func TestImageEngineProviderMock(t *testing.T) {
+	mock := &ImageEngineProviderMock{
+		ImageEngineMock: func() (ImageEngine, error) {
+			return new(ImageEngineMock), nil
+		},
+	}
+
+	_, err := mock.ImageEngine()
+	if err != nil {
+		t.Errorf("expected no error from ImageEngineProviderMock.ImageEngine, but got: %v", err)
+	}
 }
 
 func TestPDFEngineProviderMock(t *testing.T) {
@@ -188,3 +235,94 @@ func TestMetricsProviderMock(t *testing.T) {
 		t.Errorf("expected no error from MetricsProviderMock.Metrics, but got: %v", err)
 	}
 }
+
+func TestSecretResolverMock(t *testing.T) {
+	mock := &SecretResolverMock{
+		ResolveSecretMock: func(ctx context.Context, value string) (string, error) {
+			return "", nil
+		},
+	}
+
+	_, err := mock.ResolveSecret(context.Background(), "foo")
+	if err != nil {
+		t.Errorf("expected no error from SecretResolverMock.ResolveSecret, but got: %v", err)
+	}
+}
+
+func TestResultStoreMock(t *testing.T) {
+	mock := &ResultStoreMock{
+		StoreResultMock: func(outputPath, filename string) (StoredResult, error) {
+			return StoredResult{}, nil
+		},
+		DeleteResultMock: func(id string) error {
+			return nil
+		},
+	}
+
+	_, err := mock.StoreResult("/foo.pdf", "foo.pdf")
+	if err != nil {
+		t.Errorf("expected no error from ResultStoreMock.StoreResult, but got: %v", err)
+	}
+
+	err = mock.DeleteResult("foo")
+	if err != nil {
+		t.Errorf("expected no error from ResultStoreMock.DeleteResult, but got: %v", err)
+	}
+}
+
+func TestJobTrackerMock(t *testing.T) {
+	var tracked bool
+
+	mock := &JobTrackerMock{
+		TrackJobMock: func(job Job) {
+			tracked = true
+		},
+	}
+
+	mock.TrackJob(Job{ID: "foo"})
+	if !tracked {
+		t.Error("expected JobTrackerMock.TrackJob to track the job")
+	}
+}
+
+func TestOutboxMock(t *testing.T) {
+	mock := &OutboxMock{
+		PutDeliveryMock: func(delivery Delivery, payloadPath string) error {
+			return nil
+		},
+		PendingDeliveriesMock: func() ([]Delivery, error) {
+			return nil, nil
+		},
+		AckDeliveryMock: func(id string) error {
+			return nil
+		},
+	}
+
+	err := mock.PutDelivery(Delivery{ID: "foo"}, "/foo.pdf")
+	if err != nil {
+		t.Errorf("expected no error from OutboxMock.PutDelivery, but got: %v", err)
+	}
+
+	_, err = mock.PendingDeliveries()
+	if err != nil {
+		t.Errorf("expected no error from OutboxMock.PendingDeliveries, but got: %v", err)
+	}
+
+	err = mock.AckDelivery("foo")
+	if err != nil {
+		t.Errorf("expected no error from OutboxMock.AckDelivery, but got: %v", err)
+	}
+}
+
+func TestFaultInjectorMock(t *testing.T) {
+	mock := &FaultInjectorMock{
+		InjectFaultMock: func(ctx context.Context, point, target string) error {
+			return nil
+		},
+	}
+
+	err := mock.InjectFault(context.Background(), FaultPointWebhook, "http://localhost/")
+	if err != nil {
+		t.Errorf("expected no error from FaultInjectorMock.InjectFault, but got: %v", err)
+	}
+}
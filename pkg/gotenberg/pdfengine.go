@@ -51,6 +51,10 @@ type PdfFormats struct {
 	// PdfUa indicates whether the PDF should comply
 	// with the PDF/UA (Universal Accessibility) standard.
 	PdfUa bool
+
+	// PdfVersion denotes the version of the PDF specification (e.g., 1.4).
+	// As each PDF/A standard mandates its version, it excludes PdfA.
+	PdfVersion string
 }
 
 // PdfEngine provides an interface for operations on PDFs. Implementations
@@ -64,6 +68,17 @@ type PdfEngine interface {
 	// Convert transforms a given PDF to the specified formats defined in
 	// PdfFormats. If no format, it does nothing.
 	Convert(ctx context.Context, logger *zap.Logger, formats PdfFormats, inputPath, outputPath string) error
+
+	// PageCount returns the number of pages of a given PDF.
+	PageCount(ctx context.Context, logger *zap.Logger, inputPath string) (int, error)
+}
+
+// PdfAppender is implemented by the [PdfEngine] which may append PDFs to an
+// existing PDF, in place. It allows merging the PDFs of a request as soon as
+// they are ready, instead of keeping all of them until the end.
+type PdfAppender interface {
+	// Append appends the given PDFs, in order, to the PDF at outputPath.
+	Append(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error
 }
 
 // PdfEngineProvider offers an interface to instantiate a [PdfEngine].
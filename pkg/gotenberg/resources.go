@@ -0,0 +1,111 @@
+package gotenberg
+
+import (
+	"context"
+	"sync"
+	"syscall"
+)
+
+// processTrackerKey is the key of the [ProcessTracker] of a context.
+type processTrackerKey struct{}
+
+// ProcessTracker accounts the memory of the unix processes a request runs,
+// e.g., a PDF engine, so that a module may report or limit it. The [Cmd]
+// created with a context holding a tracker register their processes in it.
+type ProcessTracker struct {
+	processes map[*Cmd]struct{}
+	peak      int64
+	mu        sync.Mutex
+}
+
+// NewProcessTracker creates a [ProcessTracker].
+func NewProcessTracker() *ProcessTracker {
+	return &ProcessTracker{
+		processes: make(map[*Cmd]struct{}),
+	}
+}
+
+// WithProcessTracker returns a copy of the context holding the given
+// [ProcessTracker].
+func WithProcessTracker(ctx context.Context, tracker *ProcessTracker) context.Context {
+	return context.WithValue(ctx, processTrackerKey{}, tracker)
+}
+
+// processTrackerFromContext returns the [ProcessTracker] of a context, if
+// any.
+func processTrackerFromContext(ctx context.Context) *ProcessTracker {
+	if ctx == nil {
+		return nil
+	}
+
+	tracker, _ := ctx.Value(processTrackerKey{}).(*ProcessTracker)
+
+	return tracker
+}
+
+// Sample reads the resident set size of the running processes, and returns
+// their sum, in bytes. It also updates the peak.
+func (t *ProcessTracker) Sample() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var rss int64
+
+	for cmd := range t.processes {
+		usage, err := cmd.MemoryUsage()
+		if err != nil {
+			// The process may have just exited.
+			continue
+		}
+
+		rss += usage
+	}
+
+	if rss > t.peak {
+		t.peak = rss
+	}
+
+	return rss
+}
+
+// Peak returns the highest resident set size, in bytes, of the processes
+// running at once, as far as the samples and the exited processes tell.
+func (t *ProcessTracker) Peak() int64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.peak
+}
+
+// add registers a started process.
+func (t *ProcessTracker) add(cmd *Cmd) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.processes[cmd] = struct{}{}
+}
+
+// remove unregisters an exited process. As the samples may miss a short
+// spike, the maximum resident set size the kernel reports for the process
+// counts too.
+func (t *ProcessTracker) remove(cmd *Cmd) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.processes, cmd)
+
+	if cmd.process.ProcessState == nil {
+		return
+	}
+
+	rusage, ok := cmd.process.ProcessState.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return
+	}
+
+	// Linux reports the maximum resident set size in kilobytes.
+	maxRss := rusage.Maxrss * 1024
+	if maxRss > t.peak {
+		t.peak = maxRss
+	}
+}
@@ -0,0 +1,64 @@
+package gotenberg
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestProcessTrackerFromContext(t *testing.T) {
+	//nolint:staticcheck
+	if processTrackerFromContext(nil) != nil {
+		t.Error("expected no tracker with a nil context")
+	}
+
+	if processTrackerFromContext(context.Background()) != nil {
+		t.Error("expected no tracker")
+	}
+
+	tracker := NewProcessTracker()
+	if processTrackerFromContext(WithProcessTracker(context.Background(), tracker)) != tracker {
+		t.Error("expected the tracker of the context")
+	}
+}
+
+func TestProcessTracker(t *testing.T) {
+	tracker := NewProcessTracker()
+
+	if tracker.Sample() != 0 {
+		t.Error("expected no memory without a process")
+	}
+
+	ctx := WithProcessTracker(context.Background(), tracker)
+
+	cmd, err := CommandContext(ctx, zap.NewNop(), "sleep", "10")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	rss := tracker.Sample()
+	if rss <= 0 {
+		t.Errorf("expected a positive memory usage but got %d", rss)
+	}
+
+	if tracker.Peak() < rss {
+		t.Errorf("expected a peak of at least %d but got %d", rss, tracker.Peak())
+	}
+
+	_ = cmd.Kill()
+	_ = cmd.Wait()
+
+	if tracker.Sample() != 0 {
+		t.Error("expected no memory after the process exited")
+	}
+
+	if tracker.Peak() < rss {
+		t.Errorf("expected a peak of at least %d but got %d", rss, tracker.Peak())
+	}
+}
@@ -0,0 +1,36 @@
+package gotenberg
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrResultStoreDisabled happens if a [ResultStore] is not configured for
+// keeping the output files.
+var ErrResultStoreDisabled = errors.New("result store disabled")
+
+// ResultStore is a module interface which keeps the output files of the
+// asynchronous requests and returns signed, expiring URLs for downloading
+// them, e.g., from a browser, without the credentials of the original
+// request.
+type ResultStore interface {
+	// StoreResult keeps a copy of the output file under the given filename.
+	// It returns [ErrResultStoreDisabled] if the store is not configured.
+	StoreResult(outputPath, filename string) (StoredResult, error)
+
+	// DeleteResult removes an output file before its expiration. Removing a
+	// missing or expired output file is not an error.
+	DeleteResult(id string) error
+}
+
+// StoredResult is an output file kept by a [ResultStore].
+type StoredResult struct {
+	// ID identifies the output file within the store.
+	ID string `json:"-"`
+
+	Url       string    `json:"url"`
+	Filename  string    `json:"filename"`
+	Size      int64     `json:"size"`
+	Sha256    string    `json:"sha256"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,452 @@
+package gotenberg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"go.uber.org/multierr"
+	"golang.org/x/sys/unix"
+)
+
+// SandboxExecCommand is the hidden command of the Gotenberg binary which
+// installs a seccomp filter before executing a sandboxed unix process. See
+// [RunSandboxExec].
+const SandboxExecCommand = "sandbox-exec"
+
+const (
+	// seccompDataNrOffset and seccompDataArchOffset are the offsets of the
+	// syscall number and of the architecture in the seccomp_data struct.
+	seccompDataNrOffset   = 0
+	seccompDataArchOffset = 4
+
+	seccompRetKillProcess = 0x80000000
+	seccompRetErrno       = 0x00050000
+	seccompRetAllow       = 0x7fff0000
+
+	seccompSetModeFilter   = 1
+	seccompFilterFlagTsync = 1
+
+	// x32SyscallBit flags the syscalls of the x32 ABI on amd64, which
+	// would bypass the syscall numbers of the filter otherwise.
+	x32SyscallBit = 0x40000000
+)
+
+// sandboxNamespaces are the namespaces a sandboxed unix process may run in.
+// The network namespace is not one of them, as the modules talk to their
+// unix processes through the loopback interface.
+var sandboxNamespaces = map[string]uintptr{
+	"user": syscall.CLONE_NEWUSER,
+	"ipc":  syscall.CLONE_NEWIPC,
+	"pid":  syscall.CLONE_NEWPID,
+	"uts":  syscall.CLONE_NEWUTS,
+}
+
+// seccompSyscalls are the syscalls a seccomp filter may deny. They exist on
+// both amd64 and arm64.
+var seccompSyscalls = map[string]uintptr{
+	"acct":              unix.SYS_ACCT,
+	"add_key":           unix.SYS_ADD_KEY,
+	"bpf":               unix.SYS_BPF,
+	"clock_settime":     unix.SYS_CLOCK_SETTIME,
+	"delete_module":     unix.SYS_DELETE_MODULE,
+	"finit_module":      unix.SYS_FINIT_MODULE,
+	"init_module":       unix.SYS_INIT_MODULE,
+	"kcmp":              unix.SYS_KCMP,
+	"kexec_file_load":   unix.SYS_KEXEC_FILE_LOAD,
+	"kexec_load":        unix.SYS_KEXEC_LOAD,
+	"keyctl":            unix.SYS_KEYCTL,
+	"lookup_dcookie":    unix.SYS_LOOKUP_DCOOKIE,
+	"mount":             unix.SYS_MOUNT,
+	"name_to_handle_at": unix.SYS_NAME_TO_HANDLE_AT,
+	"open_by_handle_at": unix.SYS_OPEN_BY_HANDLE_AT,
+	"perf_event_open":   unix.SYS_PERF_EVENT_OPEN,
+	"pivot_root":        unix.SYS_PIVOT_ROOT,
+	"process_vm_readv":  unix.SYS_PROCESS_VM_READV,
+	"process_vm_writev": unix.SYS_PROCESS_VM_WRITEV,
+	"ptrace":            unix.SYS_PTRACE,
+	"quotactl":          unix.SYS_QUOTACTL,
+	"reboot":            unix.SYS_REBOOT,
+	"request_key":       unix.SYS_REQUEST_KEY,
+	"setns":             unix.SYS_SETNS,
+	"settimeofday":      unix.SYS_SETTIMEOFDAY,
+	"swapoff":           unix.SYS_SWAPOFF,
+	"swapon":            unix.SYS_SWAPON,
+	"syslog":            unix.SYS_SYSLOG,
+	"umount2":           unix.SYS_UMOUNT2,
+	"unshare":           unix.SYS_UNSHARE,
+	"userfaultfd":       unix.SYS_USERFAULTFD,
+	"vhangup":           unix.SYS_VHANGUP,
+}
+
+// DefaultSeccompDeny are the syscalls the seccomp filter denies, i.e.,
+// administration syscalls a document converter has no use for.
+var DefaultSeccompDeny = []string{
+	"acct",
+	"add_key",
+	"bpf",
+	"clock_settime",
+	"delete_module",
+	"finit_module",
+	"init_module",
+	"kcmp",
+	"kexec_file_load",
+	"kexec_load",
+	"keyctl",
+	"lookup_dcookie",
+	"mount",
+	"name_to_handle_at",
+	"open_by_handle_at",
+	"perf_event_open",
+	"pivot_root",
+	"process_vm_readv",
+	"process_vm_writev",
+	"quotactl",
+	"reboot",
+	"request_key",
+	"settimeofday",
+	"swapoff",
+	"swapon",
+	"syslog",
+	"umount2",
+	"userfaultfd",
+	"vhangup",
+}
+
+// SandboxOptions tighten the unix processes of a module, e.g., LibreOffice,
+// so that a hostile document has less room if it exploits a flaw of the
+// converter.
+type SandboxOptions struct {
+	// Namespaces are the namespaces the unix process runs in, among "user",
+	// "ipc", "pid" and "uts". Unless Gotenberg runs as root, the other
+	// namespaces require the user namespace, which the host must allow
+	// for unprivileged users.
+	// Optional.
+	Namespaces []string
+
+	// Seccomp enables a seccomp filter denying the [DefaultSeccompDeny]
+	// syscalls with an EPERM error.
+	// Optional.
+	Seccomp bool
+
+	// SeccompDeny are the syscalls the seccomp filter denies on top of the
+	// [DefaultSeccompDeny] ones.
+	// Optional.
+	SeccompDeny []string
+
+	// Uid and Gid are the dedicated user and group the unix process runs
+	// as, so that a compromised converter cannot read the files of the
+	// other ones. It requires Gotenberg to run as root. Set to 0 to keep
+	// the user and group of Gotenberg.
+	// Optional.
+	Uid int
+	Gid int
+}
+
+// Enabled tells if the unix process has to be sandboxed.
+func (opts SandboxOptions) Enabled() bool {
+	return len(opts.Namespaces) > 0 || opts.Seccomp || opts.Uid != 0
+}
+
+// Strict returns a copy of the options with the seccomp filter and all the
+// namespaces enabled, e.g., for the unix processes handling suspicious
+// inputs. There is no user namespace with a dedicated user, as the latter
+// requires Gotenberg to run as root.
+func (opts SandboxOptions) Strict() SandboxOptions {
+	strict := opts
+	strict.Seccomp = true
+	strict.Namespaces = []string{"ipc", "pid", "uts"}
+
+	if opts.Uid == 0 {
+		strict.Namespaces = append(strict.Namespaces, "user")
+	}
+
+	return strict
+}
+
+// Validate validates the options.
+func (opts SandboxOptions) Validate() error {
+	var err error
+
+	for _, namespace := range opts.Namespaces {
+		_, ok := sandboxNamespaces[namespace]
+		if !ok {
+			err = multierr.Append(err, fmt.Errorf("unknown sandbox namespace '%s', expected either 'user', 'ipc', 'pid' or 'uts'", namespace))
+		}
+	}
+
+	if len(opts.Namespaces) > 0 && !slices.Contains(opts.Namespaces, "user") && os.Geteuid() != 0 {
+		err = multierr.Append(err, errors.New("sandbox namespaces require the 'user' namespace when not running as root"))
+	}
+
+	if opts.Uid < 0 || opts.Gid < 0 {
+		err = multierr.Append(err, errors.New("sandbox user and group must be positive"))
+	}
+
+	if (opts.Uid == 0) != (opts.Gid == 0) {
+		err = multierr.Append(err, errors.New("sandbox user and group must be set together"))
+	}
+
+	if opts.Uid > 0 && os.Geteuid() != 0 {
+		err = multierr.Append(err, errors.New("sandbox user requires Gotenberg to run as root"))
+	}
+
+	if opts.Uid > 0 && slices.Contains(opts.Namespaces, "user") {
+		err = multierr.Append(err, errors.New("sandbox user is not compatible with the 'user' namespace"))
+	}
+
+	if len(opts.SeccompDeny) > 0 && !opts.Seccomp {
+		err = multierr.Append(err, errors.New("denied syscalls require the seccomp filter"))
+	}
+
+	if opts.Seccomp {
+		_, filterErr := seccompFilter(runtime.GOARCH, append(DefaultSeccompDeny, opts.SeccompDeny...))
+		if filterErr != nil {
+			err = multierr.Append(err, fmt.Errorf("seccomp filter: %w", filterErr))
+		}
+	}
+
+	return err
+}
+
+// Apply configures an [exec.Cmd] so that its unix process starts in the
+// sandbox. With a seccomp filter, the Gotenberg binary starts first, thanks
+// to its [SandboxExecCommand], and executes the unix process once the
+// filter is installed.
+func (opts SandboxOptions) Apply(cmd *exec.Cmd) error {
+	if !opts.Enabled() {
+		return nil
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+
+	for _, namespace := range opts.Namespaces {
+		flag, ok := sandboxNamespaces[namespace]
+		if !ok {
+			return fmt.Errorf("unknown sandbox namespace '%s'", namespace)
+		}
+
+		cmd.SysProcAttr.Cloneflags |= flag
+	}
+
+	if slices.Contains(opts.Namespaces, "user") {
+		// The unix process keeps the user and group of Gotenberg, but they
+		// have no privileges outside the namespace.
+		cmd.SysProcAttr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+		cmd.SysProcAttr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+		cmd.SysProcAttr.GidMappingsEnableSetgroups = false
+	}
+
+	if opts.Uid > 0 {
+		// No supplementary groups either.
+		cmd.SysProcAttr.Credential = &syscall.Credential{
+			Uid: uint32(opts.Uid),
+			Gid: uint32(opts.Gid),
+		}
+	}
+
+	if !opts.Seccomp {
+		return nil
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("get Gotenberg binary path: %w", err)
+	}
+
+	args := []string{self, SandboxExecCommand}
+	if len(opts.SeccompDeny) > 0 {
+		args = append(args, "--seccomp-deny="+strings.Join(opts.SeccompDeny, ","))
+	}
+
+	args = append(args, "--", cmd.Path)
+	args = append(args, cmd.Args[1:]...)
+
+	cmd.Path = self
+	cmd.Args = args
+
+	return nil
+}
+
+// Own gives a directory and its content to the dedicated user and group of
+// the sandbox, if any, and makes the directory private to them. Gotenberg,
+// running as root, still has access to it.
+func (opts SandboxOptions) Own(dirPath string) error {
+	if opts.Uid == 0 {
+		return nil
+	}
+
+	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		return os.Lchown(path, opts.Uid, opts.Gid)
+	})
+	if err != nil {
+		return fmt.Errorf("change owner of '%s': %w", dirPath, err)
+	}
+
+	err = os.Chmod(dirPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("change mode of '%s': %w", dirPath, err)
+	}
+
+	return nil
+}
+
+// SetSandbox makes the unix process start in the sandbox of the given
+// [SandboxOptions].
+func (cmd *Cmd) SetSandbox(opts SandboxOptions) error {
+	err := opts.Apply(cmd.process)
+	if err != nil {
+		return fmt.Errorf("apply sandbox: %w", err)
+	}
+
+	return nil
+}
+
+// RunSandboxExec is the [SandboxExecCommand] of the Gotenberg binary, i.e.,
+// "sandbox-exec [--seccomp-deny=syscall,...] -- binPath [args...]". It
+// installs the seccomp filter and replaces itself with the unix process. It
+// returns an exit code only if it fails to do so.
+func RunSandboxExec(args []string) int {
+	deny := slices.Clone(DefaultSeccompDeny)
+
+	for len(args) > 0 && args[0] != "--" {
+		value, ok := strings.CutPrefix(args[0], "--seccomp-deny=")
+		if !ok {
+			fmt.Fprintf(os.Stderr, "%s: unknown argument '%s'\n", SandboxExecCommand, args[0])
+			return 2
+		}
+
+		deny = append(deny, strings.Split(value, ",")...)
+		args = args[1:]
+	}
+
+	if len(args) < 2 {
+		fmt.Fprintf(os.Stderr, "usage: %s [--seccomp-deny=syscall,...] -- binPath [args...]\n", SandboxExecCommand)
+		return 2
+	}
+
+	filter, err := seccompFilter(runtime.GOARCH, deny)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", SandboxExecCommand, err)
+		return 1
+	}
+
+	err = installSeccompFilter(filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", SandboxExecCommand, err)
+		return 1
+	}
+
+	binPath, err := exec.LookPath(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", SandboxExecCommand, err)
+		return 127
+	}
+
+	err = syscall.Exec(binPath, args[1:], os.Environ())
+
+	fmt.Fprintf(os.Stderr, "%s: execute '%s': %s\n", SandboxExecCommand, binPath, err)
+
+	return 126
+}
+
+// seccompFilter returns the BPF program of a seccomp filter which denies
+// the given syscalls with an EPERM error, allows the other ones, and kills
+// the unix process if the architecture is not the expected one. The syscall
+// numbers are the ones of the architecture Gotenberg was built for.
+func seccompFilter(arch string, deny []string) ([]unix.SockFilter, error) {
+	var auditArch uint32
+
+	switch arch {
+	case "amd64":
+		auditArch = unix.AUDIT_ARCH_X86_64
+	case "arm64":
+		auditArch = unix.AUDIT_ARCH_AARCH64
+	default:
+		return nil, fmt.Errorf("unsupported architecture '%s'", arch)
+	}
+
+	var nrs []uint32
+	for _, name := range deny {
+		nr, ok := seccompSyscalls[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown syscall '%s'", name)
+		}
+
+		if !slices.Contains(nrs, uint32(nr)) {
+			nrs = append(nrs, uint32(nr))
+		}
+	}
+
+	filter := []unix.SockFilter{
+		{Code: unix.BPF_LD | unix.BPF_W | unix.BPF_ABS, K: seccompDataArchOffset},
+		{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, Jt: 1, K: auditArch},
+		{Code: unix.BPF_RET | unix.BPF_K, K: seccompRetKillProcess},
+		{Code: unix.BPF_LD | unix.BPF_W | unix.BPF_ABS, K: seccompDataNrOffset},
+	}
+
+	jumps := len(nrs)
+	if arch == "amd64" {
+		jumps++
+	}
+
+	// The jumps go to the last instruction, which denies the syscall, and
+	// their offsets must fit in a byte.
+	if jumps+1 > 255 {
+		return nil, fmt.Errorf("too many syscalls to deny: %d", len(nrs))
+	}
+
+	offset := func() uint8 {
+		return uint8(jumps - (len(filter) - 4))
+	}
+
+	if arch == "amd64" {
+		filter = append(filter, unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JGE | unix.BPF_K, Jt: offset(), K: x32SyscallBit})
+	}
+
+	for _, nr := range nrs {
+		filter = append(filter, unix.SockFilter{Code: unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K, Jt: offset(), K: nr})
+	}
+
+	filter = append(filter,
+		unix.SockFilter{Code: unix.BPF_RET | unix.BPF_K, K: seccompRetAllow},
+		unix.SockFilter{Code: unix.BPF_RET | unix.BPF_K, K: seccompRetErrno | uint32(unix.EPERM)},
+	)
+
+	return filter, nil
+}
+
+// installSeccompFilter installs a seccomp filter on all the threads of the
+// current process. The filter applies to the unix processes it executes.
+func installSeccompFilter(filter []unix.SockFilter) error {
+	err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0)
+	if err != nil {
+		return fmt.Errorf("set no new privileges: %w", err)
+	}
+
+	prog := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+
+	_, _, errno := unix.Syscall(unix.SYS_SECCOMP, seccompSetModeFilter, seccompFilterFlagTsync, uintptr(unsafe.Pointer(&prog)))
+	if errno != 0 {
+		return fmt.Errorf("install seccomp filter: %w", errno)
+	}
+
+	return nil
+}
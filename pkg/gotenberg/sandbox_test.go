@@ -0,0 +1,321 @@
+package gotenberg
+
+import (
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"slices"
+	"syscall"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestSandboxOptions_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		opts        SandboxOptions
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			opts:     SandboxOptions{},
+		},
+		{
+			scenario:    "unknown namespace",
+			opts:        SandboxOptions{Namespaces: []string{"user", "net"}},
+			expectError: true,
+		},
+		{
+			scenario: "user namespace",
+			opts:     SandboxOptions{Namespaces: []string{"user", "ipc", "pid", "uts"}},
+		},
+		{
+			scenario:    "unknown syscall",
+			opts:        SandboxOptions{Seccomp: true, SeccompDeny: []string{"foo"}},
+			expectError: true,
+		},
+		{
+			scenario:    "denied syscalls without seccomp",
+			opts:        SandboxOptions{SeccompDeny: []string{"ptrace"}},
+			expectError: true,
+		},
+		{
+			scenario:    "negative user",
+			opts:        SandboxOptions{Uid: -1, Gid: -1},
+			expectError: true,
+		},
+		{
+			scenario:    "user without group",
+			opts:        SandboxOptions{Uid: 1001},
+			expectError: true,
+		},
+		{
+			scenario:    "user with the user namespace",
+			opts:        SandboxOptions{Namespaces: []string{"user"}, Uid: 1001, Gid: 1001},
+			expectError: true,
+		},
+		{
+			scenario:    "user without root",
+			opts:        SandboxOptions{Uid: 1001, Gid: 1001},
+			expectError: os.Geteuid() != 0,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.opts.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestSandboxOptions_Strict(t *testing.T) {
+	opts := SandboxOptions{SeccompDeny: []string{"ptrace"}}
+
+	strict := opts.Strict()
+	if !strict.Seccomp {
+		t.Error("expected the seccomp filter")
+	}
+
+	expectNamespaces := []string{"ipc", "pid", "uts", "user"}
+	if !reflect.DeepEqual(strict.Namespaces, expectNamespaces) {
+		t.Errorf("expected namespaces %v but got %v", expectNamespaces, strict.Namespaces)
+	}
+
+	if !reflect.DeepEqual(strict.SeccompDeny, opts.SeccompDeny) {
+		t.Errorf("expected denied syscalls %v but got %v", opts.SeccompDeny, strict.SeccompDeny)
+	}
+
+	if opts.Seccomp || len(opts.Namespaces) > 0 {
+		t.Error("expected the original options to be unchanged")
+	}
+
+	strict = SandboxOptions{Uid: 1001, Gid: 1001}.Strict()
+	if slices.Contains(strict.Namespaces, "user") {
+		t.Error("expected no user namespace with a dedicated user")
+	}
+}
+
+func TestSandboxOptions_Apply(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		cmd := exec.Command("foo", "bar")
+
+		err := SandboxOptions{}.Apply(cmd)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if cmd.SysProcAttr != nil {
+			t.Errorf("expected no SysProcAttr but got: %+v", cmd.SysProcAttr)
+		}
+	})
+
+	t.Run("namespaces", func(t *testing.T) {
+		cmd := exec.Command("foo", "bar")
+
+		err := SandboxOptions{Namespaces: []string{"user", "pid"}}.Apply(cmd)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		expectFlags := uintptr(syscall.CLONE_NEWUSER | syscall.CLONE_NEWPID)
+		if cmd.SysProcAttr.Cloneflags != expectFlags {
+			t.Errorf("expected clone flags %#x but got %#x", expectFlags, cmd.SysProcAttr.Cloneflags)
+		}
+
+		expectUidMappings := []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+		if !reflect.DeepEqual(cmd.SysProcAttr.UidMappings, expectUidMappings) {
+			t.Errorf("expected UID mappings %+v but got %+v", expectUidMappings, cmd.SysProcAttr.UidMappings)
+		}
+
+		if cmd.Args[0] != "foo" {
+			t.Errorf("expected unchanged args but got %v", cmd.Args)
+		}
+	})
+
+	t.Run("user", func(t *testing.T) {
+		cmd := exec.Command("foo", "bar")
+
+		err := SandboxOptions{Uid: 1001, Gid: 1002}.Apply(cmd)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		expectCredential := &syscall.Credential{Uid: 1001, Gid: 1002}
+		if !reflect.DeepEqual(cmd.SysProcAttr.Credential, expectCredential) {
+			t.Errorf("expected credential %+v but got %+v", expectCredential, cmd.SysProcAttr.Credential)
+		}
+	})
+
+	t.Run("seccomp", func(t *testing.T) {
+		cmd := exec.Command("/foo", "bar")
+
+		err := SandboxOptions{Seccomp: true, SeccompDeny: []string{"ptrace", "kcmp"}}.Apply(cmd)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		self, err := os.Executable()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if cmd.Path != self {
+			t.Errorf("expected path '%s' but got '%s'", self, cmd.Path)
+		}
+
+		expectArgs := []string{self, SandboxExecCommand, "--seccomp-deny=ptrace,kcmp", "--", "/foo", "bar"}
+		if !reflect.DeepEqual(cmd.Args, expectArgs) {
+			t.Errorf("expected args %v but got %v", expectArgs, cmd.Args)
+		}
+	})
+}
+
+func TestSandboxOptions_Own(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		err := SandboxOptions{}.Own("/foo")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	})
+
+	t.Run("non-existing directory", func(t *testing.T) {
+		err := SandboxOptions{Uid: 1001, Gid: 1001}.Own("/foo")
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		// Without root, the current user may only give its own files to
+		// itself.
+		uid, gid := os.Getuid(), os.Getgid()
+		if uid == 0 {
+			uid, gid = 65534, 65534
+		}
+
+		dirPath := t.TempDir()
+		filePath := dirPath + "/foo.txt"
+
+		err := os.WriteFile(filePath, []byte("foo"), 0o644)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = SandboxOptions{Uid: uid, Gid: gid}.Own(dirPath)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		info, err := os.Stat(dirPath)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if info.Mode().Perm() != 0o700 {
+			t.Errorf("expected mode 0700 but got %#o", info.Mode().Perm())
+		}
+
+		for _, path := range []string{dirPath, filePath} {
+			info, err = os.Stat(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			stat := info.Sys().(*syscall.Stat_t)
+			if int(stat.Uid) != uid || int(stat.Gid) != gid {
+				t.Errorf("expected '%s' to belong to %d:%d but got %d:%d", path, uid, gid, stat.Uid, stat.Gid)
+			}
+		}
+
+		// Let the test clean up the directory.
+		_ = os.Chmod(dirPath, 0o755)
+	})
+}
+
+func TestRunSandboxExec(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		args     []string
+	}{
+		{
+			scenario: "unknown argument",
+			args:     []string{"--foo", "--", "true"},
+		},
+		{
+			scenario: "no binary",
+			args:     []string{"--"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			code := RunSandboxExec(tc.args)
+			if code != 2 {
+				t.Errorf("expected exit code 2 but got %d", code)
+			}
+		})
+	}
+}
+
+func TestSeccompFilter(t *testing.T) {
+	t.Run("unsupported architecture", func(t *testing.T) {
+		_, err := seccompFilter("386", DefaultSeccompDeny)
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("unknown syscall", func(t *testing.T) {
+		_, err := seccompFilter(runtime.GOARCH, []string{"foo"})
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("deny syscalls", func(t *testing.T) {
+		arch := runtime.GOARCH
+		if arch != "amd64" && arch != "arm64" {
+			t.Skipf("unsupported architecture '%s'", arch)
+		}
+
+		filter, err := seccompFilter(arch, []string{"ptrace", "mount", "ptrace"})
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		// Architecture check, syscall number load, optional x32 check,
+		// one jump per distinct syscall, allow and deny.
+		expectLen := 4 + 2 + 2
+		if arch == "amd64" {
+			expectLen++
+		}
+
+		if len(filter) != expectLen {
+			t.Fatalf("expected %d instructions but got %d", expectLen, len(filter))
+		}
+
+		deny := filter[len(filter)-1]
+		if deny.Code != unix.BPF_RET|unix.BPF_K || deny.K != seccompRetErrno|uint32(unix.EPERM) {
+			t.Errorf("expected the last instruction to deny with EPERM but got %+v", deny)
+		}
+
+		allow := filter[len(filter)-2]
+		if allow.Code != unix.BPF_RET|unix.BPF_K || allow.K != seccompRetAllow {
+			t.Errorf("expected the penultimate instruction to allow but got %+v", allow)
+		}
+
+		// Every jump must land on the deny instruction.
+		for i := 4; i < len(filter)-2; i++ {
+			target := i + 1 + int(filter[i].Jt)
+			if target != len(filter)-1 {
+				t.Errorf("expected instruction %d to jump to %d but got %d", i, len(filter)-1, target)
+			}
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotSecretReference happens if a [SecretResolver] does not handle a
+// value, i.e., the value is not a reference to one of its secrets.
+var ErrNotSecretReference = errors.New("not a secret reference")
+
+// SecretResolver is a module interface which resolves references to secrets
+// kept by an external secrets manager (e.g., "vault:secret/data/gotenberg#key"),
+// so that the secrets do not have to live in the flags or the environment
+// variables.
+type SecretResolver interface {
+	// ResolveSecret returns the value of the secret the given value
+	// references, or [ErrNotSecretReference] if the value is not a reference
+	// it handles.
+	ResolveSecret(ctx context.Context, value string) (string, error)
+}
+
+// ResolveSecret returns the value of the secret the given flag value
+// references, thanks to the [SecretResolver] modules. A value which is not a
+// reference is returned as is.
+//
+//	func (m *YourModule) Provision(ctx *gotenberg.Context) error {
+//		password, err := gotenberg.ResolveSecret(ctx, flags.MustString("password"))
+//	}
+func ResolveSecret(ctx *Context, value string) (string, error) {
+	if value == "" {
+		return value, nil
+	}
+
+	mods, err := ctx.Modules(new(SecretResolver))
+	if err != nil {
+		return "", fmt.Errorf("get secret resolvers: %w", err)
+	}
+
+	for _, mod := range mods {
+		secret, err := mod.(SecretResolver).ResolveSecret(context.Background(), value)
+		if errors.Is(err, ErrNotSecretReference) {
+			continue
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("resolve secret: %w", err)
+		}
+
+		return secret, nil
+	}
+
+	return value, nil
+}
@@ -0,0 +1,92 @@
+package gotenberg
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecret(t *testing.T) {
+	buildResolvers := func(resolve func(ctx context.Context, value string) (string, error)) []ModuleDescriptor {
+		mod := &struct {
+			ModuleMock
+			SecretResolverMock
+		}{}
+		mod.DescriptorMock = func() ModuleDescriptor {
+			return ModuleDescriptor{ID: "foo", New: func() Module { return mod }}
+		}
+		mod.ResolveSecretMock = resolve
+
+		return []ModuleDescriptor{mod.Descriptor()}
+	}
+
+	vault := func(ctx context.Context, value string) (string, error) {
+		ref, ok := strings.CutPrefix(value, "vault:")
+		if !ok {
+			return "", ErrNotSecretReference
+		}
+
+		if ref == "missing" {
+			return "", errors.New("secret not found")
+		}
+
+		return "secret", nil
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		mods        []ModuleDescriptor
+		value       string
+		expect      string
+		expectError bool
+	}{
+		{
+			scenario: "empty value",
+			mods:     buildResolvers(vault),
+			value:    "",
+			expect:   "",
+		},
+		{
+			scenario: "no secret resolvers",
+			value:    "vault:foo",
+			expect:   "vault:foo",
+		},
+		{
+			scenario: "not a secret reference",
+			mods:     buildResolvers(vault),
+			value:    "foo",
+			expect:   "foo",
+		},
+		{
+			scenario: "secret reference",
+			mods:     buildResolvers(vault),
+			value:    "vault:foo",
+			expect:   "secret",
+		},
+		{
+			scenario:    "secret resolution failure",
+			mods:        buildResolvers(vault),
+			value:       "vault:missing",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := NewContext(ParsedFlags{}, tc.mods)
+
+			actual, err := ResolveSecret(ctx, tc.value)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -89,10 +89,18 @@ type processSupervisor struct {
 
 // NewProcessSupervisor initializes a new [ProcessSupervisor].
 func NewProcessSupervisor(logger *zap.Logger, process Process, maxReqLimit, maxQueueSize int64) ProcessSupervisor {
+	return NewConcurrentProcessSupervisor(logger, process, maxReqLimit, maxQueueSize, 1)
+}
+
+// NewConcurrentProcessSupervisor initializes a new [ProcessSupervisor] which
+// runs up to maxConcurrency tasks at the same time with its [Process], e.g.,
+// for a process which pipelines its requests. Beware: a restart happens
+// while the other tasks are running.
+func NewConcurrentProcessSupervisor(logger *zap.Logger, process Process, maxReqLimit, maxQueueSize int64, maxConcurrency int) ProcessSupervisor {
 	b := &processSupervisor{
 		logger:       logger,
 		process:      process,
-		mutexChan:    make(chan struct{}, 1),
+		mutexChan:    make(chan struct{}, max(maxConcurrency, 1)),
 		maxReqLimit:  maxReqLimit,
 		maxQueueSize: maxQueueSize,
 	}
@@ -466,6 +466,81 @@ func TestProcessSupervisor_Run(t *testing.T) {
 	}
 }
 
+func TestProcessSupervisor_Run_concurrency(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		maxConcurrency    int
+		expectMaxInFlight int32
+	}{
+		{
+			scenario:          "one task at a time",
+			maxConcurrency:    1,
+			expectMaxInFlight: 1,
+		},
+		{
+			scenario:          "concurrent tasks",
+			maxConcurrency:    3,
+			expectMaxInFlight: 3,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			process := &ProcessMock{
+				StartMock: func(logger *zap.Logger) error {
+					return nil
+				},
+				HealthyMock: func(logger *zap.Logger) bool {
+					return true
+				},
+			}
+
+			s := NewConcurrentProcessSupervisor(zap.NewNop(), process, 0, 0, tc.maxConcurrency)
+
+			err := s.Launch()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var (
+				inFlight    atomic.Int32
+				maxInFlight atomic.Int32
+				wg          sync.WaitGroup
+			)
+
+			for i := 0; i < 6; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					err := s.Run(context.Background(), zap.NewNop(), func() error {
+						current := inFlight.Add(1)
+						defer inFlight.Add(-1)
+
+						for {
+							previous := maxInFlight.Load()
+							if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+								break
+							}
+						}
+
+						time.Sleep(time.Duration(50) * time.Millisecond)
+
+						return nil
+					})
+					if err != nil {
+						t.Errorf("expected no error but got: %v", err)
+					}
+				}()
+			}
+
+			wg.Wait()
+
+			if maxInFlight.Load() != tc.expectMaxInFlight {
+				t.Errorf("expected %d tasks at the same time but got %d", tc.expectMaxInFlight, maxInFlight.Load())
+			}
+		})
+	}
+}
+
 func TestProcessSupervisor_runWithDeadline(t *testing.T) {
 	for _, tc := range []struct {
 		scenario    string
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AccessLogger is a module interface which receives an entry for each request
+// handled by the [Api], e.g., to emit access logs. Implementations should not
+// block.
+type AccessLogger interface {
+	LogAccess(entry AccessLogEntry)
+}
+
+// AccessLogEntry gathers the details of a handled request.
+type AccessLogEntry struct {
+	// Time is the moment the request has been handled.
+	Time time.Time
+
+	// Trace is the request identifier.
+	Trace string
+
+	// RemoteIP is the IP of the client.
+	RemoteIP string
+
+	// Method is the HTTP method of the request.
+	Method string
+
+	// Route is the path of the matched route (e.g., /forms/chromium/convert/url).
+	Route string
+
+	// Status is the HTTP status of the response.
+	Status int
+
+	// Duration is the time spent handling the request.
+	Duration time.Duration
+
+	// BytesIn and BytesOut are the sizes of the request and response bodies.
+	BytesIn  int64
+	BytesOut int64
+
+	// Header is the header of the request.
+	Header http.Header
+
+	// Filenames are the names of the files of a "multipart/form-data"
+	// request. They are not sanitized.
+	Filenames []string
+
+	// Error is the error message, if any.
+	Error string
+}
+
+// newAccessLogEntry creates an [AccessLogEntry] from the related
+// [echo.Context].
+func newAccessLogEntry(c echo.Context, startTime, finishTime time.Time, err error) AccessLogEntry {
+	entry := AccessLogEntry{
+		Time:     finishTime,
+		RemoteIP: c.RealIP(),
+		Method:   c.Request().Method,
+		Route:    c.Path(),
+		Status:   c.Response().Status,
+		Duration: finishTime.Sub(startTime),
+		BytesIn:  c.Request().ContentLength,
+		BytesOut: c.Response().Size,
+		Header:   c.Request().Header,
+	}
+
+	if entry.Route == "" {
+		entry.Route = c.Request().URL.Path
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	trace, ok := c.Get("trace").(string)
+	if ok {
+		entry.Trace = trace
+	}
+
+	ctx, ok := c.Get("context").(*Context)
+	if ok {
+		for filename := range ctx.files {
+			entry.Filenames = append(entry.Filenames, filename)
+		}
+
+		sort.Strings(entry.Filenames)
+	}
+
+	return entry
+}
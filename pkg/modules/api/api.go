@@ -13,6 +13,7 @@ import (
 
 	"github.com/alexliesenfeld/health"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/bytes"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -32,15 +33,37 @@ type Api struct {
 	port                      int
 	startTimeout              time.Duration
 	timeout                   time.Duration
+	maxTimeout                time.Duration
+	keepaliveInterval         time.Duration
 	rootPath                  string
 	traceHeader               string
 	disableHealthCheckLogging bool
+	disableOutputMetadata     bool
+	jsonResponseMaxSize       int64
+	storage                   string
+	storageTmpfsDir           string
+	storageMemoryDir          string
+	storageRoutes             map[string]string
+	disabledExtensions        map[string][]string
+	storageMinFreeSpace       int64
+	storageMinFreeInodes      int64
+	disableStorageGuard       bool
+	fileTypeMismatch          string
+	profiles                  Profiles
+	adminToken                string
 
 	routes              []Route
 	externalMiddlewares []Middleware
 	healthChecks        []health.CheckerOption
 	readyFn             []func() error
+	errorReporters      []ErrorReporter
+	accessLoggers       []AccessLogger
+	scratchRemover      ScratchRemover
+	templateProvider    TemplateProvider
+	assetProvider       AssetProvider
+	pdfEngine           gotenberg.PdfEngine
 	fs                  *gotenberg.FileSystem
+	storages            map[string]gotenberg.Storage
 	logger              *zap.Logger
 	srv                 *echo.Echo
 }
@@ -68,6 +91,11 @@ type Route struct {
 	// Optional.
 	DisableLogging bool
 
+	// IsAdmin tells if the route requires the admin token. The routes
+	// starting with /admin must set it.
+	// Optional.
+	IsAdmin bool
+
 	// Handler is the function which handles the request.
 	// Required.
 	Handler echo.HandlerFunc
@@ -161,9 +189,24 @@ func (a *Api) Descriptor() gotenberg.ModuleDescriptor {
 			fs.String("api-port-from-env", "", "Set the environment variable with the port on which the API should listen - override the default port")
 			fs.Duration("api-start-timeout", time.Duration(30)*time.Second, "Set the time limit for the API to start")
 			fs.Duration("api-timeout", time.Duration(30)*time.Second, "Set the time limit for requests")
+			fs.Duration("api-max-timeout", 0, "Set the maximum time limit a request may ask for with the processTimeout form field - 0 means the time limit for requests is also the maximum")
+			fs.Duration("api-keepalive-interval", time.Duration(15)*time.Second, "Set the interval at which to keep alive the connection of a synchronous request asking for it, either with 102 Processing responses or as an event stream - 0 disables")
 			fs.String("api-root-path", "/", "Set the root path of the API - for service discovery via URL paths")
 			fs.String("api-trace-header", "Gotenberg-Trace", "Set the header name to use for identifying requests")
 			fs.Bool("api-disable-health-check-logging", false, "Disable health check logging")
+			fs.Bool("api-disable-output-metadata", false, "Disable the output metadata response headers and the metadata.json file in archives")
+			fs.String("api-json-response-max-size", "5MB", "Set the maximum size of the output files returned as base64 in a JSON response - requests with 'Accept: application/json' and larger outputs fail with a 406 status")
+			fs.String("api-storage", DiskStorage, "Set the default storage backend of the requests' working directories - disk, tmpfs or memory")
+			fs.String("api-storage-tmpfs-dir", "", "Set the directory of a tmpfs mount for the tmpfs storage backend")
+			fs.String("api-storage-memory-dir", "/dev/shm", "Set the shared memory directory for the memory storage backend")
+			fs.StringSlice("api-storage-routes", make([]string, 0), "Set the storage backend of the routes starting with a given path - e.g., /forms/chromium=memory")
+			fs.StringSlice("api-disable-extensions", make([]string, 0), "Disable extensions of this build, for all routes or for the routes starting with a given path - e.g., validateOnly or /forms/libreoffice=jsonResponse")
+			fs.String("api-storage-min-free-space", "64MB", "Set the minimum free space to keep on the storage after accepting a request - requests which would exceed it fail with a 507 status")
+			fs.Int64("api-storage-min-free-inodes", 1024, "Set the minimum number of free inodes to keep on the storage - requests which would exceed it fail with a 507 status")
+			fs.Bool("api-disable-storage-guard", false, "Disable the check of the free space and inodes of the storage before accepting a request")
+			fs.String("api-file-type-mismatch", FileTypeMismatchIgnore, fmt.Sprintf("Set what happens when the content of an uploaded file does not match its extension, e.g., a PDF renamed to .docx - %s, %s or %s", FileTypeMismatchReject, FileTypeMismatchCorrect, FileTypeMismatchIgnore))
+			fs.String("api-profiles-file", "", "Set the JSON file with the conversion profiles, i.e., named presets of form fields the requests may select with the profile form field")
+			fs.String("api-admin-token", "", "Set the token, or a reference to a secret, the admin routes (e.g., /admin/usage) require as a bearer token in the 'Authorization' header - the API does not start if a module adds admin routes without it")
 
 			return fs
 		}(),
@@ -177,9 +220,61 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 	a.port = flags.MustInt("api-port")
 	a.startTimeout = flags.MustDuration("api-start-timeout")
 	a.timeout = flags.MustDuration("api-timeout")
+	a.maxTimeout = flags.MustDuration("api-max-timeout")
+	a.keepaliveInterval = flags.MustDuration("api-keepalive-interval")
 	a.rootPath = flags.MustString("api-root-path")
 	a.traceHeader = flags.MustString("api-trace-header")
 	a.disableHealthCheckLogging = flags.MustBool("api-disable-health-check-logging")
+	a.disableOutputMetadata = flags.MustBool("api-disable-output-metadata")
+
+	jsonResponseMaxSize, err := bytes.Parse(flags.MustHumanReadableBytesString("api-json-response-max-size"))
+	if err != nil {
+		return fmt.Errorf("parse JSON response maximum size: %w", err)
+	}
+
+	a.jsonResponseMaxSize = jsonResponseMaxSize
+
+	a.storage = flags.MustString("api-storage")
+	a.storageTmpfsDir = flags.MustString("api-storage-tmpfs-dir")
+	a.storageMemoryDir = flags.MustString("api-storage-memory-dir")
+
+	storageRoutes, err := parseStorageRoutes(flags.MustStringSlice("api-storage-routes"))
+	if err != nil {
+		return fmt.Errorf("parse storage routes: %w", err)
+	}
+
+	a.storageRoutes = storageRoutes
+
+	disabledExtensions, err := parseDisabledExtensions(flags.MustStringSlice("api-disable-extensions"))
+	if err != nil {
+		return fmt.Errorf("parse disabled extensions: %w", err)
+	}
+
+	a.disabledExtensions = disabledExtensions
+
+	storageMinFreeSpace, err := bytes.Parse(flags.MustHumanReadableBytesString("api-storage-min-free-space"))
+	if err != nil {
+		return fmt.Errorf("parse storage minimum free space: %w", err)
+	}
+
+	a.storageMinFreeSpace = storageMinFreeSpace
+	a.storageMinFreeInodes = flags.MustInt64("api-storage-min-free-inodes")
+	a.disableStorageGuard = flags.MustBool("api-disable-storage-guard")
+	a.fileTypeMismatch = flags.MustString("api-file-type-mismatch")
+
+	profiles, err := loadProfiles(flags.MustString("api-profiles-file"))
+	if err != nil {
+		return fmt.Errorf("load profiles: %w", err)
+	}
+
+	a.profiles = profiles
+
+	adminToken, err := gotenberg.ResolveSecret(ctx, flags.MustString("api-admin-token"))
+	if err != nil {
+		return fmt.Errorf("get admin token: %w", err)
+	}
+
+	a.adminToken = adminToken
 
 	// Port from env?
 	portEnvVar := flags.MustString("api-port-from-env")
@@ -268,6 +363,86 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 		a.readyFn = append(a.readyFn, healthChecker.Ready)
 	}
 
+	// Get error reporters from modules.
+	mods, err = ctx.Modules(new(ErrorReporter))
+	if err != nil {
+		return fmt.Errorf("get error reporters: %w", err)
+	}
+
+	a.errorReporters = make([]ErrorReporter, len(mods))
+	for i, errorReporter := range mods {
+		a.errorReporters[i] = errorReporter.(ErrorReporter)
+	}
+
+	// Get access loggers from modules.
+	mods, err = ctx.Modules(new(AccessLogger))
+	if err != nil {
+		return fmt.Errorf("get access loggers: %w", err)
+	}
+
+	a.accessLoggers = make([]AccessLogger, len(mods))
+	for i, accessLogger := range mods {
+		a.accessLoggers[i] = accessLogger.(AccessLogger)
+	}
+
+	// Scratch remover, if any, for removing the working directories of the
+	// requests.
+	mods, err = ctx.Modules(new(ScratchRemover))
+	if err != nil {
+		return fmt.Errorf("get scratch removers: %w", err)
+	}
+
+	if len(mods) > 1 {
+		return errors.New("expected at most one scratch remover module")
+	}
+
+	if len(mods) == 1 {
+		a.scratchRemover = mods[0].(ScratchRemover)
+	}
+
+	// Template provider, if any, for the templates the requests reference.
+	mods, err = ctx.Modules(new(TemplateProvider))
+	if err != nil {
+		return fmt.Errorf("get template providers: %w", err)
+	}
+
+	if len(mods) > 1 {
+		return errors.New("expected at most one template provider module")
+	}
+
+	if len(mods) == 1 {
+		a.templateProvider = mods[0].(TemplateProvider)
+	}
+
+	// Asset provider, if any, for the shared assets the requests reference.
+	mods, err = ctx.Modules(new(AssetProvider))
+	if err != nil {
+		return fmt.Errorf("get asset providers: %w", err)
+	}
+
+	if len(mods) > 1 {
+		return errors.New("expected at most one asset provider module")
+	}
+
+	if len(mods) == 1 {
+		a.assetProvider = mods[0].(AssetProvider)
+	}
+
+	// PDF engine, if any, for counting the pages of the output files.
+	mods, err = ctx.Modules(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine providers: %w", err)
+	}
+
+	if len(mods) > 0 {
+		engine, err := mods[0].(gotenberg.PdfEngineProvider).PdfEngine()
+		if err != nil {
+			return fmt.Errorf("get PDF engine: %w", err)
+		}
+
+		a.pdfEngine = engine
+	}
+
 	// Logger.
 	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
 	if err != nil {
@@ -281,8 +456,16 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 
 	a.logger = logger
 
-	// File system.
+	// File system and storage backends.
 	a.fs = gotenberg.NewFileSystem()
+	a.storages = map[string]gotenberg.Storage{
+		DiskStorage:   a.fs,
+		MemoryStorage: gotenberg.NewFileSystemAt(a.storageMemoryDir),
+	}
+
+	if a.storageTmpfsDir != "" {
+		a.storages[TmpfsStorage] = gotenberg.NewFileSystemAt(a.storageTmpfsDir)
+	}
 
 	return nil
 }
@@ -315,12 +498,50 @@ func (a *Api) Validate() error {
 		)
 	}
 
+	if a.maxTimeout != 0 && a.maxTimeout < a.timeout {
+		err = multierr.Append(err,
+			errors.New("maximum timeout must be either 0 or at least the timeout"),
+		)
+	}
+
+	if a.keepaliveInterval < 0 {
+		err = multierr.Append(err,
+			errors.New("keepalive interval must be at least 0"),
+		)
+	}
+
+	if a.storageMinFreeInodes < 0 {
+		err = multierr.Append(err,
+			errors.New("storage minimum free inodes must be at least 0"),
+		)
+	}
+
+	storageErr := a.validateStorage(a.storage)
+	if storageErr != nil {
+		err = multierr.Append(err, storageErr)
+	}
+
+	for path, backend := range a.storageRoutes {
+		storageErr = a.validateStorage(backend)
+		if storageErr != nil {
+			err = multierr.Append(err, fmt.Errorf("storage route '%s': %w", path, storageErr))
+		}
+	}
+
+	switch a.fileTypeMismatch {
+	case "", FileTypeMismatchReject, FileTypeMismatchCorrect, FileTypeMismatchIgnore:
+	default:
+		err = multierr.Append(err,
+			fmt.Errorf("file type mismatch must be either '%s', '%s' or '%s'", FileTypeMismatchReject, FileTypeMismatchCorrect, FileTypeMismatchIgnore),
+		)
+	}
+
 	if err != nil {
 		return err
 	}
 
 	routesMap := make(map[string]string, len(a.routes)+1)
-	routesMap["/health"] = "/health"
+	routesMap["GET /health"] = "/health"
 
 	for _, route := range a.routes {
 		if route.Path == "" {
@@ -343,11 +564,20 @@ func (a *Api) Validate() error {
 			return fmt.Errorf("route '%s' has a nil handler", route.Path)
 		}
 
-		if _, ok := routesMap[route.Path]; ok {
-			return fmt.Errorf("route '%s' is already registered", route.Path)
+		if !route.IsAdmin && strings.HasPrefix(route.Path, "/admin") {
+			return fmt.Errorf("route '%s' starts with /admin but is not an admin route", route.Path)
+		}
+
+		if route.IsAdmin && a.adminToken == "" {
+			return fmt.Errorf("admin route '%s' requires the admin token", route.Path)
+		}
+
+		key := fmt.Sprintf("%s %s", route.Method, route.Path)
+		if _, ok := routesMap[key]; ok {
+			return fmt.Errorf("route '%s' is already registered", key)
 		}
 
-		routesMap[route.Path] = route.Path
+		routesMap[key] = route.Path
 	}
 
 	for _, middleware := range a.externalMiddlewares {
@@ -367,8 +597,9 @@ func (a *Api) Start() error {
 	a.srv.Server.ReadTimeout = a.timeout
 	a.srv.Server.IdleTimeout = a.timeout
 	// See https://github.com/gotenberg/gotenberg/issues/396.
-	a.srv.Server.WriteTimeout = a.timeout + a.timeout
-	a.srv.HTTPErrorHandler = httpErrorHandler()
+	// The time limit of a request may be up to the maximum timeout.
+	a.srv.Server.WriteTimeout = max(a.timeout, a.maxTimeout) + a.timeout
+	a.srv.HTTPErrorHandler = httpErrorHandler(a.errorReporters, a.rootPath, a.disabledExtensions)
 
 	// Let's prepare the modules' routes.
 	var disableLoggingForPaths []string
@@ -391,7 +622,8 @@ func (a *Api) Start() error {
 		latencyMiddleware(),
 		rootPathMiddleware(a.rootPath),
 		traceMiddleware(a.traceHeader),
-		loggerMiddleware(a.logger, disableLoggingForPaths),
+		loggerMiddleware(a.logger, disableLoggingForPaths, a.accessLoggers),
+		adminMiddleware(a.adminToken),
 	)
 
 	// Add the modules' middlewares in their respective stacks.
@@ -407,18 +639,45 @@ func (a *Api) Start() error {
 		}
 	}
 
-	hardTimeout := a.timeout + (time.Duration(5) * time.Second)
+	hardTimeout := a.timeout + hardTimeoutMargin
 
 	// Add the modules' routes and their specific middlewares.
 	for _, route := range a.routes {
 		var middlewares []echo.MiddlewareFunc
 
+		if route.IsAdmin {
+			middlewares = append(middlewares, adminOnlyMiddleware())
+		}
+
 		if route.IsMultipart {
-			middlewares = append(middlewares, contextMiddleware(a.fs, a.timeout))
+			// The path of the route without the root path, as in the flags.
+			routePath := fmt.Sprintf("/%s", route.Path)
+			storage := a.routeStorage(routePath)
+
+			if !a.disableStorageGuard {
+				middlewares = append(middlewares, storageGuardMiddleware(storage, a.storageMinFreeSpace, a.storageMinFreeInodes))
+			}
+
+			middlewares = append(middlewares, contextMiddleware(storage, a.timeout, contextOptions{
+				pdfEngine:           a.pdfEngine,
+				outputMetadata:      !a.disableOutputMetadata,
+				jsonResponseMaxSize: a.jsonResponseMaxSize,
+				errorReporters:      a.errorReporters,
+				fileTypeMismatch:    a.fileTypeMismatch,
+				maxTimeout:          a.maxTimeout,
+				keepaliveInterval:   a.keepaliveInterval,
+				scratchRemover:      a.scratchRemover,
+				disabledExtensions:  a.routeDisabledExtensions(routePath),
+				profiles:            a.profiles,
+				templateProvider:    a.templateProvider,
+				assetProvider:       a.assetProvider,
+			}))
 
 			for _, externalMultipartMiddleware := range externalMultipartMiddlewares {
 				middlewares = append(middlewares, externalMultipartMiddleware.Handler)
 			}
+
+			middlewares = append(middlewares, startMiddleware())
 		}
 
 		middlewares = append(middlewares, hardTimeoutMiddleware(hardTimeout))
@@ -434,14 +434,61 @@ func TestApi_Provision(t *testing.T) {
 
 func TestApi_Validate(t *testing.T) {
 	for _, tc := range []struct {
-		scenario    string
-		port        int
-		rootPath    string
-		traceHeader string
-		routes      []Route
-		middlewares []Middleware
-		expectError bool
+		scenario      string
+		port          int
+		rootPath      string
+		traceHeader   string
+		storage       string
+		storageRoutes map[string]string
+		typeMismatch  string
+		timeout       time.Duration
+		maxTimeout    time.Duration
+		adminToken    string
+		routes        []Route
+		middlewares   []Middleware
+		expectError   bool
 	}{
+		{
+			scenario:    "invalid storage backend",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			storage:     "foo",
+			expectError: true,
+		},
+		{
+			scenario:    "tmpfs storage backend without directory",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			storage:     TmpfsStorage,
+			expectError: true,
+		},
+		{
+			scenario:      "invalid storage route backend",
+			port:          10,
+			rootPath:      "/foo/",
+			traceHeader:   "foo",
+			storageRoutes: map[string]string{"/forms/foo": "foo"},
+			expectError:   true,
+		},
+		{
+			scenario:     "invalid file type mismatch policy",
+			port:         10,
+			rootPath:     "/foo/",
+			traceHeader:  "foo",
+			typeMismatch: "foo",
+			expectError:  true,
+		},
+		{
+			scenario:    "maximum timeout less than the timeout",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			timeout:     time.Duration(30) * time.Second,
+			maxTimeout:  time.Duration(10) * time.Second,
+			expectError: true,
+		},
 		{
 			scenario:    "invalid port (< 1)",
 			port:        0,
@@ -576,6 +623,38 @@ func TestApi_Validate(t *testing.T) {
 			middlewares: nil,
 			expectError: true,
 		},
+		{
+			scenario:    "invalid route: /admin path without admin flag",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			adminToken:  "foo",
+			routes: []Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/foo",
+					Handler: func(_ echo.Context) error { return nil },
+				},
+			},
+			middlewares: nil,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid route: admin route without admin token",
+			port:        10,
+			rootPath:    "/foo/",
+			traceHeader: "foo",
+			routes: []Route{
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/foo",
+					IsAdmin: true,
+					Handler: func(_ echo.Context) error { return nil },
+				},
+			},
+			middlewares: nil,
+			expectError: true,
+		},
 		{
 			scenario:    "invalid middleware: nil handler",
 			port:        10,
@@ -595,12 +674,24 @@ func TestApi_Validate(t *testing.T) {
 			port:        10,
 			rootPath:    "/foo/",
 			traceHeader: "foo",
+			adminToken:  "foo",
 			routes: []Route{
 				{
 					Method:  http.MethodGet,
 					Path:    "/foo",
 					Handler: func(_ echo.Context) error { return nil },
 				},
+				{
+					Method:  http.MethodDelete,
+					Path:    "/foo",
+					Handler: func(_ echo.Context) error { return nil },
+				},
+				{
+					Method:  http.MethodGet,
+					Path:    "/admin/foo",
+					IsAdmin: true,
+					Handler: func(_ echo.Context) error { return nil },
+				},
 				{
 					Method:      http.MethodGet,
 					Path:        "/forms/foo",
@@ -627,10 +718,20 @@ func TestApi_Validate(t *testing.T) {
 				port:                tc.port,
 				rootPath:            tc.rootPath,
 				traceHeader:         tc.traceHeader,
+				storage:             tc.storage,
+				storageRoutes:       tc.storageRoutes,
+				fileTypeMismatch:    tc.typeMismatch,
+				timeout:             tc.timeout,
+				maxTimeout:          tc.maxTimeout,
+				adminToken:          tc.adminToken,
 				routes:              tc.routes,
 				externalMiddlewares: tc.middlewares,
 			}
 
+			if mod.storage == "" {
+				mod.storage = DiskStorage
+			}
+
 			err := mod.Validate()
 			if !tc.expectError && err != nil {
 				t.Fatalf("expected no error but got: %v", err)
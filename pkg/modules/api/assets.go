@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+)
+
+// assetsField is the form field which references shared assets.
+const assetsField = "assets"
+
+// ErrAssetNotFound happens if there is no shared asset with a given checksum.
+var ErrAssetNotFound = errors.New("asset not found")
+
+// assetChecksumRegexp matches the SHA-256 checksums of the shared assets.
+var assetChecksumRegexp = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// AssetProvider is a module interface which provides the shared assets,
+// e.g., logos, stylesheets or fonts, that the requests reference by their
+// SHA-256 checksum with the "assets" form field:
+//
+//	{"logo.png": "3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b"}
+type AssetProvider interface {
+	// OpenAsset returns the content of an asset. It returns
+	// [ErrAssetNotFound] if there is no asset with the given checksum.
+	OpenAsset(ctx context.Context, checksum string) (io.ReadCloser, error)
+}
+
+// addAssets reads the "assets" form field and copies the referenced assets
+// into the working directory, under the given filenames.
+func (ctx *Context) addAssets(provider AssetProvider) error {
+	val, ok := ctx.values[assetsField]
+	if !ok || val[0] == "" {
+		return nil
+	}
+
+	invalid := func(format string, a ...any) error {
+		err := fmt.Errorf("form field '%s' is invalid (%s)", assetsField, fmt.Sprintf(format, a...))
+
+		return WrapError(
+			err,
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode(ErrorCodeInvalidFormData),
+		)
+	}
+
+	var assets map[string]string
+
+	err := json.Unmarshal([]byte(val[0]), &assets)
+	if err != nil {
+		return invalid("got '%s', expected a JSON object of filenames and checksums", val[0])
+	}
+
+	if provider == nil {
+		return invalid("no asset store is available")
+	}
+
+	filenames := make([]string, 0, len(assets))
+	for filename := range assets {
+		filenames = append(filenames, filename)
+	}
+
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		checksum := assets[filename]
+		if !assetChecksumRegexp.MatchString(checksum) {
+			return invalid("got '%s' for '%s', expected a SHA-256 checksum", checksum, filename)
+		}
+
+		err = ctx.addAsset(provider, filename, checksum)
+		if err != nil {
+			if errors.Is(err, ErrAssetNotFound) {
+				return invalid("got '%s' for '%s', no such asset", checksum, filename)
+			}
+
+			if errors.Is(err, os.ErrExist) {
+				return invalid("asset '%s' has the filename of another file", filename)
+			}
+
+			return fmt.Errorf("add asset '%s': %w", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// addAsset copies an asset into the working directory.
+func (ctx *Context) addAsset(provider AssetProvider, filename, checksum string) error {
+	in, err := provider.OpenAsset(ctx, checksum)
+	if err != nil {
+		return fmt.Errorf("open asset: %w", err)
+	}
+
+	defer func() {
+		err := in.Close()
+		if err != nil {
+			ctx.Log().Error(fmt.Sprintf("close asset: %s", err))
+		}
+	}()
+
+	return ctx.addInputFile(filename, in)
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestContext_addAssets(t *testing.T) {
+	logo := strings.Repeat("a", 64)
+	style := strings.Repeat("b", 64)
+
+	provider := &AssetProviderMock{
+		OpenAssetMock: func(ctx context.Context, checksum string) (io.ReadCloser, error) {
+			switch checksum {
+			case logo:
+				return io.NopCloser(strings.NewReader("logo")), nil
+			case style:
+				return io.NopCloser(strings.NewReader("style")), nil
+			default:
+				return nil, ErrAssetNotFound
+			}
+		},
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		values        map[string][]string
+		files         []string
+		provider      AssetProvider
+		expectFiles   []string
+		expectStatus  int
+		expectError   bool
+		expectContent map[string]string
+	}{
+		{
+			scenario:    "no asset",
+			values:      map[string][]string{},
+			files:       []string{"index.html"},
+			provider:    provider,
+			expectFiles: []string{"index.html"},
+		},
+		{
+			scenario:     "not a JSON object",
+			values:       map[string][]string{"assets": {"foo"}},
+			provider:     provider,
+			expectStatus: http.StatusBadRequest,
+			expectError:  true,
+		},
+		{
+			scenario:     "no asset store",
+			values:       map[string][]string{"assets": {`{"logo.png":"` + logo + `"}`}},
+			expectStatus: http.StatusBadRequest,
+			expectError:  true,
+		},
+		{
+			scenario:     "not a checksum",
+			values:       map[string][]string{"assets": {`{"logo.png":"foo"}`}},
+			provider:     provider,
+			expectStatus: http.StatusBadRequest,
+			expectError:  true,
+		},
+		{
+			scenario:     "unknown asset",
+			values:       map[string][]string{"assets": {`{"logo.png":"` + strings.Repeat("c", 64) + `"}`}},
+			provider:     provider,
+			expectStatus: http.StatusBadRequest,
+			expectError:  true,
+		},
+		{
+			scenario:     "same filename as an uploaded file",
+			values:       map[string][]string{"assets": {`{"index.html":"` + logo + `"}`}},
+			files:        []string{"index.html"},
+			provider:     provider,
+			expectStatus: http.StatusBadRequest,
+			expectError:  true,
+		},
+		{
+			scenario: "many assets",
+			values:   map[string][]string{"assets": {`{"logo.png":"` + logo + `","style.css":"` + style + `"}`}},
+			files:    []string{"index.html"},
+			provider: provider,
+			expectFiles: []string{
+				"index.html",
+				"logo.png",
+				"style.css",
+			},
+			expectContent: map[string]string{
+				"logo.png":  "logo",
+				"style.css": "style",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+
+			files := make(map[string]string)
+			for _, filename := range tc.files {
+				files[filename] = dirPath + "/" + filename
+			}
+
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetDirPath(dirPath)
+			ctx.SetValues(tc.values)
+			ctx.SetFiles(files)
+			ctx.SetLogger(zap.NewNop())
+
+			err := ctx.addAssets(tc.provider)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				status := ParseErrorResponse(err).Status
+				if status != tc.expectStatus {
+					t.Errorf("expected status %d but got %d", tc.expectStatus, status)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var filenames []string
+			for filename := range ctx.files {
+				filenames = append(filenames, filename)
+			}
+
+			sort.Strings(filenames)
+			if !reflect.DeepEqual(filenames, tc.expectFiles) {
+				t.Errorf("expected files %v but got %v", tc.expectFiles, filenames)
+			}
+
+			for filename, expect := range tc.expectContent {
+				b, err := os.ReadFile(dirPath + "/" + filename)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				if string(b) != expect {
+					t.Errorf("expected content '%s' for '%s' but got '%s'", expect, filename, string(b))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// asyncField is the form field which turns a request into an
+	// asynchronous one, whose client polls for the result.
+	asyncField = "async"
+
+	// AsyncHeader is the header alternative to the "async" form field.
+	AsyncHeader = "Gotenberg-Async"
+)
+
+// Async tells if the client asks for an asynchronous request, i.e., if its
+// "async" form field or its "Gotenberg-Async" header is true. The module
+// handling such a request returns [ErrAsyncProcess].
+func (ctx *Context) Async() bool {
+	return ctx.async
+}
+
+// SetAccepted sets the body of the 202 Accepted response to an asynchronous
+// request, e.g., where to poll for its result. Otherwise, the response is a
+// 204 No Content.
+func (ctx *Context) SetAccepted(body any) {
+	ctx.accepted = body
+}
+
+// SuspendDeadline suspends the time limit of the request until
+// [Context.RestartDeadline], e.g., while an asynchronous request waits for its
+// turn.
+func (ctx *Context) SuspendDeadline() {
+	deadlineCtx, ok := ctx.Context.(*deadlineContext)
+	if !ok {
+		return
+	}
+
+	deadlineCtx.setDeadline(time.Time{})
+}
+
+// RestartDeadline restarts the time limit of the request, as far from now as
+// its duration, e.g., once an asynchronous request gets its turn. The context
+// stays the same, and so does its cancellation.
+func (ctx *Context) RestartDeadline() {
+	deadlineCtx, ok := ctx.Context.(*deadlineContext)
+	if !ok || ctx.timeout <= 0 {
+		// No time limit to restart.
+		return
+	}
+
+	deadlineCtx.setDeadline(time.Now().Add(ctx.timeout))
+}
+
+// parseAsync reads the "async" form field and the given value of the
+// "Gotenberg-Async" header. Either of them may turn the request into an
+// asynchronous one.
+func (ctx *Context) parseAsync(header string) error {
+	val, ok := ctx.values[asyncField]
+	if ok && val[0] != "" {
+		async, err := strconv.ParseBool(val[0])
+		if err != nil {
+			err = fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", asyncField, val[0], err)
+
+			return WrapError(
+				err,
+				NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode(ErrorCodeInvalidFormData),
+			)
+		}
+
+		ctx.async = async
+	}
+
+	if header == "" {
+		return nil
+	}
+
+	async, err := strconv.ParseBool(header)
+	if err != nil {
+		return WrapError(
+			fmt.Errorf("parse '%s' header: %w", AsyncHeader, err),
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid '%s' header value: expected a boolean, but got '%s'", AsyncHeader, header)).WithCode(ErrorCodeInvalidAsyncHeader),
+		)
+	}
+
+	ctx.async = ctx.async || async
+
+	return nil
+}
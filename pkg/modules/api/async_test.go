@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestContext_parseAsync(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		values      map[string][]string
+		header      string
+		expectAsync bool
+		expectError bool
+	}{
+		{
+			scenario: "neither async form field nor header",
+			values:   map[string][]string{},
+		},
+		{
+			scenario:    "invalid async form field",
+			values:      map[string][]string{"async": {"foo"}},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid Gotenberg-Async header",
+			values:      map[string][]string{},
+			header:      "foo",
+			expectError: true,
+		},
+		{
+			scenario:    "async form field",
+			values:      map[string][]string{"async": {"true"}},
+			expectAsync: true,
+		},
+		{
+			scenario:    "Gotenberg-Async header",
+			values:      map[string][]string{},
+			header:      "true",
+			expectAsync: true,
+		},
+		{
+			scenario:    "async form field with a false Gotenberg-Async header",
+			values:      map[string][]string{"async": {"true"}},
+			header:      "false",
+			expectAsync: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetValues(tc.values)
+
+			err := ctx.parseAsync(tc.header)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				var httpErr HttpError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("expected an HTTP error but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != http.StatusBadRequest {
+					t.Errorf("expected %d as HTTP status code but got %d", http.StatusBadRequest, status)
+				}
+			}
+
+			if ctx.Async() != tc.expectAsync {
+				t.Errorf("expected async %t but got %t", tc.expectAsync, ctx.Async())
+			}
+		})
+	}
+}
+
+func TestContext_SetAccepted(t *testing.T) {
+	ctx := &ContextMock{Context: new(Context)}
+
+	if ctx.Accepted() != nil {
+		t.Fatalf("expected no accepted response body but got %+v", ctx.Accepted())
+	}
+
+	ctx.SetAccepted("foo")
+
+	if ctx.Accepted() != "foo" {
+		t.Errorf("expected 'foo' as accepted response body but got %+v", ctx.Accepted())
+	}
+}
+
+func TestContext_SuspendDeadline(t *testing.T) {
+	deadlineCtx, cancel := newDeadlineContext(time.Now().Add(time.Millisecond))
+	defer cancel()
+
+	ctx := &ContextMock{Context: new(Context)}
+	ctx.Context.Context = deadlineCtx
+	ctx.SuspendDeadline()
+
+	_, ok := ctx.Deadline()
+	if ok {
+		t.Fatal("expected no deadline")
+	}
+
+	time.Sleep(time.Duration(10) * time.Millisecond)
+
+	if ctx.Err() != nil {
+		t.Errorf("expected no error but got: %v", ctx.Err())
+	}
+}
+
+func TestContext_RestartDeadline(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		timeout        time.Duration
+		expectDeadline bool
+	}{
+		{
+			scenario:       "no time limit",
+			expectDeadline: false,
+		},
+		{
+			scenario:       "time limit",
+			timeout:        time.Duration(1) * time.Hour,
+			expectDeadline: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			// The request waits for its turn.
+			deadlineCtx, cancel := newDeadlineContext(time.Time{})
+
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.Context.Context = deadlineCtx
+			ctx.SetTimeout(tc.timeout)
+			ctx.RestartDeadline()
+
+			deadline, ok := ctx.Deadline()
+			if ok != tc.expectDeadline {
+				t.Fatalf("expected a deadline %t but got %t", tc.expectDeadline, ok)
+			}
+
+			if ok && time.Until(deadline) <= time.Duration(59)*time.Minute {
+				t.Errorf("expected a deadline in about %s but got %s", tc.timeout, time.Until(deadline))
+			}
+
+			// The cancellation of the request still applies.
+			cancel()
+
+			if !errors.Is(ctx.Err(), context.Canceled) {
+				t.Errorf("expected error %v but got: %v", context.Canceled, ctx.Err())
+			}
+		})
+	}
+}
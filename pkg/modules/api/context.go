@@ -10,7 +10,9 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -32,6 +34,59 @@ var (
 	ErrOutOfBoundsOutputPath = errors.New("output path is not within context's working directory")
 )
 
+// contextOptions gathers the options of the API module related to the
+// [Context].
+type contextOptions struct {
+	// pdfEngine reads the page count of the output files.
+	// Optional.
+	pdfEngine gotenberg.PdfEngine
+
+	// outputMetadata enables the output metadata.
+	outputMetadata bool
+
+	// jsonResponseMaxSize is the maximum size, in bytes, of the output files
+	// within a JSON response. Zero means no limit.
+	jsonResponseMaxSize int64
+
+	// errorReporters receive the errors happening in an asynchronous
+	// fashion.
+	// Optional.
+	errorReporters []ErrorReporter
+
+	// fileTypeMismatch tells what to do with the uploaded files whose
+	// content does not match their extension. Empty means
+	// [FileTypeMismatchIgnore].
+	fileTypeMismatch string
+
+	// maxTimeout is the maximum time limit a request may ask for. Zero
+	// means the default timeout is also the maximum.
+	maxTimeout time.Duration
+
+	// keepaliveInterval is the interval at which to keep alive the
+	// connection of a synchronous request asking for it. Zero disables it.
+	keepaliveInterval time.Duration
+
+	// scratchRemover removes the working directory. Nil means it is removed
+	// immediately.
+	scratchRemover ScratchRemover
+
+	// disabledExtensions are the extensions disabled for the route.
+	disabledExtensions map[string]bool
+
+	// profiles are the presets of form fields a request may select.
+	// Optional.
+	profiles Profiles
+
+	// templateProvider provides the stored templates a request may
+	// reference.
+	// Optional.
+	templateProvider TemplateProvider
+
+	// assetProvider provides the shared assets a request may reference.
+	// Optional.
+	assetProvider AssetProvider
+}
+
 // Context is the request context for a "multipart/form-data" requests.
 type Context struct {
 	dirPath string
@@ -40,6 +95,40 @@ type Context struct {
 
 	outputPaths []string
 
+	pdfEngine      gotenberg.PdfEngine
+	outputMetadata bool
+	engines        []string
+	warnings       []string
+	metadataMu     sync.Mutex
+	metadata       *Metadata
+	checksums      map[string]string
+	pageCounts     map[string]int
+	riskReport     *RiskReport
+	resourceUsage  func() ResourceUsage
+
+	jsonResponseMaxSize int64
+	errorReporters      []ErrorReporter
+
+	disposition       string
+	outputContentType string
+
+	validateOnly bool
+	async        bool
+	accepted     any
+	timeout      time.Duration
+	boundFields  map[string]bool
+	boundFiles   map[string]bool
+
+	disabledExtensions map[string]bool
+
+	startMu  sync.Mutex
+	startFns []func()
+	started  bool
+
+	// stopDisconnectWatch stops cancelling the context if the client
+	// disconnects.
+	stopDisconnectWatch func() bool
+
 	cancelled bool
 	logger    *zap.Logger
 	echoCtx   echo.Context
@@ -47,15 +136,26 @@ type Context struct {
 }
 
 // newContext returns a [Context] by parsing a "multipart/form-data" request.
-func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSystem, timeout time.Duration) (*Context, context.CancelFunc, error) {
-	processCtx, processCancel := context.WithTimeout(context.Background(), timeout)
+func newContext(echoCtx echo.Context, logger *zap.Logger, storage gotenberg.Storage, timeout time.Duration, options contextOptions) (*Context, context.CancelFunc, error) {
+	startTime := time.Now()
+	processCtx, processCancel := newDeadlineContext(startTime.Add(timeout))
 
 	ctx := &Context{
-		outputPaths: make([]string, 0),
-		cancelled:   false,
-		logger:      logger,
-		echoCtx:     echoCtx,
-		Context:     processCtx,
+		outputPaths:    make([]string, 0),
+		pdfEngine:      options.pdfEngine,
+		outputMetadata: options.outputMetadata,
+		cancelled:      false,
+		logger:         logger,
+		echoCtx:        echoCtx,
+		Context:        processCtx,
+
+		jsonResponseMaxSize: options.jsonResponseMaxSize,
+		errorReporters:      options.errorReporters,
+		disabledExtensions:  options.disabledExtensions,
+	}
+
+	if !ctx.ExtensionEnabled(ExtensionOutputMetadata) {
+		ctx.outputMetadata = false
 	}
 
 	// A custom cancel function which removes the context's working directory
@@ -72,7 +172,12 @@ func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSyst
 				return
 			}
 
-			err := os.RemoveAll(ctx.dirPath)
+			removeAll := os.RemoveAll
+			if options.scratchRemover != nil {
+				removeAll = options.scratchRemover.RemoveScratch
+			}
+
+			err := removeAll(ctx.dirPath)
 			if err != nil {
 				ctx.logger.Error(fmt.Sprintf("remove context's working directory: %s", err))
 
@@ -90,28 +195,28 @@ func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSyst
 		if errors.Is(err, http.ErrNotMultipart) {
 			return nil, cancel, WrapError(
 				fmt.Errorf("get multipart form: %w", err),
-				NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: want 'multipart/form-data'"),
+				NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: want 'multipart/form-data'").WithCode(ErrorCodeInvalidContentType),
 			)
 		}
 
 		if errors.Is(err, http.ErrMissingBoundary) {
 			return nil, cancel, WrapError(
 				fmt.Errorf("get multipart form: %w", err),
-				NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: no boundary"),
+				NewSentinelHttpError(http.StatusUnsupportedMediaType, "Invalid 'Content-Type' header value: no boundary").WithCode(ErrorCodeInvalidContentType),
 			)
 		}
 
 		if strings.Contains(err.Error(), io.EOF.Error()) {
 			return nil, cancel, WrapError(
 				fmt.Errorf("get multipart form: %w", err),
-				NewSentinelHttpError(http.StatusBadRequest, "Malformed body: it does not match the 'Content-Type' header boundaries"),
+				NewSentinelHttpError(http.StatusBadRequest, "Malformed body: it does not match the 'Content-Type' header boundaries").WithCode(ErrorCodeMalformedBody),
 			)
 		}
 
 		return nil, cancel, fmt.Errorf("get multipart form: %w", err)
 	}
 
-	dirPath, err := fs.MkdirAll()
+	dirPath, err := storage.MkdirAll()
 	if err != nil {
 		return nil, cancel, fmt.Errorf("create working directory: %w", err)
 	}
@@ -119,6 +224,63 @@ func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSyst
 	ctx.dirPath = dirPath
 	ctx.values = form.Value
 	ctx.files = make(map[string]string)
+	ctx.boundFields = make(map[string]bool)
+	ctx.boundFiles = make(map[string]bool)
+
+	if ctx.ExtensionEnabled(ExtensionProfile) {
+		profile, err := applyProfile(ctx.values, options.profiles)
+		if err != nil {
+			return ctx, cancel, err
+		}
+
+		if profile != "" {
+			ctx.logger.Debug(fmt.Sprintf("profile '%s' applied", profile))
+		}
+	}
+
+	if ctx.ExtensionEnabled(ExtensionValidateOnly) {
+		err = ctx.parseValidateOnly()
+		if err != nil {
+			return ctx, cancel, err
+		}
+	}
+
+	if ctx.ExtensionEnabled(ExtensionAsync) {
+		err = ctx.parseAsync(echoCtx.Request().Header.Get(AsyncHeader))
+		if err != nil {
+			return ctx, cancel, err
+		}
+	}
+
+	processTimeout := timeout
+	if ctx.ExtensionEnabled(ExtensionProcessTimeout) {
+		processTimeout, err = parseProcessTimeout(ctx.values, timeout, options.maxTimeout)
+		if err != nil {
+			return ctx, cancel, err
+		}
+	}
+
+	if ctx.ExtensionEnabled(ExtensionDisposition) {
+		ctx.disposition, ctx.outputContentType, err = parseDisposition(ctx.values)
+		if err != nil {
+			return ctx, cancel, err
+		}
+	}
+
+	ctx.timeout = processTimeout
+
+	if processTimeout != timeout {
+		// The request overrides the time limit, which still starts when the
+		// request has been received.
+		processCtx.setDeadline(startTime.Add(processTimeout))
+	}
+
+	// If the client disconnects, nobody is waiting for the result anymore:
+	// let's cancel the context so that underlying processes stop early.
+	ctx.stopDisconnectWatch = context.AfterFunc(echoCtx.Request().Context(), func() {
+		logger.Debug("client disconnected, cancelling the request context")
+		processCancel()
+	})
 
 	copyToDisk := func(fh *multipart.FileHeader) error {
 		in, err := fh.Open()
@@ -170,12 +332,43 @@ func newContext(echoCtx echo.Context, logger *zap.Logger, fs *gotenberg.FileSyst
 		}
 	}
 
+	if ctx.ExtensionEnabled(ExtensionTemplate) {
+		err = ctx.addTemplates(options.templateProvider)
+		if err != nil {
+			return ctx, cancel, fmt.Errorf("add templates: %w", err)
+		}
+	}
+
+	if ctx.ExtensionEnabled(ExtensionAssets) {
+		err = ctx.addAssets(options.assetProvider)
+		if err != nil {
+			return ctx, cancel, fmt.Errorf("add assets: %w", err)
+		}
+	}
+
+	if ctx.ExtensionEnabled(ExtensionFileTypeMismatch) {
+		err = ctx.checkFileTypes(options.fileTypeMismatch)
+		if err != nil {
+			return ctx, cancel, fmt.Errorf("check file types: %w", err)
+		}
+	}
+
 	ctx.Log().Debug(fmt.Sprintf("form fields: %+v", ctx.values))
 	ctx.Log().Debug(fmt.Sprintf("form files: %+v", ctx.files))
 
 	return ctx, cancel, err
 }
 
+// detachFromClient stops cancelling the context if the client disconnects,
+// e.g., when the request is processed asynchronously.
+func (ctx *Context) detachFromClient() {
+	if ctx.stopDisconnectWatch == nil {
+		return
+	}
+
+	ctx.stopDisconnectWatch()
+}
+
 // Request returns the [http.Request].
 func (ctx *Context) Request() *http.Request {
 	return ctx.echoCtx.Request()
@@ -187,6 +380,7 @@ func (ctx *Context) FormData() *FormData {
 		values: ctx.values,
 		files:  ctx.files,
 		errors: nil,
+		ctx:    ctx,
 	}
 }
 
@@ -219,6 +413,70 @@ func (ctx *Context) AddOutputPaths(paths ...string) error {
 	return nil
 }
 
+// InputPaths returns the absolute paths of the uploaded files, in
+// alphanumeric order.
+func (ctx *Context) InputPaths() []string {
+	paths := make([]string, 0, len(ctx.files))
+	for _, path := range ctx.files {
+		paths = append(paths, path)
+	}
+
+	sort.Sort(gotenberg.AlphanumericSort(paths))
+
+	return paths
+}
+
+// addInputFile copies a file into the working directory, as if the client had
+// uploaded it. It returns [os.ErrExist] if there is already a file with the
+// same filename.
+func (ctx *Context) addInputFile(filename string, in io.Reader) error {
+	// Same as the uploaded files.
+	filename = norm.NFC.String(filepath.Base(filename))
+
+	_, ok := ctx.files[filename]
+	if ok {
+		return fmt.Errorf("file '%s': %w", filename, os.ErrExist)
+	}
+
+	path := fmt.Sprintf("%s/%s", ctx.dirPath, filename)
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create local file: %w", err)
+	}
+
+	defer func() {
+		err := out.Close()
+		if err != nil {
+			ctx.Log().Error(fmt.Sprintf("close local file: %s", err))
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		return fmt.Errorf("copy to local file: %w", err)
+	}
+
+	ctx.files[filename] = path
+
+	return nil
+}
+
+// FormValues returns a copy of the form fields.
+func (ctx *Context) FormValues() map[string][]string {
+	values := make(map[string][]string, len(ctx.values))
+	for key, value := range ctx.values {
+		values[key] = append([]string(nil), value...)
+	}
+
+	return values
+}
+
+// OutputPaths returns the registered output paths.
+func (ctx *Context) OutputPaths() []string {
+	return ctx.outputPaths
+}
+
 // Log returns the context [zap.Logger].
 func (ctx *Context) Log() *zap.Logger {
 	return ctx.logger
@@ -241,7 +499,89 @@ func (ctx *Context) BuildOutputFile() (string, error) {
 		return ctx.outputPaths[0], nil
 	}
 
-	z := archiver.Zip{
+	archivePaths, err := ctx.outputArchivePaths()
+	if err != nil {
+		return "", err
+	}
+
+	z := newZip()
+
+	archivePath := ctx.GeneratePath("", ".zip")
+
+	err = z.Archive(archivePaths, archivePath)
+	if err != nil {
+		return "", fmt.Errorf("archive output files: %w", err)
+	}
+
+	ctx.logger.Debug(fmt.Sprintf("archive '%s' created", archivePath))
+
+	return archivePath, nil
+}
+
+// IsOutputArchive returns true if there are many output files, i.e., if
+// the output file is a ZIP archive.
+func (ctx *Context) IsOutputArchive() bool {
+	return len(ctx.outputPaths) > 1
+}
+
+// WriteOutputArchive writes the ZIP archive of the output files to the given
+// writer while creating it, i.e., without creating it on disk first.
+func (ctx *Context) WriteOutputArchive(w io.Writer) error {
+	if ctx.cancelled {
+		return ErrContextAlreadyClosed
+	}
+
+	if !ctx.IsOutputArchive() {
+		return errors.New("not enough output paths for an archive")
+	}
+
+	archivePaths, err := ctx.outputArchivePaths()
+	if err != nil {
+		return err
+	}
+
+	z := newZip()
+
+	err = z.Create(w)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+
+	for _, path := range archivePaths {
+		err = writeArchiveFile(z, path)
+		if err != nil {
+			return fmt.Errorf("archive output file '%s': %w", path, err)
+		}
+	}
+
+	err = z.Close()
+	if err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
+
+// outputArchivePaths returns the paths of the files to archive, with the
+// metadata file if requested.
+func (ctx *Context) outputArchivePaths() ([]string, error) {
+	archivePaths := ctx.outputPaths
+
+	if ctx.outputMetadata {
+		metadataPath, err := ctx.writeMetadata()
+		if err != nil {
+			return nil, fmt.Errorf("write output metadata: %w", err)
+		}
+
+		archivePaths = append(archivePaths[:len(archivePaths):len(archivePaths)], metadataPath)
+	}
+
+	return archivePaths, nil
+}
+
+// newZip returns the [archiver.Zip] which archives the output files.
+func newZip() *archiver.Zip {
+	return &archiver.Zip{
 		CompressionLevel:       flate.DefaultCompression,
 		MkdirAll:               true,
 		SelectiveCompression:   true,
@@ -249,17 +589,28 @@ func (ctx *Context) BuildOutputFile() (string, error) {
 		OverwriteExisting:      false,
 		ImplicitTopLevelFolder: false,
 	}
+}
 
-	archivePath := ctx.GeneratePath("", ".zip")
-
-	err := z.Archive(ctx.outputPaths, archivePath)
+// writeArchiveFile writes a file to an archive being created.
+func writeArchiveFile(z *archiver.Zip, path string) error {
+	f, err := os.Open(path)
 	if err != nil {
-		return "", fmt.Errorf("archive output files: %w", err)
+		return fmt.Errorf("open file: %w", err)
 	}
 
-	ctx.logger.Debug(fmt.Sprintf("archive '%s' created", archivePath))
+	defer func() {
+		_ = f.Close()
+	}()
 
-	return archivePath, nil
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("get file stat: %w", err)
+	}
+
+	return z.Write(archiver.File{
+		FileInfo:   info,
+		ReadCloser: f,
+	})
 }
 
 // OutputFilename returns the filename based on the given output path or the
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"archive/zip"
 	"bytes"
+	"context"
 	"errors"
 	"mime/multipart"
 	"net/http"
@@ -100,7 +102,7 @@ func TestNewContext(t *testing.T) {
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			handler := func(c echo.Context) error {
-				_, cancel, err := newContext(c, zap.NewNop(), gotenberg.NewFileSystem(), time.Duration(10)*time.Second)
+				_, cancel, err := newContext(c, zap.NewNop(), gotenberg.NewFileSystem(), time.Duration(10)*time.Second, contextOptions{})
 				defer cancel()
 				// Context already cancelled.
 				defer cancel()
@@ -150,6 +152,66 @@ func TestNewContext(t *testing.T) {
 	}
 }
 
+func TestNewContext_ClientDisconnect(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		detach          bool
+		expectCancelled bool
+	}{
+		{
+			scenario:        "client disconnects",
+			expectCancelled: true,
+		},
+		{
+			scenario:        "client disconnects after detaching",
+			detach:          true,
+			expectCancelled: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			err := writer.Close()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			clientCtx, clientCancel := context.WithCancel(context.Background())
+			defer clientCancel()
+
+			req := httptest.NewRequest(http.MethodPost, "/", body).WithContext(clientCtx)
+			req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+
+			srv := echo.New()
+			c := srv.NewContext(req, httptest.NewRecorder())
+
+			ctx, cancel, err := newContext(c, zap.NewNop(), gotenberg.NewFileSystem(), time.Duration(10)*time.Second, contextOptions{})
+			defer cancel()
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.detach {
+				ctx.detachFromClient()
+			}
+
+			clientCancel()
+
+			select {
+			case <-ctx.Done():
+				if !tc.expectCancelled {
+					t.Fatal("expected context not to be cancelled")
+				}
+			case <-time.After(time.Duration(100) * time.Millisecond):
+				if tc.expectCancelled {
+					t.Fatal("expected context to be cancelled")
+				}
+			}
+		})
+	}
+}
+
 func TestContext_Request(t *testing.T) {
 	request := httptest.NewRequest(http.MethodPost, "/", nil)
 	recorder := httptest.NewRecorder()
@@ -178,6 +240,7 @@ func TestContext_FormData(t *testing.T) {
 	expect := &FormData{
 		values: ctx.values,
 		files:  ctx.files,
+		ctx:    ctx,
 	}
 
 	if !reflect.DeepEqual(actual, expect) {
@@ -248,6 +311,41 @@ func TestContext_AddOutputPaths(t *testing.T) {
 	}
 }
 
+func TestContext_InputPaths(t *testing.T) {
+	ctx := Context{files: map[string]string{"b.pdf": "/foo/b.pdf", "c.pdf": "/foo/c.pdf", "a.pdf": "/foo/a.pdf"}}
+	expect := []string{"/foo/a.pdf", "/foo/b.pdf", "/foo/c.pdf"}
+	actual := ctx.InputPaths()
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %v but got %v", expect, actual)
+	}
+}
+
+func TestContext_FormValues(t *testing.T) {
+	expect := map[string][]string{"foo": {"bar"}}
+	ctx := Context{values: map[string][]string{"foo": {"bar"}}}
+	actual := ctx.FormValues()
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %v but got %v", expect, actual)
+	}
+
+	actual["foo"][0] = "baz"
+	if ctx.values["foo"][0] != "bar" {
+		t.Error("expected a copy of the form fields")
+	}
+}
+
+func TestContext_OutputPaths(t *testing.T) {
+	expect := []string{"/foo/foo.pdf"}
+	ctx := Context{outputPaths: expect}
+	actual := ctx.OutputPaths()
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %v but got %v", expect, actual)
+	}
+}
+
 func TestContext_Log(t *testing.T) {
 	expect := zap.NewNop()
 	ctx := Context{logger: expect}
@@ -325,6 +423,89 @@ func TestContext_BuildOutputFile(t *testing.T) {
 	}
 }
 
+func TestContext_WriteOutputArchive(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		ctx           *Context
+		expectError   bool
+		expectEntries []string
+	}{
+		{
+			scenario:    "ErrContextAlreadyClosed",
+			ctx:         &Context{cancelled: true},
+			expectError: true,
+		},
+		{
+			scenario:    "no archive: one output path",
+			ctx:         &Context{outputPaths: []string{"/tests/test/testdata/api/sample1.txt"}},
+			expectError: true,
+		},
+		{
+			scenario:    "cannot archive: invalid output paths",
+			ctx:         &Context{outputPaths: []string{"foo.txt", "foo.pdf"}},
+			expectError: true,
+		},
+		{
+			scenario: "success: many output paths",
+			ctx: &Context{
+				outputPaths: []string{
+					"/tests/test/testdata/api/sample1.txt",
+					"/tests/test/testdata/api/sample2.pdf",
+				},
+			},
+			expectError:   false,
+			expectEntries: []string{"sample1.txt", "sample2.pdf"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			fs := gotenberg.NewFileSystem()
+			dirPath, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected no erro but got: %v", err)
+			}
+
+			defer func() {
+				err := os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			tc.ctx.dirPath = dirPath
+			tc.ctx.logger = zap.NewNop()
+
+			buf := new(bytes.Buffer)
+			err = tc.ctx.WriteOutputArchive(buf)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var entries []string
+			for _, f := range r.File {
+				entries = append(entries, f.Name)
+			}
+
+			if !reflect.DeepEqual(entries, tc.expectEntries) {
+				t.Errorf("expected entries %v but got %v", tc.expectEntries, entries)
+			}
+		})
+	}
+}
+
 func TestContext_OutputFilename(t *testing.T) {
 	for _, tc := range []struct {
 		scenario             string
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InputSize returns the total size, in bytes, of the uploaded files.
+func (ctx *Context) InputSize() (int64, error) {
+	var size int64
+
+	for _, path := range ctx.files {
+		stat, err := os.Stat(path)
+		if err != nil {
+			return 0, fmt.Errorf("get stat from file: %w", err)
+		}
+
+		size += stat.Size()
+	}
+
+	return size, nil
+}
+
+// EstimatedPageCount returns the total number of pages of the uploaded files,
+// as estimated before the conversion. The pages of some files, e.g.,
+// spreadsheets, are unknown before the conversion. See [EstimatePageCount].
+func (ctx *Context) EstimatedPageCount() int {
+	var count int
+
+	for filename, path := range ctx.files {
+		count += ctx.pageCount(filename, path)
+	}
+
+	return count
+}
+
+// pageCount returns the estimated number of pages of an uploaded file, or 0
+// if unknown. If the PDF page tree cannot be read directly, e.g., because of
+// compressed objects, the PDF engine counts the pages. As the estimations may
+// be used more than once, e.g., for the admission control and the pre-flight
+// report, they are computed once per file.
+func (ctx *Context) pageCount(filename, path string) int {
+	ctx.metadataMu.Lock()
+	count, ok := ctx.pageCounts[path]
+	ctx.metadataMu.Unlock()
+
+	if ok {
+		return count
+	}
+
+	count, err := EstimatePageCount(path)
+	if err != nil {
+		ctx.logger.Debug(fmt.Sprintf("estimate pages of '%s': %s", path, err))
+
+		count = 0
+		if ctx.pdfEngine != nil && strings.EqualFold(filepath.Ext(filename), ".pdf") {
+			count, err = ctx.pdfEngine.PageCount(ctx, ctx.logger, path)
+			if err != nil {
+				// Not critical, the page count is an estimation.
+				ctx.logger.Debug(fmt.Sprintf("count pages of '%s': %s", path, err))
+
+				count = 0
+			}
+		}
+	}
+
+	ctx.metadataMu.Lock()
+	defer ctx.metadataMu.Unlock()
+
+	if ctx.pageCounts == nil {
+		ctx.pageCounts = make(map[string]int)
+	}
+
+	ctx.pageCounts[path] = count
+
+	return count
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestContext_InputSize(t *testing.T) {
+	dirPath := t.TempDir()
+
+	files := map[string]string{
+		"foo.pdf":  dirPath + "/foo.pdf",
+		"bar.docx": dirPath + "/bar.docx",
+	}
+
+	err := os.WriteFile(files["foo.pdf"], []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = os.WriteFile(files["bar.docx"], []byte("barbar"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	ctx := &ContextMock{Context: new(Context)}
+	ctx.SetFiles(files)
+
+	size, err := ctx.InputSize()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if size != 9 {
+		t.Errorf("expected 9 but got %d", size)
+	}
+
+	ctx.SetFiles(map[string]string{"baz.pdf": dirPath + "/baz.pdf"})
+
+	_, err = ctx.InputSize()
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
+
+func TestContext_EstimatedPageCount(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		pdfEngine   gotenberg.PdfEngine
+		expectCount int
+	}{
+		{
+			scenario:    "no PDF engine",
+			expectCount: 0,
+		},
+		{
+			scenario: "PDF files",
+			pdfEngine: &gotenberg.PdfEngineMock{
+				PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+					if filepath.Base(inputPath) == "bar.pdf" {
+						return 0, errors.New("foo")
+					}
+
+					return 3, nil
+				},
+			},
+			expectCount: 6,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetFiles(map[string]string{
+				"foo.pdf":  "/foo.pdf",
+				"bar.pdf":  "/bar.pdf",
+				"baz.PDF":  "/baz.PDF",
+				"qux.docx": "/qux.docx",
+			})
+			ctx.SetLogger(zap.NewNop())
+			ctx.Context.Context = context.Background()
+			ctx.pdfEngine = tc.pdfEngine
+
+			count := ctx.EstimatedPageCount()
+			if count != tc.expectCount {
+				t.Errorf("expected %d but got %d", tc.expectCount, count)
+			}
+		})
+	}
+}
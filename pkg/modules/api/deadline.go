@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// deadlineContext is the [context.Context] of a request. Its deadline may be
+// suspended then restarted, e.g., while an asynchronous request waits for its
+// turn, without replacing the context, which keeps its cancellation. It is
+// safe for concurrent use.
+type deadlineContext struct {
+	done chan struct{}
+
+	mu       sync.Mutex
+	deadline time.Time
+	timer    *time.Timer
+	err      error
+}
+
+// newDeadlineContext returns a [deadlineContext] with the given deadline,
+// and the function which cancels it.
+func newDeadlineContext(deadline time.Time) (*deadlineContext, context.CancelFunc) {
+	ctx := &deadlineContext{done: make(chan struct{})}
+	ctx.setDeadline(deadline)
+
+	return ctx, func() {
+		ctx.cancel(context.Canceled)
+	}
+}
+
+// Deadline returns the deadline of the context, if any.
+func (ctx *deadlineContext) Deadline() (time.Time, bool) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	return ctx.deadline, !ctx.deadline.IsZero()
+}
+
+// Done returns a channel closed once the context is cancelled or past its
+// deadline.
+func (ctx *deadlineContext) Done() <-chan struct{} {
+	return ctx.done
+}
+
+// Err returns either [context.Canceled] or [context.DeadlineExceeded] once
+// the context is done, nil otherwise.
+func (ctx *deadlineContext) Err() error {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	return ctx.err
+}
+
+// Value returns nil, as the context carries no values.
+func (ctx *deadlineContext) Value(key any) any {
+	return nil
+}
+
+// setDeadline replaces the deadline of the context. A zero deadline
+// suspends it. It does nothing once the context is done.
+func (ctx *deadlineContext) setDeadline(deadline time.Time) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	if ctx.err != nil {
+		return
+	}
+
+	if ctx.timer != nil {
+		ctx.timer.Stop()
+		ctx.timer = nil
+	}
+
+	ctx.deadline = deadline
+
+	if deadline.IsZero() {
+		return
+	}
+
+	ctx.timer = time.AfterFunc(time.Until(deadline), func() {
+		ctx.mu.Lock()
+		defer ctx.mu.Unlock()
+
+		// The deadline may have changed while the timer fired.
+		if ctx.deadline.Equal(deadline) {
+			ctx.finish(context.DeadlineExceeded)
+		}
+	})
+}
+
+// cancel makes the context done with the given error, unless it is already.
+func (ctx *deadlineContext) cancel(err error) {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	ctx.finish(err)
+}
+
+// finish makes the context done with the given error, unless it is already.
+// The caller holds the lock.
+func (ctx *deadlineContext) finish(err error) {
+	if ctx.err != nil {
+		return
+	}
+
+	ctx.err = err
+	close(ctx.done)
+
+	if ctx.timer != nil {
+		ctx.timer.Stop()
+		ctx.timer = nil
+	}
+}
+
+// Interface guards.
+var (
+	_ context.Context = (*deadlineContext)(nil)
+)
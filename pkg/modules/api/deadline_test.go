@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeadlineContext(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		deadline    time.Time
+		setDeadline func(ctx *deadlineContext)
+		cancel      bool
+		expectDone  bool
+		expectedErr error
+	}{
+		{
+			scenario:   "no deadline",
+			expectDone: false,
+		},
+		{
+			scenario:    "past deadline",
+			deadline:    time.Now().Add(-time.Second),
+			expectDone:  true,
+			expectedErr: context.DeadlineExceeded,
+		},
+		{
+			scenario: "suspended deadline",
+			deadline: time.Now().Add(time.Millisecond),
+			setDeadline: func(ctx *deadlineContext) {
+				ctx.setDeadline(time.Time{})
+			},
+			expectDone: false,
+		},
+		{
+			scenario: "restarted deadline",
+			deadline: time.Now().Add(time.Millisecond),
+			setDeadline: func(ctx *deadlineContext) {
+				ctx.setDeadline(time.Now().Add(time.Hour))
+			},
+			expectDone: false,
+		},
+		{
+			scenario:    "cancelled",
+			deadline:    time.Now().Add(time.Hour),
+			cancel:      true,
+			expectDone:  true,
+			expectedErr: context.Canceled,
+		},
+		{
+			scenario: "deadline of a cancelled context",
+			setDeadline: func(ctx *deadlineContext) {
+				ctx.cancel(context.Canceled)
+				ctx.setDeadline(time.Now().Add(-time.Second))
+			},
+			expectDone:  true,
+			expectedErr: context.Canceled,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx, cancel := newDeadlineContext(tc.deadline)
+			defer cancel()
+
+			if tc.setDeadline != nil {
+				tc.setDeadline(ctx)
+			}
+
+			if tc.cancel {
+				cancel()
+			}
+
+			select {
+			case <-ctx.Done():
+				if !tc.expectDone {
+					t.Fatalf("expected the context not to be done but got: %v", ctx.Err())
+				}
+			case <-time.After(time.Duration(50) * time.Millisecond):
+				if tc.expectDone {
+					t.Fatal("expected the context to be done")
+				}
+			}
+
+			if !errors.Is(ctx.Err(), tc.expectedErr) {
+				t.Errorf("expected error %v but got: %v", tc.expectedErr, ctx.Err())
+			}
+		})
+	}
+}
+
+func TestDeadlineContext_derived(t *testing.T) {
+	ctx, cancel := newDeadlineContext(time.Now().Add(time.Millisecond))
+	defer cancel()
+
+	derived, cancelDerived := context.WithCancel(ctx)
+	defer cancelDerived()
+
+	select {
+	case <-derived.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the derived context to be done")
+	}
+
+	if !errors.Is(derived.Err(), context.DeadlineExceeded) {
+		t.Errorf("expected error %v but got: %v", context.DeadlineExceeded, derived.Err())
+	}
+}
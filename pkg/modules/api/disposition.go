@@ -0,0 +1,152 @@
+package api
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// dispositionField is the form field which tells whether the output file
+	// is an attachment or should be displayed inline, e.g., by a browser.
+	dispositionField = "disposition"
+
+	// contentTypeField is the form field which overrides the content type of
+	// the output file.
+	contentTypeField = "contentType"
+
+	// DispositionAttachment is the default disposition of the output file.
+	DispositionAttachment = "attachment"
+
+	// DispositionInline is the disposition of an output file to display
+	// inline.
+	DispositionInline = "inline"
+)
+
+// safeContentTypes are the content types browsers do not run scripts of, as
+// opposed to, e.g., HTML or SVG. The "contentType" form field must be one of
+// them, and an output file of another content type is always an attachment.
+var safeContentTypes = map[string]bool{
+	"application/epub+zip":     true,
+	"application/json":         true,
+	"application/octet-stream": true,
+	"application/pdf":          true,
+	"application/x-pdf":        true,
+	"application/zip":          true,
+	"image/gif":                true,
+	"image/jpeg":               true,
+	"image/png":                true,
+	"image/tiff":               true,
+	"image/webp":               true,
+	"text/csv":                 true,
+	"text/plain":               true,
+}
+
+func init() {
+	// Go knows the TIFF images only thanks to the MIME types database of the
+	// system, if any, while the image engines render them.
+	for _, ext := range []string{".tif", ".tiff"} {
+		_ = mime.AddExtensionType(ext, "image/tiff")
+	}
+}
+
+// isSafeContentType tells if a content type is one of [safeContentTypes].
+func isSafeContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return safeContentTypes[strings.ToLower(mediaType)]
+}
+
+// parseDisposition reads the "disposition" and "contentType" form fields. It
+// returns [DispositionAttachment] and an empty content type if they are
+// empty, i.e., the content type is the one of the output file.
+func parseDisposition(values map[string][]string) (string, string, error) {
+	disposition := DispositionAttachment
+	contentType := ""
+
+	val, ok := values[dispositionField]
+	if ok && val[0] != "" {
+		if val[0] != DispositionAttachment && val[0] != DispositionInline {
+			return "", "", invalidDispositionError(
+				fmt.Errorf("form field '%s' is invalid (got '%s', expected either '%s' or '%s')", dispositionField, val[0], DispositionAttachment, DispositionInline),
+			)
+		}
+
+		disposition = val[0]
+	}
+
+	val, ok = values[contentTypeField]
+	if ok && val[0] != "" {
+		mediaType, params, err := mime.ParseMediaType(val[0])
+		if err != nil {
+			return "", "", invalidDispositionError(
+				fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", contentTypeField, val[0], err),
+			)
+		}
+
+		if !safeContentTypes[mediaType] {
+			return "", "", invalidDispositionError(
+				fmt.Errorf("form field '%s' is invalid (got '%s', which browsers may run scripts of)", contentTypeField, val[0]),
+			)
+		}
+
+		contentType = mime.FormatMediaType(mediaType, params)
+	}
+
+	return disposition, contentType, nil
+}
+
+// invalidDispositionError wraps an error of the "disposition" or
+// "contentType" form fields with a 400 [SentinelHttpError].
+func invalidDispositionError(err error) error {
+	return WrapError(
+		err,
+		NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode(ErrorCodeInvalidFormData),
+	)
+}
+
+// OutputDisposition returns the disposition of the output file, either
+// [DispositionAttachment] or [DispositionInline]. An archive is always an
+// attachment.
+func (ctx *Context) OutputDisposition() string {
+	if ctx.disposition == "" || ctx.IsOutputArchive() {
+		return DispositionAttachment
+	}
+
+	return ctx.disposition
+}
+
+// OutputContentType returns the content type the client asks for the output
+// file, if any. It is empty for an archive, which is always a ZIP archive.
+func (ctx *Context) OutputContentType() string {
+	if ctx.IsOutputArchive() {
+		return ""
+	}
+
+	return ctx.outputContentType
+}
+
+// OutputContentDisposition returns the "Content-Disposition" header's value
+// of the given output file, i.e., the result of [Context.BuildOutputFile]. The
+// output file is an attachment if browsers may run its scripts.
+func (ctx *Context) OutputContentDisposition(outputPath string) string {
+	disposition := ctx.OutputDisposition()
+
+	contentType := ctx.OutputContentType()
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(outputPath))
+	}
+
+	if !isSafeContentType(contentType) {
+		disposition = DispositionAttachment
+	}
+
+	return mime.FormatMediaType(disposition, map[string]string{
+		"filename": ctx.OutputFilename(outputPath),
+	})
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseDisposition(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		values            map[string][]string
+		expectDisposition string
+		expectContentType string
+		expectError       bool
+	}{
+		{
+			scenario:          "no form fields",
+			values:            map[string][]string{},
+			expectDisposition: DispositionAttachment,
+		},
+		{
+			scenario:          "empty form fields",
+			values:            map[string][]string{"disposition": {""}, "contentType": {""}},
+			expectDisposition: DispositionAttachment,
+		},
+		{
+			scenario:    "invalid disposition form field",
+			values:      map[string][]string{"disposition": {"foo"}},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid contentType form field",
+			values:      map[string][]string{"contentType": {"application/"}},
+			expectError: true,
+		},
+		{
+			scenario:    "script-capable contentType form field",
+			values:      map[string][]string{"disposition": {"inline"}, "contentType": {"image/svg+xml"}},
+			expectError: true,
+		},
+		{
+			scenario:          "inline disposition",
+			values:            map[string][]string{"disposition": {"inline"}},
+			expectDisposition: DispositionInline,
+		},
+		{
+			scenario:          "content type",
+			values:            map[string][]string{"contentType": {"Image/PNG"}},
+			expectDisposition: DispositionAttachment,
+			expectContentType: "image/png",
+		},
+		{
+			scenario:          "content type with parameters",
+			values:            map[string][]string{"disposition": {"inline"}, "contentType": {"text/plain;charset=UTF-8"}},
+			expectDisposition: DispositionInline,
+			expectContentType: "text/plain; charset=UTF-8",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			disposition, contentType, err := parseDisposition(tc.values)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				var httpErr HttpError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("expected an HTTP error but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != http.StatusBadRequest {
+					t.Errorf("expected %d status code but got %d", http.StatusBadRequest, status)
+				}
+
+				return
+			}
+
+			if disposition != tc.expectDisposition {
+				t.Errorf("expected disposition '%s' but got '%s'", tc.expectDisposition, disposition)
+			}
+
+			if contentType != tc.expectContentType {
+				t.Errorf("expected content type '%s' but got '%s'", tc.expectContentType, contentType)
+			}
+		})
+	}
+}
+
+func TestContext_OutputContentDisposition(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		ctx               *Context
+		expectDisposition string
+		expectContentType string
+	}{
+		{
+			scenario: "default",
+			ctx: &Context{
+				outputPaths: []string{"/foo.pdf"},
+			},
+			expectDisposition: "attachment; filename=foo.pdf",
+		},
+		{
+			scenario: "single output file",
+			ctx: &Context{
+				outputPaths:       []string{"/foo.pdf"},
+				disposition:       DispositionInline,
+				outputContentType: "application/x-pdf",
+			},
+			expectDisposition: "inline; filename=foo.pdf",
+			expectContentType: "application/x-pdf",
+		},
+		{
+			scenario: "TIFF image",
+			ctx: &Context{
+				outputPaths: []string{"/foo.tif"},
+				disposition: DispositionInline,
+			},
+			expectDisposition: "inline; filename=foo.tif",
+		},
+		{
+			scenario: "script-capable output file",
+			ctx: &Context{
+				outputPaths: []string{"/foo.html"},
+				disposition: DispositionInline,
+			},
+			expectDisposition: "attachment; filename=foo.html",
+		},
+		{
+			scenario: "archive",
+			ctx: &Context{
+				outputPaths:       []string{"/foo.pdf", "/bar.pdf"},
+				disposition:       DispositionInline,
+				outputContentType: "application/x-pdf",
+			},
+			expectDisposition: "attachment; filename=foo.zip",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.echoCtx = echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+
+			outputPath := tc.ctx.outputPaths[0]
+			if tc.ctx.IsOutputArchive() {
+				outputPath = "/foo.zip"
+			}
+
+			disposition := tc.ctx.OutputContentDisposition(outputPath)
+			if disposition != tc.expectDisposition {
+				t.Errorf("expected '%s' but got '%s'", tc.expectDisposition, disposition)
+			}
+
+			contentType := tc.ctx.OutputContentType()
+			if contentType != tc.expectContentType {
+				t.Errorf("expected content type '%s' but got '%s'", tc.expectContentType, contentType)
+			}
+		})
+	}
+}
@@ -1,16 +1,148 @@
 package api
 
+import (
+	"net/http"
+	"strings"
+)
+
 // Credits: https://www.joeshaw.org/error-handling-in-go-http-applications.
 
+// Stable and machine-readable error codes, returned in the "code" field of the
+// JSON error responses. The codes specific to a module are prefixed by its
+// name (e.g., "CHROMIUM_NAVIGATION_TIMEOUT").
+const (
+	ErrorCodeInternal                     = "INTERNAL_ERROR"
+	ErrorCodeTimeout                      = "TIMEOUT"
+	ErrorCodeForbidden                    = "FORBIDDEN"
+	ErrorCodeUnauthorized                 = "UNAUTHORIZED"
+	ErrorCodeMaximumQueueSizeExceeded     = "MAXIMUM_QUEUE_SIZE_EXCEEDED"
+	ErrorCodePdfEngineUnsupportedFormat   = "PDF_ENGINE_UNSUPPORTED_FORMAT"
+	ErrorCodeImageEngineUnsupportedFormat = "IMAGE_ENGINE_UNSUPPORTED_FORMAT"
+	ErrorCodeInvalidFormData              = "INVALID_FORM_DATA"
+	ErrorCodeInvalidContentType           = "INVALID_CONTENT_TYPE"
+	ErrorCodeInvalidAsyncHeader           = "INVALID_ASYNC_HEADER"
+	ErrorCodeMalformedBody                = "MALFORMED_BODY"
+	ErrorCodeJsonResponseTooLarge         = "JSON_RESPONSE_TOO_LARGE"
+	ErrorCodeInsufficientStorage          = "INSUFFICIENT_STORAGE"
+	ErrorCodeFileTypeMismatch             = "FILE_TYPE_MISMATCH"
+	ErrorCodeAllPagesBlank                = "ALL_PAGES_BLANK"
+	ErrorCodeOutputTooLarge               = "OUTPUT_TOO_LARGE"
+)
+
+// Error codes of the modules.
+const (
+	ErrorCodeArchivalInvalidOcrLanguages = "ARCHIVAL_INVALID_OCR_LANGUAGES"
+	ErrorCodeArchivalInvalidPdf          = "ARCHIVAL_INVALID_PDF"
+
+	ErrorCodeAssetsAssetTooLarge = "ASSETS_ASSET_TOO_LARGE"
+
+	ErrorCodeChaosFaultAlreadyExists = "CHAOS_FAULT_ALREADY_EXISTS"
+	ErrorCodeChaosFaultNotFound      = "CHAOS_FAULT_NOT_FOUND"
+	ErrorCodeChaosInvalidFault       = "CHAOS_INVALID_FAULT"
+
+	ErrorCodeChromiumConsoleExceptions                    = "CHROMIUM_CONSOLE_EXCEPTIONS"
+	ErrorCodeChromiumInvalidCsv                           = "CHROMIUM_INVALID_CSV"
+	ErrorCodeChromiumInvalidEvaluationExpression          = "CHROMIUM_INVALID_EVALUATION_EXPRESSION"
+	ErrorCodeChromiumInvalidHttpStatusCode                = "CHROMIUM_INVALID_HTTP_STATUS_CODE"
+	ErrorCodeChromiumInvalidMarkup                        = "CHROMIUM_INVALID_MARKUP"
+	ErrorCodeChromiumInvalidPrinterSettings               = "CHROMIUM_INVALID_PRINTER_SETTINGS"
+	ErrorCodeChromiumInvalidSvg                           = "CHROMIUM_INVALID_SVG"
+	ErrorCodeChromiumMalformedPageRanges                  = "CHROMIUM_MALFORMED_PAGE_RANGES"
+	ErrorCodeChromiumMarkdownFilesNotFound                = "CHROMIUM_MARKDOWN_FILES_NOT_FOUND"
+	ErrorCodeChromiumNavigationTimeout                    = "CHROMIUM_NAVIGATION_TIMEOUT"
+	ErrorCodeChromiumOmitBackgroundWithoutPrintBackground = "CHROMIUM_OMIT_BACKGROUND_WITHOUT_PRINT_BACKGROUND"
+	ErrorCodeChromiumTooManySvgFiles                      = "CHROMIUM_TOO_MANY_SVG_FILES"
+	ErrorCodeChromiumUnsupportedMarkup                    = "CHROMIUM_UNSUPPORTED_MARKUP"
+
+	ErrorCodeClamavFileTooLarge   = "CLAMAV_FILE_TOO_LARGE"
+	ErrorCodeClamavInfectedFile   = "CLAMAV_INFECTED_FILE"
+	ErrorCodeClamavInfectedOutput = "CLAMAV_INFECTED_OUTPUT"
+	ErrorCodeClamavUnavailable    = "CLAMAV_UNAVAILABLE"
+
+	ErrorCodeConcurrencyQueueTimeout = "CONCURRENCY_QUEUE_TIMEOUT"
+	ErrorCodeConcurrencySlowLaneFull = "CONCURRENCY_SLOW_LANE_FULL"
+
+	ErrorCodeDistributedInvalidQuery     = "DISTRIBUTED_INVALID_QUERY"
+	ErrorCodeDistributedQueueUnavailable = "DISTRIBUTED_QUEUE_UNAVAILABLE"
+
+	ErrorCodeDocxtemplateInvalidTemplate = "DOCXTEMPLATE_INVALID_TEMPLATE"
+
+	ErrorCodeEmailMalformedMessage = "EMAIL_MALFORMED_MESSAGE"
+
+	ErrorCodeGhostscriptInvalidPdf = "GHOSTSCRIPT_INVALID_PDF"
+
+	ErrorCodeHooksFailed = "HOOKS_FAILED"
+
+	ErrorCodeImagesInvalidImage      = "IMAGES_INVALID_IMAGE"
+	ErrorCodeImagesInvalidPageLayout = "IMAGES_INVALID_PAGE_LAYOUT"
+
+	ErrorCodeJobsAsyncNotAvailable = "JOBS_ASYNC_NOT_AVAILABLE"
+	ErrorCodeJobsInvalidQuery      = "JOBS_INVALID_QUERY"
+	ErrorCodeJobsJobNotDone        = "JOBS_JOB_NOT_DONE"
+	ErrorCodeJobsJobNotFound       = "JOBS_JOB_NOT_FOUND"
+	ErrorCodeJobsResultNotFound    = "JOBS_RESULT_NOT_FOUND"
+
+	ErrorCodeLatexCompilationFailed = "LATEX_COMPILATION_FAILED"
+	ErrorCodeLatexInvalidMainFile   = "LATEX_INVALID_MAIN_FILE"
+
+	ErrorCodeLibreofficeInvalidDocument     = "LIBREOFFICE_INVALID_DOCUMENT"
+	ErrorCodeLibreofficeInvalidPdf          = "LIBREOFFICE_INVALID_PDF"
+	ErrorCodeLibreofficeInvalidPdfFormats   = "LIBREOFFICE_INVALID_PDF_FORMATS"
+	ErrorCodeLibreofficeInvalidWorkbook     = "LIBREOFFICE_INVALID_WORKBOOK"
+	ErrorCodeLibreofficeMalformedPageRanges = "LIBREOFFICE_MALFORMED_PAGE_RANGES"
+
+	ErrorCodePdftohtmlInvalidPdf = "PDFTOHTML_INVALID_PDF"
+
+	ErrorCodePipelineUploadFailed = "PIPELINE_UPLOAD_FAILED"
+
+	ErrorCodePolicyFileTypeForbidden = "POLICY_FILE_TYPE_FORBIDDEN"
+	ErrorCodePolicyRouteForbidden    = "POLICY_ROUTE_FORBIDDEN"
+	ErrorCodePolicyUnknownKey        = "POLICY_UNKNOWN_KEY"
+
+	ErrorCodeResourcesMemoryLimitExceeded = "RESOURCES_MEMORY_LIMIT_EXCEEDED"
+
+	ErrorCodeResultsExpired          = "RESULTS_EXPIRED"
+	ErrorCodeResultsInvalidSignature = "RESULTS_INVALID_SIGNATURE"
+
+	ErrorCodeSchedulerInvalidJob       = "SCHEDULER_INVALID_JOB"
+	ErrorCodeSchedulerJobAlreadyExists = "SCHEDULER_JOB_ALREADY_EXISTS"
+	ErrorCodeSchedulerJobNotFound      = "SCHEDULER_JOB_NOT_FOUND"
+
+	ErrorCodeTemplatesInvalidTemplate  = "TEMPLATES_INVALID_TEMPLATE"
+	ErrorCodeTemplatesTemplateNotFound = "TEMPLATES_TEMPLATE_NOT_FOUND"
+	ErrorCodeTemplatesTemplateTooLarge = "TEMPLATES_TEMPLATE_TOO_LARGE"
+
+	ErrorCodeThumbnailInvalidImage = "THUMBNAIL_INVALID_IMAGE"
+	ErrorCodeThumbnailInvalidPdf   = "THUMBNAIL_INVALID_PDF"
+
+	ErrorCodeWebhookInvalidErrorUrl         = "WEBHOOK_INVALID_ERROR_URL"
+	ErrorCodeWebhookInvalidEvents           = "WEBHOOK_INVALID_EVENTS"
+	ErrorCodeWebhookInvalidEventsUrl        = "WEBHOOK_INVALID_EVENTS_URL"
+	ErrorCodeWebhookInvalidExtraHttpHeaders = "WEBHOOK_INVALID_EXTRA_HTTP_HEADERS"
+	ErrorCodeWebhookInvalidMethod           = "WEBHOOK_INVALID_METHOD"
+	ErrorCodeWebhookInvalidResultUrl        = "WEBHOOK_INVALID_RESULT_URL"
+	ErrorCodeWebhookResultUrlDisabled       = "WEBHOOK_RESULT_URL_DISABLED"
+
+	ErrorCodeXslfoInvalidDocument  = "XSLFO_INVALID_DOCUMENT"
+	ErrorCodeXslfoInvalidFormFiles = "XSLFO_INVALID_FORM_FILES"
+)
+
 // HttpError is an interface allowing to retrieve the HTTP details of an error.
 type HttpError interface {
 	HttpError() (int, string)
 }
 
+// HttpErrorCoder is an interface allowing to retrieve the error code of an
+// error.
+type HttpErrorCoder interface {
+	HttpErrorCode() string
+}
+
 // SentinelHttpError is the HTTP sidekick of an error.
 type SentinelHttpError struct {
 	status  int
 	message string
+	code    string
 }
 
 // NewSentinelHttpError creates a [SentinelHttpError]. The message will be sent
@@ -33,6 +165,29 @@ func (err SentinelHttpError) HttpError() (int, string) {
 	return err.status, err.message
 }
 
+// WithCode returns a copy of the [SentinelHttpError] with the given error
+// code.
+//
+//	api.NewSentinelHttpError(
+//	  http.StatusBadRequest,
+//	  "Hey, you did something wrong!"
+//	).WithCode("MY_MODULE_SOMETHING_WRONG")
+func (err SentinelHttpError) WithCode(code string) SentinelHttpError {
+	err.code = code
+
+	return err
+}
+
+// HttpErrorCode returns the error code. If no code has been set, it derives
+// one from the status (e.g., "BAD_REQUEST").
+func (err SentinelHttpError) HttpErrorCode() string {
+	if err.code != "" {
+		return err.code
+	}
+
+	return statusErrorCode(err.status)
+}
+
 // sentinelWrappedError contains both the error which will logged and the
 // sidekick [SentinelHttpError].
 type sentinelWrappedError struct {
@@ -48,6 +203,10 @@ func (w sentinelWrappedError) HttpError() (int, string) {
 	return w.sentinel.HttpError()
 }
 
+func (w sentinelWrappedError) HttpErrorCode() string {
+	return w.sentinel.HttpErrorCode()
+}
+
 // WrapError wraps the given error with a [SentinelHttpError]. The wrapped
 // error will be displayed in a log, while the [SentinelHttpError] will be sent
 // in the response.
@@ -68,10 +227,23 @@ func WrapError(err error, sentinel SentinelHttpError) error {
 	}
 }
 
+// statusErrorCode derives an error code from an HTTP status, e.g.,
+// "BAD_REQUEST" for a 400.
+func statusErrorCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" || status == http.StatusInternalServerError {
+		return ErrorCodeInternal
+	}
+
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
+
 // Interface guards.
 var (
-	_ error     = (*SentinelHttpError)(nil)
-	_ HttpError = (*SentinelHttpError)(nil)
-	_ error     = (*sentinelWrappedError)(nil)
-	_ HttpError = (*sentinelWrappedError)(nil)
+	_ error          = (*SentinelHttpError)(nil)
+	_ HttpError      = (*SentinelHttpError)(nil)
+	_ HttpErrorCoder = (*SentinelHttpError)(nil)
+	_ error          = (*sentinelWrappedError)(nil)
+	_ HttpError      = (*sentinelWrappedError)(nil)
+	_ HttpErrorCoder = (*sentinelWrappedError)(nil)
 )
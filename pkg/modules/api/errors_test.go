@@ -50,6 +50,50 @@ func TestSentinelHttpError_HttpError(t *testing.T) {
 	}
 }
 
+func TestSentinelHttpError_WithCode(t *testing.T) {
+	err := NewSentinelHttpError(http.StatusBadRequest, "foo")
+	actual := err.WithCode("FOO")
+
+	if actual.code != "FOO" {
+		t.Errorf("expected '%s' but got '%s'", "FOO", actual.code)
+	}
+
+	if err.code != "" {
+		t.Errorf("expected the original error to be left untouched but got code '%s'", err.code)
+	}
+}
+
+func TestSentinelHttpError_HttpErrorCode(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		err      SentinelHttpError
+		expect   string
+	}{
+		{
+			scenario: "explicit code",
+			err:      NewSentinelHttpError(http.StatusBadRequest, "foo").WithCode("FOO"),
+			expect:   "FOO",
+		},
+		{
+			scenario: "code derived from status",
+			err:      NewSentinelHttpError(http.StatusUnsupportedMediaType, "foo"),
+			expect:   "UNSUPPORTED_MEDIA_TYPE",
+		},
+		{
+			scenario: "unknown status",
+			err:      NewSentinelHttpError(999, "foo"),
+			expect:   ErrorCodeInternal,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := tc.err.HttpErrorCode()
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
 func TestSentinelWrappedError_Is(t *testing.T) {
 	errSentinel := SentinelHttpError{}
 
@@ -86,6 +130,17 @@ func TestSentinelWrappedError_HttpError(t *testing.T) {
 	}
 }
 
+func TestSentinelWrappedError_HttpErrorCode(t *testing.T) {
+	actual := sentinelWrappedError{
+		error:    errors.New("foo"),
+		sentinel: NewSentinelHttpError(http.StatusBadRequest, "foo").WithCode("FOO"),
+	}.HttpErrorCode()
+
+	if actual != "FOO" {
+		t.Errorf("expected '%s' but got '%s'", "FOO", actual)
+	}
+}
+
 func TestWrapError(t *testing.T) {
 	errFoo := errors.New("foo")
 
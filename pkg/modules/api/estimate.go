@@ -0,0 +1,205 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrPageCountUnknown happens if the number of pages of a file cannot be
+// estimated before the conversion.
+var ErrPageCountUnknown = errors.New("page count unknown")
+
+var (
+	// pdfLinearizedRegexp matches the number of pages of the linearization
+	// dictionary, at the beginning of a linearized PDF.
+	pdfLinearizedRegexp = regexp.MustCompile(`/Linearized\s[^>]*?/N\s+(\d+)`)
+
+	// pdfPagesRegexp matches the number of pages of a page tree node.
+	pdfPagesRegexp = regexp.MustCompile(`/Type\s*/Pages\b[^>]*?/Count\s+(\d+)|/Count\s+(\d+)[^>]*?/Type\s*/Pages\b`)
+
+	// ooxmlExtensions are the extensions of the Office Open XML documents,
+	// whose extended properties tell the number of pages or slides.
+	ooxmlExtensions = []string{".docx", ".docm", ".dotx", ".dotm", ".pptx", ".pptm", ".potx", ".ppsx"}
+
+	// odfExtensions are the extensions of the OpenDocument files, whose
+	// metadata tell the number of pages.
+	odfExtensions = []string{".odt", ".ott", ".odp", ".otp", ".odg"}
+)
+
+const (
+	// pdfHeaderSize is the size of the beginning of a PDF which holds the
+	// linearization dictionary, if any.
+	pdfHeaderSize = 1024
+
+	// pdfChunkSize is the size of the chunks read while looking for the page
+	// tree, and pdfChunkOverlap the overlap between two chunks, so that a
+	// dictionary over two chunks still matches.
+	pdfChunkSize    = 1 << 20
+	pdfChunkOverlap = 1024
+)
+
+// EstimatePageCount returns the number of pages of a file without converting
+// it: it reads the page tree of a PDF, or the metadata an office application
+// writes in an OOXML or ODF document. It returns [ErrPageCountUnknown] if the
+// file does not tell, e.g., a spreadsheet or a PDF with compressed objects.
+func EstimatePageCount(path string) (int, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+
+	switch {
+	case ext == ".pdf":
+		return estimatePdfPageCount(path)
+	case slices.Contains(ooxmlExtensions, ext):
+		return estimateOoxmlPageCount(path)
+	case slices.Contains(odfExtensions, ext):
+		return estimateOdfPageCount(path)
+	default:
+		return 0, ErrPageCountUnknown
+	}
+}
+
+// estimatePdfPageCount reads the number of pages of the linearization
+// dictionary or, if the PDF is not linearized, the highest count of its page
+// tree nodes, i.e., the count of the root node.
+func estimatePdfPageCount(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	buf := make([]byte, pdfChunkSize+pdfChunkOverlap)
+
+	n, err := io.ReadFull(f, buf[:pdfHeaderSize])
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("read file: %w", err)
+	}
+
+	if !bytes.HasPrefix(buf[:n], []byte("%PDF-")) {
+		return 0, fmt.Errorf("not a PDF: %w", ErrPageCountUnknown)
+	}
+
+	match := pdfLinearizedRegexp.FindSubmatch(buf[:n])
+	if match != nil {
+		return strconv.Atoi(string(match[1]))
+	}
+
+	count := 0
+	size := n
+
+	for {
+		read, err := io.ReadFull(f, buf[size:])
+		size += read
+
+		for _, match := range pdfPagesRegexp.FindAllSubmatch(buf[:size], -1) {
+			value := match[1]
+			if len(value) == 0 {
+				value = match[2]
+			}
+
+			c, convErr := strconv.Atoi(string(value))
+			if convErr == nil && c > count {
+				count = c
+			}
+		}
+
+		if err != nil {
+			if !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+				return 0, fmt.Errorf("read file: %w", err)
+			}
+
+			break
+		}
+
+		// Keep the end of the chunk, in case a dictionary spans two chunks.
+		size = copy(buf, buf[size-pdfChunkOverlap:size])
+	}
+
+	if count == 0 {
+		return 0, fmt.Errorf("no page tree: %w", ErrPageCountUnknown)
+	}
+
+	return count, nil
+}
+
+// estimateOoxmlPageCount reads the number of pages or slides of the extended
+// properties of an OOXML document.
+func estimateOoxmlPageCount(path string) (int, error) {
+	var properties struct {
+		Pages  int `xml:"Pages"`
+		Slides int `xml:"Slides"`
+	}
+
+	err := readZipXml(path, "docProps/app.xml", &properties)
+	if err != nil {
+		return 0, err
+	}
+
+	count := max(properties.Pages, properties.Slides)
+	if count == 0 {
+		return 0, fmt.Errorf("no pages in extended properties: %w", ErrPageCountUnknown)
+	}
+
+	return count, nil
+}
+
+// estimateOdfPageCount reads the number of pages of the metadata of an ODF
+// document.
+func estimateOdfPageCount(path string) (int, error) {
+	var meta struct {
+		Statistic struct {
+			PageCount int `xml:"page-count,attr"`
+		} `xml:"meta>document-statistic"`
+	}
+
+	err := readZipXml(path, "meta.xml", &meta)
+	if err != nil {
+		return 0, err
+	}
+
+	if meta.Statistic.PageCount == 0 {
+		return 0, fmt.Errorf("no pages in metadata: %w", ErrPageCountUnknown)
+	}
+
+	return meta.Statistic.PageCount, nil
+}
+
+// readZipXml decodes an XML entry of a ZIP archive.
+func readZipXml(path, name string, v any) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	entry, err := r.Open(name)
+	if err != nil {
+		return fmt.Errorf("open '%s': %w", name, ErrPageCountUnknown)
+	}
+
+	defer func() {
+		_ = entry.Close()
+	}()
+
+	err = xml.NewDecoder(entry).Decode(v)
+	if err != nil {
+		return fmt.Errorf("decode '%s': %w", name, err)
+	}
+
+	return nil
+}
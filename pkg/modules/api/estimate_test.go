@@ -0,0 +1,157 @@
+package api
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEstimatePageCount(t *testing.T) {
+	dirPath := t.TempDir()
+
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dirPath, name)
+
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return path
+	}
+
+	writeZip := func(name string, entries map[string]string) string {
+		path := filepath.Join(dirPath, name)
+
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		w := zip.NewWriter(f)
+		for entryName, content := range entries {
+			entry, err := w.Create(entryName)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			_, err = entry.Write([]byte(content))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
+		err = w.Close()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return path
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		path          string
+		expectCount   int
+		expectUnknown bool
+		expectError   bool
+	}{
+		{
+			scenario:      "unsupported extension",
+			path:          writeFile("foo.xlsx", "foo"),
+			expectError:   true,
+			expectUnknown: true,
+		},
+		{
+			scenario:    "non-existing PDF",
+			path:        filepath.Join(dirPath, "bar.pdf"),
+			expectError: true,
+		},
+		{
+			scenario:      "not a PDF",
+			path:          writeFile("not.pdf", "foo"),
+			expectError:   true,
+			expectUnknown: true,
+		},
+		{
+			scenario:    "linearized PDF",
+			path:        writeFile("linearized.pdf", "%PDF-1.7\n1 0 obj\n<< /Linearized 1 /L 1234 /H [ 10 20 ] /O 4 /E 100 /N 42 /T 1000 >>\nendobj\n"),
+			expectCount: 42,
+		},
+		{
+			scenario:    "PDF page tree",
+			path:        writeFile("tree.pdf", "%PDF-1.4\n2 0 obj\n<< /Type /Pages /Kids [ 3 0 R 4 0 R ] /Count 2 /Parent 1 0 R >>\nendobj\n1 0 obj\n<< /Count 7 /Kids [ 2 0 R ] /Type /Pages >>\nendobj\n5 0 obj\n<< /Type /Page /Parent 2 0 R >>\nendobj\n"),
+			expectCount: 7,
+		},
+		{
+			scenario:    "PDF page tree beyond the first chunk",
+			path:        writeFile("large.pdf", "%PDF-1.4\n"+strings.Repeat(" ", pdfChunkSize)+"1 0 obj\n<< /Type /Pages /Count 12 >>\nendobj\n"),
+			expectCount: 12,
+		},
+		{
+			scenario:      "PDF with compressed objects",
+			path:          writeFile("compressed.pdf", "%PDF-1.5\n1 0 obj\n<< /Type /ObjStm /N 3 /First 10 /Length 100 >>\nstream\nfoo\nendstream\nendobj\n"),
+			expectError:   true,
+			expectUnknown: true,
+		},
+		{
+			scenario:    "DOCX",
+			path:        writeZip("foo.docx", map[string]string{"docProps/app.xml": `<?xml version="1.0"?><Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Pages>3</Pages><Words>100</Words></Properties>`}),
+			expectCount: 3,
+		},
+		{
+			scenario:    "PPTX",
+			path:        writeZip("foo.pptx", map[string]string{"docProps/app.xml": `<?xml version="1.0"?><Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Slides>12</Slides></Properties>`}),
+			expectCount: 12,
+		},
+		{
+			scenario:      "DOCX without extended properties",
+			path:          writeZip("bar.docx", map[string]string{"word/document.xml": "<document/>"}),
+			expectError:   true,
+			expectUnknown: true,
+		},
+		{
+			scenario:    "invalid DOCX",
+			path:        writeFile("baz.docx", "foo"),
+			expectError: true,
+		},
+		{
+			scenario:    "ODT",
+			path:        writeZip("foo.odt", map[string]string{"meta.xml": `<?xml version="1.0"?><office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:meta="urn:oasis:names:tc:opendocument:xmlns:meta:1.0"><office:meta><meta:document-statistic meta:page-count="5" meta:word-count="100"/></office:meta></office:document-meta>`}),
+			expectCount: 5,
+		},
+		{
+			scenario:      "ODT without statistics",
+			path:          writeZip("bar.odt", map[string]string{"meta.xml": `<?xml version="1.0"?><office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"><office:meta/></office:document-meta>`}),
+			expectError:   true,
+			expectUnknown: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			count, err := EstimatePageCount(tc.path)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if errors.Is(err, ErrPageCountUnknown) != tc.expectUnknown {
+				t.Errorf("expected unknown page count %t but got: %v", tc.expectUnknown, err)
+			}
+
+			if count != tc.expectCount {
+				t.Errorf("expected %d but got %d", tc.expectCount, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The extensions of this build to the stock routes. They may be disabled, for
+// all routes or per route, so that the routes behave like the upstream ones
+// and this build is a drop-in replacement for their clients. A disabled
+// extension is ignored, e.g., its form field is as any unknown form field.
+const (
+	// ExtensionValidateOnly is the "validateOnly" form field.
+	ExtensionValidateOnly = "validateOnly"
+
+	// ExtensionProcessTimeout is the "processTimeout" form field.
+	ExtensionProcessTimeout = "processTimeout"
+
+	// ExtensionJsonResponse is the JSON response for the clients sending the
+	// "Accept: application/json" header.
+	ExtensionJsonResponse = "jsonResponse"
+
+	// ExtensionKeepalive is the "102 Processing" responses or the event
+	// stream for the clients asking for them.
+	ExtensionKeepalive = "keepalive"
+
+	// ExtensionProfile is the "profile" form field.
+	ExtensionProfile = "profile"
+
+	// ExtensionDisposition is the "disposition" and "contentType" form
+	// fields.
+	ExtensionDisposition = "disposition"
+
+	// ExtensionOutputMetadata is the metadata response headers and the
+	// metadata.json file in archives.
+	ExtensionOutputMetadata = "outputMetadata"
+
+	// ExtensionTemplate is the "templateId" form field.
+	ExtensionTemplate = "template"
+
+	// ExtensionAssets is the "assets" form field.
+	ExtensionAssets = "assets"
+
+	// ExtensionAsync is the "async" form field and the "Gotenberg-Async"
+	// header.
+	ExtensionAsync = "async"
+
+	// ExtensionJsonErrors is the JSON body of the error responses, with a
+	// stable error code, instead of a plain text message.
+	ExtensionJsonErrors = "jsonErrors"
+
+	// ExtensionValidationDetails is the "errors" and "unknownFields" of the
+	// JSON body of the 400 responses to an invalid form data.
+	ExtensionValidationDetails = "validationDetails"
+
+	// ExtensionFileTypeMismatch is the check of the uploaded files whose
+	// content does not match their extension, as configured with the
+	// "api-file-type-mismatch" flag.
+	ExtensionFileTypeMismatch = "fileTypeMismatch"
+
+	// ExtensionRemoveBlankPages is the "removeBlankPages" and
+	// "blankPageThreshold" form fields of the LibreOffice and PDF engines
+	// routes.
+	ExtensionRemoveBlankPages = "removeBlankPages"
+
+	// ExtensionSplitSheets is the "splitSheets" form field of the LibreOffice
+	// route.
+	ExtensionSplitSheets = "splitSheets"
+
+	// ExtensionTextDirection is the "textDirection" form field of the
+	// LibreOffice route.
+	ExtensionTextDirection = "textDirection"
+
+	// ExtensionDocumentLocale is the "documentLocale" form field of the
+	// LibreOffice route.
+	ExtensionDocumentLocale = "documentLocale"
+
+	// ExtensionExportLinks is the "exportLinks" form field of the LibreOffice
+	// route.
+	ExtensionExportLinks = "exportLinks"
+
+	// ExtensionAttachSource is the "attachSource" form field of the LibreOffice
+	// route.
+	ExtensionAttachSource = "attachSource"
+
+	// ExtensionExtraInputFormats is the input formats the LibreOffice route
+	// accepts in addition to the upstream ones, e.g., WordPerfect documents.
+	ExtensionExtraInputFormats = "extraInputFormats"
+
+	// ExtensionAsImages is the "asImages" and "slideImageFormat" form fields of
+	// the LibreOffice route.
+	ExtensionAsImages = "asImages"
+
+	// ExtensionPdfVersion is the "pdfVersion" form field of the LibreOffice and
+	// PDF engines routes.
+	ExtensionPdfVersion = "pdfVersion"
+
+	// ExtensionPageLabels is the "pageLabels" form field of the PDF engines
+	// merge route, and the page labels the merged PDF keeps.
+	ExtensionPageLabels = "pageLabels"
+
+	// ExtensionBookmarks is the "preserveBookmarks" and "bookmarkTitles" form
+	// fields of the PDF engines merge route.
+	ExtensionBookmarks = "bookmarks"
+
+	// ExtensionRemoveDuplicatePages is the "removeDuplicatePages" form field of
+	// the PDF engines merge route.
+	ExtensionRemoveDuplicatePages = "removeDuplicatePages"
+
+	// ExtensionMaxOutputBytes is the "maxOutputBytes" form field of the
+	// Chromium, LibreOffice and PDF engines routes.
+	ExtensionMaxOutputBytes = "maxOutputBytes"
+)
+
+// knownExtensions are the extensions which may be disabled.
+var knownExtensions = map[string]bool{
+	ExtensionValidateOnly:   true,
+	ExtensionProcessTimeout: true,
+	ExtensionJsonResponse:   true,
+	ExtensionKeepalive:      true,
+	ExtensionProfile:        true,
+	ExtensionDisposition:    true,
+	ExtensionOutputMetadata: true,
+	ExtensionTemplate:       true,
+	ExtensionAssets:         true,
+	ExtensionAsync:          true,
+	ExtensionJsonErrors:     true,
+
+	ExtensionValidationDetails: true,
+	ExtensionFileTypeMismatch:  true,
+
+	ExtensionRemoveBlankPages:     true,
+	ExtensionSplitSheets:          true,
+	ExtensionTextDirection:        true,
+	ExtensionDocumentLocale:       true,
+	ExtensionExportLinks:          true,
+	ExtensionAttachSource:         true,
+	ExtensionExtraInputFormats:    true,
+	ExtensionAsImages:             true,
+	ExtensionPdfVersion:           true,
+	ExtensionPageLabels:           true,
+	ExtensionBookmarks:            true,
+	ExtensionRemoveDuplicatePages: true,
+	ExtensionMaxOutputBytes:       true,
+}
+
+// parseDisabledExtensions parses the "extension" entries, which disable an
+// extension for all routes, and the "path=extension" entries, which disable
+// an extension for the routes starting with the given path. The extension must
+// be one of the Extension constants. Modules check their own extensions thanks
+// to [Context.ExtensionEnabled].
+func parseDisabledExtensions(entries []string) (map[string][]string, error) {
+	extensions := make(map[string][]string, len(entries))
+
+	for _, entry := range entries {
+		path, extension, ok := strings.Cut(entry, "=")
+		if !ok {
+			path, extension = "", path
+		}
+
+		path = strings.TrimSpace(path)
+		extension = strings.TrimSpace(extension)
+
+		if extension == "" || (ok && !strings.HasPrefix(path, "/")) {
+			return nil, fmt.Errorf("invalid disabled extension '%s': expected 'extension' or '/path=extension'", entry)
+		}
+
+		if !knownExtensions[extension] {
+			return nil, fmt.Errorf("invalid disabled extension '%s': unknown extension '%s'", entry, extension)
+		}
+
+		extensions[path] = append(extensions[path], extension)
+	}
+
+	return extensions, nil
+}
+
+// routeDisabledExtensions returns the extensions disabled for a route.
+func (a *Api) routeDisabledExtensions(path string) map[string]bool {
+	return disabledExtensionsOf(a.disabledExtensions, path)
+}
+
+// disabledExtensionsOf returns the extensions disabled for a path, given the
+// parsed "api-disable-extensions" entries.
+func disabledExtensionsOf(entries map[string][]string, path string) map[string]bool {
+	disabled := make(map[string]bool)
+
+	for prefix, extensions := range entries {
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+
+		for _, extension := range extensions {
+			disabled[extension] = true
+		}
+	}
+
+	return disabled
+}
+
+// isApiField tells if a form field is handled by the API for all routes,
+// instead of by the route.
+func isApiField(key string) bool {
+	switch key {
+	case validateOnlyField, processTimeoutField, profileField, dispositionField, contentTypeField, templateIdField, assetsField, asyncField:
+		return true
+	default:
+		return false
+	}
+}
+
+// ExtensionEnabled tells if an extension is enabled for the current route.
+// Routes check it before handling the form fields of their own extensions.
+//
+//	if ctx.ExtensionEnabled(api.ExtensionRemoveBlankPages) {
+//		form.Bool("removeBlankPages", &removeBlankPages, false)
+//	}
+func (ctx *Context) ExtensionEnabled(extension string) bool {
+	return !ctx.disabledExtensions[extension]
+}
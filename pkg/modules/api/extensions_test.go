@@ -0,0 +1,114 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDisabledExtensions(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		entries          []string
+		expectExtensions map[string][]string
+		expectError      bool
+	}{
+		{
+			scenario:         "no entries",
+			entries:          nil,
+			expectExtensions: map[string][]string{},
+		},
+		{
+			scenario:    "empty extension",
+			entries:     []string{"/forms/chromium="},
+			expectError: true,
+		},
+		{
+			scenario:    "relative path",
+			entries:     []string{"forms/chromium=jsonResponse"},
+			expectError: true,
+		},
+		{
+			scenario:    "unknown extension",
+			entries:     []string{"/forms/libreoffice=foo"},
+			expectError: true,
+		},
+		{
+			scenario: "valid entries",
+			entries:  []string{"validateOnly", "/forms/chromium=jsonResponse", " /forms/chromium = keepalive "},
+			expectExtensions: map[string][]string{
+				"":                {ExtensionValidateOnly},
+				"/forms/chromium": {ExtensionJsonResponse, ExtensionKeepalive},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			extensions, err := parseDisabledExtensions(tc.entries)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && !reflect.DeepEqual(extensions, tc.expectExtensions) {
+				t.Errorf("expected %v but got %v", tc.expectExtensions, extensions)
+			}
+		})
+	}
+}
+
+func TestApi_routeDisabledExtensions(t *testing.T) {
+	mod := &Api{
+		disabledExtensions: map[string][]string{
+			"":                           {ExtensionValidateOnly},
+			"/forms/chromium":            {ExtensionJsonResponse},
+			"/forms/libreoffice/convert": {"asImages"},
+		},
+	}
+
+	for _, tc := range []struct {
+		path   string
+		expect map[string]bool
+	}{
+		{
+			path:   "/forms/chromium/convert/url",
+			expect: map[string]bool{ExtensionValidateOnly: true, ExtensionJsonResponse: true},
+		},
+		{
+			path:   "/forms/libreoffice/convert",
+			expect: map[string]bool{ExtensionValidateOnly: true, "asImages": true},
+		},
+		{
+			path:   "/forms/pdfengines/merge",
+			expect: map[string]bool{ExtensionValidateOnly: true},
+		},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			actual := mod.routeDisabledExtensions(tc.path)
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %v but got %v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestContext_ExtensionEnabled(t *testing.T) {
+	ctx := &Context{
+		disabledExtensions: map[string]bool{"asImages": true},
+	}
+
+	if ctx.ExtensionEnabled("asImages") {
+		t.Error("expected extension 'asImages' to be disabled")
+	}
+
+	if !ctx.ExtensionEnabled(ExtensionValidateOnly) {
+		t.Errorf("expected extension '%s' to be enabled", ExtensionValidateOnly)
+	}
+
+	if !new(Context).ExtensionEnabled(ExtensionValidateOnly) {
+		t.Errorf("expected extension '%s' to be enabled without disabled extensions", ExtensionValidateOnly)
+	}
+}
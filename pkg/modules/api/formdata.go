@@ -23,26 +23,43 @@ type FormData struct {
 	values map[string][]string
 	files  map[string]string
 	errors error
+
+	// ctx, if set, gathers the fields and files bound by the route for the
+	// pre-flight report.
+	ctx *Context
 }
 
-// Validate returns nil or an error related to the [FormData] values, with a
-// [SentinelHttpError] (status code 400, errors' details as message) wrapped
-// inside.
+// Validate returns nil or a [ValidationError] (status code 400, errors'
+// details as message) with all the problems of the [FormData] values. If the
+// [ExtensionValidationDetails] is disabled, the error has no details beyond
+// its message.
 //
 //	var foo string
 //
 //	err := ctx.FormData().
 //	   MandatoryString("foo", &foo, "bar").
 //	   Validate()
+//
+// If the request is a pre-flight one (see [Context.ValidateOnly]), it returns
+// [ErrValidateOnly] instead of nil, so that the route stops before the
+// conversion.
 func (form *FormData) Validate() error {
 	if form.errors == nil {
+		if form.ctx != nil && form.ctx.validateOnly {
+			return ErrValidateOnly
+		}
+
 		return nil
 	}
 
-	return WrapError(
-		form.errors,
-		NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", form.errors)),
-	)
+	if form.ctx != nil && !form.ctx.ExtensionEnabled(ExtensionValidationDetails) {
+		return WrapError(
+			form.errors,
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", form.errors)).WithCode(ErrorCodeInvalidFormData),
+		)
+	}
+
+	return newValidationError(form.errors)
 }
 
 // String binds a form field to a string variable.
@@ -169,9 +186,11 @@ func (form *FormData) Custom(key string, assign func(value string) error) *FormD
 
 	err := assign(value)
 	if err != nil {
-		form.append(
-			fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", key, value, err),
-		)
+		form.append(FieldError{
+			Field:  key,
+			Reason: fmt.Sprintf("invalid value '%s': %s", value, err),
+			err:    fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", key, value, err),
+		})
 	}
 
 	return form
@@ -200,9 +219,11 @@ func (form *FormData) MandatoryCustom(key string, assign func(value string) erro
 
 	err := assign(value)
 	if err != nil {
-		form.append(
-			fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", key, value, err),
-		)
+		form.append(FieldError{
+			Field:  key,
+			Reason: fmt.Sprintf("invalid value '%s': %s", value, err),
+			err:    fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", key, value, err),
+		})
 	}
 
 	return form
@@ -286,9 +307,11 @@ func (form *FormData) MandatoryPaths(extensions []string, target *[]string) *For
 		return form
 	}
 
-	form.append(
-		fmt.Errorf("no form file found for extensions: %v", extensions),
-	)
+	form.append(FieldError{
+		Reason:   fmt.Sprintf("no form file found for extensions: %v", extensions),
+		Expected: "file",
+		err:      fmt.Errorf("no form file found for extensions: %v", extensions),
+	})
 
 	return form
 }
@@ -301,6 +324,7 @@ func (form *FormData) paths(extensions []string, target *[]string) *FormData {
 			// See https://github.com/gotenberg/gotenberg/issues/228.
 			if strings.ToLower(filepath.Ext(filename)) == ext {
 				*target = append(*target, path)
+				form.bindFile(filename)
 			}
 		}
 	}
@@ -320,6 +344,7 @@ func (form *FormData) append(err error) {
 // empty or the "key" does not exist, it binds the default value. Currently,
 // only the string, bool, int, float64 and time.Duration types are bindable.
 func (form *FormData) mustValue(key string, target interface{}, defaultValue interface{}) *FormData {
+	form.bindField(key)
 	val, ok := form.values[key]
 
 	if !ok || val[0] == "" {
@@ -349,12 +374,16 @@ func (form *FormData) mustValue(key string, target interface{}, defaultValue int
 // Currently, only the string, bool, int, float64 and time.Duration types are
 // bindable.
 func (form *FormData) mustMandatoryField(key string, target interface{}) *FormData {
+	form.bindField(key)
 	val, ok := form.values[key]
 
 	if !ok || val[0] == "" {
-		form.append(
-			fmt.Errorf("form field '%s' is required", key),
-		)
+		form.append(FieldError{
+			Field:    key,
+			Reason:   "required",
+			Expected: expectedType(target),
+			err:      fmt.Errorf("form field '%s' is required", key),
+		})
 
 		return form
 	}
@@ -386,9 +415,12 @@ func (form *FormData) mustAssign(key, value string, target interface{}) *FormDat
 	}
 
 	if err != nil {
-		form.append(
-			fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", key, value, err),
-		)
+		form.append(FieldError{
+			Field:    key,
+			Reason:   fmt.Sprintf("invalid value '%s'", value),
+			Expected: expectedType(target),
+			err:      fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", key, value, err),
+		})
 	}
 
 	return form
@@ -401,6 +433,7 @@ func (form *FormData) path(filename string, target *string) *FormData {
 		nameLowerExt := strings.TrimSuffix(name, filepath.Ext(name)) + strings.ToLower(filepath.Ext(name))
 		if name == filename || nameLowerExt == filename {
 			*target = path
+			form.bindFile(name)
 			return form
 		}
 	}
@@ -417,9 +450,12 @@ func (form *FormData) mandatoryPath(filename string, target *string) *FormData {
 		return form
 	}
 
-	form.append(
-		fmt.Errorf("form file '%s' is required", filename),
-	)
+	form.append(FieldError{
+		File:     filename,
+		Reason:   "required",
+		Expected: "file",
+		err:      fmt.Errorf("form file '%s' is required", filename),
+	})
 
 	return form
 }
@@ -429,9 +465,11 @@ func (form *FormData) mandatoryPath(filename string, target *string) *FormData {
 func (form *FormData) readFile(path, filename string, target *string) *FormData {
 	b, err := os.ReadFile(path)
 	if err != nil {
-		form.append(
-			fmt.Errorf("form file '%s' is invalid (%w)", filename, err),
-		)
+		form.append(FieldError{
+			File:   filename,
+			Reason: "unreadable",
+			err:    fmt.Errorf("form file '%s' is invalid (%w)", filename, err),
+		})
 
 		return form
 	}
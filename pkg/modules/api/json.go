@@ -0,0 +1,144 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// JsonResponse is the body of a response when the client asks for a JSON
+// response, i.e., with the "Accept: application/json" header and no other
+// media type as preferred. The output files are base64 encoded.
+type JsonResponse struct {
+	Files    []JsonResponseFile `json:"files"`
+	Metadata *Metadata          `json:"metadata,omitempty"`
+}
+
+// JsonResponseFile is an output file within a [JsonResponse].
+type JsonResponseFile struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	Sha256      string `json:"sha256"`
+	Data        string `json:"data"`
+}
+
+// acceptsJson tells if the client asks for a JSON response, i.e., if
+// "application/json" is the single media type of the Accept header with the
+// highest preference. Defaults such as "application/json, text/plain, */*"
+// keep the binary response.
+func acceptsJson(c echo.Context) bool {
+	jsonQuality := 0.0
+	otherQuality := 0.0
+
+	for _, accept := range c.Request().Header.Values(echo.HeaderAccept) {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				quality, err = strconv.ParseFloat(q, 64)
+				if err != nil {
+					continue
+				}
+			}
+
+			if mediaType == echo.MIMEApplicationJSON {
+				jsonQuality = max(jsonQuality, quality)
+				continue
+			}
+
+			otherQuality = max(otherQuality, quality)
+		}
+	}
+
+	return jsonQuality > 0 && jsonQuality > otherQuality
+}
+
+// JsonResponse returns the [JsonResponse] of the registered output paths.
+// Contrary to [Context.BuildOutputFile], many output paths are not archived.
+func (ctx *Context) JsonResponse() (JsonResponse, error) {
+	if ctx.cancelled {
+		return JsonResponse{}, ErrContextAlreadyClosed
+	}
+
+	if len(ctx.outputPaths) == 0 {
+		return JsonResponse{}, errors.New("no output path")
+	}
+
+	var totalSize int64
+	for _, outputPath := range ctx.outputPaths {
+		stat, err := os.Stat(outputPath)
+		if err != nil {
+			return JsonResponse{}, fmt.Errorf("get stat from output file: %w", err)
+		}
+
+		totalSize += stat.Size()
+	}
+
+	if ctx.jsonResponseMaxSize > 0 && totalSize > ctx.jsonResponseMaxSize {
+		return JsonResponse{}, WrapError(
+			fmt.Errorf("output files size %d exceeds the JSON response maximum size %d", totalSize, ctx.jsonResponseMaxSize),
+			NewSentinelHttpError(
+				http.StatusNotAcceptable,
+				"The output files are too large for a JSON response; remove the 'Accept: application/json' header to get them as a binary response",
+			).WithCode(ErrorCodeJsonResponseTooLarge),
+		)
+	}
+
+	response := JsonResponse{
+		Files: make([]JsonResponseFile, len(ctx.outputPaths)),
+	}
+
+	for i, outputPath := range ctx.outputPaths {
+		b, err := os.ReadFile(outputPath)
+		if err != nil {
+			return JsonResponse{}, fmt.Errorf("read output file: %w", err)
+		}
+
+		filename := filepath.Base(outputPath)
+		if len(ctx.outputPaths) == 1 {
+			filename = ctx.OutputFilename(outputPath)
+		}
+
+		contentType := ctx.OutputContentType()
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(outputPath))
+		}
+
+		if contentType == "" {
+			contentType = http.DetectContentType(b)
+		}
+
+		response.Files[i] = JsonResponseFile{
+			Filename:    filename,
+			ContentType: contentType,
+			Size:        int64(len(b)),
+			Sha256:      fmt.Sprintf("%x", sha256.Sum256(b)),
+			Data:        base64.StdEncoding.EncodeToString(b),
+		}
+	}
+
+	if ctx.outputMetadata {
+		metadata, err := ctx.Metadata()
+		if err != nil {
+			return JsonResponse{}, fmt.Errorf("get output metadata: %w", err)
+		}
+
+		response.Metadata = &metadata
+	}
+
+	return response, nil
+}
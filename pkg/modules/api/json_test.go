@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func TestAcceptsJson(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		accept   string
+		expect   bool
+	}{
+		{
+			scenario: "no Accept header",
+			expect:   false,
+		},
+		{
+			scenario: "any media type",
+			accept:   "*/*",
+			expect:   false,
+		},
+		{
+			scenario: "PDF media type",
+			accept:   "application/pdf",
+			expect:   false,
+		},
+		{
+			scenario: "JSON media type",
+			accept:   "application/json",
+			expect:   true,
+		},
+		{
+			scenario: "JSON media type preferred over many media types",
+			accept:   "application/json, */*;q=0.8",
+			expect:   true,
+		},
+		{
+			scenario: "JSON media type less preferred than another media type",
+			accept:   "text/html, application/json;q=0.9, */*;q=0.8",
+			expect:   false,
+		},
+		{
+			scenario: "JSON media type as preferred as other media types",
+			accept:   "application/json, text/plain, */*",
+			expect:   false,
+		},
+		{
+			scenario: "JSON media type refused",
+			accept:   "application/json;q=0",
+			expect:   false,
+		},
+		{
+			scenario: "JSON media type alone with a lower preference",
+			accept:   "application/json;q=0.5",
+			expect:   true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.accept != "" {
+				req.Header.Set(echo.HeaderAccept, tc.accept)
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			actual := acceptsJson(c)
+			if actual != tc.expect {
+				t.Errorf("expected %t but got %t", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestContext_JsonResponse(t *testing.T) {
+	dirPath := t.TempDir()
+
+	for filename, content := range map[string]string{
+		"foo.pdf": "foo",
+		"bar.txt": "bar",
+	} {
+		err := os.WriteFile(dirPath+"/"+filename, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario         string
+		ctx              *Context
+		outputFilename   string
+		expectFiles      []JsonResponseFile
+		expectMetadata   bool
+		expectError      bool
+		expectHttpError  bool
+		expectHttpStatus int
+	}{
+		{
+			scenario:    "ErrContextAlreadyClosed",
+			ctx:         &Context{cancelled: true},
+			expectError: true,
+		},
+		{
+			scenario:    "no output path",
+			ctx:         &Context{},
+			expectError: true,
+		},
+		{
+			scenario: "non-existing output file",
+			ctx: &Context{
+				outputPaths: []string{dirPath + "/baz.pdf"},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "maximum size exceeded",
+			ctx: &Context{
+				outputPaths:         []string{dirPath + "/foo.pdf", dirPath + "/bar.txt"},
+				jsonResponseMaxSize: 5,
+			},
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusNotAcceptable,
+		},
+		{
+			scenario: "single output file with custom filename",
+			ctx: &Context{
+				outputPaths: []string{dirPath + "/foo.pdf"},
+			},
+			outputFilename: "qux",
+			expectFiles: []JsonResponseFile{
+				{
+					Filename:    "qux.pdf",
+					ContentType: "application/pdf",
+					Size:        3,
+					Sha256:      "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+					Data:        base64.StdEncoding.EncodeToString([]byte("foo")),
+				},
+			},
+		},
+		{
+			scenario: "many output files with metadata",
+			ctx: &Context{
+				outputPaths:         []string{dirPath + "/foo.pdf", dirPath + "/bar.txt"},
+				outputMetadata:      true,
+				jsonResponseMaxSize: 6,
+			},
+			expectFiles: []JsonResponseFile{
+				{
+					Filename:    "foo.pdf",
+					ContentType: "application/pdf",
+					Size:        3,
+					Sha256:      "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+					Data:        base64.StdEncoding.EncodeToString([]byte("foo")),
+				},
+				{
+					Filename:    "bar.txt",
+					ContentType: "text/plain; charset=utf-8",
+					Size:        3,
+					Sha256:      "fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9",
+					Data:        base64.StdEncoding.EncodeToString([]byte("bar")),
+				},
+			},
+			expectMetadata: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.outputFilename != "" {
+				req.Header.Set("Gotenberg-Output-Filename", tc.outputFilename)
+			}
+
+			tc.ctx.dirPath = dirPath
+			tc.ctx.logger = zap.NewNop()
+			tc.ctx.echoCtx = echo.New().NewContext(req, httptest.NewRecorder())
+
+			response, err := tc.ctx.JsonResponse()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectHttpError {
+				var httpErr HttpError
+				isHttpErr := errors.As(err, &httpErr)
+				if !isHttpErr {
+					t.Fatalf("expected an HTTP error but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if !reflect.DeepEqual(response.Files, tc.expectFiles) {
+				t.Errorf("expected %+v but got %+v", tc.expectFiles, response.Files)
+			}
+
+			if tc.expectMetadata && response.Metadata == nil {
+				t.Error("expected metadata but got none")
+			}
+
+			if !tc.expectMetadata && response.Metadata != nil {
+				t.Errorf("expected no metadata but got %+v", response.Metadata)
+			}
+		})
+	}
+}
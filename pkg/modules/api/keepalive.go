@@ -0,0 +1,178 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// keepaliveHeader is the header a client sets to "true" for receiving
+// informational "102 Processing" responses while a synchronous request is
+// processing.
+const keepaliveHeader = "Gotenberg-Keepalive"
+
+// mimeTextEventStream is the media type of an event stream.
+const mimeTextEventStream = "text/event-stream"
+
+// keepaliveMode tells how to keep alive the connection of a synchronous
+// request.
+type keepaliveMode int
+
+const (
+	// keepaliveNone does not keep alive the connection.
+	keepaliveNone keepaliveMode = iota
+
+	// keepaliveProcessing sends "102 Processing" informational responses.
+	// The final response is unchanged.
+	keepaliveProcessing
+
+	// keepaliveEventStream switches to an event stream with "progress"
+	// events. The final response is either a "result" event, with the body
+	// a JSON client would have received, or an "error" event, with an
+	// [ErrorResponse].
+	keepaliveEventStream
+)
+
+// ProgressEvent is the data of a "progress" event.
+type ProgressEvent struct {
+	Elapsed string `json:"elapsed"`
+}
+
+// keepaliveModeOf returns the [keepaliveMode] a client asks for.
+func keepaliveModeOf(c echo.Context) keepaliveMode {
+	for _, accept := range c.Request().Header.Values(echo.HeaderAccept) {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+			if err != nil {
+				continue
+			}
+
+			if mediaType == mimeTextEventStream {
+				return keepaliveEventStream
+			}
+		}
+	}
+
+	keepalive, err := strconv.ParseBool(c.Request().Header.Get(keepaliveHeader))
+	if err == nil && keepalive {
+		return keepaliveProcessing
+	}
+
+	return keepaliveNone
+}
+
+// awaitWithKeepalive calls the given function and, while it runs, keeps alive
+// the connection at the given interval. Requests that complete within the
+// first interval are not affected. It returns true if it has started an event
+// stream, in which case the caller must send the result as a final event.
+func awaitWithKeepalive(c echo.Context, mode keepaliveMode, interval time.Duration, fn func() error) (bool, error) {
+	if mode == keepaliveNone || interval <= 0 {
+		return false, fn()
+	}
+
+	startTime := time.Now()
+	errChan := make(chan error, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				errChan <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		errChan <- fn()
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	streaming := false
+
+	for {
+		select {
+		case err := <-errChan:
+			return streaming, err
+		case <-ticker.C:
+			if mode == keepaliveProcessing {
+				// Bypass the echo response, which would otherwise consider
+				// itself as committed.
+				c.Response().Writer.WriteHeader(http.StatusProcessing)
+
+				continue
+			}
+
+			if !streaming {
+				c.Response().Header().Set(echo.HeaderContentType, mimeTextEventStream)
+				c.Response().Header().Set("Cache-Control", "no-cache")
+				c.Response().WriteHeader(http.StatusOK)
+				streaming = true
+			}
+
+			// A failure means the client has disconnected, which cancels the
+			// process anyway.
+			_ = writeEvent(c, "progress", ProgressEvent{
+				Elapsed: time.Since(startTime).Round(time.Second).String(),
+			})
+		}
+	}
+}
+
+// writeEvent sends an event with its data as JSON.
+func writeEvent(c echo.Context, event string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal event data: %w", err)
+	}
+
+	_, err = fmt.Fprintf(c.Response(), "event: %s\ndata: %s\n\n", event, b)
+	if err != nil {
+		return fmt.Errorf("write event: %w", err)
+	}
+
+	c.Response().Flush()
+
+	return nil
+}
+
+// streamResult sends the result of a request as the final event of an event
+// stream. It returns the processing error, if any, so that it is still
+// logged and reported.
+func streamResult(c echo.Context, ctx *Context, err error) error {
+	var data any
+
+	switch {
+	case errors.Is(err, ErrValidateOnly):
+		data, err = ctx.PreflightReport()
+		if err != nil {
+			err = fmt.Errorf("build pre-flight report: %w", err)
+		}
+	case err == nil:
+		data, err = ctx.JsonResponse()
+		if err != nil {
+			err = fmt.Errorf("build JSON response: %w", err)
+		}
+	}
+
+	if err != nil {
+		writeErr := writeEvent(c, "error", ParseErrorResponse(err))
+		if writeErr != nil {
+			ctx.Log().Debug(fmt.Sprintf("send error event: %s", writeErr))
+		}
+
+		return err
+	}
+
+	err = writeEvent(c, "result", data)
+	if err != nil {
+		return fmt.Errorf("send result event: %w", err)
+	}
+
+	return nil
+}
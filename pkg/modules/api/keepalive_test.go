@@ -0,0 +1,133 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func TestKeepaliveModeOf(t *testing.T) {
+	for _, tc := range []struct {
+		scenario   string
+		headers    map[string]string
+		expectMode keepaliveMode
+	}{
+		{
+			scenario:   "no keepalive",
+			expectMode: keepaliveNone,
+		},
+		{
+			scenario:   "invalid Gotenberg-Keepalive header",
+			headers:    map[string]string{"Gotenberg-Keepalive": "foo"},
+			expectMode: keepaliveNone,
+		},
+		{
+			scenario:   "Gotenberg-Keepalive header",
+			headers:    map[string]string{"Gotenberg-Keepalive": "true"},
+			expectMode: keepaliveProcessing,
+		},
+		{
+			scenario:   "event stream",
+			headers:    map[string]string{"Accept": "application/json, text/event-stream"},
+			expectMode: keepaliveEventStream,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			mode := keepaliveModeOf(c)
+			if mode != tc.expectMode {
+				t.Errorf("expected %d but got %d", tc.expectMode, mode)
+			}
+		})
+	}
+}
+
+func TestAwaitWithKeepalive(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		mode            keepaliveMode
+		fn              func() error
+		expectStreaming bool
+		expectError     bool
+		expectBody      string
+	}{
+		{
+			scenario: "no keepalive",
+			mode:     keepaliveNone,
+			fn: func() error {
+				return errors.New("foo")
+			},
+			expectError: true,
+		},
+		{
+			scenario: "fast event stream",
+			mode:     keepaliveEventStream,
+			fn: func() error {
+				return nil
+			},
+		},
+		{
+			scenario: "slow event stream",
+			mode:     keepaliveEventStream,
+			fn: func() error {
+				time.Sleep(time.Duration(100) * time.Millisecond)
+				return nil
+			},
+			expectStreaming: true,
+			expectBody:      "event: progress\ndata: {\"elapsed\":",
+		},
+		{
+			scenario: "panic",
+			mode:     keepaliveEventStream,
+			fn: func() error {
+				panic("foo")
+			},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), recorder)
+			c.Set("logger", zap.NewNop())
+
+			streaming, err := awaitWithKeepalive(c, tc.mode, time.Duration(30)*time.Millisecond, tc.fn)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if streaming != tc.expectStreaming {
+				t.Fatalf("expected streaming %t but got %t", tc.expectStreaming, streaming)
+			}
+
+			if !tc.expectStreaming {
+				return
+			}
+
+			contentType := recorder.Header().Get(echo.HeaderContentType)
+			if contentType != mimeTextEventStream {
+				t.Errorf("expected '%s' content type but got '%s'", mimeTextEventStream, contentType)
+			}
+
+			if !strings.HasPrefix(recorder.Body.String(), tc.expectBody) {
+				t.Errorf("expected body starting with '%s' but got '%s'", tc.expectBody, recorder.Body.String())
+			}
+		})
+	}
+}
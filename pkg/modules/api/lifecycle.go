@@ -0,0 +1,58 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// OnStart registers a function called when the conversion of the request
+// starts, i.e., once the request has gone through all the middlewares, e.g.,
+// after having waited for a concurrency slot. If the conversion has already
+// started, the function is called immediately.
+func (ctx *Context) OnStart(fn func()) {
+	ctx.startMu.Lock()
+
+	if !ctx.started {
+		ctx.startFns = append(ctx.startFns, fn)
+		ctx.startMu.Unlock()
+
+		return
+	}
+
+	ctx.startMu.Unlock()
+	fn()
+}
+
+// start calls the functions registered with [Context.OnStart], once.
+func (ctx *Context) start() {
+	ctx.startMu.Lock()
+
+	if ctx.started {
+		ctx.startMu.Unlock()
+
+		return
+	}
+
+	ctx.started = true
+	fns := ctx.startFns
+	ctx.startFns = nil
+	ctx.startMu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// startMiddleware marks the start of the conversion of a multipart request.
+// It comes after the modules' middlewares, right before the route handler.
+func startMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, ok := c.Get("context").(*Context)
+			if ok {
+				ctx.start()
+			}
+
+			return next(c)
+		}
+	}
+}
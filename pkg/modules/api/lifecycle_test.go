@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestContext_OnStart(t *testing.T) {
+	ctx := new(Context)
+
+	var calls []string
+	ctx.OnStart(func() { calls = append(calls, "foo") })
+	ctx.OnStart(func() { calls = append(calls, "bar") })
+
+	if len(calls) != 0 {
+		t.Fatalf("expected no call before the start but got %v", calls)
+	}
+
+	ctx.start()
+	ctx.start()
+
+	if len(calls) != 2 || calls[0] != "foo" || calls[1] != "bar" {
+		t.Fatalf("expected [foo bar] but got %v", calls)
+	}
+
+	ctx.OnStart(func() { calls = append(calls, "baz") })
+
+	if len(calls) != 3 || calls[2] != "baz" {
+		t.Errorf("expected an immediate call after the start but got %v", calls)
+	}
+}
+
+func TestStartMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *Context
+		expectStart bool
+	}{
+		{
+			scenario: "no context",
+		},
+		{
+			scenario:    "context",
+			ctx:         new(Context),
+			expectStart: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+
+			started := false
+			if tc.ctx != nil {
+				c.Set("context", tc.ctx)
+				tc.ctx.OnStart(func() { started = true })
+			}
+
+			called := false
+			err := startMiddleware()(func(c echo.Context) error {
+				called = true
+
+				if tc.expectStart && !started {
+					t.Error("expected the start before the handler")
+				}
+
+				return nil
+			})(c)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !called {
+				t.Error("expected the next handler to be called")
+			}
+
+			if started != tc.expectStart {
+				t.Errorf("expected start %t but got %t", tc.expectStart, started)
+			}
+		})
+	}
+}
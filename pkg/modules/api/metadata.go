@@ -0,0 +1,309 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// PageCountHeader is the response header with the total number of pages
+	// of the PDF output files.
+	PageCountHeader = "Gotenberg-Page-Count"
+
+	// OutputSizeHeader is the response header with the size, in bytes, of the
+	// output file.
+	OutputSizeHeader = "Gotenberg-Output-Size"
+
+	// Sha256Header is the response header with the hex-encoded SHA-256
+	// checksum of the output file.
+	Sha256Header = "Gotenberg-Output-Sha256"
+
+	// ProcessingTimeHeader is the response header with the duration, in
+	// milliseconds, of the processing.
+	ProcessingTimeHeader = "Gotenberg-Processing-Time"
+
+	// EngineHeader is the response header with the comma-separated list of
+	// the engines involved in the processing.
+	EngineHeader = "Gotenberg-Engine"
+
+	// WarningCountHeader is the response header with the number of warnings
+	// of the processing, detailed in the output metadata.
+	WarningCountHeader = "Gotenberg-Warning-Count"
+
+	// metadataFilename is the name of the JSON file added to archives.
+	metadataFilename = "metadata.json"
+)
+
+// OutputMetadata gathers information about an output file.
+type OutputMetadata struct {
+	Filename  string `json:"filename"`
+	Size      int64  `json:"size"`
+	PageCount int    `json:"pageCount,omitempty"`
+	Sha256    string `json:"sha256,omitempty"`
+}
+
+// Metadata gathers information about the result of a request.
+type Metadata struct {
+	Trace            string           `json:"trace,omitempty"`
+	Engines          []string         `json:"engines,omitempty"`
+	ProcessingTimeMs int64            `json:"processingTimeMs"`
+	PageCount        int              `json:"pageCount"`
+	Size             int64            `json:"size"`
+	Outputs          []OutputMetadata `json:"outputs"`
+	Warnings         []string         `json:"warnings,omitempty"`
+	Risk             *RiskReport      `json:"risk,omitempty"`
+	Resources        *ResourceUsage   `json:"resources,omitempty"`
+}
+
+// AddEngines registers the names of the engines involved in the processing.
+// They are reported to the client with the output metadata.
+func (ctx *Context) AddEngines(names ...string) {
+	ctx.metadataMu.Lock()
+	defer ctx.metadataMu.Unlock()
+
+	for _, name := range names {
+		exists := false
+		for _, engine := range ctx.engines {
+			if engine == name {
+				exists = true
+				break
+			}
+		}
+
+		if !exists {
+			ctx.engines = append(ctx.engines, name)
+		}
+	}
+}
+
+// AddWarnings registers warnings about the processing, e.g., something the
+// output files may lack. They are reported to the client with the output
+// metadata.
+func (ctx *Context) AddWarnings(warnings ...string) {
+	ctx.metadataMu.Lock()
+	defer ctx.metadataMu.Unlock()
+
+	ctx.warnings = append(ctx.warnings, warnings...)
+}
+
+// Metadata returns the [Metadata] of the registered output paths.
+func (ctx *Context) Metadata() (Metadata, error) {
+	if ctx.cancelled {
+		return Metadata{}, ErrContextAlreadyClosed
+	}
+
+	metadata := Metadata{
+		Outputs: make([]OutputMetadata, len(ctx.outputPaths)),
+	}
+
+	ctx.metadataMu.Lock()
+	metadata.Engines = append(metadata.Engines, ctx.engines...)
+	metadata.Warnings = append(metadata.Warnings, ctx.warnings...)
+	metadata.Risk = ctx.riskReport
+	readResourceUsage := ctx.resourceUsage
+	ctx.metadataMu.Unlock()
+
+	if readResourceUsage != nil {
+		usage := readResourceUsage()
+		metadata.Resources = &usage
+	}
+
+	if ctx.echoCtx != nil {
+		trace, ok := ctx.echoCtx.Get("trace").(string)
+		if ok {
+			metadata.Trace = trace
+		}
+
+		startTime, ok := ctx.echoCtx.Get("startTime").(time.Time)
+		if ok {
+			metadata.ProcessingTimeMs = time.Since(startTime).Milliseconds()
+		}
+	}
+
+	for i, outputPath := range ctx.outputPaths {
+		stat, err := os.Stat(outputPath)
+		if err != nil {
+			return Metadata{}, fmt.Errorf("get stat from output file: %w", err)
+		}
+
+		output := OutputMetadata{
+			Filename: filepath.Base(outputPath),
+			Size:     stat.Size(),
+		}
+
+		if ctx.outputMetadata {
+			output.Sha256, err = ctx.checksum(outputPath)
+			if err != nil {
+				return Metadata{}, fmt.Errorf("compute checksum of output file: %w", err)
+			}
+		}
+
+		if ctx.pdfEngine != nil && strings.EqualFold(filepath.Ext(outputPath), ".pdf") {
+			count, err := ctx.pdfEngine.PageCount(ctx, ctx.logger, outputPath)
+			if err != nil {
+				// Not critical, the metadata is informative.
+				ctx.logger.Debug(fmt.Sprintf("count pages of '%s': %s", outputPath, err))
+			} else {
+				output.PageCount = count
+			}
+		}
+
+		metadata.Outputs[i] = output
+		metadata.PageCount += output.PageCount
+		metadata.Size += output.Size
+	}
+
+	return metadata, nil
+}
+
+// OutputHeaders returns the metadata headers for the given output file, i.e.,
+// the result of [Context.BuildOutputFile]. It returns nil if the output
+// metadata are disabled.
+func (ctx *Context) OutputHeaders(outputPath string) map[string]string {
+	if !ctx.outputMetadata {
+		return nil
+	}
+
+	metadata := ctx.metadata
+	if metadata == nil {
+		m, err := ctx.Metadata()
+		if err != nil {
+			ctx.logger.Error(fmt.Sprintf("get output metadata: %s", err))
+
+			return nil
+		}
+
+		metadata = &m
+	}
+
+	headers := map[string]string{
+		ProcessingTimeHeader: strconv.FormatInt(metadata.ProcessingTimeMs, 10),
+	}
+
+	// The output file does not exist yet if it is an archive streamed while
+	// being created.
+	stat, err := os.Stat(outputPath)
+	if err == nil {
+		headers[OutputSizeHeader] = strconv.FormatInt(stat.Size(), 10)
+
+		checksum, err := ctx.checksum(outputPath)
+		if err != nil {
+			ctx.logger.Error(fmt.Sprintf("compute checksum of output file: %s", err))
+		} else {
+			headers[Sha256Header] = checksum
+		}
+	}
+
+	if metadata.PageCount > 0 {
+		headers[PageCountHeader] = strconv.Itoa(metadata.PageCount)
+	}
+
+	if len(metadata.Engines) > 0 {
+		headers[EngineHeader] = strings.Join(metadata.Engines, ", ")
+	}
+
+	if len(metadata.Warnings) > 0 {
+		headers[WarningCountHeader] = strconv.Itoa(len(metadata.Warnings))
+	}
+
+	if metadata.Risk != nil {
+		headers[RiskScoreHeader] = strconv.Itoa(metadata.Risk.Score)
+	}
+
+	if metadata.Resources != nil {
+		headers[PeakMemoryHeader] = strconv.FormatInt(metadata.Resources.PeakMemoryBytes, 10)
+		headers[PeakScratchSizeHeader] = strconv.FormatInt(metadata.Resources.PeakScratchBytes, 10)
+	}
+
+	return headers
+}
+
+// checksum returns the hex-encoded SHA-256 checksum of a file. As the
+// metadata may be gathered more than once, e.g., for the headers and the
+// archive, the checksums are computed once per file.
+func (ctx *Context) checksum(path string) (string, error) {
+	ctx.metadataMu.Lock()
+	defer ctx.metadataMu.Unlock()
+
+	checksum, ok := ctx.checksums[path]
+	if ok {
+		return checksum, nil
+	}
+
+	checksum, err := sha256File(path)
+	if err != nil {
+		return "", err
+	}
+
+	if ctx.checksums == nil {
+		ctx.checksums = make(map[string]string)
+	}
+
+	ctx.checksums[path] = checksum
+
+	return checksum, nil
+}
+
+// sha256File returns the hex-encoded SHA-256 checksum of a file, read as a
+// stream.
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeMetadata writes the [Metadata] as a JSON file within the context's
+// working directory and returns its path.
+func (ctx *Context) writeMetadata() (string, error) {
+	metadata, err := ctx.Metadata()
+	if err != nil {
+		return "", fmt.Errorf("get metadata: %w", err)
+	}
+
+	ctx.metadata = &metadata
+
+	b, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal metadata: %w", err)
+	}
+
+	// A dedicated directory avoids overriding a file from the request with
+	// the same name.
+	dirPath := ctx.GeneratePath("", "")
+
+	err = os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("create metadata directory: %w", err)
+	}
+
+	path := fmt.Sprintf("%s/%s", dirPath, metadataFilename)
+
+	err = os.WriteFile(path, b, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write metadata: %w", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestContext_AddEngines(t *testing.T) {
+	ctx := &Context{}
+	ctx.AddEngines("chromium")
+	ctx.AddEngines("pdfcpu", "chromium")
+
+	expect := []string{"chromium", "pdfcpu"}
+
+	if !reflect.DeepEqual(ctx.engines, expect) {
+		t.Errorf("expected %v but got %v", expect, ctx.engines)
+	}
+}
+
+func TestContext_AddWarnings(t *testing.T) {
+	ctx := &Context{}
+	ctx.AddWarnings("foo")
+	ctx.AddWarnings("bar", "baz")
+
+	expect := []string{"foo", "bar", "baz"}
+
+	if !reflect.DeepEqual(ctx.warnings, expect) {
+		t.Errorf("expected %v but got %v", expect, ctx.warnings)
+	}
+}
+
+func TestContext_Metadata(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		ctx             *Context
+		outputs         map[string]string
+		expectPageCount int
+		expectSize      int64
+		expectSha256    string
+		expectError     bool
+	}{
+		{
+			scenario:    "ErrContextAlreadyClosed",
+			ctx:         &Context{cancelled: true},
+			expectError: true,
+		},
+		{
+			scenario: "non-existing output file",
+			ctx: &Context{
+				outputPaths: []string{"/foo/bar.pdf"},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "no PDF engine",
+			ctx:      &Context{},
+			outputs: map[string]string{
+				"foo.pdf": "foo",
+			},
+			expectPageCount: 0,
+			expectSize:      3,
+		},
+		{
+			scenario: "page count error",
+			ctx: &Context{
+				outputMetadata: true,
+				pdfEngine: &gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 0, errors.New("foo")
+					},
+				},
+			},
+			outputs: map[string]string{
+				"foo.pdf": "foo",
+			},
+			expectPageCount: 0,
+			expectSize:      3,
+			expectSha256:    "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		},
+		{
+			scenario: "success",
+			ctx: &Context{
+				pdfEngine: &gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 2, nil
+					},
+				},
+			},
+			outputs: map[string]string{
+				"foo.pdf": "foo",
+				"bar.pdf": "bar",
+				"baz.png": "baz",
+			},
+			expectPageCount: 4,
+			expectSize:      9,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+
+			for filename, content := range tc.outputs {
+				path := dirPath + "/" + filename
+
+				err := os.WriteFile(path, []byte(content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				tc.ctx.outputPaths = append(tc.ctx.outputPaths, path)
+			}
+
+			tc.ctx.dirPath = dirPath
+			tc.ctx.logger = zap.NewNop()
+
+			metadata, err := tc.ctx.Metadata()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if metadata.PageCount != tc.expectPageCount {
+				t.Errorf("expected %d pages but got %d", tc.expectPageCount, metadata.PageCount)
+			}
+
+			if metadata.Size != tc.expectSize {
+				t.Errorf("expected size %d but got %d", tc.expectSize, metadata.Size)
+			}
+
+			if len(metadata.Outputs) > 0 && metadata.Outputs[0].Sha256 != tc.expectSha256 {
+				t.Errorf("expected SHA-256 '%s' but got '%s'", tc.expectSha256, metadata.Outputs[0].Sha256)
+			}
+		})
+	}
+}
+
+func TestContext_OutputHeaders(t *testing.T) {
+	dirPath := t.TempDir()
+	outputPath := dirPath + "/foo.pdf"
+
+	err := os.WriteFile(outputPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		ctx           *Context
+		expectHeaders map[string]string
+	}{
+		{
+			scenario: "output metadata disabled",
+			ctx: &Context{
+				outputPaths: []string{outputPath},
+			},
+			expectHeaders: nil,
+		},
+		{
+			scenario: "output metadata enabled",
+			ctx: &Context{
+				outputPaths:    []string{outputPath},
+				outputMetadata: true,
+				engines:        []string{"chromium", "pdfcpu"},
+				warnings:       []string{"foo"},
+				riskReport:     &RiskReport{Score: 60},
+				resourceUsage: func() ResourceUsage {
+					return ResourceUsage{PeakMemoryBytes: 2048, PeakScratchBytes: 1024}
+				},
+				pdfEngine: &gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 3, nil
+					},
+				},
+			},
+			expectHeaders: map[string]string{
+				ProcessingTimeHeader:  "0",
+				OutputSizeHeader:      "3",
+				Sha256Header:          "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+				PageCountHeader:       "3",
+				EngineHeader:          "chromium, pdfcpu",
+				WarningCountHeader:    "1",
+				RiskScoreHeader:       "60",
+				PeakMemoryHeader:      "2048",
+				PeakScratchSizeHeader: "1024",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.dirPath = dirPath
+			tc.ctx.logger = zap.NewNop()
+
+			actual := tc.ctx.OutputHeaders(outputPath)
+
+			if !reflect.DeepEqual(actual, tc.expectHeaders) {
+				t.Errorf("expected %v but got %v", tc.expectHeaders, actual)
+			}
+		})
+	}
+}
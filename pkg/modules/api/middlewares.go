@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"net/http"
@@ -19,50 +20,139 @@ import (
 // asynchronous fashion.
 var ErrAsyncProcess = errors.New("async process")
 
+// hardTimeoutMargin is the delay after the time limit of a request before a
+// hard timeout.
+const hardTimeoutMargin = time.Duration(5) * time.Second
+
+// ErrorResponse is the JSON body of an error response.
+type ErrorResponse struct {
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+
+	// Errors and UnknownFields detail a [ValidationError].
+	Errors        []FieldError `json:"errors,omitempty"`
+	UnknownFields []string     `json:"unknownFields,omitempty"`
+}
+
 // ParseError parses an error and returns the corresponding HTTP status and
 // HTTP message.
 func ParseError(err error) (int, string) {
+	response := ParseErrorResponse(err)
+
+	return response.Status, response.Message
+}
+
+// ParseErrorResponse parses an error and returns the corresponding
+// [ErrorResponse], i.e., the HTTP status, the HTTP message, and a stable error
+// code clients may rely on.
+func ParseErrorResponse(err error) ErrorResponse {
+	// The error code of an error wrapped with a SentinelHttpError takes
+	// precedence, as it is usually more specific.
+	code := ""
+	var coder HttpErrorCoder
+	if errors.As(err, &coder) {
+		code = coder.HttpErrorCode()
+	}
+
+	response := func(status int, message, defaultCode string) ErrorResponse {
+		if code == "" {
+			code = defaultCode
+		}
+
+		return ErrorResponse{
+			Code:    code,
+			Status:  status,
+			Message: message,
+		}
+	}
+
 	var echoErr *echo.HTTPError
 	ok := errors.As(err, &echoErr)
 	if ok {
-		return echoErr.Code, http.StatusText(echoErr.Code)
+		return response(echoErr.Code, http.StatusText(echoErr.Code), statusErrorCode(echoErr.Code))
 	}
 
 	if errors.Is(err, context.DeadlineExceeded) {
-		return http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable)
+		return response(http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable), ErrorCodeTimeout)
 	}
 
 	if errors.Is(err, gotenberg.ErrFiltered) {
-		return http.StatusForbidden, http.StatusText(http.StatusForbidden)
+		return response(http.StatusForbidden, http.StatusText(http.StatusForbidden), ErrorCodeForbidden)
 	}
 
 	if errors.Is(err, gotenberg.ErrMaximumQueueSizeExceeded) {
-		return http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests)
+		return response(http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests), ErrorCodeMaximumQueueSizeExceeded)
 	}
 
 	if errors.Is(err, gotenberg.ErrPdfFormatNotSupported) {
-		return http.StatusBadRequest, "At least one PDF engine cannot process the requested PDF format, while others may have failed to convert due to different issues"
+		return response(http.StatusBadRequest, "At least one PDF engine cannot process the requested PDF format, while others may have failed to convert due to different issues", ErrorCodePdfEngineUnsupportedFormat)
+	}
+
+	if errors.Is(err, gotenberg.ErrImageFormatNotSupported) {
+		return response(http.StatusBadRequest, "The image engine cannot render the pages to the requested image format", ErrorCodeImageEngineUnsupportedFormat)
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		status, message := validationErr.HttpError()
+		r := response(status, message, ErrorCodeInvalidFormData)
+		r.Errors = validationErr.Errors
+		r.UnknownFields = validationErr.UnknownFields
+
+		return r
 	}
 
 	var httpErr HttpError
 	if errors.As(err, &httpErr) {
-		return httpErr.HttpError()
+		status, message := httpErr.HttpError()
+
+		return response(status, message, statusErrorCode(status))
 	}
 
 	// Default 500 status code.
-	return http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError)
+	return response(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError), ErrorCodeInternal)
 }
 
 // httpErrorHandler is the centralized HTTP error handler. It parses the error,
-// returns a response as "text/plain; charset=UTF-8".
-func httpErrorHandler() echo.HTTPErrorHandler {
+// sends it to the [ErrorReporter] modules, and returns an [ErrorResponse] as
+// "application/json; charset=UTF-8", or the message as
+// "text/plain; charset=UTF-8" if the [ExtensionJsonErrors] extension is
+// disabled for the route.
+func httpErrorHandler(reporters []ErrorReporter, rootPath string, disabledExtensions map[string][]string) echo.HTTPErrorHandler {
 	return func(err error, c echo.Context) {
 		logger := c.Get("logger").(*zap.Logger)
-		status, message := ParseError(err)
 
-		c.Response().Header().Add(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+		if len(reporters) > 0 {
+			report := newErrorReport(c, err, false)
+
+			for _, reporter := range reporters {
+				reporter.ReportError(report)
+			}
+		}
 
-		err = c.String(status, message)
+		if c.Response().Committed {
+			// The response has already been sent, e.g., as an event
+			// stream.
+			return
+		}
+
+		// The path of the route without the root path, as in the flags.
+		path := fmt.Sprintf("/%s", strings.TrimPrefix(c.Request().URL.Path, rootPath))
+		if disabledExtensionsOf(disabledExtensions, path)[ExtensionJsonErrors] {
+			status, message := ParseError(err)
+
+			err = c.String(status, message)
+			if err != nil {
+				logger.Error(fmt.Sprintf("send error response: %s", err.Error()))
+			}
+
+			return
+		}
+
+		response := ParseErrorResponse(err)
+
+		err = c.JSON(response.Status, response)
 		if err != nil {
 			logger.Error(fmt.Sprintf("send error response: %s", err.Error()))
 		}
@@ -135,11 +225,59 @@ func traceMiddleware(header string) echo.MiddlewareFunc {
 	}
 }
 
+// adminMiddleware sets in the [echo.Context] under "admin" whether the
+// request carries the admin token as a bearer token in the Authorization
+// header. See [IsAdmin].
+func adminMiddleware(token string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			admin := false
+			bearer, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if ok && token != "" {
+				admin = subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+			}
+
+			c.Set("admin", admin)
+
+			// Call the next middleware in the chain.
+			return next(c)
+		}
+	}
+}
+
+// adminOnlyMiddleware rejects the requests without the admin token, i.e., for
+// the routes with [Route.IsAdmin].
+func adminOnlyMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !IsAdmin(c) {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+
+				return WrapError(
+					errors.New("missing or invalid admin token"),
+					NewSentinelHttpError(http.StatusUnauthorized, http.StatusText(http.StatusUnauthorized)).WithCode(ErrorCodeUnauthorized),
+				)
+			}
+
+			// Call the next middleware in the chain.
+			return next(c)
+		}
+	}
+}
+
+// IsAdmin tells if the request carries the admin token, e.g., for a route
+// which also accepts other credentials.
+func IsAdmin(c echo.Context) bool {
+	admin, ok := c.Get("admin").(bool)
+	return ok && admin
+}
+
 // loggerMiddleware sets the logger in the [echo.Context] under "logger" and
-// logs a synchronous request result.
+// logs a synchronous request result. It also sends an [AccessLogEntry] to the
+// [AccessLogger] modules.
 //
 //	logger := c.Get("logger").(*zap.Logger)
-func loggerMiddleware(logger *zap.Logger, disableLoggingForPaths []string) echo.MiddlewareFunc {
+func loggerMiddleware(logger *zap.Logger, disableLoggingForPaths []string, accessLoggers []AccessLogger) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			startTime := c.Get("startTime").(time.Time)
@@ -202,26 +340,77 @@ func loggerMiddleware(logger *zap.Logger, disableLoggingForPaths []string) echo.
 				reqLogger.Info("request handled", fields...)
 			}
 
+			if len(accessLoggers) > 0 {
+				entry := newAccessLogEntry(c, startTime, finishTime, err)
+
+				for _, accessLogger := range accessLoggers {
+					accessLogger.LogAccess(entry)
+				}
+			}
+
 			return nil
 		}
 	}
 }
 
+// storageGuardMiddleware rejects a "multipart/form-data" request with a 507
+// status if the storage has not enough free space or inodes to handle it,
+// instead of failing mid-conversion. The required space is estimated from the
+// Content-Length header: the uploaded files plus, roughly, as much for the
+// output files.
+func storageGuardMiddleware(storage gotenberg.Storage, minFreeSpace, minFreeInodes int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			logger := c.Get("logger").(*zap.Logger)
+
+			availableSpace, availableInodes, err := freeSpace(storage.RootDir())
+			if err != nil {
+				// Not critical, the storage may still be usable.
+				logger.Error(fmt.Sprintf("check storage free space: %s", err))
+
+				return next(c)
+			}
+
+			requiredSpace := minFreeSpace
+			if c.Request().ContentLength > 0 {
+				requiredSpace += 2 * c.Request().ContentLength
+			}
+
+			if availableSpace < requiredSpace {
+				return WrapError(
+					fmt.Errorf("not enough free space on '%s': %d bytes available, %d bytes required", storage.RootDir(), availableSpace, requiredSpace),
+					NewSentinelHttpError(http.StatusInsufficientStorage, "Not enough storage space to handle the request, please try again later").WithCode(ErrorCodeInsufficientStorage),
+				)
+			}
+
+			if availableInodes >= 0 && availableInodes < minFreeInodes {
+				return WrapError(
+					fmt.Errorf("not enough free inodes on '%s': %d inodes available, %d inodes required", storage.RootDir(), availableInodes, minFreeInodes),
+					NewSentinelHttpError(http.StatusInsufficientStorage, "Not enough storage space to handle the request, please try again later").WithCode(ErrorCodeInsufficientStorage),
+				)
+			}
+
+			return next(c)
+		}
+	}
+}
+
 // contextMiddleware, a middleware for "multipart/form-data" requests, sets the
 // [Context] and related context.CancelFunc in the [echo.Context] under
 // "context" and "cancel". If the process is synchronous, it also handles the
-// result of a "multipart/form-data" request.
+// result of a "multipart/form-data" request, either as a file or as a
+// [JsonResponse] if the client accepts JSON.
 //
 //	ctx := c.Get("context").(*api.Context)
 //	cancel := c.Get("cancel").(context.CancelFunc)
-func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration) echo.MiddlewareFunc {
+func contextMiddleware(storage gotenberg.Storage, timeout time.Duration, options contextOptions) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			logger := c.Get("logger").(*zap.Logger)
 
 			// We create a context with a timeout so that underlying processes are
 			// able to stop early and handle correctly a timeout scenario.
-			ctx, cancel, err := newContext(c, logger, fs, timeout)
+			ctx, cancel, err := newContext(c, logger, storage, timeout, options)
 			if err != nil {
 				cancel()
 
@@ -230,30 +419,107 @@ func contextMiddleware(fs *gotenberg.FileSystem, timeout time.Duration) echo.Mid
 			c.Set("context", ctx)
 			c.Set("cancel", cancel)
 
-			// Call the next middleware in the chain.
-			err = next(c)
+			// Call the next middleware in the chain, keeping alive the
+			// connection if the client asks for it.
+			keepalive := keepaliveNone
+			if ctx.ExtensionEnabled(ExtensionKeepalive) {
+				keepalive = keepaliveModeOf(c)
+			}
+
+			streaming, err := awaitWithKeepalive(c, keepalive, options.keepaliveInterval, func() error {
+				return next(c)
+			})
 
 			if errors.Is(err, ErrAsyncProcess) {
 				// A middleware/handler tells us that it's handling the process
 				// in an asynchronous fashion. Therefore, we must not cancel
-				// the context nor send an output file.
+				// the context nor send an output file. The process also
+				// outlives the client's connection.
+				ctx.detachFromClient()
+
+				if streaming {
+					if ctx.accepted != nil {
+						return writeEvent(c, "accepted", ctx.accepted)
+					}
+
+					return writeEvent(c, "accepted", struct{}{})
+				}
+
+				if ctx.accepted != nil {
+					return c.JSON(http.StatusAccepted, ctx.accepted)
+				}
+
 				return c.NoContent(http.StatusNoContent)
 			}
 
 			defer cancel()
 
+			// The route is done binding the form fields.
+			ctx.setUnknownFields(err)
+
+			if streaming {
+				return streamResult(c, ctx, err)
+			}
+
+			if errors.Is(err, ErrValidateOnly) {
+				// A pre-flight request with valid form data: let's tell the
+				// client what the route would process.
+				report, err := ctx.PreflightReport()
+				if err != nil {
+					return fmt.Errorf("build pre-flight report: %w", err)
+				}
+
+				err = c.JSON(http.StatusOK, report)
+				if err != nil {
+					return fmt.Errorf("send response: %w", err)
+				}
+
+				return nil
+			}
+
 			if err != nil {
 				return err
 			}
 
+			if c.Response().Committed {
+				// The handler has already responded, e.g., with the JSON
+				// properties of the input files.
+				return nil
+			}
+
+			if acceptsJson(c) && ctx.ExtensionEnabled(ExtensionJsonResponse) {
+				response, err := ctx.JsonResponse()
+				if err != nil {
+					return fmt.Errorf("build JSON response: %w", err)
+				}
+
+				err = c.JSON(http.StatusOK, response)
+				if err != nil {
+					return fmt.Errorf("send response: %w", err)
+				}
+
+				return nil
+			}
+
 			// No error, let's build the output file.
 			outputPath, err := ctx.BuildOutputFile()
 			if err != nil {
 				return fmt.Errorf("build output file: %w", err)
 			}
 
+			for key, value := range ctx.OutputHeaders(outputPath) {
+				c.Response().Header().Set(key, value)
+			}
+
+			contentType := ctx.OutputContentType()
+			if contentType != "" {
+				c.Response().Header().Set(echo.HeaderContentType, contentType)
+			}
+
+			c.Response().Header().Set(echo.HeaderContentDisposition, ctx.OutputContentDisposition(outputPath))
+
 			// Send the output file.
-			err = c.Attachment(outputPath, ctx.OutputFilename(outputPath))
+			err = c.File(outputPath)
 			if err != nil {
 				return fmt.Errorf("send response: %w", err)
 			}
@@ -270,11 +536,21 @@ func hardTimeoutMiddleware(hardTimeout time.Duration) echo.MiddlewareFunc {
 		return func(c echo.Context) error {
 			logger := c.Get("logger").(*zap.Logger)
 
+			// A multipart request may have its own time limit.
+			timeout := hardTimeout
+			ctx, ok := c.Get("context").(*Context)
+			if ok {
+				deadline, ok := ctx.Deadline()
+				if ok {
+					timeout = time.Until(deadline) + hardTimeoutMargin
+				}
+			}
+
 			// Define a hard timeout if the route handler fails to timeout as
 			// expected.
 			hardTimeoutCtx, hardTimeoutCancel := context.WithTimeout(
 				context.Background(),
-				hardTimeout,
+				timeout,
 			)
 			defer hardTimeoutCancel()
 
@@ -3,10 +3,15 @@ package api
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -64,28 +69,138 @@ func TestParseError(t *testing.T) {
 	}
 }
 
+func TestParseErrorResponse(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		err      error
+		expect   ErrorResponse
+	}{
+		{
+			scenario: "echo HTTP error",
+			err:      echo.ErrNotFound,
+			expect: ErrorResponse{
+				Code:    "NOT_FOUND",
+				Status:  http.StatusNotFound,
+				Message: http.StatusText(http.StatusNotFound),
+			},
+		},
+		{
+			scenario: "context.DeadlineExceeded",
+			err:      fmt.Errorf("foo: %w", context.DeadlineExceeded),
+			expect: ErrorResponse{
+				Code:    ErrorCodeTimeout,
+				Status:  http.StatusServiceUnavailable,
+				Message: http.StatusText(http.StatusServiceUnavailable),
+			},
+		},
+		{
+			scenario: "gotenberg.ErrPdfFormatNotSupported",
+			err:      fmt.Errorf("foo: %w", gotenberg.ErrPdfFormatNotSupported),
+			expect: ErrorResponse{
+				Code:    ErrorCodePdfEngineUnsupportedFormat,
+				Status:  http.StatusBadRequest,
+				Message: "At least one PDF engine cannot process the requested PDF format, while others may have failed to convert due to different issues",
+			},
+		},
+		{
+			scenario: "gotenberg.ErrImageFormatNotSupported",
+			err:      fmt.Errorf("foo: %w", gotenberg.ErrImageFormatNotSupported),
+			expect: ErrorResponse{
+				Code:    ErrorCodeImageEngineUnsupportedFormat,
+				Status:  http.StatusBadRequest,
+				Message: "The image engine cannot render the pages to the requested image format",
+			},
+		},
+		{
+			scenario: "wrapped error with code",
+			err: fmt.Errorf("foo: %w", WrapError(
+				errors.New("foo"),
+				NewSentinelHttpError(http.StatusBadRequest, "foo").WithCode("FOO"),
+			)),
+			expect: ErrorResponse{
+				Code:    "FOO",
+				Status:  http.StatusBadRequest,
+				Message: "foo",
+			},
+		},
+		{
+			scenario: "wrapped error without code",
+			err: WrapError(
+				errors.New("foo"),
+				NewSentinelHttpError(http.StatusConflict, "foo"),
+			),
+			expect: ErrorResponse{
+				Code:    "CONFLICT",
+				Status:  http.StatusConflict,
+				Message: "foo",
+			},
+		},
+		{
+			scenario: "validation error",
+			err: fmt.Errorf("validate form data: %w", &ValidationError{
+				Errors: []FieldError{
+					{Field: "foo", Reason: "required", Expected: "boolean", err: errors.New("form field 'foo' is required")},
+					{File: "bar.txt", Reason: "required", Expected: "file", err: errors.New("form file 'bar.txt' is required")},
+				},
+				UnknownFields: []string{"baz"},
+			}),
+			expect: ErrorResponse{
+				Code:    ErrorCodeInvalidFormData,
+				Status:  http.StatusBadRequest,
+				Message: "Invalid form data: form field 'foo' is required; form file 'bar.txt' is required",
+				Errors: []FieldError{
+					{Field: "foo", Reason: "required", Expected: "boolean", err: errors.New("form field 'foo' is required")},
+					{File: "bar.txt", Reason: "required", Expected: "file", err: errors.New("form file 'bar.txt' is required")},
+				},
+				UnknownFields: []string{"baz"},
+			},
+		},
+		{
+			scenario: "unknown error",
+			err:      errors.New("foo"),
+			expect: ErrorResponse{
+				Code:    ErrorCodeInternal,
+				Status:  http.StatusInternalServerError,
+				Message: http.StatusText(http.StatusInternalServerError),
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := ParseErrorResponse(tc.err)
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
 func TestHttpErrorHandler(t *testing.T) {
 	for i, tc := range []struct {
 		err           error
 		expectStatus  int
+		expectCode    string
 		expectMessage string
 	}{
 		{
 			err:           echo.ErrInternalServerError,
 			expectStatus:  http.StatusInternalServerError,
+			expectCode:    ErrorCodeInternal,
 			expectMessage: http.StatusText(http.StatusInternalServerError),
 		},
 		{
 			err:           context.DeadlineExceeded,
 			expectStatus:  http.StatusServiceUnavailable,
+			expectCode:    ErrorCodeTimeout,
 			expectMessage: http.StatusText(http.StatusServiceUnavailable),
 		},
 		{
 			err: WrapError(
 				errors.New("foo"),
-				NewSentinelHttpError(http.StatusBadRequest, "foo"),
+				NewSentinelHttpError(http.StatusBadRequest, "foo").WithCode("FOO"),
 			),
 			expectStatus:  http.StatusBadRequest,
+			expectCode:    "FOO",
 			expectMessage: "foo",
 		},
 	} {
@@ -99,12 +214,12 @@ func TestHttpErrorHandler(t *testing.T) {
 		c := srv.NewContext(request, recorder)
 		c.Set("logger", zap.NewNop())
 
-		handler := httpErrorHandler()
+		handler := httpErrorHandler(nil, "/", nil)
 		handler(tc.err, c)
 
 		contentType := recorder.Header().Get(echo.HeaderContentType)
-		if contentType != echo.MIMETextPlainCharsetUTF8 {
-			t.Errorf("test %d: expected %s '%s' but got '%s'", i, echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8, contentType)
+		if contentType != echo.MIMEApplicationJSONCharsetUTF8 {
+			t.Errorf("test %d: expected %s '%s' but got '%s'", i, echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8, contentType)
 		}
 
 		// Note: we cannot test the trace header in the response here, as it is set in the trace middleware.
@@ -113,9 +228,84 @@ func TestHttpErrorHandler(t *testing.T) {
 			t.Errorf("test %d: expected HTTP status code %d but got %d", i, tc.expectStatus, recorder.Code)
 		}
 
-		if recorder.Body.String() != tc.expectMessage {
-			t.Errorf("test %d: expected message '%s' but got '%s'", i, tc.expectMessage, recorder.Body.String())
+		var body ErrorResponse
+		err := json.Unmarshal(recorder.Body.Bytes(), &body)
+		if err != nil {
+			t.Fatalf("test %d: expected no error but got: %v", i, err)
+		}
+
+		if body.Status != tc.expectStatus {
+			t.Errorf("test %d: expected status %d in body but got %d", i, tc.expectStatus, body.Status)
+		}
+
+		if body.Code != tc.expectCode {
+			t.Errorf("test %d: expected code '%s' but got '%s'", i, tc.expectCode, body.Code)
 		}
+
+		if body.Message != tc.expectMessage {
+			t.Errorf("test %d: expected message '%s' but got '%s'", i, tc.expectMessage, body.Message)
+		}
+	}
+}
+
+func TestHttpErrorHandler_PlainText(t *testing.T) {
+	for _, tc := range []struct {
+		scenario           string
+		path               string
+		disabledExtensions map[string][]string
+		expectContentType  string
+	}{
+		{
+			scenario:          "JSON errors enabled",
+			path:              "/forms/libreoffice/convert",
+			expectContentType: echo.MIMEApplicationJSONCharsetUTF8,
+		},
+		{
+			scenario:           "JSON errors disabled for all routes",
+			path:               "/forms/libreoffice/convert",
+			disabledExtensions: map[string][]string{"": {ExtensionJsonErrors}},
+			expectContentType:  echo.MIMETextPlainCharsetUTF8,
+		},
+		{
+			scenario:           "JSON errors disabled for the route",
+			path:               "/forms/libreoffice/convert",
+			disabledExtensions: map[string][]string{"/forms/libreoffice": {ExtensionJsonErrors}},
+			expectContentType:  echo.MIMETextPlainCharsetUTF8,
+		},
+		{
+			scenario:           "JSON errors disabled for another route",
+			path:               "/forms/chromium/convert/url",
+			disabledExtensions: map[string][]string{"/forms/libreoffice": {ExtensionJsonErrors}},
+			expectContentType:  echo.MIMEApplicationJSONCharsetUTF8,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, tc.path, nil)
+
+			c := echo.New().NewContext(request, recorder)
+			c.Set("logger", zap.NewNop())
+
+			err := WrapError(
+				errors.New("foo"),
+				NewSentinelHttpError(http.StatusBadRequest, "foo").WithCode("FOO"),
+			)
+
+			httpErrorHandler(nil, "/", tc.disabledExtensions)(err, c)
+
+			contentType := recorder.Header().Get(echo.HeaderContentType)
+			if contentType != tc.expectContentType {
+				t.Errorf("expected %s '%s' but got '%s'", echo.HeaderContentType, tc.expectContentType, contentType)
+			}
+
+			if recorder.Code != http.StatusBadRequest {
+				t.Errorf("expected HTTP status code %d but got %d", http.StatusBadRequest, recorder.Code)
+			}
+
+			if tc.expectContentType == echo.MIMETextPlainCharsetUTF8 && recorder.Body.String() != "foo" {
+				t.Errorf("expected body 'foo' but got '%s'", recorder.Body.String())
+			}
+		})
 	}
 }
 
@@ -231,6 +421,132 @@ func TestTraceMiddleware(t *testing.T) {
 	}
 }
 
+func TestAdminMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		token         string
+		authorization string
+		expectAdmin   bool
+	}{
+		{
+			scenario:      "no admin token",
+			token:         "",
+			authorization: "Bearer ",
+			expectAdmin:   false,
+		},
+		{
+			scenario:      "no authorization header",
+			token:         "foo",
+			authorization: "",
+			expectAdmin:   false,
+		},
+		{
+			scenario:      "not a bearer token",
+			token:         "foo",
+			authorization: "foo",
+			expectAdmin:   false,
+		},
+		{
+			scenario:      "wrong token",
+			token:         "foo",
+			authorization: "Bearer bar",
+			expectAdmin:   false,
+		},
+		{
+			scenario:      "admin token",
+			token:         "foo",
+			authorization: "Bearer foo",
+			expectAdmin:   true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/foo", nil)
+			if tc.authorization != "" {
+				request.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			}
+
+			srv := echo.New()
+			c := srv.NewContext(request, httptest.NewRecorder())
+
+			err := adminMiddleware(tc.token)(
+				func(c echo.Context) error {
+					return nil
+				},
+			)(c)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if IsAdmin(c) != tc.expectAdmin {
+				t.Errorf("expected admin %t but got %t", tc.expectAdmin, IsAdmin(c))
+			}
+		})
+	}
+}
+
+func TestAdminOnlyMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		admin            bool
+		expectHttpError  bool
+		expectHttpStatus int
+		expectNextCalled bool
+	}{
+		{
+			scenario:         "not admin",
+			admin:            false,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusUnauthorized,
+		},
+		{
+			scenario:         "admin",
+			admin:            true,
+			expectNextCalled: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			srv := echo.New()
+			c := srv.NewContext(httptest.NewRequest(http.MethodGet, "/admin/foo", nil), recorder)
+			c.Set("admin", tc.admin)
+
+			nextCalled := false
+			err := adminOnlyMiddleware()(
+				func(c echo.Context) error {
+					nextCalled = true
+					return nil
+				},
+			)(c)
+
+			var httpErr HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Fatalf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected status %d but got %d", tc.expectHttpStatus, status)
+				}
+
+				if recorder.Header().Get(echo.HeaderWWWAuthenticate) != "Bearer" {
+					t.Errorf("expected a '%s' header", echo.HeaderWWWAuthenticate)
+				}
+			}
+
+			if nextCalled != tc.expectNextCalled {
+				t.Errorf("expected next called %t but got %t", tc.expectNextCalled, nextCalled)
+			}
+		})
+	}
+}
+
 func TestLoggerMiddleware(t *testing.T) {
 	for i, tc := range []struct {
 		request     *http.Request
@@ -279,13 +595,72 @@ func TestLoggerMiddleware(t *testing.T) {
 			disableLoggingForPaths = append(disableLoggingForPaths, tc.request.RequestURI)
 		}
 
-		err := loggerMiddleware(zap.NewNop(), disableLoggingForPaths)(tc.next)(c)
+		err := loggerMiddleware(zap.NewNop(), disableLoggingForPaths, nil)(tc.next)(c)
 		if err != nil {
 			t.Errorf("test %d: expected no error but got: %v", i, err)
 		}
 	}
 }
 
+func TestStorageGuardMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		rootDir       string
+		contentLength int64
+		minFreeSpace  int64
+		minFreeInodes int64
+		expectStatus  int
+	}{
+		{
+			scenario:     "non-existing root directory",
+			rootDir:      "/foo",
+			expectStatus: http.StatusOK,
+		},
+		{
+			scenario:     "not enough free space",
+			rootDir:      os.TempDir(),
+			minFreeSpace: math.MaxInt64 / 2,
+			expectStatus: http.StatusInsufficientStorage,
+		},
+		{
+			scenario:      "not enough free space for the request",
+			rootDir:       os.TempDir(),
+			contentLength: math.MaxInt64 / 4,
+			expectStatus:  http.StatusInsufficientStorage,
+		},
+		{
+			scenario:      "enough free space",
+			rootDir:       os.TempDir(),
+			contentLength: 1024,
+			expectStatus:  http.StatusOK,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/forms/foo", nil)
+			request.ContentLength = tc.contentLength
+
+			c := echo.New().NewContext(request, httptest.NewRecorder())
+			c.Set("logger", zap.NewNop())
+
+			storage := gotenberg.NewFileSystemAt(tc.rootDir)
+			next := func(c echo.Context) error {
+				return nil
+			}
+
+			err := storageGuardMiddleware(storage, tc.minFreeSpace, tc.minFreeInodes)(next)(c)
+
+			status := http.StatusOK
+			if err != nil {
+				status = ParseErrorResponse(err).Status
+			}
+
+			if status != tc.expectStatus {
+				t.Errorf("expected status %d but got %d: %v", tc.expectStatus, status, err)
+			}
+		})
+	}
+}
+
 func TestContextMiddleware(t *testing.T) {
 	buildMultipartFormDataRequest := func() *http.Request {
 		body := &bytes.Buffer{}
@@ -330,6 +705,29 @@ func TestContextMiddleware(t *testing.T) {
 			}(),
 			expectStatus: http.StatusNoContent,
 		},
+		{
+			request: buildMultipartFormDataRequest(),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					ctx := c.Get("context").(*Context)
+					ctx.SetAccepted(map[string]string{"id": "foo"})
+
+					return ErrAsyncProcess
+				}
+			}(),
+			expectStatus:      http.StatusAccepted,
+			expectContentType: echo.MIMEApplicationJSONCharsetUTF8,
+		},
+		{
+			request: buildMultipartFormDataRequest(),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					return c.JSON(http.StatusOK, map[string]string{"foo": "bar"})
+				}
+			}(),
+			expectStatus:      http.StatusOK,
+			expectContentType: echo.MIMEApplicationJSONCharsetUTF8,
+		},
 		{
 			request: buildMultipartFormDataRequest(),
 			next: func() echo.HandlerFunc {
@@ -385,6 +783,102 @@ func TestContextMiddleware(t *testing.T) {
 			expectStatus:      http.StatusOK,
 			expectContentType: "application/zip",
 		},
+		{
+			request: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+
+				err := writer.WriteField("disposition", "inline")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = writer.WriteField("contentType", "application/x-pdf")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = writer.Close()
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/", body)
+				req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+
+				return req
+			}(),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					ctx := c.Get("context").(*Context)
+					ctx.outputPaths = []string{
+						"/tests/test/testdata/api/sample2.pdf",
+					}
+
+					return nil
+				}
+			}(),
+			expectStatus:      http.StatusOK,
+			expectContentType: "application/x-pdf",
+			expectFilename:    "inline; filename=sample2.pdf",
+		},
+		{
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set(echo.HeaderAccept, "application/json")
+
+				return req
+			}(),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					ctx := c.Get("context").(*Context)
+					ctx.outputPaths = []string{
+						"/tests/test/testdata/api/sample1.txt",
+						"/tests/test/testdata/api/sample2.pdf",
+					}
+
+					return nil
+				}
+			}(),
+			expectStatus:      http.StatusOK,
+			expectContentType: echo.MIMEApplicationJSONCharsetUTF8,
+		},
+		{
+			request: func() *http.Request {
+				body := &bytes.Buffer{}
+				writer := multipart.NewWriter(body)
+
+				err := writer.WriteField("validateOnly", "true")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = writer.Close()
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/", body)
+				req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+
+				return req
+			}(),
+			next: func() echo.HandlerFunc {
+				return func(c echo.Context) error {
+					ctx := c.Get("context").(*Context)
+
+					var foo string
+					err := ctx.FormData().String("foo", &foo, "bar").Validate()
+					if err != nil {
+						return fmt.Errorf("validate form data: %w", err)
+					}
+
+					return errors.New("conversion should not run")
+				}
+			}(),
+			expectStatus:      http.StatusOK,
+			expectContentType: echo.MIMEApplicationJSONCharsetUTF8,
+		},
 	} {
 		recorder := httptest.NewRecorder()
 
@@ -397,7 +891,7 @@ func TestContextMiddleware(t *testing.T) {
 		c.Set("trace", "foo")
 		c.Set("startTime", time.Now())
 
-		err := contextMiddleware(gotenberg.NewFileSystem(), time.Duration(10)*time.Second)(tc.next)(c)
+		err := contextMiddleware(gotenberg.NewFileSystem(), time.Duration(10)*time.Second, contextOptions{})(tc.next)(c)
 
 		if tc.expectErr && err == nil {
 			t.Errorf("test %d: expected error but got: %v", i, err)
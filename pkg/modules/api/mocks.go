@@ -1,9 +1,15 @@
 package api
 
 import (
+	"context"
+	"io"
+	"time"
+
 	"github.com/alexliesenfeld/health"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
 
 // ContextMock is a helper for tests.
@@ -52,6 +58,15 @@ func (ctx *ContextMock) SetFiles(files map[string]string) {
 	ctx.files = files
 }
 
+// Warnings returns the registered warnings.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.AddWarnings("foo")
+//	warnings := ctx.Warnings()
+func (ctx *ContextMock) Warnings() []string {
+	return ctx.warnings
+}
+
 // SetCancelled sets if the context is cancelled or not.
 //
 //	ctx := &api.ContextMock{Context: &api.Context{}}
@@ -60,12 +75,64 @@ func (ctx *ContextMock) SetCancelled(cancelled bool) {
 	ctx.cancelled = cancelled
 }
 
-// OutputPaths returns the registered output paths.
+// SetValidateOnly sets if the request is a pre-flight one or not.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetValidateOnly(true)
+func (ctx *ContextMock) SetValidateOnly(validateOnly bool) {
+	ctx.validateOnly = validateOnly
+	ctx.boundFields = make(map[string]bool)
+	ctx.boundFiles = make(map[string]bool)
+}
+
+// SetAsync sets if the request is an asynchronous one.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetAsync(true)
+func (ctx *ContextMock) SetAsync(async bool) {
+	ctx.async = async
+}
+
+// Accepted returns the body of the 202 Accepted response to an asynchronous
+// request, if any.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	body := ctx.Accepted()
+func (ctx *ContextMock) Accepted() any {
+	return ctx.accepted
+}
+
+// SetTimeout sets the time limit of the request.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetTimeout(time.Duration(30) * time.Second)
+func (ctx *ContextMock) SetTimeout(timeout time.Duration) {
+	ctx.timeout = timeout
+}
+
+// SetDeadline sets the deadline of the request, which
+// [Context.SuspendDeadline] and [Context.RestartDeadline] may change. It
+// returns the function which cancels the request.
 //
 //	ctx := &api.ContextMock{Context: &api.Context{}}
-//	outputPaths := ctx.OutputPaths()
-func (ctx *ContextMock) OutputPaths() []string {
-	return ctx.outputPaths
+//	cancel := ctx.SetDeadline(time.Now().Add(time.Duration(30) * time.Second))
+func (ctx *ContextMock) SetDeadline(deadline time.Time) context.CancelFunc {
+	deadlineCtx, cancel := newDeadlineContext(deadline)
+	ctx.Context.Context = deadlineCtx
+
+	return cancel
+}
+
+// SetDisabledExtensions sets the extensions disabled for the route.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetDisabledExtensions(api.ExtensionRemoveBlankPages)
+func (ctx *ContextMock) SetDisabledExtensions(extensions ...string) {
+	ctx.disabledExtensions = make(map[string]bool, len(extensions))
+
+	for _, extension := range extensions {
+		ctx.disabledExtensions[extension] = true
+	}
 }
 
 // SetLogger sets the logger.
@@ -76,6 +143,15 @@ func (ctx *ContextMock) SetLogger(logger *zap.Logger) {
 	ctx.logger = logger
 }
 
+// SetPdfEngine sets the [gotenberg.PdfEngine] which counts the pages of the
+// PDF files.
+//
+//	ctx := &api.ContextMock{Context: &api.Context{}}
+//	ctx.SetPdfEngine(&gotenberg.PdfEngineMock{})
+func (ctx *ContextMock) SetPdfEngine(engine gotenberg.PdfEngine) {
+	ctx.pdfEngine = engine
+}
+
 // SetEchoContext sets the echo.Context.
 //
 //	ctx := &api.ContextMock{Context: &api.Context{}}
@@ -116,9 +192,39 @@ func (mod *HealthCheckerMock) Ready() error {
 	return mod.ReadyMock()
 }
 
+// ErrorReporterMock is a mock for the [ErrorReporter] interface.
+type ErrorReporterMock struct {
+	ReportErrorMock func(report ErrorReport)
+}
+
+func (mod *ErrorReporterMock) ReportError(report ErrorReport) {
+	mod.ReportErrorMock(report)
+}
+
+// TemplateProviderMock is a mock for the [TemplateProvider] interface.
+type TemplateProviderMock struct {
+	OpenTemplateMock func(ctx context.Context, id string) (string, io.ReadCloser, error)
+}
+
+func (mod *TemplateProviderMock) OpenTemplate(ctx context.Context, id string) (string, io.ReadCloser, error) {
+	return mod.OpenTemplateMock(ctx, id)
+}
+
+// AssetProviderMock is a mock for the [AssetProvider] interface.
+type AssetProviderMock struct {
+	OpenAssetMock func(ctx context.Context, checksum string) (io.ReadCloser, error)
+}
+
+func (mod *AssetProviderMock) OpenAsset(ctx context.Context, checksum string) (io.ReadCloser, error) {
+	return mod.OpenAssetMock(ctx, checksum)
+}
+
 // Interface guards.
 var (
 	_ Router             = (*RouterMock)(nil)
 	_ MiddlewareProvider = (*MiddlewareProviderMock)(nil)
 	_ HealthChecker      = (*HealthCheckerMock)(nil)
+	_ ErrorReporter      = (*ErrorReporterMock)(nil)
+	_ TemplateProvider   = (*TemplateProviderMock)(nil)
+	_ AssetProvider      = (*AssetProviderMock)(nil)
 )
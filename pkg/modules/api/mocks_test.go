@@ -1,12 +1,18 @@
 package api
 
 import (
+	"context"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alexliesenfeld/health"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
 
 func TestContextMock_SetDirPath(t *testing.T) {
@@ -65,6 +71,18 @@ func TestContextMock_SetFiles(t *testing.T) {
 	}
 }
 
+func TestContextMock_Warnings(t *testing.T) {
+	mock := &ContextMock{&Context{}}
+	mock.AddWarnings("foo")
+
+	actual := mock.Warnings()
+	expect := []string{"foo"}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %v but got %v", expect, actual)
+	}
+}
+
 func TestContextMock_SetCancelled(t *testing.T) {
 	mock := &ContextMock{&Context{}}
 	mock.SetCancelled(true)
@@ -76,6 +94,31 @@ func TestContextMock_SetCancelled(t *testing.T) {
 	}
 }
 
+func TestContextMock_SetTimeout(t *testing.T) {
+	mock := &ContextMock{&Context{}}
+	mock.SetTimeout(time.Duration(30) * time.Second)
+
+	actual := mock.timeout
+	expect := time.Duration(30) * time.Second
+
+	if actual != expect {
+		t.Errorf("expected %s but got %s", expect, actual)
+	}
+}
+
+func TestContextMock_SetDisabledExtensions(t *testing.T) {
+	mock := &ContextMock{&Context{}}
+	mock.SetDisabledExtensions(ExtensionRemoveBlankPages)
+
+	if mock.ExtensionEnabled(ExtensionRemoveBlankPages) {
+		t.Errorf("expected extension '%s' to be disabled", ExtensionRemoveBlankPages)
+	}
+
+	if !mock.ExtensionEnabled(ExtensionValidateOnly) {
+		t.Errorf("expected extension '%s' to be enabled", ExtensionValidateOnly)
+	}
+}
+
 func TestContextMock_OutputPaths(t *testing.T) {
 	mock := ContextMock{
 		&Context{
@@ -117,6 +160,17 @@ func TestContextMock_SetEchoContext(t *testing.T) {
 	}
 }
 
+func TestContextMock_SetPdfEngine(t *testing.T) {
+	mock := ContextMock{&Context{}}
+
+	expect := new(gotenberg.PdfEngineMock)
+	mock.SetPdfEngine(expect)
+
+	if mock.pdfEngine != expect {
+		t.Errorf("expected %v but got %v", expect, mock.pdfEngine)
+	}
+}
+
 func TestRouterMock(t *testing.T) {
 	mock := &RouterMock{
 		RoutesMock: func() ([]Route, error) {
@@ -163,3 +217,47 @@ func TestHealthCheckerMock(t *testing.T) {
 		t.Errorf("expected no error from HealthCheckerMock.Ready, but got: %v", err)
 	}
 }
+
+func TestErrorReporterMock(t *testing.T) {
+	var reported bool
+	mock := &ErrorReporterMock{
+		ReportErrorMock: func(report ErrorReport) {
+			reported = true
+		},
+	}
+
+	mock.ReportError(ErrorReport{})
+	if !reported {
+		t.Error("expected ErrorReporterMock.ReportError to be called")
+	}
+}
+
+func TestTemplateProviderMock(t *testing.T) {
+	mock := &TemplateProviderMock{
+		OpenTemplateMock: func(ctx context.Context, id string) (string, io.ReadCloser, error) {
+			return "foo.docx", io.NopCloser(strings.NewReader("foo")), nil
+		},
+	}
+
+	filename, _, err := mock.OpenTemplate(context.Background(), "foo")
+	if err != nil {
+		t.Errorf("expected no error from TemplateProviderMock.OpenTemplate, but got: %v", err)
+	}
+
+	if filename != "foo.docx" {
+		t.Errorf("expected filename 'foo.docx' from TemplateProviderMock.OpenTemplate, but got '%s'", filename)
+	}
+}
+
+func TestAssetProviderMock(t *testing.T) {
+	mock := &AssetProviderMock{
+		OpenAssetMock: func(ctx context.Context, checksum string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("foo")), nil
+		},
+	}
+
+	_, err := mock.OpenAsset(context.Background(), "foo")
+	if err != nil {
+		t.Errorf("expected no error from AssetProviderMock.OpenAsset, but got: %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// validateOnlyField is the form field which turns a request into a
+// pre-flight one.
+const validateOnlyField = "validateOnly"
+
+// ErrValidateOnly happens when the form data of a pre-flight request are
+// valid. The route stops before the conversion and the [Api] returns a
+// [PreflightReport] instead of the output files.
+var ErrValidateOnly = errors.New("validate only")
+
+// PreflightFile gathers information about a file a route would process.
+type PreflightFile struct {
+	Filename  string `json:"filename"`
+	Size      int64  `json:"size"`
+	PageCount int    `json:"pageCount,omitempty"`
+}
+
+// PreflightReport is the response of a pre-flight request, i.e., what the
+// route would process if the request was not a dry run.
+type PreflightReport struct {
+	// Files are the files the route would process.
+	Files []PreflightFile `json:"files"`
+
+	// IgnoredFiles are the files the route would not process, e.g., because
+	// of their extensions.
+	IgnoredFiles []string `json:"ignoredFiles,omitempty"`
+
+	// Fields are the form fields the route would use.
+	Fields []string `json:"fields"`
+
+	// UnknownFields are the form fields the route would not use, e.g.,
+	// because of a typo.
+	UnknownFields []string `json:"unknownFields,omitempty"`
+
+	// EstimatedPageCount is the total number of pages of the files, as
+	// estimated before the conversion. The pages of some files, e.g.,
+	// spreadsheets, are unknown before the conversion.
+	EstimatedPageCount int `json:"estimatedPageCount"`
+}
+
+// ValidateOnly tells if the request is a pre-flight one, i.e., if its
+// "validateOnly" form field is true.
+func (ctx *Context) ValidateOnly() bool {
+	return ctx.validateOnly
+}
+
+// parseValidateOnly reads the "validateOnly" form field.
+func (ctx *Context) parseValidateOnly() error {
+	val, ok := ctx.values[validateOnlyField]
+	if !ok || val[0] == "" {
+		return nil
+	}
+
+	validateOnly, err := strconv.ParseBool(val[0])
+	if err != nil {
+		err = fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", validateOnlyField, val[0], err)
+
+		return WrapError(
+			err,
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode(ErrorCodeInvalidFormData),
+		)
+	}
+
+	ctx.validateOnly = validateOnly
+
+	return nil
+}
+
+// bindField registers a form field used by the route.
+func (form *FormData) bindField(key string) {
+	if form.ctx == nil || form.ctx.boundFields == nil {
+		return
+	}
+
+	form.ctx.boundFields[key] = true
+}
+
+// bindFile registers a form file used by the route.
+func (form *FormData) bindFile(filename string) {
+	if form.ctx == nil || form.ctx.boundFiles == nil {
+		return
+	}
+
+	form.ctx.boundFiles[filename] = true
+}
+
+// PreflightReport returns the [PreflightReport] of the form fields and files
+// bound by the route.
+func (ctx *Context) PreflightReport() (PreflightReport, error) {
+	if ctx.cancelled {
+		return PreflightReport{}, ErrContextAlreadyClosed
+	}
+
+	report := PreflightReport{
+		Files:  make([]PreflightFile, 0),
+		Fields: make([]string, 0),
+	}
+
+	filenames := make([]string, 0, len(ctx.files))
+	for filename := range ctx.files {
+		filenames = append(filenames, filename)
+	}
+
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		if !ctx.boundFiles[filename] {
+			report.IgnoredFiles = append(report.IgnoredFiles, filename)
+
+			continue
+		}
+
+		path := ctx.files[filename]
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			return PreflightReport{}, fmt.Errorf("get stat from file: %w", err)
+		}
+
+		file := PreflightFile{
+			Filename:  filename,
+			Size:      stat.Size(),
+			PageCount: ctx.pageCount(filename, path),
+		}
+
+		report.Files = append(report.Files, file)
+		report.EstimatedPageCount += file.PageCount
+	}
+
+	keys := make([]string, 0, len(ctx.values))
+	for key := range ctx.values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if isApiField(key) {
+			// Handled by the API, not by the route.
+			continue
+		}
+
+		if ctx.boundFields[key] {
+			report.Fields = append(report.Fields, key)
+
+			continue
+		}
+
+		report.UnknownFields = append(report.UnknownFields, key)
+	}
+
+	return report, nil
+}
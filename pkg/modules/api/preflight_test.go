@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestContext_parseValidateOnly(t *testing.T) {
+	for _, tc := range []struct {
+		scenario           string
+		values             map[string][]string
+		expectValidateOnly bool
+		expectError        bool
+	}{
+		{
+			scenario: "no validateOnly form field",
+			values:   map[string][]string{},
+		},
+		{
+			scenario:    "invalid validateOnly form field",
+			values:      map[string][]string{"validateOnly": {"foo"}},
+			expectError: true,
+		},
+		{
+			scenario:           "validateOnly form field",
+			values:             map[string][]string{"validateOnly": {"true"}},
+			expectValidateOnly: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetValues(tc.values)
+
+			err := ctx.parseValidateOnly()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				var httpErr HttpError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("expected an HTTP error but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != http.StatusBadRequest {
+					t.Errorf("expected %d as HTTP status code but got %d", http.StatusBadRequest, status)
+				}
+			}
+
+			if ctx.ValidateOnly() != tc.expectValidateOnly {
+				t.Errorf("expected validate only %t but got %t", tc.expectValidateOnly, ctx.ValidateOnly())
+			}
+		})
+	}
+}
+
+func TestContext_PreflightReport(t *testing.T) {
+	dirPath := t.TempDir()
+	files := make(map[string]string)
+
+	for filename, content := range map[string]string{
+		"foo.pdf": "%PDF-1.7",
+		"bar.pdf": "%PDF-1.7",
+		"baz.txt": "baz",
+	} {
+		path := filepath.Join(dirPath, filename)
+
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		files[filename] = path
+	}
+
+	ctx := &ContextMock{Context: new(Context)}
+	ctx.SetDirPath(dirPath)
+	ctx.SetFiles(files)
+	ctx.SetValues(map[string][]string{
+		"validateOnly": {"true"},
+		"foo":          {"foo"},
+		"fooo":         {"foo"},
+	})
+	ctx.SetLogger(zap.NewNop())
+	ctx.SetValidateOnly(true)
+	ctx.Context.Context = context.Background()
+	ctx.pdfEngine = &gotenberg.PdfEngineMock{
+		PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+			if filepath.Base(inputPath) == "bar.pdf" {
+				return 0, errors.New("foo")
+			}
+
+			return 3, nil
+		},
+	}
+
+	var (
+		foo, bar string
+		paths    []string
+	)
+
+	err := ctx.FormData().
+		String("foo", &foo, "").
+		String("bar", &bar, "bar").
+		MandatoryPaths([]string{".pdf"}, &paths).
+		Validate()
+	if !errors.Is(err, ErrValidateOnly) {
+		t.Fatalf("expected error %v but got: %v", ErrValidateOnly, err)
+	}
+
+	actual, err := ctx.PreflightReport()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := PreflightReport{
+		Files: []PreflightFile{
+			{Filename: "bar.pdf", Size: 8},
+			{Filename: "foo.pdf", Size: 8, PageCount: 3},
+		},
+		IgnoredFiles:       []string{"baz.txt"},
+		Fields:             []string{"foo"},
+		UnknownFields:      []string{"fooo"},
+		EstimatedPageCount: 3,
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+
+	ctx.SetCancelled(true)
+
+	_, err = ctx.PreflightReport()
+	if !errors.Is(err, ErrContextAlreadyClosed) {
+		t.Errorf("expected error %v but got: %v", ErrContextAlreadyClosed, err)
+	}
+}
+
+func TestFormData_Validate_validateOnly(t *testing.T) {
+	ctx := &ContextMock{Context: new(Context)}
+	ctx.SetValues(map[string][]string{"foo": {"foo"}})
+	ctx.SetValidateOnly(true)
+
+	var foo int
+
+	err := ctx.FormData().Int("foo", &foo, 0).Validate()
+	if errors.Is(err, ErrValidateOnly) {
+		t.Fatal("expected an invalid form data error but got a pre-flight one")
+	}
+
+	var httpErr HttpError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("expected an HTTP error but got: %v", err)
+	}
+}
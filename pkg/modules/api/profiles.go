@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// profileField is the form field which selects a conversion profile.
+const profileField = "profile"
+
+// Profiles are named presets of form fields, e.g., "invoice-archive", which
+// the operators define in a JSON file:
+//
+//	{
+//	  "invoice-archive": {
+//	    "pdfa": "PDF/A-3b",
+//	    "pdfua": true,
+//	    "metadata": {"Producer": "ACME"}
+//	  }
+//	}
+//
+// The strings are the form values as is, the other JSON values are their
+// JSON representations, e.g., "true" or "{\"Producer\":\"ACME\"}".
+type Profiles map[string]map[string]string
+
+// UnmarshalJSON implements [json.Unmarshaler].
+func (p *Profiles) UnmarshalJSON(b []byte) error {
+	var raw map[string]map[string]json.RawMessage
+
+	err := json.Unmarshal(b, &raw)
+	if err != nil {
+		return err
+	}
+
+	profiles := make(Profiles, len(raw))
+
+	for name, fields := range raw {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("profile with an empty name")
+		}
+
+		profiles[name] = make(map[string]string, len(fields))
+
+		for key, value := range fields {
+			if key == profileField {
+				return fmt.Errorf("profile '%s' sets the '%s' form field", name, profileField)
+			}
+
+			var s string
+
+			err = json.Unmarshal(value, &s)
+			if err == nil {
+				profiles[name][key] = s
+
+				continue
+			}
+
+			var compact bytes.Buffer
+
+			err = json.Compact(&compact, value)
+			if err != nil {
+				return fmt.Errorf("profile '%s', form field '%s': %w", name, key, err)
+			}
+
+			profiles[name][key] = compact.String()
+		}
+	}
+
+	*p = profiles
+
+	return nil
+}
+
+// loadProfiles reads the [Profiles] from a JSON file. An empty path means no
+// profiles.
+func loadProfiles(path string) (Profiles, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read profiles file: %w", err)
+	}
+
+	var profiles Profiles
+
+	err = json.Unmarshal(b, &profiles)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal profiles file: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// applyProfile reads the "profile" form field and adds the form fields of
+// the selected profile to the values. The form fields of the request take
+// precedence, so that a client may still override a preset.
+func applyProfile(values map[string][]string, profiles Profiles) (string, error) {
+	val, ok := values[profileField]
+	if !ok || val[0] == "" {
+		return "", nil
+	}
+
+	name := val[0]
+
+	fields, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, fmt.Sprintf("'%s'", name))
+		}
+
+		sort.Strings(names)
+
+		expected := "no profiles are available"
+		if len(names) > 0 {
+			expected = fmt.Sprintf("expected either %s", strings.Join(names, ", "))
+		}
+
+		err := fmt.Errorf("form field '%s' is invalid (got '%s', %s)", profileField, name, expected)
+
+		return "", WrapError(
+			err,
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode(ErrorCodeInvalidFormData),
+		)
+	}
+
+	for key, value := range fields {
+		current, ok := values[key]
+		if ok && len(current) > 0 && current[0] != "" {
+			continue
+		}
+
+		values[key] = []string{value}
+	}
+
+	return name, nil
+}
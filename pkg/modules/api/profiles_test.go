@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		content        string
+		noFile         bool
+		expectProfiles Profiles
+		expectError    bool
+	}{
+		{
+			scenario:       "no profiles file",
+			noFile:         true,
+			expectProfiles: nil,
+		},
+		{
+			scenario: "profiles",
+			content: `{
+  "invoice-archive": {
+    "pdfa": "PDF/A-3b",
+    "pdfua": true,
+    "scale": 0.9,
+    "metadata": {"Producer": "ACME"}
+  },
+  "web-preview": {}
+}`,
+			expectProfiles: Profiles{
+				"invoice-archive": {
+					"pdfa":     "PDF/A-3b",
+					"pdfua":    "true",
+					"scale":    "0.9",
+					"metadata": `{"Producer":"ACME"}`,
+				},
+				"web-preview": {},
+			},
+		},
+		{
+			scenario:    "invalid JSON",
+			content:     `{"invoice-archive": [}`,
+			expectError: true,
+		},
+		{
+			scenario:    "profile with an empty name",
+			content:     `{" ": {"pdfua": true}}`,
+			expectError: true,
+		},
+		{
+			scenario:    "profile setting the profile form field",
+			content:     `{"invoice-archive": {"profile": "web-preview"}}`,
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := ""
+
+			if !tc.noFile {
+				path = filepath.Join(t.TempDir(), "profiles.json")
+
+				err := os.WriteFile(path, []byte(tc.content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			profiles, err := loadProfiles(path)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(profiles, tc.expectProfiles) {
+				t.Errorf("expected %v but got %v", tc.expectProfiles, profiles)
+			}
+		})
+	}
+
+	t.Run("non-existing profiles file", func(t *testing.T) {
+		_, err := loadProfiles("/foo/profiles.json")
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+}
+
+func TestApplyProfile(t *testing.T) {
+	profiles := Profiles{
+		"invoice-archive": {
+			"pdfa":  "PDF/A-3b",
+			"pdfua": "true",
+		},
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		values        map[string][]string
+		profiles      Profiles
+		expectProfile string
+		expectValues  map[string][]string
+		expectError   bool
+	}{
+		{
+			scenario:     "no profile form field",
+			values:       map[string][]string{"pdfua": {"false"}},
+			profiles:     profiles,
+			expectValues: map[string][]string{"pdfua": {"false"}},
+		},
+		{
+			scenario:     "empty profile form field",
+			values:       map[string][]string{"profile": {""}},
+			profiles:     profiles,
+			expectValues: map[string][]string{"profile": {""}},
+		},
+		{
+			scenario:    "unknown profile",
+			values:      map[string][]string{"profile": {"web-preview"}},
+			profiles:    profiles,
+			expectError: true,
+		},
+		{
+			scenario:    "no profiles",
+			values:      map[string][]string{"profile": {"invoice-archive"}},
+			expectError: true,
+		},
+		{
+			scenario:      "profile",
+			values:        map[string][]string{"profile": {"invoice-archive"}},
+			profiles:      profiles,
+			expectProfile: "invoice-archive",
+			expectValues: map[string][]string{
+				"profile": {"invoice-archive"},
+				"pdfa":    {"PDF/A-3b"},
+				"pdfua":   {"true"},
+			},
+		},
+		{
+			scenario:      "profile with overridden form fields",
+			values:        map[string][]string{"profile": {"invoice-archive"}, "pdfa": {""}, "pdfua": {"false"}},
+			profiles:      profiles,
+			expectProfile: "invoice-archive",
+			expectValues: map[string][]string{
+				"profile": {"invoice-archive"},
+				"pdfa":    {"PDF/A-3b"},
+				"pdfua":   {"false"},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			profile, err := applyProfile(tc.values, tc.profiles)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				var httpErr HttpError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("expected an HTTP error but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != http.StatusBadRequest {
+					t.Errorf("expected %d status code but got %d", http.StatusBadRequest, status)
+				}
+
+				return
+			}
+
+			if profile != tc.expectProfile {
+				t.Errorf("expected profile '%s' but got '%s'", tc.expectProfile, profile)
+			}
+
+			if !reflect.DeepEqual(tc.values, tc.expectValues) {
+				t.Errorf("expected %v but got %v", tc.expectValues, tc.values)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorReporter is a module interface which receives the errors of the
+// requests handled by the [Api], e.g., to forward them to an error tracking
+// service. Implementations should not block.
+type ErrorReporter interface {
+	ReportError(report ErrorReport)
+}
+
+// ErrorReport gathers the details of a failed request.
+type ErrorReport struct {
+	// Time is the moment the error has been reported.
+	Time time.Time `json:"time"`
+
+	// Trace is the request identifier.
+	Trace string `json:"trace"`
+
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+
+	// Route is the path of the matched route (e.g., /forms/chromium/convert/url).
+	Route string `json:"route"`
+
+	// Status, Code and Message are the details sent to the client.
+	Status  int    `json:"status"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	// Error is the detailed error message, as logged. It is not sanitized,
+	// e.g., it may contain the paths and the names of the files.
+	Error string `json:"error"`
+
+	// ErrorChain contains the error messages of the wrapped errors, from the
+	// outermost to the innermost. They are not sanitized either.
+	ErrorChain []string `json:"errorChain"`
+
+	// Options are the form fields of a "multipart/form-data" request.
+	Options map[string][]string `json:"options,omitempty"`
+
+	// Filenames are the names of the files of a "multipart/form-data"
+	// request. They are not sanitized.
+	Filenames []string `json:"filenames,omitempty"`
+
+	// Async tells if the error happened while processing the request in an
+	// asynchronous fashion (e.g., webhook).
+	Async bool `json:"async"`
+}
+
+// newErrorReport creates an [ErrorReport] from an error and the related
+// [echo.Context].
+func newErrorReport(c echo.Context, err error, async bool) ErrorReport {
+	response := ParseErrorResponse(err)
+
+	report := ErrorReport{
+		Time:    time.Now(),
+		Method:  c.Request().Method,
+		Route:   c.Path(),
+		Status:  response.Status,
+		Code:    response.Code,
+		Message: response.Message,
+		Error:   err.Error(),
+		Async:   async,
+	}
+
+	if report.Route == "" {
+		report.Route = c.Request().URL.Path
+	}
+
+	trace, ok := c.Get("trace").(string)
+	if ok {
+		report.Trace = trace
+	}
+
+	for wrapped := err; wrapped != nil; wrapped = errors.Unwrap(wrapped) {
+		report.ErrorChain = append(report.ErrorChain, wrapped.Error())
+	}
+
+	ctx, ok := c.Get("context").(*Context)
+	if ok {
+		report.Options = ctx.values
+
+		for filename := range ctx.files {
+			report.Filenames = append(report.Filenames, filename)
+		}
+
+		sort.Strings(report.Filenames)
+	}
+
+	return report
+}
+
+// ReportError sends the given error, which happened while processing the
+// request in an asynchronous fashion, to the [ErrorReporter] modules.
+func (ctx *Context) ReportError(err error) {
+	if err == nil || len(ctx.errorReporters) == 0 || ctx.echoCtx == nil {
+		return
+	}
+
+	report := newErrorReport(ctx.echoCtx, err, true)
+
+	for _, reporter := range ctx.errorReporters {
+		reporter.ReportError(report)
+	}
+}
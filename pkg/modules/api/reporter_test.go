@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestNewErrorReport(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/forms/foo", nil)
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.Set("trace", "foo")
+	c.Set("context", &Context{
+		values: map[string][]string{"foo": {"bar"}},
+		files:  map[string]string{"foo.docx": "/tmp/foo.docx", "bar.docx": "/tmp/bar.docx"},
+	})
+
+	err := fmt.Errorf("convert: %w", WrapError(
+		errors.New("foo"),
+		NewSentinelHttpError(http.StatusBadRequest, "Invalid foo").WithCode("FOO"),
+	))
+
+	report := newErrorReport(c, err, false)
+
+	if report.Trace != "foo" {
+		t.Errorf("expected trace 'foo' but got '%s'", report.Trace)
+	}
+
+	if report.Method != http.MethodPost || report.Route != "/forms/foo" {
+		t.Errorf("expected 'POST /forms/foo' but got '%s %s'", report.Method, report.Route)
+	}
+
+	if report.Status != http.StatusBadRequest || report.Code != "FOO" || report.Message != "Invalid foo" {
+		t.Errorf("expected status, code and message from the sentinel error but got %d, '%s' and '%s'", report.Status, report.Code, report.Message)
+	}
+
+	expectChain := []string{"convert: foo", "foo"}
+	if !reflect.DeepEqual(report.ErrorChain, expectChain) {
+		t.Errorf("expected %v but got %v", expectChain, report.ErrorChain)
+	}
+
+	expectFilenames := []string{"bar.docx", "foo.docx"}
+	if !reflect.DeepEqual(report.Filenames, expectFilenames) {
+		t.Errorf("expected %v but got %v", expectFilenames, report.Filenames)
+	}
+
+	if !reflect.DeepEqual(report.Options, map[string][]string{"foo": {"bar"}}) {
+		t.Errorf("expected options from the context but got %v", report.Options)
+	}
+}
+
+func TestContext_ReportError(t *testing.T) {
+	var reports []ErrorReport
+	reporter := &ErrorReporterMock{
+		ReportErrorMock: func(report ErrorReport) {
+			reports = append(reports, report)
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/forms/foo", nil)
+	ctx := &Context{
+		errorReporters: []ErrorReporter{reporter},
+		echoCtx:        echo.New().NewContext(req, httptest.NewRecorder()),
+	}
+
+	ctx.ReportError(nil)
+	ctx.ReportError(errors.New("foo"))
+
+	if len(reports) != 1 {
+		t.Fatalf("expected 1 report but got %d", len(reports))
+	}
+
+	if !reports[0].Async {
+		t.Error("expected an asynchronous report")
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+const (
+	// PeakMemoryHeader is the response header with the peak resident set
+	// size, in bytes, of the processes of the request, if accounted.
+	PeakMemoryHeader = "Gotenberg-Peak-Memory"
+
+	// PeakScratchSizeHeader is the response header with the peak size, in
+	// bytes, of the working directory of the request, if accounted.
+	PeakScratchSizeHeader = "Gotenberg-Peak-Scratch-Size"
+)
+
+// ResourceUsage is the accounting of the resources of a request. It is
+// reported to the client with the output metadata.
+type ResourceUsage struct {
+	// PeakMemoryBytes is the peak resident set size of the processes the
+	// request ran, e.g., a PDF engine. The long-lived processes shared
+	// between requests, e.g., Chromium, do not count.
+	PeakMemoryBytes int64 `json:"peakMemoryBytes"`
+
+	// PeakScratchBytes is the peak size of the working directory of the
+	// request.
+	PeakScratchBytes int64 `json:"peakScratchBytes"`
+}
+
+// SetResourceUsage registers the function which reads the accounting of the
+// resources of the request, as it changes until the response.
+func (ctx *Context) SetResourceUsage(read func() ResourceUsage) {
+	ctx.metadataMu.Lock()
+	defer ctx.metadataMu.Unlock()
+
+	ctx.resourceUsage = read
+}
+
+// ScratchSize returns the size, in bytes, of the files within the working
+// directory of the request.
+func (ctx *Context) ScratchSize() (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(ctx.dirPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// The conversions may remove a file while walking.
+			if path != ctx.dirPath {
+				return nil
+			}
+
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("walk working directory: %w", err)
+	}
+
+	return size, nil
+}
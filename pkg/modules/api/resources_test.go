@@ -0,0 +1,76 @@
+package api
+
+import (
+	"os"
+	"testing"
+)
+
+func TestContext_ScratchSize(t *testing.T) {
+	t.Run("non-existing directory", func(t *testing.T) {
+		ctx := &Context{dirPath: "/foo"}
+
+		_, err := ctx.ScratchSize()
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		dirPath := t.TempDir()
+
+		err := os.MkdirAll(dirPath+"/bar", 0o755)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		for path, content := range map[string]string{
+			dirPath + "/foo.txt":     "foo",
+			dirPath + "/bar/baz.txt": "bazbaz",
+		} {
+			err = os.WriteFile(path, []byte(content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
+		ctx := &Context{dirPath: dirPath}
+
+		size, err := ctx.ScratchSize()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if size != 9 {
+			t.Errorf("expected 9 but got %d", size)
+		}
+	})
+}
+
+func TestContext_SetResourceUsage(t *testing.T) {
+	ctx := &Context{}
+
+	metadata, err := ctx.Metadata()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if metadata.Resources != nil {
+		t.Errorf("expected no resource usage but got %+v", metadata.Resources)
+	}
+
+	var peak int64
+	ctx.SetResourceUsage(func() ResourceUsage {
+		return ResourceUsage{PeakMemoryBytes: peak}
+	})
+
+	peak = 2048
+
+	metadata, err = ctx.Metadata()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if metadata.Resources == nil || metadata.Resources.PeakMemoryBytes != 2048 {
+		t.Errorf("expected a peak memory of 2048 but got %+v", metadata.Resources)
+	}
+}
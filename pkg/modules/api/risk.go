@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+)
+
+// RiskScoreHeader is the response header with the risk score of the input
+// files, if assessed.
+const RiskScoreHeader = "Gotenberg-Risk-Score"
+
+// RiskFinding is something suspicious about an input file, e.g., a macro.
+type RiskFinding struct {
+	// Filename is the name of the input file.
+	Filename string `json:"filename"`
+
+	// Kind is the kind of finding, e.g., "macro" or "executable".
+	Kind string `json:"kind"`
+
+	// Detail describes the finding, e.g., the name of an archive entry.
+	Detail string `json:"detail,omitempty"`
+
+	// Score is the weight of the finding in the risk score.
+	Score int `json:"score"`
+}
+
+// RiskReport is the assessment of the input files of a request. It is
+// reported to the client with the output metadata.
+type RiskReport struct {
+	// Score is the sum of the scores of the findings.
+	Score int `json:"score"`
+
+	// Quarantined tells if the conversions of the request run in isolated
+	// processes.
+	Quarantined bool `json:"quarantined"`
+
+	// Findings are the suspicious things about the input files.
+	Findings []RiskFinding `json:"findings,omitempty"`
+}
+
+// quarantineKey is the key of the [Context] value telling if the request is
+// quarantined. See [Quarantined].
+type quarantineKey struct{}
+
+// SetRiskReport registers the assessment of the input files.
+func (ctx *Context) SetRiskReport(report RiskReport) {
+	ctx.metadataMu.Lock()
+	defer ctx.metadataMu.Unlock()
+
+	ctx.riskReport = &report
+}
+
+// RiskReport returns the assessment of the input files, if any.
+func (ctx *Context) RiskReport() *RiskReport {
+	ctx.metadataMu.Lock()
+	defer ctx.metadataMu.Unlock()
+
+	return ctx.riskReport
+}
+
+// Value returns the value associated with the key. It also tells if the
+// request is quarantined, for the contexts derived from the [Context].
+func (ctx *Context) Value(key any) any {
+	_, ok := key.(quarantineKey)
+	if ok {
+		report := ctx.RiskReport()
+
+		return report != nil && report.Quarantined
+	}
+
+	if ctx.Context == nil {
+		return nil
+	}
+
+	return ctx.Context.Value(key)
+}
+
+// Quarantined tells if the inputs of a request are suspicious enough to run
+// its conversions in isolated processes. The context is either a [Context]
+// or derived from one.
+func Quarantined(ctx context.Context) bool {
+	quarantined, _ := ctx.Value(quarantineKey{}).(bool)
+
+	return quarantined
+}
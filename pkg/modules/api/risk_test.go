@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+type testKey struct{}
+
+func TestQuarantined(t *testing.T) {
+	ctx := &Context{Context: context.WithValue(context.Background(), testKey{}, "foo")}
+
+	if Quarantined(ctx) {
+		t.Error("expected a context without risk report not to be quarantined")
+	}
+
+	ctx.SetRiskReport(RiskReport{Score: 10})
+	if Quarantined(ctx) {
+		t.Error("expected a context with a low risk not to be quarantined")
+	}
+
+	ctx.SetRiskReport(RiskReport{Score: 100, Quarantined: true})
+
+	derived, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if !Quarantined(derived) {
+		t.Error("expected a derived context to be quarantined")
+	}
+
+	if derived.Value(testKey{}) != "foo" {
+		t.Errorf("expected the other values to pass through but got %v", derived.Value(testKey{}))
+	}
+
+	if ctx.RiskReport().Score != 100 {
+		t.Errorf("expected score 100 but got %d", ctx.RiskReport().Score)
+	}
+
+	if Quarantined(context.Background()) {
+		t.Error("expected a background context not to be quarantined")
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	// FileTypeMismatchReject rejects the uploaded files whose content does
+	// not match their extension.
+	FileTypeMismatchReject = "reject"
+
+	// FileTypeMismatchCorrect renames the uploaded files whose content does
+	// not match their extension, if the actual extension is known.
+	FileTypeMismatchCorrect = "correct"
+
+	// FileTypeMismatchIgnore keeps the uploaded files as they are.
+	FileTypeMismatchIgnore = "ignore"
+)
+
+// sniffLen is the number of bytes read to detect the type of a file. The PDF
+// header may follow some leading whitespace.
+const sniffLen = 1024
+
+// utf8Bom is the byte order mark some editors write at the start of a file.
+var utf8Bom = []byte("\xEF\xBB\xBF")
+
+// fileType is a file type detected from its magic bytes.
+type fileType struct {
+	name      string
+	magic     []byte
+	extension string
+
+	// leadingSpace tells if the magic bytes may follow a byte order mark or
+	// some whitespace.
+	leadingSpace bool
+}
+
+// fileTypes are the detectable file types. Only the mismatches involving a
+// PDF are handled: the other engines (LibreOffice, ImageMagick, etc.) sniff
+// the content of the files on their own, but a PDF renamed to, say, .docx
+// makes LibreOffice fail cryptically, and a non-PDF file renamed to .pdf
+// makes the PDF engines fail.
+var fileTypes = []fileType{
+	{name: "PDF", magic: []byte("%PDF-"), extension: ".pdf", leadingSpace: true},
+	{name: "ZIP", magic: []byte("PK\x03\x04")},
+	{name: "OLE2", magic: []byte("\xD0\xCF\x11\xE0\xA1\xB1\x1A\xE1")},
+	{name: "PNG", magic: []byte("\x89PNG\r\n\x1a\n"), extension: ".png"},
+	{name: "JPEG", magic: []byte("\xFF\xD8\xFF"), extension: ".jpg"},
+	{name: "GIF", magic: []byte("GIF8"), extension: ".gif"},
+	{name: "TIFF", magic: []byte("II*\x00"), extension: ".tiff"},
+	{name: "TIFF", magic: []byte("MM\x00*"), extension: ".tiff"},
+}
+
+// detectFileType returns the type of a file from its magic bytes, if known.
+func detectFileType(path string) (fileType, bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileType{}, false, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return fileType{}, false, fmt.Errorf("read file: %w", err)
+	}
+
+	head = head[:n]
+
+	for _, ft := range fileTypes {
+		if bytes.HasPrefix(head, ft.magic) {
+			return ft, true, nil
+		}
+	}
+
+	// Only the leading whitespace is skipped: a text file which merely
+	// mentions a PDF header is not a PDF.
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, utf8Bom), " \t\r\n\f\x00")
+
+	for _, ft := range fileTypes {
+		if ft.leadingSpace && bytes.HasPrefix(head, ft.magic) {
+			return ft, true, nil
+		}
+	}
+
+	return fileType{}, false, nil
+}
+
+// fileTypeMismatch returns the detected type of a file if its content does
+// not match its extension, i.e., a PDF without the .pdf extension, or a
+// .pdf file with another known type.
+func fileTypeMismatch(filename, path string) (fileType, bool, error) {
+	ft, ok, err := detectFileType(path)
+	if err != nil || !ok {
+		return fileType{}, false, err
+	}
+
+	isPdfExt := strings.EqualFold(filepath.Ext(filename), ".pdf")
+	isPdf := ft.extension == ".pdf"
+
+	if isPdf == isPdfExt {
+		return fileType{}, false, nil
+	}
+
+	return ft, true, nil
+}
+
+// checkFileTypes applies the given policy to the uploaded files whose
+// content does not match their extension.
+func (ctx *Context) checkFileTypes(policy string) error {
+	if policy == "" || policy == FileTypeMismatchIgnore {
+		return nil
+	}
+
+	filenames := make([]string, 0, len(ctx.files))
+	for filename := range ctx.files {
+		filenames = append(filenames, filename)
+	}
+
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		path := ctx.files[filename]
+
+		ft, mismatch, err := fileTypeMismatch(filename, path)
+		if err != nil {
+			return fmt.Errorf("detect type of '%s': %w", filename, err)
+		}
+
+		if !mismatch {
+			continue
+		}
+
+		correctedFilename := strings.TrimSuffix(filename, filepath.Ext(filename)) + ft.extension
+		_, exists := ctx.files[correctedFilename]
+
+		if policy == FileTypeMismatchReject || ft.extension == "" || exists {
+			return WrapError(
+				fmt.Errorf("'%s' is a %s file", filename, ft.name),
+				NewSentinelHttpError(
+					http.StatusBadRequest,
+					fmt.Sprintf("The content of the file '%s' does not match its extension: it is a %s file", filename, ft.name),
+				).WithCode(ErrorCodeFileTypeMismatch),
+			)
+		}
+
+		correctedPath := filepath.Join(ctx.dirPath, correctedFilename)
+
+		err = os.Rename(path, correctedPath)
+		if err != nil {
+			return fmt.Errorf("rename '%s' to '%s': %w", filename, correctedFilename, err)
+		}
+
+		delete(ctx.files, filename)
+		ctx.files[correctedFilename] = correctedPath
+
+		ctx.Log().Debug(fmt.Sprintf("'%s' is a %s file, renamed to '%s'", filename, ft.name, correctedFilename))
+	}
+
+	return nil
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestFileTypeMismatch(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		filename       string
+		content        string
+		expectMismatch bool
+		expectType     string
+	}{
+		{
+			scenario: "PDF with the .pdf extension",
+			filename: "foo.PDF",
+			content:  "%PDF-1.7",
+		},
+		{
+			scenario:       "PDF with the .docx extension",
+			filename:       "foo.docx",
+			content:        "%PDF-1.7",
+			expectMismatch: true,
+			expectType:     "PDF",
+		},
+		{
+			scenario:       "PDF header after a byte order mark and whitespace",
+			filename:       "foo.docx",
+			content:        "\xEF\xBB\xBF\r\n %PDF-1.7",
+			expectMismatch: true,
+			expectType:     "PDF",
+		},
+		{
+			scenario: "text file mentioning a PDF header",
+			filename: "foo.txt",
+			content:  "A PDF starts with %PDF-1.7",
+		},
+		{
+			scenario: "ZIP with a PDF header",
+			filename: "foo.docx",
+			content:  "PK\x03\x04%PDF-1.7",
+		},
+		{
+			scenario:       "ZIP with the .pdf extension",
+			filename:       "foo.pdf",
+			content:        "PK\x03\x04",
+			expectMismatch: true,
+			expectType:     "ZIP",
+		},
+		{
+			scenario:       "PNG with the .pdf extension",
+			filename:       "foo.pdf",
+			content:        "\x89PNG\r\n\x1a\n",
+			expectMismatch: true,
+			expectType:     "PNG",
+		},
+		{
+			scenario: "PNG with the .jpg extension",
+			filename: "foo.jpg",
+			content:  "\x89PNG\r\n\x1a\n",
+		},
+		{
+			scenario: "unknown type with the .pdf extension",
+			filename: "foo.pdf",
+			content:  "<html></html>",
+		},
+		{
+			scenario: "empty file",
+			filename: "foo.txt",
+			content:  "",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			ft, mismatch, err := fileTypeMismatch(tc.filename, path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if mismatch != tc.expectMismatch {
+				t.Errorf("expected mismatch %t but got %t", tc.expectMismatch, mismatch)
+			}
+
+			if ft.name != tc.expectType {
+				t.Errorf("expected type '%s' but got '%s'", tc.expectType, ft.name)
+			}
+		})
+	}
+
+	_, _, err := fileTypeMismatch("foo.pdf", "/foo/foo.pdf")
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+}
+
+func TestContext_checkFileTypes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		policy           string
+		files            map[string]string
+		expectError      bool
+		expectHttpStatus int
+		expectFilenames  []string
+	}{
+		{
+			scenario:        "ignore",
+			policy:          FileTypeMismatchIgnore,
+			files:           map[string]string{"foo.docx": "%PDF-1.7"},
+			expectFilenames: []string{"foo.docx"},
+		},
+		{
+			scenario:         "reject",
+			policy:           FileTypeMismatchReject,
+			files:            map[string]string{"foo.docx": "%PDF-1.7", "bar.pdf": "%PDF-1.7"},
+			expectError:      true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:        "correct",
+			policy:          FileTypeMismatchCorrect,
+			files:           map[string]string{"foo.docx": "%PDF-1.7", "bar.pdf": "%PDF-1.7"},
+			expectFilenames: []string{"bar.pdf", "foo.pdf"},
+		},
+		{
+			scenario:         "correct with an unknown extension",
+			policy:           FileTypeMismatchCorrect,
+			files:            map[string]string{"foo.pdf": "PK\x03\x04"},
+			expectError:      true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "correct with an existing file",
+			policy:           FileTypeMismatchCorrect,
+			files:            map[string]string{"foo.docx": "%PDF-1.7", "foo.pdf": "%PDF-1.7"},
+			expectError:      true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			files := make(map[string]string)
+
+			for filename, content := range tc.files {
+				path := filepath.Join(dirPath, filename)
+
+				err := os.WriteFile(path, []byte(content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				files[filename] = path
+			}
+
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetDirPath(dirPath)
+			ctx.SetFiles(files)
+			ctx.SetLogger(zap.NewNop())
+
+			err := ctx.checkFileTypes(tc.policy)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				var httpErr HttpError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("expected an HTTP error but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+
+				return
+			}
+
+			var actual []string
+			for _, path := range ctx.InputPaths() {
+				_, err := os.Stat(path)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				actual = append(actual, filepath.Base(path))
+			}
+
+			if !reflect.DeepEqual(actual, tc.expectFilenames) {
+				t.Errorf("expected %+v but got %+v", tc.expectFilenames, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+const (
+	// DiskStorage is the storage backend which uses the system's temporary
+	// directory.
+	DiskStorage = "disk"
+
+	// TmpfsStorage is the storage backend which uses a directory on a
+	// dedicated tmpfs mount, e.g., "docker run --tmpfs /tmp/gotenberg".
+	TmpfsStorage = "tmpfs"
+
+	// MemoryStorage is the storage backend which uses the shared memory of
+	// the system, i.e., "/dev/shm" on Linux.
+	MemoryStorage = "memory"
+)
+
+// ScratchRemover is a module interface which removes the working directory of
+// a request once handled, e.g., after a retention period or by overwriting its
+// files first. Without such a module, the [Api] removes it immediately.
+type ScratchRemover interface {
+	RemoveScratch(dirPath string) error
+}
+
+// parseStorageRoutes parses the "path=backend" entries which select a
+// storage backend for the routes starting with the given path.
+func parseStorageRoutes(entries []string) (map[string]string, error) {
+	routes := make(map[string]string, len(entries))
+
+	for _, entry := range entries {
+		path, backend, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(path) == "" || strings.TrimSpace(backend) == "" {
+			return nil, fmt.Errorf("invalid storage route '%s': expected 'path=backend'", entry)
+		}
+
+		routes[strings.TrimSpace(path)] = strings.TrimSpace(backend)
+	}
+
+	return routes, nil
+}
+
+// validateStorage checks that a storage backend is known and usable.
+func (a *Api) validateStorage(backend string) error {
+	switch backend {
+	case DiskStorage:
+		return nil
+	case TmpfsStorage:
+		if a.storageTmpfsDir == "" {
+			return errors.New("tmpfs storage requires a tmpfs directory")
+		}
+
+		return nil
+	case MemoryStorage:
+		stat, err := os.Stat(a.storageMemoryDir)
+		if err != nil {
+			return fmt.Errorf("memory storage directory: %w", err)
+		}
+
+		if !stat.IsDir() {
+			return fmt.Errorf("memory storage directory '%s' is not a directory", a.storageMemoryDir)
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("storage backend '%s' is not '%s', '%s' or '%s'", backend, DiskStorage, TmpfsStorage, MemoryStorage)
+	}
+}
+
+// routeStorage returns the storage backend of a route, i.e., the one of the
+// longest matching path from the storage routes or the default one.
+func (a *Api) routeStorage(path string) gotenberg.Storage {
+	backend := a.storage
+	longest := -1
+
+	for prefix, b := range a.storageRoutes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			backend = b
+			longest = len(prefix)
+		}
+	}
+
+	storage, ok := a.storages[backend]
+	if !ok {
+		return a.fs
+	}
+
+	return storage
+}
+
+// freeSpace returns the available bytes and inodes of the file system of the
+// given path. The available inodes are -1 if the file system does not limit
+// them (e.g., some tmpfs or overlay mounts).
+func freeSpace(path string) (int64, int64, error) {
+	var stat syscall.Statfs_t
+
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return 0, 0, fmt.Errorf("get file system statistics of '%s': %w", path, err)
+	}
+
+	inodes := int64(-1)
+	if stat.Files > 0 {
+		inodes = int64(stat.Ffree)
+	}
+
+	return int64(stat.Bavail) * int64(stat.Bsize), inodes, nil
+}
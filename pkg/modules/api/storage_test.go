@@ -0,0 +1,177 @@
+package api
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestParseStorageRoutes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		entries      []string
+		expectRoutes map[string]string
+		expectError  bool
+	}{
+		{
+			scenario:     "no entries",
+			entries:      nil,
+			expectRoutes: map[string]string{},
+		},
+		{
+			scenario:    "missing backend",
+			entries:     []string{"/forms/chromium"},
+			expectError: true,
+		},
+		{
+			scenario:    "empty path",
+			entries:     []string{"=memory"},
+			expectError: true,
+		},
+		{
+			scenario: "valid entries",
+			entries:  []string{"/forms/chromium=memory", " /forms/libreoffice = disk "},
+			expectRoutes: map[string]string{
+				"/forms/chromium":    MemoryStorage,
+				"/forms/libreoffice": DiskStorage,
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			routes, err := parseStorageRoutes(tc.entries)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && !reflect.DeepEqual(routes, tc.expectRoutes) {
+				t.Errorf("expected %v but got %v", tc.expectRoutes, routes)
+			}
+		})
+	}
+}
+
+func TestApi_validateStorage(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		backend          string
+		storageTmpfsDir  string
+		storageMemoryDir string
+		expectError      bool
+	}{
+		{
+			scenario: "disk",
+			backend:  DiskStorage,
+		},
+		{
+			scenario:    "tmpfs without directory",
+			backend:     TmpfsStorage,
+			expectError: true,
+		},
+		{
+			scenario:        "tmpfs",
+			backend:         TmpfsStorage,
+			storageTmpfsDir: "/tmp",
+		},
+		{
+			scenario:         "memory with non-existing directory",
+			backend:          MemoryStorage,
+			storageMemoryDir: "/foo",
+			expectError:      true,
+		},
+		{
+			scenario:         "memory",
+			backend:          MemoryStorage,
+			storageMemoryDir: t.TempDir(),
+		},
+		{
+			scenario:    "unknown backend",
+			backend:     "foo",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Api{
+				storageTmpfsDir:  tc.storageTmpfsDir,
+				storageMemoryDir: tc.storageMemoryDir,
+			}
+
+			err := mod.validateStorage(tc.backend)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestApi_routeStorage(t *testing.T) {
+	disk := gotenberg.NewFileSystem()
+	memory := gotenberg.NewFileSystemAt("/dev/shm")
+	tmpfs := gotenberg.NewFileSystemAt("/mnt/tmpfs")
+
+	mod := &Api{
+		storage: DiskStorage,
+		storageRoutes: map[string]string{
+			"/forms/chromium":               MemoryStorage,
+			"/forms/chromium/convert/html":  TmpfsStorage,
+			"/forms/libreoffice/convert":    DiskStorage,
+			"/forms/pdfengines/not/enabled": "foo",
+		},
+		fs: disk,
+		storages: map[string]gotenberg.Storage{
+			DiskStorage:   disk,
+			MemoryStorage: memory,
+			TmpfsStorage:  tmpfs,
+		},
+	}
+
+	for _, tc := range []struct {
+		path   string
+		expect gotenberg.Storage
+	}{
+		{path: "/forms/chromium/convert/url", expect: memory},
+		{path: "/forms/chromium/convert/html", expect: tmpfs},
+		{path: "/forms/libreoffice/convert", expect: disk},
+		{path: "/forms/pdfengines/merge", expect: disk},
+		{path: "/forms/pdfengines/not/enabled", expect: disk},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			actual := mod.routeStorage(tc.path)
+
+			if actual != tc.expect {
+				t.Errorf("expected storage at '%s' but got '%s'", tc.expect.WorkingDirPath(), actual.WorkingDirPath())
+			}
+		})
+	}
+}
+
+func TestFreeSpace(t *testing.T) {
+	_, _, err := freeSpace("/foo")
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	space, inodes, err := freeSpace(os.TempDir())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if space <= 0 {
+		t.Errorf("expected available space but got %d", space)
+	}
+
+	if inodes == 0 || inodes < -1 {
+		t.Errorf("expected available inodes or -1 but got %d", inodes)
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// templateIdField is the form field which references stored templates.
+const templateIdField = "templateId"
+
+// ErrTemplateNotFound happens if there is no stored template with a given ID.
+var ErrTemplateNotFound = errors.New("template not found")
+
+// TemplateProvider is a module interface which provides the stored templates,
+// e.g., a 5 MB DOCX template, that the requests reference with the
+// "templateId" form field instead of uploading them again and again.
+type TemplateProvider interface {
+	// OpenTemplate returns the filename and the content of a template. It
+	// returns [ErrTemplateNotFound] if there is no template with the given
+	// ID.
+	OpenTemplate(ctx context.Context, id string) (string, io.ReadCloser, error)
+}
+
+// addTemplates reads the "templateId" form fields and copies the referenced
+// templates into the working directory, as if the client had uploaded them.
+func (ctx *Context) addTemplates(provider TemplateProvider) error {
+	var ids []string
+
+	for _, value := range ctx.values[templateIdField] {
+		for _, id := range strings.Split(value, ",") {
+			id = strings.TrimSpace(id)
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	invalid := func(format string, a ...any) error {
+		err := fmt.Errorf("form field '%s' is invalid (%s)", templateIdField, fmt.Sprintf(format, a...))
+
+		return WrapError(
+			err,
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode(ErrorCodeInvalidFormData),
+		)
+	}
+
+	if provider == nil {
+		return invalid("no template store is available")
+	}
+
+	for _, id := range ids {
+		err := ctx.addTemplate(provider, id)
+		if err != nil {
+			if errors.Is(err, ErrTemplateNotFound) {
+				return invalid("got '%s', no such template", id)
+			}
+
+			if errors.Is(err, os.ErrExist) {
+				return invalid("template '%s' has the filename of another file", id)
+			}
+
+			return fmt.Errorf("add template '%s': %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// addTemplate copies a template into the working directory.
+func (ctx *Context) addTemplate(provider TemplateProvider, id string) error {
+	filename, in, err := provider.OpenTemplate(ctx, id)
+	if err != nil {
+		return fmt.Errorf("open template: %w", err)
+	}
+
+	defer func() {
+		err := in.Close()
+		if err != nil {
+			ctx.Log().Error(fmt.Sprintf("close template: %s", err))
+		}
+	}()
+
+	return ctx.addInputFile(filename, in)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestContext_addTemplates(t *testing.T) {
+	provider := &TemplateProviderMock{
+		OpenTemplateMock: func(ctx context.Context, id string) (string, io.ReadCloser, error) {
+			switch id {
+			case "invoice":
+				return "invoice.docx", io.NopCloser(strings.NewReader("invoice")), nil
+			case "header":
+				return "../header.html", io.NopCloser(strings.NewReader("header")), nil
+			default:
+				return "", nil, ErrTemplateNotFound
+			}
+		},
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		values        map[string][]string
+		files         []string
+		provider      TemplateProvider
+		expectFiles   []string
+		expectStatus  int
+		expectError   bool
+		expectContent map[string]string
+	}{
+		{
+			scenario:    "no template",
+			values:      map[string][]string{},
+			files:       []string{"data.json"},
+			provider:    provider,
+			expectFiles: []string{"data.json"},
+		},
+		{
+			scenario:     "no template store",
+			values:       map[string][]string{"templateId": {"invoice"}},
+			expectStatus: http.StatusBadRequest,
+			expectError:  true,
+		},
+		{
+			scenario:     "unknown template",
+			values:       map[string][]string{"templateId": {"foo"}},
+			provider:     provider,
+			expectStatus: http.StatusBadRequest,
+			expectError:  true,
+		},
+		{
+			scenario:     "same filename as an uploaded file",
+			values:       map[string][]string{"templateId": {"invoice"}},
+			files:        []string{"invoice.docx"},
+			provider:     provider,
+			expectStatus: http.StatusBadRequest,
+			expectError:  true,
+		},
+		{
+			scenario: "many templates",
+			values:   map[string][]string{"templateId": {"invoice, header"}},
+			files:    []string{"data.json"},
+			provider: provider,
+			expectFiles: []string{
+				"data.json",
+				"header.html",
+				"invoice.docx",
+			},
+			expectContent: map[string]string{
+				"header.html":  "header",
+				"invoice.docx": "invoice",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+
+			files := make(map[string]string)
+			for _, filename := range tc.files {
+				files[filename] = dirPath + "/" + filename
+			}
+
+			ctx := &ContextMock{Context: new(Context)}
+			ctx.SetDirPath(dirPath)
+			ctx.SetValues(tc.values)
+			ctx.SetFiles(files)
+			ctx.SetLogger(zap.NewNop())
+
+			err := ctx.addTemplates(tc.provider)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				status := ParseErrorResponse(err).Status
+				if status != tc.expectStatus {
+					t.Errorf("expected status %d but got %d", tc.expectStatus, status)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var filenames []string
+			for filename := range ctx.files {
+				filenames = append(filenames, filename)
+			}
+
+			sort.Strings(filenames)
+			if !reflect.DeepEqual(filenames, tc.expectFiles) {
+				t.Errorf("expected files %v but got %v", tc.expectFiles, filenames)
+			}
+
+			for filename, expect := range tc.expectContent {
+				b, err := os.ReadFile(dirPath + "/" + filename)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				if string(b) != expect {
+					t.Errorf("expected content '%s' for '%s' but got '%s'", expect, filename, string(b))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// processTimeoutField is the form field which overrides the time limit of a
+// request.
+const processTimeoutField = "processTimeout"
+
+// parseProcessTimeout reads the "processTimeout" form field. It returns the
+// default timeout if the field is empty. A request may ask for a shorter
+// time limit (e.g., interactive usages failing fast), or for a longer one up
+// to the maximum timeout (e.g., batch jobs). A maximum timeout of zero means
+// the default timeout is also the maximum.
+func parseProcessTimeout(values map[string][]string, timeout, maxTimeout time.Duration) (time.Duration, error) {
+	val, ok := values[processTimeoutField]
+	if !ok || val[0] == "" {
+		return timeout, nil
+	}
+
+	if maxTimeout <= 0 {
+		maxTimeout = timeout
+	}
+
+	processTimeout, err := time.ParseDuration(val[0])
+	if err != nil {
+		err = fmt.Errorf("form field '%s' is invalid (got '%s', resulting to %w)", processTimeoutField, val[0], err)
+	} else if processTimeout <= 0 {
+		err = fmt.Errorf("form field '%s' is invalid (got '%s', must be more than 0)", processTimeoutField, val[0])
+	} else if processTimeout > maxTimeout {
+		err = fmt.Errorf("form field '%s' is invalid (got '%s', must be at most %s)", processTimeoutField, val[0], maxTimeout)
+	}
+
+	if err != nil {
+		return 0, WrapError(
+			err,
+			NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err)).WithCode(ErrorCodeInvalidFormData),
+		)
+	}
+
+	return processTimeout, nil
+}
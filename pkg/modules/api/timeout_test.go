@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseProcessTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		values        map[string][]string
+		maxTimeout    time.Duration
+		expectTimeout time.Duration
+		expectError   bool
+	}{
+		{
+			scenario:      "no processTimeout form field",
+			values:        map[string][]string{},
+			expectTimeout: time.Duration(30) * time.Second,
+		},
+		{
+			scenario:      "empty processTimeout form field",
+			values:        map[string][]string{"processTimeout": {""}},
+			expectTimeout: time.Duration(30) * time.Second,
+		},
+		{
+			scenario:    "invalid processTimeout form field",
+			values:      map[string][]string{"processTimeout": {"foo"}},
+			expectError: true,
+		},
+		{
+			scenario:    "processTimeout form field not more than 0",
+			values:      map[string][]string{"processTimeout": {"0s"}},
+			expectError: true,
+		},
+		{
+			scenario:    "processTimeout form field more than the timeout without maximum timeout",
+			values:      map[string][]string{"processTimeout": {"1m"}},
+			expectError: true,
+		},
+		{
+			scenario:    "processTimeout form field more than the maximum timeout",
+			values:      map[string][]string{"processTimeout": {"11m"}},
+			maxTimeout:  time.Duration(10) * time.Minute,
+			expectError: true,
+		},
+		{
+			scenario:      "processTimeout form field less than the timeout",
+			values:        map[string][]string{"processTimeout": {"10s"}},
+			expectTimeout: time.Duration(10) * time.Second,
+		},
+		{
+			scenario:      "processTimeout form field up to the maximum timeout",
+			values:        map[string][]string{"processTimeout": {"10m"}},
+			maxTimeout:    time.Duration(10) * time.Minute,
+			expectTimeout: time.Duration(10) * time.Minute,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			timeout, err := parseProcessTimeout(tc.values, time.Duration(30)*time.Second, tc.maxTimeout)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				var httpErr HttpError
+				if !errors.As(err, &httpErr) {
+					t.Fatalf("expected an HTTP error but got: %v", err)
+				}
+
+				status, _ := httpErr.HttpError()
+				if status != http.StatusBadRequest {
+					t.Errorf("expected %d status code but got %d", http.StatusBadRequest, status)
+				}
+
+				return
+			}
+
+			if timeout != tc.expectTimeout {
+				t.Errorf("expected %s but got %s", tc.expectTimeout, timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// FieldError is a problem with a form field or a form file, detailed in the
+// "errors" of an [ErrorResponse].
+type FieldError struct {
+	// Field is the key of the form field, if any.
+	Field string `json:"field,omitempty"`
+
+	// File is the filename of the form file, if any.
+	File string `json:"file,omitempty"`
+
+	// Reason tells what is wrong, e.g., "required".
+	Reason string `json:"reason"`
+
+	// Expected is the expected type of the value, e.g., "boolean", if
+	// known.
+	Expected string `json:"expected,omitempty"`
+
+	err error
+}
+
+// Error returns the description of the error.
+func (err FieldError) Error() string {
+	if err.err == nil {
+		return err.Reason
+	}
+
+	return err.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (err FieldError) Unwrap() error {
+	return err.err
+}
+
+// ValidationError gathers all the problems with the form data of a request,
+// instead of the first one only. It is an [HttpError] with a 400 status.
+type ValidationError struct {
+	// Errors are the problems with the form fields and files.
+	Errors []FieldError
+
+	// UnknownFields are the form fields the route did not bind, e.g.,
+	// because of a typo. The [Api] sets them once the route returns, i.e.,
+	// once it is done binding the form fields.
+	UnknownFields []string
+}
+
+// newValidationError returns a [ValidationError] from the errors of a
+// [FormData].
+func newValidationError(errs error) *ValidationError {
+	validationErr := new(ValidationError)
+
+	for _, err := range multierr.Errors(errs) {
+		fieldErr, ok := err.(FieldError)
+		if !ok {
+			fieldErr = FieldError{Reason: err.Error(), err: err}
+		}
+
+		validationErr.Errors = append(validationErr.Errors, fieldErr)
+	}
+
+	return validationErr
+}
+
+// Error returns the description of the errors.
+func (err *ValidationError) Error() string {
+	messages := make([]string, len(err.Errors))
+	for i, fieldErr := range err.Errors {
+		messages[i] = fieldErr.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// HttpError returns the status and message.
+func (err *ValidationError) HttpError() (int, string) {
+	return http.StatusBadRequest, fmt.Sprintf("Invalid form data: %s", err.Error())
+}
+
+// HttpErrorCode returns the error code.
+func (err *ValidationError) HttpErrorCode() string {
+	return ErrorCodeInvalidFormData
+}
+
+// expectedType returns the name of the type of a form field target, as
+// reported to the clients.
+func expectedType(target interface{}) string {
+	switch target.(type) {
+	case *bool:
+		return "boolean"
+	case *int:
+		return "integer"
+	case *float64:
+		return "number"
+	case *time.Duration:
+		return "duration"
+	default:
+		return "string"
+	}
+}
+
+// setUnknownFields sets the unknown fields of a [ValidationError]. It must be
+// called once the route returns, as a route may bind form fields after a
+// first validation.
+func (ctx *Context) setUnknownFields(err error) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.UnknownFields = ctx.unknownFields()
+	}
+}
+
+// unknownFields returns the form fields not bound by the route so far,
+// sorted alphabetically. It returns nil if the bound fields are not
+// tracked.
+func (ctx *Context) unknownFields() []string {
+	if ctx == nil || ctx.boundFields == nil {
+		return nil
+	}
+
+	var unknown []string
+
+	for key := range ctx.values {
+		if isApiField(key) {
+			// Handled by the API, not by the route.
+			continue
+		}
+
+		if !ctx.boundFields[key] {
+			unknown = append(unknown, key)
+		}
+	}
+
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// Interface guards.
+var (
+	_ error          = (*FieldError)(nil)
+	_ error          = (*ValidationError)(nil)
+	_ HttpError      = (*ValidationError)(nil)
+	_ HttpErrorCoder = (*ValidationError)(nil)
+)
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestFormData_ValidateAggregatedErrors(t *testing.T) {
+	ctx := &Context{
+		values: map[string][]string{
+			"foo":            {"bar"},
+			"timeout":        {"1 minute"},
+			"landscpe":       {"true"},
+			"processTimeout": {"10s"},
+			"marginTop":      {"1"},
+		},
+		files:       map[string]string{},
+		boundFields: make(map[string]bool),
+		boundFiles:  make(map[string]bool),
+	}
+
+	var (
+		foo       string
+		landscape bool
+		scale     float64
+		timeout   int
+		path      string
+	)
+
+	err := ctx.FormData().
+		String("foo", &foo, "").
+		MandatoryBool("landscape", &landscape).
+		Float64("scale", &scale, 1.0).
+		Int("timeout", &timeout, 0).
+		MandatoryPath("header.html", &path).
+		Validate()
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a ValidationError but got: %v", err)
+	}
+
+	expectErrors := []FieldError{
+		{Field: "landscape", Reason: "required", Expected: "boolean"},
+		{Field: "timeout", Reason: "invalid value '1 minute'", Expected: "integer"},
+		{File: "header.html", Reason: "required", Expected: "file"},
+	}
+
+	if len(validationErr.Errors) != len(expectErrors) {
+		t.Fatalf("expected %d errors but got %d: %v", len(expectErrors), len(validationErr.Errors), validationErr.Errors)
+	}
+
+	for i, expect := range expectErrors {
+		actual := validationErr.Errors[i]
+		actual.err = nil
+
+		if actual != expect {
+			t.Errorf("expected error %+v but got %+v", expect, actual)
+		}
+	}
+
+	if validationErr.UnknownFields != nil {
+		t.Errorf("expected no unknown fields before the route returns but got %v", validationErr.UnknownFields)
+	}
+
+	// The route binds another form field after the validation.
+	var marginTop string
+	ctx.FormData().String("marginTop", &marginTop, "")
+
+	ctx.setUnknownFields(err)
+
+	expectUnknownFields := []string{"landscpe"}
+	if !reflect.DeepEqual(validationErr.UnknownFields, expectUnknownFields) {
+		t.Errorf("expected unknown fields %v but got %v", expectUnknownFields, validationErr.UnknownFields)
+	}
+
+	status, message := validationErr.HttpError()
+	if status != http.StatusBadRequest {
+		t.Errorf("expected %d status code but got %d", http.StatusBadRequest, status)
+	}
+
+	expectMessage := "Invalid form data: form field 'landscape' is required; form field 'timeout' is invalid (got '1 minute', resulting to strconv.Atoi: parsing \"1 minute\": invalid syntax); form file 'header.html' is required"
+	if message != expectMessage {
+		t.Errorf("expected message '%s' but got '%s'", expectMessage, message)
+	}
+
+	b, err := json.Marshal(validationErr.Errors[0])
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expectJson := `{"field":"landscape","reason":"required","expected":"boolean"}`
+	if string(b) != expectJson {
+		t.Errorf("expected JSON %s but got %s", expectJson, b)
+	}
+}
+
+func TestFormData_ValidateWithoutDetails(t *testing.T) {
+	ctx := &Context{
+		values:             map[string][]string{"landscpe": {"true"}},
+		files:              map[string]string{},
+		boundFields:        make(map[string]bool),
+		boundFiles:         make(map[string]bool),
+		disabledExtensions: map[string]bool{ExtensionValidationDetails: true},
+	}
+
+	var landscape bool
+
+	err := ctx.FormData().
+		MandatoryBool("landscape", &landscape).
+		Validate()
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		t.Fatalf("expected no ValidationError but got: %v", err)
+	}
+
+	response := ParseErrorResponse(err)
+
+	expect := ErrorResponse{
+		Code:    ErrorCodeInvalidFormData,
+		Status:  http.StatusBadRequest,
+		Message: "Invalid form data: form field 'landscape' is required",
+	}
+
+	if !reflect.DeepEqual(response, expect) {
+		t.Errorf("expected %+v but got %+v", expect, response)
+	}
+}
+
+func TestNewValidationError(t *testing.T) {
+	validationErr := newValidationError(errors.New("foo"))
+
+	if len(validationErr.Errors) != 1 {
+		t.Fatalf("expected 1 error but got %d", len(validationErr.Errors))
+	}
+
+	if validationErr.Errors[0].Reason != "foo" {
+		t.Errorf("expected reason 'foo' but got '%s'", validationErr.Errors[0].Reason)
+	}
+
+	if validationErr.Error() != "foo" {
+		t.Errorf("expected 'foo' but got '%s'", validationErr.Error())
+	}
+}
+
+func TestContext_UnknownFields(t *testing.T) {
+	var ctx *Context
+	if ctx.unknownFields() != nil {
+		t.Errorf("expected no unknown fields for a nil context")
+	}
+
+	ctx = &Context{values: map[string][]string{"foo": {"bar"}}}
+	if ctx.unknownFields() != nil {
+		t.Errorf("expected no unknown fields if the bound fields are not tracked")
+	}
+}
@@ -0,0 +1,130 @@
+package archival
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	pdfcpuLog "github.com/pdfcpu/pdfcpu/pkg/log"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Archival))
+}
+
+// Archival is a module which provides a route for converting documents to
+// archival PDFs. It relies on the LibreOffice module for the office
+// documents, and on the PDF engines for the PDF/A conversion.
+type Archival struct {
+	ocrBinPath     string
+	veraPdfBinPath string
+	libreOffice    libreofficeapi.Uno
+	engine         gotenberg.PdfEngine
+	conf           *pdfcpuConfig.Configuration
+	disableRoutes  bool
+}
+
+// Descriptor returns an [Archival]'s module descriptor.
+func (mod *Archival) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "archival",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("archival", flag.ExitOnError)
+			fs.Bool("archival-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Archival) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Archival) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("archival-disable-routes")
+
+	ocrBinPath, ok := os.LookupEnv("OCRMYPDF_BIN_PATH")
+	if !ok {
+		return errors.New("OCRMYPDF_BIN_PATH environment variable is not set")
+	}
+
+	mod.ocrBinPath = ocrBinPath
+
+	veraPdfBinPath, ok := os.LookupEnv("VERAPDF_BIN_PATH")
+	if !ok {
+		return errors.New("VERAPDF_BIN_PATH environment variable is not set")
+	}
+
+	mod.veraPdfBinPath = veraPdfBinPath
+
+	provider, err := ctx.Module(new(libreofficeapi.Provider))
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno provider: %w", err)
+	}
+
+	libreOffice, err := provider.(libreofficeapi.Provider).LibreOffice()
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno: %w", err)
+	}
+
+	mod.libreOffice = libreOffice
+
+	provider, err = ctx.Module(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine provider: %w", err)
+	}
+
+	engine, err := provider.(gotenberg.PdfEngineProvider).PdfEngine()
+	if err != nil {
+		return fmt.Errorf("get PDF engine: %w", err)
+	}
+
+	mod.engine = engine
+
+	// Same as the pdfcpu module.
+	pdfcpuConfig.ConfigPath = "disable"
+	pdfcpuLog.DisableLoggers()
+	mod.conf = pdfcpuConfig.NewDefaultConfiguration()
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Archival) Validate() error {
+	_, err := os.Stat(mod.ocrBinPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("OCRmyPDF binary path does not exist: %w", err)
+	}
+
+	_, err = os.Stat(mod.veraPdfBinPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("veraPDF binary path does not exist: %w", err)
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Archival) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		convertRoute(mod.ocrBinPath, mod.veraPdfBinPath, mod.libreOffice, mod.engine, mod.conf),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Archival)(nil)
+	_ gotenberg.Provisioner = (*Archival)(nil)
+	_ gotenberg.Validator   = (*Archival)(nil)
+	_ api.Router            = (*Archival)(nil)
+)
@@ -0,0 +1,212 @@
+package archival
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestArchival_Descriptor(t *testing.T) {
+	descriptor := new(Archival).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Archival))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestArchival_Provision(t *testing.T) {
+	// Each provider is a distinct module, so that a scenario may omit any of
+	// them.
+	libreOfficeProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			libreofficeapi.ProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "libreoffice", New: func() gotenberg.Module { return mod }}
+		}
+		mod.LibreOfficeMock = func() (libreofficeapi.Uno, error) {
+			return new(libreofficeapi.ApiMock), err
+		}
+
+		return mod
+	}
+
+	pdfEngineProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			gotenberg.PdfEngineProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "pdfengines", New: func() gotenberg.Module { return mod }}
+		}
+		mod.PdfEngineMock = func() (gotenberg.PdfEngine, error) {
+			return new(gotenberg.PdfEngineMock), err
+		}
+
+		return mod
+	}
+
+	newContext := func(mods ...gotenberg.Module) *gotenberg.Context {
+		descriptors := make([]gotenberg.ModuleDescriptor, len(mods))
+		for i, mod := range mods {
+			descriptors[i] = mod.Descriptor()
+		}
+
+		return gotenberg.NewContext(
+			gotenberg.ParsedFlags{
+				FlagSet: new(Archival).Descriptor().FlagSet,
+			},
+			descriptors,
+		)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		unsetEnv    string
+		expectError bool
+	}{
+		{
+			scenario:    "no OCRMYPDF_BIN_PATH environment variable",
+			ctx:         newContext(libreOfficeProvider(nil), pdfEngineProvider(nil)),
+			unsetEnv:    "OCRMYPDF_BIN_PATH",
+			expectError: true,
+		},
+		{
+			scenario:    "no VERAPDF_BIN_PATH environment variable",
+			ctx:         newContext(libreOfficeProvider(nil), pdfEngineProvider(nil)),
+			unsetEnv:    "VERAPDF_BIN_PATH",
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice API provider",
+			ctx:         newContext(pdfEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice API from LibreOffice API provider",
+			ctx:         newContext(libreOfficeProvider(errors.New("foo")), pdfEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no PDF engine provider",
+			ctx:         newContext(libreOfficeProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no PDF engine from PDF engine provider",
+			ctx:         newContext(libreOfficeProvider(nil), pdfEngineProvider(errors.New("foo"))),
+			expectError: true,
+		},
+		{
+			scenario:    "provision success",
+			ctx:         newContext(libreOfficeProvider(nil), pdfEngineProvider(nil)),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			// Make sure the environment variable is absent, even in the
+			// Docker image.
+			t.Setenv("OCRMYPDF_BIN_PATH", "/usr/bin/ocrmypdf")
+			t.Setenv("VERAPDF_BIN_PATH", "/opt/verapdf/verapdf")
+			if tc.unsetEnv != "" {
+				_ = os.Unsetenv(tc.unsetEnv)
+			}
+
+			mod := new(Archival)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestArchival_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		ocrBinPath     string
+		veraPdfBinPath string
+		expectError    bool
+	}{
+		{
+			scenario:       "non-existing OCRmyPDF binary",
+			ocrBinPath:     "/foo",
+			veraPdfBinPath: os.Args[0],
+			expectError:    true,
+		},
+		{
+			scenario:       "non-existing veraPDF binary",
+			ocrBinPath:     os.Args[0],
+			veraPdfBinPath: "/foo",
+			expectError:    true,
+		},
+		{
+			scenario:       "validate success",
+			ocrBinPath:     os.Args[0],
+			veraPdfBinPath: os.Args[0],
+			expectError:    false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Archival)
+			mod.ocrBinPath = tc.ocrBinPath
+			mod.veraPdfBinPath = tc.veraPdfBinPath
+			err := mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestArchival_Routes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		expectRoutes  int
+		disableRoutes bool
+	}{
+		{
+			scenario:      "routes not disabled",
+			expectRoutes:  1,
+			disableRoutes: false,
+		},
+		{
+			scenario:      "routes disabled",
+			expectRoutes:  0,
+			disableRoutes: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Archival)
+			mod.disableRoutes = tc.disableRoutes
+
+			routes, err := mod.Routes()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectRoutes != len(routes) {
+				t.Errorf("expected %d routes but got %d", tc.expectRoutes, len(routes))
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package archival provides a module which adds a route for preparing
+// documents for long-term archiving in a single call: conversion to PDF, OCR
+// of the scanned pages, conversion to PDF/A, validation with veraPDF, and
+// embedding of the source file.
+package archival
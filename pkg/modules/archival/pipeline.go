@@ -0,0 +1,176 @@
+package archival
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+var (
+	// ErrInvalidPdf happens if OCRmyPDF or veraPDF cannot read a PDF.
+	ErrInvalidPdf = errors.New("invalid PDF")
+
+	// ErrInvalidOcrLanguages happens if OCRmyPDF rejects the OCR languages,
+	// e.g., if Tesseract has no data for one of them.
+	ErrInvalidOcrLanguages = errors.New("invalid OCR languages")
+)
+
+// ocr adds a text layer to the pages of a PDF which have none, i.e., to the
+// scanned pages. The other pages are left as is.
+func ocr(ctx context.Context, logger *zap.Logger, binPath, inputPath, outputPath, languages string) error {
+	args := []string{
+		"--skip-text",
+		// The PDF engines handle the PDF/A conversion.
+		"--output-type", "pdf",
+		"--language", languages,
+		"--quiet",
+		inputPath,
+		outputPath,
+	}
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	exitCode, err := cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("OCR PDF: %w", err)
+	}
+
+	// See https://ocrmypdf.readthedocs.io/en/latest/advanced.html#return-code-policy.
+	switch exitCode {
+	case 1:
+		return fmt.Errorf("OCR PDF: %v: %w", err, ErrInvalidOcrLanguages)
+	case 2, 8:
+		return fmt.Errorf("OCR PDF: %v: %w", err, ErrInvalidPdf)
+	default:
+		return fmt.Errorf("OCR PDF: %w", err)
+	}
+}
+
+// validationFailure is a rule of the PDF/A specification a PDF breaks.
+type validationFailure struct {
+	Clause       string `json:"clause"`
+	TestNumber   int    `json:"testNumber"`
+	Description  string `json:"description"`
+	FailedChecks int    `json:"failedChecks"`
+}
+
+// validationReport is the outcome of the validation of a PDF with veraPDF.
+type validationReport struct {
+	Compliant   bool                `json:"compliant"`
+	Profile     string              `json:"profile"`
+	Statement   string              `json:"statement"`
+	PassedRules int                 `json:"passedRules"`
+	FailedRules int                 `json:"failedRules"`
+	Failures    []validationFailure `json:"failures,omitempty"`
+}
+
+// veraPdfReport is the subset of the machine-readable report of veraPDF
+// this module reads.
+type veraPdfReport struct {
+	Jobs []struct {
+		ValidationReport *struct {
+			ProfileName string `xml:"profileName,attr"`
+			Statement   string `xml:"statement,attr"`
+			IsCompliant bool   `xml:"isCompliant,attr"`
+			Details     struct {
+				PassedRules int `xml:"passedRules,attr"`
+				FailedRules int `xml:"failedRules,attr"`
+				Rules       []struct {
+					Clause       string `xml:"clause,attr"`
+					TestNumber   int    `xml:"testNumber,attr"`
+					Status       string `xml:"status,attr"`
+					FailedChecks int    `xml:"failedChecks,attr"`
+					Description  string `xml:"description"`
+				} `xml:"rule"`
+			} `xml:"details"`
+		} `xml:"validationReport"`
+	} `xml:"jobs>job"`
+}
+
+// validate checks the conformance of a PDF with a PDF/A format, e.g.,
+// PDF/A-2b.
+func validate(ctx context.Context, logger *zap.Logger, binPath, inputPath, pdfa string) (validationReport, error) {
+	flavour := strings.ToLower(strings.TrimPrefix(pdfa, "PDF/A-"))
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath, "--flavour", flavour, "--format", "mrr", inputPath)
+	if err != nil {
+		return validationReport{}, fmt.Errorf("create command: %w", err)
+	}
+
+	var stdout bytes.Buffer
+	cmd.SetStdout(&stdout)
+
+	// veraPDF exits with 1 if the PDF is not compliant, which is a valid
+	// outcome.
+	exitCode, err := cmd.Exec()
+	if err != nil {
+		if ctx.Err() != nil {
+			return validationReport{}, fmt.Errorf("validate PDF: %w", err)
+		}
+
+		if exitCode != 1 {
+			return validationReport{}, fmt.Errorf("validate PDF: %v: %w", err, ErrInvalidPdf)
+		}
+	}
+
+	var raw veraPdfReport
+	err = xml.Unmarshal(stdout.Bytes(), &raw)
+	if err != nil {
+		return validationReport{}, fmt.Errorf("unmarshal veraPDF report: %w", err)
+	}
+
+	// Without a validation report, veraPDF could not parse the PDF.
+	if len(raw.Jobs) == 0 || raw.Jobs[0].ValidationReport == nil {
+		return validationReport{}, fmt.Errorf("validate PDF: no validation report: %w", ErrInvalidPdf)
+	}
+
+	vr := raw.Jobs[0].ValidationReport
+	report := validationReport{
+		Compliant:   vr.IsCompliant,
+		Profile:     vr.ProfileName,
+		Statement:   vr.Statement,
+		PassedRules: vr.Details.PassedRules,
+		FailedRules: vr.Details.FailedRules,
+	}
+
+	for _, rule := range vr.Details.Rules {
+		if rule.Status != "failed" {
+			continue
+		}
+
+		report.Failures = append(report.Failures, validationFailure{
+			Clause:       rule.Clause,
+			TestNumber:   rule.TestNumber,
+			Description:  strings.TrimSpace(rule.Description),
+			FailedChecks: rule.FailedChecks,
+		})
+	}
+
+	return report, nil
+}
+
+// attach embeds a file in a PDF. The attachment has the name of the file.
+func attach(inputPath, outputPath, filePath string, conf *pdfcpuConfig.Configuration) error {
+	err := pdfcpuAPI.AddAttachmentsFile(inputPath, outputPath, []string{filePath}, false, conf)
+	if err != nil {
+		return fmt.Errorf("add attachment with PDFcpu: %w", err)
+	}
+
+	return nil
+}
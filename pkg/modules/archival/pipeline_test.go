@@ -0,0 +1,230 @@
+package archival
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// testMrr is a machine-readable report of veraPDF for a non-compliant PDF.
+const testMrr = `<?xml version="1.0" encoding="utf-8"?>
+<report>
+  <jobs>
+    <job>
+      <item size="1024"><name>/tmp/foo.pdf</name></item>
+      <validationReport jobEndStatus="normal" profileName="PDF/A-2B validation profile" statement="PDF file is not compliant with Validation Profile requirements." isCompliant="false">
+        <details passedRules="143" failedRules="1" passedChecks="1000" failedChecks="2">
+          <rule specification="ISO 19005-2:2011" clause="6.2.11.4.1" testNumber="1" status="failed" failedChecks="2">
+            <description>
+              The font programs for all fonts used for rendering within a conforming file shall be embedded.
+            </description>
+          </rule>
+          <rule specification="ISO 19005-2:2011" clause="6.1.2" testNumber="1" status="passed" passedChecks="1"/>
+        </details>
+      </validationReport>
+    </job>
+  </jobs>
+</report>`
+
+// fakeBin writes a script which prints the given output to stdout, or
+// which writes its arguments to its last argument if output is empty, then
+// exits with the given code.
+func fakeBin(t *testing.T, output string, exitCode int) string {
+	script := "for last; do true; done\necho \"$@\" > \"$last\"\n"
+	if output != "" {
+		script = "cat <<'EOF'\n" + output + "\nEOF\n"
+	}
+
+	if exitCode != 0 {
+		script += "exit " + strconv.Itoa(exitCode) + "\n"
+	}
+
+	return gotenbergtest.FakeBinary(t, "bin", script)
+}
+
+func TestOcr(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		expectError   bool
+		expectedError error
+		expectArgs    string
+	}{
+		{
+			scenario:      "ErrInvalidOcrLanguages",
+			binPath:       fakeBin(t, "", 1),
+			expectError:   true,
+			expectedError: ErrInvalidOcrLanguages,
+		},
+		{
+			scenario:      "ErrInvalidPdf",
+			binPath:       fakeBin(t, "", 2),
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:    "other error",
+			binPath:     fakeBin(t, "", 3),
+			expectError: true,
+		},
+		{
+			scenario:    "success",
+			binPath:     fakeBin(t, "", 0),
+			expectError: false,
+			expectArgs:  "--skip-text --output-type pdf --language eng+fra --quiet /tmp/foo.pdf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "foo.pdf")
+
+			err := ocr(context.Background(), zap.NewNop(), tc.binPath, "/tmp/foo.pdf", outputPath, "eng+fra")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			b, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			expect := tc.expectArgs + " " + outputPath + "\n"
+			if string(b) != expect {
+				t.Errorf("expected arguments '%s' but got '%s'", expect, string(b))
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		expectError   bool
+		expectedError error
+		expectReport  validationReport
+	}{
+		{
+			scenario:      "ErrInvalidPdf (exit code)",
+			binPath:       fakeBin(t, "foo", 7),
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:    "invalid report",
+			binPath:     fakeBin(t, "<report>", 0),
+			expectError: true,
+		},
+		{
+			scenario:      "ErrInvalidPdf (no validation report)",
+			binPath:       fakeBin(t, "<report><jobs><job><taskException/></job></jobs></report>", 0),
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:    "non-compliant PDF",
+			binPath:     fakeBin(t, testMrr, 1),
+			expectError: false,
+			expectReport: validationReport{
+				Compliant:   false,
+				Profile:     "PDF/A-2B validation profile",
+				Statement:   "PDF file is not compliant with Validation Profile requirements.",
+				PassedRules: 143,
+				FailedRules: 1,
+				Failures: []validationFailure{
+					{
+						Clause:       "6.2.11.4.1",
+						TestNumber:   1,
+						Description:  "The font programs for all fonts used for rendering within a conforming file shall be embedded.",
+						FailedChecks: 2,
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := validate(context.Background(), zap.NewNop(), tc.binPath, "/tmp/foo.pdf", "PDF/A-2b")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expectReport) {
+				t.Errorf("expected %+v but got %+v", tc.expectReport, actual)
+			}
+		})
+	}
+}
+
+func TestAttach(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+	conf := pdfcpuConfig.NewDefaultConfiguration()
+
+	dirPath := t.TempDir()
+	sourcePath := filepath.Join(dirPath, "source.txt")
+
+	err := os.WriteFile(sourcePath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = attach("/foo.pdf", filepath.Join(dirPath, "foo.pdf"), sourcePath, conf)
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	outputPath := filepath.Join(dirPath, "output.pdf")
+
+	err = attach("../../../test/testdata/pdfengines/sample1.pdf", outputPath, sourcePath, conf)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	f, err := os.Open(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	attachments, err := pdfcpuAPI.Attachments(f, conf)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(attachments) != 1 || attachments[0].FileName != "source.txt" {
+		t.Errorf("expected the attachment 'source.txt' but got %+v", attachments)
+	}
+}
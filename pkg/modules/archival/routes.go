@@ -0,0 +1,218 @@
+package archival
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+var (
+	// pdfaFormats are the PDF/A formats veraPDF validates.
+	pdfaFormats = []string{
+		gotenberg.PdfA1a, gotenberg.PdfA1b,
+		gotenberg.PdfA2a, gotenberg.PdfA2b, gotenberg.PdfA2u,
+		gotenberg.PdfA3a, gotenberg.PdfA3b, gotenberg.PdfA3u,
+	}
+
+	// ocrLanguagesRegexp matches Tesseract languages joined with '+', e.g.,
+	// "eng+fra".
+	ocrLanguagesRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]*(\+[a-z][a-z0-9_]*)*$`)
+)
+
+// report describes how the archival pipeline processed a document.
+type report struct {
+	Source         string           `json:"source"`
+	PdfA           string           `json:"pdfa"`
+	Ocr            bool             `json:"ocr"`
+	SourceEmbedded bool             `json:"sourceEmbedded"`
+	Validation     validationReport `json:"validation"`
+}
+
+// convertRoute returns an [api.Route] which can convert documents to
+// archival PDFs. For each document, it returns the PDF and a JSON validation
+// report.
+//
+// Note: PDF/A-2 only permits embedded files which are PDF/A themselves.
+// Therefore, the validation happens before embedding the source, and the
+// report describes the PDF without it; choose PDF/A-3b for a PDF which
+// remains compliant with its source.
+func convertRoute(ocrBinPath, veraPdfBinPath string, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, conf *pdfcpuConfig.Configuration) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/archival/convert",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			extensions := []string{".pdf"}
+			for _, ext := range libreOffice.Extensions() {
+				if !slices.Contains(extensions, ext) {
+					extensions = append(extensions, ext)
+				}
+			}
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths   []string
+				pdfa         string
+				ocrEnabled   bool
+				ocrLanguages string
+				embedSource  bool
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths(extensions, &inputPaths).
+				Custom("pdfa", func(value string) error {
+					if value == "" {
+						pdfa = gotenberg.PdfA2b
+						return nil
+					}
+
+					if !slices.Contains(pdfaFormats, value) {
+						return fmt.Errorf("wrong value, expected one of %s", strings.Join(pdfaFormats, ", "))
+					}
+
+					pdfa = value
+
+					return nil
+				}).
+				Bool("ocr", &ocrEnabled, true).
+				Custom("ocrLanguages", func(value string) error {
+					if value == "" {
+						ocrLanguages = "eng"
+						return nil
+					}
+
+					if !ocrLanguagesRegexp.MatchString(value) {
+						return errors.New("wrong value, expected Tesseract languages joined with '+', e.g., 'eng+fra'")
+					}
+
+					ocrLanguages = value
+
+					return nil
+				}).
+				Bool("embedSource", &embedSource, true).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			wrapError := func(err error, inputPath string) error {
+				if errors.Is(err, ErrInvalidPdf) {
+					return api.WrapError(
+						err,
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The PDF of '%s' is invalid", filepath.Base(inputPath))).WithCode(api.ErrorCodeArchivalInvalidPdf),
+					)
+				}
+
+				if errors.Is(err, ErrInvalidOcrLanguages) {
+					return api.WrapError(
+						err,
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The OCR languages '%s' are not available", ocrLanguages)).WithCode(api.ErrorCodeArchivalInvalidOcrLanguages),
+					)
+				}
+
+				return err
+			}
+
+			// Alright, let's run each document through the pipeline.
+			outputPaths := make([]string, 0, len(inputPaths)*2)
+
+			for _, inputPath := range inputPaths {
+				filename := filepath.Base(inputPath)
+				pdfPath := inputPath
+
+				// 1. Convert to PDF.
+				if strings.ToLower(filepath.Ext(inputPath)) != ".pdf" {
+					ctx.AddEngines("libreoffice")
+					pdfPath = ctx.GeneratePath("", ".pdf")
+
+					err = libreOffice.Pdf(ctx, ctx.Log(), inputPath, pdfPath, libreofficeapi.Options{})
+					if err != nil {
+						return fmt.Errorf("convert to PDF: %w", err)
+					}
+				}
+
+				// 2. OCR the scanned pages.
+				if ocrEnabled {
+					ocrPath := ctx.GeneratePath("", ".pdf")
+
+					err = ocr(ctx, ctx.Log(), ocrBinPath, pdfPath, ocrPath, ocrLanguages)
+					if err != nil {
+						return wrapError(fmt.Errorf("run OCR: %w", err), inputPath)
+					}
+
+					pdfPath = ocrPath
+				}
+
+				// 3. Convert to PDF/A.
+				pdfaPath := ctx.GeneratePath("", ".pdf")
+
+				err = engine.Convert(ctx, ctx.Log(), gotenberg.PdfFormats{PdfA: pdfa}, pdfPath, pdfaPath)
+				if err != nil {
+					return fmt.Errorf("convert PDF: %w", err)
+				}
+
+				// 4. Validate.
+				validation, err := validate(ctx, ctx.Log(), veraPdfBinPath, pdfaPath, pdfa)
+				if err != nil {
+					return wrapError(fmt.Errorf("check PDF/A conformance: %w", err), inputPath)
+				}
+
+				// 5. Embed the source.
+				// document.docx -> document.docx.pdf.
+				outputPath := ctx.GeneratePath(filename, ".pdf")
+
+				if embedSource {
+					err = attach(pdfaPath, outputPath, inputPath, conf)
+				} else {
+					err = os.Rename(pdfaPath, outputPath)
+				}
+				if err != nil {
+					return fmt.Errorf("write archival PDF: %w", err)
+				}
+
+				b, err := json.MarshalIndent(report{
+					Source:         filename,
+					PdfA:           pdfa,
+					Ocr:            ocrEnabled,
+					SourceEmbedded: embedSource,
+					Validation:     validation,
+				}, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshal report: %w", err)
+				}
+
+				// document.docx -> document.docx.report.json.
+				reportPath := ctx.GeneratePath(filename, ".report.json")
+
+				err = os.WriteFile(reportPath, b, 0o600)
+				if err != nil {
+					return fmt.Errorf("write report: %w", err)
+				}
+
+				outputPaths = append(outputPaths, outputPath, reportPath)
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,319 @@
+package archival
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestConvertRoute(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+	conf := pdfcpuConfig.NewDefaultConfiguration()
+
+	samplePath := "../../../test/testdata/pdfengines/sample1.pdf"
+
+	copyFile := func(src, dst string) error {
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = in.Close()
+		}()
+
+		out, err := os.Create(dst)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			_ = out.Close()
+		}()
+
+		_, err = io.Copy(out, in)
+
+		return err
+	}
+
+	newContext := func(filenames []string, values map[string][]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string)
+
+		for _, filename := range filenames {
+			path := filepath.Join(dirPath, filename)
+
+			err := copyFile(samplePath, path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			paths[filename] = path
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetFiles(paths)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	// ocrmypdf copies its input to its output.
+	ocrBin := func(exitCode int) string {
+		script := "for last; do true; done\nfor arg; do [ \"$arg\" = \"$last\" ] && break; in=\"$arg\"; done\ncp \"$in\" \"$last\"\n"
+		if exitCode != 0 {
+			script = "exit " + strconv.Itoa(exitCode) + "\n"
+		}
+
+		return gotenbergtest.FakeBinary(t, "ocrmypdf", script)
+	}
+
+	libreOffice := func(err error) libreofficeapi.Uno {
+		return &libreofficeapi.ApiMock{
+			PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				if err != nil {
+					return err
+				}
+
+				return copyFile(samplePath, outputPath)
+			},
+			ExtensionsMock: func() []string {
+				return []string{".docx"}
+			},
+		}
+	}
+
+	engine := func(err error) gotenberg.PdfEngine {
+		return &gotenberg.PdfEngineMock{
+			ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+				if err != nil {
+					return err
+				}
+
+				if formats.PdfA == "" {
+					return errors.New("expected a PDF/A format")
+				}
+
+				return copyFile(inputPath, outputPath)
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		ocrBinPath             string
+		veraPdfBinPath         string
+		libreOffice            libreofficeapi.Uno
+		engine                 gotenberg.PdfEngine
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectReport           *report
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newContext(nil, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid pdfa form field",
+			ctx:                    newContext([]string{"document.pdf"}, map[string][]string{"pdfa": {"foo"}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid ocrLanguages form field",
+			ctx:                    newContext([]string{"document.pdf"}, map[string][]string{"ocrLanguages": {"eng fra"}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from LibreOffice",
+			ctx:                    newContext([]string{"document.docx"}, nil),
+			libreOffice:            libreOffice(errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "ErrInvalidOcrLanguages",
+			ctx:                    newContext([]string{"document.pdf"}, map[string][]string{"ocrLanguages": {"foo"}}),
+			ocrBinPath:             ocrBin(1),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "ErrInvalidPdf from OCRmyPDF",
+			ctx:                    newContext([]string{"document.pdf"}, nil),
+			ocrBinPath:             ocrBin(2),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from PDF engine",
+			ctx:                    newContext([]string{"document.pdf"}, nil),
+			engine:                 engine(errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "ErrInvalidPdf from veraPDF",
+			ctx:                    newContext([]string{"document.pdf"}, nil),
+			veraPdfBinPath:         fakeBin(t, "foo", 7),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "success",
+			ctx:                    newContext([]string{"document.docx", "document.pdf"}, nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 4,
+			expectReport: &report{
+				Source:         "document.docx",
+				PdfA:           gotenberg.PdfA2b,
+				Ocr:            true,
+				SourceEmbedded: true,
+			},
+		},
+		{
+			scenario: "success without OCR nor source",
+			ctx: newContext([]string{"document.pdf"}, map[string][]string{
+				"pdfa":        {gotenberg.PdfA3b},
+				"ocr":         {"false"},
+				"embedSource": {"false"},
+			}),
+			ocrBinPath:             ocrBin(2),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+			expectReport: &report{
+				Source:         "document.pdf",
+				PdfA:           gotenberg.PdfA3b,
+				Ocr:            false,
+				SourceEmbedded: false,
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			if tc.ocrBinPath == "" {
+				tc.ocrBinPath = ocrBin(0)
+			}
+
+			if tc.veraPdfBinPath == "" {
+				tc.veraPdfBinPath = fakeBin(t, testMrr, 1)
+			}
+
+			if tc.libreOffice == nil {
+				tc.libreOffice = libreOffice(nil)
+			}
+
+			if tc.engine == nil {
+				tc.engine = engine(nil)
+			}
+
+			err := convertRoute(tc.ocrBinPath, tc.veraPdfBinPath, tc.libreOffice, tc.engine, conf).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+
+			if tc.expectReport == nil {
+				return
+			}
+
+			for _, outputPath := range tc.ctx.OutputPaths() {
+				if filepath.Base(outputPath) != tc.expectReport.Source+".report.json" {
+					continue
+				}
+
+				b, err := os.ReadFile(outputPath)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				var actual report
+				err = json.Unmarshal(b, &actual)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				if actual.Source != tc.expectReport.Source || actual.PdfA != tc.expectReport.PdfA || actual.Ocr != tc.expectReport.Ocr || actual.SourceEmbedded != tc.expectReport.SourceEmbedded {
+					t.Errorf("expected %+v but got %+v", tc.expectReport, actual)
+				}
+
+				if actual.Validation.Compliant || actual.Validation.FailedRules != 1 {
+					t.Errorf("expected a non-compliant validation report but got %+v", actual.Validation)
+				}
+
+				return
+			}
+
+			t.Errorf("expected a report for '%s' but got none in %s", tc.expectReport.Source, strings.Join(tc.ctx.OutputPaths(), ", "))
+		})
+	}
+}
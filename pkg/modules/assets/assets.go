@@ -0,0 +1,332 @@
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/labstack/gommon/bytes"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Assets))
+}
+
+// ErrAssetTooLarge happens if an asset exceeds the maximum size.
+var ErrAssetTooLarge = errors.New("asset too large")
+
+// Asset is the description of a stored asset.
+type Asset struct {
+	// Filename is the name of the uploaded file. The requests may reference
+	// the asset under another filename.
+	Filename string `json:"filename"`
+
+	// Checksum is the hex-encoded SHA-256 checksum of the asset, which
+	// identifies it.
+	Checksum string `json:"checksum"`
+
+	// Size is the size of the asset, in bytes.
+	Size int64 `json:"size"`
+}
+
+// Assets is a module which keeps the shared assets, provides them to the
+// requests referencing them, and adds a route for uploading them.
+type Assets struct {
+	enable          bool
+	dir             string
+	ttl             time.Duration
+	maxSize         int64
+	cleanupInterval time.Duration
+
+	logger *zap.Logger
+	stop   chan struct{}
+}
+
+// Descriptor returns an [Assets]'s module descriptor.
+func (mod *Assets) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "assets",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("assets", flag.ExitOnError)
+			fs.Bool("assets-enable", false, "Enable the shared assets, the 'assets' form field and the route for uploading them")
+			fs.String("assets-dir", filepath.Join(os.TempDir(), "gotenberg-assets"), "Set the directory in which to keep the assets")
+			fs.Duration("assets-ttl", time.Duration(24)*time.Hour, "Set the time after which an asset no request uses expires and is deleted")
+			fs.String("assets-max-size", "10MB", "Set the maximum size of an asset")
+			fs.Duration("assets-cleanup-interval", time.Duration(1)*time.Minute, "Set the interval at which to delete the expired assets")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Assets) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Assets) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("assets-enable")
+	mod.dir = flags.MustString("assets-dir")
+	mod.ttl = flags.MustDuration("assets-ttl")
+	mod.cleanupInterval = flags.MustDuration("assets-cleanup-interval")
+
+	maxSize, err := bytes.Parse(flags.MustHumanReadableBytesString("assets-max-size"))
+	if err != nil {
+		return fmt.Errorf("parse maximum size: %w", err)
+	}
+
+	mod.maxSize = maxSize
+
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Assets) Validate() error {
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.dir == "" {
+		err = multierr.Append(err,
+			errors.New("directory must not be empty"),
+		)
+	}
+
+	if mod.ttl <= 0 {
+		err = multierr.Append(err,
+			errors.New("TTL must be more than 0"),
+		)
+	}
+
+	if mod.maxSize <= 0 {
+		err = multierr.Append(err,
+			errors.New("maximum size must be more than 0"),
+		)
+	}
+
+	if mod.cleanupInterval <= 0 {
+		err = multierr.Append(err,
+			errors.New("cleanup interval must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Start creates the directory of the assets and deletes the expired ones
+// periodically.
+func (mod *Assets) Start() error {
+	if !mod.enable {
+		return nil
+	}
+
+	err := os.MkdirAll(mod.dir, 0o700)
+	if err != nil {
+		return fmt.Errorf("create assets directory: %w", err)
+	}
+
+	mod.stop = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(mod.cleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-mod.stop:
+				return
+			case <-ticker.C:
+				err := mod.cleanup(time.Now())
+				if err != nil {
+					mod.logger.Error(fmt.Sprintf("delete expired assets: %s", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Assets) StartupMessage() string {
+	if !mod.enable {
+		return "shared assets disabled"
+	}
+
+	return fmt.Sprintf("assets kept in '%s' for %s after their last use", mod.dir, mod.ttl)
+}
+
+// Stop stops the periodic deletion of the expired assets.
+func (mod *Assets) Stop(ctx context.Context) error {
+	if mod.stop != nil {
+		close(mod.stop)
+	}
+
+	return nil
+}
+
+// OpenAsset returns the content of an asset. Each use postpones its
+// expiration.
+func (mod *Assets) OpenAsset(ctx context.Context, checksum string) (io.ReadCloser, error) {
+	if !mod.enable {
+		return nil, fmt.Errorf("assets disabled: %w", api.ErrAssetNotFound)
+	}
+
+	path := filepath.Join(mod.dir, filepath.Base(checksum))
+
+	now := time.Now()
+
+	err := os.Chtimes(path, now, now)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("asset '%s': %w", checksum, api.ErrAssetNotFound)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("touch asset '%s': %w", checksum, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open asset '%s': %w", checksum, err)
+	}
+
+	return f, nil
+}
+
+// store copies a file to the assets directory, under its checksum. An
+// existing asset with the same content is kept, with a postponed
+// expiration.
+func (mod *Assets) store(path string) (Asset, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Asset{}, fmt.Errorf("stat file: %w", err)
+	}
+
+	if info.Size() > mod.maxSize {
+		return Asset{}, fmt.Errorf("'%s' has more than %d bytes: %w", filepath.Base(path), mod.maxSize, ErrAssetTooLarge)
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return Asset{}, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = src.Close()
+	}()
+
+	tmp, err := os.CreateTemp(mod.dir, ".upload-*")
+	if err != nil {
+		return Asset{}, fmt.Errorf("create temporary file: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	h := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(tmp, h), src)
+	if err != nil {
+		_ = tmp.Close()
+
+		return Asset{}, fmt.Errorf("copy file: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return Asset{}, fmt.Errorf("close temporary file: %w", err)
+	}
+
+	asset := Asset{
+		Filename: filepath.Base(path),
+		Checksum: hex.EncodeToString(h.Sum(nil)),
+		Size:     size,
+	}
+
+	// Same content, same file: replacing an existing asset is harmless.
+	err = os.Rename(tmp.Name(), filepath.Join(mod.dir, asset.Checksum))
+	if err != nil {
+		return Asset{}, fmt.Errorf("rename temporary file: %w", err)
+	}
+
+	return asset, nil
+}
+
+// cleanup deletes the assets no request used for longer than the TTL.
+func (mod *Assets) cleanup(now time.Time) error {
+	entries, err := os.ReadDir(mod.dir)
+	if err != nil {
+		return fmt.Errorf("read assets directory: %w", err)
+	}
+
+	var cleanupErr error
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			// Upload in progress.
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			cleanupErr = multierr.Append(cleanupErr, err)
+			continue
+		}
+
+		if now.Sub(info.ModTime()) < mod.ttl {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(mod.dir, entry.Name()))
+		if err != nil {
+			cleanupErr = multierr.Append(cleanupErr, err)
+			continue
+		}
+
+		mod.logger.Debug(fmt.Sprintf("asset '%s' deleted", entry.Name()))
+	}
+
+	return cleanupErr
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Assets)(nil)
+	_ gotenberg.Provisioner = (*Assets)(nil)
+	_ gotenberg.Validator   = (*Assets)(nil)
+	_ gotenberg.App         = (*Assets)(nil)
+	_ api.Router            = (*Assets)(nil)
+	_ api.AssetProvider     = (*Assets)(nil)
+)
@@ -0,0 +1,252 @@
+package assets
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestAssets_Descriptor(t *testing.T) {
+	descriptor := new(Assets).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Assets))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestAssets_Provision(t *testing.T) {
+	mod := new(Assets)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Assets).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enable {
+		t.Error("expected assets to be disabled by default")
+	}
+
+	if mod.maxSize != 10*1000*1000 {
+		t.Errorf("expected maximum size of 10MB but got %d", mod.maxSize)
+	}
+}
+
+func TestAssets_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Assets
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			mod:      &Assets{},
+		},
+		{
+			scenario:    "empty directory",
+			mod:         &Assets{enable: true, ttl: time.Hour, maxSize: 1, cleanupInterval: time.Minute},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid TTL",
+			mod:         &Assets{enable: true, dir: "/tmp", maxSize: 1, cleanupInterval: time.Minute},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid maximum size",
+			mod:         &Assets{enable: true, dir: "/tmp", ttl: time.Hour, cleanupInterval: time.Minute},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid cleanup interval",
+			mod:         &Assets{enable: true, dir: "/tmp", ttl: time.Hour, maxSize: 1},
+			expectError: true,
+		},
+		{
+			scenario: "validate success",
+			mod:      &Assets{enable: true, dir: "/tmp", ttl: time.Hour, maxSize: 1, cleanupInterval: time.Minute},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestAssets_store(t *testing.T) {
+	mod := &Assets{
+		enable:  true,
+		dir:     t.TempDir(),
+		maxSize: 10,
+	}
+
+	srcDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"logo.png":    "logo",
+		"copy.png":    "logo",
+		"large.woff2": "foobarbazqux",
+	} {
+		err := os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	asset, err := mod.store(filepath.Join(srcDir, "logo.png"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := Asset{
+		Filename: "logo.png",
+		Checksum: "3598ce6f965b2481fe26316c06b30950c46ac7f8e7229f104aa78f579997668d",
+		Size:     4,
+	}
+
+	if asset != expect {
+		t.Errorf("expected %+v but got %+v", expect, asset)
+	}
+
+	duplicate, err := mod.store(filepath.Join(srcDir, "copy.png"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if duplicate.Checksum != asset.Checksum {
+		t.Errorf("expected the same checksum '%s' but got '%s'", asset.Checksum, duplicate.Checksum)
+	}
+
+	entries, err := os.ReadDir(mod.dir)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected a single stored file but got %d", len(entries))
+	}
+
+	_, err = mod.store(filepath.Join(srcDir, "large.woff2"))
+	if !errors.Is(err, ErrAssetTooLarge) {
+		t.Errorf("expected error %v but got: %v", ErrAssetTooLarge, err)
+	}
+}
+
+func TestAssets_OpenAsset(t *testing.T) {
+	mod := &Assets{
+		enable: true,
+		dir:    t.TempDir(),
+	}
+
+	checksum := "3a7bd3e2360a3d29eea436fcfb7e44c735d117c42d1c1835420b6b9942dd4f1b"
+	path := filepath.Join(mod.dir, checksum)
+
+	err := os.WriteFile(path, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	old := time.Now().Add(-time.Duration(2) * time.Hour)
+
+	err = os.Chtimes(path, old, old)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	in, err := mod.OpenAsset(context.Background(), checksum)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	b, _ := io.ReadAll(in)
+	_ = in.Close()
+
+	if string(b) != "foo" {
+		t.Errorf("expected content 'foo' but got '%s'", string(b))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !info.ModTime().After(old) {
+		t.Error("expected the expiration of the asset to be postponed")
+	}
+
+	_, err = mod.OpenAsset(context.Background(), "foo")
+	if !errors.Is(err, api.ErrAssetNotFound) {
+		t.Errorf("expected error %v but got: %v", api.ErrAssetNotFound, err)
+	}
+
+	_, err = new(Assets).OpenAsset(context.Background(), checksum)
+	if !errors.Is(err, api.ErrAssetNotFound) {
+		t.Errorf("expected error %v but got: %v", api.ErrAssetNotFound, err)
+	}
+}
+
+func TestAssets_cleanup(t *testing.T) {
+	mod := &Assets{
+		dir:    t.TempDir(),
+		ttl:    time.Duration(1) * time.Hour,
+		logger: zap.NewNop(),
+	}
+
+	for _, name := range []string{"expired", "recent", ".upload-foo"} {
+		err := os.WriteFile(filepath.Join(mod.dir, name), []byte("foo"), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	expired := time.Now().Add(-time.Duration(2) * time.Hour)
+	for _, name := range []string{"expired", ".upload-foo"} {
+		err := os.Chtimes(filepath.Join(mod.dir, name), expired, expired)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	err := mod.cleanup(time.Now())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(mod.dir, "expired"))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected expired asset to be deleted but got: %v", err)
+	}
+
+	for _, name := range []string{"recent", ".upload-foo"} {
+		_, err = os.Stat(filepath.Join(mod.dir, name))
+		if err != nil {
+			t.Errorf("expected '%s' to be kept but got: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// Package assets provides a module which keeps the shared assets of the
+// requests, e.g., logos, stylesheets or fonts, under their SHA-256 checksum.
+// The clients upload them once, then reference them by checksum with the
+// "assets" form field of their Chromium conversions, which only carry their
+// HTML documents.
+package assets
@@ -0,0 +1,64 @@
+package assets
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// Routes returns the HTTP route for uploading the assets.
+func (mod *Assets) Routes() ([]api.Route, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Route{
+		uploadRoute(mod),
+	}, nil
+}
+
+// uploadRoute returns an [api.Route] which stores the uploaded files as
+// shared assets and returns their checksums.
+func uploadRoute(mod *Assets) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/assets",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			inputPaths := ctx.InputPaths()
+			if len(inputPaths) == 0 {
+				return api.WrapError(
+					errors.New("no form file"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: no form file found").WithCode(api.ErrorCodeInvalidFormData),
+				)
+			}
+
+			assets := make([]Asset, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				asset, err := mod.store(inputPath)
+				if errors.Is(err, ErrAssetTooLarge) {
+					return api.WrapError(
+						fmt.Errorf("store asset: %w", err),
+						api.NewSentinelHttpError(http.StatusRequestEntityTooLarge, fmt.Sprintf("Asset '%s' is larger than %d bytes", filepath.Base(inputPath), mod.maxSize)).WithCode(api.ErrorCodeAssetsAssetTooLarge),
+					)
+				}
+
+				if err != nil {
+					return fmt.Errorf("store asset: %w", err)
+				}
+
+				assets[i] = asset
+			}
+
+			return c.JSON(http.StatusOK, assets)
+		},
+	}
+}
@@ -0,0 +1,130 @@
+package assets
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestAssets_Routes(t *testing.T) {
+	routes, err := new(Assets).Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 0 {
+		t.Errorf("expected no route but got %d", len(routes))
+	}
+
+	routes, err = (&Assets{enable: true}).Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 1 || routes[0].Path != "/forms/assets" || !routes[0].IsMultipart {
+		t.Errorf("expected the '/forms/assets' multipart route but got %+v", routes)
+	}
+}
+
+func TestUploadRoute(t *testing.T) {
+	srcDir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"logo.png":  "logo",
+		"style.css": "body { color: red; }",
+	} {
+		err := os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		files        map[string]string
+		maxSize      int64
+		expectStatus int
+		expectAssets int
+	}{
+		{
+			scenario:     "no file",
+			files:        map[string]string{},
+			maxSize:      100,
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "too large",
+			files: map[string]string{
+				"style.css": filepath.Join(srcDir, "style.css"),
+			},
+			maxSize:      10,
+			expectStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			scenario: "success",
+			files: map[string]string{
+				"logo.png":  filepath.Join(srcDir, "logo.png"),
+				"style.css": filepath.Join(srcDir, "style.css"),
+			},
+			maxSize:      100,
+			expectStatus: http.StatusOK,
+			expectAssets: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Assets{
+				enable:  true,
+				dir:     t.TempDir(),
+				maxSize: tc.maxSize,
+			}
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetFiles(tc.files)
+			ctx.SetLogger(zap.NewNop())
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/assets", nil), rec)
+			c.Set("context", ctx.Context)
+
+			err := uploadRoute(mod).Handler(c)
+			if err != nil {
+				status := api.ParseErrorResponse(err).Status
+				if status != tc.expectStatus {
+					t.Errorf("expected status %d but got %d: %v", tc.expectStatus, status, err)
+				}
+
+				return
+			}
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d but got %d", tc.expectStatus, rec.Code)
+			}
+
+			var assets []Asset
+
+			err = json.Unmarshal(rec.Body.Bytes(), &assets)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(assets) != tc.expectAssets {
+				t.Fatalf("expected %d assets but got %+v", tc.expectAssets, assets)
+			}
+
+			for _, asset := range assets {
+				_, err = os.Stat(filepath.Join(mod.dir, asset.Checksum))
+				if err != nil {
+					t.Errorf("expected asset '%s' to be stored but got: %v", asset.Filename, err)
+				}
+			}
+		})
+	}
+}
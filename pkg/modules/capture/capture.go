@@ -0,0 +1,242 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/replay"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Capture))
+}
+
+// sensitiveHeaderRegexp matches the headers which values are not captured,
+// e.g., the credentials.
+var sensitiveHeaderRegexp = regexp.MustCompile(`(?i)(authorization|cookie|token|secret|password|api-key)`)
+
+// Capture is a module which persists the failed requests for replaying them
+// later.
+type Capture struct {
+	enable          bool
+	dir             string
+	ttl             time.Duration
+	minStatus       int
+	cleanupInterval time.Duration
+
+	logger *zap.Logger
+	stop   chan struct{}
+}
+
+// Descriptor returns a [Capture]'s module descriptor.
+func (mod *Capture) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "capture",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("capture", flag.ExitOnError)
+			fs.Bool("capture-enable", false, "Persist the form fields and files of the failed requests for replaying them - note: captured requests may contain sensitive documents")
+			fs.String("capture-dir", filepath.Join(os.TempDir(), "gotenberg-captures"), "Set the directory in which to persist the failed requests")
+			fs.Duration("capture-ttl", time.Duration(24)*time.Hour, "Set the time after which a captured request is deleted - 0 keeps them")
+			fs.Int("capture-min-status", http.StatusInternalServerError, "Set the minimum HTTP status code of the failed requests to capture")
+			fs.Duration("capture-cleanup-interval", time.Duration(1)*time.Minute, "Set the interval at which to delete the expired captured requests")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Capture) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Capture) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("capture-enable")
+	mod.dir = flags.MustString("capture-dir")
+	mod.ttl = flags.MustDuration("capture-ttl")
+	mod.minStatus = flags.MustInt("capture-min-status")
+	mod.cleanupInterval = flags.MustDuration("capture-cleanup-interval")
+
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Capture) Validate() error {
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.dir == "" {
+		err = multierr.Append(err,
+			errors.New("directory must not be empty"),
+		)
+	}
+
+	if mod.ttl < 0 {
+		err = multierr.Append(err,
+			errors.New("TTL must be at least 0"),
+		)
+	}
+
+	if mod.cleanupInterval <= 0 {
+		err = multierr.Append(err,
+			errors.New("cleanup interval must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Start creates the directory of the captured requests and deletes the
+// expired ones periodically.
+func (mod *Capture) Start() error {
+	if !mod.enable {
+		return nil
+	}
+
+	err := os.MkdirAll(mod.dir, 0o700)
+	if err != nil {
+		return fmt.Errorf("create capture directory: %w", err)
+	}
+
+	if mod.ttl == 0 {
+		return nil
+	}
+
+	mod.stop = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(mod.cleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-mod.stop:
+				return
+			case <-ticker.C:
+				err := mod.cleanup(time.Now())
+				if err != nil {
+					mod.logger.Error(fmt.Sprintf("delete expired captured requests: %s", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Capture) StartupMessage() string {
+	if !mod.enable {
+		return "capture disabled"
+	}
+
+	return fmt.Sprintf("failed requests captured in '%s'", mod.dir)
+}
+
+// Stop stops the periodic deletion of the expired captured requests.
+func (mod *Capture) Stop(ctx context.Context) error {
+	if mod.stop != nil {
+		close(mod.stop)
+	}
+
+	return nil
+}
+
+// Middlewares returns the middleware.
+func (mod *Capture) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		captureMiddleware(mod),
+	}, nil
+}
+
+// save persists a failed request.
+func (mod *Capture) save(capture replay.Capture, inputPaths []string) (string, error) {
+	dir := filepath.Join(mod.dir, fmt.Sprintf("%s-%s", capture.Time.UTC().Format("20060102T150405Z"), uuid.NewString()))
+
+	err := replay.Save(dir, capture, inputPaths)
+	if err != nil {
+		// Let's not keep a partial capture.
+		_ = os.RemoveAll(dir)
+
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// cleanup deletes the captured requests older than the TTL.
+func (mod *Capture) cleanup(now time.Time) error {
+	entries, err := os.ReadDir(mod.dir)
+	if err != nil {
+		return fmt.Errorf("read capture directory: %w", err)
+	}
+
+	var cleanupErr error
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			cleanupErr = multierr.Append(cleanupErr, err)
+			continue
+		}
+
+		if now.Sub(info.ModTime()) < mod.ttl {
+			continue
+		}
+
+		err = os.RemoveAll(filepath.Join(mod.dir, entry.Name()))
+		if err != nil {
+			cleanupErr = multierr.Append(cleanupErr, err)
+			continue
+		}
+
+		mod.logger.Debug(fmt.Sprintf("captured request '%s' deleted", entry.Name()))
+	}
+
+	return cleanupErr
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Capture)(nil)
+	_ gotenberg.Provisioner  = (*Capture)(nil)
+	_ gotenberg.Validator    = (*Capture)(nil)
+	_ gotenberg.App          = (*Capture)(nil)
+	_ api.MiddlewareProvider = (*Capture)(nil)
+)
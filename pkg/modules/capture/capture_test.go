@@ -0,0 +1,142 @@
+package capture
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestCapture_Descriptor(t *testing.T) {
+	descriptor := new(Capture).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Capture))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestCapture_Provision(t *testing.T) {
+	mod := new(Capture)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Capture).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enable {
+		t.Error("expected capture to be disabled by default")
+	}
+}
+
+func TestCapture_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		enable          bool
+		dir             string
+		ttl             time.Duration
+		cleanupInterval time.Duration
+		expectError     bool
+	}{
+		{
+			scenario: "disabled",
+		},
+		{
+			scenario:        "empty directory",
+			enable:          true,
+			ttl:             time.Duration(1) * time.Hour,
+			cleanupInterval: time.Duration(1) * time.Minute,
+			expectError:     true,
+		},
+		{
+			scenario:        "invalid TTL",
+			enable:          true,
+			dir:             "/tmp",
+			ttl:             -1,
+			cleanupInterval: time.Duration(1) * time.Minute,
+			expectError:     true,
+		},
+		{
+			scenario:    "invalid cleanup interval",
+			enable:      true,
+			dir:         "/tmp",
+			ttl:         time.Duration(1) * time.Hour,
+			expectError: true,
+		},
+		{
+			scenario:        "validate success",
+			enable:          true,
+			dir:             "/tmp",
+			ttl:             time.Duration(1) * time.Hour,
+			cleanupInterval: time.Duration(1) * time.Minute,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Capture{
+				enable:          tc.enable,
+				dir:             tc.dir,
+				ttl:             tc.ttl,
+				cleanupInterval: tc.cleanupInterval,
+			}
+
+			err := mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestCapture_cleanup(t *testing.T) {
+	mod := &Capture{
+		dir:    t.TempDir(),
+		ttl:    time.Duration(1) * time.Hour,
+		logger: zap.NewNop(),
+	}
+
+	for _, name := range []string{"expired", "recent"} {
+		err := os.Mkdir(filepath.Join(mod.dir, name), 0o700)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	expired := time.Now().Add(-time.Duration(2) * time.Hour)
+	err := os.Chtimes(filepath.Join(mod.dir, "expired"), expired, expired)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = mod.cleanup(time.Now())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(mod.dir, "expired"))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected expired capture to be deleted but got: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(mod.dir, "recent"))
+	if err != nil {
+		t.Errorf("expected recent capture to be kept but got: %v", err)
+	}
+}
@@ -0,0 +1,4 @@
+// Package capture provides a module which persists the failed requests, i.e.,
+// their form fields and files, to a directory for a limited time, so that the
+// "replay" command may send them again to a local Gotenberg instance.
+package capture
@@ -0,0 +1,75 @@
+package capture
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/replay"
+)
+
+// captureMiddleware persists a failed multipart request. It runs after the
+// webhook middleware, so that asynchronous conversions are captured too.
+func captureMiddleware(mod *Capture) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryLowPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					err := next(c)
+					if err == nil || errors.Is(err, api.ErrAsyncProcess) || errors.Is(err, api.ErrValidateOnly) {
+						return err
+					}
+
+					response := api.ParseErrorResponse(err)
+					if response.Status < mod.minStatus {
+						return err
+					}
+
+					ctx := c.Get("context").(*api.Context)
+
+					header := make(http.Header)
+					for key, values := range c.Request().Header {
+						if sensitiveHeaderRegexp.MatchString(key) {
+							continue
+						}
+
+						header[key] = values
+					}
+
+					capture := replay.Capture{
+						Time:   time.Now(),
+						Method: c.Request().Method,
+						Path:   c.Request().URL.Path,
+						Header: header,
+						Values: ctx.FormValues(),
+						Status: response.Status,
+						Error:  err.Error(),
+					}
+
+					trace, ok := c.Get("trace").(string)
+					if ok {
+						capture.Trace = trace
+					}
+
+					dir, saveErr := mod.save(capture, ctx.InputPaths())
+					if saveErr != nil {
+						// Not critical, the error is still returned.
+						ctx.Log().Error(fmt.Sprintf("capture failed request: %s", saveErr))
+
+						return err
+					}
+
+					ctx.Log().Info(fmt.Sprintf("failed request captured in '%s'", dir))
+
+					return err
+				}
+			}
+		}(),
+	}
+}
@@ -0,0 +1,114 @@
+package capture
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/replay"
+)
+
+func TestCaptureMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		err           error
+		expectCapture bool
+	}{
+		{
+			scenario: "success",
+		},
+		{
+			scenario: "asynchronous process",
+			err:      api.ErrAsyncProcess,
+		},
+		{
+			scenario: "client error",
+			err:      api.WrapError(errors.New("foo"), api.NewSentinelHttpError(http.StatusBadRequest, "foo")),
+		},
+		{
+			scenario:      "server error",
+			err:           errors.New("foo"),
+			expectCapture: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Capture{
+				dir:       t.TempDir(),
+				minStatus: http.StatusInternalServerError,
+				logger:    zap.NewNop(),
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/forms/libreoffice/convert", nil)
+			req.Header.Set("Authorization", "Bearer foo")
+			req.Header.Set("Gotenberg-Output-Filename", "foo")
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			c.Set("trace", "foo")
+
+			inputPath := filepath.Join(t.TempDir(), "foo.docx")
+			err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetLogger(zap.NewNop())
+			ctx.SetValues(map[string][]string{"landscape": {"true"}})
+			ctx.SetFiles(map[string]string{"foo.docx": inputPath})
+			c.Set("context", ctx.Context)
+
+			err = captureMiddleware(mod).Handler(func(c echo.Context) error {
+				return tc.err
+			})(c)
+
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v but got: %v", tc.err, err)
+			}
+
+			entries, err := os.ReadDir(mod.dir)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !tc.expectCapture {
+				if len(entries) != 0 {
+					t.Fatalf("expected no capture but got %d", len(entries))
+				}
+
+				return
+			}
+
+			if len(entries) != 1 {
+				t.Fatalf("expected 1 capture but got %d", len(entries))
+			}
+
+			capture, err := replay.Load(filepath.Join(mod.dir, entries[0].Name()))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if capture.Trace != "foo" {
+				t.Errorf("expected trace 'foo' but got '%s'", capture.Trace)
+			}
+
+			if capture.Header.Get("Authorization") != "" {
+				t.Error("expected no Authorization header")
+			}
+
+			if capture.Header.Get("Gotenberg-Output-Filename") != "foo" {
+				t.Error("expected Gotenberg-Output-Filename header")
+			}
+
+			if len(capture.Files) != 1 || capture.Files[0] != "foo.docx" {
+				t.Errorf("expected files [foo.docx] but got %v", capture.Files)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Chaos))
+}
+
+// Chaos is a module which injects the faults registered with its admin API.
+// The faults live in memory and vanish on restart.
+type Chaos struct {
+	enable              bool
+	disableRouteLogging bool
+
+	faults map[string]*fault
+	random func() float64
+	logger *zap.Logger
+	mu     sync.Mutex
+}
+
+// Descriptor returns a [Chaos]'s module descriptor.
+func (mod *Chaos) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "chaos",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("chaos", flag.ExitOnError)
+			fs.Bool("chaos-enable", false, "Enable the fault injection and its admin routes, which require the API admin token - never in production")
+			fs.Bool("chaos-disable-route-logging", false, "Disable the route logging")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Chaos) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Chaos) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("chaos-enable")
+	mod.disableRouteLogging = flags.MustBool("chaos-disable-route-logging")
+
+	mod.faults = make(map[string]*fault)
+	mod.random = rand.Float64
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Middlewares returns the middleware which injects the faults of the
+// conversions.
+func (mod *Chaos) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		chaosMiddleware(mod),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module        = (*Chaos)(nil)
+	_ gotenberg.Provisioner   = (*Chaos)(nil)
+	_ gotenberg.FaultInjector = (*Chaos)(nil)
+	_ api.MiddlewareProvider  = (*Chaos)(nil)
+	_ api.Router              = (*Chaos)(nil)
+)
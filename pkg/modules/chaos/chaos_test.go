@@ -0,0 +1,113 @@
+package chaos
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestChaos_Descriptor(t *testing.T) {
+	descriptor := new(Chaos).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Chaos))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestChaos_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectError bool
+	}{
+		{
+			scenario: "no logger provider",
+			ctx: func() *gotenberg.Context {
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Chaos).Descriptor().FlagSet,
+					},
+					nil,
+				)
+			}(),
+			expectError: true,
+		},
+		{
+			scenario: "provision success",
+			ctx: func() *gotenberg.Context {
+				provider := &struct {
+					gotenberg.ModuleMock
+					gotenberg.LoggerProviderMock
+				}{}
+				provider.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "foo", New: func() gotenberg.Module { return provider }}
+				}
+				provider.LoggerMock = func(mod gotenberg.Module) (*zap.Logger, error) {
+					return zap.NewNop(), nil
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Chaos).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{
+						provider.Descriptor(),
+					},
+				)
+			}(),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Chaos)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && mod.enable {
+				t.Error("expected the fault injection to be disabled by default")
+			}
+		})
+	}
+}
+
+func TestChaos_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario: "disabled",
+		},
+		{
+			scenario:          "enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Chaos{enable: tc.enable}
+
+			middlewares, err := mod.Middlewares()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(middlewares) != tc.expectMiddlewares {
+				t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+			}
+		})
+	}
+}
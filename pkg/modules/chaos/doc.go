@@ -0,0 +1,8 @@
+// Package chaos provides a module which injects failures on purpose, e.g.,
+// slow conversions, engine crashes or webhook timeouts, so that the platform
+// teams test their retry and alerting logic against a staging instance. An
+// admin API, which requires the API admin token, manages the faults at
+// runtime: the API does not start without it.
+//
+// This module must never be enabled in production.
+package chaos
@@ -0,0 +1,319 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+const (
+	// KindLatency slows down the conversions.
+	KindLatency = "latency"
+
+	// KindCrash fails the conversions as if the engine had crashed.
+	KindCrash = "crash"
+
+	// KindWebhookTimeout fails the calls to the webhooks as if they had
+	// timed out.
+	KindWebhookTimeout = "webhookTimeout"
+)
+
+var (
+	// ErrInjectedFault happens when a fault fails an operation.
+	ErrInjectedFault = errors.New("injected fault")
+
+	// ErrInvalidFault happens if the description of a fault is invalid.
+	ErrInvalidFault = errors.New("invalid fault")
+
+	// ErrFaultNotFound happens if there is no fault with a given ID.
+	ErrFaultNotFound = errors.New("fault not found")
+
+	// ErrFaultAlreadyExists happens when creating a fault with the ID of
+	// another one.
+	ErrFaultAlreadyExists = errors.New("fault already exists")
+)
+
+// faultIdRegexp restricts the IDs of the faults, as they are path parameters
+// of the admin API.
+var faultIdRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Fault describes a failure to inject.
+type Fault struct {
+	// ID identifies the fault. Default to a UUID.
+	ID string `json:"id"`
+
+	// Kind is either "latency", "crash" or "webhookTimeout".
+	Kind string `json:"kind"`
+
+	// Target restricts the fault to the routes whose path starts with it,
+	// e.g., "/forms/libreoffice", or to the webhooks whose URL starts with
+	// it. Default to all of them.
+	Target string `json:"target,omitempty"`
+
+	// Probability is the chance, between 0 and 1, that the fault applies to
+	// an operation. Default to 1.
+	Probability float64 `json:"probability,omitempty"`
+
+	// Delay is the time a latency fault adds, or the time a crash or a
+	// webhook timeout takes, e.g., "30s".
+	Delay string `json:"delay,omitempty"`
+
+	// Count is the maximum number of operations the fault applies to, after
+	// which it vanishes. Default to no limit.
+	Count int64 `json:"count,omitempty"`
+
+	// Duration is the time after which the fault vanishes, e.g., "1h".
+	// Default to no limit.
+	Duration string `json:"duration,omitempty"`
+}
+
+// FaultStatus is a fault with its injections.
+type FaultStatus struct {
+	Fault
+
+	// Injected is the number of operations the fault applied to.
+	Injected int64 `json:"injected"`
+
+	// CreatedAt is the creation time of the fault.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt is the time after which the fault vanishes, if any.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// fault is a registered [Fault].
+type fault struct {
+	Fault
+
+	delay     time.Duration
+	injected  int64
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// point returns the point of the operations the fault applies to.
+func (f *fault) point() string {
+	if f.Kind == KindWebhookTimeout {
+		return gotenberg.FaultPointWebhook
+	}
+
+	return gotenberg.FaultPointConversion
+}
+
+// active tells if the fault still applies.
+func (f *fault) active(now time.Time) bool {
+	if f.Count > 0 && f.injected >= f.Count {
+		return false
+	}
+
+	return f.expiresAt.IsZero() || now.Before(f.expiresAt)
+}
+
+// status returns the [FaultStatus] of the fault.
+func (f *fault) status() FaultStatus {
+	status := FaultStatus{
+		Fault:     f.Fault,
+		Injected:  f.injected,
+		CreatedAt: f.createdAt,
+	}
+
+	if !f.expiresAt.IsZero() {
+		expiresAt := f.expiresAt
+		status.ExpiresAt = &expiresAt
+	}
+
+	return status
+}
+
+// newFault validates a [Fault] and returns its registered counterpart.
+func newFault(f Fault, now time.Time) (*fault, error) {
+	if f.ID == "" {
+		f.ID = uuid.NewString()
+	}
+
+	if !faultIdRegexp.MatchString(f.ID) {
+		return nil, fmt.Errorf("%w: ID '%s' must only contain letters, digits, dots, underscores and hyphens", ErrInvalidFault, f.ID)
+	}
+
+	switch f.Kind {
+	case KindLatency, KindCrash, KindWebhookTimeout:
+	default:
+		return nil, fmt.Errorf("%w: kind '%s' is not '%s', '%s' or '%s'", ErrInvalidFault, f.Kind, KindLatency, KindCrash, KindWebhookTimeout)
+	}
+
+	if f.Probability == 0 {
+		f.Probability = 1
+	}
+
+	if f.Probability < 0 || f.Probability > 1 {
+		return nil, fmt.Errorf("%w: probability %g must be between 0 and 1", ErrInvalidFault, f.Probability)
+	}
+
+	if f.Count < 0 {
+		return nil, fmt.Errorf("%w: count %d must be at least 0", ErrInvalidFault, f.Count)
+	}
+
+	registered := &fault{
+		Fault:     f,
+		createdAt: now,
+	}
+
+	if f.Delay != "" {
+		delay, err := time.ParseDuration(f.Delay)
+		if err != nil || delay < 0 {
+			return nil, fmt.Errorf("%w: delay '%s' must be a positive duration", ErrInvalidFault, f.Delay)
+		}
+
+		registered.delay = delay
+	}
+
+	if f.Kind == KindLatency && registered.delay == 0 {
+		return nil, fmt.Errorf("%w: a latency requires a delay", ErrInvalidFault)
+	}
+
+	if f.Duration != "" {
+		duration, err := time.ParseDuration(f.Duration)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("%w: duration '%s' must be a positive duration", ErrInvalidFault, f.Duration)
+		}
+
+		registered.expiresAt = now.Add(duration)
+	}
+
+	return registered, nil
+}
+
+// InjectFault applies the active faults of a point whose target matches. The
+// latencies add up, and the first crash or webhook timeout fails the
+// operation.
+func (mod *Chaos) InjectFault(ctx context.Context, point, target string) error {
+	now := time.Now()
+
+	mod.mu.Lock()
+
+	var faults []fault
+
+	for id, f := range mod.faults {
+		if !f.active(now) {
+			delete(mod.faults, id)
+			continue
+		}
+
+		if f.point() != point || !strings.HasPrefix(target, f.Target) {
+			continue
+		}
+
+		if mod.random() >= f.Probability {
+			continue
+		}
+
+		f.injected++
+		faults = append(faults, *f)
+	}
+
+	mod.mu.Unlock()
+
+	// The oldest faults first, so that the outcome does not depend on the
+	// order of the map.
+	sort.Slice(faults, func(i, j int) bool {
+		return faults[i].createdAt.Before(faults[j].createdAt)
+	})
+
+	for _, f := range faults {
+		mod.logger.Warn(fmt.Sprintf("inject fault '%s' (%s) into '%s'", f.ID, f.Kind, target), zap.String("point", point))
+
+		if f.delay > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("wait for fault '%s': %w", f.ID, ctx.Err())
+			case <-time.After(f.delay):
+			}
+		}
+
+		switch f.Kind {
+		case KindCrash:
+			return fmt.Errorf("engine crashed: %w", ErrInjectedFault)
+		case KindWebhookTimeout:
+			return fmt.Errorf("webhook timed out: %w (%w)", context.DeadlineExceeded, ErrInjectedFault)
+		}
+	}
+
+	return nil
+}
+
+// put registers a fault.
+func (mod *Chaos) put(f Fault) (FaultStatus, error) {
+	registered, err := newFault(f, time.Now())
+	if err != nil {
+		return FaultStatus{}, err
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	_, ok := mod.faults[registered.ID]
+	if ok {
+		return FaultStatus{}, fmt.Errorf("fault '%s': %w", registered.ID, ErrFaultAlreadyExists)
+	}
+
+	mod.faults[registered.ID] = registered
+
+	return registered.status(), nil
+}
+
+// list returns the active faults, the oldest first.
+func (mod *Chaos) list() []FaultStatus {
+	now := time.Now()
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	statuses := make([]FaultStatus, 0, len(mod.faults))
+
+	for id, f := range mod.faults {
+		if !f.active(now) {
+			delete(mod.faults, id)
+			continue
+		}
+
+		statuses = append(statuses, f.status())
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].CreatedAt.Before(statuses[j].CreatedAt)
+	})
+
+	return statuses
+}
+
+// delete removes a fault.
+func (mod *Chaos) delete(id string) error {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	_, ok := mod.faults[id]
+	if !ok {
+		return fmt.Errorf("fault '%s': %w", id, ErrFaultNotFound)
+	}
+
+	delete(mod.faults, id)
+
+	return nil
+}
+
+// clear removes all the faults.
+func (mod *Chaos) clear() {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	mod.faults = make(map[string]*fault)
+}
@@ -0,0 +1,258 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func newTestChaos(random float64) *Chaos {
+	return &Chaos{
+		enable: true,
+		faults: make(map[string]*fault),
+		random: func() float64 {
+			return random
+		},
+		logger: zap.NewNop(),
+	}
+}
+
+func TestNewFault(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		fault       Fault
+		expectError bool
+	}{
+		{
+			scenario:    "invalid ID",
+			fault:       Fault{ID: "../foo", Kind: KindCrash},
+			expectError: true,
+		},
+		{
+			scenario:    "unknown kind",
+			fault:       Fault{Kind: "foo"},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid probability",
+			fault:       Fault{Kind: KindCrash, Probability: 1.5},
+			expectError: true,
+		},
+		{
+			scenario:    "negative count",
+			fault:       Fault{Kind: KindCrash, Count: -1},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid delay",
+			fault:       Fault{Kind: KindCrash, Delay: "foo"},
+			expectError: true,
+		},
+		{
+			scenario:    "latency without delay",
+			fault:       Fault{Kind: KindLatency},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid duration",
+			fault:       Fault{Kind: KindCrash, Duration: "0s"},
+			expectError: true,
+		},
+		{
+			scenario: "valid fault",
+			fault:    Fault{Kind: KindLatency, Target: "/forms/libreoffice", Delay: "10s", Count: 3, Duration: "1h"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			now := time.Now()
+
+			f, err := newFault(tc.fault, now)
+
+			if tc.expectError {
+				if !errors.Is(err, ErrInvalidFault) {
+					t.Fatalf("expected an invalid fault but got: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if f.ID == "" || f.Probability != 1 || f.delay != 10*time.Second || !f.expiresAt.Equal(now.Add(time.Hour)) {
+				t.Errorf("unexpected fault %+v", f)
+			}
+		})
+	}
+}
+
+func TestChaos_InjectFault(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		faults         []Fault
+		random         float64
+		point          string
+		target         string
+		expectError    bool
+		expectTimeout  bool
+		expectInjected map[string]int64
+	}{
+		{
+			scenario: "no fault",
+			point:    gotenberg.FaultPointConversion,
+			target:   "/forms/chromium/convert/url",
+		},
+		{
+			scenario:       "other target",
+			faults:         []Fault{{ID: "foo", Kind: KindCrash, Target: "/forms/libreoffice"}},
+			point:          gotenberg.FaultPointConversion,
+			target:         "/forms/chromium/convert/url",
+			expectInjected: map[string]int64{"foo": 0},
+		},
+		{
+			scenario:       "other point",
+			faults:         []Fault{{ID: "foo", Kind: KindWebhookTimeout}},
+			point:          gotenberg.FaultPointConversion,
+			target:         "/forms/chromium/convert/url",
+			expectInjected: map[string]int64{"foo": 0},
+		},
+		{
+			scenario:       "unlikely fault",
+			faults:         []Fault{{ID: "foo", Kind: KindCrash, Probability: 0.5}},
+			random:         0.7,
+			point:          gotenberg.FaultPointConversion,
+			target:         "/forms/chromium/convert/url",
+			expectInjected: map[string]int64{"foo": 0},
+		},
+		{
+			scenario:       "latency",
+			faults:         []Fault{{ID: "foo", Kind: KindLatency, Delay: "1ms"}},
+			point:          gotenberg.FaultPointConversion,
+			target:         "/forms/chromium/convert/url",
+			expectInjected: map[string]int64{"foo": 1},
+		},
+		{
+			scenario:       "crash",
+			faults:         []Fault{{ID: "foo", Kind: KindLatency, Delay: "1ms"}, {ID: "bar", Kind: KindCrash, Target: "/forms/chromium"}},
+			point:          gotenberg.FaultPointConversion,
+			target:         "/forms/chromium/convert/url",
+			expectError:    true,
+			expectInjected: map[string]int64{"foo": 1, "bar": 1},
+		},
+		{
+			scenario:       "webhook timeout",
+			faults:         []Fault{{ID: "foo", Kind: KindWebhookTimeout, Target: "https://example.com"}},
+			point:          gotenberg.FaultPointWebhook,
+			target:         "https://example.com/webhook",
+			expectError:    true,
+			expectTimeout:  true,
+			expectInjected: map[string]int64{"foo": 1},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := newTestChaos(tc.random)
+
+			for _, f := range tc.faults {
+				_, err := mod.put(f)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			err := mod.InjectFault(context.Background(), tc.point, tc.target)
+
+			if tc.expectError && !errors.Is(err, ErrInjectedFault) {
+				t.Fatalf("expected an injected fault but got: %v", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectTimeout && !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected a timeout but got: %v", err)
+			}
+
+			for id, expect := range tc.expectInjected {
+				if mod.faults[id].injected != expect {
+					t.Errorf("expected fault '%s' injected %d time(s) but got %d", id, expect, mod.faults[id].injected)
+				}
+			}
+		})
+	}
+}
+
+func TestChaos_InjectFault_cancelled(t *testing.T) {
+	mod := newTestChaos(0)
+
+	_, err := mod.put(Fault{Kind: KindLatency, Delay: "1h"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(10)*time.Millisecond)
+	defer cancel()
+
+	err = mod.InjectFault(ctx, gotenberg.FaultPointConversion, "/forms/chromium/convert/url")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a timeout but got: %v", err)
+	}
+}
+
+func TestChaos_faults(t *testing.T) {
+	mod := newTestChaos(0)
+
+	_, err := mod.put(Fault{ID: "foo", Kind: KindCrash, Count: 1})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = mod.put(Fault{ID: "foo", Kind: KindCrash})
+	if !errors.Is(err, ErrFaultAlreadyExists) {
+		t.Errorf("expected an existing fault but got: %v", err)
+	}
+
+	_, err = mod.put(Fault{ID: "bar", Kind: KindWebhookTimeout})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(mod.list()) != 2 {
+		t.Fatalf("expected 2 faults but got %d", len(mod.list()))
+	}
+
+	// A fault vanishes once its count is reached.
+	_ = mod.InjectFault(context.Background(), gotenberg.FaultPointConversion, "/forms/chromium/convert/url")
+
+	faults := mod.list()
+	if len(faults) != 1 || faults[0].ID != "bar" {
+		t.Fatalf("expected the fault 'bar' only but got %+v", faults)
+	}
+
+	err = mod.delete("foo")
+	if !errors.Is(err, ErrFaultNotFound) {
+		t.Errorf("expected a missing fault but got: %v", err)
+	}
+
+	err = mod.delete("bar")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = mod.put(Fault{Kind: KindCrash})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	mod.clear()
+
+	if len(mod.list()) != 0 {
+		t.Errorf("expected no faults but got %d", len(mod.list()))
+	}
+}
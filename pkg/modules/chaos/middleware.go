@@ -0,0 +1,41 @@
+package chaos
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// chaosMiddleware injects the faults of the conversions into the multipart
+// requests. It runs after the webhook middleware, so that the faults also
+// apply to the asynchronous conversions, and their failures go to the
+// webhooks.
+func chaosMiddleware(mod *Chaos) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryLowPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					ctx := c.Get("context").(*api.Context)
+
+					// The targets do not depend on the root path.
+					path := c.Request().URL.Path
+					if i := strings.Index(path, "/forms/"); i >= 0 {
+						path = path[i:]
+					}
+
+					err := mod.InjectFault(ctx, gotenberg.FaultPointConversion, path)
+					if err != nil {
+						return err
+					}
+
+					return next(c)
+				}
+			}
+		}(),
+	}
+}
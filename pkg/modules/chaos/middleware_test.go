@@ -0,0 +1,79 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestChaosMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		target      string
+		fault       Fault
+		expectError bool
+	}{
+		{
+			scenario: "other route",
+			target:   "/forms/chromium/convert/url",
+			fault:    Fault{Kind: KindCrash, Target: "/forms/libreoffice"},
+		},
+		{
+			scenario:    "crash",
+			target:      "/forms/libreoffice/convert",
+			fault:       Fault{Kind: KindCrash, Target: "/forms/libreoffice"},
+			expectError: true,
+		},
+		{
+			scenario:    "crash with a root path",
+			target:      "/foo/forms/libreoffice/convert",
+			fault:       Fault{Kind: KindCrash, Target: "/forms/libreoffice"},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := newTestChaos(0)
+
+			_, err := mod.put(tc.fault)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, tc.target, nil), httptest.NewRecorder())
+			c.Set("context", &api.Context{Context: context.Background()})
+
+			var nextCalled bool
+
+			err = chaosMiddleware(mod).Handler(func(c echo.Context) error {
+				nextCalled = true
+				return nil
+			})(c)
+
+			if tc.expectError {
+				if !errors.Is(err, ErrInjectedFault) {
+					t.Fatalf("expected an injected fault but got: %v", err)
+				}
+
+				if nextCalled {
+					t.Error("expected the conversion not to run")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !nextCalled {
+				t.Error("expected the conversion to run")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package chaos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// Routes returns the admin routes for managing the faults.
+func (mod *Chaos) Routes() ([]api.Route, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/admin/chaos/faults",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, mod.list())
+			},
+		},
+		{
+			Method:         http.MethodPost,
+			Path:           "/admin/chaos/faults",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				decoder := json.NewDecoder(c.Request().Body)
+				decoder.DisallowUnknownFields()
+
+				var f Fault
+
+				err := decoder.Decode(&f)
+				if err != nil {
+					return faultError(fmt.Errorf("decode fault: %w: %w", ErrInvalidFault, err))
+				}
+
+				status, err := mod.put(f)
+				if err != nil {
+					return faultError(fmt.Errorf("create fault: %w", err))
+				}
+
+				return c.JSON(http.StatusCreated, status)
+			},
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/admin/chaos/faults",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				mod.clear()
+
+				return c.NoContent(http.StatusNoContent)
+			},
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/admin/chaos/faults/:id",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				err := mod.delete(c.Param("id"))
+				if err != nil {
+					return faultError(fmt.Errorf("delete fault: %w", err))
+				}
+
+				return c.NoContent(http.StatusNoContent)
+			},
+		},
+	}, nil
+}
+
+// faultError wraps the errors of the admin API the client is responsible
+// for, so that they have a meaningful HTTP status.
+func faultError(err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidFault):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid fault: %s", err)).WithCode(api.ErrorCodeChaosInvalidFault),
+		)
+	case errors.Is(err, ErrFaultNotFound):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusNotFound, "Fault not found").WithCode(api.ErrorCodeChaosFaultNotFound),
+		)
+	case errors.Is(err, ErrFaultAlreadyExists):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusConflict, "A fault with this ID already exists").WithCode(api.ErrorCodeChaosFaultAlreadyExists),
+		)
+	}
+
+	return err
+}
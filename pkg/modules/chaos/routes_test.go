@@ -0,0 +1,151 @@
+package chaos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestChaos_Routes(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		routes, err := new(Chaos).Routes()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(routes) != 0 {
+			t.Errorf("expected no route but got %d", len(routes))
+		}
+	})
+
+	mod := newTestChaos(0)
+
+	routes, err := mod.Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 4 {
+		t.Fatalf("expected 4 routes but got %d", len(routes))
+	}
+
+	for _, route := range routes {
+		if !route.IsAdmin {
+			t.Errorf("expected '%s %s' to be an admin route", route.Method, route.Path)
+		}
+	}
+
+	call := func(method, path, id, body string) (int, string) {
+		for _, route := range routes {
+			if route.Method != method || route.Path != path {
+				continue
+			}
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(method, path, strings.NewReader(body)), rec)
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+
+			err := route.Handler(c)
+			if err != nil {
+				response := api.ParseErrorResponse(err)
+
+				return response.Status, response.Code
+			}
+
+			return rec.Code, rec.Body.String()
+		}
+
+		t.Fatalf("expected a route '%s %s'", method, path)
+
+		return 0, ""
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		method       string
+		path         string
+		id           string
+		body         string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			scenario:     "invalid JSON",
+			method:       http.MethodPost,
+			path:         "/admin/chaos/faults",
+			body:         `{"foo":"bar"}`,
+			expectStatus: http.StatusBadRequest,
+			expectBody:   "CHAOS_INVALID_FAULT",
+		},
+		{
+			scenario:     "invalid fault",
+			method:       http.MethodPost,
+			path:         "/admin/chaos/faults",
+			body:         `{"kind":"foo"}`,
+			expectStatus: http.StatusBadRequest,
+			expectBody:   "CHAOS_INVALID_FAULT",
+		},
+		{
+			scenario:     "create fault",
+			method:       http.MethodPost,
+			path:         "/admin/chaos/faults",
+			body:         `{"id":"foo","kind":"latency","target":"/forms/libreoffice","delay":"5s","probability":0.5}`,
+			expectStatus: http.StatusCreated,
+			expectBody:   `"id":"foo","kind":"latency"`,
+		},
+		{
+			scenario:     "fault already exists",
+			method:       http.MethodPost,
+			path:         "/admin/chaos/faults",
+			body:         `{"id":"foo","kind":"crash"}`,
+			expectStatus: http.StatusConflict,
+			expectBody:   "CHAOS_FAULT_ALREADY_EXISTS",
+		},
+		{
+			scenario:     "list faults",
+			method:       http.MethodGet,
+			path:         "/admin/chaos/faults",
+			expectStatus: http.StatusOK,
+			expectBody:   `[{"id":"foo"`,
+		},
+		{
+			scenario:     "delete fault",
+			method:       http.MethodDelete,
+			path:         "/admin/chaos/faults/:id",
+			id:           "foo",
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			scenario:     "delete unknown fault",
+			method:       http.MethodDelete,
+			path:         "/admin/chaos/faults/:id",
+			id:           "foo",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "CHAOS_FAULT_NOT_FOUND",
+		},
+		{
+			scenario:     "clear faults",
+			method:       http.MethodDelete,
+			path:         "/admin/chaos/faults",
+			expectStatus: http.StatusNoContent,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			status, body := call(tc.method, tc.path, tc.id, tc.body)
+
+			if status != tc.expectStatus {
+				t.Errorf("expected status %d but got %d", tc.expectStatus, status)
+			}
+
+			if !strings.Contains(body, tc.expectBody) {
+				t.Errorf("expected body to contain '%s' but got '%s'", tc.expectBody, body)
+			}
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/chromedp/cdproto/fetch"
@@ -37,6 +40,8 @@ type browserArguments struct {
 	hostResolverRules        string
 	proxyServer              string
 	wsUrlReadTimeout         time.Duration
+	cgroupLimits             gotenberg.CgroupLimits
+	sandbox                  gotenberg.SandboxOptions
 
 	// Tasks specific.
 	allowList         *regexp2.Regexp
@@ -44,6 +49,7 @@ type browserArguments struct {
 	clearCache        bool
 	clearCookies      bool
 	disableJavaScript bool
+	streamChunkSize   int
 }
 
 type chromiumBrowser struct {
@@ -51,6 +57,7 @@ type chromiumBrowser struct {
 	ctx                context.Context
 	cancelFunc         context.CancelFunc
 	userProfileDirPath string
+	cgroup             *gotenberg.Cgroup
 	ctxMu              sync.RWMutex
 	isStarted          atomic.Bool
 
@@ -92,6 +99,22 @@ func (b *chromiumBrowser) Start(logger *zap.Logger) error {
 		chromedp.UserDataDir(b.userProfileDirPath),
 	)
 
+	if b.arguments.sandbox.Uid > 0 {
+		// The user profile directory is also the private temporary
+		// directory of the browser.
+		err := os.MkdirAll(b.userProfileDirPath, 0o700)
+		if err != nil {
+			return fmt.Errorf("create browser user profile directory: %w", err)
+		}
+
+		err = b.arguments.sandbox.Own(b.userProfileDirPath)
+		if err != nil {
+			return fmt.Errorf("own browser user profile directory: %w", err)
+		}
+
+		opts = append(opts, chromedp.Env(fmt.Sprintf("TMPDIR=%s", b.userProfileDirPath)))
+	}
+
 	if b.arguments.incognito {
 		opts = append(opts, chromedp.Flag("incognito", b.arguments.incognito))
 	}
@@ -127,13 +150,62 @@ func (b *chromiumBrowser) Start(logger *zap.Logger) error {
 	// See https://github.com/gotenberg/gotenberg/issues/524.
 	opts = append(opts, chromedp.WSURLReadTimeout(b.arguments.wsUrlReadTimeout))
 
+	var (
+		cgroup    *gotenberg.Cgroup
+		cgroupDir *os.File
+	)
+
+	if b.arguments.cgroupLimits.Enabled() {
+		var err error
+		cgroup, err = gotenberg.NewCgroup(b.arguments.cgroupLimits)
+		if err != nil {
+			return fmt.Errorf("create browser cgroup: %w", err)
+		}
+	}
+
+	if cgroup != nil || b.arguments.sandbox.Enabled() {
+		opts = append(opts, chromedp.ModifyCmdFunc(func(cmd *exec.Cmd) {
+			// Modifying the command disables the chromedp defaults, hence
+			// the parent death signal.
+			cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGKILL}
+
+			err := b.arguments.sandbox.Apply(cmd)
+			if err != nil {
+				logger.Error(fmt.Sprintf("apply browser sandbox: %s", err))
+			}
+
+			if cgroup == nil {
+				return
+			}
+
+			dir, err := cgroup.Apply(cmd.SysProcAttr)
+			if err != nil {
+				logger.Error(fmt.Sprintf("apply browser cgroup: %s", err))
+				return
+			}
+
+			cgroupDir = dir
+		}))
+	}
+
 	allocatorCtx, allocatorCancel := chromedp.NewExecAllocator(b.initialCtx, opts...)
 	ctx, cancel := chromedp.NewContext(allocatorCtx, chromedp.WithDebugf(debug.Printf))
 
 	err := chromedp.Run(ctx)
+
+	if cgroupDir != nil {
+		// The browser has started, the cgroup directory is not required
+		// anymore.
+		closeErr := cgroupDir.Close()
+		if closeErr != nil {
+			logger.Error(fmt.Sprintf("close browser cgroup directory: %s", closeErr))
+		}
+	}
+
 	if err != nil {
 		cancel()
 		allocatorCancel()
+		removeCgroup(logger, cgroup)
 		return fmt.Errorf("run exec allocator: %w", err)
 	}
 
@@ -147,6 +219,7 @@ func (b *chromiumBrowser) Start(logger *zap.Logger) error {
 		cancel()
 		allocatorCancel()
 	}
+	b.cgroup = cgroup
 	b.isStarted.Store(true)
 
 	return nil
@@ -180,13 +253,28 @@ func (b *chromiumBrowser) Stop(logger *zap.Logger) error {
 	defer b.ctxMu.Unlock()
 
 	b.cancelFunc()
+	removeCgroup(logger, b.cgroup)
+
 	b.ctx = nil
 	b.userProfileDirPath = ""
+	b.cgroup = nil
 	b.isStarted.Store(false)
 
 	return nil
 }
 
+// removeCgroup removes the cgroup of a browser, if any.
+func removeCgroup(logger *zap.Logger, cgroup *gotenberg.Cgroup) {
+	if cgroup == nil {
+		return
+	}
+
+	err := cgroup.Remove()
+	if err != nil {
+		logger.Error(fmt.Sprintf("remove browser cgroup: %s", err))
+	}
+}
+
 func (b *chromiumBrowser) Healthy(logger *zap.Logger) bool {
 	// Good to know: the supervisor does not call this method if no first start
 	// or if the process is restarting.
@@ -215,6 +303,11 @@ func (b *chromiumBrowser) Healthy(logger *zap.Logger) bool {
 }
 
 func (b *chromiumBrowser) pdf(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+	err := b.ownWorkingDir(outputPath)
+	if err != nil {
+		return err
+	}
+
 	// Note: no error wrapping because it leaks on errors we want to display to
 	// the end user.
 	return b.do(ctx, logger, url, options.Options, chromedp.Tasks{
@@ -232,11 +325,16 @@ func (b *chromiumBrowser) pdf(ctx context.Context, logger *zap.Logger, url, outp
 		waitDelayBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitDelay),
 		waitForExpressionBeforePrintActionFunc(logger, b.arguments.disableJavaScript, options.WaitForExpression),
 		// PDF specific.
-		printToPdfActionFunc(logger, outputPath, options),
+		printToPdfActionFunc(logger, outputPath, options, b.arguments.streamChunkSize),
 	})
 }
 
 func (b *chromiumBrowser) screenshot(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+	err := b.ownWorkingDir(outputPath)
+	if err != nil {
+		return err
+	}
+
 	// Note: no error wrapping because it leaks on errors we want to display to
 	// the end user.
 	return b.do(ctx, logger, url, options.Options, chromedp.Tasks{
@@ -247,6 +345,7 @@ func (b *chromiumBrowser) screenshot(ctx context.Context, logger *zap.Logger, ur
 		clearCookiesActionFunc(logger, b.arguments.clearCookies),
 		disableJavaScriptActionFunc(logger, b.arguments.disableJavaScript),
 		extraHttpHeadersActionFunc(logger, options.ExtraHttpHeaders),
+		setDeviceMetricsOverrideActionFunc(logger, options.Width, options.Height),
 		navigateActionFunc(logger, url, options.SkipNetworkIdleEvent),
 		hideDefaultWhiteBackgroundActionFunc(logger, options.OmitBackground, true),
 		forceExactColorsActionFunc(),
@@ -258,6 +357,17 @@ func (b *chromiumBrowser) screenshot(ctx context.Context, logger *zap.Logger, ur
 	})
 }
 
+// ownWorkingDir gives the directory of the output file, which also holds
+// the files of the request, to the dedicated user of the browser, if any.
+func (b *chromiumBrowser) ownWorkingDir(outputPath string) error {
+	err := b.arguments.sandbox.Own(filepath.Dir(outputPath))
+	if err != nil {
+		return fmt.Errorf("own working directory: %w", err)
+	}
+
+	return nil
+}
+
 func (b *chromiumBrowser) do(ctx context.Context, logger *zap.Logger, url string, options Options, tasks chromedp.Tasks) error {
 	if !b.isStarted.Load() {
 		return errors.New("browser not started, cannot handle tasks")
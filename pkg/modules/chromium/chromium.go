@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/alexliesenfeld/health"
+	"github.com/labstack/gommon/bytes"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/zap"
 
@@ -62,6 +63,10 @@ type Chromium struct {
 	autoStart     bool
 	disableRoutes bool
 	args          browserArguments
+	markup        markupConverter
+
+	// ghostscriptBinPath is optional, for fitting the PDFs to a size budget.
+	ghostscriptBinPath string
 
 	logger     *zap.Logger
 	browser    browser
@@ -233,6 +238,19 @@ func DefaultPdfOptions() PdfOptions {
 type ScreenshotOptions struct {
 	Options
 
+	// Width is the device screen width, in pixels.
+	// Optional.
+	Width int
+
+	// Height is the device screen height, in pixels.
+	// Optional.
+	Height int
+
+	// Clip defines whether to clip the screenshot according to the device
+	// dimensions.
+	// Optional.
+	Clip bool
+
 	// Format is the image compression format, either "png" or "jpeg" or
 	// "webp".
 	// Optional.
@@ -252,6 +270,9 @@ type ScreenshotOptions struct {
 func DefaultScreenshotOptions() ScreenshotOptions {
 	return ScreenshotOptions{
 		Options:          DefaultOptions(),
+		Width:            800,
+		Height:           600,
+		Clip:             false,
 		Format:           "png",
 		Quality:          100,
 		OptimizeForSpeed: false,
@@ -297,6 +318,16 @@ func (mod *Chromium) Descriptor() gotenberg.ModuleDescriptor {
 			fs.Bool("chromium-clear-cache", false, "Clear Chromium cache between each conversion")
 			fs.Bool("chromium-clear-cookies", false, "Clear Chromium cookies between each conversion")
 			fs.Bool("chromium-disable-javascript", false, "Disable JavaScript")
+			fs.String("chromium-cgroup-parent", "", "Set the path of a delegated cgroup (v2) in which each Chromium browser runs in its own cgroup - e.g., /sys/fs/cgroup/gotenberg")
+			fs.String("chromium-cgroup-memory-max", "0B", "Set the maximum memory of a Chromium browser cgroup. Set to 0 to disable this limit")
+			fs.Float64("chromium-cgroup-cpu-max", 0, "Set the maximum number of CPUs of a Chromium browser cgroup, e.g., 0.5. Set to 0 to disable this limit")
+			fs.Int64("chromium-cgroup-pids-max", 0, "Set the maximum number of processes of a Chromium browser cgroup. Set to 0 to disable this limit")
+			fs.StringSlice("chromium-sandbox-namespaces", make([]string, 0), "Set the namespaces each Chromium browser runs in, among user, ipc, pid and uts - the other namespaces require the user namespace when not running as root")
+			fs.Bool("chromium-sandbox-seccomp", false, "Run each Chromium browser under a seccomp filter which denies the syscalls a document converter has no use for, e.g., mount or ptrace")
+			fs.StringSlice("chromium-sandbox-seccomp-deny", make([]string, 0), "Set the syscalls the seccomp filter of the Chromium browsers denies on top of the default ones")
+			fs.Int("chromium-sandbox-uid", 0, "Set the dedicated user ID the Chromium browsers run as, with private working directories - requires Gotenberg to run as root. Set to 0 to disable this feature")
+			fs.Int("chromium-sandbox-gid", 0, "Set the dedicated group ID the Chromium browsers run as. Set to 0 to disable this feature")
+			fs.String("chromium-stream-chunk-size", "1MB", "Set the size of the buffer used to copy a PDF from Chromium to disk - a bigger buffer means fewer round trips but more memory")
 			fs.Bool("chromium-disable-routes", false, "Disable the routes")
 
 			return fs
@@ -316,6 +347,29 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 		return errors.New("CHROMIUM_BIN_PATH environment variable is not set")
 	}
 
+	// Optional, the markdown routes do not convert AsciiDoc and
+	// reStructuredText files otherwise.
+	asciidoctorBinPath, _ := os.LookupEnv("ASCIIDOCTOR_BIN_PATH")
+	rst2htmlBinPath, _ := os.LookupEnv("RST2HTML_BIN_PATH")
+	mod.markup = markupConverter{
+		asciidoctorBinPath: asciidoctorBinPath,
+		rst2htmlBinPath:    rst2htmlBinPath,
+	}
+
+	// Optional, the convert routes do not fit the PDFs to a size budget
+	// otherwise.
+	mod.ghostscriptBinPath, _ = os.LookupEnv("GHOSTSCRIPT_BIN_PATH")
+
+	cgroupMemoryMax, err := bytes.Parse(flags.MustHumanReadableBytesString("chromium-cgroup-memory-max"))
+	if err != nil {
+		return fmt.Errorf("parse cgroup maximum memory: %w", err)
+	}
+
+	streamChunkSize, err := bytes.Parse(flags.MustHumanReadableBytesString("chromium-stream-chunk-size"))
+	if err != nil {
+		return fmt.Errorf("parse stream chunk size: %w", err)
+	}
+
 	mod.args = browserArguments{
 		binPath:                  binPath,
 		incognito:                flags.MustBool("chromium-incognito"),
@@ -326,12 +380,26 @@ func (mod *Chromium) Provision(ctx *gotenberg.Context) error {
 		hostResolverRules:        flags.MustString("chromium-host-resolver-rules"),
 		proxyServer:              flags.MustString("chromium-proxy-server"),
 		wsUrlReadTimeout:         flags.MustDuration("chromium-start-timeout"),
+		cgroupLimits: gotenberg.CgroupLimits{
+			ParentPath: flags.MustString("chromium-cgroup-parent"),
+			MemoryMax:  cgroupMemoryMax,
+			CpuMax:     flags.MustFloat64("chromium-cgroup-cpu-max"),
+			PidsMax:    flags.MustInt64("chromium-cgroup-pids-max"),
+		},
+		sandbox: gotenberg.SandboxOptions{
+			Namespaces:  flags.MustStringSlice("chromium-sandbox-namespaces"),
+			Seccomp:     flags.MustBool("chromium-sandbox-seccomp"),
+			SeccompDeny: flags.MustStringSlice("chromium-sandbox-seccomp-deny"),
+			Uid:         flags.MustInt("chromium-sandbox-uid"),
+			Gid:         flags.MustInt("chromium-sandbox-gid"),
+		},
 
 		allowList:         flags.MustRegexp("chromium-allow-list"),
 		denyList:          flags.MustRegexp("chromium-deny-list"),
 		clearCache:        flags.MustBool("chromium-clear-cache"),
 		clearCookies:      flags.MustBool("chromium-clear-cookies"),
 		disableJavaScript: flags.MustBool("chromium-disable-javascript"),
+		streamChunkSize:   int(streamChunkSize),
 	}
 
 	// Logger.
@@ -370,6 +438,37 @@ func (mod *Chromium) Validate() error {
 		return fmt.Errorf("chromium binary path does not exist: %w", err)
 	}
 
+	if mod.markup.asciidoctorBinPath != "" {
+		_, err = os.Stat(mod.markup.asciidoctorBinPath)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("asciidoctor binary path does not exist: %w", err)
+		}
+	}
+
+	if mod.markup.rst2htmlBinPath != "" {
+		_, err = os.Stat(mod.markup.rst2htmlBinPath)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("rst2html binary path does not exist: %w", err)
+		}
+	}
+
+	if mod.ghostscriptBinPath != "" {
+		_, err = os.Stat(mod.ghostscriptBinPath)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("ghostscript binary path does not exist: %w", err)
+		}
+	}
+
+	err = mod.args.cgroupLimits.Validate()
+	if err != nil {
+		return fmt.Errorf("validate cgroup limits: %w", err)
+	}
+
+	err = mod.args.sandbox.Validate()
+	if err != nil {
+		return fmt.Errorf("validate sandbox: %w", err)
+	}
+
 	return nil
 }
 
@@ -449,6 +548,16 @@ func (mod *Chromium) Checks() ([]health.CheckerOption, error) {
 	}, nil
 }
 
+// Debug returns the version of Chromium.
+func (mod *Chromium) Debug(ctx context.Context) (map[string]any, error) {
+	version, err := gotenberg.BinaryVersion(ctx, mod.logger, mod.args.binPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"version": version}, nil
+}
+
 // Ready returns no error if the module is ready.
 func (mod *Chromium) Ready() error {
 	if !mod.autoStart {
@@ -490,12 +599,15 @@ func (mod *Chromium) Routes() ([]api.Route, error) {
 	}
 
 	return []api.Route{
-		convertUrlRoute(mod, mod.engine),
+		convertUrlRoute(mod, mod.engine, mod.ghostscriptBinPath),
 		screenshotUrlRoute(mod),
-		convertHtmlRoute(mod, mod.engine),
+		convertHtmlRoute(mod, mod.engine, mod.ghostscriptBinPath),
 		screenshotHtmlRoute(mod),
-		convertMarkdownRoute(mod, mod.engine),
-		screenshotMarkdownRoute(mod),
+		convertMarkdownRoute(mod, mod.engine, mod.markup, mod.ghostscriptBinPath),
+		screenshotMarkdownRoute(mod, mod.markup),
+		convertSvgRoute(mod, mod.engine, mod.ghostscriptBinPath),
+		screenshotSvgRoute(mod),
+		convertCsvRoute(mod, mod.engine, mod.ghostscriptBinPath),
 	}, nil
 }
 
@@ -523,6 +635,7 @@ var (
 	_ gotenberg.Validator       = (*Chromium)(nil)
 	_ gotenberg.App             = (*Chromium)(nil)
 	_ gotenberg.MetricsProvider = (*Chromium)(nil)
+	_ gotenberg.Debuggable      = (*Chromium)(nil)
 	_ api.HealthChecker         = (*Chromium)(nil)
 	_ api.Router                = (*Chromium)(nil)
 	_ Api                       = (*Chromium)(nil)
@@ -179,6 +179,7 @@ func TestChromium_Validate(t *testing.T) {
 	for _, tc := range []struct {
 		scenario    string
 		binPath     string
+		markup      markupConverter
 		expectError bool
 	}{
 		{
@@ -191,6 +192,18 @@ func TestChromium_Validate(t *testing.T) {
 			binPath:     "/foo",
 			expectError: true,
 		},
+		{
+			scenario:    "asciidoctor bin path does not exist",
+			binPath:     os.Args[0],
+			markup:      markupConverter{asciidoctorBinPath: "/foo"},
+			expectError: true,
+		},
+		{
+			scenario:    "rst2html bin path does not exist",
+			binPath:     os.Args[0],
+			markup:      markupConverter{rst2htmlBinPath: "/foo"},
+			expectError: true,
+		},
 		{
 			scenario:    "validate success",
 			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
@@ -202,6 +215,7 @@ func TestChromium_Validate(t *testing.T) {
 			mod.args = browserArguments{
 				binPath: tc.binPath,
 			}
+			mod.markup = tc.markup
 			err := mod.Validate()
 
 			if !tc.expectError && err != nil {
@@ -461,7 +475,7 @@ func TestChromium_Routes(t *testing.T) {
 	}{
 		{
 			scenario:      "routes not disabled",
-			expectRoutes:  6,
+			expectRoutes:  9,
 			disableRoutes: false,
 		},
 		{
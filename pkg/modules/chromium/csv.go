@@ -0,0 +1,240 @@
+package chromium
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// ErrInvalidCsv happens if a file is not a valid CSV or TSV document.
+var ErrInvalidCsv = errors.New("invalid CSV")
+
+// csvTableOptions gathers the available options for rendering CSV and TSV
+// files as tables.
+type csvTableOptions struct {
+	// Delimiter is the field delimiter. If zero, it is a tab for TSV files
+	// and a comma for CSV files.
+	Delimiter rune
+
+	// Header defines whether the first row is the header of the table.
+	Header bool
+
+	// RepeatHeader defines whether to repeat the header on each page.
+	RepeatHeader bool
+
+	// ColumnWidths are the relative widths of the columns, from the first
+	// one. The other columns have a relative width of 1. If empty, the
+	// widths depend on the content.
+	ColumnWidths []float64
+
+	// FontSize is the font size, in points.
+	FontSize float64
+
+	// Striped defines whether to alternate the background of the rows.
+	Striped bool
+
+	// Borders defines whether to draw the borders of the cells.
+	Borders bool
+}
+
+// defaultCsvTableOptions returns the default values for [csvTableOptions].
+func defaultCsvTableOptions() csvTableOptions {
+	return csvTableOptions{
+		Delimiter:    0,
+		Header:       true,
+		RepeatHeader: true,
+		ColumnWidths: nil,
+		FontSize:     10,
+		Striped:      true,
+		Borders:      true,
+	}
+}
+
+// csvTable is a CSV or TSV file, ready for the template.
+type csvTable struct {
+	Widths []string
+	Header []string
+	Rows   [][]string
+}
+
+// readCsvTable reads a CSV or TSV file. Rows shorter than the longest one are
+// padded with empty cells.
+func readCsvTable(path string, opts csvTableOptions) (csvTable, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return csvTable{}, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	br := bufio.NewReader(f)
+	// Spreadsheet applications often write a UTF-8 byte order mark.
+	bom, err := br.Peek(3)
+	if err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		_, _ = br.Discard(3)
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+
+	reader.Comma = opts.Delimiter
+	if reader.Comma == 0 {
+		reader.Comma = ','
+		if strings.EqualFold(filepath.Ext(path), ".tsv") {
+			reader.Comma = '\t'
+		}
+	}
+
+	var (
+		rows    [][]string
+		columns int
+	)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return csvTable{}, fmt.Errorf("read record: %v: %w", err, ErrInvalidCsv)
+		}
+
+		rows = append(rows, record)
+		columns = max(columns, len(record))
+	}
+
+	for i, row := range rows {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		rows[i] = row
+	}
+
+	var table csvTable
+
+	if opts.Header && len(rows) > 0 {
+		table.Header = rows[0]
+		rows = rows[1:]
+	}
+	table.Rows = rows
+
+	if len(opts.ColumnWidths) > 0 && columns > 0 {
+		weights := make([]float64, columns)
+		total := 0.0
+		for i := range weights {
+			weights[i] = 1
+			if i < len(opts.ColumnWidths) {
+				weights[i] = opts.ColumnWidths[i]
+			}
+			total += weights[i]
+		}
+
+		table.Widths = make([]string, columns)
+		for i, weight := range weights {
+			table.Widths[i] = strconv.FormatFloat(weight/total*100, 'f', 3, 64)
+		}
+	}
+
+	return table, nil
+}
+
+var csvTemplate = template.Must(template.New("csv").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>
+  html, body { margin: 0; padding: 0; }
+  body { font-family: sans-serif; font-size: {{ .FontSize }}pt; -webkit-print-color-adjust: exact; print-color-adjust: exact; }
+  table { width: 100%; border-collapse: collapse; break-after: page; }
+  table:last-of-type { break-after: auto; }
+  {{- if .FixedLayout }}
+  table.fixed { table-layout: fixed; }
+  {{- end }}
+  thead { display: {{ if .RepeatHeader }}table-header-group{{ else }}table-row-group{{ end }}; }
+  tr { break-inside: avoid; }
+  th, td { padding: 0.25em 0.5em; text-align: left; vertical-align: top; overflow-wrap: anywhere; white-space: pre-wrap; }
+  th { font-weight: bold; background: #e6e6e6; }
+  {{- if .Borders }}
+  th, td { border: 1px solid #999999; }
+  {{- end }}
+  {{- if .Striped }}
+  tbody tr:nth-child(even) { background: #f5f5f5; }
+  {{- end }}
+</style>
+</head>
+<body>
+{{- range .Tables }}
+<table{{ if .Widths }} class="fixed"{{ end }}>
+{{- if .Widths }}
+<colgroup>{{ range .Widths }}<col style="width: {{ . }}%">{{ end }}</colgroup>
+{{- end }}
+{{- if .Header }}
+<thead><tr>{{ range .Header }}<th>{{ . }}</th>{{ end }}</tr></thead>
+{{- end }}
+<tbody>
+{{- range .Rows }}
+<tr>{{ range . }}<td>{{ . }}</td>{{ end }}</tr>
+{{- end }}
+</tbody>
+</table>
+{{- end }}
+</body>
+</html>
+`))
+
+// csvToHtml writes an HTML document which displays CSV or TSV files as
+// tables, one table per file, each starting on a new page, and returns its
+// URL.
+func csvToHtml(ctx *api.Context, inputPaths []string, opts csvTableOptions) (string, error) {
+	tables := make([]csvTable, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		table, err := readCsvTable(inputPath, opts)
+		if err != nil {
+			return "", fmt.Errorf("read '%s': %w", inputPath, err)
+		}
+
+		tables[i] = table
+	}
+
+	var buf bytes.Buffer
+	err := csvTemplate.Execute(&buf, struct {
+		Tables       []csvTable
+		FontSize     string
+		FixedLayout  bool
+		RepeatHeader bool
+		Striped      bool
+		Borders      bool
+	}{
+		Tables:       tables,
+		FontSize:     strconv.FormatFloat(opts.FontSize, 'f', -1, 64),
+		FixedLayout:  len(opts.ColumnWidths) > 0,
+		RepeatHeader: opts.RepeatHeader,
+		Striped:      opts.Striped,
+		Borders:      opts.Borders,
+	})
+	if err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+
+	htmlPath := ctx.GeneratePath("", ".html")
+
+	err = os.WriteFile(htmlPath, buf.Bytes(), 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write HTML document: %w", err)
+	}
+
+	return fmt.Sprintf("file://%s", htmlPath), nil
+}
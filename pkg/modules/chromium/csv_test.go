@@ -0,0 +1,207 @@
+package chromium
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestReadCsvTable(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		filename    string
+		content     string
+		options     func(opts csvTableOptions) csvTableOptions
+		expectTable csvTable
+		expectError bool
+	}{
+		{
+			scenario: "CSV with header",
+			filename: "foo.csv",
+			content:  "\xef\xbb\xbfname,age\nfoo,1\n\"bar, baz\",2\n",
+			expectTable: csvTable{
+				Header: []string{"name", "age"},
+				Rows:   [][]string{{"foo", "1"}, {"bar, baz", "2"}},
+			},
+		},
+		{
+			scenario: "TSV without header",
+			filename: "foo.tsv",
+			content:  "foo\t1\nbar\t2\n",
+			options: func(opts csvTableOptions) csvTableOptions {
+				opts.Header = false
+				return opts
+			},
+			expectTable: csvTable{
+				Rows: [][]string{{"foo", "1"}, {"bar", "2"}},
+			},
+		},
+		{
+			scenario: "custom delimiter",
+			filename: "foo.csv",
+			content:  "a;b\nc;d\n",
+			options: func(opts csvTableOptions) csvTableOptions {
+				opts.Delimiter = ';'
+				return opts
+			},
+			expectTable: csvTable{
+				Header: []string{"a", "b"},
+				Rows:   [][]string{{"c", "d"}},
+			},
+		},
+		{
+			scenario: "ragged rows and column widths",
+			filename: "foo.csv",
+			content:  "a,b,c\nd\n",
+			options: func(opts csvTableOptions) csvTableOptions {
+				opts.ColumnWidths = []float64{2}
+				return opts
+			},
+			expectTable: csvTable{
+				Widths: []string{"50.000", "25.000", "25.000"},
+				Header: []string{"a", "b", "c"},
+				Rows:   [][]string{{"d", "", ""}},
+			},
+		},
+		{
+			scenario:    "empty file",
+			filename:    "foo.csv",
+			content:     "",
+			expectTable: csvTable{},
+		},
+		{
+			scenario:    "bare quote",
+			filename:    "foo.csv",
+			content:     "a,b\"c\n",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid delimiter",
+			filename:    "foo.csv",
+			content:     "a,b\n",
+			expectError: true,
+			options: func(opts csvTableOptions) csvTableOptions {
+				opts.Delimiter = '\n'
+				return opts
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			opts := defaultCsvTableOptions()
+			if tc.options != nil {
+				opts = tc.options(opts)
+			}
+
+			table, err := readCsvTable(path, opts)
+
+			if tc.expectError {
+				if !errors.Is(err, ErrInvalidCsv) {
+					t.Fatalf("expected ErrInvalidCsv but got: %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(tc.expectTable, table) {
+				t.Errorf("expected %+v but got %+v", tc.expectTable, table)
+			}
+		})
+	}
+}
+
+func TestCsvToHtml(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		options        func(opts csvTableOptions) csvTableOptions
+		expectContains []string
+		expectMissing  []string
+	}{
+		{
+			scenario: "default options",
+			expectContains: []string{
+				"font-size: 10pt",
+				"display: table-header-group",
+				"border: 1px solid",
+				"nth-child(even)",
+				"<thead><tr><th>name</th><th>value</th></tr></thead>",
+				"<tr><td>&lt;b&gt;foo&lt;/b&gt;</td><td>1</td></tr>",
+			},
+			expectMissing: []string{
+				`class="fixed"`,
+			},
+		},
+		{
+			scenario: "custom options",
+			options: func(opts csvTableOptions) csvTableOptions {
+				opts.RepeatHeader = false
+				opts.Striped = false
+				opts.Borders = false
+				opts.FontSize = 8.5
+				opts.ColumnWidths = []float64{3, 1}
+				return opts
+			},
+			expectContains: []string{
+				"font-size: 8.5pt",
+				"display: table-row-group",
+				`<table class="fixed">`,
+				`<col style="width: 75.000%">`,
+			},
+			expectMissing: []string{
+				"border: 1px solid",
+				"nth-child(even)",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(t.TempDir())
+
+			path := filepath.Join(ctx.DirPath(), "foo.csv")
+			err := os.WriteFile(path, []byte("name,value\n<b>foo</b>,1\n"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			opts := defaultCsvTableOptions()
+			if tc.options != nil {
+				opts = tc.options(opts)
+			}
+
+			url, err := csvToHtml(ctx.Context, []string{path}, opts)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			html, err := os.ReadFile(strings.TrimPrefix(url, "file://"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			for _, expect := range tc.expectContains {
+				if !strings.Contains(string(html), expect) {
+					t.Errorf("expected HTML to contain '%s' but got: %s", expect, html)
+				}
+			}
+
+			for _, missing := range tc.expectMissing {
+				if strings.Contains(string(html), missing) {
+					t.Errorf("expected HTML not to contain '%s' but got: %s", missing, html)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package chromium
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/russross/blackfriday/v2"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+var (
+	// ErrUnsupportedMarkup happens if the command converting a markup
+	// language to HTML is not available.
+	ErrUnsupportedMarkup = errors.New("unsupported markup language")
+
+	// ErrInvalidMarkup happens if a command cannot convert a file to HTML.
+	ErrInvalidMarkup = errors.New("invalid markup")
+)
+
+// markupExtensions are the extensions of the files the markdown routes
+// convert to HTML: markdown, AsciiDoc, and reStructuredText.
+var markupExtensions = []string{".md", ".adoc", ".asciidoc", ".rst"}
+
+// markupConverter converts markup files to HTML fragments. Markdown is
+// converted in-process, while AsciiDoc and reStructuredText are converted by
+// Asciidoctor and Docutils, if available.
+type markupConverter struct {
+	asciidoctorBinPath string
+	rst2htmlBinPath    string
+}
+
+// toHtml converts a markup file to an HTML fragment, according to its
+// extension. The fragment is not sanitized.
+func (conv markupConverter) toHtml(ctx *api.Context, path string) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".adoc", ".asciidoc":
+		if conv.asciidoctorBinPath == "" {
+			return nil, fmt.Errorf("AsciiDoc: %w", ErrUnsupportedMarkup)
+		}
+
+		outputPath := ctx.GeneratePath("", ".html")
+
+		// The secure safe mode forbids includes, and the embedded document
+		// has no header nor footer.
+		return conv.exec(ctx, outputPath, conv.asciidoctorBinPath,
+			"--safe-mode", "secure",
+			"--no-header-footer",
+			"--attribute", "showtitle",
+			"--out-file", outputPath,
+			path,
+		)
+	case ".rst":
+		if conv.rst2htmlBinPath == "" {
+			return nil, fmt.Errorf("reStructuredText: %w", ErrUnsupportedMarkup)
+		}
+
+		// Only the body of the document, with its title.
+		templatePath := ctx.GeneratePath("", ".txt")
+
+		err := os.WriteFile(templatePath, []byte("%(body_pre_docinfo)s%(docinfo)s%(body)s"), 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("write Docutils template: %w", err)
+		}
+
+		outputPath := ctx.GeneratePath("", ".html")
+
+		// File insertions and raw directives could leak local files.
+		return conv.exec(ctx, outputPath, conv.rst2htmlBinPath,
+			"--template", templatePath,
+			"--no-file-insertion",
+			"--no-raw",
+			"--no-generator",
+			"--no-datestamp",
+			"--no-source-link",
+			"--quiet",
+			"--halt", "severe",
+			path,
+			outputPath,
+		)
+	default:
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read markdown file: %w", err)
+		}
+
+		return blackfriday.Run(b), nil
+	}
+}
+
+func (conv markupConverter) exec(ctx *api.Context, outputPath, binPath string, args ...string) ([]byte, error) {
+	cmd, err := gotenberg.CommandContext(ctx, ctx.Log(), binPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("convert to HTML: %w", err)
+		}
+
+		return nil, fmt.Errorf("convert to HTML: %v: %w", err, ErrInvalidMarkup)
+	}
+
+	b, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("read HTML file: %w", err)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,127 @@
+package chromium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// fakeMarkupCommand writes a script which mimics Asciidoctor or rst2html: it
+// either writes the given HTML to the output path, i.e., the argument
+// following "--out-file" or the last argument, or fails if there is none.
+func fakeMarkupCommand(t *testing.T, html string) string {
+	script := "exit 1\n"
+
+	if html != "" {
+		script = fmt.Sprintf(`out=""
+previous=""
+for arg in "$@"; do
+  if [ "$previous" = "--out-file" ]; then out="$arg"; fi
+  previous="$arg"
+done
+if [ -z "$out" ]; then out="$previous"; fi
+printf '%%s' '%s' > "$out"
+`, html)
+	}
+
+	return gotenbergtest.FakeBinary(t, "markup", script)
+}
+
+func TestMarkupConverter_toHtml(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		conv        func(t *testing.T) markupConverter
+		filename    string
+		content     string
+		expectHtml  string
+		expectError error
+	}{
+		{
+			scenario:   "markdown",
+			conv:       func(t *testing.T) markupConverter { return markupConverter{} },
+			filename:   "foo.md",
+			content:    "# Foo",
+			expectHtml: "<h1>Foo</h1>",
+		},
+		{
+			scenario:    "AsciiDoc without Asciidoctor",
+			conv:        func(t *testing.T) markupConverter { return markupConverter{} },
+			filename:    "foo.adoc",
+			content:     "= Foo",
+			expectError: ErrUnsupportedMarkup,
+		},
+		{
+			scenario:    "reStructuredText without rst2html",
+			conv:        func(t *testing.T) markupConverter { return markupConverter{} },
+			filename:    "foo.rst",
+			content:     "Foo\n===",
+			expectError: ErrUnsupportedMarkup,
+		},
+		{
+			scenario: "AsciiDoc",
+			conv: func(t *testing.T) markupConverter {
+				return markupConverter{asciidoctorBinPath: fakeMarkupCommand(t, "<h1>Foo</h1>")}
+			},
+			filename:   "foo.asciidoc",
+			content:    "= Foo",
+			expectHtml: "<h1>Foo</h1>",
+		},
+		{
+			scenario: "reStructuredText",
+			conv: func(t *testing.T) markupConverter {
+				return markupConverter{rst2htmlBinPath: fakeMarkupCommand(t, "<h1>Foo</h1>")}
+			},
+			filename:   "foo.rst",
+			content:    "Foo\n===",
+			expectHtml: "<h1>Foo</h1>",
+		},
+		{
+			scenario: "invalid reStructuredText",
+			conv: func(t *testing.T) markupConverter {
+				return markupConverter{rst2htmlBinPath: fakeMarkupCommand(t, "")}
+			},
+			filename:    "foo.rst",
+			content:     "Foo\n===",
+			expectError: ErrInvalidMarkup,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(t.TempDir())
+			ctx.SetLogger(zap.NewNop())
+			ctx.Context.Context = context.Background()
+
+			path := filepath.Join(ctx.DirPath(), tc.filename)
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			html, err := tc.conv(t).toHtml(ctx.Context, path)
+
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected error %v but got: %v", tc.expectError, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if strings.TrimSpace(string(html)) != tc.expectHtml {
+				t.Errorf("expected '%s' but got '%s'", tc.expectHtml, html)
+			}
+		})
+	}
+}
@@ -22,6 +22,15 @@ func (api *ApiMock) Screenshot(ctx context.Context, logger *zap.Logger, url, out
 	return api.ScreenshotMock(ctx, logger, url, outputPath, options)
 }
 
+// ProviderMock is a mock for the [Provider] interface.
+type ProviderMock struct {
+	ChromiumMock func() (Api, error)
+}
+
+func (provider *ProviderMock) Chromium() (Api, error) {
+	return provider.ChromiumMock()
+}
+
 // browserMock is a mock for the [browser] interface.
 type browserMock struct {
 	gotenberg.ProcessMock
@@ -39,6 +48,7 @@ func (b *browserMock) screenshot(ctx context.Context, logger *zap.Logger, url, o
 
 // Interface guards.
 var (
-	_ Api     = (*ApiMock)(nil)
-	_ browser = (*browserMock)(nil)
+	_ Api      = (*ApiMock)(nil)
+	_ Provider = (*ProviderMock)(nil)
+	_ browser  = (*browserMock)(nil)
 )
@@ -28,6 +28,19 @@ func TestApiMock(t *testing.T) {
 	}
 }
 
+func TestProviderMock(t *testing.T) {
+	mock := &ProviderMock{
+		ChromiumMock: func() (Api, error) {
+			return new(ApiMock), nil
+		},
+	}
+
+	_, err := mock.Chromium()
+	if err != nil {
+		t.Errorf("expected no error from ProviderMock.Chromium, but got: %v", err)
+	}
+}
+
 func TestBrowserMock(t *testing.T) {
 	mock := &browserMock{
 		pdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
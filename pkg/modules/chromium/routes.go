@@ -2,10 +2,12 @@ package chromium
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"html/template"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -15,11 +17,11 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/microcosm-cc/bluemonday"
-	"github.com/russross/blackfriday/v2"
 	"go.uber.org/multierr"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pdfengines"
 )
 
 // FormDataChromiumOptions creates [Options] from the form data. Fallback to
@@ -161,12 +163,21 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 	defaultScreenshotOptions := DefaultScreenshotOptions()
 
 	var (
+		width, height    int
+		clip             bool
 		format           string
 		quality          int
 		optimizeForSpeed bool
 	)
 
 	form.
+		Custom("width", func(value string) error {
+			return assignScreenshotDimension(value, defaultScreenshotOptions.Width, &width)
+		}).
+		Custom("height", func(value string) error {
+			return assignScreenshotDimension(value, defaultScreenshotOptions.Height, &height)
+		}).
+		Bool("clip", &clip, defaultScreenshotOptions.Clip).
 		Custom("format", func(value string) error {
 			if value == "" {
 				format = defaultScreenshotOptions.Format
@@ -207,6 +218,9 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 
 	screenshotOptions := ScreenshotOptions{
 		Options:          options,
+		Width:            width,
+		Height:           height,
+		Clip:             clip,
 		Format:           format,
 		Quality:          quality,
 		OptimizeForSpeed: optimizeForSpeed,
@@ -215,6 +229,27 @@ func FormDataChromiumScreenshotOptions(ctx *api.Context) (*api.FormData, Screens
 	return form, screenshotOptions
 }
 
+// assignScreenshotDimension parses a strictly positive number of pixels.
+func assignScreenshotDimension(value string, defaultValue int, target *int) error {
+	if value == "" {
+		*target = defaultValue
+		return nil
+	}
+
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	if intValue < 1 {
+		return errors.New("value is inferior to 1")
+	}
+
+	*target = intValue
+
+	return nil
+}
+
 // FormDataChromiumPdfFormats creates [gotenberg.PdfFormats] from the form
 // data. Fallback to default value if the considered key is not present.
 func FormDataChromiumPdfFormats(form *api.FormData) gotenberg.PdfFormats {
@@ -233,8 +268,32 @@ func FormDataChromiumPdfFormats(form *api.FormData) gotenberg.PdfFormats {
 	}
 }
 
+// FormDataChromiumMaxOutputBytes returns the size budget of the resulting
+// PDF from the form data, if any and if the maxOutputBytes extension is
+// enabled.
+func FormDataChromiumMaxOutputBytes(ctx *api.Context, form *api.FormData) int64 {
+	var maxOutputBytes int64
+
+	if !ctx.ExtensionEnabled(api.ExtensionMaxOutputBytes) {
+		return maxOutputBytes
+	}
+
+	form.Custom("maxOutputBytes", func(value string) error {
+		maxBytes, err := pdfengines.ParseMaxOutputBytes(value)
+		if err != nil {
+			return err
+		}
+
+		maxOutputBytes = maxBytes
+
+		return nil
+	})
+
+	return maxOutputBytes
+}
+
 // convertUrlRoute returns an [api.Route] which can convert a URL to PDF.
-func convertUrlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
+func convertUrlRoute(chromium Api, engine gotenberg.PdfEngine, ghostscriptBinPath string) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/chromium/convert/url",
@@ -243,6 +302,7 @@ func convertUrlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumPdfOptions(ctx)
 			pdfFormats := FormDataChromiumPdfFormats(form)
+			maxOutputBytes := FormDataChromiumMaxOutputBytes(ctx, form)
 
 			var url string
 			err := form.
@@ -252,7 +312,7 @@ func convertUrlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
-			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options)
+			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options, ghostscriptBinPath, maxOutputBytes)
 			if err != nil {
 				return fmt.Errorf("convert URL to PDF: %w", err)
 			}
@@ -293,7 +353,7 @@ func screenshotUrlRoute(chromium Api) api.Route {
 
 // convertHtmlRoute returns an [api.Route] which can convert an HTML file to
 // PDF.
-func convertHtmlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
+func convertHtmlRoute(chromium Api, engine gotenberg.PdfEngine, ghostscriptBinPath string) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/chromium/convert/html",
@@ -302,6 +362,7 @@ func convertHtmlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumPdfOptions(ctx)
 			pdfFormats := FormDataChromiumPdfFormats(form)
+			maxOutputBytes := FormDataChromiumMaxOutputBytes(ctx, form)
 
 			var inputPath string
 			err := form.
@@ -312,7 +373,7 @@ func convertHtmlRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 			}
 
 			url := fmt.Sprintf("file://%s", inputPath)
-			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options)
+			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options, ghostscriptBinPath, maxOutputBytes)
 			if err != nil {
 				return fmt.Errorf("convert HTML to PDF: %w", err)
 			}
@@ -352,9 +413,9 @@ func screenshotHtmlRoute(chromium Api) api.Route {
 	}
 }
 
-// convertMarkdownRoute returns an [api.Route] which can convert markdown files
-// to PDF.
-func convertMarkdownRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
+// convertMarkdownRoute returns an [api.Route] which can convert markdown,
+// AsciiDoc, and reStructuredText files to PDF.
+func convertMarkdownRoute(chromium Api, engine gotenberg.PdfEngine, markup markupConverter, ghostscriptBinPath string) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/chromium/convert/markdown",
@@ -363,6 +424,7 @@ func convertMarkdownRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 			ctx := c.Get("context").(*api.Context)
 			form, options := FormDataChromiumPdfOptions(ctx)
 			pdfFormats := FormDataChromiumPdfFormats(form)
+			maxOutputBytes := FormDataChromiumMaxOutputBytes(ctx, form)
 
 			var (
 				inputPath     string
@@ -371,18 +433,18 @@ func convertMarkdownRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 
 			err := form.
 				MandatoryPath("index.html", &inputPath).
-				MandatoryPaths([]string{".md"}, &markdownPaths).
+				MandatoryPaths(markupExtensions, &markdownPaths).
 				Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
-			url, err := markdownToHtml(ctx, inputPath, markdownPaths)
+			url, err := markdownToHtml(ctx, markup, inputPath, markdownPaths)
 			if err != nil {
 				return fmt.Errorf("transform markdown file(s) to HTML: %w", err)
 			}
 
-			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options)
+			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options, ghostscriptBinPath, maxOutputBytes)
 			if err != nil {
 				return fmt.Errorf("convert markdown to PDF: %w", err)
 			}
@@ -393,8 +455,8 @@ func convertMarkdownRoute(chromium Api, engine gotenberg.PdfEngine) api.Route {
 }
 
 // screenshotMarkdownRoute returns an [api.Route] which can take a screenshot
-// from markdown files.
-func screenshotMarkdownRoute(chromium Api) api.Route {
+// from markdown, AsciiDoc, and reStructuredText files.
+func screenshotMarkdownRoute(chromium Api, markup markupConverter) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/chromium/screenshot/markdown",
@@ -410,13 +472,13 @@ func screenshotMarkdownRoute(chromium Api) api.Route {
 
 			err := form.
 				MandatoryPath("index.html", &inputPath).
-				MandatoryPaths([]string{".md"}, &markdownPaths).
+				MandatoryPaths(markupExtensions, &markdownPaths).
 				Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
-			url, err := markdownToHtml(ctx, inputPath, markdownPaths)
+			url, err := markdownToHtml(ctx, markup, inputPath, markdownPaths)
 			if err != nil {
 				return fmt.Errorf("transform markdown file(s) to HTML: %w", err)
 			}
@@ -431,11 +493,254 @@ func screenshotMarkdownRoute(chromium Api) api.Route {
 	}
 }
 
-func markdownToHtml(ctx *api.Context, inputPath string, markdownPaths []string) (string, error) {
+// convertSvgRoute returns an [api.Route] which can convert SVG files to PDF,
+// one SVG file per page.
+func convertSvgRoute(chromium Api, engine gotenberg.PdfEngine, ghostscriptBinPath string) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/chromium/convert/svg",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			form, options := FormDataChromiumPdfOptions(ctx)
+			pdfFormats := FormDataChromiumPdfFormats(form)
+			maxOutputBytes := FormDataChromiumMaxOutputBytes(ctx, form)
+
+			var (
+				svgPaths    []string
+				svgPageSize bool
+			)
+
+			err := form.
+				MandatoryPaths([]string{".svg"}, &svgPaths).
+				Bool("svgPageSize", &svgPageSize, true).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			if svgPageSize {
+				// Each page has the size of its SVG file, thanks to the
+				// @page rules of the HTML document.
+				options.PreferCssPageSize = true
+				options.MarginTop = 0
+				options.MarginBottom = 0
+				options.MarginLeft = 0
+				options.MarginRight = 0
+			}
+
+			url, err := svgToHtml(ctx, svgPaths, svgPageSize)
+			if err != nil {
+				return handleSvgError(fmt.Errorf("transform SVG file(s) to HTML: %w", err))
+			}
+
+			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options, ghostscriptBinPath, maxOutputBytes)
+			if err != nil {
+				return fmt.Errorf("convert SVG to PDF: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// screenshotSvgRoute returns an [api.Route] which can take a screenshot from
+// an SVG file.
+func screenshotSvgRoute(chromium Api) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/chromium/screenshot/svg",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			form, options := FormDataChromiumScreenshotOptions(ctx)
+
+			var (
+				svgPaths    []string
+				svgPageSize bool
+			)
+
+			err := form.
+				MandatoryPaths([]string{".svg"}, &svgPaths).
+				Bool("svgPageSize", &svgPageSize, true).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			if len(svgPaths) > 1 {
+				return api.WrapError(
+					fmt.Errorf("got %d SVG files", len(svgPaths)),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						"Only one SVG file is allowed",
+					).WithCode(api.ErrorCodeChromiumTooManySvgFiles),
+				)
+			}
+
+			size, err := readSvgSize(svgPaths[0])
+			if err != nil {
+				return handleSvgError(fmt.Errorf("read SVG size: %w", err))
+			}
+
+			sized := svgPageSize && size.known()
+			if sized {
+				// The screenshot has the size of the SVG file.
+				options.Width = int(math.Ceil(size.Width))
+				options.Height = int(math.Ceil(size.Height))
+				options.Clip = true
+			}
+
+			url, err := svgToHtml(ctx, svgPaths, sized)
+			if err != nil {
+				return handleSvgError(fmt.Errorf("transform SVG file to HTML: %w", err))
+			}
+
+			err = screenshotUrl(ctx, chromium, url, options)
+			if err != nil {
+				return fmt.Errorf("SVG screenshot: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// convertCsvRoute returns an [api.Route] which can convert CSV and TSV files
+// to PDF tables, one table per file.
+func convertCsvRoute(chromium Api, engine gotenberg.PdfEngine, ghostscriptBinPath string) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/chromium/convert/csv",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			form, options := FormDataChromiumPdfOptions(ctx)
+			pdfFormats := FormDataChromiumPdfFormats(form)
+			maxOutputBytes := FormDataChromiumMaxOutputBytes(ctx, form)
+			defaultTableOptions := defaultCsvTableOptions()
+
+			var (
+				csvPaths     []string
+				tableOptions csvTableOptions
+			)
+
+			err := form.
+				MandatoryPaths([]string{".csv", ".tsv"}, &csvPaths).
+				Custom("csvDelimiter", func(value string) error {
+					if value == "" {
+						tableOptions.Delimiter = defaultTableOptions.Delimiter
+						return nil
+					}
+
+					// An escaped tab is easier to send than a tab.
+					if value == "\\t" {
+						value = "\t"
+					}
+
+					runes := []rune(value)
+					if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' {
+						return errors.New("value is not a single character, or is a quote or a line break")
+					}
+
+					tableOptions.Delimiter = runes[0]
+
+					return nil
+				}).
+				Bool("csvHeader", &tableOptions.Header, defaultTableOptions.Header).
+				Bool("csvRepeatHeader", &tableOptions.RepeatHeader, defaultTableOptions.RepeatHeader).
+				Custom("csvColumnWidths", func(value string) error {
+					if value == "" {
+						tableOptions.ColumnWidths = defaultTableOptions.ColumnWidths
+						return nil
+					}
+
+					for _, field := range strings.Split(value, ",") {
+						width, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+						if err != nil {
+							return err
+						}
+
+						if width <= 0 {
+							return errors.New("value is not strictly positive")
+						}
+
+						tableOptions.ColumnWidths = append(tableOptions.ColumnWidths, width)
+					}
+
+					return nil
+				}).
+				Custom("csvFontSize", func(value string) error {
+					if value == "" {
+						tableOptions.FontSize = defaultTableOptions.FontSize
+						return nil
+					}
+
+					fontSize, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return err
+					}
+
+					if fontSize <= 0 {
+						return errors.New("value is not strictly positive")
+					}
+
+					tableOptions.FontSize = fontSize
+
+					return nil
+				}).
+				Bool("csvStriped", &tableOptions.Striped, defaultTableOptions.Striped).
+				Bool("csvBorders", &tableOptions.Borders, defaultTableOptions.Borders).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			url, err := csvToHtml(ctx, csvPaths, tableOptions)
+			if err != nil {
+				if errors.Is(err, ErrInvalidCsv) {
+					return api.WrapError(
+						fmt.Errorf("transform CSV file(s) to HTML: %w", err),
+						api.NewSentinelHttpError(
+							http.StatusBadRequest,
+							"At least one of the files is not a valid CSV or TSV",
+						).WithCode(api.ErrorCodeChromiumInvalidCsv),
+					)
+				}
+
+				return fmt.Errorf("transform CSV file(s) to HTML: %w", err)
+			}
+
+			err = convertUrl(ctx, chromium, engine, url, pdfFormats, options, ghostscriptBinPath, maxOutputBytes)
+			if err != nil {
+				return fmt.Errorf("convert CSV to PDF: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+func handleSvgError(err error) error {
+	if errors.Is(err, ErrInvalidSvg) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusBadRequest,
+				"At least one of the files is not a valid SVG",
+			).WithCode(api.ErrorCodeChromiumInvalidSvg),
+		)
+	}
+
+	return err
+}
+
+func markdownToHtml(ctx *api.Context, markup markupConverter, inputPath string, markdownPaths []string) (string, error) {
 	// We have to convert each markdown file referenced in the HTML
 	// file to... HTML. Thanks to the "html/template" package, we are
 	// able to provide the "toHTML" function which the user may call
-	// directly inside the HTML file.
+	// directly inside the HTML file. AsciiDoc and reStructuredText files
+	// work the same way.
 
 	var markdownFilesNotFoundErr error
 
@@ -463,12 +768,11 @@ func markdownToHtml(ctx *api.Context, inputPath string, markdownPaths []string)
 					return "", nil
 				}
 
-				b, err := os.ReadFile(path)
+				unsafe, err := markup.toHtml(ctx, path)
 				if err != nil {
-					return "", fmt.Errorf("read markdown file '%s': %w", filename, err)
+					return "", fmt.Errorf("convert '%s' to HTML: %w", filename, err)
 				}
 
-				unsafe := blackfriday.Run(b)
 				sanitized := bluemonday.UGCPolicy().SanitizeBytes(unsafe)
 
 				// #nosec
@@ -483,6 +787,26 @@ func markdownToHtml(ctx *api.Context, inputPath string, markdownPaths []string)
 
 	err = tmpl.Execute(&buffer, &struct{}{})
 	if err != nil {
+		if errors.Is(err, ErrUnsupportedMarkup) {
+			return "", api.WrapError(
+				fmt.Errorf("execute template: %w", err),
+				api.NewSentinelHttpError(
+					http.StatusBadRequest,
+					"AsciiDoc or reStructuredText conversion is not available",
+				).WithCode(api.ErrorCodeChromiumUnsupportedMarkup),
+			)
+		}
+
+		if errors.Is(err, ErrInvalidMarkup) {
+			return "", api.WrapError(
+				fmt.Errorf("execute template: %w", err),
+				api.NewSentinelHttpError(
+					http.StatusBadRequest,
+					"At least one of the AsciiDoc or reStructuredText files cannot be converted",
+				).WithCode(api.ErrorCodeChromiumInvalidMarkup),
+			)
+		}
+
 		return "", fmt.Errorf("execute template: %w", err)
 	}
 
@@ -492,7 +816,7 @@ func markdownToHtml(ctx *api.Context, inputPath string, markdownPaths []string)
 			api.NewSentinelHttpError(
 				http.StatusBadRequest,
 				fmt.Sprintf("Markdown file(s) not found: %s", markdownFilesNotFoundErr),
-			),
+			).WithCode(api.ErrorCodeChromiumMarkdownFilesNotFound),
 		)
 	}
 
@@ -506,10 +830,16 @@ func markdownToHtml(ctx *api.Context, inputPath string, markdownPaths []string)
 	return fmt.Sprintf("file://%s", inputPath), nil
 }
 
-func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url string, pdfFormats gotenberg.PdfFormats, options PdfOptions) error {
+func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url string, pdfFormats gotenberg.PdfFormats, options PdfOptions, ghostscriptBinPath string, maxOutputBytes int64) error {
+	err := pdfengines.ValidateMaxOutputBytes(maxOutputBytes, ghostscriptBinPath, pdfFormats)
+	if err != nil {
+		return err
+	}
+
 	outputPath := ctx.GeneratePath("", ".pdf")
+	ctx.AddEngines("chromium")
 
-	err := chromium.Pdf(ctx, ctx.Log(), url, outputPath, options)
+	err = chromium.Pdf(ctx, ctx.Log(), url, outputPath, options)
 	err = handleChromiumError(err, options.Options)
 	if err != nil {
 		if errors.Is(err, ErrOmitBackgroundWithoutPrintBackground) {
@@ -518,7 +848,7 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 				api.NewSentinelHttpError(
 					http.StatusBadRequest,
 					"omitBackground requires printBackground set to true",
-				),
+				).WithCode(api.ErrorCodeChromiumOmitBackgroundWithoutPrintBackground),
 			)
 		}
 
@@ -528,7 +858,7 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 				api.NewSentinelHttpError(
 					http.StatusBadRequest,
 					"Chromium does not handle the provided settings; please check for aberrant form values",
-				),
+				).WithCode(api.ErrorCodeChromiumInvalidPrinterSettings),
 			)
 		}
 
@@ -538,7 +868,7 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 				api.NewSentinelHttpError(
 					http.StatusBadRequest,
 					fmt.Sprintf("Chromium does not handle the page ranges '%s' (nativePageRanges)", options.PageRanges),
-				),
+				).WithCode(api.ErrorCodeChromiumMalformedPageRanges),
 			)
 		}
 
@@ -562,6 +892,11 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 		outputPath = convertOutputPath
 	}
 
+	err = pdfengines.FitSizeOrFail(ctx, ghostscriptBinPath, outputPath, "PDF", maxOutputBytes)
+	if err != nil {
+		return err
+	}
+
 	err = ctx.AddOutputPaths(outputPath)
 	if err != nil {
 		return fmt.Errorf("add output path: %w", err)
@@ -573,6 +908,7 @@ func convertUrl(ctx *api.Context, chromium Api, engine gotenberg.PdfEngine, url
 func screenshotUrl(ctx *api.Context, chromium Api, url string, options ScreenshotOptions) error {
 	ext := fmt.Sprintf(".%s", options.Format)
 	outputPath := ctx.GeneratePath("", ext)
+	ctx.AddEngines("chromium")
 
 	err := chromium.Screenshot(ctx, ctx.Log(), url, outputPath, options)
 	err = handleChromiumError(err, options.Options)
@@ -606,7 +942,7 @@ func handleChromiumError(err error, options Options) error {
 			api.NewSentinelHttpError(
 				http.StatusBadRequest,
 				fmt.Sprintf("The expression '%s' (waitForExpression) returned an exception or undefined", options.WaitForExpression),
-			),
+			).WithCode(api.ErrorCodeChromiumInvalidEvaluationExpression),
 		)
 	}
 
@@ -616,7 +952,7 @@ func handleChromiumError(err error, options Options) error {
 			api.NewSentinelHttpError(
 				http.StatusConflict,
 				fmt.Sprintf("Invalid HTTP status code from the main page: %s", strings.ReplaceAll(err.Error(), fmt.Sprintf(": %s", ErrInvalidHttpStatusCode.Error()), "")),
-			),
+			).WithCode(api.ErrorCodeChromiumInvalidHttpStatusCode),
 		)
 	}
 
@@ -626,7 +962,17 @@ func handleChromiumError(err error, options Options) error {
 			api.NewSentinelHttpError(
 				http.StatusConflict,
 				fmt.Sprintf("Chromium console exceptions:\n %s", strings.ReplaceAll(err.Error(), ErrConsoleExceptions.Error(), "")),
-			),
+			).WithCode(api.ErrorCodeChromiumConsoleExceptions),
+		)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(
+				http.StatusServiceUnavailable,
+				http.StatusText(http.StatusServiceUnavailable),
+			).WithCode(api.ErrorCodeChromiumNavigationTimeout),
 		)
 	}
 
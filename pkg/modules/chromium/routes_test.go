@@ -310,6 +310,65 @@ func TestFormDataChromiumScreenshotOptions(t *testing.T) {
 				return options
 			}(),
 		},
+		{
+			scenario: "invalid width form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"width": {
+						"foo",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Width = 0
+				return options
+			}(),
+		},
+		{
+			scenario: "width form field inferior to 1",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"width": {
+						"0",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Width = 0
+				return options
+			}(),
+		},
+		{
+			scenario: "valid width, height and clip form fields",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetValues(map[string][]string{
+					"width": {
+						"1280",
+					},
+					"height": {
+						"720",
+					},
+					"clip": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			expectedOptions: func() ScreenshotOptions {
+				options := DefaultScreenshotOptions()
+				options.Width = 1280
+				options.Height = 720
+				options.Clip = true
+				return options
+			}(),
+		},
 		{
 			scenario: "valid quality form field",
 			ctx: func() *api.ContextMock {
@@ -475,7 +534,7 @@ func TestConvertUrlRoute(t *testing.T) {
 			c := echo.New().NewContext(nil, nil)
 			c.Set("context", tc.ctx.Context)
 
-			err := convertUrlRoute(tc.api, nil).Handler(c)
+			err := convertUrlRoute(tc.api, nil, "").Handler(c)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
@@ -677,7 +736,7 @@ func TestConvertHtmlRoute(t *testing.T) {
 			c := echo.New().NewContext(nil, nil)
 			c.Set("context", tc.ctx.Context)
 
-			err := convertHtmlRoute(tc.api, nil).Handler(c)
+			err := convertHtmlRoute(tc.api, nil, "").Handler(c)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
@@ -890,6 +949,39 @@ func TestConvertMarkdownRoute(t *testing.T) {
 			expectHttpError:        false,
 			expectOutputPathsCount: 0,
 		},
+		{
+			scenario: "unsupported AsciiDoc file",
+			ctx: func() *api.ContextMock {
+				dirPath := fmt.Sprintf("%s/%s", os.TempDir(), uuid.NewString())
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(dirPath)
+				ctx.SetFiles(map[string]string{
+					"index.html": fmt.Sprintf("%s/index.html", dirPath),
+					"doc.adoc":   fmt.Sprintf("%s/doc.adoc", dirPath),
+				})
+
+				err := os.MkdirAll(dirPath, 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/index.html", dirPath), []byte("<div>{{ toHTML \"doc.adoc\" }}</div>"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/doc.adoc", dirPath), []byte("= Hello World!"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
 		{
 			scenario: "error from Chromium",
 			ctx: func() *api.ContextMock {
@@ -975,7 +1067,7 @@ func TestConvertMarkdownRoute(t *testing.T) {
 			c := echo.New().NewContext(nil, nil)
 			c.Set("context", tc.ctx.Context)
 
-			err := convertMarkdownRoute(tc.api, nil).Handler(c)
+			err := convertMarkdownRoute(tc.api, nil, markupConverter{}, "").Handler(c)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
@@ -1182,7 +1274,7 @@ func TestScreenshotMarkdownRoute(t *testing.T) {
 			c := echo.New().NewContext(nil, nil)
 			c.Set("context", tc.ctx.Context)
 
-			err := screenshotMarkdownRoute(tc.api).Handler(c)
+			err := screenshotMarkdownRoute(tc.api, markupConverter{}).Handler(c)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
@@ -1217,6 +1309,460 @@ func TestScreenshotMarkdownRoute(t *testing.T) {
 	}
 }
 
+// filesTestContext returns an [api.ContextMock] with the given files.
+func filesTestContext(t *testing.T, files map[string]string) *api.ContextMock {
+	dirPath := t.TempDir()
+	ctx := &api.ContextMock{Context: new(api.Context)}
+	ctx.SetDirPath(dirPath)
+
+	paths := make(map[string]string)
+	for filename, content := range files {
+		path := fmt.Sprintf("%s/%s", dirPath, filename)
+		err := os.WriteFile(path, []byte(content), 0o755)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+		paths[filename] = path
+	}
+	ctx.SetFiles(paths)
+
+	return ctx
+}
+
+func TestConvertSvgRoute(t *testing.T) {
+	for _, tc := range []struct {
+		scenario                string
+		files                   map[string]string
+		values                  map[string][]string
+		api                     Api
+		expectError             bool
+		expectHttpError         bool
+		expectHttpStatus        int
+		expectOutputPathsCount  int
+		expectPreferCssPageSize bool
+	}{
+		{
+			scenario:               "missing mandatory SVG form files",
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid SVG file",
+			files: map[string]string{
+				"foo.svg": "<html></html>",
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "error from Chromium",
+			files: map[string]string{
+				"foo.svg": `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50"></svg>`,
+			},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return errors.New("foo")
+			}},
+			expectError:             true,
+			expectHttpError:         false,
+			expectOutputPathsCount:  0,
+			expectPreferCssPageSize: true,
+		},
+		{
+			scenario: "success",
+			files: map[string]string{
+				"foo.svg": `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50"></svg>`,
+				"bar.svg": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"></svg>`,
+			},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return nil
+			}},
+			expectError:             false,
+			expectHttpError:         false,
+			expectOutputPathsCount:  1,
+			expectPreferCssPageSize: true,
+		},
+		{
+			scenario: "success without SVG page size",
+			files: map[string]string{
+				"foo.svg": `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50"></svg>`,
+			},
+			values: map[string][]string{
+				"svgPageSize": {"false"},
+			},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return nil
+			}},
+			expectError:             false,
+			expectHttpError:         false,
+			expectOutputPathsCount:  1,
+			expectPreferCssPageSize: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := filesTestContext(t, tc.files)
+			ctx.SetValues(tc.values)
+			ctx.SetLogger(zap.NewNop())
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", ctx.Context)
+
+			var actualOptions PdfOptions
+			if mock, ok := tc.api.(*ApiMock); ok {
+				pdfMock := mock.PdfMock
+				mock.PdfMock = func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+					actualOptions = options
+					return pdfMock(ctx, logger, url, outputPath, options)
+				}
+			}
+
+			err := convertSvgRoute(tc.api, nil, "").Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(ctx.OutputPaths()))
+			}
+
+			if tc.expectPreferCssPageSize != actualOptions.PreferCssPageSize {
+				t.Errorf("expected preferCssPageSize %t but got %t", tc.expectPreferCssPageSize, actualOptions.PreferCssPageSize)
+			}
+		})
+	}
+}
+
+func TestScreenshotSvgRoute(t *testing.T) {
+	for _, tc := range []struct {
+		scenario               string
+		files                  map[string]string
+		values                 map[string][]string
+		api                    Api
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectWidth            int
+		expectHeight           int
+		expectClip             bool
+	}{
+		{
+			scenario:               "missing mandatory SVG form file",
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "too many SVG files",
+			files: map[string]string{
+				"foo.svg": `<svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+				"bar.svg": `<svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid SVG file",
+			files: map[string]string{
+				"foo.svg": "foo",
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "error from Chromium",
+			files: map[string]string{
+				"foo.svg": `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50"></svg>`,
+			},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return errors.New("foo")
+			}},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+			expectWidth:            100,
+			expectHeight:           50,
+			expectClip:             true,
+		},
+		{
+			scenario: "success with SVG size",
+			files: map[string]string{
+				"foo.svg": `<svg xmlns="http://www.w3.org/2000/svg" width="100.5" height="50"></svg>`,
+			},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectWidth:            101,
+			expectHeight:           50,
+			expectClip:             true,
+		},
+		{
+			scenario: "success without SVG size",
+			files: map[string]string{
+				"foo.svg": `<svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+			},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectWidth:            800,
+			expectHeight:           600,
+			expectClip:             false,
+		},
+		{
+			scenario: "success without SVG page size",
+			files: map[string]string{
+				"foo.svg": `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50"></svg>`,
+			},
+			values: map[string][]string{
+				"svgPageSize": {"false"},
+			},
+			api: &ApiMock{ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectWidth:            800,
+			expectHeight:           600,
+			expectClip:             false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := filesTestContext(t, tc.files)
+			ctx.SetValues(tc.values)
+			ctx.SetLogger(zap.NewNop())
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", ctx.Context)
+
+			var actualOptions ScreenshotOptions
+			if mock, ok := tc.api.(*ApiMock); ok {
+				screenshotMock := mock.ScreenshotMock
+				mock.ScreenshotMock = func(ctx context.Context, logger *zap.Logger, url, outputPath string, options ScreenshotOptions) error {
+					actualOptions = options
+					return screenshotMock(ctx, logger, url, outputPath, options)
+				}
+			}
+
+			err := screenshotSvgRoute(tc.api).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(ctx.OutputPaths()))
+			}
+
+			if tc.api == nil {
+				return
+			}
+
+			if tc.expectWidth != actualOptions.Width || tc.expectHeight != actualOptions.Height || tc.expectClip != actualOptions.Clip {
+				t.Errorf("expected %dx%d (clip %t) but got %dx%d (clip %t)", tc.expectWidth, tc.expectHeight, tc.expectClip, actualOptions.Width, actualOptions.Height, actualOptions.Clip)
+			}
+		})
+	}
+}
+
+func TestConvertCsvRoute(t *testing.T) {
+	for _, tc := range []struct {
+		scenario               string
+		files                  map[string]string
+		values                 map[string][]string
+		api                    Api
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "missing mandatory CSV form files",
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid csvDelimiter form field",
+			files: map[string]string{
+				"foo.csv": "a,b\n",
+			},
+			values: map[string][]string{
+				"csvDelimiter": {"ab"},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid csvColumnWidths form field",
+			files: map[string]string{
+				"foo.csv": "a,b\n",
+			},
+			values: map[string][]string{
+				"csvColumnWidths": {"1,-1"},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid csvFontSize form field",
+			files: map[string]string{
+				"foo.csv": "a,b\n",
+			},
+			values: map[string][]string{
+				"csvFontSize": {"foo"},
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid CSV file",
+			files: map[string]string{
+				"foo.csv": "a,b\"c\n",
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "error from Chromium",
+			files: map[string]string{
+				"foo.csv": "a,b\n",
+			},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return errors.New("foo")
+			}},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success",
+			files: map[string]string{
+				"foo.csv": "a,b\n",
+				"bar.tsv": "a\tb\n",
+			},
+			values: map[string][]string{
+				"csvDelimiter":    {"\\t"},
+				"csvHeader":       {"false"},
+				"csvColumnWidths": {"2, 1"},
+				"csvFontSize":     {"12"},
+			},
+			api: &ApiMock{PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options PdfOptions) error {
+				return nil
+			}},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := filesTestContext(t, tc.files)
+			ctx.SetValues(tc.values)
+			ctx.SetLogger(zap.NewNop())
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", ctx.Context)
+
+			err := convertCsvRoute(tc.api, nil, "").Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(ctx.OutputPaths()))
+			}
+		})
+	}
+}
+
 func TestConvertUrl(t *testing.T) {
 	for _, tc := range []struct {
 		scenario               string
@@ -1388,7 +1934,7 @@ func TestConvertUrl(t *testing.T) {
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			tc.ctx.SetLogger(zap.NewNop())
-			err := convertUrl(tc.ctx.Context, tc.api, tc.engine, "", tc.pdfFormats, tc.options)
+			err := convertUrl(tc.ctx.Context, tc.api, tc.engine, "", tc.pdfFormats, tc.options, "", 0)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
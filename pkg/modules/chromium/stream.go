@@ -12,6 +12,22 @@ import (
 	cdprotoio "github.com/chromedp/cdproto/io"
 )
 
+// defaultStreamChunkSize is the size of the copy buffer if none is set.
+const defaultStreamChunkSize = 1 << 20
+
+// copyStream copies a Chromium stream into a writer with a buffer of the
+// given size. The size of the buffer bounds the size of each IO.read command:
+// a bigger buffer means fewer round trips with Chromium, but more memory.
+func copyStream(w io.Writer, r io.Reader, chunkSize int) (int64, error) {
+	if chunkSize <= 0 {
+		chunkSize = defaultStreamChunkSize
+	}
+
+	// Hide the io.ReaderFrom of the writer, e.g., an *os.File, which would
+	// read with its own small buffer otherwise.
+	return io.CopyBuffer(struct{ io.Writer }{w}, r, make([]byte, chunkSize))
+}
+
 // Credits: https://raw.githubusercontent.com/mafredri/cdp/3c5eab7ffc5cbee667b0a813ce470ac423792811/protocol/io/stream_reader.go.
 type streamReader struct {
 	ctx    context.Context
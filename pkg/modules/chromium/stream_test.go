@@ -0,0 +1,61 @@
+package chromium
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// chunkReader records the sizes of the reads.
+type chunkReader struct {
+	r     io.Reader
+	sizes []int
+}
+
+func (reader *chunkReader) Read(p []byte) (int, error) {
+	reader.sizes = append(reader.sizes, len(p))
+
+	return reader.r.Read(p)
+}
+
+func TestCopyStream(t *testing.T) {
+	for _, tc := range []struct {
+		scenario   string
+		chunkSize  int
+		expectSize int
+	}{
+		{
+			scenario:   "default chunk size",
+			chunkSize:  0,
+			expectSize: defaultStreamChunkSize,
+		},
+		{
+			scenario:   "custom chunk size",
+			chunkSize:  4,
+			expectSize: 4,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			content := strings.Repeat("foo", 10)
+			reader := &chunkReader{r: strings.NewReader(content)}
+
+			var buf bytes.Buffer
+
+			written, err := copyStream(&buf, reader, tc.chunkSize)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if written != int64(len(content)) || buf.String() != content {
+				t.Errorf("expected '%s' but got '%s'", content, buf.String())
+			}
+
+			for _, size := range reader.sizes {
+				if size != tc.expectSize {
+					t.Errorf("expected reads of %d bytes but got %d", tc.expectSize, size)
+				}
+			}
+		})
+	}
+}
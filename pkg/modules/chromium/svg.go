@@ -0,0 +1,205 @@
+package chromium
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// ErrInvalidSvg happens if a file is not an SVG document.
+var ErrInvalidSvg = errors.New("invalid SVG")
+
+// svgSize is the intrinsic size of an SVG document, in CSS pixels. It is
+// zero-valued if the document does not declare it.
+type svgSize struct {
+	Width, Height float64
+}
+
+// known returns true if the size is declared.
+func (size svgSize) known() bool {
+	return size.Width > 0 && size.Height > 0
+}
+
+// readSvgSize reads the intrinsic size of an SVG document, either from the
+// width and height attributes of its root element or from its viewBox.
+func readSvgSize(path string) (svgSize, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return svgSize{}, fmt.Errorf("open SVG: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	decoder := xml.NewDecoder(f)
+	decoder.Strict = false
+	// The root element is enough; the encoding does not matter for its
+	// attributes.
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return svgSize{}, fmt.Errorf("read root element: %v: %w", err, ErrInvalidSvg)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		if start.Name.Local != "svg" {
+			return svgSize{}, fmt.Errorf("root element '%s': %w", start.Name.Local, ErrInvalidSvg)
+		}
+
+		var width, height, viewBoxWidth, viewBoxHeight float64
+		for _, attr := range start.Attr {
+			switch attr.Name.Local {
+			case "width":
+				width = parseSvgLength(attr.Value)
+			case "height":
+				height = parseSvgLength(attr.Value)
+			case "viewBox":
+				fields := strings.FieldsFunc(attr.Value, func(r rune) bool {
+					return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+				})
+				if len(fields) == 4 {
+					viewBoxWidth, _ = strconv.ParseFloat(fields[2], 64)
+					viewBoxHeight, _ = strconv.ParseFloat(fields[3], 64)
+				}
+			}
+		}
+
+		hasViewBox := viewBoxWidth > 0 && viewBoxHeight > 0
+
+		switch {
+		case width > 0 && height > 0:
+		case width > 0 && hasViewBox:
+			height = width * viewBoxHeight / viewBoxWidth
+		case height > 0 && hasViewBox:
+			width = height * viewBoxWidth / viewBoxHeight
+		case hasViewBox:
+			width, height = viewBoxWidth, viewBoxHeight
+		default:
+			return svgSize{}, nil
+		}
+
+		return svgSize{Width: width, Height: height}, nil
+	}
+}
+
+// svgUnits are the absolute units, with their size in CSS pixels.
+var svgUnits = []struct {
+	suffix string
+	pixels float64
+}{
+	{"px", 1},
+	{"pt", 96.0 / 72},
+	{"pc", 16},
+	{"in", 96},
+	{"cm", 96 / 2.54},
+	{"mm", 96 / 25.4},
+}
+
+// parseSvgLength converts an absolute length to CSS pixels. It returns 0 for
+// relative lengths, e.g., percentages.
+func parseSvgLength(value string) float64 {
+	value = strings.TrimSpace(value)
+
+	factor := 1.0
+	for _, unit := range svgUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSuffix(value, unit.suffix)
+			factor = unit.pixels
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number <= 0 || math.IsInf(number, 0) {
+		return 0
+	}
+
+	return number * factor
+}
+
+var svgTemplate = template.Must(template.New("svg").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>
+  html, body { margin: 0; padding: 0; }
+  .page { break-after: page; }
+  .page:last-child { break-after: auto; }
+  img { display: block; }
+  .fit img { max-width: 100%; max-height: 100vh; margin: auto; }
+  {{- range $i, $page := . }}{{ if $page.Sized }}
+  @page svg-{{ $i }} { size: {{ $page.Width }}px {{ $page.Height }}px; margin: 0; }
+  #svg-{{ $i }} { page: svg-{{ $i }}; }
+  {{- end }}{{ end }}
+</style>
+</head>
+<body>
+{{- range $i, $page := . }}
+<div class="page{{ if not $page.Sized }} fit{{ end }}" id="svg-{{ $i }}"><img src="{{ $page.Url }}"{{ if $page.Sized }} style="width: {{ $page.Width }}px; height: {{ $page.Height }}px"{{ end }}></div>
+{{- end }}
+</body>
+</html>
+`))
+
+// svgToHtml writes an HTML document which displays SVG documents, one per
+// page, and returns its URL. If sized is true, each page has the size of
+// its SVG document, if declared. Otherwise, the SVG documents fit their pages.
+//
+// The SVG documents are displayed as images: they do not run scripts nor
+// load external resources.
+func svgToHtml(ctx *api.Context, inputPaths []string, sized bool) (string, error) {
+	type svgPage struct {
+		Url           template.URL
+		Sized         bool
+		Width, Height string
+	}
+
+	pages := make([]svgPage, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		size, err := readSvgSize(inputPath)
+		if err != nil {
+			return "", fmt.Errorf("read size of '%s': %w", inputPath, err)
+		}
+
+		pages[i] = svgPage{
+			Url:    template.URL(fmt.Sprintf("file://%s", inputPath)),
+			Sized:  sized && size.known(),
+			Width:  strconv.FormatFloat(size.Width, 'f', -1, 64),
+			Height: strconv.FormatFloat(size.Height, 'f', -1, 64),
+		}
+	}
+
+	var buf bytes.Buffer
+	err := svgTemplate.Execute(&buf, pages)
+	if err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+
+	htmlPath := ctx.GeneratePath("", ".html")
+
+	err = os.WriteFile(htmlPath, buf.Bytes(), 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write HTML document: %w", err)
+	}
+
+	return fmt.Sprintf("file://%s", htmlPath), nil
+}
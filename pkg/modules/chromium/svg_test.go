@@ -0,0 +1,178 @@
+package chromium
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestReadSvgSize(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		content      string
+		expectSize   svgSize
+		expectError  bool
+		expectSvgErr bool
+	}{
+		{
+			scenario:   "width and height",
+			content:    `<svg xmlns="http://www.w3.org/2000/svg" width="200" height="100px"></svg>`,
+			expectSize: svgSize{Width: 200, Height: 100},
+		},
+		{
+			scenario:   "absolute units",
+			content:    `<svg xmlns="http://www.w3.org/2000/svg" width="1in" height="72pt"></svg>`,
+			expectSize: svgSize{Width: 96, Height: 96},
+		},
+		{
+			scenario:   "viewBox",
+			content:    `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 300,150"></svg>`,
+			expectSize: svgSize{Width: 300, Height: 150},
+		},
+		{
+			scenario:   "width and viewBox",
+			content:    `<svg xmlns="http://www.w3.org/2000/svg" width="600" viewBox="0 0 300 150"></svg>`,
+			expectSize: svgSize{Width: 600, Height: 300},
+		},
+		{
+			scenario:   "height and viewBox",
+			content:    `<svg xmlns="http://www.w3.org/2000/svg" height="50" viewBox="0 0 300 150"></svg>`,
+			expectSize: svgSize{Width: 100, Height: 50},
+		},
+		{
+			scenario:   "relative lengths",
+			content:    `<svg xmlns="http://www.w3.org/2000/svg" width="100%" height="100%"></svg>`,
+			expectSize: svgSize{},
+		},
+		{
+			scenario:   "prolog and comments",
+			content:    `<?xml version="1.0" encoding="ISO-8859-1"?><!DOCTYPE svg><!-- foo --><svg width="10" height="20"></svg>`,
+			expectSize: svgSize{Width: 10, Height: 20},
+		},
+		{
+			scenario:     "not an SVG",
+			content:      `<html></html>`,
+			expectError:  true,
+			expectSvgErr: true,
+		},
+		{
+			scenario:     "not XML",
+			content:      `foo`,
+			expectError:  true,
+			expectSvgErr: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "foo.svg")
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			size, err := readSvgSize(path)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectSvgErr && !errors.Is(err, ErrInvalidSvg) {
+				t.Errorf("expected ErrInvalidSvg but got: %v", err)
+			}
+
+			if tc.expectSize != size {
+				t.Errorf("expected %+v but got %+v", tc.expectSize, size)
+			}
+		})
+	}
+}
+
+func TestSvgToHtml(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		contents       []string
+		sized          bool
+		expectContains []string
+		expectMissing  []string
+	}{
+		{
+			scenario: "sized pages",
+			contents: []string{
+				`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50"></svg>`,
+				`<svg xmlns="http://www.w3.org/2000/svg"></svg>`,
+			},
+			sized: true,
+			expectContains: []string{
+				"@page svg-0 { size: 100px 50px; margin: 0; }",
+				`<div class="page" id="svg-0">`,
+				`<div class="page fit" id="svg-1">`,
+			},
+			expectMissing: []string{
+				"@page svg-1",
+			},
+		},
+		{
+			scenario: "fitted pages",
+			contents: []string{
+				`<svg xmlns="http://www.w3.org/2000/svg" width="100" height="50"></svg>`,
+			},
+			sized: false,
+			expectContains: []string{
+				`<div class="page fit" id="svg-0">`,
+			},
+			expectMissing: []string{
+				"@page svg-0",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(t.TempDir())
+
+			var inputPaths []string
+			for i, content := range tc.contents {
+				path := filepath.Join(ctx.DirPath(), strings.Repeat("a", i+1)+".svg")
+				err := os.WriteFile(path, []byte(content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				inputPaths = append(inputPaths, path)
+			}
+
+			url, err := svgToHtml(ctx.Context, inputPaths, tc.sized)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			html, err := os.ReadFile(strings.TrimPrefix(url, "file://"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			for _, inputPath := range inputPaths {
+				if !strings.Contains(string(html), "file://"+inputPath) {
+					t.Errorf("expected HTML to reference '%s' but got: %s", inputPath, html)
+				}
+			}
+
+			for _, expect := range tc.expectContains {
+				if !strings.Contains(string(html), expect) {
+					t.Errorf("expected HTML to contain '%s' but got: %s", expect, html)
+				}
+			}
+
+			for _, missing := range tc.expectMissing {
+				if strings.Contains(string(html), missing) {
+					t.Errorf("expected HTML not to contain '%s' but got: %s", missing, html)
+				}
+			}
+		})
+	}
+}
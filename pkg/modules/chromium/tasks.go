@@ -1,7 +1,6 @@
 package chromium
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"os"
@@ -15,7 +14,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func printToPdfActionFunc(logger *zap.Logger, outputPath string, options PdfOptions) chromedp.ActionFunc {
+func printToPdfActionFunc(logger *zap.Logger, outputPath string, options PdfOptions, chunkSize int) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
 		paperHeight := options.PaperHeight
 		pageRanges := options.PageRanges
@@ -87,7 +86,7 @@ func printToPdfActionFunc(logger *zap.Logger, outputPath string, options PdfOpti
 			}
 		}()
 
-		file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY, 0o600)
+		file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
 		if err != nil {
 			return fmt.Errorf("open output path: %w", err)
 		}
@@ -99,13 +98,13 @@ func printToPdfActionFunc(logger *zap.Logger, outputPath string, options PdfOpti
 			}
 		}()
 
-		buffer := bufio.NewReader(reader)
-
-		_, err = buffer.WriteTo(file)
+		written, err := copyStream(file, reader, chunkSize)
 		if err != nil {
 			return fmt.Errorf("write result to output path: %w", err)
 		}
 
+		logger.Debug(fmt.Sprintf("wrote %d bytes of PDF in chunks of %d bytes", written, chunkSize))
+
 		return nil
 	}
 }
@@ -118,6 +117,17 @@ func captureScreenshotActionFunc(logger *zap.Logger, outputPath string, options
 			WithOptimizeForSpeed(options.OptimizeForSpeed).
 			WithFormat(page.CaptureScreenshotFormat(options.Format))
 
+		if options.Clip {
+			captureScreenshot = captureScreenshot.
+				WithClip(&page.Viewport{
+					X:      0,
+					Y:      0,
+					Width:  float64(options.Width),
+					Height: float64(options.Height),
+					Scale:  1,
+				})
+		}
+
 		if options.Format == "jpeg" {
 			captureScreenshot = captureScreenshot.
 				WithQuality(int64(options.Quality))
@@ -151,6 +161,19 @@ func captureScreenshotActionFunc(logger *zap.Logger, outputPath string, options
 	}
 }
 
+func setDeviceMetricsOverrideActionFunc(logger *zap.Logger, width, height int) chromedp.ActionFunc {
+	return func(ctx context.Context) error {
+		logger.Debug(fmt.Sprintf("set device metrics override to %dx%d", width, height))
+
+		err := emulation.SetDeviceMetricsOverride(int64(width), int64(height), 1.0, false).Do(ctx)
+		if err == nil {
+			return nil
+		}
+
+		return fmt.Errorf("set device metrics override: %w", err)
+	}
+}
+
 func clearCacheActionFunc(logger *zap.Logger, clear bool) chromedp.ActionFunc {
 	return func(ctx context.Context) error {
 		// See https://github.com/gotenberg/gotenberg/issues/753.
@@ -0,0 +1,127 @@
+package clamav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alexliesenfeld/health"
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(ClamAv))
+}
+
+// ClamAv is a module which scans the files of the multipart requests with a
+// ClamAV daemon: the uploaded files before the conversions and, optionally,
+// the output files after.
+type ClamAv struct {
+	address     string
+	timeout     time.Duration
+	scanOutputs bool
+
+	client *client
+}
+
+// Descriptor returns a [ClamAv]'s module descriptor.
+func (mod *ClamAv) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "clamav",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("clamav", flag.ExitOnError)
+			fs.String("clamav-address", "", "Set the address of the ClamAV daemon, e.g., tcp://clamav:3310 or unix:///run/clamav/clamd.ctl - leave empty to disable the scans")
+			fs.Duration("clamav-timeout", time.Duration(30)*time.Second, "Set the time limit for scanning a file")
+			fs.Bool("clamav-scan-outputs", false, "Scan the output files too")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(ClamAv) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *ClamAv) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.address = flags.MustString("clamav-address")
+	mod.timeout = flags.MustDuration("clamav-timeout")
+	mod.scanOutputs = flags.MustBool("clamav-scan-outputs")
+
+	if mod.address == "" {
+		// Exit early.
+		return nil
+	}
+
+	c, err := newClient(mod.address, mod.timeout)
+	if err != nil {
+		return fmt.Errorf("create ClamAV client for '%s': %w", mod.address, err)
+	}
+
+	mod.client = c
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *ClamAv) Validate() error {
+	if mod.address == "" {
+		// Exit early.
+		return nil
+	}
+
+	if mod.timeout <= 0 {
+		return errors.New("timeout must be more than 0")
+	}
+
+	return nil
+}
+
+// Middlewares returns the middlewares.
+func (mod *ClamAv) Middlewares() ([]api.Middleware, error) {
+	if mod.address == "" {
+		return nil, nil
+	}
+
+	middlewares := []api.Middleware{inputsMiddleware(mod.client)}
+
+	if mod.scanOutputs {
+		middlewares = append(middlewares, outputsMiddleware(mod.client))
+	}
+
+	return middlewares, nil
+}
+
+// Checks adds a health check that verifies if the ClamAV daemon is
+// available.
+func (mod *ClamAv) Checks() ([]health.CheckerOption, error) {
+	if mod.address == "" {
+		return nil, nil
+	}
+
+	return []health.CheckerOption{
+		health.WithCheck(health.Check{
+			Name: "clamav",
+			Check: func(ctx context.Context) error {
+				return mod.client.ping(ctx)
+			},
+		}),
+	}, nil
+}
+
+// Ready returns no error if the module is ready.
+func (mod *ClamAv) Ready() error {
+	return nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*ClamAv)(nil)
+	_ gotenberg.Provisioner  = (*ClamAv)(nil)
+	_ gotenberg.Validator    = (*ClamAv)(nil)
+	_ api.MiddlewareProvider = (*ClamAv)(nil)
+	_ api.HealthChecker      = (*ClamAv)(nil)
+)
@@ -0,0 +1,179 @@
+package clamav
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestClamAv_Descriptor(t *testing.T) {
+	descriptor := new(ClamAv).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(ClamAv))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestClamAv_Provision(t *testing.T) {
+	newContext := func(args ...string) *gotenberg.Context {
+		fs := new(ClamAv).Descriptor().FlagSet
+
+		err := fs.Parse(args)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		ctx          *gotenberg.Context
+		expectClient bool
+		expectError  bool
+	}{
+		{
+			scenario: "disabled",
+			ctx:      newContext(),
+		},
+		{
+			scenario:    "invalid address",
+			ctx:         newContext("--clamav-address=http://clamav:3310"),
+			expectError: true,
+		},
+		{
+			scenario:     "success",
+			ctx:          newContext("--clamav-address=tcp://clamav:3310"),
+			expectClient: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(ClamAv)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if (mod.client != nil) != tc.expectClient {
+				t.Errorf("expected client %t but got %t", tc.expectClient, mod.client != nil)
+			}
+		})
+	}
+}
+
+func TestClamAv_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *ClamAv
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			mod:      &ClamAv{timeout: 0},
+		},
+		{
+			scenario:    "invalid timeout",
+			mod:         &ClamAv{address: "tcp://clamav:3310", timeout: 0},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			mod:      &ClamAv{address: "tcp://clamav:3310", timeout: time.Second},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestClamAv_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		mod               *ClamAv
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "disabled",
+			mod:               new(ClamAv),
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "input files only",
+			mod:               &ClamAv{address: "tcp://clamav:3310"},
+			expectMiddlewares: 1,
+		},
+		{
+			scenario:          "input and output files",
+			mod:               &ClamAv{address: "tcp://clamav:3310", scanOutputs: true},
+			expectMiddlewares: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			middlewares, err := tc.mod.Middlewares()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(middlewares) != tc.expectMiddlewares {
+				t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+			}
+		})
+	}
+}
+
+func TestClamAv_Checks(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		mod          *ClamAv
+		expectChecks int
+	}{
+		{
+			scenario:     "disabled",
+			mod:          new(ClamAv),
+			expectChecks: 0,
+		},
+		{
+			scenario:     "enabled",
+			mod:          &ClamAv{address: "tcp://clamav:3310"},
+			expectChecks: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			checks, err := tc.mod.Checks()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(checks) != tc.expectChecks {
+				t.Errorf("expected %d checks but got %d", tc.expectChecks, len(checks))
+			}
+		})
+	}
+}
+
+func TestClamAv_Ready(t *testing.T) {
+	err := new(ClamAv).Ready()
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+
+}
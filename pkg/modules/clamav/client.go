@@ -0,0 +1,177 @@
+package clamav
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrSizeLimitExceeded happens if a file is larger than the StreamMaxLength
+// of the ClamAV daemon.
+var ErrSizeLimitExceeded = errors.New("size limit exceeded")
+
+// chunkSize is the size of the chunks of the INSTREAM command.
+const chunkSize = 64 * 1024
+
+// client talks to a ClamAV daemon, over TCP or a Unix socket.
+type client struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// newClient parses an address, e.g., "tcp://clamav:3310" or
+// "unix:///run/clamav/clamd.ctl", and returns a client.
+func newClient(address string, timeout time.Duration) (*client, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("parse address: %w", err)
+	}
+
+	switch u.Scheme {
+	case "tcp":
+		if u.Host == "" {
+			return nil, errors.New("no host in TCP address")
+		}
+
+		return &client{network: "tcp", address: u.Host, timeout: timeout}, nil
+	case "unix":
+		if u.Path == "" {
+			return nil, errors.New("no path in Unix socket address")
+		}
+
+		return &client{network: "unix", address: u.Path, timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("unsupported scheme '%s', expected either 'tcp' or 'unix'", u.Scheme)
+	}
+}
+
+// ping checks if the daemon is available.
+func (c *client) ping(ctx context.Context) error {
+	response, err := c.command(ctx, "zPING\x00", nil)
+	if err != nil {
+		return err
+	}
+
+	if response != "PONG" {
+		return fmt.Errorf("unexpected response '%s'", response)
+	}
+
+	return nil
+}
+
+// scan streams a file to the daemon. It returns the name of the signature if
+// the file is infected, an empty string otherwise.
+func (c *client) scan(ctx context.Context, path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	response, err := c.command(ctx, "zINSTREAM\x00", f)
+	if err != nil {
+		return "", err
+	}
+
+	// E.g., "stream: OK" or "stream: Eicar-Signature FOUND".
+	response = strings.TrimPrefix(response, "stream: ")
+
+	switch {
+	case response == "OK":
+		return "", nil
+	case strings.HasSuffix(response, " FOUND"):
+		return strings.TrimSuffix(response, " FOUND"), nil
+	case strings.Contains(response, "size limit exceeded"):
+		return "", fmt.Errorf("scan '%s': %w", path, ErrSizeLimitExceeded)
+	default:
+		return "", fmt.Errorf("scan '%s': unexpected response '%s'", path, response)
+	}
+}
+
+// command sends a command, with the chunks of a stream if any, and returns
+// the response, without its NULL terminator.
+func (c *client) command(ctx context.Context, command string, stream io.Reader) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, c.network, c.address)
+	if err != nil {
+		return "", fmt.Errorf("connect to ClamAV daemon: %w", err)
+	}
+
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	deadline, _ := ctx.Deadline()
+
+	err = conn.SetDeadline(deadline)
+	if err != nil {
+		return "", fmt.Errorf("set deadline: %w", err)
+	}
+
+	w := bufio.NewWriterSize(conn, chunkSize+4)
+
+	_, err = w.WriteString(command)
+	if err != nil {
+		return "", fmt.Errorf("write command: %w", err)
+	}
+
+	if stream != nil {
+		chunk := make([]byte, chunkSize)
+		size := make([]byte, 4)
+
+		for {
+			n, err := stream.Read(chunk)
+			if n > 0 {
+				binary.BigEndian.PutUint32(size, uint32(n))
+
+				_, err := w.Write(size)
+				if err == nil {
+					_, err = w.Write(chunk[:n])
+				}
+
+				if err != nil {
+					// The daemon closes the connection when the size limit
+					// is exceeded, and writes why.
+					break
+				}
+			}
+
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				return "", fmt.Errorf("read stream: %w", err)
+			}
+		}
+
+		// A zero-length chunk ends the stream.
+		_, _ = w.Write([]byte{0, 0, 0, 0})
+	}
+
+	_ = w.Flush()
+
+	response, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && len(response) == 0 {
+		return "", fmt.Errorf("read response: %w", err)
+	}
+
+	return string(bytes.TrimRight(response, "\x00\n")), nil
+}
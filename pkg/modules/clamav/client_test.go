@@ -0,0 +1,265 @@
+package clamav
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClamd starts a server which mimics a ClamAV daemon: streams containing
+// "EICAR" are infected, and streams containing "TOOLARGE" exceed the size
+// limit. It returns its address.
+func fakeClamd(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer func() {
+					_ = conn.Close()
+				}()
+
+				r := bufio.NewReader(conn)
+
+				command, err := r.ReadString(0)
+				if err != nil {
+					return
+				}
+
+				if command == "zPING\x00" {
+					_, _ = conn.Write([]byte("PONG\x00"))
+					return
+				}
+
+				var stream bytes.Buffer
+				size := make([]byte, 4)
+
+				for {
+					_, err = io.ReadFull(r, size)
+					if err != nil {
+						return
+					}
+
+					n := binary.BigEndian.Uint32(size)
+					if n == 0 {
+						break
+					}
+
+					_, err = io.CopyN(&stream, r, int64(n))
+					if err != nil {
+						return
+					}
+				}
+
+				switch {
+				case strings.Contains(stream.String(), "EICAR"):
+					_, _ = conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+				case strings.Contains(stream.String(), "TOOLARGE"):
+					_, _ = conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+				case strings.Contains(stream.String(), "BROKEN"):
+					_, _ = conn.Write([]byte("stream: Can't allocate memory ERROR\x00"))
+				default:
+					_, _ = conn.Write([]byte("stream: OK\x00"))
+				}
+			}()
+		}
+	}()
+
+	return "tcp://" + listener.Addr().String()
+}
+
+func TestNewClient(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		address       string
+		expectNetwork string
+		expectAddress string
+		expectError   bool
+	}{
+		{
+			scenario:      "TCP address",
+			address:       "tcp://clamav:3310",
+			expectNetwork: "tcp",
+			expectAddress: "clamav:3310",
+		},
+		{
+			scenario:      "Unix socket address",
+			address:       "unix:///run/clamav/clamd.ctl",
+			expectNetwork: "unix",
+			expectAddress: "/run/clamav/clamd.ctl",
+		},
+		{
+			scenario:    "TCP address without host",
+			address:     "tcp://",
+			expectError: true,
+		},
+		{
+			scenario:    "Unix socket address without path",
+			address:     "unix://",
+			expectError: true,
+		},
+		{
+			scenario:    "unsupported scheme",
+			address:     "http://clamav:3310",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid address",
+			address:     "://",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			c, err := newClient(tc.address, time.Second)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if c.network != tc.expectNetwork || c.address != tc.expectAddress {
+				t.Errorf("expected '%s' '%s' but got '%s' '%s'", tc.expectNetwork, tc.expectAddress, c.network, c.address)
+			}
+		})
+	}
+}
+
+func TestClient_ping(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		address     string
+		expectError bool
+	}{
+		{
+			scenario: "daemon available",
+			address:  fakeClamd(t),
+		},
+		{
+			scenario:    "daemon unavailable",
+			address:     "unix:///foo/clamd.ctl",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			c, err := newClient(tc.address, time.Second)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = c.ping(context.Background())
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestClient_scan(t *testing.T) {
+	address := fakeClamd(t)
+
+	for _, tc := range []struct {
+		scenario        string
+		content         string
+		expectSignature string
+		expectError     bool
+		expectedError   error
+	}{
+		{
+			scenario: "clean file",
+			content:  strings.Repeat("foo", chunkSize),
+		},
+		{
+			scenario:        "infected file",
+			content:         "X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*",
+			expectSignature: "Eicar-Test-Signature",
+		},
+		{
+			scenario:      "file too large",
+			content:       "TOOLARGE",
+			expectError:   true,
+			expectedError: ErrSizeLimitExceeded,
+		},
+		{
+			scenario:    "scan error",
+			content:     "BROKEN",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "foo.pdf")
+
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			c, err := newClient(address, time.Second)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			signature, err := c.scan(context.Background(), path)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if signature != tc.expectSignature {
+				t.Errorf("expected signature '%s' but got '%s'", tc.expectSignature, signature)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		c, err := newClient(address, time.Second)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		_, err = c.scan(context.Background(), "/foo/bar.pdf")
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+}
@@ -0,0 +1,4 @@
+// Package clamav provides a module which scans the uploaded files, and
+// optionally the output files, with a ClamAV daemon. The requests with
+// infected files are rejected.
+package clamav
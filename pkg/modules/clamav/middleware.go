@@ -0,0 +1,107 @@
+package clamav
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// inputsMiddleware scans the uploaded files before any other multipart
+// middleware, so that the requests with infected files are rejected
+// synchronously, even with a webhook.
+func inputsMiddleware(c *client) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryHighPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(e echo.Context) error {
+					ctx := e.Get("context").(*api.Context)
+
+					err := scanFiles(ctx, c, ctx.InputPaths(), api.ErrorCodeClamavInfectedFile)
+					if err != nil {
+						return fmt.Errorf("scan input files: %w", err)
+					}
+
+					return next(e)
+				}
+			}
+		}(),
+	}
+}
+
+// outputsMiddleware scans the output files once the route handler is done.
+// It runs after the webhook middleware, so that the asynchronous conversions
+// are also scanned.
+func outputsMiddleware(c *client) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryLowPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(e echo.Context) error {
+					err := next(e)
+					if err != nil {
+						return err
+					}
+
+					ctx := e.Get("context").(*api.Context)
+
+					err = scanFiles(ctx, c, ctx.OutputPaths(), api.ErrorCodeClamavInfectedOutput)
+					if err != nil {
+						return fmt.Errorf("scan output files: %w", err)
+					}
+
+					return nil
+				}
+			}
+		}(),
+	}
+}
+
+// scanFiles scans files one after the other, and stops at the first
+// infected file.
+func scanFiles(ctx *api.Context, c *client, paths []string, infectedCode string) error {
+	for _, path := range paths {
+		filename := filepath.Base(path)
+
+		signature, err := c.scan(ctx, path)
+		if err != nil {
+			if errors.Is(err, ErrSizeLimitExceeded) {
+				return api.WrapError(
+					err,
+					api.NewSentinelHttpError(
+						http.StatusRequestEntityTooLarge,
+						fmt.Sprintf("The file '%s' is too large to be scanned", filename),
+					).WithCode(api.ErrorCodeClamavFileTooLarge),
+				)
+			}
+
+			return api.WrapError(
+				err,
+				api.NewSentinelHttpError(http.StatusServiceUnavailable, "The antivirus is unavailable, please try again later").WithCode(api.ErrorCodeClamavUnavailable),
+			)
+		}
+
+		if signature != "" {
+			ctx.Log().Warn(fmt.Sprintf("'%s' infected by '%s'", filename, signature))
+
+			return api.WrapError(
+				fmt.Errorf("'%s' infected by '%s'", filename, signature),
+				api.NewSentinelHttpError(
+					http.StatusUnprocessableEntity,
+					fmt.Sprintf("The file '%s' is infected (%s)", filename, signature),
+				).WithCode(infectedCode),
+			)
+		}
+
+		ctx.Log().Debug(fmt.Sprintf("'%s' scanned, no threat found", filename))
+	}
+
+	return nil
+}
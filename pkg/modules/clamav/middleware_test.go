@@ -0,0 +1,149 @@
+package clamav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestMiddlewares(t *testing.T) {
+	clean := "%PDF-1.7"
+	infected := "X5O!P%@AP[4\\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*"
+
+	for _, tc := range []struct {
+		scenario       string
+		address        string
+		outputs        bool
+		input          string
+		output         string
+		expectStatus   int
+		expectCode     string
+		expectNextCall bool
+	}{
+		{
+			scenario:       "clean input file",
+			input:          clean,
+			expectStatus:   http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			scenario:     "infected input file",
+			input:        infected,
+			expectStatus: http.StatusUnprocessableEntity,
+			expectCode:   "CLAMAV_INFECTED_FILE",
+		},
+		{
+			scenario:     "input file too large",
+			input:        "TOOLARGE",
+			expectStatus: http.StatusRequestEntityTooLarge,
+			expectCode:   "CLAMAV_FILE_TOO_LARGE",
+		},
+		{
+			scenario:     "daemon unavailable",
+			address:      "unix:///foo/clamd.ctl",
+			input:        clean,
+			expectStatus: http.StatusServiceUnavailable,
+			expectCode:   "CLAMAV_UNAVAILABLE",
+		},
+		{
+			scenario:       "clean output file",
+			outputs:        true,
+			input:          clean,
+			output:         clean,
+			expectStatus:   http.StatusOK,
+			expectNextCall: true,
+		},
+		{
+			scenario:       "infected output file",
+			outputs:        true,
+			input:          clean,
+			output:         infected,
+			expectStatus:   http.StatusUnprocessableEntity,
+			expectCode:     "CLAMAV_INFECTED_OUTPUT",
+			expectNextCall: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			address := tc.address
+			if address == "" {
+				address = fakeClamd(t)
+			}
+
+			c, err := newClient(address, time.Second)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, "foo.pdf")
+
+			err = os.WriteFile(inputPath, []byte(tc.input), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(dirPath)
+			ctx.SetFiles(map[string]string{"foo.pdf": inputPath})
+			ctx.SetLogger(zap.NewNop())
+			ctx.Context.Context = context.Background()
+
+			e := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/foo", nil), httptest.NewRecorder())
+			e.Set("context", ctx.Context)
+
+			var nextCalled bool
+			next := func(e echo.Context) error {
+				nextCalled = true
+
+				if tc.output == "" {
+					return nil
+				}
+
+				outputPath := ctx.GeneratePath("", ".pdf")
+
+				err := os.WriteFile(outputPath, []byte(tc.output), 0o600)
+				if err != nil {
+					return err
+				}
+
+				return ctx.AddOutputPaths(outputPath)
+			}
+
+			handler := next
+			if tc.outputs {
+				handler = outputsMiddleware(c).Handler(handler)
+			}
+
+			err = inputsMiddleware(c).Handler(handler)(e)
+
+			status := http.StatusOK
+			var code string
+			if err != nil {
+				response := api.ParseErrorResponse(err)
+				status = response.Status
+				code = response.Code
+			}
+
+			if status != tc.expectStatus {
+				t.Fatalf("expected status %d but got %d: %v", tc.expectStatus, status, err)
+			}
+
+			if code != tc.expectCode {
+				t.Errorf("expected code '%s' but got '%s'", tc.expectCode, code)
+			}
+
+			if nextCalled != tc.expectNextCall {
+				t.Errorf("expected next call %t but got %t", tc.expectNextCall, nextCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,370 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/labstack/gommon/bytes"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Concurrency))
+}
+
+// decreaseFactor is the factor applied to the limit when the system is
+// overloaded.
+const decreaseFactor = 0.75
+
+// Concurrency is a module which limits the number of concurrent conversions
+// and adjusts this limit according to the system load: it increases the limit
+// by one while the requests wait too long for a slot, and decreases it by a
+// quarter as soon as the CPU load or the memory usage exceed their targets.
+//
+// It may also route the oversized requests, according to the size and the
+// page count of their input files, to a slow lane: a separate pool of slots
+// with its own bounded queue, so that a few 1,000-page conversions do not
+// delay all the others.
+type Concurrency struct {
+	min                int
+	max                int
+	targetCpuLoad      float64
+	targetMemoryUsage  float64
+	targetQueueLatency time.Duration
+	interval           time.Duration
+
+	slowLaneMax       int
+	slowLaneQueueSize int
+	slowLaneInputSize int64
+	slowLanePageCount int
+
+	loadAvgPath string
+	memInfoPath string
+	numCpu      int
+
+	limiter  *limiter
+	slowLane *limiter
+	logger   *zap.Logger
+	cancel   context.CancelFunc
+}
+
+// Descriptor returns a [Concurrency]'s module descriptor.
+func (mod *Concurrency) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "concurrency",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("concurrency", flag.ExitOnError)
+			fs.Int("concurrency-max", 0, "Set the maximum number of concurrent conversions. Set to 0 to disable the adaptive concurrency")
+			fs.Int("concurrency-min", 1, "Set the minimum number of concurrent conversions")
+			fs.Float64("concurrency-target-cpu-load", 0.8, "Set the load average per CPU above which the concurrency decreases")
+			fs.Float64("concurrency-target-memory-usage", 0.8, "Set the ratio of memory usage above which the concurrency decreases")
+			fs.Duration("concurrency-target-queue-latency", time.Duration(1)*time.Second, "Set the time waited by requests above which the concurrency increases, if the system is not overloaded")
+			fs.Duration("concurrency-adjust-interval", time.Duration(1)*time.Second, "Set the interval for adjusting the concurrency")
+			fs.Int("concurrency-slow-lane-max", 0, "Set the number of concurrent conversions of the oversized requests. Set to 0 to disable the slow lane")
+			fs.Int("concurrency-slow-lane-queue-size", 10, "Set the maximum number of oversized requests waiting for a slot - 0 means no limit")
+			fs.String("concurrency-slow-lane-input-size", "0B", "Set the total size of the input files from which a request is oversized, e.g., 100MB - 0B disables this criterion")
+			fs.Int("concurrency-slow-lane-page-count", 0, "Set the estimated total number of pages of the input files from which a request is oversized - 0 disables this criterion")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Concurrency) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Concurrency) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.max = flags.MustInt("concurrency-max")
+	mod.min = flags.MustInt("concurrency-min")
+	mod.targetCpuLoad = flags.MustFloat64("concurrency-target-cpu-load")
+	mod.targetMemoryUsage = flags.MustFloat64("concurrency-target-memory-usage")
+	mod.targetQueueLatency = flags.MustDuration("concurrency-target-queue-latency")
+	mod.interval = flags.MustDuration("concurrency-adjust-interval")
+	mod.slowLaneMax = flags.MustInt("concurrency-slow-lane-max")
+	mod.slowLaneQueueSize = flags.MustInt("concurrency-slow-lane-queue-size")
+	mod.slowLanePageCount = flags.MustInt("concurrency-slow-lane-page-count")
+
+	slowLaneInputSize, err := bytes.Parse(flags.MustHumanReadableBytesString("concurrency-slow-lane-input-size"))
+	if err != nil {
+		return fmt.Errorf("parse slow lane input size: %w", err)
+	}
+
+	mod.slowLaneInputSize = slowLaneInputSize
+
+	mod.loadAvgPath = loadAvgPath
+	mod.memInfoPath = memInfoPath
+	mod.numCpu = runtime.NumCPU()
+
+	if mod.slowLaneMax > 0 {
+		mod.slowLane = newLimiter(mod.slowLaneMax)
+		mod.slowLane.maxWaiting = mod.slowLaneQueueSize
+	}
+
+	if mod.max == 0 {
+		// Exit early.
+		return nil
+	}
+
+	mod.limiter = newLimiter(mod.min)
+
+	// Logger.
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Concurrency) Validate() error {
+	var err error
+
+	if mod.slowLaneMax < 0 {
+		err = multierr.Append(err,
+			errors.New("slow lane concurrency must be at least 0"),
+		)
+	}
+
+	if mod.slowLaneMax > 0 {
+		if mod.slowLaneQueueSize < 0 {
+			err = multierr.Append(err,
+				errors.New("slow lane queue size must be at least 0"),
+			)
+		}
+
+		if mod.slowLaneInputSize <= 0 && mod.slowLanePageCount <= 0 {
+			err = multierr.Append(err,
+				errors.New("slow lane requires an input size or a page count"),
+			)
+		}
+	}
+
+	if mod.max == 0 {
+		// Exit early.
+		return err
+	}
+
+	if mod.min < 1 {
+		err = multierr.Append(err,
+			errors.New("minimum concurrency must be at least 1"),
+		)
+	}
+
+	if mod.max < mod.min {
+		err = multierr.Append(err,
+			errors.New("maximum concurrency must be at least the minimum concurrency"),
+		)
+	}
+
+	if mod.targetCpuLoad <= 0 {
+		err = multierr.Append(err,
+			errors.New("target CPU load must be more than 0"),
+		)
+	}
+
+	if mod.targetMemoryUsage <= 0 || mod.targetMemoryUsage > 1 {
+		err = multierr.Append(err,
+			errors.New("target memory usage must be more than 0 and at most 1"),
+		)
+	}
+
+	if mod.targetQueueLatency < 0 {
+		err = multierr.Append(err,
+			errors.New("target queue latency must be at least 0"),
+		)
+	}
+
+	if mod.interval <= 0 {
+		err = multierr.Append(err,
+			errors.New("adjust interval must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Start starts adjusting the concurrency.
+func (mod *Concurrency) Start() error {
+	if mod.max == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mod.cancel = cancel
+
+	go func() {
+		ticker := time.NewTicker(mod.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				mod.adjust()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Concurrency) StartupMessage() string {
+	msg := "adaptive concurrency disabled"
+	if mod.max > 0 {
+		msg = fmt.Sprintf("adaptive concurrency between %d and %d", mod.min, mod.max)
+	}
+
+	if mod.slowLaneMax > 0 {
+		msg = fmt.Sprintf("%s, slow lane of %d", msg, mod.slowLaneMax)
+	}
+
+	return msg
+}
+
+// Stop stops adjusting the concurrency.
+func (mod *Concurrency) Stop(ctx context.Context) error {
+	if mod.cancel != nil {
+		mod.cancel()
+	}
+
+	return nil
+}
+
+// Middlewares returns the middleware.
+func (mod *Concurrency) Middlewares() ([]api.Middleware, error) {
+	if mod.max == 0 && mod.slowLaneMax == 0 {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		concurrencyMiddleware(mod),
+	}, nil
+}
+
+// Metrics returns the metrics.
+func (mod *Concurrency) Metrics() ([]gotenberg.Metric, error) {
+	var metrics []gotenberg.Metric
+
+	if mod.max > 0 {
+		metrics = append(metrics,
+			gotenberg.Metric{
+				Name:        "concurrency_limit",
+				Description: "Current maximum number of concurrent conversions.",
+				Read: func() float64 {
+					limit, _, _ := mod.limiter.stats()
+					return float64(limit)
+				},
+			},
+			gotenberg.Metric{
+				Name:        "concurrency_in_flight",
+				Description: "Current number of concurrent conversions.",
+				Read: func() float64 {
+					_, inFlight, _ := mod.limiter.stats()
+					return float64(inFlight)
+				},
+			},
+			gotenberg.Metric{
+				Name:        "concurrency_waiting",
+				Description: "Current number of conversions waiting for a slot.",
+				Read: func() float64 {
+					_, _, waiting := mod.limiter.stats()
+					return float64(waiting)
+				},
+			},
+		)
+	}
+
+	if mod.slowLaneMax > 0 {
+		metrics = append(metrics,
+			gotenberg.Metric{
+				Name:        "concurrency_slow_lane_in_flight",
+				Description: "Current number of concurrent conversions of the oversized requests.",
+				Read: func() float64 {
+					_, inFlight, _ := mod.slowLane.stats()
+					return float64(inFlight)
+				},
+			},
+			gotenberg.Metric{
+				Name:        "concurrency_slow_lane_waiting",
+				Description: "Current number of oversized requests waiting for a slot.",
+				Read: func() float64 {
+					_, _, waiting := mod.slowLane.stats()
+					return float64(waiting)
+				},
+			},
+		)
+	}
+
+	return metrics, nil
+}
+
+// adjust decreases the limit if the system is overloaded, or increases it if
+// the requests wait too long for a slot.
+func (mod *Concurrency) adjust() {
+	limit, _, _ := mod.limiter.stats()
+	maxWait := mod.limiter.takeMaxWait()
+
+	newLimit := limit
+
+	switch {
+	case mod.overloaded():
+		newLimit = max(mod.min, int(float64(limit)*decreaseFactor))
+	case maxWait > mod.targetQueueLatency:
+		newLimit = min(mod.max, limit+1)
+	}
+
+	if newLimit == limit {
+		return
+	}
+
+	mod.logger.Debug(fmt.Sprintf("adjust concurrency from %d to %d (longest wait: %s)", limit, newLimit, maxWait))
+	mod.limiter.setLimit(newLimit)
+}
+
+// overloaded tells if the CPU load or the memory usage exceed their targets.
+// A metric which cannot be read is ignored.
+func (mod *Concurrency) overloaded() bool {
+	load, err := readLoad(mod.loadAvgPath)
+	if err != nil {
+		mod.logger.Debug(fmt.Sprintf("read CPU load: %s", err))
+	} else if load/float64(mod.numCpu) > mod.targetCpuLoad {
+		return true
+	}
+
+	usage, err := readMemoryUsage(mod.memInfoPath)
+	if err != nil {
+		mod.logger.Debug(fmt.Sprintf("read memory usage: %s", err))
+	} else if usage > mod.targetMemoryUsage {
+		return true
+	}
+
+	return false
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module          = (*Concurrency)(nil)
+	_ gotenberg.Provisioner     = (*Concurrency)(nil)
+	_ gotenberg.Validator       = (*Concurrency)(nil)
+	_ gotenberg.App             = (*Concurrency)(nil)
+	_ gotenberg.MetricsProvider = (*Concurrency)(nil)
+	_ api.MiddlewareProvider    = (*Concurrency)(nil)
+)
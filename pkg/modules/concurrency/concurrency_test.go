@@ -0,0 +1,369 @@
+package concurrency
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestConcurrency_Descriptor(t *testing.T) {
+	descriptor := new(Concurrency).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Concurrency))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestConcurrency_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		ctx            *gotenberg.Context
+		expectLimiter  bool
+		expectSlowLane bool
+		expectError    bool
+		expectPanic    bool
+	}{
+		{
+			scenario: "disabled",
+			ctx: gotenberg.NewContext(
+				gotenberg.ParsedFlags{
+					FlagSet: new(Concurrency).Descriptor().FlagSet,
+				},
+				nil,
+			),
+		},
+		{
+			scenario: "invalid slow lane input size",
+			ctx: func() *gotenberg.Context {
+				fs := new(Concurrency).Descriptor().FlagSet
+				err := fs.Parse([]string{"--concurrency-slow-lane-input-size=foo"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectPanic: true,
+		},
+		{
+			scenario: "slow lane only",
+			ctx: func() *gotenberg.Context {
+				fs := new(Concurrency).Descriptor().FlagSet
+				err := fs.Parse([]string{"--concurrency-slow-lane-max=1", "--concurrency-slow-lane-page-count=1000"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectSlowLane: true,
+		},
+		{
+			scenario: "no logger provider",
+			ctx: func() *gotenberg.Context {
+				fs := new(Concurrency).Descriptor().FlagSet
+				err := fs.Parse([]string{"--concurrency-max=4"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectLimiter: true,
+			expectError:   true,
+		},
+		{
+			scenario: "success",
+			ctx: func() *gotenberg.Context {
+				provider := &struct {
+					gotenberg.ModuleMock
+					gotenberg.LoggerProviderMock
+				}{}
+				provider.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "foo", New: func() gotenberg.Module { return provider }}
+				}
+				provider.LoggerMock = func(mod gotenberg.Module) (*zap.Logger, error) {
+					return zap.NewNop(), nil
+				}
+
+				fs := new(Concurrency).Descriptor().FlagSet
+				err := fs.Parse([]string{"--concurrency-max=4"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{FlagSet: fs},
+					[]gotenberg.ModuleDescriptor{
+						provider.Descriptor(),
+					},
+				)
+			}(),
+			expectLimiter: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			if tc.expectPanic {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Fatal("expected panic but got none")
+					}
+				}()
+			}
+
+			if !tc.expectPanic {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("expected no panic but got: %v", r)
+					}
+				}()
+			}
+
+			mod := new(Concurrency)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if (mod.limiter != nil) != tc.expectLimiter {
+				t.Errorf("expected limiter %t but got %t", tc.expectLimiter, mod.limiter != nil)
+			}
+
+			if (mod.slowLane != nil) != tc.expectSlowLane {
+				t.Errorf("expected slow lane %t but got %t", tc.expectSlowLane, mod.slowLane != nil)
+			}
+		})
+	}
+}
+
+func TestConcurrency_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Concurrency
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			mod:      &Concurrency{max: 0, min: 0},
+		},
+		{
+			scenario:    "invalid properties",
+			mod:         &Concurrency{max: 1, min: 2, targetMemoryUsage: 2, targetQueueLatency: -1},
+			expectError: true,
+		},
+		{
+			scenario:    "slow lane without criterion",
+			mod:         &Concurrency{slowLaneMax: 1},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid slow lane queue size",
+			mod:         &Concurrency{slowLaneMax: 1, slowLaneQueueSize: -1, slowLanePageCount: 1000},
+			expectError: true,
+		},
+		{
+			scenario: "slow lane without adaptive concurrency",
+			mod:      &Concurrency{slowLaneMax: 1, slowLaneInputSize: 1024},
+		},
+		{
+			scenario: "validate success",
+			mod: &Concurrency{
+				max:                4,
+				min:                1,
+				targetCpuLoad:      0.8,
+				targetMemoryUsage:  0.8,
+				targetQueueLatency: time.Duration(1) * time.Second,
+				interval:           time.Duration(1) * time.Second,
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestConcurrency_Start(t *testing.T) {
+	mod := &Concurrency{
+		max:      2,
+		min:      1,
+		interval: time.Duration(10) * time.Millisecond,
+		limiter:  newLimiter(1),
+		logger:   zap.NewNop(),
+	}
+
+	err := mod.Start()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.StartupMessage() == "" {
+		t.Error("expected a startup message")
+	}
+
+	err = mod.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestConcurrency_adjust(t *testing.T) {
+	writeFile := func(name, content string) string {
+		path := filepath.Join(t.TempDir(), name)
+
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return path
+	}
+
+	idleLoad := writeFile("loadavg", "0.10 0.10 0.10 1/1 1")
+	highLoad := writeFile("loadavg", "8.00 0.10 0.10 1/1 1")
+	lowMemory := writeFile("meminfo", "MemTotal: 1000 kB\nMemAvailable: 900 kB\n")
+	highMemory := writeFile("meminfo", "MemTotal: 1000 kB\nMemAvailable: 100 kB\n")
+
+	for _, tc := range []struct {
+		scenario    string
+		limit       int
+		loadAvgPath string
+		memInfoPath string
+		maxWait     time.Duration
+		expectLimit int
+	}{
+		{
+			scenario:    "high CPU load",
+			limit:       8,
+			loadAvgPath: highLoad,
+			memInfoPath: lowMemory,
+			expectLimit: 6,
+		},
+		{
+			scenario:    "high memory usage",
+			limit:       8,
+			loadAvgPath: idleLoad,
+			memInfoPath: highMemory,
+			expectLimit: 6,
+		},
+		{
+			scenario:    "decrease down to the minimum",
+			limit:       2,
+			loadAvgPath: highLoad,
+			memInfoPath: highMemory,
+			expectLimit: 2,
+		},
+		{
+			scenario:    "long queue latency",
+			limit:       4,
+			loadAvgPath: idleLoad,
+			memInfoPath: lowMemory,
+			maxWait:     time.Duration(2) * time.Second,
+			expectLimit: 5,
+		},
+		{
+			scenario:    "increase up to the maximum",
+			limit:       8,
+			loadAvgPath: idleLoad,
+			memInfoPath: lowMemory,
+			maxWait:     time.Duration(2) * time.Second,
+			expectLimit: 8,
+		},
+		{
+			scenario:    "unreadable metrics and short queue latency",
+			limit:       4,
+			loadAvgPath: "/foo",
+			memInfoPath: "/foo",
+			expectLimit: 4,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Concurrency{
+				min:                2,
+				max:                8,
+				targetCpuLoad:      0.8,
+				targetMemoryUsage:  0.8,
+				targetQueueLatency: time.Duration(1) * time.Second,
+				loadAvgPath:        tc.loadAvgPath,
+				memInfoPath:        tc.memInfoPath,
+				numCpu:             2,
+				limiter:            newLimiter(tc.limit),
+				logger:             zap.NewNop(),
+			}
+			mod.limiter.maxWait = tc.maxWait
+
+			mod.adjust()
+
+			limit, _, _ := mod.limiter.stats()
+			if limit != tc.expectLimit {
+				t.Errorf("expected limit %d but got %d", tc.expectLimit, limit)
+			}
+		})
+	}
+}
+
+func TestConcurrency_Metrics(t *testing.T) {
+	mod := &Concurrency{max: 0}
+
+	metrics, err := mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(metrics) != 0 {
+		t.Errorf("expected no metric but got %d", len(metrics))
+	}
+
+	mod = &Concurrency{max: 4, limiter: newLimiter(3)}
+
+	metrics, err = mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(metrics) != 3 {
+		t.Fatalf("expected 3 metrics but got %d", len(metrics))
+	}
+
+	if metrics[0].Read() != 3 {
+		t.Errorf("expected limit 3 but got %f", metrics[0].Read())
+	}
+
+	mod = &Concurrency{max: 4, limiter: newLimiter(3), slowLaneMax: 1, slowLane: newLimiter(1)}
+
+	metrics, err = mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(metrics) != 5 {
+		t.Fatalf("expected 5 metrics but got %d", len(metrics))
+	}
+
+	if metrics[3].Name != "concurrency_slow_lane_in_flight" || metrics[3].Read() != 0 {
+		t.Errorf("expected no slow lane conversion but got %f", metrics[3].Read())
+	}
+}
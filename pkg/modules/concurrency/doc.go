@@ -0,0 +1,6 @@
+// Package concurrency provides a module which limits the number of concurrent
+// conversions and adjusts this limit dynamically according to the CPU load,
+// the memory usage, and the time requests wait for a slot. It may also route
+// the oversized requests to a slow lane, i.e., a separate bounded pool of
+// slots and queue.
+package concurrency
@@ -0,0 +1,162 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// errQueueFull happens if a task cannot wait for a slot, as too many tasks
+// already do.
+var errQueueFull = errors.New("queue full")
+
+// limiter admits up to a limit of concurrent tasks. The other tasks wait, in
+// a FIFO fashion, for a slot to be released or for their context to be done.
+type limiter struct {
+	limit      int
+	maxWaiting int
+	inFlight   int
+	waiters    []waiter
+	maxWait    time.Duration
+	mu         sync.Mutex
+}
+
+// waiter is a task waiting for a slot.
+type waiter struct {
+	ch    chan struct{}
+	start time.Time
+}
+
+func newLimiter(limit int) *limiter {
+	return &limiter{
+		limit: limit,
+	}
+}
+
+// acquire waits for a slot. It returns the context error if the context is
+// done before, or [errQueueFull] if the maximum number of waiting tasks is
+// reached.
+func (l *limiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+
+	if l.inFlight < l.limit && len(l.waiters) == 0 {
+		l.inFlight++
+		l.mu.Unlock()
+
+		return nil
+	}
+
+	if l.maxWaiting > 0 && len(l.waiters) >= l.maxWaiting {
+		l.mu.Unlock()
+
+		return errQueueFull
+	}
+
+	w := waiter{
+		ch:    make(chan struct{}),
+		start: time.Now(),
+	}
+	l.waiters = append(l.waiters, w)
+	l.mu.Unlock()
+
+	select {
+	case <-w.ch:
+		l.observeWait(time.Since(w.start))
+
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		for i, other := range l.waiters {
+			if other.ch == w.ch {
+				l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+				l.observeWaitLocked(time.Since(w.start))
+
+				return ctx.Err()
+			}
+		}
+
+		// The slot has been granted in the meantime: give it back.
+		l.releaseLocked()
+
+		return ctx.Err()
+	}
+}
+
+// release releases a slot, either to the first waiting task or to the pool.
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.releaseLocked()
+}
+
+func (l *limiter) releaseLocked() {
+	if len(l.waiters) > 0 && l.inFlight <= l.limit {
+		// The slot goes straight to the first waiting task.
+		close(l.waiters[0].ch)
+		l.waiters = l.waiters[1:]
+
+		return
+	}
+
+	l.inFlight--
+}
+
+// setLimit updates the limit and, if it increases, grants the new slots to
+// the waiting tasks. If it decreases, the tasks in flight are not affected.
+func (l *limiter) setLimit(limit int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+
+	for l.inFlight < l.limit && len(l.waiters) > 0 {
+		close(l.waiters[0].ch)
+		l.waiters = l.waiters[1:]
+		l.inFlight++
+	}
+}
+
+// observeWait records the time a task waited for a slot.
+func (l *limiter) observeWait(wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.observeWaitLocked(wait)
+}
+
+func (l *limiter) observeWaitLocked(wait time.Duration) {
+	if wait > l.maxWait {
+		l.maxWait = wait
+	}
+}
+
+// stats returns the current limit and the number of tasks in flight and
+// waiting.
+func (l *limiter) stats() (int, int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.limit, l.inFlight, len(l.waiters)
+}
+
+// takeMaxWait returns the longest wait since the last call, and resets it. The
+// tasks still waiting count for the time they have waited so far.
+func (l *limiter) takeMaxWait() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	maxWait := l.maxWait
+	l.maxWait = 0
+
+	for _, w := range l.waiters {
+		if wait := time.Since(w.start); wait > maxWait {
+			maxWait = wait
+		}
+	}
+
+	return maxWait
+}
@@ -0,0 +1,168 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter_acquire(t *testing.T) {
+	l := newLimiter(1)
+
+	err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(50)*time.Millisecond)
+	defer cancel()
+
+	err = l.acquire(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v but got: %v", context.DeadlineExceeded, err)
+	}
+
+	limit, inFlight, waiting := l.stats()
+	if limit != 1 || inFlight != 1 || waiting != 0 {
+		t.Errorf("expected limit 1, 1 in flight and 0 waiting but got %d, %d and %d", limit, inFlight, waiting)
+	}
+
+	if l.takeMaxWait() < time.Duration(50)*time.Millisecond {
+		t.Error("expected the wait of the timed out task to be recorded")
+	}
+}
+
+func TestLimiter_acquire_queueFull(t *testing.T) {
+	l := newLimiter(1)
+	l.maxWaiting = 1
+
+	err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		_ = l.acquire(ctx)
+	}()
+
+	// Wait for the task to be waiting.
+	for {
+		_, _, waiting := l.stats()
+		if waiting == 1 {
+			break
+		}
+
+		time.Sleep(time.Duration(1) * time.Millisecond)
+	}
+
+	err = l.acquire(context.Background())
+	if !errors.Is(err, errQueueFull) {
+		t.Fatalf("expected %v but got: %v", errQueueFull, err)
+	}
+
+	_, inFlight, waiting := l.stats()
+	if inFlight != 1 || waiting != 1 {
+		t.Errorf("expected 1 in flight and 1 waiting but got %d and %d", inFlight, waiting)
+	}
+}
+
+func TestLimiter_release(t *testing.T) {
+	l := newLimiter(1)
+
+	err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- l.acquire(context.Background())
+	}()
+
+	// Wait for the task to be waiting.
+	for {
+		_, _, waiting := l.stats()
+		if waiting == 1 {
+			break
+		}
+
+		time.Sleep(time.Duration(1) * time.Millisecond)
+	}
+
+	l.release()
+
+	select {
+	case err = <-acquired:
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	case <-time.After(time.Duration(1) * time.Second):
+		t.Fatal("expected the waiting task to acquire the released slot")
+	}
+
+	_, inFlight, _ := l.stats()
+	if inFlight != 1 {
+		t.Errorf("expected 1 in flight but got %d", inFlight)
+	}
+
+	l.release()
+
+	_, inFlight, _ = l.stats()
+	if inFlight != 0 {
+		t.Errorf("expected 0 in flight but got %d", inFlight)
+	}
+}
+
+func TestLimiter_setLimit(t *testing.T) {
+	l := newLimiter(1)
+
+	err := l.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- l.acquire(context.Background())
+	}()
+
+	for {
+		_, _, waiting := l.stats()
+		if waiting == 1 {
+			break
+		}
+
+		time.Sleep(time.Duration(1) * time.Millisecond)
+	}
+
+	l.setLimit(2)
+
+	select {
+	case err = <-acquired:
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	case <-time.After(time.Duration(1) * time.Second):
+		t.Fatal("expected the waiting task to acquire the new slot")
+	}
+
+	// Decreasing the limit does not affect the tasks in flight.
+	l.setLimit(1)
+
+	limit, inFlight, _ := l.stats()
+	if limit != 1 || inFlight != 2 {
+		t.Errorf("expected limit 1 and 2 in flight but got %d and %d", limit, inFlight)
+	}
+
+	l.release()
+	l.release()
+
+	_, inFlight, _ = l.stats()
+	if inFlight != 0 {
+		t.Errorf("expected 0 in flight but got %d", inFlight)
+	}
+}
@@ -0,0 +1,53 @@
+package concurrency
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// concurrencyMiddleware waits for a slot before handling a multipart request,
+// either in the regular lane or, if oversized, in the slow lane. It runs
+// after the webhook middleware, so that asynchronous conversions also hold a
+// slot until they are done.
+func concurrencyMiddleware(mod *Concurrency) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryLowPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					ctx := c.Get("context").(*api.Context)
+
+					l := mod.lane(ctx)
+					if l == nil {
+						return next(c)
+					}
+
+					err := l.acquire(ctx)
+					if errors.Is(err, errQueueFull) {
+						return api.WrapError(
+							fmt.Errorf("wait for a slow lane slot: %w", err),
+							api.NewSentinelHttpError(http.StatusServiceUnavailable, "The server is too busy to handle oversized requests, please try again later").WithCode(api.ErrorCodeConcurrencySlowLaneFull),
+						)
+					}
+
+					if err != nil {
+						return api.WrapError(
+							fmt.Errorf("wait for a concurrency slot: %w", err),
+							api.NewSentinelHttpError(http.StatusServiceUnavailable, "The server is too busy to handle the request, please try again later").WithCode(api.ErrorCodeConcurrencyQueueTimeout),
+						)
+					}
+
+					defer l.release()
+
+					return next(c)
+				}
+			}
+		}(),
+	}
+}
@@ -0,0 +1,155 @@
+package concurrency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestConcurrencyMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		limit        int
+		expectStatus int
+	}{
+		{
+			scenario:     "no slot available",
+			limit:        0,
+			expectStatus: http.StatusServiceUnavailable,
+		},
+		{
+			scenario:     "slot available",
+			limit:        1,
+			expectStatus: http.StatusOK,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			l := newLimiter(tc.limit)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(50)*time.Millisecond)
+			defer cancel()
+
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/foo", nil), httptest.NewRecorder())
+			c.Set("context", &api.Context{Context: ctx})
+
+			var inFlight int
+			next := func(c echo.Context) error {
+				_, inFlight, _ = l.stats()
+				return nil
+			}
+
+			err := concurrencyMiddleware(&Concurrency{limiter: l}).Handler(next)(c)
+
+			status := http.StatusOK
+			if err != nil {
+				status = api.ParseErrorResponse(err).Status
+			}
+
+			if status != tc.expectStatus {
+				t.Fatalf("expected status %d but got %d: %v", tc.expectStatus, status, err)
+			}
+
+			if tc.expectStatus == http.StatusOK && inFlight != 1 {
+				t.Errorf("expected the request to hold a slot but got %d in flight", inFlight)
+			}
+
+			_, inFlight, _ = l.stats()
+			if inFlight != 0 {
+				t.Errorf("expected the slot to be released but got %d in flight", inFlight)
+			}
+		})
+	}
+}
+
+func TestConcurrencyMiddleware_slowLane(t *testing.T) {
+	dirPath := t.TempDir()
+
+	err := os.WriteFile(dirPath+"/foo.pdf", []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario       string
+		mod            *Concurrency
+		expectStatus   int
+		expectCode     string
+		expectSlowLane bool
+	}{
+		{
+			scenario: "no limit",
+			mod:      &Concurrency{},
+		},
+		{
+			scenario:       "oversized request",
+			mod:            &Concurrency{limiter: newLimiter(0), slowLane: newLimiter(1), slowLaneInputSize: 3},
+			expectSlowLane: true,
+		},
+		{
+			scenario: "slow lane full",
+			mod: func() *Concurrency {
+				slowLane := newLimiter(0)
+				slowLane.maxWaiting = 1
+				slowLane.waiters = []waiter{{ch: make(chan struct{}), start: time.Now()}}
+
+				return &Concurrency{slowLane: slowLane, slowLaneInputSize: 3}
+			}(),
+			expectStatus: http.StatusServiceUnavailable,
+			expectCode:   "CONCURRENCY_SLOW_LANE_FULL",
+		},
+		{
+			scenario: "regular request",
+			mod:      &Concurrency{limiter: newLimiter(1), slowLane: newLimiter(0), slowLaneInputSize: 4},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/foo", nil), httptest.NewRecorder())
+
+			ctx := &api.ContextMock{Context: &api.Context{Context: context.Background()}}
+			ctx.SetFiles(map[string]string{"foo.pdf": dirPath + "/foo.pdf"})
+			ctx.SetLogger(zap.NewNop())
+			c.Set("context", ctx.Context)
+
+			var slowLane bool
+			next := func(c echo.Context) error {
+				if tc.mod.slowLane != nil {
+					_, inFlight, _ := tc.mod.slowLane.stats()
+					slowLane = inFlight == 1
+				}
+
+				return nil
+			}
+
+			err := concurrencyMiddleware(tc.mod).Handler(next)(c)
+
+			if tc.expectStatus != 0 {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				response := api.ParseErrorResponse(err)
+				if response.Status != tc.expectStatus || response.Code != tc.expectCode {
+					t.Errorf("expected status %d and code '%s' but got %d and '%s'", tc.expectStatus, tc.expectCode, response.Status, response.Code)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if slowLane != tc.expectSlowLane {
+				t.Errorf("expected slow lane %t but got %t", tc.expectSlowLane, slowLane)
+			}
+		})
+	}
+}
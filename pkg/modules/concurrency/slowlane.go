@@ -0,0 +1,40 @@
+package concurrency
+
+import (
+	"fmt"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// oversized tells if a request goes to the slow lane, according to the size
+// and the page count of its input files. The page count is only estimated if
+// the size does not suffice, as it requires reading the files.
+func (mod *Concurrency) oversized(ctx *api.Context) bool {
+	if mod.slowLaneInputSize > 0 {
+		size, err := ctx.InputSize()
+		if err != nil {
+			// Not critical, the request goes to the regular lane.
+			ctx.Log().Debug(fmt.Sprintf("get input size: %s", err))
+		} else if size >= mod.slowLaneInputSize {
+			return true
+		}
+	}
+
+	if mod.slowLanePageCount > 0 && ctx.EstimatedPageCount() >= mod.slowLanePageCount {
+		return true
+	}
+
+	return false
+}
+
+// lane returns the limiter of a request, or nil if its concurrency is not
+// limited.
+func (mod *Concurrency) lane(ctx *api.Context) *limiter {
+	if mod.slowLane != nil && mod.oversized(ctx) {
+		ctx.Log().Debug("oversized request, use the slow lane")
+
+		return mod.slowLane
+	}
+
+	return mod.limiter
+}
@@ -0,0 +1,72 @@
+package concurrency
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestConcurrency_oversized(t *testing.T) {
+	dirPath := t.TempDir()
+
+	err := os.WriteFile(dirPath+"/foo.pdf", []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario        string
+		mod             *Concurrency
+		files           map[string]string
+		expectOversized bool
+	}{
+		{
+			scenario: "input size below the threshold",
+			mod:      &Concurrency{slowLaneInputSize: 4},
+			files:    map[string]string{"foo.pdf": dirPath + "/foo.pdf"},
+		},
+		{
+			scenario:        "input size above the threshold",
+			mod:             &Concurrency{slowLaneInputSize: 3},
+			files:           map[string]string{"foo.pdf": dirPath + "/foo.pdf"},
+			expectOversized: true,
+		},
+		{
+			scenario: "non-existing file",
+			mod:      &Concurrency{slowLaneInputSize: 3},
+			files:    map[string]string{"bar.pdf": dirPath + "/bar.pdf"},
+		},
+		{
+			scenario: "page count below the threshold",
+			mod:      &Concurrency{slowLanePageCount: 1001},
+			files:    map[string]string{"foo.pdf": dirPath + "/foo.pdf"},
+		},
+		{
+			scenario:        "page count above the threshold",
+			mod:             &Concurrency{slowLaneInputSize: 1024, slowLanePageCount: 1000},
+			files:           map[string]string{"foo.pdf": dirPath + "/foo.pdf"},
+			expectOversized: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: &api.Context{Context: context.Background()}}
+			ctx.SetFiles(tc.files)
+			ctx.SetLogger(zap.NewNop())
+			ctx.SetPdfEngine(&gotenberg.PdfEngineMock{
+				PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+					return 1000, nil
+				},
+			})
+
+			oversized := tc.mod.oversized(ctx.Context)
+			if oversized != tc.expectOversized {
+				t.Errorf("expected oversized %t but got %t", tc.expectOversized, oversized)
+			}
+		})
+	}
+}
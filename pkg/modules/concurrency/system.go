@@ -0,0 +1,85 @@
+package concurrency
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	loadAvgPath = "/proc/loadavg"
+	memInfoPath = "/proc/meminfo"
+)
+
+// readLoad returns the load average over the last minute.
+func readLoad(path string) (float64, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read '%s': %w", path, err)
+	}
+
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("empty '%s'", path)
+	}
+
+	load, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse load average from '%s': %w", path, err)
+	}
+
+	return load, nil
+}
+
+// readMemoryUsage returns the ratio of the memory in use, i.e., not
+// available for starting new applications without swapping.
+func readMemoryUsage(path string) (float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open '%s': %w", path, err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var total, available float64
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		var dest *float64
+
+		switch fields[0] {
+		case "MemTotal:":
+			dest = &total
+		case "MemAvailable:":
+			dest = &available
+		default:
+			continue
+		}
+
+		*dest, err = strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse '%s' from '%s': %w", fields[0], path, err)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return 0, fmt.Errorf("scan '%s': %w", path, err)
+	}
+
+	if total == 0 {
+		return 0, errors.New("no total memory")
+	}
+
+	return 1 - available/total, nil
+}
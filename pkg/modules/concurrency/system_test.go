@@ -0,0 +1,113 @@
+package concurrency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadLoad(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		content     string
+		expectLoad  float64
+		expectError bool
+	}{
+		{
+			scenario:    "empty file",
+			content:     "",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid load",
+			content:     "foo 0.16 0.06 2/72 14410",
+			expectError: true,
+		},
+		{
+			scenario:   "valid load",
+			content:    "0.41 0.16 0.06 2/72 14410",
+			expectLoad: 0.41,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "loadavg")
+
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			load, err := readLoad(path)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if load != tc.expectLoad {
+				t.Errorf("expected load %f but got %f", tc.expectLoad, load)
+			}
+		})
+	}
+
+	_, err := readLoad("/foo")
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
+
+func TestReadMemoryUsage(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		content     string
+		expectUsage float64
+		expectError bool
+	}{
+		{
+			scenario:    "no total memory",
+			content:     "MemAvailable:    4000 kB\n",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid total memory",
+			content:     "MemTotal:        foo kB\n",
+			expectError: true,
+		},
+		{
+			scenario:    "valid memory usage",
+			content:     "MemTotal:        8000 kB\nMemFree:         1000 kB\nMemAvailable:    2000 kB\n",
+			expectUsage: 0.75,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "meminfo")
+
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			usage, err := readMemoryUsage(path)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if usage != tc.expectUsage {
+				t.Errorf("expected usage %f but got %f", tc.expectUsage, usage)
+			}
+		})
+	}
+
+	_, err := readMemoryUsage("/foo")
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
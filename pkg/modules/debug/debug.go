@@ -0,0 +1,287 @@
+package debug
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/alexliesenfeld/health"
+	"github.com/labstack/echo/v4"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Debug))
+}
+
+// sensitiveFlagRegexp matches the flags which values must not leave
+// Gotenberg, e.g., passwords or DSNs.
+var sensitiveFlagRegexp = regexp.MustCompile(`(?i)(password|secret|token|dsn|credential|auth|api-key|signing-key)`)
+
+// redactedValue replaces the value of a sensitive flag.
+const redactedValue = "[REDACTED]"
+
+// Debug is a module which adds an admin route returning a diagnostics bundle.
+// It also keeps the recent errors for the bundle.
+type Debug struct {
+	enableRoute         bool
+	maxErrors           int
+	timeout             time.Duration
+	disableRouteLogging bool
+
+	flags         map[string]string
+	healthChecks  []health.CheckerOption
+	debuggables   map[string]gotenberg.Debuggable
+	errors        []api.ErrorReport
+	errorsMu      sync.Mutex
+	debuggableIds []string
+}
+
+// Descriptor returns a [Debug]'s module descriptor.
+func (mod *Debug) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "debug",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("debug", flag.ExitOnError)
+			fs.Bool("debug-enable-route", false, "Enable the admin route which returns a diagnostics bundle - it requires the API admin token")
+			fs.Int("debug-max-errors", 50, "Set the number of recent errors to keep for the diagnostics bundle")
+			fs.Duration("debug-timeout", time.Duration(10)*time.Second, "Set the time limit for gathering the diagnostics of each module")
+			fs.Bool("debug-disable-route-logging", false, "Disable the route logging")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Debug) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Debug) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enableRoute = flags.MustBool("debug-enable-route")
+	mod.maxErrors = flags.MustInt("debug-max-errors")
+	mod.timeout = flags.MustDuration("debug-timeout")
+	mod.disableRouteLogging = flags.MustBool("debug-disable-route-logging")
+
+	if !mod.enableRoute {
+		// Exit early.
+		return nil
+	}
+
+	mod.flags = make(map[string]string)
+	flags.VisitAll(func(f *flag.Flag) {
+		if sensitiveFlagRegexp.MatchString(f.Name) && f.Value.String() != "" {
+			mod.flags[f.Name] = redactedValue
+
+			return
+		}
+
+		mod.flags[f.Name] = f.Value.String()
+	})
+
+	mods, err := ctx.Modules(new(api.HealthChecker))
+	if err != nil {
+		return fmt.Errorf("get health checkers: %w", err)
+	}
+
+	for _, healthChecker := range mods {
+		checks, err := healthChecker.(api.HealthChecker).Checks()
+		if err != nil {
+			return fmt.Errorf("get health checks: %w", err)
+		}
+
+		mod.healthChecks = append(mod.healthChecks, checks...)
+	}
+
+	mods, err = ctx.Modules(new(gotenberg.Debuggable))
+	if err != nil {
+		return fmt.Errorf("get debuggable modules: %w", err)
+	}
+
+	mod.debuggables = make(map[string]gotenberg.Debuggable)
+	for _, debuggable := range mods {
+		id := debuggable.(gotenberg.Module).Descriptor().ID
+		mod.debuggables[id] = debuggable.(gotenberg.Debuggable)
+		mod.debuggableIds = append(mod.debuggableIds, id)
+	}
+
+	sort.Strings(mod.debuggableIds)
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Debug) Validate() error {
+	var err error
+
+	if mod.maxErrors < 0 {
+		err = multierr.Append(err,
+			errors.New("maximum number of errors must be at least 0"),
+		)
+	}
+
+	if mod.timeout <= 0 {
+		err = multierr.Append(err,
+			errors.New("timeout must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// ReportError keeps the recent errors for the diagnostics bundle. The form
+// fields are not kept.
+func (mod *Debug) ReportError(report api.ErrorReport) {
+	if !mod.enableRoute || mod.maxErrors == 0 {
+		return
+	}
+
+	report.Options = nil
+
+	mod.errorsMu.Lock()
+	defer mod.errorsMu.Unlock()
+
+	mod.errors = append(mod.errors, report)
+	if len(mod.errors) > mod.maxErrors {
+		mod.errors = mod.errors[len(mod.errors)-mod.maxErrors:]
+	}
+}
+
+// Routes returns the HTTP route.
+func (mod *Debug) Routes() ([]api.Route, error) {
+	if !mod.enableRoute {
+		return nil, nil
+	}
+
+	return []api.Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/admin/debug/bundle",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+				c.Response().Header().Set(
+					echo.HeaderContentDisposition,
+					fmt.Sprintf("attachment; filename=\"gotenberg-debug-%s.zip\"", time.Now().UTC().Format("20060102T150405Z")),
+				)
+				c.Response().WriteHeader(http.StatusOK)
+
+				err := mod.writeBundle(c.Request().Context(), c.Response())
+				if err != nil {
+					// Headers already sent, the client gets a truncated
+					// archive.
+					return fmt.Errorf("write diagnostics bundle: %w", err)
+				}
+
+				return nil
+			},
+		},
+	}, nil
+}
+
+// writeBundle writes the diagnostics bundle as a zip archive.
+func (mod *Debug) writeBundle(ctx context.Context, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	writeJson := func(name string, data any) error {
+		f, err := archive.Create(name)
+		if err != nil {
+			return fmt.Errorf("create '%s' in archive: %w", name, err)
+		}
+
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(data)
+		if err != nil {
+			return fmt.Errorf("write '%s' in archive: %w", name, err)
+		}
+
+		return nil
+	}
+
+	err := writeJson("version.json", map[string]any{
+		"gotenberg": gotenberg.Version,
+		"go":        runtime.Version(),
+		"os":        runtime.GOOS,
+		"arch":      runtime.GOARCH,
+		"cpus":      runtime.NumCPU(),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writeJson("flags.json", mod.flags)
+	if err != nil {
+		return err
+	}
+
+	err = writeJson("health.json", mod.health(ctx))
+	if err != nil {
+		return err
+	}
+
+	mod.errorsMu.Lock()
+	recentErrors := make([]api.ErrorReport, len(mod.errors))
+	copy(recentErrors, mod.errors)
+	mod.errorsMu.Unlock()
+
+	err = writeJson("errors.json", recentErrors)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range mod.debuggableIds {
+		debugCtx, cancel := context.WithTimeout(ctx, mod.timeout)
+		data, debugErr := mod.debuggables[id].Debug(debugCtx)
+		cancel()
+
+		if debugErr != nil {
+			// A failing module must not prevent the bundle, its error is
+			// a diagnostic too.
+			data = map[string]any{"error": debugErr.Error()}
+		}
+
+		err = writeJson(fmt.Sprintf("modules/%s.json", id), data)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return nil
+}
+
+// health runs the health checks of the modules.
+func (mod *Debug) health(ctx context.Context) health.CheckerResult {
+	checks := append([]health.CheckerOption{health.WithTimeout(mod.timeout)}, mod.healthChecks...)
+	checker := health.NewChecker(checks...)
+
+	return checker.Check(ctx)
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Debug)(nil)
+	_ gotenberg.Provisioner = (*Debug)(nil)
+	_ gotenberg.Validator   = (*Debug)(nil)
+	_ api.ErrorReporter     = (*Debug)(nil)
+	_ api.Router            = (*Debug)(nil)
+)
@@ -0,0 +1,168 @@
+package debug
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+type debuggableMock struct {
+	debugMock func(ctx context.Context) (map[string]any, error)
+}
+
+func (mod *debuggableMock) Debug(ctx context.Context) (map[string]any, error) {
+	return mod.debugMock(ctx)
+}
+
+func TestDebug_Descriptor(t *testing.T) {
+	descriptor := new(Debug).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Debug))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestDebug_Provision(t *testing.T) {
+	mod := new(Debug)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Debug).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enableRoute {
+		t.Error("expected route to be disabled by default")
+	}
+}
+
+func TestDebug_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		maxErrors   int
+		timeout     time.Duration
+		expectError bool
+	}{
+		{
+			scenario:    "invalid maximum number of errors",
+			maxErrors:   -1,
+			timeout:     time.Duration(10) * time.Second,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid timeout",
+			maxErrors:   50,
+			timeout:     0,
+			expectError: true,
+		},
+		{
+			scenario:  "validate success",
+			maxErrors: 50,
+			timeout:   time.Duration(10) * time.Second,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Debug{
+				maxErrors: tc.maxErrors,
+				timeout:   tc.timeout,
+			}
+
+			err := mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestDebug_ReportError(t *testing.T) {
+	mod := &Debug{
+		enableRoute: true,
+		maxErrors:   2,
+	}
+
+	for _, route := range []string{"/foo", "/bar", "/baz"} {
+		mod.ReportError(api.ErrorReport{
+			Route:   route,
+			Options: map[string][]string{"foo": {"bar"}},
+		})
+	}
+
+	if len(mod.errors) != 2 {
+		t.Fatalf("expected 2 errors but got %d", len(mod.errors))
+	}
+
+	if mod.errors[0].Route != "/bar" || mod.errors[1].Route != "/baz" {
+		t.Errorf("expected the most recent errors but got: %+v", mod.errors)
+	}
+
+	if mod.errors[0].Options != nil {
+		t.Errorf("expected no options but got: %v", mod.errors[0].Options)
+	}
+}
+
+func TestDebug_writeBundle(t *testing.T) {
+	mod := &Debug{
+		enableRoute: true,
+		timeout:     time.Duration(10) * time.Second,
+		flags:       map[string]string{"foo": "bar"},
+		debuggables: map[string]gotenberg.Debuggable{
+			"foo": &debuggableMock{
+				debugMock: func(ctx context.Context) (map[string]any, error) {
+					return map[string]any{"version": "1.0.0"}, nil
+				},
+			},
+			"bar": &debuggableMock{
+				debugMock: func(ctx context.Context) (map[string]any, error) {
+					return nil, errors.New("foo")
+				},
+			},
+		},
+		debuggableIds: []string{"bar", "foo"},
+	}
+
+	var buf bytes.Buffer
+
+	err := mod.writeBundle(context.Background(), &buf)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var names []string
+	for _, f := range reader.File {
+		names = append(names, f.Name)
+	}
+
+	sort.Strings(names)
+
+	expect := []string{"errors.json", "flags.json", "health.json", "modules/bar.json", "modules/foo.json", "version.json"}
+	if !reflect.DeepEqual(names, expect) {
+		t.Errorf("expected %v but got %v", expect, names)
+	}
+}
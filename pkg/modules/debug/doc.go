@@ -0,0 +1,5 @@
+// Package debug provides a module which adds an admin route assembling a
+// diagnostics bundle, i.e., the version, the flags, the health of the
+// modules, the recent errors, the versions of the external binaries, and the
+// fonts, as a zip archive for support tickets.
+package debug
@@ -0,0 +1,393 @@
+package distributed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Distributed))
+}
+
+// replicaRegexp matches the valid replica IDs, as they name directories.
+var replicaRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// tagRegexp matches the valid tags.
+var tagRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Distributed is a module which shares the asynchronous requests between
+// replicas with a queue on a shared volume.
+type Distributed struct {
+	enable         bool
+	dir            string
+	replica        string
+	url            string
+	workers        int
+	pollInterval   time.Duration
+	claimTimeout   time.Duration
+	affinityHeader string
+	affinityTtl    time.Duration
+	tags           []string
+	routes         map[string]string
+
+	disableRouteLogging bool
+
+	queue      queue
+	client     *http.Client
+	jobTracker gotenberg.JobTracker
+	logger     *zap.Logger
+	inFlight   atomic.Int64
+	stop       chan struct{}
+	wg         sync.WaitGroup
+}
+
+// Descriptor returns a [Distributed]'s module descriptor.
+func (mod *Distributed) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "distributed",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("distributed", flag.ExitOnError)
+			fs.Bool("distributed-enable", false, "Enable the sharing of the asynchronous requests between replicas")
+			fs.String("distributed-dir", "", "Set the directory of the shared queue, on a volume all the replicas share")
+			fs.String("distributed-replica-id", "", "Set the unique ID of this replica - default to the hostname")
+			fs.String("distributed-url", "http://localhost:3000", "Set the base URL of this replica's API, without the root path, for handling the pulled requests")
+			fs.Int("distributed-workers", 1, "Set the number of requests this replica handles at once")
+			fs.Duration("distributed-poll-interval", time.Duration(1)*time.Second, "Set the interval at which an idle worker looks for requests in the queue")
+			fs.Duration("distributed-claim-timeout", time.Duration(1)*time.Minute, "Set the time without a heartbeat after which the request of a crashed replica goes back to the queue")
+			fs.String("distributed-affinity-header", "Gotenberg-Affinity-Key", "Set the header of the key which makes the requests of a workflow stick to the same replica")
+			fs.Duration("distributed-affinity-ttl", time.Duration(10)*time.Minute, "Set the time after its last request after which an affinity key no longer sticks to its replica")
+			fs.StringSlice("distributed-tags", make([]string, 0), "Set the tags of this replica, which pulls the requests of the routes requiring one of them - e.g., libreoffice-heavy")
+			fs.StringSlice("distributed-routes", make([]string, 0), "Set the tag a replica must have for pulling the requests of the routes starting with a given path - e.g., /forms/libreoffice=libreoffice-heavy")
+			fs.Bool("distributed-disable-route-logging", false, "Disable the route logging")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Distributed) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Distributed) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("distributed-enable")
+	mod.dir = flags.MustString("distributed-dir")
+	mod.replica = flags.MustString("distributed-replica-id")
+	mod.url = flags.MustString("distributed-url")
+	mod.workers = flags.MustInt("distributed-workers")
+	mod.pollInterval = flags.MustDuration("distributed-poll-interval")
+	mod.claimTimeout = flags.MustDuration("distributed-claim-timeout")
+	mod.affinityHeader = flags.MustString("distributed-affinity-header")
+	mod.affinityTtl = flags.MustDuration("distributed-affinity-ttl")
+	mod.tags = flags.MustStringSlice("distributed-tags")
+	mod.disableRouteLogging = flags.MustBool("distributed-disable-route-logging")
+
+	routes, err := parseRoutes(flags.MustStringSlice("distributed-routes"))
+	if err != nil {
+		return fmt.Errorf("parse routes: %w", err)
+	}
+
+	mod.routes = routes
+
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	if mod.replica == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("get hostname: %w", err)
+		}
+
+		mod.replica = hostname
+	}
+
+	mod.queue = queue{dir: mod.dir}
+
+	// The API bounds the time of the requests.
+	mod.client = &http.Client{}
+
+	jobTrackers, err := ctx.Modules(new(gotenberg.JobTracker))
+	if err != nil {
+		return fmt.Errorf("get job trackers: %w", err)
+	}
+
+	if len(jobTrackers) > 1 {
+		return fmt.Errorf("expected at most one job tracker, but got %d", len(jobTrackers))
+	}
+
+	if len(jobTrackers) == 1 {
+		mod.jobTracker = jobTrackers[0].(gotenberg.JobTracker)
+	}
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Distributed) Validate() error {
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.dir == "" {
+		err = multierr.Append(err,
+			errors.New("directory must not be empty"),
+		)
+	}
+
+	if !replicaRegexp.MatchString(mod.replica) {
+		err = multierr.Append(err,
+			fmt.Errorf("replica ID '%s' must only contain letters, digits, dots, underscores and hyphens", mod.replica),
+		)
+	}
+
+	u, parseErr := url.Parse(mod.url)
+	if parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		err = multierr.Append(err,
+			fmt.Errorf("URL '%s' must be an absolute HTTP(S) URL", mod.url),
+		)
+	}
+
+	if mod.workers < 1 {
+		err = multierr.Append(err,
+			errors.New("workers must be at least 1"),
+		)
+	}
+
+	if mod.pollInterval <= 0 {
+		err = multierr.Append(err,
+			errors.New("poll interval must be more than 0"),
+		)
+	}
+
+	if mod.claimTimeout <= 0 {
+		err = multierr.Append(err,
+			errors.New("claim timeout must be more than 0"),
+		)
+	}
+
+	if mod.affinityHeader == "" {
+		err = multierr.Append(err,
+			errors.New("affinity header must not be empty"),
+		)
+	}
+
+	if mod.affinityTtl <= 0 {
+		err = multierr.Append(err,
+			errors.New("affinity TTL must be more than 0"),
+		)
+	}
+
+	for _, tag := range mod.tags {
+		if !tagRegexp.MatchString(tag) {
+			err = multierr.Append(err,
+				fmt.Errorf("tag '%s' must only contain letters, digits, dots, underscores and hyphens", tag),
+			)
+		}
+	}
+
+	for path, tag := range mod.routes {
+		if !strings.HasPrefix(path, "/forms/") {
+			err = multierr.Append(err,
+				fmt.Errorf("route '%s' must start with '/forms/'", path),
+			)
+		}
+
+		if !tagRegexp.MatchString(tag) {
+			err = multierr.Append(err,
+				fmt.Errorf("tag '%s' of route '%s' must only contain letters, digits, dots, underscores and hyphens", tag, path),
+			)
+		}
+	}
+
+	return err
+}
+
+// Start creates the directories of the queue, and starts the workers and
+// the recovery of the requests of the crashed replicas.
+func (mod *Distributed) Start() error {
+	if !mod.enable {
+		return nil
+	}
+
+	err := mod.queue.init(mod.replica)
+	if err != nil {
+		return fmt.Errorf("init queue: %w", err)
+	}
+
+	mod.stop = make(chan struct{})
+
+	for i := 0; i < mod.workers; i++ {
+		mod.wg.Add(1)
+
+		go func() {
+			defer mod.wg.Done()
+			mod.work()
+		}()
+	}
+
+	mod.wg.Add(1)
+
+	go func() {
+		defer mod.wg.Done()
+
+		ticker := time.NewTicker(mod.claimTimeout / 2)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-mod.stop:
+				return
+			case <-ticker.C:
+				now := time.Now()
+
+				count, err := mod.queue.reclaim(mod.claimTimeout, now)
+				if err != nil {
+					mod.logger.Error(fmt.Sprintf("recover requests: %s", err))
+				}
+
+				if count > 0 {
+					mod.logger.Warn(fmt.Sprintf("%d request(s) of crashed replicas back in the queue", count))
+				}
+
+				err = mod.queue.expire(mod.affinityTtl, now)
+				if err != nil {
+					mod.logger.Error(fmt.Sprintf("expire affinity keys: %s", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Distributed) StartupMessage() string {
+	if !mod.enable {
+		return "distributed mode disabled"
+	}
+
+	if len(mod.tags) == 0 {
+		return fmt.Sprintf("replica '%s' pulling up to %d request(s) at once from '%s'", mod.replica, mod.workers, mod.dir)
+	}
+
+	return fmt.Sprintf("replica '%s' with tags %s pulling up to %d request(s) at once from '%s'", mod.replica, strings.Join(mod.tags, ", "), mod.workers, mod.dir)
+}
+
+// Stop stops the workers once their current requests are over.
+func (mod *Distributed) Stop(ctx context.Context) error {
+	if mod.stop == nil {
+		return nil
+	}
+
+	close(mod.stop)
+
+	done := make(chan struct{})
+
+	go func() {
+		mod.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// The claims of the unfinished requests expire, and the other
+		// replicas will pull them again.
+		return fmt.Errorf("wait for workers: %w", ctx.Err())
+	}
+}
+
+// routeTag returns the tag a replica must have for pulling the requests of
+// a route, i.e., the one of the longest matching path from the routes, if
+// any. The path may start with the root path of the API.
+func (mod *Distributed) routeTag(path string) string {
+	i := strings.Index(path, "/forms/")
+	if i < 0 {
+		return ""
+	}
+
+	path = path[i:]
+	tag := ""
+	longest := -1
+
+	for prefix, t := range mod.routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			tag = t
+			longest = len(prefix)
+		}
+	}
+
+	return tag
+}
+
+// parseRoutes parses the "path=tag" entries of the routes.
+func parseRoutes(entries []string) (map[string]string, error) {
+	routes := make(map[string]string, len(entries))
+
+	for _, entry := range entries {
+		path, tag, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(path) == "" || strings.TrimSpace(tag) == "" {
+			return nil, fmt.Errorf("invalid route '%s': expected 'path=tag'", entry)
+		}
+
+		routes[strings.TrimSpace(path)] = strings.TrimSpace(tag)
+	}
+
+	return routes, nil
+}
+
+// Middlewares returns the middleware which puts the asynchronous requests in
+// the queue.
+func (mod *Distributed) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		enqueueMiddleware(mod),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module          = (*Distributed)(nil)
+	_ gotenberg.Provisioner     = (*Distributed)(nil)
+	_ gotenberg.Validator       = (*Distributed)(nil)
+	_ gotenberg.App             = (*Distributed)(nil)
+	_ api.MiddlewareProvider    = (*Distributed)(nil)
+	_ api.Router                = (*Distributed)(nil)
+	_ gotenberg.MetricsProvider = (*Distributed)(nil)
+)
@@ -0,0 +1,269 @@
+package distributed
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestDistributed_Descriptor(t *testing.T) {
+	descriptor := new(Distributed).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Distributed))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestDistributed_Provision(t *testing.T) {
+	mod := new(Distributed)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Distributed).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enable {
+		t.Error("expected distributed mode to be disabled by default")
+	}
+}
+
+func TestDistributed_Validate(t *testing.T) {
+	valid := func() *Distributed {
+		return &Distributed{
+			enable:         true,
+			dir:            "/shared/gotenberg",
+			replica:        "gotenberg-0",
+			url:            "http://localhost:3000",
+			workers:        1,
+			pollInterval:   time.Duration(1) * time.Second,
+			claimTimeout:   time.Duration(1) * time.Minute,
+			affinityHeader: "Gotenberg-Affinity-Key",
+			affinityTtl:    time.Duration(10) * time.Minute,
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		mod         func() *Distributed
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			mod: func() *Distributed {
+				return new(Distributed)
+			},
+		},
+		{
+			scenario: "empty directory",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.dir = ""
+				return mod
+			},
+			expectError: true,
+		},
+		{
+			scenario: "invalid replica ID",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.replica = "../foo"
+				return mod
+			},
+			expectError: true,
+		},
+		{
+			scenario: "relative URL",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.url = "localhost:3000"
+				return mod
+			},
+			expectError: true,
+		},
+		{
+			scenario: "no workers",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.workers = 0
+				return mod
+			},
+			expectError: true,
+		},
+		{
+			scenario: "invalid claim timeout",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.claimTimeout = 0
+				return mod
+			},
+			expectError: true,
+		},
+		{
+			scenario: "empty affinity header",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.affinityHeader = ""
+				return mod
+			},
+			expectError: true,
+		},
+		{
+			scenario: "invalid tag",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.tags = []string{"libreoffice heavy"}
+				return mod
+			},
+			expectError: true,
+		},
+		{
+			scenario: "route outside of the multipart routes",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.routes = map[string]string{"/health": "libreoffice-heavy"}
+				return mod
+			},
+			expectError: true,
+		},
+		{
+			scenario: "validate success",
+			mod:      valid,
+		},
+		{
+			scenario: "validate success with tags and routes",
+			mod: func() *Distributed {
+				mod := valid()
+				mod.tags = []string{"libreoffice-heavy"}
+				mod.routes = map[string]string{"/forms/libreoffice": "libreoffice-heavy"}
+				return mod
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod().Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		entries     []string
+		expect      map[string]string
+		expectError bool
+	}{
+		{
+			scenario: "no routes",
+			expect:   map[string]string{},
+		},
+		{
+			scenario:    "missing tag",
+			entries:     []string{"/forms/libreoffice="},
+			expectError: true,
+		},
+		{
+			scenario: "routes",
+			entries:  []string{"/forms/libreoffice=libreoffice-heavy", " /forms/chromium = chromium-only "},
+			expect: map[string]string{
+				"/forms/libreoffice": "libreoffice-heavy",
+				"/forms/chromium":    "chromium-only",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := parseRoutes(tc.entries)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %v but got %v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestDistributed_routeTag(t *testing.T) {
+	mod := &Distributed{
+		routes: map[string]string{
+			"/forms/libreoffice":              "libreoffice-heavy",
+			"/forms/chromium":                 "chromium",
+			"/forms/chromium/screenshot/html": "chromium-screenshots",
+		},
+	}
+
+	for _, tc := range []struct {
+		path   string
+		expect string
+	}{
+		{path: "/forms/libreoffice/convert", expect: "libreoffice-heavy"},
+		{path: "/foo/forms/chromium/convert/url", expect: "chromium"},
+		{path: "/forms/chromium/screenshot/html", expect: "chromium-screenshots"},
+		{path: "/forms/pdfengines/merge", expect: ""},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			actual := mod.routeTag(tc.path)
+			if actual != tc.expect {
+				t.Errorf("expected tag '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestDistributed_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario: "disabled",
+		},
+		{
+			scenario:          "enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Distributed{enable: tc.enable}
+
+			middlewares, err := mod.Middlewares()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(middlewares) != tc.expectMiddlewares {
+				t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+			}
+		})
+	}
+}
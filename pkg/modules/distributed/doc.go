@@ -0,0 +1,53 @@
+// Package distributed provides a module which lets many replicas share the
+// asynchronous requests: a replica receiving such a request puts it in a
+// queue on a shared volume, and the workers of every replica pull the
+// requests from this queue as they have capacity for them.
+//
+// The requests sharing an affinity key stick to the replica which pulled the
+// first one, for the workflows which rely on the state of a replica.
+//
+// The replicas may have tags, e.g., libreoffice-heavy, and the requests of
+// some routes may require a replica with a given tag, so that each workload
+// type has its own pool of replicas. A request requiring a tag no replica
+// has stays in the queue.
+//
+// The jobs module may keep its jobs on the same volume, so that any replica
+// lists the jobs of all of them.
+//
+// # Scaling
+//
+// The autoscalers may react to the backlog of the queue instead of the CPU.
+// The route GET /distributed/scaling, with an optional "tag" query parameter,
+// returns the number of pending and in-flight requests for the KEDA metrics
+// API scaler:
+//
+//	apiVersion: keda.sh/v1alpha1
+//	kind: ScaledObject
+//	metadata:
+//	  name: gotenberg-libreoffice-heavy
+//	spec:
+//	  scaleTargetRef:
+//	    name: gotenberg-libreoffice-heavy
+//	  triggers:
+//	    - type: metrics-api
+//	      metadata:
+//	        url: "http://gotenberg:3000/distributed/scaling?tag=libreoffice-heavy"
+//	        valueLocation: "backlog"
+//	        targetValue: "2"
+//
+// The Prometheus module exposes the same numbers, by tag, as the
+// distributed_queue_depth and distributed_in_flight metrics. With the
+// Prometheus adapter, they become external metrics for the Horizontal Pod
+// Autoscaler:
+//
+//	externalRules:
+//	  - seriesQuery: 'gotenberg_distributed_queue_depth'
+//	    metricsQuery: 'max(<<.Series>>{<<.LabelMatchers>>}) by (tag)'
+//	    resources:
+//	      namespaced: false
+//	    name:
+//	      as: "gotenberg_queue_depth"
+//
+// As every replica reads the same queue, the metrics use max rather than sum
+// across the replicas.
+package distributed
@@ -0,0 +1,86 @@
+package distributed
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// enqueueMiddleware puts the asynchronous requests in the shared queue
+// instead of handling them, as any replica may pull them. It runs before the
+// router, so that it does not parse the multipart bodies: the pre-flight
+// requests with a webhook URL go to the queue too.
+func enqueueMiddleware(mod *Distributed) api.Middleware {
+	return api.Middleware{
+		Stack: api.PreRouterStack,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					if !shouldEnqueue(c.Request()) {
+						return next(c)
+					}
+
+					trace := c.Get("trace").(string)
+					req := request{
+						Trace:      trace,
+						Method:     c.Request().Method,
+						Uri:        c.Request().URL.RequestURI(),
+						Path:       c.Request().URL.Path,
+						Header:     c.Request().Header.Clone(),
+						Affinity:   c.Request().Header.Get(mod.affinityHeader),
+						Tag:        mod.routeTag(c.Request().URL.Path),
+						Replica:    mod.replica,
+						EnqueuedAt: time.Now().UTC(),
+					}
+
+					// The replica which pulls the request reuses the same
+					// trace.
+					req.Header.Set(c.Get("traceHeader").(string), trace)
+
+					err := mod.queue.push(req, c.Request().Body)
+					if err != nil {
+						return api.WrapError(
+							fmt.Errorf("push request: %w", err),
+							api.NewSentinelHttpError(http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable)).WithCode(api.ErrorCodeDistributedQueueUnavailable),
+						)
+					}
+
+					if mod.jobTracker != nil {
+						mod.jobTracker.TrackJob(gotenberg.Job{
+							ID:        trace,
+							Path:      req.Path,
+							State:     gotenberg.JobStateQueued,
+							CreatedAt: req.EnqueuedAt,
+							UpdatedAt: req.EnqueuedAt,
+						})
+					}
+
+					c.Get("logger").(*zap.Logger).Debug(fmt.Sprintf("request queued by replica '%s'", mod.replica))
+
+					return c.NoContent(http.StatusNoContent)
+				}
+			}
+		}(),
+	}
+}
+
+// shouldEnqueue tells if a request goes to the shared queue, i.e., it is an
+// asynchronous multipart request which no replica pulled yet.
+func shouldEnqueue(r *http.Request) bool {
+	if r.Method != http.MethodPost || r.Header.Get(gotenberg.ReplicaHeader) != "" {
+		return false
+	}
+
+	if r.Header.Get("Gotenberg-Webhook-Url") == "" {
+		return false
+	}
+
+	return strings.Contains(r.URL.Path, "/forms/")
+}
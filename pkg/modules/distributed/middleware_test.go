@@ -0,0 +1,153 @@
+package distributed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestShouldEnqueue(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		method   string
+		target   string
+		headers  map[string]string
+		expect   bool
+	}{
+		{
+			scenario: "synchronous request",
+			method:   http.MethodPost,
+			target:   "/forms/chromium/convert/url",
+		},
+		{
+			scenario: "not a multipart route",
+			method:   http.MethodGet,
+			target:   "/health",
+			headers:  map[string]string{"Gotenberg-Webhook-Url": "http://localhost/"},
+		},
+		{
+			scenario: "pulled request",
+			method:   http.MethodPost,
+			target:   "/forms/chromium/convert/url",
+			headers:  map[string]string{"Gotenberg-Webhook-Url": "http://localhost/", gotenberg.ReplicaHeader: "a"},
+		},
+		{
+			scenario: "asynchronous request",
+			method:   http.MethodPost,
+			target:   "/foo/forms/chromium/convert/url",
+			headers:  map[string]string{"Gotenberg-Webhook-Url": "http://localhost/"},
+			expect:   true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, nil)
+			for key, value := range tc.headers {
+				req.Header.Set(key, value)
+			}
+
+			actual := shouldEnqueue(req)
+			if actual != tc.expect {
+				t.Errorf("expected %t but got %t", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestEnqueueMiddleware(t *testing.T) {
+	var tracked []gotenberg.Job
+
+	mod := &Distributed{
+		replica:        "a",
+		affinityHeader: "Gotenberg-Affinity-Key",
+		routes:         map[string]string{"/forms/chromium": "chromium-only"},
+		queue:          newTestQueue(t, "a"),
+		jobTracker: &gotenberg.JobTrackerMock{
+			TrackJobMock: func(job gotenberg.Job) {
+				tracked = append(tracked, job)
+			},
+		},
+	}
+
+	var nextCalled bool
+
+	handler := enqueueMiddleware(mod).Handler(func(c echo.Context) error {
+		nextCalled = true
+		return nil
+	})
+
+	call := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.Set("trace", "foo")
+		c.Set("traceHeader", "Gotenberg-Trace")
+		c.Set("logger", zap.NewNop())
+
+		err := handler(c)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return rec
+	}
+
+	call(httptest.NewRequest(http.MethodPost, "/forms/chromium/convert/url", strings.NewReader("body")))
+
+	if !nextCalled {
+		t.Error("expected the synchronous request to go through")
+	}
+
+	nextCalled = false
+
+	req := httptest.NewRequest(http.MethodPost, "/forms/chromium/convert/url?foo=bar", strings.NewReader("body"))
+	req.Header.Set("Gotenberg-Webhook-Url", "http://localhost/")
+	req.Header.Set("Gotenberg-Affinity-Key", "workflow")
+
+	rec := call(req)
+
+	if nextCalled {
+		t.Error("expected the asynchronous request to go to the queue")
+	}
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected status %d but got %d", http.StatusNoContent, rec.Code)
+	}
+
+	names, err := mod.queue.pending()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(names) != 1 {
+		t.Fatalf("expected 1 pending request but got %d", len(names))
+	}
+
+	queued, err := readRequest(filepath.Join(mod.queue.pendingDir(), names[0]))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if queued.Uri != "/forms/chromium/convert/url?foo=bar" || queued.Affinity != "workflow" || queued.Tag != "chromium-only" || queued.Header.Get("Gotenberg-Trace") != "foo" {
+		t.Errorf("unexpected queued request %+v", queued)
+	}
+
+	b, err := os.ReadFile(filepath.Join(mod.queue.pendingDir(), names[0], bodyFilename))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if string(b) != "body" {
+		t.Errorf("expected body 'body' but got '%s'", string(b))
+	}
+
+	if len(tracked) != 1 || tracked[0].ID != "foo" || tracked[0].State != gotenberg.JobStateQueued {
+		t.Errorf("expected queued job 'foo' but got %+v", tracked)
+	}
+}
@@ -0,0 +1,491 @@
+package distributed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/multierr"
+)
+
+const (
+	// requestFilename is the file of the metadata of a request within its
+	// directory.
+	requestFilename = "request.json"
+
+	// bodyFilename is the file of the body of a request within its
+	// directory.
+	bodyFilename = "body"
+)
+
+// errQueueEmpty happens if there is no request a replica may pull.
+var errQueueEmpty = errors.New("queue empty")
+
+// request is an asynchronous request in the queue.
+type request struct {
+	Trace      string      `json:"trace"`
+	Method     string      `json:"method"`
+	Uri        string      `json:"uri"`
+	Path       string      `json:"path"`
+	Header     http.Header `json:"header"`
+	Affinity   string      `json:"affinity,omitempty"`
+	Tag        string      `json:"tag,omitempty"`
+	Replica    string      `json:"replica"`
+	EnqueuedAt time.Time   `json:"enqueuedAt"`
+}
+
+// affinityKey returns the key which binds the request to a replica. A
+// workflow sticks to one replica per tag, as a replica may not have the tags
+// of all the requests of the workflow.
+func (r request) affinityKey() string {
+	if r.Affinity == "" || r.Tag == "" {
+		return r.Affinity
+	}
+
+	return r.Tag + "/" + r.Affinity
+}
+
+// item is a request pulled by a replica.
+type item struct {
+	name    string
+	path    string
+	request request
+}
+
+// queue is a queue of requests in a directory that the replicas share. Each
+// request has its own directory, which moves atomically between the states:
+//
+//	queue/<name>                pending
+//	claimed/<replica>/<name>    pulled by a replica
+//
+// The affinity keys bind to replicas with files in the affinity directory.
+type queue struct {
+	dir string
+}
+
+// init creates the directories of the queue.
+func (q queue) init(replica string) error {
+	for _, dirPath := range []string{q.pendingDir(), q.claimedDir(replica), q.affinityDir()} {
+		err := os.MkdirAll(dirPath, 0o700)
+		if err != nil {
+			return fmt.Errorf("create directory '%s': %w", dirPath, err)
+		}
+	}
+
+	return nil
+}
+
+// push adds a request to the queue. The directory of the request appears
+// atomically, so that the replicas never pull a partial request.
+func (q queue) push(req request, body io.Reader) error {
+	// The names sort in the order of arrival.
+	name := fmt.Sprintf("%020d-%s", req.EnqueuedAt.UnixNano(), uuid.NewString())
+
+	// Dotfiles are not requests yet.
+	tmpPath := filepath.Join(q.pendingDir(), "."+name)
+
+	err := os.Mkdir(tmpPath, 0o700)
+	if err != nil {
+		return fmt.Errorf("create temporary request directory: %w", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(tmpPath)
+	}()
+
+	err = writeFile(filepath.Join(tmpPath, bodyFilename), body)
+	if err != nil {
+		return fmt.Errorf("write body: %w", err)
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	err = writeFile(filepath.Join(tmpPath, requestFilename), bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("write request: %w", err)
+	}
+
+	err = os.Rename(tmpPath, filepath.Join(q.pendingDir(), name))
+	if err != nil {
+		return fmt.Errorf("rename request directory: %w", err)
+	}
+
+	return nil
+}
+
+// pull claims the oldest request a replica may handle, i.e., without a tag
+// the replica does not have, nor an affinity key bound to another replica.
+// It returns [errQueueEmpty] if there is no such request. If the binding of
+// the affinity key fails, it returns the claimed request with the error.
+func (q queue) pull(replica string, tags []string, affinityTtl time.Duration, now time.Time) (item, error) {
+	names, err := q.pending()
+	if err != nil {
+		return item{}, err
+	}
+
+	for _, name := range names {
+		req, err := readRequest(filepath.Join(q.pendingDir(), name))
+		if err != nil {
+			// Another replica may have pulled the request meanwhile.
+			continue
+		}
+
+		if req.Tag != "" && !slices.Contains(tags, req.Tag) {
+			continue
+		}
+
+		if req.Affinity != "" {
+			owner, ok := q.affinity(req.affinityKey(), affinityTtl, now)
+			if ok && owner != replica {
+				continue
+			}
+		}
+
+		claimedPath := filepath.Join(q.claimedDir(replica), name)
+
+		// Only one replica wins the rename.
+		err = os.Rename(filepath.Join(q.pendingDir(), name), claimedPath)
+		if err != nil {
+			continue
+		}
+
+		// The modification time is the heartbeat of the claim.
+		_ = os.Chtimes(claimedPath, now, now)
+
+		if req.Affinity != "" {
+			err = q.bind(req.affinityKey(), replica, now)
+			if err != nil {
+				return item{name: name, path: claimedPath, request: req}, fmt.Errorf("bind affinity: %w", err)
+			}
+		}
+
+		return item{name: name, path: claimedPath, request: req}, nil
+	}
+
+	return item{}, errQueueEmpty
+}
+
+// done removes a request once handled.
+func (q queue) done(it item) error {
+	err := os.RemoveAll(it.path)
+	if err != nil {
+		return fmt.Errorf("remove request: %w", err)
+	}
+
+	return nil
+}
+
+// release puts a request back in the queue, for any replica to pull it
+// again.
+func (q queue) release(it item) error {
+	err := os.Rename(it.path, filepath.Join(q.pendingDir(), it.name))
+	if err != nil {
+		return fmt.Errorf("rename request directory: %w", err)
+	}
+
+	return nil
+}
+
+// heartbeat tells the other replicas a claim is still alive.
+func (q queue) heartbeat(it item, now time.Time) error {
+	err := os.Chtimes(it.path, now, now)
+	if err != nil {
+		return fmt.Errorf("touch request directory: %w", err)
+	}
+
+	return nil
+}
+
+// reclaim puts back in the queue the requests whose claims have no
+// heartbeat for longer than the timeout, e.g., because their replica
+// crashed. It returns the number of recovered requests.
+func (q queue) reclaim(timeout time.Duration, now time.Time) (int, error) {
+	replicas, err := os.ReadDir(filepath.Join(q.dir, "claimed"))
+	if err != nil {
+		return 0, fmt.Errorf("read claimed directory: %w", err)
+	}
+
+	var (
+		count      int
+		recoverErr error
+	)
+
+	for _, replica := range replicas {
+		if !replica.IsDir() {
+			continue
+		}
+
+		entries, err := os.ReadDir(q.claimedDir(replica.Name()))
+		if err != nil {
+			recoverErr = multierr.Append(recoverErr, err)
+			continue
+		}
+
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || now.Sub(info.ModTime()) < timeout {
+				continue
+			}
+
+			err = os.Rename(filepath.Join(q.claimedDir(replica.Name()), entry.Name()), filepath.Join(q.pendingDir(), entry.Name()))
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					recoverErr = multierr.Append(recoverErr, err)
+				}
+
+				continue
+			}
+
+			count++
+		}
+	}
+
+	return count, recoverErr
+}
+
+// affinity returns the replica bound to an affinity key, if the binding is
+// not older than the TTL.
+func (q queue) affinity(key string, ttl time.Duration, now time.Time) (string, bool) {
+	path := q.affinityPath(key)
+
+	info, err := os.Stat(path)
+	if err != nil || now.Sub(info.ModTime()) >= ttl {
+		return "", false
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+
+	return string(b), true
+}
+
+// bind binds an affinity key to a replica, or refreshes the binding.
+func (q queue) bind(key, replica string, now time.Time) error {
+	f, err := os.CreateTemp(q.affinityDir(), ".affinity-")
+	if err != nil {
+		return fmt.Errorf("create temporary affinity file: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.WriteString(replica)
+	if err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("write affinity: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("close affinity file: %w", err)
+	}
+
+	err = os.Chtimes(f.Name(), now, now)
+	if err != nil {
+		return fmt.Errorf("touch affinity file: %w", err)
+	}
+
+	err = os.Rename(f.Name(), q.affinityPath(key))
+	if err != nil {
+		return fmt.Errorf("rename affinity file: %w", err)
+	}
+
+	return nil
+}
+
+// expire removes the affinity bindings older than the TTL.
+func (q queue) expire(ttl time.Duration, now time.Time) error {
+	entries, err := os.ReadDir(q.affinityDir())
+	if err != nil {
+		return fmt.Errorf("read affinity directory: %w", err)
+	}
+
+	var expireErr error
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < ttl {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(q.affinityDir(), entry.Name()))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			expireErr = multierr.Append(expireErr, err)
+		}
+	}
+
+	return expireErr
+}
+
+// depth gathers the numbers of requests of a tag, or of all tags.
+type depth struct {
+	// Queued is the number of pending requests.
+	Queued int
+
+	// InFlight is the number of requests the replicas are handling.
+	InFlight int
+
+	// OldestQueuedAt is the arrival time of the oldest pending request, if
+	// any.
+	OldestQueuedAt time.Time
+}
+
+// stats returns the depth of the queue by tag, the requests without a tag
+// being under the empty tag. The requests which move meanwhile may count
+// twice or not at all.
+func (q queue) stats() (map[string]depth, error) {
+	stats := make(map[string]depth)
+
+	names, err := q.pending()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		req, err := readRequest(filepath.Join(q.pendingDir(), name))
+		if err != nil {
+			continue
+		}
+
+		d := stats[req.Tag]
+		d.Queued++
+
+		if d.OldestQueuedAt.IsZero() || req.EnqueuedAt.Before(d.OldestQueuedAt) {
+			d.OldestQueuedAt = req.EnqueuedAt
+		}
+
+		stats[req.Tag] = d
+	}
+
+	replicas, err := os.ReadDir(filepath.Join(q.dir, "claimed"))
+	if err != nil {
+		return nil, fmt.Errorf("read claimed directory: %w", err)
+	}
+
+	for _, replica := range replicas {
+		if !replica.IsDir() {
+			continue
+		}
+
+		entries, err := os.ReadDir(q.claimedDir(replica.Name()))
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			req, err := readRequest(filepath.Join(q.claimedDir(replica.Name()), entry.Name()))
+			if err != nil {
+				continue
+			}
+
+			d := stats[req.Tag]
+			d.InFlight++
+			stats[req.Tag] = d
+		}
+	}
+
+	return stats, nil
+}
+
+// pending returns the names of the pending requests, the oldest first.
+func (q queue) pending() ([]string, error) {
+	entries, err := os.ReadDir(q.pendingDir())
+	if err != nil {
+		return nil, fmt.Errorf("read queue directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (q queue) pendingDir() string {
+	return filepath.Join(q.dir, "queue")
+}
+
+func (q queue) claimedDir(replica string) string {
+	return filepath.Join(q.dir, "claimed", replica)
+}
+
+func (q queue) affinityDir() string {
+	return filepath.Join(q.dir, "affinity")
+}
+
+// affinityPath returns the path of the binding of an affinity key. The keys
+// come from the clients, so they are hashed.
+func (q queue) affinityPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(q.affinityDir(), hex.EncodeToString(sum[:]))
+}
+
+// readRequest reads the metadata of a request.
+func readRequest(dirPath string) (request, error) {
+	b, err := os.ReadFile(filepath.Join(dirPath, requestFilename))
+	if err != nil {
+		return request{}, fmt.Errorf("read request: %w", err)
+	}
+
+	var req request
+
+	err = json.Unmarshal(b, &req)
+	if err != nil {
+		return request{}, fmt.Errorf("unmarshal request: %w", err)
+	}
+
+	return req, nil
+}
+
+// writeFile writes a new file and flushes it to the disk.
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+
+	_, err = io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("close file: %w", err)
+	}
+
+	return nil
+}
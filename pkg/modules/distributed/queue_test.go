@@ -0,0 +1,281 @@
+package distributed
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestQueue(t *testing.T, replicas ...string) queue {
+	q := queue{dir: t.TempDir()}
+
+	for _, replica := range replicas {
+		err := q.init(replica)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	return q
+}
+
+func TestQueue_pushAndPull(t *testing.T) {
+	q := newTestQueue(t, "a", "b")
+	now := time.Now()
+
+	for i, trace := range []string{"foo", "bar"} {
+		err := q.push(request{Trace: trace, Method: http.MethodPost, EnqueuedAt: now.Add(time.Duration(i) * time.Second)}, strings.NewReader(trace))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	first, err := q.pull("a", nil, time.Minute, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if first.request.Trace != "foo" {
+		t.Errorf("expected the oldest request 'foo' but got '%s'", first.request.Trace)
+	}
+
+	b, err := os.ReadFile(filepath.Join(first.path, bodyFilename))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if string(b) != "foo" {
+		t.Errorf("expected body 'foo' but got '%s'", string(b))
+	}
+
+	second, err := q.pull("b", nil, time.Minute, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if second.request.Trace != "bar" {
+		t.Errorf("expected request 'bar' but got '%s'", second.request.Trace)
+	}
+
+	_, err = q.pull("a", nil, time.Minute, now)
+	if !errors.Is(err, errQueueEmpty) {
+		t.Errorf("expected error %v but got: %v", errQueueEmpty, err)
+	}
+
+	err = q.release(second)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	again, err := q.pull("a", nil, time.Minute, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if again.request.Trace != "bar" {
+		t.Errorf("expected the released request 'bar' but got '%s'", again.request.Trace)
+	}
+
+	for _, it := range []item{first, again} {
+		err = q.done(it)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	names, err := q.pending()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(names) != 0 {
+		t.Errorf("expected no pending request but got %v", names)
+	}
+}
+
+func TestQueue_push(t *testing.T) {
+	q := newTestQueue(t, "a")
+
+	err := q.push(request{Trace: "foo"}, failingReader{})
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	entries, err := os.ReadDir(q.pendingDir())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(entries) != 0 {
+		t.Errorf("expected no partial request but got %d entries", len(entries))
+	}
+}
+
+func TestQueue_affinity(t *testing.T) {
+	q := newTestQueue(t, "a", "b")
+	now := time.Now()
+
+	for i, trace := range []string{"foo", "bar", "baz"} {
+		affinity := "workflow"
+		if trace == "baz" {
+			affinity = ""
+		}
+
+		err := q.push(request{Trace: trace, Affinity: affinity, EnqueuedAt: now.Add(time.Duration(i) * time.Second)}, strings.NewReader(trace))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	it, err := q.pull("a", nil, time.Minute, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if it.request.Trace != "foo" {
+		t.Fatalf("expected request 'foo' but got '%s'", it.request.Trace)
+	}
+
+	owner, ok := q.affinity("workflow", time.Minute, now)
+	if !ok || owner != "a" {
+		t.Errorf("expected the affinity key to stick to 'a' but got '%s'", owner)
+	}
+
+	// 'bar' sticks to 'a'.
+	it, err = q.pull("b", nil, time.Minute, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if it.request.Trace != "baz" {
+		t.Errorf("expected request 'baz' but got '%s'", it.request.Trace)
+	}
+
+	// Once expired, the affinity key no longer sticks.
+	it, err = q.pull("b", nil, time.Minute, now.Add(2*time.Minute))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if it.request.Trace != "bar" {
+		t.Errorf("expected request 'bar' but got '%s'", it.request.Trace)
+	}
+
+	err = q.expire(time.Minute, now.Add(4*time.Minute))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, ok = q.affinity("workflow", time.Hour, now)
+	if ok {
+		t.Error("expected the affinity key to be removed")
+	}
+}
+
+func TestQueue_tags(t *testing.T) {
+	q := newTestQueue(t, "a", "b")
+	now := time.Now()
+
+	err := q.push(request{Trace: "foo", Affinity: "workflow", Tag: "libreoffice-heavy", EnqueuedAt: now}, strings.NewReader("foo"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = q.push(request{Trace: "bar", Affinity: "workflow", EnqueuedAt: now.Add(time.Second)}, strings.NewReader("bar"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// 'a' does not have the tag of 'foo'.
+	it, err := q.pull("a", []string{"chromium-only"}, time.Minute, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if it.request.Trace != "bar" {
+		t.Fatalf("expected request 'bar' but got '%s'", it.request.Trace)
+	}
+
+	// The workflow sticks to 'a' only for the requests without a tag.
+	it, err = q.pull("b", []string{"libreoffice-heavy"}, time.Minute, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if it.request.Trace != "foo" {
+		t.Errorf("expected request 'foo' but got '%s'", it.request.Trace)
+	}
+
+	owner, ok := q.affinity("libreoffice-heavy/workflow", time.Minute, now)
+	if !ok || owner != "b" {
+		t.Errorf("expected the affinity key to stick to 'b' for the tag but got '%s'", owner)
+	}
+}
+
+func TestQueue_reclaim(t *testing.T) {
+	q := newTestQueue(t, "a")
+	now := time.Now()
+
+	err := q.push(request{Trace: "foo", EnqueuedAt: now}, strings.NewReader("foo"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	it, err := q.pull("a", nil, time.Minute, now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	count, err := q.reclaim(time.Minute, now.Add(30*time.Second))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if count != 0 {
+		t.Errorf("expected no recovered request but got %d", count)
+	}
+
+	err = q.heartbeat(it, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	count, err = q.reclaim(time.Minute, now.Add(90*time.Second))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if count != 0 {
+		t.Errorf("expected no recovered request after a heartbeat but got %d", count)
+	}
+
+	count, err = q.reclaim(time.Minute, now.Add(3*time.Minute))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if count != 1 {
+		t.Errorf("expected 1 recovered request but got %d", count)
+	}
+
+	names, err := q.pending()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(names) != 1 || names[0] != it.name {
+		t.Errorf("expected pending request '%s' but got %v", it.name, names)
+	}
+}
+
+// failingReader is a reader which always fails.
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
@@ -0,0 +1,159 @@
+package distributed
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// scaling is the response of the scaling route, for the autoscalers which
+// poll a JSON endpoint, e.g., the KEDA metrics API scaler.
+type scaling struct {
+	// Tag is the tag of the requests, if the client asked for one.
+	Tag string `json:"tag,omitempty"`
+
+	// QueueDepth is the number of pending requests.
+	QueueDepth int `json:"queueDepth"`
+
+	// InFlight is the number of requests all the replicas are handling.
+	InFlight int `json:"inFlight"`
+
+	// Backlog is the sum of the pending and the in-flight requests, i.e.,
+	// the work the replicas of the pool have to do.
+	Backlog int `json:"backlog"`
+
+	// OldestQueuedSeconds is the time the oldest pending request waits for.
+	OldestQueuedSeconds float64 `json:"oldestQueuedSeconds"`
+
+	// ReplicaInFlight is the number of requests the replica answering
+	// handles.
+	ReplicaInFlight int64 `json:"replicaInFlight"`
+
+	// ReplicaWorkers is the number of requests the replica answering may
+	// handle at once.
+	ReplicaWorkers int `json:"replicaWorkers"`
+}
+
+// scaling returns the scaling signals of the requests with a given tag, or of
+// all the requests if the tag is empty.
+func (mod *Distributed) scaling(tag string, now time.Time) (scaling, error) {
+	stats, err := mod.queue.stats()
+	if err != nil {
+		return scaling{}, fmt.Errorf("get queue stats: %w", err)
+	}
+
+	resp := scaling{
+		Tag:             tag,
+		ReplicaInFlight: mod.inFlight.Load(),
+		ReplicaWorkers:  mod.workers,
+	}
+
+	var oldest time.Time
+
+	for t, d := range stats {
+		if tag != "" && t != tag {
+			continue
+		}
+
+		resp.QueueDepth += d.Queued
+		resp.InFlight += d.InFlight
+
+		if !d.OldestQueuedAt.IsZero() && (oldest.IsZero() || d.OldestQueuedAt.Before(oldest)) {
+			oldest = d.OldestQueuedAt
+		}
+	}
+
+	resp.Backlog = resp.QueueDepth + resp.InFlight
+
+	if !oldest.IsZero() {
+		resp.OldestQueuedSeconds = now.Sub(oldest).Seconds()
+	}
+
+	return resp, nil
+}
+
+// Routes returns the route which exposes the scaling signals.
+func (mod *Distributed) Routes() ([]api.Route, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/distributed/scaling",
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				tag := c.QueryParam("tag")
+				if tag != "" && !tagRegexp.MatchString(tag) {
+					return api.WrapError(
+						fmt.Errorf("invalid tag '%s'", tag),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid query: tag '%s' must only contain letters, digits, dots, underscores and hyphens", tag)).WithCode(api.ErrorCodeDistributedInvalidQuery),
+					)
+				}
+
+				resp, err := mod.scaling(tag, time.Now())
+				if err != nil {
+					return fmt.Errorf("get scaling signals: %w", err)
+				}
+
+				return c.JSON(http.StatusOK, resp)
+			},
+		},
+	}, nil
+}
+
+// Metrics returns the metrics of the queue, by tag, and of this replica.
+func (mod *Distributed) Metrics() ([]gotenberg.Metric, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	read := func(value func(d depth) float64) func() map[string]float64 {
+		return func() map[string]float64 {
+			stats, err := mod.queue.stats()
+			if err != nil {
+				mod.logger.Error(fmt.Sprintf("get queue stats: %s", err))
+				return nil
+			}
+
+			values := make(map[string]float64, len(stats))
+			for tag, d := range stats {
+				values[tag] = value(d)
+			}
+
+			return values
+		}
+	}
+
+	return []gotenberg.Metric{
+		{
+			Name:        "distributed_queue_depth",
+			Description: "Current number of requests in the shared queue, by required tag.",
+			Label:       "tag",
+			ReadLabeled: read(func(d depth) float64 {
+				return float64(d.Queued)
+			}),
+		},
+		{
+			Name:        "distributed_in_flight",
+			Description: "Current number of requests the replicas are handling, by required tag.",
+			Label:       "tag",
+			ReadLabeled: read(func(d depth) float64 {
+				return float64(d.InFlight)
+			}),
+		},
+		{
+			Name:        "distributed_replica_in_flight",
+			Description: "Current number of requests this replica is handling.",
+			Read: func() float64 {
+				return float64(mod.inFlight.Load())
+			},
+		},
+	}, nil
+}
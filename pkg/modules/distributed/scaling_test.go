@@ -0,0 +1,207 @@
+package distributed
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func newTestScalingModule(t *testing.T, now time.Time) *Distributed {
+	mod := &Distributed{
+		enable:  true,
+		workers: 2,
+		queue:   newTestQueue(t, "a"),
+		logger:  zap.NewNop(),
+	}
+
+	for i, tag := range []string{"", "libreoffice-heavy", "libreoffice-heavy", "chromium-only"} {
+		err := mod.queue.push(request{Trace: "foo", Tag: tag, EnqueuedAt: now.Add(time.Duration(i) * time.Second)}, strings.NewReader("foo"))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	// Claim the request without a tag, then the oldest 'libreoffice-heavy'
+	// request.
+	for _, tags := range [][]string{nil, {"libreoffice-heavy"}} {
+		_, err := mod.queue.pull("a", tags, time.Minute, now)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	mod.inFlight.Add(1)
+
+	return mod
+}
+
+func TestDistributed_scaling(t *testing.T) {
+	now := time.Now()
+	mod := newTestScalingModule(t, now)
+
+	for _, tc := range []struct {
+		scenario string
+		tag      string
+		expect   scaling
+	}{
+		{
+			scenario: "all the requests",
+			expect:   scaling{QueueDepth: 2, InFlight: 2, Backlog: 4, OldestQueuedSeconds: 2, ReplicaInFlight: 1, ReplicaWorkers: 2},
+		},
+		{
+			scenario: "requests with a tag",
+			tag:      "libreoffice-heavy",
+			expect:   scaling{Tag: "libreoffice-heavy", QueueDepth: 1, InFlight: 1, Backlog: 2, OldestQueuedSeconds: 2, ReplicaInFlight: 1, ReplicaWorkers: 2},
+		},
+		{
+			scenario: "requests with another tag",
+			tag:      "chromium-only",
+			expect:   scaling{Tag: "chromium-only", QueueDepth: 1, Backlog: 1, OldestQueuedSeconds: 1, ReplicaInFlight: 1, ReplicaWorkers: 2},
+		},
+		{
+			scenario: "unknown tag",
+			tag:      "foo",
+			expect:   scaling{Tag: "foo", ReplicaInFlight: 1, ReplicaWorkers: 2},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := mod.scaling(tc.tag, now.Add(4*time.Second))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestDistributed_Routes(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		routes, err := new(Distributed).Routes()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(routes) != 0 {
+			t.Errorf("expected no routes but got %d", len(routes))
+		}
+	})
+
+	mod := newTestScalingModule(t, time.Now())
+
+	routes, err := mod.Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 1 {
+		t.Fatalf("expected 1 route but got %d", len(routes))
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		target       string
+		expectStatus int
+		expectDepth  int
+	}{
+		{
+			scenario:     "invalid tag",
+			target:       "/distributed/scaling?tag=../foo",
+			expectStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:     "all the requests",
+			target:       "/distributed/scaling",
+			expectStatus: http.StatusOK,
+			expectDepth:  2,
+		},
+		{
+			scenario:     "requests with a tag",
+			target:       "/distributed/scaling?tag=chromium-only",
+			expectStatus: http.StatusOK,
+			expectDepth:  1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, tc.target, nil), rec)
+
+			err := routes[0].Handler(c)
+			if tc.expectStatus != http.StatusOK {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				status := api.ParseErrorResponse(err).Status
+				if status != tc.expectStatus {
+					t.Errorf("expected status %d but got %d", tc.expectStatus, status)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var resp scaling
+
+			err = json.Unmarshal(rec.Body.Bytes(), &resp)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if resp.QueueDepth != tc.expectDepth {
+				t.Errorf("expected queue depth %d but got %d", tc.expectDepth, resp.QueueDepth)
+			}
+		})
+	}
+}
+
+func TestDistributed_Metrics(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		metrics, err := new(Distributed).Metrics()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(metrics) != 0 {
+			t.Errorf("expected no metrics but got %d", len(metrics))
+		}
+	})
+
+	mod := newTestScalingModule(t, time.Now())
+
+	metrics, err := mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(metrics) != 3 {
+		t.Fatalf("expected 3 metrics but got %d", len(metrics))
+	}
+
+	queueDepth := metrics[0].ReadLabeled()
+	if queueDepth["libreoffice-heavy"] != 1 || queueDepth["chromium-only"] != 1 || queueDepth[""] != 0 {
+		t.Errorf("unexpected queue depth %v", queueDepth)
+	}
+
+	inFlight := metrics[1].ReadLabeled()
+	if inFlight["libreoffice-heavy"] != 1 || inFlight[""] != 1 {
+		t.Errorf("unexpected in-flight requests %v", inFlight)
+	}
+
+	if metrics[2].Read() != 1 {
+		t.Errorf("expected 1 request in flight for this replica but got %f", metrics[2].Read())
+	}
+}
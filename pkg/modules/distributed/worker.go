@@ -0,0 +1,186 @@
+package distributed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// work pulls the requests from the queue and sends them to this replica,
+// one at a time, until the module stops.
+func (mod *Distributed) work() {
+	for {
+		select {
+		case <-mod.stop:
+			return
+		default:
+		}
+
+		it, err := mod.queue.pull(mod.replica, mod.tags, mod.affinityTtl, time.Now())
+		if err != nil && it.path == "" {
+			if !errors.Is(err, errQueueEmpty) {
+				mod.logger.Error(fmt.Sprintf("pull request: %s", err))
+			}
+
+			mod.wait()
+
+			continue
+		}
+
+		if err != nil {
+			mod.logger.Warn(fmt.Sprintf("pull request: %s", err))
+		}
+
+		if !mod.handle(it) {
+			// Let the other replicas, or this one once ready, pull the
+			// request again.
+			mod.wait()
+		}
+	}
+}
+
+// wait waits for the poll interval, or for the module to stop.
+func (mod *Distributed) wait() {
+	select {
+	case <-mod.stop:
+	case <-time.After(mod.pollInterval):
+	}
+}
+
+// handle sends a pulled request to this replica. It returns false if the
+// replica could not accept the request, which went back to the queue.
+func (mod *Distributed) handle(it item) bool {
+	logger := mod.logger.With(zap.String("trace", it.request.Trace))
+
+	mod.inFlight.Add(1)
+	defer mod.inFlight.Add(-1)
+
+	stopHeartbeat := make(chan struct{})
+	defer close(stopHeartbeat)
+
+	go func() {
+		ticker := time.NewTicker(mod.claimTimeout / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stopHeartbeat:
+				return
+			case now := <-ticker.C:
+				err := mod.queue.heartbeat(it, now)
+				if err != nil {
+					logger.Error(fmt.Sprintf("heartbeat: %s", err))
+				}
+			}
+		}
+	}()
+
+	status, msg, err := mod.send(it)
+	if err != nil || status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+		if err == nil {
+			err = fmt.Errorf("got status %d: %s", status, msg)
+		}
+
+		logger.Warn(fmt.Sprintf("send request '%s' to this replica, back in the queue: %s", it.name, err))
+
+		err = mod.queue.release(it)
+		if err != nil {
+			// The claim will expire.
+			logger.Error(fmt.Sprintf("release request '%s': %s", it.name, err))
+		}
+
+		return false
+	}
+
+	if status >= http.StatusBadRequest {
+		// The request is invalid, e.g., a webhook URL not allowed, which
+		// the replica which received it could not tell synchronously.
+		logger.Error(fmt.Sprintf("request '%s' rejected with status %d: %s", it.name, status, msg))
+
+		if mod.jobTracker != nil {
+			mod.jobTracker.TrackJob(gotenberg.Job{
+				ID:        it.request.Trace,
+				Path:      it.request.Path,
+				State:     gotenberg.JobStateFailed,
+				Error:     msg,
+				CreatedAt: it.request.EnqueuedAt,
+				UpdatedAt: time.Now().UTC(),
+			})
+		}
+	}
+
+	err = mod.queue.done(it)
+	if err != nil {
+		logger.Error(fmt.Sprintf("remove request '%s': %s", it.name, err))
+	}
+
+	return true
+}
+
+// send sends a pulled request to this replica, and returns the status and
+// the message of the response. The request waits for the end of the
+// asynchronous process.
+func (mod *Distributed) send(it item) (int, string, error) {
+	body, err := os.Open(filepath.Join(it.path, bodyFilename))
+	if err != nil {
+		return 0, "", fmt.Errorf("open body: %w", err)
+	}
+
+	defer func() {
+		_ = body.Close()
+	}()
+
+	info, err := body.Stat()
+	if err != nil {
+		return 0, "", fmt.Errorf("stat body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), it.request.Method, strings.TrimSuffix(mod.url, "/")+it.request.Uri, body)
+	if err != nil {
+		return 0, "", fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header = it.request.Header.Clone()
+	req.Header.Set(gotenberg.ReplicaHeader, mod.replica)
+	req.ContentLength = info.Size()
+
+	resp, err := mod.client.Do(req)
+	if err != nil {
+		return 0, "", fmt.Errorf("send request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNoContent {
+		return resp.StatusCode, "", nil
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return resp.StatusCode, "", nil
+	}
+
+	// The API answers with a JSON error, but a proxy in between may not.
+	var errResp struct {
+		Message string `json:"message"`
+	}
+
+	if json.Unmarshal(b, &errResp) == nil && errResp.Message != "" {
+		return resp.StatusCode, errResp.Message, nil
+	}
+
+	return resp.StatusCode, strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,115 @@
+package distributed
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestDistributed_handle(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		status        int
+		body          string
+		expectHandled bool
+		expectPending int
+		expectFailed  string
+	}{
+		{
+			scenario:      "accepted",
+			status:        http.StatusNoContent,
+			expectHandled: true,
+		},
+		{
+			scenario:      "rejected",
+			status:        http.StatusForbidden,
+			body:          `{"status":403,"message":"Forbidden"}`,
+			expectHandled: true,
+			expectFailed:  "Forbidden",
+		},
+		{
+			scenario:      "too many requests",
+			status:        http.StatusTooManyRequests,
+			expectPending: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var received *http.Request
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				b, _ := io.ReadAll(req.Body)
+				if string(b) != "body" {
+					t.Errorf("expected body 'body' but got '%s'", string(b))
+				}
+
+				received = req
+				rw.WriteHeader(tc.status)
+				_, _ = rw.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			var tracked []gotenberg.Job
+
+			mod := &Distributed{
+				replica:      "a",
+				url:          srv.URL,
+				claimTimeout: time.Minute,
+				affinityTtl:  time.Minute,
+				queue:        newTestQueue(t, "a"),
+				client:       srv.Client(),
+				jobTracker: &gotenberg.JobTrackerMock{
+					TrackJobMock: func(job gotenberg.Job) {
+						tracked = append(tracked, job)
+					},
+				},
+				logger: zap.NewNop(),
+			}
+
+			header := make(http.Header)
+			header.Set("Gotenberg-Trace", "foo")
+
+			err := mod.queue.push(request{Trace: "foo", Method: http.MethodPost, Uri: "/forms/chromium/convert/url", Header: header, EnqueuedAt: time.Now()}, strings.NewReader("body"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			it, err := mod.queue.pull("a", nil, time.Minute, time.Now())
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			handled := mod.handle(it)
+			if handled != tc.expectHandled {
+				t.Errorf("expected handled %t but got %t", tc.expectHandled, handled)
+			}
+
+			if received.Header.Get(gotenberg.ReplicaHeader) != "a" || received.Header.Get("Gotenberg-Trace") != "foo" {
+				t.Errorf("unexpected headers %v", received.Header)
+			}
+
+			names, err := mod.queue.pending()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(names) != tc.expectPending {
+				t.Errorf("expected %d pending requests but got %d", tc.expectPending, len(names))
+			}
+
+			if tc.expectFailed == "" && len(tracked) > 0 {
+				t.Errorf("expected no tracked job but got %+v", tracked)
+			}
+
+			if tc.expectFailed != "" && (len(tracked) != 1 || tracked[0].State != gotenberg.JobStateFailed || tracked[0].Error != tc.expectFailed) {
+				t.Errorf("expected failed job with error '%s' but got %+v", tc.expectFailed, tracked)
+			}
+		})
+	}
+}
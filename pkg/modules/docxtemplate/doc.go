@@ -0,0 +1,4 @@
+// Package docxtemplate provides a module which adds a route for filling DOCX
+// templates with JSON data, e.g., loops, conditions, and images, and for
+// converting the results to PDF with LibreOffice.
+package docxtemplate
@@ -0,0 +1,212 @@
+package docxtemplate
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidTemplate happens if a file is not a valid DOCX template, or if
+// its template syntax is invalid.
+var ErrInvalidTemplate = errors.New("invalid DOCX template")
+
+// maxPartSize is the maximum uncompressed size of a file within a DOCX
+// archive.
+const maxPartSize = 64 << 20
+
+// templatePartName matches the XML parts of a DOCX file the template engine
+// fills: the main document, the headers, and the footers.
+var templatePartName = regexp.MustCompile(`^word/(document|header\d*|footer\d*)\.xml$`)
+
+// docx is a DOCX file, i.e., a ZIP archive of XML parts.
+type docx struct {
+	names []string
+	files map[string][]byte
+}
+
+// readDocx reads a DOCX file in memory.
+func readDocx(path string) (*docx, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("open archive: %v: %w", err, ErrInvalidTemplate)
+	}
+
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	doc := &docx{
+		files: make(map[string][]byte),
+	}
+
+	for _, f := range reader.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+
+		if f.UncompressedSize64 > maxPartSize {
+			return nil, fmt.Errorf("file '%s' is too large: %w", f.Name, ErrInvalidTemplate)
+		}
+
+		b, err := readZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("read file '%s': %w", f.Name, err)
+		}
+
+		doc.names = append(doc.names, f.Name)
+		doc.files[f.Name] = b
+	}
+
+	if _, ok := doc.files["word/document.xml"]; !ok {
+		return nil, fmt.Errorf("no 'word/document.xml' part: %w", ErrInvalidTemplate)
+	}
+
+	return doc, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open: %v: %w", err, ErrInvalidTemplate)
+	}
+
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	// The declared size may lie.
+	b, err := io.ReadAll(io.LimitReader(rc, maxPartSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read: %v: %w", err, ErrInvalidTemplate)
+	}
+
+	if len(b) > maxPartSize {
+		return nil, fmt.Errorf("too large: %w", ErrInvalidTemplate)
+	}
+
+	return b, nil
+}
+
+// templateParts returns the names of the parts to fill, sorted.
+func (doc *docx) templateParts() []string {
+	var parts []string
+	for _, name := range doc.names {
+		if templatePartName.MatchString(name) {
+			parts = append(parts, name)
+		}
+	}
+
+	sort.Strings(parts)
+
+	return parts
+}
+
+// set adds or replaces a file.
+func (doc *docx) set(name string, b []byte) {
+	if _, ok := doc.files[name]; !ok {
+		doc.names = append(doc.names, name)
+	}
+
+	doc.files[name] = b
+}
+
+// write writes the DOCX file, with its files in their original order.
+func (doc *docx) write(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	writer := zip.NewWriter(f)
+
+	for _, name := range doc.names {
+		w, err := writer.Create(name)
+		if err != nil {
+			return fmt.Errorf("create '%s': %w", name, err)
+		}
+
+		_, err = w.Write(doc.files[name])
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", name, err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return f.Close()
+}
+
+// relsName returns the name of the relationships part of a part, e.g.,
+// "word/_rels/document.xml.rels" for "word/document.xml".
+func relsName(part string) string {
+	i := strings.LastIndex(part, "/")
+
+	return part[:i+1] + "_rels/" + part[i+1:] + ".rels"
+}
+
+// addRelationship adds a relationship to a part and returns its ID.
+func (doc *docx) addRelationship(part, relType, target string) string {
+	name := relsName(part)
+
+	rels, ok := doc.files[name]
+	if !ok {
+		rels = []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`)
+	}
+
+	content := string(rels)
+
+	// An ID which does not collide with the existing ones.
+	var id string
+	for i := 1; ; i++ {
+		id = fmt.Sprintf("rIdGotenberg%d", i)
+		if !strings.Contains(content, `"`+id+`"`) {
+			break
+		}
+	}
+
+	relationship := fmt.Sprintf(`<Relationship Id="%s" Type="%s" Target="%s"/>`, id, relType, target)
+
+	end := strings.LastIndex(content, "</Relationships>")
+	if end < 0 {
+		// E.g., <Relationships ... />.
+		content = strings.Replace(content, "/>", ">"+relationship+"</Relationships>", 1)
+	} else {
+		content = content[:end] + relationship + content[end:]
+	}
+
+	doc.set(name, []byte(content))
+
+	return id
+}
+
+// addContentType declares the content type of an extension, if not already
+// declared.
+func (doc *docx) addContentType(extension, contentType string) {
+	const name = "[Content_Types].xml"
+
+	content := string(doc.files[name])
+	if strings.Contains(strings.ToLower(content), fmt.Sprintf(`extension="%s"`, extension)) {
+		return
+	}
+
+	end := strings.LastIndex(content, "</Types>")
+	if end < 0 {
+		return
+	}
+
+	content = content[:end] + fmt.Sprintf(`<Default Extension="%s" ContentType="%s"/>`, extension, contentType) + content[end:]
+	doc.set(name, []byte(content))
+}
@@ -0,0 +1,256 @@
+package docxtemplate
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`</Types>`
+
+	testRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+)
+
+// testDocument wraps the body of a main document part.
+func testDocument(body string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` +
+		body +
+		`</w:body></w:document>`
+}
+
+// writeTestDocx writes a DOCX file with the given files, in the given order.
+func writeTestDocx(t *testing.T, path string, files ...string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	writer := zip.NewWriter(f)
+
+	for i := 0; i+1 < len(files); i += 2 {
+		w, err := writer.Create(files[i])
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		_, err = w.Write([]byte(files[i+1]))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestReadDocx(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		files       []string
+		notZip      bool
+		expectNames []string
+		expectError bool
+	}{
+		{
+			scenario:    "not a ZIP archive",
+			notZip:      true,
+			expectError: true,
+		},
+		{
+			scenario:    "no main document",
+			files:       []string{"[Content_Types].xml", testContentTypes},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			files: []string{
+				"[Content_Types].xml", testContentTypes,
+				"word/document.xml", testDocument(""),
+				"word/_rels/document.xml.rels", testRels,
+			},
+			expectNames: []string{"[Content_Types].xml", "word/document.xml", "word/_rels/document.xml.rels"},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "template.docx")
+
+			if tc.notZip {
+				err := os.WriteFile(path, []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			} else {
+				writeTestDocx(t, path, tc.files...)
+			}
+
+			doc, err := readDocx(path)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				if !errors.Is(err, ErrInvalidTemplate) {
+					t.Fatalf("expected error %v but got: %v", ErrInvalidTemplate, err)
+				}
+
+				return
+			}
+
+			if !reflect.DeepEqual(doc.names, tc.expectNames) {
+				t.Errorf("expected %v but got %v", tc.expectNames, doc.names)
+			}
+		})
+	}
+}
+
+func TestDocx_TemplateParts(t *testing.T) {
+	doc := &docx{
+		names: []string{
+			"word/footer1.xml",
+			"word/styles.xml",
+			"word/document.xml",
+			"word/header2.xml",
+			"word/_rels/document.xml.rels",
+			"word/header1.xml",
+		},
+	}
+
+	actual := doc.templateParts()
+	expect := []string{"word/document.xml", "word/footer1.xml", "word/header1.xml", "word/header2.xml"}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %v but got %v", expect, actual)
+	}
+}
+
+func TestDocx_Write(t *testing.T) {
+	dirPath := t.TempDir()
+	inputPath := filepath.Join(dirPath, "input.docx")
+	outputPath := filepath.Join(dirPath, "output.docx")
+
+	writeTestDocx(t, inputPath,
+		"[Content_Types].xml", testContentTypes,
+		"word/document.xml", testDocument(""),
+	)
+
+	doc, err := readDocx(inputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	doc.set("word/media/foo.png", []byte("foo"))
+
+	err = doc.write(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	actual, err := readDocx(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !reflect.DeepEqual(actual, doc) {
+		t.Errorf("expected %+v but got %+v", doc, actual)
+	}
+}
+
+func TestRelsName(t *testing.T) {
+	for _, tc := range []struct {
+		part   string
+		expect string
+	}{
+		{part: "word/document.xml", expect: "word/_rels/document.xml.rels"},
+		{part: "word/header1.xml", expect: "word/_rels/header1.xml.rels"},
+	} {
+		t.Run(tc.part, func(t *testing.T) {
+			actual := relsName(tc.part)
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestDocx_AddRelationship(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		rels         string
+		expectId     string
+		expectSuffix string
+	}{
+		{
+			scenario:     "no relationships part",
+			expectId:     "rIdGotenberg1",
+			expectSuffix: `<Relationship Id="rIdGotenberg1" Type="image" Target="media/foo.png"/></Relationships>`,
+		},
+		{
+			scenario:     "empty relationships element",
+			rels:         `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"/>`,
+			expectId:     "rIdGotenberg1",
+			expectSuffix: `<Relationship Id="rIdGotenberg1" Type="image" Target="media/foo.png"/></Relationships>`,
+		},
+		{
+			scenario:     "existing relationships",
+			rels:         strings.Replace(testRels, "rId1", "rIdGotenberg1", 1),
+			expectId:     "rIdGotenberg2",
+			expectSuffix: `<Relationship Id="rIdGotenberg2" Type="image" Target="media/foo.png"/></Relationships>`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			doc := &docx{files: make(map[string][]byte)}
+			if tc.rels != "" {
+				doc.set("word/_rels/document.xml.rels", []byte(tc.rels))
+			}
+
+			id := doc.addRelationship("word/document.xml", "image", "media/foo.png")
+			if id != tc.expectId {
+				t.Errorf("expected '%s' but got '%s'", tc.expectId, id)
+			}
+
+			actual := string(doc.files["word/_rels/document.xml.rels"])
+			if !strings.HasSuffix(actual, tc.expectSuffix) {
+				t.Errorf("expected '%s' to end with '%s'", actual, tc.expectSuffix)
+			}
+		})
+	}
+}
+
+func TestDocx_AddContentType(t *testing.T) {
+	doc := &docx{files: make(map[string][]byte)}
+	doc.set("[Content_Types].xml", []byte(testContentTypes))
+
+	doc.addContentType("png", "image/png")
+	doc.addContentType("png", "image/png")
+	doc.addContentType("xml", "application/xml")
+
+	actual := string(doc.files["[Content_Types].xml"])
+
+	if strings.Count(actual, `Extension="png"`) != 1 {
+		t.Errorf("expected one 'png' content type in '%s'", actual)
+	}
+
+	if strings.Count(actual, `Extension="xml"`) != 1 {
+		t.Errorf("expected one 'xml' content type in '%s'", actual)
+	}
+}
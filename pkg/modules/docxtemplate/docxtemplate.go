@@ -0,0 +1,87 @@
+package docxtemplate
+
+import (
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(DocxTemplate))
+}
+
+// DocxTemplate is a module which provides a route for filling DOCX templates
+// and converting them to PDF.
+type DocxTemplate struct {
+	libreOffice   libreofficeapi.Uno
+	engine        gotenberg.PdfEngine
+	disableRoutes bool
+}
+
+// Descriptor returns a [DocxTemplate]'s module descriptor.
+func (mod *DocxTemplate) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "docxtemplate",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("docxtemplate", flag.ExitOnError)
+			fs.Bool("docxtemplate-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(DocxTemplate) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *DocxTemplate) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("docxtemplate-disable-routes")
+
+	provider, err := ctx.Module(new(libreofficeapi.Provider))
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno provider: %w", err)
+	}
+
+	libreOffice, err := provider.(libreofficeapi.Provider).LibreOffice()
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno: %w", err)
+	}
+
+	mod.libreOffice = libreOffice
+
+	provider, err = ctx.Module(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine provider: %w", err)
+	}
+
+	engine, err := provider.(gotenberg.PdfEngineProvider).PdfEngine()
+	if err != nil {
+		return fmt.Errorf("get PDF engine: %w", err)
+	}
+
+	mod.engine = engine
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *DocxTemplate) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		convertRoute(mod.libreOffice, mod.engine),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*DocxTemplate)(nil)
+	_ gotenberg.Provisioner = (*DocxTemplate)(nil)
+	_ api.Router            = (*DocxTemplate)(nil)
+)
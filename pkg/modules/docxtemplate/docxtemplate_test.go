@@ -0,0 +1,147 @@
+package docxtemplate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestDocxTemplate_Descriptor(t *testing.T) {
+	descriptor := new(DocxTemplate).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(DocxTemplate))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestDocxTemplate_Provision(t *testing.T) {
+	// Each provider is a distinct module, so that a scenario may omit any of
+	// them.
+	libreOfficeProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			libreofficeapi.ProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "libreoffice", New: func() gotenberg.Module { return mod }}
+		}
+		mod.LibreOfficeMock = func() (libreofficeapi.Uno, error) {
+			return new(libreofficeapi.ApiMock), err
+		}
+
+		return mod
+	}
+
+	pdfEngineProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			gotenberg.PdfEngineProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "pdfengines", New: func() gotenberg.Module { return mod }}
+		}
+		mod.PdfEngineMock = func() (gotenberg.PdfEngine, error) {
+			return new(gotenberg.PdfEngineMock), err
+		}
+
+		return mod
+	}
+
+	newContext := func(mods ...gotenberg.Module) *gotenberg.Context {
+		descriptors := make([]gotenberg.ModuleDescriptor, len(mods))
+		for i, mod := range mods {
+			descriptors[i] = mod.Descriptor()
+		}
+
+		return gotenberg.NewContext(
+			gotenberg.ParsedFlags{
+				FlagSet: new(DocxTemplate).Descriptor().FlagSet,
+			},
+			descriptors,
+		)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectError bool
+	}{
+		{
+			scenario:    "no LibreOffice API provider",
+			ctx:         newContext(),
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice API from LibreOffice API provider",
+			ctx:         newContext(libreOfficeProvider(errors.New("foo"))),
+			expectError: true,
+		},
+		{
+			scenario:    "no PDF engine provider",
+			ctx:         newContext(libreOfficeProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no PDF engine from PDF engine provider",
+			ctx:         newContext(libreOfficeProvider(nil), pdfEngineProvider(errors.New("foo"))),
+			expectError: true,
+		},
+		{
+			scenario:    "provision success",
+			ctx:         newContext(libreOfficeProvider(nil), pdfEngineProvider(nil)),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(DocxTemplate)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestDocxTemplate_Routes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		expectRoutes  int
+		disableRoutes bool
+	}{
+		{
+			scenario:      "routes not disabled",
+			expectRoutes:  1,
+			disableRoutes: false,
+		},
+		{
+			scenario:      "routes disabled",
+			expectRoutes:  0,
+			disableRoutes: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(DocxTemplate)
+			mod.disableRoutes = tc.disableRoutes
+
+			routes, err := mod.Routes()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectRoutes != len(routes) {
+				t.Errorf("expected %d routes but got %d", tc.expectRoutes, len(routes))
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package docxtemplate
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// emusPerPixel is the number of English Metric Units per pixel, at 96
+	// DPI.
+	emusPerPixel = 9525
+
+	// emusPerCm is the number of English Metric Units per centimeter.
+	emusPerCm = 360000
+)
+
+// imageContentTypes are the content types of the supported images.
+var imageContentTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+}
+
+// mediaImage is an image embedded in the DOCX file.
+type mediaImage struct {
+	target        string
+	width, height int
+}
+
+// imageEmbedder embeds the uploaded images in a DOCX file.
+type imageEmbedder struct {
+	doc *docx
+	// paths are the paths of the uploaded images, by filename.
+	paths map[string]string
+	// media are the images already embedded, by filename.
+	media map[string]mediaImage
+	// ids are the relationship IDs of the embedded images, by part and
+	// filename.
+	ids map[string]map[string]string
+	// part is the part currently filled.
+	part     string
+	count    int
+	drawings int
+}
+
+func newImageEmbedder(doc *docx, imagePaths []string) *imageEmbedder {
+	paths := make(map[string]string, len(imagePaths))
+	for _, path := range imagePaths {
+		paths[filepath.Base(path)] = path
+	}
+
+	return &imageEmbedder{
+		doc:   doc,
+		paths: paths,
+		media: make(map[string]mediaImage),
+		ids:   make(map[string]map[string]string),
+	}
+}
+
+// embed adds an uploaded image to the current part, if not already added,
+// and returns its relationship ID and its size, in pixels.
+func (embedder *imageEmbedder) embed(filename string) (string, mediaImage, error) {
+	img, ok := embedder.media[filename]
+	if !ok {
+		path, ok := embedder.paths[filename]
+		if !ok {
+			return "", mediaImage{}, fmt.Errorf("image '%s' not found in the form files", filename)
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", mediaImage{}, fmt.Errorf("read image '%s': %w", filename, err)
+		}
+
+		config, _, err := image.DecodeConfig(bytes.NewReader(b))
+		if err != nil {
+			return "", mediaImage{}, fmt.Errorf("decode image '%s': %w", filename, err)
+		}
+
+		extension := strings.ToLower(filepath.Ext(filename))
+		embedder.count++
+
+		img = mediaImage{
+			target: fmt.Sprintf("media/gotenberg%d%s", embedder.count, extension),
+			width:  config.Width,
+			height: config.Height,
+		}
+
+		embedder.doc.set("word/"+img.target, b)
+		embedder.doc.addContentType(strings.TrimPrefix(extension, "."), imageContentTypes[extension])
+		embedder.media[filename] = img
+	}
+
+	ids, ok := embedder.ids[embedder.part]
+	if !ok {
+		ids = make(map[string]string)
+		embedder.ids[embedder.part] = ids
+	}
+
+	id, ok := ids[filename]
+	if !ok {
+		id = embedder.doc.addRelationship(
+			embedder.part,
+			"http://schemas.openxmlformats.org/officeDocument/2006/relationships/image",
+			img.target,
+		)
+		ids[filename] = id
+	}
+
+	return id, img, nil
+}
+
+// image is the "image" template function. It displays an uploaded image,
+// with its natural size at 96 DPI or with the given size, in centimeters. If
+// only the width is given, the height keeps the aspect ratio.
+func (embedder *imageEmbedder) image(filename string, size ...float64) (rawXml, error) {
+	if len(size) > 2 {
+		return "", fmt.Errorf("image '%s': expected at most a width and a height, got %d sizes", filename, len(size))
+	}
+
+	id, img, err := embedder.embed(filename)
+	if err != nil {
+		return "", err
+	}
+
+	width := int64(img.width) * emusPerPixel
+	height := int64(img.height) * emusPerPixel
+
+	if len(size) > 0 {
+		if size[0] <= 0 || (len(size) == 2 && size[1] <= 0) {
+			return "", fmt.Errorf("image '%s': sizes must be positive", filename)
+		}
+
+		width = int64(size[0] * emusPerCm)
+		height = width * int64(max(img.height, 1)) / int64(max(img.width, 1))
+
+		if len(size) == 2 {
+			height = int64(size[1] * emusPerCm)
+		}
+	}
+
+	embedder.drawings++
+
+	// The action is within a w:t element, within a w:r element: the drawing
+	// requires its own run.
+	return rawXml(fmt.Sprintf(
+		`</w:t></w:r><w:r><w:drawing>`+
+			`<wp:inline xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing" distT="0" distB="0" distL="0" distR="0">`+
+			`<wp:extent cx="%[1]d" cy="%[2]d"/>`+
+			`<wp:docPr id="%[3]d" name="Picture %[3]d"/>`+
+			`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">`+
+			`<a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+			`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+			`<pic:nvPicPr><pic:cNvPr id="%[3]d" name="%[4]s"/><pic:cNvPicPr/></pic:nvPicPr>`+
+			`<pic:blipFill><a:blip xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" r:embed="%[5]s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+			`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%[1]d" cy="%[2]d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>`+
+			`</pic:pic></a:graphicData></a:graphic></wp:inline>`+
+			`</w:drawing></w:r><w:r><w:t xml:space="preserve">`,
+		width, height, 10000+embedder.drawings, escapeXml(filename), id,
+	)), nil
+}
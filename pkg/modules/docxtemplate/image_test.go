@@ -0,0 +1,164 @@
+package docxtemplate
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestPng writes a PNG image of the given size.
+func writeTestPng(t *testing.T, path string, width, height int) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	err = png.Encode(f, image.NewGray(image.Rect(0, 0, width, height)))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestImageEmbedder_Image(t *testing.T) {
+	dirPath := t.TempDir()
+	pngPath := filepath.Join(dirPath, "logo.png")
+	writeTestPng(t, pngPath, 100, 50)
+
+	invalidPath := filepath.Join(dirPath, "invalid.png")
+	err := os.WriteFile(invalidPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		filename     string
+		size         []float64
+		expectExtent string
+		expectError  bool
+	}{
+		{
+			scenario:    "image not found",
+			filename:    "foo.png",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid image",
+			filename:    "invalid.png",
+			expectError: true,
+		},
+		{
+			scenario:    "too many sizes",
+			filename:    "logo.png",
+			size:        []float64{1, 2, 3},
+			expectError: true,
+		},
+		{
+			scenario:    "negative size",
+			filename:    "logo.png",
+			size:        []float64{-1},
+			expectError: true,
+		},
+		{
+			scenario:     "natural size",
+			filename:     "logo.png",
+			expectExtent: `<wp:extent cx="952500" cy="476250"/>`,
+		},
+		{
+			scenario:     "width only",
+			filename:     "logo.png",
+			size:         []float64{4},
+			expectExtent: `<wp:extent cx="1440000" cy="720000"/>`,
+		},
+		{
+			scenario:     "width and height",
+			filename:     "logo.png",
+			size:         []float64{4, 4},
+			expectExtent: `<wp:extent cx="1440000" cy="1440000"/>`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			doc := &docx{files: make(map[string][]byte)}
+			doc.set("[Content_Types].xml", []byte(testContentTypes))
+
+			embedder := newImageEmbedder(doc, []string{pngPath, invalidPath})
+			embedder.part = "word/document.xml"
+
+			actual, err := embedder.image(tc.filename, tc.size...)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				return
+			}
+
+			if !strings.Contains(string(actual), tc.expectExtent) {
+				t.Errorf("expected '%s' to contain '%s'", actual, tc.expectExtent)
+			}
+
+			if !strings.Contains(string(actual), `r:embed="rIdGotenberg1"`) {
+				t.Errorf("expected '%s' to refer to 'rIdGotenberg1'", actual)
+			}
+
+			if _, ok := doc.files["word/media/gotenberg1.png"]; !ok {
+				t.Error("expected 'word/media/gotenberg1.png' to be embedded")
+			}
+
+			if !strings.Contains(string(doc.files["[Content_Types].xml"]), `Extension="png"`) {
+				t.Error("expected the 'png' content type to be declared")
+			}
+		})
+	}
+}
+
+func TestImageEmbedder_Embed(t *testing.T) {
+	pngPath := filepath.Join(t.TempDir(), "logo.png")
+	writeTestPng(t, pngPath, 10, 10)
+
+	doc := &docx{files: make(map[string][]byte)}
+	embedder := newImageEmbedder(doc, []string{pngPath})
+
+	var ids []string
+	for _, part := range []string{"word/document.xml", "word/document.xml", "word/header1.xml"} {
+		embedder.part = part
+
+		id, _, err := embedder.embed("logo.png")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if ids[0] != ids[1] {
+		t.Errorf("expected the same relationship within a part, got '%s' and '%s'", ids[0], ids[1])
+	}
+
+	if _, ok := doc.files["word/_rels/header1.xml.rels"]; !ok {
+		t.Error("expected a relationship for the header part")
+	}
+
+	media := 0
+	for _, name := range doc.names {
+		if strings.HasPrefix(name, "word/media/") {
+			media++
+		}
+	}
+
+	if media != 1 {
+		t.Errorf("expected the image to be embedded once, got %d times", media)
+	}
+}
@@ -0,0 +1,276 @@
+package docxtemplate
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// xmlTag matches the tags of an XML document, except the declaration and
+// the comments.
+var xmlTag = regexp.MustCompile(`<(/?)([A-Za-z_][\w:.-]*)[^>]*?(/?)>`)
+
+// action matches a template action, e.g., "{{ .name }}".
+var action = regexp.MustCompile(`\{\{.*?\}\}`)
+
+// unpreservedAction matches a w:t element which contains an action, but
+// which does not preserve its spaces.
+var unpreservedAction = regexp.MustCompile(`<w:t>([^<]*\{\{)`)
+
+// blockAction matches an action which applies to its whole paragraph or
+// table row, e.g., "{{tr range .items }}".
+var blockAction = regexp.MustCompile(`\{\{(-?\s*)(p|tr)\s+(.*?)\}\}`)
+
+// element is an element of an XML document, from the start of its opening
+// tag to the end of its closing tag.
+type element struct {
+	name       string
+	start, end int
+}
+
+// textNode is the content of a w:t element.
+type textNode struct {
+	start, end int
+	// paragraph is the index of the innermost w:p element containing the
+	// node, in the elements.
+	paragraph int
+	// ancestors are the indexes of the elements containing the node.
+	ancestors []int
+}
+
+// scan lists the elements and the text nodes of an XML document.
+func scan(doc string) ([]element, []textNode) {
+	var (
+		elements []element
+		nodes    []textNode
+		stack    []int
+	)
+
+	for _, match := range xmlTag.FindAllStringSubmatchIndex(doc, -1) {
+		closing := match[3] > match[2]
+		name := doc[match[4]:match[5]]
+		selfClosing := match[7] > match[6]
+
+		switch {
+		case closing:
+			// Pop up to the matching element, if any.
+			for i := len(stack) - 1; i >= 0; i-- {
+				if elements[stack[i]].name != name {
+					continue
+				}
+
+				elements[stack[i]].end = match[1]
+				stack = stack[:i]
+				break
+			}
+		case selfClosing:
+		default:
+			if name == "w:t" && len(stack) > 0 {
+				// The content is up to the closing tag.
+				end := strings.Index(doc[match[1]:], "</w:t>")
+				if end >= 0 {
+					paragraph := -1
+					for i := len(stack) - 1; i >= 0; i-- {
+						if elements[stack[i]].name == "w:p" {
+							paragraph = stack[i]
+							break
+						}
+					}
+
+					nodes = append(nodes, textNode{
+						start:     match[1],
+						end:       match[1] + end,
+						paragraph: paragraph,
+						ancestors: append([]int(nil), stack...),
+					})
+				}
+			}
+
+			elements = append(elements, element{name: name, start: match[0]})
+			stack = append(stack, len(elements)-1)
+		}
+	}
+
+	return elements, nodes
+}
+
+// replacement replaces a range of an XML document.
+type replacement struct {
+	start, end int
+	value      string
+}
+
+// apply applies non-overlapping replacements to an XML document.
+func apply(doc string, replacements []replacement) string {
+	sort.Slice(replacements, func(i, j int) bool {
+		return replacements[i].start < replacements[j].start
+	})
+
+	var b strings.Builder
+	previous := 0
+
+	for _, r := range replacements {
+		b.WriteString(doc[previous:r.start])
+		b.WriteString(r.value)
+		previous = r.end
+	}
+
+	b.WriteString(doc[previous:])
+
+	return b.String()
+}
+
+// tagReplacer restores the characters Word escapes or auto-corrects within
+// the actions.
+var tagReplacer = strings.NewReplacer(
+	"&quot;", `"`,
+	"&apos;", "'",
+	"&lt;", "<",
+	"&gt;", ">",
+	"&amp;", "&",
+	"“", `"`,
+	"”", `"`,
+	"‘", "'",
+	"’", "'",
+)
+
+// normalize prepares an XML part of a DOCX file for the template engine.
+//
+// Word splits the text of a paragraph into runs, according to the
+// formatting, the spell checking, or the editing history, so that an action
+// may span several w:t elements. normalize moves each action into the w:t
+// element in which it starts. Then, it replaces the paragraphs and table rows
+// with block actions, e.g., "{{tr range .items }}", by their actions.
+func normalize(doc string) string {
+	_, nodes := scan(doc)
+
+	byParagraph := make(map[int][]int)
+	var paragraphs []int
+	for i, node := range nodes {
+		if _, ok := byParagraph[node.paragraph]; !ok {
+			paragraphs = append(paragraphs, node.paragraph)
+		}
+		byParagraph[node.paragraph] = append(byParagraph[node.paragraph], i)
+	}
+
+	contents := make([]string, len(nodes))
+	changed := make([]bool, len(nodes))
+	for i, node := range nodes {
+		contents[i] = doc[node.start:node.end]
+	}
+
+	for _, paragraph := range paragraphs {
+		indexes := byParagraph[paragraph]
+
+		// The text of the paragraph, and for each of its characters, the
+		// node and the offset within the node.
+		var (
+			text    strings.Builder
+			owners  []int
+			offsets []int
+		)
+
+		for _, index := range indexes {
+			text.WriteString(contents[index])
+			for offset := 0; offset < len(contents[index]); offset++ {
+				owners = append(owners, index)
+				offsets = append(offsets, offset)
+			}
+		}
+
+		matches := action.FindAllStringIndex(text.String(), -1)
+
+		// From the last action to the first one, so that the offsets of the
+		// previous actions remain valid.
+		for i := len(matches) - 1; i >= 0; i-- {
+			start, end := matches[i][0], matches[i][1]
+			first, last := owners[start], owners[end-1]
+			tag := tagReplacer.Replace(text.String()[start:end])
+
+			if first == last {
+				contents[first] = contents[first][:offsets[start]] + tag + contents[first][offsets[end-1]+1:]
+				changed[first] = true
+				continue
+			}
+
+			contents[first] = contents[first][:offsets[start]] + tag
+			changed[first] = true
+
+			for _, index := range indexes {
+				if index > first && index < last {
+					contents[index] = ""
+					changed[index] = true
+				}
+			}
+
+			contents[last] = contents[last][offsets[end-1]+1:]
+			changed[last] = true
+		}
+	}
+
+	var replacements []replacement
+	for i, node := range nodes {
+		if !changed[i] {
+			continue
+		}
+
+		replacements = append(replacements, replacement{start: node.start, end: node.end, value: contents[i]})
+	}
+
+	doc = apply(doc, replacements)
+
+	// The output of the actions may start or end with spaces.
+	doc = unpreservedAction.ReplaceAllString(doc, `<w:t xml:space="preserve">$1`)
+
+	return replaceBlocks(doc)
+}
+
+// replaceBlocks replaces the paragraphs and table rows with block actions by
+// their actions, without the "p" or "tr" prefix. A block within another
+// block is replaced along with it, so that its actions are not lost.
+func replaceBlocks(doc string) string {
+	elements, nodes := scan(doc)
+
+	var replacements []replacement
+
+	for _, node := range nodes {
+		for _, match := range blockAction.FindAllStringSubmatch(doc[node.start:node.end], -1) {
+			// The innermost ancestor of the expected kind.
+			target := -1
+			for i := len(node.ancestors) - 1; i >= 0; i-- {
+				if elements[node.ancestors[i]].name == "w:"+match[2] {
+					target = node.ancestors[i]
+					break
+				}
+			}
+
+			if target < 0 || elements[target].end == 0 {
+				continue
+			}
+
+			r := replacement{
+				start: elements[target].start,
+				end:   elements[target].end,
+				value: "{{" + match[1] + match[3] + "}}",
+			}
+
+			// The actions come in the order of the document, and elements
+			// do not overlap unless one contains the other.
+			for len(replacements) > 0 {
+				last := replacements[len(replacements)-1]
+				if last.end <= r.start {
+					break
+				}
+
+				r.start = min(r.start, last.start)
+				r.end = max(r.end, last.end)
+				r.value = last.value + r.value
+				replacements = replacements[:len(replacements)-1]
+			}
+
+			replacements = append(replacements, r)
+		}
+	}
+
+	return apply(doc, replacements)
+}
@@ -0,0 +1,69 @@
+package docxtemplate
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		doc      string
+		expect   string
+	}{
+		{
+			scenario: "no actions",
+			doc:      `<w:p><w:r><w:t>foo</w:t></w:r></w:p>`,
+			expect:   `<w:p><w:r><w:t>foo</w:t></w:r></w:p>`,
+		},
+		{
+			scenario: "action within a single run",
+			doc:      `<w:p><w:r><w:t>Dear {{ .name }},</w:t></w:r></w:p>`,
+			expect:   `<w:p><w:r><w:t xml:space="preserve">Dear {{ .name }},</w:t></w:r></w:p>`,
+		},
+		{
+			scenario: "action split across runs",
+			doc:      `<w:p><w:r><w:t>Dear {{ .na</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t>m</w:t></w:r><w:r><w:t>e }},</w:t></w:r></w:p>`,
+			expect:   `<w:p><w:r><w:t xml:space="preserve">Dear {{ .name }}</w:t></w:r><w:r><w:rPr><w:b/></w:rPr><w:t></w:t></w:r><w:r><w:t>,</w:t></w:r></w:p>`,
+		},
+		{
+			scenario: "several actions split across runs",
+			doc:      `<w:p><w:r><w:t>{{ .a }}{</w:t></w:r><w:r><w:t>{ .b }} {{ .c</w:t></w:r><w:r><w:t> }}</w:t></w:r></w:p>`,
+			expect:   `<w:p><w:r><w:t xml:space="preserve">{{ .a }}{{ .b }}</w:t></w:r><w:r><w:t xml:space="preserve"> {{ .c }}</w:t></w:r><w:r><w:t></w:t></w:r></w:p>`,
+		},
+		{
+			scenario: "escaped and auto-corrected characters within actions",
+			doc:      `<w:p><w:r><w:t>{{ if eq .a “x” }}&amp;{{ end }}{{ printf &quot;%s&quot; .b }}</w:t></w:r></w:p>`,
+			expect:   `<w:p><w:r><w:t xml:space="preserve">{{ if eq .a "x" }}&amp;{{ end }}{{ printf "%s" .b }}</w:t></w:r></w:p>`,
+		},
+		{
+			scenario: "paragraph block actions",
+			doc:      `<w:p><w:r><w:t>{{p if .a }}</w:t></w:r></w:p><w:p><w:r><w:t>foo</w:t></w:r></w:p><w:p><w:r><w:t>{{p end }}</w:t></w:r></w:p>`,
+			expect:   `{{if .a }}<w:p><w:r><w:t>foo</w:t></w:r></w:p>{{end }}`,
+		},
+		{
+			scenario: "table row block actions",
+			doc:      `<w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{tr range .items }}</w:t></w:r></w:p></w:tc></w:tr><w:tr><w:tc><w:p><w:r><w:t>{{ .name }}</w:t></w:r></w:p></w:tc></w:tr><w:tr><w:tc><w:p><w:r><w:t>{{tr end }}</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`,
+			expect:   `<w:tbl>{{range .items }}<w:tr><w:tc><w:p><w:r><w:t xml:space="preserve">{{ .name }}</w:t></w:r></w:p></w:tc></w:tr>{{end }}</w:tbl>`,
+		},
+		{
+			scenario: "paragraph block action within a replaced table row",
+			doc:      `<w:tr><w:tc><w:p><w:r><w:t>{{tr if .a }}{{p if .b }}</w:t></w:r></w:p></w:tc></w:tr>`,
+			expect:   `{{if .a }}{{if .b }}`,
+		},
+		{
+			scenario: "table row block action after paragraph block actions",
+			doc:      `<w:p><w:r><w:t>{{p if .a }}</w:t></w:r></w:p><w:tr><w:tc><w:p><w:r><w:t>{{p if .b }}</w:t></w:r></w:p><w:p><w:r><w:t>{{p end }}</w:t></w:r></w:p><w:p><w:r><w:t>{{tr end }}</w:t></w:r></w:p></w:tc></w:tr>`,
+			expect:   `{{if .a }}{{if .b }}{{end }}{{end }}`,
+		},
+		{
+			scenario: "block action split across runs",
+			doc:      `<w:p><w:r><w:t>{{</w:t></w:r><w:r><w:t>p if .a }}</w:t></w:r></w:p>`,
+			expect:   `{{if .a }}`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := normalize(tc.doc)
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package docxtemplate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+// convertRoute returns an [api.Route] which can fill DOCX templates with JSON
+// data and convert the results to PDF. The images the templates display are
+// form files too.
+func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/docxtemplate/convert",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths       []string
+				imagePaths       []string
+				data             interface{}
+				landscape        bool
+				nativePageRanges string
+				pdfa             string
+				pdfua            bool
+				merge            bool
+			)
+
+			imageExtensions := make([]string, 0, len(imageContentTypes))
+			for extension := range imageContentTypes {
+				imageExtensions = append(imageExtensions, extension)
+			}
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".docx"}, &inputPaths).
+				Paths(imageExtensions, &imagePaths).
+				Custom("data", func(value string) error {
+					if value == "" {
+						data = map[string]interface{}{}
+						return nil
+					}
+
+					err := json.Unmarshal([]byte(value), &data)
+					if err != nil {
+						return fmt.Errorf("unmarshal data: %w", err)
+					}
+
+					return nil
+				}).
+				Bool("landscape", &landscape, false).
+				String("nativePageRanges", &nativePageRanges, "").
+				String("pdfa", &pdfa, "").
+				Bool("pdfua", &pdfua, false).
+				Bool("merge", &merge, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			pdfFormats := gotenberg.PdfFormats{
+				PdfA:  pdfa,
+				PdfUa: pdfua,
+			}
+
+			merge = merge && len(inputPaths) > 1
+
+			options := libreofficeapi.Options{
+				Landscape:  landscape,
+				PageRanges: nativePageRanges,
+			}
+
+			// The PDF engine converts the merged PDF instead.
+			if !merge {
+				options.PdfFormats = pdfFormats
+			}
+
+			// Alright, let's fill the templates and convert them to PDF.
+			ctx.AddEngines("libreoffice")
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				doc, err := readDocx(inputPath)
+				if err == nil {
+					err = fill(doc, data, imagePaths)
+				}
+				if err != nil {
+					if errors.Is(err, ErrInvalidTemplate) {
+						return api.WrapError(
+							fmt.Errorf("fill template: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("The template '%s' is invalid: %s", filepath.Base(inputPath), err),
+							).WithCode(api.ErrorCodeDocxtemplateInvalidTemplate),
+						)
+					}
+
+					return fmt.Errorf("fill template: %w", err)
+				}
+
+				docxPath := ctx.GeneratePath("", ".docx")
+
+				err = doc.write(docxPath)
+				if err != nil {
+					return fmt.Errorf("write filled template: %w", err)
+				}
+
+				// document.docx -> document.docx.pdf.
+				outputPaths[i] = ctx.GeneratePath(filepath.Base(inputPath), ".pdf")
+
+				err = libreOffice.Pdf(ctx, ctx.Log(), docxPath, outputPaths[i], options)
+				if err != nil {
+					if errors.Is(err, libreofficeapi.ErrInvalidPdfFormats) {
+						return api.WrapError(
+							fmt.Errorf("convert to PDF: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("A PDF format in '%+v' is not supported", pdfFormats),
+							).WithCode(api.ErrorCodeLibreofficeInvalidPdfFormats),
+						)
+					}
+
+					if errors.Is(err, libreofficeapi.ErrMalformedPageRanges) {
+						return api.WrapError(
+							fmt.Errorf("convert to PDF: %w", err),
+							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)).WithCode(api.ErrorCodeLibreofficeMalformedPageRanges),
+						)
+					}
+
+					return fmt.Errorf("convert to PDF: %w", err)
+				}
+			}
+
+			if !merge {
+				err = ctx.AddOutputPaths(outputPaths...)
+				if err != nil {
+					return fmt.Errorf("add output paths: %w", err)
+				}
+
+				return nil
+			}
+
+			outputPath := ctx.GeneratePath("", ".pdf")
+
+			err = engine.Merge(ctx, ctx.Log(), outputPaths, outputPath)
+			if err != nil {
+				return fmt.Errorf("merge PDFs: %w", err)
+			}
+
+			zeroValued := gotenberg.PdfFormats{}
+			if pdfFormats != zeroValued {
+				convertInputPath := outputPath
+				convertOutputPath := ctx.GeneratePath("", ".pdf")
+
+				err = engine.Convert(ctx, ctx.Log(), pdfFormats, convertInputPath, convertOutputPath)
+				if err != nil {
+					return fmt.Errorf("convert PDF: %w", err)
+				}
+
+				// Important: the output path is now the converted file.
+				outputPath = convertOutputPath
+			}
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
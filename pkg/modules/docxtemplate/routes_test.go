@@ -0,0 +1,219 @@
+package docxtemplate
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestConvertRoute(t *testing.T) {
+	validBody := `<w:p><w:r><w:t>Dear {{ .name }},</w:t></w:r></w:p>`
+
+	newContext := func(bodies map[string]string, values map[string][]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string)
+
+		for filename, body := range bodies {
+			path := filepath.Join(dirPath, filename)
+			writeTestDocx(t, path,
+				"[Content_Types].xml", testContentTypes,
+				"word/document.xml", testDocument(body),
+			)
+
+			paths[filename] = path
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetFiles(paths)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	libreOffice := func(err error) libreofficeapi.Uno {
+		return &libreofficeapi.ApiMock{
+			PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				if err != nil {
+					return err
+				}
+
+				return os.WriteFile(outputPath, []byte("%PDF"), 0o600)
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		libreOffice            libreofficeapi.Uno
+		engine                 gotenberg.PdfEngine
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newContext(nil, nil),
+			libreOffice:            libreOffice(nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid data form field",
+			ctx:                    newContext(map[string]string{"letter.docx": validBody}, map[string][]string{"data": {"foo"}}),
+			libreOffice:            libreOffice(nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid template",
+			ctx:                    newContext(map[string]string{"letter.docx": `<w:p><w:r><w:t>{{ end }}</w:t></w:r></w:p>`}, nil),
+			libreOffice:            libreOffice(nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "ErrInvalidPdfFormats",
+			ctx:                    newContext(map[string]string{"letter.docx": validBody}, map[string][]string{"pdfa": {"foo"}}),
+			libreOffice:            libreOffice(libreofficeapi.ErrInvalidPdfFormats),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "ErrMalformedPageRanges",
+			ctx:                    newContext(map[string]string{"letter.docx": validBody}, map[string][]string{"nativePageRanges": {"foo"}}),
+			libreOffice:            libreOffice(libreofficeapi.ErrMalformedPageRanges),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from LibreOffice",
+			ctx:                    newContext(map[string]string{"letter.docx": validBody}, nil),
+			libreOffice:            libreOffice(errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:    "PDF engine merge error",
+			ctx:         newContext(map[string]string{"a.docx": validBody, "b.docx": validBody}, map[string][]string{"merge": {"true"}}),
+			libreOffice: libreOffice(nil),
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "PDF engine convert error",
+			ctx: newContext(map[string]string{"a.docx": validBody, "b.docx": validBody}, map[string][]string{
+				"merge": {"true"},
+				"pdfa":  {gotenberg.PdfA1b},
+			}),
+			libreOffice: libreOffice(nil),
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "success",
+			ctx:                    newContext(map[string]string{"a.docx": validBody, "b.docx": validBody}, map[string][]string{"data": {`{"name": "Ada"}`}}),
+			libreOffice:            libreOffice(nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+		{
+			scenario: "success with merge and PDF formats",
+			ctx: newContext(map[string]string{"a.docx": validBody, "b.docx": validBody}, map[string][]string{
+				"data":  {`{"name": "Ada"}`},
+				"merge": {"true"},
+				"pdfa":  {gotenberg.PdfA1b},
+			}),
+			libreOffice: libreOffice(nil),
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return nil
+				},
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertRoute(tc.libreOffice, tc.engine).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package docxtemplate
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// rawXml is a value the template engine writes as is, e.g., an image.
+type rawXml string
+
+// xmlReplacer escapes the text within a w:t element. Line breaks and tabs
+// become the matching Word elements.
+var xmlReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+	"\r\n", `</w:t><w:br/><w:t xml:space="preserve">`,
+	"\n", `</w:t><w:br/><w:t xml:space="preserve">`,
+	"\t", `</w:t><w:tab/><w:t xml:space="preserve">`,
+)
+
+func escapeXml(s string) string {
+	return xmlReplacer.Replace(s)
+}
+
+// escaper is the name of the function which escapes the output of the
+// actions.
+const escaper = "_gotenbergEscapeXml"
+
+// escapeValue is the escaper. Missing values are empty, and numbers do not
+// use the exponent notation.
+func escapeValue(args ...interface{}) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	switch value := args[len(args)-1].(type) {
+	case nil:
+		return ""
+	case rawXml:
+		return string(value)
+	case string:
+		return escapeXml(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return escapeXml(fmt.Sprint(value))
+	}
+}
+
+// escapeTree pipes the output of the actions to the escaper. Actions which
+// only declare or assign variables do not output anything.
+func escapeTree(tree *parse.Tree, node parse.Node) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+
+		for _, child := range node.Nodes {
+			escapeTree(tree, child)
+		}
+	case *parse.ActionNode:
+		if len(node.Pipe.Decl) > 0 {
+			return
+		}
+
+		node.Pipe.Cmds = append(node.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      node.Pos,
+			Args:     []parse.Node{parse.NewIdentifier(escaper).SetTree(tree).SetPos(node.Pos)},
+		})
+	case *parse.IfNode:
+		escapeTree(tree, node.List)
+		escapeTree(tree, node.ElseList)
+	case *parse.RangeNode:
+		escapeTree(tree, node.List)
+		escapeTree(tree, node.ElseList)
+	case *parse.WithNode:
+		escapeTree(tree, node.List)
+		escapeTree(tree, node.ElseList)
+	}
+}
+
+// fill fills the parts of a DOCX template with JSON data. The template
+// syntax is the one of Go's text/template package, plus:
+//
+//   - {{p ...}} and {{tr ...}}, which replace their whole paragraph or table
+//     row by the action, e.g., for repeating a table row with range.
+//   - {{ image "logo.png" [width [height]] }}, which displays an uploaded
+//     image, with sizes in centimeters.
+//
+// The output of the actions is escaped.
+func fill(doc *docx, data interface{}, imagePaths []string) error {
+	embedder := newImageEmbedder(doc, imagePaths)
+
+	funcs := template.FuncMap{
+		escaper: escapeValue,
+		"image": embedder.image,
+	}
+
+	for _, part := range doc.templateParts() {
+		tmpl, err := template.New(part).Funcs(funcs).Parse(normalize(string(doc.files[part])))
+		if err != nil {
+			return fmt.Errorf("parse '%s': %v: %w", part, err, ErrInvalidTemplate)
+		}
+
+		for _, t := range tmpl.Templates() {
+			if t.Tree != nil {
+				escapeTree(t.Tree, t.Tree.Root)
+			}
+		}
+
+		embedder.part = part
+
+		var buf bytes.Buffer
+		err = tmpl.Execute(&buf, data)
+		if err != nil {
+			return fmt.Errorf("execute '%s': %v: %w", part, err, ErrInvalidTemplate)
+		}
+
+		doc.set(part, buf.Bytes())
+	}
+
+	return nil
+}
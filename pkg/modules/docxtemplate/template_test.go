@@ -0,0 +1,123 @@
+package docxtemplate
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEscapeValue(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		args     []interface{}
+		expect   string
+	}{
+		{scenario: "no arguments", expect: ""},
+		{scenario: "nil", args: []interface{}{nil}, expect: ""},
+		{scenario: "raw XML", args: []interface{}{rawXml("<w:br/>")}, expect: "<w:br/>"},
+		{scenario: "string", args: []interface{}{`a < b & "c"`}, expect: "a &lt; b &amp; &quot;c&quot;"},
+		{scenario: "line breaks", args: []interface{}{"a\nb"}, expect: `a</w:t><w:br/><w:t xml:space="preserve">b`},
+		{scenario: "number", args: []interface{}{1000000.0}, expect: "1000000"},
+		{scenario: "boolean", args: []interface{}{true}, expect: "true"},
+		{scenario: "last argument", args: []interface{}{"a", "b"}, expect: "b"},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := escapeValue(tc.args...)
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestFill(t *testing.T) {
+	pngPath := filepath.Join(t.TempDir(), "logo.png")
+	writeTestPng(t, pngPath, 10, 10)
+
+	for _, tc := range []struct {
+		scenario       string
+		body           string
+		header         string
+		data           interface{}
+		expectBody     string
+		expectHeader   string
+		expectError    bool
+		expectTemplate bool
+	}{
+		{
+			scenario:       "invalid syntax",
+			body:           `<w:p><w:r><w:t>{{ if .a }}</w:t></w:r></w:p>`,
+			expectError:    true,
+			expectTemplate: true,
+		},
+		{
+			scenario:       "execution error",
+			body:           `<w:p><w:r><w:t>{{ image "foo.png" }}</w:t></w:r></w:p>`,
+			expectError:    true,
+			expectTemplate: true,
+		},
+		{
+			scenario:   "values, missing values, and escaping",
+			body:       `<w:p><w:r><w:t>{{ .name }}, {{ .missing }}{{ $total := .total }}{{ $total }}</w:t></w:r></w:p>`,
+			data:       map[string]interface{}{"name": "<Ada>", "total": 12.5},
+			expectBody: `<w:p><w:r><w:t xml:space="preserve">&lt;Ada&gt;, 12.5</w:t></w:r></w:p>`,
+		},
+		{
+			scenario: "loops and conditions",
+			body: `<w:tbl><w:tr><w:tc><w:p><w:r><w:t>{{tr range .items }}</w:t></w:r></w:p></w:tc></w:tr>` +
+				`<w:tr><w:tc><w:p><w:r><w:t>{{ .name }}{{ if .paid }} (paid){{ end }}</w:t></w:r></w:p></w:tc></w:tr>` +
+				`<w:tr><w:tc><w:p><w:r><w:t>{{tr end }}</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`,
+			data: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"name": "a", "paid": true},
+				map[string]interface{}{"name": "b", "paid": false},
+			}},
+			expectBody: `<w:tbl><w:tr><w:tc><w:p><w:r><w:t xml:space="preserve">a (paid)</w:t></w:r></w:p></w:tc></w:tr>` +
+				`<w:tr><w:tc><w:p><w:r><w:t xml:space="preserve">b</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`,
+		},
+		{
+			scenario:     "image in header",
+			body:         `<w:p><w:r><w:t>foo</w:t></w:r></w:p>`,
+			header:       `<w:hdr><w:p><w:r><w:t>{{ image .logo }}</w:t></w:r></w:p></w:hdr>`,
+			data:         map[string]interface{}{"logo": "logo.png"},
+			expectBody:   `<w:p><w:r><w:t>foo</w:t></w:r></w:p>`,
+			expectHeader: `r:embed="rIdGotenberg1"`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			doc := &docx{files: make(map[string][]byte)}
+			doc.set("[Content_Types].xml", []byte(testContentTypes))
+			doc.set("word/document.xml", []byte(testDocument(tc.body)))
+			if tc.header != "" {
+				doc.set("word/header1.xml", []byte(tc.header))
+			}
+
+			err := fill(doc, tc.data, []string{pngPath})
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectTemplate && !errors.Is(err, ErrInvalidTemplate) {
+				t.Fatalf("expected error %v but got: %v", ErrInvalidTemplate, err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			actual := string(doc.files["word/document.xml"])
+			if actual != testDocument(tc.expectBody) {
+				t.Errorf("expected '%s' but got '%s'", testDocument(tc.expectBody), actual)
+			}
+
+			if tc.expectHeader != "" && !strings.Contains(string(doc.files["word/header1.xml"]), tc.expectHeader) {
+				t.Errorf("expected '%s' to contain '%s'", doc.files["word/header1.xml"], tc.expectHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package email
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf16"
+)
+
+// The Compound File Binary format is the container of the Outlook .msg
+// files. This is a minimal, read-only implementation, which loads the whole
+// file in memory.
+//
+// See https://learn.microsoft.com/en-us/openspecs/windows_protocols/ms-cfb.
+
+var cfbSignature = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
+
+const (
+	cfbHeaderSize    = 512
+	cfbDirEntrySize  = 128
+	cfbDifatInHeader = 109
+
+	cfbEndOfChain = 0xFFFFFFFE
+	cfbFreeSector = 0xFFFFFFFF
+	cfbNoStream   = 0xFFFFFFFF
+
+	cfbTypeStorage = 1
+	cfbTypeStream  = 2
+	cfbTypeRoot    = 5
+)
+
+// ErrMalformedCompoundFile happens if a file is not a valid Compound File
+// Binary.
+var ErrMalformedCompoundFile = errors.New("malformed compound file")
+
+// cfbEntry is a directory entry, i.e., either a storage or a stream.
+type cfbEntry struct {
+	name        string
+	kind        byte
+	left        uint32
+	right       uint32
+	child       uint32
+	startSector uint32
+	size        uint64
+}
+
+// cfbFile is a parsed Compound File Binary.
+type cfbFile struct {
+	data          []byte
+	sectorSize    int
+	miniSize      int
+	miniCutoff    uint64
+	fat           []uint32
+	miniFat       []uint32
+	miniStream    []byte
+	entries       []cfbEntry
+	maxChainSteps int
+}
+
+// parseCfb parses a Compound File Binary.
+func parseCfb(data []byte) (*cfbFile, error) {
+	if len(data) < cfbHeaderSize || !bytes.Equal(data[:8], cfbSignature) {
+		return nil, fmt.Errorf("invalid signature: %w", ErrMalformedCompoundFile)
+	}
+
+	sectorShift := binary.LittleEndian.Uint16(data[0x1E:])
+	miniShift := binary.LittleEndian.Uint16(data[0x20:])
+	if (sectorShift != 9 && sectorShift != 12) || miniShift != 6 {
+		return nil, fmt.Errorf("invalid sector sizes: %w", ErrMalformedCompoundFile)
+	}
+
+	f := &cfbFile{
+		data:       data,
+		sectorSize: 1 << sectorShift,
+		miniSize:   1 << miniShift,
+		miniCutoff: uint64(binary.LittleEndian.Uint32(data[0x38:])),
+	}
+
+	// A chain cannot be longer than the number of sectors of the file.
+	f.maxChainSteps = len(data)/f.sectorSize + 1
+
+	err := f.readFat()
+	if err != nil {
+		return nil, fmt.Errorf("read FAT: %w", err)
+	}
+
+	dir, err := f.readChain(binary.LittleEndian.Uint32(data[0x30:]), f.fat, f.sector)
+	if err != nil {
+		return nil, fmt.Errorf("read directory: %w", err)
+	}
+
+	for offset := 0; offset+cfbDirEntrySize <= len(dir); offset += cfbDirEntrySize {
+		f.entries = append(f.entries, parseCfbEntry(dir[offset:offset+cfbDirEntrySize]))
+	}
+
+	if len(f.entries) == 0 || f.entries[0].kind != cfbTypeRoot {
+		return nil, fmt.Errorf("no root entry: %w", ErrMalformedCompoundFile)
+	}
+
+	miniFat, err := f.readChain(binary.LittleEndian.Uint32(data[0x3C:]), f.fat, f.sector)
+	if err != nil {
+		return nil, fmt.Errorf("read mini FAT: %w", err)
+	}
+
+	f.miniFat = toUint32s(miniFat)
+
+	root := f.entries[0]
+	f.miniStream, err = f.readChain(root.startSector, f.fat, f.sector)
+	if err != nil {
+		return nil, fmt.Errorf("read mini stream: %w", err)
+	}
+
+	return f, nil
+}
+
+// readFat reads the sectors of the FAT, as listed by the DIFAT.
+func (f *cfbFile) readFat() error {
+	numFatSectors := int(binary.LittleEndian.Uint32(f.data[0x2C:]))
+	difat := toUint32s(f.data[0x4C:cfbHeaderSize])
+
+	next := binary.LittleEndian.Uint32(f.data[0x44:])
+	for steps := 0; next != cfbEndOfChain && next != cfbFreeSector && len(difat) < numFatSectors; steps++ {
+		if steps > f.maxChainSteps {
+			return fmt.Errorf("DIFAT loop: %w", ErrMalformedCompoundFile)
+		}
+
+		sector, err := f.sector(next)
+		if err != nil {
+			return err
+		}
+
+		// The last entry of a DIFAT sector is the next DIFAT sector.
+		entries := toUint32s(sector)
+		difat = append(difat, entries[:len(entries)-1]...)
+		next = entries[len(entries)-1]
+	}
+
+	if numFatSectors > len(difat) {
+		return fmt.Errorf("missing FAT sectors: %w", ErrMalformedCompoundFile)
+	}
+
+	for _, id := range difat[:numFatSectors] {
+		sector, err := f.sector(id)
+		if err != nil {
+			return err
+		}
+
+		f.fat = append(f.fat, toUint32s(sector)...)
+	}
+
+	return nil
+}
+
+// sector returns the content of a sector.
+func (f *cfbFile) sector(id uint32) ([]byte, error) {
+	offset := (int64(id) + 1) * int64(f.sectorSize)
+	if offset+int64(f.sectorSize) > int64(len(f.data)) {
+		return nil, fmt.Errorf("sector %d out of bounds: %w", id, ErrMalformedCompoundFile)
+	}
+
+	return f.data[offset : offset+int64(f.sectorSize)], nil
+}
+
+// miniSector returns the content of a sector of the mini stream.
+func (f *cfbFile) miniSector(id uint32) ([]byte, error) {
+	offset := int64(id) * int64(f.miniSize)
+	if offset+int64(f.miniSize) > int64(len(f.miniStream)) {
+		return nil, fmt.Errorf("mini sector %d out of bounds: %w", id, ErrMalformedCompoundFile)
+	}
+
+	return f.miniStream[offset : offset+int64(f.miniSize)], nil
+}
+
+// readChain concatenates the sectors of a chain.
+func (f *cfbFile) readChain(start uint32, table []uint32, sector func(id uint32) ([]byte, error)) ([]byte, error) {
+	var buf []byte
+
+	for id, steps := start, 0; id != cfbEndOfChain && id != cfbFreeSector; steps++ {
+		if steps > f.maxChainSteps*(f.sectorSize/f.miniSize) {
+			return nil, fmt.Errorf("chain loop: %w", ErrMalformedCompoundFile)
+		}
+
+		content, err := sector(id)
+		if err != nil {
+			return nil, err
+		}
+
+		buf = append(buf, content...)
+
+		if int(id) >= len(table) {
+			return nil, fmt.Errorf("sector %d out of allocation table: %w", id, ErrMalformedCompoundFile)
+		}
+
+		id = table[id]
+	}
+
+	return buf, nil
+}
+
+// children returns the indexes of the direct children of a storage.
+func (f *cfbFile) children(storage int) []int {
+	var (
+		indexes []int
+		visit   func(id uint32, depth int)
+	)
+
+	visit = func(id uint32, depth int) {
+		if id == cfbNoStream || int(id) >= len(f.entries) || depth > len(f.entries) {
+			return
+		}
+
+		entry := f.entries[id]
+		visit(entry.left, depth+1)
+		indexes = append(indexes, int(id))
+		visit(entry.right, depth+1)
+	}
+
+	visit(f.entries[storage].child, 0)
+
+	return indexes
+}
+
+// stream returns the content of a stream.
+func (f *cfbFile) stream(index int) ([]byte, error) {
+	entry := f.entries[index]
+	if entry.kind != cfbTypeStream {
+		return nil, fmt.Errorf("entry '%s' is not a stream: %w", entry.name, ErrMalformedCompoundFile)
+	}
+
+	var (
+		content []byte
+		err     error
+	)
+
+	if entry.size < f.miniCutoff {
+		content, err = f.readChain(entry.startSector, f.miniFat, f.miniSector)
+	} else {
+		content, err = f.readChain(entry.startSector, f.fat, f.sector)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read stream '%s': %w", entry.name, err)
+	}
+
+	if uint64(len(content)) < entry.size {
+		return nil, fmt.Errorf("truncated stream '%s': %w", entry.name, ErrMalformedCompoundFile)
+	}
+
+	return content[:entry.size], nil
+}
+
+func parseCfbEntry(b []byte) cfbEntry {
+	nameLen := int(binary.LittleEndian.Uint16(b[64:]))
+	if nameLen > 64 {
+		nameLen = 64
+	}
+
+	// The length includes the terminating null character.
+	name := decodeUtf16(b[:nameLen])
+	for len(name) > 0 && name[len(name)-1] == 0 {
+		name = name[:len(name)-1]
+	}
+
+	return cfbEntry{
+		name:        name,
+		kind:        b[66],
+		left:        binary.LittleEndian.Uint32(b[68:]),
+		right:       binary.LittleEndian.Uint32(b[72:]),
+		child:       binary.LittleEndian.Uint32(b[76:]),
+		startSector: binary.LittleEndian.Uint32(b[116:]),
+		// Only the low 32 bits are relevant for version 3 files.
+		size: uint64(binary.LittleEndian.Uint32(b[120:])),
+	}
+}
+
+// decodeUtf16 decodes little-endian UTF-16 bytes.
+func decodeUtf16(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+
+	return string(utf16.Decode(units))
+}
+
+func toUint32s(b []byte) []uint32 {
+	values := make([]uint32, len(b)/4)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+
+	return values
+}
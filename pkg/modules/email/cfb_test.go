@@ -0,0 +1,333 @@
+package email
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"unicode/utf16"
+)
+
+// cfbTestNode is either a stream, or a storage if it has children.
+type cfbTestNode struct {
+	name     string
+	data     []byte
+	storage  bool
+	children []cfbTestNode
+}
+
+// buildCfb builds a version 3 Compound File Binary, with 512 bytes sectors,
+// 64 bytes mini sectors, and a 4096 bytes mini stream cutoff.
+func buildCfb(t *testing.T, nodes []cfbTestNode) []byte {
+	const (
+		sectorSize = 512
+		miniSize   = 64
+		cutoff     = 4096
+		fatSect    = 0xFFFFFFFD
+	)
+
+	type dirEntry struct {
+		name        string
+		kind        byte
+		left, right uint32
+		child       uint32
+		start       uint32
+		size        uint32
+	}
+
+	var (
+		entries    []dirEntry
+		miniStream []byte
+		miniFat    []uint32
+		bigStreams [][]byte
+		bigIndexes []int
+	)
+
+	entries = append(entries, dirEntry{name: "Root Entry", kind: cfbTypeRoot, left: cfbNoStream, right: cfbNoStream, child: cfbNoStream})
+
+	var add func(parent int, children []cfbTestNode)
+	add = func(parent int, children []cfbTestNode) {
+		previous := -1
+		for _, node := range children {
+			index := len(entries)
+			entry := dirEntry{name: node.name, left: cfbNoStream, right: cfbNoStream, child: cfbNoStream, start: cfbEndOfChain}
+
+			if node.storage {
+				entry.kind = cfbTypeStorage
+			} else {
+				entry.kind = cfbTypeStream
+				entry.size = uint32(len(node.data))
+
+				switch {
+				case len(node.data) == 0:
+				case len(node.data) < cutoff:
+					entry.start = uint32(len(miniStream) / miniSize)
+					padded := append([]byte(nil), node.data...)
+					for len(padded)%miniSize != 0 {
+						padded = append(padded, 0)
+					}
+					for i := 0; i < len(padded)/miniSize; i++ {
+						next := uint32(len(miniFat) + 1)
+						if i == len(padded)/miniSize-1 {
+							next = cfbEndOfChain
+						}
+						miniFat = append(miniFat, next)
+					}
+					miniStream = append(miniStream, padded...)
+				default:
+					bigStreams = append(bigStreams, node.data)
+					bigIndexes = append(bigIndexes, index)
+				}
+			}
+
+			entries = append(entries, entry)
+
+			// A degenerated tree: each sibling is on the right of the
+			// previous one.
+			if previous == -1 {
+				entries[parent].child = uint32(index)
+			} else {
+				entries[previous].right = uint32(index)
+			}
+			previous = index
+
+			if node.storage {
+				add(index, node.children)
+			}
+		}
+	}
+
+	add(0, nodes)
+
+	// The chains, in sector order: directory, mini FAT, mini stream, big
+	// streams.
+	dir := make([]byte, 0, len(entries)*cfbDirEntrySize)
+	chains := [][]byte{nil}
+
+	miniFatBytes := make([]byte, len(miniFat)*4)
+	for i, v := range miniFat {
+		binary.LittleEndian.PutUint32(miniFatBytes[i*4:], v)
+	}
+	chains = append(chains, miniFatBytes, miniStream)
+	chains = append(chains, bigStreams...)
+
+	sectorsOf := func(b []byte) int {
+		return (len(b) + sectorSize - 1) / sectorSize
+	}
+
+	dirSectors := (len(entries)*cfbDirEntrySize + sectorSize - 1) / sectorSize
+	total := dirSectors
+	for _, chain := range chains[1:] {
+		total += sectorsOf(chain)
+	}
+
+	numFat := 1
+	for numFat*sectorSize/4 < total+numFat {
+		numFat++
+	}
+
+	fat := make([]uint32, numFat*sectorSize/4)
+	for i := range fat {
+		fat[i] = cfbFreeSector
+	}
+	for i := 0; i < numFat; i++ {
+		fat[i] = fatSect
+	}
+
+	next := uint32(numFat)
+	allocate := func(sectors int) uint32 {
+		if sectors == 0 {
+			return cfbEndOfChain
+		}
+
+		start := next
+		for i := 0; i < sectors; i++ {
+			if i == sectors-1 {
+				fat[next] = cfbEndOfChain
+			} else {
+				fat[next] = next + 1
+			}
+			next++
+		}
+
+		return start
+	}
+
+	dirStart := allocate(dirSectors)
+	miniFatStart := allocate(sectorsOf(miniFatBytes))
+	miniStreamStart := allocate(sectorsOf(miniStream))
+	for i, data := range bigStreams {
+		entries[bigIndexes[i]].start = allocate(sectorsOf(data))
+	}
+
+	entries[0].start = miniStreamStart
+	entries[0].size = uint32(len(miniStream))
+
+	for _, entry := range entries {
+		b := make([]byte, cfbDirEntrySize)
+		units := utf16.Encode([]rune(entry.name))
+		for i, u := range units {
+			binary.LittleEndian.PutUint16(b[i*2:], u)
+		}
+		binary.LittleEndian.PutUint16(b[64:], uint16((len(units)+1)*2))
+		b[66] = entry.kind
+		b[67] = 1
+		binary.LittleEndian.PutUint32(b[68:], entry.left)
+		binary.LittleEndian.PutUint32(b[72:], entry.right)
+		binary.LittleEndian.PutUint32(b[76:], entry.child)
+		binary.LittleEndian.PutUint32(b[116:], entry.start)
+		binary.LittleEndian.PutUint32(b[120:], entry.size)
+		dir = append(dir, b...)
+	}
+
+	header := make([]byte, cfbHeaderSize)
+	copy(header, cfbSignature)
+	binary.LittleEndian.PutUint16(header[0x18:], 0x3E)
+	binary.LittleEndian.PutUint16(header[0x1A:], 3)
+	binary.LittleEndian.PutUint16(header[0x1C:], 0xFFFE)
+	binary.LittleEndian.PutUint16(header[0x1E:], 9)
+	binary.LittleEndian.PutUint16(header[0x20:], 6)
+	binary.LittleEndian.PutUint32(header[0x2C:], uint32(numFat))
+	binary.LittleEndian.PutUint32(header[0x30:], dirStart)
+	binary.LittleEndian.PutUint32(header[0x38:], cutoff)
+	binary.LittleEndian.PutUint32(header[0x3C:], miniFatStart)
+	binary.LittleEndian.PutUint32(header[0x40:], uint32(sectorsOf(miniFatBytes)))
+	binary.LittleEndian.PutUint32(header[0x44:], cfbEndOfChain)
+	for i := 0; i < cfbDifatInHeader; i++ {
+		value := uint32(cfbFreeSector)
+		if i < numFat {
+			value = uint32(i)
+		}
+		binary.LittleEndian.PutUint32(header[0x4C+i*4:], value)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(header)
+
+	fatBytes := make([]byte, len(fat)*4)
+	for i, v := range fat {
+		binary.LittleEndian.PutUint32(fatBytes[i*4:], v)
+	}
+	buf.Write(fatBytes)
+
+	for _, chain := range append([][]byte{dir, miniFatBytes, miniStream}, bigStreams...) {
+		buf.Write(chain)
+		for i := len(chain); i%sectorSize != 0; i++ {
+			buf.WriteByte(0)
+		}
+	}
+
+	if buf.Len() != (int(next)+1)*sectorSize {
+		t.Fatalf("expected %d bytes but got %d", (int(next)+1)*sectorSize, buf.Len())
+	}
+
+	return buf.Bytes()
+}
+
+func TestParseCfb(t *testing.T) {
+	small := []byte("foo")
+	large := bytes.Repeat([]byte("bar"), 2000)
+
+	data := buildCfb(t, []cfbTestNode{
+		{name: "small", data: small},
+		{name: "large", data: large},
+		{
+			name:    "storage",
+			storage: true,
+			children: []cfbTestNode{
+				{name: "nested", data: []byte("baz")},
+			},
+		},
+	})
+
+	f, err := parseCfb(data)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	streams := make(map[string][]byte)
+	var storage int
+
+	for _, index := range f.children(0) {
+		entry := f.entries[index]
+		if entry.kind == cfbTypeStorage {
+			storage = index
+			continue
+		}
+
+		content, err := f.stream(index)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		streams[entry.name] = content
+	}
+
+	if !bytes.Equal(streams["small"], small) {
+		t.Errorf("expected small stream '%s' but got '%s'", small, streams["small"])
+	}
+
+	if !bytes.Equal(streams["large"], large) {
+		t.Errorf("expected large stream of %d bytes but got %d bytes", len(large), len(streams["large"]))
+	}
+
+	if storage == 0 || f.entries[storage].name != "storage" {
+		t.Fatal("expected a storage")
+	}
+
+	children := f.children(storage)
+	if len(children) != 1 {
+		t.Fatalf("expected one nested stream but got %d", len(children))
+	}
+
+	nested, err := f.stream(children[0])
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if string(nested) != "baz" {
+		t.Errorf("expected nested stream 'baz' but got '%s'", nested)
+	}
+
+	_, err = f.stream(storage)
+	if !errors.Is(err, ErrMalformedCompoundFile) {
+		t.Errorf("expected ErrMalformedCompoundFile but got: %v", err)
+	}
+}
+
+func TestParseCfb_malformed(t *testing.T) {
+	valid := buildCfb(t, []cfbTestNode{{name: "foo", data: []byte("foo")}})
+
+	for _, tc := range []struct {
+		scenario string
+		data     []byte
+	}{
+		{
+			scenario: "empty file",
+			data:     nil,
+		},
+		{
+			scenario: "invalid signature",
+			data:     append([]byte("not a compound file"), make([]byte, cfbHeaderSize)...),
+		},
+		{
+			scenario: "truncated file",
+			data:     valid[:cfbHeaderSize+10],
+		},
+		{
+			scenario: "invalid sector size",
+			data: func() []byte {
+				data := append([]byte(nil), valid...)
+				binary.LittleEndian.PutUint16(data[0x1E:], 7)
+				return data
+			}(),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			_, err := parseCfb(tc.data)
+			if !errors.Is(err, ErrMalformedCompoundFile) {
+				t.Errorf("expected ErrMalformedCompoundFile but got: %v", err)
+			}
+		})
+	}
+}
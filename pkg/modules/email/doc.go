@@ -0,0 +1,5 @@
+// Package email provides a module which adds a route for converting emails,
+// i.e., .eml and Outlook .msg files, to PDF. It renders the headers and the
+// body of each email with Chromium, and may convert its attachments with
+// LibreOffice and merge them into the same document.
+package email
@@ -0,0 +1,100 @@
+package email
+
+import (
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Email))
+}
+
+// Email is a module which provides a route for converting emails to PDF.
+type Email struct {
+	chromium      chromium.Api
+	libreOffice   libreofficeapi.Uno
+	engine        gotenberg.PdfEngine
+	disableRoutes bool
+}
+
+// Descriptor returns an [Email]'s module descriptor.
+func (mod *Email) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "email",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("email", flag.ExitOnError)
+			fs.Bool("email-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Email) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Email) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("email-disable-routes")
+
+	provider, err := ctx.Module(new(chromium.Provider))
+	if err != nil {
+		return fmt.Errorf("get Chromium provider: %w", err)
+	}
+
+	chromiumApi, err := provider.(chromium.Provider).Chromium()
+	if err != nil {
+		return fmt.Errorf("get Chromium API: %w", err)
+	}
+
+	mod.chromium = chromiumApi
+
+	provider, err = ctx.Module(new(libreofficeapi.Provider))
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno provider: %w", err)
+	}
+
+	libreOffice, err := provider.(libreofficeapi.Provider).LibreOffice()
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno: %w", err)
+	}
+
+	mod.libreOffice = libreOffice
+
+	provider, err = ctx.Module(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine provider: %w", err)
+	}
+
+	engine, err := provider.(gotenberg.PdfEngineProvider).PdfEngine()
+	if err != nil {
+		return fmt.Errorf("get PDF engine: %w", err)
+	}
+
+	mod.engine = engine
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Email) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		convertRoute(mod.chromium, mod.libreOffice, mod.engine),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Email)(nil)
+	_ gotenberg.Provisioner = (*Email)(nil)
+	_ api.Router            = (*Email)(nil)
+)
@@ -0,0 +1,187 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// maxEmlDepth is the maximum nesting of multipart bodies.
+const maxEmlDepth = 16
+
+var wordDecoder = &mime.WordDecoder{
+	CharsetReader: charset.NewReaderLabel,
+}
+
+// parseEml parses an .eml file, i.e., an RFC 5322 message.
+func parseEml(r io.Reader) (message, error) {
+	m, err := mail.ReadMessage(r)
+	if err != nil {
+		return message{}, fmt.Errorf("read message: %v: %w", err, ErrMalformedMessage)
+	}
+
+	msg := message{
+		From:    decodeHeader(m.Header.Get("From")),
+		To:      decodeHeader(m.Header.Get("To")),
+		Cc:      decodeHeader(m.Header.Get("Cc")),
+		Subject: decodeHeader(m.Header.Get("Subject")),
+	}
+
+	date, err := m.Header.Date()
+	if err == nil {
+		msg.Date = date
+	}
+
+	err = parseEmlPart(&msg, textproto.MIMEHeader(m.Header), m.Body, 0)
+	if err != nil {
+		return message{}, err
+	}
+
+	return msg, nil
+}
+
+// parseEmlPart walks through a part of an .eml file, and fills the message
+// with its bodies and attachments.
+func parseEmlPart(msg *message, header textproto.MIMEHeader, body io.Reader, depth int) error {
+	if depth > maxEmlDepth {
+		return fmt.Errorf("too many nested parts: %w", ErrMalformedMessage)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		// RFC 2045: default to plain text.
+		mediaType, params = "text/plain", map[string]string{"charset": "us-ascii"}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		boundary := params["boundary"]
+		if boundary == "" {
+			return fmt.Errorf("multipart without boundary: %w", ErrMalformedMessage)
+		}
+
+		reader := multipart.NewReader(body, boundary)
+		for {
+			// Not NextPart, as it decodes the quoted-printable parts
+			// on its own.
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("read part: %v: %w", err, ErrMalformedMessage)
+			}
+
+			err = parseEmlPart(msg, part.Header, part, depth+1)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return fmt.Errorf("decode part: %v: %w", err, ErrMalformedMessage)
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := decodeHeader(dispositionParams["filename"])
+	if filename == "" {
+		filename = decodeHeader(params["name"])
+	}
+
+	contentId := normalizeContentId(header.Get("Content-ID"))
+	isBody := disposition != "attachment" && filename == ""
+
+	switch {
+	case isBody && mediaType == "text/html" && msg.HtmlBody == "":
+		msg.HtmlBody = decodeCharset(params["charset"], content)
+	case isBody && mediaType == "text/plain" && msg.TextBody == "":
+		msg.TextBody = decodeCharset(params["charset"], content)
+	default:
+		if filename == "" && mediaType == "message/rfc822" {
+			filename = "message.eml"
+		}
+
+		msg.Attachments = append(msg.Attachments, attachment{
+			Filename:    filename,
+			ContentType: mediaType,
+			ContentId:   contentId,
+			Inline:      disposition != "attachment" && contentId != "",
+			Data:        content,
+		})
+	}
+
+	return nil
+}
+
+func decodeTransferEncoding(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, newBase64Cleaner(r))
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+// decodeHeader decodes the RFC 2047 encoded-words of a header, if any.
+func decodeHeader(value string) string {
+	decoded, err := wordDecoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+
+	return decoded
+}
+
+// decodeCharset converts a content to UTF-8.
+func decodeCharset(label string, content []byte) string {
+	if label == "" {
+		return string(content)
+	}
+
+	r, err := charset.NewReaderLabel(label, bytes.NewReader(content))
+	if err != nil {
+		return string(content)
+	}
+
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return string(content)
+	}
+
+	return string(decoded)
+}
+
+// base64Cleaner removes the line breaks and spaces of a base64 content, as
+// the standard decoder does not tolerate the latter.
+type base64Cleaner struct {
+	r io.Reader
+}
+
+func newBase64Cleaner(r io.Reader) io.Reader {
+	return &base64Cleaner{r: r}
+}
+
+func (c *base64Cleaner) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+
+	clean := p[:0]
+	for _, b := range p[:n] {
+		if b != '\r' && b != '\n' && b != ' ' && b != '\t' {
+			clean = append(clean, b)
+		}
+	}
+
+	return len(clean), err
+}
@@ -0,0 +1,165 @@
+package email
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseEml(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		eml               string
+		expectMessage     message
+		expectAttachments []attachment
+		expectError       bool
+	}{
+		{
+			scenario:    "malformed headers",
+			eml:         "not an email",
+			expectError: true,
+		},
+		{
+			scenario: "multipart without boundary",
+			eml: "From: foo@example.com\r\n" +
+				"Content-Type: multipart/mixed\r\n" +
+				"\r\n" +
+				"foo",
+			expectError: true,
+		},
+		{
+			scenario: "plain text without content type",
+			eml: "From: Foo <foo@example.com>\r\n" +
+				"To: bar@example.com\r\n" +
+				"Cc: baz@example.com\r\n" +
+				"Subject: Hello\r\n" +
+				"Date: Mon, 02 Jan 2006 15:04:05 +0000\r\n" +
+				"\r\n" +
+				"Hello, world!",
+			expectMessage: message{
+				From:     "Foo <foo@example.com>",
+				To:       "bar@example.com",
+				Cc:       "baz@example.com",
+				Subject:  "Hello",
+				Date:     time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+				TextBody: "Hello, world!",
+			},
+		},
+		{
+			scenario: "encoded-word subject and ISO-8859-1 quoted-printable body",
+			eml: "From: foo@example.com\r\n" +
+				"Subject: =?UTF-8?B?SMOpbGxv?=\r\n" +
+				"Content-Type: text/plain; charset=iso-8859-1\r\n" +
+				"Content-Transfer-Encoding: quoted-printable\r\n" +
+				"\r\n" +
+				"Caf=E9 cr=E8me=\r\n" +
+				" br=FBl=E9e",
+			expectMessage: message{
+				From:     "foo@example.com",
+				Subject:  "Héllo",
+				TextBody: "Café crème brûlée",
+			},
+		},
+		{
+			scenario: "multipart with inline image and attachment",
+			eml: "From: foo@example.com\r\n" +
+				"Subject: Report\r\n" +
+				"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+				"\r\n" +
+				"--outer\r\n" +
+				"Content-Type: multipart/alternative; boundary=\"alt\"\r\n" +
+				"\r\n" +
+				"--alt\r\n" +
+				"Content-Type: text/plain; charset=utf-8\r\n" +
+				"\r\n" +
+				"See the logo.\r\n" +
+				"--alt\r\n" +
+				"Content-Type: multipart/related; boundary=\"rel\"\r\n" +
+				"\r\n" +
+				"--rel\r\n" +
+				"Content-Type: text/html; charset=utf-8\r\n" +
+				"\r\n" +
+				"<p>See the <img src=\"cid:logo@example.com\"></p>\r\n" +
+				"--rel\r\n" +
+				"Content-Type: image/png\r\n" +
+				"Content-Transfer-Encoding: base64\r\n" +
+				"Content-ID: <logo@example.com>\r\n" +
+				"\r\n" +
+				"Zm9v\r\n" +
+				"YmFy\r\n" +
+				"--rel--\r\n" +
+				"--alt--\r\n" +
+				"--outer\r\n" +
+				"Content-Type: application/pdf; name=\"ignored.pdf\"\r\n" +
+				"Content-Disposition: attachment; filename=\"report.pdf\"\r\n" +
+				"Content-Transfer-Encoding: base64\r\n" +
+				"\r\n" +
+				"JVBERi0xLjQ=\r\n" +
+				"--outer--\r\n",
+			expectMessage: message{
+				From:     "foo@example.com",
+				Subject:  "Report",
+				TextBody: "See the logo.",
+				HtmlBody: "<p>See the <img src=\"cid:logo@example.com\"></p>",
+			},
+			expectAttachments: []attachment{
+				{
+					ContentType: "image/png",
+					ContentId:   "logo@example.com",
+					Inline:      true,
+					Data:        []byte("foobar"),
+				},
+				{
+					Filename:    "report.pdf",
+					ContentType: "application/pdf",
+					Data:        []byte("%PDF-1.4"),
+				},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			msg, err := parseEml(strings.NewReader(tc.eml))
+
+			if tc.expectError {
+				if !errors.Is(err, ErrMalformedMessage) {
+					t.Fatalf("expected ErrMalformedMessage but got: %v", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			attachments := msg.Attachments
+			msg.Attachments = nil
+
+			if msg.From != tc.expectMessage.From ||
+				msg.To != tc.expectMessage.To ||
+				msg.Cc != tc.expectMessage.Cc ||
+				msg.Subject != tc.expectMessage.Subject ||
+				!msg.Date.Equal(tc.expectMessage.Date) ||
+				msg.TextBody != tc.expectMessage.TextBody ||
+				msg.HtmlBody != tc.expectMessage.HtmlBody {
+				t.Errorf("expected message %+v but got %+v", tc.expectMessage, msg)
+			}
+
+			if len(attachments) != len(tc.expectAttachments) {
+				t.Fatalf("expected %d attachments but got %d", len(tc.expectAttachments), len(attachments))
+			}
+
+			for i, expect := range tc.expectAttachments {
+				actual := attachments[i]
+				if actual.Filename != expect.Filename ||
+					actual.ContentType != expect.ContentType ||
+					actual.ContentId != expect.ContentId ||
+					actual.Inline != expect.Inline ||
+					string(actual.Data) != string(expect.Data) {
+					t.Errorf("expected attachment %+v but got %+v", expect, actual)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package email
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrMalformedMessage happens if an email cannot be parsed.
+var ErrMalformedMessage = errors.New("malformed message")
+
+// message is an email, either from an .eml or an .msg file.
+type message struct {
+	From        string
+	To          string
+	Cc          string
+	Subject     string
+	Date        time.Time
+	HtmlBody    string
+	TextBody    string
+	Attachments []attachment
+}
+
+// attachment is a file of an email. Inline attachments, e.g., the images of
+// the HTML body, have a content ID.
+type attachment struct {
+	Filename    string
+	ContentType string
+	ContentId   string
+	Inline      bool
+	Data        []byte
+}
+
+// filename returns a safe filename for the attachment.
+func (a attachment) filename(fallback string) string {
+	filename := filepath.Base(strings.ReplaceAll(a.Filename, "\\", "/"))
+	if filename == "." || filename == "/" || filename == "" {
+		return fallback
+	}
+
+	return filename
+}
+
+// normalizeContentId removes the angle brackets around a content ID.
+func normalizeContentId(contentId string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(contentId), "<"), ">")
+}
@@ -0,0 +1,228 @@
+package email
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The Outlook .msg files store the MAPI properties of a message in the
+// streams of a Compound File Binary, named after the property ID and type.
+//
+// See https://learn.microsoft.com/en-us/openspecs/exchange_server_protocols/ms-oxmsg.
+
+const (
+	msgPropertyPrefix   = "__substg1.0_"
+	msgAttachmentPrefix = "__attach_version1.0_"
+	msgPropertiesStream = "__properties_version1.0"
+
+	// Property types.
+	msgTypeString8 = 0x001E
+	msgTypeUnicode = 0x001F
+	msgTypeBinary  = 0x0102
+	msgTypeSysTime = 0x0040
+
+	// Property IDs.
+	msgSubject             = 0x0037
+	msgClientSubmitTime    = 0x0039
+	msgSenderName          = 0x0C1A
+	msgSenderEmailAddress  = 0x0C1F
+	msgDisplayCc           = 0x0E03
+	msgDisplayTo           = 0x0E04
+	msgMessageDeliveryTime = 0x0E06
+	msgBody                = 0x1000
+	msgHtmlBody            = 0x1013
+	msgAttachData          = 0x3701
+	msgAttachFilename      = 0x3704
+	msgAttachLongFilename  = 0x3707
+	msgAttachMimeTag       = 0x370E
+	msgAttachContentId     = 0x3712
+
+	// The header of the properties stream of the top-level message.
+	msgTopLevelPropertiesHeaderSize = 32
+	msgPropertyEntrySize            = 16
+)
+
+// msgProperties gathers the variable-length properties of a storage, by ID.
+type msgProperties map[uint16]msgProperty
+
+type msgProperty struct {
+	kind uint16
+	data []byte
+}
+
+// string returns a property as a string.
+func (props msgProperties) string(id uint16) string {
+	prop, ok := props[id]
+	if !ok {
+		return ""
+	}
+
+	switch prop.kind {
+	case msgTypeUnicode:
+		return strings.TrimRight(decodeUtf16(prop.data), "\x00")
+	default:
+		return strings.TrimRight(string(prop.data), "\x00")
+	}
+}
+
+// parseMsg parses an Outlook .msg file.
+func parseMsg(data []byte) (message, error) {
+	f, err := parseCfb(data)
+	if err != nil {
+		return message{}, fmt.Errorf("parse compound file: %v: %w", err, ErrMalformedMessage)
+	}
+
+	props, err := readMsgProperties(f, 0)
+	if err != nil {
+		return message{}, fmt.Errorf("read properties: %v: %w", err, ErrMalformedMessage)
+	}
+
+	msg := message{
+		From:     formatSender(props.string(msgSenderName), props.string(msgSenderEmailAddress)),
+		To:       props.string(msgDisplayTo),
+		Cc:       props.string(msgDisplayCc),
+		Subject:  props.string(msgSubject),
+		TextBody: props.string(msgBody),
+		HtmlBody: props.string(msgHtmlBody),
+	}
+
+	msg.Date = readMsgDate(f)
+
+	for _, index := range f.children(0) {
+		entry := f.entries[index]
+		if entry.kind != cfbTypeStorage || !strings.HasPrefix(entry.name, msgAttachmentPrefix) {
+			continue
+		}
+
+		attachmentProps, err := readMsgProperties(f, index)
+		if err != nil {
+			return message{}, fmt.Errorf("read attachment properties: %v: %w", err, ErrMalformedMessage)
+		}
+
+		data, ok := attachmentProps[msgAttachData]
+		if !ok || data.kind != msgTypeBinary {
+			// E.g., an embedded message, which is not supported.
+			continue
+		}
+
+		filename := attachmentProps.string(msgAttachLongFilename)
+		if filename == "" {
+			filename = attachmentProps.string(msgAttachFilename)
+		}
+
+		contentId := normalizeContentId(attachmentProps.string(msgAttachContentId))
+
+		msg.Attachments = append(msg.Attachments, attachment{
+			Filename:    filename,
+			ContentType: attachmentProps.string(msgAttachMimeTag),
+			ContentId:   contentId,
+			Inline:      contentId != "" && strings.Contains(msg.HtmlBody, "cid:"+contentId),
+			Data:        data.data,
+		})
+	}
+
+	return msg, nil
+}
+
+// readMsgProperties reads the variable-length properties of a storage.
+func readMsgProperties(f *cfbFile, storage int) (msgProperties, error) {
+	props := make(msgProperties)
+
+	for _, index := range f.children(storage) {
+		entry := f.entries[index]
+		if entry.kind != cfbTypeStream || !strings.HasPrefix(entry.name, msgPropertyPrefix) {
+			continue
+		}
+
+		// E.g., __substg1.0_0037001F.
+		var id, kind uint16
+		_, err := fmt.Sscanf(strings.TrimPrefix(entry.name, msgPropertyPrefix), "%04X%04X", &id, &kind)
+		if err != nil {
+			continue
+		}
+
+		if kind != msgTypeString8 && kind != msgTypeUnicode && kind != msgTypeBinary {
+			continue
+		}
+
+		data, err := f.stream(index)
+		if err != nil {
+			return nil, err
+		}
+
+		props[id] = msgProperty{kind: kind, data: data}
+	}
+
+	return props, nil
+}
+
+// readMsgDate reads the date of the message from the fixed-length properties
+// of the top-level message. It returns the zero time if not available.
+func readMsgDate(f *cfbFile) time.Time {
+	for _, index := range f.children(0) {
+		if f.entries[index].name != msgPropertiesStream {
+			continue
+		}
+
+		data, err := f.stream(index)
+		if err != nil || len(data) < msgTopLevelPropertiesHeaderSize {
+			return time.Time{}
+		}
+
+		var submitTime, deliveryTime time.Time
+		for offset := msgTopLevelPropertiesHeaderSize; offset+msgPropertyEntrySize <= len(data); offset += msgPropertyEntrySize {
+			kind := binary.LittleEndian.Uint16(data[offset:])
+			id := binary.LittleEndian.Uint16(data[offset+2:])
+			if kind != msgTypeSysTime {
+				continue
+			}
+
+			value := fileTime(binary.LittleEndian.Uint64(data[offset+8:]))
+			switch id {
+			case msgClientSubmitTime:
+				submitTime = value
+			case msgMessageDeliveryTime:
+				deliveryTime = value
+			}
+		}
+
+		if !submitTime.IsZero() {
+			return submitTime
+		}
+
+		return deliveryTime
+	}
+
+	return time.Time{}
+}
+
+// fileTime converts a Windows FILETIME, i.e., the number of 100-nanosecond
+// intervals since January 1, 1601 (UTC), to a [time.Time].
+func fileTime(value uint64) time.Time {
+	if value == 0 {
+		return time.Time{}
+	}
+
+	// Number of 100-nanosecond intervals between 1601 and 1970.
+	const epochDelta = 116444736000000000
+	if value < epochDelta {
+		return time.Time{}
+	}
+
+	intervals := value - epochDelta
+
+	return time.Unix(int64(intervals/10000000), int64(intervals%10000000)*100).UTC()
+}
+
+func formatSender(name, address string) string {
+	switch {
+	case name == "":
+		return address
+	case address == "" || name == address:
+		return name
+	default:
+		return fmt.Sprintf("%s <%s>", name, address)
+	}
+}
@@ -0,0 +1,143 @@
+package email
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func msgUnicodeProperty(id uint16, value string) cfbTestNode {
+	units := utf16.Encode([]rune(value))
+	data := make([]byte, len(units)*2)
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(data[i*2:], u)
+	}
+
+	return cfbTestNode{name: msgPropertyName(id, msgTypeUnicode), data: data}
+}
+
+func msgPropertyName(id, kind uint16) string {
+	const hex = "0123456789ABCDEF"
+	name := []byte(msgPropertyPrefix)
+	for _, v := range []uint16{id, kind} {
+		name = append(name, hex[v>>12&0xF], hex[v>>8&0xF], hex[v>>4&0xF], hex[v&0xF])
+	}
+
+	return string(name)
+}
+
+func msgDateProperties(id uint16, date time.Time) cfbTestNode {
+	data := make([]byte, msgTopLevelPropertiesHeaderSize+msgPropertyEntrySize)
+	entry := data[msgTopLevelPropertiesHeaderSize:]
+	binary.LittleEndian.PutUint16(entry, msgTypeSysTime)
+	binary.LittleEndian.PutUint16(entry[2:], id)
+	binary.LittleEndian.PutUint64(entry[8:], uint64(date.UnixNano()/100)+116444736000000000)
+
+	return cfbTestNode{name: msgPropertiesStream, data: data}
+}
+
+func TestParseMsg(t *testing.T) {
+	date := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+
+	data := buildCfb(t, []cfbTestNode{
+		msgDateProperties(msgMessageDeliveryTime, date),
+		msgUnicodeProperty(msgSubject, "Héllo"),
+		msgUnicodeProperty(msgSenderName, "Foo"),
+		msgUnicodeProperty(msgSenderEmailAddress, "foo@example.com"),
+		msgUnicodeProperty(msgDisplayTo, "Bar"),
+		{name: msgPropertyName(msgDisplayCc, msgTypeString8), data: []byte("Baz\x00")},
+		msgUnicodeProperty(msgBody, "Hello, world!"),
+		{name: msgPropertyName(msgHtmlBody, msgTypeBinary), data: []byte(`<img src="cid:logo">`)},
+		{
+			name:    msgAttachmentPrefix + "#00000000",
+			storage: true,
+			children: []cfbTestNode{
+				{name: msgPropertyName(msgAttachData, msgTypeBinary), data: []byte("foo")},
+				msgUnicodeProperty(msgAttachLongFilename, "logo.png"),
+				msgUnicodeProperty(msgAttachMimeTag, "image/png"),
+				msgUnicodeProperty(msgAttachContentId, "<logo>"),
+			},
+		},
+		{
+			name:    msgAttachmentPrefix + "#00000001",
+			storage: true,
+			children: []cfbTestNode{
+				{name: msgPropertyName(msgAttachData, msgTypeBinary), data: []byte("bar")},
+				msgUnicodeProperty(msgAttachFilename, "REPORT~1.DOC"),
+			},
+		},
+		{
+			// An embedded message, which is skipped.
+			name:    msgAttachmentPrefix + "#00000002",
+			storage: true,
+			children: []cfbTestNode{
+				msgUnicodeProperty(msgAttachFilename, "message.msg"),
+			},
+		},
+	})
+
+	msg, err := parseMsg(data)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		field  string
+		expect string
+		actual string
+	}{
+		{"From", "Foo <foo@example.com>", msg.From},
+		{"To", "Bar", msg.To},
+		{"Cc", "Baz", msg.Cc},
+		{"Subject", "Héllo", msg.Subject},
+		{"TextBody", "Hello, world!", msg.TextBody},
+		{"HtmlBody", `<img src="cid:logo">`, msg.HtmlBody},
+	} {
+		if tc.expect != tc.actual {
+			t.Errorf("expected %s '%s' but got '%s'", tc.field, tc.expect, tc.actual)
+		}
+	}
+
+	if !msg.Date.Equal(date) {
+		t.Errorf("expected date '%s' but got '%s'", date, msg.Date)
+	}
+
+	if len(msg.Attachments) != 2 {
+		t.Fatalf("expected 2 attachments but got %d", len(msg.Attachments))
+	}
+
+	logo := msg.Attachments[0]
+	if logo.Filename != "logo.png" || logo.ContentType != "image/png" || logo.ContentId != "logo" || !logo.Inline || string(logo.Data) != "foo" {
+		t.Errorf("unexpected inline attachment: %+v", logo)
+	}
+
+	report := msg.Attachments[1]
+	if report.Filename != "REPORT~1.DOC" || report.Inline || string(report.Data) != "bar" {
+		t.Errorf("unexpected attachment: %+v", report)
+	}
+}
+
+func TestParseMsg_malformed(t *testing.T) {
+	_, err := parseMsg([]byte("not a compound file"))
+	if !errors.Is(err, ErrMalformedMessage) {
+		t.Errorf("expected ErrMalformedMessage but got: %v", err)
+	}
+}
+
+func TestFormatSender(t *testing.T) {
+	for _, tc := range []struct {
+		name, address, expect string
+	}{
+		{"", "foo@example.com", "foo@example.com"},
+		{"Foo", "", "Foo"},
+		{"foo@example.com", "foo@example.com", "foo@example.com"},
+		{"Foo", "foo@example.com", "Foo <foo@example.com>"},
+	} {
+		actual := formatSender(tc.name, tc.address)
+		if actual != tc.expect {
+			t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var messageTemplate = template.Must(template.New("message").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Subject }}</title>
+<style>
+  .gotenberg-email-headers { font-family: sans-serif; font-size: 12px; border-collapse: collapse; margin-bottom: 16px; width: 100%; }
+  .gotenberg-email-headers th { text-align: left; vertical-align: top; padding: 2px 12px 2px 0; white-space: nowrap; }
+  .gotenberg-email-headers td { padding: 2px 0; word-break: break-word; }
+  .gotenberg-email-text { white-space: pre-wrap; word-wrap: break-word; font-family: monospace; }
+</style>
+</head>
+<body>
+<table class="gotenberg-email-headers">
+  {{- if .From }}<tr><th>From</th><td>{{ .From }}</td></tr>{{ end }}
+  {{- if .To }}<tr><th>To</th><td>{{ .To }}</td></tr>{{ end }}
+  {{- if .Cc }}<tr><th>Cc</th><td>{{ .Cc }}</td></tr>{{ end }}
+  {{- if .Date }}<tr><th>Date</th><td>{{ .Date }}</td></tr>{{ end }}
+  {{- if .Subject }}<tr><th>Subject</th><td>{{ .Subject }}</td></tr>{{ end }}
+  {{- if .Attachments }}<tr><th>Attachments</th><td>{{ range $i, $a := .Attachments }}{{ if $i }}, {{ end }}{{ $a }}{{ end }}</td></tr>{{ end }}
+</table>
+<hr>
+{{ if .Html }}{{ .Html }}{{ else }}<div class="gotenberg-email-text">{{ .Text }}</div>{{ end }}
+</body>
+</html>
+`))
+
+// renderHtml writes the HTML document of a message, with its headers, its
+// body and its inline images, and returns its path.
+func renderHtml(msg message, generatePath func(filename, extension string) string) (string, error) {
+	body := msg.HtmlBody
+	var attachmentNames []string
+
+	for i, a := range msg.Attachments {
+		if !a.Inline {
+			attachmentNames = append(attachmentNames, a.filename(fmt.Sprintf("attachment-%d", i+1)))
+			continue
+		}
+
+		// The inline images are referenced by their content ID, e.g.,
+		// <img src="cid:foo">.
+		path := generatePath("", attachmentExtension(a))
+
+		err := os.WriteFile(path, a.Data, 0o600)
+		if err != nil {
+			return "", fmt.Errorf("write inline attachment: %w", err)
+		}
+
+		body = strings.ReplaceAll(body, "cid:"+a.ContentId, "file://"+path)
+	}
+
+	data := struct {
+		From, To, Cc, Subject, Date string
+		Attachments                 []string
+		Html                        template.HTML
+		Text                        string
+	}{
+		From:        msg.From,
+		To:          msg.To,
+		Cc:          msg.Cc,
+		Subject:     msg.Subject,
+		Attachments: attachmentNames,
+		// The body of the email is rendered as is, like any HTML document
+		// sent to Chromium.
+		Html: template.HTML(body),
+		Text: msg.TextBody,
+	}
+
+	if !msg.Date.IsZero() {
+		data.Date = msg.Date.Format(time.RFC1123Z)
+	}
+
+	var buf bytes.Buffer
+	err := messageTemplate.Execute(&buf, data)
+	if err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+
+	path := generatePath("", ".html")
+
+	err = os.WriteFile(path, buf.Bytes(), 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write HTML document: %w", err)
+	}
+
+	return path, nil
+}
+
+// attachmentExtension returns the extension of an attachment, either from its
+// filename or from its content type.
+func attachmentExtension(a attachment) string {
+	ext := strings.ToLower(filepath.Ext(a.filename("")))
+	if ext != "" {
+		return ext
+	}
+
+	// The standard library returns the extensions in alphabetical order,
+	// e.g., ".jfif" first for JPEG images.
+	switch strings.ToLower(a.ContentType) {
+	case "image/jpeg":
+		return ".jpg"
+	case "image/png":
+		return ".png"
+	case "image/gif":
+		return ".gif"
+	}
+
+	extensions, err := mime.ExtensionsByType(a.ContentType)
+	if err != nil || len(extensions) == 0 {
+		return ""
+	}
+
+	return extensions[0]
+}
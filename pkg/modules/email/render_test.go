@@ -0,0 +1,115 @@
+package email
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderHtml(t *testing.T) {
+	dirPath := t.TempDir()
+	var count int
+	generatePath := func(filename, extension string) string {
+		if filename == "" {
+			count++
+			filename = fmt.Sprintf("file-%d", count)
+		}
+
+		return filepath.Join(dirPath, filename+extension)
+	}
+
+	for _, tc := range []struct {
+		scenario       string
+		msg            message
+		expectContains []string
+		expectMissing  []string
+	}{
+		{
+			scenario: "HTML body with inline image",
+			msg: message{
+				From:     "Foo <foo@example.com>",
+				Subject:  "<b>Report</b>",
+				Date:     time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC),
+				HtmlBody: `<p>Logo: <img src="cid:logo"></p>`,
+				Attachments: []attachment{
+					{ContentType: "image/png", ContentId: "logo", Inline: true, Data: []byte("foo")},
+					{Filename: "../../report.pdf", Data: []byte("bar")},
+				},
+			},
+			expectContains: []string{
+				"<td>Foo &lt;foo@example.com&gt;</td>",
+				"<td>&lt;b&gt;Report&lt;/b&gt;</td>",
+				"<td>Mon, 02 Jan 2006 15:04:05 &#43;0000</td>",
+				"<td>report.pdf</td>",
+				`<img src="file://` + dirPath,
+			},
+			expectMissing: []string{
+				"cid:logo",
+				"gotenberg-email-text\">",
+				"<th>Cc</th>",
+			},
+		},
+		{
+			scenario: "text body",
+			msg: message{
+				TextBody: "Hello <world>",
+			},
+			expectContains: []string{
+				`<div class="gotenberg-email-text">Hello &lt;world&gt;</div>`,
+			},
+			expectMissing: []string{
+				"<th>From</th>",
+				"<th>Date</th>",
+				"<th>Attachments</th>",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path, err := renderHtml(tc.msg, generatePath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			html := string(b)
+
+			for _, expect := range tc.expectContains {
+				if !strings.Contains(html, expect) {
+					t.Errorf("expected HTML to contain '%s' but got: %s", expect, html)
+				}
+			}
+
+			for _, expect := range tc.expectMissing {
+				if strings.Contains(html, expect) {
+					t.Errorf("expected HTML not to contain '%s' but got: %s", expect, html)
+				}
+			}
+		})
+	}
+}
+
+func TestAttachmentExtension(t *testing.T) {
+	for _, tc := range []struct {
+		attachment attachment
+		expect     string
+	}{
+		{attachment{Filename: "logo.PNG", ContentType: "image/jpeg"}, ".png"},
+		{attachment{ContentType: "image/jpeg"}, ".jpg"},
+		{attachment{ContentType: "image/png"}, ".png"},
+		{attachment{ContentType: "image/gif"}, ".gif"},
+		{attachment{ContentType: "application/pdf"}, ".pdf"},
+		{attachment{ContentType: "application/x-foo"}, ""},
+	} {
+		actual := attachmentExtension(tc.attachment)
+		if actual != tc.expect {
+			t.Errorf("expected '%s' for %+v but got '%s'", tc.expect, tc.attachment, actual)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+// convertRoute returns an [api.Route] which can convert emails to PDF.
+func convertRoute(chromiumApi chromium.Api, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/email/convert",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			form, options := chromium.FormDataChromiumPdfOptions(ctx)
+			pdfFormats := chromium.FormDataChromiumPdfFormats(form)
+
+			var (
+				inputPaths         []string
+				convertAttachments bool
+				merge              bool
+			)
+
+			err := form.
+				MandatoryPaths([]string{".eml", ".msg"}, &inputPaths).
+				Bool("convertAttachments", &convertAttachments, false).
+				Bool("merge", &merge, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			ctx.AddEngines("chromium")
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				msg, err := readMessage(inputPath)
+				if err != nil {
+					if errors.Is(err, ErrMalformedMessage) {
+						return api.WrapError(
+							fmt.Errorf("read email: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("The email '%s' is malformed", filepath.Base(inputPath)),
+							).WithCode(api.ErrorCodeEmailMalformedMessage),
+						)
+					}
+
+					return fmt.Errorf("read email: %w", err)
+				}
+
+				htmlPath, err := renderHtml(msg, ctx.GeneratePath)
+				if err != nil {
+					return fmt.Errorf("render email: %w", err)
+				}
+
+				pdfPaths := []string{ctx.GeneratePath("", ".pdf")}
+
+				err = chromiumApi.Pdf(ctx, ctx.Log(), fmt.Sprintf("file://%s", htmlPath), pdfPaths[0], options)
+				if err != nil {
+					return fmt.Errorf("convert email to PDF: %w", err)
+				}
+
+				if convertAttachments {
+					attachmentPaths, err := convertAttachmentsToPdf(ctx, libreOffice, msg.Attachments)
+					if err != nil {
+						return fmt.Errorf("convert attachments to PDF: %w", err)
+					}
+
+					pdfPaths = append(pdfPaths, attachmentPaths...)
+				}
+
+				// mail.eml -> mail.eml.pdf.
+				outputPaths[i] = ctx.GeneratePath(filepath.Base(inputPath), ".pdf")
+
+				if len(pdfPaths) == 1 {
+					err = os.Rename(pdfPaths[0], outputPaths[i])
+					if err != nil {
+						return fmt.Errorf("rename PDF: %w", err)
+					}
+
+					continue
+				}
+
+				err = engine.Merge(ctx, ctx.Log(), pdfPaths, outputPaths[i])
+				if err != nil {
+					return fmt.Errorf("merge email and attachments: %w", err)
+				}
+			}
+
+			if len(outputPaths) > 1 && merge {
+				outputPath := ctx.GeneratePath("", ".pdf")
+
+				err = engine.Merge(ctx, ctx.Log(), outputPaths, outputPath)
+				if err != nil {
+					return fmt.Errorf("merge PDFs: %w", err)
+				}
+
+				outputPaths = []string{outputPath}
+			}
+
+			// Now, let's check if the client want to convert the PDFs to
+			// specific PDF formats.
+			zeroValued := gotenberg.PdfFormats{}
+			if pdfFormats != zeroValued {
+				for i, outputPath := range outputPaths {
+					convertOutputPath := ctx.GeneratePath("", ".pdf")
+
+					err = engine.Convert(ctx, ctx.Log(), pdfFormats, outputPath, convertOutputPath)
+					if err != nil {
+						return fmt.Errorf("convert PDF: %w", err)
+					}
+
+					// Keep the filename of the email.
+					err = os.Rename(convertOutputPath, outputPath)
+					if err != nil {
+						return fmt.Errorf("rename converted PDF: %w", err)
+					}
+
+					outputPaths[i] = outputPath
+				}
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// readMessage parses an email according to its extension.
+func readMessage(inputPath string) (message, error) {
+	if strings.ToLower(filepath.Ext(inputPath)) == ".msg" {
+		data, err := os.ReadFile(inputPath)
+		if err != nil {
+			return message{}, fmt.Errorf("read file: %w", err)
+		}
+
+		return parseMsg(data)
+	}
+
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return message{}, fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return parseEml(f)
+}
+
+// convertAttachmentsToPdf converts the attachments of an email, except the
+// inline ones, to PDF. It keeps the PDF attachments as is and skips the
+// attachments LibreOffice cannot convert.
+func convertAttachmentsToPdf(ctx *api.Context, libreOffice libreofficeapi.Uno, attachments []attachment) ([]string, error) {
+	var pdfPaths []string
+
+	for _, a := range attachments {
+		if a.Inline {
+			continue
+		}
+
+		ext := strings.ToLower(filepath.Ext(a.filename("")))
+		if ext != ".pdf" && !slices.Contains(libreOffice.Extensions(), ext) {
+			ctx.Log().Debug(fmt.Sprintf("skip attachment '%s': unsupported extension", a.Filename))
+			continue
+		}
+
+		inputPath := ctx.GeneratePath("", ext)
+
+		err := os.WriteFile(inputPath, a.Data, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("write attachment: %w", err)
+		}
+
+		if ext == ".pdf" {
+			pdfPaths = append(pdfPaths, inputPath)
+			continue
+		}
+
+		ctx.AddEngines("libreoffice")
+		outputPath := ctx.GeneratePath("", ".pdf")
+
+		err = libreOffice.Pdf(ctx, ctx.Log(), inputPath, outputPath, libreofficeapi.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("convert attachment '%s': %w", a.Filename, err)
+		}
+
+		pdfPaths = append(pdfPaths, outputPath)
+	}
+
+	return pdfPaths, nil
+}
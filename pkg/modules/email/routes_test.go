@@ -0,0 +1,300 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+const testEml = "From: foo@example.com\r\n" +
+	"Subject: Report\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>See the attachments.</p>\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/pdf\r\n" +
+	"Content-Disposition: attachment; filename=\"report.pdf\"\r\n" +
+	"\r\n" +
+	"%PDF-1.4\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/vnd.openxmlformats-officedocument.wordprocessingml.document\r\n" +
+	"Content-Disposition: attachment; filename=\"report.docx\"\r\n" +
+	"\r\n" +
+	"docx\r\n" +
+	"--outer\r\n" +
+	"Content-Type: application/zip\r\n" +
+	"Content-Disposition: attachment; filename=\"archive.zip\"\r\n" +
+	"\r\n" +
+	"zip\r\n" +
+	"--outer--\r\n"
+
+func TestConvertRoute(t *testing.T) {
+	newContext := func(files map[string]string, values map[string][]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string)
+
+		for filename, content := range files {
+			path := filepath.Join(dirPath, filename)
+
+			err := os.WriteFile(path, []byte(content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			paths[filename] = path
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetFiles(paths)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	writePdf := func(outputPath string) error {
+		return os.WriteFile(outputPath, []byte("%PDF-1.4"), 0o600)
+	}
+
+	chromiumSuccess := &chromium.ApiMock{
+		PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options chromium.PdfOptions) error {
+			return writePdf(outputPath)
+		},
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		chromium               chromium.Api
+		libreOffice            libreofficeapi.Uno
+		engine                 gotenberg.PdfEngine
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectOutputPaths      []string
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newContext(nil, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "malformed email",
+			ctx:                    newContext(map[string]string{"mail.msg": "not a compound file"}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "error from Chromium",
+			ctx:      newContext(map[string]string{"mail.eml": testEml}, nil),
+			chromium: &chromium.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options chromium.PdfOptions) error {
+					return errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "success without attachments",
+			ctx:                    newContext(map[string]string{"mail.eml": testEml}, nil),
+			chromium:               chromiumSuccess,
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"mail.eml.pdf"},
+		},
+		{
+			scenario: "error from LibreOffice",
+			ctx: newContext(
+				map[string]string{"mail.eml": testEml},
+				map[string][]string{"convertAttachments": {"true"}},
+			),
+			chromium: chromiumSuccess,
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return errors.New("foo")
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with attachments",
+			ctx: newContext(
+				map[string]string{"mail.eml": testEml},
+				map[string][]string{"convertAttachments": {"true"}},
+			),
+			chromium: chromiumSuccess,
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return writePdf(outputPath)
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					// The email, the PDF and the DOCX attachments, but not
+					// the ZIP archive.
+					if len(inputPaths) != 3 {
+						return errors.New("expected 3 PDFs to merge")
+					}
+
+					return writePdf(outputPath)
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"mail.eml.pdf"},
+		},
+		{
+			scenario: "error from PDF engine (merge)",
+			ctx: newContext(
+				map[string]string{"mail.eml": testEml, "mail2.eml": testEml},
+				map[string][]string{"merge": {"true"}},
+			),
+			chromium: chromiumSuccess,
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "success with many emails",
+			ctx:                    newContext(map[string]string{"mail.eml": testEml, "mail2.eml": testEml}, nil),
+			chromium:               chromiumSuccess,
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+			expectOutputPaths:      []string{"mail.eml.pdf", "mail2.eml.pdf"},
+		},
+		{
+			scenario: "success with merge",
+			ctx: newContext(
+				map[string]string{"mail.eml": testEml, "mail2.eml": testEml},
+				map[string][]string{"merge": {"true"}},
+			),
+			chromium: chromiumSuccess,
+			engine: &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					return writePdf(outputPath)
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "error from PDF engine (convert)",
+			ctx: newContext(
+				map[string]string{"mail.eml": testEml},
+				map[string][]string{"pdfa": {gotenberg.PdfA1b}},
+			),
+			chromium: chromiumSuccess,
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with PDF formats",
+			ctx: newContext(
+				map[string]string{"mail.eml": testEml},
+				map[string][]string{"pdfa": {gotenberg.PdfA1b}},
+			),
+			chromium: chromiumSuccess,
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return writePdf(outputPath)
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"mail.eml.pdf"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertRoute(tc.chromium, tc.libreOffice, tc.engine).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+
+			for _, filename := range tc.expectOutputPaths {
+				path := filepath.Join(tc.ctx.DirPath(), filename)
+				if !slices.Contains(tc.ctx.OutputPaths(), path) {
+					t.Errorf("expected '%s' in output paths %v", path, tc.ctx.OutputPaths())
+				}
+			}
+		})
+	}
+}
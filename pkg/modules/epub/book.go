@@ -0,0 +1,518 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/russross/blackfriday/v2"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// chapterExtensions are the extensions of the files which become the
+// chapters of a book.
+var chapterExtensions = []string{".html", ".md"}
+
+// mediaTypes are the media types of the files a book may embed, e.g., the
+// stylesheets, images, and fonts the chapters reference.
+var mediaTypes = map[string]string{
+	".css":   "text/css",
+	".gif":   "image/gif",
+	".jpeg":  "image/jpeg",
+	".jpg":   "image/jpeg",
+	".png":   "image/png",
+	".svg":   "image/svg+xml",
+	".webp":  "image/webp",
+	".otf":   "font/otf",
+	".ttf":   "font/ttf",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+}
+
+// assetExtensions returns the extensions of [mediaTypes].
+func assetExtensions() []string {
+	extensions := make([]string, 0, len(mediaTypes))
+	for ext := range mediaTypes {
+		extensions = append(extensions, ext)
+	}
+
+	return extensions
+}
+
+// metadata gathers the metadata of a book.
+type metadata struct {
+	Title      string
+	Author     string
+	Language   string
+	Identifier string
+	// Cover is the name of the cover image, if any.
+	Cover string
+}
+
+// chapter is a chapter of a book, as an XHTML content document.
+type chapter struct {
+	Name   string
+	Title  string
+	Styles []string
+	Body   string
+}
+
+// resource is a file of a book, other than a chapter.
+type resource struct {
+	Name    string
+	Content []byte
+}
+
+// book is an EPUB 3 publication.
+type book struct {
+	metadata
+	Modified  string
+	Chapters  []chapter
+	Resources []resource
+}
+
+// newBook creates a book from HTML and markdown files, in this order, and
+// their assets. The chapters may reference the assets and each other by
+// their filenames.
+func newBook(meta metadata, chapterPaths, assetPaths []string) (*book, error) {
+	b := &book{
+		metadata: meta,
+		Modified: time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+	}
+
+	for _, path := range assetPaths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read asset: %w", err)
+		}
+
+		b.Resources = append(b.Resources, resource{Name: filepath.Base(path), Content: content})
+	}
+
+	// The chapters become XHTML documents: document.html ->
+	// document.xhtml. The links between them must follow.
+	names := map[string]string{"nav.xhtml": ""}
+	for _, r := range b.Resources {
+		names[r.Name] = r.Name
+	}
+
+	chapterNames := make([]string, len(chapterPaths))
+	links := make(map[string]string, len(chapterPaths))
+
+	for i, path := range chapterPaths {
+		base := filepath.Base(path)
+		stem := strings.TrimSuffix(base, filepath.Ext(base))
+		name := stem + ".xhtml"
+
+		for n := 2; ; n++ {
+			if _, ok := names[name]; !ok {
+				break
+			}
+
+			name = fmt.Sprintf("%s-%d.xhtml", stem, n)
+		}
+
+		names[name] = base
+		chapterNames[i] = name
+		links[base] = name
+	}
+
+	for i, path := range chapterPaths {
+		c, styles, err := readChapter(path, chapterNames[i], links)
+		if err != nil {
+			return nil, fmt.Errorf("read chapter '%s': %w", filepath.Base(path), err)
+		}
+
+		b.Chapters = append(b.Chapters, c)
+		b.Resources = append(b.Resources, styles...)
+	}
+
+	if b.Title == "" {
+		b.Title = b.Chapters[0].Title
+	}
+
+	return b, nil
+}
+
+// readChapter reads an HTML or markdown file and converts it to an XHTML
+// content document. The styles of the file become stylesheets.
+func readChapter(path, name string, links map[string]string) (chapter, []resource, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return chapter{}, nil, fmt.Errorf("read file: %w", err)
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".md" {
+		content = blackfriday.Run(content)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(content))
+	if err != nil {
+		return chapter{}, nil, fmt.Errorf("parse HTML: %w", err)
+	}
+
+	c := chapter{Name: name}
+
+	var (
+		styles  []resource
+		body    *html.Node
+		heading string
+	)
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; {
+			next := child.NextSibling
+
+			if child.Type == html.ElementNode {
+				switch child.DataAtom {
+				case atom.Title:
+					if c.Title == "" {
+						c.Title = strings.TrimSpace(textContent(child))
+					}
+				case atom.Style:
+					// A stylesheet, as XHTML would not accept some CSS in
+					// a style element.
+					styleName := fmt.Sprintf("%s-%d.css", strings.TrimSuffix(name, ".xhtml"), len(styles)+1)
+					styles = append(styles, resource{Name: styleName, Content: []byte(textContent(child))})
+					c.Styles = append(c.Styles, styleName)
+					n.RemoveChild(child)
+				case atom.Link:
+					if strings.EqualFold(attr(child, "rel"), "stylesheet") && attr(child, "href") != "" {
+						c.Styles = append(c.Styles, attr(child, "href"))
+					}
+				case atom.Body:
+					body = child
+				case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+					if heading == "" {
+						heading = strings.TrimSpace(textContent(child))
+					}
+				}
+
+				// Reading systems do not have to run scripts, and the
+				// content of these elements is not XML.
+				if unsupportedElements[child.DataAtom] {
+					n.RemoveChild(child)
+				} else {
+					child.Attr = xhtmlAttributes(child, links)
+				}
+			}
+
+			if child.Parent == n {
+				walk(child)
+			}
+
+			child = next
+		}
+	}
+
+	walk(doc)
+
+	if c.Title == "" {
+		c.Title = heading
+	}
+
+	if c.Title == "" {
+		c.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	var buf bytes.Buffer
+	if body != nil {
+		for child := body.FirstChild; child != nil; child = child.NextSibling {
+			err = html.Render(&buf, child)
+			if err != nil {
+				return chapter{}, nil, fmt.Errorf("render XHTML: %w", err)
+			}
+		}
+	}
+
+	c.Body = strings.TrimSpace(buf.String())
+
+	return c, styles, nil
+}
+
+// unsupportedElements are the elements removed from the chapters.
+var unsupportedElements = map[atom.Atom]bool{
+	atom.Script:    true,
+	atom.Noscript:  true,
+	atom.Iframe:    true,
+	atom.Noembed:   true,
+	atom.Noframes:  true,
+	atom.Xmp:       true,
+	atom.Plaintext: true,
+}
+
+// xmlName matches the attribute names which are also valid XML names.
+var xmlName = regexp.MustCompile(`^[a-zA-Z_][-a-zA-Z0-9_.]*$`)
+
+// xhtmlAttributes returns the attributes of an element which XHTML accepts,
+// without the event handlers, and with the links to the chapters updated.
+func xhtmlAttributes(n *html.Node, links map[string]string) []html.Attribute {
+	attrs := make([]html.Attribute, 0, len(n.Attr)+1)
+	hasXmlns, hasXlink := false, false
+
+	for _, a := range n.Attr {
+		if a.Namespace == "" && (!xmlName.MatchString(a.Key) || strings.HasPrefix(a.Key, "on")) {
+			continue
+		}
+
+		hasXmlns = hasXmlns || (a.Namespace == "" && a.Key == "xmlns")
+		hasXlink = hasXlink || (a.Namespace == "xmlns" && a.Key == "xlink")
+
+		if n.DataAtom == atom.A && a.Key == "href" {
+			a.Val = chapterLink(a.Val, links)
+		}
+
+		attrs = append(attrs, a)
+	}
+
+	// HTML infers the namespaces of SVG and MathML, XHTML does not.
+	switch {
+	case n.Namespace == "svg" && n.Data == "svg":
+		if !hasXmlns {
+			attrs = append(attrs, html.Attribute{Key: "xmlns", Val: "http://www.w3.org/2000/svg"})
+		}
+
+		if !hasXlink {
+			attrs = append(attrs, html.Attribute{Namespace: "xmlns", Key: "xlink", Val: "http://www.w3.org/1999/xlink"})
+		}
+	case n.Namespace == "math" && n.Data == "math" && !hasXmlns:
+		attrs = append(attrs, html.Attribute{Key: "xmlns", Val: "http://www.w3.org/1998/Math/MathML"})
+	}
+
+	return attrs
+}
+
+// chapterLink updates a link to a chapter, e.g., "document.html#section" ->
+// "document.xhtml#section". Other links are unchanged.
+func chapterLink(href string, links map[string]string) string {
+	u, err := url.Parse(href)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return href
+	}
+
+	name, ok := links[u.Path]
+	if !ok {
+		return href
+	}
+
+	u.Path = name
+
+	return u.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	walk(n)
+
+	return b.String()
+}
+
+var templates = template.Must(template.New("epub").Funcs(template.FuncMap{
+	"escape": func(s string) (string, error) {
+		var b strings.Builder
+		err := xml.EscapeText(&b, []byte(s))
+
+		return b.String(), err
+	},
+	"href": func(name string) string {
+		return (&url.URL{Path: name}).String()
+	},
+	"mediaType": func(name string) string {
+		return mediaTypes[strings.ToLower(filepath.Ext(name))]
+	},
+}).Parse(`
+{{- define "container.xml" -}}
+<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+{{ end -}}
+
+{{- define "content.opf" -}}
+<?xml version="1.0" encoding="UTF-8"?>
+<package version="3.0" xmlns="http://www.idpf.org/2007/opf" unique-identifier="identifier" xml:lang="{{ escape .Language }}">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="identifier">{{ escape .Identifier }}</dc:identifier>
+    <dc:title>{{ escape .Title }}</dc:title>
+    <dc:language>{{ escape .Language }}</dc:language>
+    {{- if .Author }}
+    <dc:creator>{{ escape .Author }}</dc:creator>
+    {{- end }}
+    <meta property="dcterms:modified">{{ .Modified }}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    {{- range $i, $c := .Chapters }}
+    <item id="chapter{{ $i }}" href="{{ escape (href $c.Name) }}" media-type="application/xhtml+xml"/>
+    {{- end }}
+    {{- range $i, $r := .Resources }}
+    <item id="resource{{ $i }}" href="{{ escape (href $r.Name) }}" media-type="{{ mediaType $r.Name }}"{{ if eq $r.Name $.Cover }} properties="cover-image"{{ end }}/>
+    {{- end }}
+  </manifest>
+  <spine>
+    {{- range $i, $c := .Chapters }}
+    <itemref idref="chapter{{ $i }}"/>
+    {{- end }}
+  </spine>
+</package>
+{{ end -}}
+
+{{- define "nav.xhtml" -}}
+<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{ escape .Language }}" lang="{{ escape .Language }}">
+<head>
+<meta charset="UTF-8"/>
+<title>{{ escape .Title }}</title>
+</head>
+<body>
+<nav epub:type="toc" id="toc">
+<h1>{{ escape .Title }}</h1>
+<ol>
+{{- range .Chapters }}
+<li><a href="{{ escape (href .Name) }}">{{ escape .Title }}</a></li>
+{{- end }}
+</ol>
+</nav>
+</body>
+</html>
+{{ end -}}
+
+{{- define "chapter.xhtml" -}}
+<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{ escape .Language }}" lang="{{ escape .Language }}">
+<head>
+<meta charset="UTF-8"/>
+<title>{{ escape .Chapter.Title }}</title>
+{{- range .Chapter.Styles }}
+<link rel="stylesheet" type="text/css" href="{{ escape . }}"/>
+{{- end }}
+</head>
+<body>
+{{ .Chapter.Body }}
+</body>
+</html>
+{{ end -}}
+`))
+
+// write writes the book to an EPUB file.
+func (b *book) write(outputPath string) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create EPUB file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	w := zip.NewWriter(f)
+
+	// The mimetype file comes first, without compression.
+	mimetype, err := w.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return fmt.Errorf("create mimetype: %w", err)
+	}
+
+	_, err = io.WriteString(mimetype, "application/epub+zip")
+	if err != nil {
+		return fmt.Errorf("write mimetype: %w", err)
+	}
+
+	execute := func(name, tmpl string, data any) error {
+		fw, err := w.Create(name)
+		if err != nil {
+			return fmt.Errorf("create '%s': %w", name, err)
+		}
+
+		err = templates.ExecuteTemplate(fw, tmpl, data)
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", name, err)
+		}
+
+		return nil
+	}
+
+	err = execute("META-INF/container.xml", "container.xml", b)
+	if err != nil {
+		return err
+	}
+
+	err = execute("OEBPS/content.opf", "content.opf", b)
+	if err != nil {
+		return err
+	}
+
+	err = execute("OEBPS/nav.xhtml", "nav.xhtml", b)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range b.Chapters {
+		err = execute("OEBPS/"+c.Name, "chapter.xhtml", struct {
+			Language string
+			Chapter  chapter
+		}{
+			Language: b.Language,
+			Chapter:  c,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, r := range b.Resources {
+		fw, err := w.Create("OEBPS/" + r.Name)
+		if err != nil {
+			return fmt.Errorf("create '%s': %w", r.Name, err)
+		}
+
+		_, err = fw.Write(r.Content)
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", r.Name, err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("close EPUB file: %w", err)
+	}
+
+	return nil
+}
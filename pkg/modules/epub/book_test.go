@@ -0,0 +1,261 @@
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTestFiles writes files to a temporary directory and returns their
+// paths, in the same order.
+func writeTestFiles(t *testing.T, files ...[2]string) []string {
+	dirPath := t.TempDir()
+	paths := make([]string, len(files))
+
+	for i, file := range files {
+		paths[i] = filepath.Join(dirPath, file[0])
+
+		err := os.WriteFile(paths[i], []byte(file[1]), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	return paths
+}
+
+const testHtmlChapter = `<!DOCTYPE html>
+<html>
+<head>
+<title>Introduction</title>
+<link rel="stylesheet" href="book.css">
+<style>p > em { color: red; }</style>
+<script>alert("foo")</script>
+</head>
+<body onload="alert('foo')">
+<h1 class="title" @click="foo">Welcome</h1>
+<p>See the <a href="usage.md#setup">setup</a> &amp; the <a href="https://gotenberg.dev">docs</a>.<br>
+<img src="logo.png" alt="Logo"></p>
+<svg viewBox="0 0 10 10"><use xlink:href="#dot"/></svg>
+<noscript><p>Enable JavaScript</p></noscript>
+<button onclick="alert('foo')" disabled>Ok</button>
+</body>
+</html>
+`
+
+func TestNewBook(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		meta           metadata
+		chapters       [][2]string
+		assets         [][2]string
+		expectTitle    string
+		expectChapters []chapter
+		expectNames    []string
+	}{
+		{
+			scenario: "HTML and markdown chapters",
+			meta:     metadata{Language: "en"},
+			chapters: [][2]string{
+				{"intro.html", testHtmlChapter},
+				{"usage.md", "# Usage\n\n## Setup\n\nRun *it*."},
+			},
+			assets: [][2]string{
+				{"book.css", "body { margin: 0; }"},
+				{"logo.png", "png"},
+			},
+			expectTitle: "Introduction",
+			expectChapters: []chapter{
+				{
+					Name:   "intro.xhtml",
+					Title:  "Introduction",
+					Styles: []string{"book.css", "intro-1.css"},
+					Body: `<h1 class="title">Welcome</h1>
+<p>See the <a href="usage.xhtml#setup">setup</a> &amp; the <a href="https://gotenberg.dev">docs</a>.<br/>
+<img src="logo.png" alt="Logo"/></p>
+<svg viewBox="0 0 10 10" xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><use xlink:href="#dot"></use></svg>
+
+<button disabled="">Ok</button>`,
+				},
+				{
+					Name:  "usage.xhtml",
+					Title: "Usage",
+					Body:  "<h1>Usage</h1>\n\n<h2>Setup</h2>\n\n<p>Run <em>it</em>.</p>",
+				},
+			},
+			expectNames: []string{"book.css", "logo.png", "intro-1.css"},
+		},
+		{
+			scenario: "chapters with the same name",
+			meta:     metadata{Title: "Book", Language: "en"},
+			chapters: [][2]string{
+				{"nav.html", "<p>Nav</p>"},
+				{"nav.md", "Markdown"},
+			},
+			expectTitle: "Book",
+			expectChapters: []chapter{
+				{
+					Name:  "nav-2.xhtml",
+					Title: "nav",
+					Body:  "<p>Nav</p>",
+				},
+				{
+					Name:  "nav-3.xhtml",
+					Title: "nav",
+					Body:  "<p>Markdown</p>",
+				},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			b, err := newBook(tc.meta, writeTestFiles(t, tc.chapters...), writeTestFiles(t, tc.assets...))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if b.Title != tc.expectTitle {
+				t.Errorf("expected title '%s' but got '%s'", tc.expectTitle, b.Title)
+			}
+
+			if !reflect.DeepEqual(b.Chapters, tc.expectChapters) {
+				t.Errorf("expected chapters %+v but got %+v", tc.expectChapters, b.Chapters)
+			}
+
+			var names []string
+			for _, r := range b.Resources {
+				names = append(names, r.Name)
+			}
+
+			if !reflect.DeepEqual(names, tc.expectNames) {
+				t.Errorf("expected resources %v but got %v", tc.expectNames, names)
+			}
+		})
+	}
+}
+
+func TestBook_write(t *testing.T) {
+	meta := metadata{
+		Title:      "Gotenberg & Co",
+		Author:     "Gotenberg",
+		Language:   "fr",
+		Identifier: "urn:uuid:foo",
+		Cover:      "logo.png",
+	}
+
+	b, err := newBook(
+		meta,
+		writeTestFiles(t, [2]string{"intro.html", testHtmlChapter}, [2]string{"my usage.md", "# Usage"}),
+		writeTestFiles(t, [2]string{"book.css", "body {}"}, [2]string{"logo.png", "png"}),
+	)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "book.epub")
+
+	err = b.write(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	r, err := zip.OpenReader(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = r.Close()
+	}()
+
+	if r.File[0].Name != "mimetype" || r.File[0].Method != zip.Store {
+		t.Fatalf("expected an uncompressed mimetype file first but got '%s'", r.File[0].Name)
+	}
+
+	var names []string
+	contents := make(map[string]string)
+
+	for _, f := range r.File {
+		names = append(names, f.Name)
+
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		content, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		contents[f.Name] = string(content)
+
+		// The XHTML and XML files must be well-formed.
+		ext := filepath.Ext(f.Name)
+		if ext != ".xhtml" && ext != ".opf" && ext != ".xml" {
+			continue
+		}
+
+		decoder := xml.NewDecoder(strings.NewReader(string(content)))
+		for {
+			_, err = decoder.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				t.Fatalf("expected well-formed '%s' but got: %v\n%s", f.Name, err, content)
+			}
+		}
+	}
+
+	expectNames := []string{
+		"mimetype",
+		"META-INF/container.xml",
+		"OEBPS/content.opf",
+		"OEBPS/nav.xhtml",
+		"OEBPS/intro.xhtml",
+		"OEBPS/my usage.xhtml",
+		"OEBPS/book.css",
+		"OEBPS/logo.png",
+		"OEBPS/intro-1.css",
+	}
+
+	if !reflect.DeepEqual(names, expectNames) {
+		t.Errorf("expected files %v but got %v", expectNames, names)
+	}
+
+	for name, expect := range map[string][]string{
+		"mimetype": {"application/epub+zip"},
+		"OEBPS/content.opf": {
+			`<dc:title>Gotenberg &amp; Co</dc:title>`,
+			`<dc:creator>Gotenberg</dc:creator>`,
+			`<dc:language>fr</dc:language>`,
+			`<dc:identifier id="identifier">urn:uuid:foo</dc:identifier>`,
+			`href="my%20usage.xhtml"`,
+			`href="logo.png" media-type="image/png" properties="cover-image"`,
+			`<itemref idref="chapter1"/>`,
+		},
+		"OEBPS/nav.xhtml": {
+			`<li><a href="intro.xhtml">Introduction</a></li>`,
+			`<li><a href="my%20usage.xhtml">Usage</a></li>`,
+		},
+		"OEBPS/intro.xhtml": {
+			`<link rel="stylesheet" type="text/css" href="intro-1.css"/>`,
+			`<h1 class="title">Welcome</h1>`,
+		},
+		"OEBPS/intro-1.css": {"p > em { color: red; }"},
+	} {
+		for _, e := range expect {
+			if !strings.Contains(contents[name], e) {
+				t.Errorf("expected '%s' in '%s' but got:\n%s", e, name, contents[name])
+			}
+		}
+	}
+}
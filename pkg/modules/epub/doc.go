@@ -0,0 +1,4 @@
+// Package epub provides a module which adds routes for converting documents
+// to EPUB: HTML and markdown files are packaged as the chapters of a book,
+// while LibreOffice exports the office documents.
+package epub
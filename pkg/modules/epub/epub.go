@@ -0,0 +1,74 @@
+package epub
+
+import (
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Epub))
+}
+
+// Epub is a module which provides routes for converting documents to EPUB.
+type Epub struct {
+	libreOffice   libreofficeapi.Uno
+	disableRoutes bool
+}
+
+// Descriptor returns an [Epub]'s module descriptor.
+func (mod *Epub) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "epub",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("epub", flag.ExitOnError)
+			fs.Bool("epub-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Epub) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Epub) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("epub-disable-routes")
+
+	provider, err := ctx.Module(new(libreofficeapi.Provider))
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno provider: %w", err)
+	}
+
+	libreOffice, err := provider.(libreofficeapi.Provider).LibreOffice()
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno: %w", err)
+	}
+
+	mod.libreOffice = libreOffice
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Epub) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		convertHtmlRoute(),
+		convertLibreOfficeRoute(mod.libreOffice),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Epub)(nil)
+	_ gotenberg.Provisioner = (*Epub)(nil)
+	_ api.Router            = (*Epub)(nil)
+)
@@ -0,0 +1,120 @@
+package epub
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestEpub_Descriptor(t *testing.T) {
+	descriptor := new(Epub).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Epub))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestEpub_Provision(t *testing.T) {
+	libreOfficeProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			libreofficeapi.ProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "libreoffice", New: func() gotenberg.Module { return mod }}
+		}
+		mod.LibreOfficeMock = func() (libreofficeapi.Uno, error) {
+			return new(libreofficeapi.ApiMock), err
+		}
+
+		return mod
+	}
+
+	newContext := func(mods ...gotenberg.Module) *gotenberg.Context {
+		descriptors := make([]gotenberg.ModuleDescriptor, len(mods))
+		for i, mod := range mods {
+			descriptors[i] = mod.Descriptor()
+		}
+
+		return gotenberg.NewContext(
+			gotenberg.ParsedFlags{
+				FlagSet: new(Epub).Descriptor().FlagSet,
+			},
+			descriptors,
+		)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectError bool
+	}{
+		{
+			scenario:    "no LibreOffice API provider",
+			ctx:         newContext(),
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice API from LibreOffice API provider",
+			ctx:         newContext(libreOfficeProvider(errors.New("foo"))),
+			expectError: true,
+		},
+		{
+			scenario:    "provision success",
+			ctx:         newContext(libreOfficeProvider(nil)),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Epub)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestEpub_Routes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		expectRoutes  int
+		disableRoutes bool
+	}{
+		{
+			scenario:      "routes not disabled",
+			expectRoutes:  2,
+			disableRoutes: false,
+		},
+		{
+			scenario:      "routes disabled",
+			expectRoutes:  0,
+			disableRoutes: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Epub)
+			mod.disableRoutes = tc.disableRoutes
+
+			routes, err := mod.Routes()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectRoutes != len(routes) {
+				t.Errorf("expected %d routes but got %d", tc.expectRoutes, len(routes))
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package epub
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+// convertHtmlRoute returns an [api.Route] which can package HTML and
+// markdown files as an EPUB, each file being a chapter.
+func convertHtmlRoute() api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/epub/convert/html",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				chapterPaths []string
+				assetPaths   []string
+				meta         metadata
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths(chapterExtensions, &chapterPaths).
+				Paths(assetExtensions(), &assetPaths).
+				String("title", &meta.Title, "").
+				String("author", &meta.Author, "").
+				String("language", &meta.Language, "en").
+				String("identifier", &meta.Identifier, "").
+				Custom("cover", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					for _, assetPath := range assetPaths {
+						if filepath.Base(assetPath) == value && strings.HasPrefix(mediaTypes[strings.ToLower(filepath.Ext(value))], "image/") {
+							meta.Cover = value
+							return nil
+						}
+					}
+
+					return fmt.Errorf("no image named '%s'", value)
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			if meta.Identifier == "" {
+				meta.Identifier = fmt.Sprintf("urn:uuid:%s", uuid.NewString())
+			}
+
+			b, err := newBook(meta, chapterPaths, assetPaths)
+			if err != nil {
+				return fmt.Errorf("create book: %w", err)
+			}
+
+			outputPath := ctx.GeneratePath("", ".epub")
+
+			err = b.write(outputPath)
+			if err != nil {
+				return fmt.Errorf("write book: %w", err)
+			}
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// convertLibreOfficeRoute returns an [api.Route] which can convert office
+// documents to EPUB with the LibreOffice EPUB export filter.
+func convertLibreOfficeRoute(libreOffice libreofficeapi.Uno) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/epub/convert/libreoffice",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var inputPaths []string
+
+			err := ctx.FormData().
+				MandatoryPaths(libreOffice.Extensions(), &inputPaths).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// Alright, let's convert each document to EPUB.
+			ctx.AddEngines("libreoffice")
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				// document.docx -> document.docx.epub.
+				outputPaths[i] = ctx.GeneratePath(filepath.Base(inputPath), ".epub")
+
+				err = libreOffice.Convert(ctx, ctx.Log(), inputPath, outputPaths[i], libreofficeapi.ConvertOptions{
+					Format: "epub",
+				})
+				if err != nil {
+					return fmt.Errorf("convert to EPUB: %w", err)
+				}
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package epub
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func newTestContext(t *testing.T, files map[string]string, values map[string][]string) *api.ContextMock {
+	dirPath := t.TempDir()
+	paths := make(map[string]string)
+
+	for filename, content := range files {
+		path := filepath.Join(dirPath, filename)
+
+		err := os.WriteFile(path, []byte(content), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		paths[filename] = path
+	}
+
+	ctx := &api.ContextMock{Context: new(api.Context)}
+	ctx.SetDirPath(dirPath)
+	ctx.SetFiles(paths)
+	ctx.SetValues(values)
+
+	return ctx
+}
+
+func assertRouteResult(t *testing.T, ctx *api.ContextMock, err error, expectError, expectHttpError bool, expectHttpStatus, expectOutputPathsCount int) {
+	if expectError && err == nil {
+		t.Fatal("expected error but got none", err)
+	}
+
+	if !expectError && err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var httpErr api.HttpError
+	isHttpError := errors.As(err, &httpErr)
+
+	if expectHttpError && !isHttpError {
+		t.Errorf("expected an HTTP error but got: %v", err)
+	}
+
+	if !expectHttpError && isHttpError {
+		t.Errorf("expected no HTTP error but got one: %v", httpErr)
+	}
+
+	if err != nil && expectHttpError && isHttpError {
+		status, _ := httpErr.HttpError()
+		if status != expectHttpStatus {
+			t.Errorf("expected %d as HTTP status code but got %d", expectHttpStatus, status)
+		}
+	}
+
+	if expectOutputPathsCount != len(ctx.OutputPaths()) {
+		t.Errorf("expected %d output paths but got %d", expectOutputPathsCount, len(ctx.OutputPaths()))
+	}
+}
+
+func TestConvertHtmlRoute(t *testing.T) {
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newTestContext(t, map[string]string{"logo.png": "png"}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "cover not found",
+			ctx: newTestContext(t, map[string]string{"index.html": "<p>Foo</p>", "book.css": "body {}"}, map[string][]string{
+				"cover": {"book.css"},
+			}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success",
+			ctx: newTestContext(t, map[string]string{"index.html": "<p>Foo</p>", "chapter.md": "# Bar", "logo.png": "png"}, map[string][]string{
+				"title":      {"Foo"},
+				"author":     {"Bar"},
+				"language":   {"fr"},
+				"identifier": {"isbn:foo"},
+				"cover":      {"logo.png"},
+			}),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertHtmlRoute().Handler(c)
+
+			assertRouteResult(t, tc.ctx, err, tc.expectError, tc.expectHttpError, tc.expectHttpStatus, tc.expectOutputPathsCount)
+		})
+	}
+}
+
+func TestConvertLibreOfficeRoute(t *testing.T) {
+	libreOffice := func(err error) libreofficeapi.Uno {
+		return &libreofficeapi.ApiMock{
+			ConvertMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.ConvertOptions) error {
+				if options.Format != "epub" {
+					return errors.New("wrong format")
+				}
+
+				return err
+			},
+			ExtensionsMock: func() []string {
+				return []string{".docx"}
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		libreOffice            libreofficeapi.Uno
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newTestContext(t, nil, nil),
+			libreOffice:            libreOffice(nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from LibreOffice",
+			ctx:                    newTestContext(t, map[string]string{"document.docx": "docx"}, nil),
+			libreOffice:            libreOffice(errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "success",
+			ctx:                    newTestContext(t, map[string]string{"a.docx": "docx", "b.docx": "docx"}, nil),
+			libreOffice:            libreOffice(nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertLibreOfficeRoute(tc.libreOffice).Handler(c)
+
+			assertRouteResult(t, tc.ctx, err, tc.expectError, tc.expectHttpError, tc.expectHttpStatus, tc.expectOutputPathsCount)
+		})
+	}
+}
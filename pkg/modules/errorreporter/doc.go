@@ -0,0 +1,5 @@
+// Package errorreporter provides a module which sends the errors of the
+// requests to Sentry or to a generic HTTP endpoint.
+//
+// See: https://sentry.io/.
+package errorreporter
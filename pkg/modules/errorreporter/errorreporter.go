@@ -0,0 +1,315 @@
+package errorreporter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(ErrorReporter))
+}
+
+// sensitiveOptionRegexp matches the form fields which values must not leave
+// Gotenberg, e.g., extraHttpHeaders or cookies.
+var sensitiveOptionRegexp = regexp.MustCompile(`(?i)(header|cookie|password|secret|token|auth)`)
+
+// absolutePathRegexp matches the absolute paths within the error messages,
+// e.g., of the working directories of the requests.
+var absolutePathRegexp = regexp.MustCompile(`(?:/[^\s'"/:;,()\[\]]+)+`)
+
+// ErrorReporter is a module which sends the errors of the requests to Sentry
+// or to a generic HTTP endpoint.
+type ErrorReporter struct {
+	sentryDsn   string
+	httpUrl     string
+	environment string
+	minStatus   int
+	timeout     time.Duration
+
+	sinks  []sink
+	client *http.Client
+	logger *zap.Logger
+}
+
+// sink creates the HTTP request to send a report to a destination.
+type sink interface {
+	request(report api.ErrorReport) (*http.Request, error)
+}
+
+// Descriptor returns an [ErrorReporter]'s module descriptor.
+func (mod *ErrorReporter) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "error-reporter",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("error-reporter", flag.ExitOnError)
+			fs.String("error-reporter-sentry-dsn", "", "Set the Sentry DSN to which the errors are sent, or a reference to a secret")
+			fs.String("error-reporter-http-url", "", "Set the URL of a generic HTTP endpoint to which the errors are sent as JSON")
+			fs.String("error-reporter-environment", "", "Set the environment of the reported errors")
+			fs.Int("error-reporter-min-status", http.StatusInternalServerError, "Set the minimum HTTP status code of the errors to report")
+			fs.Duration("error-reporter-timeout", time.Duration(10)*time.Second, "Set the time limit for sending an error report")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(ErrorReporter) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *ErrorReporter) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.environment = flags.MustString("error-reporter-environment")
+	mod.minStatus = flags.MustInt("error-reporter-min-status")
+	mod.timeout = flags.MustDuration("error-reporter-timeout")
+
+	// The DSN and the URL may contain credentials.
+	sentryDsn, err := gotenberg.ResolveSecret(ctx, flags.MustString("error-reporter-sentry-dsn"))
+	if err != nil {
+		return fmt.Errorf("get Sentry DSN: %w", err)
+	}
+
+	httpUrl, err := gotenberg.ResolveSecret(ctx, flags.MustString("error-reporter-http-url"))
+	if err != nil {
+		return fmt.Errorf("get HTTP URL: %w", err)
+	}
+
+	mod.sentryDsn = sentryDsn
+	mod.httpUrl = httpUrl
+
+	if mod.sentryDsn == "" && mod.httpUrl == "" {
+		// Exit early.
+		return nil
+	}
+
+	if mod.sentryDsn != "" {
+		serverName, err := os.Hostname()
+		if err != nil {
+			return fmt.Errorf("get hostname: %w", err)
+		}
+
+		sentry, err := newSentrySink(mod.sentryDsn, mod.environment, serverName)
+		if err != nil {
+			return fmt.Errorf("create Sentry sink: %w", err)
+		}
+
+		mod.sinks = append(mod.sinks, sentry)
+	}
+
+	if mod.httpUrl != "" {
+		mod.sinks = append(mod.sinks, httpSink{url: mod.httpUrl})
+	}
+
+	mod.client = &http.Client{
+		Timeout: mod.timeout,
+	}
+
+	// Logger.
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *ErrorReporter) Validate() error {
+	var err error
+
+	if mod.minStatus < http.StatusBadRequest || mod.minStatus > 599 {
+		err = multierr.Append(err,
+			errors.New("minimum status must be between 400 and 599"),
+		)
+	}
+
+	if mod.timeout <= 0 {
+		err = multierr.Append(err,
+			errors.New("timeout must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// ReportError sends, in a goroutine, the sanitized report to the configured
+// destinations if its status is high enough.
+func (mod *ErrorReporter) ReportError(report api.ErrorReport) {
+	if len(mod.sinks) == 0 || report.Status < mod.minStatus {
+		return
+	}
+
+	report = sanitize(report)
+
+	go func() {
+		for _, s := range mod.sinks {
+			err := mod.send(s, report)
+			if err != nil {
+				mod.logger.Error(fmt.Sprintf("send error report: %s", err))
+			}
+		}
+	}()
+}
+
+// send sends the report to the given destination.
+func (mod *ErrorReporter) send(s sink, report api.ErrorReport) error {
+	req, err := s.request(report)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Gotenberg")
+
+	resp, err := mod.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send '%s' request to '%s': %w", req.Method, req.URL.Redacted(), err)
+	}
+
+	defer func() {
+		// Drain the body so that the connection may be reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		err := resp.Body.Close()
+		if err != nil {
+			mod.logger.Error(fmt.Sprintf("close response body from '%s': %s", req.URL.Redacted(), err))
+		}
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("send '%s' request to '%s': got status: '%s'", req.Method, req.URL.Redacted(), resp.Status)
+	}
+
+	mod.logger.Debug(fmt.Sprintf("error report '%s' sent to '%s'", report.Trace, req.URL.Redacted()))
+
+	return nil
+}
+
+// sanitize removes the sensitive data from a report: the values of the
+// sensitive form fields are redacted and the filenames are hashed, while
+// keeping their extensions. The same goes for the filenames and the absolute
+// paths within the error messages.
+func sanitize(report api.ErrorReport) api.ErrorReport {
+	report.Message = sanitizeMessage(report.Message, report.Filenames)
+	report.Error = sanitizeMessage(report.Error, report.Filenames)
+
+	if report.ErrorChain != nil {
+		chain := make([]string, len(report.ErrorChain))
+
+		for i, message := range report.ErrorChain {
+			chain[i] = sanitizeMessage(message, report.Filenames)
+		}
+
+		report.ErrorChain = chain
+	}
+
+	if report.Options != nil {
+		options := make(map[string][]string, len(report.Options))
+
+		for key, values := range report.Options {
+			if sensitiveOptionRegexp.MatchString(key) {
+				options[key] = []string{"[REDACTED]"}
+				continue
+			}
+
+			options[key] = values
+		}
+
+		report.Options = options
+	}
+
+	if report.Filenames != nil {
+		filenames := make([]string, len(report.Filenames))
+
+		for i, filename := range report.Filenames {
+			filenames[i] = sanitizeFilename(filename)
+		}
+
+		report.Filenames = filenames
+	}
+
+	return report
+}
+
+// sanitizeFilename replaces the name of a file by a short hash, so that
+// the reports of a same file may be correlated.
+func sanitizeFilename(filename string) string {
+	ext := filepath.Ext(filename)
+	sum := sha256.Sum256([]byte(strings.TrimSuffix(filename, ext)))
+
+	return fmt.Sprintf("%s%s", hex.EncodeToString(sum[:6]), ext)
+}
+
+// sanitizeMessage hashes the absolute paths, by their last element, and the
+// given filenames within an error message, e.g., the stderr of a tool.
+func sanitizeMessage(message string, filenames []string) string {
+	message = absolutePathRegexp.ReplaceAllStringFunc(message, func(path string) string {
+		return sanitizeFilename(filepath.Base(path))
+	})
+
+	// The longest filenames first, as they may contain the shortest ones.
+	sorted := slices.Clone(filenames)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(sorted[i]) > len(sorted[j])
+	})
+
+	for _, filename := range sorted {
+		message = strings.ReplaceAll(message, filename, sanitizeFilename(filename))
+	}
+
+	return message
+}
+
+// httpSink sends a report as JSON to a generic HTTP endpoint.
+type httpSink struct {
+	url string
+}
+
+func (s httpSink) request(report api.ErrorReport) (*http.Request, error) {
+	b, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("marshal report: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+
+	return req, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*ErrorReporter)(nil)
+	_ gotenberg.Provisioner = (*ErrorReporter)(nil)
+	_ gotenberg.Validator   = (*ErrorReporter)(nil)
+	_ api.ErrorReporter     = (*ErrorReporter)(nil)
+	_ sink                  = (*httpSink)(nil)
+)
@@ -0,0 +1,367 @@
+package errorreporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestErrorReporter_Descriptor(t *testing.T) {
+	descriptor := new(ErrorReporter).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(ErrorReporter))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestErrorReporter_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectSinks int
+		expectError bool
+	}{
+		{
+			scenario: "no destination",
+			ctx: func() *gotenberg.Context {
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(ErrorReporter).Descriptor().FlagSet,
+					},
+					nil,
+				)
+			}(),
+			expectSinks: 0,
+		},
+		{
+			scenario: "invalid Sentry DSN",
+			ctx: func() *gotenberg.Context {
+				fs := new(ErrorReporter).Descriptor().FlagSet
+				err := fs.Parse([]string{"--error-reporter-sentry-dsn=https://sentry.io/1"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectError: true,
+		},
+		{
+			scenario: "no logger provider",
+			ctx: func() *gotenberg.Context {
+				fs := new(ErrorReporter).Descriptor().FlagSet
+				err := fs.Parse([]string{"--error-reporter-http-url=http://localhost/errors"})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+			}(),
+			expectSinks: 1,
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			ctx: func() *gotenberg.Context {
+				provider := &struct {
+					gotenberg.ModuleMock
+					gotenberg.LoggerProviderMock
+				}{}
+				provider.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "foo", New: func() gotenberg.Module { return provider }}
+				}
+				provider.LoggerMock = func(mod gotenberg.Module) (*zap.Logger, error) {
+					return zap.NewNop(), nil
+				}
+
+				fs := new(ErrorReporter).Descriptor().FlagSet
+				err := fs.Parse([]string{
+					"--error-reporter-sentry-dsn=https://public@sentry.io/1",
+					"--error-reporter-http-url=http://localhost/errors",
+				})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{FlagSet: fs},
+					[]gotenberg.ModuleDescriptor{
+						provider.Descriptor(),
+					},
+				)
+			}(),
+			expectSinks: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(ErrorReporter)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if len(mod.sinks) != tc.expectSinks {
+				t.Errorf("expected %d sinks but got %d", tc.expectSinks, len(mod.sinks))
+			}
+		})
+	}
+}
+
+func TestErrorReporter_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		minStatus   int
+		timeout     time.Duration
+		expectError bool
+	}{
+		{
+			scenario:    "invalid minimum status",
+			minStatus:   http.StatusOK,
+			timeout:     time.Duration(10) * time.Second,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid timeout",
+			minStatus:   http.StatusInternalServerError,
+			timeout:     0,
+			expectError: true,
+		},
+		{
+			scenario:    "validate success",
+			minStatus:   http.StatusInternalServerError,
+			timeout:     time.Duration(10) * time.Second,
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(ErrorReporter)
+			mod.minStatus = tc.minStatus
+			mod.timeout = tc.timeout
+			err := mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestErrorReporter_ReportError(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		report       api.ErrorReport
+		expectReport bool
+	}{
+		{
+			scenario: "status lower than the minimum status",
+			report: api.ErrorReport{
+				Status: http.StatusBadRequest,
+			},
+			expectReport: false,
+		},
+		{
+			scenario: "report sent",
+			report: api.ErrorReport{
+				Trace:     "foo",
+				Status:    http.StatusInternalServerError,
+				Filenames: []string{"secret.docx"},
+			},
+			expectReport: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			reports := make(chan api.ErrorReport, 1)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var report api.ErrorReport
+				err := json.NewDecoder(r.Body).Decode(&report)
+				if err != nil {
+					t.Errorf("expected no error but got: %v", err)
+				}
+
+				reports <- report
+			}))
+			defer srv.Close()
+
+			mod := new(ErrorReporter)
+			mod.minStatus = http.StatusInternalServerError
+			mod.sinks = []sink{httpSink{url: srv.URL}}
+			mod.client = srv.Client()
+			mod.logger = zap.NewNop()
+
+			mod.ReportError(tc.report)
+
+			select {
+			case report := <-reports:
+				if !tc.expectReport {
+					t.Fatal("expected no report but got one")
+				}
+
+				if report.Trace != tc.report.Trace {
+					t.Errorf("expected trace '%s' but got '%s'", tc.report.Trace, report.Trace)
+				}
+
+				if reflect.DeepEqual(report.Filenames, tc.report.Filenames) {
+					t.Errorf("expected sanitized filenames but got %v", report.Filenames)
+				}
+			case <-time.After(time.Duration(500) * time.Millisecond):
+				if tc.expectReport {
+					t.Fatal("expected a report but got none")
+				}
+			}
+		})
+	}
+}
+
+func TestErrorReporter_send(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		status      int
+		expectError bool
+	}{
+		{
+			scenario:    "error status",
+			status:      http.StatusBadRequest,
+			expectError: true,
+		},
+		{
+			scenario:    "success",
+			status:      http.StatusOK,
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("User-Agent") != "Gotenberg" {
+					t.Errorf("expected 'Gotenberg' User-Agent but got '%s'", r.Header.Get("User-Agent"))
+				}
+
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			mod := new(ErrorReporter)
+			mod.client = srv.Client()
+			mod.logger = zap.NewNop()
+
+			err := mod.send(httpSink{url: srv.URL}, api.ErrorReport{})
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+
+	t.Run("unreachable destination", func(t *testing.T) {
+		mod := new(ErrorReporter)
+		mod.client = http.DefaultClient
+		mod.logger = zap.NewNop()
+
+		err := mod.send(httpSink{url: "http://localhost:0"}, api.ErrorReport{})
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		mod := new(ErrorReporter)
+
+		err := mod.send(httpSink{url: "\n"}, api.ErrorReport{})
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+}
+
+func TestSanitize(t *testing.T) {
+	report := api.ErrorReport{
+		Options: map[string][]string{
+			"landscape":        {"true"},
+			"extraHttpHeaders": {`{"Authorization":"Bearer foo"}`},
+			"cookies":          {"foo=bar"},
+			"userPassword":     {"foo"},
+		},
+		Filenames: []string{"secret.docx", "index.html"},
+		Message:   "Cannot read the properties of 'secret.docx'",
+		Error:     "convert: open /tmp/1234/5678/secret.docx: no such file or directory",
+		ErrorChain: []string{
+			"convert: open /tmp/1234/5678/secret.docx: no such file or directory",
+			"open /tmp/1234/5678/secret.docx: no such file or directory",
+		},
+	}
+
+	actual := sanitize(report)
+
+	expectMessage := fmt.Sprintf("Cannot read the properties of '%s'", sanitizeFilename("secret.docx"))
+	if actual.Message != expectMessage {
+		t.Errorf("expected message '%s' but got '%s'", expectMessage, actual.Message)
+	}
+
+	expectError := fmt.Sprintf("convert: open %s: no such file or directory", sanitizeFilename("secret.docx"))
+	if actual.Error != expectError {
+		t.Errorf("expected error '%s' but got '%s'", expectError, actual.Error)
+	}
+
+	expectChain := []string{expectError, fmt.Sprintf("open %s: no such file or directory", sanitizeFilename("secret.docx"))}
+	if !reflect.DeepEqual(actual.ErrorChain, expectChain) {
+		t.Errorf("expected error chain %v but got %v", expectChain, actual.ErrorChain)
+	}
+
+	expectOptions := map[string][]string{
+		"landscape":        {"true"},
+		"extraHttpHeaders": {"[REDACTED]"},
+		"cookies":          {"[REDACTED]"},
+		"userPassword":     {"[REDACTED]"},
+	}
+
+	if !reflect.DeepEqual(actual.Options, expectOptions) {
+		t.Errorf("expected %v but got %v", expectOptions, actual.Options)
+	}
+
+	expectFilenames := []string{sanitizeFilename("secret.docx"), sanitizeFilename("index.html")}
+
+	if !reflect.DeepEqual(actual.Filenames, expectFilenames) {
+		t.Errorf("expected %v but got %v", expectFilenames, actual.Filenames)
+	}
+
+	if report.Filenames[0] != "secret.docx" || report.ErrorChain[1] != "open /tmp/1234/5678/secret.docx: no such file or directory" {
+		t.Error("expected the original report to be left untouched")
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	actual := sanitizeFilename("secret.docx")
+
+	if actual == "secret.docx" || len(actual) != len("000000000000.docx") {
+		t.Errorf("expected a hashed filename with the .docx extension but got '%s'", actual)
+	}
+
+	if actual != sanitizeFilename("secret.docx") {
+		t.Error("expected the same hash for the same filename")
+	}
+}
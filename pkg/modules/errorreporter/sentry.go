@@ -0,0 +1,155 @@
+package errorreporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// sentrySink sends a report as an event to the store endpoint of a Sentry
+// project.
+//
+// See: https://develop.sentry.dev/sdk/store/.
+type sentrySink struct {
+	storeUrl    string
+	auth        string
+	environment string
+	serverName  string
+}
+
+// sentryEvent is the payload of a Sentry event.
+type sentryEvent struct {
+	EventId     string                `json:"event_id"`
+	Timestamp   string                `json:"timestamp"`
+	Level       string                `json:"level"`
+	Platform    string                `json:"platform"`
+	Logger      string                `json:"logger"`
+	ServerName  string                `json:"server_name,omitempty"`
+	Environment string                `json:"environment,omitempty"`
+	Transaction string                `json:"transaction,omitempty"`
+	Exception   sentryEventExceptions `json:"exception"`
+	Tags        map[string]string     `json:"tags,omitempty"`
+	Extra       map[string]any        `json:"extra,omitempty"`
+}
+
+type sentryEventExceptions struct {
+	Values []sentryEventException `json:"values"`
+}
+
+type sentryEventException struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// newSentrySink parses a Sentry DSN, i.e.,
+// "{PROTOCOL}://{PUBLIC_KEY}:{SECRET_KEY}@{HOST}{PATH}/{PROJECT_ID}", and
+// creates a [sentrySink].
+func newSentrySink(dsn, environment, serverName string) (sentrySink, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return sentrySink{}, fmt.Errorf("parse DSN: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return sentrySink{}, fmt.Errorf("unsupported DSN scheme '%s'", u.Scheme)
+	}
+
+	if u.User == nil || u.User.Username() == "" {
+		return sentrySink{}, errors.New("DSN without public key")
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	i := strings.LastIndex(path, "/")
+	projectId := path[i+1:]
+
+	if projectId == "" {
+		return sentrySink{}, errors.New("DSN without project identifier")
+	}
+
+	auth := fmt.Sprintf("Sentry sentry_version=7, sentry_client=gotenberg, sentry_key=%s", u.User.Username())
+
+	secret, ok := u.User.Password()
+	if ok && secret != "" {
+		auth = fmt.Sprintf("%s, sentry_secret=%s", auth, secret)
+	}
+
+	return sentrySink{
+		storeUrl:    fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, path[:i], projectId),
+		auth:        auth,
+		environment: environment,
+		serverName:  serverName,
+	}, nil
+}
+
+func (s sentrySink) request(report api.ErrorReport) (*http.Request, error) {
+	b, err := json.Marshal(s.event(report))
+	if err != nil {
+		return nil, fmt.Errorf("marshal event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.storeUrl, bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", s.auth)
+
+	return req, nil
+}
+
+// event converts a report to a [sentryEvent]. The error chain becomes the
+// exceptions, from the innermost to the outermost, as expected by Sentry.
+func (s sentrySink) event(report api.ErrorReport) sentryEvent {
+	exceptions := make([]sentryEventException, len(report.ErrorChain))
+	for i, message := range report.ErrorChain {
+		exceptions[len(report.ErrorChain)-1-i] = sentryEventException{
+			Type:  "error",
+			Value: message,
+		}
+	}
+
+	if len(exceptions) > 0 {
+		exceptions[len(exceptions)-1].Type = report.Code
+	}
+
+	return sentryEvent{
+		EventId:     strings.ReplaceAll(uuid.New().String(), "-", ""),
+		Timestamp:   report.Time.UTC().Format("2006-01-02T15:04:05.000Z"),
+		Level:       "error",
+		Platform:    "go",
+		Logger:      "gotenberg",
+		ServerName:  s.serverName,
+		Environment: s.environment,
+		Transaction: fmt.Sprintf("%s %s", report.Method, report.Route),
+		Exception: sentryEventExceptions{
+			Values: exceptions,
+		},
+		Tags: map[string]string{
+			"trace":  report.Trace,
+			"route":  report.Route,
+			"code":   report.Code,
+			"status": strconv.Itoa(report.Status),
+			"async":  strconv.FormatBool(report.Async),
+		},
+		Extra: map[string]any{
+			"message":   report.Message,
+			"options":   report.Options,
+			"filenames": report.Filenames,
+		},
+	}
+}
+
+// Interface guards.
+var (
+	_ sink = (*sentrySink)(nil)
+)
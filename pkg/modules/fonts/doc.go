@@ -0,0 +1,5 @@
+// Package fonts provides a module which warms up the fontconfig cache at
+// startup, loads extra fonts from directories (e.g., a mounted volume), and
+// exposes the installed font families via an HTTP route, so that rendering
+// issues related to fonts can be diagnosed remotely.
+package fonts
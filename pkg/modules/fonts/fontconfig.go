@@ -0,0 +1,138 @@
+package fonts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultConfigPath is the main fontconfig configuration file.
+const defaultConfigPath = "/etc/fonts/fonts.conf"
+
+// listFormat is the output format of fc-list, i.e., one font per line with
+// its main family, its main style and its file, separated by tabulations.
+const listFormat = "%{family[0]}\t%{style[0]}\t%{file}\n"
+
+// Family gathers the styles and files of a font family.
+type Family struct {
+	Name   string   `json:"name"`
+	Styles []string `json:"styles"`
+	Files  []string `json:"files"`
+}
+
+// Inventory is the response of the fonts route.
+type Inventory struct {
+	Count    int      `json:"count"`
+	Families []Family `json:"families"`
+}
+
+// writeConfig writes a fontconfig configuration file which includes the
+// given configuration file and adds the given font directories. It returns
+// the path of the new configuration file.
+func writeConfig(includePath string, dirs []string) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<!DOCTYPE fontconfig SYSTEM 'fonts.dtd'>\n<fontconfig>\n")
+
+	writeElement := func(name, value string) error {
+		buf.WriteString(fmt.Sprintf(" <%s>", name))
+		err := xml.EscapeText(&buf, []byte(value))
+		if err != nil {
+			return fmt.Errorf("escape '%s': %w", value, err)
+		}
+		buf.WriteString(fmt.Sprintf("</%s>\n", name))
+
+		return nil
+	}
+
+	err := writeElement("include", includePath)
+	if err != nil {
+		return "", err
+	}
+
+	for _, dir := range dirs {
+		err = writeElement("dir", dir)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	buf.WriteString("</fontconfig>\n")
+
+	file, err := os.CreateTemp("", "gotenberg-fonts-*.conf")
+	if err != nil {
+		return "", fmt.Errorf("create fontconfig configuration file: %w", err)
+	}
+
+	_, err = file.Write(buf.Bytes())
+	if err != nil {
+		_ = file.Close()
+		return "", fmt.Errorf("write fontconfig configuration file: %w", err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return "", fmt.Errorf("close fontconfig configuration file: %w", err)
+	}
+
+	return file.Name(), nil
+}
+
+// parseList parses the output of fc-list into font families, sorted by
+// name.
+func parseList(output []byte) Inventory {
+	families := make(map[string]*Family)
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 || fields[0] == "" {
+			continue
+		}
+
+		family, ok := families[fields[0]]
+		if !ok {
+			family = &Family{Name: fields[0]}
+			families[fields[0]] = family
+		}
+
+		family.Styles = appendUnique(family.Styles, fields[1])
+		family.Files = appendUnique(family.Files, fields[2])
+	}
+
+	inventory := Inventory{
+		Families: make([]Family, 0, len(families)),
+	}
+
+	for _, family := range families {
+		sort.Strings(family.Styles)
+		sort.Strings(family.Files)
+		inventory.Families = append(inventory.Families, *family)
+	}
+
+	sort.Slice(inventory.Families, func(i, j int) bool {
+		return inventory.Families[i].Name < inventory.Families[j].Name
+	})
+
+	inventory.Count = len(inventory.Families)
+
+	return inventory
+}
+
+func appendUnique(values []string, value string) []string {
+	if value == "" {
+		return values
+	}
+
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
@@ -0,0 +1,80 @@
+package fonts
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWriteConfig(t *testing.T) {
+	path, err := writeConfig("/etc/fonts/fonts.conf", []string{"/fonts", "/foo & bar"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = os.Remove(path)
+	}()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, expect := range []string{
+		"<include>/etc/fonts/fonts.conf</include>",
+		"<dir>/fonts</dir>",
+		"<dir>/foo &amp; bar</dir>",
+	} {
+		if !strings.Contains(string(b), expect) {
+			t.Errorf("expected '%s' in:\n%s", expect, string(b))
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		output   string
+		expect   Inventory
+	}{
+		{
+			scenario: "no font",
+			output:   "",
+			expect:   Inventory{Count: 0, Families: []Family{}},
+		},
+		{
+			scenario: "fonts",
+			output: "DejaVu Sans\tBold\t/usr/share/fonts/DejaVuSans-Bold.ttf\n" +
+				"Carlito\tRegular\t/fonts/Carlito-Regular.ttf\n" +
+				"DejaVu Sans\tBook\t/usr/share/fonts/DejaVuSans.ttf\n" +
+				"DejaVu Sans\tBook\t/usr/share/fonts/DejaVuSans.ttf\n" +
+				"malformed line\n" +
+				"\tBook\t/usr/share/fonts/foo.ttf\n",
+			expect: Inventory{
+				Count: 2,
+				Families: []Family{
+					{
+						Name:   "Carlito",
+						Styles: []string{"Regular"},
+						Files:  []string{"/fonts/Carlito-Regular.ttf"},
+					},
+					{
+						Name:   "DejaVu Sans",
+						Styles: []string{"Bold", "Book"},
+						Files:  []string{"/usr/share/fonts/DejaVuSans-Bold.ttf", "/usr/share/fonts/DejaVuSans.ttf"},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := parseList([]byte(tc.output))
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
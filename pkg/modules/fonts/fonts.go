@@ -0,0 +1,247 @@
+package fonts
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Fonts))
+}
+
+// Fonts is a module which warms up the fontconfig cache at startup, loads
+// extra fonts from directories, and exposes the installed font families via
+// an HTTP route.
+type Fonts struct {
+	fcCacheBinPath      string
+	fcListBinPath       string
+	extraDirs           []string
+	warmupTimeout       time.Duration
+	disableRouteLogging bool
+
+	logger     *zap.Logger
+	configPath string
+	families   int
+}
+
+// Descriptor returns a [Fonts]'s module descriptor.
+func (mod *Fonts) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "fonts",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("fonts", flag.ExitOnError)
+			fs.StringSlice("fonts-extra-dirs", make([]string, 0), "Set the directories of extra fonts to load at startup, e.g., a mounted volume")
+			fs.Duration("fonts-warmup-timeout", time.Duration(60)*time.Second, "Set the maximum duration of the font cache warmup")
+			fs.Bool("fonts-disable-route-logging", false, "Disable the route logging")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Fonts) },
+	}
+}
+
+// Provision sets the module properties. If there are extra font
+// directories, it points fontconfig to a configuration file which adds them,
+// so that the Chromium and LibreOffice processes load them too.
+func (mod *Fonts) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.extraDirs = flags.MustStringSlice("fonts-extra-dirs")
+	mod.warmupTimeout = flags.MustDuration("fonts-warmup-timeout")
+	mod.disableRouteLogging = flags.MustBool("fonts-disable-route-logging")
+
+	fcCacheBinPath, ok := os.LookupEnv("FC_CACHE_BIN_PATH")
+	if !ok {
+		return errors.New("FC_CACHE_BIN_PATH environment variable is not set")
+	}
+
+	mod.fcCacheBinPath = fcCacheBinPath
+
+	fcListBinPath, ok := os.LookupEnv("FC_LIST_BIN_PATH")
+	if !ok {
+		return errors.New("FC_LIST_BIN_PATH environment variable is not set")
+	}
+
+	mod.fcListBinPath = fcListBinPath
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	if len(mod.extraDirs) == 0 {
+		return nil
+	}
+
+	includePath := os.Getenv("FONTCONFIG_FILE")
+	if includePath == "" {
+		includePath = defaultConfigPath
+	}
+
+	mod.configPath, err = writeConfig(includePath, mod.extraDirs)
+	if err != nil {
+		return fmt.Errorf("write fontconfig configuration: %w", err)
+	}
+
+	err = os.Setenv("FONTCONFIG_FILE", mod.configPath)
+	if err != nil {
+		return fmt.Errorf("set FONTCONFIG_FILE environment variable: %w", err)
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Fonts) Validate() error {
+	var err error
+
+	_, statErr := os.Stat(mod.fcCacheBinPath)
+	if os.IsNotExist(statErr) {
+		err = multierr.Append(err, fmt.Errorf("fc-cache binary path does not exist: %w", statErr))
+	}
+
+	_, statErr = os.Stat(mod.fcListBinPath)
+	if os.IsNotExist(statErr) {
+		err = multierr.Append(err, fmt.Errorf("fc-list binary path does not exist: %w", statErr))
+	}
+
+	for _, dir := range mod.extraDirs {
+		info, statErr := os.Stat(dir)
+		if statErr != nil {
+			err = multierr.Append(err, fmt.Errorf("extra fonts directory '%s': %w", dir, statErr))
+			continue
+		}
+
+		if !info.IsDir() {
+			err = multierr.Append(err, fmt.Errorf("extra fonts directory '%s' is not a directory", dir))
+		}
+	}
+
+	if mod.warmupTimeout <= 0 {
+		err = multierr.Append(err, errors.New("warmup timeout must be strictly positive"))
+	}
+
+	return err
+}
+
+// Start warms up the font cache. A failure does not prevent Gotenberg from
+// starting, as the processes build the cache on their own otherwise.
+func (mod *Fonts) Start() error {
+	ctx, cancel := context.WithTimeout(context.Background(), mod.warmupTimeout)
+	defer cancel()
+
+	cmd, err := gotenberg.CommandContext(ctx, mod.logger, mod.fcCacheBinPath)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		mod.logger.Warn(fmt.Sprintf("warm up font cache: %s", err))
+	}
+
+	inventory, err := mod.inventory(ctx)
+	if err != nil {
+		mod.logger.Warn(err.Error())
+		return nil
+	}
+
+	mod.families = inventory.Count
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Fonts) StartupMessage() string {
+	return fmt.Sprintf("font cache warmed up, %d font families available", mod.families)
+}
+
+// Stop removes the fontconfig configuration file, if any.
+func (mod *Fonts) Stop(ctx context.Context) error {
+	if mod.configPath == "" {
+		return nil
+	}
+
+	err := os.Remove(mod.configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove fontconfig configuration file: %w", err)
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP route.
+func (mod *Fonts) Routes() ([]api.Route, error) {
+	return []api.Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/fonts",
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				inventory, err := mod.inventory(c.Request().Context())
+				if err != nil {
+					return fmt.Errorf("list fonts: %w", err)
+				}
+
+				return c.JSON(http.StatusOK, inventory)
+			},
+		},
+	}, nil
+}
+
+// Debug returns the font families known by fontconfig.
+func (mod *Fonts) Debug(ctx context.Context) (map[string]any, error) {
+	inventory, err := mod.inventory(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list fonts: %w", err)
+	}
+
+	return map[string]any{"fonts": inventory}, nil
+}
+
+// inventory lists the font families known by fontconfig.
+func (mod *Fonts) inventory(ctx context.Context) (Inventory, error) {
+	cmd, err := gotenberg.CommandContext(ctx, mod.logger, mod.fcListBinPath, "--format", listFormat)
+	if err != nil {
+		return Inventory{}, fmt.Errorf("create command: %w", err)
+	}
+
+	var output bytes.Buffer
+	cmd.SetStdout(&output)
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return Inventory{}, fmt.Errorf("list fonts with fc-list: %w", err)
+	}
+
+	return parseList(output.Bytes()), nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Fonts)(nil)
+	_ gotenberg.Provisioner = (*Fonts)(nil)
+	_ gotenberg.Validator   = (*Fonts)(nil)
+	_ gotenberg.App         = (*Fonts)(nil)
+	_ gotenberg.Debuggable  = (*Fonts)(nil)
+	_ api.Router            = (*Fonts)(nil)
+)
@@ -0,0 +1,325 @@
+package fonts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+func TestFonts_Descriptor(t *testing.T) {
+	descriptor := new(Fonts).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Fonts))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestFonts_Provision(t *testing.T) {
+	newContext := func(args []string, loggerErr error) *gotenberg.Context {
+		mod := &struct {
+			gotenberg.ModuleMock
+			gotenberg.LoggerProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "bar", New: func() gotenberg.Module { return mod }}
+		}
+		mod.LoggerMock = func(mod gotenberg.Module) (*zap.Logger, error) {
+			return zap.NewNop(), loggerErr
+		}
+
+		fs := new(Fonts).Descriptor().FlagSet
+		err := fs.Parse(args)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return gotenberg.NewContext(
+			gotenberg.ParsedFlags{
+				FlagSet: fs,
+			},
+			[]gotenberg.ModuleDescriptor{
+				mod.Descriptor(),
+			},
+		)
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		setEnv       bool
+		args         []string
+		loggerErr    error
+		expectError  bool
+		expectConfig bool
+	}{
+		{
+			scenario:    "missing environment variables",
+			setEnv:      false,
+			expectError: true,
+		},
+		{
+			scenario:    "no logger from logger provider",
+			setEnv:      true,
+			loggerErr:   errors.New("foo"),
+			expectError: true,
+		},
+		{
+			scenario:     "provision success",
+			setEnv:       true,
+			expectError:  false,
+			expectConfig: false,
+		},
+		{
+			scenario:     "provision success with extra directories",
+			setEnv:       true,
+			args:         []string{"--fonts-extra-dirs=/fonts"},
+			expectError:  false,
+			expectConfig: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Setenv("FONTCONFIG_FILE", "")
+			t.Setenv("FC_CACHE_BIN_PATH", "/usr/bin/fc-cache")
+			t.Setenv("FC_LIST_BIN_PATH", "/usr/bin/fc-list")
+			if !tc.setEnv {
+				// Restored by t.Setenv.
+				_ = os.Unsetenv("FC_CACHE_BIN_PATH")
+				_ = os.Unsetenv("FC_LIST_BIN_PATH")
+			}
+
+			mod := new(Fonts)
+			err := mod.Provision(newContext(tc.args, tc.loggerErr))
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectConfig {
+				return
+			}
+
+			defer func() {
+				_ = mod.Stop(context.Background())
+			}()
+
+			if os.Getenv("FONTCONFIG_FILE") != mod.configPath {
+				t.Errorf("expected FONTCONFIG_FILE to be '%s' but got '%s'", mod.configPath, os.Getenv("FONTCONFIG_FILE"))
+			}
+		})
+	}
+}
+
+func TestFonts_Validate(t *testing.T) {
+	dir := t.TempDir()
+	binPath := gotenbergtest.FakeBinary(t, "fc-foo", "exit 0\n")
+
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		extraDirs     []string
+		warmupTimeout time.Duration
+		expectError   bool
+	}{
+		{
+			scenario:      "binary path does not exist",
+			binPath:       "/foo",
+			warmupTimeout: time.Second,
+			expectError:   true,
+		},
+		{
+			scenario:      "extra directory does not exist",
+			binPath:       binPath,
+			extraDirs:     []string{"/foo"},
+			warmupTimeout: time.Second,
+			expectError:   true,
+		},
+		{
+			scenario:      "extra directory is a file",
+			binPath:       binPath,
+			extraDirs:     []string{binPath},
+			warmupTimeout: time.Second,
+			expectError:   true,
+		},
+		{
+			scenario:      "invalid warmup timeout",
+			binPath:       binPath,
+			warmupTimeout: 0,
+			expectError:   true,
+		},
+		{
+			scenario:      "validate success",
+			binPath:       binPath,
+			extraDirs:     []string{dir},
+			warmupTimeout: time.Second,
+			expectError:   false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Fonts{
+				fcCacheBinPath: tc.binPath,
+				fcListBinPath:  tc.binPath,
+				extraDirs:      tc.extraDirs,
+				warmupTimeout:  tc.warmupTimeout,
+			}
+			err := mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestFonts_Start(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		fcCache        string
+		fcList         string
+		expectFamilies int
+	}{
+		{
+			scenario:       "warmup success",
+			fcCache:        "exit 0\n",
+			fcList:         "printf 'Carlito\\tRegular\\t/fonts/Carlito-Regular.ttf\\n'\n",
+			expectFamilies: 1,
+		},
+		{
+			scenario:       "warmup failure",
+			fcCache:        "exit 1\n",
+			fcList:         "exit 1\n",
+			expectFamilies: 0,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Fonts{
+				fcCacheBinPath: gotenbergtest.FakeBinary(t, "fc-cache", tc.fcCache),
+				fcListBinPath:  gotenbergtest.FakeBinary(t, "fc-list", tc.fcList),
+				warmupTimeout:  time.Duration(5) * time.Second,
+				logger:         zap.NewNop(),
+			}
+
+			err := mod.Start()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if mod.families != tc.expectFamilies {
+				t.Errorf("expected %d families but got %d", tc.expectFamilies, mod.families)
+			}
+		})
+	}
+}
+
+func TestFonts_StartupMessage(t *testing.T) {
+	mod := &Fonts{families: 42}
+
+	actual := mod.StartupMessage()
+	expect := "font cache warmed up, 42 font families available"
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestFonts_Stop(t *testing.T) {
+	path, err := writeConfig(defaultConfigPath, []string{"/fonts"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	mod := &Fonts{configPath: path}
+
+	err = mod.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(path)
+	if !os.IsNotExist(err) {
+		t.Errorf("expected the configuration file to be removed but got: %v", err)
+	}
+}
+
+func TestFonts_Routes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		fcList       string
+		expectStatus int
+		expectCount  int
+	}{
+		{
+			scenario:     "fc-list failure",
+			fcList:       "exit 1\n",
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			scenario:     "fonts inventory",
+			fcList:       "printf 'Carlito\\tRegular\\t/fonts/Carlito-Regular.ttf\\nDejaVu Sans\\tBook\\t/usr/share/fonts/DejaVuSans.ttf\\n'\n",
+			expectStatus: http.StatusOK,
+			expectCount:  2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Fonts{
+				fcListBinPath: gotenbergtest.FakeBinary(t, "fc-list", tc.fcList),
+				logger:        zap.NewNop(),
+			}
+
+			routes, err := mod.Routes()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(routes) != 1 {
+				t.Fatalf("expected 1 route but got %d", len(routes))
+			}
+
+			srv := echo.New()
+			srv.GET(routes[0].Path, routes[0].Handler)
+
+			req := httptest.NewRequest(http.MethodGet, "/fonts", nil)
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectStatus {
+				t.Fatalf("expected status %d but got %d", tc.expectStatus, rec.Code)
+			}
+
+			if tc.expectStatus != http.StatusOK {
+				return
+			}
+
+			var inventory Inventory
+			err = json.Unmarshal(rec.Body.Bytes(), &inventory)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if inventory.Count != tc.expectCount {
+				t.Errorf("expected %d families but got %d", tc.expectCount, inventory.Count)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+// Package ghostscript provides a module which adds a route for normalizing
+// the raster content of PDFs with Ghostscript, i.e., downsampling their
+// images to a target resolution and converting their colors to a target
+// color space, as print vendors often demand.
+package ghostscript
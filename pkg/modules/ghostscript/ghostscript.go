@@ -0,0 +1,81 @@
+package ghostscript
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Ghostscript))
+}
+
+// Ghostscript is a module which provides a route for normalizing the raster
+// content of PDFs.
+type Ghostscript struct {
+	binPath       string
+	disableRoutes bool
+}
+
+// Descriptor returns a [Ghostscript]'s module descriptor.
+func (mod *Ghostscript) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "ghostscript",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("ghostscript", flag.ExitOnError)
+			fs.Bool("ghostscript-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Ghostscript) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Ghostscript) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("ghostscript-disable-routes")
+
+	binPath, ok := os.LookupEnv("GHOSTSCRIPT_BIN_PATH")
+	if !ok {
+		return errors.New("GHOSTSCRIPT_BIN_PATH environment variable is not set")
+	}
+
+	mod.binPath = binPath
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Ghostscript) Validate() error {
+	_, err := os.Stat(mod.binPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("ghostscript binary path does not exist: %w", err)
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Ghostscript) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		normalizeRoute(mod.binPath),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Ghostscript)(nil)
+	_ gotenberg.Provisioner = (*Ghostscript)(nil)
+	_ gotenberg.Validator   = (*Ghostscript)(nil)
+	_ api.Router            = (*Ghostscript)(nil)
+)
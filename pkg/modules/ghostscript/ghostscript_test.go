@@ -0,0 +1,142 @@
+package ghostscript
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestGhostscript_Descriptor(t *testing.T) {
+	descriptor := new(Ghostscript).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Ghostscript))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestGhostscript_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		setEnv      bool
+		expectError bool
+	}{
+		{
+			scenario: "no GHOSTSCRIPT_BIN_PATH environment variable",
+			ctx: func() *gotenberg.Context {
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Ghostscript).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{},
+				)
+			}(),
+			setEnv:      false,
+			expectError: true,
+		},
+		{
+			scenario: "provision success",
+			ctx: func() *gotenberg.Context {
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Ghostscript).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{},
+				)
+			}(),
+			setEnv:      true,
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			// Make sure the environment variable is absent, even in the
+			// Docker image.
+			t.Setenv("GHOSTSCRIPT_BIN_PATH", "/usr/bin/gs")
+			if !tc.setEnv {
+				_ = os.Unsetenv("GHOSTSCRIPT_BIN_PATH")
+			}
+
+			mod := new(Ghostscript)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestGhostscript_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		binPath     string
+		expectError bool
+	}{
+		{
+			scenario:    "non-existing Ghostscript binary",
+			binPath:     "/foo",
+			expectError: true,
+		},
+		{
+			scenario:    "validate success",
+			binPath:     os.Args[0],
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Ghostscript)
+			mod.binPath = tc.binPath
+			err := mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestGhostscript_Routes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		expectRoutes  int
+		disableRoutes bool
+	}{
+		{
+			scenario:      "routes not disabled",
+			expectRoutes:  1,
+			disableRoutes: false,
+		},
+		{
+			scenario:      "routes disabled",
+			expectRoutes:  0,
+			disableRoutes: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Ghostscript)
+			mod.disableRoutes = tc.disableRoutes
+
+			routes, err := mod.Routes()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectRoutes != len(routes) {
+				t.Errorf("expected %d routes but got %d", tc.expectRoutes, len(routes))
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package ghostscript
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// ErrInvalidPdf happens if Ghostscript cannot normalize a PDF.
+var ErrInvalidPdf = errors.New("invalid PDF")
+
+const (
+	// minResolution and maxResolution are the bounds of the target
+	// resolution, in DPI.
+	minResolution = 36
+	maxResolution = 2400
+)
+
+// colorSpaces are the target color spaces, with their Ghostscript color
+// conversion strategy and process color model.
+var colorSpaces = map[string][2]string{
+	"rgb":  {"RGB", "DeviceRGB"},
+	"cmyk": {"CMYK", "DeviceCMYK"},
+	"gray": {"Gray", "DeviceGray"},
+}
+
+// normalizeOptions gathers the available options for normalizing a PDF.
+type normalizeOptions struct {
+	// Resolution is the target resolution of the images, in DPI. The images
+	// with a higher resolution are downsampled, the others are kept as is,
+	// as upsampling would not add any detail.
+	Resolution int
+
+	// ColorSpace is the target color space of the whole content, either
+	// "rgb", "cmyk" or "gray".
+	ColorSpace string
+}
+
+// defaultNormalizeOptions returns the default values for
+// [normalizeOptions].
+func defaultNormalizeOptions() normalizeOptions {
+	return normalizeOptions{
+		Resolution: 300,
+		ColorSpace: "rgb",
+	}
+}
+
+// normalize rewrites a PDF with its images downsampled to the target
+// resolution and its colors converted to the target color space.
+func normalize(ctx context.Context, logger *zap.Logger, binPath, inputPath, outputPath string, opts normalizeOptions) error {
+	colorSpace, ok := colorSpaces[opts.ColorSpace]
+	if !ok {
+		return fmt.Errorf("unknown color space '%s'", opts.ColorSpace)
+	}
+
+	resolution := strconv.Itoa(opts.Resolution)
+
+	args := []string{
+		"-dSAFER",
+		"-dBATCH",
+		"-dNOPAUSE",
+		"-dQUIET",
+		"-sDEVICE=pdfwrite",
+		"-sColorConversionStrategy=" + colorSpace[0],
+		"-sProcessColorModel=" + colorSpace[1],
+	}
+
+	// A threshold of 1 downsamples every image above the target
+	// resolution, while the default only downsamples the images 1.5 times
+	// above it.
+	for _, kind := range []string{"Color", "Gray", "Mono"} {
+		args = append(args,
+			fmt.Sprintf("-dDownsample%sImages=true", kind),
+			fmt.Sprintf("-d%sImageResolution=%s", kind, resolution),
+			fmt.Sprintf("-d%sImageDownsampleThreshold=1.0", kind),
+		)
+	}
+
+	args = append(args,
+		"-sOutputFile="+outputPath,
+		inputPath,
+	)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("normalize PDF: %w", err)
+		}
+
+		return fmt.Errorf("normalize PDF: %v: %w", err, ErrInvalidPdf)
+	}
+
+	return nil
+}
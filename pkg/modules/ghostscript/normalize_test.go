@@ -0,0 +1,124 @@
+package ghostscript
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakeGhostscript writes a script which mimics Ghostscript, i.e., which
+// writes its arguments to its output file, or which fails.
+func fakeGhostscript(t *testing.T, fail bool) string {
+	script := "for arg; do case \"$arg\" in -sOutputFile=*) out=\"${arg#-sOutputFile=}\";; esac; done\necho \"$@\" > \"$out\"\n"
+	if fail {
+		script = "exit 1\n"
+	}
+
+	return gotenbergtest.FakeBinary(t, "gs", script)
+}
+
+func TestNormalize(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		opts          normalizeOptions
+		cancelledCtx  bool
+		expectArgs    []string
+		expectError   bool
+		expectedError error
+	}{
+		{
+			scenario:      "invalid PDF",
+			binPath:       fakeGhostscript(t, true),
+			opts:          defaultNormalizeOptions(),
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:     "context done",
+			binPath:      fakeGhostscript(t, false),
+			opts:         defaultNormalizeOptions(),
+			cancelledCtx: true,
+			expectError:  true,
+		},
+		{
+			scenario:    "unknown color space",
+			binPath:     fakeGhostscript(t, false),
+			opts:        normalizeOptions{Resolution: 300, ColorSpace: "lab"},
+			expectError: true,
+		},
+		{
+			scenario: "default options",
+			binPath:  fakeGhostscript(t, false),
+			opts:     defaultNormalizeOptions(),
+			expectArgs: []string{
+				"-sDEVICE=pdfwrite",
+				"-sColorConversionStrategy=RGB -sProcessColorModel=DeviceRGB",
+				"-dDownsampleColorImages=true -dColorImageResolution=300 -dColorImageDownsampleThreshold=1.0",
+				"-dDownsampleGrayImages=true -dGrayImageResolution=300 -dGrayImageDownsampleThreshold=1.0",
+				"-dDownsampleMonoImages=true -dMonoImageResolution=300 -dMonoImageDownsampleThreshold=1.0",
+				"document.pdf",
+			},
+		},
+		{
+			scenario: "CMYK at 150 DPI",
+			binPath:  fakeGhostscript(t, false),
+			opts:     normalizeOptions{Resolution: 150, ColorSpace: "cmyk"},
+			expectArgs: []string{
+				"-sColorConversionStrategy=CMYK -sProcessColorModel=DeviceCMYK",
+				"-dColorImageResolution=150",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.cancelledCtx {
+				cancel()
+			}
+
+			outputPath := filepath.Join(t.TempDir(), "document.pdf")
+
+			err := normalize(ctx, zap.NewNop(), tc.binPath, "document.pdf", outputPath, tc.opts)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if tc.cancelledCtx && errors.Is(err, ErrInvalidPdf) {
+				t.Fatalf("expected no %v error but got one", ErrInvalidPdf)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			b, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			for _, expect := range tc.expectArgs {
+				if !strings.Contains(string(b), expect) {
+					t.Errorf("expected '%s' in arguments '%s'", expect, strings.TrimSpace(string(b)))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package ghostscript
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// normalizeRoute returns an [api.Route] which can normalize the raster
+// content of PDFs to a target resolution and color space.
+func normalizeRoute(binPath string) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/ghostscript/normalize",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			defaultOptions := defaultNormalizeOptions()
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				opts       normalizeOptions
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Custom("resolution", func(value string) error {
+					if value == "" {
+						opts.Resolution = defaultOptions.Resolution
+						return nil
+					}
+
+					resolution, err := strconv.Atoi(value)
+					if err != nil {
+						return err
+					}
+
+					if resolution < minResolution || resolution > maxResolution {
+						return fmt.Errorf("value is not between %d and %d", minResolution, maxResolution)
+					}
+
+					opts.Resolution = resolution
+
+					return nil
+				}).
+				Custom("colorSpace", func(value string) error {
+					if value == "" {
+						opts.ColorSpace = defaultOptions.ColorSpace
+						return nil
+					}
+
+					value = strings.ToLower(value)
+
+					_, ok := colorSpaces[value]
+					if !ok {
+						return errors.New("wrong value, expected either 'rgb', 'cmyk' or 'gray'")
+					}
+
+					opts.ColorSpace = value
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// Alright, let's normalize each PDF.
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				// document.pdf -> document.pdf, within the working directory.
+				outputPaths[i] = ctx.GeneratePath(strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath)), ".pdf")
+
+				err = normalize(ctx, ctx.Log(), binPath, inputPath, outputPaths[i], opts)
+				if err != nil {
+					if errors.Is(err, ErrInvalidPdf) {
+						return api.WrapError(
+							fmt.Errorf("normalize PDF: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("The PDF '%s' is invalid", filepath.Base(inputPath)),
+							).WithCode(api.ErrorCodeGhostscriptInvalidPdf),
+						)
+					}
+
+					return fmt.Errorf("normalize PDF: %w", err)
+				}
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package ghostscript
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestNormalizeRoute(t *testing.T) {
+	newContext := func(files map[string]string, values map[string][]string) *api.ContextMock {
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(t.TempDir())
+		ctx.SetFiles(files)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	document := map[string]string{"document.pdf": "/document.pdf"}
+	binPath := fakeGhostscript(t, false)
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		binPath                string
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newContext(nil, nil),
+			binPath:                binPath,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid resolution form field",
+			ctx:                    newContext(document, map[string][]string{"resolution": {"foo"}}),
+			binPath:                binPath,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "resolution form field out of bounds",
+			ctx:                    newContext(document, map[string][]string{"resolution": {"10000"}}),
+			binPath:                binPath,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid colorSpace form field",
+			ctx:                    newContext(document, map[string][]string{"colorSpace": {"lab"}}),
+			binPath:                binPath,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid PDF",
+			ctx:                    newContext(document, nil),
+			binPath:                fakeGhostscript(t, true),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success",
+			ctx: newContext(map[string]string{"a.pdf": "/a.pdf", "b.pdf": "/b.pdf"}, map[string][]string{
+				"resolution": {"300"},
+				"colorSpace": {"CMYK"},
+			}),
+			binPath:                binPath,
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := normalizeRoute(tc.binPath).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+		})
+	}
+}
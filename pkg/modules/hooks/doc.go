@@ -0,0 +1,4 @@
+// Package hooks provides a module which runs post-processing hooks on the
+// output files of the multipart requests before they are returned. A hook is
+// either an external command or an HTTP transformer, configured per route.
+package hooks
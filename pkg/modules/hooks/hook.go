@@ -0,0 +1,175 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// ErrHookFailed happens if a hook fails to process an output file.
+var ErrHookFailed = errors.New("hook failed")
+
+// hook is a post-processing step of the output files of the routes starting
+// with a given path.
+type hook struct {
+	// prefix is the path prefix of the routes.
+	prefix string
+
+	// target is either the absolute path of a command, or the URL of an HTTP
+	// transformer.
+	target string
+}
+
+// isTransformer tells if the hook is an HTTP transformer.
+func (h hook) isTransformer() bool {
+	return strings.HasPrefix(h.target, "http://") || strings.HasPrefix(h.target, "https://")
+}
+
+// parseHooks parses entries like "/forms/chromium=https://stamp/transform" or
+// "/forms/libreoffice=/usr/local/bin/stamp". The order of the entries is the
+// execution order of the hooks.
+func parseHooks(entries []string) ([]hook, error) {
+	hooks := make([]hook, len(entries))
+
+	for i, entry := range entries {
+		prefix, target, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(prefix) == "" || strings.TrimSpace(target) == "" {
+			return nil, fmt.Errorf("invalid hook '%s': expected 'path=command' or 'path=URL'", entry)
+		}
+
+		hooks[i] = hook{
+			prefix: strings.TrimSpace(prefix),
+			target: strings.TrimSpace(target),
+		}
+	}
+
+	return hooks, nil
+}
+
+// validate checks that the command exists, or that the URL is valid.
+func (h hook) validate() error {
+	if h.isTransformer() {
+		_, err := url.ParseRequestURI(h.target)
+		if err != nil {
+			return fmt.Errorf("parse URL: %w", err)
+		}
+
+		return nil
+	}
+
+	if !filepath.IsAbs(h.target) {
+		return fmt.Errorf("command '%s' is neither an absolute path nor an HTTP URL", h.target)
+	}
+
+	_, err := os.Stat(h.target)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("command '%s' does not exist: %w", h.target, err)
+	}
+
+	return nil
+}
+
+// run processes the input file and writes the result to the output path.
+func (h hook) run(ctx context.Context, logger *zap.Logger, client *http.Client, inputPath, outputPath string) error {
+	if h.isTransformer() {
+		return h.transform(ctx, client, inputPath, outputPath)
+	}
+
+	return h.exec(ctx, logger, inputPath, outputPath)
+}
+
+// exec runs the command with the input and output paths as arguments.
+func (h hook) exec(ctx context.Context, logger *zap.Logger, inputPath, outputPath string) error {
+	cmd, err := gotenberg.CommandContext(ctx, logger, h.target, inputPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("run command '%s': %w", h.target, err)
+		}
+
+		return fmt.Errorf("run command '%s': %v: %w", h.target, err, ErrHookFailed)
+	}
+
+	_, err = os.Stat(outputPath)
+	if err != nil {
+		return fmt.Errorf("command '%s' did not write its output: %v: %w", h.target, err, ErrHookFailed)
+	}
+
+	return nil
+}
+
+// transform sends the input file to the HTTP transformer and writes the
+// response body to the output path.
+func (h hook) transform(ctx context.Context, client *http.Client, inputPath, outputPath string) error {
+	in, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("open input file: %w", err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.target, in)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	filename := filepath.Base(inputPath)
+	contentType := mime.TypeByExtension(filepath.Ext(filename))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	req.Header.Set("User-Agent", "Gotenberg")
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("send request to '%s': %w", h.target, err)
+		}
+
+		return fmt.Errorf("send request to '%s': %v: %w", h.target, err, ErrHookFailed)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("'%s' responded with status code %d: %w", h.target, resp.StatusCode, ErrHookFailed)
+	}
+
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create output file: %w", err)
+	}
+
+	defer func() {
+		_ = out.Close()
+	}()
+
+	_, err = io.Copy(out, resp.Body)
+	if err != nil {
+		return fmt.Errorf("write response from '%s': %v: %w", h.target, err, ErrHookFailed)
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakeCommand writes a script which appends a suffix to its input, writes
+// the result to its output, then exits with the given code.
+func fakeCommand(t *testing.T, exitCode string) string {
+	script := "{ cat \"$1\"; printf ' stamped'; } > \"$2\"\nexit " + exitCode + "\n"
+	return gotenbergtest.FakeBinary(t, "stamp", script)
+}
+
+// fakeTransformer starts an HTTP server which appends a suffix to the
+// request body, or which fails on the "/fail" path.
+func fakeTransformer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" || r.Header.Get("Content-Type") != "application/pdf" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		b, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(append(b, []byte(" stamped")...))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestParseHooks(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		entries     []string
+		expectError bool
+	}{
+		{
+			scenario:    "missing separator",
+			entries:     []string{"/forms/chromium"},
+			expectError: true,
+		},
+		{
+			scenario:    "empty target",
+			entries:     []string{"/forms/chromium= "},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			entries:  []string{"/forms/chromium=https://stamp/transform"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			_, err := parseHooks(tc.entries)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestHook_validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		hook        hook
+		expectError bool
+	}{
+		{
+			scenario:    "relative command",
+			hook:        hook{target: "stamp"},
+			expectError: true,
+		},
+		{
+			scenario:    "non-existing command",
+			hook:        hook{target: "/foo/stamp"},
+			expectError: true,
+		},
+		{
+			scenario: "command",
+			hook:     hook{target: fakeCommand(t, "0")},
+		},
+		{
+			scenario:    "invalid URL",
+			hook:        hook{target: "http://[::1"},
+			expectError: true,
+		},
+		{
+			scenario: "URL",
+			hook:     hook{target: "https://stamp/transform"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.hook.validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestHook_run(t *testing.T) {
+	server := fakeTransformer(t)
+
+	for _, tc := range []struct {
+		scenario      string
+		hook          hook
+		expectError   bool
+		expectedError error
+	}{
+		{
+			scenario:      "command failure",
+			hook:          hook{target: fakeCommand(t, "1")},
+			expectError:   true,
+			expectedError: ErrHookFailed,
+		},
+		{
+			scenario: "command success",
+			hook:     hook{target: fakeCommand(t, "0")},
+		},
+		{
+			scenario:      "transformer failure",
+			hook:          hook{target: server.URL + "/fail"},
+			expectError:   true,
+			expectedError: ErrHookFailed,
+		},
+		{
+			scenario: "transformer success",
+			hook:     hook{target: server.URL + "/transform"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, "foo.pdf")
+			outputPath := filepath.Join(dirPath, "bar.pdf")
+
+			err := os.WriteFile(inputPath, []byte("%PDF-1.7"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = tc.hook.run(context.Background(), zap.NewNop(), server.Client(), inputPath, outputPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			b, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(b) != "%PDF-1.7 stamped" {
+				t.Errorf("expected '%%PDF-1.7 stamped' but got '%s'", string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package hooks
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Hooks))
+}
+
+// Hooks is a module which runs post-processing hooks, i.e., external
+// commands or HTTP transformers, on the output files of the routes.
+type Hooks struct {
+	hooks         []hook
+	timeout       time.Duration
+	failurePolicy string
+}
+
+// Descriptor returns a [Hooks]'s module descriptor.
+func (mod *Hooks) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "hooks",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("hooks", flag.ExitOnError)
+			fs.StringSlice("hooks-routes", make([]string, 0), "Set the post-processing hooks of the routes starting with a given path, either a command called with the input and output paths, or an HTTP URL receiving the file in a POST request - e.g., /forms/chromium=https://stamp/transform")
+			fs.Duration("hooks-timeout", time.Duration(30)*time.Second, "Set the time limit for a hook to process an output file")
+			fs.String("hooks-failure-policy", failurePolicyFail, fmt.Sprintf("Set what happens when a hook fails - either '%s' the request or '%s' the hook", failurePolicyFail, failurePolicySkip))
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Hooks) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Hooks) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.timeout = flags.MustDuration("hooks-timeout")
+	mod.failurePolicy = flags.MustString("hooks-failure-policy")
+
+	hooks, err := parseHooks(flags.MustStringSlice("hooks-routes"))
+	if err != nil {
+		return fmt.Errorf("parse hooks: %w", err)
+	}
+
+	mod.hooks = hooks
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Hooks) Validate() error {
+	var err error
+
+	if mod.timeout <= 0 {
+		err = multierr.Append(err,
+			errors.New("timeout must be more than 0"),
+		)
+	}
+
+	if mod.failurePolicy != failurePolicyFail && mod.failurePolicy != failurePolicySkip {
+		err = multierr.Append(err,
+			fmt.Errorf("failure policy must be either '%s' or '%s'", failurePolicyFail, failurePolicySkip),
+		)
+	}
+
+	for _, h := range mod.hooks {
+		hookErr := h.validate()
+		if hookErr != nil {
+			err = multierr.Append(err, fmt.Errorf("hook '%s': %w", h.prefix, hookErr))
+		}
+	}
+
+	return err
+}
+
+// Middlewares returns the middlewares.
+func (mod *Hooks) Middlewares() ([]api.Middleware, error) {
+	if len(mod.hooks) == 0 {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		outputsMiddleware(mod.hooks, &http.Client{}, mod.timeout, mod.failurePolicy),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Hooks)(nil)
+	_ gotenberg.Provisioner  = (*Hooks)(nil)
+	_ gotenberg.Validator    = (*Hooks)(nil)
+	_ api.MiddlewareProvider = (*Hooks)(nil)
+)
@@ -0,0 +1,154 @@
+package hooks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestHooks_Descriptor(t *testing.T) {
+	descriptor := new(Hooks).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Hooks))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestHooks_Provision(t *testing.T) {
+	newContext := func(args ...string) *gotenberg.Context {
+		fs := new(Hooks).Descriptor().FlagSet
+
+		err := fs.Parse(args)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		return gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectHooks []hook
+		expectError bool
+	}{
+		{
+			scenario:    "no hooks",
+			ctx:         newContext(),
+			expectHooks: []hook{},
+		},
+		{
+			scenario:    "invalid hook",
+			ctx:         newContext("--hooks-routes=/forms/chromium"),
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			ctx:      newContext("--hooks-routes=/forms/chromium=https://stamp/transform,/forms/=/usr/local/bin/stamp"),
+			expectHooks: []hook{
+				{prefix: "/forms/chromium", target: "https://stamp/transform"},
+				{prefix: "/forms/", target: "/usr/local/bin/stamp"},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Hooks)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !reflect.DeepEqual(mod.hooks, tc.expectHooks) {
+				t.Errorf("expected %+v but got %+v", tc.expectHooks, mod.hooks)
+			}
+		})
+	}
+}
+
+func TestHooks_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Hooks
+		expectError bool
+	}{
+		{
+			scenario:    "invalid timeout",
+			mod:         &Hooks{timeout: 0, failurePolicy: failurePolicyFail},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid failure policy",
+			mod:         &Hooks{timeout: time.Second, failurePolicy: "foo"},
+			expectError: true,
+		},
+		{
+			scenario: "invalid hook",
+			mod: &Hooks{
+				timeout:       time.Second,
+				failurePolicy: failurePolicyFail,
+				hooks:         []hook{{prefix: "/forms/", target: "/foo"}},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			mod: &Hooks{
+				timeout:       time.Second,
+				failurePolicy: failurePolicySkip,
+				hooks:         []hook{{prefix: "/forms/", target: "https://stamp/transform"}},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestHooks_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		mod               *Hooks
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "no hooks",
+			mod:               new(Hooks),
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "hooks",
+			mod:               &Hooks{hooks: []hook{{prefix: "/forms/", target: "https://stamp/transform"}}},
+			expectMiddlewares: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			middlewares, err := tc.mod.Middlewares()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(middlewares) != tc.expectMiddlewares {
+				t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+			}
+		})
+	}
+}
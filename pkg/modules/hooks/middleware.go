@@ -0,0 +1,133 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+const (
+	// failurePolicyFail rejects the request if a hook fails.
+	failurePolicyFail = "fail"
+
+	// failurePolicySkip keeps the output file as it was before the failing
+	// hook.
+	failurePolicySkip = "skip"
+)
+
+// routePath returns the path of the route, without the root path of the
+// API.
+func routePath(c echo.Context) string {
+	path := c.Path()
+
+	rootPath, ok := c.Get("rootPath").(string)
+	if ok {
+		path = strings.TrimPrefix(path, rootPath)
+	}
+
+	return "/" + strings.TrimPrefix(path, "/")
+}
+
+// outputsMiddleware runs the hooks of the route on the output files once the
+// route handler is done. Like the ClamAV output scans, it runs after the
+// webhook middleware, so that the asynchronous conversions are also
+// processed.
+func outputsMiddleware(hooks []hook, client *http.Client, timeout time.Duration, failurePolicy string) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryLowPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(e echo.Context) error {
+					err := next(e)
+					if err != nil {
+						return err
+					}
+
+					path := routePath(e)
+
+					var routeHooks []hook
+					for _, h := range hooks {
+						if strings.HasPrefix(path, h.prefix) {
+							routeHooks = append(routeHooks, h)
+						}
+					}
+
+					if len(routeHooks) == 0 {
+						return nil
+					}
+
+					ctx := e.Get("context").(*api.Context)
+
+					for _, outputPath := range ctx.OutputPaths() {
+						for _, h := range routeHooks {
+							err = runHook(ctx, h, client, timeout, outputPath)
+							if err == nil {
+								continue
+							}
+
+							if failurePolicy == failurePolicySkip && errors.Is(err, ErrHookFailed) {
+								ctx.Log().Warn(fmt.Sprintf("skip hook '%s' for '%s': %s", h.target, filepath.Base(outputPath), err))
+
+								continue
+							}
+
+							if errors.Is(err, ErrHookFailed) {
+								return api.WrapError(
+									fmt.Errorf("run hook: %w", err),
+									api.NewSentinelHttpError(
+										http.StatusBadGateway,
+										fmt.Sprintf("A post-processing hook failed for the file '%s'", filepath.Base(outputPath)),
+									).WithCode(api.ErrorCodeHooksFailed),
+								)
+							}
+
+							return fmt.Errorf("run hook: %w", err)
+						}
+					}
+
+					return nil
+				}
+			}
+		}(),
+	}
+}
+
+// runHook runs a hook on an output file, and replaces the output file with
+// the result.
+func runHook(ctx *api.Context, h hook, client *http.Client, timeout time.Duration, outputPath string) error {
+	hookCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resultPath := ctx.GeneratePath("", filepath.Ext(outputPath))
+
+	err := h.run(hookCtx, ctx.Log(), client, outputPath, resultPath)
+	if err != nil {
+		_ = os.Remove(resultPath)
+
+		if hookCtx.Err() != nil && ctx.Err() == nil {
+			// Only the hook timed out: the request may continue.
+			return fmt.Errorf("hook '%s' timed out after %s: %v: %w", h.target, timeout, err, ErrHookFailed)
+		}
+
+		return err
+	}
+
+	err = os.Rename(resultPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("replace output file: %w", err)
+	}
+
+	ctx.Log().Debug(fmt.Sprintf("hook '%s' applied to '%s'", h.target, filepath.Base(outputPath)))
+
+	return nil
+}
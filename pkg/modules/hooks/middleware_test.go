@@ -0,0 +1,129 @@
+package hooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestOutputsMiddleware(t *testing.T) {
+	server := fakeTransformer(t)
+
+	for _, tc := range []struct {
+		scenario      string
+		hooks         []hook
+		timeout       time.Duration
+		failurePolicy string
+		expectStatus  int
+		expectCode    string
+		expectOutput  string
+	}{
+		{
+			scenario:      "no matching hook",
+			hooks:         []hook{{prefix: "/forms/chromium", target: server.URL + "/transform"}},
+			timeout:       time.Second,
+			failurePolicy: failurePolicyFail,
+			expectStatus:  http.StatusOK,
+			expectOutput:  "%PDF-1.7",
+		},
+		{
+			scenario: "hooks in order",
+			hooks: []hook{
+				{prefix: "/forms/", target: server.URL + "/transform"},
+				{prefix: "/forms/foo", target: fakeCommand(t, "0")},
+			},
+			timeout:       time.Second,
+			failurePolicy: failurePolicyFail,
+			expectStatus:  http.StatusOK,
+			expectOutput:  "%PDF-1.7 stamped stamped",
+		},
+		{
+			scenario:      "failing hook with the fail policy",
+			hooks:         []hook{{prefix: "/forms/foo", target: server.URL + "/fail"}},
+			timeout:       time.Second,
+			failurePolicy: failurePolicyFail,
+			expectStatus:  http.StatusBadGateway,
+			expectCode:    "HOOKS_FAILED",
+			expectOutput:  "%PDF-1.7",
+		},
+		{
+			scenario: "failing hook with the skip policy",
+			hooks: []hook{
+				{prefix: "/forms/foo", target: server.URL + "/fail"},
+				{prefix: "/forms/foo", target: server.URL + "/transform"},
+			},
+			timeout:       time.Second,
+			failurePolicy: failurePolicySkip,
+			expectStatus:  http.StatusOK,
+			expectOutput:  "%PDF-1.7 stamped",
+		},
+		{
+			scenario:      "hook timeout",
+			hooks:         []hook{{prefix: "/forms/foo", target: server.URL + "/transform"}},
+			timeout:       time.Nanosecond,
+			failurePolicy: failurePolicyFail,
+			expectStatus:  http.StatusBadGateway,
+			expectCode:    "HOOKS_FAILED",
+			expectOutput:  "%PDF-1.7",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(dirPath)
+			ctx.SetLogger(zap.NewNop())
+			ctx.Context.Context = context.Background()
+
+			e := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/foo", nil), httptest.NewRecorder())
+			e.SetPath("//forms/foo")
+			e.Set("rootPath", "/")
+			e.Set("context", ctx.Context)
+
+			outputPath := ctx.GeneratePath("", ".pdf")
+			next := func(e echo.Context) error {
+				err := os.WriteFile(outputPath, []byte("%PDF-1.7"), 0o600)
+				if err != nil {
+					return err
+				}
+
+				return ctx.AddOutputPaths(outputPath)
+			}
+
+			err := outputsMiddleware(tc.hooks, server.Client(), tc.timeout, tc.failurePolicy).Handler(next)(e)
+
+			status := http.StatusOK
+			var code string
+			if err != nil {
+				response := api.ParseErrorResponse(err)
+				status = response.Status
+				code = response.Code
+			}
+
+			if status != tc.expectStatus {
+				t.Fatalf("expected status %d but got %d: %v", tc.expectStatus, status, err)
+			}
+
+			if code != tc.expectCode {
+				t.Errorf("expected code '%s' but got '%s'", tc.expectCode, code)
+			}
+
+			b, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(b) != tc.expectOutput {
+				t.Errorf("expected output '%s' but got '%s'", tc.expectOutput, string(b))
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+// Package images provides a module which adds a route for assembling images,
+// i.e., JPEG, PNG, TIFF, WebP, HEIC and AVIF files, into a PDF, one image per
+// page. It handles the page size, the margins, how the images fit their
+// pages, and the EXIF orientation of the images.
+//
+// HEIC, HEIF and AVIF images are converted to PNG beforehand, using the
+// heif-convert command-line tool from libheif. The path to its binary must be
+// specified using the HEIF_CONVERT_BIN_PATH environment variable.
+//
+// See: https://github.com/strukturag/libheif.
+package images
@@ -0,0 +1,160 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// exifOrientationTag is the EXIF tag of the orientation, whose values range
+// from 1 (no transformation) to 8.
+//
+// See https://www.cipa.jp/std/documents/e/DC-008-2012_E.pdf.
+const exifOrientationTag = 0x0112
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifOrientation returns the EXIF orientation of an image according to its
+// format, or 1 if not available.
+func exifOrientation(format string, data []byte) int {
+	switch format {
+	case "jpeg":
+		return jpegOrientation(data)
+	case "tiff":
+		return tiffOrientation(data)
+	case "png":
+		return pngOrientation(data)
+	case "webp":
+		return webpOrientation(data)
+	default:
+		return 1
+	}
+}
+
+// jpegOrientation looks for the EXIF data in the APP1 segments of a JPEG.
+func jpegOrientation(data []byte) int {
+	offset := 2 // SOI.
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+
+		marker := data[offset+1]
+		if marker == 0xDA || marker == 0xD9 {
+			// Start of scan or end of image: no more metadata.
+			return 1
+		}
+
+		length := int(binary.BigEndian.Uint16(data[offset+2:]))
+		if length < 2 || offset+2+length > len(data) {
+			return 1
+		}
+
+		segment := data[offset+4 : offset+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, exifHeader) {
+			return tiffOrientation(segment[len(exifHeader):])
+		}
+
+		offset += 2 + length
+	}
+
+	return 1
+}
+
+// pngOrientation looks for the EXIF data in the eXIf chunk of a PNG.
+func pngOrientation(data []byte) int {
+	offset := 8 // Signature.
+	for offset+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		kind := string(data[offset+4 : offset+8])
+		if length < 0 || offset+12+length > len(data) {
+			return 1
+		}
+
+		switch kind {
+		case "eXIf":
+			return tiffOrientation(data[offset+8 : offset+8+length])
+		case "IDAT", "IEND":
+			// The eXIf chunk must come before the image data.
+			return 1
+		}
+
+		offset += 12 + length
+	}
+
+	return 1
+}
+
+// webpOrientation looks for the EXIF data in the EXIF chunk of a WebP.
+func webpOrientation(data []byte) int {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 1
+	}
+
+	offset := 12
+	for offset+8 <= len(data) {
+		kind := string(data[offset : offset+4])
+		length := int(binary.LittleEndian.Uint32(data[offset+4:]))
+		if length < 0 || offset+8+length > len(data) {
+			return 1
+		}
+
+		if kind == "EXIF" {
+			// Some encoders keep the JPEG header.
+			return tiffOrientation(bytes.TrimPrefix(data[offset+8:offset+8+length], exifHeader))
+		}
+
+		// Chunks are padded to an even size.
+		offset += 8 + length + length%2
+	}
+
+	return 1
+}
+
+// tiffOrientation reads the orientation in the first IFD of a TIFF
+// structure, which is also the layout of the EXIF data.
+func tiffOrientation(data []byte) int {
+	if len(data) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(data[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	if order.Uint16(data[2:]) != 42 {
+		return 1
+	}
+
+	offset := int(order.Uint32(data[4:]))
+	if offset < 8 || offset+2 > len(data) {
+		return 1
+	}
+
+	count := int(order.Uint16(data[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(data) {
+			return 1
+		}
+
+		// Tag, then type: SHORT is 3.
+		if order.Uint16(data[entry:]) != exifOrientationTag || order.Uint16(data[entry+2:]) != 3 {
+			continue
+		}
+
+		orientation := int(order.Uint16(data[entry+8:]))
+		if orientation < 1 || orientation > 8 {
+			return 1
+		}
+
+		return orientation
+	}
+
+	return 1
+}
@@ -0,0 +1,151 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+)
+
+// tiffWithOrientation returns a TIFF structure with an orientation entry,
+// like the EXIF data.
+func tiffWithOrientation(order binary.ByteOrder, orientation uint16) []byte {
+	b := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(b, "II")
+	} else {
+		copy(b, "MM")
+	}
+	order.PutUint16(b[2:], 42)
+	order.PutUint32(b[4:], 8)
+	order.PutUint16(b[8:], 1)
+	order.PutUint16(b[10:], exifOrientationTag)
+	order.PutUint16(b[12:], 3)
+	order.PutUint32(b[14:], 1)
+	order.PutUint16(b[18:], orientation)
+
+	return b
+}
+
+// jpegWithOrientation inserts an APP1 segment with EXIF data after the SOI
+// marker of a JPEG.
+func jpegWithOrientation(jpeg []byte, orientation uint16) []byte {
+	payload := append(append([]byte(nil), exifHeader...), tiffWithOrientation(binary.BigEndian, orientation)...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+
+	var buf bytes.Buffer
+	buf.Write(jpeg[:2])
+	buf.Write(segment)
+	buf.Write(payload)
+	buf.Write(jpeg[2:])
+
+	return buf.Bytes()
+}
+
+// pngWithOrientation inserts an eXIf chunk after the IHDR chunk of a PNG.
+func pngWithOrientation(png []byte, orientation uint16) []byte {
+	payload := tiffWithOrientation(binary.LittleEndian, orientation)
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], "eXIf")
+	chunk = append(chunk, payload...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	// Signature (8 bytes), then IHDR (25 bytes).
+	var buf bytes.Buffer
+	buf.Write(png[:33])
+	buf.Write(chunk)
+	buf.Write(png[33:])
+
+	return buf.Bytes()
+}
+
+// webpWithOrientation appends an EXIF chunk to a WebP.
+func webpWithOrientation(orientation uint16) []byte {
+	payload := append(append([]byte(nil), exifHeader...), tiffWithOrientation(binary.BigEndian, orientation)...)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	buf.Write(make([]byte, 4))
+	buf.WriteString("WEBP")
+	// An unrelated chunk, of odd size.
+	buf.WriteString("ICCP")
+	buf.Write([]byte{3, 0, 0, 0, 'f', 'o', 'o', 0})
+	buf.WriteString("EXIF")
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(payload))))
+	buf.Write(payload)
+
+	return buf.Bytes()
+}
+
+func TestExifOrientation(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		format            string
+		data              []byte
+		expectOrientation int
+	}{
+		{
+			scenario:          "JPEG with orientation",
+			format:            "jpeg",
+			data:              jpegWithOrientation([]byte{0xFF, 0xD8, 0xFF, 0xD9}, 6),
+			expectOrientation: 6,
+		},
+		{
+			scenario:          "JPEG without EXIF",
+			format:            "jpeg",
+			data:              []byte{0xFF, 0xD8, 0xFF, 0xDA, 0x00, 0x02},
+			expectOrientation: 1,
+		},
+		{
+			scenario:          "truncated JPEG",
+			format:            "jpeg",
+			data:              jpegWithOrientation([]byte{0xFF, 0xD8, 0xFF, 0xD9}, 6)[:12],
+			expectOrientation: 1,
+		},
+		{
+			scenario:          "TIFF with orientation (little endian)",
+			format:            "tiff",
+			data:              tiffWithOrientation(binary.LittleEndian, 8),
+			expectOrientation: 8,
+		},
+		{
+			scenario:          "TIFF with invalid orientation",
+			format:            "tiff",
+			data:              tiffWithOrientation(binary.BigEndian, 9),
+			expectOrientation: 1,
+		},
+		{
+			scenario:          "PNG with orientation",
+			format:            "png",
+			data:              pngWithOrientation(encodeTestImage(t, "png", 1, 1), 3),
+			expectOrientation: 3,
+		},
+		{
+			scenario:          "WebP with orientation",
+			format:            "webp",
+			data:              webpWithOrientation(5),
+			expectOrientation: 5,
+		},
+		{
+			scenario:          "not a WebP",
+			format:            "webp",
+			data:              []byte("foo"),
+			expectOrientation: 1,
+		},
+		{
+			scenario:          "unsupported format",
+			format:            "gif",
+			data:              tiffWithOrientation(binary.BigEndian, 6),
+			expectOrientation: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := exifOrientation(tc.format, tc.data)
+			if actual != tc.expectOrientation {
+				t.Errorf("expected orientation %d but got %d", tc.expectOrientation, actual)
+			}
+		})
+	}
+}
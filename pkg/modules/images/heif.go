@@ -0,0 +1,56 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// heifExtensions are the extensions of the HEIF images, e.g., the HEIC
+// photos of iPhones, and of the AVIF images. The standard library cannot
+// decode them, so they are converted to PNG on ingestion.
+var heifExtensions = []string{".heic", ".heif", ".avif"}
+
+func isHeif(path string) bool {
+	return slices.Contains(heifExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// convertHeif converts a HEIF image to PNG with the heif-convert command
+// from libheif, which also applies the rotation and mirroring of the image.
+// The command runs in the given sandbox, if enabled, which owns the
+// directory of the output file.
+func convertHeif(ctx context.Context, logger *zap.Logger, binPath string, sandbox gotenberg.SandboxOptions, inputPath, outputPath string) error {
+	err := sandbox.Own(filepath.Dir(outputPath))
+	if err != nil {
+		return fmt.Errorf("own working directory: %w", err)
+	}
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath, inputPath, outputPath)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	err = cmd.SetSandbox(sandbox)
+	if err != nil {
+		return fmt.Errorf("set sandbox: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	// Only a broken image should fail the conversion, unless the context
+	// is done.
+	if ctx.Err() != nil {
+		return fmt.Errorf("convert HEIF image with heif-convert: %w", err)
+	}
+
+	return fmt.Errorf("convert HEIF image with heif-convert: %v: %w", err, ErrInvalidImage)
+}
@@ -0,0 +1,115 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakeHeifConvert writes a script which mimics heif-convert: it either writes
+// the given PNG to the output path, or fails if there is none.
+func fakeHeifConvert(t *testing.T, png []byte) string {
+	dirPath := t.TempDir()
+	script := "exit 1\n"
+
+	if png != nil {
+		pngPath := filepath.Join(dirPath, "image.png")
+
+		err := os.WriteFile(pngPath, png, 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		script = fmt.Sprintf("cp '%s' \"$2\"\n", pngPath)
+	}
+
+	return gotenbergtest.FakeBinary(t, "heif-convert", script)
+}
+
+func TestIsHeif(t *testing.T) {
+	for _, tc := range []struct {
+		path   string
+		expect bool
+	}{
+		{path: "/foo/photo.HEIC", expect: true},
+		{path: "/foo/photo.heif", expect: true},
+		{path: "/foo/photo.avif", expect: true},
+		{path: "/foo/photo.png", expect: false},
+		{path: "/foo/heic", expect: false},
+	} {
+		actual := isHeif(tc.path)
+		if actual != tc.expect {
+			t.Errorf("expected %t for '%s' but got %t", tc.expect, tc.path, actual)
+		}
+	}
+}
+
+func TestConvertHeif(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		ctx           context.Context
+		expectError   bool
+		expectInvalid bool
+	}{
+		{
+			scenario:      "invalid image",
+			binPath:       fakeHeifConvert(t, nil),
+			ctx:           context.Background(),
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario: "context done",
+			binPath:  fakeHeifConvert(t, nil),
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			}(),
+			expectError:   true,
+			expectInvalid: false,
+		},
+		{
+			scenario:    "success",
+			binPath:     fakeHeifConvert(t, encodeTestImage(t, "png", 1, 1)),
+			ctx:         context.Background(),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "foo.png")
+
+			err := convertHeif(tc.ctx, zap.NewNop(), tc.binPath, gotenberg.SandboxOptions{}, "/foo.heic", outputPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				if tc.expectInvalid != errors.Is(err, ErrInvalidImage) {
+					t.Errorf("expected ErrInvalidImage to be %t but got: %v", tc.expectInvalid, err)
+				}
+
+				return
+			}
+
+			_, err = os.Stat(outputPath)
+			if err != nil {
+				t.Errorf("expected output file but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package images
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+
+	_ "golang.org/x/image/tiff"
+	_ "golang.org/x/image/webp"
+)
+
+// maxPixels is the maximum number of pixels of an image, so that a small
+// file cannot claim gigabytes of memory once decoded.
+const maxPixels = 100_000_000
+
+// ErrInvalidImage happens if an image cannot be decoded.
+var ErrInvalidImage = errors.New("invalid image")
+
+// pdfImage is an image, ready to be embedded in a PDF as an image XObject.
+type pdfImage struct {
+	// Width and height are in pixels, after the EXIF orientation.
+	width, height int
+	colorSpace    string
+	filter        string
+	data          []byte
+	// alpha is the compressed alpha channel, if any.
+	alpha []byte
+}
+
+// loadImage reads an image file and converts it to a [pdfImage].
+func loadImage(path string) (pdfImage, error) {
+	data, config, format, err := readImage(path)
+	if err != nil {
+		return pdfImage{}, err
+	}
+
+	orientation := exifOrientation(format, data)
+	isGray := config.ColorModel == color.GrayModel || config.ColorModel == color.Gray16Model
+
+	// PDF readers decode the JPEG images on their own, so there is no need to
+	// re-encode them, unless they have to be rotated or are in CMYK.
+	if format == "jpeg" && orientation == 1 && (isGray || config.ColorModel == color.YCbCrModel) {
+		colorSpace := "DeviceRGB"
+		if isGray {
+			colorSpace = "DeviceGray"
+		}
+
+		return pdfImage{
+			width:      config.Width,
+			height:     config.Height,
+			colorSpace: colorSpace,
+			filter:     "DCTDecode",
+			data:       data,
+		}, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return pdfImage{}, fmt.Errorf("decode image: %v: %w", err, ErrInvalidImage)
+	}
+
+	return encodeImage(orient(toNrgba(img), orientation), isGray)
+}
+
+// readImage reads an image file, and checks its dimensions before it is
+// decoded.
+func readImage(path string) ([]byte, image.Config, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, image.Config{}, "", fmt.Errorf("read image: %w", err)
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, image.Config{}, "", fmt.Errorf("decode image config: %v: %w", err, ErrInvalidImage)
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, image.Config{}, "", fmt.Errorf("image of %dx%d pixels: %w", config.Width, config.Height, ErrInvalidImage)
+	}
+
+	return data, config, format, nil
+}
+
+// DecodeImage reads an image file, i.e., a JPEG, PNG, TIFF or WebP file, and
+// applies its EXIF orientation. Other modules may use it for reading the
+// uploaded images.
+func DecodeImage(path string) (*image.NRGBA, error) {
+	data, _, format, err := readImage(path)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %v: %w", err, ErrInvalidImage)
+	}
+
+	return orient(toNrgba(img), exifOrientation(format, data)), nil
+}
+
+// encodeImage compresses the pixels of an image, and its alpha channel if
+// not fully opaque.
+func encodeImage(img *image.NRGBA, isGray bool) (pdfImage, error) {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+
+	components := 3
+	colorSpace := "DeviceRGB"
+	if isGray {
+		components = 1
+		colorSpace = "DeviceGray"
+	}
+
+	pixels := make([]byte, 0, width*height*components)
+	alpha := make([]byte, 0, width*height)
+	opaque := true
+
+	for y := 0; y < height; y++ {
+		row := img.Pix[y*img.Stride : y*img.Stride+width*4]
+		for x := 0; x < width*4; x += 4 {
+			if isGray {
+				pixels = append(pixels, row[x])
+			} else {
+				pixels = append(pixels, row[x], row[x+1], row[x+2])
+			}
+
+			alpha = append(alpha, row[x+3])
+			if row[x+3] != 0xFF {
+				opaque = false
+			}
+		}
+	}
+
+	data, err := deflate(pixels)
+	if err != nil {
+		return pdfImage{}, fmt.Errorf("compress image: %w", err)
+	}
+
+	result := pdfImage{
+		width:      width,
+		height:     height,
+		colorSpace: colorSpace,
+		filter:     "FlateDecode",
+		data:       data,
+	}
+
+	if !opaque {
+		result.alpha, err = deflate(alpha)
+		if err != nil {
+			return pdfImage{}, fmt.Errorf("compress alpha channel: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+
+	_, err := w.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// toNrgba converts an image to non-premultiplied RGBA, with its origin at
+// (0, 0).
+func toNrgba(img image.Image) *image.NRGBA {
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Rect, img, bounds.Min, draw.Src)
+
+	return nrgba
+}
+
+// orient applies an EXIF orientation to an image, so that it displays as
+// intended.
+func orient(img *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+
+	// Orientations 5 to 8 swap the width and the height.
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for sy := 0; sy < h; sy++ {
+		for sx := 0; sx < w; sx++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirror horizontal.
+				dx, dy = w-1-sx, sy
+			case 3: // Rotate 180.
+				dx, dy = w-1-sx, h-1-sy
+			case 4: // Mirror vertical.
+				dx, dy = sx, h-1-sy
+			case 5: // Transpose.
+				dx, dy = sy, sx
+			case 6: // Rotate 90 clockwise.
+				dx, dy = h-1-sy, sx
+			case 7: // Transverse.
+				dx, dy = h-1-sy, w-1-sx
+			case 8: // Rotate 90 counterclockwise.
+				dx, dy = sy, w-1-sx
+			}
+
+			copy(dst.Pix[dy*dst.Stride+dx*4:dy*dst.Stride+dx*4+4], img.Pix[sy*img.Stride+sx*4:sy*img.Stride+sx*4+4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,345 @@
+package images
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/tiff"
+)
+
+// A 1x1 lossless WebP, as there is no WebP encoder.
+const testWebp = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+// encodeTestImage encodes an image whose top-left pixel is red, and the
+// others are blue.
+func encodeTestImage(t *testing.T, format string, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{B: 0xFF, A: 0xFF})
+		}
+	}
+	img.Set(0, 0, color.NRGBA{R: 0xFF, A: 0xFF})
+
+	var (
+		buf bytes.Buffer
+		err error
+	)
+
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "png":
+		err = png.Encode(&buf, img)
+	case "tiff":
+		err = tiff.Encode(&buf, img, nil)
+	case "webp":
+		var data []byte
+		data, err = base64.StdEncoding.DecodeString(testWebp)
+		buf.Write(data)
+	default:
+		t.Fatalf("unexpected format '%s'", format)
+	}
+
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func writeTestFile(t *testing.T, filename string, data []byte) string {
+	path := filepath.Join(t.TempDir(), filename)
+
+	err := os.WriteFile(path, data, 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadImage(t *testing.T) {
+	for _, tc := range []struct {
+		scenario         string
+		filename         string
+		data             []byte
+		expectWidth      int
+		expectHeight     int
+		expectColorSpace string
+		expectFilter     string
+		expectAlpha      bool
+		expectError      bool
+		expectInvalid    bool
+	}{
+		{
+			scenario:      "non-existing file",
+			filename:      "",
+			expectError:   true,
+			expectInvalid: false,
+		},
+		{
+			scenario:      "not an image",
+			filename:      "foo.png",
+			data:          []byte("foo"),
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario:      "truncated image",
+			filename:      "foo.png",
+			data:          encodeTestImage(t, "png", 1, 1)[:16],
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario: "too many pixels",
+			filename: "foo.png",
+			data: func() []byte {
+				// Only the header, which claims 20000x20000 pixels.
+				data := encodeTestImage(t, "png", 1, 1)[:33]
+				binary.BigEndian.PutUint32(data[16:], 20000)
+				binary.BigEndian.PutUint32(data[20:], 20000)
+				binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+				return data
+			}(),
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario:         "JPEG as is",
+			filename:         "foo.jpg",
+			data:             encodeTestImage(t, "jpeg", 4, 2),
+			expectWidth:      4,
+			expectHeight:     2,
+			expectColorSpace: "DeviceRGB",
+			expectFilter:     "DCTDecode",
+		},
+		{
+			scenario:         "rotated JPEG",
+			filename:         "foo.jpg",
+			data:             jpegWithOrientation(encodeTestImage(t, "jpeg", 4, 2), 6),
+			expectWidth:      2,
+			expectHeight:     4,
+			expectColorSpace: "DeviceRGB",
+			expectFilter:     "FlateDecode",
+		},
+		{
+			scenario:         "PNG",
+			filename:         "foo.png",
+			data:             encodeTestImage(t, "png", 4, 2),
+			expectWidth:      4,
+			expectHeight:     2,
+			expectColorSpace: "DeviceRGB",
+			expectFilter:     "FlateDecode",
+		},
+		{
+			scenario: "PNG with alpha channel",
+			filename: "foo.png",
+			data: func() []byte {
+				var buf bytes.Buffer
+				img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+				_ = png.Encode(&buf, img)
+				return buf.Bytes()
+			}(),
+			expectWidth:      2,
+			expectHeight:     2,
+			expectColorSpace: "DeviceRGB",
+			expectFilter:     "FlateDecode",
+			expectAlpha:      true,
+		},
+		{
+			scenario: "grayscale PNG",
+			filename: "foo.png",
+			data: func() []byte {
+				var buf bytes.Buffer
+				_ = png.Encode(&buf, image.NewGray(image.Rect(0, 0, 3, 1)))
+				return buf.Bytes()
+			}(),
+			expectWidth:      3,
+			expectHeight:     1,
+			expectColorSpace: "DeviceGray",
+			expectFilter:     "FlateDecode",
+		},
+		{
+			scenario:         "TIFF",
+			filename:         "foo.tiff",
+			data:             encodeTestImage(t, "tiff", 4, 2),
+			expectWidth:      4,
+			expectHeight:     2,
+			expectColorSpace: "DeviceRGB",
+			expectFilter:     "FlateDecode",
+		},
+		{
+			scenario:         "WebP",
+			filename:         "foo.webp",
+			data:             encodeTestImage(t, "webp", 1, 1),
+			expectWidth:      1,
+			expectHeight:     1,
+			expectColorSpace: "DeviceRGB",
+			expectFilter:     "FlateDecode",
+			expectAlpha:      true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "foo")
+			if tc.filename != "" {
+				path = writeTestFile(t, tc.filename, tc.data)
+			}
+
+			img, err := loadImage(path)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				if tc.expectInvalid != errors.Is(err, ErrInvalidImage) {
+					t.Errorf("expected ErrInvalidImage to be %t but got: %v", tc.expectInvalid, err)
+				}
+
+				return
+			}
+
+			if img.width != tc.expectWidth || img.height != tc.expectHeight {
+				t.Errorf("expected %dx%d pixels but got %dx%d", tc.expectWidth, tc.expectHeight, img.width, img.height)
+			}
+
+			if img.colorSpace != tc.expectColorSpace {
+				t.Errorf("expected color space '%s' but got '%s'", tc.expectColorSpace, img.colorSpace)
+			}
+
+			if img.filter != tc.expectFilter {
+				t.Errorf("expected filter '%s' but got '%s'", tc.expectFilter, img.filter)
+			}
+
+			if tc.expectAlpha != (img.alpha != nil) {
+				t.Errorf("expected alpha channel to be %t", tc.expectAlpha)
+			}
+		})
+	}
+}
+
+func TestDecodeImage(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		filename      string
+		data          []byte
+		expectWidth   int
+		expectHeight  int
+		expectError   bool
+		expectInvalid bool
+	}{
+		{
+			scenario:      "non-existing file",
+			filename:      "",
+			expectError:   true,
+			expectInvalid: false,
+		},
+		{
+			scenario:      "not an image",
+			filename:      "foo.png",
+			data:          []byte("foo"),
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario:      "truncated image",
+			filename:      "foo.png",
+			data:          encodeTestImage(t, "png", 1, 1)[:16],
+			expectError:   true,
+			expectInvalid: true,
+		},
+		{
+			scenario:     "PNG",
+			filename:     "foo.png",
+			data:         encodeTestImage(t, "png", 4, 2),
+			expectWidth:  4,
+			expectHeight: 2,
+		},
+		{
+			scenario:     "rotated JPEG",
+			filename:     "foo.jpg",
+			data:         jpegWithOrientation(encodeTestImage(t, "jpeg", 4, 2), 6),
+			expectWidth:  2,
+			expectHeight: 4,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "foo")
+			if tc.filename != "" {
+				path = writeTestFile(t, tc.filename, tc.data)
+			}
+
+			img, err := DecodeImage(path)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				if tc.expectInvalid != errors.Is(err, ErrInvalidImage) {
+					t.Errorf("expected ErrInvalidImage to be %t but got: %v", tc.expectInvalid, err)
+				}
+
+				return
+			}
+
+			if img.Rect.Dx() != tc.expectWidth || img.Rect.Dy() != tc.expectHeight {
+				t.Errorf("expected %dx%d pixels but got %dx%d", tc.expectWidth, tc.expectHeight, img.Rect.Dx(), img.Rect.Dy())
+			}
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// A 3x2 image, whose top-left pixel is red.
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.NRGBA{R: 0xFF, A: 0xFF})
+
+	for _, tc := range []struct {
+		orientation  int
+		expectWidth  int
+		expectHeight int
+		expectRedX   int
+		expectRedY   int
+	}{
+		{orientation: 1, expectWidth: 3, expectHeight: 2, expectRedX: 0, expectRedY: 0},
+		{orientation: 2, expectWidth: 3, expectHeight: 2, expectRedX: 2, expectRedY: 0},
+		{orientation: 3, expectWidth: 3, expectHeight: 2, expectRedX: 2, expectRedY: 1},
+		{orientation: 4, expectWidth: 3, expectHeight: 2, expectRedX: 0, expectRedY: 1},
+		{orientation: 5, expectWidth: 2, expectHeight: 3, expectRedX: 0, expectRedY: 0},
+		{orientation: 6, expectWidth: 2, expectHeight: 3, expectRedX: 1, expectRedY: 0},
+		{orientation: 7, expectWidth: 2, expectHeight: 3, expectRedX: 1, expectRedY: 2},
+		{orientation: 8, expectWidth: 2, expectHeight: 3, expectRedX: 0, expectRedY: 2},
+	} {
+		actual := orient(img, tc.orientation)
+
+		if actual.Rect.Dx() != tc.expectWidth || actual.Rect.Dy() != tc.expectHeight {
+			t.Errorf("orientation %d: expected %dx%d pixels but got %dx%d", tc.orientation, tc.expectWidth, tc.expectHeight, actual.Rect.Dx(), actual.Rect.Dy())
+			continue
+		}
+
+		if actual.NRGBAAt(tc.expectRedX, tc.expectRedY).R != 0xFF {
+			t.Errorf("orientation %d: expected red pixel at (%d, %d)", tc.orientation, tc.expectRedX, tc.expectRedY)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package images
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Images))
+}
+
+// Images is a module which provides a route for assembling images into a
+// PDF.
+type Images struct {
+	engine             gotenberg.PdfEngine
+	heifConvertBinPath string
+	sandbox            gotenberg.SandboxOptions
+	disableRoutes      bool
+}
+
+// Descriptor returns an [Images]'s module descriptor.
+func (mod *Images) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "images",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("images", flag.ExitOnError)
+			fs.StringSlice("images-sandbox-namespaces", make([]string, 0), "Set the namespaces each heif-convert process runs in, among user, ipc, pid and uts - the other namespaces require the user namespace when not running as root")
+			fs.Bool("images-sandbox-seccomp", false, "Run each heif-convert process under a seccomp filter which denies the syscalls an image converter has no use for, e.g., mount or ptrace")
+			fs.StringSlice("images-sandbox-seccomp-deny", make([]string, 0), "Set the syscalls the seccomp filter of the heif-convert processes denies on top of the default ones")
+			fs.Int("images-sandbox-uid", 0, "Set the dedicated user ID the heif-convert processes run as, with private working directories - requires Gotenberg to run as root. Set to 0 to disable this feature")
+			fs.Int("images-sandbox-gid", 0, "Set the dedicated group ID the heif-convert processes run as. Set to 0 to disable this feature")
+			fs.Bool("images-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Images) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Images) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("images-disable-routes")
+
+	heifConvertBinPath, ok := os.LookupEnv("HEIF_CONVERT_BIN_PATH")
+	if !ok {
+		return errors.New("HEIF_CONVERT_BIN_PATH environment variable is not set")
+	}
+
+	mod.heifConvertBinPath = heifConvertBinPath
+	mod.sandbox = gotenberg.SandboxOptions{
+		Namespaces:  flags.MustStringSlice("images-sandbox-namespaces"),
+		Seccomp:     flags.MustBool("images-sandbox-seccomp"),
+		SeccompDeny: flags.MustStringSlice("images-sandbox-seccomp-deny"),
+		Uid:         flags.MustInt("images-sandbox-uid"),
+		Gid:         flags.MustInt("images-sandbox-gid"),
+	}
+
+	provider, err := ctx.Module(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine provider: %w", err)
+	}
+
+	engine, err := provider.(gotenberg.PdfEngineProvider).PdfEngine()
+	if err != nil {
+		return fmt.Errorf("get PDF engine: %w", err)
+	}
+
+	mod.engine = engine
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Images) Validate() error {
+	_, err := os.Stat(mod.heifConvertBinPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("heif-convert binary path does not exist: %w", err)
+	}
+
+	err = mod.sandbox.Validate()
+	if err != nil {
+		return fmt.Errorf("validate sandbox: %w", err)
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Images) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		convertRoute(mod.engine, mod.heifConvertBinPath, mod.sandbox),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Images)(nil)
+	_ gotenberg.Provisioner = (*Images)(nil)
+	_ gotenberg.Validator   = (*Images)(nil)
+	_ api.Router            = (*Images)(nil)
+)
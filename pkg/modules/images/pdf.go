@@ -0,0 +1,277 @@
+package images
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// fitContain scales an image to fit its page, keeping its aspect ratio.
+	fitContain = "contain"
+	// fitCover scales an image to cover its page, keeping its aspect ratio.
+	// The overflowing parts are cropped.
+	fitCover = "cover"
+	// fitFill stretches an image to fill its page.
+	fitFill = "fill"
+	// fitNone keeps the image at its natural size, i.e., 96 pixels per inch.
+	// The overflowing parts are cropped.
+	fitNone = "none"
+)
+
+const (
+	pointsPerInch = 72
+	pixelsPerInch = 96
+)
+
+// options gathers the layout of the pages.
+type options struct {
+	// PaperWidth is the paper width, in inches.
+	PaperWidth float64
+
+	// PaperHeight is the paper height, in inches.
+	PaperHeight float64
+
+	// Landscape sets the paper orientation.
+	Landscape bool
+
+	// MarginTop is the top margin, in inches.
+	MarginTop float64
+
+	// MarginBottom is the bottom margin, in inches.
+	MarginBottom float64
+
+	// MarginLeft is the left margin, in inches.
+	MarginLeft float64
+
+	// MarginRight is the right margin, in inches.
+	MarginRight float64
+
+	// Fit is how an image fits its page, either "contain", "cover", "fill",
+	// or "none".
+	Fit string
+}
+
+// defaultOptions returns the default values for [options].
+func defaultOptions() options {
+	return options{
+		PaperWidth:  8.5,
+		PaperHeight: 11,
+		Fit:         fitContain,
+	}
+}
+
+// pageSize returns the width and the height of a page, in points.
+func (o options) pageSize() (float64, float64) {
+	width, height := o.PaperWidth*pointsPerInch, o.PaperHeight*pointsPerInch
+	if o.Landscape {
+		return height, width
+	}
+
+	return width, height
+}
+
+// box returns the area of a page within its margins, in points, from its
+// bottom-left corner.
+func (o options) box() rect {
+	width, height := o.pageSize()
+
+	return rect{
+		x:      o.MarginLeft * pointsPerInch,
+		y:      o.MarginBottom * pointsPerInch,
+		width:  width - (o.MarginLeft+o.MarginRight)*pointsPerInch,
+		height: height - (o.MarginTop+o.MarginBottom)*pointsPerInch,
+	}
+}
+
+type rect struct {
+	x, y, width, height float64
+}
+
+// layout returns where to draw an image of the given size, in pixels, within
+// a box.
+func layout(box rect, width, height int, fit string) rect {
+	w, h := float64(width), float64(height)
+
+	var scale float64
+	switch fit {
+	case fitFill:
+		return box
+	case fitCover:
+		scale = math.Max(box.width/w, box.height/h)
+	case fitNone:
+		scale = float64(pointsPerInch) / pixelsPerInch
+	default:
+		scale = math.Min(box.width/w, box.height/h)
+	}
+
+	w, h = w*scale, h*scale
+
+	// Centered.
+	return rect{
+		x:      box.x + (box.width-w)/2,
+		y:      box.y + (box.height-h)/2,
+		width:  w,
+		height: h,
+	}
+}
+
+// writePdf assembles images into a PDF, one image per page. It loads the
+// images one at a time.
+func writePdf(outputPath string, inputPaths []string, opts options) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create PDF: %w", err)
+	}
+
+	w := &pdfWriter{w: bufio.NewWriter(f)}
+
+	err = w.write(inputPaths, opts)
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	err = w.w.Flush()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write PDF: %w", err)
+	}
+
+	return f.Close()
+}
+
+// pdfWriter writes a minimal PDF 1.4 document.
+type pdfWriter struct {
+	w       *bufio.Writer
+	offset  int
+	offsets []int
+	err     error
+}
+
+const (
+	catalogObject = 1
+	pagesObject   = 2
+)
+
+func (pw *pdfWriter) write(inputPaths []string, opts options) error {
+	// Objects 1 and 2 are the catalog and the page tree.
+	pw.offsets = make([]int, 2)
+	pw.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	pageWidth, pageHeight := opts.pageSize()
+	box := opts.box()
+	kids := make([]string, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		img, err := loadImage(inputPath)
+		if err != nil {
+			return fmt.Errorf("load image '%s': %w", inputPath, err)
+		}
+
+		smask := 0
+		if img.alpha != nil {
+			smask = pw.beginObject()
+			pw.stream(
+				fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode", img.width, img.height),
+				img.alpha,
+			)
+		}
+
+		imageObject := pw.beginObject()
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s", img.width, img.height, img.colorSpace, img.filter)
+		if smask != 0 {
+			dict += fmt.Sprintf(" /SMask %d 0 R", smask)
+		}
+		pw.stream(dict, img.data)
+
+		// Clip to the box, then draw the image in its rectangle.
+		r := layout(box, img.width, img.height, opts.Fit)
+		content := fmt.Sprintf(
+			"q %s %s %s %s re W n %s 0 0 %s %s %s cm /Im0 Do Q",
+			number(box.x), number(box.y), number(box.width), number(box.height),
+			number(r.width), number(r.height), number(r.x), number(r.y),
+		)
+
+		contentObject := pw.beginObject()
+		pw.stream("", []byte(content))
+
+		pageObject := pw.beginObject()
+		pw.printf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /XObject << /Im0 %d 0 R >> >> /Contents %d 0 R >>\nendobj\n",
+			pagesObject, number(pageWidth), number(pageHeight), imageObject, contentObject,
+		)
+
+		kids[i] = fmt.Sprintf("%d 0 R", pageObject)
+
+		if pw.err != nil {
+			return fmt.Errorf("write PDF: %w", pw.err)
+		}
+	}
+
+	pw.object(catalogObject)
+	pw.printf("<< /Type /Catalog /Pages %d 0 R >>\nendobj\n", pagesObject)
+
+	pw.object(pagesObject)
+	pw.printf("<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(kids))
+
+	xref := pw.offset
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, offset := range pw.offsets {
+		pw.printf("%010d 00000 n \n", offset)
+	}
+	pw.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, catalogObject, xref)
+
+	if pw.err != nil {
+		return fmt.Errorf("write PDF: %w", pw.err)
+	}
+
+	return nil
+}
+
+// beginObject starts a new object and returns its number.
+func (pw *pdfWriter) beginObject() int {
+	pw.offsets = append(pw.offsets, 0)
+	n := len(pw.offsets)
+	pw.object(n)
+
+	return n
+}
+
+// object starts the object with the given number.
+func (pw *pdfWriter) object(n int) {
+	pw.offsets[n-1] = pw.offset
+	pw.printf("%d 0 obj\n", n)
+}
+
+func (pw *pdfWriter) stream(dict string, data []byte) {
+	if dict != "" {
+		dict += " "
+	}
+
+	pw.printf("<< %s/Length %d >>\nstream\n", dict, len(data))
+	pw.bytes(data)
+	pw.printf("\nendstream\nendobj\n")
+}
+
+func (pw *pdfWriter) printf(format string, args ...interface{}) {
+	pw.bytes([]byte(fmt.Sprintf(format, args...)))
+}
+
+func (pw *pdfWriter) bytes(data []byte) {
+	if pw.err != nil {
+		return
+	}
+
+	var n int
+	n, pw.err = pw.w.Write(data)
+	pw.offset += n
+}
+
+// number formats a number for a PDF, with at most 3 decimals.
+func number(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
@@ -0,0 +1,151 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func TestOptions_box(t *testing.T) {
+	for _, tc := range []struct {
+		scenario  string
+		options   options
+		expectBox rect
+	}{
+		{
+			scenario:  "default options",
+			options:   defaultOptions(),
+			expectBox: rect{x: 0, y: 0, width: 612, height: 792},
+		},
+		{
+			scenario: "landscape with margins",
+			options: options{
+				PaperWidth:   8.5,
+				PaperHeight:  11,
+				Landscape:    true,
+				MarginTop:    1,
+				MarginBottom: 0.5,
+				MarginLeft:   0.25,
+				MarginRight:  0.75,
+			},
+			expectBox: rect{x: 18, y: 36, width: 720, height: 504},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := tc.options.box()
+			if actual != tc.expectBox {
+				t.Errorf("expected %+v but got %+v", tc.expectBox, actual)
+			}
+		})
+	}
+}
+
+func TestLayout(t *testing.T) {
+	box := rect{x: 10, y: 20, width: 200, height: 100}
+
+	for _, tc := range []struct {
+		fit    string
+		expect rect
+	}{
+		{fit: fitContain, expect: rect{x: 85, y: 20, width: 50, height: 100}},
+		{fit: fitCover, expect: rect{x: 10, y: -130, width: 200, height: 400}},
+		{fit: fitFill, expect: box},
+		{fit: fitNone, expect: rect{x: 95, y: 40, width: 30, height: 60}},
+	} {
+		t.Run(tc.fit, func(t *testing.T) {
+			// A 40x80 pixels image.
+			actual := layout(box, 40, 80, tc.fit)
+			if actual != tc.expect {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestWritePdf(t *testing.T) {
+	inputPaths := []string{
+		writeTestFile(t, "1.jpg", encodeTestImage(t, "jpeg", 40, 20)),
+		writeTestFile(t, "2.png", encodeTestImage(t, "png", 20, 40)),
+		writeTestFile(t, "3.webp", encodeTestImage(t, "webp", 1, 1)),
+		writeTestFile(t, "4.tiff", encodeTestImage(t, "tiff", 10, 10)),
+	}
+	outputPath := filepath.Join(t.TempDir(), "foo.pdf")
+
+	opts := defaultOptions()
+	opts.MarginTop = 0.5
+	opts.Fit = fitCover
+
+	err := writePdf(outputPath, inputPaths, opts)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	b, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, expect := range [][]byte{
+		[]byte("%PDF-1.4"),
+		[]byte("/Filter /DCTDecode"),
+		[]byte("/SMask"),
+		[]byte("/MediaBox [0 0 612 792]"),
+		[]byte("%%EOF"),
+	} {
+		if !bytes.Contains(b, expect) {
+			t.Errorf("expected PDF to contain '%s'", expect)
+		}
+	}
+
+	pdfcpuModel.ConfigPath = "disable"
+	conf := pdfcpuModel.NewDefaultConfiguration()
+	conf.ValidationMode = pdfcpuModel.ValidationStrict
+
+	count, err := pdfcpuAPI.PageCount(bytes.NewReader(b), conf)
+	if err != nil {
+		t.Fatalf("expected a valid PDF but got: %v", err)
+	}
+
+	if count != len(inputPaths) {
+		t.Errorf("expected %d pages but got %d", len(inputPaths), count)
+	}
+
+	err = pdfcpuAPI.Validate(bytes.NewReader(b), conf)
+	if err != nil {
+		t.Errorf("expected a valid PDF but got: %v", err)
+	}
+}
+
+func TestWritePdf_invalidImage(t *testing.T) {
+	inputPaths := []string{
+		writeTestFile(t, "1.png", encodeTestImage(t, "png", 1, 1)),
+		writeTestFile(t, "2.png", []byte("foo")),
+	}
+
+	err := writePdf(filepath.Join(t.TempDir(), "foo.pdf"), inputPaths, defaultOptions())
+	if !errors.Is(err, ErrInvalidImage) {
+		t.Errorf("expected ErrInvalidImage but got: %v", err)
+	}
+}
+
+func TestNumber(t *testing.T) {
+	for _, tc := range []struct {
+		value  float64
+		expect string
+	}{
+		{value: 612, expect: "612"},
+		{value: -130, expect: "-130"},
+		{value: 0.75, expect: "0.75"},
+		{value: 1.0 / 3, expect: "0.333"},
+	} {
+		actual := number(tc.value)
+		if actual != tc.expect {
+			t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+		}
+	}
+}
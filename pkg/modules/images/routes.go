@@ -0,0 +1,149 @@
+package images
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// extensions are the extensions of the supported images.
+var extensions = append([]string{".jpg", ".jpeg", ".png", ".tif", ".tiff", ".webp"}, heifExtensions...)
+
+// convertRoute returns an [api.Route] which can assemble images into a PDF.
+func convertRoute(engine gotenberg.PdfEngine, heifConvertBinPath string, sandbox gotenberg.SandboxOptions) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/images/convert",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			defaultOpts := defaultOptions()
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				opts       options
+				pdfa       string
+				pdfua      bool
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths(extensions, &inputPaths).
+				Float64("paperWidth", &opts.PaperWidth, defaultOpts.PaperWidth).
+				Float64("paperHeight", &opts.PaperHeight, defaultOpts.PaperHeight).
+				Bool("landscape", &opts.Landscape, defaultOpts.Landscape).
+				Float64("marginTop", &opts.MarginTop, defaultOpts.MarginTop).
+				Float64("marginBottom", &opts.MarginBottom, defaultOpts.MarginBottom).
+				Float64("marginLeft", &opts.MarginLeft, defaultOpts.MarginLeft).
+				Float64("marginRight", &opts.MarginRight, defaultOpts.MarginRight).
+				Custom("fit", func(value string) error {
+					if value == "" {
+						opts.Fit = defaultOpts.Fit
+						return nil
+					}
+
+					if value != fitContain && value != fitCover && value != fitFill && value != fitNone {
+						return fmt.Errorf("wrong value, expected either '%s', '%s', '%s' or '%s'", fitContain, fitCover, fitFill, fitNone)
+					}
+
+					opts.Fit = value
+
+					return nil
+				}).
+				String("pdfa", &pdfa, "").
+				Bool("pdfua", &pdfua, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			box := opts.box()
+			if opts.MarginTop < 0 || opts.MarginBottom < 0 || opts.MarginLeft < 0 || opts.MarginRight < 0 || box.width <= 0 || box.height <= 0 {
+				return api.WrapError(
+					errors.New("invalid page layout"),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						"The paper size and the margins leave no room for the images",
+					).WithCode(api.ErrorCodeImagesInvalidPageLayout),
+				)
+			}
+
+			pdfFormats := gotenberg.PdfFormats{
+				PdfA:  pdfa,
+				PdfUa: pdfua,
+			}
+
+			invalidImageErr := func(err error) error {
+				return api.WrapError(
+					err,
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						"At least one image is invalid or too large",
+					).WithCode(api.ErrorCodeImagesInvalidImage),
+				)
+			}
+
+			// The HEIF images are converted to PNG first.
+			for i, inputPath := range inputPaths {
+				if !isHeif(inputPath) {
+					continue
+				}
+
+				pngPath := ctx.GeneratePath("", ".png")
+
+				err = convertHeif(ctx, ctx.Log(), heifConvertBinPath, sandbox, inputPath, pngPath)
+				if err != nil {
+					if errors.Is(err, ErrInvalidImage) {
+						return invalidImageErr(fmt.Errorf("convert HEIF image: %w", err))
+					}
+
+					return fmt.Errorf("convert HEIF image: %w", err)
+				}
+
+				inputPaths[i] = pngPath
+			}
+
+			// Alright, let's assemble the images.
+
+			outputPath := ctx.GeneratePath("", ".pdf")
+
+			err = writePdf(outputPath, inputPaths, opts)
+			if err != nil {
+				if errors.Is(err, ErrInvalidImage) {
+					return invalidImageErr(fmt.Errorf("assemble images: %w", err))
+				}
+
+				return fmt.Errorf("assemble images: %w", err)
+			}
+
+			// So far so good, the images are assembled into one unique PDF.
+			// Now, let's check if the client want to convert this result PDF
+			// to specific PDF formats.
+			zeroValued := gotenberg.PdfFormats{}
+			if pdfFormats != zeroValued {
+				convertInputPath := outputPath
+				convertOutputPath := ctx.GeneratePath("", ".pdf")
+
+				err = engine.Convert(ctx, ctx.Log(), pdfFormats, convertInputPath, convertOutputPath)
+				if err != nil {
+					return fmt.Errorf("convert PDF: %w", err)
+				}
+
+				// Important: the output path is now the converted file.
+				outputPath = convertOutputPath
+			}
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
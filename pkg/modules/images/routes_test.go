@@ -0,0 +1,212 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestConvertRoute(t *testing.T) {
+	newContext := func(files map[string][]byte, values map[string][]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string)
+
+		for filename, data := range files {
+			path := filepath.Join(dirPath, filename)
+
+			err := os.WriteFile(path, data, 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			paths[filename] = path
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetFiles(paths)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	images := map[string][]byte{
+		"1.jpg":  encodeTestImage(t, "jpeg", 4, 2),
+		"2.png":  encodeTestImage(t, "png", 2, 4),
+		"3.tiff": encodeTestImage(t, "tiff", 2, 2),
+		"4.webp": encodeTestImage(t, "webp", 1, 1),
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		engine                 gotenberg.PdfEngine
+		heifConvertBinPath     string
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newContext(nil, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid fit",
+			ctx: newContext(images, map[string][]string{
+				"fit": {"foo"},
+			}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "margins larger than the paper",
+			ctx: newContext(images, map[string][]string{
+				"marginLeft":  {"5"},
+				"marginRight": {"5"},
+			}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "negative margin",
+			ctx: newContext(images, map[string][]string{
+				"marginTop": {"-1"},
+			}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid image",
+			ctx: newContext(map[string][]byte{
+				"1.png": []byte("foo"),
+			}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid HEIF image",
+			ctx: newContext(map[string][]byte{
+				"1.heic": []byte("foo"),
+			}, nil),
+			heifConvertBinPath:     fakeHeifConvert(t, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with HEIF images",
+			ctx: newContext(map[string][]byte{
+				"1.heic": []byte("foo"),
+				"2.avif": []byte("bar"),
+				"3.png":  encodeTestImage(t, "png", 1, 1),
+			}, nil),
+			heifConvertBinPath:     fakeHeifConvert(t, encodeTestImage(t, "png", 2, 2)),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "error from PDF engine (convert)",
+			ctx: newContext(images, map[string][]string{
+				"pdfa": {gotenberg.PdfA1b},
+			}),
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with PDF formats",
+			ctx: newContext(images, map[string][]string{
+				"pdfua": {"true"},
+			}),
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success",
+			ctx: newContext(images, map[string][]string{
+				"paperWidth":  {"8.27"},
+				"paperHeight": {"11.7"},
+				"landscape":   {"true"},
+				"marginTop":   {"0.5"},
+				"fit":         {"cover"},
+			}),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertRoute(tc.engine, tc.heifConvertBinPath, gotenberg.SandboxOptions{}).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+		})
+	}
+}
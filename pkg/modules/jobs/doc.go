@@ -0,0 +1,22 @@
+// Package jobs provides a module which records the lifecycle of the
+// asynchronous requests for a limited time, and adds routes for listing,
+// getting and deleting them. Together with a result store, it lets the
+// clients recover the output files whose webhook delivery failed.
+//
+// The module also handles the asynchronous requests without a webhook, i.e.,
+// with the "async" form field or the "Gotenberg-Async" header: it returns at
+// once a 202 Accepted response with a random ID and a token for the job and
+// the URLs of the job and of its result, converts in the background, a
+// limited number of them at the same time, and keeps the output file in the
+// result store. The time limit of a job starts once it gets its turn. The
+// clients poll the job, then download its result, with the token in the
+// "Gotenberg-Job-Token" header. Otherwise, the job is not found, unless the
+// request has the API admin token.
+//
+// With an outbox directory, the module also persists the webhook deliveries
+// until the webhooks acknowledge them, so that the webhook module retries
+// them after a crash or a restart.
+//
+// With a jobs directory, e.g., on a volume the replicas share in distributed
+// mode, the jobs outlive the restarts and every replica lists them all.
+package jobs
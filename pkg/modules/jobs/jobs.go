@@ -0,0 +1,421 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Jobs))
+}
+
+// errJobNotFound happens if there is no job with a given ID.
+var errJobNotFound = errors.New("job not found")
+
+// Jobs is a module which records the lifecycle of the asynchronous requests
+// and adds routes for listing, getting and deleting them.
+type Jobs struct {
+	enable              bool
+	ttl                 time.Duration
+	maxJobs             int
+	cleanupInterval     time.Duration
+	dir                 string
+	outboxDir           string
+	asyncMaxConcurrency int
+	disableRouteLogging bool
+
+	resultStore gotenberg.ResultStore
+	logger      *zap.Logger
+	store       store
+	asyncSlots  chan struct{}
+	mu          sync.RWMutex
+	stop        chan struct{}
+}
+
+// Descriptor returns a [Jobs]'s module descriptor.
+func (mod *Jobs) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "jobs",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("jobs", flag.ExitOnError)
+			fs.Bool("jobs-enable", false, "Enable the recording of the asynchronous requests and their routes - listing and deleting the jobs require the API admin token")
+			fs.Duration("jobs-ttl", time.Duration(1)*time.Hour, "Set the time after which a job is forgotten - should match the TTL of the results")
+			fs.Int("jobs-max-jobs", 10000, "Set the maximum number of jobs to keep - the oldest jobs are forgotten first")
+			fs.Duration("jobs-cleanup-interval", time.Duration(1)*time.Minute, "Set the interval at which to forget the expired jobs")
+			fs.String("jobs-dir", "", "Set the directory in which to keep the jobs, e.g., a volume the replicas share - empty keeps them in memory")
+			fs.String("jobs-outbox-dir", "", "Set the directory in which to persist the webhook deliveries until their acknowledgment - empty disables the outbox")
+			fs.Int("jobs-async-max-concurrency", 4, "Set the maximum number of asynchronous requests without webhook converting at the same time - the others wait for their turn")
+			fs.Bool("jobs-disable-route-logging", false, "Disable the route logging")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Jobs) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Jobs) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("jobs-enable")
+	mod.ttl = flags.MustDuration("jobs-ttl")
+	mod.maxJobs = flags.MustInt("jobs-max-jobs")
+	mod.cleanupInterval = flags.MustDuration("jobs-cleanup-interval")
+	mod.dir = flags.MustString("jobs-dir")
+	mod.outboxDir = flags.MustString("jobs-outbox-dir")
+	mod.asyncMaxConcurrency = flags.MustInt("jobs-async-max-concurrency")
+	mod.disableRouteLogging = flags.MustBool("jobs-disable-route-logging")
+
+	mod.store = newMemoryStore()
+	if mod.dir != "" {
+		mod.store = diskStore{dir: mod.dir}
+	}
+
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	if mod.asyncMaxConcurrency > 0 {
+		mod.asyncSlots = make(chan struct{}, mod.asyncMaxConcurrency)
+	}
+
+	resultStores, err := ctx.Modules(new(gotenberg.ResultStore))
+	if err != nil {
+		return fmt.Errorf("get result stores: %w", err)
+	}
+
+	if len(resultStores) > 1 {
+		return fmt.Errorf("expected at most one result store, but got %d", len(resultStores))
+	}
+
+	if len(resultStores) == 1 {
+		mod.resultStore = resultStores[0].(gotenberg.ResultStore)
+	}
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Jobs) Validate() error {
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.ttl <= 0 {
+		err = multierr.Append(err,
+			errors.New("TTL must be more than 0"),
+		)
+	}
+
+	if mod.maxJobs <= 0 {
+		err = multierr.Append(err,
+			errors.New("maximum number of jobs must be more than 0"),
+		)
+	}
+
+	if mod.cleanupInterval <= 0 {
+		err = multierr.Append(err,
+			errors.New("cleanup interval must be more than 0"),
+		)
+	}
+
+	if mod.asyncMaxConcurrency <= 0 {
+		err = multierr.Append(err,
+			errors.New("asynchronous max concurrency must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Start creates the jobs and outbox directories, if any, and forgets the expired jobs
+// and deliveries periodically.
+func (mod *Jobs) Start() error {
+	if !mod.enable {
+		return nil
+	}
+
+	if mod.dir != "" {
+		err := os.MkdirAll(mod.dir, 0o700)
+		if err != nil {
+			return fmt.Errorf("create jobs directory: %w", err)
+		}
+	}
+
+	if mod.outboxEnabled() {
+		err := os.MkdirAll(mod.outboxDir, 0o700)
+		if err != nil {
+			return fmt.Errorf("create outbox directory: %w", err)
+		}
+	}
+
+	mod.stop = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(mod.cleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-mod.stop:
+				return
+			case <-ticker.C:
+				now := time.Now()
+
+				err := mod.cleanup(now)
+				if err != nil {
+					mod.logger.Error(fmt.Sprintf("forget expired jobs: %s", err))
+				}
+
+				if !mod.outboxEnabled() {
+					continue
+				}
+
+				err = mod.cleanupDeliveries(now)
+				if err != nil {
+					mod.logger.Error(fmt.Sprintf("remove expired deliveries: %s", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Jobs) StartupMessage() string {
+	if !mod.enable {
+		return "jobs disabled"
+	}
+
+	if !mod.outboxEnabled() {
+		return fmt.Sprintf("jobs kept for %s", mod.ttl)
+	}
+
+	return fmt.Sprintf("jobs kept for %s, webhook deliveries persisted in '%s'", mod.ttl, mod.outboxDir)
+}
+
+// Stop stops the periodic cleanup of the expired jobs.
+func (mod *Jobs) Stop(ctx context.Context) error {
+	if mod.stop != nil {
+		close(mod.stop)
+	}
+
+	return nil
+}
+
+// TrackJob creates or updates a job. The creation date of an existing job
+// never changes.
+func (mod *Jobs) TrackJob(job gotenberg.Job) {
+	if !mod.enable {
+		return
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	existing, ok, err := mod.store.get(job.ID)
+	if err != nil {
+		mod.logger.Error(fmt.Sprintf("get job '%s': %s", job.ID, err))
+	}
+
+	if ok {
+		job.CreatedAt = existing.CreatedAt
+	}
+
+	err = mod.store.put(job)
+	if err != nil {
+		mod.logger.Error(fmt.Sprintf("put job '%s': %s", job.ID, err))
+
+		return
+	}
+
+	if ok {
+		return
+	}
+
+	count, err := mod.store.count()
+	if err != nil || count <= mod.maxJobs {
+		return
+	}
+
+	// Forget the oldest job.
+	jobs, err := mod.store.all()
+	if err != nil {
+		mod.logger.Error(fmt.Sprintf("get jobs: %s", err))
+	}
+
+	var oldest gotenberg.Job
+	for _, j := range jobs {
+		if oldest.ID == "" || j.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = j
+		}
+	}
+
+	err = mod.store.remove(oldest.ID)
+	if err != nil {
+		mod.logger.Error(fmt.Sprintf("remove job '%s': %s", oldest.ID, err))
+
+		return
+	}
+
+	mod.logger.Debug(fmt.Sprintf("job '%s' forgotten, too many jobs", oldest.ID))
+}
+
+// job returns a job. It returns [errJobNotFound] if there is no job with the
+// given ID.
+func (mod *Jobs) job(id string) (gotenberg.Job, error) {
+	mod.mu.RLock()
+	defer mod.mu.RUnlock()
+
+	job, ok, err := mod.store.get(id)
+	if err != nil {
+		return gotenberg.Job{}, fmt.Errorf("get job: %w", err)
+	}
+
+	if !ok {
+		return gotenberg.Job{}, errJobNotFound
+	}
+
+	return job, nil
+}
+
+// list returns the jobs matching a query, the most recent first.
+func (mod *Jobs) list(q query) ([]gotenberg.Job, string, error) {
+	mod.mu.RLock()
+	all, err := mod.store.all()
+	mod.mu.RUnlock()
+
+	if err != nil {
+		return nil, "", fmt.Errorf("get jobs: %w", err)
+	}
+
+	jobs := make([]gotenberg.Job, 0, len(all))
+	for _, job := range all {
+		if q.matches(job) {
+			jobs = append(jobs, job)
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return before(jobs[i], jobs[j])
+	})
+
+	if q.after != nil {
+		start := sort.Search(len(jobs), func(i int) bool {
+			return before(*q.after, jobs[i])
+		})
+		jobs = jobs[start:]
+	}
+
+	if len(jobs) <= q.limit {
+		return jobs, "", nil
+	}
+
+	jobs = jobs[:q.limit]
+
+	return jobs, encodeCursor(jobs[len(jobs)-1]), nil
+}
+
+// delete forgets a job and removes its result, if any. It returns
+// [errJobNotFound] if there is no job with the given ID.
+func (mod *Jobs) delete(id string) error {
+	mod.mu.Lock()
+	job, ok, err := mod.store.get(id)
+	if err == nil && ok {
+		err = mod.store.remove(id)
+	}
+	mod.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("remove job: %w", err)
+	}
+
+	if !ok {
+		return errJobNotFound
+	}
+
+	if job.Result == nil || mod.resultStore == nil {
+		return nil
+	}
+
+	err = mod.resultStore.DeleteResult(job.Result.ID)
+	if err != nil {
+		return fmt.Errorf("delete result: %w", err)
+	}
+
+	return nil
+}
+
+// cleanup forgets the jobs older than the TTL.
+func (mod *Jobs) cleanup(now time.Time) error {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	jobs, err := mod.store.all()
+
+	for _, job := range jobs {
+		if now.Sub(job.CreatedAt) < mod.ttl {
+			continue
+		}
+
+		removeErr := mod.store.remove(job.ID)
+		if removeErr != nil {
+			err = multierr.Append(err, removeErr)
+			continue
+		}
+
+		mod.logger.Debug(fmt.Sprintf("job '%s' forgotten", job.ID))
+	}
+
+	return err
+}
+
+// before tells if a job comes before another one in the listings, i.e., the
+// most recent first, then by ID.
+func before(a, b gotenberg.Job) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+
+	return a.ID < b.ID
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Jobs)(nil)
+	_ gotenberg.Provisioner  = (*Jobs)(nil)
+	_ gotenberg.Validator    = (*Jobs)(nil)
+	_ gotenberg.App          = (*Jobs)(nil)
+	_ gotenberg.JobTracker   = (*Jobs)(nil)
+	_ gotenberg.Outbox       = (*Jobs)(nil)
+	_ api.Router             = (*Jobs)(nil)
+	_ api.MiddlewareProvider = (*Jobs)(nil)
+)
@@ -0,0 +1,309 @@
+package jobs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestJobs_Descriptor(t *testing.T) {
+	descriptor := new(Jobs).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Jobs))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestJobs_Provision(t *testing.T) {
+	mod := new(Jobs)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Jobs).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enable {
+		t.Error("expected jobs to be disabled by default")
+	}
+
+	if mod.store == nil {
+		t.Error("expected the store to be initialized")
+	}
+}
+
+func TestJobs_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario            string
+		enable              bool
+		ttl                 time.Duration
+		maxJobs             int
+		cleanupInterval     time.Duration
+		asyncMaxConcurrency int
+		expectError         bool
+	}{
+		{
+			scenario: "disabled",
+		},
+		{
+			scenario:            "invalid TTL",
+			enable:              true,
+			maxJobs:             10,
+			cleanupInterval:     time.Duration(1) * time.Minute,
+			asyncMaxConcurrency: 4,
+			expectError:         true,
+		},
+		{
+			scenario:            "invalid maximum number of jobs",
+			enable:              true,
+			ttl:                 time.Duration(1) * time.Hour,
+			cleanupInterval:     time.Duration(1) * time.Minute,
+			asyncMaxConcurrency: 4,
+			expectError:         true,
+		},
+		{
+			scenario:            "invalid cleanup interval",
+			enable:              true,
+			ttl:                 time.Duration(1) * time.Hour,
+			maxJobs:             10,
+			asyncMaxConcurrency: 4,
+			expectError:         true,
+		},
+		{
+			scenario:        "invalid asynchronous max concurrency",
+			enable:          true,
+			ttl:             time.Duration(1) * time.Hour,
+			maxJobs:         10,
+			cleanupInterval: time.Duration(1) * time.Minute,
+			expectError:     true,
+		},
+		{
+			scenario:            "validate success",
+			enable:              true,
+			ttl:                 time.Duration(1) * time.Hour,
+			maxJobs:             10,
+			cleanupInterval:     time.Duration(1) * time.Minute,
+			asyncMaxConcurrency: 4,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Jobs{
+				enable:              tc.enable,
+				ttl:                 tc.ttl,
+				maxJobs:             tc.maxJobs,
+				cleanupInterval:     tc.cleanupInterval,
+				asyncMaxConcurrency: tc.asyncMaxConcurrency,
+			}
+
+			err := mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestJobs_TrackJob(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		mod := &Jobs{store: newMemoryStore()}
+		mod.TrackJob(gotenberg.Job{ID: "foo"})
+
+		count, _ := mod.store.count()
+		if count != 0 {
+			t.Errorf("expected no job but got %d", count)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mod := &Jobs{enable: true, maxJobs: 10, store: newMemoryStore(), logger: zap.NewNop()}
+		createdAt := time.Now()
+
+		mod.TrackJob(gotenberg.Job{ID: "foo", State: gotenberg.JobStateQueued, CreatedAt: createdAt})
+		mod.TrackJob(gotenberg.Job{ID: "foo", State: gotenberg.JobStateDone, CreatedAt: createdAt.Add(time.Minute)})
+
+		job, err := mod.job("foo")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if job.State != gotenberg.JobStateDone {
+			t.Errorf("expected state '%s' but got '%s'", gotenberg.JobStateDone, job.State)
+		}
+
+		if !job.CreatedAt.Equal(createdAt) {
+			t.Errorf("expected creation date %s but got %s", createdAt, job.CreatedAt)
+		}
+	})
+
+	t.Run("too many jobs", func(t *testing.T) {
+		mod := &Jobs{enable: true, maxJobs: 2, store: newMemoryStore(), logger: zap.NewNop()}
+		now := time.Now()
+
+		mod.TrackJob(gotenberg.Job{ID: "foo", CreatedAt: now})
+		mod.TrackJob(gotenberg.Job{ID: "bar", CreatedAt: now.Add(-time.Minute)})
+		mod.TrackJob(gotenberg.Job{ID: "baz", CreatedAt: now.Add(time.Minute)})
+
+		_, err := mod.job("bar")
+		if !errors.Is(err, errJobNotFound) {
+			t.Errorf("expected the oldest job to be forgotten but got: %v", err)
+		}
+
+		count, _ := mod.store.count()
+		if count != 2 {
+			t.Errorf("expected 2 jobs but got %d", count)
+		}
+	})
+}
+
+func TestJobs_list(t *testing.T) {
+	now := time.Now()
+	mod := &Jobs{enable: true, maxJobs: 10, store: newMemoryStore(), logger: zap.NewNop()}
+
+	for _, job := range []gotenberg.Job{
+		{ID: "a", Path: "/forms/chromium/convert/url", State: gotenberg.JobStateDone, Delivered: true, CreatedAt: now.Add(-3 * time.Minute)},
+		{ID: "b", Path: "/forms/chromium/convert/html", State: gotenberg.JobStateDone, CreatedAt: now.Add(-2 * time.Minute)},
+		{ID: "c", Path: "/forms/libreoffice/convert", State: gotenberg.JobStateFailed, CreatedAt: now.Add(-1 * time.Minute)},
+		{ID: "d", Path: "/forms/chromium/convert/url", State: gotenberg.JobStateDone, CreatedAt: now.Add(-1 * time.Minute)},
+	} {
+		mod.TrackJob(job)
+	}
+
+	delivered := false
+
+	for _, tc := range []struct {
+		scenario   string
+		query      query
+		expectIds  []string
+		expectNext bool
+	}{
+		{
+			scenario:  "all jobs",
+			query:     query{limit: 10},
+			expectIds: []string{"c", "d", "b", "a"},
+		},
+		{
+			scenario:  "done and undelivered",
+			query:     query{state: gotenberg.JobStateDone, delivered: &delivered, limit: 10},
+			expectIds: []string{"d", "b"},
+		},
+		{
+			scenario:  "path prefix",
+			query:     query{path: "/forms/chromium", limit: 10},
+			expectIds: []string{"d", "b", "a"},
+		},
+		{
+			scenario:  "since and until",
+			query:     query{since: now.Add(-150 * time.Second), until: now.Add(-30 * time.Second), limit: 10},
+			expectIds: []string{"c", "d", "b"},
+		},
+		{
+			scenario:   "first page",
+			query:      query{limit: 2},
+			expectIds:  []string{"c", "d"},
+			expectNext: true,
+		},
+		{
+			scenario:  "next page",
+			query:     query{limit: 2, after: &gotenberg.Job{ID: "d", CreatedAt: now.Add(-1 * time.Minute)}},
+			expectIds: []string{"b", "a"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			jobs, next, err := mod.list(tc.query)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			ids := make([]string, 0, len(jobs))
+			for _, job := range jobs {
+				ids = append(ids, job.ID)
+			}
+
+			if !reflect.DeepEqual(ids, tc.expectIds) {
+				t.Errorf("expected jobs %v but got %v", tc.expectIds, ids)
+			}
+
+			if tc.expectNext && next == "" {
+				t.Error("expected a next cursor")
+			}
+
+			if !tc.expectNext && next != "" {
+				t.Errorf("expected no next cursor but got '%s'", next)
+			}
+		})
+	}
+}
+
+func TestJobs_delete(t *testing.T) {
+	var deleted string
+
+	mod := &Jobs{
+		enable:  true,
+		maxJobs: 10,
+		store:   newMemoryStore(),
+		resultStore: &gotenberg.ResultStoreMock{
+			DeleteResultMock: func(id string) error {
+				deleted = id
+				return nil
+			},
+		},
+		logger: zap.NewNop(),
+	}
+
+	mod.TrackJob(gotenberg.Job{ID: "foo", Result: &gotenberg.StoredResult{ID: "bar"}})
+
+	err := mod.delete("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if deleted != "bar" {
+		t.Errorf("expected result 'bar' to be deleted but got '%s'", deleted)
+	}
+
+	err = mod.delete("foo")
+	if !errors.Is(err, errJobNotFound) {
+		t.Errorf("expected error %v but got: %v", errJobNotFound, err)
+	}
+}
+
+func TestJobs_cleanup(t *testing.T) {
+	now := time.Now()
+	mod := &Jobs{enable: true, ttl: time.Hour, maxJobs: 10, store: newMemoryStore(), logger: zap.NewNop()}
+
+	mod.TrackJob(gotenberg.Job{ID: "foo", CreatedAt: now.Add(-2 * time.Hour)})
+	mod.TrackJob(gotenberg.Job{ID: "bar", CreatedAt: now})
+
+	err := mod.cleanup(now)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = mod.job("foo")
+	if !errors.Is(err, errJobNotFound) {
+		t.Errorf("expected the expired job to be forgotten but got: %v", err)
+	}
+
+	_, err = mod.job("bar")
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
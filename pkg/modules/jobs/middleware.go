@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// Middlewares returns the middleware which handles the asynchronous requests
+// without a webhook.
+func (mod *Jobs) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		asyncMiddleware(mod),
+	}, nil
+}
+
+// acceptedJob is the response to an asynchronous request without a webhook.
+// Only its client gets the token of the job, which the routes of the job
+// require.
+type acceptedJob struct {
+	ID        string `json:"id"`
+	Token     string `json:"token"`
+	StatusUrl string `json:"statusUrl"`
+	ResultUrl string `json:"resultUrl"`
+}
+
+// asyncMiddleware handles the asynchronous requests whose clients poll for
+// the result instead of giving a webhook. It returns immediately with a
+// random ID and a token for the job, and the URLs to poll, then converts in
+// the background.
+// The output file goes to the result store, so that the clients download it
+// once the job is done.
+func asyncMiddleware(mod *Jobs) api.Middleware {
+	return api.Middleware{
+		Stack: api.MultipartStack,
+		// As the webhook middleware, above the very low priority middlewares,
+		// so that they run within the asynchronous process.
+		Priority: api.LowPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					ctx := c.Get("context").(*api.Context)
+					if !ctx.Async() || ctx.ValidateOnly() || c.Request().Header.Get("Gotenberg-Webhook-Url") != "" {
+						// Not an asynchronous request, a pre-flight one, or
+						// the webhook middleware handles it.
+						return next(c)
+					}
+
+					if mod.resultStore == nil {
+						return api.WrapError(
+							errors.New("no result store"),
+							api.NewSentinelHttpError(http.StatusBadRequest, "Invalid request: asynchronous requests without webhook require the results").WithCode(api.ErrorCodeJobsAsyncNotAvailable),
+						)
+					}
+
+					token, tokenHash, err := newToken()
+					if err != nil {
+						return fmt.Errorf("create job token: %w", err)
+					}
+
+					cancel := c.Get("cancel").(context.CancelFunc)
+
+					job := gotenberg.Job{
+						ID:        uuid.NewString(),
+						TokenHash: tokenHash,
+						Path:      c.Request().URL.Path,
+						State:     gotenberg.JobStateQueued,
+						CreatedAt: time.Now().UTC(),
+					}
+
+					rootPath := c.Get("rootPath").(string)
+					ctx.SetAccepted(acceptedJob{
+						ID:        job.ID,
+						Token:     token,
+						StatusUrl: fmt.Sprintf("%sjobs/%s", rootPath, job.ID),
+						ResultUrl: fmt.Sprintf("%sjobs/%s/result", rootPath, job.ID),
+					})
+
+					track := func(update func(job *gotenberg.Job)) {
+						update(&job)
+						job.UpdatedAt = time.Now().UTC()
+						mod.TrackJob(job)
+					}
+
+					// Record the queued job, whose time limit starts once it gets
+					// its turn.
+					track(func(job *gotenberg.Job) {})
+					ctx.SuspendDeadline()
+					ctx.OnStart(func() {
+						track(func(job *gotenberg.Job) {
+							job.State = gotenberg.JobStateRunning
+						})
+					})
+
+					go func() {
+						defer cancel()
+
+						result, err := mod.convertAsync(ctx, func() error {
+							return next(c)
+						})
+
+						if err != nil {
+							ctx.Log().Error(err.Error())
+							ctx.ReportError(err)
+						}
+
+						track(func(job *gotenberg.Job) {
+							if err != nil {
+								job.State = gotenberg.JobStateFailed
+								job.Error = api.ParseErrorResponse(err).Message
+
+								return
+							}
+
+							job.State = gotenberg.JobStateDone
+							job.Result = &result
+						})
+					}()
+
+					return api.ErrAsyncProcess
+				}
+			}
+		}(),
+	}
+}
+
+// convertAsync waits for an asynchronous slot, converts, and keeps the
+// output file in the result store. The time limit of the request starts once
+// the job gets its slot, so that the queued jobs do not expire.
+func (mod *Jobs) convertAsync(ctx *api.Context, convert func() error) (gotenberg.StoredResult, error) {
+	select {
+	case mod.asyncSlots <- struct{}{}:
+	case <-mod.stop:
+		return gotenberg.StoredResult{}, errors.New("wait for an asynchronous slot: module stopped")
+	}
+
+	defer func() {
+		<-mod.asyncSlots
+	}()
+
+	ctx.RestartDeadline()
+
+	err := convert()
+	if err != nil {
+		return gotenberg.StoredResult{}, err
+	}
+
+	outputPath, err := ctx.BuildOutputFile()
+	if err != nil {
+		return gotenberg.StoredResult{}, fmt.Errorf("build output file: %w", err)
+	}
+
+	result, err := mod.resultStore.StoreResult(outputPath, ctx.OutputFilename(outputPath))
+	if err != nil {
+		return gotenberg.StoredResult{}, fmt.Errorf("store output file: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,268 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestJobs_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "disabled",
+			enable:            false,
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Jobs{enable: tc.enable}
+
+			middlewares, err := mod.Middlewares()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(middlewares) != tc.expectMiddlewares {
+				t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+			}
+		})
+	}
+}
+
+func TestAsyncMiddleware(t *testing.T) {
+	resultStore := func(err error) gotenberg.ResultStore {
+		return &gotenberg.ResultStoreMock{
+			StoreResultMock: func(outputPath, filename string) (gotenberg.StoredResult, error) {
+				if err != nil {
+					return gotenberg.StoredResult{}, err
+				}
+
+				return gotenberg.StoredResult{Url: "https://foo/results/" + filename, Filename: filename}, nil
+			},
+		}
+	}
+
+	convert := func(err error) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if err != nil {
+				return err
+			}
+
+			ctx := c.Get("context").(*api.Context)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			outputPath := ctx.GeneratePath("", ".pdf")
+
+			err := os.WriteFile(outputPath, []byte("foo"), 0o600)
+			if err != nil {
+				return err
+			}
+
+			return ctx.AddOutputPaths(outputPath)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario         string
+		async            bool
+		webhook          bool
+		queued           bool
+		resultStore      gotenberg.ResultStore
+		next             echo.HandlerFunc
+		expectAsync      bool
+		expectHttpStatus int
+		expectState      string
+		expectResult     bool
+	}{
+		{
+			scenario:    "not an asynchronous request",
+			async:       false,
+			resultStore: resultStore(nil),
+			next:        convert(nil),
+			expectAsync: false,
+		},
+		{
+			scenario:    "webhook request",
+			async:       true,
+			webhook:     true,
+			resultStore: resultStore(nil),
+			next:        convert(nil),
+			expectAsync: false,
+		},
+		{
+			scenario:         "no result store",
+			async:            true,
+			next:             convert(nil),
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:    "conversion failure",
+			async:       true,
+			resultStore: resultStore(nil),
+			next:        convert(errors.New("foo")),
+			expectAsync: true,
+			expectState: gotenberg.JobStateFailed,
+		},
+		{
+			scenario:    "result store failure",
+			async:       true,
+			resultStore: resultStore(gotenberg.ErrResultStoreDisabled),
+			next:        convert(nil),
+			expectAsync: true,
+			expectState: gotenberg.JobStateFailed,
+		},
+		{
+			scenario:     "success",
+			async:        true,
+			resultStore:  resultStore(nil),
+			next:         convert(nil),
+			expectAsync:  true,
+			expectState:  gotenberg.JobStateDone,
+			expectResult: true,
+		},
+		{
+			scenario:     "success despite a request deadline passed while queued",
+			async:        true,
+			queued:       true,
+			resultStore:  resultStore(nil),
+			next:         convert(nil),
+			expectAsync:  true,
+			expectState:  gotenberg.JobStateDone,
+			expectResult: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Jobs{
+				enable:      true,
+				maxJobs:     10,
+				store:       newMemoryStore(),
+				asyncSlots:  make(chan struct{}, 1),
+				resultStore: tc.resultStore,
+				logger:      zap.NewNop(),
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/forms/chromium/convert/url", nil)
+			if tc.webhook {
+				req.Header.Set("Gotenberg-Webhook-Url", "https://foo")
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetTimeout(time.Hour)
+
+			deadline := time.Now().Add(time.Hour)
+			if tc.queued {
+				// Another job has the slot until the deadline of the request
+				// has passed.
+				deadline = time.Now().Add(time.Millisecond)
+				mod.asyncSlots <- struct{}{}
+
+				go func() {
+					time.Sleep(time.Duration(20) * time.Millisecond)
+					<-mod.asyncSlots
+				}()
+			}
+
+			cancelCtx := ctx.SetDeadline(deadline)
+			defer cancelCtx()
+
+			ctx.SetDirPath(t.TempDir())
+			ctx.SetLogger(zap.NewNop())
+			ctx.SetEchoContext(c)
+			ctx.SetAsync(tc.async)
+
+			done := make(chan struct{})
+
+			c.Set("context", ctx.Context)
+			c.Set("cancel", context.CancelFunc(func() {
+				close(done)
+			}))
+			c.Set("trace", "foo")
+			c.Set("rootPath", "/")
+
+			err := asyncMiddleware(mod).Handler(tc.next)(c)
+
+			if tc.expectHttpStatus != 0 {
+				response := api.ParseErrorResponse(err)
+				if response.Status != tc.expectHttpStatus {
+					t.Fatalf("expected status %d but got %d", tc.expectHttpStatus, response.Status)
+				}
+
+				return
+			}
+
+			if !tc.expectAsync {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return
+			}
+
+			if !errors.Is(err, api.ErrAsyncProcess) {
+				t.Fatalf("expected error %v but got: %v", api.ErrAsyncProcess, err)
+			}
+
+			accepted, ok := ctx.Accepted().(acceptedJob)
+			if !ok {
+				t.Fatalf("expected an accepted job but got %+v", ctx.Accepted())
+			}
+
+			// The ID of the job is not the trace of the request.
+			if accepted.ID == "" || accepted.ID == "foo" || accepted.Token == "" {
+				t.Errorf("expected a random ID and a token but got %+v", accepted)
+			}
+
+			expectAccepted := acceptedJob{
+				ID:        accepted.ID,
+				Token:     accepted.Token,
+				StatusUrl: "/jobs/" + accepted.ID,
+				ResultUrl: "/jobs/" + accepted.ID + "/result",
+			}
+			if accepted != expectAccepted {
+				t.Errorf("expected accepted response %+v but got %+v", expectAccepted, accepted)
+			}
+
+			<-done
+
+			job, err := mod.job(accepted.ID)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if job.TokenHash != hashToken(accepted.Token) {
+				t.Errorf("expected the hash of the token '%s' but got '%s'", hashToken(accepted.Token), job.TokenHash)
+			}
+
+			if job.State != tc.expectState {
+				t.Errorf("expected state '%s' but got '%s'", tc.expectState, job.State)
+			}
+
+			if tc.expectResult != (job.Result != nil) {
+				t.Errorf("expected a result: %t, but got %+v", tc.expectResult, job.Result)
+			}
+		})
+	}
+}
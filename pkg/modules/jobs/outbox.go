@@ -0,0 +1,235 @@
+package jobs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+const (
+	// deliveryFilename is the file of a delivery within its directory.
+	deliveryFilename = "delivery.json"
+
+	// payloadFilename is the file of the payload within the directory of a
+	// delivery.
+	payloadFilename = "payload"
+)
+
+// PutDelivery persists a delivery and a copy of its payload in their own
+// directory. The directory appears atomically, so that a crash never leaves
+// a partial delivery behind.
+func (mod *Jobs) PutDelivery(delivery gotenberg.Delivery, payloadPath string) error {
+	if !mod.outboxEnabled() {
+		return gotenberg.ErrOutboxDisabled
+	}
+
+	_, err := uuid.Parse(delivery.ID)
+	if err != nil {
+		return fmt.Errorf("invalid delivery ID '%s': %w", delivery.ID, err)
+	}
+
+	err = os.MkdirAll(mod.outboxDir, 0o700)
+	if err != nil {
+		return fmt.Errorf("create outbox directory: %w", err)
+	}
+
+	// Dotfiles are not deliveries yet.
+	tmpPath, err := os.MkdirTemp(mod.outboxDir, ".delivery-")
+	if err != nil {
+		return fmt.Errorf("create temporary delivery directory: %w", err)
+	}
+
+	defer func() {
+		_ = os.RemoveAll(tmpPath)
+	}()
+
+	err = copyFile(payloadPath, filepath.Join(tmpPath, payloadFilename))
+	if err != nil {
+		return fmt.Errorf("copy payload: %w", err)
+	}
+
+	b, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("marshal delivery: %w", err)
+	}
+
+	err = writeFile(filepath.Join(tmpPath, deliveryFilename), bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("write delivery: %w", err)
+	}
+
+	err = os.Rename(tmpPath, filepath.Join(mod.outboxDir, delivery.ID))
+	if err != nil {
+		return fmt.Errorf("rename delivery directory: %w", err)
+	}
+
+	return nil
+}
+
+// PendingDeliveries returns the persisted deliveries, the oldest first.
+func (mod *Jobs) PendingDeliveries() ([]gotenberg.Delivery, error) {
+	if !mod.outboxEnabled() {
+		return nil, gotenberg.ErrOutboxDisabled
+	}
+
+	entries, err := os.ReadDir(mod.outboxDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read outbox directory: %w", err)
+	}
+
+	var (
+		deliveries []gotenberg.Delivery
+		readErr    error
+	)
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		dirPath := filepath.Join(mod.outboxDir, entry.Name())
+
+		b, err := os.ReadFile(filepath.Join(dirPath, deliveryFilename))
+		if err != nil {
+			readErr = multierr.Append(readErr, fmt.Errorf("read delivery '%s': %w", entry.Name(), err))
+			continue
+		}
+
+		var delivery gotenberg.Delivery
+
+		err = json.Unmarshal(b, &delivery)
+		if err != nil {
+			readErr = multierr.Append(readErr, fmt.Errorf("unmarshal delivery '%s': %w", entry.Name(), err))
+			continue
+		}
+
+		delivery.PayloadPath = filepath.Join(dirPath, payloadFilename)
+		deliveries = append(deliveries, delivery)
+	}
+
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+
+	return deliveries, readErr
+}
+
+// AckDelivery removes a delivery and its payload.
+func (mod *Jobs) AckDelivery(id string) error {
+	if !mod.outboxEnabled() {
+		return gotenberg.ErrOutboxDisabled
+	}
+
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid delivery ID '%s': %w", id, err)
+	}
+
+	err = os.RemoveAll(filepath.Join(mod.outboxDir, id))
+	if err != nil {
+		return fmt.Errorf("remove delivery: %w", err)
+	}
+
+	return nil
+}
+
+// cleanupDeliveries removes the deliveries older than the TTL, as their jobs
+// are forgotten too.
+func (mod *Jobs) cleanupDeliveries(now time.Time) error {
+	entries, err := os.ReadDir(mod.outboxDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("read outbox directory: %w", err)
+	}
+
+	var cleanupErr error
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			cleanupErr = multierr.Append(cleanupErr, err)
+			continue
+		}
+
+		if now.Sub(info.ModTime()) < mod.ttl {
+			continue
+		}
+
+		err = os.RemoveAll(filepath.Join(mod.outboxDir, entry.Name()))
+		if err != nil {
+			cleanupErr = multierr.Append(cleanupErr, err)
+			continue
+		}
+
+		if !strings.HasPrefix(entry.Name(), ".") {
+			mod.logger.Warn(fmt.Sprintf("delivery '%s' expired before its acknowledgment", entry.Name()))
+		}
+	}
+
+	return cleanupErr
+}
+
+// outboxEnabled tells if the module persists the webhook deliveries.
+func (mod *Jobs) outboxEnabled() bool {
+	return mod.enable && mod.outboxDir != ""
+}
+
+// copyFile copies a file.
+func copyFile(srcPath, destPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("open source file: %w", err)
+	}
+
+	defer func() {
+		_ = src.Close()
+	}()
+
+	return writeFile(destPath, src)
+}
+
+// writeFile writes a new file and flushes it to the disk, so that it
+// survives a crash once the delivery exists.
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+
+	_, err = io.Copy(f, r)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("close file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,161 @@
+package jobs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestJobs_outbox(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		mod := &Jobs{enable: true}
+
+		err := mod.PutDelivery(gotenberg.Delivery{ID: uuid.NewString()}, "/foo")
+		if !errors.Is(err, gotenberg.ErrOutboxDisabled) {
+			t.Errorf("expected error %v but got: %v", gotenberg.ErrOutboxDisabled, err)
+		}
+
+		_, err = mod.PendingDeliveries()
+		if !errors.Is(err, gotenberg.ErrOutboxDisabled) {
+			t.Errorf("expected error %v but got: %v", gotenberg.ErrOutboxDisabled, err)
+		}
+
+		err = mod.AckDelivery(uuid.NewString())
+		if !errors.Is(err, gotenberg.ErrOutboxDisabled) {
+			t.Errorf("expected error %v but got: %v", gotenberg.ErrOutboxDisabled, err)
+		}
+	})
+
+	t.Run("no deliveries yet", func(t *testing.T) {
+		mod := &Jobs{enable: true, outboxDir: filepath.Join(t.TempDir(), "outbox")}
+
+		deliveries, err := mod.PendingDeliveries()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(deliveries) != 0 {
+			t.Errorf("expected no delivery but got %d", len(deliveries))
+		}
+	})
+
+	t.Run("put, list and acknowledge", func(t *testing.T) {
+		dirPath := t.TempDir()
+		mod := &Jobs{enable: true, outboxDir: filepath.Join(dirPath, "outbox")}
+
+		payloadPath := filepath.Join(dirPath, "foo.pdf")
+
+		err := os.WriteFile(payloadPath, []byte("foo"), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = mod.PutDelivery(gotenberg.Delivery{ID: "foo"}, payloadPath)
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+
+		now := time.Now().UTC()
+		older := gotenberg.Delivery{ID: uuid.NewString(), Url: "http://localhost/", Headers: map[string]string{"Foo": "bar"}, CreatedAt: now.Add(-time.Minute)}
+		newer := gotenberg.Delivery{ID: uuid.NewString(), Url: "http://localhost/", CreatedAt: now}
+
+		for _, delivery := range []gotenberg.Delivery{newer, older} {
+			err = mod.PutDelivery(delivery, payloadPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
+		deliveries, err := mod.PendingDeliveries()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(deliveries) != 2 || deliveries[0].ID != older.ID || deliveries[1].ID != newer.ID {
+			t.Fatalf("expected deliveries [%s %s] but got %+v", older.ID, newer.ID, deliveries)
+		}
+
+		if deliveries[0].Headers["Foo"] != "bar" {
+			t.Errorf("expected header 'Foo' 'bar' but got '%s'", deliveries[0].Headers["Foo"])
+		}
+
+		b, err := os.ReadFile(deliveries[0].PayloadPath)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if string(b) != "foo" {
+			t.Errorf("expected payload 'foo' but got '%s'", string(b))
+		}
+
+		err = mod.AckDelivery(older.ID)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		deliveries, err = mod.PendingDeliveries()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(deliveries) != 1 || deliveries[0].ID != newer.ID {
+			t.Errorf("expected deliveries [%s] but got %+v", newer.ID, deliveries)
+		}
+
+		err = mod.AckDelivery("../foo")
+		if err == nil {
+			t.Error("expected error but got none")
+		}
+	})
+}
+
+func TestJobs_cleanupDeliveries(t *testing.T) {
+	dirPath := t.TempDir()
+	mod := &Jobs{enable: true, ttl: time.Hour, outboxDir: filepath.Join(dirPath, "outbox"), logger: zap.NewNop()}
+
+	err := mod.cleanupDeliveries(time.Now())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	payloadPath := filepath.Join(dirPath, "foo.pdf")
+
+	err = os.WriteFile(payloadPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	id := uuid.NewString()
+
+	err = mod.PutDelivery(gotenberg.Delivery{ID: id}, payloadPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = mod.cleanupDeliveries(time.Now())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(mod.outboxDir, id))
+	if err != nil {
+		t.Fatalf("expected the delivery to be kept but got: %v", err)
+	}
+
+	err = mod.cleanupDeliveries(time.Now().Add(2 * time.Hour))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(mod.outboxDir, id))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected the delivery to be removed but got: %v", err)
+	}
+}
@@ -0,0 +1,278 @@
+package jobs
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+const (
+	// defaultLimit is the number of jobs per page if the client does not
+	// set one.
+	defaultLimit = 50
+
+	// maxLimit is the maximum number of jobs per page.
+	maxLimit = 1000
+)
+
+// query gathers the filters and the pagination of a listing.
+type query struct {
+	state     string
+	path      string
+	delivered *bool
+	since     time.Time
+	until     time.Time
+	limit     int
+	after     *gotenberg.Job
+}
+
+// matches tells if a job matches the filters of the query.
+func (q query) matches(job gotenberg.Job) bool {
+	if q.state != "" && job.State != q.state {
+		return false
+	}
+
+	if q.path != "" && !strings.HasPrefix(job.Path, q.path) {
+		return false
+	}
+
+	if q.delivered != nil && job.Delivered != *q.delivered {
+		return false
+	}
+
+	if !q.since.IsZero() && job.CreatedAt.Before(q.since) {
+		return false
+	}
+
+	if !q.until.IsZero() && !job.CreatedAt.Before(q.until) {
+		return false
+	}
+
+	return true
+}
+
+// Routes returns the routes for listing, getting and deleting the jobs, and
+// for downloading their results. Listing and deleting the jobs require the
+// admin token, getting a job and its result either the admin token or the
+// token of the job.
+func (mod *Jobs) Routes() ([]api.Route, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/jobs",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				q, err := parseQuery(c)
+				if err != nil {
+					return api.WrapError(
+						fmt.Errorf("parse query: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid query: %s", err)).WithCode(api.ErrorCodeJobsInvalidQuery),
+					)
+				}
+
+				jobs, nextCursor, err := mod.list(q)
+				if err != nil {
+					return fmt.Errorf("list jobs: %w", err)
+				}
+
+				for i := range jobs {
+					jobs[i].TokenHash = ""
+				}
+
+				return c.JSON(http.StatusOK, struct {
+					Jobs       []gotenberg.Job `json:"jobs"`
+					NextCursor string          `json:"nextCursor,omitempty"`
+				}{
+					Jobs:       jobs,
+					NextCursor: nextCursor,
+				})
+			},
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/jobs/:id",
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				job, err := mod.authorizedJob(c)
+				if err != nil {
+					return err
+				}
+
+				return c.JSON(http.StatusOK, job)
+			},
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/jobs/:id/result",
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				job, err := mod.authorizedJob(c)
+				if err != nil {
+					return err
+				}
+
+				if job.State == gotenberg.JobStateQueued || job.State == gotenberg.JobStateRunning {
+					return api.WrapError(
+						fmt.Errorf("job '%s' is %s", job.ID, job.State),
+						api.NewSentinelHttpError(http.StatusConflict, fmt.Sprintf("Job '%s' is not done yet", job.ID)).WithCode(api.ErrorCodeJobsJobNotDone),
+					)
+				}
+
+				if job.Result == nil {
+					return api.WrapError(
+						fmt.Errorf("job '%s' has no result", job.ID),
+						api.NewSentinelHttpError(http.StatusNotFound, fmt.Sprintf("Job '%s' has no result", job.ID)).WithCode(api.ErrorCodeJobsResultNotFound),
+					)
+				}
+
+				// The result store serves the output file, without the
+				// credentials of this request.
+				return c.Redirect(http.StatusSeeOther, job.Result.Url)
+			},
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/jobs/:id",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				err := mod.delete(c.Param("id"))
+				if err != nil {
+					return jobError(c.Param("id"), err)
+				}
+
+				return c.NoContent(http.StatusNoContent)
+			},
+		},
+	}, nil
+}
+
+// authorizedJob returns the job of a request, if its client may get it.
+// Otherwise, the job is not found, so that the clients cannot probe the IDs.
+func (mod *Jobs) authorizedJob(c echo.Context) (gotenberg.Job, error) {
+	id := c.Param("id")
+
+	job, err := mod.job(id)
+	if err == nil && !authorized(c, job) {
+		err = errJobNotFound
+	}
+
+	if err != nil {
+		return gotenberg.Job{}, jobError(id, err)
+	}
+
+	job.TokenHash = ""
+
+	return job, nil
+}
+
+// jobError converts an error of the module into an HTTP error.
+func jobError(id string, err error) error {
+	if errors.Is(err, errJobNotFound) {
+		return api.WrapError(
+			fmt.Errorf("job '%s': %w", id, err),
+			api.NewSentinelHttpError(http.StatusNotFound, fmt.Sprintf("Job '%s' not found", id)).WithCode(api.ErrorCodeJobsJobNotFound),
+		)
+	}
+
+	return fmt.Errorf("job '%s': %w", id, err)
+}
+
+// parseQuery reads the filters and the pagination of a listing from the
+// query parameters.
+func parseQuery(c echo.Context) (query, error) {
+	q := query{
+		state: c.QueryParam("state"),
+		path:  c.QueryParam("path"),
+		limit: defaultLimit,
+	}
+
+	switch q.state {
+	case "", gotenberg.JobStateQueued, gotenberg.JobStateRunning, gotenberg.JobStateDone, gotenberg.JobStateFailed:
+	default:
+		return query{}, fmt.Errorf("state '%s' is not one of '%s', '%s', '%s' or '%s'", q.state, gotenberg.JobStateQueued, gotenberg.JobStateRunning, gotenberg.JobStateDone, gotenberg.JobStateFailed)
+	}
+
+	if val := c.QueryParam("delivered"); val != "" {
+		delivered, err := strconv.ParseBool(val)
+		if err != nil {
+			return query{}, fmt.Errorf("delivered '%s' is not a boolean", val)
+		}
+
+		q.delivered = &delivered
+	}
+
+	for param, t := range map[string]*time.Time{"since": &q.since, "until": &q.until} {
+		val := c.QueryParam(param)
+		if val == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, val)
+		if err != nil {
+			return query{}, fmt.Errorf("%s '%s' is not an RFC 3339 date", param, val)
+		}
+
+		*t = parsed
+	}
+
+	if val := c.QueryParam("limit"); val != "" {
+		limit, err := strconv.Atoi(val)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return query{}, fmt.Errorf("limit '%s' is not between 1 and %d", val, maxLimit)
+		}
+
+		q.limit = limit
+	}
+
+	if val := c.QueryParam("cursor"); val != "" {
+		after, err := decodeCursor(val)
+		if err != nil {
+			return query{}, fmt.Errorf("cursor '%s' is invalid", val)
+		}
+
+		q.after = &after
+	}
+
+	return q, nil
+}
+
+// encodeCursor returns the opaque cursor of the page following a job.
+func encodeCursor(job gotenberg.Job) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", job.CreatedAt.UnixNano(), job.ID)))
+}
+
+// decodeCursor returns the creation date and the ID of the job a cursor
+// points to.
+func decodeCursor(cursor string) (gotenberg.Job, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return gotenberg.Job{}, fmt.Errorf("decode cursor: %w", err)
+	}
+
+	nanos, id, ok := strings.Cut(string(b), ":")
+	if !ok {
+		return gotenberg.Job{}, errors.New("missing separator")
+	}
+
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return gotenberg.Job{}, fmt.Errorf("parse date: %w", err)
+	}
+
+	return gotenberg.Job{ID: id, CreatedAt: time.Unix(0, n)}, nil
+}
@@ -0,0 +1,323 @@
+package jobs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestJobs_Routes(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		routes, err := new(Jobs).Routes()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(routes) != 0 {
+			t.Errorf("expected no route but got %d", len(routes))
+		}
+	})
+
+	now := time.Now().UTC()
+	mod := &Jobs{enable: true, maxJobs: 10, store: newMemoryStore(), logger: zap.NewNop()}
+	mod.TrackJob(gotenberg.Job{ID: "foo", TokenHash: hashToken("foo-token"), State: gotenberg.JobStateDone, CreatedAt: now.Add(-time.Minute)})
+	mod.TrackJob(gotenberg.Job{ID: "bar", State: gotenberg.JobStateFailed, CreatedAt: now})
+
+	routes, err := mod.Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 4 {
+		t.Fatalf("expected 4 routes but got %d", len(routes))
+	}
+
+	for _, route := range routes {
+		expectAdmin := route.Path == "/jobs" || route.Method == http.MethodDelete
+		if route.IsAdmin != expectAdmin {
+			t.Errorf("expected '%s %s' admin %t but got %t", route.Method, route.Path, expectAdmin, route.IsAdmin)
+		}
+	}
+
+	// withToken gives the token of a job, asAdmin the API admin token.
+	withToken := func(token string) func(c echo.Context) {
+		return func(c echo.Context) {
+			c.Request().Header.Set(tokenHeader, token)
+		}
+	}
+
+	asAdmin := func(c echo.Context) {
+		c.Set("admin", true)
+	}
+
+	call := func(method, path, target, id string, authorize func(c echo.Context)) (int, string) {
+		for _, route := range routes {
+			if route.Method != method || route.Path != path {
+				continue
+			}
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(method, target, nil), rec)
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+
+			if authorize != nil {
+				authorize(c)
+			}
+
+			err := route.Handler(c)
+			if err != nil {
+				response := api.ParseErrorResponse(err)
+
+				return response.Status, response.Code
+			}
+
+			return rec.Code, rec.Body.String()
+		}
+
+		t.Fatalf("no route %s %s", method, path)
+
+		return 0, ""
+	}
+
+	t.Run("list", func(t *testing.T) {
+		status, body := call(http.MethodGet, "/jobs", "/jobs?state=done", "", asAdmin)
+		if status != http.StatusOK {
+			t.Fatalf("expected status %d but got %d", http.StatusOK, status)
+		}
+
+		var page struct {
+			Jobs       []gotenberg.Job `json:"jobs"`
+			NextCursor string          `json:"nextCursor"`
+		}
+
+		err := json.Unmarshal([]byte(body), &page)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(page.Jobs) != 1 || page.Jobs[0].ID != "foo" {
+			t.Fatalf("expected job 'foo' but got %+v", page.Jobs)
+		}
+
+		if page.Jobs[0].TokenHash != "" {
+			t.Errorf("expected no token hash but got '%s'", page.Jobs[0].TokenHash)
+		}
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		_, body := call(http.MethodGet, "/jobs", "/jobs?limit=1", "", asAdmin)
+
+		var page struct {
+			Jobs       []gotenberg.Job `json:"jobs"`
+			NextCursor string          `json:"nextCursor"`
+		}
+
+		err := json.Unmarshal([]byte(body), &page)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(page.Jobs) != 1 || page.Jobs[0].ID != "bar" || page.NextCursor == "" {
+			t.Fatalf("expected job 'bar' and a next cursor but got %+v", page)
+		}
+
+		_, body = call(http.MethodGet, "/jobs", "/jobs?limit=1&cursor="+page.NextCursor, "", asAdmin)
+
+		// The last page has no next cursor, which would not reset the one of
+		// the previous page.
+		page.NextCursor = ""
+
+		err = json.Unmarshal([]byte(body), &page)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(page.Jobs) != 1 || page.Jobs[0].ID != "foo" || page.NextCursor != "" {
+			t.Errorf("expected job 'foo' and no next cursor but got %+v", page)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, code := call(http.MethodGet, "/jobs", "/jobs?state=foo", "", asAdmin)
+		if code != "JOBS_INVALID_QUERY" {
+			t.Errorf("expected code 'JOBS_INVALID_QUERY' but got '%s'", code)
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		for _, tc := range []struct {
+			scenario     string
+			id           string
+			authorize    func(c echo.Context)
+			expectStatus int
+		}{
+			{
+				scenario:     "no token",
+				id:           "foo",
+				expectStatus: http.StatusNotFound,
+			},
+			{
+				scenario:     "wrong token",
+				id:           "foo",
+				authorize:    withToken("bar-token"),
+				expectStatus: http.StatusNotFound,
+			},
+			{
+				scenario:     "job without token",
+				id:           "bar",
+				authorize:    withToken("foo-token"),
+				expectStatus: http.StatusNotFound,
+			},
+			{
+				scenario:     "token of the job",
+				id:           "foo",
+				authorize:    withToken("foo-token"),
+				expectStatus: http.StatusOK,
+			},
+			{
+				scenario:     "admin token",
+				id:           "bar",
+				authorize:    asAdmin,
+				expectStatus: http.StatusOK,
+			},
+		} {
+			t.Run(tc.scenario, func(t *testing.T) {
+				status, body := call(http.MethodGet, "/jobs/:id", "/jobs/"+tc.id, tc.id, tc.authorize)
+				if status != tc.expectStatus {
+					t.Fatalf("expected status %d but got %d", tc.expectStatus, status)
+				}
+
+				if strings.Contains(body, "tokenHash") {
+					t.Errorf("expected no token hash but got %s", body)
+				}
+			})
+		}
+	})
+
+	t.Run("result", func(t *testing.T) {
+		mod.TrackJob(gotenberg.Job{ID: "baz", State: gotenberg.JobStateRunning, CreatedAt: now.Add(-time.Hour)})
+		mod.TrackJob(gotenberg.Job{ID: "qux", TokenHash: hashToken("qux-token"), State: gotenberg.JobStateDone, Result: &gotenberg.StoredResult{Url: "https://foo/results/qux"}, CreatedAt: now.Add(-time.Hour)})
+
+		status, _ := call(http.MethodGet, "/jobs/:id/result", "/jobs/qux/result", "qux", nil)
+		if status != http.StatusNotFound {
+			t.Errorf("expected status %d but got %d", http.StatusNotFound, status)
+		}
+
+		status, _ = call(http.MethodGet, "/jobs/:id/result", "/jobs/qux/result", "qux", withToken("qux-token"))
+		if status != http.StatusSeeOther {
+			t.Errorf("expected status %d but got %d", http.StatusSeeOther, status)
+		}
+
+		status, code := call(http.MethodGet, "/jobs/:id/result", "/jobs/baz/result", "baz", asAdmin)
+		if status != http.StatusConflict || code != "JOBS_JOB_NOT_DONE" {
+			t.Errorf("expected status %d and code 'JOBS_JOB_NOT_DONE' but got %d and '%s'", http.StatusConflict, status, code)
+		}
+
+		status, code = call(http.MethodGet, "/jobs/:id/result", "/jobs/bar/result", "bar", asAdmin)
+		if status != http.StatusNotFound || code != "JOBS_RESULT_NOT_FOUND" {
+			t.Errorf("expected status %d and code 'JOBS_RESULT_NOT_FOUND' but got %d and '%s'", http.StatusNotFound, status, code)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		status, _ := call(http.MethodDelete, "/jobs/:id", "/jobs/foo", "foo", asAdmin)
+		if status != http.StatusNoContent {
+			t.Errorf("expected status %d but got %d", http.StatusNoContent, status)
+		}
+
+		status, code := call(http.MethodGet, "/jobs/:id", "/jobs/foo", "foo", asAdmin)
+		if status != http.StatusNotFound || code != "JOBS_JOB_NOT_FOUND" {
+			t.Errorf("expected status %d and code 'JOBS_JOB_NOT_FOUND' but got %d and '%s'", http.StatusNotFound, status, code)
+		}
+	})
+}
+
+func TestParseQuery(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		target      string
+		expectLimit int
+		expectError bool
+	}{
+		{
+			scenario:    "default",
+			target:      "/jobs",
+			expectLimit: defaultLimit,
+		},
+		{
+			scenario:    "all filters",
+			target:      "/jobs?state=done&path=/forms/chromium&delivered=false&since=2026-01-01T00:00:00Z&until=2026-01-02T00:00:00Z&limit=10",
+			expectLimit: 10,
+		},
+		{
+			scenario:    "invalid state",
+			target:      "/jobs?state=foo",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid delivered",
+			target:      "/jobs?delivered=foo",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid since",
+			target:      "/jobs?since=yesterday",
+			expectError: true,
+		},
+		{
+			scenario:    "limit too high",
+			target:      "/jobs?limit=1001",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid cursor",
+			target:      "/jobs?cursor=foo",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, tc.target, nil), httptest.NewRecorder())
+
+			q, err := parseQuery(c)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if q.limit != tc.expectLimit {
+				t.Errorf("expected limit %d but got %d", tc.expectLimit, q.limit)
+			}
+		})
+	}
+}
+
+func TestCursor(t *testing.T) {
+	job := gotenberg.Job{ID: "foo:bar", CreatedAt: time.Unix(0, 1700000000123456789)}
+
+	actual, err := decodeCursor(encodeCursor(job))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if actual.ID != job.ID || !actual.CreatedAt.Equal(job.CreatedAt) {
+		t.Errorf("expected %+v but got %+v", job, actual)
+	}
+}
@@ -0,0 +1,209 @@
+package jobs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// store keeps the jobs. The [Jobs] module guards the accesses.
+type store interface {
+	get(id string) (gotenberg.Job, bool, error)
+	put(job gotenberg.Job) error
+	remove(id string) error
+	all() ([]gotenberg.Job, error)
+	count() (int, error)
+}
+
+// memoryStore keeps the jobs in memory.
+type memoryStore struct {
+	jobs map[string]gotenberg.Job
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{jobs: make(map[string]gotenberg.Job)}
+}
+
+func (s *memoryStore) get(id string) (gotenberg.Job, bool, error) {
+	job, ok := s.jobs[id]
+
+	return job, ok, nil
+}
+
+func (s *memoryStore) put(job gotenberg.Job) error {
+	s.jobs[job.ID] = job
+
+	return nil
+}
+
+func (s *memoryStore) remove(id string) error {
+	delete(s.jobs, id)
+
+	return nil
+}
+
+func (s *memoryStore) all() ([]gotenberg.Job, error) {
+	jobs := make([]gotenberg.Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (s *memoryStore) count() (int, error) {
+	return len(s.jobs), nil
+}
+
+// diskStore keeps the jobs as JSON files in a directory, which the replicas
+// of a distributed deployment may share.
+type diskStore struct {
+	dir string
+}
+
+func (s diskStore) get(id string) (gotenberg.Job, bool, error) {
+	b, err := os.ReadFile(s.path(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return gotenberg.Job{}, false, nil
+		}
+
+		return gotenberg.Job{}, false, fmt.Errorf("read job: %w", err)
+	}
+
+	var job gotenberg.Job
+
+	err = json.Unmarshal(b, &job)
+	if err != nil {
+		return gotenberg.Job{}, false, fmt.Errorf("unmarshal job: %w", err)
+	}
+
+	return job, true, nil
+}
+
+func (s diskStore) put(job gotenberg.Job) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("marshal job: %w", err)
+	}
+
+	// Dotfiles are not jobs yet.
+	f, err := os.CreateTemp(s.dir, ".job-")
+	if err != nil {
+		return fmt.Errorf("create temporary job file: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+
+	_, err = f.Write(b)
+	if err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("write job: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("close job file: %w", err)
+	}
+
+	// The other replicas never read a partial job.
+	err = os.Rename(f.Name(), s.path(job.ID))
+	if err != nil {
+		return fmt.Errorf("rename job file: %w", err)
+	}
+
+	return nil
+}
+
+func (s diskStore) remove(id string) error {
+	err := os.Remove(s.path(id))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove job: %w", err)
+	}
+
+	return nil
+}
+
+func (s diskStore) all() ([]gotenberg.Job, error) {
+	names, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		jobs   []gotenberg.Job
+		allErr error
+	)
+
+	for _, name := range names {
+		b, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			// Another replica may have removed the job meanwhile.
+			if !errors.Is(err, os.ErrNotExist) {
+				allErr = multierr.Append(allErr, fmt.Errorf("read job: %w", err))
+			}
+
+			continue
+		}
+
+		var job gotenberg.Job
+
+		err = json.Unmarshal(b, &job)
+		if err != nil {
+			allErr = multierr.Append(allErr, fmt.Errorf("unmarshal job '%s': %w", name, err))
+			continue
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, allErr
+}
+
+func (s diskStore) count() (int, error) {
+	names, err := s.names()
+
+	return len(names), err
+}
+
+// names returns the filenames of the jobs.
+func (s diskStore) names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read jobs directory: %w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		names = append(names, entry.Name())
+	}
+
+	return names, nil
+}
+
+// path returns the path of the file of a job. The IDs come from the trace
+// headers of the clients, so they are encoded.
+func (s diskStore) path(id string) string {
+	return filepath.Join(s.dir, base64.RawURLEncoding.EncodeToString([]byte(id))+".json")
+}
+
+// Interface guards.
+var (
+	_ store = (*memoryStore)(nil)
+	_ store = (*diskStore)(nil)
+)
@@ -0,0 +1,129 @@
+package jobs
+
+import (
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestStore(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		store    func(t *testing.T) store
+	}{
+		{
+			scenario: "memory",
+			store: func(t *testing.T) store {
+				return newMemoryStore()
+			},
+		},
+		{
+			scenario: "disk",
+			store: func(t *testing.T) store {
+				return diskStore{dir: t.TempDir()}
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			s := tc.store(t)
+			now := time.Now().UTC().Truncate(time.Second)
+
+			_, ok, err := s.get("foo")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if ok {
+				t.Fatal("expected no job 'foo'")
+			}
+
+			// The IDs come from the clients.
+			for _, job := range []gotenberg.Job{
+				{ID: "foo", State: gotenberg.JobStateQueued, CreatedAt: now},
+				{ID: "../bar", State: gotenberg.JobStateDone, CreatedAt: now},
+				{ID: "foo", State: gotenberg.JobStateRunning, CreatedAt: now},
+			} {
+				err = s.put(job)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			job, ok, err := s.get("foo")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !ok || job.State != gotenberg.JobStateRunning || !job.CreatedAt.Equal(now) {
+				t.Errorf("expected job 'foo' to be running but got %+v", job)
+			}
+
+			count, err := s.count()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if count != 2 {
+				t.Errorf("expected 2 jobs but got %d", count)
+			}
+
+			err = s.remove("foo")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = s.remove("foo")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			jobs, err := s.all()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			ids := make([]string, 0, len(jobs))
+			for _, job := range jobs {
+				ids = append(ids, job.ID)
+			}
+
+			sort.Strings(ids)
+			if len(ids) != 1 || ids[0] != "../bar" {
+				t.Errorf("expected jobs [../bar] but got %v", ids)
+			}
+		})
+	}
+}
+
+func TestDiskStore_shared(t *testing.T) {
+	dirPath := t.TempDir()
+
+	// Two replicas sharing a directory.
+	a, b := diskStore{dir: dirPath}, diskStore{dir: dirPath}
+
+	err := a.put(gotenberg.Job{ID: "foo", State: gotenberg.JobStateDone})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	job, ok, err := b.get("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !ok || job.State != gotenberg.JobStateDone {
+		t.Errorf("expected the other replica to see job 'foo' but got %+v", job)
+	}
+
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary file left but got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// tokenHeader is the header with which a client gives the token of its job,
+// as returned in the 202 Accepted response.
+const tokenHeader = "Gotenberg-Job-Token"
+
+// newToken returns a random token and its hash, which the job keeps instead
+// of the token.
+func newToken() (string, string, error) {
+	b := make([]byte, 32)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", "", fmt.Errorf("read random bytes: %w", err)
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, hashToken(token), nil
+}
+
+// hashToken returns the SHA-256 hash of a token.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// authorized tells if the client of a request may get a job: either it has
+// the API admin token, or it gives the token of the job. The jobs of the
+// webhook requests have no token.
+func authorized(c echo.Context, job gotenberg.Job) bool {
+	if api.IsAdmin(c) {
+		return true
+	}
+
+	token := c.Request().Header.Get(tokenHeader)
+	if token == "" || job.TokenHash == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(hashToken(token)), []byte(job.TokenHash)) == 1
+}
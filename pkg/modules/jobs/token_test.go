@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"testing"
+)
+
+func TestNewToken(t *testing.T) {
+	token, tokenHash, err := newToken()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(token) != 43 {
+		t.Errorf("expected a token of 43 characters but got '%s'", token)
+	}
+
+	if tokenHash != hashToken(token) {
+		t.Errorf("expected the hash '%s' but got '%s'", hashToken(token), tokenHash)
+	}
+
+	other, _, err := newToken()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if other == token {
+		t.Errorf("expected two different tokens but got '%s' twice", token)
+	}
+}
@@ -0,0 +1,148 @@
+package latex
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// ErrCompilationFailed happens if a TeX engine cannot compile a document.
+var ErrCompilationFailed = errors.New("LaTeX compilation failed")
+
+// maxLogErrors is the maximum number of error lines extracted from a log.
+const maxLogErrors = 10
+
+// compileOptions gathers the options of a compilation.
+type compileOptions struct {
+	// BinPath is the path to the binary of the TeX engine.
+	BinPath string
+
+	// Passes is the number of times the TeX engine compiles the document,
+	// e.g., for resolving the references and the table of contents.
+	Passes int
+}
+
+// compileError is an [ErrCompilationFailed] with the errors extracted from
+// the log of the TeX engine.
+type compileError struct {
+	lines []string
+}
+
+// Error implements the error interface.
+func (err compileError) Error() string {
+	if len(err.lines) == 0 {
+		return ErrCompilationFailed.Error()
+	}
+
+	return fmt.Sprintf("%s: %s", ErrCompilationFailed, strings.Join(err.lines, "; "))
+}
+
+// Is makes [errors.Is] match [ErrCompilationFailed].
+func (err compileError) Is(target error) bool {
+	return target == ErrCompilationFailed
+}
+
+// compile compiles a TeX document to PDF. The TeX engine runs in the
+// directory of the document, so that the document may refer to its assets
+// with relative paths. The PDF and the log are written next to the
+// document, using the base name of the output path.
+func compile(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, opts compileOptions) error {
+	dirPath := filepath.Dir(inputPath)
+	jobName := strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
+
+	args := []string{
+		"-interaction=nonstopmode",
+		"-halt-on-error",
+		"-file-line-error",
+		"-no-shell-escape",
+		fmt.Sprintf("-output-directory=%s", filepath.Dir(outputPath)),
+		fmt.Sprintf("-jobname=%s", jobName),
+		filepath.Base(inputPath),
+	}
+
+	for pass := 1; pass <= opts.Passes; pass++ {
+		cmd, err := gotenberg.CommandContext(ctx, logger, opts.BinPath, args...)
+		if err != nil {
+			return fmt.Errorf("create command: %w", err)
+		}
+
+		cmd.SetDir(dirPath)
+		// The document may neither read nor write files outside its
+		// directory.
+		cmd.SetEnv("openin_any=p", "openout_any=p")
+
+		_, err = cmd.Exec()
+		if err == nil {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("compile pass %d: %w", pass, err)
+		}
+
+		logPath := filepath.Join(filepath.Dir(outputPath), jobName+".log")
+		logErrors, logErr := readLogErrors(logPath)
+		if logErr != nil {
+			logger.Debug(fmt.Sprintf("read LaTeX log: %s", logErr))
+		}
+
+		return fmt.Errorf("compile pass %d: %v: %w", pass, err, compileError{lines: logErrors})
+	}
+
+	_, err := os.Stat(outputPath)
+	if err != nil {
+		// E.g., an empty document.
+		return fmt.Errorf("stat PDF: %v: %w", err, compileError{lines: []string{"no PDF output"}})
+	}
+
+	return nil
+}
+
+// logErrorLine matches the errors in a log, either in the classic "! ..."
+// form or in the "file:line: ..." form.
+var logErrorLine = regexp.MustCompile(`^(!\s.+|\S+:\d+:\s.+)$`)
+
+// readLogErrors extracts the errors from the log of a TeX engine, with the
+// "l.<line>" context following them, if any.
+func readLogErrors(logPath string) ([]string, error) {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return nil, fmt.Errorf("open log: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var logErrors []string
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() && len(logErrors) < maxLogErrors {
+		line := strings.TrimSpace(scanner.Text())
+
+		if logErrorLine.MatchString(line) {
+			logErrors = append(logErrors, line)
+			continue
+		}
+
+		if strings.HasPrefix(line, "l.") && len(logErrors) > 0 {
+			logErrors[len(logErrors)-1] = fmt.Sprintf("%s (%s)", logErrors[len(logErrors)-1], line)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return logErrors, fmt.Errorf("scan log: %w", err)
+	}
+
+	return logErrors, nil
+}
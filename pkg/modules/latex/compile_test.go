@@ -0,0 +1,158 @@
+package latex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakeTexEngine writes a script which mimics a TeX engine. It writes the
+// given log next to the PDF, then either writes a PDF and succeeds, or
+// fails. It also counts its runs in a "passes" file of the working
+// directory.
+func fakeTexEngine(t *testing.T, log string, fail bool) string {
+	exit := "printf '%%PDF-1.4' > \"$out/$job.pdf\"\nexit 0"
+	if fail {
+		exit = "exit 1"
+	}
+
+	script := fmt.Sprintf(`out=""
+job=""
+for arg in "$@"; do
+  case "$arg" in
+    -output-directory=*) out="${arg#-output-directory=}" ;;
+    -jobname=*) job="${arg#-jobname=}" ;;
+  esac
+done
+echo pass >> passes
+printf '%%s\n' '%s' > "$out/$job.log"
+%s
+`, log, exit)
+
+	return gotenbergtest.FakeBinary(t, "pdflatex", script)
+}
+
+func TestCompile(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		binPath      func(t *testing.T) string
+		passes       int
+		expectPasses int
+		expectError  bool
+		expectLines  []string
+	}{
+		{
+			scenario: "success",
+			binPath: func(t *testing.T) string {
+				return fakeTexEngine(t, "This is pdfTeX", false)
+			},
+			passes:       3,
+			expectPasses: 3,
+		},
+		{
+			scenario: "compilation error",
+			binPath: func(t *testing.T) string {
+				return fakeTexEngine(t, "./main.tex:3: Undefined control sequence.\nl.3 \\foo", true)
+			},
+			passes:       2,
+			expectPasses: 1,
+			expectError:  true,
+			expectLines:  []string{"./main.tex:3: Undefined control sequence. (l.3 \\foo)"},
+		},
+		{
+			scenario: "no PDF output",
+			binPath: func(t *testing.T) string {
+				return gotenbergtest.FakeBinary(t, "pdflatex", "echo pass >> passes\n")
+			},
+			passes:       1,
+			expectPasses: 1,
+			expectError:  true,
+			expectLines:  []string{"no PDF output"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, "main.tex")
+			outputPath := filepath.Join(dirPath, "output.pdf")
+
+			err := os.WriteFile(inputPath, []byte("\\documentclass{article}"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = compile(context.Background(), zap.NewNop(), inputPath, outputPath, compileOptions{
+				BinPath: tc.binPath(t),
+				Passes:  tc.passes,
+			})
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				var compileErr compileError
+				if !errors.As(err, &compileErr) || !errors.Is(err, ErrCompilationFailed) {
+					t.Fatalf("expected a compilation error but got: %v", err)
+				}
+
+				if !reflect.DeepEqual(tc.expectLines, compileErr.lines) {
+					t.Errorf("expected %q but got %q", tc.expectLines, compileErr.lines)
+				}
+			}
+
+			passes, err := os.ReadFile(filepath.Join(dirPath, "passes"))
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if strings.Count(string(passes), "pass") != tc.expectPasses {
+				t.Errorf("expected %d passes but got %d", tc.expectPasses, strings.Count(string(passes), "pass"))
+			}
+		})
+	}
+}
+
+func TestReadLogErrors(t *testing.T) {
+	log := `This is pdfTeX, Version 3.141592653
+! LaTeX Error: File ` + "`foo.sty'" + ` not found.
+Type X to quit or <RETURN> to proceed,
+l.2 \usepackage
+./main.tex:5: Undefined control sequence.
+l.5 \bar
+Output written on main.pdf.`
+
+	path := filepath.Join(t.TempDir(), "main.log")
+
+	err := os.WriteFile(path, []byte(log), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	lines, err := readLogErrors(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := []string{
+		"! LaTeX Error: File `foo.sty' not found. (l.2 \\usepackage)",
+		"./main.tex:5: Undefined control sequence. (l.5 \\bar)",
+	}
+
+	if !reflect.DeepEqual(expect, lines) {
+		t.Errorf("expected %q but got %q", expect, lines)
+	}
+
+	_, err = readLogErrors("/foo")
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+}
@@ -0,0 +1,10 @@
+// Package latex provides a module which adds a route for compiling LaTeX
+// documents to PDF, with their assets, e.g., images or bibliographies.
+//
+// The documents are compiled with pdfLaTeX, or with XeLaTeX or LuaLaTeX if
+// available. The paths to their binaries must be specified using the
+// PDFLATEX_BIN_PATH, XELATEX_BIN_PATH and LUALATEX_BIN_PATH environment
+// variables. Only the first one is mandatory.
+//
+// See: https://tug.org/texlive.
+package latex
@@ -0,0 +1,128 @@
+package latex
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Latex))
+}
+
+const (
+	enginePdfLatex = "pdflatex"
+	engineXeLatex  = "xelatex"
+	engineLuaLatex = "lualatex"
+)
+
+// Latex is a module which provides a route for compiling LaTeX documents to
+// PDF.
+type Latex struct {
+	engine        gotenberg.PdfEngine
+	binPaths      map[string]string
+	maxPasses     int
+	disableRoutes bool
+}
+
+// Descriptor returns a [Latex]'s module descriptor.
+func (mod *Latex) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "latex",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("latex", flag.ExitOnError)
+			fs.Int("latex-max-passes", 5, "Set the maximum number of compilation passes of a LaTeX document")
+			fs.Bool("latex-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Latex) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Latex) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.maxPasses = flags.MustInt("latex-max-passes")
+	mod.disableRoutes = flags.MustBool("latex-disable-routes")
+
+	pdfLatexBinPath, ok := os.LookupEnv("PDFLATEX_BIN_PATH")
+	if !ok {
+		return errors.New("PDFLATEX_BIN_PATH environment variable is not set")
+	}
+
+	mod.binPaths = map[string]string{
+		enginePdfLatex: pdfLatexBinPath,
+	}
+
+	// Optional, the route does not compile with XeLaTeX or LuaLaTeX
+	// otherwise.
+	xeLatexBinPath, ok := os.LookupEnv("XELATEX_BIN_PATH")
+	if ok {
+		mod.binPaths[engineXeLatex] = xeLatexBinPath
+	}
+
+	luaLatexBinPath, ok := os.LookupEnv("LUALATEX_BIN_PATH")
+	if ok {
+		mod.binPaths[engineLuaLatex] = luaLatexBinPath
+	}
+
+	provider, err := ctx.Module(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine provider: %w", err)
+	}
+
+	engine, err := provider.(gotenberg.PdfEngineProvider).PdfEngine()
+	if err != nil {
+		return fmt.Errorf("get PDF engine: %w", err)
+	}
+
+	mod.engine = engine
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Latex) Validate() error {
+	if mod.maxPasses < 1 {
+		return errors.New("maximum number of compilation passes must be at least 1")
+	}
+
+	for _, name := range []string{enginePdfLatex, engineXeLatex, engineLuaLatex} {
+		binPath, ok := mod.binPaths[name]
+		if !ok {
+			continue
+		}
+
+		_, err := os.Stat(binPath)
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%s binary path does not exist: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Latex) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		convertRoute(mod.engine, mod.binPaths, mod.maxPasses),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Latex)(nil)
+	_ gotenberg.Provisioner = (*Latex)(nil)
+	_ gotenberg.Validator   = (*Latex)(nil)
+	_ api.Router            = (*Latex)(nil)
+)
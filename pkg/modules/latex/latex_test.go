@@ -0,0 +1,212 @@
+package latex
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestLatex_Descriptor(t *testing.T) {
+	descriptor := new(Latex).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Latex))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestLatex_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		setEnv      bool
+		expectError bool
+	}{
+		{
+			scenario: "no PDFLATEX_BIN_PATH environment variable",
+			ctx: func() *gotenberg.Context {
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Latex).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{},
+				)
+			}(),
+			setEnv:      false,
+			expectError: true,
+		},
+		{
+			scenario: "no PDF engine provider",
+			ctx: func() *gotenberg.Context {
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Latex).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{},
+				)
+			}(),
+			setEnv:      true,
+			expectError: true,
+		},
+		{
+			scenario: "no PDF engine from PDF engine provider",
+			ctx: func() *gotenberg.Context {
+				mod := &struct {
+					gotenberg.ModuleMock
+					gotenberg.PdfEngineProviderMock
+				}{}
+				mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "bar", New: func() gotenberg.Module { return mod }}
+				}
+				mod.PdfEngineMock = func() (gotenberg.PdfEngine, error) {
+					return nil, errors.New("foo")
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Latex).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{
+						mod.Descriptor(),
+					},
+				)
+			}(),
+			setEnv:      true,
+			expectError: true,
+		},
+		{
+			scenario: "provision success",
+			ctx: func() *gotenberg.Context {
+				mod := &struct {
+					gotenberg.ModuleMock
+					gotenberg.PdfEngineProviderMock
+				}{}
+				mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "bar", New: func() gotenberg.Module { return mod }}
+				}
+				mod.PdfEngineMock = func() (gotenberg.PdfEngine, error) {
+					return new(gotenberg.PdfEngineMock), nil
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(Latex).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{
+						mod.Descriptor(),
+					},
+				)
+			}(),
+			setEnv:      true,
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			// Make sure the environment variables are absent, even in the
+			// Docker image.
+			t.Setenv("PDFLATEX_BIN_PATH", "/usr/bin/pdflatex")
+			t.Setenv("XELATEX_BIN_PATH", "/usr/bin/xelatex")
+			if !tc.setEnv {
+				_ = os.Unsetenv("PDFLATEX_BIN_PATH")
+			}
+
+			mod := new(Latex)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestLatex_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		binPaths    map[string]string
+		maxPasses   int
+		expectError bool
+	}{
+		{
+			scenario:    "invalid maximum number of passes",
+			binPaths:    map[string]string{enginePdfLatex: os.Args[0]},
+			maxPasses:   0,
+			expectError: true,
+		},
+		{
+			scenario:    "non-existing pdflatex binary",
+			binPaths:    map[string]string{enginePdfLatex: "/foo"},
+			maxPasses:   5,
+			expectError: true,
+		},
+		{
+			scenario:    "non-existing lualatex binary",
+			binPaths:    map[string]string{enginePdfLatex: os.Args[0], engineLuaLatex: "/foo"},
+			maxPasses:   5,
+			expectError: true,
+		},
+		{
+			scenario:    "validate success",
+			binPaths:    map[string]string{enginePdfLatex: os.Args[0], engineXeLatex: os.Args[0]},
+			maxPasses:   5,
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Latex)
+			mod.binPaths = tc.binPaths
+			mod.maxPasses = tc.maxPasses
+			err := mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestLatex_Routes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		expectRoutes  int
+		disableRoutes bool
+	}{
+		{
+			scenario:      "routes not disabled",
+			expectRoutes:  1,
+			disableRoutes: false,
+		},
+		{
+			scenario:      "routes disabled",
+			expectRoutes:  0,
+			disableRoutes: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Latex)
+			mod.disableRoutes = tc.disableRoutes
+
+			routes, err := mod.Routes()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectRoutes != len(routes) {
+				t.Errorf("expected %d routes but got %d", tc.expectRoutes, len(routes))
+			}
+		})
+	}
+}
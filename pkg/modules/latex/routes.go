@@ -0,0 +1,178 @@
+package latex
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// defaultPasses is the default number of compilation passes, so that the
+// references and the table of contents are resolved.
+const defaultPasses = 2
+
+// convertRoute returns an [api.Route] which can compile a LaTeX document to
+// PDF. The other form files are the assets of the document, e.g., its images
+// or its bibliography.
+func convertRoute(engine gotenberg.PdfEngine, binPaths map[string]string, maxPasses int) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/latex/convert",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				texPaths  []string
+				mainFile  string
+				opts      compileOptions
+				outputLog bool
+				pdfa      string
+				pdfua     bool
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".tex"}, &texPaths).
+				String("mainFile", &mainFile, "").
+				Custom("engine", func(value string) error {
+					if value == "" {
+						value = enginePdfLatex
+					}
+
+					if value != enginePdfLatex && value != engineXeLatex && value != engineLuaLatex {
+						return fmt.Errorf("wrong value, expected either '%s', '%s' or '%s'", enginePdfLatex, engineXeLatex, engineLuaLatex)
+					}
+
+					binPath, ok := binPaths[value]
+					if !ok {
+						return fmt.Errorf("'%s' is not available", value)
+					}
+
+					opts.BinPath = binPath
+
+					return nil
+				}).
+				Custom("passes", func(value string) error {
+					if value == "" {
+						opts.Passes = min(defaultPasses, maxPasses)
+						return nil
+					}
+
+					passes, err := strconv.Atoi(value)
+					if err != nil {
+						return err
+					}
+
+					if passes < 1 || passes > maxPasses {
+						return fmt.Errorf("value is not between 1 and %d", maxPasses)
+					}
+
+					opts.Passes = passes
+
+					return nil
+				}).
+				Bool("outputLog", &outputLog, false).
+				String("pdfa", &pdfa, "").
+				Bool("pdfua", &pdfua, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			inputPath, err := mainPath(texPaths, mainFile)
+			if err != nil {
+				return api.WrapError(
+					fmt.Errorf("find main file: %w", err),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						fmt.Sprintf("Invalid form data: %s", err),
+					).WithCode(api.ErrorCodeLatexInvalidMainFile),
+				)
+			}
+
+			pdfFormats := gotenberg.PdfFormats{
+				PdfA:  pdfa,
+				PdfUa: pdfua,
+			}
+
+			// Alright, let's compile the document.
+
+			outputPath := ctx.GeneratePath("", ".pdf")
+
+			err = compile(ctx, ctx.Log(), inputPath, outputPath, opts)
+			if err != nil {
+				var compileErr compileError
+				if errors.As(err, &compileErr) {
+					return api.WrapError(
+						fmt.Errorf("compile LaTeX document: %w", err),
+						api.NewSentinelHttpError(
+							http.StatusBadRequest,
+							compileErr.Error(),
+						).WithCode(api.ErrorCodeLatexCompilationFailed),
+					)
+				}
+
+				return fmt.Errorf("compile LaTeX document: %w", err)
+			}
+
+			logPath := strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".log"
+
+			// So far so good, the document is compiled to PDF.
+			// Now, let's check if the client want to convert this result PDF
+			// to specific PDF formats.
+			zeroValued := gotenberg.PdfFormats{}
+			if pdfFormats != zeroValued {
+				convertInputPath := outputPath
+				convertOutputPath := ctx.GeneratePath("", ".pdf")
+
+				err = engine.Convert(ctx, ctx.Log(), pdfFormats, convertInputPath, convertOutputPath)
+				if err != nil {
+					return fmt.Errorf("convert PDF: %w", err)
+				}
+
+				// Important: the output path is now the converted file.
+				outputPath = convertOutputPath
+			}
+
+			outputPaths := []string{outputPath}
+			if outputLog {
+				outputPaths = append(outputPaths, logPath)
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// mainPath returns the path of the main TeX file: either the only one, or
+// the one with the given filename.
+func mainPath(texPaths []string, mainFile string) (string, error) {
+	if mainFile == "" {
+		if len(texPaths) > 1 {
+			return "", errors.New("form field 'mainFile' is required with several TeX files")
+		}
+
+		return texPaths[0], nil
+	}
+
+	for _, texPath := range texPaths {
+		if filepath.Base(texPath) == mainFile {
+			return texPath, nil
+		}
+	}
+
+	return "", fmt.Errorf("no TeX file named '%s'", mainFile)
+}
@@ -0,0 +1,220 @@
+package latex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestConvertRoute(t *testing.T) {
+	newContext := func(files map[string]string, values map[string][]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string)
+
+		for filename, data := range files {
+			path := filepath.Join(dirPath, filename)
+
+			err := os.WriteFile(path, []byte(data), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			paths[filename] = path
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetFiles(paths)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	document := map[string]string{
+		"main.tex": "\\documentclass{article}\\begin{document}Foo\\end{document}",
+		"logo.png": "foo",
+	}
+
+	binPaths := map[string]string{
+		enginePdfLatex: fakeTexEngine(t, "This is pdfTeX", false),
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		engine                 gotenberg.PdfEngine
+		binPaths               map[string]string
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newContext(map[string]string{"logo.png": "foo"}, nil),
+			binPaths:               binPaths,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid engine form field",
+			ctx:                    newContext(document, map[string][]string{"engine": {"foo"}}),
+			binPaths:               binPaths,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "unavailable engine",
+			ctx:                    newContext(document, map[string][]string{"engine": {"xelatex"}}),
+			binPaths:               binPaths,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid passes form field",
+			ctx:                    newContext(document, map[string][]string{"passes": {"foo"}}),
+			binPaths:               binPaths,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "too many passes",
+			ctx:                    newContext(document, map[string][]string{"passes": {"6"}}),
+			binPaths:               binPaths,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "several TeX files without main file",
+			ctx: newContext(map[string]string{
+				"main.tex":    "foo",
+				"chapter.tex": "bar",
+			}, nil),
+			binPaths:               binPaths,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "non-existing main file",
+			ctx:                    newContext(document, map[string][]string{"mainFile": {"foo.tex"}}),
+			binPaths:               binPaths,
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "compilation error",
+			ctx:      newContext(document, nil),
+			binPaths: map[string]string{
+				enginePdfLatex: fakeTexEngine(t, "! Undefined control sequence.", true),
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "PDF engine convert error",
+			ctx:      newContext(document, map[string][]string{"pdfa": {gotenberg.PdfA1b}}),
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return errors.New("foo")
+				},
+			},
+			binPaths:               binPaths,
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success with PDF formats",
+			ctx:      newContext(document, map[string][]string{"pdfa": {gotenberg.PdfA1b}}),
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			binPaths:               binPaths,
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "success with main file and log",
+			ctx: newContext(map[string]string{
+				"main.tex":    "foo",
+				"chapter.tex": "bar",
+			}, map[string][]string{
+				"mainFile":  {"main.tex"},
+				"engine":    {"pdflatex"},
+				"passes":    {"1"},
+				"outputLog": {"true"},
+			}),
+			binPaths:               binPaths,
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertRoute(tc.engine, tc.binPaths, 5).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/alexliesenfeld/health"
+	"github.com/labstack/gommon/bytes"
 	flag "github.com/spf13/pflag"
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
@@ -34,10 +37,11 @@ var (
 type Api struct {
 	autoStart bool
 	args      libreOfficeArguments
+	options   workerOptions
 
-	logger      *zap.Logger
-	libreOffice libreOffice
-	supervisor  gotenberg.ProcessSupervisor
+	logger            *zap.Logger
+	workers           []*worker
+	quarantineWorkers []*worker
 }
 
 // Options gathers available options when converting a document to PDF.
@@ -54,11 +58,37 @@ type Options struct {
 	// PDF/A-3b and PDF/UA.
 	// Optional.
 	PdfFormats gotenberg.PdfFormats
+
+	// ExportLinks exports the bookmarks of the document as PDF bookmarks and
+	// named destinations, so that the internal links and cross-references
+	// keep working.
+	// Optional.
+	ExportLinks bool
+
+	// InputFilter is the LibreOffice import filter. If not set, the filter
+	// of the extension of the document, if any, applies.
+	// Optional.
+	InputFilter string
+}
+
+// ConvertOptions gathers available options when converting a document to
+// another format than PDF.
+type ConvertOptions struct {
+	// Format is the format of the resulting document, e.g., "docx", "odt"
+	// or "txt".
+	// Required.
+	Format string
+
+	// InputFilter is the LibreOffice import filter, e.g.,
+	// "writer_pdf_import" for opening a PDF with Writer instead of Draw.
+	// Optional.
+	InputFilter string
 }
 
 // Uno is an abstraction on top of the Universal Network Objects API.
 type Uno interface {
 	Pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	Convert(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error
 	Extensions() []string
 }
 
@@ -79,10 +109,26 @@ func (a *Api) Descriptor() gotenberg.ModuleDescriptor {
 		ID: "libreoffice-api",
 		FlagSet: func() *flag.FlagSet {
 			fs := flag.NewFlagSet("api", flag.ExitOnError)
+			fs.Int("libreoffice-workers", 1, "Number of LibreOffice instances that handle conversions concurrently")
+			fs.Int("libreoffice-quarantine-workers", 0, "Number of LibreOffice instances that handle the conversions of quarantined requests, under the strictest sandbox and restarting after each conversion. Set to 0 to disable this feature")
 			fs.Int64("libreoffice-restart-after", 10, "Number of conversions after which LibreOffice will automatically restart. Set to 0 to disable this feature")
 			fs.Int64("libreoffice-max-queue-size", 0, "Maximum request queue size for LibreOffice. Set to 0 to disable this feature")
+			fs.Bool("libreoffice-rolling-restart", false, "Restart LibreOffice by starting a replacement instance, then draining and stopping the previous one, so that restarts do not delay conversions - note: an instance uses twice its memory while restarting")
+			fs.String("libreoffice-restart-memory-threshold", "0B", "Memory usage above which LibreOffice automatically restarts. Requires rolling restarts. Set to 0 to disable this feature")
 			fs.Bool("libreoffice-auto-start", false, "Automatically launch LibreOffice upon initialization if set to true; otherwise, LibreOffice will start at the time of the first conversion")
 			fs.Duration("libreoffice-start-timeout", time.Duration(20)*time.Second, "Maximum duration to wait for LibreOffice to start or restart")
+			fs.String("libreoffice-cgroup-parent", "", "Set the path of a delegated cgroup (v2) in which each LibreOffice process runs in its own cgroup - e.g., /sys/fs/cgroup/gotenberg")
+			fs.String("libreoffice-cgroup-memory-max", "0B", "Set the maximum memory of a LibreOffice process cgroup. Set to 0 to disable this limit")
+			fs.Float64("libreoffice-cgroup-cpu-max", 0, "Set the maximum number of CPUs of a LibreOffice process cgroup, e.g., 0.5. Set to 0 to disable this limit")
+			fs.Int64("libreoffice-cgroup-pids-max", 0, "Set the maximum number of processes of a LibreOffice process cgroup. Set to 0 to disable this limit")
+			fs.StringSlice("libreoffice-sandbox-namespaces", make([]string, 0), "Set the namespaces each LibreOffice process runs in, among user, ipc, pid and uts - the other namespaces require the user namespace when not running as root")
+			fs.Bool("libreoffice-sandbox-seccomp", false, "Run each LibreOffice process under a seccomp filter which denies the syscalls a document converter has no use for, e.g., mount or ptrace")
+			fs.StringSlice("libreoffice-sandbox-seccomp-deny", make([]string, 0), "Set the syscalls the seccomp filter of the LibreOffice processes denies on top of the default ones")
+			fs.Int("libreoffice-sandbox-uid", 0, "Set the dedicated user ID the LibreOffice processes run as, with private working directories - requires Gotenberg to run as root. Set to 0 to disable this feature")
+			fs.Int("libreoffice-sandbox-gid", 0, "Set the dedicated group ID the LibreOffice processes run as. Set to 0 to disable this feature")
+			fs.Bool("libreoffice-complex-text-layout", false, "Enable the complex text layout (CTL) of LibreOffice, e.g., for right-to-left scripts such as Arabic or Hebrew")
+			fs.Bool("libreoffice-uno-bridge", false, "Keep a persistent UNO bridge per LibreOffice instance for the conversions to PDF, instead of starting unoconverter for each of them, which speeds up the conversions of small documents")
+			fs.Int("libreoffice-uno-bridge-pipeline-depth", 1, "Number of conversions to PDF a LibreOffice instance accepts at once through its UNO bridge, so that it loads the next documents while exporting the current one - requires rolling restarts or no restart-after count when above 1")
 
 			return fs
 		}(),
@@ -105,10 +151,54 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 		return errors.New("UNOCONVERTER_BIN_PATH environment variable is not set")
 	}
 
+	unoBridge := flags.MustBool("libreoffice-uno-bridge")
+
+	var pythonBinPath string
+	if unoBridge {
+		pythonBinPath, ok = os.LookupEnv("PYTHON_BIN_PATH")
+		if !ok {
+			return errors.New("PYTHON_BIN_PATH environment variable is not set")
+		}
+	}
+
+	cgroupMemoryMax, err := bytes.Parse(flags.MustHumanReadableBytesString("libreoffice-cgroup-memory-max"))
+	if err != nil {
+		return fmt.Errorf("parse cgroup maximum memory: %w", err)
+	}
+
+	restartMemoryThreshold, err := bytes.Parse(flags.MustHumanReadableBytesString("libreoffice-restart-memory-threshold"))
+	if err != nil {
+		return fmt.Errorf("parse restart memory threshold: %w", err)
+	}
+
+	a.options = workerOptions{
+		restartAfter:           flags.MustInt64("libreoffice-restart-after"),
+		maxQueueSize:           flags.MustInt64("libreoffice-max-queue-size"),
+		rollingRestart:         flags.MustBool("libreoffice-rolling-restart"),
+		restartMemoryThreshold: restartMemoryThreshold,
+		pipelineDepth:          flags.MustInt("libreoffice-uno-bridge-pipeline-depth"),
+	}
+
 	a.args = libreOfficeArguments{
 		binPath:      libreOfficeBinPath,
 		unoBinPath:   unoBinPath,
 		startTimeout: flags.MustDuration("libreoffice-start-timeout"),
+		cgroupLimits: gotenberg.CgroupLimits{
+			ParentPath: flags.MustString("libreoffice-cgroup-parent"),
+			MemoryMax:  cgroupMemoryMax,
+			CpuMax:     flags.MustFloat64("libreoffice-cgroup-cpu-max"),
+			PidsMax:    flags.MustInt64("libreoffice-cgroup-pids-max"),
+		},
+		sandbox: gotenberg.SandboxOptions{
+			Namespaces:  flags.MustStringSlice("libreoffice-sandbox-namespaces"),
+			Seccomp:     flags.MustBool("libreoffice-sandbox-seccomp"),
+			SeccompDeny: flags.MustStringSlice("libreoffice-sandbox-seccomp-deny"),
+			Uid:         flags.MustInt("libreoffice-sandbox-uid"),
+			Gid:         flags.MustInt("libreoffice-sandbox-gid"),
+		},
+		complexTextLayout: flags.MustBool("libreoffice-complex-text-layout"),
+		unoBridge:         unoBridge,
+		pythonBinPath:     pythonBinPath,
 	}
 
 	// Logger.
@@ -122,9 +212,34 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 	}
 	a.logger = logger.Named("libreoffice")
 
-	// Process.
-	a.libreOffice = newLibreOfficeProcess(a.args)
-	a.supervisor = gotenberg.NewProcessSupervisor(a.logger, a.libreOffice, flags.MustInt64("libreoffice-restart-after"), flags.MustInt64("libreoffice-max-queue-size"))
+	// Processes.
+	numWorkers := flags.MustInt("libreoffice-workers")
+	a.workers = make([]*worker, numWorkers)
+	for i := range a.workers {
+		logger := a.logger
+		if numWorkers > 1 {
+			logger = logger.With(zap.Int("worker", i))
+		}
+
+		a.workers[i] = newWorker(logger, func() libreOffice { return newLibreOfficeProcess(a.args) }, a.options)
+	}
+
+	// Quarantine processes, each handling a single conversion.
+	quarantineArgs := a.args
+	quarantineArgs.sandbox = a.args.sandbox.Strict()
+	quarantineOptions := a.options
+	quarantineOptions.restartAfter = 1
+	quarantineOptions.pipelineDepth = 1
+
+	numQuarantineWorkers := flags.MustInt("libreoffice-quarantine-workers")
+	if numQuarantineWorkers > 0 {
+		a.quarantineWorkers = make([]*worker, numQuarantineWorkers)
+	}
+
+	for i := range a.quarantineWorkers {
+		logger := a.logger.With(zap.String("pool", "quarantine"), zap.Int("worker", i))
+		a.quarantineWorkers[i] = newWorker(logger, func() libreOffice { return newLibreOfficeProcess(quarantineArgs) }, quarantineOptions)
+	}
 
 	return nil
 }
@@ -133,6 +248,26 @@ func (a *Api) Provision(ctx *gotenberg.Context) error {
 func (a *Api) Validate() error {
 	var err error
 
+	if len(a.workers) < 1 {
+		err = multierr.Append(err, errors.New("number of LibreOffice workers must be at least 1"))
+	}
+
+	if a.options.restartMemoryThreshold > 0 && !a.options.rollingRestart {
+		err = multierr.Append(err, errors.New("restart memory threshold requires rolling restarts"))
+	}
+
+	if a.options.pipelineDepth < 1 {
+		err = multierr.Append(err, errors.New("UNO bridge pipeline depth must be at least 1"))
+	}
+
+	if a.options.pipelineDepth > 1 && !a.args.unoBridge {
+		err = multierr.Append(err, errors.New("UNO bridge pipeline depth requires the UNO bridge"))
+	}
+
+	if a.options.pipelineDepth > 1 && a.options.restartAfter > 0 && !a.options.rollingRestart {
+		err = multierr.Append(err, errors.New("UNO bridge pipeline depth requires rolling restarts or no restart-after count"))
+	}
+
 	_, statErr := os.Stat(a.args.binPath)
 	if os.IsNotExist(statErr) {
 		err = multierr.Append(err, fmt.Errorf("LibreOffice binary path does not exist: %w", statErr))
@@ -143,19 +278,47 @@ func (a *Api) Validate() error {
 		err = multierr.Append(err, fmt.Errorf("unoconverter binary path does not exist: %w", statErr))
 	}
 
+	if a.args.unoBridge {
+		_, statErr = os.Stat(a.args.pythonBinPath)
+		if os.IsNotExist(statErr) {
+			err = multierr.Append(err, fmt.Errorf("Python binary path does not exist: %w", statErr))
+		}
+	}
+
+	cgroupErr := a.args.cgroupLimits.Validate()
+	if cgroupErr != nil {
+		err = multierr.Append(err, cgroupErr)
+	}
+
+	sandboxErr := a.args.sandbox.Validate()
+	if sandboxErr != nil {
+		err = multierr.Append(err, fmt.Errorf("validate sandbox: %w", sandboxErr))
+	}
+
+	if len(a.quarantineWorkers) > 0 {
+		sandboxErr = a.args.sandbox.Strict().Validate()
+		if sandboxErr != nil {
+			err = multierr.Append(err, fmt.Errorf("validate quarantine sandbox: %w", sandboxErr))
+		}
+	}
+
 	return err
 }
 
-// Start does nothing if auto-start is not enabled. Otherwise, it starts a
-// LibreOffice instance.
+// Start does nothing if auto-start is not enabled. Otherwise, it starts the
+// LibreOffice instances.
 func (a *Api) Start() error {
 	if !a.autoStart {
 		return nil
 	}
 
-	err := a.supervisor.Launch()
-	if err != nil {
-		return fmt.Errorf("launch supervisor: %w", err)
+	for _, w := range a.allWorkers() {
+		_, supervisor := w.current()
+
+		err := supervisor.Launch()
+		if err != nil {
+			return fmt.Errorf("launch supervisor: %w", err)
+		}
 	}
 
 	return nil
@@ -178,7 +341,14 @@ func (a *Api) Stop(ctx context.Context) error {
 
 	<-ctx.Done()
 
-	err := a.supervisor.Shutdown()
+	var err error
+	for _, w := range a.allWorkers() {
+		shutdownErr := w.shutdown()
+		if shutdownErr != nil {
+			err = multierr.Append(err, shutdownErr)
+		}
+	}
+
 	if err == nil {
 		return nil
 	}
@@ -193,14 +363,25 @@ func (a *Api) Metrics() ([]gotenberg.Metric, error) {
 			Name:        "libreoffice_requests_queue_size",
 			Description: "Current number of LibreOffice conversion requests waiting to be treated.",
 			Read: func() float64 {
-				return float64(a.supervisor.ReqQueueSize())
+				var size int64
+				for _, w := range a.allWorkers() {
+					_, supervisor := w.current()
+					size += supervisor.ReqQueueSize()
+				}
+
+				return float64(size)
 			},
 		},
 		{
 			Name:        "libreoffice_restarts_count",
 			Description: "Current number of LibreOffice restarts.",
 			Read: func() float64 {
-				return float64(a.supervisor.RestartsCount())
+				var count int64
+				for _, w := range a.allWorkers() {
+					count += w.restartsCount()
+				}
+
+				return float64(count)
 			},
 		},
 	}, nil
@@ -212,16 +393,29 @@ func (a *Api) Checks() ([]health.CheckerOption, error) {
 		health.WithCheck(health.Check{
 			Name: "libreoffice",
 			Check: func(_ context.Context) error {
-				if a.supervisor.Healthy() {
-					return nil
+				for _, w := range a.allWorkers() {
+					_, supervisor := w.current()
+					if !supervisor.Healthy() {
+						return errors.New("LibreOffice is unhealthy")
+					}
 				}
 
-				return errors.New("LibreOffice is unhealthy")
+				return nil
 			},
 		}),
 	}, nil
 }
 
+// Debug returns the version of LibreOffice.
+func (a *Api) Debug(ctx context.Context) (map[string]any, error) {
+	version, err := gotenberg.BinaryVersion(ctx, a.logger, a.args.binPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"version": version}, nil
+}
+
 // Ready returns no error if the module is ready.
 func (a *Api) Ready() error {
 	if !a.autoStart {
@@ -239,7 +433,15 @@ func (a *Api) Ready() error {
 			ticker.Stop()
 			return fmt.Errorf("context done while waiting for LibreOffice to be ready: %w", ctx.Err())
 		case <-ticker.C:
-			ok := a.libreOffice.Healthy(a.logger)
+			ok := true
+			for _, w := range a.allWorkers() {
+				libreOffice, _ := w.current()
+				if !libreOffice.Healthy(a.logger) {
+					ok = false
+					break
+				}
+			}
+
 			if ok {
 				ticker.Stop()
 				return nil
@@ -255,10 +457,45 @@ func (a *Api) LibreOffice() (Uno, error) {
 	return a, nil
 }
 
-// Pdf converts a document to PDF.
+// allWorkers returns the workers and the quarantine workers.
+func (a *Api) allWorkers() []*worker {
+	return append(append([]*worker(nil), a.workers...), a.quarantineWorkers...)
+}
+
+// pool returns the workers which may handle a conversion, i.e., the
+// quarantine workers if the request is quarantined.
+func (a *Api) pool(ctx context.Context, logger *zap.Logger) []*worker {
+	if !api.Quarantined(ctx) {
+		return a.workers
+	}
+
+	if len(a.quarantineWorkers) == 0 {
+		logger.Warn("quarantined request, but no quarantine workers: fallback to the regular workers")
+
+		return a.workers
+	}
+
+	logger.Debug("quarantined request, use the quarantine workers")
+
+	return a.quarantineWorkers
+}
+
+// Pdf converts a document to PDF with the least busy LibreOffice instance.
 func (a *Api) Pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
-	return a.supervisor.Run(ctx, logger, func() error {
-		return a.libreOffice.pdf(ctx, logger, inputPath, outputPath, options)
+	if options.InputFilter == "" {
+		options.InputFilter = inputFilters[strings.ToLower(filepath.Ext(inputPath))]
+	}
+
+	return leastBusyWorker(a.pool(ctx, logger)).run(ctx, logger, func(libreOffice libreOffice) error {
+		return libreOffice.pdf(ctx, logger, inputPath, outputPath, options)
+	})
+}
+
+// Convert converts a document to another format with the least busy
+// LibreOffice instance.
+func (a *Api) Convert(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error {
+	return leastBusyWorker(a.pool(ctx, logger)).run(ctx, logger, func(libreOffice libreOffice) error {
+		return libreOffice.convert(ctx, logger, inputPath, outputPath, options)
 	})
 }
 
@@ -330,7 +567,6 @@ func (a *Api) Extensions() []string {
 		".sxd",
 		".sxi",
 		".sxw",
-		".sxw",
 		".tif",
 		".tiff",
 		".txt",
@@ -339,6 +575,8 @@ func (a *Api) Extensions() []string {
 		".uot",
 		".vor",
 		".wmf",
+		".works",
+		".wpd",
 		".wps",
 		".xhtml",
 		".xls",
@@ -350,6 +588,38 @@ func (a *Api) Extensions() []string {
 	}
 }
 
+// ExtraExtensions are the extensions of [Api.Extensions] which the upstream
+// LibreOffice module does not accept.
+var ExtraExtensions = []string{".works", ".wpd"}
+
+// inputFilters are the LibreOffice import filters of the extensions the type
+// detection may not recognize reliably, e.g., flat XML documents without an
+// XML declaration or legacy formats.
+// Note: the ".wps" extension is shared by Microsoft Works and WPS Office;
+// LibreOffice detects which one applies.
+var inputFilters = map[string]string{
+	".fodg":  "OpenDocument Drawing Flat XML",
+	".fodp":  "OpenDocument Presentation Flat XML",
+	".fods":  "OpenDocument Spreadsheet Flat XML",
+	".fodt":  "OpenDocument Text Flat XML",
+	".works": "MS_Works",
+	".wpd":   "WordPerfect",
+}
+
+// pdfVersions are the values of the "SelectPdfVersion" export option for the
+// PDF versions LibreOffice exports.
+var pdfVersions = map[string]int{
+	"1.5": 15,
+	"1.6": 16,
+	"1.7": 17,
+}
+
+// SupportsPdfVersion tells if LibreOffice exports PDFs of the given version.
+func SupportsPdfVersion(version string) bool {
+	_, ok := pdfVersions[version]
+	return ok
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module          = (*Api)(nil)
@@ -357,6 +627,7 @@ var (
 	_ gotenberg.Validator       = (*Api)(nil)
 	_ gotenberg.App             = (*Api)(nil)
 	_ gotenberg.MetricsProvider = (*Api)(nil)
+	_ gotenberg.Debuggable      = (*Api)(nil)
 	_ api.HealthChecker         = (*Api)(nil)
 	_ Uno                       = (*Api)(nil)
 	_ Provider                  = (*Api)(nil)
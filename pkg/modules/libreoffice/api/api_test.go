@@ -3,8 +3,10 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
 func TestApi_Descriptor(t *testing.T) {
@@ -110,47 +113,122 @@ func TestApi_Provision(t *testing.T) {
 
 func TestApi_Validate(t *testing.T) {
 	for _, tc := range []struct {
-		scenario    string
-		binPath     string
-		unoBinPath  string
-		expectError bool
+		scenario      string
+		numWorkers    int
+		options       workerOptions
+		binPath       string
+		unoBinPath    string
+		unoBridge     bool
+		pythonBinPath string
+		expectError   bool
 	}{
+		{
+			scenario:    "restart memory threshold without rolling restart",
+			numWorkers:  1,
+			options:     workerOptions{restartMemoryThreshold: 1024},
+			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice worker",
+			numWorkers:  0,
+			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
+			expectError: true,
+		},
 		{
 			scenario:    "empty LibreOffice bin path",
+			numWorkers:  1,
 			binPath:     "",
 			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
 			expectError: true,
 		},
 		{
 			scenario:    "LibreOffice bin path does not exist",
+			numWorkers:  1,
 			binPath:     "/foo",
 			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
 			expectError: true,
 		},
 		{
 			scenario:    "empty uno bin path",
+			numWorkers:  1,
 			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
 			unoBinPath:  "",
 			expectError: true,
 		},
 		{
 			scenario:    "uno bin path does not exist",
+			numWorkers:  1,
 			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
 			unoBinPath:  "/foo",
 			expectError: true,
 		},
+		{
+			scenario:    "invalid UNO bridge pipeline depth",
+			numWorkers:  1,
+			options:     workerOptions{pipelineDepth: 0},
+			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
+			expectError: true,
+		},
+		{
+			scenario:    "UNO bridge pipeline depth without UNO bridge",
+			numWorkers:  1,
+			options:     workerOptions{pipelineDepth: 2},
+			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
+			expectError: true,
+		},
+		{
+			scenario:      "UNO bridge pipeline depth with restart-after count",
+			numWorkers:    1,
+			options:       workerOptions{pipelineDepth: 2, restartAfter: 10},
+			binPath:       os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:    os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoBridge:     true,
+			pythonBinPath: os.Getenv("PYTHON_BIN_PATH"),
+			expectError:   true,
+		},
+		{
+			scenario:      "Python bin path does not exist",
+			numWorkers:    1,
+			options:       workerOptions{pipelineDepth: 1},
+			binPath:       os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:    os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoBridge:     true,
+			pythonBinPath: "/foo",
+			expectError:   true,
+		},
 		{
 			scenario:    "validate success",
+			numWorkers:  1,
+			options:     workerOptions{pipelineDepth: 1},
 			binPath:     os.Getenv("CHROMIUM_BIN_PATH"),
 			unoBinPath:  os.Getenv("UNOCONVERTER_BIN_PATH"),
 			expectError: false,
 		},
+		{
+			scenario:      "validate success with UNO bridge pipelining",
+			numWorkers:    1,
+			options:       workerOptions{pipelineDepth: 2, restartAfter: 10, rollingRestart: true},
+			binPath:       os.Getenv("CHROMIUM_BIN_PATH"),
+			unoBinPath:    os.Getenv("UNOCONVERTER_BIN_PATH"),
+			unoBridge:     true,
+			pythonBinPath: os.Getenv("PYTHON_BIN_PATH"),
+			expectError:   false,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
+			a.workers = make([]*worker, tc.numWorkers)
+			a.options = tc.options
 			a.args = libreOfficeArguments{
-				binPath:    tc.binPath,
-				unoBinPath: tc.unoBinPath,
+				binPath:       tc.binPath,
+				unoBinPath:    tc.unoBinPath,
+				unoBridge:     tc.unoBridge,
+				pythonBinPath: tc.pythonBinPath,
 			}
 			err := a.Validate()
 
@@ -197,7 +275,7 @@ func TestApi_Start(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
 			a.autoStart = tc.autoStart
-			a.supervisor = tc.supervisor
+			a.workers = []*worker{{supervisor: tc.supervisor}}
 
 			err := a.Start()
 
@@ -250,7 +328,7 @@ func TestApi_Stop(t *testing.T) {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
 			a.logger = zap.NewNop()
-			a.supervisor = tc.supervisor
+			a.workers = []*worker{{supervisor: tc.supervisor}}
 
 			ctx, cancel := context.WithTimeout(context.Background(), 0*time.Second)
 			cancel()
@@ -270,12 +348,26 @@ func TestApi_Stop(t *testing.T) {
 
 func TestApi_Metrics(t *testing.T) {
 	a := new(Api)
-	a.supervisor = &gotenberg.ProcessSupervisorMock{
-		ReqQueueSizeMock: func() int64 {
-			return 10
+	a.workers = []*worker{
+		{
+			supervisor: &gotenberg.ProcessSupervisorMock{
+				ReqQueueSizeMock: func() int64 {
+					return 10
+				},
+				RestartsCountMock: func() int64 {
+					return 0
+				},
+			},
 		},
-		RestartsCountMock: func() int64 {
-			return 0
+		{
+			supervisor: &gotenberg.ProcessSupervisorMock{
+				ReqQueueSizeMock: func() int64 {
+					return 5
+				},
+				RestartsCountMock: func() int64 {
+					return 1
+				},
+			},
 		},
 	}
 
@@ -289,13 +381,13 @@ func TestApi_Metrics(t *testing.T) {
 	}
 
 	actual := metrics[0].Read()
-	if actual != float64(10) {
-		t.Errorf("expected %f for libreoffice_requests_queue_size, but got %f", float64(10), actual)
+	if actual != float64(15) {
+		t.Errorf("expected %f for libreoffice_requests_queue_size, but got %f", float64(15), actual)
 	}
 
 	actual = metrics[1].Read()
-	if actual != float64(0) {
-		t.Errorf("expected %f for libreoffice_restarts_count, but got %f", float64(0), actual)
+	if actual != float64(1) {
+		t.Errorf("expected %f for libreoffice_restarts_count, but got %f", float64(1), actual)
 	}
 }
 
@@ -322,7 +414,7 @@ func TestApi_Checks(t *testing.T) {
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
-			a.supervisor = tc.supervisor
+			a.workers = []*worker{{supervisor: tc.supervisor}}
 
 			checks, err := a.Checks()
 			if err != nil {
@@ -379,7 +471,7 @@ func TestChromium_Ready(t *testing.T) {
 			a := new(Api)
 			a.autoStart = tc.autoStart
 			a.args = libreOfficeArguments{startTimeout: tc.startTimeout}
-			a.libreOffice = tc.libreOffice
+			a.workers = []*worker{{libreOffice: tc.libreOffice}}
 
 			err := a.Ready()
 
@@ -408,6 +500,8 @@ func TestApi_Pdf(t *testing.T) {
 		scenario    string
 		supervisor  gotenberg.ProcessSupervisor
 		libreOffice libreOffice
+		inputPath   string
+		options     Options
 		expectError bool
 	}{
 		{
@@ -424,15 +518,143 @@ func TestApi_Pdf(t *testing.T) {
 			}},
 			expectError: true,
 		},
+		{
+			scenario:  "input filter of the extension",
+			inputPath: "/foo/document.FODT",
+			libreOffice: &libreOfficeMock{pdfMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options Options) error {
+				if options.InputFilter != "OpenDocument Text Flat XML" {
+					return fmt.Errorf("unexpected input filter '%s'", options.InputFilter)
+				}
+				return nil
+			}},
+			expectError: false,
+		},
+		{
+			scenario:  "explicit input filter",
+			inputPath: "/foo/document.wpd",
+			options:   Options{InputFilter: "Text"},
+			libreOffice: &libreOfficeMock{pdfMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options Options) error {
+				if options.InputFilter != "Text" {
+					return fmt.Errorf("unexpected input filter '%s'", options.InputFilter)
+				}
+				return nil
+			}},
+			expectError: false,
+		},
+		{
+			scenario:  "no input filter",
+			inputPath: "/foo/document.docx",
+			libreOffice: &libreOfficeMock{pdfMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options Options) error {
+				if options.InputFilter != "" {
+					return fmt.Errorf("unexpected input filter '%s'", options.InputFilter)
+				}
+				return nil
+			}},
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			a := new(Api)
+			a.workers = []*worker{
+				{
+					libreOffice: tc.libreOffice,
+					supervisor: &gotenberg.ProcessSupervisorMock{RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+						return task()
+					}},
+					inFlight: new(sync.WaitGroup),
+				},
+			}
+
+			err := a.Pdf(context.Background(), zap.NewNop(), tc.inputPath, "", tc.options)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestApi_Pool(t *testing.T) {
+	quarantinedCtx := &api.Context{Context: context.Background()}
+	quarantinedCtx.SetRiskReport(api.RiskReport{Score: 100, Quarantined: true})
+
+	workers := []*worker{new(worker)}
+	quarantineWorkers := []*worker{new(worker)}
+
+	for _, tc := range []struct {
+		scenario          string
+		ctx               context.Context
+		quarantineWorkers []*worker
+		expectWorkers     []*worker
+	}{
+		{
+			scenario:          "regular request",
+			ctx:               context.Background(),
+			quarantineWorkers: quarantineWorkers,
+			expectWorkers:     workers,
+		},
+		{
+			scenario:          "quarantined request",
+			ctx:               quarantinedCtx,
+			quarantineWorkers: quarantineWorkers,
+			expectWorkers:     quarantineWorkers,
+		},
+		{
+			scenario:      "quarantined request without quarantine workers",
+			ctx:           quarantinedCtx,
+			expectWorkers: workers,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			a := &Api{workers: workers, quarantineWorkers: tc.quarantineWorkers}
+
+			actual := a.pool(tc.ctx, zap.NewNop())
+			if len(actual) != 1 || actual[0] != tc.expectWorkers[0] {
+				t.Errorf("expected %v but got %v", tc.expectWorkers, actual)
+			}
+		})
+	}
+}
+
+func TestApi_Convert(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		supervisor  gotenberg.ProcessSupervisor
+		libreOffice libreOffice
+		expectError bool
+	}{
+		{
+			scenario: "convert task success",
+			libreOffice: &libreOfficeMock{convertMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options ConvertOptions) error {
+				return nil
+			}},
+			expectError: false,
+		},
+		{
+			scenario: "convert task error",
+			libreOffice: &libreOfficeMock{convertMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options ConvertOptions) error {
+				return errors.New("convert task error")
+			}},
+			expectError: true,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			a := new(Api)
-			a.supervisor = &gotenberg.ProcessSupervisorMock{RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
-				return task()
-			}}
-			a.libreOffice = tc.libreOffice
+			a.workers = []*worker{
+				{
+					libreOffice: tc.libreOffice,
+					supervisor: &gotenberg.ProcessSupervisorMock{RunMock: func(ctx context.Context, logger *zap.Logger, task func() error) error {
+						return task()
+					}},
+					inFlight: new(sync.WaitGroup),
+				},
+			}
 
-			err := a.Pdf(context.Background(), zap.NewNop(), "", "", Options{})
+			err := a.Convert(context.Background(), zap.NewNop(), "", "", ConvertOptions{})
 
 			if !tc.expectError && err != nil {
 				t.Fatalf("expected no error but got: %v", err)
@@ -450,9 +672,26 @@ func TestApi_Extensions(t *testing.T) {
 	extensions := a.Extensions()
 
 	actual := len(extensions)
-	expect := 80
+	expect := 82
 
 	if actual != expect {
 		t.Errorf("expected %d extensions, but got %d", expect, actual)
 	}
 }
+
+func TestSupportsPdfVersion(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		expect  bool
+	}{
+		{version: "1.4", expect: false},
+		{version: "1.5", expect: true},
+		{version: "1.7", expect: true},
+		{version: "2.0", expect: false},
+	} {
+		actual := SupportsPdfVersion(tc.version)
+		if actual != tc.expect {
+			t.Errorf("expected %t for '%s' but got %t", tc.expect, tc.version, actual)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// bridgeScript is the Python script of the UNO bridge.
+//
+//go:embed bridge.py
+var bridgeScript []byte
+
+// errBridgeClosed happens if the UNO bridge exits, e.g., alongside its
+// LibreOffice instance, before answering a request.
+var errBridgeClosed = errors.New("UNO bridge closed")
+
+// bridgeRequest is a conversion to PDF sent to the UNO bridge.
+type bridgeRequest struct {
+	Id          uint64            `json:"id"`
+	InputPath   string            `json:"inputPath"`
+	OutputPath  string            `json:"outputPath"`
+	InputFilter string            `json:"inputFilter,omitempty"`
+	Landscape   bool              `json:"landscape,omitempty"`
+	Export      map[string]string `json:"export,omitempty"`
+}
+
+// bridgeResponse is the answer of the UNO bridge to a [bridgeRequest]. The
+// first response, with a zero ID, tells if the bridge is connected.
+type bridgeResponse struct {
+	Id    uint64 `json:"id"`
+	Error string `json:"error,omitempty"`
+	// ExitCode mimics the exit codes of unoconverter on failure.
+	ExitCode int `json:"exitCode,omitempty"`
+}
+
+// unoBridge is a persistent UNO connection to a LibreOffice instance, held
+// by a Python process, so that the conversions do not pay for starting
+// unoconverter and connecting to LibreOffice. The requests may be
+// pipelined: the bridge loads the next documents while exporting the
+// current one.
+type unoBridge struct {
+	logger  *zap.Logger
+	cmd     *gotenberg.Cmd
+	stdin   io.WriteCloser
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[uint64]chan bridgeResponse
+	nextId  uint64
+	closed  bool
+	done    chan struct{}
+}
+
+// startUnoBridge starts a UNO bridge to the LibreOffice instance listening
+// on the given port, and waits until it is connected.
+func startUnoBridge(ctx context.Context, logger *zap.Logger, pythonBinPath, dirPath string, port int) (*unoBridge, error) {
+	scriptPath := filepath.Join(dirPath, "bridge.py")
+
+	err := os.WriteFile(scriptPath, bridgeScript, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("write UNO bridge script: %w", err)
+	}
+
+	stdinReader, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("create stdin pipe: %w", err)
+	}
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		closeFiles(logger, stdinReader, stdinWriter)
+
+		return nil, fmt.Errorf("create stdout pipe: %w", err)
+	}
+
+	cmd := gotenberg.Command(logger, pythonBinPath, scriptPath, strconv.Itoa(port))
+	cmd.SetStdin(stdinReader)
+	cmd.SetStdout(stdoutWriter)
+
+	err = cmd.Start()
+
+	// The unix process has its own copies of these ends.
+	closeFiles(logger, stdinReader, stdoutWriter)
+
+	if err != nil {
+		closeFiles(logger, stdinWriter, stdoutReader)
+
+		return nil, fmt.Errorf("start UNO bridge: %w", err)
+	}
+
+	go func() {
+		// By waiting the process, we avoid the creation of a zombie process.
+		err := cmd.Wait()
+		if err != nil {
+			logger.Debug(fmt.Sprintf("UNO bridge exited: %s", err))
+		}
+	}()
+
+	bridge, ready := newUnoBridge(logger, stdinWriter, stdoutReader)
+	bridge.cmd = cmd
+
+	select {
+	case err = <-ready:
+		if err != nil {
+			bridge.close()
+
+			return nil, fmt.Errorf("connect UNO bridge: %w", err)
+		}
+
+		return bridge, nil
+	case <-ctx.Done():
+		bridge.close()
+
+		return nil, fmt.Errorf("connect UNO bridge: %w", ctx.Err())
+	}
+}
+
+// newUnoBridge creates a [unoBridge] which writes its requests to stdin and
+// reads the responses from stdout. The returned channel receives the result
+// of the connection of the bridge.
+func newUnoBridge(logger *zap.Logger, stdin io.WriteCloser, stdout io.ReadCloser) (*unoBridge, <-chan error) {
+	bridge := &unoBridge{
+		logger:  logger,
+		stdin:   stdin,
+		pending: make(map[uint64]chan bridgeResponse),
+		done:    make(chan struct{}),
+	}
+
+	ready := make(chan error, 1)
+	go bridge.read(stdout, ready)
+
+	return bridge, ready
+}
+
+// read dispatches the responses of the bridge to the pending requests until
+// the bridge exits.
+func (bridge *unoBridge) read(stdout io.ReadCloser, ready chan<- error) {
+	defer close(bridge.done)
+
+	connected := false
+	scanner := bufio.NewScanner(stdout)
+
+	for scanner.Scan() {
+		var res bridgeResponse
+
+		err := json.Unmarshal(scanner.Bytes(), &res)
+		if err != nil {
+			bridge.logger.Debug(fmt.Sprintf("unexpected UNO bridge output '%s': %s", scanner.Text(), err))
+			continue
+		}
+
+		if !connected {
+			connected = true
+
+			if res.Error != "" {
+				ready <- errors.New(res.Error)
+				continue
+			}
+
+			ready <- nil
+			continue
+		}
+
+		bridge.mu.Lock()
+		resChan, ok := bridge.pending[res.Id]
+		delete(bridge.pending, res.Id)
+		bridge.mu.Unlock()
+
+		if ok {
+			resChan <- res
+		}
+	}
+
+	if !connected {
+		ready <- errBridgeClosed
+	}
+
+	err := stdout.Close()
+	if err != nil {
+		bridge.logger.Debug(fmt.Sprintf("close UNO bridge stdout: %s", err))
+	}
+
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+
+	bridge.closed = true
+
+	for id, resChan := range bridge.pending {
+		resChan <- bridgeResponse{Id: id, Error: errBridgeClosed.Error()}
+		delete(bridge.pending, id)
+	}
+}
+
+// pdf sends a conversion to PDF to the bridge and waits for its response
+// or until the context is done. It returns the exit code unoconverter would
+// have returned.
+func (bridge *unoBridge) pdf(ctx context.Context, req bridgeRequest) (int, error) {
+	resChan := make(chan bridgeResponse, 1)
+
+	bridge.mu.Lock()
+	if bridge.closed {
+		bridge.mu.Unlock()
+
+		return 0, errBridgeClosed
+	}
+
+	bridge.nextId++
+	req.Id = bridge.nextId
+	bridge.pending[req.Id] = resChan
+	bridge.mu.Unlock()
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		bridge.forget(req.Id)
+
+		return 0, fmt.Errorf("marshal request: %w", err)
+	}
+
+	bridge.writeMu.Lock()
+	_, err = bridge.stdin.Write(append(line, '\n'))
+	bridge.writeMu.Unlock()
+
+	if err != nil {
+		bridge.forget(req.Id)
+
+		return 0, fmt.Errorf("write request: %w", err)
+	}
+
+	select {
+	case res := <-resChan:
+		if res.Error != "" {
+			return res.ExitCode, errors.New(res.Error)
+		}
+
+		return 0, nil
+	case <-ctx.Done():
+		// LibreOffice cannot abort a conversion: the bridge will answer
+		// nobody.
+		bridge.forget(req.Id)
+
+		return 0, ctx.Err()
+	}
+}
+
+// forget removes a pending request.
+func (bridge *unoBridge) forget(id uint64) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+
+	delete(bridge.pending, id)
+}
+
+// healthy tells if the bridge is still running.
+func (bridge *unoBridge) healthy() bool {
+	select {
+	case <-bridge.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// close stops the bridge. The pending requests fail.
+func (bridge *unoBridge) close() {
+	err := bridge.stdin.Close()
+	if err != nil {
+		bridge.logger.Debug(fmt.Sprintf("close UNO bridge stdin: %s", err))
+	}
+
+	if bridge.cmd == nil {
+		return
+	}
+
+	err = bridge.cmd.Kill()
+	if err != nil {
+		bridge.logger.Debug(fmt.Sprintf("kill UNO bridge: %s", err))
+	}
+}
+
+// closeFiles closes the given files, e.g., the ends of a pipe.
+func closeFiles(logger *zap.Logger, files ...*os.File) {
+	for _, file := range files {
+		err := file.Close()
+		if err != nil {
+			logger.Debug(fmt.Sprintf("close '%s': %s", file.Name(), err))
+		}
+	}
+}
@@ -0,0 +1,156 @@
+# UNO bridge of Gotenberg.
+#
+# It keeps a persistent UNO connection to a LibreOffice instance and converts
+# documents to PDF through it. It reads JSON requests on stdin and writes JSON
+# responses on stdout, one per line. It loads the next document while
+# exporting the current one, so that the requests may be pipelined.
+#
+# Usage: python3 bridge.py <port>
+
+import json
+import queue
+import sys
+import threading
+
+import uno
+from com.sun.star.beans import PropertyValue
+
+# The PDF export filters according to the services of a document, in order.
+PDF_FILTERS = [
+    ("com.sun.star.text.WebDocument", "writer_web_pdf_Export"),
+    ("com.sun.star.text.GenericTextDocument", "writer_pdf_Export"),
+    ("com.sun.star.sheet.SpreadsheetDocument", "calc_pdf_Export"),
+    ("com.sun.star.presentation.PresentationDocument", "impress_pdf_Export"),
+    ("com.sun.star.drawing.DrawingDocument", "draw_pdf_Export"),
+]
+
+# The exit code of unoconverter if LibreOffice cannot export a document.
+EXPORT_ERROR = 5
+
+write_lock = threading.Lock()
+
+
+def properties(values):
+    result = []
+    for name, value in values.items():
+        prop = PropertyValue()
+        prop.Name = name
+        prop.Value = value
+        result.append(prop)
+
+    return tuple(result)
+
+
+def export_value(raw):
+    # As with unoconverter, the values of the export options are strings.
+    if raw in ("true", "false"):
+        return raw == "true"
+
+    try:
+        return int(raw)
+    except ValueError:
+        return raw
+
+
+def respond(response):
+    with write_lock:
+        sys.stdout.write(json.dumps(response) + "\n")
+        sys.stdout.flush()
+
+
+def connect(port):
+    local = uno.getComponentContext()
+    resolver = local.ServiceManager.createInstanceWithContext("com.sun.star.bridge.UnoUrlResolver", local)
+    context = resolver.resolve(
+        "uno:socket,host=127.0.0.1,port=%d,tcpNoDelay=1;urp;StarOffice.ComponentContext" % port
+    )
+
+    return context.ServiceManager.createInstanceWithContext("com.sun.star.frame.Desktop", context)
+
+
+def load(desktop, request):
+    values = {"Hidden": True, "ReadOnly": True}
+    if request.get("inputFilter"):
+        values["FilterName"] = request["inputFilter"]
+
+    document = desktop.loadComponentFromURL(
+        uno.systemPathToFileUrl(request["inputPath"]), "_blank", 0, properties(values)
+    )
+    if document is None:
+        raise RuntimeError("unsupported document")
+
+    return document
+
+
+def export(document, request):
+    if request.get("landscape"):
+        orientation = uno.Enum("com.sun.star.view.PaperOrientation", "LANDSCAPE")
+        document.setPrinter(properties({"PaperOrientation": orientation}))
+
+    filter_name = None
+    for service, name in PDF_FILTERS:
+        if document.supportsService(service):
+            filter_name = name
+            break
+
+    if filter_name is None:
+        raise RuntimeError("no PDF export filter for this document")
+
+    filter_data = {name: export_value(value) for name, value in request.get("export", {}).items()}
+    values = {
+        "FilterName": filter_name,
+        "FilterData": uno.Any("[]com.sun.star.beans.PropertyValue", properties(filter_data)),
+    }
+
+    uno.invoke(document, "storeToURL", (uno.systemPathToFileUrl(request["outputPath"]), properties(values)))
+
+
+def main():
+    try:
+        desktop = connect(int(sys.argv[1]))
+    except Exception as e:
+        respond({"id": 0, "error": "connect: %s" % e})
+        return 1
+
+    # The first response tells that the bridge is ready.
+    respond({"id": 0})
+
+    # At most one document waits for its export while the next one loads.
+    loaded = queue.Queue(maxsize=1)
+
+    def loader():
+        for line in sys.stdin:
+            request = json.loads(line)
+            try:
+                loaded.put((request, load(desktop, request), None))
+            except Exception as e:
+                loaded.put((request, None, e))
+
+        loaded.put(None)
+
+    threading.Thread(target=loader, daemon=True).start()
+
+    while True:
+        item = loaded.get()
+        if item is None:
+            return 0
+
+        request, document, error = item
+        if error is not None:
+            respond({"id": request["id"], "error": "load document: %s" % error})
+            continue
+
+        try:
+            export(document, request)
+            respond({"id": request["id"]})
+        except Exception as e:
+            respond({"id": request["id"], "error": "export document: %s" % e, "exitCode": EXPORT_ERROR})
+        finally:
+            try:
+                document.close(True)
+            except Exception:
+                pass
+
+
+if __name__ == "__main__":
+    sys.exit(main())
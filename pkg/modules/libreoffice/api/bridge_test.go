@@ -0,0 +1,283 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeBridge starts a [unoBridge] talking to the given handler instead of
+// a Python process. The handler receives the requests and returns the
+// responses to write, if any.
+func fakeBridge(t *testing.T, connectErr string, handler func(req bridgeRequest) []bridgeResponse) (*unoBridge, <-chan error, func()) {
+	t.Helper()
+
+	reqReader, reqWriter := io.Pipe()
+	resReader, resWriter := io.Pipe()
+
+	bridge, ready := newUnoBridge(zap.NewNop(), reqWriter, resReader)
+
+	var writeMu sync.Mutex
+	write := func(res bridgeResponse) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		b, _ := json.Marshal(res)
+		_, _ = resWriter.Write(append(b, '\n'))
+	}
+
+	go func() {
+		write(bridgeResponse{Error: connectErr})
+
+		scanner := bufio.NewScanner(reqReader)
+		for scanner.Scan() {
+			var req bridgeRequest
+
+			err := json.Unmarshal(scanner.Bytes(), &req)
+			if err != nil {
+				continue
+			}
+
+			for _, res := range handler(req) {
+				write(res)
+			}
+		}
+	}()
+
+	stop := func() {
+		_ = reqReader.Close()
+		_ = resWriter.Close()
+	}
+
+	return bridge, ready, stop
+}
+
+func TestUnoBridge(t *testing.T) {
+	t.Run("connection error", func(t *testing.T) {
+		_, ready, stop := fakeBridge(t, "foo", func(req bridgeRequest) []bridgeResponse {
+			return nil
+		})
+		defer stop()
+
+		err := <-ready
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("pipelined conversions", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			received []bridgeRequest
+		)
+
+		// The fake bridge answers once it has received three requests, in
+		// reverse order.
+		bridge, ready, stop := fakeBridge(t, "", func(req bridgeRequest) []bridgeResponse {
+			mu.Lock()
+			defer mu.Unlock()
+
+			received = append(received, req)
+			if len(received) < 3 {
+				return nil
+			}
+
+			var responses []bridgeResponse
+			for i := len(received) - 1; i >= 0; i-- {
+				res := bridgeResponse{Id: received[i].Id}
+				if received[i].InputPath == "/bad.docx" {
+					res.Error = "export document: foo"
+					res.ExitCode = 5
+				}
+
+				responses = append(responses, res)
+			}
+
+			return responses
+		})
+		defer stop()
+
+		err := <-ready
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		type result struct {
+			exitCode int
+			err      error
+		}
+
+		results := make(map[string]result)
+		var wg sync.WaitGroup
+
+		for _, inputPath := range []string{"/a.docx", "/b.docx", "/bad.docx"} {
+			wg.Add(1)
+			go func(inputPath string) {
+				defer wg.Done()
+
+				exitCode, err := bridge.pdf(context.Background(), bridgeRequest{InputPath: inputPath, OutputPath: inputPath + ".pdf"})
+
+				mu.Lock()
+				results[inputPath] = result{exitCode: exitCode, err: err}
+				mu.Unlock()
+			}(inputPath)
+		}
+
+		wg.Wait()
+
+		for _, inputPath := range []string{"/a.docx", "/b.docx"} {
+			if results[inputPath].err != nil {
+				t.Errorf("expected no error for '%s' but got: %v", inputPath, results[inputPath].err)
+			}
+		}
+
+		if results["/bad.docx"].err == nil || results["/bad.docx"].exitCode != 5 {
+			t.Errorf("expected an error with exit code 5 for '/bad.docx' but got %+v", results["/bad.docx"])
+		}
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		bridge, ready, stop := fakeBridge(t, "", func(req bridgeRequest) []bridgeResponse {
+			return nil
+		})
+		defer stop()
+
+		err := <-ready
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(50)*time.Millisecond)
+		defer cancel()
+
+		_, err = bridge.pdf(ctx, bridgeRequest{InputPath: "/a.docx"})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected error %v but got: %v", context.DeadlineExceeded, err)
+		}
+
+		bridge.mu.Lock()
+		defer bridge.mu.Unlock()
+
+		if len(bridge.pending) != 0 {
+			t.Errorf("expected no pending request but got %d", len(bridge.pending))
+		}
+	})
+
+	t.Run("bridge exits", func(t *testing.T) {
+		received := make(chan struct{})
+
+		bridge, ready, stop := fakeBridge(t, "", func(req bridgeRequest) []bridgeResponse {
+			close(received)
+			return nil
+		})
+
+		err := <-ready
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		go func() {
+			<-received
+			stop()
+		}()
+
+		_, err = bridge.pdf(context.Background(), bridgeRequest{InputPath: "/a.docx"})
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+
+		<-bridge.done
+
+		if bridge.healthy() {
+			t.Error("expected an unhealthy bridge")
+		}
+
+		_, err = bridge.pdf(context.Background(), bridgeRequest{InputPath: "/a.docx"})
+		if !errors.Is(err, errBridgeClosed) {
+			t.Errorf("expected error %v but got: %v", errBridgeClosed, err)
+		}
+	})
+}
+
+func TestNewBridgeRequest(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		options       Options
+		export        []string
+		expectRequest bridgeRequest
+	}{
+		{
+			scenario: "no export option",
+			options:  Options{Landscape: true, InputFilter: "writer_pdf_import"},
+			expectRequest: bridgeRequest{
+				InputPath:   "/foo.docx",
+				OutputPath:  "/foo.pdf",
+				InputFilter: "writer_pdf_import",
+				Landscape:   true,
+			},
+		},
+		{
+			scenario: "export options",
+			export:   []string{"PageRange=1-2", "SelectPdfVersion=2"},
+			expectRequest: bridgeRequest{
+				InputPath:  "/foo.docx",
+				OutputPath: "/foo.pdf",
+				Export: map[string]string{
+					"PageRange":        "1-2",
+					"SelectPdfVersion": "2",
+				},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			req := newBridgeRequest("/foo.docx", "/foo.pdf", tc.options, tc.export)
+
+			if !reflect.DeepEqual(req, tc.expectRequest) {
+				t.Errorf("expected request %+v but got %+v", tc.expectRequest, req)
+			}
+		})
+	}
+}
+
+func TestStartUnoBridge(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		pythonBinPath string
+		expectError   bool
+	}{
+		{
+			scenario:      "non-existing Python binary",
+			pythonBinPath: "/foo",
+			expectError:   true,
+		},
+		{
+			scenario:      "bridge exits before connecting",
+			pythonBinPath: "true",
+			expectError:   true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			bridge, err := startUnoBridge(context.Background(), zap.NewNop(), tc.pythonBinPath, t.TempDir(), 2002)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if bridge != nil {
+				bridge.close()
+			}
+		})
+	}
+}
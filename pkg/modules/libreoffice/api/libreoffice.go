@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,18 +22,58 @@ import (
 type libreOffice interface {
 	gotenberg.Process
 	pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	convert(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error
+	memoryUsage() (int64, error)
 }
 
 type libreOfficeArguments struct {
-	binPath      string
-	unoBinPath   string
-	startTimeout time.Duration
+	binPath           string
+	unoBinPath        string
+	startTimeout      time.Duration
+	cgroupLimits      gotenberg.CgroupLimits
+	sandbox           gotenberg.SandboxOptions
+	complexTextLayout bool
+	unoBridge         bool
+	pythonBinPath     string
+}
+
+// complexTextLayoutConfiguration enables the complex text layout (CTL) of
+// LibreOffice, i.e., the shaping and the bidirectional layout of scripts such
+// as Arabic or Hebrew.
+const complexTextLayoutConfiguration = `<?xml version="1.0" encoding="UTF-8"?>
+<oor:items xmlns:oor="http://openoffice.org/2001/registry" xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+<item oor:path="/org.openoffice.Office.Common/I18N/CTL"><prop oor:name="CTLFont" oor:op="fuse"><value>true</value></prop></item>
+<item oor:path="/org.openoffice.Office.Common/I18N/CTL"><prop oor:name="CTLSequenceChecking" oor:op="fuse"><value>true</value></prop></item>
+</oor:items>
+`
+
+// writeUserProfileConfiguration writes the configuration of a LibreOffice
+// user profile before its first start, if any.
+func writeUserProfileConfiguration(userProfileDirPath string, arguments libreOfficeArguments) error {
+	if !arguments.complexTextLayout {
+		return nil
+	}
+
+	dirPath := filepath.Join(userProfileDirPath, "user")
+	err := os.MkdirAll(dirPath, 0o755)
+	if err != nil {
+		return fmt.Errorf("create user directory: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dirPath, "registrymodifications.xcu"), []byte(complexTextLayoutConfiguration), 0o644)
+	if err != nil {
+		return fmt.Errorf("write registry modifications: %w", err)
+	}
+
+	return nil
 }
 
 type libreOfficeProcess struct {
 	socketPort         int
 	userProfileDirPath string
 	cmd                *gotenberg.Cmd
+	cgroup             *gotenberg.Cgroup
+	bridge             *unoBridge
 	cfgMu              sync.RWMutex
 	isStarted          atomic.Bool
 
@@ -61,6 +102,25 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 	}
 
 	userProfileDirPath := p.fs.NewDirPath()
+
+	// Each LibreOffice instance has its own temporary directory, so that
+	// removing the files of one instance does not affect the others.
+	tmpDirPath := filepath.Join(userProfileDirPath, "tmp")
+	err = os.MkdirAll(tmpDirPath, 0o755)
+	if err != nil {
+		return fmt.Errorf("create LibreOffice's temporary directory: %w", err)
+	}
+
+	err = writeUserProfileConfiguration(userProfileDirPath, p.arguments)
+	if err != nil {
+		return fmt.Errorf("configure LibreOffice's user profile: %w", err)
+	}
+
+	err = p.arguments.sandbox.Own(userProfileDirPath)
+	if err != nil {
+		return fmt.Errorf("own LibreOffice's user profile: %w", err)
+	}
+
 	args := []string{
 		"--headless",
 		"--invisible",
@@ -81,6 +141,13 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 		return fmt.Errorf("create LibreOffice command: %w", err)
 	}
 
+	cmd.SetEnv(fmt.Sprintf("TMPDIR=%s", tmpDirPath))
+
+	err = cmd.SetSandbox(p.arguments.sandbox)
+	if err != nil {
+		return fmt.Errorf("set LibreOffice sandbox: %w", err)
+	}
+
 	// For whatever reason, LibreOffice requires a first start before being
 	// able to run as a daemon.
 	exitCode, err := cmd.Exec()
@@ -92,9 +159,32 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 
 	// Second start (daemon).
 	cmd = gotenberg.Command(logger, p.arguments.binPath, args...)
+	cmd.SetEnv(fmt.Sprintf("TMPDIR=%s", tmpDirPath))
+
+	err = cmd.SetSandbox(p.arguments.sandbox)
+	if err != nil {
+		return fmt.Errorf("set LibreOffice sandbox: %w", err)
+	}
+
+	var cgroup *gotenberg.Cgroup
+	if p.arguments.cgroupLimits.Enabled() {
+		cgroup, err = gotenberg.NewCgroup(p.arguments.cgroupLimits)
+		if err != nil {
+			return fmt.Errorf("create LibreOffice cgroup: %w", err)
+		}
+
+		err = cmd.SetCgroup(cgroup)
+		if err != nil {
+			removeCgroup(logger, cgroup)
+
+			return fmt.Errorf("set LibreOffice cgroup: %w", err)
+		}
+	}
 
 	err = cmd.Start()
 	if err != nil {
+		removeCgroup(logger, cgroup)
+
 		return fmt.Errorf("start LibreOffice: %w", err)
 	}
 
@@ -132,7 +222,10 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 		}
 	}()
 
-	var success bool
+	var (
+		success bool
+		bridge  *unoBridge
+	)
 
 	defer func() {
 		if success {
@@ -142,6 +235,8 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 			p.socketPort = port
 			p.userProfileDirPath = userProfileDirPath
 			p.cmd = cmd
+			p.cgroup = cgroup
+			p.bridge = bridge
 			p.isStarted.Store(true)
 
 			return
@@ -153,6 +248,8 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 			logger.Debug(fmt.Sprintf("kill LibreOffice process: %v", err))
 		}
 
+		removeCgroup(logger, cgroup)
+
 		// And the user profile directory is deleted.
 		err = os.RemoveAll(userProfileDirPath)
 		if err != nil {
@@ -172,6 +269,16 @@ func (p *libreOfficeProcess) Start(logger *zap.Logger) error {
 			}
 
 			logger.Debug("LibreOffice socket available")
+
+			if p.arguments.unoBridge {
+				bridge, err = startUnoBridge(ctx, logger, p.arguments.pythonBinPath, userProfileDirPath, port)
+				if err != nil {
+					return err
+				}
+
+				logger.Debug("UNO bridge connected")
+			}
+
 			success = true
 
 			return nil
@@ -198,8 +305,9 @@ func (p *libreOfficeProcess) Stop(logger *zap.Logger) error {
 
 			logger.Debug(fmt.Sprintf("'%s' LibreOffice's user profile directory removed", userProfileDirPath))
 
-			// Also remove LibreOffice specific files in the temporary directory.
-			err = gotenberg.GarbageCollect(logger, os.TempDir(), []string{"OSL_PIPE", ".tmp"})
+			// Also remove LibreOffice specific files in the temporary
+			// directory. Other files are in the user profile directory.
+			err = gotenberg.GarbageCollect(logger, os.TempDir(), []string{"OSL_PIPE"})
 			if err != nil {
 				logger.Error(err.Error())
 			}
@@ -209,19 +317,39 @@ func (p *libreOfficeProcess) Stop(logger *zap.Logger) error {
 	p.cfgMu.Lock()
 	defer p.cfgMu.Unlock()
 
+	if p.bridge != nil {
+		p.bridge.close()
+	}
+
 	err := p.cmd.Kill()
 	if err != nil {
 		return fmt.Errorf("kill LibreOffice process: %w", err)
 	}
 
+	removeCgroup(logger, p.cgroup)
+
 	p.socketPort = 0
 	p.userProfileDirPath = ""
 	p.cmd = nil
+	p.cgroup = nil
+	p.bridge = nil
 	p.isStarted.Store(false)
 
 	return nil
 }
 
+// removeCgroup removes the cgroup of a LibreOffice process, if any.
+func removeCgroup(logger *zap.Logger, cgroup *gotenberg.Cgroup) {
+	if cgroup == nil {
+		return
+	}
+
+	err := cgroup.Remove()
+	if err != nil {
+		logger.Error(fmt.Sprintf("remove LibreOffice's cgroup: %v", err))
+	}
+}
+
 func (p *libreOfficeProcess) Healthy(logger *zap.Logger) bool {
 	// Good to know: the supervisor does not call this method if no first start
 	// or if the process is restarting.
@@ -234,6 +362,10 @@ func (p *libreOfficeProcess) Healthy(logger *zap.Logger) bool {
 	p.cfgMu.RLock()
 	defer p.cfgMu.RUnlock()
 
+	if p.bridge != nil && !p.bridge.healthy() {
+		return false
+	}
+
 	conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", p.socketPort), time.Duration(10)*time.Second)
 	if err == nil {
 		err = conn.Close()
@@ -247,49 +379,69 @@ func (p *libreOfficeProcess) Healthy(logger *zap.Logger) bool {
 	return false
 }
 
-func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
+func (p *libreOfficeProcess) memoryUsage() (int64, error) {
 	if !p.isStarted.Load() {
-		return errors.New("LibreOffice not started, cannot handle PDF conversion")
+		return 0, errors.New("LibreOffice not started, cannot get its memory usage")
 	}
 
-	args := []string{
-		"--no-launch",
-		"--format",
-		"pdf",
+	p.cfgMu.RLock()
+	defer p.cfgMu.RUnlock()
+
+	// The cgroup also accounts for the children of the process.
+	if p.cgroup != nil {
+		return p.cgroup.MemoryUsage()
 	}
 
-	args = append(args, "--port", fmt.Sprintf("%d", p.socketPort))
+	return p.cmd.MemoryUsage()
+}
 
-	checkedEntry := logger.Check(zap.DebugLevel, "check for debug level before setting high verbosity")
-	if checkedEntry != nil {
-		args = append(args, "-vvv")
+func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
+	if !p.isStarted.Load() {
+		return errors.New("LibreOffice not started, cannot handle PDF conversion")
 	}
 
-	if options.Landscape {
-		args = append(args, "--printer", "PaperOrientation=landscape")
-	}
+	// The export options, in the form "key=value".
+	var export []string
 
 	if options.PageRanges != "" {
-		args = append(args, "--export", fmt.Sprintf("PageRange=%s", options.PageRanges))
+		export = append(export, fmt.Sprintf("PageRange=%s", options.PageRanges))
 	}
 
 	switch options.PdfFormats.PdfA {
 	case "":
 	case gotenberg.PdfA1b:
-		args = append(args, "--export", "SelectPdfVersion=1")
+		export = append(export, "SelectPdfVersion=1")
 	case gotenberg.PdfA2b:
-		args = append(args, "--export", "SelectPdfVersion=2")
+		export = append(export, "SelectPdfVersion=2")
 	case gotenberg.PdfA3b:
-		args = append(args, "--export", "SelectPdfVersion=3")
+		export = append(export, "SelectPdfVersion=3")
 	default:
 		return ErrInvalidPdfFormats
 	}
 
+	if options.PdfFormats.PdfVersion != "" {
+		selection, ok := pdfVersions[options.PdfFormats.PdfVersion]
+		if !ok || options.PdfFormats.PdfA != "" {
+			return ErrInvalidPdfFormats
+		}
+
+		export = append(export, fmt.Sprintf("SelectPdfVersion=%d", selection))
+	}
+
 	if options.PdfFormats.PdfUa {
-		args = append(
-			args,
-			"--export", "UseTaggedPDF=true",
-			"--export", "EnableTextAccessForAccessibilityTools=true",
+		export = append(
+			export,
+			"UseTaggedPDF=true",
+			"EnableTextAccessForAccessibilityTools=true",
+		)
+	}
+
+	if options.ExportLinks {
+		export = append(
+			export,
+			"ExportBookmarks=true",
+			"ExportBookmarksToPDFDestination=true",
+			"ConvertOOoTargetToPDFTarget=true",
 		)
 	}
 
@@ -298,16 +450,24 @@ func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputP
 		return fmt.Errorf("non-basic latin characters guard: %w", err)
 	}
 
-	args = append(args, "--output", outputPath, inputPath)
-
-	cmd, err := gotenberg.CommandContext(ctx, logger, p.arguments.unoBinPath, args...)
+	err = p.ownWorkingDirs(inputPath, outputPath)
 	if err != nil {
-		return fmt.Errorf("create uno command: %w", err)
+		return err
 	}
 
 	logger.Debug(fmt.Sprintf("print to PDF with: %+v", options))
 
-	exitCode, err := cmd.Exec()
+	p.cfgMu.RLock()
+	bridge := p.bridge
+	p.cfgMu.RUnlock()
+
+	var exitCode int
+	if bridge != nil {
+		exitCode, err = bridge.pdf(ctx, newBridgeRequest(inputPath, outputPath, options, export))
+	} else {
+		exitCode, err = p.unoconverterPdf(ctx, logger, inputPath, outputPath, options, export)
+	}
+
 	if err == nil {
 		return nil
 	}
@@ -329,6 +489,133 @@ func (p *libreOfficeProcess) pdf(ctx context.Context, logger *zap.Logger, inputP
 	return fmt.Errorf("convert to PDF: %w", err)
 }
 
+// unoconverterPdf converts a document to PDF with a new unoconverter
+// process.
+func (p *libreOfficeProcess) unoconverterPdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options, export []string) (int, error) {
+	args := []string{
+		"--no-launch",
+		"--format",
+		"pdf",
+	}
+
+	args = append(args, "--port", fmt.Sprintf("%d", p.socketPort))
+
+	checkedEntry := logger.Check(zap.DebugLevel, "check for debug level before setting high verbosity")
+	if checkedEntry != nil {
+		args = append(args, "-vvv")
+	}
+
+	if options.Landscape {
+		args = append(args, "--printer", "PaperOrientation=landscape")
+	}
+
+	for _, option := range export {
+		args = append(args, "--export", option)
+	}
+
+	if options.InputFilter != "" {
+		args = append(args, "--input-filter-name", options.InputFilter)
+	}
+
+	args = append(args, "--output", outputPath, inputPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, p.arguments.unoBinPath, args...)
+	if err != nil {
+		return 0, fmt.Errorf("create uno command: %w", err)
+	}
+
+	return cmd.Exec()
+}
+
+// newBridgeRequest creates the [bridgeRequest] of a conversion to PDF.
+func newBridgeRequest(inputPath, outputPath string, options Options, export []string) bridgeRequest {
+	req := bridgeRequest{
+		InputPath:   inputPath,
+		OutputPath:  outputPath,
+		InputFilter: options.InputFilter,
+		Landscape:   options.Landscape,
+	}
+
+	if len(export) > 0 {
+		req.Export = make(map[string]string, len(export))
+	}
+
+	for _, option := range export {
+		key, value, _ := strings.Cut(option, "=")
+		req.Export[key] = value
+	}
+
+	return req
+}
+
+func (p *libreOfficeProcess) convert(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error {
+	if !p.isStarted.Load() {
+		return errors.New("LibreOffice not started, cannot handle conversion")
+	}
+
+	args := []string{
+		"--no-launch",
+		"--format",
+		options.Format,
+	}
+
+	args = append(args, "--port", fmt.Sprintf("%d", p.socketPort))
+
+	checkedEntry := logger.Check(zap.DebugLevel, "check for debug level before setting high verbosity")
+	if checkedEntry != nil {
+		args = append(args, "-vvv")
+	}
+
+	if options.InputFilter != "" {
+		args = append(args, "--input-filter-name", options.InputFilter)
+	}
+
+	inputPath, err := nonBasicLatinCharactersGuard(logger, inputPath)
+	if err != nil {
+		return fmt.Errorf("non-basic latin characters guard: %w", err)
+	}
+
+	args = append(args, "--output", outputPath, inputPath)
+
+	err = p.ownWorkingDirs(inputPath, outputPath)
+	if err != nil {
+		return err
+	}
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, p.arguments.unoBinPath, args...)
+	if err != nil {
+		return fmt.Errorf("create uno command: %w", err)
+	}
+
+	logger.Debug(fmt.Sprintf("convert with: %+v", options))
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return fmt.Errorf("convert to %s: %w", options.Format, err)
+	}
+
+	return nil
+}
+
+// ownWorkingDirs gives the directories of the input and output files to the
+// dedicated user of the LibreOffice processes, if any, so that LibreOffice
+// may read and write them while the other converters may not.
+func (p *libreOfficeProcess) ownWorkingDirs(inputPath, outputPath string) error {
+	dirPaths := []string{filepath.Dir(inputPath)}
+	if filepath.Dir(outputPath) != dirPaths[0] {
+		dirPaths = append(dirPaths, filepath.Dir(outputPath))
+	}
+
+	for _, dirPath := range dirPaths {
+		err := p.arguments.sandbox.Own(dirPath)
+		if err != nil {
+			return fmt.Errorf("own working directory: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // LibreOffice cannot convert a file with a name containing non-basic Latin
 // characters.
 // See:
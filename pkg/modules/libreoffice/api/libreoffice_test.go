@@ -211,6 +211,38 @@ func TestLibreOfficeProcess_Healthy(t *testing.T) {
 	}
 }
 
+func TestLibreOfficeProcess_memoryUsage(t *testing.T) {
+	p := new(libreOfficeProcess)
+	p.isStarted.Store(false)
+
+	_, err := p.memoryUsage()
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	p.cmd = gotenberg.Command(zap.NewNop(), "sleep", "10")
+	err = p.cmd.Start()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = p.cmd.Kill()
+		_ = p.cmd.Wait()
+	}()
+
+	p.isStarted.Store(true)
+
+	usage, err := p.memoryUsage()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if usage <= 0 {
+		t.Errorf("expected a positive memory usage but got %d", usage)
+	}
+}
+
 func TestLibreOfficeProcess_pdf(t *testing.T) {
 	for _, tc := range []struct {
 		scenario      string
@@ -249,6 +281,36 @@ func TestLibreOfficeProcess_pdf(t *testing.T) {
 			expectError:   true,
 			expectedError: ErrInvalidPdfFormats,
 		},
+		{
+			scenario: "ErrInvalidPdfFormats (unsupported PDF version)",
+			libreOffice: func() libreOffice {
+				p := new(libreOfficeProcess)
+				p.socketPort = 12345
+				p.isStarted.Store(true)
+				return p
+			}(),
+			fs:            gotenberg.NewFileSystem(),
+			options:       Options{PdfFormats: gotenberg.PdfFormats{PdfVersion: "1.4"}},
+			cancelledCtx:  false,
+			start:         false,
+			expectError:   true,
+			expectedError: ErrInvalidPdfFormats,
+		},
+		{
+			scenario: "ErrInvalidPdfFormats (PDF version with PDF/A)",
+			libreOffice: func() libreOffice {
+				p := new(libreOfficeProcess)
+				p.socketPort = 12345
+				p.isStarted.Store(true)
+				return p
+			}(),
+			fs:            gotenberg.NewFileSystem(),
+			options:       Options{PdfFormats: gotenberg.PdfFormats{PdfA: gotenberg.PdfA1b, PdfVersion: "1.7"}},
+			cancelledCtx:  false,
+			start:         false,
+			expectError:   true,
+			expectedError: ErrInvalidPdfFormats,
+		},
 		{
 			scenario: "ErrMalformedPageRanges",
 			libreOffice: newLibreOfficeProcess(
@@ -509,6 +571,93 @@ func TestLibreOfficeProcess_pdf(t *testing.T) {
 			start:        true,
 			expectError:  false,
 		},
+		{
+			scenario: "success (export links)",
+			libreOffice: newLibreOfficeProcess(
+				libreOfficeArguments{
+					binPath:      os.Getenv("LIBREOFFICE_BIN_PATH"),
+					unoBinPath:   os.Getenv("UNOCONVERTER_BIN_PATH"),
+					startTimeout: 5 * time.Second,
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/document.txt", fs.WorkingDirPath()), []byte("Landscape"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options:      Options{ExportLinks: true},
+			cancelledCtx: false,
+			start:        true,
+			expectError:  false,
+		},
+		{
+			scenario: "success (input filter)",
+			libreOffice: newLibreOfficeProcess(
+				libreOfficeArguments{
+					binPath:      os.Getenv("LIBREOFFICE_BIN_PATH"),
+					unoBinPath:   os.Getenv("UNOCONVERTER_BIN_PATH"),
+					startTimeout: 5 * time.Second,
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/document.txt", fs.WorkingDirPath()), []byte("Landscape"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options:      Options{InputFilter: "Text"},
+			cancelledCtx: false,
+			start:        true,
+			expectError:  false,
+		},
+		{
+			scenario: "success (PDF version)",
+			libreOffice: newLibreOfficeProcess(
+				libreOfficeArguments{
+					binPath:      os.Getenv("LIBREOFFICE_BIN_PATH"),
+					unoBinPath:   os.Getenv("UNOCONVERTER_BIN_PATH"),
+					startTimeout: 5 * time.Second,
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf(fmt.Sprintf("expected no error but got: %v", err))
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/document.txt", fs.WorkingDirPath()), []byte("Landscape"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options:      Options{PdfFormats: gotenberg.PdfFormats{PdfVersion: "1.5"}},
+			cancelledCtx: false,
+			start:        true,
+			expectError:  false,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			// Force the debug level.
@@ -565,6 +714,138 @@ func TestLibreOfficeProcess_pdf(t *testing.T) {
 	}
 }
 
+func TestLibreOfficeProcess_convert(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		libreOffice  libreOffice
+		fs           *gotenberg.FileSystem
+		options      ConvertOptions
+		cancelledCtx bool
+		start        bool
+		expectError  bool
+	}{
+		{
+			scenario: "LibreOffice not started",
+			libreOffice: func() libreOffice {
+				p := new(libreOfficeProcess)
+				p.isStarted.Store(false)
+				return p
+			}(),
+			fs:           gotenberg.NewFileSystem(),
+			options:      ConvertOptions{Format: "odt"},
+			cancelledCtx: false,
+			start:        false,
+			expectError:  true,
+		},
+		{
+			scenario: "context done",
+			libreOffice: newLibreOfficeProcess(
+				libreOfficeArguments{
+					binPath:      os.Getenv("LIBREOFFICE_BIN_PATH"),
+					unoBinPath:   os.Getenv("UNOCONVERTER_BIN_PATH"),
+					startTimeout: 5 * time.Second,
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/document.txt", fs.WorkingDirPath()), []byte("Context done"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options:      ConvertOptions{Format: "odt"},
+			cancelledCtx: true,
+			start:        true,
+			expectError:  true,
+		},
+		{
+			scenario: "success",
+			libreOffice: newLibreOfficeProcess(
+				libreOfficeArguments{
+					binPath:      os.Getenv("LIBREOFFICE_BIN_PATH"),
+					unoBinPath:   os.Getenv("UNOCONVERTER_BIN_PATH"),
+					startTimeout: 5 * time.Second,
+				},
+			),
+			fs: func() *gotenberg.FileSystem {
+				fs := gotenberg.NewFileSystem()
+
+				err := os.MkdirAll(fs.WorkingDirPath(), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = os.WriteFile(fmt.Sprintf("%s/document.txt", fs.WorkingDirPath()), []byte("Success"), 0o755)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return fs
+			}(),
+			options:      ConvertOptions{Format: "odt"},
+			cancelledCtx: false,
+			start:        true,
+			expectError:  false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			logger := zap.NewExample()
+
+			defer func() {
+				err := os.RemoveAll(tc.fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up, but got: %v", err)
+				}
+			}()
+
+			if tc.start {
+				err := tc.libreOffice.Start(logger)
+				if err != nil {
+					t.Fatalf("setup error: %v", err)
+				}
+
+				defer func(p libreOffice, logger *zap.Logger) {
+					err = p.Stop(logger)
+					if err != nil {
+						t.Fatalf("expected no error while cleaning up, but got: %v", err)
+					}
+				}(tc.libreOffice, logger)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+			defer cancel()
+
+			if tc.cancelledCtx {
+				cancel()
+			}
+
+			err := tc.libreOffice.convert(
+				ctx,
+				logger,
+				fmt.Sprintf("%s/document.txt", tc.fs.WorkingDirPath()),
+				fmt.Sprintf("%s/%s.%s", tc.fs.WorkingDirPath(), uuid.NewString(), tc.options.Format),
+				tc.options,
+			)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
 func TestNonBasicLatinCharactersGuard(t *testing.T) {
 	for _, tc := range []struct {
 		scenario            string
@@ -648,3 +929,40 @@ func TestNonBasicLatinCharactersGuard(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteUserProfileConfiguration(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		arguments    libreOfficeArguments
+		expectConfig bool
+	}{
+		{
+			scenario:     "no configuration",
+			arguments:    libreOfficeArguments{},
+			expectConfig: false,
+		},
+		{
+			scenario:     "complex text layout",
+			arguments:    libreOfficeArguments{complexTextLayout: true},
+			expectConfig: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+
+			err := writeUserProfileConfiguration(dirPath, tc.arguments)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			_, err = os.Stat(fmt.Sprintf("%s/user/registrymodifications.xcu", dirPath))
+			if tc.expectConfig && err != nil {
+				t.Errorf("expected a configuration but got: %v", err)
+			}
+
+			if !tc.expectConfig && !os.IsNotExist(err) {
+				t.Errorf("expected no configuration but got: %v", err)
+			}
+		})
+	}
+}
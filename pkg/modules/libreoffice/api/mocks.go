@@ -11,6 +11,7 @@ import (
 // ApiMock is a mock for the [Uno] interface.
 type ApiMock struct {
 	PdfMock        func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	ConvertMock    func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error
 	ExtensionsMock func() []string
 }
 
@@ -18,6 +19,10 @@ func (api *ApiMock) Pdf(ctx context.Context, logger *zap.Logger, inputPath, outp
 	return api.PdfMock(ctx, logger, inputPath, outputPath, options)
 }
 
+func (api *ApiMock) Convert(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error {
+	return api.ConvertMock(ctx, logger, inputPath, outputPath, options)
+}
+
 func (api *ApiMock) Extensions() []string {
 	return api.ExtensionsMock()
 }
@@ -34,13 +39,23 @@ func (provider *ProviderMock) LibreOffice() (Uno, error) {
 // libreOfficeMock is a mock for the [libreOffice] interface.
 type libreOfficeMock struct {
 	gotenberg.ProcessMock
-	pdfMock func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	pdfMock         func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error
+	convertMock     func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error
+	memoryUsageMock func() (int64, error)
 }
 
 func (b *libreOfficeMock) pdf(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
 	return b.pdfMock(ctx, logger, inputPath, outputPath, options)
 }
 
+func (b *libreOfficeMock) convert(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error {
+	return b.convertMock(ctx, logger, inputPath, outputPath, options)
+}
+
+func (b *libreOfficeMock) memoryUsage() (int64, error) {
+	return b.memoryUsageMock()
+}
+
 // Interface guards.
 var (
 	_ Uno         = (*ApiMock)(nil)
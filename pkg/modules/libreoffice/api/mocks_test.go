@@ -12,6 +12,9 @@ func TestApiMock(t *testing.T) {
 		PdfMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options Options) error {
 			return nil
 		},
+		ConvertMock: func(ctx context.Context, logger *zap.Logger, input, outputPath string, options ConvertOptions) error {
+			return nil
+		},
 		ExtensionsMock: func() []string {
 			return nil
 		},
@@ -22,6 +25,11 @@ func TestApiMock(t *testing.T) {
 		t.Errorf("expected no error from ApiMock.Pdf, but got: %v", err)
 	}
 
+	err = mock.Convert(context.Background(), zap.NewNop(), "", "", ConvertOptions{})
+	if err != nil {
+		t.Errorf("expected no error from ApiMock.Convert, but got: %v", err)
+	}
+
 	ext := mock.Extensions()
 	if ext != nil {
 		t.Errorf("expected nil result from ApiMock.Extensions, but got: %v", ext)
@@ -46,10 +54,26 @@ func TestLibreOfficeMock(t *testing.T) {
 		pdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
 			return nil
 		},
+		convertMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options ConvertOptions) error {
+			return nil
+		},
+		memoryUsageMock: func() (int64, error) {
+			return 0, nil
+		},
 	}
 
 	err := mock.pdf(context.Background(), zap.NewNop(), "", "", Options{})
 	if err != nil {
 		t.Errorf("expected no error from libreOfficeMock.pdf, but got: %v", err)
 	}
+
+	err = mock.convert(context.Background(), zap.NewNop(), "", "", ConvertOptions{})
+	if err != nil {
+		t.Errorf("expected no error from libreOfficeMock.convert, but got: %v", err)
+	}
+
+	_, err = mock.memoryUsage()
+	if err != nil {
+		t.Errorf("expected no error from libreOfficeMock.memoryUsage, but got: %v", err)
+	}
 }
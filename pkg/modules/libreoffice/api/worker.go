@@ -0,0 +1,217 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// workerOptions gathers the options of a worker.
+type workerOptions struct {
+	restartAfter           int64
+	maxQueueSize           int64
+	rollingRestart         bool
+	restartMemoryThreshold int64
+	// pipelineDepth is the number of conversions the LibreOffice instance
+	// handles at once, through its UNO bridge.
+	pipelineDepth int
+}
+
+// worker is a LibreOffice instance managed by its own supervisor.
+//
+// With rolling restarts, the worker does not let the supervisor restart the
+// instance once it reaches the restart-after count or the memory threshold.
+// Instead, it starts a replacement instance in the background, routes the
+// next conversions to it, then waits for the conversions of the previous
+// instance to complete before stopping it.
+type worker struct {
+	logger         *zap.Logger
+	newLibreOffice func() libreOffice
+	options        workerOptions
+
+	mu          sync.RWMutex
+	libreOffice libreOffice
+	supervisor  gotenberg.ProcessSupervisor
+	inFlight    *sync.WaitGroup
+	stopped     bool
+
+	// load is the number of conversions either running or waiting on this
+	// worker.
+	load        atomic.Int64
+	conversions atomic.Int64
+	recycling   atomic.Bool
+	restarts    atomic.Int64
+}
+
+func newWorker(logger *zap.Logger, newLibreOffice func() libreOffice, options workerOptions) *worker {
+	w := &worker{
+		logger:         logger,
+		newLibreOffice: newLibreOffice,
+		options:        options,
+		inFlight:       new(sync.WaitGroup),
+	}
+	w.libreOffice = newLibreOffice()
+	w.supervisor = w.newSupervisor(w.libreOffice)
+
+	return w
+}
+
+func (w *worker) newSupervisor(libreOffice libreOffice) gotenberg.ProcessSupervisor {
+	restartAfter := w.options.restartAfter
+	if w.options.rollingRestart {
+		// The worker handles these restarts.
+		restartAfter = 0
+	}
+
+	return gotenberg.NewConcurrentProcessSupervisor(w.logger, libreOffice, restartAfter, w.options.maxQueueSize, w.options.pipelineDepth)
+}
+
+// current returns the LibreOffice instance currently handling the
+// conversions, alongside its supervisor.
+func (w *worker) current() (libreOffice, gotenberg.ProcessSupervisor) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.libreOffice, w.supervisor
+}
+
+// restartsCount returns the number of restarts of the worker, rolling ones
+// included.
+func (w *worker) restartsCount() int64 {
+	_, supervisor := w.current()
+
+	return w.restarts.Load() + supervisor.RestartsCount()
+}
+
+// shutdown stops the current LibreOffice instance. It also prevents an
+// ongoing rolling restart from swapping in its replacement.
+func (w *worker) shutdown() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.stopped = true
+
+	return w.supervisor.Shutdown()
+}
+
+// run runs a task with the current LibreOffice instance and triggers a
+// rolling restart if required.
+func (w *worker) run(ctx context.Context, logger *zap.Logger, task func(libreOffice libreOffice) error) error {
+	w.load.Add(1)
+	defer w.load.Add(-1)
+
+	w.mu.RLock()
+	libreOffice, supervisor, inFlight := w.libreOffice, w.supervisor, w.inFlight
+	inFlight.Add(1)
+	w.mu.RUnlock()
+
+	err := supervisor.Run(ctx, logger, func() error {
+		return task(libreOffice)
+	})
+
+	inFlight.Done()
+	w.conversions.Add(1)
+
+	if w.options.rollingRestart && w.shouldRestart(libreOffice) && w.recycling.CompareAndSwap(false, true) {
+		go func() {
+			defer w.recycling.Store(false)
+
+			restartErr := w.rollingRestart()
+			if restartErr != nil {
+				w.logger.Error(fmt.Sprintf("rolling restart: %s", restartErr))
+			}
+		}()
+	}
+
+	return err
+}
+
+// shouldRestart tells if the LibreOffice instance has reached the
+// restart-after count or the memory threshold.
+func (w *worker) shouldRestart(libreOffice libreOffice) bool {
+	if w.options.restartAfter > 0 && w.conversions.Load() >= w.options.restartAfter {
+		w.logger.Debug("max request limit reached, rolling restart...")
+		return true
+	}
+
+	if w.options.restartMemoryThreshold <= 0 {
+		return false
+	}
+
+	usage, err := libreOffice.memoryUsage()
+	if err != nil {
+		w.logger.Debug(fmt.Sprintf("get LibreOffice memory usage: %s", err))
+		return false
+	}
+
+	if usage < w.options.restartMemoryThreshold {
+		return false
+	}
+
+	w.logger.Debug(fmt.Sprintf("memory usage of %d bytes above threshold, rolling restart...", usage))
+
+	return true
+}
+
+// rollingRestart starts a replacement LibreOffice instance, swaps it with
+// the current one, then drains and stops the latter. If the replacement
+// cannot start, the current instance keeps handling the conversions.
+func (w *worker) rollingRestart() error {
+	libreOffice := w.newLibreOffice()
+	supervisor := w.newSupervisor(libreOffice)
+
+	err := supervisor.Launch()
+	if err != nil {
+		return fmt.Errorf("launch replacement: %w", err)
+	}
+
+	w.mu.Lock()
+
+	if w.stopped {
+		w.mu.Unlock()
+
+		err = supervisor.Shutdown()
+		if err != nil {
+			return fmt.Errorf("shutdown replacement: %w", err)
+		}
+
+		return nil
+	}
+
+	previousSupervisor, previousInFlight := w.supervisor, w.inFlight
+	w.libreOffice, w.supervisor, w.inFlight = libreOffice, supervisor, new(sync.WaitGroup)
+	w.conversions.Store(0)
+	w.restarts.Add(previousSupervisor.RestartsCount() + 1)
+
+	w.mu.Unlock()
+
+	w.logger.Debug("replacement started, draining the previous process...")
+	previousInFlight.Wait()
+
+	err = previousSupervisor.Shutdown()
+	if err != nil {
+		return fmt.Errorf("shutdown previous process: %w", err)
+	}
+
+	w.logger.Debug("process successfully replaced")
+
+	return nil
+}
+
+// leastBusyWorker returns the worker with the lowest load. On equality, the
+// first one wins.
+func leastBusyWorker(workers []*worker) *worker {
+	selected := workers[0]
+	for _, w := range workers[1:] {
+		if w.load.Load() < selected.load.Load() {
+			selected = w
+		}
+	}
+
+	return selected
+}
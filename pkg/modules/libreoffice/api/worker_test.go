@@ -0,0 +1,288 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// newTestLibreOffice returns a LibreOffice mock which counts its starts and
+// stops.
+func newTestLibreOffice(starts, stops *atomic.Int32, startErr error, usage int64) libreOffice {
+	return &libreOfficeMock{
+		ProcessMock: gotenberg.ProcessMock{
+			StartMock: func(logger *zap.Logger) error {
+				if startErr != nil {
+					return startErr
+				}
+
+				starts.Add(1)
+				return nil
+			},
+			StopMock: func(logger *zap.Logger) error {
+				stops.Add(1)
+				return nil
+			},
+			HealthyMock: func(logger *zap.Logger) bool {
+				return true
+			},
+		},
+		pdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options Options) error {
+			return nil
+		},
+		memoryUsageMock: func() (int64, error) {
+			return usage, nil
+		},
+	}
+}
+
+func TestNewWorker(t *testing.T) {
+	w := newWorker(zap.NewNop(), func() libreOffice { return new(libreOfficeMock) }, workerOptions{restartAfter: 10})
+
+	libreOffice, supervisor := w.current()
+
+	if libreOffice == nil {
+		t.Error("expected a LibreOffice process")
+	}
+
+	if supervisor == nil {
+		t.Error("expected a process supervisor")
+	}
+}
+
+func TestWorker_run(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		options        workerOptions
+		startErr       error
+		usage          int64
+		conversions    int
+		expectRestarts int64
+		expectStarts   int32
+		expectStops    int32
+	}{
+		{
+			scenario:       "no rolling restart",
+			options:        workerOptions{restartAfter: 2},
+			conversions:    3,
+			expectRestarts: 1,
+			expectStarts:   2,
+			expectStops:    1,
+		},
+		{
+			scenario:       "rolling restart after a number of conversions",
+			options:        workerOptions{restartAfter: 2, rollingRestart: true},
+			conversions:    2,
+			expectRestarts: 1,
+			expectStarts:   2,
+			expectStops:    1,
+		},
+		{
+			scenario:       "rolling restart above the memory threshold",
+			options:        workerOptions{rollingRestart: true, restartMemoryThreshold: 1024},
+			usage:          2048,
+			conversions:    1,
+			expectRestarts: 1,
+			expectStarts:   2,
+			expectStops:    1,
+		},
+		{
+			scenario:       "no rolling restart below the memory threshold",
+			options:        workerOptions{rollingRestart: true, restartMemoryThreshold: 1024},
+			usage:          512,
+			conversions:    3,
+			expectRestarts: 0,
+			expectStarts:   1,
+			expectStops:    0,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var starts, stops atomic.Int32
+			w := newWorker(zap.NewNop(), func() libreOffice {
+				return newTestLibreOffice(&starts, &stops, tc.startErr, tc.usage)
+			}, tc.options)
+
+			for i := 0; i < tc.conversions; i++ {
+				err := w.run(context.Background(), zap.NewNop(), func(libreOffice libreOffice) error {
+					return libreOffice.pdf(context.Background(), zap.NewNop(), "", "", Options{})
+				})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			// Wait for the rolling restart, if any.
+			deadline := time.Now().Add(time.Duration(1) * time.Second)
+			for time.Now().Before(deadline) && (w.recycling.Load() || w.restartsCount() < tc.expectRestarts) {
+				time.Sleep(time.Duration(1) * time.Millisecond)
+			}
+
+			if w.restartsCount() != tc.expectRestarts {
+				t.Errorf("expected %d restarts but got %d", tc.expectRestarts, w.restartsCount())
+			}
+
+			if starts.Load() != tc.expectStarts {
+				t.Errorf("expected %d starts but got %d", tc.expectStarts, starts.Load())
+			}
+
+			if stops.Load() != tc.expectStops {
+				t.Errorf("expected %d stops but got %d", tc.expectStops, stops.Load())
+			}
+		})
+	}
+}
+
+func TestWorker_rollingRestart(t *testing.T) {
+	t.Run("drain the previous process", func(t *testing.T) {
+		var starts, stops atomic.Int32
+		w := newWorker(zap.NewNop(), func() libreOffice {
+			return newTestLibreOffice(&starts, &stops, nil, 0)
+		}, workerOptions{rollingRestart: true})
+
+		previous, _ := w.current()
+
+		release := make(chan struct{})
+		running := make(chan struct{})
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = w.run(context.Background(), zap.NewNop(), func(libreOffice libreOffice) error {
+				close(running)
+				<-release
+				return nil
+			})
+		}()
+
+		<-running
+
+		restarted := make(chan error, 1)
+		go func() {
+			restarted <- w.rollingRestart()
+		}()
+
+		// The replacement handles the next conversions right away...
+		deadline := time.Now().Add(time.Duration(1) * time.Second)
+		for {
+			current, _ := w.current()
+			if current != previous {
+				break
+			}
+
+			if time.Now().After(deadline) {
+				t.Fatal("expected the replacement to handle the next conversions")
+			}
+
+			time.Sleep(time.Duration(1) * time.Millisecond)
+		}
+
+		// ...while the previous process stops only once drained.
+		if stops.Load() != 0 {
+			t.Fatal("expected the previous process to keep running until drained")
+		}
+
+		close(release)
+		wg.Wait()
+
+		err := <-restarted
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if stops.Load() != 1 {
+			t.Errorf("expected the previous process to be stopped but got %d stops", stops.Load())
+		}
+	})
+
+	t.Run("replacement failed to start", func(t *testing.T) {
+		var starts, stops atomic.Int32
+		first := true
+		w := newWorker(zap.NewNop(), func() libreOffice {
+			if first {
+				first = false
+				return newTestLibreOffice(&starts, &stops, nil, 0)
+			}
+
+			return newTestLibreOffice(&starts, &stops, errors.New("foo"), 0)
+		}, workerOptions{rollingRestart: true})
+
+		previous, _ := w.current()
+
+		err := w.rollingRestart()
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+
+		current, _ := w.current()
+		if current != previous {
+			t.Error("expected the previous process to keep handling the conversions")
+		}
+	})
+
+	t.Run("worker stopped", func(t *testing.T) {
+		var starts, stops atomic.Int32
+		w := newWorker(zap.NewNop(), func() libreOffice {
+			return newTestLibreOffice(&starts, &stops, nil, 0)
+		}, workerOptions{rollingRestart: true})
+
+		err := w.shutdown()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = w.rollingRestart()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if starts.Load() != 1 || stops.Load() != 2 {
+			t.Errorf("expected the replacement to be stopped but got %d starts and %d stops", starts.Load(), stops.Load())
+		}
+	})
+}
+
+func TestLeastBusyWorker(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		loads       []int64
+		expectIndex int
+	}{
+		{
+			scenario:    "single worker",
+			loads:       []int64{3},
+			expectIndex: 0,
+		},
+		{
+			scenario:    "idle workers",
+			loads:       []int64{0, 0, 0},
+			expectIndex: 0,
+		},
+		{
+			scenario:    "busy workers",
+			loads:       []int64{2, 1, 3, 1},
+			expectIndex: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			workers := make([]*worker, len(tc.loads))
+			for i, load := range tc.loads {
+				workers[i] = new(worker)
+				workers[i].load.Store(load)
+			}
+
+			actual := leastBusyWorker(workers)
+
+			if actual != workers[tc.expectIndex] {
+				t.Errorf("expected worker %d", tc.expectIndex)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package libreoffice
+
+import (
+	"fmt"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// attachSources embeds the source documents of a PDF in place, so that its
+// recipients may retrieve the editable documents. The attachments have the
+// names of the documents.
+func attachSources(pdfPath string, sourcePaths []string) error {
+	// An empty output path means in place.
+	err := pdfcpuAPI.AddAttachmentsFile(pdfPath, "", sourcePaths, false, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		return fmt.Errorf("add attachments with PDFcpu: %w", err)
+	}
+
+	return nil
+}
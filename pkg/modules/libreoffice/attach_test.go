@@ -0,0 +1,62 @@
+package libreoffice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func TestAttachSources(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+
+	dirPath := t.TempDir()
+	sourcePath := filepath.Join(dirPath, "document.docx")
+
+	err := os.WriteFile(sourcePath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = attachSources(filepath.Join(dirPath, "foo.pdf"), []string{sourcePath})
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	b, err := os.ReadFile("../../../test/testdata/pdfengines/sample1.pdf")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	pdfPath := filepath.Join(dirPath, "document.docx.pdf")
+
+	err = os.WriteFile(pdfPath, b, 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = attachSources(pdfPath, []string{sourcePath})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	f, err := os.Open(pdfPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	attachments, err := pdfcpuAPI.Attachments(f, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(attachments) != 1 || attachments[0].FileName != "document.docx" {
+		t.Errorf("expected the attachment 'document.docx' but got %+v", attachments)
+	}
+}
@@ -0,0 +1,173 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// textDirectionLtr forces a left-to-right text direction.
+	textDirectionLtr = "ltr"
+
+	// textDirectionRtl forces a right-to-left text direction, e.g., for
+	// Arabic or Hebrew.
+	textDirectionRtl = "rtl"
+)
+
+var (
+	docxBidiRegexp                 = regexp.MustCompile(`<w:bidi(?:\s[^>]*)?/>`)
+	docxPPrDefaultRegexp           = regexp.MustCompile(`(?s)<w:pPrDefault\s*/>|<w:pPrDefault>.*?</w:pPrDefault>`)
+	docxPPrRegexp                  = regexp.MustCompile(`(?s)<w:pPr>(.*?)</w:pPr>`)
+	docxStylesRegexp               = regexp.MustCompile(`<w:styles(?:\s[^>]*)?>`)
+	odtWritingModeRegexp           = regexp.MustCompile(`\sstyle:writing-mode="[^"]*"`)
+	odtDefaultParagraphStyleRegexp = regexp.MustCompile(`<style:default-style\s[^>]*style:family="paragraph"[^>]*>`)
+)
+
+// forceTextDirection writes a copy of a DOCX or ODT document in which all
+// the paragraphs have the given text direction. It returns false if the
+// format of the document does not support it.
+func forceTextDirection(inputPath, outputPath, direction string) (bool, error) {
+	var (
+		entries []string
+		force   func(entry, content, direction string) string
+	)
+
+	switch strings.ToLower(filepath.Ext(inputPath)) {
+	case ".docx":
+		entries = []string{"word/document.xml", "word/styles.xml"}
+		force = forceDocxTextDirection
+	case ".odt":
+		entries = []string{"content.xml", "styles.xml"}
+		force = forceOdtTextDirection
+	default:
+		return false, nil
+	}
+
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return false, fmt.Errorf("open document: %w", err)
+	}
+	defer reader.Close()
+
+	contents := make(map[string][]byte)
+	for _, f := range reader.File {
+		for _, entry := range entries {
+			if f.Name != entry {
+				continue
+			}
+
+			content, err := readZipFile(f)
+			if err != nil {
+				return false, fmt.Errorf("read '%s': %w", entry, err)
+			}
+
+			contents[entry] = []byte(force(entry, string(content), direction))
+		}
+	}
+
+	err = rewriteArchive(reader.File, contents, outputPath)
+	if err != nil {
+		return false, fmt.Errorf("rewrite document: %w", err)
+	}
+
+	return true, nil
+}
+
+// forceDocxTextDirection removes the text directions of the paragraphs and
+// of their styles, and sets the given one as the default.
+func forceDocxTextDirection(entry, content, direction string) string {
+	content = docxBidiRegexp.ReplaceAllString(content, "")
+	if entry != "word/styles.xml" {
+		return content
+	}
+
+	bidi := `<w:bidi w:val="0"/>`
+	if direction == textDirectionRtl {
+		bidi = "<w:bidi/>"
+	}
+
+	pPrDefault := "<w:pPrDefault><w:pPr>" + bidi + "</w:pPr></w:pPrDefault>"
+
+	loc := docxPPrDefaultRegexp.FindStringIndex(content)
+	if loc != nil {
+		pPr := docxPPrRegexp.FindStringSubmatch(content[loc[0]:loc[1]])
+		if pPr != nil {
+			pPrDefault = "<w:pPrDefault><w:pPr>" + bidi + pPr[1] + "</w:pPr></w:pPrDefault>"
+		}
+
+		return content[:loc[0]] + pPrDefault + content[loc[1]:]
+	}
+
+	// The paragraph defaults come after the run defaults, and the document
+	// defaults come first.
+	switch {
+	case strings.Contains(content, "<w:docDefaults/>"):
+		return strings.Replace(content, "<w:docDefaults/>", "<w:docDefaults>"+pPrDefault+"</w:docDefaults>", 1)
+	case strings.Contains(content, "</w:docDefaults>"):
+		return strings.Replace(content, "</w:docDefaults>", pPrDefault+"</w:docDefaults>", 1)
+	}
+
+	loc = docxStylesRegexp.FindStringIndex(content)
+	if loc == nil {
+		return content
+	}
+
+	return content[:loc[1]] + "<w:docDefaults>" + pPrDefault + "</w:docDefaults>" + content[loc[1]:]
+}
+
+// forceOdtTextDirection replaces the writing modes of the paragraphs and of
+// the pages, and sets the given one as the default.
+func forceOdtTextDirection(entry, content, direction string) string {
+	writingMode := "lr-tb"
+	if direction == textDirectionRtl {
+		writingMode = "rl-tb"
+	}
+
+	attr := fmt.Sprintf(` style:writing-mode="%s"`, writingMode)
+
+	content = odtWritingModeRegexp.ReplaceAllString(content, attr)
+	if entry != "styles.xml" {
+		return content
+	}
+
+	properties := "<style:paragraph-properties" + attr + "/>"
+
+	loc := odtDefaultParagraphStyleRegexp.FindStringIndex(content)
+	if loc == nil {
+		style := `<style:default-style style:family="paragraph">` + properties + "</style:default-style>"
+
+		if strings.Contains(content, "<office:styles/>") {
+			return strings.Replace(content, "<office:styles/>", "<office:styles>"+style+"</office:styles>", 1)
+		}
+
+		return strings.Replace(content, "<office:styles>", "<office:styles>"+style, 1)
+	}
+
+	tag := content[loc[0]:loc[1]]
+	if strings.HasSuffix(tag, "/>") {
+		return content[:loc[0]] + strings.TrimSuffix(tag, "/>") + ">" + properties + "</style:default-style>" + content[loc[1]:]
+	}
+
+	end := strings.Index(content[loc[1]:], "</style:default-style>")
+	if end < 0 {
+		return content
+	}
+
+	styleContent := content[loc[1] : loc[1]+end]
+	if strings.Contains(styleContent, "writing-mode") {
+		// Already replaced.
+		return content
+	}
+
+	i := strings.Index(styleContent, "<style:paragraph-properties")
+	if i < 0 {
+		return content[:loc[1]] + properties + content[loc[1]:]
+	}
+
+	i = loc[1] + i + len("<style:paragraph-properties")
+
+	return content[:i] + attr + content[i:]
+}
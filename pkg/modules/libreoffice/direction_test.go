@@ -0,0 +1,143 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestForceTextDirection(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		filename      string
+		entries       [][2]string
+		direction     string
+		expectOk      bool
+		expectError   bool
+		expectEntries map[string][]string
+	}{
+		{
+			scenario: "unsupported format",
+			filename: "document.doc",
+			expectOk: false,
+		},
+		{
+			scenario:    "invalid document",
+			filename:    "document.docx",
+			expectError: true,
+		},
+		{
+			scenario: "DOCX",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"word/document.xml", `<w:document><w:body><w:p><w:pPr><w:bidi w:val="0"/></w:pPr></w:p><w:tbl><w:tblPr><w:bidiVisual/></w:tblPr></w:tbl></w:body></w:document>`},
+				{"word/styles.xml", `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:docDefaults><w:rPrDefault/><w:pPrDefault><w:pPr><w:spacing w:after="160"/></w:pPr></w:pPrDefault></w:docDefaults><w:style><w:pPr><w:bidi/></w:pPr></w:style></w:styles>`},
+			},
+			direction: textDirectionRtl,
+			expectOk:  true,
+			expectEntries: map[string][]string{
+				"word/document.xml": {`<w:pPr></w:pPr>`, `<w:bidiVisual/>`},
+				"word/styles.xml":   {`<w:pPrDefault><w:pPr><w:bidi/><w:spacing w:after="160"/></w:pPr></w:pPrDefault>`, `<w:style><w:pPr></w:pPr></w:style>`},
+			},
+		},
+		{
+			scenario: "DOCX without document defaults",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"word/styles.xml", `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:style/></w:styles>`},
+			},
+			direction: textDirectionLtr,
+			expectOk:  true,
+			expectEntries: map[string][]string{
+				"word/styles.xml": {`<w:docDefaults><w:pPrDefault><w:pPr><w:bidi w:val="0"/></w:pPr></w:pPrDefault></w:docDefaults><w:style/>`},
+			},
+		},
+		{
+			scenario: "ODT",
+			filename: "document.odt",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.text"},
+				{"content.xml", `<office:document-content><style:style><style:paragraph-properties style:writing-mode="lr-tb"/></style:style></office:document-content>`},
+				{"styles.xml", `<office:document-styles><office:styles><style:default-style style:family="paragraph"><style:paragraph-properties fo:orphans="2"/></style:default-style></office:styles></office:document-styles>`},
+			},
+			direction: textDirectionRtl,
+			expectOk:  true,
+			expectEntries: map[string][]string{
+				"content.xml": {`<style:paragraph-properties style:writing-mode="rl-tb"/>`},
+				"styles.xml":  {`<style:default-style style:family="paragraph"><style:paragraph-properties style:writing-mode="rl-tb" fo:orphans="2"/></style:default-style>`},
+			},
+		},
+		{
+			scenario: "ODT without default paragraph style",
+			filename: "document.odt",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.text"},
+				{"styles.xml", `<office:document-styles><office:styles/></office:document-styles>`},
+			},
+			direction: textDirectionRtl,
+			expectOk:  true,
+			expectEntries: map[string][]string{
+				"styles.xml": {`<office:styles><style:default-style style:family="paragraph"><style:paragraph-properties style:writing-mode="rl-tb"/></style:default-style></office:styles>`},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, tc.filename)
+			outputPath := filepath.Join(dirPath, "output"+filepath.Ext(tc.filename))
+
+			if tc.entries == nil {
+				err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("write file: %v", err)
+				}
+			} else {
+				writeTestWorkbook(t, inputPath, tc.entries)
+			}
+
+			ok, err := forceTextDirection(inputPath, outputPath, tc.direction)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if ok != tc.expectOk {
+				t.Fatalf("expected %t but got %t", tc.expectOk, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			reader, err := zip.OpenReader(outputPath)
+			if err != nil {
+				t.Fatalf("open document: %v", err)
+			}
+			defer reader.Close()
+
+			for _, f := range reader.File {
+				expects, ok := tc.expectEntries[f.Name]
+				if !ok {
+					continue
+				}
+
+				content, err := readZipFile(f)
+				if err != nil {
+					t.Fatalf("read '%s': %v", f.Name, err)
+				}
+
+				for _, expect := range expects {
+					if !strings.Contains(string(content), expect) {
+						t.Errorf("expected '%s' in '%s' but got: %s", expect, f.Name, content)
+					}
+				}
+			}
+		})
+	}
+}
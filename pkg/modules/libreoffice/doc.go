@@ -1,3 +1,8 @@
 // Package libreoffice provides a module which adds a route for converting
-// documents to PDF with LibreOffice.
+// documents to PDF with LibreOffice. It also adds a route which reads the
+// properties of Office Open XML and OpenDocument files, e.g., their author or
+// their number of words, without converting them. As it reads them from the
+// archives of the files, this route only supports zip-based formats, e.g.,
+// DOCX or ODT, but not DOC or RTF. Its response is a JSON object with the
+// properties of each document, by filename.
 package libreoffice
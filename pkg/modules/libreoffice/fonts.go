@@ -0,0 +1,158 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	docxFontRegexp        = regexp.MustCompile(`<w:font\s[^>]*w:name="([^"]*)"`)
+	odfFontFaceRegexp     = regexp.MustCompile(`<style:font-face\s[^>]*svg:font-family="([^"]*)"`)
+	pdfBaseFontRegexp     = regexp.MustCompile(`/BaseFont\s*/([^\s/<>\[\]()]+)`)
+	pdfSubsetPrefixRegexp = regexp.MustCompile(`^[A-Z]{6}\+`)
+	pdfNameEscapedRegexp  = regexp.MustCompile(`#[0-9A-Fa-f]{2}`)
+)
+
+// ignoredFallbackFonts are the fonts LibreOffice adds on its own, e.g., for
+// the bullets.
+var ignoredFallbackFonts = map[string]bool{
+	"opensymbol": true,
+}
+
+// fallbackFonts returns the fonts of a PDF converted by LibreOffice which the
+// source document does not declare, i.e., the fonts LibreOffice fell back to
+// because the declared ones are not installed or do not have some glyphs. It
+// returns nothing if the format of the document does not declare its fonts.
+func fallbackFonts(inputPath, pdfPath string) ([]string, error) {
+	declared, err := declaredFonts(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("get declared fonts: %w", err)
+	}
+
+	if declared == nil {
+		return nil, nil
+	}
+
+	used, err := pdfFonts(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("get PDF fonts: %w", err)
+	}
+
+	var fallback []string
+	for _, font := range used {
+		key := fontKey(font)
+		if declared[key] || ignoredFallbackFonts[key] {
+			continue
+		}
+
+		fallback = append(fallback, font)
+	}
+
+	return fallback, nil
+}
+
+// declaredFonts returns the keys of the fonts a DOCX or an OpenDocument file
+// declares, or nil if the format is not supported.
+func declaredFonts(inputPath string) (map[string]bool, error) {
+	var (
+		entries []string
+		re      *regexp.Regexp
+	)
+
+	switch strings.ToLower(filepath.Ext(inputPath)) {
+	case ".docx":
+		entries = []string{"word/fontTable.xml"}
+		re = docxFontRegexp
+	case ".odt", ".ods", ".odp":
+		entries = []string{"content.xml", "styles.xml"}
+		re = odfFontFaceRegexp
+	default:
+		return nil, nil
+	}
+
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("open document: %w", err)
+	}
+	defer reader.Close()
+
+	fonts := make(map[string]bool)
+	for _, f := range reader.File {
+		for _, entry := range entries {
+			if f.Name != entry {
+				continue
+			}
+
+			content, err := readZipFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("read '%s': %w", entry, err)
+			}
+
+			for _, match := range re.FindAllStringSubmatch(string(content), -1) {
+				fonts[fontKey(html.UnescapeString(match[1]))] = true
+			}
+		}
+	}
+
+	return fonts, nil
+}
+
+// pdfFonts returns the names of the fonts of a PDF, without their subset
+// prefixes. LibreOffice does not write the font dictionaries in compressed
+// object streams, so that there is no need to parse the PDF.
+func pdfFonts(pdfPath string) ([]string, error) {
+	b, err := os.ReadFile(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("read PDF: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var fonts []string
+	for _, match := range pdfBaseFontRegexp.FindAllSubmatch(b, -1) {
+		// ABCDEF+Noto#20Sans -> Noto Sans.
+		name := pdfNameEscapedRegexp.ReplaceAllStringFunc(string(match[1]), func(escaped string) string {
+			c, _ := strconv.ParseUint(escaped[1:], 16, 8)
+			return string(rune(c))
+		})
+		name = pdfSubsetPrefixRegexp.ReplaceAllString(name, "")
+
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+		fonts = append(fonts, name)
+	}
+
+	sort.Strings(fonts)
+
+	return fonts, nil
+}
+
+// fontKey returns a key for comparing the name of a font in a document with
+// its name in a PDF, e.g., "Noto Sans Arabic" and "NotoSansArabic-Bold".
+func fontKey(name string) string {
+	name = strings.Trim(name, `'"`)
+
+	i := strings.IndexAny(name, "-,")
+	if i > 0 {
+		name = name[:i]
+	}
+
+	key := strings.ToLower(strings.Join(strings.Fields(name), ""))
+	for _, suffix := range []string{"psmt", "mt"} {
+		trimmed := strings.TrimSuffix(key, suffix)
+		if trimmed != "" && trimmed != key {
+			return trimmed
+		}
+	}
+
+	return key
+}
@@ -0,0 +1,105 @@
+package libreoffice
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFallbackFonts(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n" +
+		"4 0 obj\n<</Type/Font/Subtype/TrueType/BaseFont/BAAAAA+NotoSansArabic-Bold>>\nendobj\n" +
+		"5 0 obj\n<</Type/Font/Subtype/TrueType/BaseFont /CAAAAA+DejaVu#20Sans>>\nendobj\n" +
+		"6 0 obj\n<</Type/Font/Subtype/TrueType/BaseFont/DAAAAA+OpenSymbol>>\nendobj\n" +
+		"7 0 obj\n<</Type/Font/Subtype/TrueType/BaseFont/TimesNewRomanPSMT>>\nendobj\n")
+
+	for _, tc := range []struct {
+		scenario       string
+		filename       string
+		entries        [][2]string
+		expectFallback []string
+		expectError    bool
+	}{
+		{
+			scenario:       "unsupported format",
+			filename:       "document.doc",
+			expectFallback: nil,
+		},
+		{
+			scenario:    "invalid document",
+			filename:    "document.docx",
+			expectError: true,
+		},
+		{
+			scenario: "DOCX",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"word/fontTable.xml", `<w:fonts><w:font w:name="Noto Sans Arabic"/><w:font w:name="Times New Roman"/></w:fonts>`},
+			},
+			expectFallback: []string{"DejaVu Sans"},
+		},
+		{
+			scenario: "ODT",
+			filename: "document.odt",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.text"},
+				{"content.xml", `<office:font-face-decls><style:font-face style:name="DejaVu Sans1" svg:font-family="&apos;DejaVu Sans&apos;"/></office:font-face-decls>`},
+			},
+			expectFallback: []string{"NotoSansArabic-Bold", "TimesNewRomanPSMT"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, tc.filename)
+			pdfPath := filepath.Join(dirPath, "document.pdf")
+
+			if tc.entries == nil {
+				err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("write file: %v", err)
+				}
+			} else {
+				writeTestWorkbook(t, inputPath, tc.entries)
+			}
+
+			err := os.WriteFile(pdfPath, pdf, 0o600)
+			if err != nil {
+				t.Fatalf("write PDF: %v", err)
+			}
+
+			fallback, err := fallbackFonts(inputPath, pdfPath)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(fallback, tc.expectFallback) {
+				t.Errorf("expected %v but got %v", tc.expectFallback, fallback)
+			}
+		})
+	}
+}
+
+func TestFontKey(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		expect string
+	}{
+		{name: "Noto Sans Arabic", expect: "notosansarabic"},
+		{name: "NotoSansArabic-Bold", expect: "notosansarabic"},
+		{name: "'DejaVu Sans'", expect: "dejavusans"},
+		{name: "Arial,Bold", expect: "arial"},
+		{name: "TimesNewRomanPSMT", expect: "timesnewroman"},
+		{name: "Arial MT", expect: "arial"},
+	} {
+		actual := fontKey(tc.name)
+		if actual != tc.expect {
+			t.Errorf("expected '%s' for '%s' but got '%s'", tc.expect, tc.name, actual)
+		}
+	}
+}
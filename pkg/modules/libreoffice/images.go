@@ -0,0 +1,49 @@
+package libreoffice
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pdfengines"
+)
+
+// slideImageFormats are the values of the "slideImageFormat" form field, and
+// their image formats.
+var slideImageFormats = map[string]string{
+	"jpg":  gotenberg.ImageFormatJpeg,
+	"png":  gotenberg.ImageFormatPng,
+	"webp": gotenberg.ImageFormatWebp,
+	"tiff": gotenberg.ImageFormatTiff,
+}
+
+// addImages renders the pages of the PDFs to images of the given format, and
+// adds the images, instead of the PDFs, to the output paths. The images are
+// named after their PDF and their page number, e.g., "slides.pptx-1.png".
+func addImages(ctx *api.Context, imageEngine gotenberg.ImageEngine, format string, pdfPaths ...string) error {
+	options := gotenberg.ImageOptions{
+		Format:     format,
+		Resolution: 150,
+		Quality:    90,
+	}
+
+	var outputPaths []string
+
+	for _, pdfPath := range pdfPaths {
+		paths, err := pdfengines.RenderImages(ctx, imageEngine, options, pdfPath, strings.TrimSuffix(filepath.Base(pdfPath), ".pdf"))
+		if err != nil {
+			return err
+		}
+
+		outputPaths = append(outputPaths, paths...)
+	}
+
+	err := ctx.AddOutputPaths(outputPaths...)
+	if err != nil {
+		return fmt.Errorf("add output paths: %w", err)
+	}
+
+	return nil
+}
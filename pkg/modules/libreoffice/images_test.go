@@ -0,0 +1,99 @@
+package libreoffice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// renderSlides returns an image engine which renders two pages to images of
+// the requested format, or which fails with the given error.
+func renderSlides(renderErr error) gotenberg.ImageEngine {
+	return &gotenberg.ImageEngineMock{
+		RenderMock: func(ctx context.Context, logger *zap.Logger, options gotenberg.ImageOptions, inputPath, outputDirPath string) ([]string, error) {
+			if renderErr != nil {
+				return nil, renderErr
+			}
+
+			paths := make([]string, 2)
+
+			for i := range paths {
+				paths[i] = filepath.Join(outputDirPath, fmt.Sprintf("page-%d.%s", i+1, options.Format))
+
+				err := os.WriteFile(paths[i], []byte("foo"), 0o600)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			return paths, nil
+		},
+	}
+}
+
+func TestAddImages(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		imageEngine     gotenberg.ImageEngine
+		format          string
+		pdfPaths        []string
+		expectError     bool
+		expectedError   error
+		expectFilenames []string
+	}{
+		{
+			scenario:      "ErrImageFormatNotSupported",
+			imageEngine:   renderSlides(gotenberg.ErrImageFormatNotSupported),
+			format:        gotenberg.ImageFormatWebp,
+			pdfPaths:      []string{"/slides.pptx.pdf"},
+			expectError:   true,
+			expectedError: gotenberg.ErrImageFormatNotSupported,
+		},
+		{
+			scenario:        "success",
+			imageEngine:     renderSlides(nil),
+			format:          gotenberg.ImageFormatPng,
+			pdfPaths:        []string{"/slides.pptx.pdf", "/document.odp.pdf"},
+			expectError:     false,
+			expectFilenames: []string{"slides.pptx-1.png", "slides.pptx-2.png", "document.odp-1.png", "document.odp-2.png"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(t.TempDir())
+			ctx.SetLogger(zap.NewNop())
+
+			err := addImages(ctx.Context, tc.imageEngine, tc.format, tc.pdfPaths...)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			var filenames []string
+			for _, path := range ctx.OutputPaths() {
+				filenames = append(filenames, filepath.Base(path))
+			}
+
+			if !slices.Equal(filenames, tc.expectFilenames) {
+				t.Errorf("expected filenames %v but got %v", tc.expectFilenames, filenames)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	odpPageRegexp = regexp.MustCompile(`<draw:page[\s>]`)
+
+	// infoExtensions are the extensions of the documents which properties
+	// may be read without LibreOffice.
+	infoExtensions = []string{".docx", ".docm", ".dotx", ".xlsx", ".xlsm", ".pptx", ".pptm", ".ppsx", ".odt", ".ods", ".odp", ".odg"}
+)
+
+// documentInfo holds the properties of a document. The dates are as the
+// document states them, usually in the ISO 8601 format. A zero count means
+// either that the document does not state it or that it does not apply to
+// the document.
+type documentInfo struct {
+	Title           string   `json:"title,omitempty"`
+	Subject         string   `json:"subject,omitempty"`
+	Author          string   `json:"author,omitempty"`
+	LastModifiedBy  string   `json:"lastModifiedBy,omitempty"`
+	Created         string   `json:"created,omitempty"`
+	Modified        string   `json:"modified,omitempty"`
+	Pages           int      `json:"pages,omitempty"`
+	Words           int      `json:"words,omitempty"`
+	Characters      int      `json:"characters,omitempty"`
+	Slides          int      `json:"slides,omitempty"`
+	Sheets          int      `json:"sheets,omitempty"`
+	EmbeddedObjects []string `json:"embeddedObjects"`
+}
+
+// ooxmlCoreProperties is the docProps/core.xml entry of an Office Open XML
+// archive.
+type ooxmlCoreProperties struct {
+	Title          string `xml:"title"`
+	Subject        string `xml:"subject"`
+	Creator        string `xml:"creator"`
+	LastModifiedBy string `xml:"lastModifiedBy"`
+	Created        string `xml:"created"`
+	Modified       string `xml:"modified"`
+}
+
+// ooxmlAppProperties is the docProps/app.xml entry of an Office Open XML
+// archive.
+type ooxmlAppProperties struct {
+	Pages      int `xml:"Pages"`
+	Words      int `xml:"Words"`
+	Characters int `xml:"Characters"`
+	Slides     int `xml:"Slides"`
+}
+
+// odfMeta is the meta.xml entry of an OpenDocument archive.
+type odfMeta struct {
+	Title          string `xml:"meta>title"`
+	Subject        string `xml:"meta>subject"`
+	InitialCreator string `xml:"meta>initial-creator"`
+	Creator        string `xml:"meta>creator"`
+	CreationDate   string `xml:"meta>creation-date"`
+	Date           string `xml:"meta>date"`
+	Statistic      struct {
+		PageCount      int `xml:"page-count,attr"`
+		WordCount      int `xml:"word-count,attr"`
+		CharacterCount int `xml:"character-count,attr"`
+	} `xml:"meta>document-statistic"`
+}
+
+// readDocumentInfo reads the properties of an Office Open XML or OpenDocument
+// file from its archive.
+func readDocumentInfo(inputPath string) (documentInfo, error) {
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return documentInfo{}, fmt.Errorf("open document: %w", err)
+	}
+	defer reader.Close()
+
+	if strings.HasPrefix(strings.ToLower(filepath.Ext(inputPath)), ".od") {
+		return readOdfInfo(reader.File)
+	}
+
+	return readOoxmlInfo(reader.File)
+}
+
+// readOoxmlInfo reads the properties of an Office Open XML archive. The
+// embedded objects are the entries of the embeddings directories, e.g.,
+// word/embeddings.
+func readOoxmlInfo(files []*zip.File) (documentInfo, error) {
+	info := documentInfo{EmbeddedObjects: []string{}}
+
+	for _, f := range files {
+		switch {
+		case f.Name == "docProps/core.xml":
+			var core ooxmlCoreProperties
+
+			err := unmarshalZipFile(f, &core)
+			if err != nil {
+				return documentInfo{}, err
+			}
+
+			info.Title = core.Title
+			info.Subject = core.Subject
+			info.Author = core.Creator
+			info.LastModifiedBy = core.LastModifiedBy
+			info.Created = core.Created
+			info.Modified = core.Modified
+		case f.Name == "docProps/app.xml":
+			var app ooxmlAppProperties
+
+			err := unmarshalZipFile(f, &app)
+			if err != nil {
+				return documentInfo{}, err
+			}
+
+			info.Pages = app.Pages
+			info.Words = app.Words
+			info.Characters = app.Characters
+			info.Slides = app.Slides
+		case f.Name == xlsxWorkbookEntry:
+			content, err := readZipFile(f)
+			if err != nil {
+				return documentInfo{}, fmt.Errorf("read '%s': %w", f.Name, err)
+			}
+
+			info.Sheets = len(xlsxSheetRegexp.FindAllString(string(content), -1))
+		case path.Base(path.Dir(f.Name)) == "embeddings" && !f.FileInfo().IsDir():
+			info.EmbeddedObjects = append(info.EmbeddedObjects, path.Base(f.Name))
+		}
+	}
+
+	return info, nil
+}
+
+// readOdfInfo reads the properties of an OpenDocument archive. The embedded
+// objects are the "Object N" directories, e.g., a chart.
+func readOdfInfo(files []*zip.File) (documentInfo, error) {
+	info := documentInfo{EmbeddedObjects: []string{}}
+	objects := make(map[string]bool)
+
+	for _, f := range files {
+		switch {
+		case f.Name == "meta.xml":
+			var meta odfMeta
+
+			err := unmarshalZipFile(f, &meta)
+			if err != nil {
+				return documentInfo{}, err
+			}
+
+			info.Title = meta.Title
+			info.Subject = meta.Subject
+			info.Author = meta.InitialCreator
+			info.LastModifiedBy = meta.Creator
+			info.Created = meta.CreationDate
+			info.Modified = meta.Date
+			info.Pages = meta.Statistic.PageCount
+			info.Words = meta.Statistic.WordCount
+			info.Characters = meta.Statistic.CharacterCount
+		case f.Name == odsContentEntry:
+			content, err := readZipFile(f)
+			if err != nil {
+				return documentInfo{}, fmt.Errorf("read '%s': %w", f.Name, err)
+			}
+
+			// Text documents may have tables too, which are not sheets.
+			if strings.Contains(string(content), "<office:spreadsheet") {
+				info.Sheets = len(odsTableRegexp.FindAllString(string(content), -1))
+			}
+
+			if strings.Contains(string(content), "<office:presentation") {
+				info.Slides = len(odpPageRegexp.FindAllString(string(content), -1))
+			}
+		default:
+			name, _, ok := strings.Cut(f.Name, "/")
+			if ok && strings.HasPrefix(name, "Object ") && !objects[name] {
+				objects[name] = true
+				info.EmbeddedObjects = append(info.EmbeddedObjects, name)
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// unmarshalZipFile decodes the XML content of an archive entry.
+func unmarshalZipFile(f *zip.File, v any) error {
+	content, err := readZipFile(f)
+	if err != nil {
+		return fmt.Errorf("read '%s': %w", f.Name, err)
+	}
+
+	err = xml.Unmarshal(content, v)
+	if err != nil {
+		return fmt.Errorf("unmarshal '%s': %w", f.Name, err)
+	}
+
+	return nil
+}
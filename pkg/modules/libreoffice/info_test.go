@@ -0,0 +1,184 @@
+package libreoffice
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const (
+	testDocxCore = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><dc:title>Report</dc:title><dc:subject>Sales</dc:subject><dc:creator>Jane</dc:creator><cp:lastModifiedBy>John</cp:lastModifiedBy><dcterms:created xsi:type="dcterms:W3CDTF">2024-01-02T03:04:05Z</dcterms:created><dcterms:modified xsi:type="dcterms:W3CDTF">2024-02-03T04:05:06Z</dcterms:modified></cp:coreProperties>`
+
+	testDocxApp = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Pages>3</Pages><Words>250</Words><Characters>1400</Characters></Properties>`
+
+	testPptxApp = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Properties xmlns="http://schemas.openxmlformats.org/officeDocument/2006/extended-properties"><Words>40</Words><Slides>12</Slides></Properties>`
+
+	testOdtMeta = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-meta xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:meta="urn:oasis:names:tc:opendocument:xmlns:meta:1.0"><office:meta><dc:title>Report</dc:title><meta:initial-creator>Jane</meta:initial-creator><dc:creator>John</dc:creator><meta:creation-date>2024-01-02T03:04:05</meta:creation-date><dc:date>2024-02-03T04:05:06</dc:date><meta:document-statistic meta:table-count="1" meta:page-count="2" meta:word-count="120" meta:character-count="700"/></office:meta></office:document-meta>`
+
+	testOdtContent = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"><office:body><office:text><table:table table:name="Table1"><table:table-row/></table:table></office:text></office:body></office:document-content>`
+
+	testOdpContent = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"><office:body><office:presentation><draw:page draw:name="page1"/><draw:page draw:name="page2"><draw:page-thumbnail/></draw:page></office:presentation></office:body></office:document-content>`
+)
+
+func TestReadDocumentInfo(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		filename    string
+		entries     [][2]string
+		expectError bool
+		expectInfo  documentInfo
+	}{
+		{
+			scenario: "DOCX",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"docProps/core.xml", testDocxCore},
+				{"docProps/app.xml", testDocxApp},
+				{"word/document.xml", "<w:document/>"},
+				{"word/embeddings/oleObject1.bin", "foo"},
+				{"word/embeddings/Microsoft_Excel_Worksheet.xlsx", "foo"},
+			},
+			expectInfo: documentInfo{
+				Title:           "Report",
+				Subject:         "Sales",
+				Author:          "Jane",
+				LastModifiedBy:  "John",
+				Created:         "2024-01-02T03:04:05Z",
+				Modified:        "2024-02-03T04:05:06Z",
+				Pages:           3,
+				Words:           250,
+				Characters:      1400,
+				EmbeddedObjects: []string{"oleObject1.bin", "Microsoft_Excel_Worksheet.xlsx"},
+			},
+		},
+		{
+			scenario: "XLSX",
+			filename: "workbook.xlsx",
+			entries: [][2]string{
+				{"docProps/core.xml", testDocxCore},
+				{"xl/workbook.xml", testXlsxWorkbook},
+			},
+			expectInfo: documentInfo{
+				Title:           "Report",
+				Subject:         "Sales",
+				Author:          "Jane",
+				LastModifiedBy:  "John",
+				Created:         "2024-01-02T03:04:05Z",
+				Modified:        "2024-02-03T04:05:06Z",
+				Sheets:          3,
+				EmbeddedObjects: []string{},
+			},
+		},
+		{
+			scenario: "PPTX without core properties",
+			filename: "slides.pptx",
+			entries: [][2]string{
+				{"docProps/app.xml", testPptxApp},
+			},
+			expectInfo: documentInfo{
+				Words:           40,
+				Slides:          12,
+				EmbeddedObjects: []string{},
+			},
+		},
+		{
+			scenario: "invalid core properties",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"docProps/core.xml", "<cp:coreProperties>"},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "ODT",
+			filename: "document.odt",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.text"},
+				{"content.xml", testOdtContent},
+				{"meta.xml", testOdtMeta},
+				{"Object 1/content.xml", "<office:document-content/>"},
+				{"Object 1/styles.xml", "<office:document-styles/>"},
+				{"ObjectReplacements/Object 1", "foo"},
+			},
+			expectInfo: documentInfo{
+				Title:           "Report",
+				Author:          "Jane",
+				LastModifiedBy:  "John",
+				Created:         "2024-01-02T03:04:05",
+				Modified:        "2024-02-03T04:05:06",
+				Pages:           2,
+				Words:           120,
+				Characters:      700,
+				EmbeddedObjects: []string{"Object 1"},
+			},
+		},
+		{
+			scenario: "ODS",
+			filename: "workbook.ods",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"},
+				{"content.xml", testOdsContent},
+			},
+			expectInfo: documentInfo{
+				Sheets:          3,
+				EmbeddedObjects: []string{},
+			},
+		},
+		{
+			scenario: "ODP",
+			filename: "slides.odp",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.presentation"},
+				{"content.xml", testOdpContent},
+			},
+			expectInfo: documentInfo{
+				Slides:          2,
+				EmbeddedObjects: []string{},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			inputPath := filepath.Join(t.TempDir(), tc.filename)
+			writeTestWorkbook(t, inputPath, tc.entries)
+
+			info, err := readDocumentInfo(inputPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if !reflect.DeepEqual(info, tc.expectInfo) {
+				t.Errorf("expected %+v but got %+v", tc.expectInfo, info)
+			}
+		})
+	}
+
+	t.Run("not an archive", func(t *testing.T) {
+		inputPath := filepath.Join(t.TempDir(), "document.docx")
+
+		err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		_, err = readDocumentInfo(inputPath)
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+}
@@ -1,7 +1,9 @@
 package libreoffice
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	flag "github.com/spf13/pflag"
 
@@ -17,9 +19,13 @@ func init() {
 // LibreOffice is a module which provides a route for converting documents to
 // PDF with LibreOffice.
 type LibreOffice struct {
-	api           libeofficeapi.Uno
-	engine        gotenberg.PdfEngine
-	disableRoutes bool
+	api                 libeofficeapi.Uno
+	engine              gotenberg.PdfEngine
+	imageEngine         gotenberg.ImageEngine
+	parallelConversions int
+	pdftohtmlBinPath    string
+	ghostscriptBinPath  string
+	disableRoutes       bool
 }
 
 // Descriptor returns a [LibreOffice]'s module descriptor.
@@ -28,6 +34,7 @@ func (mod *LibreOffice) Descriptor() gotenberg.ModuleDescriptor {
 		ID: "libreoffice",
 		FlagSet: func() *flag.FlagSet {
 			fs := flag.NewFlagSet("libreoffice", flag.ExitOnError)
+			fs.Int("libreoffice-parallel-conversions", 1, "Maximum number of documents of a same request converted concurrently - usually the number of LibreOffice workers")
 			fs.Bool("libreoffice-disable-routes", false, "Disable the routes")
 
 			return fs
@@ -39,6 +46,7 @@ func (mod *LibreOffice) Descriptor() gotenberg.ModuleDescriptor {
 // Provision sets the module properties.
 func (mod *LibreOffice) Provision(ctx *gotenberg.Context) error {
 	flags := ctx.ParsedFlags()
+	mod.parallelConversions = flags.MustInt("libreoffice-parallel-conversions")
 	mod.disableRoutes = flags.MustBool("libreoffice-disable-routes")
 
 	provider, err := ctx.Module(new(libeofficeapi.Provider))
@@ -65,6 +73,43 @@ func (mod *LibreOffice) Provision(ctx *gotenberg.Context) error {
 
 	mod.engine = engine
 
+	provider, err = ctx.Module(new(gotenberg.ImageEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get image engine provider: %w", err)
+	}
+
+	// Optional, for removing the blank pages.
+	mod.imageEngine, _ = provider.(gotenberg.ImageEngineProvider).ImageEngine()
+
+	// Optional, for importing PDFs as flowing text.
+	mod.pdftohtmlBinPath, _ = os.LookupEnv("PDFTOHTML_BIN_PATH")
+
+	// Optional, for fitting the PDFs to a size budget.
+	mod.ghostscriptBinPath, _ = os.LookupEnv("GHOSTSCRIPT_BIN_PATH")
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *LibreOffice) Validate() error {
+	if mod.parallelConversions < 1 {
+		return errors.New("parallel conversions must be at least 1")
+	}
+
+	if mod.pdftohtmlBinPath != "" {
+		_, err := os.Stat(mod.pdftohtmlBinPath)
+		if err != nil {
+			return fmt.Errorf("pdftohtml binary path does not exist: %w", err)
+		}
+	}
+
+	if mod.ghostscriptBinPath != "" {
+		_, err := os.Stat(mod.ghostscriptBinPath)
+		if err != nil {
+			return fmt.Errorf("ghostscript binary path does not exist: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -75,7 +120,9 @@ func (mod *LibreOffice) Routes() ([]api.Route, error) {
 	}
 
 	return []api.Route{
-		convertRoute(mod.api, mod.engine),
+		convertRoute(mod.api, mod.engine, mod.parallelConversions, mod.imageEngine, mod.ghostscriptBinPath),
+		importRoute(mod.api, mod.pdftohtmlBinPath, mod.parallelConversions),
+		infoRoute(),
 	}, nil
 }
 
@@ -83,5 +130,6 @@ func (mod *LibreOffice) Routes() ([]api.Route, error) {
 var (
 	_ gotenberg.Module      = (*LibreOffice)(nil)
 	_ gotenberg.Provisioner = (*LibreOffice)(nil)
+	_ gotenberg.Validator   = (*LibreOffice)(nil)
 	_ api.Router            = (*LibreOffice)(nil)
 )
@@ -2,6 +2,7 @@ package libreoffice
 
 import (
 	"errors"
+	"os"
 	"reflect"
 	"testing"
 
@@ -117,6 +118,68 @@ func TestLibreOffice_Provision(t *testing.T) {
 			}(),
 			expectError: true,
 		},
+		{
+			scenario: "no image engine provider",
+			ctx: func() *gotenberg.Context {
+				mod := &struct {
+					gotenberg.ModuleMock
+					libreofficeapi.ProviderMock
+					gotenberg.PdfEngineProviderMock
+				}{}
+				mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "bar", New: func() gotenberg.Module { return mod }}
+				}
+				mod.LibreOfficeMock = func() (libreofficeapi.Uno, error) {
+					return new(libreofficeapi.ApiMock), nil
+				}
+				mod.PdfEngineMock = func() (gotenberg.PdfEngine, error) {
+					return new(gotenberg.PdfEngineMock), nil
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(LibreOffice).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{
+						mod.Descriptor(),
+					},
+				)
+			}(),
+			expectError: true,
+		},
+		{
+			scenario: "provision success without image engine",
+			ctx: func() *gotenberg.Context {
+				mod := &struct {
+					gotenberg.ModuleMock
+					libreofficeapi.ProviderMock
+					gotenberg.PdfEngineProviderMock
+					gotenberg.ImageEngineProviderMock
+				}{}
+				mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "bar", New: func() gotenberg.Module { return mod }}
+				}
+				mod.LibreOfficeMock = func() (libreofficeapi.Uno, error) {
+					return new(libreofficeapi.ApiMock), nil
+				}
+				mod.PdfEngineMock = func() (gotenberg.PdfEngine, error) {
+					return new(gotenberg.PdfEngineMock), nil
+				}
+				mod.ImageEngineMock = func() (gotenberg.ImageEngine, error) {
+					return nil, errors.New("foo")
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(LibreOffice).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{
+						mod.Descriptor(),
+					},
+				)
+			}(),
+			expectError: false,
+		},
 		{
 			scenario: "provision success",
 			ctx: func() *gotenberg.Context {
@@ -124,6 +187,7 @@ func TestLibreOffice_Provision(t *testing.T) {
 					gotenberg.ModuleMock
 					libreofficeapi.ProviderMock
 					gotenberg.PdfEngineProviderMock
+					gotenberg.ImageEngineProviderMock
 				}{}
 				mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
 					return gotenberg.ModuleDescriptor{ID: "bar", New: func() gotenberg.Module { return mod }}
@@ -134,6 +198,9 @@ func TestLibreOffice_Provision(t *testing.T) {
 				mod.PdfEngineMock = func() (gotenberg.PdfEngine, error) {
 					return new(gotenberg.PdfEngineMock), nil
 				}
+				mod.ImageEngineMock = func() (gotenberg.ImageEngine, error) {
+					return new(gotenberg.ImageEngineMock), nil
+				}
 
 				return gotenberg.NewContext(
 					gotenberg.ParsedFlags{
@@ -162,6 +229,53 @@ func TestLibreOffice_Provision(t *testing.T) {
 	}
 }
 
+func TestLibreOffice_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario            string
+		parallelConversions int
+		pdftohtmlBinPath    string
+		expectError         bool
+	}{
+		{
+			scenario:            "invalid parallel conversions",
+			parallelConversions: 0,
+			expectError:         true,
+		},
+		{
+			scenario:            "non-existing pdftohtml binary path",
+			parallelConversions: 1,
+			pdftohtmlBinPath:    "/foo",
+			expectError:         true,
+		},
+		{
+			scenario:            "validate success",
+			parallelConversions: 1,
+			expectError:         false,
+		},
+		{
+			scenario:            "validate success with pdftohtml",
+			parallelConversions: 1,
+			pdftohtmlBinPath:    os.Args[0],
+			expectError:         false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(LibreOffice)
+			mod.parallelConversions = tc.parallelConversions
+			mod.pdftohtmlBinPath = tc.pdftohtmlBinPath
+			err := mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
 func TestLibreOffice_Routes(t *testing.T) {
 	for _, tc := range []struct {
 		scenario      string
@@ -170,7 +284,7 @@ func TestLibreOffice_Routes(t *testing.T) {
 	}{
 		{
 			scenario:      "routes not disabled",
-			expectRoutes:  1,
+			expectRoutes:  3,
 			disableRoutes: false,
 		},
 		{
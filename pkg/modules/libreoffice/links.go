@@ -0,0 +1,205 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	docxRelationshipRegexp    = regexp.MustCompile(`<Relationship\s[^>]*>`)
+	docxHyperlinkRegexp       = regexp.MustCompile(`<w:hyperlink\s[^>]*>`)
+	docxRelationshipIdRegexp  = regexp.MustCompile(`\sr:id="([^"]*)"`)
+	docxHyperlinkAnchorRegexp = regexp.MustCompile(`\sw:anchor="`)
+	docxInstrTextRegexp       = regexp.MustCompile(`(?s)<w:instrText(?:\s[^>]*)?>(.*?)</w:instrText>`)
+	docxHyperlinkFieldRegexp  = regexp.MustCompile(`HYPERLINK\s+(\\l\s+)?"([^"]*)"`)
+	docxReferenceFieldRegexp  = regexp.MustCompile(`(?:REF|PAGEREF|NOTEREF)\s+\S+[^"]*\\h`)
+	odfLinkRegexp             = regexp.MustCompile(`<text:a\s[^>]*xlink:href="([^"]*)"`)
+	odfReferenceRegexp        = regexp.MustCompile(`<text:(?:bookmark-ref|reference-ref|sequence-ref|note-ref)\s`)
+	pdfUriRegexp              = regexp.MustCompile(`/URI\s*\(((?:\\.|[^\\)])*)\)`)
+	pdfLinkAnnotationRegexp   = regexp.MustCompile(`/Subtype\s*/Link\b`)
+	pdfLiteralEscapeRegexp    = regexp.MustCompile(`\\(.)`)
+)
+
+// documentLinks gathers the links of a document.
+type documentLinks struct {
+	// external are the URLs of the external hyperlinks.
+	external []string
+
+	// internal is the number of links to a location within the document,
+	// e.g., to a bookmark, or of cross-references.
+	internal int
+}
+
+// droppedLinks returns a description of the links of a DOCX or ODT document
+// which its PDF does not have anymore, if any. It returns nothing if the
+// format of the document is not supported.
+func droppedLinks(inputPath, pdfPath string) ([]string, error) {
+	links, ok, err := readDocumentLinks(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("read document links: %w", err)
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	pdfLinks, err := readPdfLinks(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("read PDF links: %w", err)
+	}
+
+	uris := make(map[string]bool, len(pdfLinks.external))
+	for _, uri := range pdfLinks.external {
+		uris[uri] = true
+	}
+
+	var dropped []string
+	for _, url := range links.external {
+		if !uris[url] {
+			dropped = append(dropped, fmt.Sprintf("hyperlink to '%s' dropped", url))
+		}
+	}
+
+	if links.internal > pdfLinks.internal {
+		dropped = append(dropped, fmt.Sprintf("%d internal link(s) or cross-reference(s) dropped", links.internal-pdfLinks.internal))
+	}
+
+	return dropped, nil
+}
+
+// readDocumentLinks reads the links of a DOCX or ODT document. It returns
+// false if the format is not supported.
+func readDocumentLinks(inputPath string) (documentLinks, bool, error) {
+	ext := strings.ToLower(filepath.Ext(inputPath))
+	if ext != ".docx" && ext != ".odt" {
+		return documentLinks{}, false, nil
+	}
+
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return documentLinks{}, false, fmt.Errorf("open document: %w", err)
+	}
+	defer reader.Close()
+
+	contents := make(map[string]string)
+	for _, f := range reader.File {
+		switch f.Name {
+		case "word/document.xml", "word/_rels/document.xml.rels", "content.xml":
+			content, err := readZipFile(f)
+			if err != nil {
+				return documentLinks{}, false, fmt.Errorf("read '%s': %w", f.Name, err)
+			}
+
+			contents[f.Name] = string(content)
+		}
+	}
+
+	if ext == ".odt" {
+		return odtLinks(contents["content.xml"]), true, nil
+	}
+
+	return docxLinks(contents["word/document.xml"], contents["word/_rels/document.xml.rels"]), true, nil
+}
+
+// docxLinks returns the links of the main part of a DOCX document, either
+// hyperlinks or fields.
+func docxLinks(document, relationships string) documentLinks {
+	targets := make(map[string]string)
+	for _, tag := range docxRelationshipRegexp.FindAllString(relationships, -1) {
+		if attribute(tag, "TargetMode") != "External" || !strings.HasSuffix(attribute(tag, "Type"), "/hyperlink") {
+			continue
+		}
+
+		targets[attribute(tag, "Id")] = attribute(tag, "Target")
+	}
+
+	var links documentLinks
+	for _, tag := range docxHyperlinkRegexp.FindAllString(document, -1) {
+		if docxHyperlinkAnchorRegexp.MatchString(tag) {
+			links.internal++
+			continue
+		}
+
+		match := docxRelationshipIdRegexp.FindStringSubmatch(tag)
+		if match == nil {
+			continue
+		}
+
+		target, ok := targets[match[1]]
+		if ok {
+			links.external = appendUnique(links.external, target)
+		}
+	}
+
+	for _, match := range docxInstrTextRegexp.FindAllStringSubmatch(document, -1) {
+		instr := html.UnescapeString(match[1])
+
+		field := docxHyperlinkFieldRegexp.FindStringSubmatch(instr)
+		switch {
+		case field != nil && field[1] != "":
+			links.internal++
+		case field != nil:
+			links.external = appendUnique(links.external, field[2])
+		case docxReferenceFieldRegexp.MatchString(instr):
+			links.internal++
+		}
+	}
+
+	return links
+}
+
+// odtLinks returns the links of an ODT document.
+func odtLinks(content string) documentLinks {
+	var links documentLinks
+	for _, match := range odfLinkRegexp.FindAllStringSubmatch(content, -1) {
+		href := html.UnescapeString(match[1])
+		if strings.HasPrefix(href, "#") {
+			links.internal++
+			continue
+		}
+
+		links.external = appendUnique(links.external, href)
+	}
+
+	links.internal += len(odfReferenceRegexp.FindAllString(content, -1))
+
+	return links
+}
+
+// readPdfLinks reads the links of a PDF converted by LibreOffice. As for the
+// fonts, LibreOffice does not write the annotations in compressed object
+// streams.
+func readPdfLinks(pdfPath string) (documentLinks, error) {
+	b, err := os.ReadFile(pdfPath)
+	if err != nil {
+		return documentLinks{}, fmt.Errorf("read PDF: %w", err)
+	}
+
+	var links documentLinks
+	for _, match := range pdfUriRegexp.FindAllSubmatch(b, -1) {
+		links.external = appendUnique(links.external, pdfLiteralEscapeRegexp.ReplaceAllString(string(match[1]), "$1"))
+	}
+
+	// Each link annotation is either external or internal.
+	links.internal = len(pdfLinkAnnotationRegexp.FindAll(b, -1)) - len(pdfUriRegexp.FindAll(b, -1))
+	if links.internal < 0 {
+		links.internal = 0
+	}
+
+	return links, nil
+}
+
+// appendUnique appends a value to a slice if it does not contain it yet.
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
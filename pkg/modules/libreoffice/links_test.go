@@ -0,0 +1,113 @@
+package libreoffice
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDroppedLinks(t *testing.T) {
+	const (
+		docxDocument = `<w:document><w:body>` +
+			`<w:hyperlink r:id="rId4"><w:r><w:t>Site</w:t></w:r></w:hyperlink>` +
+			`<w:hyperlink r:id="rId5"><w:r><w:t>Docs</w:t></w:r></w:hyperlink>` +
+			`<w:hyperlink w:anchor="_Toc1"><w:r><w:t>Intro</w:t></w:r></w:hyperlink>` +
+			`<w:r><w:instrText xml:space="preserve"> REF _Ref2 \h </w:instrText></w:r>` +
+			`<w:r><w:instrText xml:space="preserve"> HYPERLINK "https://example.org/field" </w:instrText></w:r>` +
+			`</w:body></w:document>`
+		docxRelationships = `<Relationships>` +
+			`<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.org/" TargetMode="External"/>` +
+			`<Relationship Id="rId5" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://example.org/docs?a=1&amp;b=2" TargetMode="External"/>` +
+			`<Relationship Id="rId6" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/image1.png"/>` +
+			`</Relationships>`
+		odtContent = `<office:document-content><text:p>` +
+			`<text:a xlink:type="simple" xlink:href="https://example.org/">Site</text:a>` +
+			`<text:a xlink:type="simple" xlink:href="#Intro">Intro</text:a>` +
+			`<text:bookmark-ref text:reference-format="page" text:ref-name="Intro">1</text:bookmark-ref>` +
+			`</text:p></office:document-content>`
+	)
+
+	for _, tc := range []struct {
+		scenario      string
+		filename      string
+		entries       [][2]string
+		pdf           string
+		expectDropped []string
+		expectError   bool
+	}{
+		{
+			scenario:      "unsupported format",
+			filename:      "document.doc",
+			expectDropped: nil,
+		},
+		{
+			scenario:    "invalid document",
+			filename:    "document.docx",
+			expectError: true,
+		},
+		{
+			scenario: "DOCX without dropped links",
+			filename: "document.docx",
+			entries:  [][2]string{{"word/document.xml", docxDocument}, {"word/_rels/document.xml.rels", docxRelationships}},
+			pdf: "<</Type/Annot/Subtype/Link/A<</S/URI/URI(https://example.org/)>>>>\n" +
+				"<</Type/Annot/Subtype/Link/A<</S/URI/URI(https://example.org/docs?a=1&b=2)>>>>\n" +
+				"<</Type/Annot/Subtype/Link/A<</S/URI/URI(https://example.org/field)>>>>\n" +
+				"<</Type/Annot/Subtype/Link/Dest/Intro>>\n" +
+				"<</Type/Annot/Subtype/Link/Dest/Ref2>>\n",
+			expectDropped: nil,
+		},
+		{
+			scenario: "DOCX with dropped links",
+			filename: "document.docx",
+			entries:  [][2]string{{"word/document.xml", docxDocument}, {"word/_rels/document.xml.rels", docxRelationships}},
+			pdf:      "<</Type/Annot/Subtype/Link/A<</S/URI/URI(https://example.org/)>>>>\n",
+			expectDropped: []string{
+				"hyperlink to 'https://example.org/docs?a=1&b=2' dropped",
+				"hyperlink to 'https://example.org/field' dropped",
+				"2 internal link(s) or cross-reference(s) dropped",
+			},
+		},
+		{
+			scenario:      "ODT with dropped links",
+			filename:      "document.odt",
+			entries:       [][2]string{{"mimetype", "application/vnd.oasis.opendocument.text"}, {"content.xml", odtContent}},
+			pdf:           "<</Type/Annot/Subtype/Link/A<</S/URI/URI(https://example.org/)>>>>\n<</Type/Annot/Subtype/Link/Dest/Intro>>\n",
+			expectDropped: []string{"1 internal link(s) or cross-reference(s) dropped"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, tc.filename)
+			pdfPath := filepath.Join(dirPath, "document.pdf")
+
+			if tc.entries == nil {
+				err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("write file: %v", err)
+				}
+			} else {
+				writeTestWorkbook(t, inputPath, tc.entries)
+			}
+
+			err := os.WriteFile(pdfPath, []byte("%PDF-1.7\n"+tc.pdf), 0o600)
+			if err != nil {
+				t.Fatalf("write PDF: %v", err)
+			}
+
+			dropped, err := droppedLinks(inputPath, pdfPath)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(dropped, tc.expectDropped) {
+				t.Errorf("expected %v but got %v", tc.expectDropped, dropped)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	docxLangRegexp          = regexp.MustCompile(`<w:lang\s[^>]*/>`)
+	docxLangValRegexp       = regexp.MustCompile(`\sw:val="[^"]*"`)
+	docxRPrDefaultRegexp    = regexp.MustCompile(`(?s)<w:rPrDefault\s*/>|<w:rPrDefault>.*?</w:rPrDefault>`)
+	docxRPrRegexp           = regexp.MustCompile(`(?s)<w:rPr>(.*?)</w:rPr>`)
+	odfDefaultStyleRegexp   = regexp.MustCompile(`(?s)<style:default-style\s[^>]*/>|<style:default-style\s[^>]*[^/]>.*?</style:default-style>`)
+	odfTextPropertiesRegexp = regexp.MustCompile(`<style:text-properties(?:\s[^>]*)?/?>`)
+	odfLanguageRegexp       = regexp.MustCompile(`\sfo:(?:language|country|script)="[^"]*"`)
+)
+
+// parseDocumentLocale parses a BCP 47 language tag, e.g., "fr-FR".
+func parseDocumentLocale(value string) (language.Tag, error) {
+	tag, err := language.Parse(value)
+	if err != nil {
+		return language.Und, fmt.Errorf("parse language tag: %w", err)
+	}
+
+	// The base of a tag without language, e.g., "und", is a guess.
+	_, confidence := tag.Base()
+	if confidence != language.Exact {
+		return language.Und, fmt.Errorf("'%s' has no language", value)
+	}
+
+	return tag, nil
+}
+
+// isLocaleSensitiveWorkbook tells if the number formats of a workbook depend
+// on the locale of LibreOffice rather than on the workbook, as LibreOffice
+// reads the built-in formats of Excel workbooks for its own locale.
+func isLocaleSensitiveWorkbook(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xlsm", ".xls":
+		return true
+	default:
+		return false
+	}
+}
+
+// applyDocumentLocale writes a copy of a DOCX or OpenDocument file which has
+// the given locale as default language, so that LibreOffice renders its
+// numbers, dates and currencies accordingly. It returns false if the format
+// of the document does not support it.
+func applyDocumentLocale(inputPath, outputPath string, locale language.Tag) (bool, error) {
+	var (
+		entry string
+		apply func(content string, locale language.Tag) string
+	)
+
+	switch strings.ToLower(filepath.Ext(inputPath)) {
+	case ".docx":
+		entry = "word/styles.xml"
+		apply = applyDocxLocale
+	case ".odt", ".ods", ".odp":
+		entry = "styles.xml"
+		apply = applyOdfLocale
+	default:
+		return false, nil
+	}
+
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return false, fmt.Errorf("open document: %w", err)
+	}
+	defer reader.Close()
+
+	contents := make(map[string][]byte)
+	for _, f := range reader.File {
+		if f.Name != entry {
+			continue
+		}
+
+		content, err := readZipFile(f)
+		if err != nil {
+			return false, fmt.Errorf("read '%s': %w", entry, err)
+		}
+
+		contents[entry] = []byte(apply(string(content), locale))
+	}
+
+	err = rewriteArchive(reader.File, contents, outputPath)
+	if err != nil {
+		return false, fmt.Errorf("rewrite document: %w", err)
+	}
+
+	return true, nil
+}
+
+// applyDocxLocale sets the language of the default run properties.
+func applyDocxLocale(styles string, locale language.Tag) string {
+	val := fmt.Sprintf(` w:val="%s"`, locale)
+	lang := "<w:lang" + val + "/>"
+	rPrDefault := "<w:rPrDefault><w:rPr>" + lang + "</w:rPr></w:rPrDefault>"
+
+	loc := docxRPrDefaultRegexp.FindStringIndex(styles)
+	if loc != nil {
+		rPr := docxRPrRegexp.FindStringSubmatch(styles[loc[0]:loc[1]])
+		if rPr != nil {
+			// Keep the East Asian and the complex script languages.
+			existing := docxLangRegexp.FindString(rPr[1])
+			if existing != "" {
+				if docxLangValRegexp.MatchString(existing) {
+					lang = docxLangValRegexp.ReplaceAllString(existing, val)
+				} else {
+					lang = "<w:lang" + val + existing[len("<w:lang"):]
+				}
+			}
+
+			rPrDefault = "<w:rPrDefault><w:rPr>" + docxLangRegexp.ReplaceAllString(rPr[1], "") + lang + "</w:rPr></w:rPrDefault>"
+		}
+
+		return styles[:loc[0]] + rPrDefault + styles[loc[1]:]
+	}
+
+	// The run defaults come first in the document defaults, which come first
+	// in the styles.
+	switch {
+	case strings.Contains(styles, "<w:docDefaults/>"):
+		return strings.Replace(styles, "<w:docDefaults/>", "<w:docDefaults>"+rPrDefault+"</w:docDefaults>", 1)
+	case strings.Contains(styles, "<w:docDefaults>"):
+		return strings.Replace(styles, "<w:docDefaults>", "<w:docDefaults>"+rPrDefault, 1)
+	}
+
+	loc = docxStylesRegexp.FindStringIndex(styles)
+	if loc == nil {
+		return styles
+	}
+
+	return styles[:loc[1]] + "<w:docDefaults>" + rPrDefault + "</w:docDefaults>" + styles[loc[1]:]
+}
+
+// applyOdfLocale sets the language of the default styles, e.g., of the
+// paragraphs or of the cells.
+func applyOdfLocale(styles string, locale language.Tag) string {
+	base, _ := locale.Base()
+	attrs := fmt.Sprintf(` fo:language="%s"`, base)
+
+	region, confidence := locale.Region()
+	if confidence == language.Exact {
+		attrs += fmt.Sprintf(` fo:country="%s"`, region)
+	}
+
+	properties := "<style:text-properties" + attrs + "/>"
+
+	if !odfDefaultStyleRegexp.MatchString(styles) {
+		style := `<style:default-style style:family="paragraph">` + properties + `</style:default-style><style:default-style style:family="table-cell">` + properties + "</style:default-style>"
+
+		if strings.Contains(styles, "<office:styles/>") {
+			return strings.Replace(styles, "<office:styles/>", "<office:styles>"+style+"</office:styles>", 1)
+		}
+
+		return strings.Replace(styles, "<office:styles>", "<office:styles>"+style, 1)
+	}
+
+	return odfDefaultStyleRegexp.ReplaceAllStringFunc(styles, func(style string) string {
+		if strings.HasSuffix(style, "/>") && !strings.Contains(style, "</style:default-style>") {
+			return strings.TrimSuffix(style, "/>") + ">" + properties + "</style:default-style>"
+		}
+
+		loc := odfTextPropertiesRegexp.FindStringIndex(style)
+		if loc == nil {
+			return strings.TrimSuffix(style, "</style:default-style>") + properties + "</style:default-style>"
+		}
+
+		tag := odfLanguageRegexp.ReplaceAllString(style[loc[0]:loc[1]], "")
+		i := len("<style:text-properties")
+
+		return style[:loc[0]] + tag[:i] + attrs + tag[i:] + style[loc[1]:]
+	})
+}
@@ -0,0 +1,160 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestParseDocumentLocale(t *testing.T) {
+	for _, tc := range []struct {
+		value       string
+		expect      language.Tag
+		expectError bool
+	}{
+		{value: "fr-FR", expect: language.MustParse("fr-FR")},
+		{value: "de", expect: language.German},
+		{value: "und", expectError: true},
+		{value: "foo_bar!", expectError: true},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			actual, err := parseDocumentLocale(tc.value)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestApplyDocumentLocale(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		filename      string
+		entries       [][2]string
+		locale        language.Tag
+		expectOk      bool
+		expectError   bool
+		expectContent []string
+	}{
+		{
+			scenario: "unsupported format",
+			filename: "document.xlsx",
+			locale:   language.French,
+			expectOk: false,
+		},
+		{
+			scenario:    "invalid document",
+			filename:    "document.docx",
+			locale:      language.French,
+			expectError: true,
+		},
+		{
+			scenario: "DOCX",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"word/styles.xml", `<w:styles><w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri"/><w:lang w:val="en-US" w:eastAsia="zh-CN"/></w:rPr></w:rPrDefault></w:docDefaults></w:styles>`},
+			},
+			locale:        language.MustParse("fr-FR"),
+			expectOk:      true,
+			expectContent: []string{`<w:rPr><w:rFonts w:ascii="Calibri"/><w:lang w:val="fr-FR" w:eastAsia="zh-CN"/></w:rPr>`},
+		},
+		{
+			scenario: "DOCX without run defaults",
+			filename: "document.docx",
+			entries: [][2]string{
+				{"word/styles.xml", `<w:styles><w:docDefaults><w:pPrDefault/></w:docDefaults></w:styles>`},
+			},
+			locale:        language.MustParse("de-DE"),
+			expectOk:      true,
+			expectContent: []string{`<w:docDefaults><w:rPrDefault><w:rPr><w:lang w:val="de-DE"/></w:rPr></w:rPrDefault><w:pPrDefault/>`},
+		},
+		{
+			scenario: "ODS",
+			filename: "document.ods",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"},
+				{"styles.xml", `<office:styles><style:default-style style:family="table-cell"><style:text-properties fo:language="en" fo:country="US" style:font-name="Arial"/></style:default-style><style:default-style style:family="graphic"/></office:styles>`},
+			},
+			locale:   language.MustParse("fr-FR"),
+			expectOk: true,
+			expectContent: []string{
+				`<style:text-properties fo:language="fr" fo:country="FR" style:font-name="Arial"/>`,
+				`<style:default-style style:family="graphic"><style:text-properties fo:language="fr" fo:country="FR"/></style:default-style>`,
+			},
+		},
+		{
+			scenario: "ODT without default styles",
+			filename: "document.odt",
+			entries: [][2]string{
+				{"mimetype", "application/vnd.oasis.opendocument.text"},
+				{"styles.xml", `<office:document-styles><office:styles/></office:document-styles>`},
+			},
+			locale:        language.German,
+			expectOk:      true,
+			expectContent: []string{`<style:default-style style:family="paragraph"><style:text-properties fo:language="de"/></style:default-style>`},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, tc.filename)
+			outputPath := filepath.Join(dirPath, "output"+filepath.Ext(tc.filename))
+
+			if tc.entries == nil {
+				err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("write file: %v", err)
+				}
+			} else {
+				writeTestWorkbook(t, inputPath, tc.entries)
+			}
+
+			ok, err := applyDocumentLocale(inputPath, outputPath, tc.locale)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if ok != tc.expectOk {
+				t.Fatalf("expected %t but got %t", tc.expectOk, ok)
+			}
+
+			if !ok {
+				return
+			}
+
+			reader, err := zip.OpenReader(outputPath)
+			if err != nil {
+				t.Fatalf("open document: %v", err)
+			}
+			defer reader.Close()
+
+			content, err := readZipFile(reader.File[len(reader.File)-1])
+			if err != nil {
+				t.Fatalf("read styles: %v", err)
+			}
+
+			for _, expect := range tc.expectContent {
+				if !strings.Contains(string(content), expect) {
+					t.Errorf("expected '%s' in styles but got: %s", expect, content)
+				}
+			}
+		})
+	}
+}
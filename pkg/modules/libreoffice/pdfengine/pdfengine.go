@@ -52,9 +52,9 @@ func (engine *LibreOfficePdfEngine) Merge(ctx context.Context, logger *zap.Logge
 }
 
 // Convert converts the given PDF to a specific PDF format. Currently, only the
-// PDF/A-1b, PDF/A-2b, PDF/A-3b and PDF/UA formats are available. If another
-// PDF format is requested, it returns a [gotenberg.ErrPdfFormatNotSupported]
-// error.
+// PDF/A-1b, PDF/A-2b, PDF/A-3b and PDF/UA formats, and the PDF versions 1.5 to
+// 1.7 are available. If another PDF format is requested, it returns a
+// [gotenberg.ErrPdfFormatNotSupported] error.
 func (engine *LibreOfficePdfEngine) Convert(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
 	err := engine.unoApi.Pdf(ctx, logger, inputPath, outputPath, api.Options{
 		PdfFormats: formats,
@@ -71,6 +71,11 @@ func (engine *LibreOfficePdfEngine) Convert(ctx context.Context, logger *zap.Log
 	return fmt.Errorf("convert PDF to '%+v' with LibreOffice: %w", formats, err)
 }
 
+// PageCount is not available in this implementation.
+func (engine *LibreOfficePdfEngine) PageCount(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+	return 0, fmt.Errorf("count pages with LibreOffice: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module      = (*LibreOfficePdfEngine)(nil)
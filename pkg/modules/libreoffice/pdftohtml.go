@@ -0,0 +1,42 @@
+package libreoffice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// ErrInvalidPdf happens if pdftohtml cannot read a PDF.
+var ErrInvalidPdf = errors.New("invalid PDF")
+
+// pdfToHtml converts a PDF to a single HTML document with pdftohtml. The
+// lines of text are merged into paragraphs, without the images nor the
+// positions, so that LibreOffice imports the document as flowing text.
+func pdfToHtml(ctx context.Context, logger *zap.Logger, binPath, inputPath, outputPath string) error {
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath,
+		"-s",
+		"-i",
+		"-noframes",
+		"-q",
+		inputPath,
+		outputPath,
+	)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("convert PDF to HTML: %w", err)
+		}
+
+		return fmt.Errorf("convert PDF to HTML: %v: %w", err, ErrInvalidPdf)
+	}
+
+	return nil
+}
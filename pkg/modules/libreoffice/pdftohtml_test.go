@@ -0,0 +1,88 @@
+package libreoffice
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakePdftohtml writes a script which mimics pdftohtml, i.e., which writes
+// its last argument, or which fails.
+func fakePdftohtml(t *testing.T, fail bool) string {
+	script := "for last; do true; done\necho '<html><body><p>foo</p></body></html>' > \"$last\"\n"
+	if fail {
+		script = "exit 1\n"
+	}
+
+	return gotenbergtest.FakeBinary(t, "pdftohtml", script)
+}
+
+func TestPdfToHtml(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		cancelledCtx  bool
+		expectError   bool
+		expectedError error
+	}{
+		{
+			scenario:      "invalid PDF",
+			binPath:       fakePdftohtml(t, true),
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:     "context done",
+			binPath:      fakePdftohtml(t, false),
+			cancelledCtx: true,
+			expectError:  true,
+		},
+		{
+			scenario:    "success",
+			binPath:     fakePdftohtml(t, false),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.cancelledCtx {
+				cancel()
+			}
+
+			outputPath := filepath.Join(t.TempDir(), "document.html")
+
+			err := pdfToHtml(ctx, zap.NewNop(), tc.binPath, "document.pdf", outputPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if tc.cancelledCtx && errors.Is(err, ErrInvalidPdf) {
+				t.Fatalf("expected no %v error but got one", ErrInvalidPdf)
+			}
+
+			if !tc.expectError {
+				_, err = os.Stat(outputPath)
+				if err != nil {
+					t.Errorf("expected the HTML document but got: %v", err)
+				}
+			}
+		})
+	}
+}
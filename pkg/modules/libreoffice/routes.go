@@ -1,21 +1,48 @@
 package libreoffice
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/text/language"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pdfengines"
 )
 
+// conversion is a document, or a sheet of a workbook, to convert to PDF.
+type conversion struct {
+	// inputPath is the path of the document.
+	inputPath string
+
+	// sourcePath is the path of the uploaded document, e.g., the workbook of
+	// a sheet.
+	sourcePath string
+
+	// filename is the name of the resulting PDF, without extension.
+	filename string
+
+	// sheetName is the name of the sheet, if any.
+	sheetName string
+}
+
 // convertRoute returns an [api.Route] which can convert LibreOffice documents
-// to PDF.
-func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) api.Route {
+// to PDF. Up to parallelConversions documents are converted at the same time.
+// The sheets of the workbooks may be converted to separate PDFs, and the text
+// direction and the locale of the documents may be forced. If imageEngine is
+// not nil, the blank pages may be removed from the PDFs, or the pages may be
+// rendered to images instead. The PDFs may be optimized to fit a size budget
+// if ghostscriptBinPath is set.
+func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, parallelConversions int, imageEngine gotenberg.ImageEngine, ghostscriptBinPath string) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/libreoffice/convert",
@@ -25,89 +52,415 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 
 			// Let's get the data from the form and validate them.
 			var (
-				inputPaths       []string
-				landscape        bool
-				nativePageRanges string
-				pdfa             string
-				pdfua            bool
-				nativePdfFormats bool
-				merge            bool
+				inputPaths         []string
+				landscape          bool
+				nativePageRanges   string
+				pdfa               string
+				pdfua              bool
+				pdfVersion         string
+				nativePdfFormats   bool
+				merge              bool
+				splitSheets        bool
+				exportLinks        bool
+				attachSource       bool
+				textDirection      string
+				documentLocale     language.Tag
+				removeBlankPages   bool
+				blankPageThreshold float64
+				maxOutputBytes     int64
+				asImages           bool
+				slideImageFormat   = gotenberg.ImageFormatJpeg
 			)
 
-			err := ctx.FormData().
-				MandatoryPaths(libreOffice.Extensions(), &inputPaths).
+			extensions := libreOffice.Extensions()
+			if !ctx.ExtensionEnabled(api.ExtensionExtraInputFormats) {
+				extensions = slices.DeleteFunc(slices.Clone(extensions), func(ext string) bool {
+					return slices.Contains(libreofficeapi.ExtraExtensions, ext)
+				})
+			}
+
+			form := ctx.FormData().
+				MandatoryPaths(extensions, &inputPaths).
 				Bool("landscape", &landscape, false).
 				String("nativePageRanges", &nativePageRanges, "").
 				String("pdfa", &pdfa, "").
 				Bool("pdfua", &pdfua, false).
 				Bool("nativePdfFormats", &nativePdfFormats, true).
-				Bool("merge", &merge, false).
-				Validate()
+				Bool("merge", &merge, false)
+
+			if ctx.ExtensionEnabled(api.ExtensionRemoveBlankPages) {
+				form.
+					Bool("removeBlankPages", &removeBlankPages, false).
+					Custom("blankPageThreshold", func(value string) error {
+						threshold, err := pdfengines.ParseBlankPageThreshold(value)
+						if err != nil {
+							return err
+						}
+
+						blankPageThreshold = threshold
+
+						return nil
+					})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionSplitSheets) {
+				form.Bool("splitSheets", &splitSheets, false)
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionTextDirection) {
+				form.Custom("textDirection", func(value string) error {
+					if value != "" && value != textDirectionLtr && value != textDirectionRtl {
+						return fmt.Errorf("wrong value, expected either '%s' or '%s'", textDirectionLtr, textDirectionRtl)
+					}
+
+					textDirection = value
+
+					return nil
+				})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionDocumentLocale) {
+				form.Custom("documentLocale", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					locale, err := parseDocumentLocale(value)
+					if err != nil {
+						return err
+					}
+
+					documentLocale = locale
+
+					return nil
+				})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionExportLinks) {
+				form.Bool("exportLinks", &exportLinks, false)
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionAttachSource) {
+				form.Bool("attachSource", &attachSource, false)
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionAsImages) {
+				form.
+					Bool("asImages", &asImages, false).
+					Custom("slideImageFormat", func(value string) error {
+						if value == "" {
+							return nil
+						}
+
+						format, ok := slideImageFormats[value]
+						if !ok {
+							return errors.New("wrong value, expected either 'jpg', 'png', 'webp' or 'tiff'")
+						}
+
+						slideImageFormat = format
+
+						return nil
+					})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionPdfVersion) {
+				form.Custom("pdfVersion", func(value string) error {
+					version, err := pdfengines.ParsePdfVersion(value)
+					if err != nil {
+						return err
+					}
+
+					pdfVersion = version
+
+					return nil
+				})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionMaxOutputBytes) {
+				form.Custom("maxOutputBytes", func(value string) error {
+					maxBytes, err := pdfengines.ParseMaxOutputBytes(value)
+					if err != nil {
+						return err
+					}
+
+					maxOutputBytes = maxBytes
+
+					return nil
+				})
+			}
+
+			err := form.Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
+			if removeBlankPages && imageEngine == nil {
+				return api.WrapError(
+					errors.New("no image engine"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: removing blank pages is not available").WithCode(api.ErrorCodeInvalidFormData),
+				)
+			}
+
+			if asImages && imageEngine == nil {
+				return api.WrapError(
+					errors.New("no image engine"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: rendering the pages as images is not available").WithCode(api.ErrorCodeInvalidFormData),
+				)
+			}
+
+			// The images replace the PDFs, which neither carry the sources nor
+			// have a size budget.
+			if asImages && (attachSource || maxOutputBytes > 0) {
+				return api.WrapError(
+					errors.New("as images with PDF options"),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: rendering the pages as images is not compatible with 'attachSource' nor 'maxOutputBytes'").WithCode(api.ErrorCodeInvalidFormData),
+				)
+			}
+
+			// PDF/A-1b and PDF/A-2b do not allow to embed office documents.
+			if attachSource && (pdfa == gotenberg.PdfA1b || pdfa == gotenberg.PdfA2b) {
+				return api.WrapError(
+					fmt.Errorf("attach source with %s", pdfa),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: attaching the source is not compatible with %s", pdfa)).WithCode(api.ErrorCodeInvalidFormData),
+				)
+			}
+
 			pdfFormats := gotenberg.PdfFormats{
-				PdfA:  pdfa,
-				PdfUa: pdfua,
+				PdfA:       pdfa,
+				PdfUa:      pdfua,
+				PdfVersion: pdfVersion,
 			}
 
-			// Alright, let's convert each document to PDF.
-			outputPaths := make([]string, len(inputPaths))
-			for i, inputPath := range inputPaths {
-				// document.docx -> document.docx.pdf.
-				outputPaths[i] = ctx.GeneratePath(filepath.Base(inputPath), ".pdf")
-				options := libreofficeapi.Options{
-					Landscape:  landscape,
-					PageRanges: nativePageRanges,
-				}
+			err = pdfengines.ValidatePdfVersion(pdfFormats)
+			if err != nil {
+				return err
+			}
+
+			err = pdfengines.ValidateMaxOutputBytes(maxOutputBytes, ghostscriptBinPath, pdfFormats)
+			if err != nil {
+				return err
+			}
 
-				if nativePdfFormats {
-					options.PdfFormats = pdfFormats
+			// If asked, each sheet of the workbooks becomes a separate
+			// conversion, named after the sheet.
+			var conversions []conversion
+			takenFilenames := make(map[string]bool)
+			for _, inputPath := range inputPaths {
+				takenFilenames[filepath.Base(inputPath)] = true
+			}
+
+			for _, inputPath := range inputPaths {
+				if !splitSheets || !isWorkbook(inputPath) {
+					conversions = append(conversions, conversion{
+						inputPath:  inputPath,
+						sourcePath: inputPath,
+						filename:   filepath.Base(inputPath),
+					})
+
+					continue
 				}
 
-				err = libreOffice.Pdf(ctx, ctx.Log(), inputPath, outputPaths[i], options)
+				sheets, err := splitWorkbook(inputPath, func() string {
+					return ctx.GeneratePath("", filepath.Ext(inputPath))
+				})
 				if err != nil {
-					if errors.Is(err, libreofficeapi.ErrInvalidPdfFormats) {
-						return api.WrapError(
-							fmt.Errorf("convert to PDF: %w", err),
-							api.NewSentinelHttpError(
-								http.StatusBadRequest,
-								fmt.Sprintf("A PDF format in '%+v' is not supported", pdfFormats),
-							),
-						)
+					return api.WrapError(
+						fmt.Errorf("split sheets: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Cannot split the sheets of '%s'", filepath.Base(inputPath))).WithCode(api.ErrorCodeLibreofficeInvalidWorkbook),
+					)
+				}
+
+				for _, sheet := range sheets {
+					conversions = append(conversions, conversion{
+						inputPath:  sheet.inputPath,
+						sourcePath: inputPath,
+						filename:   sheetFilename(sheet.name, takenFilenames),
+						sheetName:  sheet.name,
+					})
+				}
+			}
+
+			// Alright, let's convert each document to PDF. The documents are
+			// converted concurrently, but the output paths keep the order of
+			// the input paths so that a merge respects it.
+			ctx.AddEngines("libreoffice")
+			outputPaths := make([]string, len(conversions))
+			for i, conv := range conversions {
+				// document.docx -> document.docx.pdf.
+				outputPaths[i] = ctx.GeneratePath(conv.filename, ".pdf")
+			}
+
+			options := libreofficeapi.Options{
+				Landscape:   landscape,
+				PageRanges:  nativePageRanges,
+				ExportLinks: exportLinks,
+			}
+
+			// The PDF engines apply the PDF formats LibreOffice does not
+			// export natively, or all of them if asked.
+			enginePdfFormats := pdfFormats
+			if nativePdfFormats {
+				options.PdfFormats = pdfFormats
+				enginePdfFormats = gotenberg.PdfFormats{}
+
+				if pdfVersion != "" && !libreofficeapi.SupportsPdfVersion(pdfVersion) {
+					options.PdfFormats.PdfVersion = ""
+					enginePdfFormats.PdfVersion = pdfVersion
+				}
+			}
+
+			// When merging, each PDF joins the resulting PDF as soon as it
+			// is ready, so that the intermediate PDFs do not pile up.
+			var (
+				merger     *pdfengines.IncrementalMerge
+				pageCounts []int
+			)
+
+			if len(outputPaths) > 1 && merge {
+				merger = pdfengines.NewIncrementalMerge(ctx, ctx.Log(), engine, ctx.GeneratePath("", ".pdf"), len(outputPaths))
+				pageCounts = make([]int, len(outputPaths))
+			}
+
+			eg, egCtx := errgroup.WithContext(ctx)
+			eg.SetLimit(parallelConversions)
+
+			for i, conv := range conversions {
+				i, conv := i, conv
+				eg.Go(func() error {
+					inputPath, err := prepareDocument(egCtx, ctx, libreOffice, conv, textDirection, documentLocale)
+					if err != nil {
+						return err
+					}
+
+					err = libreOffice.Pdf(egCtx, ctx.Log(), inputPath, outputPaths[i], options)
+					if err != nil {
+						return err
 					}
 
-					if errors.Is(err, libreofficeapi.ErrMalformedPageRanges) {
-						return api.WrapError(
-							fmt.Errorf("convert to PDF: %w", err),
-							api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)),
-						)
+					// Right-to-left and complex scripts often require fonts the
+					// documents declare but which are not installed.
+					if textDirection != "" {
+						fonts, err := fallbackFonts(conv.inputPath, outputPaths[i])
+						if err != nil {
+							ctx.Log().Debug(fmt.Sprintf("check fallback fonts of '%s': %s", conv.filename, err))
+						} else if len(fonts) > 0 {
+							warning := fmt.Sprintf("'%s' rendered with fallback fonts %s, some glyphs may be shaped wrongly", conv.filename, strings.Join(fonts, ", "))
+							ctx.Log().Warn(warning)
+							ctx.AddWarnings(warning)
+						}
 					}
 
-					return fmt.Errorf("convert to PDF: %w", err)
+					if exportLinks {
+						dropped, err := droppedLinks(conv.inputPath, outputPaths[i])
+						if err != nil {
+							ctx.Log().Debug(fmt.Sprintf("check links of '%s': %s", conv.filename, err))
+						}
+
+						for _, link := range dropped {
+							warning := fmt.Sprintf("'%s': %s", conv.filename, link)
+							ctx.Log().Warn(warning)
+							ctx.AddWarnings(warning)
+						}
+					}
+
+					if removeBlankPages {
+						_, err = pdfengines.RemoveBlankPages(egCtx, ctx.Log(), imageEngine, outputPaths[i], blankPageThreshold)
+						if errors.Is(err, pdfengines.ErrAllPagesBlank) {
+							return api.WrapError(
+								fmt.Errorf("remove blank pages: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("All pages of '%s' are blank", conv.filename)).WithCode(api.ErrorCodeAllPagesBlank),
+							)
+						}
+
+						if err != nil {
+							return err
+						}
+					}
+
+					if merger == nil {
+						return nil
+					}
+
+					// The sheet bookmarks require the page count of each
+					// PDF, which the merge removes.
+					if splitSheets {
+						pageCounts[i], err = engine.PageCount(egCtx, ctx.Log(), outputPaths[i])
+						if err != nil {
+							return fmt.Errorf("count pages of '%s': %w", conv.filename, err)
+						}
+					}
+
+					err = merger.Add(i, outputPaths[i])
+					if err != nil {
+						return fmt.Errorf("merge PDF of '%s': %w", conv.filename, err)
+					}
+
+					return nil
+				})
+			}
+
+			err = eg.Wait()
+			if err != nil {
+				if errors.Is(err, libreofficeapi.ErrInvalidPdfFormats) {
+					return api.WrapError(
+						fmt.Errorf("convert to PDF: %w", err),
+						api.NewSentinelHttpError(
+							http.StatusBadRequest,
+							fmt.Sprintf("A PDF format in '%+v' is not supported", pdfFormats),
+						).WithCode(api.ErrorCodeLibreofficeInvalidPdfFormats),
+					)
 				}
+
+				if errors.Is(err, libreofficeapi.ErrMalformedPageRanges) {
+					return api.WrapError(
+						fmt.Errorf("convert to PDF: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Malformed page ranges '%s' (nativePageRanges)", options.PageRanges)).WithCode(api.ErrorCodeLibreofficeMalformedPageRanges),
+					)
+				}
+
+				return fmt.Errorf("convert to PDF: %w", err)
 			}
 
 			// So far so good, let's check if we have to merge the PDFs. Quick
 			// win: if there is only one PDF, skip this step.
 
-			if len(outputPaths) > 1 && merge {
-				outputPath := ctx.GeneratePath("", ".pdf")
-
-				err = engine.Merge(ctx, ctx.Log(), outputPaths, outputPath)
+			if merger != nil {
+				err = merger.Close()
 				if err != nil {
 					return fmt.Errorf("merge PDFs: %w", err)
 				}
 
+				outputPath := merger.OutputPath()
+
+				// Each sheet has its bookmark in the resulting PDF.
+				if splitSheets {
+					var bookmarks []sheetBookmark
+					page := 1
+
+					for i, conv := range conversions {
+						if conv.sheetName != "" {
+							bookmarks = append(bookmarks, sheetBookmark{title: conv.sheetName, page: page})
+						}
+
+						page += pageCounts[i]
+					}
+
+					if len(bookmarks) > 0 {
+						err = addBookmarks(outputPath, bookmarks)
+						if err != nil {
+							return fmt.Errorf("add sheet bookmarks: %w", err)
+						}
+					}
+				}
+
 				// Now, let's check if the client want to convert this
 				// resulting PDF to specific PDF formats.
 				zeroValued := gotenberg.PdfFormats{}
-				if !nativePdfFormats && pdfFormats != zeroValued {
+				if enginePdfFormats != zeroValued {
 					convertInputPath := outputPath
 					convertOutputPath := ctx.GeneratePath("", ".pdf")
 
-					err = engine.Convert(ctx, ctx.Log(), pdfFormats, convertInputPath, convertOutputPath)
+					err = engine.Convert(ctx, ctx.Log(), enginePdfFormats, convertInputPath, convertOutputPath)
 					if err != nil {
 						return fmt.Errorf("convert PDF: %w", err)
 					}
@@ -116,6 +469,31 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 					outputPath = convertOutputPath
 				}
 
+				// The sources are attached last, as the conversions to
+				// specific PDF formats may not keep them.
+				if attachSource {
+					var sourcePaths []string
+					for _, conv := range conversions {
+						if !slices.Contains(sourcePaths, conv.sourcePath) {
+							sourcePaths = append(sourcePaths, conv.sourcePath)
+						}
+					}
+
+					err = attachSources(outputPath, sourcePaths)
+					if err != nil {
+						return fmt.Errorf("attach sources: %w", err)
+					}
+				}
+
+				err = pdfengines.FitSizeOrFail(ctx, ghostscriptBinPath, outputPath, "merged PDF", maxOutputBytes)
+				if err != nil {
+					return err
+				}
+
+				if asImages {
+					return addImages(ctx, imageEngine, slideImageFormat, outputPath)
+				}
+
 				// Last but not least, add the output path to the context so that
 				// the Uno is able to send it as a response to the client.
 
@@ -130,15 +508,15 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 			// Ok, we don't have to merge the PDFs. Let's check if the client
 			// want to convert each PDF to a specific PDF format.
 			zeroValued := gotenberg.PdfFormats{}
-			if !nativePdfFormats && pdfFormats != zeroValued {
+			if enginePdfFormats != zeroValued {
 				convertOutputPaths := make([]string, len(outputPaths))
 
 				for i, outputPath := range outputPaths {
 					convertInputPath := outputPath
 					// document.docx -> document.docx.pdf.
-					convertOutputPaths[i] = ctx.GeneratePath(filepath.Base(inputPaths[i]), ".pdf")
+					convertOutputPaths[i] = ctx.GeneratePath(conversions[i].filename, ".pdf")
 
-					err = engine.Convert(ctx, ctx.Log(), pdfFormats, convertInputPath, convertOutputPaths[i])
+					err = engine.Convert(ctx, ctx.Log(), enginePdfFormats, convertInputPath, convertOutputPaths[i])
 					if err != nil {
 						return fmt.Errorf("convert PDF: %w", err)
 					}
@@ -149,6 +527,26 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 				outputPaths = convertOutputPaths
 			}
 
+			if attachSource {
+				for i, outputPath := range outputPaths {
+					err = attachSources(outputPath, []string{conversions[i].sourcePath})
+					if err != nil {
+						return fmt.Errorf("attach source of '%s': %w", conversions[i].filename, err)
+					}
+				}
+			}
+
+			for i, outputPath := range outputPaths {
+				err = pdfengines.FitSizeOrFail(ctx, ghostscriptBinPath, outputPath, fmt.Sprintf("PDF of '%s'", conversions[i].filename), maxOutputBytes)
+				if err != nil {
+					return err
+				}
+			}
+
+			if asImages {
+				return addImages(ctx, imageEngine, slideImageFormat, outputPaths...)
+			}
+
 			// Last but not least, add the output paths to the context so that
 			// the Uno is able to send them as a response to the client.
 
@@ -161,3 +559,234 @@ func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine) ap
 		},
 	}
 }
+
+// prepareDocument rewrites a document according to the options LibreOffice
+// cannot apply on its own, and returns the path of the document to convert.
+func prepareDocument(ctx context.Context, apiCtx *api.Context, libreOffice libreofficeapi.Uno, conv conversion, textDirection string, documentLocale language.Tag) (string, error) {
+	inputPath := conv.inputPath
+
+	if textDirection != "" {
+		directedPath := apiCtx.GeneratePath("", filepath.Ext(inputPath))
+
+		ok, err := forceTextDirection(inputPath, directedPath, textDirection)
+		if err != nil {
+			return "", api.WrapError(
+				fmt.Errorf("force text direction: %w", err),
+				api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Cannot force the text direction of '%s'", conv.filename)).WithCode(api.ErrorCodeLibreofficeInvalidDocument),
+			)
+		}
+
+		if ok {
+			inputPath = directedPath
+		} else {
+			apiCtx.Log().Debug(fmt.Sprintf("cannot force the text direction of '%s', convert it as is", conv.filename))
+		}
+	}
+
+	if documentLocale == language.Und {
+		return inputPath, nil
+	}
+
+	// LibreOffice reads the built-in number formats of Excel workbooks for
+	// its own locale, but those of OpenDocument spreadsheets for their
+	// default language.
+	if isLocaleSensitiveWorkbook(inputPath) {
+		odsPath := apiCtx.GeneratePath("", ".ods")
+
+		err := libreOffice.Convert(ctx, apiCtx.Log(), inputPath, odsPath, libreofficeapi.ConvertOptions{Format: "ods"})
+		if err != nil {
+			return "", fmt.Errorf("convert '%s' to ODS: %w", conv.filename, err)
+		}
+
+		inputPath = odsPath
+	}
+
+	localizedPath := apiCtx.GeneratePath("", filepath.Ext(inputPath))
+
+	ok, err := applyDocumentLocale(inputPath, localizedPath, documentLocale)
+	if err != nil {
+		return "", api.WrapError(
+			fmt.Errorf("apply document locale: %w", err),
+			api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Cannot apply the locale to '%s'", conv.filename)).WithCode(api.ErrorCodeLibreofficeInvalidDocument),
+		)
+	}
+
+	if !ok {
+		apiCtx.Log().Debug(fmt.Sprintf("cannot apply the locale to '%s', convert it as is", conv.filename))
+
+		return inputPath, nil
+	}
+
+	return localizedPath, nil
+}
+
+const (
+	// fidelityLayout keeps the layout of the PDF: LibreOffice Writer imports
+	// each line of text in a positioned frame.
+	fidelityLayout = "layout"
+
+	// fidelityFlow favors the editing: the text flows in paragraphs, without
+	// the images nor the positions.
+	fidelityFlow = "flow"
+)
+
+// importRoute returns an [api.Route] which can convert PDFs to editable
+// documents, i.e., DOCX or ODT, with the LibreOffice import filters. Up to
+// parallelConversions PDFs are converted at the same time.
+func importRoute(libreOffice libreofficeapi.Uno, pdftohtmlBinPath string, parallelConversions int) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/libreoffice/import",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				format     string
+				fidelity   string
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Custom("format", func(value string) error {
+					if value == "" {
+						format = "docx"
+						return nil
+					}
+
+					if value != "docx" && value != "odt" {
+						return errors.New("wrong value, expected either 'docx' or 'odt'")
+					}
+
+					format = value
+
+					return nil
+				}).
+				Custom("fidelity", func(value string) error {
+					if value == "" {
+						fidelity = fidelityLayout
+						return nil
+					}
+
+					if value != fidelityLayout && value != fidelityFlow {
+						return fmt.Errorf("wrong value, expected either '%s' or '%s'", fidelityLayout, fidelityFlow)
+					}
+
+					if value == fidelityFlow && pdftohtmlBinPath == "" {
+						return fmt.Errorf("'%s' is not available", fidelityFlow)
+					}
+
+					fidelity = value
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// Alright, let's convert each PDF. The output paths keep the
+			// order of the input paths.
+			ctx.AddEngines("libreoffice")
+			outputPaths := make([]string, len(inputPaths))
+			for i, inputPath := range inputPaths {
+				// document.pdf -> document.pdf.docx.
+				outputPaths[i] = ctx.GeneratePath(filepath.Base(inputPath), fmt.Sprintf(".%s", format))
+			}
+
+			eg, egCtx := errgroup.WithContext(ctx)
+			eg.SetLimit(parallelConversions)
+
+			for i, inputPath := range inputPaths {
+				i, inputPath := i, inputPath
+				eg.Go(func() error {
+					if fidelity == fidelityLayout {
+						return libreOffice.Convert(egCtx, ctx.Log(), inputPath, outputPaths[i], libreofficeapi.ConvertOptions{
+							Format:      format,
+							InputFilter: "writer_pdf_import",
+						})
+					}
+
+					htmlPath := ctx.GeneratePath("", ".html")
+
+					err := pdfToHtml(egCtx, ctx.Log(), pdftohtmlBinPath, inputPath, htmlPath)
+					if err != nil {
+						return fmt.Errorf("convert '%s' to HTML: %w", filepath.Base(inputPath), err)
+					}
+
+					// Writer, not Writer/Web, so that DOCX is available.
+					return libreOffice.Convert(egCtx, ctx.Log(), htmlPath, outputPaths[i], libreofficeapi.ConvertOptions{
+						Format:      format,
+						InputFilter: "HTML (StarWriter)",
+					})
+				})
+			}
+
+			err = eg.Wait()
+			if err != nil {
+				if errors.Is(err, ErrInvalidPdf) {
+					return api.WrapError(
+						fmt.Errorf("import PDF: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, "At least one PDF is invalid").WithCode(api.ErrorCodeLibreofficeInvalidPdf),
+					)
+				}
+
+				return fmt.Errorf("import PDF: %w", err)
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// infoRoute returns an [api.Route] which reads the properties of Office Open
+// XML and OpenDocument files, e.g., their author, without converting them.
+// Only these zip-based formats are supported, as the route reads the
+// properties from their archives. The response is a JSON object with the
+// properties of each document, by filename.
+func infoRoute() api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/libreoffice/info",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var inputPaths []string
+
+			err := ctx.FormData().
+				MandatoryPaths(infoExtensions, &inputPaths).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// Alright, let's read the properties of each document.
+			infos := make(map[string]documentInfo, len(inputPaths))
+
+			for _, inputPath := range inputPaths {
+				filename := filepath.Base(inputPath)
+
+				info, err := readDocumentInfo(inputPath)
+				if err != nil {
+					return api.WrapError(
+						fmt.Errorf("read properties of '%s': %w", filename, err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Cannot read the properties of '%s'", filename)).WithCode(api.ErrorCodeLibreofficeInvalidDocument),
+					)
+				}
+
+				infos[filename] = info
+			}
+
+			return c.JSON(http.StatusOK, infos)
+		},
+	}
+}
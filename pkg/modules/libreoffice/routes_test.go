@@ -2,10 +2,19 @@ package libreoffice
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
 	"slices"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
@@ -21,12 +30,14 @@ func TestConvertRoute(t *testing.T) {
 		ctx                    *api.ContextMock
 		libreOffice            libreofficeapi.Uno
 		engine                 gotenberg.PdfEngine
+		imageEngine            gotenberg.ImageEngine
 		expectOptions          libreofficeapi.Options
 		expectError            bool
 		expectHttpError        bool
 		expectHttpStatus       int
 		expectOutputPathsCount int
 		expectOutputPaths      []string
+		expectWarningsCount    int
 	}{
 		{
 			scenario: "missing at least one mandatory file",
@@ -39,6 +50,238 @@ func TestConvertRoute(t *testing.T) {
 			expectHttpStatus:       http.StatusBadRequest,
 			expectOutputPathsCount: 0,
 		},
+		{
+			scenario: "extra input format disabled",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.wpd": "/document.wpd",
+				})
+				ctx.SetDisabledExtensions(api.ExtensionExtraInputFormats)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".docx", ".wpd"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "remove blank pages without an image engine",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"removeBlankPages": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".docx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "as images without an image engine",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"slides.pptx": "/slides.pptx",
+				})
+				ctx.SetValues(map[string][]string{
+					"asImages": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".pptx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "invalid slide image format",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"slides.pptx": "/slides.pptx",
+				})
+				ctx.SetValues(map[string][]string{
+					"asImages": {
+						"true",
+					},
+					"slideImageFormat": {
+						"gif",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".pptx"}
+			}},
+			imageEngine:            renderSlides(nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "as images with a size budget",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"slides.pptx": "/slides.pptx",
+				})
+				ctx.SetValues(map[string][]string{
+					"asImages": {
+						"true",
+					},
+					"maxOutputBytes": {
+						"1000",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".pptx"}
+			}},
+			imageEngine:            renderSlides(nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "ErrImageFormatNotSupported",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"slides.pptx": "/slides.pptx",
+				})
+				ctx.SetValues(map[string][]string{
+					"asImages": {
+						"true",
+					},
+					"slideImageFormat": {
+						"webp",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".pptx"}
+				},
+			},
+			imageEngine:            renderSlides(gotenberg.ErrImageFormatNotSupported),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (as images)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"slides.pptx": "/slides.pptx",
+				})
+				ctx.SetValues(map[string][]string{
+					"asImages": {
+						"true",
+					},
+					"slideImageFormat": {
+						"png",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".pptx"}
+				},
+			},
+			imageEngine:            renderSlides(nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+		{
+			scenario: "success (as images disabled)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"slides.pptx": "/slides.pptx",
+				})
+				ctx.SetValues(map[string][]string{
+					"asImages": {
+						"true",
+					},
+				})
+				ctx.SetDisabledExtensions(api.ExtensionAsImages)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".pptx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/slides.pptx.pdf"},
+		},
+		{
+			scenario: "success (remove blank pages disabled)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"removeBlankPages": {
+						"true",
+					},
+				})
+				ctx.SetDisabledExtensions(api.ExtensionRemoveBlankPages)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.docx.pdf"},
+		},
 		{
 			scenario: "ErrPdfFormatNotSupported (nativePdfFormats)",
 			ctx: func() *api.ContextMock {
@@ -188,52 +431,637 @@ func TestConvertRoute(t *testing.T) {
 			expectOutputPaths:      []string{"/document.docx.pdf"},
 		},
 		{
-			scenario: "success (many files)",
+			scenario: "invalid textDirection form field",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx":  "/document.docx",
-					"document2.docx": "/document2.docx",
-					"document2.doc":  "/document2.doc",
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"textDirection": {
+						"foo",
+					},
 				})
 				return ctx
 			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".docx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (text direction disabled)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"textDirection": {
+						"foo",
+					},
+				})
+				ctx.SetDisabledExtensions(api.ExtensionTextDirection)
+				return ctx
+			}(),
 			libreOffice: &libreofficeapi.ApiMock{
 				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
 					return nil
 				},
 				ExtensionsMock: func() []string {
-					return []string{".docx", ".doc"}
+					return []string{".docx"}
 				},
 			},
 			expectError:            false,
 			expectHttpError:        false,
-			expectOutputPathsCount: 3,
-			expectOutputPaths:      []string{"/document.docx.pdf", "/document2.docx.pdf", "/document2.doc.pdf"},
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.docx.pdf"},
+		},
+		{
+			scenario: "force the text direction of an invalid document",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(t.TempDir())
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"textDirection": {
+						"rtl",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".docx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (text direction of an unsupported format)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.doc": "/document.doc",
+				})
+				ctx.SetValues(map[string][]string{
+					"textDirection": {
+						"rtl",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if inputPath != "/document.doc" {
+						return fmt.Errorf("expected '/document.doc' but got '%s'", inputPath)
+					}
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".doc"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.doc.pdf"},
+		},
+		{
+			scenario: "success (text direction with fallback fonts)",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestWorkbook(t, filepath.Join(dirPath, "document.docx"), [][2]string{
+					{"word/document.xml", `<w:document><w:body><w:p/></w:body></w:document>`},
+					{"word/fontTable.xml", `<w:fonts><w:font w:name="Times New Roman"/></w:fonts>`},
+				})
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(dirPath)
+				ctx.SetFiles(map[string]string{
+					"document.docx": filepath.Join(dirPath, "document.docx"),
+				})
+				ctx.SetValues(map[string][]string{
+					"textDirection": {
+						"rtl",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return os.WriteFile(outputPath, []byte("%PDF-1.7\n<</Type/Font/BaseFont/BAAAAA+DejaVuSans>>"), 0o600)
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectWarningsCount:    1,
+		},
+		{
+			scenario: "invalid documentLocale form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"workbook.xlsx": "/workbook.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"documentLocale": {
+						"foo_bar!",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".xlsx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (document locale disabled)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"workbook.xlsx": "/workbook.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"documentLocale": {
+						"foo_bar!",
+					},
+				})
+				ctx.SetDisabledExtensions(api.ExtensionDocumentLocale)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/workbook.xlsx.pdf"},
+		},
+		{
+			scenario: "cannot convert a workbook to ODS for its locale",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"workbook.xlsx": "/workbook.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"documentLocale": {
+						"fr-FR",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.ConvertOptions) error {
+					return errors.New("foo")
+				},
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (export links)",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestWorkbook(t, filepath.Join(dirPath, "document.docx"), [][2]string{
+					{"word/document.xml", `<w:document><w:hyperlink w:anchor="foo"/></w:document>`},
+				})
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(dirPath)
+				ctx.SetFiles(map[string]string{
+					"document.docx": filepath.Join(dirPath, "document.docx"),
+				})
+				ctx.SetValues(map[string][]string{
+					"exportLinks": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if !options.ExportLinks {
+						return errors.New("expected links to be exported")
+					}
+					return os.WriteFile(outputPath, []byte("%PDF-1.7"), 0o600)
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectWarningsCount:    1,
+		},
+		{
+			scenario: "success (export links disabled)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"exportLinks": {
+						"true",
+					},
+				})
+				ctx.SetDisabledExtensions(api.ExtensionExportLinks)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.ExportLinks {
+						return errors.New("expected links not to be exported")
+					}
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario: "attach source with PDF/A-1b",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"attachSource": {
+						"true",
+					},
+					"pdfa": {
+						gotenberg.PdfA1b,
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".docx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "cannot attach source",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"attachSource": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (attach source disabled)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"attachSource": {
+						"true",
+					},
+				})
+				ctx.SetDisabledExtensions(api.ExtensionAttachSource)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.docx.pdf"},
+		},
+		{
+			scenario: "success (split sheets disabled)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"workbook.xlsx": "/workbook.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"splitSheets": {
+						"true",
+					},
+				})
+				ctx.SetDisabledExtensions(api.ExtensionSplitSheets)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".xlsx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/workbook.xlsx.pdf"},
+		},
+		{
+			scenario: "split sheets of an invalid workbook",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"workbook.xlsx": "/workbook.xlsx",
+				})
+				ctx.SetValues(map[string][]string{
+					"splitSheets": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".xlsx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (split sheets)",
+			ctx: func() *api.ContextMock {
+				dirPath := t.TempDir()
+				writeTestWorkbook(t, filepath.Join(dirPath, "workbook.xlsx"), [][2]string{{"xl/workbook.xml", testXlsxWorkbook}})
+
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetDirPath(dirPath)
+				ctx.SetFiles(map[string]string{
+					"workbook.xlsx": filepath.Join(dirPath, "workbook.xlsx"),
+					"document.docx": filepath.Join(dirPath, "document.docx"),
+				})
+				ctx.SetValues(map[string][]string{
+					"splitSheets": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx", ".xlsx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 3,
+		},
+		{
+			scenario: "success (many files)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+					"document2.doc":  "/document2.doc",
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx", ".doc"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 3,
+			expectOutputPaths:      []string{"/document.docx.pdf", "/document2.docx.pdf", "/document2.doc.pdf"},
+		},
+		{
+			scenario: "success with non-native PDF/A & PDF/UA (many files)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfa": {
+						gotenberg.PdfA1b,
+					},
+					"pdfua": {
+						"true",
+					},
+					"nativePdfFormats": {
+						"false",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+			expectOutputPaths:      []string{"/document.docx.pdf", "/document2.docx.pdf"},
+		},
+		{
+			scenario: "success with native PDF/A & PDF/UA (many files)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx":  "/document.docx",
+					"document2.docx": "/document2.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfa": {
+						gotenberg.PdfA1b,
+					},
+					"pdfua": {
+						"true",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			engine: &gotenberg.PdfEngineMock{
+				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					return nil
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+			expectOutputPaths:      []string{"/document.docx.pdf", "/document2.docx.pdf"},
+		},
+		{
+			scenario: "invalid pdfVersion form field",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfVersion": {
+						"1.3",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".docx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "success (pdfVersion disabled)",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfVersion": {
+						"1.3",
+					},
+				})
+				ctx.SetDisabledExtensions(api.ExtensionPdfVersion)
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.docx.pdf"},
+		},
+		{
+			scenario: "pdfVersion with PDF/A",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"pdfa": {
+						gotenberg.PdfA2b,
+					},
+					"pdfVersion": {
+						"1.7",
+					},
+				})
+				return ctx
+			}(),
+			libreOffice: &libreofficeapi.ApiMock{ExtensionsMock: func() []string {
+				return []string{".docx"}
+			}},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
 		},
 		{
-			scenario: "success with non-native PDF/A & PDF/UA (many files)",
+			scenario: "success with native PDF version",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx":  "/document.docx",
-					"document2.docx": "/document2.docx",
+					"document.docx": "/document.docx",
 				})
 				ctx.SetValues(map[string][]string{
-					"pdfa": {
-						gotenberg.PdfA1b,
-					},
-					"pdfua": {
-						"true",
-					},
-					"nativePdfFormats": {
-						"false",
+					"pdfVersion": {
+						"1.6",
 					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
 				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.PdfFormats.PdfVersion != "1.6" {
+						return fmt.Errorf("unexpected PDF version '%s'", options.PdfFormats.PdfVersion)
+					}
 					return nil
 				},
 				ExtensionsMock: func() []string {
@@ -242,34 +1070,36 @@ func TestConvertRoute(t *testing.T) {
 			},
 			engine: &gotenberg.PdfEngineMock{
 				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
-					return nil
+					return errors.New("unexpected conversion")
 				},
 			},
 			expectError:            false,
 			expectHttpError:        false,
-			expectOutputPathsCount: 2,
-			expectOutputPaths:      []string{"/document.docx.pdf", "/document2.docx.pdf"},
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.docx.pdf"},
 		},
 		{
-			scenario: "success with native PDF/A & PDF/UA (many files)",
+			scenario: "success with non-native PDF version",
 			ctx: func() *api.ContextMock {
 				ctx := &api.ContextMock{Context: new(api.Context)}
 				ctx.SetFiles(map[string]string{
-					"document.docx":  "/document.docx",
-					"document2.docx": "/document2.docx",
+					"document.docx": "/document.docx",
 				})
 				ctx.SetValues(map[string][]string{
-					"pdfa": {
-						gotenberg.PdfA1b,
-					},
 					"pdfua": {
 						"true",
 					},
+					"pdfVersion": {
+						"1.4",
+					},
 				})
 				return ctx
 			}(),
 			libreOffice: &libreofficeapi.ApiMock{
 				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					if options.PdfFormats != (gotenberg.PdfFormats{PdfUa: true}) {
+						return fmt.Errorf("unexpected PDF formats '%+v'", options.PdfFormats)
+					}
 					return nil
 				},
 				ExtensionsMock: func() []string {
@@ -278,13 +1108,16 @@ func TestConvertRoute(t *testing.T) {
 			},
 			engine: &gotenberg.PdfEngineMock{
 				ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+					if formats != (gotenberg.PdfFormats{PdfVersion: "1.4"}) {
+						return fmt.Errorf("unexpected PDF formats '%+v'", formats)
+					}
 					return nil
 				},
 			},
 			expectError:            false,
 			expectHttpError:        false,
-			expectOutputPathsCount: 2,
-			expectOutputPaths:      []string{"/document.docx.pdf", "/document2.docx.pdf"},
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.docx.pdf"},
 		},
 		{
 			scenario: "merge error",
@@ -515,10 +1348,11 @@ func TestConvertRoute(t *testing.T) {
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
 			c := echo.New().NewContext(nil, nil)
 			c.Set("context", tc.ctx.Context)
 
-			err := convertRoute(tc.libreOffice, tc.engine).Handler(c)
+			err := convertRoute(tc.libreOffice, tc.engine, 2, tc.imageEngine, "").Handler(c)
 
 			if tc.expectError && err == nil {
 				t.Fatal("expected error but got none", err)
@@ -555,6 +1389,398 @@ func TestConvertRoute(t *testing.T) {
 					t.Errorf("expected '%s' in output paths %v", path, tc.ctx.OutputPaths())
 				}
 			}
+
+			if tc.expectWarningsCount != len(tc.ctx.Warnings()) {
+				t.Errorf("expected %d warnings but got %v", tc.expectWarningsCount, tc.ctx.Warnings())
+			}
+		})
+	}
+}
+
+func TestConvertRoute_parallelConversions(t *testing.T) {
+	for _, tc := range []struct {
+		scenario            string
+		parallelConversions int
+		expectMaxInFlight   int32
+	}{
+		{
+			scenario:            "sequential conversions",
+			parallelConversions: 1,
+			expectMaxInFlight:   1,
+		},
+		{
+			scenario:            "parallel conversions",
+			parallelConversions: 3,
+			expectMaxInFlight:   3,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetLogger(zap.NewNop())
+			ctx.Context.Context = context.Background()
+			ctx.SetFiles(map[string]string{
+				"a.docx": "/a.docx",
+				"b.docx": "/b.docx",
+				"c.docx": "/c.docx",
+				"d.docx": "/d.docx",
+				"e.docx": "/e.docx",
+				"f.docx": "/f.docx",
+			})
+			ctx.SetValues(map[string][]string{
+				"merge": {
+					"true",
+				},
+			})
+
+			var (
+				inFlight    atomic.Int32
+				maxInFlight atomic.Int32
+				mu          sync.Mutex
+				converted   = make(map[string]string)
+			)
+
+			libreOffice := &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					current := inFlight.Add(1)
+					defer inFlight.Add(-1)
+
+					for {
+						previous := maxInFlight.Load()
+						if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+							break
+						}
+					}
+
+					time.Sleep(time.Duration(50) * time.Millisecond)
+
+					mu.Lock()
+					converted[outputPath] = inputPath
+					mu.Unlock()
+
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			}
+
+			var mergeInputPaths []string
+			engine := &gotenberg.PdfEngineMock{
+				MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+					mergeInputPaths = inputPaths
+					return nil
+				},
+			}
+
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", ctx.Context)
+
+			err := convertRoute(libreOffice, engine, tc.parallelConversions, nil, "").Handler(c)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if maxInFlight.Load() != tc.expectMaxInFlight {
+				t.Errorf("expected %d conversions at the same time but got %d", tc.expectMaxInFlight, maxInFlight.Load())
+			}
+
+			var inputPaths []string
+			for _, path := range mergeInputPaths {
+				inputPaths = append(inputPaths, converted[path])
+			}
+
+			if !slices.IsSorted(inputPaths) || len(inputPaths) != 6 {
+				t.Errorf("expected the PDFs to be merged in the order of the documents but got %v", inputPaths)
+			}
+		})
+	}
+}
+
+func TestImportRoute(t *testing.T) {
+	newContext := func(values map[string][]string) *api.ContextMock {
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(t.TempDir())
+		ctx.SetFiles(map[string]string{
+			"document.pdf": "/document.pdf",
+		})
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	libreOffice := func(expectOptions libreofficeapi.ConvertOptions, err error) libreofficeapi.Uno {
+		return &libreofficeapi.ApiMock{
+			ConvertMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.ConvertOptions) error {
+				if options != expectOptions {
+					return fmt.Errorf("expected options %+v but got %+v", expectOptions, options)
+				}
+
+				return err
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		libreOffice            libreofficeapi.Uno
+		pdftohtmlBinPath       string
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectOutputPaths      []string
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    &api.ContextMock{Context: new(api.Context)},
+			libreOffice:            libreOffice(libreofficeapi.ConvertOptions{}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid format form field",
+			ctx:                    newContext(map[string][]string{"format": {"pdf"}}),
+			libreOffice:            libreOffice(libreofficeapi.ConvertOptions{}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid fidelity form field",
+			ctx:                    newContext(map[string][]string{"fidelity": {"foo"}}),
+			libreOffice:            libreOffice(libreofficeapi.ConvertOptions{}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "flow fidelity without pdftohtml",
+			ctx:                    newContext(map[string][]string{"fidelity": {"flow"}}),
+			libreOffice:            libreOffice(libreofficeapi.ConvertOptions{}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid PDF",
+			ctx:                    newContext(map[string][]string{"fidelity": {"flow"}}),
+			libreOffice:            libreOffice(libreofficeapi.ConvertOptions{}, nil),
+			pdftohtmlBinPath:       fakePdftohtml(t, true),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from LibreOffice",
+			ctx:                    newContext(nil),
+			libreOffice:            libreOffice(libreofficeapi.ConvertOptions{Format: "docx", InputFilter: "writer_pdf_import"}, errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "success (layout fidelity)",
+			ctx:                    newContext(nil),
+			libreOffice:            libreOffice(libreofficeapi.ConvertOptions{Format: "docx", InputFilter: "writer_pdf_import"}, nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.pdf.docx"},
+		},
+		{
+			scenario: "success (flow fidelity)",
+			ctx: newContext(map[string][]string{
+				"format":   {"odt"},
+				"fidelity": {"flow"},
+			}),
+			libreOffice:            libreOffice(libreofficeapi.ConvertOptions{Format: "odt", InputFilter: "HTML (StarWriter)"}, nil),
+			pdftohtmlBinPath:       fakePdftohtml(t, false),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+			expectOutputPaths:      []string{"/document.pdf.odt"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := importRoute(tc.libreOffice, tc.pdftohtmlBinPath, 2).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+
+			for _, path := range tc.expectOutputPaths {
+				if !slices.Contains(tc.ctx.OutputPaths(), filepath.Join(tc.ctx.DirPath(), path)) {
+					t.Errorf("expected '%s' in output paths %v", path, tc.ctx.OutputPaths())
+				}
+			}
+		})
+	}
+}
+
+func TestInfoRoute(t *testing.T) {
+	newContext := func(files map[string][][2]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string, len(files))
+
+		for filename, entries := range files {
+			paths[filename] = filepath.Join(dirPath, filename)
+
+			if entries == nil {
+				err := os.WriteFile(paths[filename], []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				continue
+			}
+
+			writeTestWorkbook(t, paths[filename], entries)
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(t.TempDir())
+		ctx.SetFiles(paths)
+
+		return ctx
+	}
+
+	for _, tc := range []struct {
+		scenario         string
+		ctx              *api.ContextMock
+		expectError      bool
+		expectHttpError  bool
+		expectHttpStatus int
+		expectInfos      map[string]documentInfo
+	}{
+		{
+			scenario:         "missing at least one mandatory file",
+			ctx:              newContext(map[string][][2]string{"document.doc": nil}),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "invalid document",
+			ctx:              newContext(map[string][][2]string{"document.docx": nil}),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "success",
+			ctx: newContext(map[string][][2]string{
+				"document.docx": {{"docProps/core.xml", testDocxCore}},
+				"workbook.ods":  {{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"}, {"content.xml", testOdsContent}},
+			}),
+			expectError:     false,
+			expectHttpError: false,
+			expectInfos: map[string]documentInfo{
+				"document.docx": {
+					Title:           "Report",
+					Subject:         "Sales",
+					Author:          "Jane",
+					LastModifiedBy:  "John",
+					Created:         "2024-01-02T03:04:05Z",
+					Modified:        "2024-02-03T04:05:06Z",
+					EmbeddedObjects: []string{},
+				},
+				"workbook.ods": {
+					Sheets:          3,
+					EmbeddedObjects: []string{},
+				},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			recorder := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/forms/libreoffice/info", nil), recorder)
+			c.Set("context", tc.ctx.Context)
+
+			err := infoRoute().Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectInfos == nil {
+				return
+			}
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("expected HTTP status code %d but got %d", http.StatusOK, recorder.Code)
+			}
+
+			var infos map[string]documentInfo
+
+			err = json.Unmarshal(recorder.Body.Bytes(), &infos)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(infos, tc.expectInfos) {
+				t.Errorf("expected %+v but got %+v", tc.expectInfos, infos)
+			}
 		})
 	}
 }
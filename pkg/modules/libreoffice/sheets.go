@@ -0,0 +1,342 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// ErrNoVisibleSheets happens if a workbook does not have any visible sheet.
+var ErrNoVisibleSheets = errors.New("no visible sheets")
+
+const (
+	// xlsxWorkbookEntry is the entry of an XLSX archive which lists the
+	// sheets.
+	xlsxWorkbookEntry = "xl/workbook.xml"
+
+	// odsContentEntry is the entry of an ODS archive which contains the
+	// sheets.
+	odsContentEntry = "content.xml"
+
+	// odsHiddenStyle is the name of the table style which hides the sheets of
+	// an ODS workbook.
+	odsHiddenStyle = "GotenbergHiddenSheet"
+)
+
+var (
+	xlsxSheetRegexp     = regexp.MustCompile(`<(?:\w+:)?sheet\s[^>]*>`)
+	xlsxStateRegexp     = regexp.MustCompile(`\sstate="[^"]*"`)
+	xlsxActiveTabRegexp = regexp.MustCompile(`\sactiveTab="\d+"`)
+	odsTableRegexp      = regexp.MustCompile(`<table:table\s[^>]*>`)
+	odsTableStyleRegexp = regexp.MustCompile(`\stable:style-name="[^"]*"`)
+	odsStyleRegexp      = regexp.MustCompile(`(?s)<style:style(\s[^>]*)>(.*?)</style:style>`)
+)
+
+// sheet is a sheet of a workbook, converted on its own.
+type sheet struct {
+	// name is the name of the sheet.
+	name string
+
+	// inputPath is the path of a copy of the workbook in which the other
+	// sheets are hidden.
+	inputPath string
+}
+
+// isWorkbook tells if the sheets of a document can be converted separately.
+func isWorkbook(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xlsx", ".xlsm", ".ods":
+		return true
+	default:
+		return false
+	}
+}
+
+// splitWorkbook writes, for each visible sheet of an XLSX or ODS workbook, a
+// copy of the workbook in which the other sheets are hidden, as LibreOffice
+// does not print hidden sheets. The generatePath function returns the path of
+// each copy.
+func splitWorkbook(inputPath string, generatePath func() string) ([]sheet, error) {
+	reader, err := zip.OpenReader(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("open workbook: %w", err)
+	}
+	defer reader.Close()
+
+	entry := xlsxWorkbookEntry
+	hide := hideXlsxSheets
+	names := xlsxSheetNames
+	if strings.ToLower(filepath.Ext(inputPath)) == ".ods" {
+		entry = odsContentEntry
+		hide = hideOdsSheets
+		names = odsSheetNames
+	}
+
+	var content []byte
+	for _, f := range reader.File {
+		if f.Name != entry {
+			continue
+		}
+
+		content, err = readZipFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("read '%s': %w", entry, err)
+		}
+
+		break
+	}
+
+	if content == nil {
+		return nil, fmt.Errorf("'%s' not found", entry)
+	}
+
+	visible := names(string(content))
+	if len(visible) == 0 {
+		return nil, ErrNoVisibleSheets
+	}
+
+	sheets := make([]sheet, 0, len(visible))
+	for _, s := range visible {
+		outputPath := generatePath()
+
+		err = rewriteArchive(reader.File, map[string][]byte{entry: []byte(hide(string(content), s.index))}, outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("write workbook for sheet '%s': %w", s.name, err)
+		}
+
+		sheets = append(sheets, sheet{
+			name:      s.name,
+			inputPath: outputPath,
+		})
+	}
+
+	return sheets, nil
+}
+
+// indexedSheet is a visible sheet with its index among all the sheets.
+type indexedSheet struct {
+	index int
+	name  string
+}
+
+// xlsxSheetNames returns the visible sheets of an XLSX workbook.
+func xlsxSheetNames(workbook string) []indexedSheet {
+	var sheets []indexedSheet
+	for i, tag := range xlsxSheetRegexp.FindAllString(workbook, -1) {
+		state := attribute(tag, "state")
+		if state == "hidden" || state == "veryHidden" {
+			continue
+		}
+
+		sheets = append(sheets, indexedSheet{index: i, name: attribute(tag, "name")})
+	}
+
+	return sheets
+}
+
+// hideXlsxSheets hides all the sheets of an XLSX workbook but the one at the
+// given index, which also becomes the active one.
+func hideXlsxSheets(workbook string, index int) string {
+	i := -1
+	workbook = xlsxSheetRegexp.ReplaceAllStringFunc(workbook, func(tag string) string {
+		i++
+		j := strings.IndexAny(tag, " \t\r\n")
+		tag = xlsxStateRegexp.ReplaceAllString(tag, "")
+		if i == index {
+			return tag
+		}
+
+		// <sheet name="..."/> -> <sheet state="hidden" name="..."/>.
+		return tag[:j] + ` state="hidden"` + tag[j:]
+	})
+
+	return xlsxActiveTabRegexp.ReplaceAllString(workbook, fmt.Sprintf(` activeTab="%d"`, index))
+}
+
+// odsSheetNames returns the visible sheets of an ODS workbook.
+func odsSheetNames(content string) []indexedSheet {
+	hiddenStyles := odsHiddenStyles(content)
+
+	var sheets []indexedSheet
+	for i, tag := range odsTableRegexp.FindAllString(content, -1) {
+		if hiddenStyles[attribute(tag, "table:style-name")] {
+			continue
+		}
+
+		sheets = append(sheets, indexedSheet{index: i, name: attribute(tag, "table:name")})
+	}
+
+	return sheets
+}
+
+// odsHiddenStyles returns the names of the table styles which hide the sheets
+// of an ODS workbook.
+func odsHiddenStyles(content string) map[string]bool {
+	styles := make(map[string]bool)
+	for _, match := range odsStyleRegexp.FindAllStringSubmatch(content, -1) {
+		if attribute(match[1], "style:family") != "table" {
+			continue
+		}
+
+		if strings.Contains(match[2], `table:display="false"`) {
+			styles[attribute(match[1], "style:name")] = true
+		}
+	}
+
+	return styles
+}
+
+// hideOdsSheets hides all the sheets of an ODS workbook but the one at the
+// given index, thanks to an additional table style.
+func hideOdsSheets(content string, index int) string {
+	style := fmt.Sprintf(`<style:style style:name="%s" style:family="table"><style:table-properties table:display="false"/></style:style>`, odsHiddenStyle)
+
+	switch {
+	case strings.Contains(content, "<office:automatic-styles/>"):
+		content = strings.Replace(content, "<office:automatic-styles/>", "<office:automatic-styles>"+style+"</office:automatic-styles>", 1)
+	case strings.Contains(content, "<office:automatic-styles>"):
+		content = strings.Replace(content, "<office:automatic-styles>", "<office:automatic-styles>"+style, 1)
+	default:
+		content = strings.Replace(content, "<office:body>", "<office:automatic-styles>"+style+"</office:automatic-styles><office:body>", 1)
+	}
+
+	i := -1
+
+	return odsTableRegexp.ReplaceAllStringFunc(content, func(tag string) string {
+		i++
+		if i == index {
+			return tag
+		}
+
+		j := len("<table:table")
+		tag = odsTableStyleRegexp.ReplaceAllString(tag, "")
+
+		return tag[:j] + fmt.Sprintf(` table:style-name="%s"`, odsHiddenStyle) + tag[j:]
+	})
+}
+
+// attribute returns the unescaped value of an attribute of an XML tag.
+func attribute(tag, name string) string {
+	re := regexp.MustCompile(`\s` + regexp.QuoteMeta(name) + `="([^"]*)"`)
+
+	match := re.FindStringSubmatch(tag)
+	if match == nil {
+		return ""
+	}
+
+	return html.UnescapeString(match[1])
+}
+
+// readZipFile returns the uncompressed content of an archive entry.
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+// rewriteArchive writes a copy of an archive in which the given entries have
+// the given contents. The other entries are copied as is, so that the order
+// and the compression methods, which matter for OpenDocument, are kept.
+func rewriteArchive(files []*zip.File, contents map[string][]byte, outputPath string) error {
+	out, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	defer out.Close()
+
+	w := zip.NewWriter(out)
+
+	for _, f := range files {
+		content, ok := contents[f.Name]
+		if !ok {
+			err = w.Copy(f)
+			if err != nil {
+				return fmt.Errorf("copy '%s': %w", f.Name, err)
+			}
+
+			continue
+		}
+
+		header := f.FileHeader
+		ew, err := w.CreateHeader(&header)
+		if err != nil {
+			return fmt.Errorf("create '%s': %w", f.Name, err)
+		}
+
+		_, err = ew.Write(content)
+		if err != nil {
+			return fmt.Errorf("write '%s': %w", f.Name, err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		return fmt.Errorf("close archive: %w", err)
+	}
+
+	return out.Close()
+}
+
+// sheetFilename returns a filename for the PDF of a sheet, unique among the
+// given ones.
+func sheetFilename(name string, taken map[string]bool) string {
+	filename := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	if filename == "" || filename == "." || filename == ".." {
+		filename = "sheet"
+	}
+
+	unique := filename
+	for i := 2; taken[unique]; i++ {
+		unique = filename + "_" + strconv.Itoa(i)
+	}
+
+	taken[unique] = true
+
+	return unique
+}
+
+// sheetBookmark is the bookmark of a sheet in a merged PDF.
+type sheetBookmark struct {
+	title string
+	page  int
+}
+
+// addBookmarks replaces the bookmarks of a PDF in place.
+func addBookmarks(path string, bookmarks []sheetBookmark) error {
+	bms := make([]pdfcpu.Bookmark, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		bms[i] = pdfcpu.Bookmark{
+			Title:    bookmark.title,
+			PageFrom: bookmark.page,
+		}
+	}
+
+	// An empty output path means in place.
+	err := pdfcpuAPI.AddBookmarksFile(path, "", bms, true, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		return fmt.Errorf("add bookmarks: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package libreoffice
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+const (
+	testXlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><bookViews><workbookView activeTab="2"/></bookViews><sheets><sheet name="Sales" sheetId="1" r:id="rId1"/><sheet name="Hidden" sheetId="2" state="hidden" r:id="rId2"/><sheet name="Q&amp;A" sheetId="3" r:id="rId3"/></sheets></workbook>`
+
+	testOdsContent = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"><office:automatic-styles><style:style style:name="ta1" style:family="table"><style:table-properties table:display="true"/></style:style><style:style style:name="ta2" style:family="table"><style:table-properties table:display="false"/></style:style></office:automatic-styles><office:body><office:spreadsheet><table:table table:name="Sales" table:style-name="ta1"><table:table-row/></table:table><table:table table:name="Hidden" table:style-name="ta2"><table:table-row/></table:table><table:table table:name="Q&amp;A" table:style-name="ta1"><table:table-row/></table:table></office:spreadsheet></office:body></office:document-content>`
+)
+
+// writeTestWorkbook writes an archive with the given entries, in order.
+func writeTestWorkbook(t *testing.T, path string, entries [][2]string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create workbook: %v", err)
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for _, entry := range entries {
+		method := zip.Deflate
+		if entry[0] == "mimetype" {
+			method = zip.Store
+		}
+
+		ew, err := w.CreateHeader(&zip.FileHeader{Name: entry[0], Method: method})
+		if err != nil {
+			t.Fatalf("create entry: %v", err)
+		}
+
+		_, err = ew.Write([]byte(entry[1]))
+		if err != nil {
+			t.Fatalf("write entry: %v", err)
+		}
+	}
+
+	err = w.Close()
+	if err != nil {
+		t.Fatalf("close workbook: %v", err)
+	}
+}
+
+func TestIsWorkbook(t *testing.T) {
+	for _, tc := range []struct {
+		path   string
+		expect bool
+	}{
+		{path: "/workbook.xlsx", expect: true},
+		{path: "/workbook.XLSM", expect: true},
+		{path: "/workbook.ods", expect: true},
+		{path: "/workbook.xls", expect: false},
+		{path: "/document.docx", expect: false},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			if isWorkbook(tc.path) != tc.expect {
+				t.Errorf("expected %t but got %t", tc.expect, !tc.expect)
+			}
+		})
+	}
+}
+
+func TestXlsxSheets(t *testing.T) {
+	sheets := xlsxSheetNames(testXlsxWorkbook)
+	expectSheets := []indexedSheet{{index: 0, name: "Sales"}, {index: 2, name: "Q&A"}}
+	if !reflect.DeepEqual(sheets, expectSheets) {
+		t.Fatalf("expected %+v but got %+v", expectSheets, sheets)
+	}
+
+	hidden := hideXlsxSheets(testXlsxWorkbook, 0)
+	sheets = xlsxSheetNames(hidden)
+	expectSheets = []indexedSheet{{index: 0, name: "Sales"}}
+	if !reflect.DeepEqual(sheets, expectSheets) {
+		t.Errorf("expected %+v but got %+v", expectSheets, sheets)
+	}
+
+	if !strings.Contains(hidden, `activeTab="0"`) {
+		t.Errorf("expected the sheet to be the active one in %s", hidden)
+	}
+}
+
+func TestOdsSheets(t *testing.T) {
+	sheets := odsSheetNames(testOdsContent)
+	expectSheets := []indexedSheet{{index: 0, name: "Sales"}, {index: 2, name: "Q&A"}}
+	if !reflect.DeepEqual(sheets, expectSheets) {
+		t.Fatalf("expected %+v but got %+v", expectSheets, sheets)
+	}
+
+	hidden := hideOdsSheets(testOdsContent, 2)
+	sheets = odsSheetNames(hidden)
+	expectSheets = []indexedSheet{{index: 2, name: "Q&A"}}
+	if !reflect.DeepEqual(sheets, expectSheets) {
+		t.Errorf("expected %+v but got %+v", expectSheets, sheets)
+	}
+
+	// Without automatic styles.
+	content := strings.Replace(testOdsContent, testOdsContent[strings.Index(testOdsContent, "<office:automatic-styles>"):strings.Index(testOdsContent, "<office:body>")], "", 1)
+	sheets = odsSheetNames(hideOdsSheets(content, 1))
+	expectSheets = []indexedSheet{{index: 1, name: "Hidden"}}
+	if !reflect.DeepEqual(sheets, expectSheets) {
+		t.Errorf("expected %+v but got %+v", expectSheets, sheets)
+	}
+}
+
+func TestSplitWorkbook(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		filename     string
+		entries      [][2]string
+		expectSheets []string
+		expectError  bool
+		expectErr    error
+	}{
+		{
+			scenario:    "not an archive",
+			filename:    "workbook.xlsx",
+			expectError: true,
+		},
+		{
+			scenario:    "missing workbook entry",
+			filename:    "workbook.xlsx",
+			entries:     [][2]string{{"[Content_Types].xml", "<Types/>"}},
+			expectError: true,
+		},
+		{
+			scenario:    "no visible sheets",
+			filename:    "workbook.xlsx",
+			entries:     [][2]string{{"xl/workbook.xml", `<workbook><sheets><sheet name="Hidden" state="hidden"/></sheets></workbook>`}},
+			expectError: true,
+			expectErr:   ErrNoVisibleSheets,
+		},
+		{
+			scenario:     "XLSX",
+			filename:     "workbook.xlsx",
+			entries:      [][2]string{{"[Content_Types].xml", "<Types/>"}, {"xl/workbook.xml", testXlsxWorkbook}},
+			expectSheets: []string{"Sales", "Q&A"},
+		},
+		{
+			scenario:     "ODS",
+			filename:     "workbook.ods",
+			entries:      [][2]string{{"mimetype", "application/vnd.oasis.opendocument.spreadsheet"}, {"content.xml", testOdsContent}},
+			expectSheets: []string{"Sales", "Q&A"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, tc.filename)
+
+			if tc.entries == nil {
+				err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("write file: %v", err)
+				}
+			} else {
+				writeTestWorkbook(t, inputPath, tc.entries)
+			}
+
+			sheets, err := splitWorkbook(inputPath, func() string {
+				return filepath.Join(dirPath, uuid.NewString()+filepath.Ext(tc.filename))
+			})
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectErr != nil && !errors.Is(err, tc.expectErr) {
+				t.Fatalf("expected error %v but got: %v", tc.expectErr, err)
+			}
+
+			if len(sheets) != len(tc.expectSheets) {
+				t.Fatalf("expected %d sheets but got %d", len(tc.expectSheets), len(sheets))
+			}
+
+			for i, s := range sheets {
+				if s.name != tc.expectSheets[i] {
+					t.Errorf("expected sheet '%s' but got '%s'", tc.expectSheets[i], s.name)
+				}
+
+				reader, err := zip.OpenReader(s.inputPath)
+				if err != nil {
+					t.Fatalf("open workbook of sheet '%s': %v", s.name, err)
+				}
+
+				if len(reader.File) != len(tc.entries) || reader.File[0].Name != tc.entries[0][0] {
+					t.Errorf("expected the entries of the workbook to be kept for sheet '%s'", s.name)
+				}
+
+				content, err := readZipFile(reader.File[len(reader.File)-1])
+				_ = reader.Close()
+				if err != nil {
+					t.Fatalf("read workbook of sheet '%s': %v", s.name, err)
+				}
+
+				names := xlsxSheetNames
+				if filepath.Ext(tc.filename) == ".ods" {
+					names = odsSheetNames
+				}
+
+				visible := names(string(content))
+				if len(visible) != 1 || visible[0].name != s.name {
+					t.Errorf("expected only sheet '%s' to be visible but got %+v", s.name, visible)
+				}
+			}
+		})
+	}
+}
+
+func TestSheetFilename(t *testing.T) {
+	taken := map[string]bool{"workbook.xlsx": true}
+
+	for _, tc := range []struct {
+		name   string
+		expect string
+	}{
+		{name: "Sales", expect: "Sales"},
+		{name: "Sales", expect: "Sales_2"},
+		{name: "Q1/Q2: \"draft\"", expect: "Q1_Q2_ _draft_"},
+		{name: "workbook.xlsx", expect: "workbook.xlsx_2"},
+		{name: " ", expect: "sheet"},
+		{name: "..", expect: "sheet_2"},
+	} {
+		actual := sheetFilename(tc.name, taken)
+		if actual != tc.expect {
+			t.Errorf("expected '%s' for '%s' but got '%s'", tc.expect, tc.name, actual)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package logging
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
 	"os"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"golang.org/x/term"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
 func init() {
@@ -32,11 +35,21 @@ const (
 )
 
 // Logging is a module which implements the [gotenberg.LoggerProvider]
-// interface.
+// interface. It may also emit JSON access logs.
 type Logging struct {
 	level        string
 	format       string
 	fieldsPrefix string
+
+	redactFilenames bool
+	redactUrls      bool
+	redactFields    []string
+
+	accessLogs            bool
+	accessSampleRate      float64
+	accessRedactFilenames bool
+	accessTenantHeader    string
+	accessLogger          *zap.Logger
 }
 
 // Descriptor returns a [Logging]'s module descriptor.
@@ -48,6 +61,13 @@ func (log *Logging) Descriptor() gotenberg.ModuleDescriptor {
 			fs.String("log-level", infoLoggingLevel, fmt.Sprintf("Choose the level of logging detail. Options include %s, %s, %s, or %s", errorLoggingLevel, warnLoggingLevel, infoLoggingLevel, debugLoggingLevel))
 			fs.String("log-format", autoLoggingFormat, fmt.Sprintf("Specify the format of logging. Options include %s, %s, or %s", autoLoggingFormat, jsonLoggingFormat, textLoggingFormat))
 			fs.String("log-fields-prefix", "", "Prepend a specified prefix to each field in the logs")
+			fs.Bool("log-redact-filenames", false, "Redact the filenames, with their paths, in the logs")
+			fs.Bool("log-redact-urls", false, "Redact the URLs in the logs")
+			fs.StringSlice("log-redact-fields", make([]string, 0), "Redact the values of the specified log fields, e.g., the user_agent and referer header values")
+			fs.Bool("log-access", false, "Emit a JSON access log entry for each request")
+			fs.Float64("log-access-sample-rate", 1, "Set the ratio of successful requests with an access log entry, between 0 and 1 - failed requests always have one")
+			fs.Bool("log-access-redact-filenames", false, "Redact the filenames of the uploaded files in the access logs")
+			fs.String("log-access-tenant-header", "Gotenberg-Usage-Key", "Set the header which identifies the tenant of a request in the access logs")
 
 			return fs
 		}(),
@@ -55,18 +75,38 @@ func (log *Logging) Descriptor() gotenberg.ModuleDescriptor {
 	}
 }
 
-// Provision sets the log level and format.
+// Provision sets the log level and format, and the access logger if
+// enabled.
 func (log *Logging) Provision(ctx *gotenberg.Context) error {
 	flags := ctx.ParsedFlags()
 
 	log.level = flags.MustString("log-level")
 	log.format = flags.MustString("log-format")
 	log.fieldsPrefix = flags.MustString("log-fields-prefix")
+	log.redactFilenames = flags.MustBool("log-redact-filenames")
+	log.redactUrls = flags.MustBool("log-redact-urls")
+	log.redactFields = flags.MustStringSlice("log-redact-fields")
+	log.accessLogs = flags.MustBool("log-access")
+	log.accessSampleRate = flags.MustFloat64("log-access-sample-rate")
+	log.accessRedactFilenames = flags.MustBool("log-access-redact-filenames")
+	log.accessTenantHeader = flags.MustString("log-access-tenant-header")
+
+	if log.accessLogs {
+		// Always JSON, whatever the log format, for log pipelines.
+		encCfg := zap.NewProductionEncoderConfig()
+		encCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+
+		log.accessLogger = zap.New(customCore{
+			Core:     zapcore.NewCore(zapcore.NewJSONEncoder(encCfg), zapcore.Lock(os.Stdout), zapcore.InfoLevel),
+			redactor: newRedactor(log.redactFilenames, log.redactUrls, log.redactFields),
+		}).Named("access")
+	}
 
 	return nil
 }
 
-// Validate validates the log level and format.
+// Validate validates the log level and format, and the access logs sample
+// rate.
 func (log *Logging) Validate() error {
 	var err error
 
@@ -90,6 +130,13 @@ func (log *Logging) Validate() error {
 		)
 	}
 
+	if log.accessSampleRate < 0 || log.accessSampleRate > 1 {
+		err = multierr.Append(
+			err,
+			errors.New("access logs sample rate must be between 0 and 1"),
+		)
+	}
+
 	return err
 }
 
@@ -109,19 +156,61 @@ func (log *Logging) Logger(mod gotenberg.Module) (*zap.Logger, error) {
 		logger = zap.New(customCore{
 			Core:         zapcore.NewCore(encoder, os.Stderr, lvl),
 			fieldsPrefix: log.fieldsPrefix,
+			redactor:     newRedactor(log.redactFilenames, log.redactUrls, log.redactFields),
 		})
 	}
 
 	return logger.Named(mod.Descriptor().ID), nil
 }
 
+// LogAccess emits a JSON access log entry, if enabled. Successful requests
+// are sampled.
+func (log *Logging) LogAccess(entry api.AccessLogEntry) {
+	if log.accessLogger == nil {
+		return
+	}
+
+	if entry.Status < 400 && entry.Error == "" && rand.Float64() >= log.accessSampleRate {
+		return
+	}
+
+	filenames := entry.Filenames
+	if log.accessRedactFilenames || log.redactFilenames {
+		filenames = make([]string, len(entry.Filenames))
+		for i := range entry.Filenames {
+			filenames[i] = redactedValue
+		}
+	}
+
+	log.accessLogger.Info(
+		"access",
+		zap.String("trace", entry.Trace),
+		zap.String("tenant", entry.Header.Get(log.accessTenantHeader)),
+		zap.String("remote_ip", entry.RemoteIP),
+		zap.String("method", entry.Method),
+		zap.String("route", entry.Route),
+		zap.Int("status", entry.Status),
+		zap.Int64("duration_ms", entry.Duration.Milliseconds()),
+		zap.Int64("bytes_in", entry.BytesIn),
+		zap.Int64("bytes_out", entry.BytesOut),
+		zap.Strings("filenames", filenames),
+		zap.String("error", entry.Error),
+	)
+}
+
 // See https://github.com/gotenberg/gotenberg/issues/659.
+// It also redacts the sensitive values, if any.
 type customCore struct {
 	zapcore.Core
 	fieldsPrefix string
+	redactor     *redactor
 }
 
 func (c customCore) With(fields []zapcore.Field) zapcore.Core {
+	if c.redactor != nil {
+		c.redactor.redactFields(fields)
+	}
+
 	if c.fieldsPrefix != "" {
 		for i := range fields {
 			fields[i].Key = c.fieldsPrefix + "_" + fields[i].Key
@@ -131,6 +220,7 @@ func (c customCore) With(fields []zapcore.Field) zapcore.Core {
 	return customCore{
 		Core:         c.Core.With(fields),
 		fieldsPrefix: c.fieldsPrefix,
+		redactor:     c.redactor,
 	}
 }
 
@@ -146,6 +236,11 @@ func (c customCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.
 }
 
 func (c customCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if c.redactor != nil {
+		entry.Message = c.redactor.redact(entry.Message)
+		c.redactor.redactFields(fields)
+	}
+
 	if c.fieldsPrefix != "" {
 		for i := range fields {
 			fields[i].Key = c.fieldsPrefix + "_" + fields[i].Key
@@ -207,5 +302,6 @@ var (
 	_ gotenberg.Provisioner    = (*Logging)(nil)
 	_ gotenberg.Validator      = (*Logging)(nil)
 	_ gotenberg.LoggerProvider = (*Logging)(nil)
+	_ api.AccessLogger         = (*Logging)(nil)
 	_ zapcore.Core             = (*customCore)(nil)
 )
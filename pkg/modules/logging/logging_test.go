@@ -2,6 +2,7 @@ package logging
 
 import (
 	"fmt"
+	"net/http"
 	"reflect"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
 func TestLogging_Descriptor(t *testing.T) {
@@ -107,6 +109,7 @@ func TestLogging_Validate(t *testing.T) {
 		scenario    string
 		level       string
 		format      string
+		sampleRate  float64
 		expectError bool
 	}{
 		{
@@ -120,6 +123,13 @@ func TestLogging_Validate(t *testing.T) {
 			format:      "foo",
 			expectError: true,
 		},
+		{
+			scenario:    "invalid access logs sample rate",
+			level:       debugLoggingLevel,
+			format:      autoLoggingFormat,
+			sampleRate:  1.5,
+			expectError: true,
+		},
 		{
 			scenario: "valid level and format",
 			level:    debugLoggingLevel,
@@ -129,6 +139,7 @@ func TestLogging_Validate(t *testing.T) {
 		logging := new(Logging)
 		logging.level = tc.level
 		logging.format = tc.format
+		logging.accessSampleRate = tc.sampleRate
 
 		err := logging.Validate()
 
@@ -142,6 +153,92 @@ func TestLogging_Validate(t *testing.T) {
 	}
 }
 
+func TestLogging_LogAccess(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		disable         bool
+		sampleRate      float64
+		redact          bool
+		entry           api.AccessLogEntry
+		expectLogged    bool
+		expectFilenames []string
+	}{
+		{
+			scenario: "access logs disabled",
+			disable:  true,
+			entry:    api.AccessLogEntry{Status: http.StatusOK},
+		},
+		{
+			scenario:   "successful request not sampled",
+			sampleRate: 0,
+			entry:      api.AccessLogEntry{Status: http.StatusOK},
+		},
+		{
+			scenario:     "failed request not sampled",
+			sampleRate:   0,
+			entry:        api.AccessLogEntry{Status: http.StatusInternalServerError},
+			expectLogged: true,
+		},
+		{
+			scenario:        "successful request",
+			sampleRate:      1,
+			entry:           api.AccessLogEntry{Status: http.StatusOK, Filenames: []string{"foo.docx"}},
+			expectLogged:    true,
+			expectFilenames: []string{"foo.docx"},
+		},
+		{
+			scenario:        "redacted filenames",
+			sampleRate:      1,
+			redact:          true,
+			entry:           api.AccessLogEntry{Status: http.StatusOK, Filenames: []string{"foo.docx"}},
+			expectLogged:    true,
+			expectFilenames: []string{redactedValue},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			core, recorded := observer.New(zapcore.InfoLevel)
+
+			logging := new(Logging)
+			logging.accessSampleRate = tc.sampleRate
+			logging.accessRedactFilenames = tc.redact
+			logging.accessTenantHeader = "Gotenberg-Usage-Key"
+
+			if !tc.disable {
+				logging.accessLogger = zap.New(core)
+			}
+
+			logging.LogAccess(tc.entry)
+
+			entries := recorded.All()
+
+			if !tc.expectLogged {
+				if len(entries) != 0 {
+					t.Fatalf("expected no access log entry but got %d", len(entries))
+				}
+
+				return
+			}
+
+			if len(entries) != 1 {
+				t.Fatalf("expected 1 access log entry but got %d", len(entries))
+			}
+
+			if tc.expectFilenames == nil {
+				return
+			}
+
+			filenames, ok := entries[0].ContextMap()["filenames"].([]interface{})
+			if !ok {
+				t.Fatalf("expected filenames field but got: %v", entries[0].ContextMap())
+			}
+
+			if len(filenames) != len(tc.expectFilenames) || filenames[0] != tc.expectFilenames[0] {
+				t.Errorf("expected filenames %v but got %v", tc.expectFilenames, filenames)
+			}
+		})
+	}
+}
+
 func TestLogging_Logger(t *testing.T) {
 	for _, tc := range []struct {
 		scenario     string
@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"regexp"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedValue replaces a redacted value in the logs.
+const redactedValue = "[REDACTED]"
+
+var (
+	// urlRegexp matches the URLs, e.g., of webhooks or remote resources.
+	urlRegexp = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s'"]+`)
+
+	// filenameRegexp matches the names of the files Gotenberg usually
+	// handles, with their paths if any.
+	filenameRegexp = regexp.MustCompile(`(?i)[^\s'"]+\.(pdf|docx?|xlsx?|pptx?|od[tspg]|rtf|txt|csv|html?|md|xml|fo|tex|epub|eml|msg|jpe?g|png|gif|svg|bmp|tiff?|webp|hei[cf]|zip)\b`)
+)
+
+// redactor redacts the sensitive values of log entries.
+type redactor struct {
+	patterns []*regexp.Regexp
+	fields   map[string]struct{}
+}
+
+// newRedactor returns a [redactor], or nil if there is nothing to redact.
+func newRedactor(filenames, urls bool, fields []string) *redactor {
+	r := &redactor{
+		fields: make(map[string]struct{}),
+	}
+
+	// URLs first, as they may contain filenames.
+	if urls {
+		r.patterns = append(r.patterns, urlRegexp)
+	}
+
+	if filenames {
+		r.patterns = append(r.patterns, filenameRegexp)
+	}
+
+	for _, field := range fields {
+		r.fields[field] = struct{}{}
+	}
+
+	if len(r.patterns) == 0 && len(r.fields) == 0 {
+		return nil
+	}
+
+	return r
+}
+
+// redact redacts the sensitive parts of a string.
+func (r *redactor) redact(s string) string {
+	for _, pattern := range r.patterns {
+		s = pattern.ReplaceAllString(s, redactedValue)
+	}
+
+	return s
+}
+
+// redactFields redacts the values of the given fields in place. The values of
+// the redacted fields are entirely replaced, while string and error values
+// have their sensitive parts redacted.
+func (r *redactor) redactFields(fields []zapcore.Field) {
+	for i, field := range fields {
+		if _, ok := r.fields[field.Key]; ok {
+			fields[i] = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: redactedValue}
+
+			continue
+		}
+
+		if len(r.patterns) == 0 {
+			continue
+		}
+
+		switch field.Type {
+		case zapcore.StringType:
+			fields[i].String = r.redact(field.String)
+		case zapcore.ErrorType:
+			err, ok := field.Interface.(error)
+			if ok && err != nil {
+				fields[i] = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: r.redact(err.Error())}
+			}
+		case zapcore.StringerType:
+			stringer, ok := field.Interface.(interface{ String() string })
+			if ok && stringer != nil {
+				fields[i] = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: r.redact(stringer.String())}
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewRedactor(t *testing.T) {
+	r := newRedactor(false, false, nil)
+	if r != nil {
+		t.Errorf("expected nil redactor but got: %v", r)
+	}
+
+	r = newRedactor(true, false, nil)
+	if r == nil {
+		t.Error("expected redactor but got nil")
+	}
+}
+
+func TestRedactor_redact(t *testing.T) {
+	for _, tc := range []struct {
+		scenario  string
+		filenames bool
+		urls      bool
+		s         string
+		expect    string
+	}{
+		{
+			scenario:  "filenames",
+			filenames: true,
+			s:         "'/tmp/foo/Contract Draft.docx' converted to 'bar.PDF' in 1.5s",
+			expect:    "'/tmp/foo/Contract [REDACTED]' converted to '[REDACTED]' in 1.5s",
+		},
+		{
+			scenario: "URLs",
+			urls:     true,
+			s:        "send result to 'https://internal.example.com/hook?id=foo.pdf'",
+			expect:   "send result to '[REDACTED]'",
+		},
+		{
+			scenario: "nothing to redact",
+			urls:     true,
+			s:        "request handled",
+			expect:   "request handled",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := newRedactor(tc.filenames, tc.urls, nil).redact(tc.s)
+
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestRedactor_redactFields(t *testing.T) {
+	r := newRedactor(true, true, []string{"user_agent"})
+
+	fields := []zapcore.Field{
+		zap.String("user_agent", "foo"),
+		zap.String("webhook_url", "https://example.com"),
+		zap.Error(errors.New("convert 'foo.docx'")),
+		zap.Int("status", 200),
+	}
+
+	r.redactFields(fields)
+
+	expect := []zapcore.Field{
+		zap.String("user_agent", redactedValue),
+		zap.String("webhook_url", redactedValue),
+		zap.String("error", "convert '[REDACTED]'"),
+		zap.Int("status", 200),
+	}
+
+	if !reflect.DeepEqual(fields, expect) {
+		t.Errorf("expected %+v but got %+v", expect, fields)
+	}
+}
@@ -3,6 +3,7 @@ package pdfcpu
 import (
 	"context"
 	"fmt"
+	"os"
 
 	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
 	pdfcpuLog "github.com/pdfcpu/pdfcpu/pkg/log"
@@ -54,6 +55,28 @@ func (engine *PdfCpu) Convert(ctx context.Context, logger *zap.Logger, formats g
 	return fmt.Errorf("convert PDF to '%+v' with PDFcpu: %w", formats, gotenberg.ErrPdfEngineMethodNotSupported)
 }
 
+// PageCount returns the number of pages of a given PDF.
+func (engine *PdfCpu) PageCount(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return 0, fmt.Errorf("open PDF: %w", err)
+	}
+
+	defer func() {
+		err := f.Close()
+		if err != nil {
+			logger.Error(fmt.Sprintf("close PDF: %s", err))
+		}
+	}()
+
+	count, err := pdfcpuAPI.PageCount(f, engine.conf)
+	if err != nil {
+		return 0, fmt.Errorf("count pages with PDFcpu: %w", err)
+	}
+
+	return count, nil
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module      = (*PdfCpu)(nil)
@@ -0,0 +1,195 @@
+package pdfengines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// DefaultBlankPageThreshold is the default ratio of ink pixels, i.e., of
+// pixels darker than a light gray, below which a page is blank.
+const DefaultBlankPageThreshold = 0.001
+
+const (
+	// blankPageResolution is the resolution, in DPI, at which the pages are
+	// rendered. Blank pages do not need details.
+	blankPageResolution = 36
+
+	// blankPageMargin is the ratio of each side of a page which is not
+	// analyzed, as scanners often leave dark borders.
+	blankPageMargin = 0.05
+
+	// inkLevel is the ratio of the maximum gray value below which a pixel is
+	// ink, so that the paper texture of the scans is not.
+	inkLevel = 0.85
+)
+
+// ErrAllPagesBlank happens if all the pages of a PDF are blank, as a PDF
+// must have at least one page.
+var ErrAllPagesBlank = errors.New("all pages are blank")
+
+// RemoveBlankPages removes the blank pages of a PDF in place, thanks to an
+// image engine, and returns their numbers. A page is blank if its ratio of
+// ink pixels is at most the given threshold.
+func RemoveBlankPages(ctx context.Context, logger *zap.Logger, engine gotenberg.ImageEngine, path string, threshold float64) ([]int, error) {
+	blankPages, pageCount, err := blankPages(ctx, logger, engine, path, threshold)
+	if err != nil {
+		return nil, fmt.Errorf("detect blank pages: %w", err)
+	}
+
+	if len(blankPages) == 0 {
+		return nil, nil
+	}
+
+	if len(blankPages) == pageCount {
+		return nil, ErrAllPagesBlank
+	}
+
+	selection := make([]string, len(blankPages))
+	for i, page := range blankPages {
+		selection[i] = strconv.Itoa(page)
+	}
+
+	// An empty output path means in place.
+	err = pdfcpuAPI.RemovePagesFile(path, "", selection, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		return nil, fmt.Errorf("remove pages %s: %w", strings.Join(selection, ","), err)
+	}
+
+	logger.Debug(fmt.Sprintf("blank pages %s removed from '%s'", strings.Join(selection, ","), path))
+
+	return blankPages, nil
+}
+
+// blankPages renders the pages of a PDF in grayscale and returns the numbers
+// of the blank ones, and the number of pages.
+func blankPages(ctx context.Context, logger *zap.Logger, engine gotenberg.ImageEngine, path string, threshold float64) ([]int, int, error) {
+	dirPath := fmt.Sprintf("%s.pages", path)
+
+	err := os.Mkdir(dirPath, 0o755)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create pages directory: %w", err)
+	}
+
+	defer func() {
+		err := os.RemoveAll(dirPath)
+		if err != nil {
+			logger.Error(fmt.Sprintf("remove pages directory: %s", err))
+		}
+	}()
+
+	pages, err := renderPages(ctx, logger, engine, path, dirPath, blankPageResolution)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var blank []int
+	for page, pagePath := range pages {
+		ratio, err := inkRatio(pagePath)
+		if err != nil {
+			return nil, 0, fmt.Errorf("analyze page %d: %w", page, err)
+		}
+
+		if ratio <= threshold {
+			blank = append(blank, page)
+		}
+	}
+
+	sort.Ints(blank)
+
+	return blank, len(pages), nil
+}
+
+// renderPages renders the pages of a PDF in grayscale, at the given
+// resolution, as PNG images within a directory. It returns the paths of the
+// images by page number.
+func renderPages(ctx context.Context, logger *zap.Logger, engine gotenberg.ImageEngine, path, dirPath string, resolution int) (map[int]string, error) {
+	options := gotenberg.ImageOptions{
+		Format:     gotenberg.ImageFormatPng,
+		Resolution: resolution,
+		Gray:       true,
+	}
+
+	paths, err := engine.Render(ctx, logger, options, path, dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("render pages: %w", err)
+	}
+
+	pages := make(map[int]string, len(paths))
+	for i, pagePath := range paths {
+		pages[i+1] = pagePath
+	}
+
+	return pages, nil
+}
+
+// inkRatio returns the ratio of ink pixels of a PNG image, without its
+// margins.
+func inkRatio(path string) (float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("open image: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		return 0, fmt.Errorf("decode image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	marginX := int(float64(bounds.Dx()) * blankPageMargin)
+	marginY := int(float64(bounds.Dy()) * blankPageMargin)
+	level := 0xff * inkLevel
+
+	var ink, total int
+	for y := bounds.Min.Y + marginY; y < bounds.Max.Y-marginY; y++ {
+		for x := bounds.Min.X + marginX; x < bounds.Max.X-marginX; x++ {
+			total++
+
+			if float64(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y) < level {
+				ink++
+			}
+		}
+	}
+
+	if total == 0 {
+		return 0, nil
+	}
+
+	return float64(ink) / float64(total), nil
+}
+
+// ParseBlankPageThreshold parses the value of a "blankPageThreshold" form
+// field, i.e., a ratio between 0 and 1.
+func ParseBlankPageThreshold(value string) (float64, error) {
+	if value == "" {
+		return DefaultBlankPageThreshold, nil
+	}
+
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if threshold < 0 || threshold > 1 {
+		return 0, errors.New("value is not between 0 and 1")
+	}
+
+	return threshold, nil
+}
@@ -0,0 +1,164 @@
+package pdfengines
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeGrayPng writes a grayscale PNG image to the given path.
+func writeGrayPng(t *testing.T, path string, width, height int, pixel func(x, y int) byte) string {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetGray(x, y, color.Gray{Y: pixel(x, y)})
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	err = png.Encode(f, img)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	return path
+}
+
+func TestParseBlankPageThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		value       string
+		expect      float64
+		expectError bool
+	}{
+		{
+			scenario: "default threshold",
+			value:    "",
+			expect:   DefaultBlankPageThreshold,
+		},
+		{
+			scenario: "valid threshold",
+			value:    "0.01",
+			expect:   0.01,
+		},
+		{
+			scenario:    "invalid threshold",
+			value:       "foo",
+			expectError: true,
+		},
+		{
+			scenario:    "threshold more than 1",
+			value:       "1.5",
+			expectError: true,
+		},
+		{
+			scenario:    "negative threshold",
+			value:       "-0.1",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := ParseBlankPageThreshold(tc.value)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected %f but got %f", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestInkRatio(t *testing.T) {
+	writePng := func(t *testing.T, width, height int, pixel func(x, y int) byte) string {
+		return writeGrayPng(t, filepath.Join(t.TempDir(), "page-1.png"), width, height, pixel)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		path        func(t *testing.T) string
+		expect      float64
+		expectError bool
+	}{
+		{
+			scenario: "white page",
+			path: func(t *testing.T) string {
+				return writePng(t, 100, 100, func(x, y int) byte { return 255 })
+			},
+			expect: 0,
+		},
+		{
+			scenario: "white page with a dark scanner border",
+			path: func(t *testing.T) string {
+				return writePng(t, 100, 100, func(x, y int) byte {
+					if x < 3 || y < 3 {
+						return 0
+					}
+
+					return 240
+				})
+			},
+			expect: 0,
+		},
+		{
+			scenario: "half black page",
+			path: func(t *testing.T) string {
+				return writePng(t, 100, 100, func(x, y int) byte {
+					if x < 50 {
+						return 0
+					}
+
+					return 255
+				})
+			},
+			expect: 0.5,
+		},
+		{
+			scenario: "not a PNG image",
+			path: func(t *testing.T) string {
+				path := filepath.Join(t.TempDir(), "page-1.png")
+
+				err := os.WriteFile(path, []byte("foo"), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				return path
+			},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := inkRatio(tc.path(t))
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected %f but got %f", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package pdfengines
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// ParseBookmarkTitles parses the value of a "bookmarkTitles" form field,
+// i.e., a JSON object with the filenames of the PDFs as keys and the titles
+// of their top-level bookmarks as values.
+func ParseBookmarkTitles(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var titles map[string]string
+	err := json.Unmarshal([]byte(value), &titles)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal bookmark titles: %w", err)
+	}
+
+	for filename, title := range titles {
+		if strings.TrimSpace(title) == "" {
+			return nil, fmt.Errorf("empty title for '%s'", filename)
+		}
+	}
+
+	return titles, nil
+}
+
+// readBookmarks returns the outline of a PDF, if any.
+func readBookmarks(path string) ([]pdfcpu.Bookmark, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open PDF: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	bookmarks, err := pdfcpuAPI.Bookmarks(f, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		return nil, fmt.Errorf("read bookmarks: %w", err)
+	}
+
+	return bookmarks, nil
+}
+
+// nestBookmarks returns the outline of PDFs merged in order, given their
+// titles, outlines and page counts: each PDF has a top-level bookmark on its
+// first page, with its outline as children.
+func nestBookmarks(titles []string, outlines [][]pdfcpu.Bookmark, pageCounts []int) []pdfcpu.Bookmark {
+	nested := make([]pdfcpu.Bookmark, len(titles))
+	offset := 0
+
+	for i, title := range titles {
+		nested[i] = pdfcpu.Bookmark{
+			Title:    title,
+			PageFrom: offset + 1,
+			Kids:     mapBookmarkPages(outlines[i], func(page int) int { return page + offset }),
+		}
+
+		offset += pageCounts[i]
+	}
+
+	return nested
+}
+
+// bookmarksWithoutPages returns an outline once some pages of its PDF,
+// starting at 1, removed. The bookmarks of a removed page go to the next
+// remaining page.
+func bookmarksWithoutPages(bookmarks []pdfcpu.Bookmark, removedPages []int, pageCount int) []pdfcpu.Bookmark {
+	removed := make(map[int]bool, len(removedPages))
+	for _, page := range removedPages {
+		removed[page] = true
+	}
+
+	remaining := pageCount - len(removed)
+
+	return mapBookmarkPages(bookmarks, func(page int) int {
+		for removed[page] {
+			page++
+		}
+
+		shift := 0
+		for _, p := range removedPages {
+			if p < page {
+				shift++
+			}
+		}
+
+		return min(page-shift, remaining)
+	})
+}
+
+// mapBookmarkPages returns a copy of an outline with the pages of its
+// bookmarks mapped to other pages.
+func mapBookmarkPages(bookmarks []pdfcpu.Bookmark, mapPage func(page int) int) []pdfcpu.Bookmark {
+	if len(bookmarks) == 0 {
+		return nil
+	}
+
+	mapped := make([]pdfcpu.Bookmark, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		mapped[i] = pdfcpu.Bookmark{
+			Title:    bookmark.Title,
+			PageFrom: mapPage(bookmark.PageFrom),
+			Bold:     bookmark.Bold,
+			Italic:   bookmark.Italic,
+			Color:    bookmark.Color,
+			Kids:     mapBookmarkPages(bookmark.Kids, mapPage),
+		}
+	}
+
+	return mapped
+}
+
+// mergedBookmarks returns the outline of the PDFs once merged, where each
+// PDF has a top-level bookmark, named after its title if any or after its
+// filename otherwise. It also returns the number of pages of the resulting
+// PDF.
+func mergedBookmarks(ctx context.Context, logger *zap.Logger, engine gotenberg.PdfEngine, inputPaths []string, titles map[string]string) ([]pdfcpu.Bookmark, int, error) {
+	names := make([]string, len(inputPaths))
+	outlines := make([][]pdfcpu.Bookmark, len(inputPaths))
+	pageCounts := make([]int, len(inputPaths))
+	total := 0
+
+	for i, inputPath := range inputPaths {
+		filename := filepath.Base(inputPath)
+
+		title, ok := titles[filename]
+		if !ok {
+			title = strings.TrimSuffix(filename, filepath.Ext(filename))
+		}
+
+		names[i] = title
+
+		outline, err := readBookmarks(inputPath)
+		if err != nil {
+			// Most PDFs do not have an outline.
+			logger.Debug(fmt.Sprintf("read bookmarks of '%s': %s", filename, err))
+		}
+
+		outlines[i] = outline
+
+		count, err := engine.PageCount(ctx, logger, inputPath)
+		if err != nil {
+			return nil, 0, fmt.Errorf("count pages of '%s': %w", filename, err)
+		}
+
+		pageCounts[i] = count
+		total += count
+	}
+
+	return nestBookmarks(names, outlines, pageCounts), total, nil
+}
+
+// writeBookmarks replaces the outline of a PDF in place.
+func writeBookmarks(path string, bookmarks []pdfcpu.Bookmark) error {
+	// An empty output path means in place.
+	err := pdfcpuAPI.AddBookmarksFile(path, "", bookmarks, true, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		return fmt.Errorf("add bookmarks: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package pdfengines
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestParseBookmarkTitles(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		value       string
+		expect      map[string]string
+		expectError bool
+	}{
+		{
+			scenario: "no titles",
+			value:    "",
+			expect:   nil,
+		},
+		{
+			scenario: "valid titles",
+			value:    `{"report.pdf": "Annual report", "annex.pdf": "Annex"}`,
+			expect:   map[string]string{"report.pdf": "Annual report", "annex.pdf": "Annex"},
+		},
+		{
+			scenario:    "invalid JSON",
+			value:       `["report.pdf"]`,
+			expectError: true,
+		},
+		{
+			scenario:    "empty title",
+			value:       `{"report.pdf": " "}`,
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := ParseBookmarkTitles(tc.value)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestNestBookmarks(t *testing.T) {
+	actual := nestBookmarks(
+		[]string{"Report", "Annex"},
+		[][]pdfcpu.Bookmark{
+			nil,
+			{
+				{Title: "Part 1", PageFrom: 1, Kids: []pdfcpu.Bookmark{{Title: "Section 1.1", PageFrom: 2}}},
+				{Title: "Part 2", PageFrom: 3},
+			},
+		},
+		[]int{4, 3},
+	)
+
+	expect := []pdfcpu.Bookmark{
+		{Title: "Report", PageFrom: 1},
+		{
+			Title:    "Annex",
+			PageFrom: 5,
+			Kids: []pdfcpu.Bookmark{
+				{Title: "Part 1", PageFrom: 5, Kids: []pdfcpu.Bookmark{{Title: "Section 1.1", PageFrom: 6}}},
+				{Title: "Part 2", PageFrom: 7},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
+
+func TestBookmarksWithoutPages(t *testing.T) {
+	bookmarks := []pdfcpu.Bookmark{
+		{Title: "Report", PageFrom: 1, Kids: []pdfcpu.Bookmark{{Title: "Summary", PageFrom: 3}}},
+		{Title: "Annex", PageFrom: 5},
+	}
+
+	actual := bookmarksWithoutPages(bookmarks, []int{2, 3, 5}, 5)
+
+	expect := []pdfcpu.Bookmark{
+		{Title: "Report", PageFrom: 1, Kids: []pdfcpu.Bookmark{{Title: "Summary", PageFrom: 2}}},
+		{Title: "Annex", PageFrom: 2},
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
+
+func TestMergedBookmarks(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+
+	b, err := os.ReadFile("../../../test/testdata/pdfengines/sample1.pdf")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	dirPath := t.TempDir()
+	inputPaths := []string{filepath.Join(dirPath, "report.pdf"), filepath.Join(dirPath, "annex.pdf")}
+	for _, inputPath := range inputPaths {
+		err = os.WriteFile(inputPath, b, 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario        string
+		engine          gotenberg.PdfEngine
+		titles          map[string]string
+		expectBookmarks []pdfcpu.Bookmark
+		expectCount     int
+		expectError     bool
+	}{
+		{
+			scenario: "cannot count pages",
+			engine: &gotenberg.PdfEngineMock{PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+				return 0, errors.New("foo")
+			}},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			engine: &gotenberg.PdfEngineMock{PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+				return 3, nil
+			}},
+			titles: map[string]string{"annex.pdf": "Annex A"},
+			expectBookmarks: []pdfcpu.Bookmark{
+				{Title: "report", PageFrom: 1},
+				{Title: "Annex A", PageFrom: 4},
+			},
+			expectCount: 6,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			bookmarks, count, err := mergedBookmarks(context.Background(), zap.NewNop(), tc.engine, inputPaths, tc.titles)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(bookmarks, tc.expectBookmarks) {
+				t.Errorf("expected %+v but got %+v", tc.expectBookmarks, bookmarks)
+			}
+
+			if count != tc.expectCount {
+				t.Errorf("expected %d pages but got %d", tc.expectCount, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package pdfengines
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// duplicatePageResolution is the resolution, in DPI, at which the pages are
+// rendered before hashing them. Identical pages render identically at any
+// resolution, while a higher one tells apart pages with small differences.
+const duplicatePageResolution = 72
+
+// DuplicatePage is a page of a PDF identical to a previous page of this PDF.
+type DuplicatePage struct {
+	// Page is the number of the page, starting at 1.
+	Page int `json:"page"`
+
+	// DuplicateOf is the number of the first page identical to this page.
+	DuplicateOf int `json:"duplicateOf"`
+}
+
+// RemoveDuplicatePages removes in place the pages of a PDF identical to a
+// previous page, thanks to an image engine, and returns them. Two pages are
+// identical if their renderings are. Blank pages are never duplicates, as
+// they often separate sections on purpose.
+func RemoveDuplicatePages(ctx context.Context, logger *zap.Logger, engine gotenberg.ImageEngine, path string) ([]DuplicatePage, error) {
+	duplicates, err := duplicatePages(ctx, logger, engine, path)
+	if err != nil {
+		return nil, fmt.Errorf("detect duplicate pages: %w", err)
+	}
+
+	if len(duplicates) == 0 {
+		return nil, nil
+	}
+
+	selection := make([]string, len(duplicates))
+	for i, duplicate := range duplicates {
+		selection[i] = strconv.Itoa(duplicate.Page)
+	}
+
+	// An empty output path means in place.
+	err = pdfcpuAPI.RemovePagesFile(path, "", selection, pdfcpuConfig.NewDefaultConfiguration())
+	if err != nil {
+		return nil, fmt.Errorf("remove pages %s: %w", strings.Join(selection, ","), err)
+	}
+
+	logger.Debug(fmt.Sprintf("duplicate pages %s removed from '%s'", strings.Join(selection, ","), path))
+
+	return duplicates, nil
+}
+
+// duplicatePages renders the pages of a PDF in grayscale and returns the ones
+// identical to a previous page, in order.
+func duplicatePages(ctx context.Context, logger *zap.Logger, engine gotenberg.ImageEngine, path string) ([]DuplicatePage, error) {
+	dirPath := fmt.Sprintf("%s.duplicates", path)
+
+	err := os.Mkdir(dirPath, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create pages directory: %w", err)
+	}
+
+	defer func() {
+		err := os.RemoveAll(dirPath)
+		if err != nil {
+			logger.Error(fmt.Sprintf("remove pages directory: %s", err))
+		}
+	}()
+
+	pages, err := renderPages(ctx, logger, engine, path, dirPath, duplicatePageResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	return findDuplicatePages(pages)
+}
+
+// findDuplicatePages returns the pages whose rendering is identical to the
+// one of a previous page, given the paths of the renderings by page number.
+func findDuplicatePages(pages map[int]string) ([]DuplicatePage, error) {
+	numbers := make([]int, 0, len(pages))
+	for page := range pages {
+		numbers = append(numbers, page)
+	}
+
+	sort.Ints(numbers)
+
+	var duplicates []DuplicatePage
+	firsts := make(map[[sha256.Size]byte]int, len(numbers))
+
+	for _, page := range numbers {
+		ratio, err := inkRatio(pages[page])
+		if err != nil {
+			return nil, fmt.Errorf("analyze page %d: %w", page, err)
+		}
+
+		if ratio <= DefaultBlankPageThreshold {
+			continue
+		}
+
+		b, err := os.ReadFile(pages[page])
+		if err != nil {
+			return nil, fmt.Errorf("read page %d: %w", page, err)
+		}
+
+		sum := sha256.Sum256(b)
+
+		first, ok := firsts[sum]
+		if ok {
+			duplicates = append(duplicates, DuplicatePage{Page: page, DuplicateOf: first})
+			continue
+		}
+
+		firsts[sum] = page
+	}
+
+	return duplicates, nil
+}
+
+// pageOrigin is the PDF, and its page, a page of merged PDFs comes from.
+type pageOrigin struct {
+	Filename string `json:"filename"`
+	Page     int    `json:"page"`
+}
+
+// duplicatePageEntry describes a duplicate page removed from merged PDFs.
+type duplicatePageEntry struct {
+	Page        int        `json:"page"`
+	Origin      pageOrigin `json:"origin"`
+	DuplicateOf int        `json:"duplicateOf"`
+	FirstOrigin pageOrigin `json:"firstOrigin"`
+}
+
+// duplicatesReport describes the duplicate pages removed from merged PDFs.
+// The page numbers are the ones before the removal.
+type duplicatesReport struct {
+	PageCount  int                  `json:"pageCount"`
+	Duplicates []duplicatePageEntry `json:"duplicates"`
+}
+
+// newDuplicatesReport returns the report of the duplicate pages removed from
+// PDFs merged in order, given their filenames and page counts.
+func newDuplicatesReport(duplicates []DuplicatePage, filenames []string, pageCounts []int) duplicatesReport {
+	report := duplicatesReport{
+		Duplicates: make([]duplicatePageEntry, len(duplicates)),
+	}
+
+	for _, count := range pageCounts {
+		report.PageCount += count
+	}
+
+	origin := func(page int) pageOrigin {
+		for i, count := range pageCounts {
+			if page <= count {
+				return pageOrigin{Filename: filenames[i], Page: page}
+			}
+
+			page -= count
+		}
+
+		return pageOrigin{}
+	}
+
+	for i, duplicate := range duplicates {
+		report.Duplicates[i] = duplicatePageEntry{
+			Page:        duplicate.Page,
+			Origin:      origin(duplicate.Page),
+			DuplicateOf: duplicate.DuplicateOf,
+			FirstOrigin: origin(duplicate.DuplicateOf),
+		}
+	}
+
+	return report
+}
+
+// inputPageCounts returns the filenames and the page counts of PDFs.
+func inputPageCounts(ctx context.Context, logger *zap.Logger, engine gotenberg.PdfEngine, inputPaths []string) ([]string, []int, error) {
+	filenames := make([]string, len(inputPaths))
+	pageCounts := make([]int, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		filenames[i] = filepath.Base(inputPath)
+
+		count, err := engine.PageCount(ctx, logger, inputPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("count pages of '%s': %w", filenames[i], err)
+		}
+
+		pageCounts[i] = count
+	}
+
+	return filenames, pageCounts, nil
+}
@@ -0,0 +1,131 @@
+package pdfengines
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindDuplicatePages(t *testing.T) {
+	writePng := func(t *testing.T, dirPath string, page int, pixel func(x, y int) byte) string {
+		return writeGrayPng(t, filepath.Join(dirPath, fmt.Sprintf("page-%d.png", page)), 10, 10, pixel)
+	}
+
+	white := func(x, y int) byte { return 255 }
+	leftHalf := func(x, y int) byte {
+		if x < 5 {
+			return 0
+		}
+
+		return 255
+	}
+	topHalf := func(x, y int) byte {
+		if y < 5 {
+			return 0
+		}
+
+		return 255
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		pages       []func(x, y int) byte
+		expect      []DuplicatePage
+		expectError bool
+	}{
+		{
+			scenario: "no duplicate pages",
+			pages:    []func(x, y int) byte{leftHalf, topHalf},
+			expect:   nil,
+		},
+		{
+			scenario: "duplicate pages",
+			pages:    []func(x, y int) byte{leftHalf, topHalf, leftHalf, topHalf, leftHalf},
+			expect: []DuplicatePage{
+				{Page: 3, DuplicateOf: 1},
+				{Page: 4, DuplicateOf: 2},
+				{Page: 5, DuplicateOf: 1},
+			},
+		},
+		{
+			scenario: "blank pages are not duplicates",
+			pages:    []func(x, y int) byte{white, leftHalf, white},
+			expect:   nil,
+		},
+		{
+			scenario:    "not a PNG image",
+			pages:       []func(x, y int) byte{nil},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			pages := make(map[int]string, len(tc.pages))
+
+			for i, pixel := range tc.pages {
+				if pixel == nil {
+					path := filepath.Join(dirPath, fmt.Sprintf("page-%d.png", i+1))
+
+					err := os.WriteFile(path, []byte("foo"), 0o600)
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+
+					pages[i+1] = path
+					continue
+				}
+
+				pages[i+1] = writePng(t, dirPath, i+1, pixel)
+			}
+
+			actual, err := findDuplicatePages(pages)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestNewDuplicatesReport(t *testing.T) {
+	actual := newDuplicatesReport(
+		[]DuplicatePage{
+			{Page: 4, DuplicateOf: 2},
+			{Page: 6, DuplicateOf: 1},
+		},
+		[]string{"batch1.pdf", "batch2.pdf"},
+		[]int{3, 3},
+	)
+
+	expect := duplicatesReport{
+		PageCount: 6,
+		Duplicates: []duplicatePageEntry{
+			{
+				Page:        4,
+				Origin:      pageOrigin{Filename: "batch2.pdf", Page: 1},
+				DuplicateOf: 2,
+				FirstOrigin: pageOrigin{Filename: "batch1.pdf", Page: 2},
+			},
+			{
+				Page:        6,
+				Origin:      pageOrigin{Filename: "batch2.pdf", Page: 3},
+				DuplicateOf: 1,
+				FirstOrigin: pageOrigin{Filename: "batch1.pdf", Page: 1},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(actual, expect) {
+		t.Errorf("expected %+v but got %+v", expect, actual)
+	}
+}
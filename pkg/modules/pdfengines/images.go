@@ -0,0 +1,45 @@
+package pdfengines
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// RenderImages renders the pages of a PDF to images within the context's
+// working directory, and returns their paths. The images are named after name
+// and their page number, unless a multi-page image holds all the pages.
+func RenderImages(ctx *api.Context, engine gotenberg.ImageEngine, options gotenberg.ImageOptions, inputPath, name string) ([]string, error) {
+	dirPath := ctx.GeneratePath("", "")
+
+	err := os.Mkdir(dirPath, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("create pages directory: %w", err)
+	}
+
+	paths, err := engine.Render(ctx, ctx.Log(), options, inputPath, dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("render pages: %w", err)
+	}
+
+	outputPaths := make([]string, len(paths))
+
+	for i, path := range paths {
+		filename := fmt.Sprintf("%s-%d", name, i+1)
+		if options.MultiPage {
+			filename = name
+		}
+
+		outputPaths[i] = ctx.GeneratePath(filename, filepath.Ext(path))
+
+		err = os.Rename(path, outputPaths[i])
+		if err != nil {
+			return nil, fmt.Errorf("rename page image: %w", err)
+		}
+	}
+
+	return outputPaths, nil
+}
@@ -0,0 +1,105 @@
+package pdfengines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestRenderImages(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		options         gotenberg.ImageOptions
+		renderErr       error
+		expectError     bool
+		expectFilenames []string
+	}{
+		{
+			scenario:    "error from image engine",
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatWebp},
+			renderErr:   gotenberg.ErrImageFormatNotSupported,
+			expectError: true,
+		},
+		{
+			scenario:        "one image per page",
+			options:         gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng},
+			expectError:     false,
+			expectFilenames: []string{"foo-1.png", "foo-2.png"},
+		},
+		{
+			scenario:        "multi-page image",
+			options:         gotenberg.ImageOptions{Format: gotenberg.ImageFormatTiff, MultiPage: true},
+			expectError:     false,
+			expectFilenames: []string{"foo.png"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(t.TempDir())
+			ctx.SetLogger(zap.NewNop())
+
+			pageCount := 2
+			if tc.options.MultiPage {
+				pageCount = 1
+			}
+
+			engine := &gotenberg.ImageEngineMock{
+				RenderMock: func(ctx context.Context, logger *zap.Logger, options gotenberg.ImageOptions, inputPath, outputDirPath string) ([]string, error) {
+					if tc.renderErr != nil {
+						return nil, tc.renderErr
+					}
+
+					paths := make([]string, pageCount)
+
+					for i := range paths {
+						paths[i] = filepath.Join(outputDirPath, fmt.Sprintf("page-%d.png", i+1))
+
+						err := os.WriteFile(paths[i], []byte("foo"), 0o600)
+						if err != nil {
+							return nil, err
+						}
+					}
+
+					return paths, nil
+				},
+			}
+
+			paths, err := RenderImages(ctx.Context, engine, tc.options, "/foo.pdf", "foo")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.renderErr != nil && !errors.Is(err, tc.renderErr) {
+				t.Fatalf("expected error %v but got: %v", tc.renderErr, err)
+			}
+
+			filenames := make([]string, len(paths))
+			for i, path := range paths {
+				filenames[i] = filepath.Base(path)
+
+				_, err = os.Stat(path)
+				if err != nil {
+					t.Errorf("expected no error but got: %v", err)
+				}
+			}
+
+			if !slices.Equal(filenames, tc.expectFilenames) {
+				t.Errorf("expected filenames %v but got %v", tc.expectFilenames, filenames)
+			}
+		})
+	}
+}
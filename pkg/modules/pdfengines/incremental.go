@@ -0,0 +1,210 @@
+package pdfengines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// IncrementalMerge merges PDFs into a unique PDF as soon as they are ready,
+// in order, so that the intermediate PDFs do not pile up until the end of
+// the conversions. It appends the PDFs to the growing output PDF if the
+// engine implements [gotenberg.PdfAppender]. Otherwise, or if the engine
+// finally does not support appending, it keeps them and merges the remaining
+// PDFs on [IncrementalMerge.Close].
+//
+// It removes the PDFs it has merged.
+type IncrementalMerge struct {
+	ctx         context.Context
+	logger      *zap.Logger
+	engine      gotenberg.PdfEngine
+	outputPath  string
+	paths       []string
+	next        int
+	started     bool
+	unsupported bool
+	mu          sync.Mutex
+}
+
+// NewIncrementalMerge creates an [IncrementalMerge] of the given number of
+// PDFs into the PDF at outputPath.
+func NewIncrementalMerge(ctx context.Context, logger *zap.Logger, engine gotenberg.PdfEngine, outputPath string, count int) *IncrementalMerge {
+	_, ok := engine.(gotenberg.PdfAppender)
+
+	return &IncrementalMerge{
+		ctx:         ctx,
+		logger:      logger,
+		engine:      engine,
+		outputPath:  outputPath,
+		paths:       make([]string, count),
+		unsupported: !ok,
+	}
+}
+
+// OutputPath returns the path of the resulting PDF.
+func (merge *IncrementalMerge) OutputPath() string {
+	return merge.outputPath
+}
+
+// Add marks the PDF at the given index as ready. If all the previous PDFs
+// are merged, it merges this PDF and the following ready ones. It blocks
+// while merging.
+func (merge *IncrementalMerge) Add(index int, path string) error {
+	merge.mu.Lock()
+	defer merge.mu.Unlock()
+
+	if index < 0 || index >= len(merge.paths) {
+		return fmt.Errorf("index %d out of range [0, %d)", index, len(merge.paths))
+	}
+
+	merge.paths[index] = path
+
+	if merge.unsupported {
+		return nil
+	}
+
+	end := merge.next
+	for end < len(merge.paths) && merge.paths[end] != "" {
+		end++
+	}
+
+	if end == merge.next {
+		return nil
+	}
+
+	run := merge.paths[merge.next:end]
+
+	if !merge.started {
+		err := merge.start(run)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := merge.append(run)
+		if errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+			merge.logger.Debug("no PDF engine may append PDFs, merge the remaining PDFs at the end")
+			merge.unsupported = true
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	merge.remove(run)
+	merge.next = end
+
+	return nil
+}
+
+// Close merges the remaining PDFs. All the PDFs must be ready.
+func (merge *IncrementalMerge) Close() error {
+	merge.mu.Lock()
+	defer merge.mu.Unlock()
+
+	run := merge.paths[merge.next:]
+
+	for i, path := range run {
+		if path == "" {
+			return fmt.Errorf("PDF %d is not ready", merge.next+i)
+		}
+	}
+
+	if len(run) == 0 {
+		if !merge.started {
+			return errors.New("no PDF to merge")
+		}
+
+		return nil
+	}
+
+	if !merge.started {
+		err := merge.start(run)
+		if err != nil {
+			return err
+		}
+
+		merge.remove(run)
+		merge.next = len(merge.paths)
+
+		return nil
+	}
+
+	// The output PDF is one of the inputs of the final merge.
+	partialPath := strings.TrimSuffix(merge.outputPath, ".pdf") + "-partial.pdf"
+
+	err := os.Rename(merge.outputPath, partialPath)
+	if err != nil {
+		return fmt.Errorf("rename partial PDF: %w", err)
+	}
+
+	inputPaths := append([]string{partialPath}, run...)
+
+	err = merge.engine.Merge(merge.ctx, merge.logger, inputPaths, merge.outputPath)
+	if err != nil {
+		return fmt.Errorf("merge remaining PDFs: %w", err)
+	}
+
+	merge.remove(inputPaths)
+	merge.next = len(merge.paths)
+
+	return nil
+}
+
+// start creates the output PDF from the first PDFs.
+func (merge *IncrementalMerge) start(paths []string) error {
+	if len(paths) == 1 {
+		err := os.Rename(paths[0], merge.outputPath)
+		if err != nil {
+			return fmt.Errorf("rename first PDF: %w", err)
+		}
+
+		merge.started = true
+
+		return nil
+	}
+
+	err := merge.engine.Merge(merge.ctx, merge.logger, paths, merge.outputPath)
+	if err != nil {
+		return fmt.Errorf("merge first PDFs: %w", err)
+	}
+
+	merge.started = true
+
+	return nil
+}
+
+// append appends PDFs to the output PDF.
+func (merge *IncrementalMerge) append(paths []string) error {
+	appender, ok := merge.engine.(gotenberg.PdfAppender)
+	if !ok {
+		return gotenberg.ErrPdfEngineMethodNotSupported
+	}
+
+	err := appender.Append(merge.ctx, merge.logger, merge.outputPath, paths)
+	if err != nil {
+		return fmt.Errorf("append PDFs: %w", err)
+	}
+
+	return nil
+}
+
+// remove removes the merged PDFs. A failure only wastes disk space until
+// the end of the request.
+func (merge *IncrementalMerge) remove(paths []string) {
+	for _, path := range paths {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			merge.logger.Error(fmt.Sprintf("remove merged PDF '%s': %s", path, err))
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package pdfengines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestIncrementalMerge(t *testing.T) {
+	// The mocks concatenate the content of the PDFs.
+	concat := func(outputPath string, inputPaths []string, flag int) error {
+		out, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|flag, 0o600)
+		if err != nil {
+			return err
+		}
+
+		defer out.Close()
+
+		for _, path := range inputPaths {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			_, err = out.Write(b)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	newEngine := func(merges *int) *gotenberg.PdfEngineMock {
+		return &gotenberg.PdfEngineMock{
+			MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+				*merges++
+				return concat(outputPath, inputPaths, os.O_TRUNC)
+			},
+		}
+	}
+
+	newAppender := func(merges, appends *int) gotenberg.PdfEngine {
+		return appenderMock{
+			PdfEngineMock: newEngine(merges),
+			PdfAppenderMock: &gotenberg.PdfAppenderMock{
+				AppendMock: func(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+					*appends++
+					return concat(outputPath, inputPaths, os.O_APPEND)
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario      string
+		engine        func(merges, appends *int) gotenberg.PdfEngine
+		order         []int
+		expectMerges  int
+		expectAppends int
+	}{
+		{
+			scenario:      "in order",
+			engine:        newAppender,
+			order:         []int{0, 1, 2, 3},
+			expectMerges:  0,
+			expectAppends: 3,
+		},
+		{
+			scenario:      "out of order",
+			engine:        newAppender,
+			order:         []int{2, 0, 3, 1},
+			expectMerges:  0,
+			expectAppends: 1,
+		},
+		{
+			scenario:      "first PDFs together",
+			engine:        newAppender,
+			order:         []int{1, 0, 2, 3},
+			expectMerges:  1,
+			expectAppends: 2,
+		},
+		{
+			scenario: "no engine may append",
+			engine: func(merges, appends *int) gotenberg.PdfEngine {
+				return newEngine(merges)
+			},
+			order:        []int{0, 1, 2, 3},
+			expectMerges: 1,
+		},
+		{
+			scenario: "append not supported",
+			engine: func(merges, appends *int) gotenberg.PdfEngine {
+				return appenderMock{
+					PdfEngineMock: newEngine(merges),
+					PdfAppenderMock: &gotenberg.PdfAppenderMock{
+						AppendMock: func(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+							*appends++
+							return gotenberg.ErrPdfEngineMethodNotSupported
+						},
+					},
+				}
+			},
+			order:         []int{0, 1, 2, 3},
+			expectMerges:  1,
+			expectAppends: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dir := t.TempDir()
+			outputPath := filepath.Join(dir, "merged.pdf")
+
+			inputPaths := make([]string, len(tc.order))
+			for i := range inputPaths {
+				inputPaths[i] = filepath.Join(dir, fmt.Sprintf("%d.pdf", i))
+
+				err := os.WriteFile(inputPaths[i], []byte(fmt.Sprint(i)), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			var merges, appends int
+			merge := NewIncrementalMerge(context.Background(), zap.NewNop(), tc.engine(&merges, &appends), outputPath, len(inputPaths))
+
+			for _, i := range tc.order {
+				err := merge.Add(i, inputPaths[i])
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			err := merge.Close()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			b, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(b) != "0123" {
+				t.Errorf("expected merged content '0123' but got '%s'", string(b))
+			}
+
+			if merges != tc.expectMerges {
+				t.Errorf("expected %d merges but got %d", tc.expectMerges, merges)
+			}
+
+			if appends != tc.expectAppends {
+				t.Errorf("expected %d appends but got %d", tc.expectAppends, appends)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(entries) != 1 {
+				t.Errorf("expected only the merged PDF but got %d files", len(entries))
+			}
+		})
+	}
+
+	t.Run("missing PDF", func(t *testing.T) {
+		merge := NewIncrementalMerge(context.Background(), zap.NewNop(), &gotenberg.PdfEngineMock{}, filepath.Join(t.TempDir(), "merged.pdf"), 2)
+
+		err := merge.Close()
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("index out of range", func(t *testing.T) {
+		merge := NewIncrementalMerge(context.Background(), zap.NewNop(), &gotenberg.PdfEngineMock{}, filepath.Join(t.TempDir(), "merged.pdf"), 2)
+
+		err := merge.Add(2, "foo.pdf")
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("append error", func(t *testing.T) {
+		dir := t.TempDir()
+
+		for _, name := range []string{"0.pdf", "1.pdf"} {
+			err := os.WriteFile(filepath.Join(dir, name), []byte("foo"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		}
+
+		engine := appenderMock{
+			PdfEngineMock: &gotenberg.PdfEngineMock{},
+			PdfAppenderMock: &gotenberg.PdfAppenderMock{
+				AppendMock: func(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+					return errors.New("foo")
+				},
+			},
+		}
+
+		merge := NewIncrementalMerge(context.Background(), zap.NewNop(), engine, filepath.Join(dir, "merged.pdf"), 2)
+
+		err := merge.Add(0, filepath.Join(dir, "0.pdf"))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = merge.Add(1, filepath.Join(dir, "1.pdf"))
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+}
@@ -0,0 +1,397 @@
+package pdfengines
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	pdfcpuTypes "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// pageLabelStyles are the numbering styles of the page labels: none, i.e.,
+// only the prefix, decimal numbers, lowercase and uppercase roman numerals,
+// and lowercase and uppercase letters.
+var pageLabelStyles = []string{"", "D", "r", "R", "a", "A"}
+
+// maxNumberTreeDepth is the maximum depth of the number tree of the page
+// labels, as a guard against cyclic trees.
+const maxNumberTreeDepth = 32
+
+// PageLabel is the logical numbering of a range of pages, from its first
+// page until the first page of the next range, e.g., "i, ii, iii" for the
+// front matter, then "1, 2, 3".
+type PageLabel struct {
+	// Page is the first page of the range, starting at 1.
+	Page int `json:"page"`
+
+	// Style is the numbering style: "D" for decimal numbers, "r" and "R"
+	// for roman numerals, "a" and "A" for letters. Without style, the labels
+	// only have the prefix.
+	Style string `json:"style,omitempty"`
+
+	// Prefix is the text before the number of each label, e.g., "A-".
+	Prefix string `json:"prefix,omitempty"`
+
+	// Start is the number of the first page of the range. Defaults to 1.
+	Start int `json:"start,omitempty"`
+}
+
+// ParsePageLabels parses the value of a "pageLabels" form field, i.e., a
+// JSON array of page labels, the first one starting at page 1.
+func ParsePageLabels(value string) ([]PageLabel, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var labels []PageLabel
+	err := json.Unmarshal([]byte(value), &labels)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal page labels: %w", err)
+	}
+
+	if len(labels) == 0 {
+		return nil, errors.New("expected at least one page label")
+	}
+
+	if labels[0].Page != 1 {
+		return nil, errors.New("the first page label must start at page 1")
+	}
+
+	for i, label := range labels {
+		if i > 0 && label.Page <= labels[i-1].Page {
+			return nil, fmt.Errorf("page label %d must start after page %d", i+1, labels[i-1].Page)
+		}
+
+		if !slices.Contains(pageLabelStyles, label.Style) {
+			return nil, fmt.Errorf("wrong style '%s' for page label %d, expected one of 'D', 'r', 'R', 'a' or 'A'", label.Style, i+1)
+		}
+
+		if label.Start < 0 {
+			return nil, fmt.Errorf("start of page label %d is negative", i+1)
+		}
+	}
+
+	return labels, nil
+}
+
+// ReadPageLabels returns the page labels of a PDF, if any.
+func ReadPageLabels(path string) ([]PageLabel, error) {
+	pdfCtx, err := pdfcpuAPI.ReadContextFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read PDF: %w", err)
+	}
+
+	catalog, err := pdfCtx.Catalog()
+	if err != nil {
+		return nil, fmt.Errorf("get catalog: %w", err)
+	}
+
+	root, ok := catalog.Find("PageLabels")
+	if !ok {
+		return nil, nil
+	}
+
+	var labels []PageLabel
+	err = readPageLabelsNode(pdfCtx, root, 0, &labels)
+	if err != nil {
+		return nil, fmt.Errorf("read page labels: %w", err)
+	}
+
+	sort.SliceStable(labels, func(i, j int) bool {
+		return labels[i].Page < labels[j].Page
+	})
+
+	return labels, nil
+}
+
+// readPageLabelsNode reads the page labels of a node of the number tree of
+// the page labels, and of its children.
+func readPageLabelsNode(pdfCtx *pdfcpuModel.Context, obj pdfcpuTypes.Object, depth int, labels *[]PageLabel) error {
+	if depth > maxNumberTreeDepth {
+		return errors.New("number tree too deep")
+	}
+
+	node, err := pdfCtx.DereferenceDict(obj)
+	if err != nil {
+		return fmt.Errorf("dereference node: %w", err)
+	}
+
+	if node == nil {
+		return nil
+	}
+
+	if kids, ok := node.Find("Kids"); ok {
+		children, err := pdfCtx.DereferenceArray(kids)
+		if err != nil {
+			return fmt.Errorf("dereference kids: %w", err)
+		}
+
+		for _, child := range children {
+			err = readPageLabelsNode(pdfCtx, child, depth+1, labels)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	nums, ok := node.Find("Nums")
+	if !ok {
+		return nil
+	}
+
+	entries, err := pdfCtx.DereferenceArray(nums)
+	if err != nil {
+		return fmt.Errorf("dereference nums: %w", err)
+	}
+
+	for i := 0; i+1 < len(entries); i += 2 {
+		index, ok := entries[i].(pdfcpuTypes.Integer)
+		if !ok {
+			continue
+		}
+
+		d, err := pdfCtx.DereferenceDict(entries[i+1])
+		if err != nil {
+			return fmt.Errorf("dereference page label: %w", err)
+		}
+
+		label := PageLabel{Page: index.Value() + 1}
+
+		if style, ok := d.Find("S"); ok {
+			if name, ok := style.(pdfcpuTypes.Name); ok {
+				label.Style = name.Value()
+			}
+		}
+
+		if start, ok := d.Find("St"); ok {
+			if i, ok := start.(pdfcpuTypes.Integer); ok {
+				label.Start = i.Value()
+			}
+		}
+
+		if prefix, ok := d.Find("P"); ok {
+			switch p := prefix.(type) {
+			case pdfcpuTypes.StringLiteral:
+				label.Prefix, err = pdfcpuTypes.StringLiteralToString(p)
+			case pdfcpuTypes.HexLiteral:
+				label.Prefix, err = pdfcpuTypes.HexLiteralToString(p)
+			}
+
+			if err != nil {
+				return fmt.Errorf("decode prefix: %w", err)
+			}
+		}
+
+		*labels = append(*labels, label)
+	}
+
+	return nil
+}
+
+// WritePageLabels replaces the page labels of a PDF in place. No page label
+// removes them.
+func WritePageLabels(path string, labels []PageLabel) error {
+	pdfCtx, err := pdfcpuAPI.ReadContextFile(path)
+	if err != nil {
+		return fmt.Errorf("read PDF: %w", err)
+	}
+
+	catalog, err := pdfCtx.Catalog()
+	if err != nil {
+		return fmt.Errorf("get catalog: %w", err)
+	}
+
+	if len(labels) == 0 {
+		catalog.Delete("PageLabels")
+	} else {
+		nums := make(pdfcpuTypes.Array, 0, 2*len(labels))
+		for _, label := range labels {
+			d := pdfcpuTypes.NewDict()
+
+			if label.Style != "" {
+				d.Insert("S", pdfcpuTypes.Name(label.Style))
+			}
+
+			if label.Prefix != "" {
+				d.Insert("P", pdfString(label.Prefix))
+			}
+
+			if label.Start > 1 {
+				d.Insert("St", pdfcpuTypes.Integer(label.Start))
+			}
+
+			nums = append(nums, pdfcpuTypes.Integer(label.Page-1), d)
+		}
+
+		catalog.Update("PageLabels", pdfcpuTypes.Dict{"Nums": nums})
+	}
+
+	labeledPath := path + ".labels"
+
+	err = pdfcpuAPI.WriteContextFile(pdfCtx, labeledPath)
+	if err != nil {
+		return fmt.Errorf("write PDF: %w", err)
+	}
+
+	err = os.Rename(labeledPath, path)
+	if err != nil {
+		return fmt.Errorf("rename PDF: %w", err)
+	}
+
+	return nil
+}
+
+// pdfString returns a PDF string of a text, UTF-16 encoded if it is not
+// ASCII.
+func pdfString(text string) pdfcpuTypes.Object {
+	for _, r := range text {
+		if r > 0x7e || r < 0x20 {
+			b := []byte{0xfe, 0xff}
+			for _, u := range utf16.Encode([]rune(text)) {
+				b = append(b, byte(u>>8), byte(u))
+			}
+
+			return pdfcpuTypes.HexLiteral(hex.EncodeToString(b))
+		}
+	}
+
+	escaper := strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`)
+
+	return pdfcpuTypes.StringLiteral(escaper.Replace(text))
+}
+
+// MergePageLabels returns the page labels of PDFs merged in order, given
+// their page labels and page counts. The pages of the PDFs without page
+// labels are numbered from 1. It returns nothing if none of the PDFs has
+// page labels.
+func MergePageLabels(labels [][]PageLabel, pageCounts []int) []PageLabel {
+	labeled := false
+	for _, l := range labels {
+		if len(l) > 0 {
+			labeled = true
+			break
+		}
+	}
+
+	if !labeled {
+		return nil
+	}
+
+	var merged []PageLabel
+	offset := 0
+
+	for i, l := range labels {
+		if len(l) == 0 || l[0].Page != 1 {
+			merged = append(merged, PageLabel{Page: offset + 1, Style: "D"})
+		}
+
+		for _, label := range l {
+			if label.Page < 1 || label.Page > pageCounts[i] {
+				continue
+			}
+
+			label.Page += offset
+			merged = append(merged, label)
+		}
+
+		offset += pageCounts[i]
+	}
+
+	return merged
+}
+
+// PageLabelsWithoutPages returns the page labels of a PDF once some of its
+// pages, starting at 1, removed. The remaining pages keep their labels, as
+// long as they are not after a removed page within their range.
+func PageLabelsWithoutPages(labels []PageLabel, removedPages []int, pageCount int) []PageLabel {
+	removed := make(map[int]bool, len(removedPages))
+	for _, page := range removedPages {
+		removed[page] = true
+	}
+
+	var result []PageLabel
+	for i, label := range labels {
+		end := pageCount + 1
+		if i+1 < len(labels) {
+			end = labels[i+1].Page
+		}
+
+		// The first remaining page of the range.
+		first := label.Page
+		for first < end && removed[first] {
+			first++
+		}
+
+		if first >= end {
+			continue
+		}
+
+		if label.Style != "" && first > label.Page {
+			start := max(label.Start, 1)
+			label.Start = start + first - label.Page
+		}
+
+		shift := 0
+		for _, page := range removedPages {
+			if page < first {
+				shift++
+			}
+		}
+
+		label.Page = first - shift
+		result = append(result, label)
+	}
+
+	return result
+}
+
+// MergedPageLabels returns the page labels of the PDFs once merged, and the
+// number of pages of the resulting PDF. It returns nothing if none of the
+// PDFs has page labels.
+func MergedPageLabels(ctx context.Context, logger *zap.Logger, engine gotenberg.PdfEngine, inputPaths []string) ([]PageLabel, int, error) {
+	labels := make([][]PageLabel, len(inputPaths))
+	labeled := false
+
+	for i, inputPath := range inputPaths {
+		l, err := ReadPageLabels(inputPath)
+		if err != nil {
+			// Not a reason to fail the merge.
+			logger.Debug(fmt.Sprintf("read page labels of '%s': %s", inputPath, err))
+			continue
+		}
+
+		labels[i] = l
+		labeled = labeled || len(l) > 0
+	}
+
+	if !labeled {
+		return nil, 0, nil
+	}
+
+	pageCounts := make([]int, len(inputPaths))
+	total := 0
+
+	for i, inputPath := range inputPaths {
+		count, err := engine.PageCount(ctx, logger, inputPath)
+		if err != nil {
+			return nil, 0, fmt.Errorf("count pages of '%s': %w", inputPath, err)
+		}
+
+		pageCounts[i] = count
+		total += count
+	}
+
+	return MergePageLabels(labels, pageCounts), total, nil
+}
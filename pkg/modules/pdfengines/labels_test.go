@@ -0,0 +1,246 @@
+package pdfengines
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+func TestParsePageLabels(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		value       string
+		expect      []PageLabel
+		expectError bool
+	}{
+		{
+			scenario: "no page labels",
+			value:    "",
+			expect:   nil,
+		},
+		{
+			scenario: "valid page labels",
+			value:    `[{"page": 1, "style": "r"}, {"page": 5, "style": "D"}, {"page": 20, "style": "A", "prefix": "Annex ", "start": 2}]`,
+			expect: []PageLabel{
+				{Page: 1, Style: "r"},
+				{Page: 5, Style: "D"},
+				{Page: 20, Style: "A", Prefix: "Annex ", Start: 2},
+			},
+		},
+		{
+			scenario:    "invalid JSON",
+			value:       "foo",
+			expectError: true,
+		},
+		{
+			scenario:    "empty array",
+			value:       "[]",
+			expectError: true,
+		},
+		{
+			scenario:    "first page label not at page 1",
+			value:       `[{"page": 2, "style": "D"}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "unsorted page labels",
+			value:       `[{"page": 1, "style": "r"}, {"page": 5, "style": "D"}, {"page": 5, "style": "a"}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "wrong style",
+			value:       `[{"page": 1, "style": "x"}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "negative start",
+			value:       `[{"page": 1, "style": "D", "start": -1}]`,
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := ParsePageLabels(tc.value)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestWritePageLabels(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+
+	b, err := os.ReadFile("../../../test/testdata/pdfengines/sample1.pdf")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "sample.pdf")
+	err = os.WriteFile(path, b, 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	labels, err := ReadPageLabels(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if labels != nil {
+		t.Fatalf("expected no page labels but got %+v", labels)
+	}
+
+	expect := []PageLabel{
+		{Page: 1, Style: "r"},
+		{Page: 3, Style: "D", Prefix: "(A) "},
+		{Page: 4, Prefix: "Annexe é", Start: 3},
+	}
+
+	err = WritePageLabels(path, expect)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	labels, err = ReadPageLabels(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !reflect.DeepEqual(labels, expect) {
+		t.Errorf("expected %+v but got %+v", expect, labels)
+	}
+
+	err = WritePageLabels(path, nil)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	labels, err = ReadPageLabels(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if labels != nil {
+		t.Errorf("expected no page labels but got %+v", labels)
+	}
+}
+
+func TestMergePageLabels(t *testing.T) {
+	for _, tc := range []struct {
+		scenario   string
+		labels     [][]PageLabel
+		pageCounts []int
+		expect     []PageLabel
+	}{
+		{
+			scenario:   "no page labels",
+			labels:     [][]PageLabel{nil, nil},
+			pageCounts: []int{2, 3},
+			expect:     nil,
+		},
+		{
+			scenario: "front matter and body",
+			labels: [][]PageLabel{
+				{{Page: 1, Style: "r"}},
+				nil,
+				{{Page: 1, Style: "A", Prefix: "Annex "}, {Page: 9, Style: "D"}},
+			},
+			pageCounts: []int{4, 10, 2},
+			expect: []PageLabel{
+				{Page: 1, Style: "r"},
+				{Page: 5, Style: "D"},
+				{Page: 15, Style: "A", Prefix: "Annex "},
+			},
+		},
+		{
+			scenario: "page labels not starting at page 1",
+			labels: [][]PageLabel{
+				nil,
+				{{Page: 2, Style: "r", Start: 2}},
+			},
+			pageCounts: []int{1, 3},
+			expect: []PageLabel{
+				{Page: 1, Style: "D"},
+				{Page: 2, Style: "D"},
+				{Page: 3, Style: "r", Start: 2},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := MergePageLabels(tc.labels, tc.pageCounts)
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestPageLabelsWithoutPages(t *testing.T) {
+	labels := []PageLabel{
+		{Page: 1, Style: "r"},
+		{Page: 4, Style: "D"},
+		{Page: 6, Prefix: "Cover"},
+		{Page: 7, Style: "A"},
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		removedPages []int
+		expect       []PageLabel
+	}{
+		{
+			scenario:     "no removed page",
+			removedPages: nil,
+			expect:       labels,
+		},
+		{
+			scenario:     "first pages of a range",
+			removedPages: []int{2, 4},
+			expect: []PageLabel{
+				{Page: 1, Style: "r"},
+				{Page: 3, Style: "D", Start: 2},
+				{Page: 4, Prefix: "Cover"},
+				{Page: 5, Style: "A"},
+			},
+		},
+		{
+			scenario:     "whole range",
+			removedPages: []int{6},
+			expect: []PageLabel{
+				{Page: 1, Style: "r"},
+				{Page: 4, Style: "D"},
+				{Page: 6, Style: "A"},
+			},
+		},
+		{
+			scenario:     "last range",
+			removedPages: []int{7, 8},
+			expect: []PageLabel{
+				{Page: 1, Style: "r"},
+				{Page: 4, Style: "D"},
+				{Page: 6, Prefix: "Cover"},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual := PageLabelsWithoutPages(labels, tc.removedPages, 8)
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
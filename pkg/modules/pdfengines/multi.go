@@ -3,15 +3,19 @@ package pdfengines
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"go.uber.org/multierr"
 	"go.uber.org/zap"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
 type multiPdfEngines struct {
-	engines []gotenberg.PdfEngine
+	engines             []gotenberg.PdfEngine
+	largeMergeThreshold int64
+	largeMergeEngines   []gotenberg.PdfEngine
 }
 
 func newMultiPdfEngines(engines ...gotenberg.PdfEngine) *multiPdfEngines {
@@ -21,12 +25,28 @@ func newMultiPdfEngines(engines ...gotenberg.PdfEngine) *multiPdfEngines {
 }
 
 // Merge tries to merge the given PDFs into a unique PDF thanks to its
-// children. If the context is done, it stops and returns an error.
+// children. If the total size of the PDFs reaches the large merge threshold,
+// it uses the large merge children instead. If the context is done, it stops
+// and returns an error.
 func (multi *multiPdfEngines) Merge(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+	engines := multi.engines
+
+	if multi.largeMergeThreshold > 0 {
+		size, err := totalSize(inputPaths)
+		if err != nil {
+			return fmt.Errorf("get total size of PDFs: %w", err)
+		}
+
+		if size >= multi.largeMergeThreshold {
+			logger.Debug(fmt.Sprintf("total size of PDFs %d bytes reaches the large merge threshold, merge with the large merge PDF engines", size))
+			engines = multi.largeMergeEngines
+		}
+	}
+
 	var err error
 	errChan := make(chan error, 1)
 
-	for _, engine := range multi.engines {
+	for _, engine := range engines {
 		go func(engine gotenberg.PdfEngine) {
 			errChan <- engine.Merge(ctx, logger, inputPaths, outputPath)
 		}(engine)
@@ -35,6 +55,7 @@ func (multi *multiPdfEngines) Merge(ctx context.Context, logger *zap.Logger, inp
 		case mergeErr := <-errChan:
 			errored := multierr.AppendInto(&err, mergeErr)
 			if !errored {
+				addEngine(ctx, engine)
 				return nil
 			}
 		case <-ctx.Done():
@@ -45,6 +66,46 @@ func (multi *multiPdfEngines) Merge(ctx context.Context, logger *zap.Logger, inp
 	return fmt.Errorf("merge PDFs with multi PDF engines: %w", err)
 }
 
+// Append tries to append the given PDFs to an existing PDF thanks to its
+// children which implement [gotenberg.PdfAppender]. It returns an error
+// wrapping [gotenberg.ErrPdfEngineMethodNotSupported] if none does. If the
+// context is done, it stops and returns an error.
+func (multi *multiPdfEngines) Append(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+	var appenders []gotenberg.PdfEngine
+
+	for _, engine := range multi.engines {
+		if _, ok := engine.(gotenberg.PdfAppender); ok {
+			appenders = append(appenders, engine)
+		}
+	}
+
+	if len(appenders) == 0 {
+		return fmt.Errorf("append PDFs with multi PDF engines: %w", gotenberg.ErrPdfEngineMethodNotSupported)
+	}
+
+	var err error
+	errChan := make(chan error, 1)
+
+	for _, engine := range appenders {
+		go func(engine gotenberg.PdfEngine) {
+			errChan <- engine.(gotenberg.PdfAppender).Append(ctx, logger, outputPath, inputPaths)
+		}(engine)
+
+		select {
+		case appendErr := <-errChan:
+			errored := multierr.AppendInto(&err, appendErr)
+			if !errored {
+				addEngine(ctx, engine)
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("append PDFs with multi PDF engines: %w", err)
+}
+
 // Convert converts the given PDF to a specific PDF format. thanks to its
 // children. If the context is done, it stops and returns an error.
 func (multi *multiPdfEngines) Convert(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
@@ -60,6 +121,7 @@ func (multi *multiPdfEngines) Convert(ctx context.Context, logger *zap.Logger, f
 		case mergeErr := <-errChan:
 			errored := multierr.AppendInto(&err, mergeErr)
 			if !errored {
+				addEngine(ctx, engine)
 				return nil
 			}
 		case <-ctx.Done():
@@ -70,7 +132,72 @@ func (multi *multiPdfEngines) Convert(ctx context.Context, logger *zap.Logger, f
 	return fmt.Errorf("convert PDF to '%+v' with multi PDF engines: %w", formats, err)
 }
 
+// PageCount returns the number of pages of the given PDF thanks to its
+// children. If the context is done, it stops and returns an error.
+func (multi *multiPdfEngines) PageCount(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+	var err error
+
+	type result struct {
+		count int
+		err   error
+	}
+
+	resultChan := make(chan result, 1)
+
+	for _, engine := range multi.engines {
+		go func(engine gotenberg.PdfEngine) {
+			count, err := engine.PageCount(ctx, logger, inputPath)
+			resultChan <- result{count: count, err: err}
+		}(engine)
+
+		select {
+		case res := <-resultChan:
+			errored := multierr.AppendInto(&err, res.err)
+			if !errored {
+				return res.count, nil
+			}
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+	}
+
+	return 0, fmt.Errorf("count pages with multi PDF engines: %w", err)
+}
+
+// addEngine registers the identifier of the engine which has handled an
+// operation, so that it is reported in the output metadata.
+func addEngine(ctx context.Context, engine gotenberg.PdfEngine) {
+	apiCtx, ok := ctx.(*api.Context)
+	if !ok {
+		return
+	}
+
+	mod, ok := engine.(gotenberg.Module)
+	if !ok {
+		return
+	}
+
+	apiCtx.AddEngines(mod.Descriptor().ID)
+}
+
 // Interface guards.
 var (
-	_ gotenberg.PdfEngine = (*multiPdfEngines)(nil)
+	_ gotenberg.PdfEngine   = (*multiPdfEngines)(nil)
+	_ gotenberg.PdfAppender = (*multiPdfEngines)(nil)
 )
+
+// totalSize returns the total size, in bytes, of the given files.
+func totalSize(paths []string) (int64, error) {
+	var size int64
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, fmt.Errorf("stat '%s': %w", path, err)
+		}
+
+		size += info.Size()
+	}
+
+	return size, nil
+}
@@ -3,6 +3,8 @@ package pdfengines
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"go.uber.org/zap"
@@ -95,6 +97,77 @@ func TestMultiPdfEngines_Merge(t *testing.T) {
 	}
 }
 
+func TestMultiPdfEngines_Merge_largeMerge(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "foo.pdf")
+
+	err := os.WriteFile(inputPath, make([]byte, 1024), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		threshold   int64
+		inputPaths  []string
+		expectLarge bool
+		expectError bool
+	}{
+		{
+			scenario:    "below the large merge threshold",
+			threshold:   2048,
+			inputPaths:  []string{inputPath},
+			expectLarge: false,
+		},
+		{
+			scenario:    "large merge threshold reached",
+			threshold:   2048,
+			inputPaths:  []string{inputPath, inputPath},
+			expectLarge: true,
+		},
+		{
+			scenario:    "non-existing PDF",
+			threshold:   2048,
+			inputPaths:  []string{filepath.Join(dir, "bar.pdf")},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var usedLarge bool
+			engine := newMultiPdfEngines(
+				&gotenberg.PdfEngineMock{
+					MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+						return nil
+					},
+				},
+			)
+			engine.largeMergeThreshold = tc.threshold
+			engine.largeMergeEngines = []gotenberg.PdfEngine{
+				&gotenberg.PdfEngineMock{
+					MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+						usedLarge = true
+						return nil
+					},
+				},
+			}
+
+			err := engine.Merge(context.Background(), zap.NewNop(), tc.inputPaths, "")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if usedLarge != tc.expectLarge {
+				t.Errorf("expected large merge %t but got %t", tc.expectLarge, usedLarge)
+			}
+		})
+	}
+}
+
 func TestMultiPdfEngines_Convert(t *testing.T) {
 	for _, tc := range []struct {
 		scenario    string
@@ -177,3 +250,184 @@ func TestMultiPdfEngines_Convert(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiPdfEngines_PageCount(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		engine      *multiPdfEngines
+		ctx         context.Context
+		expectCount int
+		expectError bool
+	}{
+		{
+			scenario: "nominal behavior",
+			engine: newMultiPdfEngines(
+				&gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 3, nil
+					},
+				},
+			),
+			ctx:         context.Background(),
+			expectCount: 3,
+		},
+		{
+			scenario: "at least one engine does not return an error",
+			engine: newMultiPdfEngines(
+				&gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 0, errors.New("foo")
+					},
+				},
+				&gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 2, nil
+					},
+				},
+			),
+			ctx:         context.Background(),
+			expectCount: 2,
+		},
+		{
+			scenario: "all engines return an error",
+			engine: newMultiPdfEngines(
+				&gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 0, errors.New("foo")
+					},
+				},
+				&gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 0, errors.New("foo")
+					},
+				},
+			),
+			ctx:         context.Background(),
+			expectError: true,
+		},
+		{
+			scenario: "context expired",
+			engine: newMultiPdfEngines(
+				&gotenberg.PdfEngineMock{
+					PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+						return 1, nil
+					},
+				},
+			),
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx
+			}(),
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			count, err := tc.engine.PageCount(tc.ctx, zap.NewNop(), "")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if count != tc.expectCount {
+				t.Errorf("expected %d pages but got %d", tc.expectCount, count)
+			}
+		})
+	}
+}
+
+// appenderMock is a [gotenberg.PdfEngine] which may also append PDFs.
+type appenderMock struct {
+	*gotenberg.PdfEngineMock
+	*gotenberg.PdfAppenderMock
+}
+
+func TestMultiPdfEngines_Append(t *testing.T) {
+	for _, tc := range []struct {
+		scenario              string
+		engine                *multiPdfEngines
+		ctx                   context.Context
+		expectError           bool
+		expectMethodSupported bool
+	}{
+		{
+			scenario: "nominal behavior",
+			engine: newMultiPdfEngines(
+				&gotenberg.PdfEngineMock{},
+				appenderMock{
+					PdfEngineMock: &gotenberg.PdfEngineMock{},
+					PdfAppenderMock: &gotenberg.PdfAppenderMock{
+						AppendMock: func(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+							return nil
+						},
+					},
+				},
+			),
+			ctx: context.Background(),
+		},
+		{
+			scenario:    "no engine may append",
+			engine:      newMultiPdfEngines(&gotenberg.PdfEngineMock{}),
+			ctx:         context.Background(),
+			expectError: true,
+		},
+		{
+			scenario: "all engines return an error",
+			engine: newMultiPdfEngines(
+				appenderMock{
+					PdfEngineMock: &gotenberg.PdfEngineMock{},
+					PdfAppenderMock: &gotenberg.PdfAppenderMock{
+						AppendMock: func(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+							return errors.New("foo")
+						},
+					},
+				},
+			),
+			ctx:                   context.Background(),
+			expectError:           true,
+			expectMethodSupported: true,
+		},
+		{
+			scenario: "context expired",
+			engine: newMultiPdfEngines(
+				appenderMock{
+					PdfEngineMock: &gotenberg.PdfEngineMock{},
+					PdfAppenderMock: &gotenberg.PdfAppenderMock{
+						AppendMock: func(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+							return ctx.Err()
+						},
+					},
+				},
+			),
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx
+			}(),
+			expectError:           true,
+			expectMethodSupported: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.engine.Append(tc.ctx, zap.NewNop(), "", nil)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError && !tc.expectMethodSupported && !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
+				t.Errorf("expected error %v but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+			}
+		})
+	}
+}
@@ -3,9 +3,12 @@ package pdfengines
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/labstack/gommon/bytes"
 	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
@@ -26,10 +29,24 @@ func init() {
 // [PdfEngines] can fall back to the next available engine. It also implements
 // the [api.Router] interface to expose relevant PDF processing routes if
 // enabled.
+//
+// Merging very large PDFs with an engine which loads whole documents in
+// memory (e.g., pdfcpu) may exhaust the memory of the container. Above a
+// given size of input PDFs, [PdfEngines] therefore merges with dedicated
+// engines, by default QPDF, which reads the page objects from disk as it
+// writes the output.
+//
+// If a [gotenberg.ImageEngine] module is available, [PdfEngines] also exposes
+// a route for rendering the pages of PDFs to images, removes the blank and
+// duplicate pages on demand, and provides this engine to the other modules.
 type PdfEngines struct {
-	names         []string
-	engines       []gotenberg.PdfEngine
-	disableRoutes bool
+	names               []string
+	largeMergeNames     []string
+	largeMergeThreshold int64
+	engines             []gotenberg.PdfEngine
+	imageEngine         gotenberg.ImageEngine
+	ghostscriptBinPath  string
+	disableRoutes       bool
 }
 
 // Descriptor returns a PdfEngines' module descriptor.
@@ -39,6 +56,8 @@ func (mod *PdfEngines) Descriptor() gotenberg.ModuleDescriptor {
 		FlagSet: func() *flag.FlagSet {
 			fs := flag.NewFlagSet("pdfengines", flag.ExitOnError)
 			fs.StringSlice("pdfengines-engines", make([]string, 0), "Set the PDF engines and their order - all by default")
+			fs.StringSlice("pdfengines-large-merge-engines", []string{"qpdf"}, "Set the PDF engines and their order for merging PDFs above the large merge threshold")
+			fs.String("pdfengines-large-merge-threshold", "0B", "Set the total size of input PDFs above which the large merge engines merge them, e.g., 512MB - 0B disables this behavior")
 			fs.Bool("pdfengines-disable-routes", false, "Disable the routes")
 
 			return fs
@@ -52,8 +71,30 @@ func (mod *PdfEngines) Descriptor() gotenberg.ModuleDescriptor {
 func (mod *PdfEngines) Provision(ctx *gotenberg.Context) error {
 	flags := ctx.ParsedFlags()
 	names := flags.MustStringSlice("pdfengines-engines")
+	mod.largeMergeNames = flags.MustStringSlice("pdfengines-large-merge-engines")
 	mod.disableRoutes = flags.MustBool("pdfengines-disable-routes")
 
+	largeMergeThreshold, err := bytes.Parse(flags.MustHumanReadableBytesString("pdfengines-large-merge-threshold"))
+	if err != nil {
+		return fmt.Errorf("parse large merge threshold: %w", err)
+	}
+
+	mod.largeMergeThreshold = largeMergeThreshold
+
+	// Optional, the routes do not fit the PDFs to a size budget otherwise.
+	mod.ghostscriptBinPath, _ = os.LookupEnv("GHOSTSCRIPT_BIN_PATH")
+
+	// Optional, the routes do not render the pages to images, nor remove the
+	// blank and duplicate pages otherwise.
+	imageEngines, err := ctx.Modules(new(gotenberg.ImageEngine))
+	if err != nil {
+		return fmt.Errorf("get image engines: %w", err)
+	}
+
+	if len(imageEngines) > 0 {
+		mod.imageEngine = imageEngines[0].(gotenberg.ImageEngine)
+	}
+
 	engines, err := ctx.Modules(new(gotenberg.PdfEngine))
 	if err != nil {
 		return fmt.Errorf("get PDF engines: %w", err)
@@ -98,6 +139,36 @@ func (mod *PdfEngines) Validate() error {
 		return errors.New("no PDF engine")
 	}
 
+	err := mod.validateNames(mod.names)
+
+	if mod.ghostscriptBinPath != "" {
+		_, statErr := os.Stat(mod.ghostscriptBinPath)
+		if statErr != nil {
+			err = multierr.Append(err, fmt.Errorf("ghostscript binary path does not exist: %w", statErr))
+		}
+	}
+
+	if mod.largeMergeThreshold < 0 {
+		err = multierr.Append(err, errors.New("large merge threshold must be positive"))
+	}
+
+	if mod.largeMergeThreshold > 0 {
+		if len(mod.largeMergeNames) == 0 {
+			err = multierr.Append(err, errors.New("no large merge PDF engine"))
+		}
+
+		largeMergeErr := mod.validateNames(mod.largeMergeNames)
+		if largeMergeErr != nil {
+			err = multierr.Append(err, fmt.Errorf("large merge: %w", largeMergeErr))
+		}
+	}
+
+	return err
+}
+
+// validateNames validates that the given [gotenberg.PdfEngine] modules
+// actually exist.
+func (mod *PdfEngines) validateNames(names []string) error {
 	availableEngines := make([]string, len(mod.engines))
 
 	for i, engine := range mod.engines {
@@ -106,7 +177,7 @@ func (mod *PdfEngines) Validate() error {
 
 	nonExistingEngines := make([]string, 0)
 
-	for _, name := range mod.names {
+	for _, name := range names {
 		engineExists := false
 
 		for _, engine := range mod.engines {
@@ -129,18 +200,47 @@ func (mod *PdfEngines) Validate() error {
 }
 
 // SystemMessages returns one message with the selected [gotenberg.PdfEngine]
-// modules.
+// modules, and another one with the large merge [gotenberg.PdfEngine] modules
+// if enabled.
 func (mod *PdfEngines) SystemMessages() []string {
-	return []string{
+	messages := []string{
 		strings.Join(mod.names[:], " "),
 	}
+
+	if mod.largeMergeThreshold > 0 {
+		messages = append(messages, fmt.Sprintf("large merge from %s: %s", bytes.Format(mod.largeMergeThreshold), strings.Join(mod.largeMergeNames, " ")))
+	}
+
+	return messages
 }
 
 // PdfEngine returns a [gotenberg.PdfEngine].
 func (mod *PdfEngines) PdfEngine() (gotenberg.PdfEngine, error) {
-	engines := make([]gotenberg.PdfEngine, len(mod.names))
+	multi := newMultiPdfEngines(mod.selectEngines(mod.names)...)
 
-	for i, name := range mod.names {
+	if mod.largeMergeThreshold > 0 {
+		multi.largeMergeThreshold = mod.largeMergeThreshold
+		multi.largeMergeEngines = mod.selectEngines(mod.largeMergeNames)
+	}
+
+	return multi, nil
+}
+
+// ImageEngine returns the [gotenberg.ImageEngine], if any.
+func (mod *PdfEngines) ImageEngine() (gotenberg.ImageEngine, error) {
+	if mod.imageEngine == nil {
+		return nil, errors.New("no image engine")
+	}
+
+	return mod.imageEngine, nil
+}
+
+// selectEngines returns the [gotenberg.PdfEngine] modules with the given
+// names, in the same order.
+func (mod *PdfEngines) selectEngines(names []string) []gotenberg.PdfEngine {
+	engines := make([]gotenberg.PdfEngine, len(names))
+
+	for i, name := range names {
 		for _, engine := range mod.engines {
 			if name == engine.(gotenberg.Module).Descriptor().ID {
 				engines[i] = engine
@@ -149,7 +249,7 @@ func (mod *PdfEngines) PdfEngine() (gotenberg.PdfEngine, error) {
 		}
 	}
 
-	return newMultiPdfEngines(engines...), nil
+	return engines
 }
 
 // Routes returns the HTTP routes.
@@ -165,18 +265,26 @@ func (mod *PdfEngines) Routes() ([]api.Route, error) {
 		return nil, fmt.Errorf("get pdf mod: %w", err)
 	}
 
-	return []api.Route{
-		mergeRoute(engine),
-		convertRoute(engine),
-	}, nil
+	routes := []api.Route{
+		mergeRoute(engine, mod.imageEngine, mod.ghostscriptBinPath),
+		convertRoute(engine, mod.imageEngine, mod.ghostscriptBinPath),
+		textLayerRoute(engine),
+	}
+
+	if mod.imageEngine != nil {
+		routes = append(routes, imagesRoute(mod.imageEngine))
+	}
+
+	return routes, nil
 }
 
 // Interface guards.
 var (
-	_ gotenberg.Module            = (*PdfEngines)(nil)
-	_ gotenberg.Provisioner       = (*PdfEngines)(nil)
-	_ gotenberg.Validator         = (*PdfEngines)(nil)
-	_ gotenberg.SystemLogger      = (*PdfEngines)(nil)
-	_ gotenberg.PdfEngineProvider = (*PdfEngines)(nil)
-	_ api.Router                  = (*PdfEngines)(nil)
+	_ gotenberg.Module              = (*PdfEngines)(nil)
+	_ gotenberg.Provisioner         = (*PdfEngines)(nil)
+	_ gotenberg.Validator           = (*PdfEngines)(nil)
+	_ gotenberg.SystemLogger        = (*PdfEngines)(nil)
+	_ gotenberg.PdfEngineProvider   = (*PdfEngines)(nil)
+	_ gotenberg.ImageEngineProvider = (*PdfEngines)(nil)
+	_ api.Router                    = (*PdfEngines)(nil)
 )
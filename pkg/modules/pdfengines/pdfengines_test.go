@@ -25,6 +25,7 @@ func TestPdfEngines_Provision(t *testing.T) {
 		scenario           string
 		ctx                *gotenberg.Context
 		expectedPdfEngines []string
+		expectImageEngine  bool
 		expectError        bool
 	}{
 		{
@@ -119,6 +120,39 @@ func TestPdfEngines_Provision(t *testing.T) {
 			expectedPdfEngines: []string{"b", "a"},
 			expectError:        false,
 		},
+		{
+			scenario: "with an image engine",
+			ctx: func() *gotenberg.Context {
+				engine := &struct {
+					gotenberg.ModuleMock
+					gotenberg.PdfEngineMock
+				}{}
+				engine.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "bar", New: func() gotenberg.Module { return engine }}
+				}
+
+				imageEngine := &struct {
+					gotenberg.ModuleMock
+					gotenberg.ImageEngineMock
+				}{}
+				imageEngine.DescriptorMock = func() gotenberg.ModuleDescriptor {
+					return gotenberg.ModuleDescriptor{ID: "baz", New: func() gotenberg.Module { return imageEngine }}
+				}
+
+				return gotenberg.NewContext(
+					gotenberg.ParsedFlags{
+						FlagSet: new(PdfEngines).Descriptor().FlagSet,
+					},
+					[]gotenberg.ModuleDescriptor{
+						engine.Descriptor(),
+						imageEngine.Descriptor(),
+					},
+				)
+			}(),
+			expectedPdfEngines: []string{"bar"},
+			expectImageEngine:  true,
+			expectError:        false,
+		},
 		{
 			scenario: "no valid PDF engine",
 			ctx: func() *gotenberg.Context {
@@ -167,6 +201,10 @@ func TestPdfEngines_Provision(t *testing.T) {
 				t.Fatal("expected error but got none")
 			}
 
+			if tc.expectImageEngine != (mod.imageEngine != nil) {
+				t.Errorf("expected an image engine: %t", tc.expectImageEngine)
+			}
+
 			if len(tc.expectedPdfEngines) != len(mod.names) {
 				t.Fatalf("expected %d names but got %d", len(tc.expectedPdfEngines), len(mod.names))
 			}
@@ -181,11 +219,27 @@ func TestPdfEngines_Provision(t *testing.T) {
 }
 
 func TestPdfEngines_Validate(t *testing.T) {
+	fooEngine := func() []gotenberg.PdfEngine {
+		engine := &struct {
+			gotenberg.ModuleMock
+			gotenberg.PdfEngineMock
+		}{}
+		engine.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "foo", New: func() gotenberg.Module { return engine }}
+		}
+
+		return []gotenberg.PdfEngine{
+			engine,
+		}
+	}
+
 	for _, tc := range []struct {
-		scenario    string
-		names       []string
-		engines     []gotenberg.PdfEngine
-		expectError bool
+		scenario            string
+		names               []string
+		largeMergeNames     []string
+		largeMergeThreshold int64
+		engines             []gotenberg.PdfEngine
+		expectError         bool
 	}{
 		{
 			scenario: "existing PDF engine",
@@ -232,6 +286,37 @@ func TestPdfEngines_Validate(t *testing.T) {
 			}(),
 			expectError: true,
 		},
+		{
+			scenario:            "existing large merge PDF engine",
+			names:               []string{"foo"},
+			largeMergeNames:     []string{"foo"},
+			largeMergeThreshold: 1024,
+			engines:             fooEngine(),
+			expectError:         false,
+		},
+		{
+			scenario:            "non-existing large merge PDF engine",
+			names:               []string{"foo"},
+			largeMergeNames:     []string{"bar"},
+			largeMergeThreshold: 1024,
+			engines:             fooEngine(),
+			expectError:         true,
+		},
+		{
+			scenario:            "no large merge PDF engine",
+			names:               []string{"foo"},
+			largeMergeThreshold: 1024,
+			engines:             fooEngine(),
+			expectError:         true,
+		},
+		{
+			scenario:            "large merge disabled",
+			names:               []string{"foo"},
+			largeMergeNames:     []string{"bar"},
+			largeMergeThreshold: 0,
+			engines:             fooEngine(),
+			expectError:         false,
+		},
 		{
 			scenario:    "no PDF engine",
 			expectError: true,
@@ -239,8 +324,10 @@ func TestPdfEngines_Validate(t *testing.T) {
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			mod := PdfEngines{
-				names:   tc.names,
-				engines: tc.engines,
+				names:               tc.names,
+				largeMergeNames:     tc.largeMergeNames,
+				largeMergeThreshold: tc.largeMergeThreshold,
+				engines:             tc.engines,
 			}
 
 			err := mod.Validate()
@@ -271,6 +358,23 @@ func TestPdfEngines_SystemMessages(t *testing.T) {
 	}
 }
 
+func TestPdfEngines_SystemMessages_largeMerge(t *testing.T) {
+	mod := new(PdfEngines)
+	mod.names = []string{"foo", "bar"}
+	mod.largeMergeNames = []string{"bar"}
+	mod.largeMergeThreshold = 512 * 1024 * 1024
+
+	messages := mod.SystemMessages()
+	if len(messages) != 2 {
+		t.Fatalf("expected two messages, but got %d", len(messages))
+	}
+
+	expect := "large merge from 512.00MiB: bar"
+	if messages[1] != expect {
+		t.Errorf("expected message '%s', but got '%s'", expect, messages[1])
+	}
+}
+
 func TestPdfEngines_PdfEngine(t *testing.T) {
 	mod := PdfEngines{
 		names: []string{"foo", "bar"},
@@ -304,15 +408,53 @@ func TestPdfEngines_PdfEngine(t *testing.T) {
 	}
 }
 
+func TestPdfEngines_ImageEngine(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		imageEngine gotenberg.ImageEngine
+		expectError bool
+	}{
+		{
+			scenario:    "no image engine",
+			expectError: true,
+		},
+		{
+			scenario:    "image engine",
+			imageEngine: new(gotenberg.ImageEngineMock),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := PdfEngines{imageEngine: tc.imageEngine}
+
+			_, err := mod.ImageEngine()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
 func TestPdfEngines_Routes(t *testing.T) {
 	for _, tc := range []struct {
 		scenario      string
+		imageEngine   gotenberg.ImageEngine
 		expectRoutes  int
 		disableRoutes bool
 	}{
 		{
 			scenario:      "routes not disabled",
-			expectRoutes:  2,
+			expectRoutes:  3,
+			disableRoutes: false,
+		},
+		{
+			scenario:      "routes not disabled with an image engine",
+			imageEngine:   new(gotenberg.ImageEngineMock),
+			expectRoutes:  4,
 			disableRoutes: false,
 		},
 		{
@@ -323,6 +465,7 @@ func TestPdfEngines_Routes(t *testing.T) {
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			mod := new(PdfEngines)
+			mod.imageEngine = tc.imageEngine
 			mod.disableRoutes = tc.disableRoutes
 
 			routes, err := mod.Routes()
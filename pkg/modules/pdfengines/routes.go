@@ -1,20 +1,29 @@
 package pdfengines
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
 )
 
-// mergeRoute returns an [api.Route] which can merge PDFs.
-func mergeRoute(engine gotenberg.PdfEngine) api.Route {
+// mergeRoute returns an [api.Route] which can merge PDFs. The resulting PDF
+// keeps the page labels of the PDFs, unless the request sets them, and may
+// keep their outlines, each under a bookmark of its own. The duplicate and
+// blank pages may be removed from the resulting PDF if imageEngine is not nil,
+// the former with a JSON report. The resulting PDF may be optimized to fit a
+// size budget if ghostscriptBinPath is set.
+func mergeRoute(engine gotenberg.PdfEngine, imageEngine gotenberg.ImageEngine, ghostscriptBinPath string) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/pdfengines/merge",
@@ -24,23 +33,180 @@ func mergeRoute(engine gotenberg.PdfEngine) api.Route {
 
 			// Let's get the data from the form and validate them.
 			var (
-				inputPaths []string
-				pdfa       string
-				pdfua      bool
+				inputPaths           []string
+				pdfa                 string
+				pdfua                bool
+				pdfVersion           string
+				pageLabels           []PageLabel
+				preserveBookmarks    bool
+				bookmarkTitles       map[string]string
+				removeDuplicatePages bool
+				removeBlankPages     bool
+				blankPageThreshold   float64
+				maxOutputBytes       int64
 			)
 
-			err := ctx.FormData().
+			form := ctx.FormData().
 				MandatoryPaths([]string{".pdf"}, &inputPaths).
 				String("pdfa", &pdfa, "").
-				Bool("pdfua", &pdfua, false).
-				Validate()
+				Bool("pdfua", &pdfua, false)
+
+			if ctx.ExtensionEnabled(api.ExtensionRemoveBlankPages) {
+				form.
+					Bool("removeBlankPages", &removeBlankPages, false).
+					Custom("blankPageThreshold", func(value string) error {
+						threshold, err := ParseBlankPageThreshold(value)
+						if err != nil {
+							return err
+						}
+
+						blankPageThreshold = threshold
+
+						return nil
+					})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionPdfVersion) {
+				form.Custom("pdfVersion", func(value string) error {
+					version, err := ParsePdfVersion(value)
+					if err != nil {
+						return err
+					}
+
+					pdfVersion = version
+
+					return nil
+				})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionPageLabels) {
+				form.Custom("pageLabels", func(value string) error {
+					labels, err := ParsePageLabels(value)
+					if err != nil {
+						return err
+					}
+
+					pageLabels = labels
+
+					return nil
+				})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionBookmarks) {
+				form.
+					Bool("preserveBookmarks", &preserveBookmarks, false).
+					Custom("bookmarkTitles", func(value string) error {
+						titles, err := ParseBookmarkTitles(value)
+						if err != nil {
+							return err
+						}
+
+						bookmarkTitles = titles
+
+						return nil
+					})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionRemoveDuplicatePages) {
+				form.Bool("removeDuplicatePages", &removeDuplicatePages, false)
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionMaxOutputBytes) {
+				form.Custom("maxOutputBytes", func(value string) error {
+					maxBytes, err := ParseMaxOutputBytes(value)
+					if err != nil {
+						return err
+					}
+
+					maxOutputBytes = maxBytes
+
+					return nil
+				})
+			}
+
+			err := form.Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
+			if removeDuplicatePages && imageEngine == nil {
+				return errDuplicatePagesNotAvailable
+			}
+
+			if removeBlankPages && imageEngine == nil {
+				return errBlankPagesNotAvailable
+			}
+
 			pdfFormats := gotenberg.PdfFormats{
-				PdfA:  pdfa,
-				PdfUa: pdfua,
+				PdfA:       pdfa,
+				PdfUa:      pdfua,
+				PdfVersion: pdfVersion,
+			}
+
+			err = ValidatePdfVersion(pdfFormats)
+			if err != nil {
+				return err
+			}
+
+			err = ValidateMaxOutputBytes(maxOutputBytes, ghostscriptBinPath, pdfFormats)
+			if err != nil {
+				return err
+			}
+
+			// The merge engines may not keep the page labels of the PDFs,
+			// so that the resulting PDF gets them afterward.
+			labels := pageLabels
+			pageCount := 0
+			if labels == nil && ctx.ExtensionEnabled(api.ExtensionPageLabels) {
+				labels, pageCount, err = MergedPageLabels(ctx, ctx.Log(), engine, inputPaths)
+				if err != nil {
+					return fmt.Errorf("get page labels: %w", err)
+				}
+			}
+
+			// Each PDF has a top-level bookmark, with its outline as
+			// children.
+			var bookmarks []pdfcpu.Bookmark
+			if preserveBookmarks {
+				bookmarks, pageCount, err = mergedBookmarks(ctx, ctx.Log(), engine, inputPaths, bookmarkTitles)
+				if err != nil {
+					return fmt.Errorf("get bookmarks: %w", err)
+				}
+			}
+
+			// The report of the duplicate pages tells which PDFs they come
+			// from.
+			var (
+				filenames  []string
+				pageCounts []int
+			)
+
+			if removeDuplicatePages {
+				filenames, pageCounts, err = inputPageCounts(ctx, ctx.Log(), engine, inputPaths)
+				if err != nil {
+					return fmt.Errorf("get page counts: %w", err)
+				}
+
+				pageCount = 0
+				for _, count := range pageCounts {
+					pageCount += count
+				}
+			}
+
+			// The pages removed from the resulting PDF shift its page labels
+			// and its bookmarks. The page labels set by the request are the
+			// ones of the resulting PDF.
+			withoutPages := func(removedPages []int) {
+				if len(removedPages) == 0 {
+					return
+				}
+
+				if pageLabels == nil {
+					labels = PageLabelsWithoutPages(labels, removedPages, pageCount)
+				}
+
+				bookmarks = bookmarksWithoutPages(bookmarks, removedPages, pageCount)
+				pageCount -= len(removedPages)
 			}
 
 			// Alright, let's merge the PDFs.
@@ -52,6 +218,45 @@ func mergeRoute(engine gotenberg.PdfEngine) api.Route {
 				return fmt.Errorf("merge PDFs: %w", err)
 			}
 
+			outputPaths := []string{outputPath}
+
+			if removeDuplicatePages {
+				duplicates, err := RemoveDuplicatePages(ctx, ctx.Log(), imageEngine, outputPath)
+				if err != nil {
+					return fmt.Errorf("remove duplicate pages: %w", err)
+				}
+
+				b, err := json.MarshalIndent(newDuplicatesReport(duplicates, filenames, pageCounts), "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshal duplicates report: %w", err)
+				}
+
+				reportPath := ctx.GeneratePath("duplicates", ".json")
+
+				err = os.WriteFile(reportPath, b, 0o600)
+				if err != nil {
+					return fmt.Errorf("write duplicates report: %w", err)
+				}
+
+				outputPaths = append(outputPaths, reportPath)
+
+				removedPages := make([]int, len(duplicates))
+				for i, duplicate := range duplicates {
+					removedPages[i] = duplicate.Page
+				}
+
+				withoutPages(removedPages)
+			}
+
+			if removeBlankPages {
+				removedPages, err := removeBlankPagesOrFail(ctx, imageEngine, outputPath, "merged PDF", blankPageThreshold)
+				if err != nil {
+					return err
+				}
+
+				withoutPages(removedPages)
+			}
+
 			// So far so good, the PDFs are merged into one unique PDF.
 			// Now, let's check if the client want to convert this result PDF
 			// to specific PDF formats.
@@ -67,14 +272,34 @@ func mergeRoute(engine gotenberg.PdfEngine) api.Route {
 
 				// Important: the output path is now the converted file.
 				outputPath = convertOutputPath
+				outputPaths[0] = outputPath
 			}
 
-			// Last but not least, add the output path to the context so that
-			// the API is able to send it as a response to the client.
+			if labels != nil {
+				err = WritePageLabels(outputPath, labels)
+				if err != nil {
+					return fmt.Errorf("write page labels: %w", err)
+				}
+			}
 
-			err = ctx.AddOutputPaths(outputPath)
+			if bookmarks != nil {
+				err = writeBookmarks(outputPath, bookmarks)
+				if err != nil {
+					return fmt.Errorf("write bookmarks: %w", err)
+				}
+			}
+
+			err = FitSizeOrFail(ctx, ghostscriptBinPath, outputPath, "merged PDF", maxOutputBytes)
 			if err != nil {
-				return fmt.Errorf("add output path: %w", err)
+				return err
+			}
+
+			// Last but not least, add the output paths to the context so
+			// that the API is able to send them as a response to the client.
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
 			}
 
 			return nil
@@ -83,8 +308,10 @@ func mergeRoute(engine gotenberg.PdfEngine) api.Route {
 }
 
 // convertRoute returns an [api.Route] which can convert a PDF to a specific
-// PDF format.
-func convertRoute(engine gotenberg.PdfEngine) api.Route {
+// PDF format. The blank pages may be removed from the PDFs if imageEngine is
+// not nil, and the PDFs may be optimized to fit a size budget if
+// ghostscriptBinPath is set.
+func convertRoute(engine gotenberg.PdfEngine, imageEngine gotenberg.ImageEngine, ghostscriptBinPath string) api.Route {
 	return api.Route{
 		Method:      http.MethodPost,
 		Path:        "/forms/pdfengines/convert",
@@ -94,36 +321,123 @@ func convertRoute(engine gotenberg.PdfEngine) api.Route {
 
 			// Let's get the data from the form and validate them.
 			var (
-				inputPaths []string
-				pdfa       string
-				pdfua      bool
+				inputPaths         []string
+				pdfa               string
+				pdfua              bool
+				pdfVersion         string
+				removeBlankPages   bool
+				blankPageThreshold float64
+				maxOutputBytes     int64
 			)
 
-			err := ctx.FormData().
+			form := ctx.FormData().
 				MandatoryPaths([]string{".pdf"}, &inputPaths).
 				String("pdfa", &pdfa, "").
-				Bool("pdfua", &pdfua, false).
-				Validate()
+				Bool("pdfua", &pdfua, false)
+
+			if ctx.ExtensionEnabled(api.ExtensionRemoveBlankPages) {
+				form.
+					Bool("removeBlankPages", &removeBlankPages, false).
+					Custom("blankPageThreshold", func(value string) error {
+						threshold, err := ParseBlankPageThreshold(value)
+						if err != nil {
+							return err
+						}
+
+						blankPageThreshold = threshold
+
+						return nil
+					})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionPdfVersion) {
+				form.Custom("pdfVersion", func(value string) error {
+					version, err := ParsePdfVersion(value)
+					if err != nil {
+						return err
+					}
+
+					pdfVersion = version
+
+					return nil
+				})
+			}
+
+			if ctx.ExtensionEnabled(api.ExtensionMaxOutputBytes) {
+				form.Custom("maxOutputBytes", func(value string) error {
+					maxBytes, err := ParseMaxOutputBytes(value)
+					if err != nil {
+						return err
+					}
+
+					maxOutputBytes = maxBytes
+
+					return nil
+				})
+			}
+
+			err := form.Validate()
 			if err != nil {
 				return fmt.Errorf("validate form data: %w", err)
 			}
 
+			if removeBlankPages && imageEngine == nil {
+				return errBlankPagesNotAvailable
+			}
+
 			pdfFormats := gotenberg.PdfFormats{
-				PdfA:  pdfa,
-				PdfUa: pdfua,
+				PdfA:       pdfa,
+				PdfUa:      pdfua,
+				PdfVersion: pdfVersion,
+			}
+
+			err = ValidatePdfVersion(pdfFormats)
+			if err != nil {
+				return err
+			}
+
+			err = ValidateMaxOutputBytes(maxOutputBytes, ghostscriptBinPath, pdfFormats)
+			if err != nil {
+				return err
 			}
 
 			zeroValued := gotenberg.PdfFormats{}
-			if pdfFormats == zeroValued {
+			if pdfFormats == zeroValued && !removeBlankPages && maxOutputBytes == 0 {
 				return api.WrapError(
 					errors.New("no PDF formats"),
 					api.NewSentinelHttpError(
 						http.StatusBadRequest,
-						"Invalid form data: either 'pdfa' or 'pdfua' form fields must be provided",
-					),
+						"Invalid form data: either 'pdfa', 'pdfua', 'pdfVersion', 'removeBlankPages' or 'maxOutputBytes' form fields must be provided",
+					).WithCode(api.ErrorCodeInvalidFormData),
 				)
 			}
 
+			if removeBlankPages {
+				for _, inputPath := range inputPaths {
+					_, err = removeBlankPagesOrFail(ctx, imageEngine, inputPath, filepath.Base(inputPath), blankPageThreshold)
+					if err != nil {
+						return err
+					}
+				}
+			}
+
+			// The size budget excludes the PDF formats.
+			if pdfFormats == zeroValued {
+				for _, inputPath := range inputPaths {
+					err = FitSizeOrFail(ctx, ghostscriptBinPath, inputPath, fmt.Sprintf("PDF '%s'", filepath.Base(inputPath)), maxOutputBytes)
+					if err != nil {
+						return err
+					}
+				}
+
+				err = ctx.AddOutputPaths(inputPaths...)
+				if err != nil {
+					return fmt.Errorf("add output paths: %w", err)
+				}
+
+				return nil
+			}
+
 			// Alright, let's convert the PDFs.s
 			outputPaths := make([]string, len(inputPaths))
 
@@ -153,3 +467,265 @@ func convertRoute(engine gotenberg.PdfEngine) api.Route {
 		},
 	}
 }
+
+// textLayerRoute returns an [api.Route] which can add an invisible text layer
+// to a scanned PDF, from the hOCR or ALTO files of an external OCR engine.
+// The OCR files, in alphanumeric order, must have one page for each page of
+// the PDF.
+func textLayerRoute(engine gotenberg.PdfEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/text-layer",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				ocrPaths   []string
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				MandatoryPaths(OcrExtensions, &ocrPaths).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			if len(inputPaths) > 1 {
+				return api.WrapError(
+					fmt.Errorf("got %d PDFs", len(inputPaths)),
+					api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: expected one PDF").WithCode(api.ErrorCodeInvalidFormData),
+				)
+			}
+
+			var pages []ocrPage
+			for _, ocrPath := range ocrPaths {
+				filePages, err := readOcrPages(ocrPath)
+				if err != nil {
+					return api.WrapError(
+						fmt.Errorf("read OCR file '%s': %w", ocrPath, err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: cannot read the OCR file '%s'", filepath.Base(ocrPath))).WithCode(api.ErrorCodeInvalidFormData),
+					)
+				}
+
+				pages = append(pages, filePages...)
+			}
+
+			pageCount, err := engine.PageCount(ctx, ctx.Log(), inputPaths[0])
+			if err != nil {
+				return fmt.Errorf("count pages: %w", err)
+			}
+
+			if len(pages) != pageCount {
+				return api.WrapError(
+					fmt.Errorf("%d OCR pages for %d pages", len(pages), pageCount),
+					api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid form data: the OCR files have %d page(s) while the PDF has %d page(s)", len(pages), pageCount)).WithCode(api.ErrorCodeInvalidFormData),
+				)
+			}
+
+			outputPath := ctx.GeneratePath("", ".pdf")
+
+			err = addTextLayer(inputPaths[0], outputPath, pages)
+			if err != nil {
+				return fmt.Errorf("add text layer: %w", err)
+			}
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// imagesRoute returns an [api.Route] which can render each page of PDFs to an
+// image.
+func imagesRoute(engine gotenberg.ImageEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdfengines/images",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				options    = gotenberg.ImageOptions{
+					Format:     gotenberg.ImageFormatPng,
+					Resolution: 150,
+					Quality:    90,
+				}
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Custom("format", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					if value != gotenberg.ImageFormatPng && value != gotenberg.ImageFormatJpeg && value != gotenberg.ImageFormatTiff {
+						return errors.New("wrong value, expected either 'png', 'jpeg' or 'tiff'")
+					}
+
+					options.Format = value
+
+					return nil
+				}).
+				Custom("resolution", func(value string) error {
+					return intBetween(value, &options.Resolution, 1, 1200)
+				}).
+				Custom("quality", func(value string) error {
+					return intBetween(value, &options.Quality, 1, 100)
+				}).
+				Custom("width", func(value string) error {
+					return intBetween(value, &options.Width, 0, 10000)
+				}).
+				Custom("height", func(value string) error {
+					return intBetween(value, &options.Height, 0, 10000)
+				}).
+				Custom("tiffCompression", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					if options.Format != gotenberg.ImageFormatTiff {
+						return errors.New("compression is for TIFF images, expected the 'tiff' format")
+					}
+
+					switch value {
+					case gotenberg.TiffCompressionNone, gotenberg.TiffCompressionLzw, gotenberg.TiffCompressionDeflate, gotenberg.TiffCompressionPackBits, gotenberg.TiffCompressionG4:
+					default:
+						return errors.New("wrong value, expected either 'none', 'lzw', 'deflate', 'packbits' or 'g4'")
+					}
+
+					options.Compression = value
+
+					return nil
+				}).
+				Custom("multiPage", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return err
+					}
+
+					if b && options.Format != gotenberg.ImageFormatTiff {
+						return errors.New("only TIFF images have several pages, expected the 'tiff' format")
+					}
+
+					options.MultiPage = b
+
+					return nil
+				}).
+				Bool("stripMetadata", &options.StripMetadata, false).
+				Custom("transparentBackground", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return err
+					}
+
+					if b && options.Format == gotenberg.ImageFormatJpeg {
+						return errors.New("JPEG images have no transparency, expected either the 'png' or 'tiff' format")
+					}
+
+					options.Transparent = b
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// Alright, let's render the pages. The images keep the filename
+			// of their PDF.
+			var outputPaths []string
+
+			for _, inputPath := range inputPaths {
+				name := strings.TrimSuffix(filepath.Base(inputPath), filepath.Ext(inputPath))
+
+				paths, err := RenderImages(ctx, engine, options, inputPath, name)
+				if err != nil {
+					return err
+				}
+
+				outputPaths = append(outputPaths, paths...)
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// intBetween parses an integer form field value, between lower and upper
+// inclusive, into target. An empty value keeps the target as is.
+func intBetween(value string, target *int, lower, upper int) error {
+	if value == "" {
+		return nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	if i < lower || i > upper {
+		return fmt.Errorf("value is not between %d and %d", lower, upper)
+	}
+
+	*target = i
+
+	return nil
+}
+
+// errDuplicatePagesNotAvailable happens if a request asks for removing the
+// duplicate pages while no image engine is available.
+var errDuplicatePagesNotAvailable = api.WrapError(
+	errors.New("no image engine"),
+	api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: removing duplicate pages is not available").WithCode(api.ErrorCodeInvalidFormData),
+)
+
+// errBlankPagesNotAvailable happens if a request asks for removing the blank
+// pages while no image engine is available.
+var errBlankPagesNotAvailable = api.WrapError(
+	errors.New("no image engine"),
+	api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: removing blank pages is not available").WithCode(api.ErrorCodeInvalidFormData),
+)
+
+// removeBlankPagesOrFail removes the blank pages of a PDF in place, and
+// converts the errors to HTTP errors. It returns the numbers of the removed
+// pages.
+func removeBlankPagesOrFail(ctx *api.Context, imageEngine gotenberg.ImageEngine, path, name string, threshold float64) ([]int, error) {
+	removedPages, err := RemoveBlankPages(ctx, ctx.Log(), imageEngine, path, threshold)
+	if err != nil {
+		if errors.Is(err, ErrAllPagesBlank) {
+			return nil, api.WrapError(
+				fmt.Errorf("remove blank pages: %w", err),
+				api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("All pages of the %s are blank", name)).WithCode(api.ErrorCodeAllPagesBlank),
+			)
+		}
+
+		return nil, fmt.Errorf("remove blank pages: %w", err)
+	}
+
+	return removedPages, nil
+}
@@ -0,0 +1,173 @@
+package pdfengines
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// optimizationPasses are the Ghostscript presets of the optimization passes,
+// from the lightest to the most aggressive: each one downsamples the images
+// further, to 300, 150 and 72 DPI, and recompresses them.
+var optimizationPasses = []string{"/printer", "/ebook", "/screen"}
+
+// OutputTooLargeError happens if a PDF is still larger than its size budget
+// after all the optimization passes.
+type OutputTooLargeError struct {
+	// Size is the smallest size reached, in bytes.
+	Size int64
+
+	// MaxBytes is the size budget, in bytes.
+	MaxBytes int64
+}
+
+// Error returns the description of the error.
+func (err OutputTooLargeError) Error() string {
+	return fmt.Sprintf("PDF of %d bytes larger than %d bytes", err.Size, err.MaxBytes)
+}
+
+// ParseMaxOutputBytes parses the value of a "maxOutputBytes" form field,
+// i.e., a number of bytes. Zero means no size budget.
+func ParseMaxOutputBytes(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	maxBytes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if maxBytes < 0 {
+		return 0, errors.New("value is negative")
+	}
+
+	return maxBytes, nil
+}
+
+// ValidateMaxOutputBytes returns an error if a request sets a size budget
+// while Ghostscript is not available, or along PDF formats the optimization
+// passes would not keep.
+func ValidateMaxOutputBytes(maxBytes int64, ghostscriptBinPath string, formats gotenberg.PdfFormats) error {
+	if maxBytes == 0 {
+		return nil
+	}
+
+	if ghostscriptBinPath == "" {
+		return api.WrapError(
+			errors.New("ghostscript binary path not set"),
+			api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: 'maxOutputBytes' is not available").WithCode(api.ErrorCodeInvalidFormData),
+		)
+	}
+
+	zeroValued := gotenberg.PdfFormats{}
+	if formats != zeroValued {
+		return api.WrapError(
+			errors.New("size budget with PDF formats"),
+			api.NewSentinelHttpError(http.StatusBadRequest, "Invalid form data: 'maxOutputBytes' is not compatible with 'pdfa', 'pdfua' and 'pdfVersion'").WithCode(api.ErrorCodeInvalidFormData),
+		)
+	}
+
+	return nil
+}
+
+// FitSize optimizes in place a PDF larger than a size budget, thanks to
+// Ghostscript, with more and more aggressive passes until it fits. It returns
+// an [OutputTooLargeError] if it does not, and leaves the PDF as is.
+func FitSize(ctx context.Context, logger *zap.Logger, binPath, path string, maxBytes int64) error {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat PDF: %w", err)
+	}
+
+	if stat.Size() <= maxBytes {
+		return nil
+	}
+
+	smallest := stat.Size()
+	optimizedPath := path + ".optimized"
+
+	defer func() {
+		err := os.RemoveAll(optimizedPath)
+		if err != nil {
+			logger.Error(fmt.Sprintf("remove optimized PDF: %s", err))
+		}
+	}()
+
+	// Each pass starts from the PDF as is, as the recompressions of the
+	// previous passes would add up.
+	for _, preset := range optimizationPasses {
+		cmd, err := gotenberg.CommandContext(ctx, logger, binPath,
+			"-dSAFER",
+			"-dBATCH",
+			"-dNOPAUSE",
+			"-dQUIET",
+			"-sDEVICE=pdfwrite",
+			"-dPDFSETTINGS="+preset,
+			"-sOutputFile="+optimizedPath,
+			path,
+		)
+		if err != nil {
+			return fmt.Errorf("create command: %w", err)
+		}
+
+		_, err = cmd.Exec()
+		if err != nil {
+			return fmt.Errorf("optimize PDF with %s: %w", preset, err)
+		}
+
+		stat, err = os.Stat(optimizedPath)
+		if err != nil {
+			return fmt.Errorf("stat optimized PDF: %w", err)
+		}
+
+		logger.Debug(fmt.Sprintf("'%s' optimized with %s: %d bytes", path, preset, stat.Size()))
+
+		if stat.Size() <= maxBytes {
+			err = os.Rename(optimizedPath, path)
+			if err != nil {
+				return fmt.Errorf("rename optimized PDF: %w", err)
+			}
+
+			return nil
+		}
+
+		smallest = min(smallest, stat.Size())
+	}
+
+	return OutputTooLargeError{Size: smallest, MaxBytes: maxBytes}
+}
+
+// FitSizeOrFail optimizes in place a PDF larger than a size budget, and
+// converts the errors to HTTP errors.
+func FitSizeOrFail(ctx *api.Context, binPath, path, name string, maxBytes int64) error {
+	if maxBytes == 0 {
+		return nil
+	}
+
+	err := FitSize(ctx, ctx.Log(), binPath, path, maxBytes)
+	if err == nil {
+		return nil
+	}
+
+	var tooLargeErr OutputTooLargeError
+	if errors.As(err, &tooLargeErr) {
+		return api.WrapError(
+			fmt.Errorf("fit size: %w", err),
+			api.NewSentinelHttpError(
+				http.StatusUnprocessableEntity,
+				fmt.Sprintf("The %s is still %d bytes once optimized, above the 'maxOutputBytes' of %d bytes", name, tooLargeErr.Size, tooLargeErr.MaxBytes),
+			).WithCode(api.ErrorCodeOutputTooLarge),
+		)
+	}
+
+	return fmt.Errorf("fit size: %w", err)
+}
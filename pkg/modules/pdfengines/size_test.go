@@ -0,0 +1,187 @@
+package pdfengines
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakeOptimizer writes a script which mimics Ghostscript, i.e., which writes
+// an output file of 800, 400 or 200 bytes according to the preset.
+func fakeOptimizer(t *testing.T) string {
+	script := `for arg; do
+	case "$arg" in
+	-sOutputFile=*) out="${arg#-sOutputFile=}";;
+	-dPDFSETTINGS=/printer) size=800;;
+	-dPDFSETTINGS=/ebook) size=400;;
+	-dPDFSETTINGS=/screen) size=200;;
+	esac
+done
+head -c "$size" /dev/zero > "$out"
+`
+	return gotenbergtest.FakeBinary(t, "gs", script)
+}
+
+func TestParseMaxOutputBytes(t *testing.T) {
+	for _, tc := range []struct {
+		value       string
+		expect      int64
+		expectError bool
+	}{
+		{value: "", expect: 0},
+		{value: "0", expect: 0},
+		{value: "1048576", expect: 1048576},
+		{value: "-1", expectError: true},
+		{value: "1MB", expectError: true},
+	} {
+		actual, err := ParseMaxOutputBytes(tc.value)
+
+		if tc.expectError && err == nil {
+			t.Errorf("expected error for '%s' but got none", tc.value)
+		}
+
+		if !tc.expectError && err != nil {
+			t.Errorf("expected no error for '%s' but got: %v", tc.value, err)
+		}
+
+		if actual != tc.expect {
+			t.Errorf("expected %d for '%s' but got %d", tc.expect, tc.value, actual)
+		}
+	}
+}
+
+func TestValidateMaxOutputBytes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario           string
+		maxBytes           int64
+		ghostscriptBinPath string
+		formats            gotenberg.PdfFormats
+		expectError        bool
+	}{
+		{
+			scenario: "no size budget",
+		},
+		{
+			scenario: "no size budget without Ghostscript and with PDF formats",
+			formats:  gotenberg.PdfFormats{PdfA: gotenberg.PdfA3b},
+		},
+		{
+			scenario:    "size budget without Ghostscript",
+			maxBytes:    1024,
+			expectError: true,
+		},
+		{
+			scenario:           "size budget with PDF formats",
+			maxBytes:           1024,
+			ghostscriptBinPath: "/usr/bin/gs",
+			formats:            gotenberg.PdfFormats{PdfUa: true},
+			expectError:        true,
+		},
+		{
+			scenario:           "size budget",
+			maxBytes:           1024,
+			ghostscriptBinPath: "/usr/bin/gs",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := ValidateMaxOutputBytes(tc.maxBytes, tc.ghostscriptBinPath, tc.formats)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestFitSize(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		size           int
+		maxBytes       int64
+		expectSize     int64
+		expectError    bool
+		expectTooLarge bool
+	}{
+		{
+			scenario:   "already within the size budget",
+			size:       1000,
+			maxBytes:   1000,
+			expectSize: 1000,
+		},
+		{
+			scenario:   "fits after the first pass",
+			size:       1000,
+			maxBytes:   900,
+			expectSize: 800,
+		},
+		{
+			scenario:   "fits after the last pass",
+			size:       1000,
+			maxBytes:   300,
+			expectSize: 200,
+		},
+		{
+			scenario:       "too large",
+			size:           1000,
+			maxBytes:       100,
+			expectSize:     1000,
+			expectError:    true,
+			expectTooLarge: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "document.pdf")
+
+			err := os.WriteFile(path, make([]byte, tc.size), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = FitSize(context.Background(), zap.NewNop(), fakeOptimizer(t), path, tc.maxBytes)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var tooLargeErr OutputTooLargeError
+			if tc.expectTooLarge {
+				if !errors.As(err, &tooLargeErr) {
+					t.Fatalf("expected an OutputTooLargeError but got: %v", err)
+				}
+
+				if tooLargeErr.Size != 200 {
+					t.Errorf("expected a smallest size of 200 bytes but got %d", tooLargeErr.Size)
+				}
+			}
+
+			stat, err := os.Stat(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if stat.Size() != tc.expectSize {
+				t.Errorf("expected %d bytes but got %d", tc.expectSize, stat.Size())
+			}
+
+			_, err = os.Stat(path + ".optimized")
+			if !os.IsNotExist(err) {
+				t.Errorf("expected the optimized PDF to be removed but got: %v", err)
+			}
+		})
+	}
+}
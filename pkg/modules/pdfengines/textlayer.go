@@ -0,0 +1,495 @@
+package pdfengines
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	pdfcpuTypes "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"golang.org/x/net/html"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// OcrExtensions are the extensions of the OCR files: hOCR for .hocr and
+// .html files, ALTO for .xml files.
+var OcrExtensions = []string{".hocr", ".html", ".xml"}
+
+const (
+	// textLayerFont is the name of the font of the text layer within the
+	// resources of the pages.
+	textLayerFont = "GotenbergOcr"
+
+	// textLayerCharWidth is the average width of a character of the font of
+	// the text layer, relative to its size, so that each word spans its
+	// bounding box.
+	textLayerCharWidth = 0.5
+)
+
+// ocrWord is a word recognized by an OCR engine, with its bounding box in
+// the coordinates of the image of its page, i.e., from the top left corner.
+type ocrWord struct {
+	text           string
+	x0, y0, x1, y1 float64
+}
+
+// ocrPage is a page recognized by an OCR engine. Its size is the one of its
+// image, whatever the unit.
+type ocrPage struct {
+	width, height float64
+	words         []ocrWord
+}
+
+// readOcrPages reads the pages of an hOCR or ALTO file.
+func readOcrPages(path string) ([]ocrPage, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open OCR file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var pages []ocrPage
+	if strings.ToLower(filepath.Ext(path)) == ".xml" {
+		pages, err = readAlto(f)
+	} else {
+		pages, err = readHocr(f)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, page := range pages {
+		if page.width <= 0 || page.height <= 0 {
+			return nil, fmt.Errorf("page %d has no size", i+1)
+		}
+	}
+
+	return pages, nil
+}
+
+// readHocr reads the pages of an hOCR file, i.e., the ocr_page elements and
+// their ocrx_word elements.
+func readHocr(r io.Reader) ([]ocrPage, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse hOCR: %w", err)
+	}
+
+	var (
+		pages            []ocrPage
+		originX, originY float64
+	)
+
+	var walk func(n *html.Node) error
+	walk = func(n *html.Node) error {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+
+			classes := strings.Fields(attr(child, "class"))
+
+			switch {
+			case slices.Contains(classes, "ocr_page"):
+				x0, y0, x1, y1, err := parseBbox(attr(child, "title"))
+				if err != nil {
+					return fmt.Errorf("page %d: %w", len(pages)+1, err)
+				}
+
+				originX, originY = x0, y0
+				pages = append(pages, ocrPage{width: x1 - x0, height: y1 - y0})
+			case slices.Contains(classes, "ocrx_word") && len(pages) > 0:
+				text := strings.TrimSpace(textContent(child))
+				if text == "" {
+					continue
+				}
+
+				x0, y0, x1, y1, err := parseBbox(attr(child, "title"))
+				if err != nil {
+					return fmt.Errorf("word '%s' of page %d: %w", text, len(pages), err)
+				}
+
+				page := &pages[len(pages)-1]
+				page.words = append(page.words, ocrWord{
+					text: text,
+					x0:   x0 - originX,
+					y0:   y0 - originY,
+					x1:   x1 - originX,
+					y1:   y1 - originY,
+				})
+
+				continue
+			}
+
+			err := walk(child)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	err = walk(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return pages, nil
+}
+
+// parseBbox parses the bounding box of the title of an hOCR element, e.g.,
+// "bbox 36 92 618 116; x_wconf 95".
+func parseBbox(title string) (float64, float64, float64, float64, error) {
+	for _, property := range strings.Split(title, ";") {
+		fields := strings.Fields(property)
+		if len(fields) == 0 || fields[0] != "bbox" {
+			continue
+		}
+
+		if len(fields) != 5 {
+			return 0, 0, 0, 0, fmt.Errorf("wrong bounding box '%s'", strings.TrimSpace(property))
+		}
+
+		var coordinates [4]float64
+		for i, field := range fields[1:] {
+			coordinate, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return 0, 0, 0, 0, fmt.Errorf("wrong bounding box '%s'", strings.TrimSpace(property))
+			}
+
+			coordinates[i] = coordinate
+		}
+
+		return coordinates[0], coordinates[1], coordinates[2], coordinates[3], nil
+	}
+
+	return 0, 0, 0, 0, errors.New("no bounding box")
+}
+
+// readAlto reads the pages of an ALTO file, i.e., the Page elements and
+// their String elements.
+func readAlto(r io.Reader) ([]ocrPage, error) {
+	decoder := xml.NewDecoder(r)
+
+	var pages []ocrPage
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("decode ALTO: %w", err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch element.Name.Local {
+		case "Page":
+			var page ocrPage
+
+			for _, a := range element.Attr {
+				switch a.Name.Local {
+				case "WIDTH":
+					page.width, err = strconv.ParseFloat(a.Value, 64)
+				case "HEIGHT":
+					page.height, err = strconv.ParseFloat(a.Value, 64)
+				}
+
+				if err != nil {
+					return nil, fmt.Errorf("page %d: wrong %s '%s'", len(pages)+1, a.Name.Local, a.Value)
+				}
+			}
+
+			pages = append(pages, page)
+		case "String":
+			if len(pages) == 0 {
+				continue
+			}
+
+			var (
+				word                      ocrWord
+				hpos, vpos, width, height float64
+			)
+
+			for _, a := range element.Attr {
+				switch a.Name.Local {
+				case "CONTENT":
+					word.text = strings.TrimSpace(a.Value)
+				case "HPOS":
+					hpos, err = strconv.ParseFloat(a.Value, 64)
+				case "VPOS":
+					vpos, err = strconv.ParseFloat(a.Value, 64)
+				case "WIDTH":
+					width, err = strconv.ParseFloat(a.Value, 64)
+				case "HEIGHT":
+					height, err = strconv.ParseFloat(a.Value, 64)
+				}
+
+				if err != nil {
+					return nil, fmt.Errorf("string of page %d: wrong %s '%s'", len(pages), a.Name.Local, a.Value)
+				}
+			}
+
+			if word.text == "" {
+				continue
+			}
+
+			word.x0, word.y0, word.x1, word.y1 = hpos, vpos, hpos+width, vpos+height
+
+			page := &pages[len(pages)-1]
+			page.words = append(page.words, word)
+		}
+	}
+
+	return pages, nil
+}
+
+// textLayerContent returns the content stream which draws the invisible
+// words of a page, given the size of the page as displayed, i.e., once
+// rotated.
+func textLayerContent(page ocrPage, width, height float64) ([]byte, error) {
+	scaleX := width / page.width
+	scaleY := height / page.height
+
+	// The standard fonts only cover the Windows-1252 characters.
+	encoder := encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder())
+
+	var b bytes.Buffer
+	b.WriteString("BT\n3 Tr\n")
+
+	for _, word := range page.words {
+		size := (word.y1 - word.y0) * scaleY
+		if size <= 0 || word.x1 <= word.x0 {
+			continue
+		}
+
+		text, err := encoder.String(word.text)
+		if err != nil {
+			return nil, fmt.Errorf("encode word '%s': %w", word.text, err)
+		}
+
+		// The horizontal scaling stretches the word to its bounding box.
+		scaling := 100 * (word.x1 - word.x0) * scaleX / (size * textLayerCharWidth * float64(len(text)))
+
+		fmt.Fprintf(&b, "/%s %s Tf\n%s Tz\n1 0 0 1 %s %s Tm\n<%X> Tj\n",
+			textLayerFont, formatNumber(size),
+			formatNumber(scaling),
+			formatNumber(word.x0*scaleX), formatNumber(height-word.y1*scaleY),
+			text,
+		)
+	}
+
+	b.WriteString("ET\n")
+
+	return b.Bytes(), nil
+}
+
+// formatNumber formats a number of a content stream.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+// pageTransform returns the operator which maps the coordinates of a page
+// as displayed, i.e., once rotated, to its coordinates, given its media box
+// and its rotation.
+func pageTransform(mediaBox *pdfcpuTypes.Rectangle, rotate int) string {
+	width, height := mediaBox.Width(), mediaBox.Height()
+	translate := fmt.Sprintf("1 0 0 1 %s %s cm\n", formatNumber(mediaBox.LL.X), formatNumber(mediaBox.LL.Y))
+
+	switch (rotate%360 + 360) % 360 {
+	case 90:
+		return translate + fmt.Sprintf("0 1 -1 0 %s 0 cm\n", formatNumber(width))
+	case 180:
+		return translate + fmt.Sprintf("-1 0 0 -1 %s %s cm\n", formatNumber(width), formatNumber(height))
+	case 270:
+		return translate + fmt.Sprintf("0 -1 1 0 0 %s cm\n", formatNumber(height))
+	default:
+		return translate
+	}
+}
+
+// addTextLayer writes a PDF with an invisible text layer over its first
+// pages, one for each OCR page, so that the words are searchable and
+// selectable at the place of their images.
+func addTextLayer(inputPath, outputPath string, pages []ocrPage) error {
+	pdfCtx, err := pdfcpuAPI.ReadContextFile(inputPath)
+	if err != nil {
+		return fmt.Errorf("read PDF: %w", err)
+	}
+
+	if len(pages) > pdfCtx.PageCount {
+		return fmt.Errorf("%d OCR pages for %d pages", len(pages), pdfCtx.PageCount)
+	}
+
+	// Invisible text does not require embedding the font, even for PDF/A.
+	font, err := pdfCtx.IndRefForNewObject(pdfcpuTypes.Dict{
+		"Type":     pdfcpuTypes.Name("Font"),
+		"Subtype":  pdfcpuTypes.Name("Type1"),
+		"BaseFont": pdfcpuTypes.Name("Helvetica"),
+		"Encoding": pdfcpuTypes.Name("WinAnsiEncoding"),
+	})
+	if err != nil {
+		return fmt.Errorf("add font: %w", err)
+	}
+
+	for i, page := range pages {
+		err = addPageTextLayer(pdfCtx, i+1, page, *font)
+		if err != nil {
+			return fmt.Errorf("add text layer to page %d: %w", i+1, err)
+		}
+	}
+
+	err = pdfcpuAPI.WriteContextFile(pdfCtx, outputPath)
+	if err != nil {
+		return fmt.Errorf("write PDF: %w", err)
+	}
+
+	return nil
+}
+
+// addPageTextLayer adds the invisible words of an OCR page to a page of a
+// PDF.
+func addPageTextLayer(pdfCtx *pdfcpuModel.Context, pageNr int, page ocrPage, font pdfcpuTypes.IndirectRef) error {
+	d, _, inherited, err := pdfCtx.PageDict(pageNr, true)
+	if err != nil {
+		return fmt.Errorf("get page: %w", err)
+	}
+
+	if d == nil || inherited == nil || inherited.MediaBox == nil {
+		return errors.New("page without media box")
+	}
+
+	resources, err := pdfCtx.DereferenceDict(d["Resources"])
+	if err != nil {
+		return fmt.Errorf("dereference resources: %w", err)
+	}
+
+	if resources == nil {
+		resources = pdfcpuTypes.NewDict()
+		d.Update("Resources", resources)
+	}
+
+	fonts, err := pdfCtx.DereferenceDict(resources["Font"])
+	if err != nil {
+		return fmt.Errorf("dereference fonts: %w", err)
+	}
+
+	if fonts == nil {
+		fonts = pdfcpuTypes.NewDict()
+		resources.Update("Font", fonts)
+	}
+
+	fonts.Update(textLayerFont, font)
+
+	width, height := inherited.MediaBox.Width(), inherited.MediaBox.Height()
+	if inherited.Rotate%180 != 0 {
+		width, height = height, width
+	}
+
+	content, err := textLayerContent(page, width, height)
+	if err != nil {
+		return err
+	}
+
+	// The current content may not restore the graphics state, hence the
+	// q and Q operators around it.
+	before, err := newContentStream(pdfCtx, []byte("q\n"))
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	b.WriteString("Q\nq\n")
+	b.WriteString(pageTransform(inherited.MediaBox, inherited.Rotate))
+	b.Write(content)
+	b.WriteString("Q\n")
+
+	after, err := newContentStream(pdfCtx, b.Bytes())
+	if err != nil {
+		return err
+	}
+
+	contents := pdfcpuTypes.Array{*before}
+
+	current, found := d.Find("Contents")
+	if found {
+		obj, err := pdfCtx.Dereference(current)
+		if err != nil {
+			return fmt.Errorf("dereference contents: %w", err)
+		}
+
+		if array, ok := obj.(pdfcpuTypes.Array); ok {
+			contents = append(contents, array...)
+		} else {
+			contents = append(contents, current)
+		}
+	}
+
+	d.Update("Contents", append(contents, *after))
+
+	return nil
+}
+
+// newContentStream adds a content stream to a PDF.
+func newContentStream(pdfCtx *pdfcpuModel.Context, content []byte) (*pdfcpuTypes.IndirectRef, error) {
+	sd, err := pdfCtx.NewStreamDictForBuf(content)
+	if err != nil {
+		return nil, fmt.Errorf("create content stream: %w", err)
+	}
+
+	err = sd.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("encode content stream: %w", err)
+	}
+
+	ref, err := pdfCtx.IndRefForNewObject(*sd)
+	if err != nil {
+		return nil, fmt.Errorf("add content stream: %w", err)
+	}
+
+	return ref, nil
+}
+
+// attr returns the value of an attribute of an HTML element.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+// textContent returns the text of an HTML element and of its descendants.
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(textContent(child))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,266 @@
+package pdfengines
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	pdfcpuTypes "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+func TestParseBbox(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		title       string
+		expect      [4]float64
+		expectError bool
+	}{
+		{
+			scenario: "bounding box only",
+			title:    "bbox 0 0 1240 1754",
+			expect:   [4]float64{0, 0, 1240, 1754},
+		},
+		{
+			scenario: "bounding box with other properties",
+			title:    "image \"page.png\"; bbox 36 92 618 116; x_wconf 95",
+			expect:   [4]float64{36, 92, 618, 116},
+		},
+		{
+			scenario:    "no bounding box",
+			title:       "x_wconf 95",
+			expectError: true,
+		},
+		{
+			scenario:    "wrong bounding box",
+			title:       "bbox 36 92 foo 116",
+			expectError: true,
+		},
+		{
+			scenario:    "incomplete bounding box",
+			title:       "bbox 36 92",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			x0, y0, x1, y1, err := parseBbox(tc.title)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			actual := [4]float64{x0, y0, x1, y1}
+			if actual != tc.expect {
+				t.Errorf("expected %v but got %v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestReadOcrPages(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		filename    string
+		content     string
+		expect      []ocrPage
+		expectError bool
+	}{
+		{
+			scenario: "hOCR",
+			filename: "page.hocr",
+			content: `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><body>
+<div class="ocr_page" id="page_1" title="image &quot;scan.png&quot;; bbox 0 0 1240 1754; ppageno 0">
+<span class="ocr_line" title="bbox 100 100 600 140">
+<span class="ocrx_word" title="bbox 100 100 300 140; x_wconf 96"><strong>Café</strong></span>
+<span class="ocrx_word" title="bbox 320 100 600 140; x_wconf 91">Gotenberg</span>
+<span class="ocrx_word" title="bbox 620 100 640 140; x_wconf 10"> </span>
+</span>
+</div>
+<div class="ocr_page" title="bbox 10 20 1250 1774">
+<span class="ocrx_word" title="bbox 110 120 310 160">Page</span>
+</div>
+</body></html>`,
+			expect: []ocrPage{
+				{
+					width:  1240,
+					height: 1754,
+					words: []ocrWord{
+						{text: "Café", x0: 100, y0: 100, x1: 300, y1: 140},
+						{text: "Gotenberg", x0: 320, y0: 100, x1: 600, y1: 140},
+					},
+				},
+				{
+					width:  1240,
+					height: 1754,
+					words: []ocrWord{
+						{text: "Page", x0: 100, y0: 100, x1: 300, y1: 140},
+					},
+				},
+			},
+		},
+		{
+			scenario:    "hOCR page without bounding box",
+			filename:    "page.html",
+			content:     `<html><body><div class="ocr_page" title="ppageno 0"></div></body></html>`,
+			expectError: true,
+		},
+		{
+			scenario: "ALTO",
+			filename: "page.xml",
+			content: `<?xml version="1.0" encoding="UTF-8"?>
+<alto xmlns="http://www.loc.gov/standards/alto/ns-v4#">
+<Layout><Page ID="p1" WIDTH="2480" HEIGHT="3508" PHYSICAL_IMG_NR="1"><PrintSpace>
+<TextBlock><TextLine>
+<String CONTENT="Hello" HPOS="200" VPOS="300" WIDTH="400" HEIGHT="80"/>
+<SP/>
+<String CONTENT="World" HPOS="640" VPOS="300" WIDTH="420" HEIGHT="80"/>
+</TextLine></TextBlock>
+</PrintSpace></Page></Layout>
+</alto>`,
+			expect: []ocrPage{
+				{
+					width:  2480,
+					height: 3508,
+					words: []ocrWord{
+						{text: "Hello", x0: 200, y0: 300, x1: 600, y1: 380},
+						{text: "World", x0: 640, y0: 300, x1: 1060, y1: 380},
+					},
+				},
+			},
+		},
+		{
+			scenario:    "ALTO page without size",
+			filename:    "page.xml",
+			content:     `<alto><Layout><Page ID="p1"/></Layout></alto>`,
+			expectError: true,
+		},
+		{
+			scenario:    "ALTO string with a wrong position",
+			filename:    "page.xml",
+			content:     `<alto><Layout><Page WIDTH="2480" HEIGHT="3508"><String CONTENT="Hello" HPOS="foo"/></Page></Layout></alto>`,
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+
+			err := os.WriteFile(path, []byte(tc.content), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			actual, err := readOcrPages(path)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expect) {
+				t.Errorf("expected %+v but got %+v", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestTextLayerContent(t *testing.T) {
+	page := ocrPage{
+		width:  1000,
+		height: 2000,
+		words: []ocrWord{
+			{text: "Café", x0: 100, y0: 200, x1: 300, y1: 240},
+			{text: "Empty", x0: 100, y0: 240, x1: 100, y1: 280},
+		},
+	}
+
+	actual, err := textLayerContent(page, 500, 1000)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	// 20 points high, 100 points wide for 4 characters: 100 / (20 * 0.5 * 4).
+	expect := "BT\n3 Tr\n/GotenbergOcr 20.00 Tf\n250.00 Tz\n1 0 0 1 50.00 880.00 Tm\n<436166E9> Tj\nET\n"
+	if string(actual) != expect {
+		t.Errorf("expected %q but got %q", expect, actual)
+	}
+}
+
+func TestPageTransform(t *testing.T) {
+	mediaBox := pdfcpuTypes.NewRectangle(0, 0, 600, 800)
+
+	for _, tc := range []struct {
+		rotate int
+		expect string
+	}{
+		{rotate: 0, expect: "1 0 0 1 0.00 0.00 cm\n"},
+		{rotate: 90, expect: "1 0 0 1 0.00 0.00 cm\n0 1 -1 0 600.00 0 cm\n"},
+		{rotate: 180, expect: "1 0 0 1 0.00 0.00 cm\n-1 0 0 -1 600.00 800.00 cm\n"},
+		{rotate: -90, expect: "1 0 0 1 0.00 0.00 cm\n0 -1 1 0 0 800.00 cm\n"},
+	} {
+		actual := pageTransform(mediaBox, tc.rotate)
+		if actual != tc.expect {
+			t.Errorf("expected %q for a rotation of %d but got %q", tc.expect, tc.rotate, actual)
+		}
+	}
+}
+
+func TestAddTextLayer(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+
+	dirPath := t.TempDir()
+	outputPath := filepath.Join(dirPath, "output.pdf")
+	pages := []ocrPage{
+		{
+			width:  1240,
+			height: 1754,
+			words:  []ocrWord{{text: "Gotenberg", x0: 100, y0: 100, x1: 300, y1: 140}},
+		},
+	}
+
+	err := addTextLayer("../../../test/testdata/pdfengines/sample1.pdf", outputPath, pages)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	pdfCtx, err := pdfcpuAPI.ReadContextFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	d, _, _, err := pdfCtx.PageDict(1, true)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	resources, err := pdfCtx.DereferenceDict(d["Resources"])
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	fonts, err := pdfCtx.DereferenceDict(resources["Font"])
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if _, ok := fonts.Find(textLayerFont); !ok {
+		t.Errorf("expected font '%s' in %v", textLayerFont, fonts)
+	}
+
+	tooManyPages := make([]ocrPage, pdfCtx.PageCount+1)
+
+	err = addTextLayer("../../../test/testdata/pdfengines/sample1.pdf", filepath.Join(dirPath, "error.pdf"), tooManyPages)
+	if err == nil || !strings.Contains(err.Error(), "OCR pages") {
+		t.Errorf("expected an error about the OCR pages but got: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package pdfengines
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// PdfVersions are the versions of the PDF specification a PDF may be
+// converted to.
+var PdfVersions = []string{"1.4", "1.5", "1.6", "1.7", "2.0"}
+
+// ParsePdfVersion parses the value of a "pdfVersion" form field, e.g., "1.4".
+func ParsePdfVersion(value string) (string, error) {
+	if value == "" || slices.Contains(PdfVersions, value) {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("wrong value, expected one of %s", strings.Join(PdfVersions, ", "))
+}
+
+// ValidatePdfVersion returns an error if the PDF formats have both a PDF
+// version and a PDF/A standard, as the latter mandates its version.
+func ValidatePdfVersion(formats gotenberg.PdfFormats) error {
+	if formats.PdfVersion == "" || formats.PdfA == "" {
+		return nil
+	}
+
+	return api.WrapError(
+		errors.New("PDF version with PDF/A"),
+		api.NewSentinelHttpError(
+			http.StatusBadRequest,
+			fmt.Sprintf("Invalid form data: 'pdfVersion' is not compatible with %s", formats.PdfA),
+		).WithCode(api.ErrorCodeInvalidFormData),
+	)
+}
@@ -0,0 +1,92 @@
+package pdfengines
+
+import (
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestParsePdfVersion(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		value       string
+		expect      string
+		expectError bool
+	}{
+		{
+			scenario: "no version",
+			value:    "",
+			expect:   "",
+		},
+		{
+			scenario: "PDF 1.4",
+			value:    "1.4",
+			expect:   "1.4",
+		},
+		{
+			scenario: "PDF 2.0",
+			value:    "2.0",
+			expect:   "2.0",
+		},
+		{
+			scenario:    "unknown version",
+			value:       "1.3",
+			expectError: true,
+		},
+		{
+			scenario:    "invalid version",
+			value:       "foo",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := ParsePdfVersion(tc.value)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func TestValidatePdfVersion(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		formats     gotenberg.PdfFormats
+		expectError bool
+	}{
+		{
+			scenario: "no PDF version",
+			formats:  gotenberg.PdfFormats{PdfA: gotenberg.PdfA1b},
+		},
+		{
+			scenario: "PDF version with PDF/UA",
+			formats:  gotenberg.PdfFormats{PdfUa: true, PdfVersion: "1.7"},
+		},
+		{
+			scenario:    "PDF version with PDF/A",
+			formats:     gotenberg.PdfFormats{PdfA: gotenberg.PdfA2b, PdfVersion: "1.4"},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := ValidatePdfVersion(tc.formats)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package pdftohtml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// ErrInvalidPdf happens if pdftohtml cannot convert a PDF.
+var ErrInvalidPdf = errors.New("invalid PDF")
+
+const (
+	// modeReflow merges the lines of text into paragraphs, which reflow with
+	// the width of the browser.
+	modeReflow = "reflow"
+
+	// modeLayout positions the text over a background image of each page,
+	// as in the PDF.
+	modeLayout = "layout"
+)
+
+// convertOptions gathers the available options for converting a PDF to HTML.
+type convertOptions struct {
+	// Mode is either modeReflow or modeLayout.
+	Mode string
+
+	// Images defines whether to keep the images. They are embedded in the
+	// HTML document as data URLs.
+	Images bool
+
+	// FirstPage and LastPage are the range of pages to convert, from 1. If
+	// zero, the range starts with the first page or ends with the last one.
+	FirstPage, LastPage int
+
+	// Zoom is the zoom factor of the layout mode.
+	Zoom float64
+}
+
+// defaultConvertOptions returns the default values for [convertOptions].
+func defaultConvertOptions() convertOptions {
+	return convertOptions{
+		Mode:      modeReflow,
+		Images:    true,
+		FirstPage: 0,
+		LastPage:  0,
+		Zoom:      1.5,
+	}
+}
+
+// convert converts a PDF to a single, self-contained HTML document.
+func convert(ctx context.Context, logger *zap.Logger, binPath, inputPath, outputPath string, opts convertOptions) error {
+	args := []string{
+		"-s",
+		"-noframes",
+		"-dataurls",
+		"-q",
+	}
+
+	if opts.Mode == modeLayout {
+		args = append(args, "-c", "-zoom", strconv.FormatFloat(opts.Zoom, 'f', -1, 64))
+	}
+
+	if !opts.Images {
+		args = append(args, "-i")
+	}
+
+	args = append(args, pageRangeArgs(opts.FirstPage, opts.LastPage)...)
+
+	args = append(args, inputPath, outputPath)
+
+	return run(ctx, logger, binPath, args...)
+}
+
+// run runs pdftohtml.
+func run(ctx context.Context, logger *zap.Logger, binPath string, args ...string) error {
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("convert PDF to HTML: %w", err)
+		}
+
+		return fmt.Errorf("convert PDF to HTML: %v: %w", err, ErrInvalidPdf)
+	}
+
+	return nil
+}
+
+// pageRangeArgs returns the arguments selecting a range of pages, from 1. If
+// zero, the range starts with the first page or ends with the last one.
+func pageRangeArgs(firstPage, lastPage int) []string {
+	var args []string
+
+	if firstPage > 0 {
+		args = append(args, "-f", strconv.Itoa(firstPage))
+	}
+
+	if lastPage > 0 {
+		args = append(args, "-l", strconv.Itoa(lastPage))
+	}
+
+	return args
+}
@@ -0,0 +1,113 @@
+package pdftohtml
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakePdftohtml writes a script which mimics pdftohtml, i.e., which writes
+// its arguments to its last argument, or which fails.
+func fakePdftohtml(t *testing.T, fail bool) string {
+	script := "for last; do true; done\necho \"$@\" > \"$last\"\n"
+	if fail {
+		script = "exit 1\n"
+	}
+
+	return gotenbergtest.FakeBinary(t, "pdftohtml", script)
+}
+
+func TestConvert(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		opts          convertOptions
+		cancelledCtx  bool
+		expectArgs    string
+		expectError   bool
+		expectedError error
+	}{
+		{
+			scenario:      "invalid PDF",
+			binPath:       fakePdftohtml(t, true),
+			opts:          defaultConvertOptions(),
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:     "context done",
+			binPath:      fakePdftohtml(t, false),
+			opts:         defaultConvertOptions(),
+			cancelledCtx: true,
+			expectError:  true,
+		},
+		{
+			scenario:   "reflow mode",
+			binPath:    fakePdftohtml(t, false),
+			opts:       defaultConvertOptions(),
+			expectArgs: "-s -noframes -dataurls -q document.pdf",
+		},
+		{
+			scenario: "layout mode without images, with a page range",
+			binPath:  fakePdftohtml(t, false),
+			opts: convertOptions{
+				Mode:      modeLayout,
+				Images:    false,
+				FirstPage: 2,
+				LastPage:  3,
+				Zoom:      2,
+			},
+			expectArgs: "-s -noframes -dataurls -q -c -zoom 2 -i -f 2 -l 3 document.pdf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			if tc.cancelledCtx {
+				cancel()
+			}
+
+			outputPath := filepath.Join(t.TempDir(), "document.html")
+
+			err := convert(ctx, zap.NewNop(), tc.binPath, "document.pdf", outputPath, tc.opts)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if tc.cancelledCtx && errors.Is(err, ErrInvalidPdf) {
+				t.Fatalf("expected no %v error but got one", ErrInvalidPdf)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			b, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			actual := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(string(b)), outputPath))
+			if actual != tc.expectArgs {
+				t.Errorf("expected arguments '%s' but got '%s'", tc.expectArgs, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package pdftohtml provides a module which adds a route for converting PDFs
+// to HTML with pdftohtml, either as reflowable text or with the layout of
+// their pages, e.g., for previewing them in a browser without a PDF viewer.
+package pdftohtml
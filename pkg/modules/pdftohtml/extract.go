@@ -0,0 +1,568 @@
+package pdftohtml
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// formatMarkdown approximates the structure of the document with
+	// Markdown: headings, lists, and tables.
+	formatMarkdown = "markdown"
+
+	// formatText is plain text, with the paragraphs separated by blank
+	// lines.
+	formatText = "text"
+)
+
+// extractOptions gathers the available options for extracting the text of a
+// PDF.
+type extractOptions struct {
+	// Format is either formatMarkdown or formatText.
+	Format string
+
+	// PageMarkers defines whether to mark the start of each page: an HTML
+	// comment with Markdown, a form feed with plain text.
+	PageMarkers bool
+
+	// FirstPage and LastPage are the range of pages to extract, from 1. If
+	// zero, the range starts with the first page or ends with the last one.
+	FirstPage, LastPage int
+}
+
+// defaultExtractOptions returns the default values for [extractOptions].
+func defaultExtractOptions() extractOptions {
+	return extractOptions{
+		Format:      formatMarkdown,
+		PageMarkers: false,
+		FirstPage:   0,
+		LastPage:    0,
+	}
+}
+
+// extract extracts the text of a PDF to Markdown or plain text. pdftohtml
+// lists the lines of text of each page, with their positions and fonts; the
+// structure is inferred from them.
+func extract(ctx context.Context, logger *zap.Logger, binPath, inputPath, outputPath string, opts extractOptions) error {
+	// document.pdf.md -> document.pdf.md.xml.
+	xmlPath := fmt.Sprintf("%s.xml", outputPath)
+
+	args := []string{
+		"-xml",
+		"-i",
+		"-q",
+	}
+	args = append(args, pageRangeArgs(opts.FirstPage, opts.LastPage)...)
+	args = append(args, inputPath, xmlPath)
+
+	err := run(ctx, logger, binPath, args...)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(xmlPath)
+	if err != nil {
+		return fmt.Errorf("open XML document: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	pages, err := readPages(f)
+	if err != nil {
+		return fmt.Errorf("read XML document: %v: %w", err, ErrInvalidPdf)
+	}
+
+	err = os.WriteFile(outputPath, []byte(render(pages, opts)), 0o600)
+	if err != nil {
+		return fmt.Errorf("write output: %w", err)
+	}
+
+	return nil
+}
+
+// span is a line of text, or a part of it, at a position of a page.
+type span struct {
+	top, left, width, height float64
+	size                     float64
+	bold                     bool
+	text                     string
+}
+
+// textPage is a page of text.
+type textPage struct {
+	number int
+	spans  []span
+}
+
+// readPages reads the XML document of pdftohtml.
+func readPages(r io.Reader) ([]textPage, error) {
+	var doc struct {
+		Pages []struct {
+			Number int `xml:"number,attr"`
+			Fonts  []struct {
+				Id   string  `xml:"id,attr"`
+				Size float64 `xml:"size,attr"`
+			} `xml:"fontspec"`
+			Texts []struct {
+				Top    float64 `xml:"top,attr"`
+				Left   float64 `xml:"left,attr"`
+				Width  float64 `xml:"width,attr"`
+				Height float64 `xml:"height,attr"`
+				Font   string  `xml:"font,attr"`
+				Inner  string  `xml:",innerxml"`
+			} `xml:"text"`
+		} `xml:"page"`
+	}
+
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	err := decoder.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("decode: %w", err)
+	}
+
+	// A font is declared on the first page using it.
+	sizes := make(map[string]float64)
+	pages := make([]textPage, len(doc.Pages))
+
+	for i, p := range doc.Pages {
+		for _, font := range p.Fonts {
+			sizes[font.Id] = font.Size
+		}
+
+		pages[i].number = p.Number
+
+		for _, t := range p.Texts {
+			text, bold := readInner(t.Inner)
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+
+			pages[i].spans = append(pages[i].spans, span{
+				top:    t.Top,
+				left:   t.Left,
+				width:  t.Width,
+				height: t.Height,
+				size:   sizes[t.Font],
+				bold:   bold,
+				text:   strings.Join(strings.Fields(text), " "),
+			})
+		}
+	}
+
+	return pages, nil
+}
+
+// readInner reads the content of a text element, which may contain b, i, and
+// a elements. It returns true if all the text is bold.
+func readInner(inner string) (string, bool) {
+	decoder := xml.NewDecoder(strings.NewReader("<t>" + inner + "</t>"))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var (
+		b      strings.Builder
+		depth  int
+		bold   = true
+		hasAny bool
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			if token.Name.Local == "b" {
+				depth++
+			}
+		case xml.EndElement:
+			if token.Name.Local == "b" && depth > 0 {
+				depth--
+			}
+		case xml.CharData:
+			if strings.TrimSpace(string(token)) != "" {
+				hasAny = true
+				bold = bold && depth > 0
+			}
+			b.Write(token)
+		}
+	}
+
+	return b.String(), hasAny && bold
+}
+
+// row is a line of a page, i.e., the spans with the same vertical position.
+// The spans far from each other are in distinct cells.
+type row struct {
+	top, bottom, left float64
+	size              float64
+	bold              bool
+	cells             []string
+}
+
+func (r row) text() string {
+	return strings.Join(r.cells, " ")
+}
+
+// rows groups the spans of a page into rows, from top to bottom.
+func rows(spans []span) []row {
+	sorted := append([]span(nil), spans...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].top < sorted[j].top
+	})
+
+	var groups [][]span
+	for _, s := range sorted {
+		if len(groups) > 0 {
+			last := groups[len(groups)-1]
+			previous := last[0]
+			center := s.top + s.height/2
+			previousCenter := previous.top + previous.height/2
+
+			if math.Abs(center-previousCenter) < math.Max(s.height, previous.height)/2 {
+				groups[len(groups)-1] = append(last, s)
+				continue
+			}
+		}
+
+		groups = append(groups, []span{s})
+	}
+
+	result := make([]row, len(groups))
+	for i, group := range groups {
+		sort.SliceStable(group, func(a, b int) bool {
+			return group[a].left < group[b].left
+		})
+
+		r := row{
+			top:  group[0].top,
+			left: group[0].left,
+			bold: true,
+		}
+
+		var cell strings.Builder
+		end := 0.0
+
+		for j, s := range group {
+			r.top = math.Min(r.top, s.top)
+			r.bottom = math.Max(r.bottom, s.top+s.height)
+			r.size = math.Max(r.size, s.size)
+			r.bold = r.bold && s.bold
+
+			// A gap wider than about two characters separates cells.
+			if j > 0 && s.left-end > math.Max(s.size, 1)*1.5 {
+				r.cells = append(r.cells, cell.String())
+				cell.Reset()
+			}
+
+			if cell.Len() > 0 {
+				cell.WriteString(" ")
+			}
+
+			cell.WriteString(s.text)
+			end = math.Max(end, s.left+s.width)
+		}
+
+		r.cells = append(r.cells, cell.String())
+		result[i] = r
+	}
+
+	return result
+}
+
+// blockKind is the kind of block of text.
+type blockKind int
+
+const (
+	paragraphBlock blockKind = iota
+	headingBlock
+	listItemBlock
+	tableBlock
+)
+
+// block is a block of text.
+type block struct {
+	kind  blockKind
+	level int
+	// marker is the marker of a list item, e.g., "-" or "1.".
+	marker string
+	bold   bool
+	text   string
+	table  [][]string
+}
+
+var (
+	bulletListItem   = regexp.MustCompile(`^[•◦▪▫●○■□‣⁃·\-*+–—]\s+(.+)$`)
+	numberedListItem = regexp.MustCompile(`^(\d{1,3})[.)]\s+(.+)$`)
+)
+
+// bodySize returns the most common font size, weighted by the number of
+// characters.
+func bodySize(pages []textPage) float64 {
+	counts := make(map[float64]int)
+	for _, p := range pages {
+		for _, s := range p.spans {
+			counts[s.size] += len(s.text)
+		}
+	}
+
+	size, count := 0.0, -1
+	for s, c := range counts {
+		if c > count || (c == count && s < size) {
+			size, count = s, c
+		}
+	}
+
+	return size
+}
+
+// headingLevels maps the font sizes larger than the body's to heading
+// levels, from 1 to 3.
+func headingLevels(pages []textPage, body float64) map[float64]int {
+	var sizes []float64
+	seen := make(map[float64]bool)
+
+	for _, p := range pages {
+		for _, s := range p.spans {
+			if s.size >= body*1.2 && !seen[s.size] {
+				seen[s.size] = true
+				sizes = append(sizes, s.size)
+			}
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(sizes)))
+
+	levels := make(map[float64]int, len(sizes))
+	for i, size := range sizes {
+		levels[size] = min(i+1, 3)
+	}
+
+	return levels
+}
+
+// blocks infers the blocks of text of a page.
+func blocks(rs []row, levels map[float64]int) []block {
+	var (
+		result   []block
+		current  *block
+		previous row
+	)
+
+	flush := func() {
+		if current != nil {
+			result = append(result, *current)
+			current = nil
+		}
+	}
+
+	appendLine := func(text string) {
+		if strings.HasSuffix(current.text, "-") && text != "" && strings.ToLower(text[:1]) == text[:1] {
+			current.text = strings.TrimSuffix(current.text, "-") + text
+			return
+		}
+
+		current.text += " " + text
+	}
+
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		gap := r.top - previous.bottom
+		lineHeight := previous.bottom - previous.top
+
+		// Rows of at least two cells, with the same number of cells.
+		if len(r.cells) > 1 {
+			j := i + 1
+			for j < len(rs) && len(rs[j].cells) == len(r.cells) {
+				j++
+			}
+
+			if j-i > 1 {
+				flush()
+
+				table := make([][]string, 0, j-i)
+				for _, tr := range rs[i:j] {
+					table = append(table, tr.cells)
+				}
+
+				result = append(result, block{kind: tableBlock, table: table})
+				previous = rs[j-1]
+				i = j - 1
+
+				continue
+			}
+		}
+
+		text := r.text()
+
+		if level, ok := levels[r.size]; ok {
+			// A heading may span several lines.
+			if current != nil && current.kind == headingBlock && current.level == level && gap < lineHeight {
+				appendLine(text)
+			} else {
+				flush()
+				current = &block{kind: headingBlock, level: level, text: text}
+			}
+
+			previous = r
+			continue
+		}
+
+		if match := bulletListItem.FindStringSubmatch(text); match != nil {
+			flush()
+			current = &block{kind: listItemBlock, marker: "-", text: match[1]}
+			previous = r
+			continue
+		}
+
+		if match := numberedListItem.FindStringSubmatch(text); match != nil {
+			flush()
+			current = &block{kind: listItemBlock, marker: match[1] + ".", text: match[2]}
+			previous = r
+			continue
+		}
+
+		// The continuation of a paragraph or of a list item: the gap is
+		// smaller than a line.
+		if current != nil && current.kind != headingBlock && gap < lineHeight*0.8 {
+			appendLine(text)
+			current.bold = current.bold && r.bold
+			previous = r
+			continue
+		}
+
+		flush()
+		current = &block{kind: paragraphBlock, bold: r.bold, text: text}
+		previous = r
+	}
+
+	flush()
+
+	return result
+}
+
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"<", `\<`,
+	">", `\>`,
+)
+
+var markdownBlockStart = regexp.MustCompile(`^(#|[-+=]|\d+[.)])`)
+
+// escapeMarkdown escapes the characters of a text which Markdown would
+// interpret.
+func escapeMarkdown(text string) string {
+	text = markdownReplacer.Replace(text)
+
+	if match := markdownBlockStart.FindString(text); match != "" {
+		if strings.HasSuffix(match, ".") || strings.HasSuffix(match, ")") {
+			return match[:len(match)-1] + `\` + text[len(match)-1:]
+		}
+
+		return `\` + text
+	}
+
+	return text
+}
+
+// render renders the pages to Markdown or plain text.
+func render(pages []textPage, opts extractOptions) string {
+	body := bodySize(pages)
+	levels := headingLevels(pages, body)
+	markdown := opts.Format == formatMarkdown
+
+	var out []string
+
+	for _, p := range pages {
+		var page []string
+
+		if opts.PageMarkers && markdown {
+			page = append(page, fmt.Sprintf("<!-- Page %d -->", p.number))
+		}
+
+		for _, b := range blocks(rows(p.spans), levels) {
+			page = append(page, renderBlock(b, markdown))
+		}
+
+		text := strings.Join(page, "\n\n")
+		if opts.PageMarkers && !markdown {
+			text = "\f" + text
+		}
+
+		out = append(out, text)
+	}
+
+	return strings.TrimLeft(strings.Join(out, "\n\n"), "\n") + "\n"
+}
+
+func renderBlock(b block, markdown bool) string {
+	switch b.kind {
+	case headingBlock:
+		if !markdown {
+			return b.text
+		}
+
+		return strings.Repeat("#", b.level) + " " + escapeMarkdown(b.text)
+	case listItemBlock:
+		if !markdown {
+			return b.marker + " " + b.text
+		}
+
+		return b.marker + " " + escapeMarkdown(b.text)
+	case tableBlock:
+		lines := make([]string, 0, len(b.table)+1)
+
+		for i, cells := range b.table {
+			if !markdown {
+				lines = append(lines, strings.Join(cells, "\t"))
+				continue
+			}
+
+			escaped := make([]string, len(cells))
+			for j, cell := range cells {
+				escaped[j] = strings.ReplaceAll(markdownReplacer.Replace(cell), "|", `\|`)
+			}
+
+			lines = append(lines, "| "+strings.Join(escaped, " | ")+" |")
+
+			// The first row is the header.
+			if i == 0 {
+				lines = append(lines, "|"+strings.Repeat(" --- |", len(cells)))
+			}
+		}
+
+		return strings.Join(lines, "\n")
+	default:
+		if !markdown {
+			return b.text
+		}
+
+		if b.bold {
+			return "**" + escapeMarkdown(b.text) + "**"
+		}
+
+		return escapeMarkdown(b.text)
+	}
+}
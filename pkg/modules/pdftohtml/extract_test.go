@@ -0,0 +1,178 @@
+package pdftohtml
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+const testXml = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE pdf2xml SYSTEM "pdf2xml.dtd">
+<pdf2xml producer="poppler" version="22.02.0">
+<page number="1" position="absolute" top="0" left="0" height="1263" width="892">
+	<fontspec id="0" size="30" family="Times" color="#000000"/>
+	<fontspec id="1" size="20" family="Times" color="#000000"/>
+	<fontspec id="2" size="14" family="Times" color="#000000"/>
+<text top="100" left="100" width="300" height="34" font="0"><b>Annual Report</b></text>
+<text top="160" left="100" width="200" height="24" font="1">Introduction</text>
+<text top="200" left="100" width="600" height="17" font="2">This report describes the activity of the com-</text>
+<text top="217" left="100" width="600" height="17" font="2">pany during the year &amp; its *results*.</text>
+<text top="260" left="100" width="300" height="17" font="2">• First item</text>
+<text top="277" left="100" width="300" height="17" font="2">- Second item</text>
+<text top="294" left="100" width="300" height="17" font="2">3. Third item</text>
+<text top="340" left="100" width="100" height="17" font="2">Name</text>
+<text top="340" left="400" width="100" height="17" font="2">Value</text>
+<text top="357" left="100" width="100" height="17" font="2">a|b</text>
+<text top="357" left="400" width="100" height="17" font="2">42</text>
+</page>
+<page number="2" position="absolute" top="0" left="0" height="1263" width="892">
+<text top="100" left="100" width="600" height="17" font="2"><b>Important</b></text>
+</page>
+</pdf2xml>
+`
+
+// fakePdftohtmlXml writes a script which mimics pdftohtml with the XML
+// output, i.e., which copies an XML document to its last argument.
+func fakePdftohtmlXml(t *testing.T, content string) string {
+	dirPath := t.TempDir()
+	xmlPath := filepath.Join(dirPath, "document.xml")
+
+	err := os.WriteFile(xmlPath, []byte(content), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	script := "for last; do true; done\ncp \"" + xmlPath + "\" \"$last\"\n"
+	return gotenbergtest.FakeBinary(t, "pdftohtml", script)
+}
+
+func TestExtract(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		opts          extractOptions
+		expectOutput  string
+		expectError   bool
+		expectedError error
+	}{
+		{
+			scenario:      "invalid PDF",
+			binPath:       fakePdftohtml(t, true),
+			opts:          defaultExtractOptions(),
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:      "invalid XML document",
+			binPath:       fakePdftohtmlXml(t, "<pdf2xml><page>"),
+			opts:          defaultExtractOptions(),
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario: "Markdown",
+			binPath:  fakePdftohtmlXml(t, testXml),
+			opts:     defaultExtractOptions(),
+			expectOutput: `# Annual Report
+
+## Introduction
+
+This report describes the activity of the company during the year & its \*results\*.
+
+- First item
+
+- Second item
+
+3. Third item
+
+| Name | Value |
+| --- | --- |
+| a\|b | 42 |
+
+**Important**
+`,
+		},
+		{
+			scenario: "Markdown with page markers",
+			binPath:  fakePdftohtmlXml(t, `<pdf2xml><page number="1"><fontspec id="0" size="14"/><text top="10" left="10" width="100" height="17" font="0"># 1 [draft]</text></page><page number="2"><text top="10" left="10" width="100" height="17" font="0">2. <i>done</i></text></page></pdf2xml>`),
+			opts: extractOptions{
+				Format:      formatMarkdown,
+				PageMarkers: true,
+			},
+			expectOutput: `<!-- Page 1 -->
+
+\# 1 \[draft\]
+
+<!-- Page 2 -->
+
+2. done
+`,
+		},
+		{
+			scenario: "plain text with page markers",
+			binPath:  fakePdftohtmlXml(t, testXml),
+			opts: extractOptions{
+				Format:      formatText,
+				PageMarkers: true,
+			},
+			expectOutput: "\fAnnual Report\n\nIntroduction\n\nThis report describes the activity of the company during the year & its *results*.\n\n- First item\n\n- Second item\n\n3. Third item\n\nName\tValue\na|b\t42\n\n\fImportant\n",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "document.pdf.md")
+
+			err := extract(context.Background(), zap.NewNop(), tc.binPath, "document.pdf", outputPath, tc.opts)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			b, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if string(b) != tc.expectOutput {
+				t.Errorf("expected output\n%q\nbut got\n%q", tc.expectOutput, string(b))
+			}
+		})
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	for _, tc := range []struct {
+		text   string
+		expect string
+	}{
+		{text: "plain text", expect: "plain text"},
+		{text: "# not a heading", expect: `\# not a heading`},
+		{text: "- not a list", expect: `\- not a list`},
+		{text: "12. not a list", expect: `12\. not a list`},
+		{text: "a_b <c>", expect: `a\_b \<c\>`},
+	} {
+		t.Run(tc.text, func(t *testing.T) {
+			actual := escapeMarkdown(tc.text)
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package pdftohtml
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(PdfToHtml))
+}
+
+// PdfToHtml is a module which provides a route for converting PDFs to HTML.
+type PdfToHtml struct {
+	binPath       string
+	disableRoutes bool
+}
+
+// Descriptor returns a [PdfToHtml]'s module descriptor.
+func (mod *PdfToHtml) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "pdftohtml",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("pdftohtml", flag.ExitOnError)
+			fs.Bool("pdftohtml-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(PdfToHtml) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *PdfToHtml) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("pdftohtml-disable-routes")
+
+	binPath, ok := os.LookupEnv("PDFTOHTML_BIN_PATH")
+	if !ok {
+		return errors.New("PDFTOHTML_BIN_PATH environment variable is not set")
+	}
+
+	mod.binPath = binPath
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *PdfToHtml) Validate() error {
+	_, err := os.Stat(mod.binPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("pdftohtml binary path does not exist: %w", err)
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *PdfToHtml) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		convertRoute(mod.binPath),
+		extractRoute(mod.binPath),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*PdfToHtml)(nil)
+	_ gotenberg.Provisioner = (*PdfToHtml)(nil)
+	_ gotenberg.Validator   = (*PdfToHtml)(nil)
+	_ api.Router            = (*PdfToHtml)(nil)
+)
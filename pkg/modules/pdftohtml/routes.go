@@ -0,0 +1,228 @@
+package pdftohtml
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// convertRoute returns an [api.Route] which can convert PDFs to HTML.
+func convertRoute(binPath string) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdftohtml/convert",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			defaultOptions := defaultConvertOptions()
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				opts       convertOptions
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Custom("mode", func(value string) error {
+					if value == "" {
+						opts.Mode = defaultOptions.Mode
+						return nil
+					}
+
+					if value != modeReflow && value != modeLayout {
+						return fmt.Errorf("wrong value, expected either '%s' or '%s'", modeReflow, modeLayout)
+					}
+
+					opts.Mode = value
+
+					return nil
+				}).
+				Bool("images", &opts.Images, defaultOptions.Images).
+				Custom("firstPage", func(value string) error {
+					return assignFirstPage(value, &opts.FirstPage)
+				}).
+				Custom("lastPage", func(value string) error {
+					return assignLastPage(value, opts.FirstPage, &opts.LastPage)
+				}).
+				Custom("zoom", func(value string) error {
+					if value == "" {
+						opts.Zoom = defaultOptions.Zoom
+						return nil
+					}
+
+					zoom, err := strconv.ParseFloat(value, 64)
+					if err != nil {
+						return err
+					}
+
+					if zoom <= 0 {
+						return errors.New("value is inferior or equal to 0")
+					}
+
+					opts.Zoom = zoom
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// Alright, let's convert each PDF to HTML.
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				// document.pdf -> document.pdf.html.
+				outputPaths[i] = ctx.GeneratePath(filepath.Base(inputPath), ".html")
+
+				err = convert(ctx, ctx.Log(), binPath, inputPath, outputPaths[i], opts)
+				if err != nil {
+					if errors.Is(err, ErrInvalidPdf) {
+						return api.WrapError(
+							fmt.Errorf("convert PDF to HTML: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("The PDF '%s' is invalid or the page range is out of bounds", filepath.Base(inputPath)),
+							).WithCode(api.ErrorCodePdftohtmlInvalidPdf),
+						)
+					}
+
+					return fmt.Errorf("convert PDF to HTML: %w", err)
+				}
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// assignFirstPage parses the first page of a range, if any.
+func assignFirstPage(value string, target *int) error {
+	if value == "" {
+		*target = 0
+		return nil
+	}
+
+	firstPage, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	if firstPage < 1 {
+		return errors.New("value is inferior to 1")
+	}
+
+	*target = firstPage
+
+	return nil
+}
+
+// assignLastPage parses the last page of a range, if any. It must not be
+// before the first page.
+func assignLastPage(value string, firstPage int, target *int) error {
+	err := assignFirstPage(value, target)
+	if err != nil {
+		return err
+	}
+
+	if *target > 0 && *target < firstPage {
+		return errors.New("value is inferior to firstPage")
+	}
+
+	return nil
+}
+
+// extractRoute returns an [api.Route] which can extract the text of PDFs to
+// Markdown or plain text.
+func extractRoute(binPath string) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pdftohtml/extract",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+			defaultOptions := defaultExtractOptions()
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				opts       extractOptions
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths([]string{".pdf"}, &inputPaths).
+				Custom("format", func(value string) error {
+					if value == "" {
+						opts.Format = defaultOptions.Format
+						return nil
+					}
+
+					if value != formatMarkdown && value != formatText {
+						return fmt.Errorf("wrong value, expected either '%s' or '%s'", formatMarkdown, formatText)
+					}
+
+					opts.Format = value
+
+					return nil
+				}).
+				Bool("pageMarkers", &opts.PageMarkers, defaultOptions.PageMarkers).
+				Custom("firstPage", func(value string) error {
+					return assignFirstPage(value, &opts.FirstPage)
+				}).
+				Custom("lastPage", func(value string) error {
+					return assignLastPage(value, opts.FirstPage, &opts.LastPage)
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			extension := ".md"
+			if opts.Format == formatText {
+				extension = ".txt"
+			}
+
+			// Alright, let's extract the text of each PDF.
+			outputPaths := make([]string, len(inputPaths))
+
+			for i, inputPath := range inputPaths {
+				// document.pdf -> document.pdf.md.
+				outputPaths[i] = ctx.GeneratePath(filepath.Base(inputPath), extension)
+
+				err = extract(ctx, ctx.Log(), binPath, inputPath, outputPaths[i], opts)
+				if err != nil {
+					if errors.Is(err, ErrInvalidPdf) {
+						return api.WrapError(
+							fmt.Errorf("extract PDF text: %w", err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("The PDF '%s' is invalid or the page range is out of bounds", filepath.Base(inputPath)),
+							).WithCode(api.ErrorCodePdftohtmlInvalidPdf),
+						)
+					}
+
+					return fmt.Errorf("extract PDF text: %w", err)
+				}
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,19 @@
+// Package pdftoppm provides an implementation of the gotenberg.ImageEngine
+// interface using the pdftoppm command-line tool from Poppler. It renders the
+// pages of PDFs to PNG, JPEG or TIFF images. The path to its binary must be
+// specified using the PDFTOPPM_BIN_PATH environment variable.
+//
+// The pages may also be rendered without a white backdrop, thanks to the
+// pdftocairo command-line tool from Poppler. The path to its binary must be
+// specified using the PDFTOCAIRO_BIN_PATH environment variable.
+//
+// The TIFF images may be compressed, e.g., with CCITT Group 4 for fax
+// machines, and put together into a unique multi-page TIFF image with the
+// tiffcp command-line tool from libtiff. The path to its binary must be
+// specified using the TIFFCP_BIN_PATH environment variable.
+//
+// The images carry their resolution. Their other metadata, if any, may be
+// removed.
+//
+// See: https://poppler.freedesktop.org.
+package pdftoppm
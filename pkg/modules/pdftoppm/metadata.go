@@ -0,0 +1,150 @@
+package pdftoppm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// pngSignature is the signature which starts PNG images.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngMetadataChunks are the chunks of PNG images which hold metadata. The
+// pHYs chunk, which holds the resolution, is not one of them, nor are the
+// chunks about colors.
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+	"eXIf": true,
+}
+
+// jpegMetadataMarkers are the markers of the JPEG segments which hold
+// metadata: APP1 (EXIF, XMP), APP13 (IPTC) and the comments. The APP0 segment
+// holds the resolution, while the APP2 and APP14 segments are about colors.
+var jpegMetadataMarkers = map[byte]bool{
+	0xe1: true,
+	0xed: true,
+	0xfe: true,
+}
+
+// stripMetadata removes the metadata of an image in place, but its
+// resolution.
+func stripMetadata(path, format string) error {
+	var strip func(b []byte) ([]byte, error)
+
+	switch format {
+	case gotenberg.ImageFormatPng:
+		strip = stripPngMetadata
+	case gotenberg.ImageFormatJpeg:
+		strip = stripJpegMetadata
+	default:
+		// pdftoppm only writes the structure and the resolution of the TIFF
+		// images.
+		return nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read image: %w", err)
+	}
+
+	b, err = strip(b)
+	if err != nil {
+		return fmt.Errorf("strip metadata of '%s': %w", filepath.Base(path), err)
+	}
+
+	err = os.WriteFile(path, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write image: %w", err)
+	}
+
+	return nil
+}
+
+// stripPngMetadata returns a PNG image without its metadata chunks.
+func stripPngMetadata(b []byte) ([]byte, error) {
+	if !bytes.HasPrefix(b, pngSignature) {
+		return nil, errors.New("not a PNG image")
+	}
+
+	stripped := make([]byte, 0, len(b))
+	stripped = append(stripped, pngSignature...)
+
+	rest := b[len(pngSignature):]
+
+	for len(rest) > 0 {
+		// A chunk is its length, its type, its data and its CRC.
+		if len(rest) < 12 {
+			return nil, errors.New("truncated PNG chunk")
+		}
+
+		length := int64(binary.BigEndian.Uint32(rest))
+		if length > int64(len(rest)-12) {
+			return nil, errors.New("truncated PNG chunk")
+		}
+
+		chunk := rest[:12+length]
+		chunkType := string(chunk[4:8])
+		rest = rest[12+length:]
+
+		if !pngMetadataChunks[chunkType] {
+			stripped = append(stripped, chunk...)
+		}
+
+		if chunkType == "IEND" {
+			return stripped, nil
+		}
+	}
+
+	return nil, errors.New("no PNG IEND chunk")
+}
+
+// stripJpegMetadata returns a JPEG image without its metadata segments.
+func stripJpegMetadata(b []byte) ([]byte, error) {
+	if len(b) < 2 || b[0] != 0xff || b[1] != 0xd8 {
+		return nil, errors.New("not a JPEG image")
+	}
+
+	stripped := make([]byte, 0, len(b))
+	stripped = append(stripped, b[:2]...)
+
+	rest := b[2:]
+
+	for {
+		if len(rest) < 2 || rest[0] != 0xff {
+			return nil, errors.New("invalid JPEG marker")
+		}
+
+		marker := rest[1]
+
+		// The image data, which follows the start of scan segment, has no
+		// metadata.
+		if marker == 0xda {
+			return append(stripped, rest...), nil
+		}
+
+		if len(rest) < 4 {
+			return nil, errors.New("truncated JPEG segment")
+		}
+
+		// The length of a segment counts its own 2 bytes, not the marker.
+		length := int(binary.BigEndian.Uint16(rest[2:]))
+		if length < 2 || length+2 > len(rest) {
+			return nil, errors.New("truncated JPEG segment")
+		}
+
+		segment := rest[:length+2]
+		rest = rest[length+2:]
+
+		if !jpegMetadataMarkers[marker] {
+			stripped = append(stripped, segment...)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package pdftoppm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// pngChunk returns a PNG chunk with the given type and data.
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// pngWithMetadata returns a PNG image with a resolution and metadata chunks.
+func pngWithMetadata(t *testing.T) []byte {
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2, 2)))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	b := buf.Bytes()
+
+	// The IHDR chunk follows the signature and has 13 bytes of data.
+	ihdrEnd := len(pngSignature) + 12 + 13
+
+	phys := binary.BigEndian.AppendUint32(nil, 5906)
+	phys = binary.BigEndian.AppendUint32(phys, 5906)
+	phys = append(phys, 1)
+
+	var withMetadata []byte
+	withMetadata = append(withMetadata, b[:ihdrEnd]...)
+	withMetadata = append(withMetadata, pngChunk("pHYs", phys)...)
+	withMetadata = append(withMetadata, pngChunk("tEXt", []byte("Author\x00foo"))...)
+	withMetadata = append(withMetadata, pngChunk("eXIf", []byte("MM\x00*"))...)
+	withMetadata = append(withMetadata, b[ihdrEnd:]...)
+
+	return withMetadata
+}
+
+// jpegWithMetadata returns a JPEG image with a JFIF segment and metadata
+// segments.
+func jpegWithMetadata(t *testing.T) []byte {
+	var buf bytes.Buffer
+
+	err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 2, 2)), nil)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	b := buf.Bytes()
+
+	var withMetadata []byte
+	withMetadata = append(withMetadata, b[:2]...)
+	withMetadata = append(withMetadata, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00, 0x01, 0x01, 0x01, 0x00, 0x96, 0x00, 0x96, 0x00, 0x00)
+	withMetadata = append(withMetadata, 0xff, 0xe1, 0x00, 0x08, 'E', 'x', 'i', 'f', 0x00, 0x00)
+	withMetadata = append(withMetadata, 0xff, 0xfe, 0x00, 0x05, 'f', 'o', 'o')
+	withMetadata = append(withMetadata, b[2:]...)
+
+	return withMetadata
+}
+
+func TestStripMetadata(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		format      string
+		content     func(t *testing.T) []byte
+		expectError bool
+		expectKept  []string
+		expectGone  []string
+	}{
+		{
+			scenario: "invalid PNG image",
+			format:   gotenberg.ImageFormatPng,
+			content: func(t *testing.T) []byte {
+				return []byte("foo")
+			},
+			expectError: true,
+		},
+		{
+			scenario: "truncated PNG image",
+			format:   gotenberg.ImageFormatPng,
+			content: func(t *testing.T) []byte {
+				b := pngWithMetadata(t)
+				return b[:len(b)-6]
+			},
+			expectError: true,
+		},
+		{
+			scenario:   "PNG image",
+			format:     gotenberg.ImageFormatPng,
+			content:    pngWithMetadata,
+			expectKept: []string{"IHDR", "pHYs", "IDAT", "IEND"},
+			expectGone: []string{"tEXt", "eXIf"},
+		},
+		{
+			scenario: "invalid JPEG image",
+			format:   gotenberg.ImageFormatJpeg,
+			content: func(t *testing.T) []byte {
+				return []byte("foo")
+			},
+			expectError: true,
+		},
+		{
+			scenario:   "JPEG image",
+			format:     gotenberg.ImageFormatJpeg,
+			content:    jpegWithMetadata,
+			expectKept: []string{"JFIF"},
+			expectGone: []string{"Exif", "\xff\xfe"},
+		},
+		{
+			scenario: "TIFF image",
+			format:   gotenberg.ImageFormatTiff,
+			content: func(t *testing.T) []byte {
+				return []byte("foo")
+			},
+			expectKept: []string{"foo"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "page-1")
+
+			err := os.WriteFile(path, tc.content(t), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = stripMetadata(path, tc.format)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			for _, kept := range tc.expectKept {
+				if !bytes.Contains(b, []byte(kept)) {
+					t.Errorf("expected '%s' to be kept", kept)
+				}
+			}
+
+			for _, gone := range tc.expectGone {
+				if bytes.Contains(b, []byte(gone)) {
+					t.Errorf("expected '%s' to be removed", gone)
+				}
+			}
+
+			switch tc.format {
+			case gotenberg.ImageFormatPng:
+				_, err = png.Decode(bytes.NewReader(b))
+			case gotenberg.ImageFormatJpeg:
+				_, err = jpeg.Decode(bytes.NewReader(b))
+			}
+
+			if err != nil {
+				t.Errorf("expected a valid image but got: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package pdftoppm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(PdfToPpm))
+}
+
+// extensions are the extensions pdftoppm gives to the images, by format.
+var extensions = map[string]string{
+	gotenberg.ImageFormatPng:  ".png",
+	gotenberg.ImageFormatJpeg: ".jpg",
+	gotenberg.ImageFormatTiff: ".tif",
+}
+
+// tiffCompressions are the values of the "-tiffcompression" option of
+// pdftoppm, by TIFF compression.
+var tiffCompressions = map[string]string{
+	gotenberg.TiffCompressionNone:     "none",
+	gotenberg.TiffCompressionLzw:      "lzw",
+	gotenberg.TiffCompressionDeflate:  "deflate",
+	gotenberg.TiffCompressionPackBits: "packbits",
+	gotenberg.TiffCompressionG4:       "ccittfax4",
+}
+
+// PdfToPpm abstracts the CLI tool pdftoppm and implements the
+// [gotenberg.ImageEngine] interface.
+type PdfToPpm struct {
+	binPath       string
+	cairoBinPath  string
+	tiffcpBinPath string
+}
+
+// Descriptor returns a [PdfToPpm]'s module descriptor.
+func (engine *PdfToPpm) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID:  "pdftoppm",
+		New: func() gotenberg.Module { return new(PdfToPpm) },
+	}
+}
+
+// Provision sets the modules properties.
+func (engine *PdfToPpm) Provision(ctx *gotenberg.Context) error {
+	binPath, ok := os.LookupEnv("PDFTOPPM_BIN_PATH")
+	if !ok {
+		return errors.New("PDFTOPPM_BIN_PATH environment variable is not set")
+	}
+
+	cairoBinPath, ok := os.LookupEnv("PDFTOCAIRO_BIN_PATH")
+	if !ok {
+		return errors.New("PDFTOCAIRO_BIN_PATH environment variable is not set")
+	}
+
+	tiffcpBinPath, ok := os.LookupEnv("TIFFCP_BIN_PATH")
+	if !ok {
+		return errors.New("TIFFCP_BIN_PATH environment variable is not set")
+	}
+
+	engine.binPath = binPath
+	engine.cairoBinPath = cairoBinPath
+	engine.tiffcpBinPath = tiffcpBinPath
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (engine *PdfToPpm) Validate() error {
+	_, err := os.Stat(engine.binPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("pdftoppm binary path does not exist: %w", err)
+	}
+
+	_, err = os.Stat(engine.cairoBinPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("pdftocairo binary path does not exist: %w", err)
+	}
+
+	_, err = os.Stat(engine.tiffcpBinPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("tiffcp binary path does not exist: %w", err)
+	}
+
+	return nil
+}
+
+// Render renders each page of a PDF to an image within outputDirPath. The
+// pages of a multi-page TIFF image are put together with tiffcp. As pdftoppm
+// always renders a white backdrop, pdftocairo, which accepts the same
+// options, renders the transparent images.
+func (engine *PdfToPpm) Render(ctx context.Context, logger *zap.Logger, options gotenberg.ImageOptions, inputPath, outputDirPath string) ([]string, error) {
+	ext, ok := extensions[options.Format]
+	if !ok {
+		return nil, fmt.Errorf("render pages to '%s' with pdftoppm: %w", options.Format, gotenberg.ErrImageFormatNotSupported)
+	}
+
+	isTiff := options.Format == gotenberg.ImageFormatTiff
+
+	if !isTiff && (options.Compression != "" || options.MultiPage) {
+		return nil, fmt.Errorf("render pages to '%s' with a TIFF option: %w", options.Format, gotenberg.ErrImageFormatNotSupported)
+	}
+
+	binPath := engine.binPath
+
+	if options.Transparent {
+		if options.Format == gotenberg.ImageFormatJpeg {
+			return nil, fmt.Errorf("render pages to transparent '%s' images: %w", options.Format, gotenberg.ErrImageFormatNotSupported)
+		}
+
+		binPath = engine.cairoBinPath
+	}
+
+	var args []string
+	args = append(args, fmt.Sprintf("-%s", options.Format))
+
+	if options.Transparent {
+		args = append(args, "-transp")
+	}
+
+	if options.Format == gotenberg.ImageFormatJpeg && options.Quality > 0 {
+		args = append(args, "-jpegopt", fmt.Sprintf("quality=%d", options.Quality))
+	}
+
+	if options.Compression != "" {
+		compression, ok := tiffCompressions[options.Compression]
+		if !ok {
+			return nil, fmt.Errorf("render pages to '%s' TIFF images with pdftoppm: %w", options.Compression, gotenberg.ErrImageFormatNotSupported)
+		}
+
+		args = append(args, "-tiffcompression", compression)
+
+		// CCITT Group 4 only compresses black and white images.
+		if options.Compression == gotenberg.TiffCompressionG4 {
+			args = append(args, "-mono")
+		}
+	}
+
+	if options.FirstPage > 0 {
+		args = append(args, "-f", strconv.Itoa(options.FirstPage))
+	}
+
+	if options.LastPage > 0 {
+		args = append(args, "-l", strconv.Itoa(options.LastPage))
+	}
+
+	if options.Gray {
+		args = append(args, "-gray")
+	}
+
+	if options.Resolution > 0 {
+		args = append(args, "-r", strconv.Itoa(options.Resolution))
+	}
+
+	if options.Width > 0 || options.Height > 0 {
+		args = append(args, "-scale-to-x", scale(options.Width), "-scale-to-y", scale(options.Height))
+	}
+
+	args = append(args, inputPath, filepath.Join(outputDirPath, "page"))
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath, args...)
+	if err != nil {
+		return nil, fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("render pages with %s: %w", filepath.Base(binPath), err)
+	}
+
+	paths, err := pagePaths(outputDirPath, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.StripMetadata {
+		for _, path := range paths {
+			err = stripMetadata(path, options.Format)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !options.MultiPage {
+		return paths, nil
+	}
+
+	outputPath := filepath.Join(outputDirPath, "pages"+ext)
+
+	// tiffcp keeps the compression of the pages.
+	cmd, err = gotenberg.CommandContext(ctx, logger, engine.tiffcpBinPath, append(paths, outputPath)...)
+	if err != nil {
+		return nil, fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err != nil {
+		return nil, fmt.Errorf("put pages together with tiffcp: %w", err)
+	}
+
+	return []string{outputPath}, nil
+}
+
+// scale returns the value of a "-scale-to-x" or "-scale-to-y" option, where
+// -1 keeps the aspect ratio.
+func scale(size int) string {
+	if size <= 0 {
+		return "-1"
+	}
+
+	return strconv.Itoa(size)
+}
+
+// pagePaths returns the paths of the images pdftoppm rendered within a
+// directory, in the order of the pages.
+func pagePaths(dirPath, ext string) ([]string, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("read pages directory: %w", err)
+	}
+
+	// pdftoppm pads the page numbers according to the number of pages, e.g.,
+	// page-01.png, let's not rely on it.
+	pages := make(map[int]string, len(entries))
+	numbers := make([]int, 0, len(entries))
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ext)
+		if !ok {
+			continue
+		}
+
+		i := strings.LastIndex(name, "-")
+		if i < 0 {
+			continue
+		}
+
+		page, err := strconv.Atoi(name[i+1:])
+		if err != nil {
+			continue
+		}
+
+		pages[page] = filepath.Join(dirPath, entry.Name())
+		numbers = append(numbers, page)
+	}
+
+	if len(numbers) == 0 {
+		return nil, errors.New("no page rendered")
+	}
+
+	sort.Ints(numbers)
+
+	paths := make([]string, len(numbers))
+	for i, page := range numbers {
+		paths[i] = pages[page]
+	}
+
+	return paths, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*PdfToPpm)(nil)
+	_ gotenberg.Provisioner = (*PdfToPpm)(nil)
+	_ gotenberg.Validator   = (*PdfToPpm)(nil)
+	_ gotenberg.ImageEngine = (*PdfToPpm)(nil)
+)
@@ -0,0 +1,322 @@
+package pdftoppm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakePdftoppm returns the path of a script which mimics pdftoppm: it
+// renders 10 pages, with padded page numbers, to files with the given
+// extension, and writes its arguments next to them.
+func fakePdftoppm(t *testing.T, ext string, fail bool) string {
+	script := "for last; do true; done\nfor i in 01 02 03 04 05 06 07 08 09 10; do echo foo > \"$last-$i" + ext + "\"; done\necho \"$@\" > \"$last.args\"\n"
+	if fail {
+		script = "exit 1\n"
+	}
+
+	return gotenbergtest.FakeBinary(t, "pdftoppm", script)
+}
+
+// fakeTiffcp returns the path of a script which mimics tiffcp: it writes its
+// arguments to the output file, its last argument.
+func fakeTiffcp(t *testing.T, fail bool) string {
+	script := "for last; do true; done\necho \"$@\" > \"$last\"\n"
+	if fail {
+		script = "exit 1\n"
+	}
+
+	return gotenbergtest.FakeBinary(t, "tiffcp", script)
+}
+
+func TestPdfToPpm_Descriptor(t *testing.T) {
+	descriptor := new(PdfToPpm).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(PdfToPpm))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestPdfToPpm_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		unsetEnv    string
+		expectError bool
+	}{
+		{
+			scenario:    "no PDFTOPPM_BIN_PATH environment variable",
+			unsetEnv:    "PDFTOPPM_BIN_PATH",
+			expectError: true,
+		},
+		{
+			scenario:    "no PDFTOCAIRO_BIN_PATH environment variable",
+			unsetEnv:    "PDFTOCAIRO_BIN_PATH",
+			expectError: true,
+		},
+		{
+			scenario:    "no TIFFCP_BIN_PATH environment variable",
+			unsetEnv:    "TIFFCP_BIN_PATH",
+			expectError: true,
+		},
+		{
+			scenario:    "provision success",
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			t.Setenv("PDFTOPPM_BIN_PATH", "/usr/bin/pdftoppm")
+			t.Setenv("PDFTOCAIRO_BIN_PATH", "/usr/bin/pdftocairo")
+			t.Setenv("TIFFCP_BIN_PATH", "/usr/bin/tiffcp")
+			if tc.unsetEnv != "" {
+				_ = os.Unsetenv(tc.unsetEnv)
+			}
+
+			engine := new(PdfToPpm)
+			err := engine.Provision(gotenberg.NewContext(gotenberg.ParsedFlags{}, nil))
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestPdfToPpm_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		cairoBinPath  string
+		tiffcpBinPath string
+		expectError   bool
+	}{
+		{
+			scenario:      "bin path does not exist",
+			binPath:       "/foo",
+			cairoBinPath:  os.Args[0],
+			tiffcpBinPath: os.Args[0],
+			expectError:   true,
+		},
+		{
+			scenario:      "pdftocairo bin path does not exist",
+			binPath:       os.Args[0],
+			cairoBinPath:  "/foo",
+			tiffcpBinPath: os.Args[0],
+			expectError:   true,
+		},
+		{
+			scenario:      "tiffcp bin path does not exist",
+			binPath:       os.Args[0],
+			cairoBinPath:  os.Args[0],
+			tiffcpBinPath: "/foo",
+			expectError:   true,
+		},
+		{
+			scenario:      "validate success",
+			binPath:       os.Args[0],
+			cairoBinPath:  os.Args[0],
+			tiffcpBinPath: os.Args[0],
+			expectError:   false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(PdfToPpm)
+			engine.binPath = tc.binPath
+			engine.cairoBinPath = tc.cairoBinPath
+			engine.tiffcpBinPath = tc.tiffcpBinPath
+			err := engine.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestPdfToPpm_Render(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		cairoBinPath  string
+		tiffcpBinPath string
+		options       gotenberg.ImageOptions
+		expectError   bool
+		expectedError error
+		expectArgs    string
+		expectPages   []string
+	}{
+		{
+			scenario:      "ErrImageFormatNotSupported",
+			options:       gotenberg.ImageOptions{Format: "gif"},
+			expectError:   true,
+			expectedError: gotenberg.ErrImageFormatNotSupported,
+		},
+		{
+			scenario:      "TIFF option with another format",
+			options:       gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, MultiPage: true},
+			expectError:   true,
+			expectedError: gotenberg.ErrImageFormatNotSupported,
+		},
+		{
+			scenario:      "TIFF compression not supported",
+			options:       gotenberg.ImageOptions{Format: gotenberg.ImageFormatTiff, Compression: "jbig"},
+			expectError:   true,
+			expectedError: gotenberg.ErrImageFormatNotSupported,
+		},
+		{
+			scenario:      "transparent JPEG",
+			options:       gotenberg.ImageOptions{Format: gotenberg.ImageFormatJpeg, Transparent: true},
+			expectError:   true,
+			expectedError: gotenberg.ErrImageFormatNotSupported,
+		},
+		{
+			scenario:    "pdftoppm failure",
+			binPath:     fakePdftoppm(t, ".png", true),
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng},
+			expectError: true,
+		},
+		{
+			scenario:    "no page rendered",
+			binPath:     fakePdftoppm(t, ".png", false),
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatJpeg},
+			expectError: true,
+		},
+		{
+			scenario:    "strip metadata failure",
+			binPath:     fakePdftoppm(t, ".png", false),
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, StripMetadata: true},
+			expectError: true,
+		},
+		{
+			scenario:    "PNG with a resolution",
+			binPath:     fakePdftoppm(t, ".png", false),
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, Resolution: 150},
+			expectArgs:  "-png -r 150",
+			expectPages: []string{"page-01.png", "page-02.png", "page-03.png", "page-04.png", "page-05.png", "page-06.png", "page-07.png", "page-08.png", "page-09.png", "page-10.png"},
+		},
+		{
+			scenario:    "grayscale PNG of a range of pages",
+			binPath:     fakePdftoppm(t, ".png", false),
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, FirstPage: 2, LastPage: 3, Gray: true, Resolution: 36},
+			expectArgs:  "-png -f 2 -l 3 -gray -r 36",
+			expectPages: []string{"page-01.png", "page-02.png", "page-03.png", "page-04.png", "page-05.png", "page-06.png", "page-07.png", "page-08.png", "page-09.png", "page-10.png"},
+		},
+		{
+			scenario:     "transparent PNG",
+			binPath:      fakePdftoppm(t, ".png", true),
+			cairoBinPath: fakePdftoppm(t, ".png", false),
+			options:      gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, Width: 200, Transparent: true},
+			expectArgs:   "-png -transp -scale-to-x 200 -scale-to-y -1",
+			expectPages:  []string{"page-01.png", "page-02.png", "page-03.png", "page-04.png", "page-05.png", "page-06.png", "page-07.png", "page-08.png", "page-09.png", "page-10.png"},
+		},
+		{
+			scenario:    "JPEG with a quality and a width",
+			binPath:     fakePdftoppm(t, ".jpg", false),
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatJpeg, Quality: 80, Width: 800},
+			expectArgs:  "-jpeg -jpegopt quality=80 -scale-to-x 800 -scale-to-y -1",
+			expectPages: []string{"page-01.jpg", "page-02.jpg", "page-03.jpg", "page-04.jpg", "page-05.jpg", "page-06.jpg", "page-07.jpg", "page-08.jpg", "page-09.jpg", "page-10.jpg"},
+		},
+		{
+			scenario:    "TIFF with a width and a height",
+			binPath:     fakePdftoppm(t, ".tif", false),
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatTiff, Quality: 80, Width: 800, Height: 600},
+			expectArgs:  "-tiff -scale-to-x 800 -scale-to-y 600",
+			expectPages: []string{"page-01.tif", "page-02.tif", "page-03.tif", "page-04.tif", "page-05.tif", "page-06.tif", "page-07.tif", "page-08.tif", "page-09.tif", "page-10.tif"},
+		},
+		{
+			scenario:    "TIFF with LZW compression",
+			binPath:     fakePdftoppm(t, ".tif", false),
+			options:     gotenberg.ImageOptions{Format: gotenberg.ImageFormatTiff, Compression: gotenberg.TiffCompressionLzw},
+			expectArgs:  "-tiff -tiffcompression lzw",
+			expectPages: []string{"page-01.tif", "page-02.tif", "page-03.tif", "page-04.tif", "page-05.tif", "page-06.tif", "page-07.tif", "page-08.tif", "page-09.tif", "page-10.tif"},
+		},
+		{
+			scenario:      "tiffcp failure",
+			binPath:       fakePdftoppm(t, ".tif", false),
+			tiffcpBinPath: fakeTiffcp(t, true),
+			options:       gotenberg.ImageOptions{Format: gotenberg.ImageFormatTiff, MultiPage: true},
+			expectError:   true,
+		},
+		{
+			scenario:      "multi-page TIFF with Group 4 compression",
+			binPath:       fakePdftoppm(t, ".tif", false),
+			tiffcpBinPath: fakeTiffcp(t, false),
+			options:       gotenberg.ImageOptions{Format: gotenberg.ImageFormatTiff, Resolution: 200, Compression: gotenberg.TiffCompressionG4, MultiPage: true, StripMetadata: true},
+			expectArgs:    "-tiff -tiffcompression ccittfax4 -mono -r 200",
+			expectPages:   []string{"pages.tif"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(PdfToPpm)
+			engine.binPath = tc.binPath
+			engine.cairoBinPath = tc.cairoBinPath
+			engine.tiffcpBinPath = tc.tiffcpBinPath
+
+			dirPath := t.TempDir()
+			inputPath := filepath.Join(dirPath, "document.pdf")
+			outputDirPath := filepath.Join(dirPath, "pages")
+
+			err := os.Mkdir(outputDirPath, 0o755)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			paths, err := engine.Render(context.Background(), zap.NewNop(), tc.options, inputPath, outputDirPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			prefix := filepath.Join(outputDirPath, "page")
+
+			b, err := os.ReadFile(prefix + ".args")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			expectArgs := tc.expectArgs + " " + inputPath + " " + prefix + "\n"
+			if string(b) != expectArgs {
+				t.Errorf("expected args '%s' but got '%s'", expectArgs, string(b))
+			}
+
+			pages := make([]string, len(paths))
+			for i, path := range paths {
+				pages[i] = filepath.Base(path)
+			}
+
+			if !reflect.DeepEqual(pages, tc.expectPages) {
+				t.Errorf("expected pages %+v but got %+v", tc.expectPages, pages)
+			}
+		})
+	}
+}
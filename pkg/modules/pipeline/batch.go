@@ -0,0 +1,237 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+// batchReportFilename is the name of the output file with the status of each
+// item of a batch.
+const batchReportFilename = "batch.json"
+
+// ErrInvalidBatch happens if the description of the items of a batch is
+// invalid.
+var ErrInvalidBatch = errors.New("invalid batch")
+
+// batchItemNameRegexp restricts the names of the items, as they prefix the
+// names of their output files.
+var batchItemNameRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// batchItem is an independent conversion of a batch, i.e., the steps of a
+// pipeline over some of the uploaded files.
+type batchItem struct {
+	Name  string          `json:"name,omitempty"`
+	Files []string        `json:"files"`
+	Steps json.RawMessage `json:"steps"`
+
+	steps      []step
+	inputPaths []string
+}
+
+// batchResult is the status of an item in the batch report.
+type batchResult struct {
+	Name    string   `json:"name"`
+	Status  int      `json:"status"`
+	Code    string   `json:"code,omitempty"`
+	Message string   `json:"message,omitempty"`
+	Outputs []string `json:"outputs,omitempty"`
+}
+
+// parseBatch unmarshals and validates the JSON description of the items of a
+// batch. The files of the items are the names of the uploaded files.
+func parseBatch(value string, inputPaths []string, maxItems, maxSteps int) ([]batchItem, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+
+	var items []batchItem
+	err := decoder.Decode(&items)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal items: %v: %w", err, ErrInvalidBatch)
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no item: %w", ErrInvalidBatch)
+	}
+
+	if maxItems > 0 && len(items) > maxItems {
+		return nil, fmt.Errorf("%d items, more than the maximum of %d: %w", len(items), maxItems, ErrInvalidBatch)
+	}
+
+	pathsByFilename := make(map[string]string, len(inputPaths))
+	for _, inputPath := range inputPaths {
+		pathsByFilename[filepath.Base(inputPath)] = inputPath
+	}
+
+	names := make(map[string]bool, len(items))
+
+	for i := range items {
+		item := &items[i]
+		if item.Name == "" {
+			item.Name = fmt.Sprintf("item-%d", i+1)
+		}
+
+		invalid := func(format string, a ...any) error {
+			return fmt.Errorf("item %d (%s): %s: %w", i+1, item.Name, fmt.Sprintf(format, a...), ErrInvalidBatch)
+		}
+
+		if !batchItemNameRegexp.MatchString(item.Name) {
+			return nil, invalid("wrong name, expected letters, digits, '.', '_' or '-'")
+		}
+
+		if names[item.Name] {
+			return nil, invalid("duplicate name")
+		}
+
+		names[item.Name] = true
+
+		if len(item.Files) == 0 {
+			return nil, invalid("no file")
+		}
+
+		for _, filename := range item.Files {
+			inputPath, ok := pathsByFilename[filename]
+			if !ok {
+				return nil, invalid("file '%s' not uploaded", filename)
+			}
+
+			item.inputPaths = append(item.inputPaths, inputPath)
+		}
+
+		item.steps, err = parseSteps(string(item.Steps), item.inputPaths, maxSteps)
+		if err != nil {
+			return nil, invalid("%v", err)
+		}
+	}
+
+	return items, nil
+}
+
+// runBatch runs the items of a batch, at most the given number at a time. A
+// failing item does not stop the others. It returns the status of each item
+// and the resulting files, whose names start with the name of their item.
+func runBatch(ctx *api.Context, chromiumApi chromium.Api, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, upload uploader, items []batchItem, parallelism int) ([]batchResult, []string) {
+	results := make([]batchResult, len(items))
+	outputPaths := make([][]string, len(items))
+
+	eg := new(errgroup.Group)
+	eg.SetLimit(parallelism)
+
+	for i, item := range items {
+		i, item := i, item
+		eg.Go(func() error {
+			paths, err := runBatchItem(ctx, chromiumApi, libreOffice, engine, upload, item)
+			if err != nil {
+				ctx.Log().Error(fmt.Sprintf("run batch item '%s': %s", item.Name, err))
+
+				response := api.ParseErrorResponse(pipelineError(err))
+				results[i] = batchResult{
+					Name:    item.Name,
+					Status:  response.Status,
+					Code:    response.Code,
+					Message: response.Message,
+				}
+
+				return nil
+			}
+
+			results[i] = batchResult{
+				Name:   item.Name,
+				Status: http.StatusOK,
+			}
+
+			for _, path := range paths {
+				results[i].Outputs = append(results[i].Outputs, filepath.Base(path))
+			}
+
+			outputPaths[i] = paths
+
+			return nil
+		})
+	}
+
+	// The items never fail the group.
+	_ = eg.Wait()
+
+	var paths []string
+	for _, itemPaths := range outputPaths {
+		paths = append(paths, itemPaths...)
+	}
+
+	return results, paths
+}
+
+// runBatchItem runs the steps of an item over its own links to the uploaded
+// files, prefixed with the name of the item. Thus, the items do not
+// overwrite the files of one another, even if they process the same files.
+func runBatchItem(ctx *api.Context, chromiumApi chromium.Api, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, upload uploader, item batchItem) ([]string, error) {
+	prefix := item.Name + "_"
+
+	inputPaths := make([]string, len(item.inputPaths))
+	for i, inputPath := range item.inputPaths {
+		inputPaths[i] = ctx.GeneratePath(prefix+filepath.Base(inputPath), "")
+
+		err := os.Link(inputPath, inputPaths[i])
+		if err != nil {
+			return nil, fmt.Errorf("link '%s': %w", filepath.Base(inputPath), err)
+		}
+	}
+
+	paths, err := run(ctx, chromiumApi, libreOffice, engine, upload, item.steps, inputPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some steps, e.g., merge, name the files after themselves.
+	for i, path := range paths {
+		if strings.HasPrefix(filepath.Base(path), prefix) {
+			continue
+		}
+
+		renamedPath := ctx.GeneratePath(prefix+filepath.Base(path), "")
+
+		err = os.Rename(path, renamedPath)
+		if err != nil {
+			return nil, fmt.Errorf("rename '%s': %w", filepath.Base(path), err)
+		}
+
+		paths[i] = renamedPath
+	}
+
+	return paths, nil
+}
+
+// writeBatchReport writes the status of each item of a batch to the working
+// directory.
+func writeBatchReport(ctx *api.Context, results []batchResult) (string, error) {
+	b, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal batch report: %w", err)
+	}
+
+	// Within its own directory, so that it does not overwrite an output file
+	// with the same name.
+	reportPath, err := keepName(ctx, batchReportFilename)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.WriteFile(reportPath, b, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("write batch report: %w", err)
+	}
+
+	return reportPath, nil
+}
@@ -0,0 +1,226 @@
+package pipeline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestParseBatch(t *testing.T) {
+	inputPaths := []string{"/tmp/document.docx", "/tmp/document.pdf"}
+
+	for _, tc := range []struct {
+		scenario    string
+		value       string
+		maxItems    int
+		expectNames []string
+		expectError bool
+	}{
+		{
+			scenario:    "invalid JSON",
+			value:       "foo",
+			expectError: true,
+		},
+		{
+			scenario:    "unknown field",
+			value:       `[{"files": ["document.pdf"], "steps": [{"type": "merge"}], "foo": "bar"}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "no item",
+			value:       `[]`,
+			expectError: true,
+		},
+		{
+			scenario:    "too many items",
+			value:       `[{"files": ["document.pdf"], "steps": [{"type": "merge"}]}, {"files": ["document.pdf"], "steps": [{"type": "merge"}]}]`,
+			maxItems:    1,
+			expectError: true,
+		},
+		{
+			scenario:    "wrong name",
+			value:       `[{"name": "../foo", "files": ["document.pdf"], "steps": [{"type": "merge"}]}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "duplicate name",
+			value:       `[{"name": "foo", "files": ["document.pdf"], "steps": [{"type": "merge"}]}, {"name": "foo", "files": ["document.pdf"], "steps": [{"type": "merge"}]}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "no file",
+			value:       `[{"steps": [{"type": "merge"}]}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "file not uploaded",
+			value:       `[{"files": ["foo.pdf"], "steps": [{"type": "merge"}]}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "no steps",
+			value:       `[{"files": ["document.pdf"]}]`,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid steps",
+			value:       `[{"files": ["document.docx"], "steps": [{"type": "merge"}]}]`,
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			value: `[
+				{"name": "foo", "files": ["document.docx"], "steps": [{"type": "convert"}]},
+				{"files": ["document.pdf", "document.docx"], "steps": [{"type": "convert"}, {"type": "merge"}]}
+			]`,
+			maxItems:    2,
+			expectNames: []string{"foo", "item-2"},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			items, err := parseBatch(tc.value, inputPaths, tc.maxItems, 10)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError {
+				if !errors.Is(err, ErrInvalidBatch) {
+					t.Fatalf("expected error %v but got: %v", ErrInvalidBatch, err)
+				}
+
+				return
+			}
+
+			var names []string
+			for _, item := range items {
+				names = append(names, item.Name)
+
+				if len(item.inputPaths) != len(item.Files) {
+					t.Errorf("expected %d input paths but got %d", len(item.Files), len(item.inputPaths))
+				}
+			}
+
+			if !reflect.DeepEqual(names, tc.expectNames) {
+				t.Errorf("expected names %v but got %v", tc.expectNames, names)
+			}
+		})
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+
+	dirPath := t.TempDir()
+	inputPaths := []string{filepath.Join(dirPath, "document.docx"), filepath.Join(dirPath, "document.pdf")}
+
+	for _, inputPath := range inputPaths {
+		copyFile(t, samplePath, inputPath)
+	}
+
+	ctx := &api.ContextMock{Context: new(api.Context)}
+	ctx.SetDirPath(dirPath)
+	ctx.SetLogger(zap.NewNop())
+	ctx.Context.Context = context.Background()
+
+	libreOffice := &libreofficeapi.ApiMock{
+		PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+			if options.PdfFormats.PdfA != "" {
+				return libreofficeapi.ErrInvalidPdfFormats
+			}
+
+			copyFile(t, samplePath, outputPath)
+
+			return nil
+		},
+	}
+
+	engine := &gotenberg.PdfEngineMock{
+		MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+			copyFile(t, inputPaths[0], outputPath)
+
+			return nil
+		},
+	}
+
+	items, err := parseBatch(`[
+		{"name": "foo", "files": ["document.docx"], "steps": [{"type": "convert"}]},
+		{"name": "bar", "files": ["document.docx"], "steps": [{"type": "convert", "pdfa": "PDF/A-1b"}]},
+		{"name": "baz", "files": ["document.docx", "document.pdf"], "steps": [{"type": "convert"}, {"type": "merge"}]}
+	]`, inputPaths, 0, 10)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	results, outputPaths := runBatch(ctx.Context, new(chromium.ApiMock), libreOffice, engine, uploader{}, items, 2)
+
+	expectStatuses := []int{http.StatusOK, http.StatusBadRequest, http.StatusOK}
+	for i, result := range results {
+		if result.Name != items[i].Name {
+			t.Errorf("expected result %d for '%s' but got '%s'", i, items[i].Name, result.Name)
+		}
+
+		if result.Status != expectStatuses[i] {
+			t.Errorf("expected status %d for '%s' but got %d", expectStatuses[i], result.Name, result.Status)
+		}
+	}
+
+	if results[1].Code != "LIBREOFFICE_INVALID_PDF_FORMATS" {
+		t.Errorf("expected code 'LIBREOFFICE_INVALID_PDF_FORMATS' but got '%s'", results[1].Code)
+	}
+
+	var filenames []string
+	for _, outputPath := range outputPaths {
+		filenames = append(filenames, filepath.Base(outputPath))
+
+		_, err = os.Stat(outputPath)
+		if err != nil {
+			t.Errorf("expected output file '%s' but got: %v", outputPath, err)
+		}
+	}
+
+	sort.Strings(filenames)
+
+	if len(filenames) != 2 || !strings.HasPrefix(filenames[0], "baz_") || filenames[1] != "foo_document.docx.pdf" {
+		t.Errorf("expected an output file for 'foo' and 'baz' but got %v", filenames)
+	}
+
+	reportPath, err := writeBatchReport(ctx.Context, results)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if filepath.Base(reportPath) != batchReportFilename {
+		t.Errorf("expected report '%s' but got '%s'", batchReportFilename, filepath.Base(reportPath))
+	}
+
+	b, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var report []batchResult
+	err = json.Unmarshal(b, &report)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !reflect.DeepEqual(report, results) {
+		t.Errorf("expected report %+v but got %+v", results, report)
+	}
+}
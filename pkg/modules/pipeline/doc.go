@@ -0,0 +1,5 @@
+// Package pipeline provides a module which adds a route for processing the
+// uploaded files through several steps in a single request, e.g., converting
+// them to PDF, merging, stamping, and encrypting the result, then uploading
+// it. Another route runs many independent pipelines in a single request.
+package pipeline
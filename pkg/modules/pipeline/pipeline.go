@@ -0,0 +1,159 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dlclark/regexp2"
+	pdfcpuLog "github.com/pdfcpu/pdfcpu/pkg/log"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Pipeline))
+}
+
+// Pipeline is a module which provides routes for running user-defined
+// steps over the uploaded files, either once or for each item of a batch. It relies on the Chromium and LibreOffice
+// modules for the conversions, and on the PDF engines for the merges.
+type Pipeline struct {
+	maxSteps        int
+	maxBatchItems   int
+	batchParallel   int
+	uploadTimeout   time.Duration
+	uploadAllowList *regexp2.Regexp
+	uploadDenyList  *regexp2.Regexp
+	chromium        chromium.Api
+	libreOffice     libreofficeapi.Uno
+	engine          gotenberg.PdfEngine
+	disableRoutes   bool
+}
+
+// Descriptor returns a [Pipeline]'s module descriptor.
+func (mod *Pipeline) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "pipeline",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("pipeline", flag.ExitOnError)
+			fs.Int("pipeline-max-steps", 10, "Set the maximum number of steps of a pipeline - 0 means no limit")
+			fs.Int("pipeline-batch-max-items", 100, "Set the maximum number of items of a batch - 0 means no limit")
+			fs.Int("pipeline-batch-parallelism", 4, "Set the number of items of a batch which run at the same time")
+			fs.Duration("pipeline-upload-timeout", time.Duration(30)*time.Second, "Set the timeout of each request of the upload steps")
+			fs.String("pipeline-upload-allow-list", "", "Set the allowed URLs for the upload steps using a regular expression")
+			fs.String("pipeline-upload-deny-list", "", "Set the denied URLs for the upload steps using a regular expression")
+			fs.Bool("pipeline-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Pipeline) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Pipeline) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.maxSteps = flags.MustInt("pipeline-max-steps")
+	mod.maxBatchItems = flags.MustInt("pipeline-batch-max-items")
+	mod.batchParallel = flags.MustInt("pipeline-batch-parallelism")
+	mod.uploadTimeout = flags.MustDuration("pipeline-upload-timeout")
+	mod.uploadAllowList = flags.MustRegexp("pipeline-upload-allow-list")
+	mod.uploadDenyList = flags.MustRegexp("pipeline-upload-deny-list")
+	mod.disableRoutes = flags.MustBool("pipeline-disable-routes")
+
+	provider, err := ctx.Module(new(chromium.Provider))
+	if err != nil {
+		return fmt.Errorf("get Chromium provider: %w", err)
+	}
+
+	chromiumApi, err := provider.(chromium.Provider).Chromium()
+	if err != nil {
+		return fmt.Errorf("get Chromium API: %w", err)
+	}
+
+	mod.chromium = chromiumApi
+
+	provider, err = ctx.Module(new(libreofficeapi.Provider))
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno provider: %w", err)
+	}
+
+	libreOffice, err := provider.(libreofficeapi.Provider).LibreOffice()
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno: %w", err)
+	}
+
+	mod.libreOffice = libreOffice
+
+	provider, err = ctx.Module(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine provider: %w", err)
+	}
+
+	engine, err := provider.(gotenberg.PdfEngineProvider).PdfEngine()
+	if err != nil {
+		return fmt.Errorf("get PDF engine: %w", err)
+	}
+
+	mod.engine = engine
+
+	// Same as the pdfcpu module.
+	pdfcpuConfig.ConfigPath = "disable"
+	pdfcpuLog.DisableLoggers()
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Pipeline) Validate() error {
+	if mod.maxSteps < 0 {
+		return errors.New("max steps must be more than or equal to 0")
+	}
+
+	if mod.maxBatchItems < 0 {
+		return errors.New("max batch items must be more than or equal to 0")
+	}
+
+	if mod.batchParallel < 1 {
+		return errors.New("batch parallelism must be more than 0")
+	}
+
+	if mod.uploadTimeout <= 0 {
+		return errors.New("upload timeout must be more than 0")
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Pipeline) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	upload := uploader{
+		client:    &http.Client{Timeout: mod.uploadTimeout},
+		allowList: mod.uploadAllowList,
+		denyList:  mod.uploadDenyList,
+	}
+
+	return []api.Route{
+		pipelineRoute(mod.chromium, mod.libreOffice, mod.engine, upload, mod.maxSteps),
+		batchRoute(mod.chromium, mod.libreOffice, mod.engine, upload, mod.maxBatchItems, mod.maxSteps, mod.batchParallel),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Pipeline)(nil)
+	_ gotenberg.Provisioner = (*Pipeline)(nil)
+	_ gotenberg.Validator   = (*Pipeline)(nil)
+	_ api.Router            = (*Pipeline)(nil)
+)
@@ -0,0 +1,239 @@
+package pipeline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestPipeline_Descriptor(t *testing.T) {
+	descriptor := new(Pipeline).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Pipeline))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestPipeline_Provision(t *testing.T) {
+	// Each provider is a distinct module, so that a scenario may omit any of
+	// them.
+	chromiumProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			chromium.ProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "chromium", New: func() gotenberg.Module { return mod }}
+		}
+		mod.ChromiumMock = func() (chromium.Api, error) {
+			return new(chromium.ApiMock), err
+		}
+
+		return mod
+	}
+
+	libreOfficeProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			libreofficeapi.ProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "libreoffice", New: func() gotenberg.Module { return mod }}
+		}
+		mod.LibreOfficeMock = func() (libreofficeapi.Uno, error) {
+			return new(libreofficeapi.ApiMock), err
+		}
+
+		return mod
+	}
+
+	pdfEngineProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			gotenberg.PdfEngineProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "pdfengines", New: func() gotenberg.Module { return mod }}
+		}
+		mod.PdfEngineMock = func() (gotenberg.PdfEngine, error) {
+			return new(gotenberg.PdfEngineMock), err
+		}
+
+		return mod
+	}
+
+	newContext := func(mods ...gotenberg.Module) *gotenberg.Context {
+		descriptors := make([]gotenberg.ModuleDescriptor, len(mods))
+		for i, mod := range mods {
+			descriptors[i] = mod.Descriptor()
+		}
+
+		return gotenberg.NewContext(
+			gotenberg.ParsedFlags{
+				FlagSet: new(Pipeline).Descriptor().FlagSet,
+			},
+			descriptors,
+		)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectError bool
+	}{
+		{
+			scenario:    "no Chromium API provider",
+			ctx:         newContext(libreOfficeProvider(nil), pdfEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no Chromium API from Chromium API provider",
+			ctx:         newContext(chromiumProvider(errors.New("foo")), libreOfficeProvider(nil), pdfEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice API provider",
+			ctx:         newContext(chromiumProvider(nil), pdfEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice API from LibreOffice API provider",
+			ctx:         newContext(chromiumProvider(nil), libreOfficeProvider(errors.New("foo")), pdfEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no PDF engine provider",
+			ctx:         newContext(chromiumProvider(nil), libreOfficeProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no PDF engine from PDF engine provider",
+			ctx:         newContext(chromiumProvider(nil), libreOfficeProvider(nil), pdfEngineProvider(errors.New("foo"))),
+			expectError: true,
+		},
+		{
+			scenario:    "provision success",
+			ctx:         newContext(chromiumProvider(nil), libreOfficeProvider(nil), pdfEngineProvider(nil)),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Pipeline)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestPipeline_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		maxSteps      int
+		maxBatchItems int
+		batchParallel int
+		uploadTimeout time.Duration
+		expectError   bool
+	}{
+		{
+			scenario:      "invalid max steps",
+			maxSteps:      -1,
+			batchParallel: 4,
+			uploadTimeout: time.Duration(30) * time.Second,
+			expectError:   true,
+		},
+		{
+			scenario:      "invalid max batch items",
+			maxSteps:      10,
+			maxBatchItems: -1,
+			batchParallel: 4,
+			uploadTimeout: time.Duration(30) * time.Second,
+			expectError:   true,
+		},
+		{
+			scenario:      "invalid batch parallelism",
+			maxSteps:      10,
+			batchParallel: 0,
+			uploadTimeout: time.Duration(30) * time.Second,
+			expectError:   true,
+		},
+		{
+			scenario:      "invalid upload timeout",
+			maxSteps:      10,
+			batchParallel: 4,
+			uploadTimeout: 0,
+			expectError:   true,
+		},
+		{
+			scenario:      "validate success",
+			maxSteps:      0,
+			batchParallel: 4,
+			uploadTimeout: time.Duration(30) * time.Second,
+			expectError:   false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Pipeline)
+			mod.maxSteps = tc.maxSteps
+			mod.maxBatchItems = tc.maxBatchItems
+			mod.batchParallel = tc.batchParallel
+			mod.uploadTimeout = tc.uploadTimeout
+			err := mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestPipeline_Routes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		expectRoutes  int
+		disableRoutes bool
+	}{
+		{
+			scenario:      "routes not disabled",
+			expectRoutes:  2,
+			disableRoutes: false,
+		},
+		{
+			scenario:      "routes disabled",
+			expectRoutes:  0,
+			disableRoutes: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Pipeline)
+			mod.disableRoutes = tc.disableRoutes
+
+			routes, err := mod.Routes()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectRoutes != len(routes) {
+				t.Errorf("expected %d routes but got %d", tc.expectRoutes, len(routes))
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+// pipelineRoute returns an [api.Route] which can run the steps described in
+// the "steps" form field over the uploaded files. The files resulting from
+// the last step are the outputs.
+func pipelineRoute(chromiumApi chromium.Api, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, upload uploader, maxSteps int) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pipeline",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			extensions := []string{".pdf", ".html"}
+			for _, ext := range libreOffice.Extensions() {
+				if !slices.Contains(extensions, ext) {
+					extensions = append(extensions, ext)
+				}
+			}
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				steps      []step
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths(extensions, &inputPaths).
+				MandatoryCustom("steps", func(value string) error {
+					var err error
+					steps, err = parseSteps(value, inputPaths, maxSteps)
+
+					return err
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				return errors.New("context has no deadline")
+			}
+
+			err = upload.filter(steps, deadline)
+			if err != nil {
+				return fmt.Errorf("filter upload URLs: %w", err)
+			}
+
+			// Alright, let's run the pipeline.
+			outputPaths, err := run(ctx, chromiumApi, libreOffice, engine, upload, steps, inputPaths)
+			if err != nil {
+				return fmt.Errorf("run pipeline: %w", pipelineError(err))
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// batchRoute returns an [api.Route] which can run the independent items
+// described in the "items" form field, each being the steps of a pipeline
+// over some of the uploaded files. The outputs are the files resulting from
+// the items, prefixed with their names, and a report with the status of each
+// item.
+func batchRoute(chromiumApi chromium.Api, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, upload uploader, maxItems, maxSteps, parallelism int) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/pipeline/batch",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			extensions := []string{".pdf", ".html"}
+			for _, ext := range libreOffice.Extensions() {
+				if !slices.Contains(extensions, ext) {
+					extensions = append(extensions, ext)
+				}
+			}
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				items      []batchItem
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths(extensions, &inputPaths).
+				MandatoryCustom("items", func(value string) error {
+					var err error
+					items, err = parseBatch(value, inputPaths, maxItems, maxSteps)
+
+					return err
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			deadline, ok := ctx.Deadline()
+			if !ok {
+				return errors.New("context has no deadline")
+			}
+
+			for _, item := range items {
+				err = upload.filter(item.steps, deadline)
+				if err != nil {
+					return fmt.Errorf("filter upload URLs of item '%s': %w", item.Name, err)
+				}
+			}
+
+			// Alright, let's run the items.
+			results, outputPaths := runBatch(ctx, chromiumApi, libreOffice, engine, upload, items, parallelism)
+
+			reportPath, err := writeBatchReport(ctx, results)
+			if err != nil {
+				return fmt.Errorf("write batch report: %w", err)
+			}
+
+			err = ctx.AddOutputPaths(append(outputPaths, reportPath)...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// pipelineError wraps the errors of a pipeline the client is responsible
+// for, so that they have a meaningful HTTP status.
+func pipelineError(err error) error {
+	if errors.Is(err, libreofficeapi.ErrInvalidPdfFormats) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusBadRequest, "A PDF format of a convert step is not supported").WithCode(api.ErrorCodeLibreofficeInvalidPdfFormats),
+		)
+	}
+
+	if errors.Is(err, ErrUploadFailed) {
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusBadGateway, "An upload step failed").WithCode(api.ErrorCodePipelineUploadFailed),
+		)
+	}
+
+	return err
+}
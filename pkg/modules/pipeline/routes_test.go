@@ -0,0 +1,347 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dlclark/regexp2"
+	"github.com/labstack/echo/v4"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestPipelineRoute(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newContext := func(filenames []string, values map[string][]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string)
+
+		for _, filename := range filenames {
+			path := filepath.Join(dirPath, filename)
+			copyFile(t, samplePath, path)
+			paths[filename] = path
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetFiles(paths)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	libreOffice := func(err error) libreofficeapi.Uno {
+		return &libreofficeapi.ApiMock{
+			PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				if err != nil {
+					return err
+				}
+
+				copyFile(t, samplePath, outputPath)
+
+				return nil
+			},
+			ExtensionsMock: func() []string {
+				return []string{".docx"}
+			},
+		}
+	}
+
+	chromiumApi := &chromium.ApiMock{
+		PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options chromium.PdfOptions) error {
+			copyFile(t, samplePath, outputPath)
+
+			return nil
+		},
+	}
+
+	engine := &gotenberg.PdfEngineMock{
+		MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+			copyFile(t, inputPaths[0], outputPath)
+
+			return nil
+		},
+	}
+
+	upload := uploader{
+		client:    server.Client(),
+		allowList: regexp2.MustCompile("", 0),
+		denyList:  regexp2.MustCompile(`/denied$`, 0),
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		libreOffice            libreofficeapi.Uno
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "missing at least one mandatory file",
+			ctx:                    newContext(nil, map[string][]string{"steps": {`[{"type": "merge"}]`}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "missing steps form field",
+			ctx:                    newContext([]string{"document.pdf"}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid steps form field",
+			ctx:                    newContext([]string{"document.pdf"}, map[string][]string{"steps": {`[{"type": "foo"}]`}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "filtered upload URL",
+			ctx:                    newContext([]string{"document.pdf"}, map[string][]string{"steps": {`[{"type": "upload", "url": "` + server.URL + `/denied"}]`}}),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "ErrInvalidPdfFormats",
+			ctx:                    newContext([]string{"document.docx"}, map[string][]string{"steps": {`[{"type": "convert", "pdfa": "PDF/A-1b"}]`}}),
+			libreOffice:            libreOffice(libreofficeapi.ErrInvalidPdfFormats),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from LibreOffice",
+			ctx:                    newContext([]string{"document.docx"}, map[string][]string{"steps": {`[{"type": "convert"}]`}}),
+			libreOffice:            libreOffice(errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "ErrUploadFailed",
+			ctx:                    newContext([]string{"document.pdf"}, map[string][]string{"steps": {`[{"type": "upload", "url": "` + server.URL + `/fail"}]`}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadGateway,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "success (many files)",
+			ctx:                    newContext([]string{"document.docx", "document.pdf"}, map[string][]string{"steps": {`[{"type": "convert"}]`}}),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+		},
+		{
+			scenario: "success (one file)",
+			ctx: newContext([]string{"document.docx", "document.html"}, map[string][]string{"steps": {`[
+				{"type": "convert"},
+				{"type": "merge"},
+				{"type": "encrypt", "ownerPassword": "foo"},
+				{"type": "upload", "url": "` + server.URL + `/{filename}"}
+			]`}}),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+			defer cancel()
+
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = ctx
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			if tc.libreOffice == nil {
+				tc.libreOffice = libreOffice(nil)
+			}
+
+			err := pipelineRoute(chromiumApi, tc.libreOffice, engine, upload, 10).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+		})
+	}
+}
+
+func TestBatchRoute(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+
+	newContext := func(filenames []string, values map[string][]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string)
+
+		for _, filename := range filenames {
+			path := filepath.Join(dirPath, filename)
+			copyFile(t, samplePath, path)
+			paths[filename] = path
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetFiles(paths)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	libreOffice := &libreofficeapi.ApiMock{
+		PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+			if strings.Contains(inputPath, "broken") {
+				return errors.New("foo")
+			}
+
+			copyFile(t, samplePath, outputPath)
+
+			return nil
+		},
+		ExtensionsMock: func() []string {
+			return []string{".docx"}
+		},
+	}
+
+	upload := uploader{
+		allowList: regexp2.MustCompile("", 0),
+		denyList:  regexp2.MustCompile(`/denied$`, 0),
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:         "missing items form field",
+			ctx:              newContext([]string{"document.pdf"}, nil),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:         "invalid items form field",
+			ctx:              newContext([]string{"document.pdf"}, map[string][]string{"items": {`[{"files": ["foo.pdf"], "steps": [{"type": "merge"}]}]`}}),
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario:    "filtered upload URL",
+			ctx:         newContext([]string{"document.pdf"}, map[string][]string{"items": {`[{"files": ["document.pdf"], "steps": [{"type": "upload", "url": "https://foo/denied"}]}]`}}),
+			expectError: true,
+		},
+		{
+			scenario: "success with a failed item",
+			ctx: newContext([]string{"document.docx", "broken.docx"}, map[string][]string{"items": {`[
+				{"files": ["document.docx"], "steps": [{"type": "convert"}]},
+				{"files": ["broken.docx"], "steps": [{"type": "convert"}]},
+				{"files": ["document.docx"], "steps": [{"type": "convert", "pdfua": true}]}
+			]`}}),
+			expectOutputPathsCount: 3,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(5)*time.Second)
+			defer cancel()
+
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = ctx
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := batchRoute(new(chromium.ApiMock), libreOffice, new(gotenberg.PdfEngineMock), upload, 10, 10, 2).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+		})
+	}
+}
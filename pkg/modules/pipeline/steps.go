@@ -0,0 +1,409 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dlclark/regexp2"
+	"github.com/labstack/echo/v4"
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	pdfcpuTypes "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pdfengines"
+)
+
+const (
+	// stepConvert converts the files to PDF, and optionally to PDF/A and
+	// PDF/UA.
+	stepConvert = "convert"
+
+	// stepMerge merges the PDFs into a single PDF, in the order of the files.
+	stepMerge = "merge"
+
+	// stepStamp adds a text stamp on top of the pages of the PDFs.
+	stepStamp = "stamp"
+
+	// stepEncrypt encrypts the PDFs with AES-256.
+	stepEncrypt = "encrypt"
+
+	// stepUpload sends each file to a URL. The files remain the outputs of
+	// the pipeline.
+	stepUpload = "upload"
+)
+
+var (
+	// ErrInvalidSteps happens if the description of the steps is invalid.
+	ErrInvalidSteps = errors.New("invalid steps")
+
+	// ErrUploadFailed happens if the destination of an upload step does not
+	// accept a file.
+	ErrUploadFailed = errors.New("upload failed")
+)
+
+// step is a processing step of a pipeline. Each type of step reads its own
+// fields.
+type step struct {
+	Type string `json:"type"`
+
+	// Convert.
+	PdfA  string `json:"pdfa,omitempty"`
+	PdfUa bool   `json:"pdfua,omitempty"`
+
+	// Stamp.
+	Text        string `json:"text,omitempty"`
+	Description string `json:"description,omitempty"`
+	Pages       string `json:"pages,omitempty"`
+
+	// Encrypt.
+	UserPassword  string `json:"userPassword,omitempty"`
+	OwnerPassword string `json:"ownerPassword,omitempty"`
+
+	// Upload.
+	Url     string            `json:"url,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// parseSteps unmarshals and validates the JSON description of the steps of
+// a pipeline. The input files are the files the first step processes.
+func parseSteps(value string, inputPaths []string, maxSteps int) ([]step, error) {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+
+	var steps []step
+	err := decoder.Decode(&steps)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal steps: %v: %w", err, ErrInvalidSteps)
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("no step: %w", ErrInvalidSteps)
+	}
+
+	if maxSteps > 0 && len(steps) > maxSteps {
+		return nil, fmt.Errorf("%d steps, more than the maximum of %d: %w", len(steps), maxSteps, ErrInvalidSteps)
+	}
+
+	// The steps but convert require PDFs.
+	onlyPdfs := true
+	for _, inputPath := range inputPaths {
+		if strings.ToLower(filepath.Ext(inputPath)) != ".pdf" {
+			onlyPdfs = false
+			break
+		}
+	}
+
+	for i, s := range steps {
+		invalid := func(format string, a ...any) error {
+			return fmt.Errorf("step %d (%s): %s: %w", i+1, s.Type, fmt.Sprintf(format, a...), ErrInvalidSteps)
+		}
+
+		switch s.Type {
+		case stepConvert:
+			if s.PdfA != "" && !strings.HasPrefix(s.PdfA, "PDF/A-") {
+				return nil, invalid("wrong PDF/A format '%s'", s.PdfA)
+			}
+
+			onlyPdfs = true
+
+			continue
+		case stepMerge, stepStamp, stepEncrypt:
+			if !onlyPdfs {
+				return nil, invalid("requires PDFs, add a '%s' step before", stepConvert)
+			}
+		case stepUpload:
+		default:
+			return nil, invalid("unknown type, expected one of '%s', '%s', '%s', '%s' or '%s'", stepConvert, stepMerge, stepStamp, stepEncrypt, stepUpload)
+		}
+
+		switch s.Type {
+		case stepStamp:
+			if s.Text == "" {
+				return nil, invalid("missing text")
+			}
+
+			_, err = pdfcpuAPI.TextWatermark(s.Text, s.Description, true, false, pdfcpuTypes.POINTS)
+			if err != nil {
+				return nil, invalid("wrong description '%s': %v", s.Description, err)
+			}
+
+			if s.Pages != "" {
+				_, err = pdfcpuAPI.ParsePageSelection(s.Pages)
+				if err != nil {
+					return nil, invalid("wrong pages '%s': %v", s.Pages, err)
+				}
+			}
+		case stepEncrypt:
+			if s.OwnerPassword == "" {
+				return nil, invalid("missing owner password")
+			}
+		case stepUpload:
+			u, err := url.Parse(s.Url)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, invalid("wrong URL '%s', expected an HTTP or HTTPS URL", s.Url)
+			}
+
+			method := strings.ToUpper(s.Method)
+			if method == "" {
+				method = http.MethodPut
+			}
+
+			if method != http.MethodPost && method != http.MethodPut && method != http.MethodPatch {
+				return nil, invalid("wrong method '%s', expected '%s', '%s' or '%s'", s.Method, http.MethodPost, http.MethodPut, http.MethodPatch)
+			}
+
+			steps[i].Method = method
+		}
+	}
+
+	return steps, nil
+}
+
+// uploader sends files to the URLs of the upload steps.
+type uploader struct {
+	client    *http.Client
+	allowList *regexp2.Regexp
+	denyList  *regexp2.Regexp
+}
+
+// filter checks the URLs of the upload steps against the allowed and denied
+// lists.
+func (u uploader) filter(steps []step, deadline time.Time) error {
+	for _, s := range steps {
+		if s.Type != stepUpload {
+			continue
+		}
+
+		err := gotenberg.FilterDeadline(u.allowList, u.denyList, s.Url, deadline)
+		if err != nil {
+			return fmt.Errorf("filter upload URL: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// run executes the steps over the files, and returns the resulting files.
+func run(ctx *api.Context, chromiumApi chromium.Api, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, upload uploader, steps []step, paths []string) ([]string, error) {
+	var err error
+
+	for i, s := range steps {
+		switch s.Type {
+		case stepConvert:
+			paths, err = convert(ctx, chromiumApi, libreOffice, engine, s, paths)
+		case stepMerge:
+			paths, err = merge(ctx, engine, paths)
+		case stepStamp:
+			paths, err = forEachPdf(ctx, paths, func(inputPath, outputPath string) error {
+				var pages []string
+				if s.Pages != "" {
+					var err error
+					pages, err = pdfcpuAPI.ParsePageSelection(s.Pages)
+					if err != nil {
+						return fmt.Errorf("parse pages: %w", err)
+					}
+				}
+
+				return pdfcpuAPI.AddTextWatermarksFile(inputPath, outputPath, pages, true, s.Text, s.Description, pdfcpuConfig.NewDefaultConfiguration())
+			})
+		case stepEncrypt:
+			paths, err = forEachPdf(ctx, paths, func(inputPath, outputPath string) error {
+				conf := pdfcpuConfig.NewDefaultConfiguration()
+				conf.UserPW = s.UserPassword
+				conf.OwnerPW = s.OwnerPassword
+
+				return pdfcpuAPI.EncryptFile(inputPath, outputPath, conf)
+			})
+		case stepUpload:
+			err = upload.send(ctx, s, paths)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, s.Type, err)
+		}
+	}
+
+	return paths, nil
+}
+
+// convert converts the files which are not PDFs to PDF, with Chromium for
+// the HTML files and LibreOffice for the others.
+func convert(ctx *api.Context, chromiumApi chromium.Api, libreOffice libreofficeapi.Uno, engine gotenberg.PdfEngine, s step, paths []string) ([]string, error) {
+	formats := gotenberg.PdfFormats{
+		PdfA:  s.PdfA,
+		PdfUa: s.PdfUa,
+	}
+	withFormats := formats != gotenberg.PdfFormats{}
+
+	outputPaths := make([]string, len(paths))
+
+	for i, inputPath := range paths {
+		filename := filepath.Base(inputPath)
+
+		// document.docx -> document.docx.pdf.
+		outputPath := ctx.GeneratePath(filename, ".pdf")
+
+		var err error
+		switch strings.ToLower(filepath.Ext(inputPath)) {
+		case ".pdf":
+			if !withFormats {
+				outputPaths[i] = inputPath
+				continue
+			}
+
+			outputPath, err = keepName(ctx, filename)
+			if err == nil {
+				err = engine.Convert(ctx, ctx.Log(), formats, inputPath, outputPath)
+			}
+		case ".html":
+			ctx.AddEngines("chromium")
+
+			pdfPath := outputPath
+			if withFormats {
+				pdfPath = ctx.GeneratePath("", ".pdf")
+			}
+
+			err = chromiumApi.Pdf(ctx, ctx.Log(), fmt.Sprintf("file://%s", inputPath), pdfPath, chromium.DefaultPdfOptions())
+			if err == nil && withFormats {
+				err = engine.Convert(ctx, ctx.Log(), formats, pdfPath, outputPath)
+			}
+		default:
+			ctx.AddEngines("libreoffice")
+			err = libreOffice.Pdf(ctx, ctx.Log(), inputPath, outputPath, libreofficeapi.Options{PdfFormats: formats})
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("convert '%s': %w", filename, err)
+		}
+
+		outputPaths[i] = outputPath
+	}
+
+	return outputPaths, nil
+}
+
+// merge merges the PDFs into a single PDF, which keeps their page labels.
+func merge(ctx *api.Context, engine gotenberg.PdfEngine, paths []string) ([]string, error) {
+	if len(paths) < 2 {
+		return paths, nil
+	}
+
+	labels, _, err := pdfengines.MergedPageLabels(ctx, ctx.Log(), engine, paths)
+	if err != nil {
+		return nil, fmt.Errorf("get page labels: %w", err)
+	}
+
+	outputPath := ctx.GeneratePath("", ".pdf")
+
+	err = engine.Merge(ctx, ctx.Log(), paths, outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("merge PDFs: %w", err)
+	}
+
+	if labels != nil {
+		err = pdfengines.WritePageLabels(outputPath, labels)
+		if err != nil {
+			return nil, fmt.Errorf("write page labels: %w", err)
+		}
+	}
+
+	return []string{outputPath}, nil
+}
+
+// forEachPdf applies a transformation to each PDF. The resulting PDFs keep
+// the names of the original ones.
+func forEachPdf(ctx *api.Context, paths []string, transform func(inputPath, outputPath string) error) ([]string, error) {
+	outputPaths := make([]string, len(paths))
+
+	for i, inputPath := range paths {
+		outputPath, err := keepName(ctx, filepath.Base(inputPath))
+		if err != nil {
+			return nil, err
+		}
+
+		err = transform(inputPath, outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("transform '%s': %w", filepath.Base(inputPath), err)
+		}
+
+		outputPaths[i] = outputPath
+	}
+
+	return outputPaths, nil
+}
+
+// keepName returns a path for a file with the given name, within a new
+// directory of the working directory, so that it does not overwrite a
+// previous version of this file.
+func keepName(ctx *api.Context, filename string) (string, error) {
+	dirPath := ctx.GeneratePath("", "")
+
+	err := os.Mkdir(dirPath, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("create directory: %w", err)
+	}
+
+	return filepath.Join(dirPath, filename), nil
+}
+
+// send uploads each file to the URL of an upload step. The "{filename}"
+// placeholder of the URL is the name of the file.
+func (u uploader) send(ctx *api.Context, s step, paths []string) error {
+	for _, path := range paths {
+		filename := filepath.Base(path)
+		URL := strings.ReplaceAll(s.Url, "{filename}", url.PathEscape(filename))
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read file: %w", err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, s.Method, URL, bytes.NewReader(b))
+		if err != nil {
+			return fmt.Errorf("create '%s' request to '%s': %w", s.Method, URL, err)
+		}
+
+		req.Header.Set("User-Agent", "Gotenberg")
+
+		for key, value := range s.Headers {
+			req.Header.Set(key, value)
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(filename))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		req.Header.Set(echo.HeaderContentType, contentType)
+		req.Header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+		resp, err := u.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("send '%s' request to '%s': %v: %w", s.Method, URL, err, ErrUploadFailed)
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("send '%s' request to '%s': got status code %d: %w", s.Method, URL, resp.StatusCode, ErrUploadFailed)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,376 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dlclark/regexp2"
+	pdfcpuAPI "github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuConfig "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/pdfengines"
+)
+
+// samplePath is a valid PDF.
+const samplePath = "../../../test/testdata/pdfengines/sample1.pdf"
+
+// copyFile copies a file, e.g., the sample PDF.
+func copyFile(t *testing.T, src, dst string) {
+	b, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = os.WriteFile(dst, b, 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+func TestParseSteps(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		value         string
+		inputPaths    []string
+		maxSteps      int
+		expectError   bool
+		expectedSteps []step
+	}{
+		{
+			scenario:    "invalid JSON",
+			value:       "foo",
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "unknown field",
+			value:       `[{"type": "merge", "foo": "bar"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "no step",
+			value:       `[]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "too many steps",
+			value:       `[{"type": "merge"}, {"type": "merge"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			maxSteps:    1,
+			expectError: true,
+		},
+		{
+			scenario:    "unknown type",
+			value:       `[{"type": "foo"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid PDF/A format",
+			value:       `[{"type": "convert", "pdfa": "foo"}]`,
+			inputPaths:  []string{"/foo.docx"},
+			expectError: true,
+		},
+		{
+			scenario:    "merge without convert",
+			value:       `[{"type": "merge"}]`,
+			inputPaths:  []string{"/foo.pdf", "/foo.docx"},
+			expectError: true,
+		},
+		{
+			scenario:    "stamp without text",
+			value:       `[{"type": "stamp"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "stamp with an invalid description",
+			value:       `[{"type": "stamp", "text": "foo", "description": "foo:bar"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "stamp with invalid pages",
+			value:       `[{"type": "stamp", "text": "foo", "pages": "foo"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "encrypt without owner password",
+			value:       `[{"type": "encrypt", "userPassword": "foo"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "upload with an invalid URL",
+			value:       `[{"type": "upload", "url": "file:///etc/passwd"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario:    "upload with an invalid method",
+			value:       `[{"type": "upload", "url": "https://foo", "method": "GET"}]`,
+			inputPaths:  []string{"/foo.pdf"},
+			expectError: true,
+		},
+		{
+			scenario: "success",
+			value: `[
+				{"type": "convert", "pdfa": "PDF/A-2b"},
+				{"type": "merge"},
+				{"type": "stamp", "text": "CONFIDENTIAL", "description": "points:48, rot:45, op:0.3", "pages": "1-"},
+				{"type": "encrypt", "ownerPassword": "foo"},
+				{"type": "upload", "url": "https://foo/{filename}", "headers": {"Authorization": "Bearer foo"}}
+			]`,
+			inputPaths:  []string{"/foo.pdf", "/foo.docx"},
+			maxSteps:    5,
+			expectError: false,
+			expectedSteps: []step{
+				{Type: stepConvert, PdfA: gotenberg.PdfA2b},
+				{Type: stepMerge},
+				{Type: stepStamp, Text: "CONFIDENTIAL", Description: "points:48, rot:45, op:0.3", Pages: "1-"},
+				{Type: stepEncrypt, OwnerPassword: "foo"},
+				{Type: stepUpload, Url: "https://foo/{filename}", Method: http.MethodPut, Headers: map[string]string{"Authorization": "Bearer foo"}},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := parseSteps(tc.value, tc.inputPaths, tc.maxSteps)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError && !errors.Is(err, ErrInvalidSteps) {
+				t.Fatalf("expected error %v but got: %v", ErrInvalidSteps, err)
+			}
+
+			if !reflect.DeepEqual(actual, tc.expectedSteps) {
+				t.Errorf("expected %+v but got %+v", tc.expectedSteps, actual)
+			}
+		})
+	}
+}
+
+func TestUploader_filter(t *testing.T) {
+	u := uploader{
+		allowList: regexp2.MustCompile("", 0),
+		denyList:  regexp2.MustCompile(`^https://internal`, 0),
+	}
+	deadline := time.Now().Add(time.Duration(5) * time.Second)
+
+	err := u.filter([]step{{Type: stepMerge}, {Type: stepUpload, Url: "https://foo"}}, deadline)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = u.filter([]step{{Type: stepUpload, Url: "https://internal/foo"}}, deadline)
+	if !errors.Is(err, gotenberg.ErrFiltered) {
+		t.Fatalf("expected error %v but got: %v", gotenberg.ErrFiltered, err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	pdfcpuConfig.ConfigPath = "disable"
+
+	newContext := func(filenames ...string) (*api.ContextMock, []string) {
+		dirPath := t.TempDir()
+		paths := make([]string, len(filenames))
+
+		for i, filename := range filenames {
+			paths[i] = filepath.Join(dirPath, filename)
+			copyFile(t, samplePath, paths[i])
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetLogger(zap.NewNop())
+		ctx.Context.Context = context.Background()
+
+		return ctx, paths
+	}
+
+	chromiumApi := &chromium.ApiMock{
+		PdfMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options chromium.PdfOptions) error {
+			if !strings.HasPrefix(url, "file://") {
+				return errors.New("expected a file URL")
+			}
+
+			copyFile(t, samplePath, outputPath)
+
+			return nil
+		},
+	}
+
+	libreOffice := &libreofficeapi.ApiMock{
+		PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+			copyFile(t, samplePath, outputPath)
+
+			return nil
+		},
+	}
+
+	engine := &gotenberg.PdfEngineMock{
+		MergeMock: func(ctx context.Context, logger *zap.Logger, inputPaths []string, outputPath string) error {
+			copyFile(t, inputPaths[0], outputPath)
+
+			return nil
+		},
+		ConvertMock: func(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
+			copyFile(t, inputPath, outputPath)
+
+			return nil
+		},
+	}
+
+	var uploads []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+
+		if r.URL.Path == "/fail" || len(b) == 0 || r.Header.Get("Authorization") != "foo" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		uploads = append(uploads, r.Method+" "+r.URL.Path+" "+r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	upload := uploader{client: server.Client()}
+
+	t.Run("convert", func(t *testing.T) {
+		ctx, paths := newContext("a.pdf", "b.docx", "c.html")
+
+		outputPaths, err := run(ctx.Context, chromiumApi, libreOffice, engine, upload, []step{{Type: stepConvert}}, paths)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		var actual []string
+		for _, outputPath := range outputPaths {
+			actual = append(actual, filepath.Base(outputPath))
+		}
+
+		expect := []string{"a.pdf", "b.docx.pdf", "c.html.pdf"}
+		if !reflect.DeepEqual(actual, expect) {
+			t.Errorf("expected %+v but got %+v", expect, actual)
+		}
+
+		outputPaths, err = run(ctx.Context, chromiumApi, libreOffice, engine, upload, []step{{Type: stepConvert, PdfA: gotenberg.PdfA2b}}, paths[:1])
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if outputPaths[0] == paths[0] || filepath.Base(outputPaths[0]) != "a.pdf" {
+			t.Errorf("expected a new 'a.pdf' file but got '%s'", outputPaths[0])
+		}
+	})
+
+	t.Run("merge, stamp, encrypt and upload", func(t *testing.T) {
+		ctx, paths := newContext("a.pdf", "b.pdf")
+
+		outputPaths, err := run(ctx.Context, chromiumApi, libreOffice, engine, upload, []step{
+			{Type: stepMerge},
+			{Type: stepStamp, Text: "CONFIDENTIAL", Description: "points:48, rot:45, op:0.3"},
+			{Type: stepEncrypt, UserPassword: "foo", OwnerPassword: "bar"},
+			{Type: stepUpload, Url: server.URL + "/{filename}", Method: http.MethodPut, Headers: map[string]string{"Authorization": "foo"}},
+		}, paths)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(outputPaths) != 1 {
+			t.Fatalf("expected 1 output path but got %d", len(outputPaths))
+		}
+
+		conf := pdfcpuConfig.NewDefaultConfiguration()
+		conf.UserPW = "foo"
+		conf.OwnerPW = "bar"
+
+		f, err := os.Open(outputPaths[0])
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		defer func() {
+			_ = f.Close()
+		}()
+
+		hasWatermarks, err := pdfcpuAPI.HasWatermarks(f, conf)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if !hasWatermarks {
+			t.Error("expected a stamp but got none")
+		}
+
+		expect := []string{"PUT /" + filepath.Base(outputPaths[0]) + " application/pdf"}
+		if !reflect.DeepEqual(uploads, expect) {
+			t.Errorf("expected uploads %+v but got %+v", expect, uploads)
+		}
+	})
+
+	t.Run("merge keeps page labels", func(t *testing.T) {
+		ctx, paths := newContext("a.pdf", "b.pdf")
+
+		err := pdfengines.WritePageLabels(paths[0], []pdfengines.PageLabel{{Page: 1, Style: "r"}})
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		labelsEngine := &gotenberg.PdfEngineMock{
+			MergeMock: engine.MergeMock,
+			PageCountMock: func(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+				return 1, nil
+			},
+		}
+
+		outputPaths, err := run(ctx.Context, chromiumApi, libreOffice, labelsEngine, upload, []step{{Type: stepMerge}}, paths)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		labels, err := pdfengines.ReadPageLabels(outputPaths[0])
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		expect := []pdfengines.PageLabel{{Page: 1, Style: "r"}, {Page: 2, Style: "D"}}
+		if !reflect.DeepEqual(labels, expect) {
+			t.Errorf("expected page labels %+v but got %+v", expect, labels)
+		}
+	})
+
+	t.Run("ErrUploadFailed", func(t *testing.T) {
+		ctx, paths := newContext("a.pdf")
+
+		_, err := run(ctx.Context, chromiumApi, libreOffice, engine, upload, []step{
+			{Type: stepUpload, Url: server.URL + "/fail", Method: http.MethodPost, Headers: map[string]string{"Authorization": "foo"}},
+		}, paths)
+		if !errors.Is(err, ErrUploadFailed) {
+			t.Fatalf("expected error %v but got: %v", ErrUploadFailed, err)
+		}
+	})
+}
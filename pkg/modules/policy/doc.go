@@ -0,0 +1,5 @@
+// Package policy provides a module which restricts, per API key or tenant,
+// the routes and the extensions of the uploaded files, e.g., a tenant may
+// only convert DOCX and XLSX files with LibreOffice. The forbidden requests
+// are rejected with a 403 status.
+package policy
@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// routePath returns the path of the route, without the root path of the
+// API.
+func routePath(c echo.Context) string {
+	path := c.Path()
+
+	rootPath, ok := c.Get("rootPath").(string)
+	if ok {
+		path = strings.TrimPrefix(path, rootPath)
+	}
+
+	return "/" + strings.TrimPrefix(path, "/")
+}
+
+// policyMiddleware rejects the multipart requests which do not comply with
+// the policy of their key before any other multipart middleware, so that
+// they are rejected synchronously, even with a webhook.
+func policyMiddleware(mod *Policy) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryHighPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(e echo.Context) error {
+					ctx := e.Get("context").(*api.Context)
+					key := e.Request().Header.Get(mod.keyHeader)
+
+					err := check(mod, key, routePath(e), ctx.InputPaths())
+					if err != nil {
+						ctx.Log().Warn(fmt.Sprintf("policy of key '%s': %s", key, err))
+
+						return err
+					}
+
+					return next(e)
+				}
+			}
+		}(),
+	}
+}
+
+// check returns a 403 [api.SentinelHttpError] if the route or one of the
+// uploaded files are not allowed for the given key.
+func check(mod *Policy, key, route string, inputPaths []string) error {
+	rule, ok := mod.rule(key)
+	if !ok {
+		return api.WrapError(
+			fmt.Errorf("no policy for key '%s'", key),
+			api.NewSentinelHttpError(http.StatusForbidden, "No policy allows this key").WithCode(api.ErrorCodePolicyUnknownKey),
+		)
+	}
+
+	if len(rule.Routes) > 0 && !slices.ContainsFunc(rule.Routes, func(prefix string) bool {
+		return strings.HasPrefix(route, prefix)
+	}) {
+		return api.WrapError(
+			fmt.Errorf("route '%s' not allowed", route),
+			api.NewSentinelHttpError(
+				http.StatusForbidden,
+				fmt.Sprintf("The route '%s' is not allowed for this key", route),
+			).WithCode(api.ErrorCodePolicyRouteForbidden),
+		)
+	}
+
+	if len(rule.Extensions) == 0 {
+		return nil
+	}
+
+	var forbidden []string
+	for _, path := range inputPaths {
+		if !slices.Contains(rule.Extensions, strings.ToLower(filepath.Ext(path))) {
+			forbidden = append(forbidden, filepath.Base(path))
+		}
+	}
+
+	if len(forbidden) == 0 {
+		return nil
+	}
+
+	return api.WrapError(
+		fmt.Errorf("files '%s' not allowed", strings.Join(forbidden, "', '")),
+		api.NewSentinelHttpError(
+			http.StatusForbidden,
+			fmt.Sprintf("The file types of '%s' are not allowed for this key, expected one of %s", strings.Join(forbidden, "', '"), strings.Join(rule.Extensions, ", ")),
+		).WithCode(api.ErrorCodePolicyFileTypeForbidden),
+	)
+}
@@ -0,0 +1,147 @@
+package policy
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestPolicyMiddleware(t *testing.T) {
+	rules := map[string]Rule{
+		"foo": {Routes: []string{"/forms/libreoffice"}, Extensions: []string{".docx", ".xlsx"}},
+		"bar": {Routes: []string{"/forms/chromium/convert/html", "/forms/pdfengines"}},
+	}
+
+	for _, tc := range []struct {
+		scenario        string
+		denyUnknownKeys bool
+		defaultRule     *Rule
+		key             string
+		path            string
+		files           map[string]string
+		expectCode      string
+	}{
+		{
+			scenario: "allowed route and files",
+			key:      "foo",
+			path:     "/forms/libreoffice/convert",
+			files:    map[string]string{"foo.docx": "/tmp/foo.docx", "bar.XLSX": "/tmp/bar.XLSX"},
+		},
+		{
+			scenario:   "forbidden route",
+			key:        "foo",
+			path:       "/forms/chromium/convert/url",
+			expectCode: "POLICY_ROUTE_FORBIDDEN",
+		},
+		{
+			scenario:   "forbidden file type",
+			key:        "foo",
+			path:       "/forms/libreoffice/convert",
+			files:      map[string]string{"foo.docx": "/tmp/foo.docx", "bar.pptx": "/tmp/bar.pptx"},
+			expectCode: "POLICY_FILE_TYPE_FORBIDDEN",
+		},
+		{
+			scenario: "any file type",
+			key:      "bar",
+			path:     "/forms/pdfengines/merge",
+			files:    map[string]string{"foo.pdf": "/tmp/foo.pdf"},
+		},
+		{
+			scenario: "unknown key",
+			key:      "baz",
+			path:     "/forms/chromium/convert/url",
+		},
+		{
+			scenario:        "denied unknown key",
+			denyUnknownKeys: true,
+			path:            "/forms/chromium/convert/url",
+			expectCode:      "POLICY_UNKNOWN_KEY",
+		},
+		{
+			scenario:        "default policy",
+			denyUnknownKeys: true,
+			defaultRule:     &Rule{Routes: []string{"/forms/pdfengines"}},
+			key:             "baz",
+			path:            "/forms/chromium/convert/url",
+			expectCode:      "POLICY_ROUTE_FORBIDDEN",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Policy{
+				file:            "/policy.json",
+				keyHeader:       "Gotenberg-Usage-Key",
+				denyUnknownKeys: tc.denyUnknownKeys,
+				rules:           make(map[string]Rule),
+			}
+
+			for key, rule := range rules {
+				mod.rules[key] = rule
+			}
+
+			if tc.defaultRule != nil {
+				mod.rules[defaultKey] = *tc.defaultRule
+			}
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, nil)
+			if tc.key != "" {
+				req.Header.Set("Gotenberg-Usage-Key", tc.key)
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+			c.SetPath(tc.path)
+			c.Set("rootPath", "/")
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetFiles(tc.files)
+			ctx.SetLogger(zap.NewNop())
+			c.Set("context", ctx.Context)
+
+			called := false
+			err := policyMiddleware(mod).Handler(func(c echo.Context) error {
+				called = true
+				return nil
+			})(c)
+
+			if tc.expectCode == "" {
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				if !called {
+					t.Error("expected the next handler to be called")
+				}
+
+				return
+			}
+
+			if called {
+				t.Error("expected the next handler not to be called")
+			}
+
+			var httpErr api.HttpError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("expected an HTTP error but got: %v", err)
+			}
+
+			status, _ := httpErr.HttpError()
+			if status != http.StatusForbidden {
+				t.Errorf("expected %d status code but got %d", http.StatusForbidden, status)
+			}
+
+			var coder api.HttpErrorCoder
+			if !errors.As(err, &coder) {
+				t.Fatalf("expected an error code but got: %v", err)
+			}
+
+			if coder.HttpErrorCode() != tc.expectCode {
+				t.Errorf("expected code '%s' but got '%s'", tc.expectCode, coder.HttpErrorCode())
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Policy))
+}
+
+// defaultKey is the key of the policy of the requests whose key has no
+// policy of its own, including the requests without the key header.
+const defaultKey = "*"
+
+// Policy is a module which restricts, per API key or tenant, the routes and
+// the extensions of the uploaded files.
+type Policy struct {
+	file            string
+	keyHeader       string
+	denyUnknownKeys bool
+
+	rules map[string]Rule
+}
+
+// Rule is the policy of a key. An empty list allows everything.
+type Rule struct {
+	// Routes are the prefixes of the allowed routes, e.g.,
+	// "/forms/libreoffice".
+	Routes []string `json:"routes"`
+
+	// Extensions are the allowed extensions of the uploaded files, e.g.,
+	// ".docx".
+	Extensions []string `json:"extensions"`
+}
+
+// Descriptor returns a [Policy]'s module descriptor.
+func (mod *Policy) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "policy",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("policy", flag.ExitOnError)
+			fs.String("policy-file", "", "Set the path of a JSON file with the allowed routes and file extensions per API key or tenant, the '*' key applying to the other ones - e.g., {\"acme\":{\"routes\":[\"/forms/libreoffice\"],\"extensions\":[\".docx\",\".xlsx\"]}}")
+			fs.String("policy-key-header", "Gotenberg-Usage-Key", "Set the header which identifies the API key or tenant of a request")
+			fs.Bool("policy-deny-unknown-keys", false, "Reject the requests whose key has no policy, if there is no '*' policy")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Policy) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Policy) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.file = flags.MustString("policy-file")
+	mod.keyHeader = flags.MustString("policy-key-header")
+	mod.denyUnknownKeys = flags.MustBool("policy-deny-unknown-keys")
+
+	rules, err := loadRules(mod.file)
+	if err != nil {
+		return fmt.Errorf("load policy: %w", err)
+	}
+
+	mod.rules = rules
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Policy) Validate() error {
+	if mod.file == "" {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.keyHeader == "" {
+		err = multierr.Append(err,
+			errors.New("key header must not be empty"),
+		)
+	}
+
+	for key, rule := range mod.rules {
+		for _, route := range rule.Routes {
+			if !strings.HasPrefix(route, "/") {
+				err = multierr.Append(err,
+					fmt.Errorf("route '%s' of key '%s' must start with a '/'", route, key),
+				)
+			}
+		}
+
+		for _, extension := range rule.Extensions {
+			if !strings.HasPrefix(extension, ".") {
+				err = multierr.Append(err,
+					fmt.Errorf("extension '%s' of key '%s' must start with a '.'", extension, key),
+				)
+			}
+		}
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *Policy) Middlewares() ([]api.Middleware, error) {
+	if mod.file == "" {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		policyMiddleware(mod),
+	}, nil
+}
+
+// rule returns the policy of a key. It returns false if there is none and
+// the unknown keys are denied.
+func (mod *Policy) rule(key string) (Rule, bool) {
+	rule, ok := mod.rules[key]
+	if ok {
+		return rule, true
+	}
+
+	rule, ok = mod.rules[defaultKey]
+	if ok {
+		return rule, true
+	}
+
+	return Rule{}, !mod.denyUnknownKeys
+}
+
+// loadRules reads the policy file, if any. The extensions are lower-cased.
+func loadRules(path string) (map[string]Rule, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	var rules map[string]Rule
+
+	err = json.Unmarshal(b, &rules)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal policy file: %w", err)
+	}
+
+	for key, rule := range rules {
+		for i, extension := range rule.Extensions {
+			rule.Extensions[i] = strings.ToLower(extension)
+		}
+
+		rules[key] = rule
+	}
+
+	return rules, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Policy)(nil)
+	_ gotenberg.Provisioner  = (*Policy)(nil)
+	_ gotenberg.Validator    = (*Policy)(nil)
+	_ api.MiddlewareProvider = (*Policy)(nil)
+)
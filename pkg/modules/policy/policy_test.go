@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestPolicy_Descriptor(t *testing.T) {
+	descriptor := new(Policy).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Policy))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestPolicy_Provision(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		content     string
+		expectRules map[string]Rule
+		expectError bool
+	}{
+		{
+			scenario:    "no policy file",
+			expectRules: nil,
+		},
+		{
+			scenario:    "invalid policy file",
+			content:     "foo",
+			expectError: true,
+		},
+		{
+			scenario: "policy file",
+			content:  `{"foo":{"routes":["/forms/libreoffice"],"extensions":[".DOCX",".xlsx"]},"*":{"routes":["/forms/pdfengines"]}}`,
+			expectRules: map[string]Rule{
+				"foo": {Routes: []string{"/forms/libreoffice"}, Extensions: []string{".docx", ".xlsx"}},
+				"*":   {Routes: []string{"/forms/pdfengines"}},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			fs := new(Policy).Descriptor().FlagSet
+
+			if tc.content != "" {
+				path := filepath.Join(t.TempDir(), "policy.json")
+
+				err := os.WriteFile(path, []byte(tc.content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				err = fs.Parse([]string{"--policy-file", path})
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			mod := new(Policy)
+			err := mod.Provision(gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil))
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !reflect.DeepEqual(mod.rules, tc.expectRules) {
+				t.Errorf("expected %+v but got %+v", tc.expectRules, mod.rules)
+			}
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Policy
+		expectError bool
+	}{
+		{
+			scenario: "no policy file",
+			mod:      &Policy{},
+		},
+		{
+			scenario:    "empty key header",
+			mod:         &Policy{file: "/policy.json"},
+			expectError: true,
+		},
+		{
+			scenario: "invalid route",
+			mod: &Policy{file: "/policy.json", keyHeader: "foo", rules: map[string]Rule{
+				"foo": {Routes: []string{"forms/libreoffice"}},
+			}},
+			expectError: true,
+		},
+		{
+			scenario: "invalid extension",
+			mod: &Policy{file: "/policy.json", keyHeader: "foo", rules: map[string]Rule{
+				"foo": {Extensions: []string{"docx"}},
+			}},
+			expectError: true,
+		},
+		{
+			scenario: "validate success",
+			mod: &Policy{file: "/policy.json", keyHeader: "foo", rules: map[string]Rule{
+				"foo": {Routes: []string{"/forms/libreoffice"}, Extensions: []string{".docx"}},
+			}},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestPolicy_Middlewares(t *testing.T) {
+	middlewares, err := new(Policy).Middlewares()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(middlewares) != 0 {
+		t.Errorf("expected no middleware without policy file but got %d", len(middlewares))
+	}
+
+	middlewares, err = (&Policy{file: "/policy.json"}).Middlewares()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(middlewares) != 1 {
+		t.Errorf("expected 1 middleware but got %d", len(middlewares))
+	}
+}
@@ -105,10 +105,14 @@ func (mod *Prometheus) Validate() error {
 			return errors.New("metric name cannot be empty")
 		}
 
-		if metric.Read == nil {
+		if metric.Label == "" && metric.Read == nil {
 			return fmt.Errorf("metric '%s' has nil read method", metric.Name)
 		}
 
+		if metric.Label != "" && metric.ReadLabeled == nil {
+			return fmt.Errorf("metric '%s' has nil read labeled method", metric.Name)
+		}
+
 		if _, ok := metricsMap[metric.Name]; ok {
 			return fmt.Errorf("metric '%s' is already registered", metric.Name)
 		}
@@ -127,6 +131,25 @@ func (mod *Prometheus) Start() error {
 	}
 
 	for _, metric := range mod.metrics {
+		if metric.Label != "" {
+			// Labeled metrics are read on each scrape, as their label values
+			// are not known in advance.
+			mod.registry.MustRegister(newLabeledCollector(mod.namespace, metric))
+			continue
+		}
+
+		if metric.Counter {
+			mod.registry.MustRegister(prometheus.NewCounterFunc(
+				prometheus.CounterOpts{
+					Namespace: mod.namespace,
+					Name:      metric.Name,
+					Help:      metric.Description,
+				},
+				metric.Read,
+			))
+			continue
+		}
+
 		gauge := prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Namespace: mod.namespace,
@@ -180,6 +203,44 @@ func (mod *Prometheus) Routes() ([]api.Route, error) {
 	}, nil
 }
 
+// labeledCollector is a [prometheus.Collector] for a [gotenberg.Metric] with
+// a label.
+type labeledCollector struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	read      func() map[string]float64
+}
+
+func newLabeledCollector(namespace string, metric gotenberg.Metric) labeledCollector {
+	valueType := prometheus.GaugeValue
+	if metric.Counter {
+		valueType = prometheus.CounterValue
+	}
+
+	return labeledCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", metric.Name),
+			metric.Description,
+			[]string{metric.Label},
+			nil,
+		),
+		valueType: valueType,
+		read:      metric.ReadLabeled,
+	}
+}
+
+// Describe sends the descriptor of the metric.
+func (c labeledCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect sends a value for each label value.
+func (c labeledCollector) Collect(ch chan<- prometheus.Metric) {
+	for label, value := range c.read() {
+		ch <- prometheus.MustNewConstMetric(c.desc, c.valueType, value, label)
+	}
+}
+
 // Interface guards.
 var (
 	_ gotenberg.Module      = (*Prometheus)(nil)
@@ -187,4 +248,5 @@ var (
 	_ gotenberg.Validator   = (*Prometheus)(nil)
 	_ gotenberg.App         = (*Prometheus)(nil)
 	_ api.Router            = (*Prometheus)(nil)
+	_ prometheus.Collector  = (*labeledCollector)(nil)
 )
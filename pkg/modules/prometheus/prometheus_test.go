@@ -204,6 +204,19 @@ func TestPrometheus_Validate(t *testing.T) {
 			disableCollect: false,
 			expectError:    true,
 		},
+		{
+			scenario:  "nil read labeled metric method",
+			namespace: "foo",
+			metrics: []gotenberg.Metric{
+				{
+					Name:        "foo",
+					Label:       "bar",
+					ReadLabeled: nil,
+				},
+			},
+			disableCollect: false,
+			expectError:    true,
+		},
 		{
 			scenario:  "already registered metric",
 			namespace: "foo",
@@ -283,6 +296,21 @@ func TestPrometheus_Start(t *testing.T) {
 						return 0
 					},
 				},
+				{
+					Name:    "bar",
+					Counter: true,
+					Read: func() float64 {
+						return 0
+					},
+				},
+				{
+					Name:    "baz",
+					Label:   "key",
+					Counter: true,
+					ReadLabeled: func() map[string]float64 {
+						return map[string]float64{"foo": 1, "bar": 2}
+					},
+				},
 			},
 		},
 	} {
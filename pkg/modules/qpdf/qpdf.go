@@ -72,9 +72,60 @@ func (engine *QPdf) Merge(ctx context.Context, logger *zap.Logger, inputPaths []
 	return fmt.Errorf("merge PDFs with QPDF: %w", err)
 }
 
-// Convert is not available in this implementation.
+// Append appends PDFs to an existing PDF, in place.
+func (engine *QPdf) Append(ctx context.Context, logger *zap.Logger, outputPath string, inputPaths []string) error {
+	var args []string
+	args = append(args, outputPath, "--replace-input")
+	args = append(args, "--pages", ".")
+	args = append(args, inputPaths...)
+	args = append(args, "--")
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("append PDFs with QPDF: %w", err)
+}
+
+// Convert sets the version of the given PDF. Other PDF formats are not
+// available in this implementation, and it returns a
+// [gotenberg.ErrPdfFormatNotSupported] error if requested.
 func (engine *QPdf) Convert(ctx context.Context, logger *zap.Logger, formats gotenberg.PdfFormats, inputPath, outputPath string) error {
-	return fmt.Errorf("convert PDF to '%+v' with QPDF: %w", formats, gotenberg.ErrPdfEngineMethodNotSupported)
+	if formats.PdfVersion == "" || formats.PdfA != "" || formats.PdfUa {
+		return fmt.Errorf("convert PDF to '%+v' with QPDF: %w", formats, gotenberg.ErrPdfFormatNotSupported)
+	}
+
+	args := []string{fmt.Sprintf("--force-version=%s", formats.PdfVersion)}
+
+	// Object streams came with PDF 1.5.
+	if formats.PdfVersion == "1.4" {
+		args = append(args, "--object-streams=disable")
+	}
+
+	args = append(args, inputPath, outputPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, engine.binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	_, err = cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("convert PDF to '%+v' with QPDF: %w", formats, err)
+}
+
+// PageCount is not available in this implementation.
+func (engine *QPdf) PageCount(ctx context.Context, logger *zap.Logger, inputPath string) (int, error) {
+	return 0, fmt.Errorf("count pages with QPDF: %w", gotenberg.ErrPdfEngineMethodNotSupported)
 }
 
 var (
@@ -82,4 +133,5 @@ var (
 	_ gotenberg.Provisioner = (*QPdf)(nil)
 	_ gotenberg.Validator   = (*QPdf)(nil)
 	_ gotenberg.PdfEngine   = (*QPdf)(nil)
+	_ gotenberg.PdfAppender = (*QPdf)(nil)
 )
@@ -140,11 +140,181 @@ func TestQPdf_Merge(t *testing.T) {
 	}
 }
 
+func TestQPdf_Append(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		ctx         context.Context
+		inputPaths  []string
+		expectError bool
+	}{
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			expectError: true,
+		},
+		{
+			scenario: "invalid input path",
+			ctx:      context.TODO(),
+			inputPaths: []string{
+				"foo",
+			},
+			expectError: true,
+		},
+		{
+			scenario: "append success",
+			ctx:      context.TODO(),
+			inputPaths: []string{
+				"/tests/test/testdata/pdfengines/sample2.pdf",
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(QPdf)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			outputPath := outputDir + "/foo.pdf"
+
+			b, err := os.ReadFile("/tests/test/testdata/pdfengines/sample1.pdf")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = os.WriteFile(outputPath, b, 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = engine.Append(tc.ctx, zap.NewNop(), outputPath, tc.inputPaths)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			stat, err := os.Stat(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if stat.Size() <= int64(len(b)) {
+				t.Errorf("expected the PDF to grow from %d bytes but got %d", len(b), stat.Size())
+			}
+		})
+	}
+}
+
 func TestQPdf_Convert(t *testing.T) {
-	engine := new(QPdf)
-	err := engine.Convert(context.TODO(), zap.NewNop(), gotenberg.PdfFormats{}, "", "")
+	for _, tc := range []struct {
+		scenario      string
+		ctx           context.Context
+		formats       gotenberg.PdfFormats
+		inputPath     string
+		expectError   bool
+		expectedError error
+	}{
+		{
+			scenario:      "no PDF version",
+			ctx:           context.TODO(),
+			formats:       gotenberg.PdfFormats{},
+			expectError:   true,
+			expectedError: gotenberg.ErrPdfFormatNotSupported,
+		},
+		{
+			scenario:      "PDF/A",
+			ctx:           context.TODO(),
+			formats:       gotenberg.PdfFormats{PdfA: gotenberg.PdfA1b},
+			expectError:   true,
+			expectedError: gotenberg.ErrPdfFormatNotSupported,
+		},
+		{
+			scenario:      "PDF/UA",
+			ctx:           context.TODO(),
+			formats:       gotenberg.PdfFormats{PdfUa: true, PdfVersion: "1.7"},
+			expectError:   true,
+			expectedError: gotenberg.ErrPdfFormatNotSupported,
+		},
+		{
+			scenario:    "invalid context",
+			ctx:         nil,
+			formats:     gotenberg.PdfFormats{PdfVersion: "1.4"},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid input path",
+			ctx:         context.TODO(),
+			formats:     gotenberg.PdfFormats{PdfVersion: "1.4"},
+			inputPath:   "foo",
+			expectError: true,
+		},
+		{
+			scenario:  "PDF 1.4 success",
+			ctx:       context.TODO(),
+			formats:   gotenberg.PdfFormats{PdfVersion: "1.4"},
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+		},
+		{
+			scenario:  "PDF 2.0 success",
+			ctx:       context.TODO(),
+			formats:   gotenberg.PdfFormats{PdfVersion: "2.0"},
+			inputPath: "/tests/test/testdata/pdfengines/sample1.pdf",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			engine := new(QPdf)
+			err := engine.Provision(nil)
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
+
+			fs := gotenberg.NewFileSystem()
+			outputDir, err := fs.MkdirAll()
+			if err != nil {
+				t.Fatalf("expected error but got: %v", err)
+			}
 
-	if !errors.Is(err, gotenberg.ErrPdfEngineMethodNotSupported) {
-		t.Errorf("expected error %v, but got: %v", gotenberg.ErrPdfEngineMethodNotSupported, err)
+			defer func() {
+				err = os.RemoveAll(fs.WorkingDirPath())
+				if err != nil {
+					t.Fatalf("expected no error while cleaning up but got: %v", err)
+				}
+			}()
+
+			err = engine.Convert(tc.ctx, zap.NewNop(), tc.formats, tc.inputPath, outputDir+"/foo.pdf")
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+		})
 	}
 }
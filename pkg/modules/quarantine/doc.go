@@ -0,0 +1,5 @@
+// Package quarantine provides a module which assesses the uploaded files with
+// heuristics, e.g., macros, embedded executables or an abnormal number of
+// objects. It reports the risk with the output metadata, and may isolate the
+// conversions of the suspicious files in the quarantine LibreOffice workers.
+package quarantine
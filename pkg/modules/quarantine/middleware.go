@@ -0,0 +1,40 @@
+package quarantine
+
+import (
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// quarantineMiddleware assesses the uploaded files before the route handler,
+// so that the conversions of a quarantined request go to the quarantine
+// workers. It runs after the ClamAV and policy middlewares, which reject the
+// requests instead.
+func quarantineMiddleware(mod *Quarantine) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.HighPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(e echo.Context) error {
+					ctx := e.Get("context").(*api.Context)
+
+					report, err := mod.assess(ctx.InputPaths())
+					if err != nil {
+						return fmt.Errorf("assess input files: %w", err)
+					}
+
+					if report.Score >= mod.scoreThreshold {
+						ctx.Log().Warn(fmt.Sprintf("suspicious input files (score %d, quarantined: %t): %+v", report.Score, report.Quarantined, report.Findings))
+					}
+
+					ctx.SetRiskReport(report)
+
+					return next(e)
+				}
+			}
+		}(),
+	}
+}
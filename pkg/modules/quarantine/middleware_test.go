@@ -0,0 +1,96 @@
+package quarantine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestQuarantineMiddleware(t *testing.T) {
+	dirPath := t.TempDir()
+	executablePath := filepath.Join(dirPath, "foo.docx")
+
+	err := os.WriteFile(executablePath, []byte("MZ\x90\x00"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario          string
+		files             map[string]string
+		expectQuarantined bool
+		expectError       bool
+	}{
+		{
+			scenario:    "non-existing file",
+			files:       map[string]string{"foo.docx": "/foo.docx"},
+			expectError: true,
+		},
+		{
+			scenario: "no file",
+		},
+		{
+			scenario:          "suspicious file",
+			files:             map[string]string{"foo.docx": executablePath},
+			expectQuarantined: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Quarantine{mode: modeIsolate, scoreThreshold: 50, scanner: scanner{maxObjects: 10}}
+
+			req := httptest.NewRequest(http.MethodPost, "/forms/libreoffice/convert", nil)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetFiles(tc.files)
+			ctx.SetLogger(zap.NewNop())
+			c.Set("context", ctx.Context)
+
+			called := false
+			err := quarantineMiddleware(mod).Handler(func(c echo.Context) error {
+				called = true
+				return nil
+			})(c)
+
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				if called {
+					t.Error("expected the next handler not to be called")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !called {
+				t.Error("expected the next handler to be called")
+			}
+
+			report := ctx.RiskReport()
+			if report == nil {
+				t.Fatal("expected a risk report but got none")
+			}
+
+			if report.Quarantined != tc.expectQuarantined {
+				t.Errorf("expected quarantined %t but got %t", tc.expectQuarantined, report.Quarantined)
+			}
+
+			if api.Quarantined(ctx.Context) != tc.expectQuarantined {
+				t.Errorf("expected the context to be quarantined %t", tc.expectQuarantined)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package quarantine
+
+import (
+	"errors"
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Quarantine))
+}
+
+const (
+	// modeReport reports the risk with the output metadata.
+	modeReport = "report"
+
+	// modeIsolate also isolates the conversions of the suspicious files.
+	modeIsolate = "isolate"
+)
+
+// Quarantine is a module which assesses the uploaded files of the multipart
+// requests with heuristics.
+type Quarantine struct {
+	mode           string
+	scoreThreshold int
+	scanner        scanner
+}
+
+// Descriptor returns a [Quarantine]'s module descriptor.
+func (mod *Quarantine) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "quarantine",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("quarantine", flag.ExitOnError)
+			fs.String("quarantine-mode", "", fmt.Sprintf("Set what happens with the risk of the uploaded files - either '%s' it with the output metadata, or also '%s' the conversions of the suspicious files in the quarantine LibreOffice workers. Empty disables this feature", modeReport, modeIsolate))
+			fs.Int("quarantine-score-threshold", 50, "Set the risk score from which the uploaded files are suspicious")
+			fs.Int("quarantine-max-objects", 100000, "Set the number of PDF objects or archive entries above which a file is suspicious")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Quarantine) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Quarantine) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.mode = flags.MustString("quarantine-mode")
+	mod.scoreThreshold = flags.MustInt("quarantine-score-threshold")
+	mod.scanner = scanner{
+		maxObjects: flags.MustInt("quarantine-max-objects"),
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Quarantine) Validate() error {
+	if mod.mode == "" {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.mode != modeReport && mod.mode != modeIsolate {
+		err = multierr.Append(err,
+			fmt.Errorf("mode must be either '%s' or '%s'", modeReport, modeIsolate),
+		)
+	}
+
+	if mod.scoreThreshold < 1 {
+		err = multierr.Append(err,
+			errors.New("score threshold must be more than 0"),
+		)
+	}
+
+	if mod.scanner.maxObjects < 1 {
+		err = multierr.Append(err,
+			errors.New("maximum number of objects must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *Quarantine) Middlewares() ([]api.Middleware, error) {
+	if mod.mode == "" {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		quarantineMiddleware(mod),
+	}, nil
+}
+
+// assess returns the risk report of the input files.
+func (mod *Quarantine) assess(inputPaths []string) (api.RiskReport, error) {
+	var report api.RiskReport
+
+	for _, path := range inputPaths {
+		findings, err := mod.scanner.scan(path)
+		if err != nil {
+			return api.RiskReport{}, fmt.Errorf("scan '%s': %w", path, err)
+		}
+
+		for _, finding := range findings {
+			report.Score += finding.Score
+		}
+
+		report.Findings = append(report.Findings, findings...)
+	}
+
+	report.Quarantined = mod.mode == modeIsolate && report.Score >= mod.scoreThreshold
+
+	return report, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module       = (*Quarantine)(nil)
+	_ gotenberg.Provisioner  = (*Quarantine)(nil)
+	_ gotenberg.Validator    = (*Quarantine)(nil)
+	_ api.MiddlewareProvider = (*Quarantine)(nil)
+)
@@ -0,0 +1,189 @@
+package quarantine
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestQuarantine_Descriptor(t *testing.T) {
+	descriptor := new(Quarantine).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Quarantine))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestQuarantine_Provision(t *testing.T) {
+	fs := new(Quarantine).Descriptor().FlagSet
+
+	err := fs.Parse([]string{"--quarantine-mode=isolate", "--quarantine-score-threshold=60", "--quarantine-max-objects=10"})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	mod := new(Quarantine)
+
+	err = mod.Provision(gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, nil))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.mode != modeIsolate {
+		t.Errorf("expected mode '%s' but got '%s'", modeIsolate, mod.mode)
+	}
+
+	if mod.scoreThreshold != 60 {
+		t.Errorf("expected score threshold 60 but got %d", mod.scoreThreshold)
+	}
+
+	if mod.scanner.maxObjects != 10 {
+		t.Errorf("expected maximum number of objects 10 but got %d", mod.scanner.maxObjects)
+	}
+}
+
+func TestQuarantine_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Quarantine
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			mod:      &Quarantine{},
+		},
+		{
+			scenario:    "invalid mode",
+			mod:         &Quarantine{mode: "foo", scoreThreshold: 50, scanner: scanner{maxObjects: 10}},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid score threshold",
+			mod:         &Quarantine{mode: modeReport, scoreThreshold: 0, scanner: scanner{maxObjects: 10}},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid maximum number of objects",
+			mod:         &Quarantine{mode: modeReport, scoreThreshold: 50, scanner: scanner{maxObjects: 0}},
+			expectError: true,
+		},
+		{
+			scenario: "validate success",
+			mod:      &Quarantine{mode: modeIsolate, scoreThreshold: 50, scanner: scanner{maxObjects: 10}},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestQuarantine_Middlewares(t *testing.T) {
+	middlewares, err := new(Quarantine).Middlewares()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(middlewares) != 0 {
+		t.Errorf("expected no middleware without mode but got %d", len(middlewares))
+	}
+
+	middlewares, err = (&Quarantine{mode: modeReport}).Middlewares()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(middlewares) != 1 {
+		t.Errorf("expected 1 middleware but got %d", len(middlewares))
+	}
+}
+
+func TestQuarantine_Assess(t *testing.T) {
+	dirPath := t.TempDir()
+	macroPath := filepath.Join(dirPath, "foo.doc")
+	cleanPath := filepath.Join(dirPath, "bar.txt")
+
+	err := os.WriteFile(macroPath, append(append([]byte(nil), ole2Magic...), utf16le("_VBA_PROJECT")...), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = os.WriteFile(cleanPath, []byte("bar"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario          string
+		mod               *Quarantine
+		inputPaths        []string
+		expectScore       int
+		expectQuarantined bool
+		expectError       bool
+	}{
+		{
+			scenario:    "non-existing file",
+			mod:         &Quarantine{mode: modeIsolate, scoreThreshold: 50, scanner: scanner{maxObjects: 10}},
+			inputPaths:  []string{"/foo"},
+			expectError: true,
+		},
+		{
+			scenario:   "clean files",
+			mod:        &Quarantine{mode: modeIsolate, scoreThreshold: 50, scanner: scanner{maxObjects: 10}},
+			inputPaths: []string{cleanPath},
+		},
+		{
+			scenario:    "report only",
+			mod:         &Quarantine{mode: modeReport, scoreThreshold: 50, scanner: scanner{maxObjects: 10}},
+			inputPaths:  []string{macroPath, cleanPath},
+			expectScore: scores[kindMacro],
+		},
+		{
+			scenario:    "below threshold",
+			mod:         &Quarantine{mode: modeIsolate, scoreThreshold: 70, scanner: scanner{maxObjects: 10}},
+			inputPaths:  []string{macroPath, cleanPath},
+			expectScore: scores[kindMacro],
+		},
+		{
+			scenario:          "quarantined",
+			mod:               &Quarantine{mode: modeIsolate, scoreThreshold: 50, scanner: scanner{maxObjects: 10}},
+			inputPaths:        []string{macroPath, cleanPath},
+			expectScore:       scores[kindMacro],
+			expectQuarantined: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			report, err := tc.mod.assess(tc.inputPaths)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if report.Score != tc.expectScore {
+				t.Errorf("expected score %d but got %d", tc.expectScore, report.Score)
+			}
+
+			if report.Quarantined != tc.expectQuarantined {
+				t.Errorf("expected quarantined %t but got %t", tc.expectQuarantined, report.Quarantined)
+			}
+		})
+	}
+}
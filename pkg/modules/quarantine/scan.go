@@ -0,0 +1,213 @@
+package quarantine
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// maxScanBytes is the number of bytes of a file the heuristics look into.
+const maxScanBytes = 32 << 20
+
+// maxCompressionRatio is the ratio of uncompressed to compressed sizes of a
+// ZIP archive above which it looks like a decompression bomb.
+const maxCompressionRatio = 100
+
+// The kinds of findings and their scores.
+const (
+	kindExecutable      = "executable"
+	kindMacro           = "macro"
+	kindJavaScript      = "javascript"
+	kindLaunchAction    = "launch_action"
+	kindEmbeddedObject  = "embedded_object"
+	kindObjectCount     = "object_count"
+	kindCompressionBomb = "compression_ratio"
+)
+
+var scores = map[string]int{
+	kindExecutable:      100,
+	kindLaunchAction:    80,
+	kindMacro:           60,
+	kindObjectCount:     50,
+	kindCompressionBomb: 50,
+	kindJavaScript:      40,
+	kindEmbeddedObject:  30,
+}
+
+var (
+	pdfMagic  = []byte("%PDF-")
+	zipMagic  = []byte("PK\x03\x04")
+	ole2Magic = []byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")
+	rtfMagic  = []byte(`{\rtf`)
+
+	executableMagics = [][]byte{
+		[]byte("MZ"),
+		[]byte("\x7fELF"),
+		[]byte("\xfe\xed\xfa\xce"),
+		[]byte("\xfe\xed\xfa\xcf"),
+		[]byte("\xce\xfa\xed\xfe"),
+		[]byte("\xcf\xfa\xed\xfe"),
+	}
+
+	executableExtensions = []string{".exe", ".dll", ".scr", ".com", ".bat", ".cmd", ".ps1", ".vbs", ".js", ".jar", ".msi", ".sh"}
+
+	pdfObjectRegexp = regexp.MustCompile(`\d+\s+\d+\s+obj\b`)
+)
+
+// scanner applies the heuristics to the input files.
+type scanner struct {
+	maxObjects int
+}
+
+// scan returns the findings about a file.
+func (s scanner) scan(path string) ([]api.RiskFinding, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file: %w", err)
+	}
+	defer f.Close()
+
+	b, err := io.ReadAll(io.LimitReader(f, maxScanBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	filename := filepath.Base(path)
+
+	var findings []api.RiskFinding
+	add := func(kind, detail string) {
+		findings = append(findings, api.RiskFinding{
+			Filename: filename,
+			Kind:     kind,
+			Detail:   detail,
+			Score:    scores[kind],
+		})
+	}
+
+	switch {
+	case isExecutable(b):
+		add(kindExecutable, "executable file")
+	case bytes.HasPrefix(b, pdfMagic):
+		s.scanPdf(b, add)
+	case bytes.HasPrefix(b, zipMagic):
+		err = s.scanZip(path, add)
+		if err != nil {
+			// Not a valid archive, LibreOffice will tell.
+			return findings, nil
+		}
+	case bytes.HasPrefix(b, ole2Magic):
+		scanOle2(b, add)
+	case bytes.HasPrefix(b, rtfMagic):
+		if bytes.Contains(b, []byte(`\objdata`)) {
+			add(kindEmbeddedObject, "RTF embedded object")
+		}
+	}
+
+	return findings, nil
+}
+
+// isExecutable tells if the content starts like a PE, ELF or Mach-O
+// executable.
+func isExecutable(b []byte) bool {
+	for _, magic := range executableMagics {
+		if bytes.HasPrefix(b, magic) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scanPdf looks for active content and an abnormal number of objects in a
+// PDF.
+func (s scanner) scanPdf(b []byte, add func(kind, detail string)) {
+	if bytes.Contains(b, []byte("/JavaScript")) || bytes.Contains(b, []byte("/JS")) {
+		add(kindJavaScript, "PDF JavaScript")
+	}
+
+	if bytes.Contains(b, []byte("/Launch")) {
+		add(kindLaunchAction, "PDF launch action")
+	}
+
+	if bytes.Contains(b, []byte("/EmbeddedFile")) {
+		add(kindEmbeddedObject, "PDF embedded file")
+	}
+
+	count := len(pdfObjectRegexp.FindAllIndex(b, s.maxObjects+1))
+	if count > s.maxObjects {
+		add(kindObjectCount, fmt.Sprintf("more than %d PDF objects", s.maxObjects))
+	}
+}
+
+// scanZip looks for macros, executables, embedded objects and abnormal
+// entries in a ZIP archive, e.g., an Office Open XML document.
+func (s scanner) scanZip(path string, add func(kind, detail string)) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer r.Close()
+
+	if len(r.File) > s.maxObjects {
+		add(kindObjectCount, fmt.Sprintf("more than %d archive entries", s.maxObjects))
+	}
+
+	var compressed, uncompressed uint64
+
+	for _, f := range r.File {
+		compressed += f.CompressedSize64
+		uncompressed += f.UncompressedSize64
+
+		name := strings.ToLower(f.Name)
+
+		switch {
+		case strings.HasSuffix(name, "vbaproject.bin"):
+			add(kindMacro, f.Name)
+		case strings.Contains(name, "oleobject"):
+			add(kindEmbeddedObject, f.Name)
+		default:
+			for _, extension := range executableExtensions {
+				if strings.HasSuffix(name, extension) {
+					add(kindExecutable, f.Name)
+					break
+				}
+			}
+		}
+	}
+
+	if compressed > 0 && uncompressed/compressed > maxCompressionRatio {
+		add(kindCompressionBomb, fmt.Sprintf("compression ratio of %d", uncompressed/compressed))
+	}
+
+	return nil
+}
+
+// scanOle2 looks for the streams of macros and embedded objects in an OLE2
+// compound file, e.g., a legacy Word document. The names of the streams are
+// UTF-16LE encoded.
+func scanOle2(b []byte, add func(kind, detail string)) {
+	if bytes.Contains(b, utf16le("_VBA_PROJECT")) || bytes.Contains(b, utf16le("Macros")) {
+		add(kindMacro, "VBA project")
+	}
+
+	if bytes.Contains(b, utf16le("Ole10Native")) {
+		add(kindEmbeddedObject, "OLE package")
+	}
+}
+
+// utf16le encodes an ASCII string in UTF-16LE.
+func utf16le(s string) []byte {
+	b := make([]byte, 0, len(s)*2)
+	for i := 0; i < len(s); i++ {
+		b = append(b, s[i], 0)
+	}
+
+	return b
+}
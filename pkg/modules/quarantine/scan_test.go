@@ -0,0 +1,164 @@
+package quarantine
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// zipFile returns a ZIP archive with the given entries.
+func zipFile(t *testing.T, entries map[string]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+
+	for name, content := range entries {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		_, err = f.Write([]byte(content))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	err := w.Close()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestScanner_Scan(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		filename    string
+		content     []byte
+		expectKinds []string
+	}{
+		{
+			scenario: "clean text",
+			filename: "foo.txt",
+			content:  []byte("foo"),
+		},
+		{
+			scenario:    "executable",
+			filename:    "foo.docx",
+			content:     []byte("MZ\x90\x00"),
+			expectKinds: []string{kindExecutable},
+		},
+		{
+			scenario: "clean PDF",
+			filename: "foo.pdf",
+			content:  []byte("%PDF-1.7\n1 0 obj\n<<>>\nendobj\n"),
+		},
+		{
+			scenario:    "active PDF",
+			filename:    "foo.pdf",
+			content:     []byte("%PDF-1.7\n1 0 obj\n<< /OpenAction << /S /JavaScript /JS (app.alert(1)) >> /Launch /EmbeddedFile >>\nendobj\n"),
+			expectKinds: []string{kindJavaScript, kindLaunchAction, kindEmbeddedObject},
+		},
+		{
+			scenario:    "PDF with too many objects",
+			filename:    "foo.pdf",
+			content:     []byte("%PDF-1.7\n" + strings.Repeat("1 0 obj\n<<>>\nendobj\n", 4)),
+			expectKinds: []string{kindObjectCount},
+		},
+		{
+			scenario: "clean Office Open XML document",
+			filename: "foo.docx",
+			content:  zipFile(t, map[string]string{"word/document.xml": "<w:document/>"}),
+		},
+		{
+			scenario:    "Office Open XML document with macros",
+			filename:    "foo.docm",
+			content:     zipFile(t, map[string]string{"word/vbaProject.bin": "foo"}),
+			expectKinds: []string{kindMacro},
+		},
+		{
+			scenario:    "archive with an executable",
+			filename:    "foo.zip",
+			content:     zipFile(t, map[string]string{"setup.EXE": "foo"}),
+			expectKinds: []string{kindExecutable},
+		},
+		{
+			scenario:    "archive with an embedded object",
+			filename:    "foo.xlsx",
+			content:     zipFile(t, map[string]string{"xl/embeddings/oleObject1.bin": "foo"}),
+			expectKinds: []string{kindEmbeddedObject},
+		},
+		{
+			scenario:    "archive with too many entries",
+			filename:    "foo.zip",
+			content:     zipFile(t, map[string]string{"a": "", "b": "", "c": "", "d": ""}),
+			expectKinds: []string{kindObjectCount},
+		},
+		{
+			scenario:    "decompression bomb",
+			filename:    "foo.zip",
+			content:     zipFile(t, map[string]string{"foo": strings.Repeat("0", 1<<20)}),
+			expectKinds: []string{kindCompressionBomb},
+		},
+		{
+			scenario: "invalid archive",
+			filename: "foo.zip",
+			content:  []byte("PK\x03\x04foo"),
+		},
+		{
+			scenario:    "legacy document with macros and a package",
+			filename:    "foo.doc",
+			content:     append(append(append([]byte(nil), ole2Magic...), utf16le("_VBA_PROJECT")...), utf16le("\x01Ole10Native")...),
+			expectKinds: []string{kindMacro, kindEmbeddedObject},
+		},
+		{
+			scenario:    "RTF with an embedded object",
+			filename:    "foo.rtf",
+			content:     []byte(`{\rtf1{\object\objemb{\objdata 0102}}}`),
+			expectKinds: []string{kindEmbeddedObject},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+
+			err := os.WriteFile(path, tc.content, 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			findings, err := scanner{maxObjects: 3}.scan(path)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var kinds []string
+			for _, finding := range findings {
+				if finding.Filename != tc.filename {
+					t.Errorf("expected filename '%s' but got '%s'", tc.filename, finding.Filename)
+				}
+
+				if finding.Score != scores[finding.Kind] {
+					t.Errorf("expected score %d but got %d", scores[finding.Kind], finding.Score)
+				}
+
+				kinds = append(kinds, finding.Kind)
+			}
+
+			if !reflect.DeepEqual(kinds, tc.expectKinds) {
+				t.Errorf("expected kinds %v but got %v", tc.expectKinds, kinds)
+			}
+		})
+	}
+}
+
+func TestScanner_ScanNonExistingFile(t *testing.T) {
+	_, err := scanner{maxObjects: 3}.scan("/foo")
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
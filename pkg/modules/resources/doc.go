@@ -0,0 +1,9 @@
+// Package resources provides a module which accounts the memory and the
+// scratch disk of each request, and reports them with the output metadata and
+// as Prometheus metrics. It may warn about or cancel the requests whose
+// processes exceed a memory budget.
+//
+// Only the processes a request runs, e.g., QPDF, Ghostscript or the
+// quarantine LibreOffice workers, count. The long-lived processes shared
+// between requests, i.e., Chromium and the LibreOffice workers, do not.
+package resources
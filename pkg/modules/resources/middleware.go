@@ -0,0 +1,173 @@
+package resources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/bytes"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// accounting gathers the samples of the resources of a request.
+type accounting struct {
+	mod     *Resources
+	ctx     *api.Context
+	tracker *gotenberg.ProcessTracker
+	cancel  context.CancelCauseFunc
+
+	// memory and scratch are the last samples, accounted in the module
+	// totals.
+	memory      int64
+	scratch     int64
+	peakScratch int64
+	warned      bool
+	cancelled   bool
+	mu          sync.Mutex
+}
+
+// sample reads the memory of the processes and the size of the working
+// directory of the request, and applies the limits.
+func (a *accounting) sample() {
+	memory := a.tracker.Sample()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	scratch, err := a.ctx.ScratchSize()
+	if err != nil {
+		a.ctx.Log().Debug(fmt.Sprintf("get scratch size: %s", err))
+		scratch = a.scratch
+	}
+
+	a.mod.memory.Add(memory - a.memory)
+	a.mod.scratch.Add(scratch - a.scratch)
+	a.memory, a.scratch = memory, scratch
+
+	if scratch > a.peakScratch {
+		a.peakScratch = scratch
+	}
+
+	// The peak also accounts the short spikes of the exited processes.
+	peak := a.tracker.Peak()
+	if a.mod.memorySoftLimit > 0 && peak > a.mod.memorySoftLimit && !a.warned {
+		a.warned = true
+		a.mod.softLimitExceeded.Add(1)
+
+		a.ctx.Log().Warn(fmt.Sprintf("processes used %d bytes of memory, above the soft limit of %d bytes", peak, a.mod.memorySoftLimit))
+		a.ctx.AddWarnings(fmt.Sprintf("the processes used %s of memory, above the soft limit of %s", bytes.Format(peak), bytes.Format(a.mod.memorySoftLimit)))
+	}
+
+	if a.mod.memoryHardLimit > 0 && memory > a.mod.memoryHardLimit && !a.cancelled {
+		a.cancelled = true
+		a.mod.hardLimitExceeded.Add(1)
+
+		a.ctx.Log().Warn(fmt.Sprintf("processes use %d bytes of memory, above the hard limit of %d bytes: cancel the request", memory, a.mod.memoryHardLimit))
+		a.cancel(ErrMemoryLimitExceeded)
+	}
+}
+
+// usage returns the accounting of the resources of the request.
+func (a *accounting) usage() api.ResourceUsage {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return api.ResourceUsage{
+		PeakMemoryBytes:  a.tracker.Peak(),
+		PeakScratchBytes: a.peakScratch,
+	}
+}
+
+// release removes the last samples from the module totals.
+func (a *accounting) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.mod.memory.Add(-a.memory)
+	a.mod.scratch.Add(-a.scratch)
+	a.memory, a.scratch = 0, 0
+}
+
+// resourcesMiddleware accounts the resources of the multipart requests. It
+// runs after the webhook middleware, so that the accounting also applies to
+// the asynchronous conversions.
+func resourcesMiddleware(mod *Resources) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryLowPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					ctx := c.Get("context").(*api.Context)
+
+					// The processes started with a context derived from
+					// the request context register in the tracker.
+					tracker := gotenberg.NewProcessTracker()
+					parent := ctx.Context
+					trackedCtx, cancel := context.WithCancelCause(gotenberg.WithProcessTracker(parent, tracker))
+					defer cancel(nil)
+
+					ctx.Context = trackedCtx
+					defer func() {
+						ctx.Context = parent
+					}()
+
+					a := &accounting{
+						mod:     mod,
+						ctx:     ctx,
+						tracker: tracker,
+						cancel:  cancel,
+					}
+
+					ctx.SetResourceUsage(a.usage)
+
+					done := make(chan struct{})
+					stopped := make(chan struct{})
+
+					go func() {
+						defer close(stopped)
+
+						ticker := time.NewTicker(mod.sampleInterval)
+						defer ticker.Stop()
+
+						for {
+							select {
+							case <-done:
+								return
+							case <-ticker.C:
+								a.sample()
+							}
+						}
+					}()
+
+					err := next(c)
+
+					close(done)
+					<-stopped
+
+					// The output files count in the scratch disk too.
+					a.sample()
+					a.release()
+
+					if errors.Is(context.Cause(trackedCtx), ErrMemoryLimitExceeded) {
+						return api.WrapError(
+							fmt.Errorf("run conversion: %w", ErrMemoryLimitExceeded),
+							api.NewSentinelHttpError(
+								http.StatusUnprocessableEntity,
+								fmt.Sprintf("The processes of the request used more than %s of memory", bytes.Format(mod.memoryHardLimit)),
+							).WithCode(api.ErrorCodeResourcesMemoryLimitExceeded),
+						)
+					}
+
+					return err
+				}
+			}
+		}(),
+	}
+}
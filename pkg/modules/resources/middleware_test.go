@@ -0,0 +1,139 @@
+package resources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestResourcesMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		mod               *Resources
+		duration          string
+		expectStatus      int
+		expectCode        string
+		expectWarnings    int
+		expectSoftLimit   int64
+		expectHardLimit   int64
+		expectPeakScratch int64
+	}{
+		{
+			scenario:          "no limit",
+			mod:               &Resources{sampleInterval: time.Duration(10) * time.Millisecond},
+			duration:          "0.1",
+			expectPeakScratch: 3,
+		},
+		{
+			scenario:          "memory soft limit exceeded",
+			mod:               &Resources{sampleInterval: time.Duration(10) * time.Millisecond, memorySoftLimit: 1},
+			duration:          "0.1",
+			expectWarnings:    1,
+			expectSoftLimit:   1,
+			expectPeakScratch: 3,
+		},
+		{
+			scenario:          "memory hard limit exceeded",
+			mod:               &Resources{sampleInterval: time.Duration(10) * time.Millisecond, memoryHardLimit: 1},
+			duration:          "10",
+			expectStatus:      http.StatusUnprocessableEntity,
+			expectCode:        "RESOURCES_MEMORY_LIMIT_EXCEEDED",
+			expectHardLimit:   1,
+			expectPeakScratch: 3,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+
+			err := os.WriteFile(dirPath+"/foo.txt", []byte("foo"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/forms/pdfengines/merge", nil)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.Context.Context = context.Background()
+			ctx.SetDirPath(dirPath)
+			ctx.SetLogger(zap.NewNop())
+			c.Set("context", ctx.Context)
+
+			tc.mod.logger = zap.NewNop()
+
+			err = resourcesMiddleware(tc.mod).Handler(func(c echo.Context) error {
+				cmd, err := gotenberg.CommandContext(ctx, zap.NewNop(), "sleep", tc.duration)
+				if err != nil {
+					return err
+				}
+
+				_, err = cmd.Exec()
+
+				return err
+			})(c)
+
+			if tc.expectStatus != 0 {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				response := api.ParseErrorResponse(err)
+				if response.Status != tc.expectStatus {
+					t.Errorf("expected status %d but got %d", tc.expectStatus, response.Status)
+				}
+
+				if response.Code != tc.expectCode {
+					t.Errorf("expected code '%s' but got '%s'", tc.expectCode, response.Code)
+				}
+			} else if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(ctx.Warnings()) != tc.expectWarnings {
+				t.Errorf("expected %d warnings but got %v", tc.expectWarnings, ctx.Warnings())
+			}
+
+			if tc.mod.softLimitExceeded.Load() != tc.expectSoftLimit {
+				t.Errorf("expected %d requests above the soft limit but got %d", tc.expectSoftLimit, tc.mod.softLimitExceeded.Load())
+			}
+
+			if tc.mod.hardLimitExceeded.Load() != tc.expectHardLimit {
+				t.Errorf("expected %d requests above the hard limit but got %d", tc.expectHardLimit, tc.mod.hardLimitExceeded.Load())
+			}
+
+			if tc.mod.memory.Load() != 0 || tc.mod.scratch.Load() != 0 {
+				t.Errorf("expected released totals but got %d and %d", tc.mod.memory.Load(), tc.mod.scratch.Load())
+			}
+
+			if ctx.Context.Context != context.Background() {
+				t.Error("expected the original context to be restored")
+			}
+
+			metadata, err := ctx.Metadata()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if metadata.Resources == nil {
+				t.Fatal("expected a resource usage but got none")
+			}
+
+			if metadata.Resources.PeakMemoryBytes <= 0 {
+				t.Errorf("expected a positive peak memory but got %d", metadata.Resources.PeakMemoryBytes)
+			}
+
+			if metadata.Resources.PeakScratchBytes != tc.expectPeakScratch {
+				t.Errorf("expected a peak scratch of %d but got %d", tc.expectPeakScratch, metadata.Resources.PeakScratchBytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/gommon/bytes"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Resources))
+}
+
+// ErrMemoryLimitExceeded happens if the processes of a request use more
+// memory than the hard limit.
+var ErrMemoryLimitExceeded = errors.New("memory limit exceeded")
+
+// Resources is a module which accounts the memory and the scratch disk of
+// each request.
+type Resources struct {
+	disable         bool
+	sampleInterval  time.Duration
+	memorySoftLimit int64
+	memoryHardLimit int64
+
+	memory            atomic.Int64
+	scratch           atomic.Int64
+	softLimitExceeded atomic.Int64
+	hardLimitExceeded atomic.Int64
+	logger            *zap.Logger
+}
+
+// Descriptor returns a [Resources]'s module descriptor.
+func (mod *Resources) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "resources",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("resources", flag.ExitOnError)
+			fs.Bool("resources-disable", false, "Disable the accounting of the memory and scratch disk of the requests")
+			fs.Duration("resources-sample-interval", time.Duration(500)*time.Millisecond, "Set the interval at which to sample the memory and scratch disk of a request")
+			fs.String("resources-memory-soft-limit", "0B", "Set the memory of the processes of a request above which it gets a warning, e.g., 512MB - 0B disables this behavior")
+			fs.String("resources-memory-hard-limit", "0B", "Set the memory of the processes of a request above which it is cancelled, e.g., 1GB - 0B disables this behavior")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Resources) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Resources) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disable = flags.MustBool("resources-disable")
+	mod.sampleInterval = flags.MustDuration("resources-sample-interval")
+
+	memorySoftLimit, err := bytes.Parse(flags.MustHumanReadableBytesString("resources-memory-soft-limit"))
+	if err != nil {
+		return fmt.Errorf("parse memory soft limit: %w", err)
+	}
+
+	mod.memorySoftLimit = memorySoftLimit
+
+	memoryHardLimit, err := bytes.Parse(flags.MustHumanReadableBytesString("resources-memory-hard-limit"))
+	if err != nil {
+		return fmt.Errorf("parse memory hard limit: %w", err)
+	}
+
+	mod.memoryHardLimit = memoryHardLimit
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Resources) Validate() error {
+	if mod.disable {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.sampleInterval <= 0 {
+		err = multierr.Append(err,
+			errors.New("sample interval must be more than 0"),
+		)
+	}
+
+	if mod.memorySoftLimit > 0 && mod.memoryHardLimit > 0 && mod.memorySoftLimit >= mod.memoryHardLimit {
+		err = multierr.Append(err,
+			errors.New("memory soft limit must be less than the memory hard limit"),
+		)
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware which accounts the resources of the
+// requests.
+func (mod *Resources) Middlewares() ([]api.Middleware, error) {
+	if mod.disable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		resourcesMiddleware(mod),
+	}, nil
+}
+
+// Metrics returns the metrics.
+func (mod *Resources) Metrics() ([]gotenberg.Metric, error) {
+	if mod.disable {
+		return nil, nil
+	}
+
+	return []gotenberg.Metric{
+		{
+			Name:        "resources_memory_bytes",
+			Description: "Current memory of the processes of the in-flight requests.",
+			Read: func() float64 {
+				return float64(mod.memory.Load())
+			},
+		},
+		{
+			Name:        "resources_scratch_bytes",
+			Description: "Current size of the working directories of the in-flight requests.",
+			Read: func() float64 {
+				return float64(mod.scratch.Load())
+			},
+		},
+		{
+			Name:        "resources_memory_soft_limit_exceeded_total",
+			Description: "Total number of requests above the memory soft limit.",
+			Counter:     true,
+			Read: func() float64 {
+				return float64(mod.softLimitExceeded.Load())
+			},
+		},
+		{
+			Name:        "resources_memory_hard_limit_exceeded_total",
+			Description: "Total number of requests cancelled for exceeding the memory hard limit.",
+			Counter:     true,
+			Read: func() float64 {
+				return float64(mod.hardLimitExceeded.Load())
+			},
+		},
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module          = (*Resources)(nil)
+	_ gotenberg.Provisioner     = (*Resources)(nil)
+	_ gotenberg.Validator       = (*Resources)(nil)
+	_ gotenberg.MetricsProvider = (*Resources)(nil)
+	_ api.MiddlewareProvider    = (*Resources)(nil)
+)
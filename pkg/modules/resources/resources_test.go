@@ -0,0 +1,234 @@
+package resources
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestResources_Descriptor(t *testing.T) {
+	descriptor := new(Resources).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Resources))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestResources_Provision(t *testing.T) {
+	provider := &struct {
+		gotenberg.ModuleMock
+		gotenberg.LoggerProviderMock
+	}{}
+	provider.DescriptorMock = func() gotenberg.ModuleDescriptor {
+		return gotenberg.ModuleDescriptor{ID: "foo", New: func() gotenberg.Module { return provider }}
+	}
+	provider.LoggerMock = func(mod gotenberg.Module) (*zap.Logger, error) {
+		return zap.NewNop(), nil
+	}
+
+	for _, tc := range []struct {
+		scenario        string
+		args            []string
+		modules         []gotenberg.ModuleDescriptor
+		expectSoftLimit int64
+		expectHardLimit int64
+		expectError     bool
+		expectPanic     bool
+	}{
+		{
+			scenario:    "invalid memory soft limit",
+			args:        []string{"--resources-memory-soft-limit=foo"},
+			modules:     []gotenberg.ModuleDescriptor{provider.Descriptor()},
+			expectPanic: true,
+		},
+		{
+			scenario:    "invalid memory hard limit",
+			args:        []string{"--resources-memory-hard-limit=foo"},
+			modules:     []gotenberg.ModuleDescriptor{provider.Descriptor()},
+			expectPanic: true,
+		},
+		{
+			scenario:    "no logger provider",
+			expectError: true,
+		},
+		{
+			scenario:        "provision success",
+			args:            []string{"--resources-memory-soft-limit=512MB", "--resources-memory-hard-limit=1GB"},
+			modules:         []gotenberg.ModuleDescriptor{provider.Descriptor()},
+			expectSoftLimit: 512 * 1000 * 1000,
+			expectHardLimit: 1000 * 1000 * 1000,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			if tc.expectPanic {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Fatal("expected panic but got none")
+					}
+				}()
+			}
+
+			if !tc.expectPanic {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Fatalf("expected no panic but got: %v", r)
+					}
+				}()
+			}
+
+			fs := new(Resources).Descriptor().FlagSet
+
+			err := fs.Parse(tc.args)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			ctx := gotenberg.NewContext(gotenberg.ParsedFlags{FlagSet: fs}, tc.modules)
+
+			mod := new(Resources)
+			err = mod.Provision(ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			if mod.memorySoftLimit != tc.expectSoftLimit {
+				t.Errorf("expected memory soft limit %d but got %d", tc.expectSoftLimit, mod.memorySoftLimit)
+			}
+
+			if mod.memoryHardLimit != tc.expectHardLimit {
+				t.Errorf("expected memory hard limit %d but got %d", tc.expectHardLimit, mod.memoryHardLimit)
+			}
+		})
+	}
+}
+
+func TestResources_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Resources
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			mod:      &Resources{disable: true},
+		},
+		{
+			scenario:    "invalid sample interval",
+			mod:         &Resources{sampleInterval: 0},
+			expectError: true,
+		},
+		{
+			scenario:    "soft limit above the hard limit",
+			mod:         &Resources{sampleInterval: time.Second, memorySoftLimit: 2048, memoryHardLimit: 1024},
+			expectError: true,
+		},
+		{
+			scenario: "soft limit without hard limit",
+			mod:      &Resources{sampleInterval: time.Second, memorySoftLimit: 2048},
+		},
+		{
+			scenario: "validate success",
+			mod:      &Resources{sampleInterval: time.Second, memorySoftLimit: 1024, memoryHardLimit: 2048},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestResources_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		disable           bool
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "disabled",
+			disable:           true,
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "enabled",
+			expectMiddlewares: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Resources{disable: tc.disable}
+
+			middlewares, err := mod.Middlewares()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(middlewares) != tc.expectMiddlewares {
+				t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+			}
+		})
+	}
+}
+
+func TestResources_Metrics(t *testing.T) {
+	mod := new(Resources)
+	mod.memory.Store(1)
+	mod.scratch.Store(2)
+	mod.softLimitExceeded.Store(3)
+	mod.hardLimitExceeded.Store(4)
+
+	metrics, err := mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := map[string]float64{
+		"resources_memory_bytes":                     1,
+		"resources_scratch_bytes":                    2,
+		"resources_memory_soft_limit_exceeded_total": 3,
+		"resources_memory_hard_limit_exceeded_total": 4,
+	}
+
+	if len(metrics) != len(expect) {
+		t.Fatalf("expected %d metrics but got %d", len(expect), len(metrics))
+	}
+
+	for _, metric := range metrics {
+		if metric.Read() != expect[metric.Name] {
+			t.Errorf("expected %f for '%s' but got %f", expect[metric.Name], metric.Name, metric.Read())
+		}
+	}
+
+	mod.disable = true
+
+	metrics, err = mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(metrics) != 0 {
+		t.Errorf("expected no metric but got %d", len(metrics))
+	}
+}
@@ -0,0 +1,5 @@
+// Package results provides a module which keeps the output files of the
+// asynchronous requests for a limited time and serves them through signed,
+// expiring URLs. Such URLs do not require the credentials of the original
+// request, so they may be handed to browsers.
+package results
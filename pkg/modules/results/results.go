@@ -0,0 +1,426 @@
+package results
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Results))
+}
+
+// Results is a module which keeps the output files of the asynchronous
+// requests and serves them through signed, expiring URLs.
+type Results struct {
+	dir             string
+	ttl             time.Duration
+	signingKey      []byte
+	baseUrl         string
+	cleanupInterval time.Duration
+
+	logger *zap.Logger
+	stop   chan struct{}
+}
+
+// Descriptor returns a [Results]'s module descriptor.
+func (mod *Results) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "results",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("results", flag.ExitOnError)
+			fs.String("results-signing-key", "", "Set the secret key for signing the download URLs of the results, or a reference to a secret - enables the feature")
+			fs.String("results-base-url", "", "Set the public base URL of the API, root path included, for building the download URLs (e.g., https://gotenberg.example.com)")
+			fs.String("results-dir", filepath.Join(os.TempDir(), "gotenberg-results"), "Set the directory in which to keep the results")
+			fs.Duration("results-ttl", time.Duration(1)*time.Hour, "Set the time after which a result expires and is deleted")
+			fs.Duration("results-cleanup-interval", time.Duration(1)*time.Minute, "Set the interval at which to delete the expired results")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Results) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Results) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.baseUrl = strings.TrimSuffix(flags.MustString("results-base-url"), "/")
+	mod.dir = flags.MustString("results-dir")
+	mod.ttl = flags.MustDuration("results-ttl")
+	mod.cleanupInterval = flags.MustDuration("results-cleanup-interval")
+
+	signingKey, err := gotenberg.ResolveSecret(ctx, flags.MustString("results-signing-key"))
+	if err != nil {
+		return fmt.Errorf("get signing key: %w", err)
+	}
+
+	mod.signingKey = []byte(signingKey)
+
+	if !mod.enabled() {
+		// Exit early.
+		return nil
+	}
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Results) Validate() error {
+	if !mod.enabled() {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if len(mod.signingKey) < 32 {
+		err = multierr.Append(err,
+			errors.New("signing key must be at least 32 bytes long"),
+		)
+	}
+
+	if mod.baseUrl == "" {
+		err = multierr.Append(err,
+			errors.New("base URL must not be empty"),
+		)
+	} else {
+		u, parseErr := url.Parse(mod.baseUrl)
+		if parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			err = multierr.Append(err,
+				fmt.Errorf("base URL '%s' must be an absolute HTTP(S) URL", mod.baseUrl),
+			)
+		}
+	}
+
+	if mod.dir == "" {
+		err = multierr.Append(err,
+			errors.New("directory must not be empty"),
+		)
+	}
+
+	if mod.ttl <= 0 {
+		err = multierr.Append(err,
+			errors.New("TTL must be more than 0"),
+		)
+	}
+
+	if mod.cleanupInterval <= 0 {
+		err = multierr.Append(err,
+			errors.New("cleanup interval must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Start creates the directory of the results and deletes the expired ones
+// periodically.
+func (mod *Results) Start() error {
+	if !mod.enabled() {
+		return nil
+	}
+
+	err := os.MkdirAll(mod.dir, 0o700)
+	if err != nil {
+		return fmt.Errorf("create results directory: %w", err)
+	}
+
+	mod.stop = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(mod.cleanupInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-mod.stop:
+				return
+			case <-ticker.C:
+				err := mod.cleanup(time.Now())
+				if err != nil {
+					mod.logger.Error(fmt.Sprintf("delete expired results: %s", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Results) StartupMessage() string {
+	if !mod.enabled() {
+		return "result URLs disabled"
+	}
+
+	return fmt.Sprintf("results kept in '%s' for %s", mod.dir, mod.ttl)
+}
+
+// Stop stops the periodic deletion of the expired results.
+func (mod *Results) Stop(ctx context.Context) error {
+	if mod.stop != nil {
+		close(mod.stop)
+	}
+
+	return nil
+}
+
+// StoreResult copies the output file to the results directory and returns
+// its signed download URL.
+func (mod *Results) StoreResult(outputPath, filename string) (gotenberg.StoredResult, error) {
+	if !mod.enabled() {
+		return gotenberg.StoredResult{}, gotenberg.ErrResultStoreDisabled
+	}
+
+	id := uuid.NewString()
+	filename = filepath.Base(filename)
+	dirPath := filepath.Join(mod.dir, id)
+
+	err := os.Mkdir(dirPath, 0o700)
+	if err != nil {
+		return gotenberg.StoredResult{}, fmt.Errorf("create result directory: %w", err)
+	}
+
+	size, checksum, err := copyFile(outputPath, filepath.Join(dirPath, filename))
+	if err != nil {
+		// Let's not keep a partial result.
+		_ = os.RemoveAll(dirPath)
+
+		return gotenberg.StoredResult{}, fmt.Errorf("copy output file: %w", err)
+	}
+
+	expiresAt := time.Now().Add(mod.ttl).Truncate(time.Second)
+
+	return gotenberg.StoredResult{
+		ID:        id,
+		Url:       mod.signedUrl(id, filename, expiresAt),
+		Filename:  filename,
+		Size:      size,
+		Sha256:    checksum,
+		ExpiresAt: expiresAt.UTC(),
+	}, nil
+}
+
+// DeleteResult removes a result before its expiration.
+func (mod *Results) DeleteResult(id string) error {
+	if !mod.enabled() {
+		return gotenberg.ErrResultStoreDisabled
+	}
+
+	_, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid result ID '%s': %w", id, err)
+	}
+
+	err = os.RemoveAll(filepath.Join(mod.dir, id))
+	if err != nil {
+		return fmt.Errorf("remove result: %w", err)
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP route for downloading the results.
+func (mod *Results) Routes() ([]api.Route, error) {
+	if !mod.enabled() {
+		return nil, nil
+	}
+
+	return []api.Route{
+		{
+			Method:  http.MethodGet,
+			Path:    "/results/:id/:filename",
+			Handler: mod.download,
+		},
+	}, nil
+}
+
+// download serves a result if its URL has a valid, non-expired signature.
+func (mod *Results) download(c echo.Context) error {
+	id := c.Param("id")
+	filename, err := url.PathUnescape(c.Param("filename"))
+	if err != nil {
+		filename = c.Param("filename")
+	}
+
+	expires, err := strconv.ParseInt(c.QueryParam("expires"), 10, 64)
+	if err != nil || !mod.verify(id, filename, expires, c.QueryParam("signature")) {
+		return api.WrapError(
+			errors.New("invalid result URL signature"),
+			api.NewSentinelHttpError(http.StatusForbidden, http.StatusText(http.StatusForbidden)).WithCode(api.ErrorCodeResultsInvalidSignature),
+		)
+	}
+
+	if time.Now().Unix() > expires {
+		return api.WrapError(
+			fmt.Errorf("result '%s' expired", id),
+			api.NewSentinelHttpError(http.StatusGone, "The result has expired").WithCode(api.ErrorCodeResultsExpired),
+		)
+	}
+
+	// The signature guarantees the identifier and the filename come from
+	// this module, i.e., they are not path traversals.
+	resultPath := filepath.Join(mod.dir, id, filename)
+
+	_, err = os.Stat(resultPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return api.WrapError(
+				fmt.Errorf("result '%s' not found: %w", id, err),
+				api.NewSentinelHttpError(http.StatusGone, "The result has expired").WithCode(api.ErrorCodeResultsExpired),
+			)
+		}
+
+		return fmt.Errorf("stat result: %w", err)
+	}
+
+	c.Response().Header().Set("Cache-Control", "private, no-store")
+
+	return c.Attachment(resultPath, filename)
+}
+
+// signedUrl returns the download URL of a result.
+func (mod *Results) signedUrl(id, filename string, expiresAt time.Time) string {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", mod.sign(id, filename, expiresAt.Unix()))
+
+	return fmt.Sprintf("%s/results/%s/%s?%s", mod.baseUrl, id, url.PathEscape(filename), query.Encode())
+}
+
+// sign returns the signature of a result URL.
+func (mod *Results) sign(id, filename string, expires int64) string {
+	mac := hmac.New(sha256.New, mod.signingKey)
+	mac.Write([]byte(fmt.Sprintf("%s/%s:%d", id, filename, expires)))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the signature of a result URL in constant time.
+func (mod *Results) verify(id, filename string, expires int64, signature string) bool {
+	actual, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	expected, err := base64.RawURLEncoding.DecodeString(mod.sign(id, filename, expires))
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(actual, expected)
+}
+
+// cleanup deletes the results older than the TTL.
+func (mod *Results) cleanup(now time.Time) error {
+	entries, err := os.ReadDir(mod.dir)
+	if err != nil {
+		return fmt.Errorf("read results directory: %w", err)
+	}
+
+	var cleanupErr error
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			cleanupErr = multierr.Append(cleanupErr, err)
+			continue
+		}
+
+		if now.Sub(info.ModTime()) < mod.ttl {
+			continue
+		}
+
+		err = os.RemoveAll(filepath.Join(mod.dir, entry.Name()))
+		if err != nil {
+			cleanupErr = multierr.Append(cleanupErr, err)
+			continue
+		}
+
+		mod.logger.Debug(fmt.Sprintf("result '%s' deleted", entry.Name()))
+	}
+
+	return cleanupErr
+}
+
+// enabled tells if the module keeps the results.
+func (mod *Results) enabled() bool {
+	return len(mod.signingKey) > 0
+}
+
+// copyFile copies a file and returns the number of bytes copied and the
+// hex-encoded SHA-256 checksum of the content, computed along the copy.
+func copyFile(srcPath, destPath string) (int64, string, error) {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return 0, "", fmt.Errorf("open source file: %w", err)
+	}
+
+	defer func() {
+		_ = src.Close()
+	}()
+
+	dest, err := os.OpenFile(destPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, "", fmt.Errorf("create destination file: %w", err)
+	}
+
+	h := sha256.New()
+
+	n, err := io.Copy(io.MultiWriter(dest, h), src)
+	if err != nil {
+		_ = dest.Close()
+
+		return 0, "", fmt.Errorf("copy file: %w", err)
+	}
+
+	err = dest.Close()
+	if err != nil {
+		return 0, "", fmt.Errorf("close destination file: %w", err)
+	}
+
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Results)(nil)
+	_ gotenberg.Provisioner = (*Results)(nil)
+	_ gotenberg.Validator   = (*Results)(nil)
+	_ gotenberg.App         = (*Results)(nil)
+	_ gotenberg.ResultStore = (*Results)(nil)
+	_ api.Router            = (*Results)(nil)
+)
@@ -0,0 +1,383 @@
+package results
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+const testSigningKey = "0123456789abcdef0123456789abcdef"
+
+func TestResults_Descriptor(t *testing.T) {
+	descriptor := new(Results).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Results))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestResults_Provision(t *testing.T) {
+	mod := new(Results)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Results).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enabled() {
+		t.Error("expected result URLs to be disabled by default")
+	}
+}
+
+func TestResults_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario        string
+		signingKey      string
+		baseUrl         string
+		dir             string
+		ttl             time.Duration
+		cleanupInterval time.Duration
+		expectError     bool
+	}{
+		{
+			scenario: "disabled",
+		},
+		{
+			scenario:        "signing key too short",
+			signingKey:      "foo",
+			baseUrl:         "https://gotenberg.example.com",
+			dir:             "/tmp",
+			ttl:             time.Duration(1) * time.Hour,
+			cleanupInterval: time.Duration(1) * time.Minute,
+			expectError:     true,
+		},
+		{
+			scenario:        "empty base URL",
+			signingKey:      testSigningKey,
+			dir:             "/tmp",
+			ttl:             time.Duration(1) * time.Hour,
+			cleanupInterval: time.Duration(1) * time.Minute,
+			expectError:     true,
+		},
+		{
+			scenario:        "relative base URL",
+			signingKey:      testSigningKey,
+			baseUrl:         "/gotenberg",
+			dir:             "/tmp",
+			ttl:             time.Duration(1) * time.Hour,
+			cleanupInterval: time.Duration(1) * time.Minute,
+			expectError:     true,
+		},
+		{
+			scenario:        "empty directory",
+			signingKey:      testSigningKey,
+			baseUrl:         "https://gotenberg.example.com",
+			ttl:             time.Duration(1) * time.Hour,
+			cleanupInterval: time.Duration(1) * time.Minute,
+			expectError:     true,
+		},
+		{
+			scenario:        "invalid TTL",
+			signingKey:      testSigningKey,
+			baseUrl:         "https://gotenberg.example.com",
+			dir:             "/tmp",
+			cleanupInterval: time.Duration(1) * time.Minute,
+			expectError:     true,
+		},
+		{
+			scenario:    "invalid cleanup interval",
+			signingKey:  testSigningKey,
+			baseUrl:     "https://gotenberg.example.com",
+			dir:         "/tmp",
+			ttl:         time.Duration(1) * time.Hour,
+			expectError: true,
+		},
+		{
+			scenario:        "validate success",
+			signingKey:      testSigningKey,
+			baseUrl:         "https://gotenberg.example.com",
+			dir:             "/tmp",
+			ttl:             time.Duration(1) * time.Hour,
+			cleanupInterval: time.Duration(1) * time.Minute,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Results{
+				signingKey:      []byte(tc.signingKey),
+				baseUrl:         tc.baseUrl,
+				dir:             tc.dir,
+				ttl:             tc.ttl,
+				cleanupInterval: tc.cleanupInterval,
+			}
+
+			err := mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestResults_StoreResult(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		_, err := new(Results).StoreResult("foo.pdf", "foo.pdf")
+		if !errors.Is(err, gotenberg.ErrResultStoreDisabled) {
+			t.Errorf("expected error %v but got: %v", gotenberg.ErrResultStoreDisabled, err)
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mod := &Results{
+			signingKey: []byte(testSigningKey),
+			baseUrl:    "https://gotenberg.example.com",
+			dir:        t.TempDir(),
+			ttl:        time.Duration(1) * time.Hour,
+		}
+
+		outputPath := filepath.Join(t.TempDir(), "output.pdf")
+		err := os.WriteFile(outputPath, []byte("%PDF-1.7"), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		result, err := mod.StoreResult(outputPath, "my file.pdf")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if result.Size != 8 {
+			t.Errorf("expected size 8 but got %d", result.Size)
+		}
+
+		// echo -n "%PDF-1.7" | sha256sum
+		expectSha256 := "86edbaa24831badfa0a8b04bb410141e2ee4182b6d0014493fe262a7a331c20b"
+		if result.Sha256 != expectSha256 {
+			t.Errorf("expected SHA-256 '%s' but got '%s'", expectSha256, result.Sha256)
+		}
+
+		if !strings.HasPrefix(result.Url, "https://gotenberg.example.com/results/") {
+			t.Errorf("expected URL to start with the base URL but got '%s'", result.Url)
+		}
+
+		if time.Until(result.ExpiresAt) <= 0 {
+			t.Errorf("expected result to expire in the future but got %s", result.ExpiresAt)
+		}
+
+		_, err = os.Stat(filepath.Join(mod.dir, result.ID, "my file.pdf"))
+		if err != nil {
+			t.Errorf("expected the result in its directory but got: %v", err)
+		}
+	})
+}
+
+func TestResults_DeleteResult(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		err := new(Results).DeleteResult("foo")
+		if !errors.Is(err, gotenberg.ErrResultStoreDisabled) {
+			t.Errorf("expected error %v but got: %v", gotenberg.ErrResultStoreDisabled, err)
+		}
+	})
+
+	mod := &Results{
+		signingKey: []byte(testSigningKey),
+		baseUrl:    "https://gotenberg.example.com",
+		dir:        t.TempDir(),
+		ttl:        time.Duration(1) * time.Hour,
+	}
+
+	t.Run("invalid ID", func(t *testing.T) {
+		err := mod.DeleteResult("../foo")
+		if err == nil {
+			t.Fatal("expected error but got none")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		outputPath := filepath.Join(t.TempDir(), "output.pdf")
+		err := os.WriteFile(outputPath, []byte("%PDF-1.7"), 0o600)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		result, err := mod.StoreResult(outputPath, "output.pdf")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		err = mod.DeleteResult(result.ID)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		_, err = os.Stat(filepath.Join(mod.dir, result.ID))
+		if !os.IsNotExist(err) {
+			t.Errorf("expected the result to be deleted but got: %v", err)
+		}
+
+		err = mod.DeleteResult(result.ID)
+		if err != nil {
+			t.Errorf("expected no error for a missing result but got: %v", err)
+		}
+	})
+}
+
+func TestResults_download(t *testing.T) {
+	mod := &Results{
+		signingKey: []byte(testSigningKey),
+		baseUrl:    "https://gotenberg.example.com",
+		dir:        t.TempDir(),
+		ttl:        time.Duration(1) * time.Hour,
+		logger:     zap.NewNop(),
+	}
+
+	outputPath := filepath.Join(t.TempDir(), "output.pdf")
+	err := os.WriteFile(outputPath, []byte("%PDF-1.7"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	result, err := mod.StoreResult(outputPath, "my file.pdf")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	validUrl, err := url.Parse(result.Url)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	segments := strings.Split(validUrl.Path, "/")
+	id := segments[2]
+
+	for _, tc := range []struct {
+		scenario     string
+		filename     string
+		expires      string
+		signature    string
+		expectStatus int
+	}{
+		{
+			scenario:     "valid signature",
+			filename:     "my file.pdf",
+			expires:      validUrl.Query().Get("expires"),
+			signature:    validUrl.Query().Get("signature"),
+			expectStatus: http.StatusOK,
+		},
+		{
+			scenario:     "tampered filename",
+			filename:     "other.pdf",
+			expires:      validUrl.Query().Get("expires"),
+			signature:    validUrl.Query().Get("signature"),
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			scenario:     "tampered expiration",
+			filename:     "my file.pdf",
+			expires:      "9999999999",
+			signature:    validUrl.Query().Get("signature"),
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			scenario:     "invalid expiration",
+			filename:     "my file.pdf",
+			expires:      "foo",
+			signature:    validUrl.Query().Get("signature"),
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			scenario:     "expired",
+			filename:     "my file.pdf",
+			expires:      "1",
+			signature:    mod.sign(id, "my file.pdf", 1),
+			expectStatus: http.StatusGone,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			srv := echo.New()
+			srv.HideBanner = true
+			srv.HidePort = true
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			rec := httptest.NewRecorder()
+			c := srv.NewContext(req, rec)
+			c.SetParamNames("id", "filename")
+			c.SetParamValues(id, tc.filename)
+			c.QueryParams().Set("expires", tc.expires)
+			c.QueryParams().Set("signature", tc.signature)
+
+			err := mod.download(c)
+
+			status := http.StatusOK
+			if err != nil {
+				status = api.ParseErrorResponse(err).Status
+			}
+
+			if status != tc.expectStatus {
+				t.Errorf("expected status %d but got %d (error: %v)", tc.expectStatus, status, err)
+			}
+		})
+	}
+}
+
+func TestResults_cleanup(t *testing.T) {
+	mod := &Results{
+		dir:    t.TempDir(),
+		ttl:    time.Duration(1) * time.Hour,
+		logger: zap.NewNop(),
+	}
+
+	for _, name := range []string{"expired", "recent"} {
+		err := os.Mkdir(filepath.Join(mod.dir, name), 0o700)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+	}
+
+	expired := time.Now().Add(-time.Duration(2) * time.Hour)
+	err := os.Chtimes(filepath.Join(mod.dir, "expired"), expired, expired)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = mod.cleanup(time.Now())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(mod.dir, "expired"))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected expired result to be deleted but got: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(mod.dir, "recent"))
+	if err != nil {
+		t.Errorf("expected recent result to be kept but got: %v", err)
+	}
+}
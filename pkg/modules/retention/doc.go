@@ -0,0 +1,5 @@
+// Package retention provides a module which removes the working directories
+// of the requests, either immediately or after a retention period, optionally
+// overwriting their files before unlinking them, for data-protection
+// requirements on sensitive documents.
+package retention
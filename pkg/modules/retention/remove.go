@@ -0,0 +1,87 @@
+package retention
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// removeAll removes a directory and returns the number of bytes of its
+// files. If secure, it overwrites the files with zeros and syncs them to the
+// disk before unlinking them.
+func removeAll(dirPath string, secure bool) (int64, error) {
+	var total int64
+
+	err := filepath.WalkDir(dirPath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return fmt.Errorf("get info of '%s': %w", path, err)
+		}
+
+		total += info.Size()
+
+		if !secure {
+			return nil
+		}
+
+		err = overwrite(path, info.Size())
+		if err != nil {
+			return fmt.Errorf("overwrite '%s': %w", path, err)
+		}
+
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+
+	err = os.RemoveAll(dirPath)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// overwrite writes zeros over the content of a file.
+func overwrite(path string, size int64) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+
+	_, err = io.CopyN(f, zeroReader{}, size)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write zeros: %w", err)
+	}
+
+	err = f.Sync()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("sync file: %w", err)
+	}
+
+	return f.Close()
+}
+
+// zeroReader is an infinite reader of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,212 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Retention))
+}
+
+// Retention is a module which removes the working directories of the
+// requests according to a retention policy.
+type Retention struct {
+	delay        time.Duration
+	secureDelete bool
+
+	logger   *zap.Logger
+	pending  map[string]*time.Timer
+	mu       sync.Mutex
+	removed  atomic.Int64
+	bytes    atomic.Int64
+	failures atomic.Int64
+}
+
+// Descriptor returns a [Retention]'s module descriptor.
+func (mod *Retention) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "retention",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("retention", flag.ExitOnError)
+			fs.Duration("retention-delay", 0, "Set the time during which the working directory of a request is kept once handled - 0 removes it immediately")
+			fs.Bool("retention-secure-delete", false, "Overwrite the files of a working directory before unlinking them")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Retention) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Retention) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.delay = flags.MustDuration("retention-delay")
+	mod.secureDelete = flags.MustBool("retention-secure-delete")
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+	mod.pending = make(map[string]*time.Timer)
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Retention) Validate() error {
+	if mod.delay < 0 {
+		return errors.New("delay must be at least 0")
+	}
+
+	return nil
+}
+
+// Start does nothing, the removals are scheduled per request.
+func (mod *Retention) Start() error {
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Retention) StartupMessage() string {
+	policy := "removed immediately"
+	if mod.delay > 0 {
+		policy = fmt.Sprintf("removed after %s", mod.delay)
+	}
+
+	if mod.secureDelete {
+		policy += ", files overwritten first"
+	}
+
+	return fmt.Sprintf("working directories %s", policy)
+}
+
+// Stop removes the pending working directories, so that no file outlives
+// the application.
+func (mod *Retention) Stop(ctx context.Context) error {
+	mod.mu.Lock()
+	dirPaths := make([]string, 0, len(mod.pending))
+	for dirPath, timer := range mod.pending {
+		if timer.Stop() {
+			dirPaths = append(dirPaths, dirPath)
+		}
+	}
+	mod.pending = make(map[string]*time.Timer)
+	mod.mu.Unlock()
+
+	var err error
+	for _, dirPath := range dirPaths {
+		err = multierr.Append(err, mod.remove(dirPath))
+	}
+
+	return err
+}
+
+// RemoveScratch removes a working directory, either immediately or after the
+// retention delay.
+func (mod *Retention) RemoveScratch(dirPath string) error {
+	if mod.delay == 0 {
+		return mod.remove(dirPath)
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	mod.pending[dirPath] = time.AfterFunc(mod.delay, func() {
+		mod.mu.Lock()
+		delete(mod.pending, dirPath)
+		mod.mu.Unlock()
+
+		err := mod.remove(dirPath)
+		if err != nil {
+			mod.logger.Error(fmt.Sprintf("remove working directory: %s", err))
+		}
+	})
+
+	mod.logger.Debug(fmt.Sprintf("'%s' working directory removed in %s", dirPath, mod.delay))
+
+	return nil
+}
+
+// remove removes a working directory and updates the metrics.
+func (mod *Retention) remove(dirPath string) error {
+	n, err := removeAll(dirPath, mod.secureDelete)
+	if err != nil {
+		mod.failures.Add(1)
+
+		return fmt.Errorf("remove '%s': %w", dirPath, err)
+	}
+
+	mod.removed.Add(1)
+	mod.bytes.Add(n)
+
+	return nil
+}
+
+// Metrics returns the metrics.
+func (mod *Retention) Metrics() ([]gotenberg.Metric, error) {
+	return []gotenberg.Metric{
+		{
+			Name:        "retention_removed_dirs_total",
+			Description: "Total number of removed working directories.",
+			Counter:     true,
+			Read: func() float64 {
+				return float64(mod.removed.Load())
+			},
+		},
+		{
+			Name:        "retention_removed_bytes_total",
+			Description: "Total number of bytes of the removed working directories.",
+			Counter:     true,
+			Read: func() float64 {
+				return float64(mod.bytes.Load())
+			},
+		},
+		{
+			Name:        "retention_failures_total",
+			Description: "Total number of working directories which removal failed.",
+			Counter:     true,
+			Read: func() float64 {
+				return float64(mod.failures.Load())
+			},
+		},
+		{
+			Name:        "retention_pending_dirs",
+			Description: "Current number of working directories waiting for their removal.",
+			Read: func() float64 {
+				mod.mu.Lock()
+				defer mod.mu.Unlock()
+
+				return float64(len(mod.pending))
+			},
+		},
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module          = (*Retention)(nil)
+	_ gotenberg.Provisioner     = (*Retention)(nil)
+	_ gotenberg.Validator       = (*Retention)(nil)
+	_ gotenberg.App             = (*Retention)(nil)
+	_ gotenberg.MetricsProvider = (*Retention)(nil)
+	_ api.ScratchRemover        = (*Retention)(nil)
+)
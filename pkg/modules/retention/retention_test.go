@@ -0,0 +1,138 @@
+package retention
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestRetention_Descriptor(t *testing.T) {
+	descriptor := new(Retention).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Retention))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestRetention_Validate(t *testing.T) {
+	err := (&Retention{delay: -1}).Validate()
+	if err == nil {
+		t.Error("expected error but got none")
+	}
+
+	err = (&Retention{delay: 0}).Validate()
+	if err != nil {
+		t.Errorf("expected no error but got: %v", err)
+	}
+}
+
+func TestRetention_RemoveScratch(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		delay        time.Duration
+		secureDelete bool
+		stop         bool
+	}{
+		{
+			scenario: "immediately",
+		},
+		{
+			scenario:     "immediately and securely",
+			secureDelete: true,
+		},
+		{
+			scenario: "after the delay",
+			delay:    time.Duration(50) * time.Millisecond,
+		},
+		{
+			scenario: "on stop",
+			delay:    time.Duration(1) * time.Hour,
+			stop:     true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Retention{
+				delay:        tc.delay,
+				secureDelete: tc.secureDelete,
+				logger:       zap.NewNop(),
+				pending:      make(map[string]*time.Timer),
+			}
+
+			dirPath := filepath.Join(t.TempDir(), "foo")
+			err := os.MkdirAll(dirPath, 0o755)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = os.WriteFile(filepath.Join(dirPath, "foo.pdf"), []byte("foo"), 0o600)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = mod.RemoveScratch(dirPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.delay > 0 {
+				_, err = os.Stat(dirPath)
+				if err != nil {
+					t.Fatalf("expected working directory to be kept but got: %v", err)
+				}
+
+				if tc.stop {
+					err = mod.Stop(context.Background())
+					if err != nil {
+						t.Fatalf("expected no error but got: %v", err)
+					}
+				} else {
+					time.Sleep(tc.delay * 4)
+				}
+			}
+
+			_, err = os.Stat(dirPath)
+			if !os.IsNotExist(err) {
+				t.Fatalf("expected working directory to be removed but got: %v", err)
+			}
+
+			if mod.removed.Load() != 1 {
+				t.Errorf("expected 1 removed directory but got %d", mod.removed.Load())
+			}
+
+			if mod.bytes.Load() != 3 {
+				t.Errorf("expected 3 removed bytes but got %d", mod.bytes.Load())
+			}
+		})
+	}
+}
+
+func TestOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.pdf")
+
+	err := os.WriteFile(path, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = overwrite(path, 3)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !reflect.DeepEqual(b, []byte{0, 0, 0}) {
+		t.Errorf("expected zeros but got %v", b)
+	}
+}
@@ -0,0 +1,205 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCron happens if a cron expression is invalid.
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// cronMacros are the shorthands of the common cron expressions.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField is the range of the values of a field of a cron expression.
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12},
+	{name: "day of week", min: 0, max: 6},
+}
+
+// maxCronLookahead bounds the search of the next time of a cron expression
+// which never matches, e.g., February 30.
+const maxCronLookahead = 5 * 366 * 24 * time.Hour
+
+// cron is a parsed cron expression with the five standard fields: minute,
+// hour, day of month, month and day of week.
+type cron struct {
+	minutes, hours, days, months, weekdays uint64
+
+	// As with the standard cron, if both the day of month and the day of
+	// week are restricted, a day matching either of them matches.
+	daysRestricted, weekdaysRestricted bool
+}
+
+// parseCron parses a cron expression with five fields, or one of the
+// "@hourly", "@daily", "@weekly", "@monthly" and "@yearly" macros. A field
+// is either "*", a value, a range "a-b", a list "a,b", and any of them but a
+// value with a step "/n". Sunday is either 0 or 7.
+func parseCron(expr string) (cron, error) {
+	expr = strings.TrimSpace(expr)
+
+	macro, ok := cronMacros[strings.ToLower(expr)]
+	if ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return cron{}, fmt.Errorf("expected %d fields but got %d: %w", len(cronFields), len(fields), ErrInvalidCron)
+	}
+
+	bits := make([]uint64, len(fields))
+
+	for i, field := range fields {
+		f := cronFields[i]
+		if i == 4 {
+			// Sunday as 7.
+			f.max = 7
+		}
+
+		var err error
+		bits[i], err = parseCronField(field, f)
+		if err != nil {
+			return cron{}, fmt.Errorf("%s '%s': %v: %w", f.name, field, err, ErrInvalidCron)
+		}
+	}
+
+	// Sunday as 7 is Sunday as 0.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return cron{
+		minutes:            bits[0],
+		hours:              bits[1],
+		days:               bits[2],
+		months:             bits[3],
+		weekdays:           bits[4],
+		daysRestricted:     !strings.HasPrefix(fields[2], "*"),
+		weekdaysRestricted: !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField returns the bit set of the values of a field.
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("wrong step '%s'", stepPart)
+			}
+		}
+
+		low, high := f.min, f.max
+
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+
+			var err error
+			low, err = strconv.Atoi(lowPart)
+			if err != nil {
+				return 0, fmt.Errorf("wrong value '%s'", lowPart)
+			}
+
+			high, err = strconv.Atoi(highPart)
+			if err != nil {
+				return 0, fmt.Errorf("wrong value '%s'", highPart)
+			}
+		default:
+			if hasStep {
+				return 0, fmt.Errorf("step without range in '%s'", part)
+			}
+
+			var err error
+			low, err = strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("wrong value '%s'", rangePart)
+			}
+
+			high = low
+		}
+
+		if low < f.min || high > f.max || low > high {
+			return 0, fmt.Errorf("'%s' out of range %d-%d", rangePart, f.min, f.max)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, nil
+}
+
+// next returns the first time strictly after the given time which matches
+// the expression, in the location of the given time. It returns the zero
+// time if there is none.
+func (c cron) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxCronLookahead)
+
+	for t.Before(limit) {
+		if c.months&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if c.hours&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if c.minutes&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay tells if the day of the given time matches the expression.
+func (c cron) matchDay(t time.Time) bool {
+	day := c.days&(1<<uint(t.Day())) != 0
+	weekday := c.weekdays&(1<<uint(t.Weekday())) != 0
+
+	if c.daysRestricted && c.weekdaysRestricted {
+		return day || weekday
+	}
+
+	return day && weekday
+}
@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		expr        string
+		expectError bool
+	}{
+		{scenario: "every minute", expr: "* * * * *"},
+		{scenario: "macro", expr: "@Hourly"},
+		{scenario: "lists, ranges and steps", expr: "0,30 8-18/2 */3 1-6 1-5"},
+		{scenario: "Sunday as 7", expr: "0 0 * * 7"},
+		{scenario: "too few fields", expr: "* * * *", expectError: true},
+		{scenario: "too many fields", expr: "* * * * * *", expectError: true},
+		{scenario: "out of range", expr: "60 * * * *", expectError: true},
+		{scenario: "inverted range", expr: "* 10-8 * * *", expectError: true},
+		{scenario: "wrong value", expr: "* * foo * *", expectError: true},
+		{scenario: "wrong step", expr: "*/0 * * * *", expectError: true},
+		{scenario: "step without range", expr: "5/10 * * * *", expectError: true},
+		{scenario: "unknown macro", expr: "@foo", expectError: true},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			_, err := parseCron(tc.expr)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && !errors.Is(err, ErrInvalidCron) {
+				t.Fatalf("expected error %v but got: %v", ErrInvalidCron, err)
+			}
+		})
+	}
+}
+
+func TestCron_Next(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2024, time.January, 31, 10, 15, 30, 0, time.UTC)
+
+	for _, tc := range []struct {
+		scenario string
+		expr     string
+		expect   time.Time
+	}{
+		{
+			scenario: "every minute",
+			expr:     "* * * * *",
+			expect:   time.Date(2024, time.January, 31, 10, 16, 0, 0, time.UTC),
+		},
+		{
+			scenario: "hourly",
+			expr:     "@hourly",
+			expect:   time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			scenario: "every 20 minutes",
+			expr:     "*/20 * * * *",
+			expect:   time.Date(2024, time.January, 31, 10, 20, 0, 0, time.UTC),
+		},
+		{
+			scenario: "next day",
+			expr:     "0 9 * * *",
+			expect:   time.Date(2024, time.February, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			scenario: "next month, leap day",
+			expr:     "0 0 29 2 *",
+			expect:   time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			scenario: "Sunday as 7",
+			expr:     "30 6 * * 7",
+			expect:   time.Date(2024, time.February, 4, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			scenario: "either day of month or day of week",
+			expr:     "0 0 15 * 5",
+			expect:   time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			scenario: "never",
+			expr:     "0 0 30 2 *",
+			expect:   time.Time{},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			c, err := parseCron(tc.expr)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			actual := c.next(now)
+			if !actual.Equal(tc.expect) {
+				t.Errorf("expected %s but got %s", tc.expect, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,4 @@
+// Package scheduler provides a module which runs stored conversions at the
+// times of their cron expressions, e.g., converting a dashboard URL to PDF
+// every hour and uploading it with a webhook. An admin API manages them.
+package scheduler
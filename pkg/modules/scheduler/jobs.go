@@ -0,0 +1,298 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+var (
+	// ErrInvalidJob happens if the description of a job is invalid.
+	ErrInvalidJob = errors.New("invalid job")
+
+	// ErrJobNotFound happens if there is no job with a given ID.
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrJobAlreadyExists happens when creating a job with the ID of another
+	// one.
+	ErrJobAlreadyExists = errors.New("job already exists")
+)
+
+// jobIdRegexp restricts the IDs of the jobs, as they are path parameters of
+// the admin API.
+var jobIdRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// maxErrorBodySize is the number of bytes of an error response kept as the
+// error of a run.
+const maxErrorBodySize = 4096
+
+// Job is a stored conversion which runs at the times of its schedule. Its
+// result goes wherever the request sends it, e.g., a webhook or the upload
+// step of a pipeline.
+type Job struct {
+	// ID identifies the job.
+	ID string `json:"id"`
+
+	// Schedule is the cron expression of the job, e.g., "0 * * * *" for
+	// every hour.
+	Schedule string `json:"schedule"`
+
+	// Timezone is the location of the schedule, e.g., "Europe/Paris".
+	// Default to UTC.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Path is the path of the multipart route to call, e.g.,
+	// "/forms/chromium/convert/url".
+	Path string `json:"path"`
+
+	// Header is the header of the request, e.g., the webhook headers.
+	Header map[string]string `json:"header,omitempty"`
+
+	// Values are the form fields of the request.
+	Values map[string][]string `json:"values,omitempty"`
+
+	// Disabled pauses the job.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Run is the outcome of a run of a job.
+type Run struct {
+	Time     time.Time `json:"time"`
+	Status   int       `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Duration string    `json:"duration"`
+}
+
+// JobStatus is a job with its next and last runs.
+type JobStatus struct {
+	Job
+	NextRun *time.Time `json:"nextRun,omitempty"`
+	LastRun *Run       `json:"lastRun,omitempty"`
+	Running bool       `json:"running"`
+}
+
+// entry is a job with its parsed schedule and its runs.
+type entry struct {
+	job      Job
+	cron     cron
+	location *time.Location
+	next     time.Time
+	last     *Run
+	running  bool
+}
+
+// newEntry validates a job and returns its [entry], with its next run after
+// the given time.
+func newEntry(job Job, now time.Time) (*entry, error) {
+	if !jobIdRegexp.MatchString(job.ID) {
+		return nil, fmt.Errorf("wrong ID '%s', expected letters, digits, '.', '_' or '-': %w", job.ID, ErrInvalidJob)
+	}
+
+	c, err := parseCron(job.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("parse schedule: %v: %w", err, ErrInvalidJob)
+	}
+
+	location := time.UTC
+	if job.Timezone != "" {
+		location, err = time.LoadLocation(job.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("wrong timezone '%s': %v: %w", job.Timezone, err, ErrInvalidJob)
+		}
+	}
+
+	// The jobs may only convert, not manage Gotenberg.
+	if !strings.HasPrefix(job.Path, "/forms/") {
+		return nil, fmt.Errorf("wrong path '%s', expected a route starting with '/forms/': %w", job.Path, ErrInvalidJob)
+	}
+
+	e := &entry{
+		job:      job,
+		cron:     c,
+		location: location,
+	}
+	e.schedule(now)
+
+	return e, nil
+}
+
+// schedule sets the next run after the given time.
+func (e *entry) schedule(now time.Time) {
+	e.next = time.Time{}
+	if e.job.Disabled {
+		return
+	}
+
+	e.next = e.cron.next(now.In(e.location))
+}
+
+// status returns the [JobStatus] of the entry.
+func (e *entry) status() JobStatus {
+	status := JobStatus{
+		Job:     e.job,
+		LastRun: e.last,
+		Running: e.running,
+	}
+
+	if !e.next.IsZero() {
+		next := e.next
+		status.NextRun = &next
+	}
+
+	return status
+}
+
+// loadJobs reads the jobs from a JSON file. A missing file means no job.
+func loadJobs(path string) ([]Job, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("read jobs file: %w", err)
+	}
+
+	var jobs []Job
+
+	err = json.Unmarshal(b, &jobs)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal jobs file: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// saveJobs writes the jobs to a JSON file, sorted by ID. The file is
+// replaced atomically, so that a crash does not lose the jobs.
+func saveJobs(path string, jobs []Job) error {
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	b, err := json.MarshalIndent(jobs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal jobs: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return fmt.Errorf("create jobs directory: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+
+	err = os.WriteFile(tmpPath, b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write jobs file: %w", err)
+	}
+
+	err = os.Rename(tmpPath, path)
+	if err != nil {
+		return fmt.Errorf("replace jobs file: %w", err)
+	}
+
+	return nil
+}
+
+// runner sends the requests of the jobs to Gotenberg.
+type runner struct {
+	url    string
+	client *http.Client
+}
+
+// run sends the request of a job and returns its outcome.
+func (r runner) run(ctx context.Context, job Job) Run {
+	startTime := time.Now()
+	outcome := Run{Time: startTime.UTC()}
+
+	status, err := r.send(ctx, job)
+	outcome.Status = status
+	outcome.Duration = time.Since(startTime).Round(time.Millisecond).String()
+
+	if err != nil {
+		outcome.Error = err.Error()
+	}
+
+	return outcome
+}
+
+// send sends the request of a job with its form fields. An error status is
+// an error with the message of the response.
+func (r runner) send(ctx context.Context, job Job) (int, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	keys := make([]string, 0, len(job.Values))
+	for key := range job.Values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range job.Values[key] {
+			err := writer.WriteField(key, value)
+			if err != nil {
+				return 0, fmt.Errorf("write form field '%s': %w", key, err)
+			}
+		}
+	}
+
+	err := writer.Close()
+	if err != nil {
+		return 0, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(r.url, "/")+job.Path, &body)
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+
+	for key, value := range job.Header {
+		req.Header.Set(key, value)
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("User-Agent", "Gotenberg")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("send request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusBadRequest {
+		// The output goes wherever the request sends it.
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		return resp.StatusCode, nil
+	}
+
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var response api.ErrorResponse
+
+	err = json.Unmarshal(b, &response)
+	if err == nil && response.Message != "" {
+		return resp.StatusCode, fmt.Errorf("got status %d: %s", resp.StatusCode, response.Message)
+	}
+
+	return resp.StatusCode, fmt.Errorf("got status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+}
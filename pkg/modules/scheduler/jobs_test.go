@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewEntry(t *testing.T) {
+	now := time.Date(2024, time.January, 31, 10, 15, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		scenario    string
+		job         Job
+		expectNext  time.Time
+		expectError bool
+	}{
+		{
+			scenario:    "wrong ID",
+			job:         Job{ID: "foo/bar", Schedule: "@hourly", Path: "/forms/chromium/convert/url"},
+			expectError: true,
+		},
+		{
+			scenario:    "wrong schedule",
+			job:         Job{ID: "foo", Schedule: "foo", Path: "/forms/chromium/convert/url"},
+			expectError: true,
+		},
+		{
+			scenario:    "wrong timezone",
+			job:         Job{ID: "foo", Schedule: "@hourly", Timezone: "Foo/Bar", Path: "/forms/chromium/convert/url"},
+			expectError: true,
+		},
+		{
+			scenario:    "not a conversion route",
+			job:         Job{ID: "foo", Schedule: "@hourly", Path: "/admin/scheduler/jobs"},
+			expectError: true,
+		},
+		{
+			scenario:   "disabled",
+			job:        Job{ID: "foo", Schedule: "@hourly", Path: "/forms/chromium/convert/url", Disabled: true},
+			expectNext: time.Time{},
+		},
+		{
+			scenario:   "UTC",
+			job:        Job{ID: "foo", Schedule: "0 12 * * *", Path: "/forms/chromium/convert/url"},
+			expectNext: time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			scenario:   "timezone",
+			job:        Job{ID: "foo", Schedule: "0 12 * * *", Timezone: "Etc/GMT-1", Path: "/forms/chromium/convert/url"},
+			expectNext: time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			e, err := newEntry(tc.job, now)
+
+			if tc.expectError {
+				if !errors.Is(err, ErrInvalidJob) {
+					t.Fatalf("expected error %v but got: %v", ErrInvalidJob, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if !e.next.Equal(tc.expectNext) {
+				t.Errorf("expected next run %s but got %s", tc.expectNext, e.next)
+			}
+
+			status := e.status()
+			if tc.expectNext.IsZero() != (status.NextRun == nil) {
+				t.Errorf("expected next run in status to be %s but got %v", tc.expectNext, status.NextRun)
+			}
+		})
+	}
+}
+
+func TestSaveJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheduler", "jobs.json")
+
+	jobs, err := loadJobs(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(jobs) != 0 {
+		t.Fatalf("expected no job without file but got %d", len(jobs))
+	}
+
+	expect := []Job{
+		{ID: "bar", Schedule: "@daily", Path: "/forms/chromium/convert/url", Values: map[string][]string{"url": {"https://example.com"}}},
+		{ID: "foo", Schedule: "@hourly", Path: "/forms/libreoffice/convert", Header: map[string]string{"Gotenberg-Webhook-Url": "https://example.com"}},
+	}
+
+	err = saveJobs(path, []Job{expect[1], expect[0]})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	jobs, err = loadJobs(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !reflect.DeepEqual(jobs, expect) {
+		t.Errorf("expected %+v but got %+v", expect, jobs)
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/forms/chromium/convert/url":
+			if r.FormValue("url") != "https://example.com" {
+				t.Errorf("expected form field 'url' but got '%s'", r.FormValue("url"))
+			}
+
+			if r.Header.Get("Gotenberg-Webhook-Url") != "https://webhook" {
+				t.Errorf("expected the webhook header but got '%s'", r.Header.Get("Gotenberg-Webhook-Url"))
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		case "/forms/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"BAD_REQUEST","status":400,"message":"Invalid form data"}`))
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("foo"))
+		}
+	}))
+	defer srv.Close()
+
+	r := runner{url: srv.URL + "/", client: srv.Client()}
+
+	for _, tc := range []struct {
+		scenario     string
+		job          Job
+		expectStatus int
+		expectError  string
+	}{
+		{
+			scenario: "success",
+			job: Job{
+				Path:   "/forms/chromium/convert/url",
+				Header: map[string]string{"Gotenberg-Webhook-Url": "https://webhook"},
+				Values: map[string][]string{"url": {"https://example.com"}},
+			},
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			scenario:     "JSON error",
+			job:          Job{Path: "/forms/json"},
+			expectStatus: http.StatusBadRequest,
+			expectError:  "got status 400: Invalid form data",
+		},
+		{
+			scenario:     "text error",
+			job:          Job{Path: "/forms/foo"},
+			expectStatus: http.StatusServiceUnavailable,
+			expectError:  "got status 503: foo",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			outcome := r.run(context.Background(), tc.job)
+
+			if outcome.Status != tc.expectStatus {
+				t.Errorf("expected status %d but got %d", tc.expectStatus, outcome.Status)
+			}
+
+			if !strings.Contains(outcome.Error, tc.expectError) || (tc.expectError == "" && outcome.Error != "") {
+				t.Errorf("expected error '%s' but got '%s'", tc.expectError, outcome.Error)
+			}
+
+			if outcome.Time.IsZero() || outcome.Duration == "" {
+				t.Errorf("expected the time and the duration of the run but got %+v", outcome)
+			}
+		})
+	}
+
+	outcome := runner{url: "http://127.0.0.1:0", client: srv.Client()}.run(context.Background(), Job{Path: "/forms/foo"})
+	if outcome.Error == "" {
+		t.Error("expected an error without server")
+	}
+}
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// Routes returns the admin routes for managing the jobs.
+func (mod *Scheduler) Routes() ([]api.Route, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/admin/scheduler/jobs",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, mod.jobs())
+			},
+		},
+		{
+			Method:         http.MethodPost,
+			Path:           "/admin/scheduler/jobs",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				job, err := bindJob(c)
+				if err != nil {
+					return err
+				}
+
+				status, err := mod.put(job, true)
+				if err != nil {
+					return jobError(fmt.Errorf("create job: %w", err))
+				}
+
+				return c.JSON(http.StatusCreated, status)
+			},
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/admin/scheduler/jobs/:id",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				status, err := mod.job(c.Param("id"))
+				if err != nil {
+					return jobError(err)
+				}
+
+				return c.JSON(http.StatusOK, status)
+			},
+		},
+		{
+			Method:         http.MethodPut,
+			Path:           "/admin/scheduler/jobs/:id",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				job, err := bindJob(c)
+				if err != nil {
+					return err
+				}
+
+				// The path is the reference.
+				job.ID = c.Param("id")
+
+				status, err := mod.put(job, false)
+				if err != nil {
+					return jobError(fmt.Errorf("put job: %w", err))
+				}
+
+				return c.JSON(http.StatusOK, status)
+			},
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/admin/scheduler/jobs/:id",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				err := mod.delete(c.Param("id"))
+				if err != nil {
+					return jobError(fmt.Errorf("delete job: %w", err))
+				}
+
+				return c.NoContent(http.StatusNoContent)
+			},
+		},
+		{
+			Method:         http.MethodPost,
+			Path:           "/admin/scheduler/jobs/:id/run",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				status, err := mod.trigger(c.Param("id"))
+				if err != nil {
+					return jobError(fmt.Errorf("run job: %w", err))
+				}
+
+				return c.JSON(http.StatusAccepted, status)
+			},
+		},
+	}, nil
+}
+
+// bindJob decodes the JSON body of a request as a [Job].
+func bindJob(c echo.Context) (Job, error) {
+	decoder := json.NewDecoder(c.Request().Body)
+	decoder.DisallowUnknownFields()
+
+	var job Job
+
+	err := decoder.Decode(&job)
+	if err != nil {
+		return Job{}, api.WrapError(
+			fmt.Errorf("decode job: %w", err),
+			api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid job: %s", err)).WithCode(api.ErrorCodeSchedulerInvalidJob),
+		)
+	}
+
+	return job, nil
+}
+
+// jobError wraps the errors of the admin API the client is responsible for,
+// so that they have a meaningful HTTP status.
+func jobError(err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidJob):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid job: %s", err)).WithCode(api.ErrorCodeSchedulerInvalidJob),
+		)
+	case errors.Is(err, ErrJobNotFound):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusNotFound, "Job not found").WithCode(api.ErrorCodeSchedulerJobNotFound),
+		)
+	case errors.Is(err, ErrJobAlreadyExists):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusConflict, "A job with this ID already exists").WithCode(api.ErrorCodeSchedulerJobAlreadyExists),
+		)
+	}
+
+	return err
+}
@@ -0,0 +1,181 @@
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestScheduler_Routes(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		routes, err := new(Scheduler).Routes()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(routes) != 0 {
+			t.Errorf("expected no route but got %d", len(routes))
+		}
+	})
+
+	mod := &Scheduler{
+		enable:  true,
+		entries: make(map[string]*entry),
+	}
+
+	routes, err := mod.Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 6 {
+		t.Fatalf("expected 6 routes but got %d", len(routes))
+	}
+
+	for _, route := range routes {
+		if !route.IsAdmin {
+			t.Errorf("expected '%s %s' to be an admin route", route.Method, route.Path)
+		}
+	}
+
+	call := func(method, path, id, body string) (int, string) {
+		for _, route := range routes {
+			if route.Method != method || route.Path != path {
+				continue
+			}
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(method, path, strings.NewReader(body)), rec)
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+
+			err := route.Handler(c)
+			if err != nil {
+				response := api.ParseErrorResponse(err)
+
+				return response.Status, response.Code
+			}
+
+			return rec.Code, rec.Body.String()
+		}
+
+		t.Fatalf("expected a route '%s %s'", method, path)
+
+		return 0, ""
+	}
+
+	for _, tc := range []struct {
+		scenario     string
+		method       string
+		path         string
+		id           string
+		body         string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			scenario:     "invalid JSON",
+			method:       http.MethodPost,
+			path:         "/admin/scheduler/jobs",
+			body:         `{"foo":"bar"}`,
+			expectStatus: http.StatusBadRequest,
+			expectBody:   "SCHEDULER_INVALID_JOB",
+		},
+		{
+			scenario:     "invalid job",
+			method:       http.MethodPost,
+			path:         "/admin/scheduler/jobs",
+			body:         `{"id":"foo","schedule":"@hourly","path":"/admin/usage"}`,
+			expectStatus: http.StatusBadRequest,
+			expectBody:   "SCHEDULER_INVALID_JOB",
+		},
+		{
+			scenario:     "create job",
+			method:       http.MethodPost,
+			path:         "/admin/scheduler/jobs",
+			body:         `{"id":"foo","schedule":"@hourly","path":"/forms/chromium/convert/url","disabled":true}`,
+			expectStatus: http.StatusCreated,
+			expectBody:   `"id":"foo"`,
+		},
+		{
+			scenario:     "job already exists",
+			method:       http.MethodPost,
+			path:         "/admin/scheduler/jobs",
+			body:         `{"id":"foo","schedule":"@hourly","path":"/forms/chromium/convert/url"}`,
+			expectStatus: http.StatusConflict,
+			expectBody:   "SCHEDULER_JOB_ALREADY_EXISTS",
+		},
+		{
+			scenario:     "replace job",
+			method:       http.MethodPut,
+			path:         "/admin/scheduler/jobs/:id",
+			id:           "foo",
+			body:         `{"id":"bar","schedule":"@daily","path":"/forms/chromium/convert/url","disabled":true}`,
+			expectStatus: http.StatusOK,
+			expectBody:   `"id":"foo","schedule":"@daily"`,
+		},
+		{
+			scenario:     "list jobs",
+			method:       http.MethodGet,
+			path:         "/admin/scheduler/jobs",
+			expectStatus: http.StatusOK,
+			expectBody:   `[{"id":"foo"`,
+		},
+		{
+			scenario:     "get job",
+			method:       http.MethodGet,
+			path:         "/admin/scheduler/jobs/:id",
+			id:           "foo",
+			expectStatus: http.StatusOK,
+			expectBody:   `"running":false`,
+		},
+		{
+			scenario:     "get unknown job",
+			method:       http.MethodGet,
+			path:         "/admin/scheduler/jobs/:id",
+			id:           "bar",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "SCHEDULER_JOB_NOT_FOUND",
+		},
+		{
+			scenario:     "run unknown job",
+			method:       http.MethodPost,
+			path:         "/admin/scheduler/jobs/:id/run",
+			id:           "bar",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "SCHEDULER_JOB_NOT_FOUND",
+		},
+		{
+			scenario:     "delete job",
+			method:       http.MethodDelete,
+			path:         "/admin/scheduler/jobs/:id",
+			id:           "foo",
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			scenario:     "delete unknown job",
+			method:       http.MethodDelete,
+			path:         "/admin/scheduler/jobs/:id",
+			id:           "foo",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "SCHEDULER_JOB_NOT_FOUND",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			status, body := call(tc.method, tc.path, tc.id, tc.body)
+
+			if status != tc.expectStatus {
+				t.Errorf("expected status %d but got %d", tc.expectStatus, status)
+			}
+
+			if !strings.Contains(body, tc.expectBody) {
+				t.Errorf("expected body to contain '%s' but got '%s'", tc.expectBody, body)
+			}
+		})
+	}
+}
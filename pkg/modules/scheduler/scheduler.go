@@ -0,0 +1,381 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Scheduler))
+}
+
+// tickInterval is the interval at which the scheduler looks for the jobs to
+// run. The schedules have a one-minute precision.
+const tickInterval = time.Duration(1) * time.Second
+
+// Scheduler is a module which runs stored conversions at the times of their
+// cron expressions, and adds an admin API for managing them.
+type Scheduler struct {
+	enable              bool
+	file                string
+	url                 string
+	timeout             time.Duration
+	disableRouteLogging bool
+
+	runner  runner
+	logger  *zap.Logger
+	entries map[string]*entry
+	mu      sync.Mutex
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// Descriptor returns a [Scheduler]'s module descriptor.
+func (mod *Scheduler) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "scheduler",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("scheduler", flag.ExitOnError)
+			fs.Bool("scheduler-enable", false, "Enable the scheduled conversions and their admin routes - the latter require the API admin token")
+			fs.String("scheduler-file", filepath.Join(os.TempDir(), "gotenberg-scheduler", "jobs.json"), "Set the JSON file in which to persist the scheduled conversions - empty keeps them in memory")
+			fs.String("scheduler-url", "http://localhost:3000", "Set the base URL of the Gotenberg instance which runs the scheduled conversions, with the root path of its API")
+			fs.Duration("scheduler-timeout", time.Duration(1)*time.Minute, "Set the time limit of each scheduled conversion")
+			fs.Bool("scheduler-disable-route-logging", false, "Disable the route logging")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Scheduler) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Scheduler) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("scheduler-enable")
+	mod.file = flags.MustString("scheduler-file")
+	mod.url = flags.MustString("scheduler-url")
+	mod.timeout = flags.MustDuration("scheduler-timeout")
+	mod.disableRouteLogging = flags.MustBool("scheduler-disable-route-logging")
+
+	mod.entries = make(map[string]*entry)
+
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	mod.runner = runner{
+		url:    mod.url,
+		client: &http.Client{Timeout: mod.timeout},
+	}
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Scheduler) Validate() error {
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.url == "" {
+		err = multierr.Append(err,
+			errors.New("URL must not be empty"),
+		)
+	}
+
+	if mod.timeout <= 0 {
+		err = multierr.Append(err,
+			errors.New("timeout must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Start loads the persisted jobs and runs them at the times of their
+// schedules.
+func (mod *Scheduler) Start() error {
+	if !mod.enable {
+		return nil
+	}
+
+	if mod.file != "" {
+		jobs, err := loadJobs(mod.file)
+		if err != nil {
+			return fmt.Errorf("load jobs: %w", err)
+		}
+
+		now := time.Now()
+		for _, job := range jobs {
+			e, err := newEntry(job, now)
+			if err != nil {
+				return fmt.Errorf("load job '%s': %w", job.ID, err)
+			}
+
+			mod.entries[job.ID] = e
+		}
+	}
+
+	mod.stop = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-mod.stop:
+				return
+			case now := <-ticker.C:
+				mod.tick(now)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Scheduler) StartupMessage() string {
+	if !mod.enable {
+		return "scheduler disabled"
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	return fmt.Sprintf("%d scheduled conversion(s) sent to '%s'", len(mod.entries), mod.url)
+}
+
+// Stop stops scheduling the jobs and waits for the running ones.
+func (mod *Scheduler) Stop(ctx context.Context) error {
+	if mod.stop == nil {
+		return nil
+	}
+
+	close(mod.stop)
+
+	done := make(chan struct{})
+	go func() {
+		mod.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for the running jobs: %w", ctx.Err())
+	}
+}
+
+// tick runs the jobs which are due at the given time, unless they are still
+// running.
+func (mod *Scheduler) tick(now time.Time) {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	for _, e := range mod.entries {
+		if e.running || e.next.IsZero() || now.Before(e.next) {
+			continue
+		}
+
+		e.schedule(now)
+		mod.runLocked(e)
+	}
+}
+
+// runLocked runs a job in the background. The caller holds the lock.
+func (mod *Scheduler) runLocked(e *entry) {
+	e.running = true
+	job := e.job
+
+	mod.wg.Add(1)
+
+	go func() {
+		defer mod.wg.Done()
+
+		mod.logger.Debug(fmt.Sprintf("run job '%s'", job.ID))
+
+		outcome := mod.runner.run(context.Background(), job)
+		if outcome.Error != "" {
+			mod.logger.Error(fmt.Sprintf("job '%s' failed: %s", job.ID, outcome.Error))
+		} else {
+			mod.logger.Info(fmt.Sprintf("job '%s' handled with status %d in %s", job.ID, outcome.Status, outcome.Duration))
+		}
+
+		mod.mu.Lock()
+		defer mod.mu.Unlock()
+
+		// The job may have been replaced or deleted meanwhile.
+		current, ok := mod.entries[job.ID]
+		if ok {
+			current.running = false
+			current.last = &outcome
+		}
+	}()
+}
+
+// jobs returns the status of all the jobs, sorted by ID.
+func (mod *Scheduler) jobs() []JobStatus {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(mod.entries))
+	for _, e := range mod.entries {
+		statuses = append(statuses, e.status())
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ID < statuses[j].ID
+	})
+
+	return statuses
+}
+
+// job returns the status of a job.
+func (mod *Scheduler) job(id string) (JobStatus, error) {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	e, ok := mod.entries[id]
+	if !ok {
+		return JobStatus{}, fmt.Errorf("job '%s': %w", id, ErrJobNotFound)
+	}
+
+	return e.status(), nil
+}
+
+// put creates or replaces a job, unless the job must not exist yet. It keeps
+// the last run of a replaced job.
+func (mod *Scheduler) put(job Job, create bool) (JobStatus, error) {
+	e, err := newEntry(job, time.Now())
+	if err != nil {
+		return JobStatus{}, err
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	previous, ok := mod.entries[job.ID]
+	if ok && create {
+		return JobStatus{}, fmt.Errorf("job '%s': %w", job.ID, ErrJobAlreadyExists)
+	}
+
+	if ok {
+		e.last = previous.last
+		e.running = previous.running
+	}
+
+	mod.entries[job.ID] = e
+
+	err = mod.saveLocked()
+	if err != nil {
+		if ok {
+			mod.entries[job.ID] = previous
+		} else {
+			delete(mod.entries, job.ID)
+		}
+
+		return JobStatus{}, err
+	}
+
+	return e.status(), nil
+}
+
+// delete removes a job. A running job still completes.
+func (mod *Scheduler) delete(id string) error {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	previous, ok := mod.entries[id]
+	if !ok {
+		return fmt.Errorf("job '%s': %w", id, ErrJobNotFound)
+	}
+
+	delete(mod.entries, id)
+
+	err := mod.saveLocked()
+	if err != nil {
+		mod.entries[id] = previous
+
+		return err
+	}
+
+	return nil
+}
+
+// trigger runs a job now, regardless of its schedule.
+func (mod *Scheduler) trigger(id string) (JobStatus, error) {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	e, ok := mod.entries[id]
+	if !ok {
+		return JobStatus{}, fmt.Errorf("job '%s': %w", id, ErrJobNotFound)
+	}
+
+	if !e.running {
+		mod.runLocked(e)
+	}
+
+	return e.status(), nil
+}
+
+// saveLocked persists the jobs, if a file is set. The caller holds the lock.
+func (mod *Scheduler) saveLocked() error {
+	if mod.file == "" {
+		return nil
+	}
+
+	jobs := make([]Job, 0, len(mod.entries))
+	for _, e := range mod.entries {
+		jobs = append(jobs, e.job)
+	}
+
+	err := saveJobs(mod.file, jobs)
+	if err != nil {
+		return fmt.Errorf("save jobs: %w", err)
+	}
+
+	return nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Scheduler)(nil)
+	_ gotenberg.Provisioner = (*Scheduler)(nil)
+	_ gotenberg.Validator   = (*Scheduler)(nil)
+	_ gotenberg.App         = (*Scheduler)(nil)
+	_ api.Router            = (*Scheduler)(nil)
+)
@@ -0,0 +1,260 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestScheduler_Descriptor(t *testing.T) {
+	descriptor := new(Scheduler).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Scheduler))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestScheduler_Provision(t *testing.T) {
+	mod := new(Scheduler)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Scheduler).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enable {
+		t.Error("expected scheduler to be disabled by default")
+	}
+
+	if mod.entries == nil {
+		t.Error("expected the jobs to be initialized")
+	}
+}
+
+func TestScheduler_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		enable      bool
+		url         string
+		timeout     time.Duration
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+		},
+		{
+			scenario:    "empty URL",
+			enable:      true,
+			timeout:     time.Duration(1) * time.Minute,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid timeout",
+			enable:      true,
+			url:         "http://localhost:3000",
+			expectError: true,
+		},
+		{
+			scenario: "validate success",
+			enable:   true,
+			url:      "http://localhost:3000",
+			timeout:  time.Duration(1) * time.Minute,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Scheduler{
+				enable:  tc.enable,
+				url:     tc.url,
+				timeout: tc.timeout,
+			}
+
+			err := mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestScheduler_put(t *testing.T) {
+	mod := &Scheduler{
+		file:    filepath.Join(t.TempDir(), "jobs.json"),
+		entries: make(map[string]*entry),
+	}
+
+	job := Job{ID: "foo", Schedule: "@hourly", Path: "/forms/chromium/convert/url"}
+
+	_, err := mod.put(job, true)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = mod.put(job, true)
+	if !errors.Is(err, ErrJobAlreadyExists) {
+		t.Errorf("expected error %v but got: %v", ErrJobAlreadyExists, err)
+	}
+
+	_, err = mod.put(Job{ID: "foo", Schedule: "foo", Path: "/forms/chromium/convert/url"}, false)
+	if !errors.Is(err, ErrInvalidJob) {
+		t.Errorf("expected error %v but got: %v", ErrInvalidJob, err)
+	}
+
+	job.Disabled = true
+
+	status, err := mod.put(job, false)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if status.NextRun != nil {
+		t.Errorf("expected no next run for a disabled job but got %s", status.NextRun)
+	}
+
+	jobs, err := loadJobs(mod.file)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !reflect.DeepEqual(jobs, []Job{job}) {
+		t.Errorf("expected persisted jobs %+v but got %+v", []Job{job}, jobs)
+	}
+
+	err = mod.delete("foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = mod.delete("foo")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected error %v but got: %v", ErrJobNotFound, err)
+	}
+
+	jobs, err = loadJobs(mod.file)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(jobs) != 0 {
+		t.Errorf("expected no persisted job but got %+v", jobs)
+	}
+}
+
+func TestScheduler_tick(t *testing.T) {
+	calls := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls <- r.URL.Path
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	mod := &Scheduler{
+		runner:  runner{url: srv.URL, client: srv.Client()},
+		logger:  zap.NewNop(),
+		entries: make(map[string]*entry),
+	}
+
+	now := time.Date(2024, time.January, 31, 10, 15, 0, 0, time.UTC)
+
+	for _, job := range []Job{
+		{ID: "due", Schedule: "* * * * *", Path: "/forms/due"},
+		{ID: "later", Schedule: "@daily", Path: "/forms/later"},
+		{ID: "disabled", Schedule: "* * * * *", Path: "/forms/disabled", Disabled: true},
+	} {
+		e, err := newEntry(job, now)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		mod.entries[job.ID] = e
+	}
+
+	mod.tick(now.Add(time.Duration(1) * time.Minute))
+	mod.wg.Wait()
+
+	close(calls)
+
+	var paths []string
+	for path := range calls {
+		paths = append(paths, path)
+	}
+
+	if !reflect.DeepEqual(paths, []string{"/forms/due"}) {
+		t.Errorf("expected only the due job to run but got %v", paths)
+	}
+
+	status, err := mod.job("due")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if status.Running || status.LastRun == nil || status.LastRun.Status != http.StatusNoContent {
+		t.Errorf("expected a successful last run but got %+v", status)
+	}
+
+	expectNext := now.Add(time.Duration(2) * time.Minute)
+	if status.NextRun == nil || !status.NextRun.Equal(expectNext) {
+		t.Errorf("expected next run %s but got %v", expectNext, status.NextRun)
+	}
+
+	_, err = mod.trigger("foo")
+	if !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("expected error %v but got: %v", ErrJobNotFound, err)
+	}
+}
+
+func TestScheduler_Start(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "jobs.json")
+
+	err := saveJobs(file, []Job{{ID: "foo", Schedule: "@hourly", Path: "/forms/chromium/convert/url"}})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	mod := &Scheduler{
+		enable:  true,
+		file:    file,
+		url:     "http://localhost:3000",
+		entries: make(map[string]*entry),
+	}
+
+	err = mod.Start()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(mod.jobs()) != 1 {
+		t.Errorf("expected one loaded job but got %+v", mod.jobs())
+	}
+
+	if mod.StartupMessage() == "" {
+		t.Error("expected a startup message")
+	}
+
+	err = mod.Stop(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
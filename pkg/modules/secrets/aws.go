@@ -0,0 +1,183 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// awsClient reads secrets from the AWS Secrets Manager API. It signs its
+// requests with the AWS Signature Version 4.
+type awsClient struct {
+	region          string
+	endpoint        string
+	accessKeyId     string
+	secretAccessKey string
+	sessionToken    string
+	client          *http.Client
+}
+
+// secret returns the value of an AWS secret or, if the reference has a key,
+// the value of a field of a JSON secret.
+func (a *awsClient) secret(ctx context.Context, ref string) (string, error) {
+	secretId, key := splitRef(ref)
+	if secretId == "" {
+		return "", errors.New("expected a <secret-id>[#<key>] reference")
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": secretId})
+	if err != nil {
+		return "", fmt.Errorf("marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	a.sign(req, payload, time.Now())
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		// The body of an error does not contain the secret.
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var body struct {
+		SecretString *string `json:"SecretString"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	if body.SecretString == nil {
+		return "", errors.New("binary secrets are not supported")
+	}
+
+	if key == "" {
+		return *body.SecretString, nil
+	}
+
+	var fields map[string]any
+	err = json.Unmarshal([]byte(*body.SecretString), &fields)
+	if err != nil {
+		return "", fmt.Errorf("secret is not a JSON object: %w", err)
+	}
+
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("key '%s' not found", key)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("key '%s' is not a string", key)
+	}
+
+	return s, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to a request.
+func (a *awsClient) sign(req *http.Request, payload []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if a.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.sessionToken)
+	}
+
+	// The headers must be sorted by name.
+	signedHeaders := []string{"content-type", "host", "x-amz-date"}
+	if a.sessionToken != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	signedHeaders = append(signedHeaders, "x-amz-target")
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+
+		canonicalHeaders.WriteString(fmt.Sprintf("%s:%s\n", name, strings.TrimSpace(value)))
+	}
+
+	canonicalUri := req.URL.EscapedPath()
+	if canonicalUri == "" {
+		canonicalUri = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalUri,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		hashHex(payload),
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/secretsmanager/aws4_request", dateStamp, a.region)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+a.secretAccessKey), dateStamp)
+	key = hmacSha256(key, a.region)
+	key = hmacSha256(key, "secretsmanager")
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		a.accessKeyId, scope, strings.Join(signedHeaders, ";"), signature,
+	))
+}
+
+// canonicalQuery returns the sorted, encoded query string of a request.
+func canonicalQuery(query url.Values) string {
+	// Encode sorts by key.
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func hashHex(b []byte) string {
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
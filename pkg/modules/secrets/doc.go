@@ -0,0 +1,9 @@
+// Package secrets provides a module which resolves the references to secrets
+// kept by HashiCorp Vault or AWS Secrets Manager, e.g., in the flags of the
+// other modules:
+//
+//   - vault:<path>#<key>, where the path is the one of the Vault HTTP API
+//     (e.g., vault:secret/data/gotenberg#sentry-dsn).
+//   - aws-sm:<secret-id>[#<key>], where the key selects a field of a JSON
+//     secret (e.g., aws-sm:prod/gotenberg#signing-key).
+package secrets
@@ -0,0 +1,194 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Secrets))
+}
+
+const (
+	vaultScheme = "vault:"
+	awsScheme   = "aws-sm:"
+)
+
+// Secrets is a module which resolves the references to secrets kept by
+// HashiCorp Vault or AWS Secrets Manager.
+type Secrets struct {
+	vault   *vaultClient
+	aws     *awsClient
+	timeout time.Duration
+
+	cache   map[string]string
+	cacheMu sync.Mutex
+}
+
+// Descriptor returns a [Secrets]'s module descriptor.
+func (mod *Secrets) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "secrets",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("secrets", flag.ExitOnError)
+			fs.String("secrets-vault-addr", "", "Set the address of the HashiCorp Vault server resolving the vault:<path>#<key> references")
+			fs.String("secrets-vault-token", "", "Set the token for authenticating to the HashiCorp Vault server")
+			fs.String("secrets-vault-token-file", "", "Set the file containing the token for authenticating to the HashiCorp Vault server")
+			fs.String("secrets-vault-namespace", "", "Set the HashiCorp Vault namespace, if any")
+			fs.String("secrets-aws-region", "", "Set the AWS region of the Secrets Manager resolving the aws-sm:<secret-id>#<key> references - credentials come from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables")
+			fs.String("secrets-aws-endpoint", "", "Set a custom AWS Secrets Manager endpoint")
+			fs.Duration("secrets-timeout", time.Duration(10)*time.Second, "Set the time limit for resolving a secret")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Secrets) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Secrets) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.timeout = flags.MustDuration("secrets-timeout")
+	mod.cache = make(map[string]string)
+
+	client := &http.Client{
+		Timeout: mod.timeout,
+	}
+
+	vaultAddr := flags.MustString("secrets-vault-addr")
+	if vaultAddr != "" {
+		token := flags.MustString("secrets-vault-token")
+
+		tokenFile := flags.MustString("secrets-vault-token-file")
+		if tokenFile != "" {
+			b, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return fmt.Errorf("read Vault token file: %w", err)
+			}
+
+			token = strings.TrimSpace(string(b))
+		}
+
+		mod.vault = &vaultClient{
+			addr:      strings.TrimSuffix(vaultAddr, "/"),
+			token:     token,
+			namespace: flags.MustString("secrets-vault-namespace"),
+			client:    client,
+		}
+	}
+
+	awsRegion := flags.MustString("secrets-aws-region")
+	if awsRegion != "" {
+		endpoint := flags.MustString("secrets-aws-endpoint")
+		if endpoint == "" {
+			endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", awsRegion)
+		}
+
+		mod.aws = &awsClient{
+			region:          awsRegion,
+			endpoint:        endpoint,
+			accessKeyId:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			client:          client,
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Secrets) Validate() error {
+	var err error
+
+	if mod.timeout <= 0 {
+		err = multierr.Append(err,
+			errors.New("timeout must be more than 0"),
+		)
+	}
+
+	if mod.vault != nil && mod.vault.token == "" {
+		err = multierr.Append(err,
+			errors.New("Vault token must not be empty"),
+		)
+	}
+
+	if mod.aws != nil && (mod.aws.accessKeyId == "" || mod.aws.secretAccessKey == "") {
+		err = multierr.Append(err,
+			errors.New("AWS credentials must be set with the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables"),
+		)
+	}
+
+	return err
+}
+
+// ResolveSecret returns the value of the secret a vault:<path>#<key> or an
+// aws-sm:<secret-id>[#<key>] reference points to. Resolved secrets are
+// cached.
+func (mod *Secrets) ResolveSecret(ctx context.Context, value string) (string, error) {
+	var resolve func(ctx context.Context, ref string) (string, error)
+
+	switch {
+	case strings.HasPrefix(value, vaultScheme):
+		if mod.vault == nil {
+			return "", errors.New("HashiCorp Vault is not configured")
+		}
+		resolve = mod.vault.secret
+	case strings.HasPrefix(value, awsScheme):
+		if mod.aws == nil {
+			return "", errors.New("AWS Secrets Manager is not configured")
+		}
+		resolve = mod.aws.secret
+	default:
+		return "", gotenberg.ErrNotSecretReference
+	}
+
+	mod.cacheMu.Lock()
+	defer mod.cacheMu.Unlock()
+
+	secret, ok := mod.cache[value]
+	if ok {
+		return secret, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mod.timeout)
+	defer cancel()
+
+	_, ref, _ := strings.Cut(value, ":")
+
+	secret, err := resolve(ctx, ref)
+	if err != nil {
+		// The reference is not sensitive, the secret is.
+		return "", fmt.Errorf("resolve '%s': %w", value, err)
+	}
+
+	mod.cache[value] = secret
+
+	return secret, nil
+}
+
+// splitRef splits a <path>#<key> reference.
+func splitRef(ref string) (string, string) {
+	path, key, _ := strings.Cut(ref, "#")
+
+	return path, key
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module         = (*Secrets)(nil)
+	_ gotenberg.Provisioner    = (*Secrets)(nil)
+	_ gotenberg.Validator      = (*Secrets)(nil)
+	_ gotenberg.SecretResolver = (*Secrets)(nil)
+)
@@ -0,0 +1,242 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestSecrets_Descriptor(t *testing.T) {
+	descriptor := new(Secrets).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Secrets))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestSecrets_Provision(t *testing.T) {
+	mod := new(Secrets)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Secrets).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.vault != nil || mod.aws != nil {
+		t.Error("expected no secrets managers by default")
+	}
+}
+
+func TestSecrets_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Secrets
+		expectError bool
+	}{
+		{
+			scenario:    "invalid timeout",
+			mod:         &Secrets{},
+			expectError: true,
+		},
+		{
+			scenario: "empty Vault token",
+			mod: &Secrets{
+				timeout: time.Duration(10) * time.Second,
+				vault:   &vaultClient{addr: "http://vault:8200"},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "empty AWS credentials",
+			mod: &Secrets{
+				timeout: time.Duration(10) * time.Second,
+				aws:     &awsClient{region: "eu-west-1"},
+			},
+			expectError: true,
+		},
+		{
+			scenario: "validate success",
+			mod: &Secrets{
+				timeout: time.Duration(10) * time.Second,
+				vault:   &vaultClient{addr: "http://vault:8200", token: "foo"},
+				aws:     &awsClient{region: "eu-west-1", accessKeyId: "foo", secretAccessKey: "bar"},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSecrets_ResolveSecret(t *testing.T) {
+	vaultCalls := 0
+	vaultSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vaultCalls++
+
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/secret/data/gotenberg":
+			_, _ = fmt.Fprint(w, `{"data":{"data":{"dsn":"vault-kv2"},"metadata":{"version":1}}}`)
+		case "/v1/kv/gotenberg":
+			_, _ = fmt.Fprint(w, `{"data":{"dsn":"vault-kv1"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer vaultSrv.Close()
+
+	awsSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=foo/") || r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		_, _ = fmt.Fprint(w, `{"SecretString":"{\"key\":\"aws-json\"}"}`)
+	}))
+	defer awsSrv.Close()
+
+	mod := &Secrets{
+		timeout: time.Duration(10) * time.Second,
+		cache:   make(map[string]string),
+		vault: &vaultClient{
+			addr:   vaultSrv.URL,
+			token:  "token",
+			client: vaultSrv.Client(),
+		},
+		aws: &awsClient{
+			region:          "eu-west-1",
+			endpoint:        awsSrv.URL,
+			accessKeyId:     "foo",
+			secretAccessKey: "bar",
+			client:          awsSrv.Client(),
+		},
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		value       string
+		expect      string
+		expectError error
+		expectAny   bool
+	}{
+		{
+			scenario:    "not a secret reference",
+			value:       "https://sentry.io",
+			expectError: gotenberg.ErrNotSecretReference,
+		},
+		{
+			scenario: "Vault KV version 2",
+			value:    "vault:secret/data/gotenberg#dsn",
+			expect:   "vault-kv2",
+		},
+		{
+			scenario: "Vault KV version 1",
+			value:    "vault:kv/gotenberg#dsn",
+			expect:   "vault-kv1",
+		},
+		{
+			scenario:  "Vault reference without key",
+			value:     "vault:kv/gotenberg",
+			expectAny: true,
+		},
+		{
+			scenario:  "Vault key not found",
+			value:     "vault:kv/gotenberg#foo",
+			expectAny: true,
+		},
+		{
+			scenario:  "Vault secret not found",
+			value:     "vault:kv/foo#dsn",
+			expectAny: true,
+		},
+		{
+			scenario: "AWS JSON secret",
+			value:    "aws-sm:prod/gotenberg#key",
+			expect:   "aws-json",
+		},
+		{
+			scenario: "AWS whole secret",
+			value:    "aws-sm:prod/gotenberg",
+			expect:   `{"key":"aws-json"}`,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			actual, err := mod.ResolveSecret(context.Background(), tc.value)
+
+			if tc.expectError != nil {
+				if !errors.Is(err, tc.expectError) {
+					t.Fatalf("expected error %v but got: %v", tc.expectError, err)
+				}
+
+				return
+			}
+
+			if tc.expectAny {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if actual != tc.expect {
+				t.Errorf("expected '%s' but got '%s'", tc.expect, actual)
+			}
+		})
+	}
+
+	calls := vaultCalls
+	_, err := mod.ResolveSecret(context.Background(), "vault:secret/data/gotenberg#dsn")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if vaultCalls != calls {
+		t.Error("expected the secret to be cached")
+	}
+}
+
+func TestSecrets_ResolveSecret_NotConfigured(t *testing.T) {
+	mod := &Secrets{cache: make(map[string]string)}
+
+	for _, value := range []string{"vault:secret/data/gotenberg#dsn", "aws-sm:prod/gotenberg"} {
+		_, err := mod.ResolveSecret(context.Background(), value)
+		if err == nil || errors.Is(err, gotenberg.ErrNotSecretReference) {
+			t.Errorf("expected a configuration error for '%s' but got: %v", value, err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// vaultClient reads secrets from the HashiCorp Vault HTTP API.
+type vaultClient struct {
+	addr      string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+// secret returns the value of a key of a Vault secret. It supports both the
+// KV version 1 and version 2 secrets engines.
+func (v *vaultClient) secret(ctx context.Context, ref string) (string, error) {
+	path, key := splitRef(ref)
+	if path == "" || key == "" {
+		return "", errors.New("expected a <path>#<key> reference")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/v1/%s", v.addr, strings.TrimPrefix(path, "/")), nil)
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("send request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		// The body of an error does not contain the secret.
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+		return "", fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	data := body.Data
+
+	// KV version 2 nests the secret with its metadata.
+	nested, ok := data["data"].(map[string]any)
+	if _, hasMetadata := data["metadata"]; ok && hasMetadata {
+		data = nested
+	}
+
+	value, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key '%s' not found", key)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("key '%s' is not a string", key)
+	}
+
+	return s, nil
+}
@@ -0,0 +1,5 @@
+// Package templates provides a module which stores reusable templates, e.g.,
+// a DOCX template or an HTML document, on disk or on an object storage. The
+// requests reference them with the "templateId" form field and only upload
+// their data. An admin API manages them.
+package templates
@@ -0,0 +1,144 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// Routes returns the admin routes for managing the templates.
+func (mod *Templates) Routes() ([]api.Route, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/admin/templates",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, mod.templates())
+			},
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/admin/templates/:id",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				template, ok := mod.template(c.Param("id"))
+				if !ok {
+					return templateError(fmt.Errorf("template '%s': %w", c.Param("id"), api.ErrTemplateNotFound))
+				}
+
+				return c.JSON(http.StatusOK, template)
+			},
+		},
+		{
+			Method:         http.MethodGet,
+			Path:           "/admin/templates/:id/file",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				filename, in, err := mod.OpenTemplate(c.Request().Context(), c.Param("id"))
+				if err != nil {
+					return templateError(fmt.Errorf("open template: %w", err))
+				}
+
+				defer func() {
+					_ = in.Close()
+				}()
+
+				c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+				return c.Stream(http.StatusOK, "application/octet-stream", in)
+			},
+		},
+		{
+			Method:         http.MethodPut,
+			Path:           "/admin/templates/:id",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				reader, err := c.Request().MultipartReader()
+				if err != nil {
+					return templateError(fmt.Errorf("read multipart form: %v: %w", err, ErrInvalidTemplate))
+				}
+
+				for {
+					part, err := reader.NextPart()
+					if errors.Is(err, io.EOF) {
+						return templateError(fmt.Errorf("no file: %w", ErrInvalidTemplate))
+					}
+
+					if err != nil {
+						return templateError(fmt.Errorf("read multipart form: %v: %w", err, ErrInvalidTemplate))
+					}
+
+					if part.FileName() == "" {
+						// Not a file.
+						continue
+					}
+
+					// The first file is the template.
+					template, created, err := mod.put(c.Request().Context(), c.Param("id"), part.FileName(), part)
+					if err != nil {
+						return templateError(fmt.Errorf("put template: %w", err))
+					}
+
+					status := http.StatusOK
+					if created {
+						status = http.StatusCreated
+					}
+
+					return c.JSON(status, template)
+				}
+			},
+		},
+		{
+			Method:         http.MethodDelete,
+			Path:           "/admin/templates/:id",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				err := mod.delete(c.Request().Context(), c.Param("id"))
+				if err != nil {
+					return templateError(fmt.Errorf("delete template: %w", err))
+				}
+
+				return c.NoContent(http.StatusNoContent)
+			},
+		},
+	}, nil
+}
+
+// templateError wraps the errors of the admin API the client is responsible
+// for, so that they have a meaningful HTTP status.
+func templateError(err error) error {
+	switch {
+	case errors.Is(err, ErrInvalidTemplate):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid template: %s", err)).WithCode(api.ErrorCodeTemplatesInvalidTemplate),
+		)
+	case errors.Is(err, ErrTemplateTooLarge):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusRequestEntityTooLarge, "Template too large").WithCode(api.ErrorCodeTemplatesTemplateTooLarge),
+		)
+	case errors.Is(err, api.ErrTemplateNotFound):
+		return api.WrapError(
+			err,
+			api.NewSentinelHttpError(http.StatusNotFound, "Template not found").WithCode(api.ErrorCodeTemplatesTemplateNotFound),
+		)
+	}
+
+	return err
+}
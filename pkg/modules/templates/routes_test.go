@@ -0,0 +1,245 @@
+package templates
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestTemplates_Routes(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		routes, err := new(Templates).Routes()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(routes) != 0 {
+			t.Errorf("expected no route but got %d", len(routes))
+		}
+	})
+
+	dir := t.TempDir()
+	mod := &Templates{
+		enable:   true,
+		maxSize:  10,
+		backend:  diskBackend{dir: dir},
+		cacheDir: filepath.Join(dir, "objects"),
+		logger:   zap.NewNop(),
+		index:    make(map[string]Template),
+	}
+
+	err := os.MkdirAll(mod.cacheDir, 0o700)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	routes, err := mod.Routes()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(routes) != 5 {
+		t.Fatalf("expected 5 routes but got %d", len(routes))
+	}
+
+	for _, route := range routes {
+		if !route.IsAdmin {
+			t.Errorf("expected '%s %s' to be an admin route", route.Method, route.Path)
+		}
+	}
+
+	multipartBody := func(filename, content string) (string, string) {
+		var body bytes.Buffer
+		writer := multipart.NewWriter(&body)
+
+		err := writer.WriteField("foo", "bar")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if filename != "" {
+			part, err := writer.CreateFormFile("files", filename)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			_, _ = part.Write([]byte(content))
+		}
+
+		_ = writer.Close()
+
+		return body.String(), writer.FormDataContentType()
+	}
+
+	call := func(method, path, id, body, contentType string) (int, string) {
+		for _, route := range routes {
+			if route.Method != method || route.Path != path {
+				continue
+			}
+
+			req := httptest.NewRequest(method, path, strings.NewReader(body))
+			if contentType != "" {
+				req.Header.Set(echo.HeaderContentType, contentType)
+			}
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(id)
+
+			err := route.Handler(c)
+			if err != nil {
+				response := api.ParseErrorResponse(err)
+
+				return response.Status, response.Code
+			}
+
+			return rec.Code, rec.Body.String()
+		}
+
+		t.Fatalf("expected a route '%s %s'", method, path)
+
+		return 0, ""
+	}
+
+	withFile, withFileContentType := multipartBody("invoice.docx", "invoice")
+	withoutFile, withoutFileContentType := multipartBody("", "")
+	tooLarge, tooLargeContentType := multipartBody("invoice.docx", "invoice-too-large")
+
+	for _, tc := range []struct {
+		scenario     string
+		method       string
+		path         string
+		id           string
+		body         string
+		contentType  string
+		expectStatus int
+		expectBody   string
+	}{
+		{
+			scenario:     "not multipart",
+			method:       http.MethodPut,
+			path:         "/admin/templates/:id",
+			id:           "invoice",
+			body:         "invoice",
+			expectStatus: http.StatusBadRequest,
+			expectBody:   "TEMPLATES_INVALID_TEMPLATE",
+		},
+		{
+			scenario:     "no file",
+			method:       http.MethodPut,
+			path:         "/admin/templates/:id",
+			id:           "invoice",
+			body:         withoutFile,
+			contentType:  withoutFileContentType,
+			expectStatus: http.StatusBadRequest,
+			expectBody:   "TEMPLATES_INVALID_TEMPLATE",
+		},
+		{
+			scenario:     "too large",
+			method:       http.MethodPut,
+			path:         "/admin/templates/:id",
+			id:           "invoice",
+			body:         tooLarge,
+			contentType:  tooLargeContentType,
+			expectStatus: http.StatusRequestEntityTooLarge,
+			expectBody:   "TEMPLATES_TEMPLATE_TOO_LARGE",
+		},
+		{
+			scenario:     "create template",
+			method:       http.MethodPut,
+			path:         "/admin/templates/:id",
+			id:           "invoice",
+			body:         withFile,
+			contentType:  withFileContentType,
+			expectStatus: http.StatusCreated,
+			expectBody:   `"filename":"invoice.docx"`,
+		},
+		{
+			scenario:     "replace template",
+			method:       http.MethodPut,
+			path:         "/admin/templates/:id",
+			id:           "invoice",
+			body:         withFile,
+			contentType:  withFileContentType,
+			expectStatus: http.StatusOK,
+			expectBody:   `"size":7`,
+		},
+		{
+			scenario:     "list templates",
+			method:       http.MethodGet,
+			path:         "/admin/templates",
+			expectStatus: http.StatusOK,
+			expectBody:   `[{"id":"invoice"`,
+		},
+		{
+			scenario:     "get template",
+			method:       http.MethodGet,
+			path:         "/admin/templates/:id",
+			id:           "invoice",
+			expectStatus: http.StatusOK,
+			expectBody:   `"id":"invoice"`,
+		},
+		{
+			scenario:     "download template",
+			method:       http.MethodGet,
+			path:         "/admin/templates/:id/file",
+			id:           "invoice",
+			expectStatus: http.StatusOK,
+			expectBody:   "invoice",
+		},
+		{
+			scenario:     "get unknown template",
+			method:       http.MethodGet,
+			path:         "/admin/templates/:id",
+			id:           "foo",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "TEMPLATES_TEMPLATE_NOT_FOUND",
+		},
+		{
+			scenario:     "download unknown template",
+			method:       http.MethodGet,
+			path:         "/admin/templates/:id/file",
+			id:           "foo",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "TEMPLATES_TEMPLATE_NOT_FOUND",
+		},
+		{
+			scenario:     "delete template",
+			method:       http.MethodDelete,
+			path:         "/admin/templates/:id",
+			id:           "invoice",
+			expectStatus: http.StatusNoContent,
+		},
+		{
+			scenario:     "delete unknown template",
+			method:       http.MethodDelete,
+			path:         "/admin/templates/:id",
+			id:           "invoice",
+			expectStatus: http.StatusNotFound,
+			expectBody:   "TEMPLATES_TEMPLATE_NOT_FOUND",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			status, body := call(tc.method, tc.path, tc.id, tc.body, tc.contentType)
+
+			if status != tc.expectStatus {
+				t.Errorf("expected status %d but got %d", tc.expectStatus, status)
+			}
+
+			if !strings.Contains(body, tc.expectBody) {
+				t.Errorf("expected body to contain '%s' but got '%s'", tc.expectBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The storage backends.
+const (
+	// DiskBackend keeps the templates in a local directory.
+	DiskBackend = "disk"
+
+	// HttpBackend keeps the templates on an object storage which accepts
+	// PUT, GET and DELETE requests, e.g., a bucket endpoint.
+	HttpBackend = "http"
+)
+
+// indexKey is the key of the object listing the templates.
+const indexKey = "index.json"
+
+// errObjectNotFound happens if an object does not exist on the backend.
+var errObjectNotFound = errors.New("object not found")
+
+// Template is the description of a stored template.
+type Template struct {
+	// ID identifies the template.
+	ID string `json:"id"`
+
+	// Filename is the name of the file the requests get, e.g.,
+	// "invoice.docx" or "index.html".
+	Filename string `json:"filename"`
+
+	// Size is the size of the file, in bytes.
+	Size int64 `json:"size"`
+
+	// Checksum is the SHA-256 checksum of the file. The files are stored
+	// under their checksum, so that identical templates share a file.
+	Checksum string `json:"checksum"`
+
+	// UpdatedAt is the time of the last upload.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// objectKey returns the key of the file of a template.
+func objectKey(checksum string) string {
+	return "objects/" + checksum
+}
+
+// backend keeps the objects of the templates, i.e., the index and the files.
+type backend interface {
+	put(ctx context.Context, key string, r io.Reader, size int64) error
+	get(ctx context.Context, key string, w io.Writer) error
+	delete(ctx context.Context, key string) error
+}
+
+// diskBackend keeps the objects in a directory.
+type diskBackend struct {
+	dir string
+}
+
+func (b diskBackend) put(ctx context.Context, key string, r io.Reader, size int64) error {
+	path := filepath.Join(b.dir, filepath.FromSlash(key))
+
+	err := os.MkdirAll(filepath.Dir(path), 0o700)
+	if err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	// Replace the object atomically.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	_, err = io.Copy(tmp, r)
+	if err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("write temporary file: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("close temporary file: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("rename temporary file: %w", err)
+	}
+
+	return nil
+}
+
+func (b diskBackend) get(ctx context.Context, key string, w io.Writer) error {
+	f, err := os.Open(filepath.Join(b.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("object '%s': %w", key, errObjectNotFound)
+	}
+
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	_, err = io.Copy(w, f)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	return nil
+}
+
+func (b diskBackend) delete(ctx context.Context, key string) error {
+	err := os.Remove(filepath.Join(b.dir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove file: %w", err)
+	}
+
+	return nil
+}
+
+// httpBackend keeps the objects on an object storage, under a base URL.
+type httpBackend struct {
+	url           string
+	authorization string
+	client        *http.Client
+}
+
+func (b httpBackend) put(ctx context.Context, key string, r io.Reader, size int64) error {
+	resp, err := b.do(ctx, http.MethodPut, key, r, size)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("put object '%s': got status code %d", key, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (b httpBackend) get(ctx context.Context, key string, w io.Writer) error {
+	resp, err := b.do(ctx, http.MethodGet, key, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("object '%s': %w", key, errObjectNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("get object '%s': got status code %d", key, resp.StatusCode)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	if err != nil {
+		return fmt.Errorf("read object '%s': %w", key, err)
+	}
+
+	return nil
+}
+
+func (b httpBackend) delete(ctx context.Context, key string) error {
+	resp, err := b.do(ctx, http.MethodDelete, key, nil, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("delete object '%s': got status code %d", key, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// do sends a request for an object.
+func (b httpBackend) do(ctx context.Context, method, key string, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/%s", strings.TrimSuffix(b.url, "/"), key), body)
+	if err != nil {
+		return nil, fmt.Errorf("create '%s' request: %w", method, err)
+	}
+
+	if body != nil {
+		req.ContentLength = size
+	}
+
+	if b.authorization != "" {
+		req.Header.Set("Authorization", b.authorization)
+	}
+
+	req.Header.Set("User-Agent", "Gotenberg")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send '%s' request: %w", method, err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,127 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// objectStorage is an in-memory object storage for the tests.
+type objectStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newObjectStorage(t *testing.T, authorization string) (*objectStorage, *httptest.Server) {
+	storage := &objectStorage{objects: make(map[string][]byte)}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != authorization {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		storage.mu.Lock()
+		defer storage.mu.Unlock()
+
+		key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+
+		switch r.Method {
+		case http.MethodPut:
+			b, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("expected no error but got: %v", err)
+			}
+
+			storage.objects[key] = b
+			w.WriteHeader(http.StatusOK)
+		case http.MethodGet:
+			b, ok := storage.objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			_, _ = w.Write(b)
+		case http.MethodDelete:
+			delete(storage.objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return storage, srv
+}
+
+func TestBackends(t *testing.T) {
+	_, srv := newObjectStorage(t, "Bearer foo")
+
+	for _, tc := range []struct {
+		scenario    string
+		backend     backend
+		expectError bool
+	}{
+		{
+			scenario: "disk",
+			backend:  diskBackend{dir: t.TempDir()},
+		},
+		{
+			scenario: "HTTP",
+			backend:  httpBackend{url: srv.URL + "/bucket/", authorization: "Bearer foo", client: srv.Client()},
+		},
+		{
+			scenario:    "HTTP without authorization",
+			backend:     httpBackend{url: srv.URL + "/bucket", client: srv.Client()},
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := context.Background()
+
+			err := tc.backend.put(ctx, "objects/foo", strings.NewReader("foo"), 3)
+			if tc.expectError {
+				if err == nil {
+					t.Fatal("expected error but got none")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var b bytes.Buffer
+
+			err = tc.backend.get(ctx, "objects/foo", &b)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if b.String() != "foo" {
+				t.Errorf("expected content 'foo' but got '%s'", b.String())
+			}
+
+			err = tc.backend.delete(ctx, "objects/foo")
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			err = tc.backend.delete(ctx, "objects/foo")
+			if err != nil {
+				t.Fatalf("expected no error when deleting a missing object but got: %v", err)
+			}
+
+			err = tc.backend.get(ctx, "objects/foo", &b)
+			if !errors.Is(err, errObjectNotFound) {
+				t.Errorf("expected error %v but got: %v", errObjectNotFound, err)
+			}
+		})
+	}
+}
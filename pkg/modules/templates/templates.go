@@ -0,0 +1,550 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	gommonbytes "github.com/labstack/gommon/bytes"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Templates))
+}
+
+var (
+	// ErrInvalidTemplate happens if the ID or the file of a template is
+	// invalid.
+	ErrInvalidTemplate = errors.New("invalid template")
+
+	// ErrTemplateTooLarge happens if the file of a template exceeds the
+	// maximum size.
+	ErrTemplateTooLarge = errors.New("template too large")
+)
+
+// templateIdRegexp restricts the IDs of the templates, as they are path
+// parameters of the admin API.
+var templateIdRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Templates is a module which stores reusable templates, provides them to
+// the requests referencing them, and adds an admin API for managing them.
+type Templates struct {
+	enable              bool
+	backendName         string
+	dir                 string
+	url                 string
+	authorization       string
+	timeout             time.Duration
+	maxSize             int64
+	refreshInterval     time.Duration
+	disableRouteLogging bool
+
+	backend  backend
+	cacheDir string
+	logger   *zap.Logger
+	index    map[string]Template
+	mu       sync.RWMutex
+	stop     chan struct{}
+}
+
+// Descriptor returns a [Templates]'s module descriptor.
+func (mod *Templates) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "templates",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("templates", flag.ExitOnError)
+			fs.Bool("templates-enable", false, "Enable the stored templates, the 'templateId' form field and their admin routes - the latter require the API admin token")
+			fs.String("templates-backend", DiskBackend, fmt.Sprintf("Set the storage of the templates, either '%s' or '%s'", DiskBackend, HttpBackend))
+			fs.String("templates-dir", filepath.Join(os.TempDir(), "gotenberg-templates"), "Set the directory of the templates with the disk backend, or of their local copies with the HTTP backend")
+			fs.String("templates-url", "", "Set the base URL of the object storage with the HTTP backend, e.g., a bucket endpoint, which accepts PUT, GET and DELETE requests")
+			fs.String("templates-authorization", "", "Set the 'Authorization' header of the requests to the object storage, or a reference to a secret")
+			fs.Duration("templates-timeout", time.Duration(30)*time.Second, "Set the time limit of each request to the object storage")
+			fs.String("templates-max-size", "50MB", "Set the maximum size of a template")
+			fs.Duration("templates-refresh-interval", time.Duration(1)*time.Minute, "Set the interval at which to reload the list of the templates, e.g., when several instances share an object storage. Set to 0 to disable this feature")
+			fs.Bool("templates-disable-route-logging", false, "Disable the route logging")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Templates) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Templates) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("templates-enable")
+	mod.backendName = flags.MustString("templates-backend")
+	mod.dir = flags.MustString("templates-dir")
+	mod.url = flags.MustString("templates-url")
+	mod.timeout = flags.MustDuration("templates-timeout")
+	mod.refreshInterval = flags.MustDuration("templates-refresh-interval")
+	mod.disableRouteLogging = flags.MustBool("templates-disable-route-logging")
+
+	maxSize, err := gommonbytes.Parse(flags.MustHumanReadableBytesString("templates-max-size"))
+	if err != nil {
+		return fmt.Errorf("parse maximum size: %w", err)
+	}
+
+	mod.maxSize = maxSize
+	mod.index = make(map[string]Template)
+
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	authorization, err := gotenberg.ResolveSecret(ctx, flags.MustString("templates-authorization"))
+	if err != nil {
+		return fmt.Errorf("get authorization: %w", err)
+	}
+
+	mod.authorization = authorization
+
+	switch mod.backendName {
+	case HttpBackend:
+		mod.backend = httpBackend{
+			url:           mod.url,
+			authorization: mod.authorization,
+			client:        &http.Client{Timeout: mod.timeout},
+		}
+		mod.cacheDir = mod.dir
+	default:
+		mod.backend = diskBackend{dir: mod.dir}
+		// The files of the disk backend are their own local copies.
+		mod.cacheDir = filepath.Join(mod.dir, "objects")
+	}
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(mod)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	mod.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Templates) Validate() error {
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	switch mod.backendName {
+	case DiskBackend:
+	case HttpBackend:
+		u, parseErr := url.Parse(mod.url)
+		if parseErr != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			err = multierr.Append(err,
+				fmt.Errorf("URL '%s' must be an absolute HTTP(S) URL with the '%s' backend", mod.url, HttpBackend),
+			)
+		}
+
+		if mod.timeout <= 0 {
+			err = multierr.Append(err,
+				errors.New("timeout must be more than 0"),
+			)
+		}
+	default:
+		err = multierr.Append(err,
+			fmt.Errorf("backend '%s' is not one of '%s' or '%s'", mod.backendName, DiskBackend, HttpBackend),
+		)
+	}
+
+	if mod.dir == "" {
+		err = multierr.Append(err,
+			errors.New("directory must not be empty"),
+		)
+	}
+
+	if mod.maxSize <= 0 {
+		err = multierr.Append(err,
+			errors.New("maximum size must be more than 0"),
+		)
+	}
+
+	if mod.refreshInterval < 0 {
+		err = multierr.Append(err,
+			errors.New("refresh interval must be at least 0"),
+		)
+	}
+
+	return err
+}
+
+// Start loads the list of the templates and reloads it periodically, if
+// enabled.
+func (mod *Templates) Start() error {
+	if !mod.enable {
+		return nil
+	}
+
+	err := os.MkdirAll(mod.cacheDir, 0o700)
+	if err != nil {
+		return fmt.Errorf("create templates directory: %w", err)
+	}
+
+	err = mod.refresh(context.Background())
+	if err != nil {
+		return fmt.Errorf("load templates: %w", err)
+	}
+
+	if mod.refreshInterval == 0 {
+		return nil
+	}
+
+	mod.stop = make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(mod.refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-mod.stop:
+				return
+			case <-ticker.C:
+				err := mod.refresh(context.Background())
+				if err != nil {
+					mod.logger.Error(fmt.Sprintf("reload templates: %s", err))
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (mod *Templates) StartupMessage() string {
+	if !mod.enable {
+		return "templates disabled"
+	}
+
+	mod.mu.RLock()
+	defer mod.mu.RUnlock()
+
+	return fmt.Sprintf("%d template(s) on the '%s' backend", len(mod.index), mod.backendName)
+}
+
+// Stop stops reloading the list of the templates.
+func (mod *Templates) Stop(ctx context.Context) error {
+	if mod.stop != nil {
+		close(mod.stop)
+	}
+
+	return nil
+}
+
+// OpenTemplate returns the filename and the content of a template.
+func (mod *Templates) OpenTemplate(ctx context.Context, id string) (string, io.ReadCloser, error) {
+	if !mod.enable {
+		return "", nil, fmt.Errorf("templates disabled: %w", api.ErrTemplateNotFound)
+	}
+
+	template, ok := mod.template(id)
+	if !ok {
+		return "", nil, fmt.Errorf("template '%s': %w", id, api.ErrTemplateNotFound)
+	}
+
+	path, err := mod.local(ctx, template.Checksum)
+	if err != nil {
+		return "", nil, fmt.Errorf("get local copy of template '%s': %w", id, err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", nil, fmt.Errorf("open local copy of template '%s': %w", id, err)
+	}
+
+	return template.Filename, f, nil
+}
+
+// templates returns all the templates, sorted by ID.
+func (mod *Templates) templates() []Template {
+	mod.mu.RLock()
+	defer mod.mu.RUnlock()
+
+	templates := make([]Template, 0, len(mod.index))
+	for _, template := range mod.index {
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+
+	return templates
+}
+
+// template returns a template.
+func (mod *Templates) template(id string) (Template, bool) {
+	mod.mu.RLock()
+	defer mod.mu.RUnlock()
+
+	template, ok := mod.index[id]
+
+	return template, ok
+}
+
+// put stores the file of a template and creates or replaces it. It tells if
+// the template is new.
+func (mod *Templates) put(ctx context.Context, id, filename string, r io.Reader) (Template, bool, error) {
+	if !templateIdRegexp.MatchString(id) {
+		return Template{}, false, fmt.Errorf("wrong ID '%s', expected letters, digits, '.', '_' or '-': %w", id, ErrInvalidTemplate)
+	}
+
+	filename = filepath.Base(filename)
+	if filename == "." || filename == "/" || filename == ".." {
+		return Template{}, false, fmt.Errorf("wrong filename '%s': %w", filename, ErrInvalidTemplate)
+	}
+
+	// The checksum is the key of the file: let's write it to a local file
+	// first.
+	tmp, err := os.CreateTemp(mod.cacheDir, ".upload-*")
+	if err != nil {
+		return Template{}, false, fmt.Errorf("create temporary file: %w", err)
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	hash := sha256.New()
+
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(r, mod.maxSize+1))
+	if err != nil {
+		return Template{}, false, fmt.Errorf("write temporary file: %w", err)
+	}
+
+	if size > mod.maxSize {
+		return Template{}, false, fmt.Errorf("more than %d bytes: %w", mod.maxSize, ErrTemplateTooLarge)
+	}
+
+	if size == 0 {
+		return Template{}, false, fmt.Errorf("empty file: %w", ErrInvalidTemplate)
+	}
+
+	template := Template{
+		ID:        id,
+		Filename:  filename,
+		Size:      size,
+		Checksum:  hex.EncodeToString(hash.Sum(nil)),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	_, err = tmp.Seek(0, io.SeekStart)
+	if err != nil {
+		return Template{}, false, fmt.Errorf("rewind temporary file: %w", err)
+	}
+
+	err = mod.backend.put(ctx, objectKey(template.Checksum), tmp, size)
+	if err != nil {
+		return Template{}, false, fmt.Errorf("store file: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), filepath.Join(mod.cacheDir, template.Checksum))
+	if err != nil {
+		return Template{}, false, fmt.Errorf("keep local copy: %w", err)
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	previous, exists := mod.index[id]
+	mod.index[id] = template
+
+	err = mod.saveLocked(ctx)
+	if err != nil {
+		if exists {
+			mod.index[id] = previous
+		} else {
+			delete(mod.index, id)
+		}
+
+		return Template{}, false, err
+	}
+
+	if exists {
+		mod.releaseLocked(ctx, previous.Checksum)
+	}
+
+	return template, !exists, nil
+}
+
+// delete removes a template.
+func (mod *Templates) delete(ctx context.Context, id string) error {
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	previous, ok := mod.index[id]
+	if !ok {
+		return fmt.Errorf("template '%s': %w", id, api.ErrTemplateNotFound)
+	}
+
+	delete(mod.index, id)
+
+	err := mod.saveLocked(ctx)
+	if err != nil {
+		mod.index[id] = previous
+
+		return err
+	}
+
+	mod.releaseLocked(ctx, previous.Checksum)
+
+	return nil
+}
+
+// releaseLocked removes the file of a replaced or deleted template, unless
+// another template shares it. The caller holds the lock.
+func (mod *Templates) releaseLocked(ctx context.Context, checksum string) {
+	for _, template := range mod.index {
+		if template.Checksum == checksum {
+			return
+		}
+	}
+
+	err := mod.backend.delete(ctx, objectKey(checksum))
+	if err != nil {
+		mod.logger.Error(fmt.Sprintf("remove template file '%s': %s", checksum, err))
+	}
+
+	err = os.Remove(filepath.Join(mod.cacheDir, checksum))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		mod.logger.Error(fmt.Sprintf("remove local copy of template file '%s': %s", checksum, err))
+	}
+}
+
+// saveLocked stores the list of the templates. The caller holds the lock.
+func (mod *Templates) saveLocked(ctx context.Context) error {
+	templates := make([]Template, 0, len(mod.index))
+	for _, template := range mod.index {
+		templates = append(templates, template)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].ID < templates[j].ID
+	})
+
+	b, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal templates: %w", err)
+	}
+
+	err = mod.backend.put(ctx, indexKey, bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return fmt.Errorf("store templates: %w", err)
+	}
+
+	return nil
+}
+
+// refresh loads the list of the templates from the backend. A missing list
+// means no template.
+func (mod *Templates) refresh(ctx context.Context) error {
+	var b bytes.Buffer
+
+	err := mod.backend.get(ctx, indexKey, &b)
+	if err != nil && !errors.Is(err, errObjectNotFound) {
+		return fmt.Errorf("get templates: %w", err)
+	}
+
+	var templates []Template
+
+	if b.Len() > 0 {
+		err = json.Unmarshal(b.Bytes(), &templates)
+		if err != nil {
+			return fmt.Errorf("unmarshal templates: %w", err)
+		}
+	}
+
+	index := make(map[string]Template, len(templates))
+	for _, template := range templates {
+		index[template.ID] = template
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	mod.index = index
+
+	return nil
+}
+
+// local returns the path of the local copy of a file, and downloads it
+// first if needed.
+func (mod *Templates) local(ctx context.Context, checksum string) (string, error) {
+	path := filepath.Join(mod.cacheDir, checksum)
+
+	_, err := os.Stat(path)
+	if err == nil {
+		return path, nil
+	}
+
+	tmp, err := os.CreateTemp(mod.cacheDir, ".download-*")
+	if err != nil {
+		return "", fmt.Errorf("create temporary file: %w", err)
+	}
+
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	err = mod.backend.get(ctx, objectKey(checksum), tmp)
+	if err != nil {
+		return "", fmt.Errorf("download file: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return "", fmt.Errorf("close temporary file: %w", err)
+	}
+
+	// Concurrent downloads of the same file have the same content.
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return "", fmt.Errorf("keep local copy: %w", err)
+	}
+
+	return path, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Templates)(nil)
+	_ gotenberg.Provisioner = (*Templates)(nil)
+	_ gotenberg.Validator   = (*Templates)(nil)
+	_ gotenberg.App         = (*Templates)(nil)
+	_ api.Router            = (*Templates)(nil)
+	_ api.TemplateProvider  = (*Templates)(nil)
+)
@@ -0,0 +1,297 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestTemplates_Descriptor(t *testing.T) {
+	descriptor := new(Templates).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Templates))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestTemplates_Provision(t *testing.T) {
+	mod := new(Templates)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Templates).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enable {
+		t.Error("expected templates to be disabled by default")
+	}
+
+	if mod.maxSize != 50*1000*1000 {
+		t.Errorf("expected maximum size of 50MB but got %d", mod.maxSize)
+	}
+}
+
+func TestTemplates_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		mod         *Templates
+		expectError bool
+	}{
+		{
+			scenario: "disabled",
+			mod:      &Templates{},
+		},
+		{
+			scenario:    "unknown backend",
+			mod:         &Templates{enable: true, backendName: "foo", dir: "/tmp", maxSize: 1},
+			expectError: true,
+		},
+		{
+			scenario:    "empty directory",
+			mod:         &Templates{enable: true, backendName: DiskBackend, maxSize: 1},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid maximum size",
+			mod:         &Templates{enable: true, backendName: DiskBackend, dir: "/tmp"},
+			expectError: true,
+		},
+		{
+			scenario:    "invalid refresh interval",
+			mod:         &Templates{enable: true, backendName: DiskBackend, dir: "/tmp", maxSize: 1, refreshInterval: -1},
+			expectError: true,
+		},
+		{
+			scenario:    "HTTP backend without URL",
+			mod:         &Templates{enable: true, backendName: HttpBackend, dir: "/tmp", maxSize: 1, timeout: time.Duration(1) * time.Second},
+			expectError: true,
+		},
+		{
+			scenario:    "HTTP backend without timeout",
+			mod:         &Templates{enable: true, backendName: HttpBackend, url: "https://bucket.example.com", dir: "/tmp", maxSize: 1},
+			expectError: true,
+		},
+		{
+			scenario: "disk backend",
+			mod:      &Templates{enable: true, backendName: DiskBackend, dir: "/tmp", maxSize: 1},
+		},
+		{
+			scenario: "HTTP backend",
+			mod:      &Templates{enable: true, backendName: HttpBackend, url: "https://bucket.example.com", dir: "/tmp", maxSize: 1, timeout: time.Duration(1) * time.Second},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			err := tc.mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
+func TestTemplates_put(t *testing.T) {
+	dir := t.TempDir()
+	mod := &Templates{
+		enable:   true,
+		maxSize:  10,
+		backend:  diskBackend{dir: dir},
+		cacheDir: filepath.Join(dir, "objects"),
+		logger:   zap.NewNop(),
+		index:    make(map[string]Template),
+	}
+
+	err := os.MkdirAll(mod.cacheDir, 0o700)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		scenario string
+		id       string
+		filename string
+		content  string
+		expect   error
+	}{
+		{scenario: "wrong ID", id: "foo/bar", filename: "foo.docx", content: "foo", expect: ErrInvalidTemplate},
+		{scenario: "wrong filename", id: "foo", filename: "..", content: "foo", expect: ErrInvalidTemplate},
+		{scenario: "empty file", id: "foo", filename: "foo.docx", expect: ErrInvalidTemplate},
+		{scenario: "too large", id: "foo", filename: "foo.docx", content: "foobarbazqux", expect: ErrTemplateTooLarge},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			_, _, err := mod.put(ctx, tc.id, tc.filename, strings.NewReader(tc.content))
+			if !errors.Is(err, tc.expect) {
+				t.Errorf("expected error %v but got: %v", tc.expect, err)
+			}
+		})
+	}
+
+	template, created, err := mod.put(ctx, "foo", "../foo.docx", strings.NewReader("foo"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if !created || template.Filename != "foo.docx" || template.Size != 3 {
+		t.Errorf("expected a new 'foo.docx' template of 3 bytes but got %+v (created: %t)", template, created)
+	}
+
+	// Same content, shared file.
+	_, _, err = mod.put(ctx, "bar", "bar.docx", strings.NewReader("foo"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	replaced, created, err := mod.put(ctx, "foo", "foo.docx", strings.NewReader("baz"))
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if created {
+		t.Error("expected the template to be replaced")
+	}
+
+	_, err = os.Stat(filepath.Join(mod.cacheDir, template.Checksum))
+	if err != nil {
+		t.Errorf("expected the shared file to be kept but got: %v", err)
+	}
+
+	filename, in, err := mod.OpenTemplate(ctx, "foo")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	b, _ := io.ReadAll(in)
+	_ = in.Close()
+
+	if filename != "foo.docx" || string(b) != "baz" {
+		t.Errorf("expected 'foo.docx' with 'baz' but got '%s' with '%s'", filename, string(b))
+	}
+
+	err = mod.delete(ctx, "bar")
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	_, err = os.Stat(filepath.Join(mod.cacheDir, template.Checksum))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected the unused file to be removed but got: %v", err)
+	}
+
+	err = mod.delete(ctx, "bar")
+	if !errors.Is(err, api.ErrTemplateNotFound) {
+		t.Errorf("expected error %v but got: %v", api.ErrTemplateNotFound, err)
+	}
+
+	_, _, err = mod.OpenTemplate(ctx, "bar")
+	if !errors.Is(err, api.ErrTemplateNotFound) {
+		t.Errorf("expected error %v but got: %v", api.ErrTemplateNotFound, err)
+	}
+
+	// Another instance sharing the storage.
+	other := &Templates{
+		enable:   true,
+		backend:  mod.backend,
+		cacheDir: mod.cacheDir,
+	}
+
+	err = other.refresh(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := []Template{replaced}
+	if !reflect.DeepEqual(other.templates(), expect) {
+		t.Errorf("expected %+v but got %+v", expect, other.templates())
+	}
+}
+
+func TestTemplates_OpenTemplate(t *testing.T) {
+	storage, srv := newObjectStorage(t, "")
+	ctx := context.Background()
+
+	newMod := func() *Templates {
+		mod := &Templates{
+			enable:   true,
+			maxSize:  10,
+			backend:  httpBackend{url: srv.URL + "/bucket", client: srv.Client()},
+			cacheDir: t.TempDir(),
+			logger:   zap.NewNop(),
+			index:    make(map[string]Template),
+		}
+
+		return mod
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		_, _, err := new(Templates).OpenTemplate(ctx, "foo")
+		if !errors.Is(err, api.ErrTemplateNotFound) {
+			t.Errorf("expected error %v but got: %v", api.ErrTemplateNotFound, err)
+		}
+	})
+
+	t.Run("download", func(t *testing.T) {
+		template, _, err := newMod().put(ctx, "foo", "index.html", strings.NewReader("<html/>"))
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		storage.mu.Lock()
+		_, ok := storage.objects[objectKey(template.Checksum)]
+		storage.mu.Unlock()
+
+		if !ok {
+			t.Fatal("expected the file on the object storage")
+		}
+
+		// Without a local copy.
+		mod := newMod()
+
+		err = mod.refresh(ctx)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		filename, in, err := mod.OpenTemplate(ctx, "foo")
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		b, _ := io.ReadAll(in)
+		_ = in.Close()
+
+		if filename != "index.html" || string(b) != "<html/>" {
+			t.Errorf("expected 'index.html' with '<html/>' but got '%s' with '%s'", filename, string(b))
+		}
+
+		_, err = os.Stat(filepath.Join(mod.cacheDir, template.Checksum))
+		if err != nil {
+			t.Errorf("expected a local copy but got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,14 @@
+// Package thumbnail provides a module which adds a route for creating a
+// preview image of a document, whatever its kind: the first page of a PDF or
+// of an office document, an image, an HTML file, or a web page.
+//
+// The PDFs are rasterized with the image engine of the pdfengines module,
+// e.g., pdftoppm. With the "transparentBackground" form field, the image
+// engine renders the pages without their white backdrop.
+//
+// With the "nativeSlideExport" form field, LibreOffice exports the first
+// slide of a presentation directly to an image instead, which is faster and
+// keeps the font rendering of LibreOffice.
+//
+// See: https://poppler.freedesktop.org.
+package thumbnail
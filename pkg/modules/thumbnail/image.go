@@ -0,0 +1,132 @@
+package thumbnail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+
+	"go.uber.org/zap"
+	"golang.org/x/image/draw"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// ErrInvalidPdf happens if a PDF cannot be rendered.
+var ErrInvalidPdf = errors.New("invalid PDF")
+
+// rasterize renders the first page of a PDF to a PNG file thanks to the
+// image engine. The page fits width x height pixels; if one of them is zero,
+// the other one alone defines the size. With transparent, the page has no
+// white backdrop.
+func rasterize(ctx context.Context, logger *zap.Logger, engine gotenberg.ImageEngine, inputPath, outputPath string, width, height int, transparent bool) error {
+	dirPath := fmt.Sprintf("%s.pages", outputPath)
+
+	err := os.Mkdir(dirPath, 0o755)
+	if err != nil {
+		return fmt.Errorf("create pages directory: %w", err)
+	}
+
+	defer func() {
+		err := os.RemoveAll(dirPath)
+		if err != nil {
+			logger.Error(fmt.Sprintf("remove pages directory: %s", err))
+		}
+	}()
+
+	options := gotenberg.ImageOptions{
+		Format:      gotenberg.ImageFormatPng,
+		FirstPage:   1,
+		LastPage:    1,
+		Width:       width,
+		Height:      height,
+		Transparent: transparent,
+	}
+
+	if width > 0 && height > 0 {
+		// The width fits the largest side, so that the image is never
+		// smaller than the thumbnail once resized.
+		options.Width = max(width, height)
+		options.Height = 0
+	}
+
+	paths, err := engine.Render(ctx, logger, options, inputPath, dirPath)
+	if err != nil {
+		return rasterizeError(ctx, err)
+	}
+
+	err = os.Rename(paths[0], outputPath)
+	if err != nil {
+		return fmt.Errorf("move first page image: %w", err)
+	}
+
+	return nil
+}
+
+// rasterizeError wraps the error of a rendering: unless the context is done,
+// the PDF is invalid.
+func rasterizeError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("rasterize PDF: %w", err)
+	}
+
+	return fmt.Errorf("rasterize PDF: %v: %w", err, ErrInvalidPdf)
+}
+
+// fit resizes an image so that it fits width x height pixels, keeping its
+// aspect ratio. If one of them is zero, the other one alone defines the
+// size.
+func fit(img image.Image, width, height int) *image.NRGBA {
+	bounds := img.Bounds()
+	w, h := float64(bounds.Dx()), float64(bounds.Dy())
+
+	var scale float64
+	switch {
+	case width > 0 && height > 0:
+		scale = math.Min(float64(width)/w, float64(height)/h)
+	case width > 0:
+		scale = float64(width) / w
+	default:
+		scale = float64(height) / h
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, max(1, int(math.Round(w*scale))), max(1, int(math.Round(h*scale)))))
+	draw.CatmullRom.Scale(dst, dst.Rect, img, bounds, draw.Src, nil)
+
+	return dst
+}
+
+// encode writes an image to a PNG or JPEG file. JPEG has no alpha channel,
+// so the transparent pixels become white.
+func encode(img *image.NRGBA, outputPath, format string, quality int) error {
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("create image file: %w", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	switch format {
+	case "jpeg":
+		opaque := image.NewRGBA(img.Rect)
+		draw.Draw(opaque, opaque.Rect, image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(opaque, opaque.Rect, img, img.Rect.Min, draw.Over)
+
+		err = jpeg.Encode(f, opaque, &jpeg.Options{Quality: quality})
+	default:
+		err = png.Encode(f, img)
+	}
+
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", format, err)
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,270 @@
+package thumbnail
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// writeTestPng writes a width x height PNG image to the given path.
+func writeTestPng(t *testing.T, path string, width, height int) {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	defer func() {
+		_ = f.Close()
+	}()
+
+	err = png.Encode(f, img)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+}
+
+// fakeImageEngine returns an image engine which renders a PNG image of a
+// page and records its options, if any, or which fails.
+func fakeImageEngine(t *testing.T, fail bool, options *gotenberg.ImageOptions) gotenberg.ImageEngine {
+	pngPath := filepath.Join(t.TempDir(), "page.png")
+	writeTestPng(t, pngPath, 200, 100)
+
+	return &gotenberg.ImageEngineMock{
+		RenderMock: func(ctx context.Context, logger *zap.Logger, opts gotenberg.ImageOptions, inputPath, outputDirPath string) ([]string, error) {
+			if options != nil {
+				*options = opts
+			}
+
+			if fail {
+				return nil, errors.New("foo")
+			}
+
+			b, err := os.ReadFile(pngPath)
+			if err != nil {
+				return nil, err
+			}
+
+			path := filepath.Join(outputDirPath, "page-1.png")
+
+			return []string{path}, os.WriteFile(path, b, 0o600)
+		},
+	}
+}
+
+func TestRasterize(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		ctx           context.Context
+		fail          bool
+		width         int
+		height        int
+		transparent   bool
+		expectError   bool
+		expectedError error
+		expectOptions gotenberg.ImageOptions
+	}{
+		{
+			scenario:      "ErrInvalidPdf",
+			ctx:           context.Background(),
+			fail:          true,
+			width:         256,
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:      "ErrInvalidPdf (transparent background)",
+			ctx:           context.Background(),
+			fail:          true,
+			width:         256,
+			transparent:   true,
+			expectError:   true,
+			expectedError: ErrInvalidPdf,
+		},
+		{
+			scenario:      "width and height",
+			ctx:           context.Background(),
+			width:         256,
+			height:        512,
+			expectError:   false,
+			expectOptions: gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, FirstPage: 1, LastPage: 1, Width: 512},
+		},
+		{
+			scenario:      "width only",
+			ctx:           context.Background(),
+			width:         256,
+			expectError:   false,
+			expectOptions: gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, FirstPage: 1, LastPage: 1, Width: 256},
+		},
+		{
+			scenario:      "height only",
+			ctx:           context.Background(),
+			height:        128,
+			expectError:   false,
+			expectOptions: gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, FirstPage: 1, LastPage: 1, Height: 128},
+		},
+		{
+			scenario:      "transparent background",
+			ctx:           context.Background(),
+			width:         256,
+			transparent:   true,
+			expectError:   false,
+			expectOptions: gotenberg.ImageOptions{Format: gotenberg.ImageFormatPng, FirstPage: 1, LastPage: 1, Width: 256, Transparent: true},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			dirPath := t.TempDir()
+			outputPath := filepath.Join(dirPath, "page.png")
+
+			var options gotenberg.ImageOptions
+			engine := fakeImageEngine(t, tc.fail, &options)
+
+			err := rasterize(tc.ctx, zap.NewNop(), engine, "/tmp/foo.pdf", outputPath, tc.width, tc.height, tc.transparent)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Fatalf("expected error %v but got: %v", tc.expectedError, err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			_, err = os.Stat(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if options != tc.expectOptions {
+				t.Errorf("expected options %+v but got %+v", tc.expectOptions, options)
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		width        int
+		height       int
+		expectWidth  int
+		expectHeight int
+	}{
+		{
+			scenario:     "width and height",
+			width:        100,
+			height:       100,
+			expectWidth:  100,
+			expectHeight: 50,
+		},
+		{
+			scenario:     "width only",
+			width:        50,
+			expectWidth:  50,
+			expectHeight: 25,
+		},
+		{
+			scenario:     "height only",
+			height:       200,
+			expectWidth:  400,
+			expectHeight: 200,
+		},
+		{
+			scenario:     "at least one pixel",
+			width:        1,
+			expectWidth:  1,
+			expectHeight: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			img := image.NewNRGBA(image.Rect(0, 0, 200, 100))
+
+			actual := fit(img, tc.width, tc.height)
+
+			if actual.Rect.Dx() != tc.expectWidth || actual.Rect.Dy() != tc.expectHeight {
+				t.Errorf("expected %dx%d but got %dx%d", tc.expectWidth, tc.expectHeight, actual.Rect.Dx(), actual.Rect.Dy())
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	for _, tc := range []struct {
+		scenario string
+		format   string
+	}{
+		{
+			scenario: "png",
+			format:   "png",
+		},
+		{
+			scenario: "jpeg",
+			format:   "jpeg",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			// Transparent pixels.
+			img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+			outputPath := filepath.Join(t.TempDir(), "thumbnail")
+
+			err := encode(img, outputPath, tc.format, 80)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			f, err := os.Open(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			defer func() {
+				_ = f.Close()
+			}()
+
+			var decoded image.Image
+			switch tc.format {
+			case "jpeg":
+				decoded, err = jpeg.Decode(f)
+			default:
+				decoded, err = png.Decode(f)
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			r, _, _, a := decoded.At(0, 0).RGBA()
+
+			if tc.format == "jpeg" && r < 0xf000 {
+				t.Errorf("expected a white pixel but got %v", decoded.At(0, 0))
+			}
+
+			if tc.format == "png" && a != 0 {
+				t.Errorf("expected a transparent pixel but got %v", decoded.At(0, 0))
+			}
+		})
+	}
+}
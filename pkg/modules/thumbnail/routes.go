@@ -0,0 +1,293 @@
+package thumbnail
+
+import (
+	"errors"
+	"fmt"
+	_ "image/gif"
+	"math"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	_ "golang.org/x/image/bmp"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/images"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+// maxSize is the maximum width or height of a thumbnail, in pixels.
+const maxSize = 4096
+
+var (
+	// imageExtensions are the extensions of the images decoded as is.
+	imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif", ".webp", ".tif", ".tiff", ".bmp"}
+
+	// pageExtensions are the extensions of the files Chromium renders.
+	pageExtensions = []string{".html", ".svg"}
+
+	// presentationExtensions are the extensions of the presentations whose
+	// first slide LibreOffice may export directly to an image.
+	presentationExtensions = []string{".odp", ".otp", ".fodp", ".ppt", ".pptx", ".pptm", ".pps", ".ppsx", ".pot", ".potx", ".potm", ".key", ".sxi", ".sti"}
+)
+
+// screenshotWidth is the width, in pixels, of the Chromium window for the
+// web pages.
+const screenshotWidth = 1280
+
+// thumbnailRoute returns an [api.Route] which can create the preview image
+// of a document, i.e., of its first page, or of a web page.
+func thumbnailRoute(imageEngine gotenberg.ImageEngine, chromiumApi chromium.Api, libreOffice libreofficeapi.Uno) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/thumbnail",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			extensions := []string{".pdf"}
+			extensions = append(extensions, imageExtensions...)
+			extensions = append(extensions, pageExtensions...)
+			for _, ext := range libreOffice.Extensions() {
+				if !slices.Contains(extensions, ext) {
+					extensions = append(extensions, ext)
+				}
+			}
+
+			size := func(value string, target *int) error {
+				if value == "" {
+					return nil
+				}
+
+				i, err := strconv.Atoi(value)
+				if err != nil {
+					return err
+				}
+
+				if i < 0 || i > maxSize {
+					return fmt.Errorf("value is not between 0 and %d", maxSize)
+				}
+
+				*target = i
+
+				return nil
+			}
+
+			// Let's get the data from the form and validate them.
+			var (
+				inputPaths []string
+				url        string
+				width      = 256
+				height     int
+				format     string
+				quality    int
+
+				nativeSlideExport     bool
+				transparentBackground bool
+			)
+
+			err := ctx.FormData().
+				Paths(extensions, &inputPaths).
+				Bool("nativeSlideExport", &nativeSlideExport, false).
+				Custom("url", func(value string) error {
+					if value == "" && len(inputPaths) != 1 {
+						return errors.New("expected either one file or a URL")
+					}
+
+					if value != "" && len(inputPaths) > 0 {
+						return errors.New("expected either a file or a URL, not both")
+					}
+
+					url = value
+
+					return nil
+				}).
+				Custom("width", func(value string) error {
+					return size(value, &width)
+				}).
+				Custom("height", func(value string) error {
+					err := size(value, &height)
+					if err != nil {
+						return err
+					}
+
+					if width == 0 && height == 0 {
+						return errors.New("width and height cannot both be 0")
+					}
+
+					return nil
+				}).
+				Custom("format", func(value string) error {
+					if value == "" {
+						format = "png"
+						return nil
+					}
+
+					if value != "png" && value != "jpeg" {
+						return errors.New("wrong value, expected either 'png' or 'jpeg'")
+					}
+
+					format = value
+
+					return nil
+				}).
+				Custom("quality", func(value string) error {
+					if value == "" {
+						quality = 80
+						return nil
+					}
+
+					i, err := strconv.Atoi(value)
+					if err != nil {
+						return err
+					}
+
+					if i < 1 || i > 100 {
+						return errors.New("value is not between 1 and 100")
+					}
+
+					quality = i
+
+					return nil
+				}).
+				Custom("transparentBackground", func(value string) error {
+					if value == "" {
+						return nil
+					}
+
+					b, err := strconv.ParseBool(value)
+					if err != nil {
+						return err
+					}
+
+					if b && format == "jpeg" {
+						return errors.New("JPEG has no alpha channel, expected the 'png' format")
+					}
+
+					transparentBackground = b
+
+					return nil
+				}).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			// Alright, let's find the route to an image of the first page.
+			var (
+				filename   string
+				sourcePath string
+			)
+
+			if url != "" {
+				sourcePath, err = screenshot(ctx, chromiumApi, url, width, height, transparentBackground)
+			} else {
+				inputPath := inputPaths[0]
+				filename = filepath.Base(inputPath)
+				ext := strings.ToLower(filepath.Ext(inputPath))
+
+				switch {
+				case ext == ".pdf":
+					sourcePath = ctx.GeneratePath("", ".png")
+					err = rasterize(ctx, ctx.Log(), imageEngine, inputPath, sourcePath, width, height, transparentBackground)
+				case slices.Contains(imageExtensions, ext):
+					sourcePath = inputPath
+				case slices.Contains(pageExtensions, ext):
+					sourcePath, err = screenshot(ctx, chromiumApi, fmt.Sprintf("file://%s", inputPath), width, height, transparentBackground)
+				case nativeSlideExport && slices.Contains(presentationExtensions, ext):
+					// The graphic export filter of Impress renders the first
+					// slide with the fonts of LibreOffice, without a PDF in
+					// between.
+					ctx.AddEngines("libreoffice")
+					sourcePath = ctx.GeneratePath("", ".png")
+
+					err = libreOffice.Convert(ctx, ctx.Log(), inputPath, sourcePath, libreofficeapi.ConvertOptions{Format: "png"})
+					if err != nil {
+						return fmt.Errorf("export first slide: %w", err)
+					}
+				default:
+					// Only the first page, as there is no need for the
+					// others.
+					ctx.AddEngines("libreoffice")
+					pdfPath := ctx.GeneratePath("", ".pdf")
+
+					err = libreOffice.Pdf(ctx, ctx.Log(), inputPath, pdfPath, libreofficeapi.Options{PageRanges: "1"})
+					if err != nil {
+						return fmt.Errorf("convert to PDF: %w", err)
+					}
+
+					sourcePath = ctx.GeneratePath("", ".png")
+					err = rasterize(ctx, ctx.Log(), imageEngine, pdfPath, sourcePath, width, height, transparentBackground)
+				}
+			}
+
+			if err != nil {
+				if errors.Is(err, ErrInvalidPdf) {
+					return api.WrapError(
+						fmt.Errorf("create first page image: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The PDF '%s' is invalid", filename)).WithCode(api.ErrorCodeThumbnailInvalidPdf),
+					)
+				}
+
+				return fmt.Errorf("create first page image: %w", err)
+			}
+
+			img, err := images.DecodeImage(sourcePath)
+			if err != nil {
+				if errors.Is(err, images.ErrInvalidImage) {
+					return api.WrapError(
+						fmt.Errorf("decode image: %w", err),
+						api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("The image '%s' is invalid or too large", filename)).WithCode(api.ErrorCodeThumbnailInvalidImage),
+					)
+				}
+
+				return fmt.Errorf("decode image: %w", err)
+			}
+
+			// document.docx -> document.docx.png.
+			outputPath := ctx.GeneratePath(filename, fmt.Sprintf(".%s", format))
+
+			err = encode(fit(img, width, height), outputPath, format, quality)
+			if err != nil {
+				return fmt.Errorf("encode thumbnail: %w", err)
+			}
+
+			err = ctx.AddOutputPaths(outputPath)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// screenshot takes a screenshot of the window of Chromium, whose aspect ratio
+// is the one of the thumbnail, if known.
+func screenshot(ctx *api.Context, chromiumApi chromium.Api, url string, width, height int, transparent bool) (string, error) {
+	options := chromium.DefaultScreenshotOptions()
+	options.Width = screenshotWidth
+	options.Height = screenshotWidth * 10 / 16
+	options.Clip = true
+	options.Format = "png"
+	options.OmitBackground = transparent
+
+	if width > 0 && height > 0 {
+		options.Height = min(maxSize, max(1, int(math.Round(float64(screenshotWidth)*float64(height)/float64(width)))))
+	}
+
+	outputPath := ctx.GeneratePath("", ".png")
+	ctx.AddEngines("chromium")
+
+	err := chromiumApi.Screenshot(ctx, ctx.Log(), url, outputPath, options)
+	if err != nil {
+		return "", fmt.Errorf("screenshot: %w", err)
+	}
+
+	return outputPath, nil
+}
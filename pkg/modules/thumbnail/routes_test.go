@@ -0,0 +1,314 @@
+package thumbnail
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestThumbnailRoute(t *testing.T) {
+	newContext := func(files map[string]string, values map[string][]string) *api.ContextMock {
+		dirPath := t.TempDir()
+		paths := make(map[string]string)
+
+		for filename, content := range files {
+			path := filepath.Join(dirPath, filename)
+
+			if content == "png" {
+				writeTestPng(t, path, 200, 100)
+			} else {
+				err := os.WriteFile(path, []byte(content), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+			}
+
+			paths[filename] = path
+		}
+
+		ctx := &api.ContextMock{Context: new(api.Context)}
+		ctx.SetDirPath(dirPath)
+		ctx.SetFiles(paths)
+		ctx.SetValues(values)
+
+		return ctx
+	}
+
+	chromiumApi := func(err error) chromium.Api {
+		return &chromium.ApiMock{
+			ScreenshotMock: func(ctx context.Context, logger *zap.Logger, url, outputPath string, options chromium.ScreenshotOptions) error {
+				if err != nil {
+					return err
+				}
+
+				writeTestPng(t, outputPath, options.Width, options.Height)
+
+				return nil
+			},
+		}
+	}
+
+	libreOffice := func(err error) libreofficeapi.Uno {
+		return &libreofficeapi.ApiMock{
+			PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+				if err != nil {
+					return err
+				}
+
+				if options.PageRanges != "1" {
+					return errors.New("expected the first page only")
+				}
+
+				return os.WriteFile(outputPath, []byte("%PDF"), 0o600)
+			},
+			ConvertMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.ConvertOptions) error {
+				if err != nil {
+					return err
+				}
+
+				if options.Format != "png" {
+					return errors.New("expected the PNG format")
+				}
+
+				writeTestPng(t, outputPath, 160, 90)
+
+				return nil
+			},
+			ExtensionsMock: func() []string {
+				return []string{".docx", ".pptx", ".pdf"}
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		imageEngine            gotenberg.ImageEngine
+		chromium               chromium.Api
+		libreOffice            libreofficeapi.Uno
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario:               "neither a file nor a URL",
+			ctx:                    newContext(nil, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "both a file and a URL",
+			ctx:                    newContext(map[string]string{"image.png": "png"}, map[string][]string{"url": {"https://foo"}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "more than one file",
+			ctx:                    newContext(map[string]string{"a.png": "png", "b.png": "png"}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid width form field",
+			ctx:                    newContext(map[string]string{"image.png": "png"}, map[string][]string{"width": {"5000"}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "width and height both 0",
+			ctx:                    newContext(map[string]string{"image.png": "png"}, map[string][]string{"width": {"0"}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid format form field",
+			ctx:                    newContext(map[string]string{"image.png": "png"}, map[string][]string{"format": {"gif"}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid quality form field",
+			ctx:                    newContext(map[string]string{"image.png": "png"}, map[string][]string{"quality": {"0"}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "transparent background with the jpeg format",
+			ctx:                    newContext(map[string]string{"image.png": "png"}, map[string][]string{"format": {"jpeg"}, "transparentBackground": {"true"}}),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid image",
+			ctx:                    newContext(map[string]string{"image.png": "foo"}, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "invalid PDF",
+			ctx:                    newContext(map[string]string{"document.pdf": "foo"}, nil),
+			imageEngine:            fakeImageEngine(t, true, nil),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from Chromium",
+			ctx:                    newContext(nil, map[string][]string{"url": {"https://foo"}}),
+			chromium:               chromiumApi(errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from LibreOffice",
+			ctx:                    newContext(map[string]string{"document.docx": "foo"}, nil),
+			libreOffice:            libreOffice(errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "error from LibreOffice (native slide export)",
+			ctx:                    newContext(map[string]string{"slides.pptx": "foo"}, map[string][]string{"nativeSlideExport": {"true"}}),
+			libreOffice:            libreOffice(errors.New("foo")),
+			expectError:            true,
+			expectHttpError:        false,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario:               "success with an image",
+			ctx:                    newContext(map[string]string{"image.png": "png"}, map[string][]string{"format": {"jpeg"}}),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario:               "success with a PDF",
+			ctx:                    newContext(map[string]string{"document.pdf": "%PDF"}, map[string][]string{"width": {"64"}, "height": {"64"}}),
+			imageEngine:            fakeImageEngine(t, false, nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario:               "success with a PDF (transparent background)",
+			ctx:                    newContext(map[string]string{"document.pdf": "%PDF"}, map[string][]string{"transparentBackground": {"true"}}),
+			imageEngine:            fakeImageEngine(t, false, nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario:               "success with an HTML file",
+			ctx:                    newContext(map[string]string{"index.html": "<p>foo</p>"}, nil),
+			chromium:               chromiumApi(nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario:               "success with a URL",
+			ctx:                    newContext(nil, map[string][]string{"url": {"https://foo"}, "width": {"0"}, "height": {"128"}}),
+			chromium:               chromiumApi(nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario:               "success with an office document",
+			ctx:                    newContext(map[string]string{"document.docx": "foo"}, nil),
+			imageEngine:            fakeImageEngine(t, false, nil),
+			libreOffice:            libreOffice(nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+		{
+			scenario:               "success with a presentation (native slide export)",
+			ctx:                    newContext(map[string]string{"slides.pptx": "foo"}, map[string][]string{"nativeSlideExport": {"true"}}),
+			libreOffice:            libreOffice(nil),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+			tc.ctx.Context.Context = context.Background()
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			if tc.chromium == nil {
+				tc.chromium = chromiumApi(nil)
+			}
+
+			if tc.libreOffice == nil {
+				tc.libreOffice = libreOffice(nil)
+			}
+
+			err := thumbnailRoute(tc.imageEngine, tc.chromium, tc.libreOffice).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HttpError
+			isHttpError := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHttpError {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHttpError {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHttpError {
+				status, _ := httpErr.HttpError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+		})
+	}
+}
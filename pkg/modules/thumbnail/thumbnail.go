@@ -0,0 +1,103 @@
+package thumbnail
+
+import (
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Thumbnail))
+}
+
+// Thumbnail is a module which provides a route for creating the preview
+// images of documents. It relies on an image engine for the PDFs, and on the
+// Chromium and LibreOffice modules for the web pages and the office
+// documents.
+type Thumbnail struct {
+	imageEngine   gotenberg.ImageEngine
+	chromium      chromium.Api
+	libreOffice   libreofficeapi.Uno
+	disableRoutes bool
+}
+
+// Descriptor returns a [Thumbnail]'s module descriptor.
+func (mod *Thumbnail) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "thumbnail",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("thumbnail", flag.ExitOnError)
+			fs.Bool("thumbnail-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Thumbnail) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Thumbnail) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("thumbnail-disable-routes")
+
+	provider, err := ctx.Module(new(chromium.Provider))
+	if err != nil {
+		return fmt.Errorf("get Chromium provider: %w", err)
+	}
+
+	chromiumApi, err := provider.(chromium.Provider).Chromium()
+	if err != nil {
+		return fmt.Errorf("get Chromium API: %w", err)
+	}
+
+	mod.chromium = chromiumApi
+
+	provider, err = ctx.Module(new(libreofficeapi.Provider))
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno provider: %w", err)
+	}
+
+	libreOffice, err := provider.(libreofficeapi.Provider).LibreOffice()
+	if err != nil {
+		return fmt.Errorf("get LibreOffice Uno: %w", err)
+	}
+
+	mod.libreOffice = libreOffice
+
+	provider, err = ctx.Module(new(gotenberg.ImageEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get image engine provider: %w", err)
+	}
+
+	imageEngine, err := provider.(gotenberg.ImageEngineProvider).ImageEngine()
+	if err != nil {
+		return fmt.Errorf("get image engine: %w", err)
+	}
+
+	mod.imageEngine = imageEngine
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *Thumbnail) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		thumbnailRoute(mod.imageEngine, mod.chromium, mod.libreOffice),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*Thumbnail)(nil)
+	_ gotenberg.Provisioner = (*Thumbnail)(nil)
+	_ api.Router            = (*Thumbnail)(nil)
+)
@@ -0,0 +1,173 @@
+package thumbnail
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	libreofficeapi "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
+)
+
+func TestThumbnail_Descriptor(t *testing.T) {
+	descriptor := new(Thumbnail).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Thumbnail))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestThumbnail_Provision(t *testing.T) {
+	// Each provider is a distinct module, so that a scenario may omit any of
+	// them.
+	chromiumProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			chromium.ProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "chromium", New: func() gotenberg.Module { return mod }}
+		}
+		mod.ChromiumMock = func() (chromium.Api, error) {
+			return new(chromium.ApiMock), err
+		}
+
+		return mod
+	}
+
+	libreOfficeProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			libreofficeapi.ProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "libreoffice", New: func() gotenberg.Module { return mod }}
+		}
+		mod.LibreOfficeMock = func() (libreofficeapi.Uno, error) {
+			return new(libreofficeapi.ApiMock), err
+		}
+
+		return mod
+	}
+
+	imageEngineProvider := func(err error) gotenberg.Module {
+		mod := &struct {
+			gotenberg.ModuleMock
+			gotenberg.ImageEngineProviderMock
+		}{}
+		mod.DescriptorMock = func() gotenberg.ModuleDescriptor {
+			return gotenberg.ModuleDescriptor{ID: "pdfengines", New: func() gotenberg.Module { return mod }}
+		}
+		mod.ImageEngineMock = func() (gotenberg.ImageEngine, error) {
+			return new(gotenberg.ImageEngineMock), err
+		}
+
+		return mod
+	}
+
+	newContext := func(mods ...gotenberg.Module) *gotenberg.Context {
+		descriptors := make([]gotenberg.ModuleDescriptor, len(mods))
+		for i, mod := range mods {
+			descriptors[i] = mod.Descriptor()
+		}
+
+		return gotenberg.NewContext(
+			gotenberg.ParsedFlags{
+				FlagSet: new(Thumbnail).Descriptor().FlagSet,
+			},
+			descriptors,
+		)
+	}
+
+	for _, tc := range []struct {
+		scenario    string
+		ctx         *gotenberg.Context
+		expectError bool
+	}{
+		{
+			scenario:    "no Chromium API provider",
+			ctx:         newContext(libreOfficeProvider(nil), imageEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no Chromium API from Chromium API provider",
+			ctx:         newContext(chromiumProvider(errors.New("foo")), libreOfficeProvider(nil), imageEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice API provider",
+			ctx:         newContext(chromiumProvider(nil), imageEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no LibreOffice API from LibreOffice API provider",
+			ctx:         newContext(chromiumProvider(nil), libreOfficeProvider(errors.New("foo")), imageEngineProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no image engine provider",
+			ctx:         newContext(chromiumProvider(nil), libreOfficeProvider(nil)),
+			expectError: true,
+		},
+		{
+			scenario:    "no image engine from image engine provider",
+			ctx:         newContext(chromiumProvider(nil), libreOfficeProvider(nil), imageEngineProvider(errors.New("foo"))),
+			expectError: true,
+		},
+		{
+			scenario:    "provision success",
+			ctx:         newContext(chromiumProvider(nil), libreOfficeProvider(nil), imageEngineProvider(nil)),
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Thumbnail)
+			err := mod.Provision(tc.ctx)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestThumbnail_Routes(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		expectRoutes  int
+		disableRoutes bool
+	}{
+		{
+			scenario:      "routes not disabled",
+			expectRoutes:  1,
+			disableRoutes: false,
+		},
+		{
+			scenario:      "routes disabled",
+			expectRoutes:  0,
+			disableRoutes: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := new(Thumbnail)
+			mod.disableRoutes = tc.disableRoutes
+
+			routes, err := mod.Routes()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectRoutes != len(routes) {
+				t.Errorf("expected %d routes but got %d", tc.expectRoutes, len(routes))
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package usage provides a module which tracks, per API key or tenant, the
+// conversions, the pages produced or estimated and the bytes output. It
+// exposes them via an HTTP route and as Prometheus metrics for internal
+// chargeback.
+//
+// The module is disabled by default. As it trusts the header identifying the
+// API key or tenant, a reverse proxy which authenticates the clients must set
+// it.
+package usage
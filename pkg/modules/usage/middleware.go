@@ -0,0 +1,60 @@
+package usage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// usageMiddleware accounts the result of a multipart request to the key of
+// the request. It runs after the webhook middleware, so that asynchronous
+// conversions are accounted once they are done. The pages estimated before
+// the conversion count even if it fails, as the work was admitted.
+func usageMiddleware(mod *Usage) api.Middleware {
+	return api.Middleware{
+		Stack:    api.MultipartStack,
+		Priority: api.VeryLowPriority,
+		Handler: func() echo.MiddlewareFunc {
+			return func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					key := c.Request().Header.Get(mod.keyHeader)
+					ctx := c.Get("context").(*api.Context)
+
+					err := next(c)
+					if errors.Is(err, api.ErrAsyncProcess) {
+						return err
+					}
+
+					estimatedPages := int64(ctx.EstimatedPageCount())
+
+					if err != nil {
+						mod.record(key, func(record *Record) {
+							record.Failures++
+							record.EstimatedPages += estimatedPages
+						})
+
+						return err
+					}
+
+					metadata, metadataErr := ctx.Metadata()
+					if metadataErr != nil {
+						// Not critical, the conversion is still accounted.
+						ctx.Log().Error(fmt.Sprintf("get usage metadata: %s", metadataErr))
+					}
+
+					mod.record(key, func(record *Record) {
+						record.Conversions++
+						record.Pages += int64(metadata.PageCount)
+						record.EstimatedPages += estimatedPages
+						record.OutputBytes += metadata.Size
+					})
+
+					return nil
+				}
+			}
+		}(),
+	}
+}
@@ -0,0 +1,136 @@
+package usage
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestUsageMiddleware(t *testing.T) {
+	for _, tc := range []struct {
+		scenario      string
+		key           string
+		pdf           bool
+		next          func(ctx *api.ContextMock) echo.HandlerFunc
+		expectKey     string
+		expectRecord  Record
+		expectRecords int
+		expectError   bool
+	}{
+		{
+			scenario: "asynchronous process",
+			key:      "foo",
+			next: func(ctx *api.ContextMock) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					return api.ErrAsyncProcess
+				}
+			},
+			expectRecords: 0,
+			expectError:   true,
+		},
+		{
+			scenario: "failure",
+			key:      "foo",
+			next: func(ctx *api.ContextMock) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					return errors.New("foo")
+				}
+			},
+			expectKey:     "foo",
+			expectRecord:  Record{Key: "foo", Failures: 1},
+			expectRecords: 1,
+			expectError:   true,
+		},
+		{
+			scenario: "failure with estimated pages",
+			key:      "foo",
+			pdf:      true,
+			next: func(ctx *api.ContextMock) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					return errors.New("foo")
+				}
+			},
+			expectKey:     "foo",
+			expectRecord:  Record{Key: "foo", Failures: 1, EstimatedPages: 42},
+			expectRecords: 1,
+			expectError:   true,
+		},
+		{
+			scenario: "success without key",
+			next: func(ctx *api.ContextMock) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					path := ctx.GeneratePath("foo", ".txt")
+
+					err := os.WriteFile(path, []byte("foo"), 0o600)
+					if err != nil {
+						return err
+					}
+
+					return ctx.AddOutputPaths(path)
+				}
+			},
+			expectKey:     anonymousKey,
+			expectRecord:  Record{Key: anonymousKey, Conversions: 1, OutputBytes: 3},
+			expectRecords: 1,
+			expectError:   false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Usage{keyHeader: "Gotenberg-Usage-Key", maxKeys: 10, records: make(map[string]*Record)}
+
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tc.key != "" {
+				req.Header.Set("Gotenberg-Usage-Key", tc.key)
+			}
+
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			dirPath := t.TempDir()
+
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetDirPath(dirPath)
+			ctx.SetLogger(zap.NewNop())
+
+			if tc.pdf {
+				path := dirPath + "/foo.pdf"
+
+				err := os.WriteFile(path, []byte("%PDF-1.7\n1 0 obj\n<< /Linearized 1 /N 42 >>\nendobj\n"), 0o600)
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+
+				ctx.SetFiles(map[string]string{"foo.pdf": path})
+			}
+			c.Set("context", ctx.Context)
+
+			err := usageMiddleware(mod).Handler(tc.next(ctx))(c)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if len(mod.records) != tc.expectRecords {
+				t.Fatalf("expected %d records but got %d", tc.expectRecords, len(mod.records))
+			}
+
+			if tc.expectRecords == 0 {
+				return
+			}
+
+			if *mod.records[tc.expectKey] != tc.expectRecord {
+				t.Errorf("expected %+v but got %+v", tc.expectRecord, *mod.records[tc.expectKey])
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package usage
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(Usage))
+}
+
+const (
+	// anonymousKey is the key of the requests without the key header.
+	anonymousKey = "anonymous"
+
+	// overflowKey is the key of the requests once the maximum number of keys
+	// is reached.
+	overflowKey = "other"
+)
+
+// Usage is a module which tracks, per API key or tenant, the conversions, the
+// pages produced, the pages estimated before the conversions, and the bytes
+// output. The counters live in memory and reset on restart.
+//
+// The module trusts the key header: a reverse proxy which authenticates the
+// clients must set it, and strip it from the incoming requests.
+type Usage struct {
+	keyHeader           string
+	maxKeys             int
+	disableRouteLogging bool
+	enable              bool
+
+	startTime time.Time
+	records   map[string]*Record
+	mu        sync.RWMutex
+}
+
+// Record gathers the usage of a key.
+type Record struct {
+	Key            string `json:"key"`
+	Conversions    int64  `json:"conversions"`
+	Failures       int64  `json:"failures"`
+	Pages          int64  `json:"pages"`
+	EstimatedPages int64  `json:"estimatedPages"`
+	OutputBytes    int64  `json:"outputBytes"`
+}
+
+// Report is the response of the usage route.
+type Report struct {
+	Since time.Time `json:"since"`
+	Keys  []Record  `json:"keys"`
+}
+
+// Descriptor returns a [Usage]'s module descriptor.
+func (mod *Usage) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "usage",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("usage", flag.ExitOnError)
+			fs.Bool("usage-enable", false, "Enable the usage accounting and the /admin/usage route - the latter requires the API admin token")
+			fs.String("usage-key-header", "Gotenberg-Usage-Key", "Set the header which identifies the API key or tenant of a request - it must be set by a trusted reverse proxy, as clients may forge it")
+			fs.Int("usage-max-keys", 1000, "Set the maximum number of tracked keys - beyond, the usage is accounted under the 'other' key")
+			fs.Bool("usage-disable-route-logging", false, "Disable the route logging")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(Usage) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *Usage) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.enable = flags.MustBool("usage-enable")
+	mod.keyHeader = flags.MustString("usage-key-header")
+	mod.maxKeys = flags.MustInt("usage-max-keys")
+	mod.disableRouteLogging = flags.MustBool("usage-disable-route-logging")
+
+	mod.startTime = time.Now()
+	mod.records = make(map[string]*Record)
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *Usage) Validate() error {
+	if !mod.enable {
+		// Exit early.
+		return nil
+	}
+
+	var err error
+
+	if mod.keyHeader == "" {
+		err = multierr.Append(err,
+			errors.New("key header must not be empty"),
+		)
+	}
+
+	if mod.maxKeys < 1 {
+		err = multierr.Append(err,
+			errors.New("maximum number of keys must be more than 0"),
+		)
+	}
+
+	return err
+}
+
+// Middlewares returns the middleware.
+func (mod *Usage) Middlewares() ([]api.Middleware, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Middleware{
+		usageMiddleware(mod),
+	}, nil
+}
+
+// Routes returns the HTTP route.
+func (mod *Usage) Routes() ([]api.Route, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []api.Route{
+		{
+			Method:         http.MethodGet,
+			Path:           "/admin/usage",
+			IsAdmin:        true,
+			DisableLogging: mod.disableRouteLogging,
+			Handler: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, mod.report())
+			},
+		},
+	}, nil
+}
+
+// Metrics returns the metrics.
+func (mod *Usage) Metrics() ([]gotenberg.Metric, error) {
+	if !mod.enable {
+		return nil, nil
+	}
+
+	return []gotenberg.Metric{
+		{
+			Name:        "usage_conversions_total",
+			Description: "Total number of successful conversions per key.",
+			Label:       "key",
+			Counter:     true,
+			ReadLabeled: mod.read(func(record Record) int64 { return record.Conversions }),
+		},
+		{
+			Name:        "usage_failures_total",
+			Description: "Total number of failed conversions per key.",
+			Label:       "key",
+			Counter:     true,
+			ReadLabeled: mod.read(func(record Record) int64 { return record.Failures }),
+		},
+		{
+			Name:        "usage_pages_total",
+			Description: "Total number of PDF pages produced per key.",
+			Label:       "key",
+			Counter:     true,
+			ReadLabeled: mod.read(func(record Record) int64 { return record.Pages }),
+		},
+		{
+			Name:        "usage_estimated_pages_total",
+			Description: "Total number of pages estimated before the conversions, failed or not, per key.",
+			Label:       "key",
+			Counter:     true,
+			ReadLabeled: mod.read(func(record Record) int64 { return record.EstimatedPages }),
+		},
+		{
+			Name:        "usage_output_bytes_total",
+			Description: "Total number of bytes output per key.",
+			Label:       "key",
+			Counter:     true,
+			ReadLabeled: mod.read(func(record Record) int64 { return record.OutputBytes }),
+		},
+	}, nil
+}
+
+// record applies the given update to the [Record] of a key.
+func (mod *Usage) record(key string, update func(record *Record)) {
+	if key == "" {
+		key = anonymousKey
+	}
+
+	mod.mu.Lock()
+	defer mod.mu.Unlock()
+
+	record, ok := mod.records[key]
+	if !ok && len(mod.records) >= mod.maxKeys {
+		key = overflowKey
+		record, ok = mod.records[key]
+	}
+
+	if !ok {
+		record = &Record{Key: key}
+		mod.records[key] = record
+	}
+
+	update(record)
+}
+
+// report returns a snapshot of the records, sorted by key.
+func (mod *Usage) report() Report {
+	mod.mu.RLock()
+	defer mod.mu.RUnlock()
+
+	report := Report{
+		Since: mod.startTime,
+		Keys:  make([]Record, 0, len(mod.records)),
+	}
+
+	for _, record := range mod.records {
+		report.Keys = append(report.Keys, *record)
+	}
+
+	sort.Slice(report.Keys, func(i, j int) bool {
+		return report.Keys[i].Key < report.Keys[j].Key
+	})
+
+	return report
+}
+
+// read returns a function which reads a value of each [Record].
+func (mod *Usage) read(value func(record Record) int64) func() map[string]float64 {
+	return func() map[string]float64 {
+		mod.mu.RLock()
+		defer mod.mu.RUnlock()
+
+		values := make(map[string]float64, len(mod.records))
+		for key, record := range mod.records {
+			values[key] = float64(value(*record))
+		}
+
+		return values
+	}
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module          = (*Usage)(nil)
+	_ gotenberg.Provisioner     = (*Usage)(nil)
+	_ gotenberg.Validator       = (*Usage)(nil)
+	_ gotenberg.MetricsProvider = (*Usage)(nil)
+	_ api.MiddlewareProvider    = (*Usage)(nil)
+	_ api.Router                = (*Usage)(nil)
+)
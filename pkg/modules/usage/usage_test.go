@@ -0,0 +1,264 @@
+package usage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestUsage_Descriptor(t *testing.T) {
+	descriptor := new(Usage).Descriptor()
+
+	actual := reflect.TypeOf(descriptor.New())
+	expect := reflect.TypeOf(new(Usage))
+
+	if actual != expect {
+		t.Errorf("expected '%s' but got '%s'", expect, actual)
+	}
+}
+
+func TestUsage_Provision(t *testing.T) {
+	mod := new(Usage)
+	ctx := gotenberg.NewContext(
+		gotenberg.ParsedFlags{
+			FlagSet: new(Usage).Descriptor().FlagSet,
+		},
+		nil,
+	)
+
+	err := mod.Provision(ctx)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if mod.enable {
+		t.Error("expected the module to be disabled by default")
+	}
+
+	if mod.keyHeader != "Gotenberg-Usage-Key" {
+		t.Errorf("expected key header 'Gotenberg-Usage-Key' but got '%s'", mod.keyHeader)
+	}
+
+	if mod.records == nil {
+		t.Error("expected initialized records")
+	}
+}
+
+func TestUsage_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario    string
+		keyHeader   string
+		maxKeys     int
+		enable      bool
+		expectError bool
+	}{
+		{
+			scenario:    "disabled",
+			enable:      false,
+			expectError: false,
+		},
+		{
+			scenario:    "empty key header",
+			enable:      true,
+			keyHeader:   "",
+			maxKeys:     1,
+			expectError: true,
+		},
+		{
+			scenario:    "invalid maximum number of keys",
+			enable:      true,
+			keyHeader:   "foo",
+			maxKeys:     0,
+			expectError: true,
+		},
+		{
+			scenario:    "validate success",
+			enable:      true,
+			keyHeader:   "foo",
+			maxKeys:     1,
+			expectError: false,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Usage{
+				keyHeader: tc.keyHeader,
+				maxKeys:   tc.maxKeys,
+				enable:    tc.enable,
+			}
+			err := mod.Validate()
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestUsage_Middlewares(t *testing.T) {
+	for _, tc := range []struct {
+		scenario          string
+		enable            bool
+		expectMiddlewares int
+	}{
+		{
+			scenario:          "disabled",
+			enable:            false,
+			expectMiddlewares: 0,
+		},
+		{
+			scenario:          "enabled",
+			enable:            true,
+			expectMiddlewares: 1,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Usage{enable: tc.enable}
+
+			middlewares, err := mod.Middlewares()
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if len(middlewares) != tc.expectMiddlewares {
+				t.Errorf("expected %d middlewares but got %d", tc.expectMiddlewares, len(middlewares))
+			}
+		})
+	}
+}
+
+func TestUsage_Routes(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		mod := new(Usage)
+
+		routes, err := mod.Routes()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(routes) != 0 {
+			t.Errorf("expected no route but got %d", len(routes))
+		}
+	})
+
+	t.Run("usage report", func(t *testing.T) {
+		mod := &Usage{enable: true, maxKeys: 10, records: make(map[string]*Record)}
+		mod.record("foo", func(record *Record) { record.Conversions++ })
+		mod.record("", func(record *Record) { record.Failures++ })
+
+		routes, err := mod.Routes()
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		if len(routes) != 1 || routes[0].Path != "/admin/usage" || !routes[0].IsAdmin {
+			t.Fatalf("expected the '/admin/usage' admin route but got %v", routes)
+		}
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/admin/usage", nil), rec)
+
+		err = routes[0].Handler(c)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		var report Report
+		err = json.Unmarshal(rec.Body.Bytes(), &report)
+		if err != nil {
+			t.Fatalf("expected no error but got: %v", err)
+		}
+
+		expect := []Record{
+			{Key: anonymousKey, Failures: 1},
+			{Key: "foo", Conversions: 1},
+		}
+
+		if !reflect.DeepEqual(report.Keys, expect) {
+			t.Errorf("expected %+v but got %+v", expect, report.Keys)
+		}
+	})
+}
+
+func TestUsage_Metrics(t *testing.T) {
+	mod := &Usage{enable: true, maxKeys: 10, records: make(map[string]*Record)}
+	mod.record("foo", func(record *Record) {
+		record.Conversions = 1
+		record.Failures = 2
+		record.Pages = 3
+		record.OutputBytes = 4
+		record.EstimatedPages = 5
+	})
+
+	metrics, err := mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	expect := map[string]float64{
+		"usage_conversions_total":     1,
+		"usage_failures_total":        2,
+		"usage_pages_total":           3,
+		"usage_output_bytes_total":    4,
+		"usage_estimated_pages_total": 5,
+	}
+
+	if len(metrics) != len(expect) {
+		t.Fatalf("expected %d metrics but got %d", len(expect), len(metrics))
+	}
+
+	for _, metric := range metrics {
+		if metric.Label != "key" || !metric.Counter {
+			t.Errorf("expected metric '%s' to be a counter labeled by key", metric.Name)
+		}
+
+		actual := metric.ReadLabeled()["foo"]
+		if actual != expect[metric.Name] {
+			t.Errorf("expected %f for metric '%s' but got %f", expect[metric.Name], metric.Name, actual)
+		}
+	}
+
+	mod.enable = false
+
+	metrics, err = mod.Metrics()
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if len(metrics) != 0 {
+		t.Errorf("expected no metric but got %d", len(metrics))
+	}
+}
+
+func TestUsage_record(t *testing.T) {
+	mod := &Usage{maxKeys: 2, records: make(map[string]*Record)}
+
+	for _, key := range []string{"foo", "bar", "baz", "qux", "foo"} {
+		mod.record(key, func(record *Record) { record.Conversions++ })
+	}
+
+	expect := map[string]int64{
+		"foo":       2,
+		"bar":       1,
+		overflowKey: 2,
+	}
+
+	if len(mod.records) != len(expect) {
+		t.Fatalf("expected %d records but got %d", len(expect), len(mod.records))
+	}
+
+	for key, conversions := range expect {
+		if mod.records[key].Conversions != conversions {
+			t.Errorf("expected %d conversions for key '%s' but got %d", conversions, key, mod.records[key].Conversions)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package webhook
 
 import (
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,6 +9,8 @@ import (
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
 
 // client gathers all the data required to send a request to a webhook.
@@ -19,14 +20,18 @@ type client struct {
 	errorUrl         string
 	errorMethod      string
 	extraHttpHeaders map[string]string
+	authorization    string
 	startTime        time.Time
 
 	client *retryablehttp.Client
 	logger *zap.Logger
 }
 
-// send call the webhook either to send the success response or the error response.
-func (c client) send(body io.Reader, headers map[string]string, erroed bool) error {
+// send call the webhook either to send the success response or the error
+// response. The body is any type accepted by [retryablehttp.NewRequest]:
+// readers which are neither seekers nor [retryablehttp.ReaderFunc] are fully
+// buffered in memory.
+func (c client) send(body interface{}, headers map[string]string, erroed bool) error {
 	URL := c.url
 	if erroed {
 		URL = c.errorUrl
@@ -49,12 +54,18 @@ func (c client) send(body io.Reader, headers map[string]string, erroed bool) err
 		req.Header.Set(key, value)
 	}
 
+	// Authorization from the configuration > extra HTTP headers from the
+	// user.
+	if c.authorization != "" {
+		req.Header.Set(echo.HeaderAuthorization, c.authorization)
+	}
+
 	// Middleware caller's headers > extra HTTP headers from the user.
 
 	contentLength, ok := headers[echo.HeaderContentLength]
 	if ok {
 		// Golang "http" package should automatically calculate the size of the
-		// body. But, when using a buffered file reader, it does not work.
+		// body. But, when using a file reader, it does not work.
 		// Worse, the "Content-Length" header is also removed. Therefore, in
 		// order to keep this valuable information, we have to trust the caller
 		// by reading the value of the "Content-Length" entry and set it as the
@@ -111,6 +122,42 @@ func (c client) send(body io.Reader, headers map[string]string, erroed bool) err
 	return nil
 }
 
+// faultTransport is an [http.RoundTripper] which lets a fault injector fail
+// the calls to the webhooks. It applies to each attempt, so that the faults
+// exercise the retries.
+type faultTransport struct {
+	injector gotenberg.FaultInjector
+	next     http.RoundTripper
+}
+
+// RoundTrip applies the faults before sending the request.
+func (t faultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	err := t.injector.InjectFault(req.Context(), gotenberg.FaultPointWebhook, req.URL.String())
+	if err != nil {
+		// A round tripper always closes the body.
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+
+		return nil, err
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// transport returns the [http.RoundTripper] of the clients of the webhooks,
+// or nil for the default one.
+func (w *Webhook) transport() http.RoundTripper {
+	if w.faultInjector == nil {
+		return nil
+	}
+
+	return faultTransport{
+		injector: w.faultInjector,
+		next:     http.DefaultTransport,
+	}
+}
+
 // leveledLogger is wrapper around a [zap.Logger] which is used by the
 // [retryablehttp.Client].
 type leveledLogger struct {
@@ -1,11 +1,194 @@
 package webhook
 
 import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
 
+func TestClient_send(t *testing.T) {
+	for _, tc := range []struct {
+		scenario            string
+		body                func() interface{}
+		headers             map[string]string
+		expectBody          string
+		expectContentLength int64
+		expectAttempts      int
+	}{
+		{
+			scenario: "file body",
+			body: func() interface{} {
+				f, err := os.Open("/tests/test/testdata/api/sample1.txt")
+				if err != nil {
+					t.Fatalf("expected no error but got: %v", err)
+				}
+				t.Cleanup(func() {
+					_ = f.Close()
+				})
+				return f
+			},
+			headers:             map[string]string{echo.HeaderContentLength: "3"},
+			expectBody:          "foo",
+			expectContentLength: 3,
+			expectAttempts:      2,
+		},
+		{
+			scenario: "streamed body",
+			body: func() interface{} {
+				return retryablehttp.ReaderFunc(func() (io.Reader, error) {
+					pr, pw := io.Pipe()
+					go func() {
+						_, err := io.Copy(pw, strings.NewReader("foo"))
+						pw.CloseWithError(err)
+					}()
+
+					return pr, nil
+				})
+			},
+			expectBody:          "foo",
+			expectContentLength: -1,
+			expectAttempts:      2,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var attempts int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+
+				b, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("expected no error but got: %v", err)
+				}
+
+				if string(b) != tc.expectBody {
+					t.Errorf("expected body '%s' but got '%s'", tc.expectBody, string(b))
+				}
+
+				if r.ContentLength != tc.expectContentLength {
+					t.Errorf("expected content length %d but got %d", tc.expectContentLength, r.ContentLength)
+				}
+
+				// The first attempt fails, so that the body is sent again.
+				if attempts == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer srv.Close()
+
+			c := client{
+				url:       srv.URL,
+				method:    http.MethodPost,
+				startTime: time.Now(),
+				client: &retryablehttp.Client{
+					HTTPClient:   srv.Client(),
+					RetryMax:     1,
+					RetryWaitMin: 0,
+					RetryWaitMax: 0,
+					Logger:       leveledLogger{logger: zap.NewNop()},
+					CheckRetry:   retryablehttp.DefaultRetryPolicy,
+					Backoff:      retryablehttp.DefaultBackoff,
+				},
+				logger: zap.NewNop(),
+			}
+
+			err := c.send(tc.body(), tc.headers, false)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if attempts != tc.expectAttempts {
+				t.Errorf("expected %d attempts but got %d", tc.expectAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestFaultTransport_RoundTrip(t *testing.T) {
+	var received int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var faults int
+
+	w := &Webhook{
+		faultInjector: &gotenberg.FaultInjectorMock{
+			InjectFaultMock: func(ctx context.Context, point, target string) error {
+				if point != gotenberg.FaultPointWebhook || target != srv.URL {
+					t.Errorf("unexpected point '%s' and target '%s'", point, target)
+				}
+
+				// The first attempt times out.
+				faults++
+				if faults == 1 {
+					return context.DeadlineExceeded
+				}
+
+				return nil
+			},
+		},
+	}
+
+	c := client{
+		url:    srv.URL,
+		method: http.MethodPost,
+		client: &retryablehttp.Client{
+			HTTPClient: &http.Client{
+				Transport: w.transport(),
+			},
+			RetryMax:     2,
+			RetryWaitMin: time.Millisecond,
+			RetryWaitMax: time.Millisecond,
+			CheckRetry:   retryablehttp.DefaultRetryPolicy,
+			Backoff:      retryablehttp.DefaultBackoff,
+		},
+		logger: zap.NewNop(),
+	}
+
+	err := c.send(strings.NewReader("foo"), map[string]string{}, false)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if faults != 2 || received != 1 {
+		t.Errorf("expected 2 attempts and 1 received request but got %d and %d", faults, received)
+	}
+
+	if new(Webhook).transport() != nil {
+		t.Error("expected the default transport without a fault injector")
+	}
+
+	_, err = faultTransport{
+		injector: &gotenberg.FaultInjectorMock{
+			InjectFaultMock: func(ctx context.Context, point, target string) error {
+				return context.DeadlineExceeded
+			},
+		},
+		next: http.DefaultTransport,
+	}.RoundTrip(httptest.NewRequest(http.MethodPost, srv.URL, strings.NewReader("foo")))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a timeout but got: %v", err)
+	}
+}
+
 func TestLeveledLogger_Error(t *testing.T) {
 	leveledLogger{logger: zap.NewNop()}.Error("foo")
 }
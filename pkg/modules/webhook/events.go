@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// The lifecycle events of an asynchronous conversion.
+const (
+	// eventQueued happens when the request has been accepted.
+	eventQueued = "queued"
+
+	// eventStarted happens when the conversion starts, e.g., after having
+	// waited for a concurrency slot.
+	eventStarted = "started"
+
+	// eventProgress happens at regular intervals while the conversion runs.
+	eventProgress = "progress"
+
+	// eventFailed happens when either the conversion or the upload of its
+	// result fails.
+	eventFailed = "failed"
+
+	// eventRetried happens before each new attempt to upload the result, or
+	// the error, to the webhook.
+	eventRetried = "retried"
+)
+
+var allEvents = []string{eventQueued, eventStarted, eventProgress, eventFailed, eventRetried}
+
+// event is the JSON body of a request to the events URL.
+type event struct {
+	Event   string             `json:"event"`
+	Trace   string             `json:"trace"`
+	Elapsed string             `json:"elapsed"`
+	Attempt int                `json:"attempt,omitempty"`
+	Url     string             `json:"url,omitempty"`
+	Error   *api.ErrorResponse `json:"error,omitempty"`
+}
+
+// parseEvents parses a comma-separated list of events. Empty means all the
+// events.
+func parseEvents(value string) (map[string]bool, error) {
+	filter := make(map[string]bool)
+
+	if strings.TrimSpace(value) == "" {
+		for _, name := range allEvents {
+			filter[name] = true
+		}
+
+		return filter, nil
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if !slices.Contains(allEvents, name) {
+			return nil, fmt.Errorf("event '%s' is not one of %s", name, strings.Join(allEvents, ", "))
+		}
+
+		filter[name] = true
+	}
+
+	return filter, nil
+}
+
+// emitter sends the lifecycle events of an asynchronous conversion to the
+// events URL. A nil emitter sends nothing.
+type emitter struct {
+	client      client
+	filter      map[string]bool
+	traceHeader string
+	trace       string
+	done        chan struct{}
+
+	// mu keeps the events in order, as the progress events come from
+	// another goroutine.
+	mu sync.Mutex
+}
+
+// newEmitter returns an [emitter] sending the filtered events with the given
+// client.
+func newEmitter(client client, filter map[string]bool, traceHeader, trace string) *emitter {
+	return &emitter{
+		client:      client,
+		filter:      filter,
+		traceHeader: traceHeader,
+		trace:       trace,
+		done:        make(chan struct{}),
+	}
+}
+
+// emit sends an event, if the client asked for it. A failure is only logged,
+// as the events must not fail the conversion.
+func (e *emitter) emit(evt event) {
+	if e == nil || !e.filter[evt.Event] {
+		return
+	}
+
+	evt.Trace = e.trace
+	evt.Elapsed = time.Since(e.client.startTime).Round(time.Millisecond).String()
+
+	b, err := json.Marshal(evt)
+	if err != nil {
+		e.client.logger.Error(fmt.Sprintf("marshal '%s' event: %s", evt.Event, err))
+
+		return
+	}
+
+	headers := map[string]string{
+		echo.HeaderContentType:    echo.MIMEApplicationJSONCharsetUTF8,
+		"Gotenberg-Webhook-Event": evt.Event,
+		e.traceHeader:             e.trace,
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err = e.client.send(bytes.NewReader(b), headers, false)
+	if err != nil {
+		e.client.logger.Error(fmt.Sprintf("send '%s' event to webhook: %s", evt.Event, err))
+	}
+}
+
+// progress sends progress events at the given interval until the emitter is
+// closed. A zero interval disables the progress events.
+func (e *emitter) progress(interval time.Duration) {
+	if e == nil || interval <= 0 || !e.filter[eventProgress] {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+			e.emit(event{Event: eventProgress})
+		}
+	}
+}
+
+// close stops the progress events.
+func (e *emitter) close() {
+	if e == nil {
+		return
+	}
+
+	close(e.done)
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func TestParseEvents(t *testing.T) {
+	for _, tc := range []struct {
+		scenario     string
+		value        string
+		expectFilter map[string]bool
+		expectError  bool
+	}{
+		{
+			scenario: "all events",
+			value:    " ",
+			expectFilter: map[string]bool{
+				eventQueued:   true,
+				eventStarted:  true,
+				eventProgress: true,
+				eventFailed:   true,
+				eventRetried:  true,
+			},
+		},
+		{
+			scenario:     "some events",
+			value:        "Started, failed",
+			expectFilter: map[string]bool{eventStarted: true, eventFailed: true},
+		},
+		{
+			scenario:    "unknown event",
+			value:       "started,foo",
+			expectError: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			filter, err := parseEvents(tc.value)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !reflect.DeepEqual(filter, tc.expectFilter) {
+				t.Errorf("expected %v but got %v", tc.expectFilter, filter)
+			}
+		})
+	}
+}
+
+func TestEmitter(t *testing.T) {
+	var mu sync.Mutex
+	var received []event
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var evt event
+
+		err := json.NewDecoder(r.Body).Decode(&evt)
+		if err != nil {
+			t.Errorf("expected no error but got: %v", err)
+		}
+
+		if r.Header.Get("Gotenberg-Webhook-Event") != evt.Event {
+			t.Errorf("expected header '%s' but got '%s'", evt.Event, r.Header.Get("Gotenberg-Webhook-Event"))
+		}
+
+		if r.Header.Get("Gotenberg-Trace") != "foo" {
+			t.Errorf("expected trace 'foo' but got '%s'", r.Header.Get("Gotenberg-Trace"))
+		}
+
+		mu.Lock()
+		received = append(received, evt)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	events := newEmitter(
+		client{
+			url:       srv.URL,
+			method:    http.MethodPost,
+			startTime: time.Now(),
+			client: &retryablehttp.Client{
+				HTTPClient: srv.Client(),
+				RetryMax:   0,
+				CheckRetry: retryablehttp.DefaultRetryPolicy,
+				Backoff:    retryablehttp.DefaultBackoff,
+			},
+			logger: zap.NewNop(),
+		},
+		map[string]bool{eventStarted: true, eventProgress: true, eventFailed: true},
+		"Gotenberg-Trace",
+		"foo",
+	)
+
+	events.emit(event{Event: eventQueued})
+	events.emit(event{Event: eventStarted})
+
+	done := make(chan struct{})
+	go func() {
+		events.progress(time.Duration(10) * time.Millisecond)
+		close(done)
+	}()
+
+	time.Sleep(time.Duration(50) * time.Millisecond)
+	events.close()
+	<-done
+
+	events.emit(event{Event: eventFailed, Error: &api.ErrorResponse{Code: "FOO", Status: http.StatusInternalServerError}})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(received) < 3 {
+		t.Fatalf("expected at least 3 events but got %d", len(received))
+	}
+
+	if received[0].Event != eventStarted {
+		t.Errorf("expected first event '%s' but got '%s'", eventStarted, received[0].Event)
+	}
+
+	for _, evt := range received[1 : len(received)-1] {
+		if evt.Event != eventProgress {
+			t.Errorf("expected event '%s' but got '%s'", eventProgress, evt.Event)
+		}
+	}
+
+	last := received[len(received)-1]
+	if last.Event != eventFailed || last.Error == nil || last.Error.Code != "FOO" {
+		t.Errorf("expected a failed event with the error but got %+v", last)
+	}
+
+	for _, evt := range received {
+		if evt.Trace != "foo" || evt.Elapsed == "" {
+			t.Errorf("expected the trace and the elapsed time but got %+v", evt)
+		}
+	}
+
+	// A nil emitter sends nothing.
+	var noEvents *emitter
+	noEvents.emit(event{Event: eventStarted})
+	noEvents.progress(time.Second)
+	noEvents.close()
+}
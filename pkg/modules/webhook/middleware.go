@@ -1,12 +1,12 @@
 package webhook
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strconv"
@@ -23,6 +23,9 @@ import (
 func webhookMiddleware(w *Webhook) api.Middleware {
 	return api.Middleware{
 		Stack: api.MultipartStack,
+		// Above the very low priority middlewares, so that they run within
+		// the asynchronous process.
+		Priority: api.LowPriority,
 		Handler: func() echo.MiddlewareFunc {
 			return func(next echo.HandlerFunc) echo.HandlerFunc {
 				return func(c echo.Context) error {
@@ -33,6 +36,11 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 					}
 
 					ctx := c.Get("context").(*api.Context)
+					if ctx.ValidateOnly() {
+						// A pre-flight request does not convert anything,
+						// the report is returned synchronously.
+						return next(c)
+					}
 					cancel := c.Get("cancel").(context.CancelFunc)
 
 					// Do we have a webhook error URL in case of... error?
@@ -40,7 +48,7 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 					if webhookErrorUrl == "" {
 						return api.WrapError(
 							errors.New("empty webhook error URL"),
-							api.NewSentinelHttpError(http.StatusBadRequest, "Invalid 'Gotenberg-Webhook-Error-Url' header: empty value or header not provided"),
+							api.NewSentinelHttpError(http.StatusBadRequest, "Invalid 'Gotenberg-Webhook-Error-Url' header: empty value or header not provided").WithCode(api.ErrorCodeWebhookInvalidErrorUrl),
 						)
 					}
 
@@ -85,7 +93,7 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 							api.NewSentinelHttpError(
 								http.StatusBadRequest,
 								fmt.Sprintf("Invalid '%s' header value: expected '%s', '%s' or '%s', but got '%s'", header, http.MethodPost, http.MethodPatch, http.MethodPut, method),
-							),
+							).WithCode(api.ErrorCodeWebhookInvalidMethod),
 						)
 					}
 
@@ -108,22 +116,103 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 						if err != nil {
 							return api.WrapError(
 								fmt.Errorf("unmarshal webhook extra HTTP headers: %w", err),
-								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'Gotenberg-Webhook-Extra-Http-Headers' header value: %s", err.Error())),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'Gotenberg-Webhook-Extra-Http-Headers' header value: %s", err.Error())).WithCode(api.ErrorCodeWebhookInvalidExtraHttpHeaders),
 							)
 						}
 					}
 
+					// Does the client want a signed URL instead of the output
+					// file?
+					var resultUrl bool
+
+					resultUrlHeader := c.Request().Header.Get("Gotenberg-Webhook-Result-Url")
+					if resultUrlHeader != "" {
+						resultUrl, err = strconv.ParseBool(resultUrlHeader)
+						if err != nil {
+							return api.WrapError(
+								fmt.Errorf("parse webhook result URL header: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'Gotenberg-Webhook-Result-Url' header value: expected a boolean, but got '%s'", resultUrlHeader)).WithCode(api.ErrorCodeWebhookInvalidResultUrl),
+							)
+						}
+					}
+
+					if resultUrl && w.resultStore == nil {
+						return api.WrapError(
+							gotenberg.ErrResultStoreDisabled,
+							api.NewSentinelHttpError(http.StatusBadRequest, "Invalid 'Gotenberg-Webhook-Result-Url' header value: result URLs are disabled").WithCode(api.ErrorCodeWebhookResultUrlDisabled),
+						)
+					}
+
+					// Does the client want to track the lifecycle of the
+					// conversion?
+					eventsUrl := c.Request().Header.Get("Gotenberg-Webhook-Events-Url")
+					eventsHeader := c.Request().Header.Get("Gotenberg-Webhook-Events")
+
+					if eventsUrl == "" && eventsHeader != "" {
+						return api.WrapError(
+							errors.New("webhook events without events URL"),
+							api.NewSentinelHttpError(http.StatusBadRequest, "Invalid 'Gotenberg-Webhook-Events' header: requires the 'Gotenberg-Webhook-Events-Url' header").WithCode(api.ErrorCodeWebhookInvalidEventsUrl),
+						)
+					}
+
+					var events *emitter
+
+					if eventsUrl != "" {
+						err = gotenberg.FilterDeadline(w.allowList, w.denyList, eventsUrl, deadline)
+						if err != nil {
+							return fmt.Errorf("filter webhook events URL: %w", err)
+						}
+
+						filter, err := parseEvents(eventsHeader)
+						if err != nil {
+							return api.WrapError(
+								fmt.Errorf("parse webhook events: %w", err),
+								api.NewSentinelHttpError(http.StatusBadRequest, fmt.Sprintf("Invalid 'Gotenberg-Webhook-Events' header value: %s", err)).WithCode(api.ErrorCodeWebhookInvalidEvents),
+							)
+						}
+
+						// The events are not retried, so that they do not
+						// delay the conversion.
+						events = newEmitter(
+							client{
+								url:              eventsUrl,
+								method:           http.MethodPost,
+								extraHttpHeaders: extraHTTPHeaders,
+								authorization:    w.authorization,
+								startTime:        c.Get("startTime").(time.Time),
+
+								client: &retryablehttp.Client{
+									HTTPClient: &http.Client{
+										Timeout: w.clientTimeout,
+									},
+									RetryMax: 0,
+									Logger: leveledLogger{
+										logger: ctx.Log(),
+									},
+									CheckRetry: retryablehttp.DefaultRetryPolicy,
+									Backoff:    retryablehttp.DefaultBackoff,
+								},
+								logger: ctx.Log(),
+							},
+							filter,
+							c.Get("traceHeader").(string),
+							c.Get("trace").(string),
+						)
+					}
+
 					client := &client{
 						url:              webhookUrl,
 						method:           webhookMethod,
 						errorUrl:         webhookErrorUrl,
 						errorMethod:      webhookErrorMethod,
 						extraHttpHeaders: extraHTTPHeaders,
+						authorization:    w.authorization,
 						startTime:        c.Get("startTime").(time.Time),
 
 						client: &retryablehttp.Client{
 							HTTPClient: &http.Client{
-								Timeout: w.clientTimeout,
+								Timeout:   w.clientTimeout,
+								Transport: w.transport(),
 							},
 							RetryMax:     w.maxRetry,
 							RetryWaitMin: w.retryMinWait,
@@ -137,21 +226,82 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 						logger: ctx.Log(),
 					}
 
+					if events != nil {
+						client.client.RequestLogHook = func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+							if attempt > 0 {
+								events.emit(event{Event: eventRetried, Attempt: attempt, Url: req.URL.String()})
+							}
+						}
+
+						ctx.OnStart(func() {
+							events.emit(event{Event: eventStarted})
+							go events.progress(w.eventsProgressInterval)
+						})
+					}
+
+					// Does a tracker record the lifecycle of the
+					// asynchronous requests?
+					job := gotenberg.Job{
+						ID:        c.Get("trace").(string),
+						Path:      c.Request().URL.Path,
+						State:     gotenberg.JobStateQueued,
+						CreatedAt: time.Now().UTC(),
+					}
+
+					track := func(update func(job *gotenberg.Job)) {
+						if w.jobTracker == nil {
+							return
+						}
+
+						update(&job)
+						job.UpdatedAt = time.Now().UTC()
+						w.jobTracker.TrackJob(job)
+					}
+
+					// Record the queued job.
+					track(func(job *gotenberg.Job) {})
+					ctx.OnStart(func() {
+						track(func(job *gotenberg.Job) {
+							job.State = gotenberg.JobStateRunning
+						})
+					})
+
+					// keepResult keeps a copy of the output file for the
+					// tracked jobs, so that their clients may recover it if
+					// the delivery fails.
+					keepResult := func(outputPath string) *gotenberg.StoredResult {
+						if w.jobTracker == nil || w.resultStore == nil {
+							return nil
+						}
+
+						result, err := w.resultStore.StoreResult(outputPath, ctx.OutputFilename(outputPath))
+						if err != nil {
+							if !errors.Is(err, gotenberg.ErrResultStoreDisabled) {
+								ctx.Log().Error(fmt.Sprintf("keep output file: %s", err))
+							}
+
+							return nil
+						}
+
+						return &result
+					}
+
+					// The last error of the asynchronous process, if any.
+					var asyncErr error
+
 					// This method parses an "asynchronous" error and sends a
 					// request to the webhook error URL with a JSON body
-					// containing the status and the error message.
+					// containing the error code, the status and the error
+					// message. The error is also sent to the error reporters,
+					// if any.
 					handleAsyncError := func(err error) {
-						status, message := api.ParseError(err)
-
-						body := struct {
-							Status  int    `json:"status"`
-							Message string `json:"message"`
-						}{
-							Status:  status,
-							Message: message,
-						}
+						asyncErr = err
+						ctx.ReportError(err)
+
+						response := api.ParseErrorResponse(err)
+						events.emit(event{Event: eventFailed, Error: &response})
 
-						b, err := json.Marshal(body)
+						b, err := json.Marshal(response)
 						if err != nil {
 							ctx.Log().Error(fmt.Sprintf("marshal JSON: %s", err.Error()))
 
@@ -171,11 +321,69 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 
 					// As a webhook URL has been given, we handle the request in a
 					// goroutine and return immediately.
+					done := make(chan struct{})
+
 					go func() {
+						defer close(done)
 						defer cancel()
 
+						var (
+							converted bool
+							kept      *gotenberg.StoredResult
+						)
+
+						defer func() {
+							track(func(job *gotenberg.Job) {
+								if !converted {
+									job.State = gotenberg.JobStateFailed
+									job.Error = api.ParseErrorResponse(asyncErr).Message
+
+									return
+								}
+
+								job.State = gotenberg.JobStateDone
+								job.Delivered = asyncErr == nil
+								job.Result = kept
+
+								if asyncErr != nil {
+									job.Error = asyncErr.Error()
+								}
+							})
+						}()
+
+						// delivered returns the job as it will be once the
+						// webhook acknowledges the delivery.
+						delivered := func() gotenberg.Job {
+							snapshot := job
+							snapshot.State = gotenberg.JobStateDone
+							snapshot.Delivered = true
+							snapshot.Result = kept
+							snapshot.Error = ""
+							snapshot.UpdatedAt = time.Now().UTC()
+
+							return snapshot
+						}
+
+						// A delivery kept in the outbox is retried later, so
+						// that it is not an error for the error webhook.
+						handleDeliveryError := func(msg string, persisted bool, err error) {
+							if !persisted {
+								ctx.Log().Error(fmt.Sprintf("%s: %s", msg, err))
+								handleAsyncError(err)
+
+								return
+							}
+
+							ctx.Log().Warn(fmt.Sprintf("%s, delivery kept in the outbox: %s", msg, err))
+							asyncErr = err
+						}
+
+						events.emit(event{Event: eventQueued})
+
 						// Call the next middleware in the chain.
 						err := next(c)
+						events.close()
+						converted = err == nil
 						if err != nil {
 							// The process failed for whatever reason. Let's send the
 							// details to the webhook.
@@ -185,6 +393,40 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 							return
 						}
 
+						if !resultUrl && w.streamArchive && ctx.IsOutputArchive() {
+							// Stream the archive to the webhook while
+							// creating it. Its size is unknown, hence no
+							// "Content-Length" header.
+							archivePath := ctx.GeneratePath("", ".zip")
+							headers := map[string]string{
+								echo.HeaderContentDisposition: fmt.Sprintf("attachement; filename=%q", ctx.OutputFilename(archivePath)),
+								echo.HeaderContentType:        "application/zip",
+								c.Get("traceHeader").(string): c.Get("trace").(string),
+							}
+
+							for key, value := range ctx.OutputHeaders(archivePath) {
+								headers[key] = value
+							}
+
+							// Each attempt creates the archive anew.
+							body := retryablehttp.ReaderFunc(func() (io.Reader, error) {
+								pr, pw := io.Pipe()
+								go func() {
+									pw.CloseWithError(ctx.WriteOutputArchive(pw))
+								}()
+
+								return pr, nil
+							})
+
+							err = client.send(body, headers, false)
+							if err != nil {
+								ctx.Log().Error(fmt.Sprintf("send output archive to webhook: %s", err))
+								handleAsyncError(err)
+							}
+
+							return
+						}
+
 						// No error, let's get build the output file.
 						outputPath, err := ctx.BuildOutputFile()
 						if err != nil {
@@ -194,6 +436,64 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 							return
 						}
 
+						if resultUrl {
+							// Send the signed URL of the output file to the
+							// webhook instead of the file itself.
+							result, err := w.resultStore.StoreResult(outputPath, ctx.OutputFilename(outputPath))
+							if err != nil {
+								if errors.Is(err, gotenberg.ErrResultStoreDisabled) {
+									err = api.WrapError(
+										err,
+										api.NewSentinelHttpError(http.StatusBadRequest, "Invalid 'Gotenberg-Webhook-Result-Url' header value: result URLs are disabled").WithCode(api.ErrorCodeWebhookResultUrlDisabled),
+									)
+								}
+
+								ctx.Log().Error(fmt.Sprintf("store output file: %s", err))
+								handleAsyncError(err)
+
+								return
+							}
+
+							kept = &result
+
+							b, err := json.Marshal(result)
+							if err != nil {
+								ctx.Log().Error(fmt.Sprintf("marshal JSON: %s", err))
+								handleAsyncError(err)
+
+								return
+							}
+
+							payloadPath := ctx.GeneratePath("", ".json")
+
+							err = os.WriteFile(payloadPath, b, 0o600)
+							if err != nil {
+								ctx.Log().Error(fmt.Sprintf("write result URL: %s", err))
+								handleAsyncError(err)
+
+								return
+							}
+
+							headers := map[string]string{
+								echo.HeaderContentType:        echo.MIMEApplicationJSONCharsetUTF8,
+								echo.HeaderContentLength:      strconv.Itoa(len(b)),
+								c.Get("traceHeader").(string): c.Get("trace").(string),
+							}
+
+							for key, value := range ctx.OutputHeaders(outputPath) {
+								headers[key] = value
+							}
+
+							persisted, err := w.deliver(client, delivered(), headers, payloadPath)
+							if err != nil {
+								handleDeliveryError("send result URL to webhook", persisted, err)
+							}
+
+							return
+						}
+
+						kept = keepResult(outputPath)
+
 						outputFile, err := os.Open(outputPath)
 						if err != nil {
 							ctx.Log().Error(fmt.Sprintf("open output file: %s", err))
@@ -226,29 +526,32 @@ func webhookMiddleware(w *Webhook) api.Middleware {
 							return
 						}
 
-						_, err = outputFile.Seek(0, 0)
-						if err != nil {
-							ctx.Log().Error(fmt.Sprintf("reset output file reader: %s", err))
-							handleAsyncError(err)
-
-							return
+						contentType := ctx.OutputContentType()
+						if contentType == "" {
+							contentType = http.DetectContentType(fileHeader)
 						}
 
 						headers := map[string]string{
-							echo.HeaderContentDisposition: fmt.Sprintf("attachement; filename=%q", ctx.OutputFilename(outputPath)),
-							echo.HeaderContentType:        http.DetectContentType(fileHeader),
+							echo.HeaderContentDisposition: ctx.OutputContentDisposition(outputPath),
+							echo.HeaderContentType:        contentType,
 							echo.HeaderContentLength:      strconv.FormatInt(fileStat.Size(), 10),
 							c.Get("traceHeader").(string): c.Get("trace").(string),
 						}
 
-						// Send the output file to the webhook.
-						err = client.send(bufio.NewReader(outputFile), headers, false)
+						for key, value := range ctx.OutputHeaders(outputPath) {
+							headers[key] = value
+						}
+
+						persisted, err := w.deliver(client, delivered(), headers, outputPath)
 						if err != nil {
-							ctx.Log().Error(fmt.Sprintf("send output file to webhook: %s", err))
-							handleAsyncError(err)
+							handleDeliveryError("send output file to webhook", persisted, err)
 						}
 					}()
 
+					if c.Request().Header.Get(gotenberg.ReplicaHeader) != "" {
+						<-done
+					}
+
 					return api.ErrAsyncProcess
 				}
 			}
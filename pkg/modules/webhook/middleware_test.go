@@ -263,6 +263,84 @@ func TestWebhookMiddlewareGuards(t *testing.T) {
 			expectHttpError:  true,
 			expectHttpStatus: http.StatusBadRequest,
 		},
+		{
+			scenario: "invalid webhook result URL header",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Result-Url", "foo")
+				return req
+			}(),
+			mod:              buildWebhookModule(),
+			noDeadline:       false,
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "webhook result URL without result store",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Result-Url", "true")
+				return req
+			}(),
+			mod:              buildWebhookModule(),
+			noDeadline:       false,
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "webhook events without events URL",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Events", "started")
+				return req
+			}(),
+			mod:              buildWebhookModule(),
+			noDeadline:       false,
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
+		{
+			scenario: "webhook events URL is denied",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Events-Url", "baz")
+				return req
+			}(),
+			mod: func() *Webhook {
+				mod := buildWebhookModule()
+				mod.denyList = regexp2.MustCompile("baz", 0)
+				return mod
+			}(),
+			noDeadline:  false,
+			expectError: true,
+		},
+		{
+			scenario: "invalid webhook events",
+			request: func() *http.Request {
+				req := buildMultipartFormDataRequest()
+				req.Header.Set("Gotenberg-Webhook-Url", "foo")
+				req.Header.Set("Gotenberg-Webhook-Error-Url", "bar")
+				req.Header.Set("Gotenberg-Webhook-Events-Url", "baz")
+				req.Header.Set("Gotenberg-Webhook-Events", "started,foo")
+				return req
+			}(),
+			mod:              buildWebhookModule(),
+			noDeadline:       false,
+			expectError:      true,
+			expectHttpError:  true,
+			expectHttpStatus: http.StatusBadRequest,
+		},
 	} {
 		t.Run(tc.scenario, func(t *testing.T) {
 			srv := echo.New()
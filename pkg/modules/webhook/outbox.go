@@ -0,0 +1,195 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-retryablehttp"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// deliveryIdHeader is the header which carries the ID of a delivery, for
+// the webhooks to ignore the deliveries repeated after a restart.
+const deliveryIdHeader = "Gotenberg-Webhook-Delivery-Id"
+
+// deliver sends a payload to the webhook. With an outbox, it persists the
+// delivery beforehand and removes it once the webhook acknowledges it, i.e.,
+// answers with a non-error status. A delivery the webhook does not
+// acknowledge stays in the outbox, and the returned boolean tells so.
+func (w *Webhook) deliver(c *client, job gotenberg.Job, headers map[string]string, payloadPath string) (bool, error) {
+	delivery := gotenberg.Delivery{
+		ID:               uuid.NewString(),
+		Job:              job,
+		Url:              c.url,
+		Method:           c.method,
+		Headers:          headers,
+		ExtraHttpHeaders: c.extraHttpHeaders,
+		CreatedAt:        time.Now().UTC(),
+	}
+
+	headers[deliveryIdHeader] = delivery.ID
+
+	var persisted bool
+
+	if w.outbox != nil {
+		// The redelivery loop must not pick up this delivery while the
+		// retries of the current attempt are not over.
+		w.claim(delivery.ID)
+		defer w.release(delivery.ID)
+
+		err := w.outbox.PutDelivery(delivery, payloadPath)
+		if err != nil && !errors.Is(err, gotenberg.ErrOutboxDisabled) {
+			return false, fmt.Errorf("persist delivery: %w", err)
+		}
+
+		persisted = err == nil
+	}
+
+	payload, err := os.Open(payloadPath)
+	if err != nil {
+		return persisted, fmt.Errorf("open payload: %w", err)
+	}
+
+	defer func() {
+		err := payload.Close()
+		if err != nil {
+			c.logger.Error(fmt.Sprintf("close payload: %s", err))
+		}
+	}()
+
+	// As a seeker, the payload is streamed from the disk on each attempt,
+	// instead of being buffered in memory.
+	err = c.send(payload, headers, false)
+	if err != nil {
+		return persisted, err
+	}
+
+	if persisted {
+		err = w.outbox.AckDelivery(delivery.ID)
+		if err != nil {
+			// The webhook will receive the delivery again, with the same
+			// ID.
+			c.logger.Error(fmt.Sprintf("acknowledge delivery '%s': %s", delivery.ID, err))
+		}
+	}
+
+	return persisted, nil
+}
+
+// redeliver sends the deliveries of the outbox again, e.g., after a restart
+// or a webhook outage.
+func (w *Webhook) redeliver() {
+	deliveries, err := w.outbox.PendingDeliveries()
+	if err != nil {
+		w.logger.Error(fmt.Sprintf("get pending deliveries: %s", err))
+	}
+
+	for _, delivery := range deliveries {
+		if !w.claim(delivery.ID) {
+			continue
+		}
+
+		err = w.redeliverOne(delivery)
+		w.release(delivery.ID)
+
+		if err != nil {
+			w.logger.Warn(fmt.Sprintf("redeliver '%s' to '%s': %s", delivery.ID, delivery.Url, err))
+		}
+	}
+}
+
+// redeliverOne sends a delivery of the outbox again, and removes it once
+// the webhook acknowledges it.
+func (w *Webhook) redeliverOne(delivery gotenberg.Delivery) error {
+	c := client{
+		url:              delivery.Url,
+		method:           delivery.Method,
+		extraHttpHeaders: delivery.ExtraHttpHeaders,
+		authorization:    w.authorization,
+		startTime:        time.Now(),
+
+		client: &retryablehttp.Client{
+			HTTPClient: &http.Client{
+				Timeout:   w.clientTimeout,
+				Transport: w.transport(),
+			},
+			RetryMax:     w.maxRetry,
+			RetryWaitMin: w.retryMinWait,
+			RetryWaitMax: w.retryMaxWait,
+			Logger: leveledLogger{
+				logger: w.logger,
+			},
+			CheckRetry: retryablehttp.DefaultRetryPolicy,
+			Backoff:    retryablehttp.DefaultBackoff,
+		},
+		logger: w.logger.With(zap.String("trace", delivery.Job.ID)),
+	}
+
+	payload, err := os.Open(delivery.PayloadPath)
+	if err != nil {
+		return fmt.Errorf("open payload: %w", err)
+	}
+
+	defer func() {
+		err := payload.Close()
+		if err != nil {
+			w.logger.Error(fmt.Sprintf("close payload: %s", err))
+		}
+	}()
+
+	headers := make(map[string]string, len(delivery.Headers)+1)
+	for key, value := range delivery.Headers {
+		headers[key] = value
+	}
+
+	headers[deliveryIdHeader] = delivery.ID
+
+	err = c.send(payload, headers, false)
+	if err != nil {
+		return err
+	}
+
+	err = w.outbox.AckDelivery(delivery.ID)
+	if err != nil {
+		return fmt.Errorf("acknowledge delivery: %w", err)
+	}
+
+	if w.jobTracker != nil {
+		job := delivery.Job
+		job.Delivered = true
+		job.Error = ""
+		job.UpdatedAt = time.Now().UTC()
+		w.jobTracker.TrackJob(job)
+	}
+
+	return nil
+}
+
+// claim marks a delivery as in progress. It returns false if the delivery
+// is already in progress.
+func (w *Webhook) claim(id string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.inflight[id]; ok {
+		return false
+	}
+
+	w.inflight[id] = struct{}{}
+
+	return true
+}
+
+// release marks a delivery as no longer in progress.
+func (w *Webhook) release(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.inflight, id)
+}
@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+func TestWebhook_deliver(t *testing.T) {
+	payloadPath := filepath.Join(t.TempDir(), "foo.pdf")
+
+	err := os.WriteFile(payloadPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	for _, tc := range []struct {
+		scenario        string
+		status          int
+		putErr          error
+		noOutbox        bool
+		expectPersisted bool
+		expectAck       bool
+		expectError     bool
+	}{
+		{
+			scenario: "no outbox",
+			status:   http.StatusOK,
+			noOutbox: true,
+		},
+		{
+			scenario: "outbox disabled",
+			status:   http.StatusOK,
+			putErr:   gotenberg.ErrOutboxDisabled,
+		},
+		{
+			scenario:    "outbox failure",
+			status:      http.StatusOK,
+			putErr:      errors.New("foo"),
+			expectError: true,
+		},
+		{
+			scenario:        "acknowledged",
+			status:          http.StatusOK,
+			expectPersisted: true,
+			expectAck:       true,
+		},
+		{
+			scenario:        "not acknowledged",
+			status:          http.StatusBadRequest,
+			expectPersisted: true,
+			expectError:     true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			var deliveryId string
+
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				deliveryId = req.Header.Get(deliveryIdHeader)
+				rw.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			var (
+				put   gotenberg.Delivery
+				acked string
+			)
+
+			w := &Webhook{inflight: make(map[string]struct{})}
+			if !tc.noOutbox {
+				w.outbox = &gotenberg.OutboxMock{
+					PutDeliveryMock: func(delivery gotenberg.Delivery, payloadPath string) error {
+						put = delivery
+						return tc.putErr
+					},
+					AckDeliveryMock: func(id string) error {
+						acked = id
+						return nil
+					},
+				}
+			}
+
+			c := &client{
+				url:    srv.URL,
+				method: http.MethodPost,
+				client: &retryablehttp.Client{
+					HTTPClient: srv.Client(),
+					CheckRetry: retryablehttp.DefaultRetryPolicy,
+					Backoff:    retryablehttp.DefaultBackoff,
+				},
+				logger: zap.NewNop(),
+			}
+
+			persisted, err := w.deliver(c, gotenberg.Job{ID: "foo"}, map[string]string{}, payloadPath)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if persisted != tc.expectPersisted {
+				t.Errorf("expected persisted %t but got %t", tc.expectPersisted, persisted)
+			}
+
+			if !tc.noOutbox && tc.putErr == nil && deliveryId != put.ID {
+				t.Errorf("expected delivery ID '%s' but got '%s'", put.ID, deliveryId)
+			}
+
+			if tc.expectAck && acked != put.ID {
+				t.Errorf("expected delivery '%s' to be acknowledged but got '%s'", put.ID, acked)
+			}
+
+			if !tc.expectAck && acked != "" {
+				t.Errorf("expected no acknowledgment but got '%s'", acked)
+			}
+
+			if len(w.inflight) != 0 {
+				t.Errorf("expected no delivery in progress but got %d", len(w.inflight))
+			}
+		})
+	}
+}
+
+func TestWebhook_redeliver(t *testing.T) {
+	payloadPath := filepath.Join(t.TempDir(), "payload")
+
+	err := os.WriteFile(payloadPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	var received []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		received = append(received, req.Header.Get(deliveryIdHeader))
+
+		if req.Header.Get("X-Fail") != "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var (
+		acked   []string
+		tracked []gotenberg.Job
+	)
+
+	w := &Webhook{
+		outbox: &gotenberg.OutboxMock{
+			PendingDeliveriesMock: func() ([]gotenberg.Delivery, error) {
+				return []gotenberg.Delivery{
+					{ID: "foo", Job: gotenberg.Job{ID: "foo"}, Url: srv.URL, Method: http.MethodPost, PayloadPath: payloadPath},
+					{ID: "bar", Job: gotenberg.Job{ID: "bar"}, Url: srv.URL, Method: http.MethodPost, Headers: map[string]string{"X-Fail": "true"}, PayloadPath: payloadPath},
+					{ID: "baz", Job: gotenberg.Job{ID: "baz"}, Url: srv.URL, Method: http.MethodPost, PayloadPath: payloadPath},
+				}, nil
+			},
+			AckDeliveryMock: func(id string) error {
+				acked = append(acked, id)
+				return nil
+			},
+		},
+		jobTracker: &gotenberg.JobTrackerMock{
+			TrackJobMock: func(job gotenberg.Job) {
+				tracked = append(tracked, job)
+			},
+		},
+		logger:   zap.NewNop(),
+		inflight: map[string]struct{}{"baz": {}},
+	}
+
+	w.redeliver()
+
+	if len(received) != 2 || received[0] != "foo" || received[1] != "bar" {
+		t.Errorf("expected deliveries [foo bar] but got %v", received)
+	}
+
+	if len(acked) != 1 || acked[0] != "foo" {
+		t.Errorf("expected acknowledgments [foo] but got %v", acked)
+	}
+
+	if len(tracked) != 1 || tracked[0].ID != "foo" || !tracked[0].Delivered {
+		t.Errorf("expected job 'foo' to be delivered but got %+v", tracked)
+	}
+
+	if _, ok := w.inflight["baz"]; !ok || len(w.inflight) != 1 {
+		t.Errorf("expected only 'baz' in progress but got %v", w.inflight)
+	}
+}
+
+func TestWebhook_claim(t *testing.T) {
+	w := &Webhook{inflight: make(map[string]struct{})}
+
+	if !w.claim("foo") {
+		t.Fatal("expected to claim 'foo'")
+	}
+
+	if w.claim("foo") {
+		t.Error("expected not to claim 'foo' twice")
+	}
+
+	w.release("foo")
+
+	if !w.claim("foo") {
+		t.Error("expected to claim 'foo' after its release")
+	}
+}
@@ -1,10 +1,16 @@
 package webhook
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/dlclark/regexp2"
 	flag "github.com/spf13/pflag"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
@@ -17,15 +23,28 @@ func init() {
 // Webhook is a module which provides a middleware for uploading output files
 // to any destinations in an asynchronous fashion.
 type Webhook struct {
-	allowList      *regexp2.Regexp
-	denyList       *regexp2.Regexp
-	errorAllowList *regexp2.Regexp
-	errorDenyList  *regexp2.Regexp
-	maxRetry       int
-	retryMinWait   time.Duration
-	retryMaxWait   time.Duration
-	clientTimeout  time.Duration
-	disable        bool
+	allowList              *regexp2.Regexp
+	denyList               *regexp2.Regexp
+	errorAllowList         *regexp2.Regexp
+	errorDenyList          *regexp2.Regexp
+	maxRetry               int
+	retryMinWait           time.Duration
+	retryMaxWait           time.Duration
+	clientTimeout          time.Duration
+	streamArchive          bool
+	eventsProgressInterval time.Duration
+	outboxInterval         time.Duration
+	authorization          string
+	disable                bool
+	resultStore            gotenberg.ResultStore
+	jobTracker             gotenberg.JobTracker
+	outbox                 gotenberg.Outbox
+	faultInjector          gotenberg.FaultInjector
+
+	logger   *zap.Logger
+	inflight map[string]struct{}
+	mu       sync.Mutex
+	stop     chan struct{}
 }
 
 // Descriptor returns an [Webhook]'s module descriptor.
@@ -42,6 +61,10 @@ func (w *Webhook) Descriptor() gotenberg.ModuleDescriptor {
 			fs.Duration("webhook-retry-min-wait", time.Duration(1)*time.Second, "Set the minimum duration to wait before trying to call the webhook again")
 			fs.Duration("webhook-retry-max-wait", time.Duration(30)*time.Second, "Set the maximum duration to wait before trying to call the webhook again")
 			fs.Duration("webhook-client-timeout", time.Duration(30)*time.Second, "Set the time limit for requests to the webhook")
+			fs.Duration("webhook-events-progress-interval", time.Duration(10)*time.Second, "Set the interval at which to send the progress events to the webhook events URL - 0 disables the progress events")
+			fs.Duration("webhook-outbox-interval", time.Duration(1)*time.Minute, "Set the interval at which to retry the deliveries the webhooks did not acknowledge - requires an outbox")
+			fs.Bool("webhook-stream-archive", false, "Stream the archive of many output files to the webhook while creating it - note: the request does not have a Content-Length header")
+			fs.String("webhook-authorization", "", "Set the Authorization header sent to the webhook URLs, or a reference to a secret - requires both allow lists")
 			fs.Bool("webhook-disable", false, "Disable the webhook feature")
 
 			return fs
@@ -61,8 +84,179 @@ func (w *Webhook) Provision(ctx *gotenberg.Context) error {
 	w.retryMinWait = flags.MustDuration("webhook-retry-min-wait")
 	w.retryMaxWait = flags.MustDuration("webhook-retry-max-wait")
 	w.clientTimeout = flags.MustDuration("webhook-client-timeout")
+	w.streamArchive = flags.MustBool("webhook-stream-archive")
+	w.eventsProgressInterval = flags.MustDuration("webhook-events-progress-interval")
+	w.outboxInterval = flags.MustDuration("webhook-outbox-interval")
 	w.disable = flags.MustBool("webhook-disable")
 
+	authorization, err := gotenberg.ResolveSecret(ctx, flags.MustString("webhook-authorization"))
+	if err != nil {
+		return fmt.Errorf("get authorization: %w", err)
+	}
+
+	w.authorization = authorization
+
+	resultStores, err := ctx.Modules(new(gotenberg.ResultStore))
+	if err != nil {
+		return fmt.Errorf("get result stores: %w", err)
+	}
+
+	if len(resultStores) > 1 {
+		return fmt.Errorf("expected at most one result store, but got %d", len(resultStores))
+	}
+
+	if len(resultStores) == 1 {
+		w.resultStore = resultStores[0].(gotenberg.ResultStore)
+	}
+
+	jobTrackers, err := ctx.Modules(new(gotenberg.JobTracker))
+	if err != nil {
+		return fmt.Errorf("get job trackers: %w", err)
+	}
+
+	if len(jobTrackers) > 1 {
+		return fmt.Errorf("expected at most one job tracker, but got %d", len(jobTrackers))
+	}
+
+	if len(jobTrackers) == 1 {
+		w.jobTracker = jobTrackers[0].(gotenberg.JobTracker)
+	}
+
+	faultInjectors, err := ctx.Modules(new(gotenberg.FaultInjector))
+	if err != nil {
+		return fmt.Errorf("get fault injectors: %w", err)
+	}
+
+	if len(faultInjectors) > 1 {
+		return fmt.Errorf("expected at most one fault injector, but got %d", len(faultInjectors))
+	}
+
+	if len(faultInjectors) == 1 {
+		w.faultInjector = faultInjectors[0].(gotenberg.FaultInjector)
+	}
+
+	outboxes, err := ctx.Modules(new(gotenberg.Outbox))
+	if err != nil {
+		return fmt.Errorf("get outboxes: %w", err)
+	}
+
+	if len(outboxes) > 1 {
+		return fmt.Errorf("expected at most one outbox, but got %d", len(outboxes))
+	}
+
+	w.inflight = make(map[string]struct{})
+
+	if len(outboxes) == 0 {
+		// Exit early.
+		return nil
+	}
+
+	outbox := outboxes[0].(gotenberg.Outbox)
+
+	_, err = outbox.PendingDeliveries()
+	if errors.Is(err, gotenberg.ErrOutboxDisabled) {
+		// Exit early.
+		return nil
+	}
+
+	w.outbox = outbox
+
+	loggerProvider, err := ctx.Module(new(gotenberg.LoggerProvider))
+	if err != nil {
+		return fmt.Errorf("get logger provider: %w", err)
+	}
+
+	logger, err := loggerProvider.(gotenberg.LoggerProvider).Logger(w)
+	if err != nil {
+		return fmt.Errorf("get logger: %w", err)
+	}
+
+	w.logger = logger
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (w *Webhook) Validate() error {
+	if w.disable {
+		return nil
+	}
+
+	var err error
+
+	if w.outbox != nil && w.outboxInterval <= 0 {
+		err = multierr.Append(err,
+			errors.New("outbox interval must be more than 0"),
+		)
+	}
+
+	if w.authorization == "" {
+		return err
+	}
+
+	// Otherwise, any client could receive the credentials.
+	if w.allowList.String() == "" {
+		err = multierr.Append(err,
+			errors.New("allow list must not be empty if the authorization is set"),
+		)
+	}
+
+	if w.errorAllowList.String() == "" {
+		err = multierr.Append(err,
+			errors.New("error allow list must not be empty if the authorization is set"),
+		)
+	}
+
+	return err
+}
+
+// Start retries the deliveries of the outbox, if any, on startup and then
+// periodically.
+func (w *Webhook) Start() error {
+	if w.disable || w.outbox == nil {
+		return nil
+	}
+
+	w.stop = make(chan struct{})
+
+	go func() {
+		w.redeliver()
+
+		ticker := time.NewTicker(w.outboxInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.redeliver()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// StartupMessage returns a custom startup message.
+func (w *Webhook) StartupMessage() string {
+	if w.disable {
+		return "webhook disabled"
+	}
+
+	if w.outbox == nil {
+		return "webhook deliveries not persisted"
+	}
+
+	return fmt.Sprintf("webhook deliveries persisted and retried every %s", w.outboxInterval)
+}
+
+// Stop stops the periodic retries of the deliveries.
+func (w *Webhook) Stop(ctx context.Context) error {
+	if w.stop != nil {
+		close(w.stop)
+	}
+
 	return nil
 }
 
@@ -81,5 +275,7 @@ func (w *Webhook) Middlewares() ([]api.Middleware, error) {
 var (
 	_ gotenberg.Module       = (*Webhook)(nil)
 	_ gotenberg.Provisioner  = (*Webhook)(nil)
+	_ gotenberg.Validator    = (*Webhook)(nil)
+	_ gotenberg.App          = (*Webhook)(nil)
 	_ api.MiddlewareProvider = (*Webhook)(nil)
 )
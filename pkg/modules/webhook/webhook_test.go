@@ -3,6 +3,9 @@ package webhook
 import (
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/dlclark/regexp2"
 
 	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
 )
@@ -33,6 +36,70 @@ func TestWebhook_Provision(t *testing.T) {
 	}
 }
 
+func TestWebhook_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		scenario       string
+		authorization  string
+		allowList      string
+		errorAllowList string
+		outbox         gotenberg.Outbox
+		outboxInterval time.Duration
+		expectError    bool
+	}{
+		{
+			scenario: "no authorization",
+		},
+		{
+			scenario:    "outbox without interval",
+			outbox:      new(gotenberg.OutboxMock),
+			expectError: true,
+		},
+		{
+			scenario:       "outbox with interval",
+			outbox:         new(gotenberg.OutboxMock),
+			outboxInterval: time.Duration(1) * time.Minute,
+		},
+		{
+			scenario:       "authorization without allow list",
+			authorization:  "Bearer foo",
+			errorAllowList: "^https://errors.example.com/",
+			expectError:    true,
+		},
+		{
+			scenario:      "authorization without error allow list",
+			authorization: "Bearer foo",
+			allowList:     "^https://results.example.com/",
+			expectError:   true,
+		},
+		{
+			scenario:       "validate success",
+			authorization:  "Bearer foo",
+			allowList:      "^https://results.example.com/",
+			errorAllowList: "^https://errors.example.com/",
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			mod := &Webhook{
+				authorization:  tc.authorization,
+				allowList:      regexp2.MustCompile(tc.allowList, 0),
+				errorAllowList: regexp2.MustCompile(tc.errorAllowList, 0),
+				outbox:         tc.outbox,
+				outboxInterval: tc.outboxInterval,
+			}
+
+			err := mod.Validate()
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+		})
+	}
+}
+
 func TestWebhook_Middlewares(t *testing.T) {
 	for _, tc := range []struct {
 		scenario          string
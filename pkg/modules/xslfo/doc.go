@@ -0,0 +1,9 @@
+// Package xslfo provides a module which adds a route for rendering XSL-FO
+// documents to PDF. It also renders XML documents with the XSLT stylesheet
+// transforming them to XSL-FO.
+//
+// The documents are rendered with Apache FOP. The path to its binary must be
+// specified using the FOP_BIN_PATH environment variable.
+//
+// See: https://xmlgraphics.apache.org/fop.
+package xslfo
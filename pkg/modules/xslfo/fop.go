@@ -0,0 +1,62 @@
+package xslfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+)
+
+// ErrInvalidDocument happens if Apache FOP cannot render a document.
+var ErrInvalidDocument = errors.New("invalid XSL-FO document")
+
+// document is either an XSL-FO document, or an XML document with the XSLT
+// stylesheet transforming it to XSL-FO.
+type document struct {
+	foPath  string
+	xmlPath string
+	xslPath string
+}
+
+// render renders a document to PDF with Apache FOP. FOP runs in the
+// directory of the document, so that the document may refer to its assets,
+// e.g., its images, with relative paths.
+func render(ctx context.Context, logger *zap.Logger, binPath string, doc document, outputPath string) error {
+	var dirPath string
+	args := []string{"-q"}
+
+	if doc.foPath != "" {
+		args = append(args, "-fo", doc.foPath)
+		dirPath = filepath.Dir(doc.foPath)
+	} else {
+		args = append(args, "-xml", doc.xmlPath, "-xsl", doc.xslPath)
+		dirPath = filepath.Dir(doc.xmlPath)
+	}
+
+	args = append(args, "-pdf", outputPath)
+
+	cmd, err := gotenberg.CommandContext(ctx, logger, binPath, args...)
+	if err != nil {
+		return fmt.Errorf("create command: %w", err)
+	}
+
+	cmd.SetDir(dirPath)
+	cmd.SetEnv("FOP_OPTS=-Djava.awt.headless=true")
+
+	_, err = cmd.Exec()
+	if err == nil {
+		return nil
+	}
+
+	// Only a broken document should fail the rendering, unless the context
+	// is done.
+	if ctx.Err() != nil {
+		return fmt.Errorf("render with Apache FOP: %w", err)
+	}
+
+	return fmt.Errorf("render with Apache FOP: %v: %w", err, ErrInvalidDocument)
+}
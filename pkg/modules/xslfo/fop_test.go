@@ -0,0 +1,97 @@
+package xslfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg/gotenbergtest"
+)
+
+// fakeFop writes a script which mimics Apache FOP: it writes its arguments
+// to the PDF output, i.e., its last argument, or fails.
+func fakeFop(t *testing.T, fail bool) string {
+	script := "for arg in \"$@\"; do out=\"$arg\"; done\necho \"$PWD $@\" > \"$out\"\n"
+	if fail {
+		script = "exit 1\n"
+	}
+
+	return gotenbergtest.FakeBinary(t, "fop", script)
+}
+
+func TestRender(t *testing.T) {
+	dirPath := t.TempDir()
+
+	for _, tc := range []struct {
+		scenario      string
+		binPath       string
+		doc           document
+		expectArgs    string
+		expectError   bool
+		expectInvalid bool
+	}{
+		{
+			scenario:   "XSL-FO document",
+			binPath:    fakeFop(t, false),
+			doc:        document{foPath: filepath.Join(dirPath, "invoice.fo")},
+			expectArgs: dirPath + " -q -fo " + filepath.Join(dirPath, "invoice.fo") + " -pdf ",
+		},
+		{
+			scenario:   "XML document with an XSLT stylesheet",
+			binPath:    fakeFop(t, false),
+			doc:        document{xmlPath: filepath.Join(dirPath, "invoice.xml"), xslPath: filepath.Join(dirPath, "invoice.xsl")},
+			expectArgs: dirPath + " -q -xml " + filepath.Join(dirPath, "invoice.xml") + " -xsl " + filepath.Join(dirPath, "invoice.xsl") + " -pdf ",
+		},
+		{
+			scenario:      "invalid document",
+			binPath:       fakeFop(t, true),
+			doc:           document{foPath: filepath.Join(dirPath, "invoice.fo")},
+			expectError:   true,
+			expectInvalid: true,
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			outputPath := filepath.Join(dirPath, "output.pdf")
+
+			err := render(context.Background(), zap.NewNop(), tc.binPath, tc.doc, outputPath)
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none")
+			}
+
+			if tc.expectInvalid && !errors.Is(err, ErrInvalidDocument) {
+				t.Errorf("expected ErrInvalidDocument but got: %v", err)
+			}
+
+			if tc.expectError {
+				return
+			}
+
+			output, err := os.ReadFile(outputPath)
+			if err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			if strings.TrimSpace(string(output)) != tc.expectArgs+outputPath {
+				t.Errorf("expected '%s' but got '%s'", tc.expectArgs+outputPath, output)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := render(ctx, zap.NewNop(), fakeFop(t, true), document{foPath: filepath.Join(dirPath, "invoice.fo")}, filepath.Join(dirPath, "output.pdf"))
+	if err == nil || errors.Is(err, ErrInvalidDocument) {
+		t.Errorf("expected a context error but got: %v", err)
+	}
+}
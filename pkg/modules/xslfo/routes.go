@@ -0,0 +1,162 @@
+package xslfo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+// convertRoute returns an [api.Route] which can render XSL-FO documents, or
+// XML documents with an XSLT stylesheet, to PDF.
+func convertRoute(engine gotenberg.PdfEngine, fopBinPath string) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/xslfo/convert",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			// Let's get the data from the form and validate them.
+			var (
+				foPaths  []string
+				xmlPaths []string
+				xslPaths []string
+				pdfa     string
+				pdfua    bool
+				merge    bool
+			)
+
+			err := ctx.FormData().
+				Paths([]string{".fo"}, &foPaths).
+				Paths([]string{".xml"}, &xmlPaths).
+				Paths([]string{".xsl", ".xslt"}, &xslPaths).
+				String("pdfa", &pdfa, "").
+				Bool("pdfua", &pdfua, false).
+				Bool("merge", &merge, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			docs, err := documents(foPaths, xmlPaths, xslPaths)
+			if err != nil {
+				return api.WrapError(
+					fmt.Errorf("validate form files: %w", err),
+					api.NewSentinelHttpError(
+						http.StatusBadRequest,
+						fmt.Sprintf("Invalid form data: %s", err),
+					).WithCode(api.ErrorCodeXslfoInvalidFormFiles),
+				)
+			}
+
+			pdfFormats := gotenberg.PdfFormats{
+				PdfA:  pdfa,
+				PdfUa: pdfua,
+			}
+
+			// Alright, let's render each document to PDF. Apache FOP runs in
+			// a JVM, so the documents are rendered one at a time.
+			outputPaths := make([]string, len(docs))
+			for i, doc := range docs {
+				inputPath := doc.foPath
+				if inputPath == "" {
+					inputPath = doc.xmlPath
+				}
+
+				// invoice.fo -> invoice.fo.pdf.
+				outputPaths[i] = ctx.GeneratePath(filepath.Base(inputPath), ".pdf")
+
+				err = render(ctx, ctx.Log(), fopBinPath, doc, outputPaths[i])
+				if err != nil {
+					if errors.Is(err, ErrInvalidDocument) {
+						return api.WrapError(
+							fmt.Errorf("render '%s': %w", filepath.Base(inputPath), err),
+							api.NewSentinelHttpError(
+								http.StatusBadRequest,
+								fmt.Sprintf("Apache FOP cannot render '%s'; please check the document and its stylesheet", filepath.Base(inputPath)),
+							).WithCode(api.ErrorCodeXslfoInvalidDocument),
+						)
+					}
+
+					return fmt.Errorf("render '%s': %w", filepath.Base(inputPath), err)
+				}
+			}
+
+			// So far so good, let's check if we have to merge the PDFs. Quick
+			// win: if there is only one PDF, skip this step.
+			if len(outputPaths) > 1 && merge {
+				outputPath := ctx.GeneratePath("", ".pdf")
+
+				err = engine.Merge(ctx, ctx.Log(), outputPaths, outputPath)
+				if err != nil {
+					return fmt.Errorf("merge PDFs: %w", err)
+				}
+
+				outputPaths = []string{outputPath}
+			}
+
+			// Now, let's check if the client want to convert the resulting
+			// PDFs to specific PDF formats.
+			zeroValued := gotenberg.PdfFormats{}
+			if pdfFormats != zeroValued {
+				for i, outputPath := range outputPaths {
+					convertInputPath := outputPath
+					convertOutputPath := ctx.GeneratePath("", ".pdf")
+
+					err = engine.Convert(ctx, ctx.Log(), pdfFormats, convertInputPath, convertOutputPath)
+					if err != nil {
+						return fmt.Errorf("convert PDF: %w", err)
+					}
+
+					// Important: the output path is now the converted file.
+					outputPaths[i] = convertOutputPath
+				}
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output paths: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// documents pairs the form files: either XSL-FO documents, or XML documents
+// with one XSLT stylesheet.
+func documents(foPaths, xmlPaths, xslPaths []string) ([]document, error) {
+	if len(foPaths) > 0 {
+		if len(xmlPaths) > 0 || len(xslPaths) > 0 {
+			return nil, errors.New("either XSL-FO documents, or XML documents with an XSLT stylesheet are expected, not both")
+		}
+
+		docs := make([]document, len(foPaths))
+		for i, foPath := range foPaths {
+			docs[i] = document{foPath: foPath}
+		}
+
+		return docs, nil
+	}
+
+	if len(xmlPaths) == 0 {
+		return nil, errors.New("no XSL-FO document nor XML document")
+	}
+
+	if len(xslPaths) != 1 {
+		return nil, fmt.Errorf("XML documents require exactly one XSLT stylesheet, got %d", len(xslPaths))
+	}
+
+	docs := make([]document, len(xmlPaths))
+	for i, xmlPath := range xmlPaths {
+		docs[i] = document{xmlPath: xmlPath, xslPath: xslPaths[0]}
+	}
+
+	return docs, nil
+}
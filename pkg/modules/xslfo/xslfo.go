@@ -0,0 +1,94 @@
+package xslfo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/gotenberg/gotenberg/v8/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+)
+
+func init() {
+	gotenberg.MustRegisterModule(new(XslFo))
+}
+
+// XslFo is a module which provides a route for rendering XSL-FO documents to
+// PDF.
+type XslFo struct {
+	engine        gotenberg.PdfEngine
+	fopBinPath    string
+	disableRoutes bool
+}
+
+// Descriptor returns a [XslFo]'s module descriptor.
+func (mod *XslFo) Descriptor() gotenberg.ModuleDescriptor {
+	return gotenberg.ModuleDescriptor{
+		ID: "xslfo",
+		FlagSet: func() *flag.FlagSet {
+			fs := flag.NewFlagSet("xslfo", flag.ExitOnError)
+			fs.Bool("xslfo-disable-routes", false, "Disable the routes")
+
+			return fs
+		}(),
+		New: func() gotenberg.Module { return new(XslFo) },
+	}
+}
+
+// Provision sets the module properties.
+func (mod *XslFo) Provision(ctx *gotenberg.Context) error {
+	flags := ctx.ParsedFlags()
+	mod.disableRoutes = flags.MustBool("xslfo-disable-routes")
+
+	fopBinPath, ok := os.LookupEnv("FOP_BIN_PATH")
+	if !ok {
+		return errors.New("FOP_BIN_PATH environment variable is not set")
+	}
+
+	mod.fopBinPath = fopBinPath
+
+	provider, err := ctx.Module(new(gotenberg.PdfEngineProvider))
+	if err != nil {
+		return fmt.Errorf("get PDF engine provider: %w", err)
+	}
+
+	engine, err := provider.(gotenberg.PdfEngineProvider).PdfEngine()
+	if err != nil {
+		return fmt.Errorf("get PDF engine: %w", err)
+	}
+
+	mod.engine = engine
+
+	return nil
+}
+
+// Validate validates the module properties.
+func (mod *XslFo) Validate() error {
+	_, err := os.Stat(mod.fopBinPath)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("fop binary path does not exist: %w", err)
+	}
+
+	return nil
+}
+
+// Routes returns the HTTP routes.
+func (mod *XslFo) Routes() ([]api.Route, error) {
+	if mod.disableRoutes {
+		return nil, nil
+	}
+
+	return []api.Route{
+		convertRoute(mod.engine, mod.fopBinPath),
+	}, nil
+}
+
+// Interface guards.
+var (
+	_ gotenberg.Module      = (*XslFo)(nil)
+	_ gotenberg.Provisioner = (*XslFo)(nil)
+	_ gotenberg.Validator   = (*XslFo)(nil)
+	_ api.Router            = (*XslFo)(nil)
+)
@@ -0,0 +1,5 @@
+// Package replay provides the format of the captured requests, i.e., the
+// failed requests persisted with their form fields and files, and the logic of
+// the "replay" command, which sends a captured request again to a Gotenberg
+// instance for reproducing a bug.
+package replay
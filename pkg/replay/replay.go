@@ -0,0 +1,308 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+const (
+	// CaptureFilename is the name of the file describing a captured request
+	// within its directory.
+	CaptureFilename = "request.json"
+
+	// FilesDirname is the name of the directory of the captured files within
+	// the directory of a captured request.
+	FilesDirname = "files"
+)
+
+// Capture describes a captured request.
+type Capture struct {
+	// Time is the moment the request has been captured.
+	Time time.Time `json:"time"`
+
+	// Trace is the request identifier.
+	Trace string `json:"trace"`
+
+	// Method and Path are the HTTP method and the URL path of the request.
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	// Header is the header of the request, without its sensitive values.
+	Header http.Header `json:"header"`
+
+	// Values are the form fields of the request.
+	Values map[string][]string `json:"values"`
+
+	// Files are the names of the files of the request, in the files
+	// directory.
+	Files []string `json:"files"`
+
+	// Status and Error are the result of the request.
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+}
+
+// Save persists a captured request and copies its files in the given
+// directory.
+func Save(dir string, capture Capture, inputPaths []string) error {
+	filesDir := filepath.Join(dir, FilesDirname)
+
+	err := os.MkdirAll(filesDir, 0o700)
+	if err != nil {
+		return fmt.Errorf("create capture directory: %w", err)
+	}
+
+	capture.Files = make([]string, len(inputPaths))
+	for i, inputPath := range inputPaths {
+		filename := filepath.Base(inputPath)
+
+		err = copyFile(inputPath, filepath.Join(filesDir, filename))
+		if err != nil {
+			return fmt.Errorf("copy '%s': %w", filename, err)
+		}
+
+		capture.Files[i] = filename
+	}
+
+	b, err := json.MarshalIndent(capture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal capture: %w", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, CaptureFilename), b, 0o600)
+	if err != nil {
+		return fmt.Errorf("write capture: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads a captured request from its directory.
+func Load(dir string) (Capture, error) {
+	b, err := os.ReadFile(filepath.Join(dir, CaptureFilename))
+	if err != nil {
+		return Capture{}, fmt.Errorf("read capture: %w", err)
+	}
+
+	var capture Capture
+
+	err = json.Unmarshal(b, &capture)
+	if err != nil {
+		return Capture{}, fmt.Errorf("unmarshal capture: %w", err)
+	}
+
+	return capture, nil
+}
+
+// Options gathers the options of a replay.
+type Options struct {
+	// URL is the base URL of the Gotenberg instance, e.g.,
+	// http://localhost:3000.
+	URL string
+
+	// Dir is the directory of the captured request.
+	Dir string
+
+	// OutputPath is the path of the file in which to write the response
+	// body.
+	// Optional.
+	OutputPath string
+
+	// Timeout is the maximum duration of the request.
+	Timeout time.Duration
+}
+
+// Validate validates the options.
+func (opts Options) Validate() error {
+	var err error
+
+	if opts.URL == "" {
+		err = multierr.Append(err, errors.New("URL must not be empty"))
+	}
+
+	if opts.Dir == "" {
+		err = multierr.Append(err, errors.New("capture directory must not be empty"))
+	}
+
+	if opts.Timeout <= 0 {
+		err = multierr.Append(err, errors.New("timeout must be strictly positive"))
+	}
+
+	return err
+}
+
+// Result is the result of a replay.
+type Result struct {
+	// Capture is the replayed request.
+	Capture Capture
+
+	// Status is the HTTP status of the response.
+	Status int
+
+	// Duration is the duration of the request.
+	Duration time.Duration
+
+	// Body is the response body, unless written to the output path.
+	Body []byte
+}
+
+// Replay sends a captured request to a Gotenberg instance. The files are
+// sent under the "files" form field.
+func Replay(ctx context.Context, opts Options) (Result, error) {
+	err := opts.Validate()
+	if err != nil {
+		return Result{}, fmt.Errorf("validate options: %w", err)
+	}
+
+	capture, err := Load(opts.Dir)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	for key, values := range capture.Values {
+		for _, value := range values {
+			err = writer.WriteField(key, value)
+			if err != nil {
+				return Result{}, fmt.Errorf("write form field '%s': %w", key, err)
+			}
+		}
+	}
+
+	for _, filename := range capture.Files {
+		err = writeFile(writer, filepath.Join(opts.Dir, FilesDirname, filename))
+		if err != nil {
+			return Result{}, fmt.Errorf("write form file '%s': %w", filename, err)
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return Result{}, fmt.Errorf("close multipart writer: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, capture.Method, strings.TrimSuffix(opts.URL, "/")+capture.Path, &body)
+	if err != nil {
+		return Result{}, fmt.Errorf("create request: %w", err)
+	}
+
+	for key, values := range capture.Header {
+		switch http.CanonicalHeaderKey(key) {
+		case "Content-Type", "Content-Length", "Host":
+			continue
+		}
+
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	startTime := time.Now()
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Result{}, fmt.Errorf("send request: %w", err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	result := Result{
+		Capture: capture,
+		Status:  resp.StatusCode,
+	}
+
+	if opts.OutputPath != "" && resp.StatusCode == http.StatusOK {
+		out, err := os.Create(opts.OutputPath)
+		if err != nil {
+			return Result{}, fmt.Errorf("create output file: %w", err)
+		}
+
+		defer func() {
+			_ = out.Close()
+		}()
+
+		_, err = io.Copy(out, resp.Body)
+		if err != nil {
+			return Result{}, fmt.Errorf("write output file: %w", err)
+		}
+	} else {
+		result.Body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return Result{}, fmt.Errorf("read response body: %w", err)
+		}
+	}
+
+	result.Duration = time.Since(startTime)
+
+	return result, nil
+}
+
+// copyFile copies a file.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("open source file: %w", err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("create destination file: %w", err)
+	}
+
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return fmt.Errorf("copy file: %w", err)
+	}
+
+	return out.Close()
+}
+
+// writeFile adds a file to the "files" form field.
+func writeFile(writer *multipart.Writer, path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file: %w", err)
+	}
+
+	defer func() {
+		_ = in.Close()
+	}()
+
+	part, err := writer.CreateFormFile("files", filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("create form file: %w", err)
+	}
+
+	_, err = io.Copy(part, in)
+	if err != nil {
+		return fmt.Errorf("copy file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package replay
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	inputPath := filepath.Join(t.TempDir(), "foo.docx")
+	err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	capture := Capture{
+		Trace:  "foo",
+		Method: http.MethodPost,
+		Path:   "/forms/libreoffice/convert",
+		Values: map[string][]string{"landscape": {"true"}},
+		Status: http.StatusInternalServerError,
+	}
+
+	err = Save(dir, capture, []string{inputPath})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	actual, err := Load(dir)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	capture.Files = []string{"foo.docx"}
+	if !reflect.DeepEqual(actual, capture) {
+		t.Errorf("expected %+v but got %+v", capture, actual)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, FilesDirname, "foo.docx"))
+	if err != nil {
+		t.Errorf("expected copied file but got: %v", err)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	inputPath := filepath.Join(t.TempDir(), "foo.docx")
+	err := os.WriteFile(inputPath, []byte("foo"), 0o600)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	err = Save(dir, Capture{
+		Method: http.MethodPost,
+		Path:   "/forms/libreoffice/convert",
+		Header: http.Header{"Gotenberg-Output-Filename": {"bar"}},
+		Values: map[string][]string{"landscape": {"true"}},
+	}, []string{inputPath})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/forms/libreoffice/convert" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.Header.Get("Gotenberg-Output-Filename") != "bar" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if r.FormValue("landscape") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _, err := r.FormFile("files")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_, _ = w.Write([]byte("%PDF"))
+	}))
+	defer srv.Close()
+
+	outputPath := filepath.Join(t.TempDir(), "output.pdf")
+
+	result, err := Replay(context.Background(), Options{
+		URL:        srv.URL,
+		Dir:        dir,
+		OutputPath: outputPath,
+		Timeout:    time.Duration(10) * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if result.Status != http.StatusOK {
+		t.Fatalf("expected %d status code but got %d", http.StatusOK, result.Status)
+	}
+
+	b, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("expected no error but got: %v", err)
+	}
+
+	if string(b) != "%PDF" {
+		t.Errorf("expected '%%PDF' but got '%s'", string(b))
+	}
+}
+
+func TestReplay_invalidOptions(t *testing.T) {
+	_, err := Replay(context.Background(), Options{})
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+}
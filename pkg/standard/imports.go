@@ -3,7 +3,25 @@ package standard
 import (
 	// Standard Gotenberg modules.
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/api"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/archival"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/assets"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/capture"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/chaos"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/chromium"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/clamav"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/concurrency"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/debug"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/distributed"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/docxtemplate"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/email"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/epub"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/errorreporter"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/fonts"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/ghostscript"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/hooks"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/images"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/jobs"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/latex"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/api"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/libreoffice/pdfengine"
@@ -11,7 +29,21 @@ import (
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/pdfcpu"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/pdfengines"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/pdftk"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/pdftohtml"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/pdftoppm"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/pipeline"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/policy"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/prometheus"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/qpdf"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/quarantine"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/resources"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/results"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/retention"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/scheduler"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/secrets"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/templates"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/thumbnail"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/usage"
 	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/webhook"
+	_ "github.com/gotenberg/gotenberg/v8/pkg/modules/xslfo"
 )